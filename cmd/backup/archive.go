@@ -0,0 +1,160 @@
+package main
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"golang.org/x/crypto/scrypt"
+)
+
+// Archive file layout:
+//
+//	magic (6 bytes) | salt (16 bytes) | nonce (12 bytes) | AES-256-GCM ciphertext
+//
+// The plaintext is a gzip-compressed JSON archive. The key is derived from the
+// passphrase with scrypt, so a wrong passphrase fails GCM authentication.
+const (
+	archiveMagic   = "GMBAK1"
+	archiveVersion = 2 // 2: roles, specializations and schedule_slots added
+	saltSize       = 16
+	nonceSize      = 12
+	keySize        = 32
+)
+
+var errInvalidArchive = errors.New("not a backup archive or unsupported version")
+
+// archive is the decrypted backup content
+type archive struct {
+	Version   int                                 `json:"version"`
+	CreatedAt time.Time                           `json:"created_at"`
+	Tables    map[string][]map[string]interface{} `json:"tables"`
+	Checksums map[string]string                   `json:"checksums"` // SHA-256 of each table's JSON rows
+}
+
+// tableChecksum hashes the JSON encoding of a table's rows
+func tableChecksum(rows []map[string]interface{}) (string, error) {
+	data, err := json.Marshal(rows)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// verify checks every table has a matching checksum
+func (a *archive) verify() error {
+	if a.Version != archiveVersion {
+		return errInvalidArchive
+	}
+	for _, table := range backupTables {
+		rows, ok := a.Tables[table]
+		if !ok {
+			return fmt.Errorf("table %s missing from archive", table)
+		}
+		sum, err := tableChecksum(rows)
+		if err != nil {
+			return fmt.Errorf("checksum table %s: %w", table, err)
+		}
+		if sum != a.Checksums[table] {
+			return fmt.Errorf("checksum mismatch for table %s", table)
+		}
+	}
+	return nil
+}
+
+func deriveKey(passphrase string, salt []byte) ([]byte, error) {
+	return scrypt.Key([]byte(passphrase), salt, 1<<15, 8, 1, keySize)
+}
+
+// encryptArchive compresses and encrypts the archive
+func encryptArchive(a *archive, passphrase string) ([]byte, error) {
+	var plain bytes.Buffer
+	gz := gzip.NewWriter(&plain)
+	if err := json.NewEncoder(gz).Encode(a); err != nil {
+		return nil, fmt.Errorf("encode archive: %w", err)
+	}
+	if err := gz.Close(); err != nil {
+		return nil, fmt.Errorf("compress archive: %w", err)
+	}
+
+	salt := make([]byte, saltSize)
+	nonce := make([]byte, nonceSize)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+
+	gcm, err := newGCM(passphrase, salt)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]byte, 0, len(archiveMagic)+saltSize+nonceSize+plain.Len()+gcm.Overhead())
+	out = append(out, archiveMagic...)
+	out = append(out, salt...)
+	out = append(out, nonce...)
+	out = gcm.Seal(out, nonce, plain.Bytes(), []byte(archiveMagic))
+	return out, nil
+}
+
+// decryptArchive decrypts, decompresses and verifies an archive
+func decryptArchive(data []byte, passphrase string) (*archive, error) {
+	headerSize := len(archiveMagic) + saltSize + nonceSize
+	if len(data) < headerSize || string(data[:len(archiveMagic)]) != archiveMagic {
+		return nil, errInvalidArchive
+	}
+	salt := data[len(archiveMagic) : len(archiveMagic)+saltSize]
+	nonce := data[len(archiveMagic)+saltSize : headerSize]
+
+	gcm, err := newGCM(passphrase, salt)
+	if err != nil {
+		return nil, err
+	}
+
+	plain, err := gcm.Open(nil, nonce, data[headerSize:], []byte(archiveMagic))
+	if err != nil {
+		return nil, errors.New("decryption failed: wrong passphrase or corrupted archive")
+	}
+
+	gz, err := gzip.NewReader(bytes.NewReader(plain))
+	if err != nil {
+		return nil, fmt.Errorf("decompress archive: %w", err)
+	}
+	defer gz.Close()
+
+	var a archive
+	decoder := json.NewDecoder(io.Reader(gz))
+	decoder.UseNumber()
+	if err := decoder.Decode(&a); err != nil {
+		return nil, fmt.Errorf("decode archive: %w", err)
+	}
+
+	if err := a.verify(); err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
+
+func newGCM(passphrase string, salt []byte) (cipher.AEAD, error) {
+	key, err := deriveKey(passphrase, salt)
+	if err != nil {
+		return nil, fmt.Errorf("derive key: %w", err)
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
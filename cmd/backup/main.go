@@ -0,0 +1,223 @@
+// Command backup dumps the critical tables (roles, users, specializations, profiles,
+// schedules and their slots, bookings) to an encrypted archive and restores them,
+// for clinics without managed database backups.
+//
+// Usage:
+//
+//	go run ./cmd/backup -mode=backup -file=backup.gmbak
+//	go run ./cmd/backup -mode=restore -file=backup.gmbak -dry-run
+//	go run ./cmd/backup -mode=restore -file=backup.gmbak
+//
+// The archive passphrase is read from BACKUP_PASSPHRASE (.env or environment).
+//
+// Restore inserts rows that do not exist yet (matched by primary key) and never
+// overwrites existing rows. Dry-run performs the full restore inside a transaction
+// and rolls it back, reporting what would be inserted.
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"go-template-clean-architecture/config"
+	"go-template-clean-architecture/internal/infrastructure/database"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/logger"
+)
+
+// backupTables lists the tables in foreign key order (parents first)
+var backupTables = []string{
+	"roles",
+	"users",
+	"specializations",
+	"doctor_profiles",
+	"patient_profiles",
+	"doctor_schedules",
+	"schedule_slots",
+	"bookings",
+}
+
+// serialSequences are tables whose serial primary key sequence must be advanced after restore
+var serialSequences = []string{
+	"roles",
+	"specializations",
+	"doctor_schedules",
+	"schedule_slots",
+}
+
+// restoreBatchSize is the number of rows inserted per statement
+const restoreBatchSize = 500
+
+// errDryRun rolls back the restore transaction on dry-run
+var errDryRun = errors.New("dry run")
+
+func main() {
+	mode := flag.String("mode", "", "backup or restore")
+	file := flag.String("file", "", "archive path")
+	dryRun := flag.Bool("dry-run", false, "restore: verify and simulate without committing")
+	flag.Parse()
+
+	logrus.SetFormatter(&logrus.TextFormatter{FullTimestamp: true})
+
+	if *file == "" {
+		logrus.Fatal("-file is required")
+	}
+
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		logrus.Fatalf("Failed to load config: %v", err)
+	}
+
+	// Passphrase comes from .env/environment (not a flag) to keep it out of shell history
+	passphrase := viper.GetString("BACKUP_PASSPHRASE")
+	if passphrase == "" {
+		logrus.Fatal("BACKUP_PASSPHRASE is required")
+	}
+
+	db, err := database.NewPostgresConnection(cfg.DB)
+	if err != nil {
+		logrus.Fatalf("Failed to connect to database: %v", err)
+	}
+	db = db.Session(&gorm.Session{Logger: db.Logger.LogMode(logger.Silent)})
+
+	switch *mode {
+	case "backup":
+		err = runBackup(db, *file, passphrase)
+	case "restore":
+		err = runRestore(db, *file, passphrase, *dryRun)
+	default:
+		logrus.Fatal("-mode must be backup or restore")
+	}
+	if err != nil {
+		logrus.Fatalf("%s failed: %v", *mode, err)
+	}
+}
+
+// runBackup dumps all backup tables in a single repeatable-read snapshot and writes the encrypted archive
+func runBackup(db *gorm.DB, path string, passphrase string) error {
+	a := &archive{
+		Version:   archiveVersion,
+		CreatedAt: time.Now(),
+		Tables:    make(map[string][]map[string]interface{}, len(backupTables)),
+		Checksums: make(map[string]string, len(backupTables)),
+	}
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("SET TRANSACTION ISOLATION LEVEL REPEATABLE READ READ ONLY").Error; err != nil {
+			return err
+		}
+		for _, table := range backupTables {
+			rows := []map[string]interface{}{}
+			if err := tx.Table(table).Find(&rows).Error; err != nil {
+				return fmt.Errorf("dump table %s: %w", table, err)
+			}
+			sum, err := tableChecksum(rows)
+			if err != nil {
+				return fmt.Errorf("checksum table %s: %w", table, err)
+			}
+			a.Tables[table] = rows
+			a.Checksums[table] = sum
+			logrus.Infof("Dumped %s: %d rows", table, len(rows))
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	data, err := encryptArchive(a, passphrase)
+	if err != nil {
+		return err
+	}
+
+	// 0600: the archive contains personal and medical data
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		return fmt.Errorf("write archive: %w", err)
+	}
+
+	logrus.Infof("Backup written to %s (%d bytes)", path, len(data))
+	return nil
+}
+
+// runRestore decrypts and verifies the archive, then inserts missing rows in one transaction
+func runRestore(db *gorm.DB, path string, passphrase string, dryRun bool) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("read archive: %w", err)
+	}
+
+	a, err := decryptArchive(data, passphrase)
+	if err != nil {
+		return err
+	}
+	logrus.Infof("Archive verified: created_at=%s", a.CreatedAt.Format(time.RFC3339))
+
+	err = db.Transaction(func(tx *gorm.DB) error {
+		for _, table := range backupTables {
+			rows := normalizeRows(a.Tables[table])
+			inserted := int64(0)
+			for start := 0; start < len(rows); start += restoreBatchSize {
+				end := start + restoreBatchSize
+				if end > len(rows) {
+					end = len(rows)
+				}
+				result := tx.Table(table).Clauses(clause.OnConflict{DoNothing: true}).Create(rows[start:end])
+				if result.Error != nil {
+					return fmt.Errorf("restore table %s: %w", table, result.Error)
+				}
+				inserted += result.RowsAffected
+			}
+			logrus.Infof("Restore %s: %d in archive, %d inserted, %d already present", table, len(rows), inserted, int64(len(rows))-inserted)
+		}
+
+		for _, table := range serialSequences {
+			if err := tx.Exec(fmt.Sprintf(
+				"SELECT setval(pg_get_serial_sequence('%s', 'id'), COALESCE((SELECT MAX(id) FROM %s), 0) + 1, false)", table, table,
+			)).Error; err != nil {
+				return fmt.Errorf("reset sequence for %s: %w", table, err)
+			}
+		}
+
+		if dryRun {
+			return errDryRun
+		}
+		return nil
+	})
+
+	if errors.Is(err, errDryRun) {
+		logrus.Info("Dry run complete, no changes committed")
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	logrus.Info("Restore committed. Restart the API so Redis quotas are re-synced from the database")
+	return nil
+}
+
+// normalizeRows converts json.Number values back to int64/float64 for the database driver
+func normalizeRows(rows []map[string]interface{}) []map[string]interface{} {
+	for _, row := range rows {
+		for column, value := range row {
+			number, ok := value.(json.Number)
+			if !ok {
+				continue
+			}
+			if i, err := number.Int64(); err == nil {
+				row[column] = i
+			} else if f, err := number.Float64(); err == nil {
+				row[column] = f
+			}
+		}
+	}
+	return rows
+}
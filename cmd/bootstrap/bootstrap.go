@@ -13,12 +13,19 @@ import (
 	deliveryHttp "go-template-clean-architecture/internal/delivery/http"
 	"go-template-clean-architecture/internal/delivery/http/handler"
 	"go-template-clean-architecture/internal/delivery/http/middleware"
+	"go-template-clean-architecture/internal/domain/entity"
 	"go-template-clean-architecture/internal/infrastructure/cache"
 	"go-template-clean-architecture/internal/infrastructure/database"
 	"go-template-clean-architecture/internal/repository"
 	"go-template-clean-architecture/internal/service"
+	"go-template-clean-architecture/internal/service/notification"
 	"go-template-clean-architecture/internal/usecase"
+	"go-template-clean-architecture/pkg/captcha"
 	"go-template-clean-architecture/pkg/jwt"
+	"go-template-clean-architecture/pkg/logger"
+	"go-template-clean-architecture/pkg/oauth"
+	"go-template-clean-architecture/pkg/payment"
+	"go-template-clean-architecture/pkg/storage"
 	"go-template-clean-architecture/pkg/validator"
 
 	"github.com/redis/go-redis/v9"
@@ -32,6 +39,22 @@ type App struct {
 	DB          *gorm.DB
 	RedisClient *redis.Client
 	Server      *http.Server
+
+	// Per-package loggers, reconfigured on SIGHUP
+	Logs *logger.Manager
+
+	// Background services stopped on shutdown
+	RedisSyncService          *service.RedisSyncService
+	AbsenceMonitor            *service.AbsenceMonitorService
+	UsageMeter                *service.UsageMeterService
+	OutboxService             *service.OutboxService
+	EventBus                  *service.EventBus
+	NotificationService       *service.NotificationService
+	BookingSagaService        *service.BookingSagaService
+	ScheduleGenerationService *service.ScheduleGenerationService
+	ReminderService           *service.ReminderService
+	AgendaDigestService       *service.AgendaDigestService
+	WebhookService            *service.WebhookService
 }
 
 // New creates a new App instance with all dependencies initialized
@@ -47,6 +70,13 @@ func New() (*App, error) {
 		return nil, fmt.Errorf("failed to load config: %w", err)
 	}
 	app.Config = cfg
+
+	// Apply log format, levels and sampling from config
+	logs, err := logger.New(cfg.Log)
+	if err != nil {
+		return nil, fmt.Errorf("failed to configure logging: %w", err)
+	}
+	app.Logs = logs
 	logrus.Info("Configuration loaded successfully")
 
 	// Initialize database
@@ -65,14 +95,58 @@ func New() (*App, error) {
 	app.RedisClient = redisClient
 	logrus.Info("Redis connected successfully")
 
+	// Initialize JWT service (loads the RS256 signing keys)
+	jwtService, err := jwt.NewJWTService(cfg.JWT)
+	if err != nil {
+		return nil, fmt.Errorf("failed to configure JWT: %w", err)
+	}
+
+	// Initialize CAPTCHA verification of the public register and login endpoints
+	captchaVerifier, err := captcha.NewVerifier(cfg.Captcha)
+	if err != nil {
+		return nil, fmt.Errorf("failed to configure CAPTCHA: %w", err)
+	}
+
+	// Initialize the storage of uploaded files (doctor photos)
+	fileStorage, err := storage.New(cfg.Storage)
+	if err != nil {
+		return nil, fmt.Errorf("failed to configure file storage: %w", err)
+	}
+
+	// Initialize the payment gateway of booking fees, bookings are free of charge without PAYMENT_GATEWAY
+	paymentGateway, err := payment.New(cfg.Payment)
+	if err != nil {
+		return nil, fmt.Errorf("failed to configure payment gateway: %w", err)
+	}
+
+	// Initialize the email provider of notifications, emails are logged without SMTP_HOST
+	var emailSender notification.Sender
+	if cfg.SMTP.Enabled() {
+		smtpSender, err := notification.NewSMTPSender(cfg.SMTP)
+		if err != nil {
+			return nil, fmt.Errorf("failed to configure SMTP: %w", err)
+		}
+		emailSender = smtpSender
+	}
+
+	// Initialize the push provider of notifications, pushes are logged without FCM_CREDENTIALS_FILE
+	var pushSender notification.Sender
+	if cfg.FCM.Enabled() {
+		fcmSender, err := notification.NewFCMSender(cfg.FCM)
+		if err != nil {
+			return nil, fmt.Errorf("failed to configure FCM: %w", err)
+		}
+		pushSender = fcmSender
+	}
+
 	// Initialize all layers
-	server := initializeServer(cfg, db, redisClient)
+	server := app.initializeServer(cfg, db, redisClient, jwtService, captchaVerifier, fileStorage, emailSender, pushSender, paymentGateway)
 	app.Server = server
 
 	return app, nil
 }
 
-// setupLogger configures the logrus logger
+// setupLogger configures the logrus logger until the log config is loaded
 func setupLogger() {
 	logrus.SetFormatter(&logrus.JSONFormatter{})
 	logrus.SetOutput(os.Stdout)
@@ -80,28 +154,90 @@ func setupLogger() {
 }
 
 // initializeServer creates and configures the HTTP server
-func initializeServer(cfg *config.Config, db *gorm.DB, redisClient *redis.Client) *http.Server {
-	// Initialize JWT service
-	jwtService := jwt.NewJWTService(cfg.JWT)
+func (app *App) initializeServer(cfg *config.Config, db *gorm.DB, redisClient *redis.Client, jwtService *jwt.JWTService, captchaVerifier *captcha.Verifier, fileStorage storage.Storage, emailSender, pushSender notification.Sender, paymentGateway payment.Gateway) *http.Server {
+	googleProvider := oauth.NewGoogleProvider(cfg.GoogleOAuth)
 
 	// Initialize validator
-	customValidator := validator.NewValidator()
+	customValidator := validator.NewValidator(cfg.App.StrictJSON)
 
 	// Initialize repositories
 	userRepo := repository.NewUserRepository()
 	roleRepo := repository.NewRoleRepository()
+	permissionRepo := repository.NewPermissionRepository()
+	apiKeyRepo := repository.NewAPIKeyRepository()
+	loginHistoryRepo := repository.NewLoginHistoryRepository()
 	doctorProfileRepo := repository.NewDoctorProfileRepository()
 	patientProfileRepo := repository.NewPatientProfileRepository()
 	doctorScheduleRepo := repository.NewDoctorScheduleRepository()
 	bookingRepo := repository.NewBookingRepository()
 	auditRepo := repository.NewAuditLogRepository()
-
-	// Initialize logger
-	log := logrus.StandardLogger()
+	specDefaultRepo := repository.NewSpecializationDefaultRepository()
+	waitFeedbackRepo := repository.NewWaitFeedbackRepository()
+	queueStatRepo := repository.NewDoctorQueueStatRepository()
+	usageRepo := repository.NewUsageRecordRepository()
+	outboxRepo := repository.NewOutboxRepository()
+	scheduleSlotRepo := repository.NewScheduleSlotRepository()
+	broadcastRepo := repository.NewBroadcastRepository()
+	holidayRepo := repository.NewHolidayRepository()
+	notificationRepo := repository.NewNotificationRepository()
+	deviceTokenRepo := repository.NewDeviceTokenRepository()
+	notificationTemplateRepo := repository.NewNotificationTemplateRepository()
+	notificationPreferenceRepo := repository.NewNotificationPreferenceRepository()
+	bookingSagaRepo := repository.NewBookingSagaRepository()
+	scheduleTemplateRepo := repository.NewScheduleTemplateRepository()
+	generationRunRepo := repository.NewScheduleGenerationRunRepository()
+	scheduleVersionRepo := repository.NewScheduleVersionRepository()
+	medicalRecordRepo := repository.NewMedicalRecordRepository()
+	doctorReviewRepo := repository.NewDoctorReviewRepository()
+	specializationRepo := repository.NewSpecializationRepository()
+	dataExportRepo := repository.NewDataExportRepository()
+	doctorLeaveRepo := repository.NewDoctorLeaveRepository()
+	webhookSubscriptionRepo := repository.NewWebhookSubscriptionRepository()
+	webhookDeliveryRepo := repository.NewWebhookDeliveryRepository()
+	paymentRepo := repository.NewPaymentRepository()
+	invoiceRepo := repository.NewInvoiceRepository()
+
+	// Initialize loggers (levels configurable per package)
+	serviceLog := app.Logs.For("service")
+	log := app.Logs.For("usecase")
 
 	// Initialize services
-	auditService := service.NewAuditService(db, log, auditRepo)
-	redisSyncService := service.NewRedisSyncService(db, redisClient, log)
+	outboxService := service.NewOutboxService(db, serviceLog, outboxRepo)
+	app.OutboxService = outboxService
+	eventBus := service.NewEventBus(redisClient, serviceLog, cfg, outboxService)
+	app.EventBus = eventBus
+	auditService := service.NewAuditService(db, serviceLog, auditRepo, outboxService, eventBus)
+	permissionService := service.NewPermissionService(db, serviceLog, redisClient, permissionRepo)
+	listingCache := service.NewListingCacheService(serviceLog, redisClient)
+	doctorReviewService := service.NewDoctorReviewService(db, serviceLog, redisClient, doctorReviewRepo)
+	apiKeyService := service.NewAPIKeyService(db, serviceLog, apiKeyRepo)
+	redisSyncService := service.NewRedisSyncService(db, redisClient, serviceLog)
+	app.RedisSyncService = redisSyncService
+	formatService := service.NewFormatService(cfg, serviceLog)
+	usageMeter := service.NewUsageMeterService(db, redisClient, serviceLog, cfg, usageRepo)
+	usageMeter.Start()
+	app.UsageMeter = usageMeter
+	notificationSenders := notification.NewSenders(notification.NewLogSender(serviceLog))
+	if emailSender != nil {
+		notificationSenders.Register(entity.NotificationChannelEmail, emailSender)
+	}
+	if pushSender != nil {
+		notificationSenders.Register(entity.NotificationChannelPush, pushSender)
+	}
+	notificationService := service.NewNotificationService(db, serviceLog, cfg, notificationRepo, userRepo, notificationSenders, deviceTokenRepo, notificationTemplateRepo, notificationPreferenceRepo)
+	notificationService.Start()
+	app.NotificationService = notificationService
+	reminderService := service.NewReminderService(db, redisClient, serviceLog, cfg, bookingRepo, notificationPreferenceRepo, notificationService, formatService)
+	reminderService.Start()
+	app.ReminderService = reminderService
+	agendaDigestService := service.NewAgendaDigestService(db, redisClient, serviceLog, cfg, doctorScheduleRepo, bookingRepo, notificationPreferenceRepo, notificationService, formatService)
+	agendaDigestService.Start()
+	app.AgendaDigestService = agendaDigestService
+	bookingSagaService := service.NewBookingSagaService(db, serviceLog, bookingSagaRepo)
+	app.BookingSagaService = bookingSagaService
+	generationService := service.NewScheduleGenerationService(redisClient, serviceLog, cfg)
+	app.ScheduleGenerationService = generationService
+	paymentService := service.NewPaymentService(db, serviceLog, cfg, paymentRepo, paymentGateway)
 
 	// Re-sync Redis from database on startup (Disaster Recovery)
 	// CRITICAL: Must run BEFORE accepting traffic to avoid race conditions
@@ -110,33 +246,134 @@ func initializeServer(cfg *config.Config, db *gorm.DB, redisClient *redis.Client
 	}
 
 	// Initialize usecases
-	authUsecase := usecase.NewAuthUsecase(db, log, userRepo, roleRepo, jwtService, redisClient, auditService)
-	doctorProfileUsecase := usecase.NewDoctorProfileUsecase(db, log, userRepo, doctorProfileRepo, auditService)
-	doctorScheduleUsecase := usecase.NewDoctorScheduleUsecase(db, log, doctorScheduleRepo, auditService, redisSyncService)
+	authUsecase := usecase.NewAuthUsecase(db, log, userRepo, roleRepo, jwtService, redisClient, auditService, cfg, googleProvider, patientProfileRepo, notificationService, loginHistoryRepo, permissionService, outboxService, formatService, specDefaultRepo, eventBus)
+	doctorProfileUsecase := usecase.NewDoctorProfileUsecase(db, log, userRepo, doctorProfileRepo, waitFeedbackRepo, auditService, fileStorage, doctorReviewService, specializationRepo, specDefaultRepo, cfg, doctorScheduleRepo, redisSyncService, listingCache)
+	doctorScheduleUsecase := usecase.NewDoctorScheduleUsecase(db, log, cfg, doctorScheduleRepo, specDefaultRepo, bookingRepo, queueStatRepo, scheduleSlotRepo, holidayRepo, auditService, redisSyncService, formatService, outboxService, notificationService, scheduleVersionRepo, listingCache, doctorLeaveRepo, paymentRepo)
 	auditUsecase := usecase.NewAuditLogUsecase(db, log, auditRepo)
+	specDefaultUsecase := usecase.NewSpecializationDefaultUsecase(db, log, specDefaultRepo, auditService, specializationRepo)
+	holidayUsecase := usecase.NewHolidayUsecase(db, log, holidayRepo, doctorScheduleRepo, auditService, scheduleVersionRepo, listingCache)
+	doctorLeaveUsecase := usecase.NewDoctorLeaveUsecase(db, log, doctorLeaveRepo, doctorProfileRepo, doctorScheduleRepo, scheduleVersionRepo, auditService, outboxService, listingCache)
+	specializationUsecase := usecase.NewSpecializationUsecase(db, log, specializationRepo, auditService, listingCache)
+	reportUsecase := usecase.NewReportUsecase(db, log, cfg, waitFeedbackRepo, usageRepo, usageMeter, doctorScheduleRepo, doctorProfileRepo, reminderService)
 
 	// Initialize handlers
 	authHandler := handler.NewAuthHandler(authUsecase, customValidator, jwtService)
 	doctorHandler := handler.NewDoctorHandler(doctorProfileUsecase, customValidator)
 	doctorScheduleHandler := handler.NewDoctorScheduleHandler(doctorScheduleUsecase, customValidator)
 	auditHandler := handler.NewAuditLogHandler(auditUsecase)
+	specDefaultHandler := handler.NewSpecializationDefaultHandler(specDefaultUsecase, customValidator)
+	holidayHandler := handler.NewHolidayHandler(holidayUsecase, customValidator)
+	doctorLeaveHandler := handler.NewDoctorLeaveHandler(doctorLeaveUsecase, customValidator)
+	specializationHandler := handler.NewSpecializationHandler(specializationUsecase, customValidator)
+	reportHandler := handler.NewReportHandler(reportUsecase)
+
+	// Runtime log levels
+	logLevelUsecase := usecase.NewLogLevelUsecase(db, log, app.Logs, auditService)
+	logLevelHandler := handler.NewLogLevelHandler(logLevelUsecase, customValidator)
+
+	// Redis booking counters (admin drift correction)
+	redisStateUsecase := usecase.NewRedisStateUsecase(db, log, doctorScheduleRepo, bookingRepo, auditService, redisSyncService)
+	redisStateHandler := handler.NewRedisStateHandler(redisStateUsecase, customValidator)
 
 	// Patient booking
-	bookingUsecase := usecase.NewPatientBookingUsecase(db, log, bookingRepo, doctorScheduleRepo, redisSyncService)
+	bookingUsecase := usecase.NewPatientBookingUsecase(db, log, cfg, bookingRepo, doctorScheduleRepo, waitFeedbackRepo, queueStatRepo, scheduleSlotRepo, redisSyncService, auditService, formatService, usageMeter, outboxService, notificationService, bookingSagaService, patientProfileRepo, userRepo, eventBus, paymentService, paymentRepo)
 	bookingHandler := handler.NewBookingHandler(bookingUsecase, customValidator)
 
+	// Bulk booking import (clinic migration)
+	bookingImportUsecase := usecase.NewBookingImportUsecase(db, log, cfg, userRepo, patientProfileRepo, doctorScheduleRepo, bookingRepo, redisSyncService, auditService)
+	bookingImportHandler := handler.NewBookingImportHandler(bookingImportUsecase)
+
+	// Patient broadcasts (delivered through the outbox worker)
+	broadcastUsecase := usecase.NewBroadcastUsecase(db, log, cfg, broadcastRepo, userRepo, auditService, outboxService, notificationService)
+	broadcastHandler := handler.NewBroadcastHandler(broadcastUsecase, customValidator)
+
+	// Personal data exports of patients (generated by the outbox worker)
+	dataExportUsecase := usecase.NewDataExportUsecase(db, log, dataExportRepo, userRepo, bookingRepo, medicalRecordRepo, auditRepo, auditService, outboxService, notificationService, formatService, fileStorage)
+	dataExportHandler := handler.NewDataExportHandler(dataExportUsecase)
+
+	// Webhook subscriptions of external systems (deliveries recorded by an event bus handler)
+	webhookService := service.NewWebhookService(db, serviceLog, webhookSubscriptionRepo, webhookDeliveryRepo, eventBus)
+	webhookService.Start()
+	app.WebhookService = webhookService
+	webhookUsecase := usecase.NewWebhookUsecase(db, log, webhookSubscriptionRepo, webhookDeliveryRepo, webhookService, auditService)
+	webhookHandler := handler.NewWebhookHandler(webhookUsecase, customValidator)
+
+	// Booking fee payments (confirmed by gateway callbacks, unpaid bookings expired and refunds sent by the outbox worker)
+	paymentUsecase := usecase.NewPaymentUsecase(db, log, paymentRepo, bookingRepo, paymentService, outboxService, auditService, invoiceRepo, eventBus)
+	paymentHandler := handler.NewPaymentHandler(paymentUsecase)
+
+	// Invoices of paid and completed bookings (issued by event bus handlers)
+	invoiceUsecase := usecase.NewInvoiceUsecase(db, log, cfg, invoiceRepo, bookingRepo, paymentRepo, userRepo, auditService, formatService, eventBus)
+	invoiceHandler := handler.NewInvoiceHandler(invoiceUsecase)
+
+	// Booking side effects and broadcasts (outbox and event bus handlers are registered by the usecases above)
+	outboxService.Start()
+	eventBus.Start()
+
+	// Recovery of interrupted booking sagas (steps are registered by the booking usecase)
+	bookingSagaService.Start()
+	bookingSagaUsecase := usecase.NewBookingSagaUsecase(db, log, bookingSagaRepo)
+	bookingSagaHandler := handler.NewBookingSagaHandler(bookingSagaUsecase)
+
+	// Notification history and provider delivery callbacks
+	notificationUsecase := usecase.NewNotificationUsecase(db, log, cfg, notificationRepo, bookingRepo, deviceTokenRepo, notificationPreferenceRepo, notificationService, auditService)
+	notificationHandler := handler.NewNotificationHandler(notificationUsecase, customValidator)
+
+	// Admin-editable notification content
+	notificationTemplateUsecase := usecase.NewNotificationTemplateUsecase(db, log, notificationTemplateRepo, auditService, formatService)
+	notificationTemplateHandler := handler.NewNotificationTemplateHandler(notificationTemplateUsecase, customValidator)
+
+	// Partner roster pre-registration and account claim
+	patientRosterUsecase := usecase.NewPatientRosterUsecase(db, log, userRepo, patientProfileRepo, redisClient, notificationService, auditService)
+	patientRosterHandler := handler.NewPatientRosterHandler(patientRosterUsecase, customValidator)
+
 	// Patient profile
-	patientProfileUsecase := usecase.NewPatientProfileUsecase(db, log, userRepo, patientProfileRepo, auditService)
+	patientProfileUsecase := usecase.NewPatientProfileUsecase(db, log, userRepo, patientProfileRepo, bookingRepo, auditRepo, outboxRepo, auditService, redisClient, redisSyncService, deviceTokenRepo)
 	patientHandler := handler.NewPatientHandler(patientProfileUsecase, customValidator)
 
+	// Medical records of visits (doctor and patient portals)
+	medicalRecordUsecase := usecase.NewMedicalRecordUsecase(db, log, medicalRecordRepo, bookingRepo, auditService, userRepo)
+	medicalRecordHandler := handler.NewMedicalRecordHandler(medicalRecordUsecase, customValidator)
+
+	// Patient reviews of visits and public doctor ratings
+	doctorReviewUsecase := usecase.NewDoctorReviewUsecase(db, log, doctorReviewRepo, bookingRepo, doctorProfileRepo, doctorReviewService)
+	doctorReviewHandler := handler.NewDoctorReviewHandler(doctorReviewUsecase, customValidator)
+
+	// Schedule templates, expanded nightly (the usecase registers the generator)
+	scheduleTemplateUsecase := usecase.NewScheduleTemplateUsecase(db, log, cfg, scheduleTemplateRepo, generationRunRepo, doctorScheduleRepo, scheduleSlotRepo, holidayRepo, auditService, redisSyncService, generationService, scheduleVersionRepo, listingCache, doctorLeaveRepo)
+	scheduleTemplateHandler := handler.NewScheduleTemplateHandler(scheduleTemplateUsecase, customValidator)
+	generationService.Start()
+
+	// Doctor absence detection (background)
+	absenceMonitor := service.NewAbsenceMonitorService(db, serviceLog, cfg, doctorScheduleRepo, auditService, scheduleVersionRepo)
+	absenceMonitor.Start()
+	app.AbsenceMonitor = absenceMonitor
+
+	// Roles, permissions and staff accounts
+	roleUsecase := usecase.NewRoleUsecase(db, log, roleRepo, permissionRepo, userRepo, auditService, permissionService)
+	roleHandler := handler.NewRoleHandler(roleUsecase, customValidator)
+	apiKeyUsecase := usecase.NewAPIKeyUsecase(db, log, apiKeyRepo, permissionRepo, auditService)
+	apiKeyHandler := handler.NewAPIKeyHandler(apiKeyUsecase, customValidator)
+
 	// Initialize middleware
-	authMiddleware := middleware.NewAuthMiddleware(jwtService, redisClient)
+	authMiddleware := middleware.NewAuthMiddleware(jwtService, redisClient, db, serviceLog, auditService)
+	permissionMiddleware := middleware.NewPermissionMiddleware(permissionService)
+	apiKeyMiddleware := middleware.NewAPIKeyMiddleware(apiKeyService)
 	corsMiddleware := middleware.NewCORSMiddleware()
+	usageMiddleware := middleware.NewUsageMiddleware(usageMeter, "/api/v1/health")
+	versionMiddleware := middleware.NewVersionGateMiddleware(cfg.Client, "/api/v1/health")
+	clientInfoMiddleware := middleware.NewClientInfoMiddleware()
+	captchaMiddleware := middleware.NewCaptchaMiddleware(captchaVerifier, log)
 
 	// Initialize router
-	router := deliveryHttp.NewRouter(authHandler, doctorHandler, doctorScheduleHandler, bookingHandler, patientHandler, authMiddleware, corsMiddleware, auditHandler)
+	router := deliveryHttp.NewRouter(authHandler, doctorHandler, doctorScheduleHandler, bookingHandler, patientHandler, authMiddleware, corsMiddleware, auditHandler, specDefaultHandler, reportHandler, usageMiddleware, bookingImportHandler, broadcastHandler, holidayHandler, versionMiddleware, logLevelHandler, redisStateHandler, notificationHandler, patientRosterHandler, bookingSagaHandler, scheduleTemplateHandler, roleHandler, permissionMiddleware, clientInfoMiddleware, apiKeyHandler, apiKeyMiddleware, captchaMiddleware, medicalRecordHandler, doctorReviewHandler, specializationHandler, dataExportHandler, doctorLeaveHandler, notificationTemplateHandler, webhookHandler, paymentHandler, invoiceHandler)
 	httpRouter := router.Setup()
 
+	// Files of the local storage are served by the API, S3 serves its own
+	if localStorage, ok := fileStorage.(*storage.LocalStorage); ok {
+		httpRouter.PathPrefix(storage.LocalURLPrefix).Handler(localStorage.Handler()).Methods(http.MethodGet, http.MethodHead)
+	}
+
 	// Create server
 	serverAddr := fmt.Sprintf(":%s", cfg.App.Port)
 	return &http.Server{
@@ -156,10 +393,33 @@ func (app *App) Run() {
 		}
 	}()
 
+	// Reload log settings on SIGHUP
+	go app.watchLogReload()
+
 	// Wait for interrupt signal
 	app.waitForShutdown()
 }
 
+// watchLogReload re-reads the config on SIGHUP and applies the log settings.
+// Runtime level changes made through the admin endpoint are reset.
+func (app *App) watchLogReload() {
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+
+	for range hup {
+		cfg, err := config.LoadConfig()
+		if err != nil {
+			logrus.Errorf("Failed to reload config on SIGHUP: %v", err)
+			continue
+		}
+		if err := app.Logs.Apply(cfg.Log); err != nil {
+			logrus.Errorf("Failed to apply log config on SIGHUP: %v", err)
+			continue
+		}
+		logrus.Infof("Log config reloaded: format=%s, level=%s", cfg.Log.Format, cfg.Log.Level)
+	}
+}
+
 // waitForShutdown blocks until an interrupt signal is received
 func (app *App) waitForShutdown() {
 	quit := make(chan os.Signal, 1)
@@ -185,6 +445,43 @@ func (app *App) waitForShutdown() {
 
 // Close closes all connections (database, redis, etc.)
 func (app *App) Close() {
+	// Stop background services before closing their connections
+	if app.AbsenceMonitor != nil {
+		app.AbsenceMonitor.Stop()
+	}
+	if app.UsageMeter != nil {
+		app.UsageMeter.Stop()
+	}
+	if app.OutboxService != nil {
+		app.OutboxService.Stop()
+	}
+	// After the outbox: handles the events still queued in process
+	if app.EventBus != nil {
+		app.EventBus.Stop()
+	}
+	if app.WebhookService != nil {
+		app.WebhookService.Stop()
+	}
+	if app.ReminderService != nil {
+		app.ReminderService.Stop()
+	}
+	if app.AgendaDigestService != nil {
+		app.AgendaDigestService.Stop()
+	}
+	// After the services recording notifications: drains the queued sends
+	if app.NotificationService != nil {
+		app.NotificationService.Stop()
+	}
+	if app.BookingSagaService != nil {
+		app.BookingSagaService.Stop()
+	}
+	if app.ScheduleGenerationService != nil {
+		app.ScheduleGenerationService.Stop()
+	}
+	if app.RedisSyncService != nil {
+		app.RedisSyncService.Stop()
+	}
+
 	// Close database connection
 	if app.DB != nil {
 		sqlDB, err := app.DB.DB()
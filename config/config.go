@@ -1,21 +1,44 @@
 package config
 
 import (
+	"strings"
 	"time"
 
 	"github.com/spf13/viper"
 )
 
 type Config struct {
-	App   AppConfig
-	DB    DBConfig
-	Redis RedisConfig
-	JWT   JWTConfig
+	App          AppConfig
+	DB           DBConfig
+	Redis        RedisConfig
+	JWT          JWTConfig
+	TwoFactor    TwoFactorConfig
+	Session      SessionConfig
+	GoogleOAuth  GoogleOAuthConfig
+	Captcha      CaptchaConfig
+	Storage      StorageConfig
+	Booking      BookingConfig
+	Absence      AbsenceConfig
+	Generation   GenerationConfig
+	Broadcast    BroadcastConfig
+	Notification NotificationConfig
+	Reminder     ReminderConfig
+	EventBus     EventBusConfig
+	Payment      PaymentConfig
+	Invoice      InvoiceConfig
+	SMTP         SMTPConfig
+	FCM          FCMConfig
+	Client       ClientConfig
+	Log          LogConfig
 }
 
 type AppConfig struct {
-	Port string
-	Env  string
+	Port       string
+	Env        string
+	Location   *time.Location
+	Locale     string // Clinic locale for formatted dates/times, e.g. "id-ID"
+	TenantID   string // Tenant usage is metered for (billing)
+	StrictJSON bool   // Reject request bodies with unknown fields
 }
 
 type DBConfig struct {
@@ -37,6 +60,218 @@ type JWTConfig struct {
 	Secret        string
 	AccessExpiry  time.Duration
 	RefreshExpiry time.Duration
+	// RememberMeExpiry is the refresh token lifetime of sessions signed in with "remember me"
+	RememberMeExpiry time.Duration
+	// Algorithm is "HS256" (shared Secret) or "RS256" (SigningKeys, published as JWKS)
+	Algorithm string
+	// SigningKeys are the RSA private key PEM files by key ID. All of them verify tokens,
+	// ActiveKeyID signs new ones. To rotate: add a key (verifiers cache the JWKS for
+	// 5 minutes), then make it active, and remove the old one once the tokens it signed
+	// have expired (JWT_REFRESH_EXPIRY).
+	SigningKeys map[string]string
+	ActiveKeyID string
+}
+
+// TwoFactorConfig holds TOTP two-factor authentication settings
+type TwoFactorConfig struct {
+	// Issuer is the account name prefix shown by authenticator apps
+	Issuer string
+}
+
+// SessionConfig limits the signed-in devices of a user
+type SessionConfig struct {
+	// MaxPerUser is the number of concurrent sessions; a login beyond it signs out
+	// the oldest session. 0 = unlimited.
+	MaxPerUser int
+}
+
+// GoogleOAuthConfig holds the Google sign-in (OAuth 2.0) client, disabled when not set
+type GoogleOAuthConfig struct {
+	ClientID     string
+	ClientSecret string
+	// RedirectURL is the callback, e.g. https://api.example.com/api/v1/auth/oauth/google/callback
+	RedirectURL string
+}
+
+// CaptchaConfig holds the CAPTCHA check of the public register and login endpoints,
+// disabled when no provider is set (e.g. local development)
+type CaptchaConfig struct {
+	// Provider is recaptcha, hcaptcha or turnstile
+	Provider  string
+	SecretKey string
+	// MinScore rejects reCAPTCHA v3 tokens scored below it (0.0 bot - 1.0 human)
+	MinScore float64
+}
+
+// StorageConfig holds where uploaded files (doctor photos) are kept
+type StorageConfig struct {
+	// Driver is "local" (LocalDir, served by the API under /uploads/) or "s3"
+	Driver   string
+	LocalDir string
+	// PublicURL is the base URL files are linked at, e.g. a CDN in front of the bucket.
+	// Defaults to the API (local) or the bucket URL (s3).
+	PublicURL string
+	// S3-compatible object store (AWS S3, MinIO, Cloudflare R2, ...)
+	S3Endpoint  string // e.g. https://s3.ap-southeast-1.amazonaws.com
+	S3Region    string
+	S3Bucket    string
+	S3AccessKey string
+	S3SecretKey string
+	// S3PathStyle puts the bucket in the path instead of the host name (MinIO)
+	S3PathStyle bool
+}
+
+// BookingConfig holds booking policy settings
+type BookingConfig struct {
+	// Cutoff is how long before a schedule starts new bookings are closed.
+	// Schedules may override it with their own minimum lead time (min_lead_hours).
+	Cutoff time.Duration
+	// CancellationDeadline is how long before a schedule starts patients can no longer cancel
+	CancellationDeadline time.Duration
+	// MaxAdvanceDays is how many days ahead patients can book and see schedules (0 = unlimited)
+	MaxAdvanceDays int
+	// RequiredProfileFields are the patient profile fields that must be present to book
+	// (e.g. phone_number, emergency_contact). Empty = no requirement.
+	RequiredProfileFields []string
+}
+
+// AbsenceConfig holds doctor absence detection settings
+type AbsenceConfig struct {
+	// Threshold is how long after schedule start without check-in before flagging
+	Threshold time.Duration
+	// PauseBookings closes new bookings on flagged schedules
+	PauseBookings bool
+}
+
+// GenerationConfig holds the nightly schedule generation settings (schedule templates)
+type GenerationConfig struct {
+	// Days is how many days ahead, starting tomorrow, schedules are generated
+	Days int
+	// Hour is the local hour (0-23) after which the nightly run starts
+	Hour int
+}
+
+// ClientConfig holds mobile/web client compatibility settings
+type ClientConfig struct {
+	// MinVersions is the minimum supported app version per platform (X-App-Platform), e.g. "ios" -> "2.3.0".
+	// Platforms without an entry are not gated.
+	MinVersions map[string]string
+}
+
+// BroadcastConfig holds patient broadcast delivery settings
+type BroadcastConfig struct {
+	// RatePerMinute throttles how many broadcast notifications are delivered per minute
+	RatePerMinute int
+}
+
+// NotificationConfig holds patient notification delivery settings
+type NotificationConfig struct {
+	// MaxAttempts is how many times a notification is sent before it is dead-lettered
+	MaxAttempts int
+	// Workers is how many notifications are sent concurrently
+	Workers int
+	// WebhookSecret authenticates provider delivery callbacks (X-Webhook-Secret), callbacks are rejected when empty
+	WebhookSecret string
+}
+
+// ReminderConfig holds the appointment reminder settings
+type ReminderConfig struct {
+	// DayBeforeHour is the local hour (0-23) after which patients booked tomorrow are reminded, -1 disables
+	DayBeforeHour int
+	// SameDayLead is how long before their visit patients are reminded on the day, 0 disables
+	SameDayLead time.Duration
+	// AgendaDigestHour is the local hour (0-23) after which doctors get the agenda of the day, -1 disables
+	AgendaDigestHour int
+}
+
+// EventBusConfig holds how domain events reach their subscribers
+type EventBusConfig struct {
+	// Backend is "memory" (handled by the publishing instance, lost on a crash) or "redis"
+	// (a Redis stream shared by the instances, retried until handled)
+	Backend string
+}
+
+// PaymentConfig holds the payment gateway of booking fees, bookings are free of charge without a gateway
+type PaymentConfig struct {
+	// Gateway is midtrans, xendit or stripe
+	Gateway string
+	// SecretKey is the API key of the gateway: the Midtrans server key, the Xendit secret key
+	// or the Stripe secret key
+	SecretKey string
+	// CallbackToken authenticates the payment callbacks: the Xendit callback verification token
+	// or the Stripe webhook signing secret. Midtrans signs its callbacks with the server key.
+	CallbackToken string
+	// Sandbox uses the Midtrans sandbox, Xendit and Stripe tell test mode from the keys
+	Sandbox bool
+	// Currency is the ISO 4217 code of the consultation fees, e.g. IDR
+	Currency string
+	// Expiry is how long patients have to pay before the booking is cancelled.
+	// Stripe keeps checkout pages open for at least 30 minutes.
+	Expiry time.Duration
+	// SuccessURL and CancelURL are where the payment page sends patients back to
+	SuccessURL string
+	CancelURL  string
+}
+
+// Enabled reports whether bookings with a consultation fee must be paid
+func (c PaymentConfig) Enabled() bool {
+	return c.Gateway != ""
+}
+
+// InvoiceConfig holds the numbering and the issuer of booking invoices
+type InvoiceConfig struct {
+	// Prefix of the invoice numbers, e.g. INV gives INV-2026-000001
+	Prefix string
+	// AdminFee is added to the consultation fee of every invoiced booking, and to the
+	// payment of bookings paid through the gateway (smallest currency unit)
+	AdminFee int64
+	// IssuerName and IssuerAddress head the invoice documents
+	IssuerName    string
+	IssuerAddress string
+}
+
+// SMTPConfig holds the email provider settings, email notifications are only logged without a host
+type SMTPConfig struct {
+	Host     string
+	Port     int
+	Username string // Empty = no authentication
+	Password string
+	From     string
+	FromName string
+	// DefaultSubject is used for notifications without their own subject
+	DefaultSubject string
+}
+
+// Enabled reports whether emails are sent through SMTP
+func (c SMTPConfig) Enabled() bool {
+	return c.Host != ""
+}
+
+// FCMConfig holds the push notification settings, pushes are only logged without credentials
+type FCMConfig struct {
+	// CredentialsFile is the service account key file (JSON) of the Firebase project
+	CredentialsFile string
+	// DefaultTitle is the title of pushes without their own
+	DefaultTitle string
+}
+
+// Enabled reports whether pushes are sent through FCM
+func (c FCMConfig) Enabled() bool {
+	return c.CredentialsFile != ""
+}
+
+// LogConfig holds logging output settings
+type LogConfig struct {
+	// Format is "json" (log shippers) or "text" (human-readable console)
+	Format string
+	// Level is the default level for every component, e.g. "info"
+	Level string
+	// PackageLevels overrides Level per component, e.g. "service" -> "debug"
+	PackageLevels map[string]string
+	// SampleInitial and SampleThereafter sample info/debug entries: per message and second,
+	// the first SampleInitial are logged, then every SampleThereafter-th. 0 disables sampling.
+	SampleInitial    int
+	SampleThereafter int
 }
 
 func LoadConfig() (*Config, error) {
@@ -57,10 +292,215 @@ func LoadConfig() (*Config, error) {
 		refreshExpiry = 7 * 24 * time.Hour
 	}
 
+	rememberMeExpiry, err := time.ParseDuration(viper.GetString("JWT_REMEMBER_ME_EXPIRY"))
+	if err != nil {
+		rememberMeExpiry = 30 * 24 * time.Hour
+	}
+
+	jwtAlgorithm := strings.ToUpper(viper.GetString("JWT_ALGORITHM"))
+	if jwtAlgorithm == "" {
+		jwtAlgorithm = "HS256"
+	}
+
+	// JWT_SIGNING_KEYS=2026-01=keys/jwt-2026-01.pem,2026-07=keys/jwt-2026-07.pem
+	signingKeys := make(map[string]string)
+	for _, pair := range strings.Split(viper.GetString("JWT_SIGNING_KEYS"), ",") {
+		keyID, path, ok := strings.Cut(pair, "=")
+		keyID = strings.TrimSpace(keyID)
+		path = strings.TrimSpace(path)
+		if ok && keyID != "" && path != "" {
+			signingKeys[keyID] = path
+		}
+	}
+
+	timezone := viper.GetString("APP_TIMEZONE")
+	if timezone == "" {
+		timezone = "Asia/Jakarta"
+	}
+	location, err := time.LoadLocation(timezone)
+	if err != nil {
+		location = time.Local
+	}
+
+	locale := viper.GetString("APP_LOCALE")
+	if locale == "" {
+		locale = "id-ID"
+	}
+
+	tenantID := viper.GetString("APP_TENANT_ID")
+	if tenantID == "" {
+		tenantID = "default"
+	}
+
+	twoFactorIssuer := viper.GetString("TWO_FACTOR_ISSUER")
+	if twoFactorIssuer == "" {
+		twoFactorIssuer = "Medical Booking"
+	}
+
+	captchaMinScore := 0.5
+	if raw := viper.GetString("CAPTCHA_MIN_SCORE"); raw != "" {
+		captchaMinScore = viper.GetFloat64("CAPTCHA_MIN_SCORE")
+	}
+
+	bookingCutoff, err := time.ParseDuration(viper.GetString("BOOKING_CUTOFF"))
+	if err != nil {
+		bookingCutoff = 30 * time.Minute
+	}
+
+	cancellationDeadline, err := time.ParseDuration(viper.GetString("BOOKING_CANCELLATION_DEADLINE"))
+	if err != nil {
+		cancellationDeadline = 2 * time.Hour
+	}
+
+	maxAdvanceDays := 30
+	if viper.IsSet("BOOKING_MAX_ADVANCE_DAYS") {
+		if days := viper.GetInt("BOOKING_MAX_ADVANCE_DAYS"); days >= 0 {
+			maxAdvanceDays = days
+		}
+	}
+
+	// BOOKING_REQUIRED_PROFILE_FIELDS=phone_number,emergency_contact
+	var requiredProfileFields []string
+	for _, field := range strings.Split(viper.GetString("BOOKING_REQUIRED_PROFILE_FIELDS"), ",") {
+		if field = strings.ToLower(strings.TrimSpace(field)); field != "" {
+			requiredProfileFields = append(requiredProfileFields, field)
+		}
+	}
+
+	absenceThreshold, err := time.ParseDuration(viper.GetString("ABSENCE_THRESHOLD"))
+	if err != nil {
+		absenceThreshold = 15 * time.Minute
+	}
+
+	generationDays := viper.GetInt("SCHEDULE_GENERATION_DAYS")
+	if generationDays <= 0 {
+		generationDays = 14
+	}
+
+	generationHour := 1
+	if viper.IsSet("SCHEDULE_GENERATION_HOUR") {
+		if hour := viper.GetInt("SCHEDULE_GENERATION_HOUR"); hour >= 0 && hour <= 23 {
+			generationHour = hour
+		}
+	}
+
+	reminderHour := 18
+	if viper.IsSet("REMINDER_DAY_BEFORE_HOUR") {
+		if hour := viper.GetInt("REMINDER_DAY_BEFORE_HOUR"); hour >= -1 && hour <= 23 {
+			reminderHour = hour
+		}
+	}
+
+	agendaDigestHour := 6
+	if viper.IsSet("AGENDA_DIGEST_HOUR") {
+		if hour := viper.GetInt("AGENDA_DIGEST_HOUR"); hour >= -1 && hour <= 23 {
+			agendaDigestHour = hour
+		}
+	}
+
+	reminderLead, err := time.ParseDuration(viper.GetString("REMINDER_SAME_DAY_LEAD"))
+	if err != nil || reminderLead < 0 {
+		reminderLead = 2 * time.Hour
+	}
+
+	broadcastRate := viper.GetInt("BROADCAST_RATE_PER_MINUTE")
+	if broadcastRate <= 0 {
+		broadcastRate = 120
+	}
+
+	notificationAttempts := viper.GetInt("NOTIFICATION_MAX_ATTEMPTS")
+	if notificationAttempts <= 0 {
+		notificationAttempts = 5
+	}
+	notificationWorkers := viper.GetInt("NOTIFICATION_WORKERS")
+	if notificationWorkers <= 0 {
+		notificationWorkers = 4
+	}
+
+	paymentCurrency := strings.ToUpper(strings.TrimSpace(viper.GetString("PAYMENT_CURRENCY")))
+	if paymentCurrency == "" {
+		paymentCurrency = "IDR"
+	}
+	paymentExpiry, err := time.ParseDuration(viper.GetString("PAYMENT_EXPIRY"))
+	if err != nil || paymentExpiry <= 0 {
+		paymentExpiry = 30 * time.Minute
+	}
+
+	invoicePrefix := strings.ToUpper(strings.TrimSpace(viper.GetString("INVOICE_PREFIX")))
+	if invoicePrefix == "" {
+		invoicePrefix = "INV"
+	}
+	invoiceAdminFee := viper.GetInt64("INVOICE_ADMIN_FEE")
+	if invoiceAdminFee < 0 {
+		invoiceAdminFee = 0
+	}
+	invoiceIssuer := viper.GetString("INVOICE_ISSUER_NAME")
+	if invoiceIssuer == "" {
+		invoiceIssuer = twoFactorIssuer
+	}
+
+	smtpPort := viper.GetInt("SMTP_PORT")
+	if smtpPort <= 0 {
+		smtpPort = 587
+	}
+	smtpFromName := viper.GetString("SMTP_FROM_NAME")
+	if smtpFromName == "" {
+		smtpFromName = "Medical Booking"
+	}
+	smtpSubject := viper.GetString("SMTP_DEFAULT_SUBJECT")
+	if smtpSubject == "" {
+		smtpSubject = smtpFromName
+	}
+
+	fcmTitle := viper.GetString("FCM_DEFAULT_TITLE")
+	if fcmTitle == "" {
+		fcmTitle = smtpFromName
+	}
+
+	// CLIENT_MIN_VERSIONS=ios=2.3.0,android=2.1.0
+	minVersions := make(map[string]string)
+	for _, pair := range strings.Split(viper.GetString("CLIENT_MIN_VERSIONS"), ",") {
+		platform, version, ok := strings.Cut(pair, "=")
+		platform = strings.ToLower(strings.TrimSpace(platform))
+		version = strings.TrimSpace(version)
+		if ok && platform != "" && version != "" {
+			minVersions[platform] = version
+		}
+	}
+
+	// Console logs while developing, JSON everywhere else
+	logFormat := strings.ToLower(viper.GetString("LOG_FORMAT"))
+	if logFormat == "" {
+		logFormat = "json"
+		if viper.GetString("APP_ENV") == "development" {
+			logFormat = "text"
+		}
+	}
+
+	logLevel := strings.ToLower(viper.GetString("LOG_LEVEL"))
+	if logLevel == "" {
+		logLevel = "info"
+	}
+
+	// LOG_PACKAGE_LEVELS=service=debug,usecase=warn
+	packageLevels := make(map[string]string)
+	for _, pair := range strings.Split(viper.GetString("LOG_PACKAGE_LEVELS"), ",") {
+		pkg, level, ok := strings.Cut(pair, "=")
+		pkg = strings.ToLower(strings.TrimSpace(pkg))
+		level = strings.ToLower(strings.TrimSpace(level))
+		if ok && pkg != "" && level != "" {
+			packageLevels[pkg] = level
+		}
+	}
+
 	config := &Config{
 		App: AppConfig{
-			Port: viper.GetString("APP_PORT"),
-			Env:  viper.GetString("APP_ENV"),
+			Port:       viper.GetString("APP_PORT"),
+			Env:        viper.GetString("APP_ENV"),
+			Location:   location,
+			Locale:     locale,
+			TenantID:   tenantID,
+			StrictJSON: viper.GetBool("APP_STRICT_JSON"),
 		},
 		DB: DBConfig{
 			Host:     viper.GetString("DB_HOST"),
@@ -76,9 +516,110 @@ func LoadConfig() (*Config, error) {
 			DB:       viper.GetInt("REDIS_DB"),
 		},
 		JWT: JWTConfig{
-			Secret:        viper.GetString("JWT_SECRET"),
-			AccessExpiry:  accessExpiry,
-			RefreshExpiry: refreshExpiry,
+			Secret:           viper.GetString("JWT_SECRET"),
+			AccessExpiry:     accessExpiry,
+			RefreshExpiry:    refreshExpiry,
+			RememberMeExpiry: rememberMeExpiry,
+			Algorithm:        jwtAlgorithm,
+			SigningKeys:      signingKeys,
+			ActiveKeyID:      strings.TrimSpace(viper.GetString("JWT_ACTIVE_KEY_ID")),
+		},
+		TwoFactor: TwoFactorConfig{
+			Issuer: twoFactorIssuer,
+		},
+		Session: SessionConfig{
+			MaxPerUser: max(viper.GetInt("SESSION_MAX_PER_USER"), 0),
+		},
+		GoogleOAuth: GoogleOAuthConfig{
+			ClientID:     viper.GetString("GOOGLE_OAUTH_CLIENT_ID"),
+			ClientSecret: viper.GetString("GOOGLE_OAUTH_CLIENT_SECRET"),
+			RedirectURL:  viper.GetString("GOOGLE_OAUTH_REDIRECT_URL"),
+		},
+		Captcha: CaptchaConfig{
+			Provider:  strings.ToLower(strings.TrimSpace(viper.GetString("CAPTCHA_PROVIDER"))),
+			SecretKey: viper.GetString("CAPTCHA_SECRET_KEY"),
+			MinScore:  captchaMinScore,
+		},
+		Storage: StorageConfig{
+			Driver:      strings.ToLower(strings.TrimSpace(viper.GetString("STORAGE_DRIVER"))),
+			LocalDir:    viper.GetString("STORAGE_LOCAL_DIR"),
+			PublicURL:   viper.GetString("STORAGE_PUBLIC_URL"),
+			S3Endpoint:  viper.GetString("STORAGE_S3_ENDPOINT"),
+			S3Region:    viper.GetString("STORAGE_S3_REGION"),
+			S3Bucket:    viper.GetString("STORAGE_S3_BUCKET"),
+			S3AccessKey: viper.GetString("STORAGE_S3_ACCESS_KEY"),
+			S3SecretKey: viper.GetString("STORAGE_S3_SECRET_KEY"),
+			S3PathStyle: viper.GetBool("STORAGE_S3_PATH_STYLE"),
+		},
+		Booking: BookingConfig{
+			Cutoff:               bookingCutoff,
+			CancellationDeadline: cancellationDeadline,
+			MaxAdvanceDays:       maxAdvanceDays,
+
+			RequiredProfileFields: requiredProfileFields,
+		},
+		Absence: AbsenceConfig{
+			Threshold:     absenceThreshold,
+			PauseBookings: viper.GetBool("ABSENCE_PAUSE_BOOKINGS"),
+		},
+		Generation: GenerationConfig{
+			Days: generationDays,
+			Hour: generationHour,
+		},
+		Broadcast: BroadcastConfig{
+			RatePerMinute: broadcastRate,
+		},
+		Notification: NotificationConfig{
+			MaxAttempts:   notificationAttempts,
+			Workers:       notificationWorkers,
+			WebhookSecret: viper.GetString("NOTIFICATION_WEBHOOK_SECRET"),
+		},
+		Reminder: ReminderConfig{
+			DayBeforeHour:    reminderHour,
+			SameDayLead:      reminderLead,
+			AgendaDigestHour: agendaDigestHour,
+		},
+		EventBus: EventBusConfig{
+			Backend: strings.ToLower(strings.TrimSpace(viper.GetString("EVENT_BUS_BACKEND"))),
+		},
+		Payment: PaymentConfig{
+			Gateway:       strings.ToLower(strings.TrimSpace(viper.GetString("PAYMENT_GATEWAY"))),
+			SecretKey:     viper.GetString("PAYMENT_SECRET_KEY"),
+			CallbackToken: viper.GetString("PAYMENT_CALLBACK_TOKEN"),
+			Sandbox:       viper.GetBool("PAYMENT_SANDBOX"),
+			Currency:      paymentCurrency,
+			Expiry:        paymentExpiry,
+			SuccessURL:    viper.GetString("PAYMENT_SUCCESS_URL"),
+			CancelURL:     viper.GetString("PAYMENT_CANCEL_URL"),
+		},
+		Invoice: InvoiceConfig{
+			Prefix:        invoicePrefix,
+			AdminFee:      invoiceAdminFee,
+			IssuerName:    invoiceIssuer,
+			IssuerAddress: viper.GetString("INVOICE_ISSUER_ADDRESS"),
+		},
+		SMTP: SMTPConfig{
+			Host:           viper.GetString("SMTP_HOST"),
+			Port:           smtpPort,
+			Username:       viper.GetString("SMTP_USERNAME"),
+			Password:       viper.GetString("SMTP_PASSWORD"),
+			From:           viper.GetString("SMTP_FROM"),
+			FromName:       smtpFromName,
+			DefaultSubject: smtpSubject,
+		},
+		FCM: FCMConfig{
+			CredentialsFile: viper.GetString("FCM_CREDENTIALS_FILE"),
+			DefaultTitle:    fcmTitle,
+		},
+		Client: ClientConfig{
+			MinVersions: minVersions,
+		},
+		Log: LogConfig{
+			Format:           logFormat,
+			Level:            logLevel,
+			PackageLevels:    packageLevels,
+			SampleInitial:    viper.GetInt("LOG_SAMPLE_INITIAL"),
+			SampleThereafter: viper.GetInt("LOG_SAMPLE_THEREAFTER"),
 		},
 	}
 
@@ -0,0 +1,50 @@
+package converter
+
+import (
+	"time"
+
+	"go-template-clean-architecture/internal/delivery/dto"
+	"go-template-clean-architecture/internal/domain/entity"
+)
+
+// APIKeyToResponse converts an APIKey entity (with permissions preloaded) to APIKeyResponse DTO
+func APIKeyToResponse(apiKey *entity.APIKey) *dto.APIKeyResponse {
+	if apiKey == nil {
+		return nil
+	}
+
+	permissions := make([]string, len(apiKey.Permissions))
+	for i, permission := range apiKey.Permissions {
+		permissions[i] = permission.Code
+	}
+
+	status := "active"
+	switch {
+	case apiKey.RevokedAt != nil:
+		status = "revoked"
+	case !apiKey.IsActive(time.Now()):
+		status = "expired"
+	}
+
+	return &dto.APIKeyResponse{
+		ID:          apiKey.ID,
+		Name:        apiKey.Name,
+		KeyPrefix:   apiKey.KeyPrefix,
+		Permissions: permissions,
+		Status:      status,
+		ExpiresAt:   apiKey.ExpiresAt,
+		LastUsedAt:  apiKey.LastUsedAt,
+		RevokedAt:   apiKey.RevokedAt,
+		CreatedBy:   apiKey.CreatedBy,
+		CreatedAt:   apiKey.CreatedAt,
+	}
+}
+
+// APIKeysToResponses converts a slice of APIKey entities to slice of APIKeyResponse DTOs
+func APIKeysToResponses(apiKeys []entity.APIKey) []dto.APIKeyResponse {
+	responses := make([]dto.APIKeyResponse, len(apiKeys))
+	for i, apiKey := range apiKeys {
+		responses[i] = *APIKeyToResponse(&apiKey)
+	}
+	return responses
+}
@@ -3,6 +3,8 @@ package converter
 import (
 	"go-template-clean-architecture/internal/delivery/dto"
 	"go-template-clean-architecture/internal/domain/entity"
+
+	"github.com/google/uuid"
 )
 
 // BookingToResponse converts a Booking entity to BookingResponse DTO
@@ -18,8 +20,25 @@ func BookingToResponse(booking *entity.Booking) *dto.BookingResponse {
 		BookingCode: booking.BookingCode,
 		QueueNumber: booking.QueueNumber,
 		Status:      string(booking.Status),
+		CalledAt:    booking.CalledAt,
+		Version:     booking.Version,
 		CreatedAt:   booking.CreatedAt,
 		UpdatedAt:   booking.UpdatedAt,
+		Slot:        ScheduleSlotToResponse(booking.Slot),
+	}
+	if booking.DeletedAt.Valid {
+		response.DeletedAt = &booking.DeletedAt.Time
+	}
+	if booking.InsuranceProvider != "" {
+		response.Insurance = &dto.InsuranceResponse{
+			Provider:     booking.InsuranceProvider,
+			PolicyNumber: booking.InsurancePolicyNumber,
+		}
+	}
+
+	// Include patient info if available
+	if booking.Patient.UserID != uuid.Nil {
+		response.Patient = PatientProfileToResponse(&booking.Patient, &booking.Patient.User)
 	}
 
 	// Include schedule info if available
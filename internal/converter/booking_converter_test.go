@@ -0,0 +1,88 @@
+package converter
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"go-template-clean-architecture/internal/domain/entity"
+
+	"github.com/google/uuid"
+)
+
+// benchBookings returns n bookings with the patient and the schedule (and its doctor)
+// loaded, like the admin and doctor booking lists
+func benchBookings(n int) []entity.Booking {
+	specialization := &entity.Specialization{ID: 1, Name: "Cardiology", Slug: "cardiology"}
+	doctorID := uuid.New()
+	doctor := entity.DoctorProfile{
+		UserID:           doctorID,
+		STRNumber:        "STR-0001",
+		SpecializationID: specialization.ID,
+		ConsultationFee:  150000,
+		User:             entity.User{ID: doctorID, Email: "doctor@example.com", FullName: "Dr. Benchmark"},
+		Specialization:   specialization,
+	}
+	scheduleDate := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	bookings := make([]entity.Booking, n)
+	for i := range bookings {
+		patientID := uuid.New()
+		schedule := entity.DoctorSchedule{
+			ID:           i/20 + 1,
+			DoctorID:     doctorID,
+			ScheduleDate: scheduleDate.AddDate(0, 0, i/20),
+			StartTime:    "08:00",
+			EndTime:      "12:00",
+			TotalQuota:   20,
+			Breaks:       entity.ScheduleBreaks{{StartTime: "10:00", EndTime: "10:15"}},
+			Doctor:       doctor,
+		}
+		bookings[i] = entity.Booking{
+			ID:          uuid.New(),
+			PatientID:   patientID,
+			ScheduleID:  schedule.ID,
+			BookingCode: fmt.Sprintf("BK-260101-%05d", i),
+			QueueNumber: i%20 + 1,
+			Status:      entity.BookingStatusConfirmed,
+			Version:     1,
+			CreatedAt:   scheduleDate,
+			UpdatedAt:   scheduleDate,
+			Patient: entity.PatientProfile{
+				UserID:      patientID,
+				NIK:         fmt.Sprintf("3201%012d", i),
+				PhoneNumber: "081234567890",
+				DateOfBirth: time.Date(1990, 1, 1, 0, 0, 0, 0, time.UTC),
+				Gender:      "F",
+				User:        entity.User{ID: patientID, Email: fmt.Sprintf("patient%d@example.com", i), FullName: "Patient Benchmark"},
+			},
+			Schedule: schedule,
+		}
+	}
+	return bookings
+}
+
+// BenchmarkBookingToResponse measures converting one booking with its relations loaded
+func BenchmarkBookingToResponse(b *testing.B) {
+	booking := &benchBookings(1)[0]
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		BookingToResponse(booking)
+	}
+}
+
+// BenchmarkBookingsToResponses measures converting booking lists up to an export-sized page
+func BenchmarkBookingsToResponses(b *testing.B) {
+	for _, n := range []int{10, 100, 1000, 10000} {
+		bookings := benchBookings(n)
+		b.Run(fmt.Sprintf("bookings=%d", n), func(b *testing.B) {
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				BookingsToResponses(bookings)
+			}
+		})
+	}
+}
@@ -0,0 +1,38 @@
+package converter
+
+import (
+	"go-template-clean-architecture/internal/delivery/dto"
+	"go-template-clean-architecture/internal/domain/entity"
+)
+
+// BookingSagaToResponse converts BookingSaga entity to BookingSagaResponse DTO
+func BookingSagaToResponse(saga *entity.BookingSaga) *dto.BookingSagaResponse {
+	if saga == nil {
+		return nil
+	}
+
+	return &dto.BookingSagaResponse{
+		ID:          saga.ID,
+		BookingID:   saga.BookingID,
+		BookingCode: saga.BookingCode,
+		PatientID:   saga.PatientID,
+		ScheduleID:  saga.ScheduleID,
+		SlotID:      saga.SlotID,
+		QueueNumber: saga.QueueNumber,
+		Status:      string(saga.Status),
+		Step:        saga.Step,
+		LastError:   saga.LastError,
+		CreatedAt:   saga.CreatedAt,
+		UpdatedAt:   saga.UpdatedAt,
+		FinishedAt:  saga.FinishedAt,
+	}
+}
+
+// BookingSagasToResponses converts slice of BookingSaga entities to BookingSagaResponse DTOs
+func BookingSagasToResponses(sagas []entity.BookingSaga) []dto.BookingSagaResponse {
+	responses := make([]dto.BookingSagaResponse, len(sagas))
+	for i := range sagas {
+		responses[i] = *BookingSagaToResponse(&sagas[i])
+	}
+	return responses
+}
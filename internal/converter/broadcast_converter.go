@@ -0,0 +1,39 @@
+package converter
+
+import (
+	"go-template-clean-architecture/internal/delivery/dto"
+	"go-template-clean-architecture/internal/domain/entity"
+)
+
+// BroadcastToResponse converts a Broadcast entity to BroadcastResponse DTO (empty delivery report)
+func BroadcastToResponse(broadcast *entity.Broadcast) *dto.BroadcastResponse {
+	if broadcast == nil {
+		return nil
+	}
+
+	return &dto.BroadcastResponse{
+		ID:              broadcast.ID,
+		Title:           broadcast.Title,
+		Message:         broadcast.Message,
+		Segment:         broadcast.Segment,
+		TotalRecipients: broadcast.TotalRecipients,
+		CreatedBy:       broadcast.CreatedBy,
+		CreatedAt:       broadcast.CreatedAt,
+	}
+}
+
+// BroadcastFailuresToResponses converts failed recipients to BroadcastFailureResponse DTOs
+func BroadcastFailuresToResponses(recipients []entity.BroadcastRecipient) []dto.BroadcastFailureResponse {
+	responses := make([]dto.BroadcastFailureResponse, len(recipients))
+	for i, recipient := range recipients {
+		responses[i] = dto.BroadcastFailureResponse{
+			PatientID: recipient.PatientID,
+			Attempts:  recipient.Attempts,
+			Error:     recipient.LastError,
+		}
+		if recipient.Patient != nil {
+			responses[i].PatientName = recipient.Patient.FullName
+		}
+	}
+	return responses
+}
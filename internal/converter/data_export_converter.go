@@ -0,0 +1,49 @@
+package converter
+
+import (
+	"go-template-clean-architecture/internal/delivery/dto"
+	"go-template-clean-architecture/internal/domain/entity"
+
+	"github.com/google/uuid"
+)
+
+// DataExportDownloadPath is the API path ready exports are downloaded from
+const DataExportDownloadPath = "/api/v1/patient/account/export/download"
+
+// DataExportToResponse converts a DataExport entity to DataExportResponse DTO
+func DataExportToResponse(export *entity.DataExport) *dto.DataExportResponse {
+	if export == nil {
+		return nil
+	}
+
+	response := &dto.DataExportResponse{
+		ID:          export.ID,
+		Status:      string(export.Status),
+		FileSize:    export.FileSize,
+		RequestedAt: export.CreatedAt,
+		CompletedAt: export.CompletedAt,
+		ExpiresAt:   export.ExpiresAt,
+	}
+	if export.Status == entity.DataExportStatusReady {
+		response.DownloadURL = DataExportDownloadPath
+	}
+	return response
+}
+
+// AuditLogsToDataExportEntries converts the audit trail of a user for their data export
+func AuditLogsToDataExportEntries(logs []entity.AuditLog, userID uuid.UUID) []dto.DataExportAuditEntry {
+	entries := make([]dto.DataExportAuditEntry, len(logs))
+	for i, log := range logs {
+		performedBy := "staff"
+		if log.UserID != nil && *log.UserID == userID {
+			performedBy = "you"
+		}
+		entries[i] = dto.DataExportAuditEntry{
+			Action:      log.Action,
+			PerformedBy: performedBy,
+			Metadata:    log.Metadata,
+			CreatedAt:   log.CreatedAt,
+		}
+	}
+	return entries
+}
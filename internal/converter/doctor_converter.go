@@ -11,30 +11,29 @@ func DoctorProfileToResponse(profile *entity.DoctorProfile) *dto.DoctorResponse
 		return nil
 	}
 
-	return &dto.DoctorResponse{
-		ID:             profile.UserID,
-		Email:          profile.User.Email,
-		FullName:       profile.User.FullName,
-		STRNumber:      profile.STRNumber,
-		Specialization: profile.Specialization,
-		Biography:      profile.Biography,
-		IsActive:       profile.User.IsActive,
+	response := &dto.DoctorResponse{
+		ID:               profile.UserID,
+		Email:            profile.User.Email,
+		FullName:         profile.User.FullName,
+		STRNumber:        profile.STRNumber,
+		SpecializationID: profile.SpecializationID,
+		Biography:        profile.Biography,
+		ConsultationFee:  profile.ConsultationFee,
+		PhotoURL:         profile.PhotoURL,
+		IsActive:         profile.User.IsActive,
 	}
+	if profile.Specialization != nil {
+		response.Specialization = profile.Specialization.Name
+		response.SpecializationSlug = profile.Specialization.Slug
+	}
+	return response
 }
 
 // DoctorProfilesToResponses converts a slice of DoctorProfile entities to slice of DoctorResponse DTOs
 func DoctorProfilesToResponses(profiles []entity.DoctorProfile) []dto.DoctorResponse {
 	responses := make([]dto.DoctorResponse, len(profiles))
-	for i, profile := range profiles {
-		responses[i] = dto.DoctorResponse{
-			ID:             profile.UserID,
-			Email:          profile.User.Email,
-			FullName:       profile.User.FullName,
-			STRNumber:      profile.STRNumber,
-			Specialization: profile.Specialization,
-			Biography:      profile.Biography,
-			IsActive:       profile.User.IsActive,
-		}
+	for i := range profiles {
+		responses[i] = *DoctorProfileToResponse(&profiles[i])
 	}
 	return responses
 }
@@ -0,0 +1,33 @@
+package converter
+
+import (
+	"go-template-clean-architecture/internal/delivery/dto"
+	"go-template-clean-architecture/internal/domain/entity"
+)
+
+// DoctorLeaveToResponse converts a DoctorLeave entity to DoctorLeaveResponse DTO
+func DoctorLeaveToResponse(leave *entity.DoctorLeave) *dto.DoctorLeaveResponse {
+	if leave == nil {
+		return nil
+	}
+
+	return &dto.DoctorLeaveResponse{
+		ID:        leave.ID,
+		DoctorID:  leave.DoctorID,
+		StartDate: leave.StartDate.Format("2006-01-02"),
+		EndDate:   leave.EndDate.Format("2006-01-02"),
+		Reason:    leave.Reason,
+		CreatedBy: leave.CreatedBy,
+		CreatedAt: leave.CreatedAt,
+		UpdatedAt: leave.UpdatedAt,
+	}
+}
+
+// DoctorLeavesToResponses converts a slice of DoctorLeave entities to slice of DoctorLeaveResponse DTOs
+func DoctorLeavesToResponses(leaves []entity.DoctorLeave) []dto.DoctorLeaveResponse {
+	responses := make([]dto.DoctorLeaveResponse, len(leaves))
+	for i, leave := range leaves {
+		responses[i] = *DoctorLeaveToResponse(&leave)
+	}
+	return responses
+}
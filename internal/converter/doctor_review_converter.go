@@ -0,0 +1,60 @@
+package converter
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"go-template-clean-architecture/internal/delivery/dto"
+	"go-template-clean-architecture/internal/domain/entity"
+)
+
+// anonymousReviewer names the authors of reviews whose account was erased
+const anonymousReviewer = "Anonymous"
+
+// DoctorReviewToResponse converts a DoctorReview entity to DoctorReviewResponse DTO
+func DoctorReviewToResponse(review *entity.DoctorReview) *dto.DoctorReviewResponse {
+	if review == nil {
+		return nil
+	}
+
+	return &dto.DoctorReviewResponse{
+		BookingID:  review.BookingID,
+		DoctorID:   review.DoctorID,
+		ScheduleID: review.ScheduleID,
+		Rating:     review.Rating,
+		Comment:    review.Comment,
+		CreatedAt:  review.CreatedAt,
+	}
+}
+
+// DoctorReviewItemsToResponses converts a slice of DoctorReviewItem to slice of DoctorReviewItemResponse DTOs.
+// Reviews are public, so only the first name and last initial of their author are shown.
+func DoctorReviewItemsToResponses(items []entity.DoctorReviewItem) []dto.DoctorReviewItemResponse {
+	responses := make([]dto.DoctorReviewItemResponse, len(items))
+	for i, item := range items {
+		reviewerName := anonymousReviewer
+		if !item.Erased {
+			reviewerName = maskReviewerName(item.ReviewerName)
+		}
+		responses[i] = dto.DoctorReviewItemResponse{
+			Rating:       item.Rating,
+			Comment:      item.Comment,
+			ReviewerName: reviewerName,
+			CreatedAt:    item.CreatedAt,
+		}
+	}
+	return responses
+}
+
+// maskReviewerName shortens "Budi Santoso Wijaya" to "Budi W."
+func maskReviewerName(fullName string) string {
+	names := strings.Fields(fullName)
+	switch len(names) {
+	case 0:
+		return anonymousReviewer
+	case 1:
+		return names[0]
+	}
+	initial, _ := utf8.DecodeRuneInString(names[len(names)-1])
+	return names[0] + " " + string(initial) + "."
+}
@@ -0,0 +1,31 @@
+package converter
+
+import (
+	"go-template-clean-architecture/internal/delivery/dto"
+	"go-template-clean-architecture/internal/domain/entity"
+)
+
+// HolidayToResponse converts a Holiday entity to HolidayResponse DTO
+func HolidayToResponse(holiday *entity.Holiday) *dto.HolidayResponse {
+	if holiday == nil {
+		return nil
+	}
+
+	return &dto.HolidayResponse{
+		ID:          holiday.ID,
+		Date:        holiday.Date.Format("2006-01-02"),
+		Name:        holiday.Name,
+		Description: holiday.Description,
+		CreatedAt:   holiday.CreatedAt,
+		UpdatedAt:   holiday.UpdatedAt,
+	}
+}
+
+// HolidaysToResponses converts a slice of Holiday entities to slice of HolidayResponse DTOs
+func HolidaysToResponses(holidays []entity.Holiday) []dto.HolidayResponse {
+	responses := make([]dto.HolidayResponse, len(holidays))
+	for i, holiday := range holidays {
+		responses[i] = *HolidayToResponse(&holiday)
+	}
+	return responses
+}
@@ -0,0 +1,65 @@
+package converter
+
+import (
+	"go-template-clean-architecture/internal/delivery/dto"
+	"go-template-clean-architecture/internal/domain/entity"
+)
+
+// InvoiceToResponse converts an Invoice entity to InvoiceResponse DTO
+func InvoiceToResponse(invoice *entity.Invoice) *dto.InvoiceResponse {
+	if invoice == nil {
+		return nil
+	}
+
+	items := make([]dto.InvoiceItemResponse, len(invoice.Items))
+	for i, item := range invoice.Items {
+		items[i] = dto.InvoiceItemResponse{
+			Type:        item.Type,
+			Description: item.Description,
+			Amount:      item.Amount,
+		}
+	}
+
+	return &dto.InvoiceResponse{
+		ID:          invoice.ID,
+		Number:      invoice.Number,
+		BookingID:   invoice.BookingID,
+		BookingCode: invoice.BookingCode,
+		PaymentID:   invoice.PaymentID,
+		PatientID:   invoice.PatientID,
+		PatientName: invoice.PatientName,
+		DoctorID:    invoice.DoctorID,
+		DoctorName:  invoice.DoctorName,
+		ServiceDate: invoice.ServiceDate.Format("2006-01-02"),
+		Currency:    invoice.Currency,
+		Total:       invoice.Total,
+		Status:      string(invoice.Status),
+		IssuedAt:    invoice.IssuedAt,
+		Items:       items,
+	}
+}
+
+// InvoicesToResponses converts a slice of Invoice entities to InvoiceResponse DTOs
+func InvoicesToResponses(invoices []entity.Invoice) []dto.InvoiceResponse {
+	responses := make([]dto.InvoiceResponse, len(invoices))
+	for i := range invoices {
+		responses[i] = *InvoiceToResponse(&invoices[i])
+	}
+	return responses
+}
+
+// InvoiceTotalsToFinanceTotals converts invoice totals to FinanceTotalResponse DTOs
+func InvoiceTotalsToFinanceTotals(totals []entity.InvoiceTotal) []dto.FinanceTotalResponse {
+	responses := make([]dto.FinanceTotalResponse, len(totals))
+	for i, total := range totals {
+		responses[i] = dto.FinanceTotalResponse{
+			Currency:         total.Currency,
+			Status:           string(total.Status),
+			Invoices:         total.Invoices,
+			Total:            total.Total,
+			ConsultationFees: total.ConsultationFees,
+			AdminFees:        total.AdminFees,
+		}
+	}
+	return responses
+}
@@ -0,0 +1,29 @@
+package converter
+
+import (
+	"go-template-clean-architecture/internal/delivery/dto"
+	"go-template-clean-architecture/internal/domain/entity"
+)
+
+// LoginHistoryToResponse converts a LoginHistory entity to LoginHistoryResponse DTO
+func LoginHistoryToResponse(entry *entity.LoginHistory) dto.LoginHistoryResponse {
+	return dto.LoginHistoryResponse{
+		ID:            entry.ID,
+		Method:        entry.Method,
+		Success:       entry.Success,
+		FailureReason: entry.FailureReason,
+		IPAddress:     entry.IPAddress,
+		UserAgent:     entry.UserAgent,
+		Device:        entry.Device,
+		CreatedAt:     entry.CreatedAt,
+	}
+}
+
+// LoginHistoriesToResponse converts login attempts to DTOs
+func LoginHistoriesToResponse(entries []entity.LoginHistory) []dto.LoginHistoryResponse {
+	responses := make([]dto.LoginHistoryResponse, 0, len(entries))
+	for i := range entries {
+		responses = append(responses, LoginHistoryToResponse(&entries[i]))
+	}
+	return responses
+}
@@ -0,0 +1,90 @@
+package converter
+
+import (
+	"go-template-clean-architecture/internal/delivery/dto"
+	"go-template-clean-architecture/internal/domain/entity"
+)
+
+// MedicalRecordToResponse converts a MedicalRecord entity (with booking, schedule and doctor) to MedicalRecordResponse DTO
+func MedicalRecordToResponse(record *entity.MedicalRecord) *dto.MedicalRecordResponse {
+	if record == nil {
+		return nil
+	}
+
+	response := &dto.MedicalRecordResponse{
+		BookingID:     record.BookingID,
+		BookingCode:   record.Booking.BookingCode,
+		PatientID:     record.PatientID,
+		DoctorID:      record.DoctorID,
+		DoctorName:    record.Booking.Schedule.Doctor.User.FullName,
+		ScheduleID:    record.ScheduleID,
+		Diagnosis:     record.Diagnosis,
+		ClinicalNotes: record.ClinicalNotes,
+		Vitals:        MedicalRecordVitalsToDTO(record.Vitals),
+		Version:       record.Version,
+		CreatedAt:     record.CreatedAt,
+		UpdatedAt:     record.UpdatedAt,
+	}
+	if !record.Booking.Schedule.ScheduleDate.IsZero() {
+		response.VisitDate = record.Booking.Schedule.ScheduleDate.Format("2006-01-02")
+	}
+	return response
+}
+
+// MedicalRecordsToResponses converts a slice of MedicalRecord to slice of MedicalRecordResponse DTOs
+func MedicalRecordsToResponses(records []entity.MedicalRecord) []dto.MedicalRecordResponse {
+	responses := make([]dto.MedicalRecordResponse, len(records))
+	for i := range records {
+		responses[i] = *MedicalRecordToResponse(&records[i])
+	}
+	return responses
+}
+
+// PatientVisitToResponse converts a booking (with schedule and doctor) and its record, nil when
+// none was written, to PatientVisitResponse DTO
+func PatientVisitToResponse(booking *entity.Booking, record *entity.MedicalRecord) dto.PatientVisitResponse {
+	response := dto.PatientVisitResponse{
+		BookingID:   booking.ID,
+		BookingCode: booking.BookingCode,
+		Status:      string(booking.Status),
+		QueueNumber: booking.QueueNumber,
+		ScheduleID:  booking.ScheduleID,
+		VisitDate:   booking.Schedule.ScheduleDate.Format("2006-01-02"),
+		StartTime:   booking.Schedule.StartTime,
+		EndTime:     booking.Schedule.EndTime,
+		DoctorID:    booking.Schedule.DoctorID,
+		DoctorName:  booking.Schedule.Doctor.User.FullName,
+		CalledAt:    booking.CalledAt,
+	}
+	if record != nil {
+		record.Booking = *booking
+		response.MedicalRecord = MedicalRecordToResponse(record)
+	}
+	return response
+}
+
+func MedicalRecordVitalsToDTO(vitals entity.MedicalRecordVitals) dto.MedicalRecordVitalsDTO {
+	return dto.MedicalRecordVitalsDTO{
+		SystolicBP:       vitals.SystolicBP,
+		DiastolicBP:      vitals.DiastolicBP,
+		HeartRate:        vitals.HeartRate,
+		RespiratoryRate:  vitals.RespiratoryRate,
+		Temperature:      vitals.Temperature,
+		OxygenSaturation: vitals.OxygenSaturation,
+		WeightKg:         vitals.WeightKg,
+		HeightCm:         vitals.HeightCm,
+	}
+}
+
+func MedicalRecordVitalsToEntity(vitals dto.MedicalRecordVitalsDTO) entity.MedicalRecordVitals {
+	return entity.MedicalRecordVitals{
+		SystolicBP:       vitals.SystolicBP,
+		DiastolicBP:      vitals.DiastolicBP,
+		HeartRate:        vitals.HeartRate,
+		RespiratoryRate:  vitals.RespiratoryRate,
+		Temperature:      vitals.Temperature,
+		OxygenSaturation: vitals.OxygenSaturation,
+		WeightKg:         vitals.WeightKg,
+		HeightCm:         vitals.HeightCm,
+	}
+}
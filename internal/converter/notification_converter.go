@@ -0,0 +1,140 @@
+package converter
+
+import (
+	"go-template-clean-architecture/internal/delivery/dto"
+	"go-template-clean-architecture/internal/domain/entity"
+)
+
+// NotificationToResponse converts Notification entity to NotificationResponse DTO
+func NotificationToResponse(notification *entity.Notification) *dto.NotificationResponse {
+	if notification == nil {
+		return nil
+	}
+
+	return &dto.NotificationResponse{
+		ID:                notification.ID,
+		PatientID:         notification.PatientID,
+		BookingID:         notification.BookingID,
+		EventType:         notification.EventType,
+		Channel:           notification.Channel,
+		Recipient:         notification.Recipient,
+		Subject:           notification.Subject,
+		Message:           notification.Message,
+		Status:            string(notification.Status),
+		Provider:          notification.Provider,
+		ProviderMessageID: notification.ProviderMessageID,
+		Attempts:          notification.Attempts,
+		LastError:         notification.LastError,
+		CreatedAt:         notification.CreatedAt,
+		SentAt:            notification.SentAt,
+		DeliveredAt:       notification.DeliveredAt,
+		FailedAt:          notification.FailedAt,
+		RedrivenAt:        notification.RedrivenAt,
+	}
+}
+
+// NotificationsToResponses converts slice of Notification entities to NotificationResponse DTOs
+func NotificationsToResponses(notifications []entity.Notification) []dto.NotificationResponse {
+	responses := make([]dto.NotificationResponse, len(notifications))
+	for i := range notifications {
+		responses[i] = *NotificationToResponse(&notifications[i])
+	}
+	return responses
+}
+
+// DeviceTokenToResponse converts DeviceToken entity to DeviceResponse DTO
+func DeviceTokenToResponse(device *entity.DeviceToken) *dto.DeviceResponse {
+	if device == nil {
+		return nil
+	}
+
+	return &dto.DeviceResponse{
+		ID:        device.ID,
+		Platform:  device.Platform,
+		CreatedAt: device.CreatedAt,
+		UpdatedAt: device.UpdatedAt,
+	}
+}
+
+// DeviceTokensToResponses converts slice of DeviceToken entities to DeviceResponse DTOs
+func DeviceTokensToResponses(devices []entity.DeviceToken) []dto.DeviceResponse {
+	responses := make([]dto.DeviceResponse, len(devices))
+	for i := range devices {
+		responses[i] = *DeviceTokenToResponse(&devices[i])
+	}
+	return responses
+}
+
+// InAppNotificationToResponse converts an in-app Notification entity to InAppNotificationResponse DTO
+func InAppNotificationToResponse(notification *entity.Notification) *dto.InAppNotificationResponse {
+	if notification == nil {
+		return nil
+	}
+
+	return &dto.InAppNotificationResponse{
+		ID:        notification.ID,
+		BookingID: notification.BookingID,
+		EventType: notification.EventType,
+		Title:     notification.Subject,
+		Message:   notification.Message,
+		Read:      notification.ReadAt != nil,
+		ReadAt:    notification.ReadAt,
+		CreatedAt: notification.CreatedAt,
+	}
+}
+
+// InAppNotificationsToResponses converts slice of in-app Notification entities to InAppNotificationResponse DTOs
+func InAppNotificationsToResponses(notifications []entity.Notification) []dto.InAppNotificationResponse {
+	responses := make([]dto.InAppNotificationResponse, len(notifications))
+	for i := range notifications {
+		responses[i] = *InAppNotificationToResponse(&notifications[i])
+	}
+	return responses
+}
+
+// NotificationPreferenceToResponse converts a NotificationPreference entity to NotificationPreferenceResponse DTO
+func NotificationPreferenceToResponse(preference *entity.NotificationPreference) *dto.NotificationPreferenceResponse {
+	if preference == nil {
+		return nil
+	}
+
+	return &dto.NotificationPreferenceResponse{
+		Channel:      preference.Channel,
+		Reminders:    preference.Reminders,
+		AgendaDigest: preference.AgendaDigest,
+	}
+}
+
+// NotificationAttemptsToResponses converts slice of NotificationAttempt entities to NotificationAttemptResponse DTOs
+func NotificationAttemptsToResponses(attempts []entity.NotificationAttempt) []dto.NotificationAttemptResponse {
+	responses := make([]dto.NotificationAttemptResponse, len(attempts))
+	for i, attempt := range attempts {
+		responses[i] = dto.NotificationAttemptResponse{
+			Attempt:           attempt.Attempt,
+			Channel:           attempt.Channel,
+			Recipient:         attempt.Recipient,
+			Provider:          attempt.Provider,
+			Succeeded:         attempt.Succeeded,
+			ProviderMessageID: attempt.ProviderMessageID,
+			Error:             attempt.Error,
+			DurationMs:        attempt.DurationMs,
+			CreatedAt:         attempt.CreatedAt,
+		}
+	}
+	return responses
+}
+
+// NotificationDeliveryReportsToResponses converts slice of NotificationDeliveryReport entities to NotificationDeliveryReportResponse DTOs
+func NotificationDeliveryReportsToResponses(reports []entity.NotificationDeliveryReport) []dto.NotificationDeliveryReportResponse {
+	responses := make([]dto.NotificationDeliveryReportResponse, len(reports))
+	for i, report := range reports {
+		responses[i] = dto.NotificationDeliveryReportResponse{
+			Provider:   report.Provider,
+			Status:     string(report.Status),
+			Error:      report.Error,
+			Applied:    report.Applied,
+			ReceivedAt: report.ReceivedAt,
+		}
+	}
+	return responses
+}
@@ -0,0 +1,34 @@
+package converter
+
+import (
+	"go-template-clean-architecture/internal/delivery/dto"
+	"go-template-clean-architecture/internal/domain/entity"
+)
+
+// NotificationTemplateToResponse converts a NotificationTemplate entity to NotificationTemplateResponse DTO
+func NotificationTemplateToResponse(template *entity.NotificationTemplate) *dto.NotificationTemplateResponse {
+	if template == nil {
+		return nil
+	}
+
+	return &dto.NotificationTemplateResponse{
+		ID:        template.ID,
+		EventType: template.EventType,
+		Channel:   template.Channel,
+		Locale:    template.Locale,
+		Subject:   template.Subject,
+		Body:      template.Body,
+		UpdatedBy: template.UpdatedBy,
+		CreatedAt: template.CreatedAt,
+		UpdatedAt: template.UpdatedAt,
+	}
+}
+
+// NotificationTemplatesToResponses converts a slice of NotificationTemplate entities to slice of NotificationTemplateResponse DTOs
+func NotificationTemplatesToResponses(templates []entity.NotificationTemplate) []dto.NotificationTemplateResponse {
+	responses := make([]dto.NotificationTemplateResponse, len(templates))
+	for i, template := range templates {
+		responses[i] = *NotificationTemplateToResponse(&template)
+	}
+	return responses
+}
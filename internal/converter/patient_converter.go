@@ -23,5 +23,40 @@ func PatientProfileToResponse(profile *entity.PatientProfile, user *entity.User)
 		IsActive:    user.IsActive,
 		CreatedAt:   user.CreatedAt,
 		UpdatedAt:   user.UpdatedAt,
+		Insurance:   PatientInsuranceToResponse(profile),
+
+		EmergencyContact: PatientEmergencyContactToResponse(profile),
+	}
+}
+
+// PatientInsuranceToResponse converts the insurance registered on a profile (nil without one)
+func PatientInsuranceToResponse(profile *entity.PatientProfile) *dto.InsuranceResponse {
+	if !profile.HasInsurance() {
+		return nil
+	}
+
+	response := &dto.InsuranceResponse{
+		Provider:     profile.InsuranceProvider,
+		PolicyNumber: profile.InsurancePolicyNumber,
+	}
+	if profile.InsuranceValidFrom != nil {
+		response.ValidFrom = profile.InsuranceValidFrom.Format("2006-01-02")
+	}
+	if profile.InsuranceValidUntil != nil {
+		response.ValidUntil = profile.InsuranceValidUntil.Format("2006-01-02")
+	}
+	return response
+}
+
+// PatientEmergencyContactToResponse converts the emergency contact registered on a profile (nil without one)
+func PatientEmergencyContactToResponse(profile *entity.PatientProfile) *dto.EmergencyContactResponse {
+	if !profile.HasEmergencyContact() {
+		return nil
+	}
+
+	return &dto.EmergencyContactResponse{
+		Name:         profile.EmergencyContactName,
+		Relationship: profile.EmergencyContactRelationship,
+		Phone:        profile.EmergencyContactPhone,
 	}
 }
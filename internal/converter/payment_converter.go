@@ -0,0 +1,41 @@
+package converter
+
+import (
+	"go-template-clean-architecture/internal/delivery/dto"
+	"go-template-clean-architecture/internal/domain/entity"
+)
+
+// PaymentToResponse converts a Payment entity to PaymentResponse DTO
+func PaymentToResponse(payment *entity.Payment) *dto.PaymentResponse {
+	if payment == nil {
+		return nil
+	}
+
+	response := &dto.PaymentResponse{
+		ID:            payment.ID,
+		BookingID:     payment.BookingID,
+		Gateway:       payment.Gateway,
+		Amount:        payment.Amount,
+		AdminFee:      payment.AdminFee,
+		Currency:      payment.Currency,
+		Status:        string(payment.Status),
+		FailureReason: payment.FailureReason,
+		ExpiresAt:     payment.ExpiresAt,
+		PaidAt:        payment.PaidAt,
+		CreatedAt:     payment.CreatedAt,
+	}
+	if payment.IsPending() {
+		response.PaymentURL = payment.PaymentURL
+	}
+	if payment.RefundStatus != entity.PaymentRefundNone {
+		response.Refund = &dto.PaymentRefundResponse{
+			Status:        string(payment.RefundStatus),
+			Amount:        payment.RefundAmount,
+			Reason:        payment.RefundReason,
+			FailureReason: payment.RefundFailureReason,
+			RequestedAt:   payment.RefundRequestedAt,
+			RefundedAt:    payment.RefundedAt,
+		}
+	}
+	return response
+}
@@ -0,0 +1,46 @@
+package converter
+
+import (
+	"go-template-clean-architecture/internal/delivery/dto"
+	"go-template-clean-architecture/internal/domain/entity"
+)
+
+// RoleToResponse converts a Role entity (with permissions preloaded) to RoleResponse DTO
+func RoleToResponse(role *entity.Role) *dto.RoleResponse {
+	if role == nil {
+		return nil
+	}
+
+	permissions := make([]string, len(role.Permissions))
+	for i, permission := range role.Permissions {
+		permissions[i] = permission.Code
+	}
+
+	return &dto.RoleResponse{
+		ID:          role.ID,
+		RoleName:    role.RoleName,
+		Description: role.Description,
+		Permissions: permissions,
+	}
+}
+
+// RolesToResponses converts a slice of Role entities to slice of RoleResponse DTOs
+func RolesToResponses(roles []entity.Role) []dto.RoleResponse {
+	responses := make([]dto.RoleResponse, len(roles))
+	for i, role := range roles {
+		responses[i] = *RoleToResponse(&role)
+	}
+	return responses
+}
+
+// PermissionsToResponses converts a slice of Permission entities to slice of PermissionResponse DTOs
+func PermissionsToResponses(permissions []entity.Permission) []dto.PermissionResponse {
+	responses := make([]dto.PermissionResponse, len(permissions))
+	for i, permission := range permissions {
+		responses[i] = dto.PermissionResponse{
+			Code:        permission.Code,
+			Description: permission.Description,
+		}
+	}
+	return responses
+}
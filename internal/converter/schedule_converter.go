@@ -1,6 +1,8 @@
 package converter
 
 import (
+	"math"
+
 	"go-template-clean-architecture/internal/delivery/dto"
 	"go-template-clean-architecture/internal/domain/entity"
 
@@ -20,39 +22,205 @@ func ScheduleToResponse(schedule *entity.DoctorSchedule) *dto.ScheduleResponse {
 		StartTime:    schedule.StartTime,
 		EndTime:      schedule.EndTime,
 		TotalQuota:   schedule.TotalQuota,
+		BookingMode:  schedule.BookingMode,
+		SlotMinutes:  schedule.SlotMinutes,
+		IsOpen:       schedule.IsBookingOpen(),
+		MinLeadHours: schedule.MinLeadHours,
 		CreatedAt:    schedule.CreatedAt,
 		UpdatedAt:    schedule.UpdatedAt,
+
+		DoctorCheckedInAt: schedule.DoctorCheckedInAt,
+		PossiblyAbsent:    schedule.IsPossiblyAbsent(),
+		HolidayFlaggedAt:  schedule.HolidayFlaggedAt,
+		LeaveFlaggedAt:    schedule.LeaveFlaggedAt,
+		TemplateID:        schedule.TemplateID,
+
+		Breaks: ScheduleBreaksToResponses(schedule.Breaks),
+
+		ApprovalStatus:  schedule.ApprovalStatus,
+		ReviewedAt:      schedule.ReviewedAt,
+		RejectionReason: schedule.RejectionReason,
+	}
+	if response.ApprovalStatus == "" {
+		response.ApprovalStatus = entity.ScheduleApprovalApproved
 	}
 
 	// Include doctor info if available
 	if schedule.Doctor.UserID != uuid.Nil {
 		response.Doctor = DoctorProfileToResponse(&schedule.Doctor)
+		response.ConsultationFee = &schedule.Doctor.ConsultationFee
 	}
 
 	return response
 }
 
+// ScheduleBreaksToResponses converts schedule breaks to DTOs (empty, not nil, without breaks)
+func ScheduleBreaksToResponses(breaks entity.ScheduleBreaks) []dto.ScheduleBreakResponse {
+	responses := make([]dto.ScheduleBreakResponse, len(breaks))
+	for i, brk := range breaks {
+		responses[i] = dto.ScheduleBreakResponse{
+			StartTime: brk.StartTime,
+			EndTime:   brk.EndTime,
+		}
+	}
+	return responses
+}
+
 // SchedulesToResponses converts a slice of DoctorSchedule entities to slice of ScheduleResponse DTOs
 func SchedulesToResponses(schedules []entity.DoctorSchedule) []dto.ScheduleResponse {
 	responses := make([]dto.ScheduleResponse, len(schedules))
 	for i, schedule := range schedules {
-		response := dto.ScheduleResponse{
-			ID:           schedule.ID,
-			DoctorID:     schedule.DoctorID,
-			ScheduleDate: schedule.ScheduleDate.Format("2006-01-02"),
-			StartTime:    schedule.StartTime,
-			EndTime:      schedule.EndTime,
-			TotalQuota:   schedule.TotalQuota,
-			CreatedAt:    schedule.CreatedAt,
-			UpdatedAt:    schedule.UpdatedAt,
-		}
+		responses[i] = *ScheduleToResponse(&schedule)
+	}
+	return responses
+}
 
-		// Include doctor info if available
-		if schedule.Doctor.UserID != uuid.Nil {
-			response.Doctor = DoctorProfileToResponse(&schedule.Doctor)
+// ScheduleVersionsToHistory converts the versions of a schedule (oldest first) to its history DTO
+func ScheduleVersionsToHistory(scheduleID int, versions []entity.ScheduleVersion) *dto.ScheduleHistoryResponse {
+	responses := make([]dto.ScheduleVersionResponse, len(versions))
+	for i := range versions {
+		version := &versions[i]
+		changedFields := []string(version.ChangedFields)
+		if changedFields == nil {
+			changedFields = []string{}
 		}
+		responses[i] = dto.ScheduleVersionResponse{
+			Version:       i + 1,
+			ID:            version.ID,
+			Action:        version.Action,
+			ChangedBy:     UserToResponse(version.User),
+			ChangedFields: changedFields,
+			OldValue:      scheduleSnapshotOrNil(version.OldValue),
+			NewValue:      scheduleSnapshotOrNil(version.NewValue),
+			CreatedAt:     version.CreatedAt,
+		}
+	}
 
-		responses[i] = response
+	return &dto.ScheduleHistoryResponse{
+		ScheduleID: scheduleID,
+		Versions:   responses,
+		Total:      len(responses),
+	}
+}
+
+func scheduleSnapshotOrNil(snapshot entity.ScheduleSnapshot) *entity.ScheduleSnapshot {
+	if snapshot.IsEmpty() {
+		return nil
+	}
+	return &snapshot
+}
+
+// ScheduleSlotToResponse converts a ScheduleSlot entity to ScheduleSlotResponse DTO
+func ScheduleSlotToResponse(slot *entity.ScheduleSlot) *dto.ScheduleSlotResponse {
+	if slot == nil {
+		return nil
+	}
+
+	return &dto.ScheduleSlotResponse{
+		ID:        slot.ID,
+		Position:  slot.Position,
+		StartTime: slot.StartTime,
+		EndTime:   slot.EndTime,
+	}
+}
+
+// ScheduleSlotsToResponses converts slots to responses, marking slots in reserved as unavailable
+func ScheduleSlotsToResponses(slots []entity.ScheduleSlot, reserved map[int64]bool) []dto.ScheduleSlotResponse {
+	responses := make([]dto.ScheduleSlotResponse, len(slots))
+	for i := range slots {
+		responses[i] = *ScheduleSlotToResponse(&slots[i])
+		available := !reserved[slots[i].ID]
+		responses[i].Available = &available
 	}
 	return responses
 }
+
+// ScheduleUtilizationStatsToReport converts aggregated schedule stats to the utilization report,
+// summing them into overall totals
+func ScheduleUtilizationStatsToReport(stats []entity.ScheduleUtilizationStat, startDate, endDate string) *dto.ScheduleUtilizationReportResponse {
+	report := &dto.ScheduleUtilizationReportResponse{
+		StartDate: startDate,
+		EndDate:   endDate,
+		Schedules: make([]dto.ScheduleUtilizationResponse, len(stats)),
+		Total:     len(stats),
+	}
+
+	for i, stat := range stats {
+		report.Schedules[i] = dto.ScheduleUtilizationResponse{
+			ScheduleID:   stat.ScheduleID,
+			DoctorID:     stat.DoctorID,
+			DoctorName:   stat.DoctorName,
+			ScheduleDate: stat.ScheduleDate.Format("2006-01-02"),
+			StartTime:    stat.StartTime,
+			EndTime:      stat.EndTime,
+			TotalQuota:   stat.TotalQuota,
+			Booked:       stat.Booked,
+			Cancelled:    stat.Cancelled,
+			NoShow:       stat.NoShow,
+			FillRate:     fillRate(stat.Booked, int64(stat.TotalQuota)),
+		}
+
+		report.TotalQuota += int64(stat.TotalQuota)
+		report.Booked += stat.Booked
+		report.Cancelled += stat.Cancelled
+		report.NoShow += stat.NoShow
+	}
+	report.FillRate = fillRate(report.Booked, report.TotalQuota)
+
+	return report
+}
+
+// DoctorPeriodStatsToResponse converts a doctor's aggregated period stats to the statistics
+// response, summing them into the totals
+func DoctorPeriodStatsToResponse(doctorID uuid.UUID, stats []entity.DoctorPeriodStat, startDate, endDate, period string) *dto.DoctorStatsResponse {
+	response := &dto.DoctorStatsResponse{
+		DoctorID:  doctorID,
+		StartDate: startDate,
+		EndDate:   endDate,
+		Period:    period,
+		Periods:   make([]dto.DoctorPeriodStatsResponse, len(stats)),
+	}
+
+	var totals entity.DoctorPeriodStat
+	for i, stat := range stats {
+		response.Periods[i] = doctorPeriodStatToResponse(&stat)
+		response.Periods[i].PeriodStart = stat.PeriodStart.Format("2006-01-02")
+
+		totals.Schedules += stat.Schedules
+		totals.TotalQuota += stat.TotalQuota
+		totals.Booked += stat.Booked
+		totals.Served += stat.Served
+		totals.Cancelled += stat.Cancelled
+		totals.NoShow += stat.NoShow
+		totals.ConsultationMinutes += stat.ConsultationMinutes
+		totals.ConsultationSamples += stat.ConsultationSamples
+	}
+	response.Totals = doctorPeriodStatToResponse(&totals)
+
+	return response
+}
+
+func doctorPeriodStatToResponse(stat *entity.DoctorPeriodStat) dto.DoctorPeriodStatsResponse {
+	response := dto.DoctorPeriodStatsResponse{
+		Schedules:   stat.Schedules,
+		TotalQuota:  stat.TotalQuota,
+		Booked:      stat.Booked,
+		Served:      stat.Served,
+		Cancelled:   stat.Cancelled,
+		NoShow:      stat.NoShow,
+		Utilization: fillRate(stat.Booked, stat.TotalQuota),
+	}
+	if stat.ConsultationSamples > 0 {
+		avg := math.Round(stat.ConsultationMinutes/float64(stat.ConsultationSamples)*10) / 10
+		response.AvgConsultationMinutes = &avg
+	}
+	return response
+}
+
+// fillRate returns booked / quota rounded to 4 decimals (0 without quota)
+func fillRate(booked, quota int64) float64 {
+	if quota <= 0 {
+		return 0
+	}
+	return math.Round(float64(booked)/float64(quota)*10000) / 10000
+}
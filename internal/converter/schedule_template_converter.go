@@ -0,0 +1,110 @@
+package converter
+
+import (
+	"time"
+
+	"go-template-clean-architecture/internal/delivery/dto"
+	"go-template-clean-architecture/internal/domain/entity"
+
+	"github.com/google/uuid"
+)
+
+// ScheduleTemplateToResponse converts ScheduleTemplate entity to ScheduleTemplateResponse DTO
+func ScheduleTemplateToResponse(template *entity.ScheduleTemplate) *dto.ScheduleTemplateResponse {
+	if template == nil {
+		return nil
+	}
+
+	response := &dto.ScheduleTemplateResponse{
+		ID:           template.ID,
+		DoctorID:     template.DoctorID,
+		Weekday:      template.Weekday,
+		WeekdayName:  time.Weekday(template.Weekday).String(),
+		StartTime:    template.StartTime,
+		EndTime:      template.EndTime,
+		TotalQuota:   template.TotalQuota,
+		BookingMode:  template.BookingMode,
+		SlotMinutes:  template.SlotMinutes,
+		MinLeadHours: template.MinLeadHours,
+		IsActive:     template.IsEnabled(),
+		CreatedAt:    template.CreatedAt,
+		UpdatedAt:    template.UpdatedAt,
+	}
+
+	if template.GeneratedUntil != nil {
+		response.GeneratedUntil = template.GeneratedUntil.Format("2006-01-02")
+	}
+
+	// Include doctor info if available
+	if template.Doctor.UserID != uuid.Nil {
+		response.Doctor = DoctorProfileToResponse(&template.Doctor)
+	}
+
+	return response
+}
+
+// ScheduleTemplatesToResponses converts slice of ScheduleTemplate entities to ScheduleTemplateResponse DTOs
+func ScheduleTemplatesToResponses(templates []entity.ScheduleTemplate) []dto.ScheduleTemplateResponse {
+	responses := make([]dto.ScheduleTemplateResponse, len(templates))
+	for i := range templates {
+		responses[i] = *ScheduleTemplateToResponse(&templates[i])
+	}
+	return responses
+}
+
+// ScheduleGenerationRunToResponse converts ScheduleGenerationRun entity to ScheduleGenerationRunResponse DTO,
+// including the per-date details
+func ScheduleGenerationRunToResponse(run *entity.ScheduleGenerationRun) *dto.ScheduleGenerationRunResponse {
+	if run == nil {
+		return nil
+	}
+
+	response := ScheduleGenerationRunToSummaryResponse(run)
+	response.Details = &dto.ScheduleGenerationDetailsResponse{
+		Created: scheduleGenerationItemsToResponses(run.Details.Created),
+		Skipped: scheduleGenerationItemsToResponses(run.Details.Skipped),
+		Failed:  scheduleGenerationItemsToResponses(run.Details.Failed),
+	}
+	return response
+}
+
+// ScheduleGenerationRunsToResponses converts runs to summary responses (without details)
+func ScheduleGenerationRunsToResponses(runs []entity.ScheduleGenerationRun) []dto.ScheduleGenerationRunResponse {
+	responses := make([]dto.ScheduleGenerationRunResponse, len(runs))
+	for i := range runs {
+		responses[i] = *ScheduleGenerationRunToSummaryResponse(&runs[i])
+	}
+	return responses
+}
+
+// ScheduleGenerationRunToSummaryResponse converts a run to its response without the per-date details
+func ScheduleGenerationRunToSummaryResponse(run *entity.ScheduleGenerationRun) *dto.ScheduleGenerationRunResponse {
+	return &dto.ScheduleGenerationRunResponse{
+		ID:            run.ID,
+		Trigger:       run.Trigger,
+		FromDate:      run.FromDate.Format("2006-01-02"),
+		ToDate:        run.ToDate.Format("2006-01-02"),
+		Status:        string(run.Status),
+		CreatedCount:  run.CreatedCount,
+		ExistingCount: run.ExistingCount,
+		SkippedCount:  run.SkippedCount,
+		FailedCount:   run.FailedCount,
+		Error:         run.Error,
+		StartedAt:     run.StartedAt,
+		FinishedAt:    run.FinishedAt,
+	}
+}
+
+func scheduleGenerationItemsToResponses(items []entity.ScheduleGenerationItem) []dto.ScheduleGenerationItemResponse {
+	responses := make([]dto.ScheduleGenerationItemResponse, len(items))
+	for i, item := range items {
+		responses[i] = dto.ScheduleGenerationItemResponse{
+			TemplateID:   item.TemplateID,
+			DoctorID:     item.DoctorID,
+			ScheduleDate: item.ScheduleDate,
+			ScheduleID:   item.ScheduleID,
+			Reason:       item.Reason,
+		}
+	}
+	return responses
+}
@@ -0,0 +1,31 @@
+package converter
+
+import (
+	"go-template-clean-architecture/internal/delivery/dto"
+	"go-template-clean-architecture/internal/domain/entity"
+)
+
+// SpecializationToResponse converts a Specialization entity to SpecializationResponse DTO
+func SpecializationToResponse(specialization *entity.Specialization) *dto.SpecializationResponse {
+	if specialization == nil {
+		return nil
+	}
+
+	return &dto.SpecializationResponse{
+		ID:          specialization.ID,
+		Name:        specialization.Name,
+		Slug:        specialization.Slug,
+		Description: specialization.Description,
+		CreatedAt:   specialization.CreatedAt,
+		UpdatedAt:   specialization.UpdatedAt,
+	}
+}
+
+// SpecializationsToResponses converts a slice of Specialization entities to slice of SpecializationResponse DTOs
+func SpecializationsToResponses(specializations []entity.Specialization) []dto.SpecializationResponse {
+	responses := make([]dto.SpecializationResponse, len(specializations))
+	for i := range specializations {
+		responses[i] = *SpecializationToResponse(&specializations[i])
+	}
+	return responses
+}
@@ -0,0 +1,31 @@
+package converter
+
+import (
+	"go-template-clean-architecture/internal/delivery/dto"
+	"go-template-clean-architecture/internal/domain/entity"
+)
+
+// SpecializationDefaultToResponse converts a SpecializationDefault entity to SpecializationDefaultResponse DTO
+func SpecializationDefaultToResponse(defaults *entity.SpecializationDefault) *dto.SpecializationDefaultResponse {
+	if defaults == nil {
+		return nil
+	}
+
+	return &dto.SpecializationDefaultResponse{
+		Specialization:      defaults.Specialization,
+		DefaultQuota:        defaults.DefaultQuota,
+		ConsultationMinutes: defaults.ConsultationMinutes,
+		Fee:                 defaults.Fee,
+		CreatedAt:           defaults.CreatedAt,
+		UpdatedAt:           defaults.UpdatedAt,
+	}
+}
+
+// SpecializationDefaultsToResponses converts a slice of SpecializationDefault entities to slice of response DTOs
+func SpecializationDefaultsToResponses(defaults []entity.SpecializationDefault) []dto.SpecializationDefaultResponse {
+	responses := make([]dto.SpecializationDefaultResponse, len(defaults))
+	for i, d := range defaults {
+		responses[i] = *SpecializationDefaultToResponse(&d)
+	}
+	return responses
+}
@@ -1,6 +1,8 @@
 package converter
 
 import (
+	"time"
+
 	"go-template-clean-architecture/internal/delivery/dto"
 	"go-template-clean-architecture/internal/domain/entity"
 )
@@ -13,20 +15,25 @@ func UserToResponse(user *entity.User) *dto.UserResponse {
 	}
 
 	response := &dto.UserResponse{
-		ID:        user.ID,
-		Email:     user.Email,
-		FullName:  user.FullName,
-		Role:      user.Role.RoleName,
-		CreatedAt: user.CreatedAt,
-		UpdatedAt: user.UpdatedAt,
+		ID:               user.ID,
+		Email:            user.Email,
+		FullName:         user.FullName,
+		Role:             user.Role.RoleName,
+		TwoFactorEnabled: user.IsTwoFactorEnabled(),
+		CreatedAt:        user.CreatedAt,
+		UpdatedAt:        user.UpdatedAt,
 	}
 
 	// Include DoctorProfile if exists
 	if user.DoctorProfile != nil {
 		response.DoctorProfile = &dto.DoctorProfileResponse{
-			STRNumber:      user.DoctorProfile.STRNumber,
-			Specialization: user.DoctorProfile.Specialization,
-			Biography:      user.DoctorProfile.Biography,
+			STRNumber:        user.DoctorProfile.STRNumber,
+			SpecializationID: user.DoctorProfile.SpecializationID,
+			Biography:        user.DoctorProfile.Biography,
+		}
+		if specialization := user.DoctorProfile.Specialization; specialization != nil {
+			response.DoctorProfile.Specialization = specialization.Name
+			response.DoctorProfile.SpecializationSlug = specialization.Slug
 		}
 	}
 
@@ -39,14 +46,49 @@ func UserToResponse(user *entity.User) *dto.UserResponse {
 			DateOfBirth: user.PatientProfile.DateOfBirth.Format("2006-01-02"),
 			Gender:      user.PatientProfile.Gender,
 			Address:     user.PatientProfile.Address,
+			Insurance:   PatientInsuranceToResponse(user.PatientProfile),
+
+			EmergencyContact: PatientEmergencyContactToResponse(user.PatientProfile),
 		}
 	}
 
 	return response
 }
 
+// UserToAdminResponse converts a User entity to the admin user list DTO
+func UserToAdminResponse(user *entity.User, at time.Time) dto.AdminUserResponse {
+	response := dto.AdminUserResponse{
+		UserResponse: *UserToResponse(user),
+		IsActive:     user.IsActive == nil || *user.IsActive,
+		Locked:       user.IsLocked(at),
+		LockoutCount: user.LockoutCount,
+		LastLockedAt: user.LastLockedAt,
+		LastLoginAt:  user.LastLoginAt,
+
+		MustChangePassword: user.MustChangePassword,
+		PasswordChangedAt:  user.PasswordChangedAt,
+	}
+	if response.Locked {
+		response.LockedUntil = user.LockedUntil
+	}
+	return response
+}
+
 // UserToResponseWithRole converts a User entity to UserResponse DTO with explicit role name
 // Use this when Role is not preloaded but roleID is known
+// ProfileCompletenessToResponse converts the completeness, required are the fields booking needs
+func ProfileCompletenessToResponse(completeness entity.ProfileCompleteness, required []string) *dto.ProfileCompletenessResponse {
+	requiredMissing := completeness.MissingOf(required)
+	if requiredMissing == nil {
+		requiredMissing = []string{}
+	}
+	return &dto.ProfileCompletenessResponse{
+		Score:              completeness.Score,
+		Missing:            completeness.Missing,
+		RequiredForBooking: requiredMissing,
+	}
+}
+
 // func UserToResponseWithRole(user *entity.User, roleName string) *dto.UserResponse {
 // 	if user == nil {
 // 		return nil
@@ -0,0 +1,38 @@
+package converter
+
+import (
+	"math"
+
+	"go-template-clean-architecture/internal/delivery/dto"
+	"go-template-clean-architecture/internal/domain/entity"
+)
+
+// WaitFeedbackToResponse converts a WaitFeedback entity to WaitFeedbackResponse DTO
+func WaitFeedbackToResponse(feedback *entity.WaitFeedback) *dto.WaitFeedbackResponse {
+	if feedback == nil {
+		return nil
+	}
+
+	return &dto.WaitFeedbackResponse{
+		BookingID:   feedback.BookingID,
+		DoctorID:    feedback.DoctorID,
+		ScheduleID:  feedback.ScheduleID,
+		WaitMinutes: feedback.WaitMinutes,
+		Comment:     feedback.Comment,
+		CreatedAt:   feedback.CreatedAt,
+	}
+}
+
+// DoctorWaitStatsToResponses converts a slice of DoctorWaitStat to slice of DoctorWaitTimeResponse DTOs
+func DoctorWaitStatsToResponses(stats []entity.DoctorWaitStat) []dto.DoctorWaitTimeResponse {
+	responses := make([]dto.DoctorWaitTimeResponse, len(stats))
+	for i, stat := range stats {
+		responses[i] = dto.DoctorWaitTimeResponse{
+			DoctorID:           stat.DoctorID,
+			DoctorName:         stat.DoctorName,
+			AverageWaitMinutes: math.Round(stat.AverageWaitMinutes*10) / 10,
+			FeedbackCount:      stat.FeedbackCount,
+		}
+	}
+	return responses
+}
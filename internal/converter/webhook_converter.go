@@ -0,0 +1,78 @@
+package converter
+
+import (
+	"go-template-clean-architecture/internal/delivery/dto"
+	"go-template-clean-architecture/internal/domain/entity"
+)
+
+// WebhookToResponse converts a WebhookSubscription entity to WebhookResponse DTO, without its secret
+func WebhookToResponse(subscription *entity.WebhookSubscription) *dto.WebhookResponse {
+	if subscription == nil {
+		return nil
+	}
+
+	eventTypes := []string(subscription.EventTypes)
+	if eventTypes == nil {
+		eventTypes = []string{}
+	}
+
+	return &dto.WebhookResponse{
+		ID:                  subscription.ID,
+		URL:                 subscription.URL,
+		EventTypes:          eventTypes,
+		Description:         subscription.Description,
+		Status:              string(subscription.Status),
+		ConsecutiveFailures: subscription.ConsecutiveFailures,
+		PausedAt:            subscription.PausedAt,
+		PauseReason:         subscription.PauseReason,
+		CreatedBy:           subscription.CreatedBy,
+		CreatedAt:           subscription.CreatedAt,
+		UpdatedAt:           subscription.UpdatedAt,
+	}
+}
+
+// WebhooksToResponses converts a slice of WebhookSubscription entities to slice of WebhookResponse DTOs
+func WebhooksToResponses(subscriptions []entity.WebhookSubscription) []dto.WebhookResponse {
+	responses := make([]dto.WebhookResponse, len(subscriptions))
+	for i, subscription := range subscriptions {
+		responses[i] = *WebhookToResponse(&subscription)
+	}
+	return responses
+}
+
+// WebhookDeliveryToResponse converts a WebhookDelivery entity to WebhookDeliveryResponse DTO
+func WebhookDeliveryToResponse(delivery *entity.WebhookDelivery) *dto.WebhookDeliveryResponse {
+	if delivery == nil {
+		return nil
+	}
+
+	response := &dto.WebhookDeliveryResponse{
+		ID:             delivery.ID,
+		SubscriptionID: delivery.SubscriptionID,
+		EventID:        delivery.EventID,
+		EventType:      delivery.EventType,
+		Payload:        delivery.Payload,
+		Status:         string(delivery.Status),
+		Attempts:       delivery.Attempts,
+		ResponseStatus: delivery.ResponseStatus,
+		ResponseBody:   delivery.ResponseBody,
+		LastError:      delivery.LastError,
+		DeliveredAt:    delivery.DeliveredAt,
+		FailedAt:       delivery.FailedAt,
+		CreatedAt:      delivery.CreatedAt,
+	}
+	if delivery.Status == entity.WebhookDeliveryPending {
+		nextAttemptAt := delivery.NextAttemptAt
+		response.NextAttemptAt = &nextAttemptAt
+	}
+	return response
+}
+
+// WebhookDeliveriesToResponses converts a slice of WebhookDelivery entities to slice of WebhookDeliveryResponse DTOs
+func WebhookDeliveriesToResponses(deliveries []entity.WebhookDelivery) []dto.WebhookDeliveryResponse {
+	responses := make([]dto.WebhookDeliveryResponse, len(deliveries))
+	for i, delivery := range deliveries {
+		responses[i] = *WebhookDeliveryToResponse(&delivery)
+	}
+	return responses
+}
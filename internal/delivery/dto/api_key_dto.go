@@ -0,0 +1,43 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Request DTOs
+
+type CreateAPIKeyRequest struct {
+	Name        string     `json:"name" validate:"required,min=2,max=100"`
+	Permissions []string   `json:"permissions" validate:"required,min=1,dive,required"`
+	ExpiresAt   *time.Time `json:"expires_at"` // RFC 3339, omit for a key that does not expire
+}
+
+// UpdateAPIKeyRequest replaces the name, permissions and expiry of an API key
+type UpdateAPIKeyRequest struct {
+	Name        string     `json:"name" validate:"required,min=2,max=100"`
+	Permissions []string   `json:"permissions" validate:"required,min=1,dive,required"`
+	ExpiresAt   *time.Time `json:"expires_at"`
+}
+
+// Response DTOs
+
+type APIKeyResponse struct {
+	ID          uuid.UUID  `json:"id"`
+	Name        string     `json:"name"`
+	KeyPrefix   string     `json:"key_prefix"` // Leading characters of the key
+	Permissions []string   `json:"permissions"`
+	Status      string     `json:"status"` // active, expired or revoked
+	ExpiresAt   *time.Time `json:"expires_at,omitempty"`
+	LastUsedAt  *time.Time `json:"last_used_at,omitempty"`
+	RevokedAt   *time.Time `json:"revoked_at,omitempty"`
+	CreatedBy   *uuid.UUID `json:"created_by,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+// CreateAPIKeyResponse includes the key itself, shown only once
+type CreateAPIKeyResponse struct {
+	*APIKeyResponse
+	Key string `json:"key"`
+}
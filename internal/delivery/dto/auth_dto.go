@@ -11,29 +11,170 @@ import (
 type LoginRequest struct {
 	Email    string `json:"email" validate:"required,email"`
 	Password string `json:"password" validate:"required"`
+	// RememberMe keeps the session signed in for JWT_REMEMBER_ME_EXPIRY instead of JWT_REFRESH_EXPIRY
+	RememberMe bool `json:"remember_me"`
 }
 
 type RefreshTokenRequest struct {
 	RefreshToken string `json:"refresh_token" validate:"required"`
 }
 
+// LoginTwoFactorRequest completes a login of a 2FA user with an authenticator or backup code
+type LoginTwoFactorRequest struct {
+	ChallengeToken string `json:"challenge_token" validate:"required"`
+	Code           string `json:"code" validate:"required,max=20"`
+}
+
+// RequestLoginOTPRequest sends a login code to the phone of a patient account
+type RequestLoginOTPRequest struct {
+	PhoneNumber string `json:"phone_number" validate:"required,min=10,max=20"`
+	Channel     string `json:"channel" validate:"omitempty,oneof=sms whatsapp"` // Default: sms
+}
+
+// VerifyLoginOTPRequest logs in with the code sent to the phone
+type VerifyLoginOTPRequest struct {
+	PhoneNumber string `json:"phone_number" validate:"required,min=10,max=20"`
+	Code        string `json:"code" validate:"required,len=6,numeric"`
+	RememberMe  bool   `json:"remember_me"` // See LoginRequest
+}
+
+// EnableTwoFactorRequest confirms the enrollment with a first authenticator code
+type EnableTwoFactorRequest struct {
+	Code string `json:"code" validate:"required,len=6,numeric"`
+}
+
+// DisableTwoFactorRequest turns 2FA off; needs the password and an authenticator or backup code
+type DisableTwoFactorRequest struct {
+	Password string `json:"password" validate:"required"`
+	Code     string `json:"code" validate:"required,max=20"`
+}
+
+// ChangePasswordRequest replaces the password of the logged-in user
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"current_password" validate:"required"`
+	NewPassword     string `json:"new_password" validate:"required,min=6,nefield=CurrentPassword"`
+}
+
+// SetMustChangePasswordRequest requires a user to change the password on next use (admin)
+type SetMustChangePasswordRequest struct {
+	Required *bool `json:"required" validate:"required"`
+}
+
+// GoogleRegisterRequest creates the patient account of a Google identity not linked yet.
+// Google does not provide the patient profile, so it is completed here.
+type GoogleRegisterRequest struct {
+	RegistrationToken string `json:"registration_token" validate:"required"`
+	FullName          string `json:"full_name" validate:"omitempty,min=2"` // Defaults to the Google name
+	NIK               string `json:"nik" validate:"required,len=16"`
+	PhoneNumber       string `json:"phone_number" validate:"omitempty,min=10,max=20"`
+	DateOfBirth       string `json:"date_of_birth" validate:"required"` // Format: YYYY-MM-DD
+	Gender            string `json:"gender" validate:"required,oneof=M F"`
+	Address           string `json:"address" validate:"omitempty"`
+}
+
 // Response DTOs
 
 type TokenResponse struct {
 	AccessToken  string `json:"access_token"`
 	RefreshToken string `json:"refresh_token"`
 	ExpiresIn    int64  `json:"expires_in"`
+	// Only POST /auth/password (and logout) are allowed until the password is changed
+	PasswordChangeRequired bool `json:"password_change_required,omitempty"`
+}
+
+// ImpersonateRequest starts acting as a user for support (admin)
+type ImpersonateRequest struct {
+	Reason string `json:"reason" validate:"required,min=5,max=500"` // e.g. the support ticket
+}
+
+// ImpersonationResponse is a short-lived access token acting as the user, without refresh token
+type ImpersonationResponse struct {
+	AccessToken string        `json:"access_token"`
+	ExpiresIn   int64         `json:"expires_in"`
+	ExpiresAt   time.Time     `json:"expires_at"`
+	User        *UserResponse `json:"user"`
+}
+
+// LoginResponse is the token pair, or a challenge to complete with a 2FA code
+// (POST /auth/login/2fa) when the account has two-factor authentication on
+type LoginResponse struct {
+	*TokenResponse
+	TwoFactorRequired  bool   `json:"two_factor_required"`
+	ChallengeToken     string `json:"challenge_token,omitempty"`
+	ChallengeExpiresIn int64  `json:"challenge_expires_in,omitempty"`
+}
+
+// OAuthLoginResponse is the login result of an SSO callback. An identity without
+// a patient account gets a registration token for POST /auth/oauth/google/register.
+type OAuthLoginResponse struct {
+	*LoginResponse
+	RegistrationRequired bool   `json:"registration_required"`
+	RegistrationToken    string `json:"registration_token,omitempty"`
+	Email                string `json:"email,omitempty"`
+	FullName             string `json:"full_name,omitempty"`
+}
+
+// TwoFactorSetupResponse is the secret to add to an authenticator app
+type TwoFactorSetupResponse struct {
+	Secret          string `json:"secret"`
+	ProvisioningURI string `json:"provisioning_uri"` // otpauth:// URI, render as QR code
+}
+
+// TwoFactorBackupCodesResponse lists one-time backup codes, shown only once
+type TwoFactorBackupCodesResponse struct {
+	BackupCodes []string `json:"backup_codes"`
+}
+
+// LoginHistoryResponse is one login attempt of the user
+type LoginHistoryResponse struct {
+	ID            int64     `json:"id"`
+	Method        string    `json:"method"`
+	Success       bool      `json:"success"`
+	FailureReason string    `json:"failure_reason,omitempty"`
+	IPAddress     string    `json:"ip_address"`
+	UserAgent     string    `json:"user_agent"`
+	Device        string    `json:"device"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// SessionResponse is a signed-in device of the user
+type SessionResponse struct {
+	TokenID    string    `json:"token_id"` // Revoke with DELETE /auth/sessions/{token_id}
+	Device     string    `json:"device"`
+	IPAddress  string    `json:"ip_address"`
+	UserAgent  string    `json:"user_agent"`
+	IssuedAt   time.Time `json:"issued_at"`    // Sign-in time
+	LastUsedAt time.Time `json:"last_used_at"` // Last token refresh
+	RememberMe bool      `json:"remember_me"`  // Long-lived session
+	Current    bool      `json:"current"`      // The session of this request
+}
+
+// RevokeRememberMeSessionsResponse reports a bulk revocation of remember-me sessions
+type RevokeRememberMeSessionsResponse struct {
+	Revoked int `json:"revoked"`
 }
 
 type UserResponse struct {
-	ID             uuid.UUID               `json:"id"`
-	Email          string                  `json:"email"`
-	FullName       string                  `json:"full_name"`
-	Role           string                  `json:"role"`
-	DoctorProfile  *DoctorProfileResponse  `json:"doctor_profile,omitempty"`
-	PatientProfile *PatientProfileResponse `json:"patient_profile,omitempty"`
-	CreatedAt      time.Time               `json:"created_at"`
-	UpdatedAt      time.Time               `json:"updated_at"`
+	ID               uuid.UUID               `json:"id"`
+	Email            string                  `json:"email"`
+	FullName         string                  `json:"full_name"`
+	Role             string                  `json:"role"`
+	TwoFactorEnabled bool                    `json:"two_factor_enabled"`
+	DoctorProfile    *DoctorProfileResponse  `json:"doctor_profile,omitempty"`
+	PatientProfile   *PatientProfileResponse `json:"patient_profile,omitempty"`
+	CreatedAt        time.Time               `json:"created_at"`
+	UpdatedAt        time.Time               `json:"updated_at"`
+
+	// Only on /auth/me, to prompt the user to complete the profile
+	ProfileCompleteness *ProfileCompletenessResponse `json:"profile_completeness,omitempty"`
+}
+
+// ProfileCompletenessResponse scores the filled-in profile fields. RequiredForBooking lists
+// the missing fields a patient must add before booking.
+type ProfileCompletenessResponse struct {
+	Score              int      `json:"score"` // 0-100
+	Missing            []string `json:"missing"`
+	RequiredForBooking []string `json:"required_for_booking"`
 }
 
 // Role-specific Registration Request DTOs
@@ -52,10 +193,10 @@ type RegisterPatientRequest struct {
 
 // RegisterDoctorRequest untuk registrasi dokter
 type RegisterDoctorRequest struct {
-	Email          string `json:"email" validate:"required,email"`
-	Password       string `json:"password" validate:"required,min=6"`
-	FullName       string `json:"full_name" validate:"required,min=2"`
-	STRNumber      string `json:"str_number" validate:"required"`
-	Specialization string `json:"specialization" validate:"required"`
-	Biography      string `json:"biography" validate:"omitempty"`
+	Email            string `json:"email" validate:"required,email"`
+	Password         string `json:"password" validate:"required,min=6"`
+	FullName         string `json:"full_name" validate:"required,min=2"`
+	STRNumber        string `json:"str_number" validate:"required"`
+	SpecializationID int    `json:"specialization_id" validate:"required,min=1"`
+	Biography        string `json:"biography" validate:"omitempty"`
 }
@@ -9,24 +9,44 @@ import (
 // Request DTOs
 
 type CreateBookingRequest struct {
-	ScheduleID int `json:"schedule_id" validate:"required,min=1"`
+	ScheduleID int    `json:"schedule_id" validate:"required,min=1"`
+	SlotID     *int64 `json:"slot_id" validate:"omitempty,min=1"` // Required for time-slot schedules
+
+	// Attach the insurance of the patient profile (must be valid on the schedule date)
+	UseInsurance bool `json:"use_insurance"`
 }
 
 // Response DTOs
 
 type BookingResponse struct {
-	ID          uuid.UUID         `json:"id"`
-	PatientID   uuid.UUID         `json:"patient_id"`
-	ScheduleID  int               `json:"schedule_id"`
-	BookingCode string            `json:"booking_code"`
-	QueueNumber int               `json:"queue_number"`
-	Status      string            `json:"status"`
-	Schedule    *ScheduleResponse `json:"schedule,omitempty"`
-	CreatedAt   time.Time         `json:"created_at"`
-	UpdatedAt   time.Time         `json:"updated_at"`
+	ID          uuid.UUID             `json:"id"`
+	PatientID   uuid.UUID             `json:"patient_id"`
+	ScheduleID  int                   `json:"schedule_id"`
+	BookingCode string                `json:"booking_code"`
+	QueueNumber int                   `json:"queue_number"`
+	Status      string                `json:"status"`
+	Slot        *ScheduleSlotResponse `json:"slot,omitempty"`
+	Patient     *PatientResponse      `json:"patient,omitempty"`
+	Schedule    *ScheduleResponse     `json:"schedule,omitempty"`
+	CalledAt    *time.Time            `json:"called_at,omitempty"`
+	Insurance   *InsuranceResponse    `json:"insurance,omitempty"` // Insurance used for claims
+	Payment     *PaymentResponse      `json:"payment,omitempty"`   // Consultation fee, when charged through the payment gateway
+	Version     int                   `json:"version"`
+	CreatedAt   time.Time             `json:"created_at"`
+
+	// Estimated wait from the doctor's rolling average minutes per queue number (upcoming bookings only)
+	EstimatedWaitMinutes *int       `json:"estimated_wait_minutes,omitempty"`
+	EstimatedCallAt      *time.Time `json:"estimated_call_at,omitempty"`
+	UpdatedAt            time.Time  `json:"updated_at"`
+	DeletedAt            *time.Time `json:"deleted_at,omitempty"` // Deleted bookings only (admin)
 }
 
 type BookingListResponse struct {
 	Bookings []BookingResponse `json:"bookings"`
 	Total    int               `json:"total"`
 }
+
+type WaitlistResponse struct {
+	ScheduleID int `json:"schedule_id"`
+	Position   int `json:"position"`
+}
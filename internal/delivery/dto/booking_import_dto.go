@@ -0,0 +1,17 @@
+package dto
+
+// Response DTOs
+
+type BookingImportRowError struct {
+	Row     int    `json:"row"` // CSV line number, the header is row 1
+	Field   string `json:"field,omitempty"`
+	Message string `json:"message"`
+}
+
+type BookingImportResponse struct {
+	DryRun      bool                    `json:"dry_run"`
+	TotalRows   int                     `json:"total_rows"`
+	Imported    int                     `json:"imported"`
+	ScheduleIDs []int                   `json:"schedule_ids"`
+	Errors      []BookingImportRowError `json:"errors,omitempty"`
+}
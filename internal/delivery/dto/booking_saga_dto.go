@@ -0,0 +1,25 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Response DTOs
+
+type BookingSagaResponse struct {
+	ID          uuid.UUID  `json:"id"`
+	BookingID   uuid.UUID  `json:"booking_id"`
+	BookingCode string     `json:"booking_code"`
+	PatientID   uuid.UUID  `json:"patient_id"`
+	ScheduleID  int        `json:"schedule_id"`
+	SlotID      *int64     `json:"slot_id,omitempty"`
+	QueueNumber int        `json:"queue_number"`
+	Status      string     `json:"status"`
+	Step        string     `json:"step"` // Last completed step not yet compensated
+	LastError   string     `json:"last_error,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+	FinishedAt  *time.Time `json:"finished_at,omitempty"`
+}
@@ -0,0 +1,66 @@
+package dto
+
+import (
+	"time"
+
+	"go-template-clean-architecture/internal/domain/entity"
+
+	"github.com/google/uuid"
+)
+
+// Request DTOs
+
+type CreateBroadcastRequest struct {
+	Title   string                  `json:"title" validate:"required,max=150"`
+	Message string                  `json:"message" validate:"required,max=2000"`
+	Segment BroadcastSegmentRequest `json:"segment"`
+}
+
+// BroadcastSegmentRequest selects the recipients. Empty = every active patient,
+// otherwise patients with an active booking matching all filters.
+type BroadcastSegmentRequest struct {
+	DoctorID     *uuid.UUID `json:"doctor_id,omitempty"`
+	DateFrom     string     `json:"date_from,omitempty"` // YYYY-MM-DD, schedule date
+	DateTo       string     `json:"date_to,omitempty"`   // YYYY-MM-DD, schedule date
+	UpcomingOnly bool       `json:"upcoming_only,omitempty"`
+}
+
+// Response DTOs
+
+type BroadcastResponse struct {
+	ID              uuid.UUID               `json:"id"`
+	Title           string                  `json:"title"`
+	Message         string                  `json:"message"`
+	Segment         entity.JSON             `json:"segment,omitempty"`
+	TotalRecipients int                     `json:"total_recipients"`
+	CreatedBy       *uuid.UUID              `json:"created_by,omitempty"`
+	CreatedAt       time.Time               `json:"created_at"`
+	Report          BroadcastDeliveryReport `json:"report"`
+}
+
+// BroadcastDeliveryReport summarizes the delivery progress of a broadcast
+type BroadcastDeliveryReport struct {
+	Pending   int64                      `json:"pending"`
+	Sent      int64                      `json:"sent"`
+	Failed    int64                      `json:"failed"`
+	Completed bool                       `json:"completed"`
+	Failures  []BroadcastFailureResponse `json:"failures,omitempty"`
+}
+
+type BroadcastFailureResponse struct {
+	PatientID   uuid.UUID `json:"patient_id"`
+	PatientName string    `json:"patient_name,omitempty"`
+	Attempts    int       `json:"attempts"`
+	Error       string    `json:"error"`
+}
+
+type BroadcastListResponse struct {
+	Broadcasts []BroadcastResponse `json:"broadcasts"`
+	Total      int                 `json:"total"`
+}
+
+// BroadcastPreviewResponse is returned for dry runs - nothing is sent
+type BroadcastPreviewResponse struct {
+	TotalRecipients       int       `json:"total_recipients"`
+	EstimatedCompletionAt time.Time `json:"estimated_completion_at"`
+}
@@ -0,0 +1,29 @@
+package dto
+
+import (
+	"time"
+
+	"go-template-clean-architecture/internal/domain/entity"
+
+	"github.com/google/uuid"
+)
+
+// DataExportResponse is the state of a personal data export
+type DataExportResponse struct {
+	ID          uuid.UUID  `json:"id"`
+	Status      string     `json:"status"` // pending, ready, failed, expired
+	FileSize    int64      `json:"file_size,omitempty"`
+	RequestedAt time.Time  `json:"requested_at"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+	ExpiresAt   *time.Time `json:"expires_at,omitempty"`
+	DownloadURL string     `json:"download_url,omitempty"` // Ready exports only, needs the access token
+}
+
+// DataExportAuditEntry is an entry of the audit trail in a personal data export.
+// Staff members acting on the patient's data are not named.
+type DataExportAuditEntry struct {
+	Action      string      `json:"action"`
+	PerformedBy string      `json:"performed_by"` // you, staff
+	Metadata    entity.JSON `json:"metadata,omitempty"`
+	CreatedAt   time.Time   `json:"created_at"`
+}
@@ -7,22 +7,24 @@ import (
 // Request DTOs
 
 type CreateDoctorRequest struct {
-	Email          string `json:"email" validate:"required,email"`
-	Password       string `json:"password" validate:"required,min=6"`
-	FullName       string `json:"full_name" validate:"required,min=2"`
-	STRNumber      string `json:"str_number" validate:"required"`
-	Specialization string `json:"specialization" validate:"required"`
-	Biography      string `json:"biography" validate:"omitempty"`
+	Email            string `json:"email" validate:"required,email"`
+	Password         string `json:"password" validate:"required,min=6"`
+	FullName         string `json:"full_name" validate:"required,min=2"`
+	STRNumber        string `json:"str_number" validate:"required"`
+	SpecializationID int    `json:"specialization_id" validate:"required,min=1"`
+	Biography        string `json:"biography" validate:"omitempty"`
+	ConsultationFee  *int64 `json:"consultation_fee" validate:"omitempty,gte=0"` // Defaults to the specialization fee
 }
 
 type UpdateDoctorRequest struct {
-	Email          string `json:"email" validate:"omitempty,email"`
-	Password       string `json:"password" validate:"omitempty,min=6"`
-	FullName       string `json:"full_name" validate:"omitempty,min=2"`
-	STRNumber      string `json:"str_number" validate:"omitempty"`
-	Specialization string `json:"specialization" validate:"omitempty"`
-	Biography      string `json:"biography" validate:"omitempty"`
-	IsActive       *bool  `json:"is_active" validate:"omitempty"`
+	Email            string `json:"email" validate:"omitempty,email"`
+	Password         string `json:"password" validate:"omitempty,min=6"`
+	FullName         string `json:"full_name" validate:"omitempty,min=2"`
+	STRNumber        string `json:"str_number" validate:"omitempty"`
+	SpecializationID int    `json:"specialization_id" validate:"omitempty,min=1"`
+	Biography        string `json:"biography" validate:"omitempty"`
+	ConsultationFee  *int64 `json:"consultation_fee" validate:"omitempty,gte=0"`
+	IsActive         *bool  `json:"is_active" validate:"omitempty"`
 }
 
 type DoctorUpdateSelfRequest struct {
@@ -31,16 +33,34 @@ type DoctorUpdateSelfRequest struct {
 	Biography   string `json:"biography" validate:"omitempty"`
 }
 
+// DoctorFilter for query param filtering and sorting on the doctor lists
+type DoctorFilter struct {
+	Specialization string `json:"specialization"` // Filter by specialization slug
+	Active         *bool  `json:"active"`         // Admin list only, the public list shows active doctors
+	Search         string `json:"search"`         // Filter by full name
+	Sort           string `json:"sort"`           // name (default), newest, fee_asc, fee_desc, rating
+}
+
 // Response DTOs
 
 type DoctorResponse struct {
-	ID             uuid.UUID `json:"id"`
-	Email          string    `json:"email"`
-	FullName       string    `json:"full_name"`
-	STRNumber      string    `json:"str_number"`
-	Specialization string    `json:"specialization"`
-	Biography      string    `json:"biography,omitempty"`
-	IsActive       *bool     `json:"is_active"`
+	ID                 uuid.UUID `json:"id"`
+	Email              string    `json:"email"`
+	FullName           string    `json:"full_name"`
+	STRNumber          string    `json:"str_number"`
+	SpecializationID   int       `json:"specialization_id"`
+	Specialization     string    `json:"specialization"` // Name of the specialization
+	SpecializationSlug string    `json:"specialization_slug"`
+	Biography          string    `json:"biography,omitempty"`
+	ConsultationFee    int64     `json:"consultation_fee"` // Smallest currency unit
+	PhotoURL           string    `json:"photo_url,omitempty"`
+	IsActive           *bool     `json:"is_active"`
+
+	AverageWaitMinutes *float64 `json:"average_wait_minutes,omitempty"` // From patient wait feedback
+	WaitFeedbackCount  int64    `json:"wait_feedback_count,omitempty"`
+
+	AverageRating *float64 `json:"average_rating,omitempty"` // From patient reviews
+	ReviewCount   int64    `json:"review_count,omitempty"`
 }
 
 type DoctorListResponse struct {
@@ -48,9 +68,17 @@ type DoctorListResponse struct {
 	Total   int              `json:"total"`
 }
 
+// DoctorDetailResponse is the public profile of a doctor with their next bookable schedules
+type DoctorDetailResponse struct {
+	DoctorResponse
+	UpcomingSchedules []ScheduleResponse `json:"upcoming_schedules"` // Accepting bookings, with remaining quota
+}
+
 // DoctorProfileResponse represents doctor profile data embedded in UserResponse
 type DoctorProfileResponse struct {
-	STRNumber      string `json:"str_number"`
-	Specialization string `json:"specialization"`
-	Biography      string `json:"biography,omitempty"`
+	STRNumber          string `json:"str_number"`
+	SpecializationID   int    `json:"specialization_id"`
+	Specialization     string `json:"specialization"` // Name of the specialization
+	SpecializationSlug string `json:"specialization_slug"`
+	Biography          string `json:"biography,omitempty"`
 }
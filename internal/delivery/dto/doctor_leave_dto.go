@@ -0,0 +1,36 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Request DTOs
+
+type CreateDoctorLeaveRequest struct {
+	StartDate string `json:"start_date" validate:"required"` // Format: YYYY-MM-DD
+	EndDate   string `json:"end_date" validate:"required"`   // Format: YYYY-MM-DD, inclusive
+	Reason    string `json:"reason" validate:"omitempty,max=500"`
+}
+
+// Response DTOs
+
+type DoctorLeaveResponse struct {
+	ID        int        `json:"id"`
+	DoctorID  uuid.UUID  `json:"doctor_id"`
+	StartDate string     `json:"start_date"`
+	EndDate   string     `json:"end_date"`
+	Reason    string     `json:"reason,omitempty"`
+	CreatedBy *uuid.UUID `json:"created_by,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+
+	// Schedules in the leave that were flagged and closed for booking (create only)
+	FlaggedSchedules int64 `json:"flagged_schedules"`
+}
+
+type DoctorLeaveListResponse struct {
+	Leaves []DoctorLeaveResponse `json:"leaves"`
+	Total  int                   `json:"total"`
+}
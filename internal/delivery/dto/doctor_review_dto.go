@@ -0,0 +1,33 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Request DTOs
+
+type DoctorReviewRequest struct {
+	Rating  int    `json:"rating" validate:"required,min=1,max=5"`
+	Comment string `json:"comment" validate:"omitempty,max=1000"`
+}
+
+// Response DTOs
+
+type DoctorReviewResponse struct {
+	BookingID  uuid.UUID `json:"booking_id"`
+	DoctorID   uuid.UUID `json:"doctor_id"`
+	ScheduleID int       `json:"schedule_id"`
+	Rating     int       `json:"rating"`
+	Comment    string    `json:"comment,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// DoctorReviewItemResponse is a review as shown on the doctor's public page
+type DoctorReviewItemResponse struct {
+	Rating       int       `json:"rating"`
+	Comment      string    `json:"comment,omitempty"`
+	ReviewerName string    `json:"reviewer_name"` // First name and last initial
+	CreatedAt    time.Time `json:"created_at"`
+}
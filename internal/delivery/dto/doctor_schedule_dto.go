@@ -3,6 +3,8 @@ package dto
 import (
 	"time"
 
+	"go-template-clean-architecture/internal/domain/entity"
+
 	"github.com/google/uuid"
 )
 
@@ -10,10 +12,26 @@ import (
 
 type CreateScheduleRequest struct {
 	DoctorID     uuid.UUID `json:"doctor_id" validate:"required"`
-	ScheduleDate string    `json:"schedule_date" validate:"required"` // Format: YYYY-MM-DD
-	StartTime    string    `json:"start_time" validate:"required"`    // Format: HH:MM
-	EndTime      string    `json:"end_time" validate:"required"`      // Format: HH:MM
-	TotalQuota   int       `json:"total_quota" validate:"required,min=1"`
+	ScheduleDate string    `json:"schedule_date" validate:"required"`      // Format: YYYY-MM-DD
+	StartTime    string    `json:"start_time" validate:"required"`         // Format: HH:MM
+	EndTime      string    `json:"end_time" validate:"omitempty"`          // Format: HH:MM, defaults from specialization
+	TotalQuota   int       `json:"total_quota" validate:"omitempty,min=1"` // Defaults from specialization
+
+	// Time-slot mode: the schedule is split into fixed-length appointments (quota = number of slots)
+	BookingMode string `json:"booking_mode" validate:"omitempty,oneof=queue slot"` // Default: queue
+	SlotMinutes int    `json:"slot_minutes" validate:"omitempty,min=5,max=240"`    // Defaults to the specialization consultation minutes
+
+	// Bookings must be made at least this many hours in advance (default: global booking cutoff)
+	MinLeadHours *int `json:"min_lead_hours" validate:"omitempty,min=0,max=720"`
+
+	// Pauses inside the schedule; time slots skip them
+	Breaks []ScheduleBreakRequest `json:"breaks" validate:"omitempty,max=10,dive"`
+}
+
+// ScheduleBreakRequest is a pause inside a schedule, e.g. lunch 12:00-13:00
+type ScheduleBreakRequest struct {
+	StartTime string `json:"start_time" validate:"required"` // Format: HH:MM
+	EndTime   string `json:"end_time" validate:"required"`   // Format: HH:MM
 }
 
 type UpdateScheduleRequest struct {
@@ -22,6 +40,79 @@ type UpdateScheduleRequest struct {
 	StartTime    string    `json:"start_time" validate:"omitempty"`    // Format: HH:MM
 	EndTime      string    `json:"end_time" validate:"omitempty"`      // Format: HH:MM
 	TotalQuota   *int      `json:"total_quota" validate:"omitempty,min=1"`
+
+	// Minimum booking lead time in hours; ClearMinLeadHours reverts to the global booking cutoff
+	MinLeadHours      *int `json:"min_lead_hours" validate:"omitempty,min=0,max=720"`
+	ClearMinLeadHours bool `json:"clear_min_lead_hours" validate:"excluded_with=MinLeadHours"`
+
+	// Breaks replace the current ones; ClearBreaks removes them (queue mode only)
+	Breaks      []ScheduleBreakRequest `json:"breaks" validate:"omitempty,max=10,dive"`
+	ClearBreaks bool                   `json:"clear_breaks" validate:"excluded_with=Breaks"`
+}
+
+// ProposeScheduleRequest is a schedule a doctor proposes for themselves.
+// It waits for admin approval before patients can book it.
+type ProposeScheduleRequest struct {
+	ScheduleDate string `json:"schedule_date" validate:"required"`      // Format: YYYY-MM-DD
+	StartTime    string `json:"start_time" validate:"required"`         // Format: HH:MM
+	EndTime      string `json:"end_time" validate:"omitempty"`          // Format: HH:MM, defaults from specialization
+	TotalQuota   int    `json:"total_quota" validate:"omitempty,min=1"` // Defaults from specialization
+
+	BookingMode  string `json:"booking_mode" validate:"omitempty,oneof=queue slot"` // Default: queue
+	SlotMinutes  int    `json:"slot_minutes" validate:"omitempty,min=5,max=240"`    // Defaults to the specialization consultation minutes
+	MinLeadHours *int   `json:"min_lead_hours" validate:"omitempty,min=0,max=720"`  // Default: global booking cutoff
+
+	Breaks []ScheduleBreakRequest `json:"breaks" validate:"omitempty,max=10,dive"`
+}
+
+// RejectScheduleRequest declines a doctor's schedule proposal
+type RejectScheduleRequest struct {
+	Reason string `json:"reason" validate:"required,max=500"`
+}
+
+// ReassignBookingsRequest moves a schedule's active bookings to another schedule of the same doctor.
+// If TargetScheduleID is omitted, all active bookings are cancelled instead.
+type ReassignBookingsRequest struct {
+	TargetScheduleID int  `json:"target_schedule_id" validate:"omitempty,min=1"`
+	CancelOverflow   bool `json:"cancel_overflow"` // Cancel bookings that do not fit in the target quota
+}
+
+// CopySchedulesRequest duplicates a doctor's schedules from one week to another.
+// Any date inside a week selects that week (Monday to Sunday).
+type CopySchedulesRequest struct {
+	DoctorID   uuid.UUID `json:"doctor_id" validate:"required"`
+	SourceWeek string    `json:"source_week" validate:"required"` // Format: YYYY-MM-DD
+	TargetWeek string    `json:"target_week" validate:"required"` // Format: YYYY-MM-DD
+}
+
+// BulkUpdateQuotaRequest changes the quota of a doctor's upcoming schedules in one operation.
+// Either sets TotalQuota on every schedule or adds QuotaDelta (may be negative) to each quota.
+type BulkUpdateQuotaRequest struct {
+	DoctorID   uuid.UUID `json:"doctor_id" validate:"required"`
+	StartDate  string    `json:"start_date" validate:"omitempty"` // Format: YYYY-MM-DD, defaults to today
+	EndDate    string    `json:"end_date" validate:"omitempty"`   // Format: YYYY-MM-DD, defaults to all future schedules
+	TotalQuota *int      `json:"total_quota" validate:"required_without=QuotaDelta,omitempty,min=1"`
+	QuotaDelta *int      `json:"quota_delta" validate:"required_without=TotalQuota,excluded_with=TotalQuota,omitempty,ne=0"`
+}
+
+// SetScheduleOpenRequest opens or pauses booking on a schedule
+type SetScheduleOpenRequest struct {
+	IsOpen *bool `json:"is_open" validate:"required"`
+}
+
+// ScheduleUtilizationFilter holds the optional query params of the utilization report
+type ScheduleUtilizationFilter struct {
+	StartDate      string // Format: YYYY-MM-DD, defaults to the first of the current month
+	EndDate        string // Format: YYYY-MM-DD, defaults to today
+	DoctorName     string
+	Specialization string
+}
+
+// DoctorStatsFilter holds the optional query params of the doctor statistics
+type DoctorStatsFilter struct {
+	StartDate string // Format: YYYY-MM-DD, defaults to the first of the month five months ago
+	EndDate   string // Format: YYYY-MM-DD, defaults to today
+	Period    string // day, week or month (default)
 }
 
 // Response DTOs
@@ -34,8 +125,39 @@ type ScheduleResponse struct {
 	StartTime    string          `json:"start_time"`
 	EndTime      string          `json:"end_time"`
 	TotalQuota   int             `json:"total_quota"`
+	BookingMode  string          `json:"booking_mode"`
+	SlotMinutes  int             `json:"slot_minutes,omitempty"`
+	IsOpen       bool            `json:"is_open"`
+	MinLeadHours *int            `json:"min_lead_hours,omitempty"`
 	CreatedAt    time.Time       `json:"created_at"`
 	UpdatedAt    time.Time       `json:"updated_at"`
+
+	// Fee of a consultation on the schedule (the doctor's fee), only with the doctor
+	ConsultationFee *int64 `json:"consultation_fee,omitempty"`
+
+	DoctorCheckedInAt *time.Time `json:"doctor_checked_in_at,omitempty"`
+	PossiblyAbsent    bool       `json:"possibly_absent"`
+	HolidayFlaggedAt  *time.Time `json:"holiday_flagged_at,omitempty"`
+	LeaveFlaggedAt    *time.Time `json:"leave_flagged_at,omitempty"`
+	TemplateID        *int       `json:"template_id,omitempty"`
+
+	Breaks []ScheduleBreakResponse `json:"breaks"`
+
+	// Approval workflow (schedules proposed by doctors)
+	ApprovalStatus  string     `json:"approval_status"`
+	ReviewedAt      *time.Time `json:"reviewed_at,omitempty"`
+	RejectionReason string     `json:"rejection_reason,omitempty"`
+
+	// Live availability, only filled on schedule reads
+	RemainingQuota *int  `json:"remaining_quota,omitempty"`
+	IsFull         *bool `json:"is_full,omitempty"`
+	Bookable       *bool `json:"bookable,omitempty"` // Whether a patient can book it right now
+}
+
+// ScheduleBreakResponse is a pause inside a schedule
+type ScheduleBreakResponse struct {
+	StartTime string `json:"start_time"`
+	EndTime   string `json:"end_time"`
 }
 
 type ScheduleListResponse struct {
@@ -43,10 +165,187 @@ type ScheduleListResponse struct {
 	Total     int                `json:"total"`
 }
 
-// PublicScheduleFilter for query param filtering on public schedules endpoint
-type PublicScheduleFilter struct {
+// DoctorAvailabilityResponse is a doctor's bookable days of one month (calendar pickers)
+type DoctorAvailabilityResponse struct {
+	DoctorID uuid.UUID                 `json:"doctor_id"`
+	Month    string                    `json:"month"` // Format: YYYY-MM
+	Days     []DayAvailabilityResponse `json:"days"`  // Every day of the month
+}
+
+// DayAvailabilityResponse is the availability of a doctor on one day
+type DayAvailabilityResponse struct {
+	Date            string `json:"date"`
+	HasOpenSchedule bool   `json:"has_open_schedule"` // A schedule accepts bookings now
+	RemainingSlots  int    `json:"remaining_slots"`   // Summed over the schedules accepting bookings
+}
+
+// NextAvailableResponse is the earliest schedule of a doctor that can still be booked
+type NextAvailableResponse struct {
+	DoctorID uuid.UUID         `json:"doctor_id"`
+	Schedule *ScheduleResponse `json:"schedule"` // null when nothing is bookable in the scanned window
+}
+
+// ScheduleVersionResponse is one change in the history of a schedule
+type ScheduleVersionResponse struct {
+	Version       int                      `json:"version"` // 1 = first recorded change
+	ID            int64                    `json:"id"`
+	Action        string                   `json:"action"`
+	ChangedBy     *UserResponse            `json:"changed_by,omitempty"` // Omitted for system changes
+	ChangedFields []string                 `json:"changed_fields"`
+	OldValue      *entity.ScheduleSnapshot `json:"old_value"` // null on create
+	NewValue      *entity.ScheduleSnapshot `json:"new_value"` // null on delete
+	CreatedAt     time.Time                `json:"created_at"`
+}
+
+// ScheduleHistoryResponse lists the changes of a schedule, oldest first
+type ScheduleHistoryResponse struct {
+	ScheduleID int                       `json:"schedule_id"`
+	Versions   []ScheduleVersionResponse `json:"versions"`
+	Total      int                       `json:"total"`
+}
+
+// ScheduleFilter for query param filtering on the public and admin schedule listings
+type ScheduleFilter struct {
 	StartAt        string `json:"start_at"`       // Format: YYYY-MM-DD
 	EndAt          string `json:"end_at"`         // Format: YYYY-MM-DD
 	DoctorName     string `json:"doctor_name"`    // Filter by doctor name
-	Specialization string `json:"specialization"` // Filter by specialization
+	Specialization string `json:"specialization"` // Filter by specialization slug
+}
+
+// ReassignBookingsResponse summarizes a bulk reassignment
+type ReassignBookingsResponse struct {
+	SourceScheduleID int         `json:"source_schedule_id"`
+	TargetScheduleID int         `json:"target_schedule_id,omitempty"`
+	Moved            []uuid.UUID `json:"moved"`
+	Cancelled        []uuid.UUID `json:"cancelled"`
+}
+
+// DeleteScheduleResponse summarizes a schedule deletion, or why it was refused
+type DeleteScheduleResponse struct {
+	ScheduleID        int   `json:"schedule_id"`
+	ActiveBookings    int   `json:"active_bookings"`
+	CancelledBookings int   `json:"cancelled_bookings"` // Active bookings cancelled and notified (force only)
+	RemovedBookings   int64 `json:"removed_bookings"`   // Bookings soft-deleted with the schedule, incl. cancelled ones (force only)
+}
+
+// CopySchedulesResponse summarizes a week-to-week schedule copy
+type CopySchedulesResponse struct {
+	SourceWeekStart string             `json:"source_week_start"`
+	TargetWeekStart string             `json:"target_week_start"`
+	Created         []ScheduleResponse `json:"created"`
+	Skipped         []CopySkipResponse `json:"skipped"`
+}
+
+// CopySkipResponse is a source schedule that was not copied
+type CopySkipResponse struct {
+	SourceScheduleID int    `json:"source_schedule_id"`
+	ScheduleDate     string `json:"schedule_date"` // Target date
+	Reason           string `json:"reason"`        // not_approved, past_date, holiday or conflict
+}
+
+// BulkUpdateQuotaResponse summarizes a bulk quota change
+type BulkUpdateQuotaResponse struct {
+	DoctorID  uuid.UUID                 `json:"doctor_id"`
+	StartDate string                    `json:"start_date"`
+	EndDate   string                    `json:"end_date,omitempty"`
+	Updated   []QuotaChangeResponse     `json:"updated"`
+	Skipped   []QuotaChangeSkipResponse `json:"skipped"`
+}
+
+// QuotaChangeResponse is a schedule whose quota was changed
+type QuotaChangeResponse struct {
+	ScheduleID   int    `json:"schedule_id"`
+	ScheduleDate string `json:"schedule_date"`
+	OldQuota     int    `json:"old_quota"`
+	NewQuota     int    `json:"new_quota"`
+}
+
+// QuotaChangeSkipResponse is a schedule whose quota was left unchanged
+type QuotaChangeSkipResponse struct {
+	ScheduleID   int    `json:"schedule_id"`
+	ScheduleDate string `json:"schedule_date"`
+	Reason       string `json:"reason"` // not_approved, slot_mode, unchanged, below_minimum or below_booked
+}
+
+// ScheduleSlotResponse is an appointment time of a slot-mode schedule
+type ScheduleSlotResponse struct {
+	ID        int64  `json:"id"`
+	Position  int    `json:"position"`
+	StartTime string `json:"start_time"`
+	EndTime   string `json:"end_time"`
+	Available *bool  `json:"available,omitempty"` // Only in slot listings
+}
+
+// ScheduleSummaryResponse is the doctor's in-clinic day view of one schedule
+type ScheduleSummaryResponse struct {
+	ScheduleID       int    `json:"schedule_id"`
+	ScheduleDate     string `json:"schedule_date"`
+	StartTime        string `json:"start_time"`
+	EndTime          string `json:"end_time"`
+	Booked           int    `json:"booked"`
+	CheckedIn        int    `json:"checked_in"`
+	Completed        int    `json:"completed"`
+	Waiting          int    `json:"waiting"`
+	NoShow           int    `json:"no_show"`
+	CurrentlyServing *int   `json:"currently_serving"` // Queue number last called, null before the first call
+}
+
+type ScheduleSlotListResponse struct {
+	ScheduleID int                    `json:"schedule_id"`
+	Slots      []ScheduleSlotResponse `json:"slots"`
+	Total      int                    `json:"total"`
+	Available  int                    `json:"available"`
+}
+
+// ScheduleUtilizationResponse is one schedule's row of the utilization report
+type ScheduleUtilizationResponse struct {
+	ScheduleID   int       `json:"schedule_id"`
+	DoctorID     uuid.UUID `json:"doctor_id"`
+	DoctorName   string    `json:"doctor_name"`
+	ScheduleDate string    `json:"schedule_date"`
+	StartTime    string    `json:"start_time"`
+	EndTime      string    `json:"end_time"`
+	TotalQuota   int       `json:"total_quota"`
+	Booked       int64     `json:"booked"`
+	Cancelled    int64     `json:"cancelled"`
+	NoShow       int64     `json:"no_show"`
+	FillRate     float64   `json:"fill_rate"` // booked / total_quota, 0-1
+}
+
+// ScheduleUtilizationReportResponse is the schedule utilization report over a date range
+type ScheduleUtilizationReportResponse struct {
+	StartDate  string                        `json:"start_date"`
+	EndDate    string                        `json:"end_date"`
+	Schedules  []ScheduleUtilizationResponse `json:"schedules"`
+	Total      int                           `json:"total"`
+	TotalQuota int64                         `json:"total_quota"`
+	Booked     int64                         `json:"booked"`
+	Cancelled  int64                         `json:"cancelled"`
+	NoShow     int64                         `json:"no_show"`
+	FillRate   float64                       `json:"fill_rate"` // Overall booked / total_quota, 0-1
+}
+
+// DoctorPeriodStatsResponse summarizes a doctor's schedules in one period (or all of them)
+type DoctorPeriodStatsResponse struct {
+	PeriodStart string `json:"period_start,omitempty"` // Omitted on the totals
+	Schedules   int64  `json:"schedules"`
+	TotalQuota  int64  `json:"total_quota"`
+	Booked      int64  `json:"booked"`
+	Served      int64  `json:"served"`
+	Cancelled   int64  `json:"cancelled"`
+	NoShow      int64  `json:"no_show"`
+
+	// Average minutes between consecutive queue calls, nil without any
+	AvgConsultationMinutes *float64 `json:"avg_consultation_minutes"`
+	Utilization            float64  `json:"utilization"` // booked / total_quota, 0-1
+}
+
+// DoctorStatsResponse is the performance summary of a doctor over a date range
+type DoctorStatsResponse struct {
+	DoctorID  uuid.UUID                   `json:"doctor_id"`
+	StartDate string                      `json:"start_date"`
+	EndDate   string                      `json:"end_date"`
+	Period    string                      `json:"period"`
+	Periods   []DoctorPeriodStatsResponse `json:"periods"` // Periods without schedules are omitted
+	Totals    DoctorPeriodStatsResponse   `json:"totals"`
 }
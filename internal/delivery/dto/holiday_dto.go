@@ -0,0 +1,36 @@
+package dto
+
+import "time"
+
+// Request DTOs
+
+type CreateHolidayRequest struct {
+	Date        string `json:"date" validate:"required"` // Format: YYYY-MM-DD
+	Name        string `json:"name" validate:"required,max=150"`
+	Description string `json:"description" validate:"omitempty"`
+}
+
+type UpdateHolidayRequest struct {
+	Date        string  `json:"date" validate:"omitempty"` // Format: YYYY-MM-DD
+	Name        string  `json:"name" validate:"omitempty,max=150"`
+	Description *string `json:"description" validate:"omitempty"`
+}
+
+// Response DTOs
+
+type HolidayResponse struct {
+	ID          int       `json:"id"`
+	Date        string    `json:"date"`
+	Name        string    `json:"name"`
+	Description string    `json:"description,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+
+	// Schedules on the date that were flagged for admin action (create/update only)
+	FlaggedSchedules int64 `json:"flagged_schedules"`
+}
+
+type HolidayListResponse struct {
+	Holidays []HolidayResponse `json:"holidays"`
+	Total    int               `json:"total"`
+}
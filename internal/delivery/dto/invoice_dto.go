@@ -0,0 +1,59 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// InvoiceResponse is the invoice of a booking
+type InvoiceResponse struct {
+	ID          uuid.UUID             `json:"id"`
+	Number      string                `json:"number"`
+	BookingID   uuid.UUID             `json:"booking_id"`
+	BookingCode string                `json:"booking_code"`
+	PaymentID   *uuid.UUID            `json:"payment_id,omitempty"`
+	PatientID   uuid.UUID             `json:"patient_id"`
+	PatientName string                `json:"patient_name"`
+	DoctorID    uuid.UUID             `json:"doctor_id"`
+	DoctorName  string                `json:"doctor_name"`
+	ServiceDate string                `json:"service_date"` // YYYY-MM-DD
+	Currency    string                `json:"currency"`
+	Total       int64                 `json:"total"`  // Smallest currency unit
+	Status      string                `json:"status"` // paid, issued, refunded
+	IssuedAt    time.Time             `json:"issued_at"`
+	Items       []InvoiceItemResponse `json:"items"`
+}
+
+// InvoiceItemResponse is one line of an invoice
+type InvoiceItemResponse struct {
+	Type        string `json:"type"` // consultation_fee, admin_fee
+	Description string `json:"description"`
+	Amount      int64  `json:"amount"`
+}
+
+// InvoiceFilter for query param filtering of the admin invoice list and finance report
+type InvoiceFilter struct {
+	Status    string `json:"status"`
+	DoctorID  string `json:"doctor_id"`
+	PatientID string `json:"patient_id"`
+	From      string `json:"from"` // YYYY-MM-DD, clinic time, issue date
+	To        string `json:"to"`   // YYYY-MM-DD, inclusive
+}
+
+// FinanceReportResponse totals the invoices issued in a period (admin finance view)
+type FinanceReportResponse struct {
+	From   string                 `json:"from,omitempty"`
+	To     string                 `json:"to,omitempty"`
+	Totals []FinanceTotalResponse `json:"totals"`
+}
+
+// FinanceTotalResponse totals the invoices of one currency and status
+type FinanceTotalResponse struct {
+	Currency         string `json:"currency"`
+	Status           string `json:"status"`
+	Invoices         int64  `json:"invoices"`
+	Total            int64  `json:"total"`
+	ConsultationFees int64  `json:"consultation_fees"`
+	AdminFees        int64  `json:"admin_fees"`
+}
@@ -0,0 +1,14 @@
+package dto
+
+// Request DTOs
+
+type SetLogLevelRequest struct {
+	Level string `json:"level" validate:"required,oneof=panic fatal error warn warning info debug trace"`
+}
+
+// Response DTOs
+
+type LogLevelsResponse struct {
+	Level    string            `json:"level"`    // Level of packages without an override
+	Packages map[string]string `json:"packages"` // Effective level per package
+}
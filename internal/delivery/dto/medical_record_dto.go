@@ -0,0 +1,76 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Request DTOs
+
+// MedicalRecordRequest creates or replaces the visit notes of a booking.
+// Vitals left out were not measured.
+type MedicalRecordRequest struct {
+	Diagnosis     string                 `json:"diagnosis" validate:"required,max=2000"`
+	ClinicalNotes string                 `json:"clinical_notes" validate:"omitempty,max=10000"`
+	Vitals        MedicalRecordVitalsDTO `json:"vitals"`
+}
+
+// UpdateMedicalRecordRequest replaces the visit notes at the version the doctor last read
+type UpdateMedicalRecordRequest struct {
+	MedicalRecordRequest
+	Version int `json:"version" validate:"required,min=1"`
+}
+
+// MedicalRecordVitalsDTO are the vital signs of a visit, with plausible ranges
+type MedicalRecordVitalsDTO struct {
+	SystolicBP       *int     `json:"systolic_bp,omitempty" validate:"omitempty,min=30,max=300"`      // mmHg
+	DiastolicBP      *int     `json:"diastolic_bp,omitempty" validate:"omitempty,min=10,max=200"`     // mmHg
+	HeartRate        *int     `json:"heart_rate,omitempty" validate:"omitempty,min=20,max=300"`       // Beats per minute
+	RespiratoryRate  *int     `json:"respiratory_rate,omitempty" validate:"omitempty,min=1,max=100"`  // Breaths per minute
+	Temperature      *float64 `json:"temperature,omitempty" validate:"omitempty,min=25,max=45"`       // °C
+	OxygenSaturation *int     `json:"oxygen_saturation,omitempty" validate:"omitempty,min=0,max=100"` // SpO2 %
+	WeightKg         *float64 `json:"weight_kg,omitempty" validate:"omitempty,gt=0,max=9999"`
+	HeightCm         *float64 `json:"height_cm,omitempty" validate:"omitempty,gt=0,max=999"`
+}
+
+// Response DTOs
+
+type MedicalRecordResponse struct {
+	BookingID     uuid.UUID              `json:"booking_id"`
+	BookingCode   string                 `json:"booking_code"`
+	PatientID     uuid.UUID              `json:"patient_id"`
+	DoctorID      uuid.UUID              `json:"doctor_id"`
+	DoctorName    string                 `json:"doctor_name"`
+	ScheduleID    int                    `json:"schedule_id"`
+	VisitDate     string                 `json:"visit_date"`
+	Diagnosis     string                 `json:"diagnosis"`
+	ClinicalNotes string                 `json:"clinical_notes,omitempty"`
+	Vitals        MedicalRecordVitalsDTO `json:"vitals"`
+	Version       int                    `json:"version"`
+	CreatedAt     time.Time              `json:"created_at"`
+	UpdatedAt     time.Time              `json:"updated_at"`
+}
+
+// PatientVisitResponse is one booking of a patient's visit history, with its medical record
+// when the doctor wrote one
+type PatientVisitResponse struct {
+	BookingID     uuid.UUID              `json:"booking_id"`
+	BookingCode   string                 `json:"booking_code"`
+	Status        string                 `json:"status"`
+	QueueNumber   int                    `json:"queue_number"`
+	ScheduleID    int                    `json:"schedule_id"`
+	VisitDate     string                 `json:"visit_date"`
+	StartTime     string                 `json:"start_time"`
+	EndTime       string                 `json:"end_time"`
+	DoctorID      uuid.UUID              `json:"doctor_id"`
+	DoctorName    string                 `json:"doctor_name"`
+	CalledAt      *time.Time             `json:"called_at,omitempty"`
+	MedicalRecord *MedicalRecordResponse `json:"medical_record,omitempty"`
+}
+
+// PatientHistoryResponse is a page of a patient's visit history, shown to a doctor of the patient
+type PatientHistoryResponse struct {
+	Patient *PatientResponse       `json:"patient"`
+	Visits  []PatientVisitResponse `json:"visits"`
+}
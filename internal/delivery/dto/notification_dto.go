@@ -0,0 +1,137 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Request DTOs
+
+// NotificationDeliveryReportRequest is a provider callback about one sent message
+type NotificationDeliveryReportRequest struct {
+	MessageID string `json:"message_id" validate:"required,max=255"`
+	Status    string `json:"status" validate:"required,oneof=delivered failed bounced"`
+	Error     string `json:"error,omitempty" validate:"max=1000"` // Provider failure or bounce reason
+}
+
+// RegisterDeviceRequest registers the push token (FCM registration token) of a mobile client
+type RegisterDeviceRequest struct {
+	Token    string `json:"token" validate:"required,max=512"`
+	Platform string `json:"platform" validate:"required,oneof=android ios web"`
+}
+
+// UpdateNotificationPreferenceRequest changes the given preferences, an empty channel resets it
+type UpdateNotificationPreferenceRequest struct {
+	Channel      *string `json:"channel" validate:"omitempty,oneof=sms whatsapp email"`
+	Reminders    *bool   `json:"reminders"`
+	AgendaDigest *bool   `json:"agenda_digest"` // Doctors only
+}
+
+// NotificationFilter for query param filtering of a patient's notification history
+type NotificationFilter struct {
+	Status    string `json:"status"`
+	EventType string `json:"event_type"`
+	Channel   string `json:"channel"`
+	From      string `json:"from"` // YYYY-MM-DD, clinic time
+	To        string `json:"to"`   // YYYY-MM-DD, inclusive
+}
+
+// RedriveNotificationsRequest re-drives the most recently failed dead letters matching the filters
+type RedriveNotificationsRequest struct {
+	Channel   string `json:"channel" validate:"omitempty,oneof=sms whatsapp email push"`
+	EventType string `json:"event_type" validate:"max=100"`
+	Limit     int    `json:"limit" validate:"omitempty,min=1,max=500"` // Default 100
+}
+
+// Response DTOs
+
+type NotificationResponse struct {
+	ID                uuid.UUID  `json:"id"`
+	PatientID         uuid.UUID  `json:"patient_id"`
+	BookingID         *uuid.UUID `json:"booking_id,omitempty"`
+	EventType         string     `json:"event_type"`
+	Channel           string     `json:"channel"`
+	Recipient         string     `json:"recipient"`
+	Subject           string     `json:"subject,omitempty"`
+	Message           string     `json:"message"`
+	Status            string     `json:"status"` // pending (queued), sent, delivered, failed, bounced or dead_letter
+	Provider          string     `json:"provider,omitempty"`
+	ProviderMessageID *string    `json:"provider_message_id,omitempty"`
+	Attempts          int        `json:"attempts"`
+	LastError         string     `json:"last_error,omitempty"`
+	CreatedAt         time.Time  `json:"created_at"`
+	SentAt            *time.Time `json:"sent_at,omitempty"`
+	DeliveredAt       *time.Time `json:"delivered_at,omitempty"`
+	FailedAt          *time.Time `json:"failed_at,omitempty"`
+	RedrivenAt        *time.Time `json:"redriven_at,omitempty"`
+}
+
+type NotificationListResponse struct {
+	Notifications []NotificationResponse `json:"notifications"`
+	Total         int                    `json:"total"`
+}
+
+// DeviceResponse is a registered push device, the token itself is not returned
+type DeviceResponse struct {
+	ID        int       `json:"id"`
+	Platform  string    `json:"platform"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// InAppNotificationResponse is a notification in the patient's notification center
+type InAppNotificationResponse struct {
+	ID        uuid.UUID  `json:"id"`
+	BookingID *uuid.UUID `json:"booking_id,omitempty"`
+	EventType string     `json:"event_type"`
+	Title     string     `json:"title,omitempty"`
+	Message   string     `json:"message"`
+	Read      bool       `json:"read"`
+	ReadAt    *time.Time `json:"read_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+type UnreadNotificationCountResponse struct {
+	Unread int64 `json:"unread"`
+}
+
+// NotificationPreferenceResponse is how the user wants to be notified, Channel empty = no preference
+type NotificationPreferenceResponse struct {
+	Channel      string `json:"channel"`
+	Reminders    bool   `json:"reminders"`
+	AgendaDigest bool   `json:"agenda_digest"`
+}
+
+// NotificationAttemptResponse is one send of a notification to its provider
+type NotificationAttemptResponse struct {
+	Attempt           int       `json:"attempt"`
+	Channel           string    `json:"channel"`
+	Recipient         string    `json:"recipient"`
+	Provider          string    `json:"provider"`
+	Succeeded         bool      `json:"succeeded"`
+	ProviderMessageID string    `json:"provider_message_id,omitempty"`
+	Error             string    `json:"error,omitempty"`
+	DurationMs        int64     `json:"duration_ms"`
+	CreatedAt         time.Time `json:"created_at"`
+}
+
+// NotificationDeliveryReportResponse is a provider callback about a sent notification
+type NotificationDeliveryReportResponse struct {
+	Provider   string    `json:"provider"`
+	Status     string    `json:"status"`
+	Error      string    `json:"error,omitempty"`
+	Applied    bool      `json:"applied"` // false when the notification already had a final status
+	ReceivedAt time.Time `json:"received_at"`
+}
+
+// NotificationDetailResponse is a notification with every send attempt and provider callback, oldest first
+type NotificationDetailResponse struct {
+	*NotificationResponse
+	AttemptLog      []NotificationAttemptResponse        `json:"attempt_log"`
+	DeliveryReports []NotificationDeliveryReportResponse `json:"delivery_reports"`
+}
+
+type RedriveNotificationsResponse struct {
+	Redriven int `json:"redriven"`
+}
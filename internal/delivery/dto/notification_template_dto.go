@@ -0,0 +1,54 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Request DTOs
+
+// Subject and Body are Go text/template, e.g. "Hello {{.PatientName}}, booking {{.BookingCode}}"
+type CreateNotificationTemplateRequest struct {
+	EventType string `json:"event_type" validate:"required,max=100"`                            // e.g. booking.created
+	Channel   string `json:"channel" validate:"omitempty,oneof=sms whatsapp email push in_app"` // Empty: any channel
+	Locale    string `json:"locale" validate:"omitempty,max=20"`                                // e.g. id-ID or id, empty: any locale
+	Subject   string `json:"subject" validate:"omitempty,max=255"`                              // Empty keeps the default subject
+	Body      string `json:"body" validate:"required"`
+}
+
+type UpdateNotificationTemplateRequest struct {
+	Subject *string `json:"subject" validate:"omitempty,max=255"`
+	Body    string  `json:"body" validate:"omitempty"`
+}
+
+// PreviewNotificationTemplateRequest renders a template with sample variables, Data overrides them
+type PreviewNotificationTemplateRequest struct {
+	Subject string            `json:"subject" validate:"omitempty,max=255"`
+	Body    string            `json:"body" validate:"required"`
+	Data    map[string]string `json:"data" validate:"omitempty"`
+}
+
+// Response DTOs
+
+type NotificationTemplateResponse struct {
+	ID        int        `json:"id"`
+	EventType string     `json:"event_type"`
+	Channel   string     `json:"channel"`
+	Locale    string     `json:"locale"`
+	Subject   string     `json:"subject"`
+	Body      string     `json:"body"`
+	UpdatedBy *uuid.UUID `json:"updated_by,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+}
+
+type NotificationTemplateListResponse struct {
+	Templates []NotificationTemplateResponse `json:"templates"`
+	Total     int                            `json:"total"`
+}
+
+type NotificationTemplatePreviewResponse struct {
+	Subject string `json:"subject"`
+	Body    string `json:"body"`
+}
@@ -14,6 +14,9 @@ type PatientProfileResponse struct {
 	DateOfBirth string    `json:"date_of_birth"`
 	Gender      string    `json:"gender"`
 	Address     string    `json:"address,omitempty"`
+
+	Insurance        *InsuranceResponse        `json:"insurance,omitempty"`
+	EmergencyContact *EmergencyContactResponse `json:"emergency_contact,omitempty"`
 }
 
 // PatientResponse represents a patient user with profile data
@@ -29,6 +32,38 @@ type PatientResponse struct {
 	IsActive    *bool     `json:"is_active,omitempty"`
 	CreatedAt   time.Time `json:"created_at"`
 	UpdatedAt   time.Time `json:"updated_at"`
+
+	Insurance        *InsuranceResponse        `json:"insurance,omitempty"`
+	EmergencyContact *EmergencyContactResponse `json:"emergency_contact,omitempty"`
+}
+
+// InsuranceResponse is a health insurance registered by a patient or attached to a booking
+type InsuranceResponse struct {
+	Provider     string `json:"provider"`
+	PolicyNumber string `json:"policy_number"`
+	ValidFrom    string `json:"valid_from,omitempty"`  // Format: YYYY-MM-DD, profile only
+	ValidUntil   string `json:"valid_until,omitempty"` // Format: YYYY-MM-DD, profile only
+}
+
+// EmergencyContactResponse is the person to contact in an emergency about a patient
+type EmergencyContactResponse struct {
+	Name         string `json:"name"`
+	Relationship string `json:"relationship"`
+	Phone        string `json:"phone"`
+}
+
+// PatientSearchFilter for query param filtering on the staff patient search
+type PatientSearchFilter struct {
+	NIK         string `json:"nik"`          // Exact NIK
+	PhoneNumber string `json:"phone_number"` // Exact phone number
+	Name        string `json:"name"`         // Part of the full name
+}
+
+// DeletePatientAccountRequest confirms the erasure of the patient's own account.
+// Password is required unless the account signs in with Google.
+type DeletePatientAccountRequest struct {
+	Password string `json:"password"`
+	Reason   string `json:"reason" validate:"omitempty,max=500"`
 }
 
 // PatientUpdateSelfRequest for patient self-edit profile
@@ -38,3 +73,35 @@ type PatientUpdateSelfRequest struct {
 	PhoneNumber string `json:"phone_number" validate:"omitempty,min=10,max=20"`
 	Address     string `json:"address" validate:"omitempty"`
 }
+
+// UpdateInsuranceRequest registers the patient's health insurance, replacing the previous one
+type UpdateInsuranceRequest struct {
+	Provider     string `json:"provider" validate:"required,max=100"`
+	PolicyNumber string `json:"policy_number" validate:"required,max=50"`
+	ValidFrom    string `json:"valid_from" validate:"omitempty"`  // Format: YYYY-MM-DD
+	ValidUntil   string `json:"valid_until" validate:"omitempty"` // Format: YYYY-MM-DD
+}
+
+// UpdateEmergencyContactRequest registers the patient's emergency contact, replacing the previous one
+type UpdateEmergencyContactRequest struct {
+	Name         string `json:"name" validate:"required,max=100"`
+	Relationship string `json:"relationship" validate:"required,max=50"` // e.g. spouse, parent, sibling
+	Phone        string `json:"phone" validate:"required,min=10,max=20"`
+}
+
+// PatientTimelineEventResponse is one entry of a patient's journey (admin support view)
+type PatientTimelineEventResponse struct {
+	Type        string      `json:"type"`   // e.g. patient.registered, booking.created, booking.cancel, notification
+	Source      string      `json:"source"` // patient, booking, audit_log, notification
+	OccurredAt  time.Time   `json:"occurred_at"`
+	BookingID   *uuid.UUID  `json:"booking_id,omitempty"`
+	ActorID     *uuid.UUID  `json:"actor_id,omitempty"`
+	Description string      `json:"description"`
+	Details     interface{} `json:"details,omitempty"`
+}
+
+// PatientTimelineResponse is a page of a patient's timeline, oldest first
+type PatientTimelineResponse struct {
+	Patient PatientResponse                `json:"patient"`
+	Events  []PatientTimelineEventResponse `json:"events"`
+}
@@ -0,0 +1,27 @@
+package dto
+
+// Request DTOs
+
+// RequestClaimRequest starts claiming a pre-registered account: a code is sent to its registered phone
+type RequestClaimRequest struct {
+	NIK         string `json:"nik" validate:"required,len=16,numeric"`
+	DateOfBirth string `json:"date_of_birth" validate:"required"` // Format: YYYY-MM-DD
+}
+
+// VerifyClaimRequest completes the claim with the code and sets the login credentials
+type VerifyClaimRequest struct {
+	NIK      string `json:"nik" validate:"required,len=16,numeric"`
+	Code     string `json:"code" validate:"required,len=6,numeric"`
+	Email    string `json:"email" validate:"required,email"`
+	Password string `json:"password" validate:"required,min=6"`
+}
+
+// Response DTOs
+
+type PatientRosterImportResponse struct {
+	DryRun    bool                    `json:"dry_run"`
+	Partner   string                  `json:"partner"`
+	TotalRows int                     `json:"total_rows"`
+	Imported  int                     `json:"imported"`
+	Errors    []BookingImportRowError `json:"errors,omitempty"`
+}
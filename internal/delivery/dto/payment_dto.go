@@ -0,0 +1,35 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PaymentResponse is the consultation fee payment of a booking
+type PaymentResponse struct {
+	ID            uuid.UUID  `json:"id"`
+	BookingID     uuid.UUID  `json:"booking_id"`
+	Gateway       string     `json:"gateway"`
+	Amount        int64      `json:"amount"`    // Smallest currency unit
+	AdminFee      int64      `json:"admin_fee"` // Included in amount
+	Currency      string     `json:"currency"`
+	Status        string     `json:"status"`                // pending, paid, failed, expired, cancelled
+	PaymentURL    string     `json:"payment_url,omitempty"` // Pending payments only, the page the patient pays on
+	FailureReason string     `json:"failure_reason,omitempty"`
+	ExpiresAt     time.Time  `json:"expires_at"`
+	PaidAt        *time.Time `json:"paid_at,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+
+	Refund *PaymentRefundResponse `json:"refund,omitempty"` // Paid payments refunded after the booking was cancelled
+}
+
+// PaymentRefundResponse is the refund of a paid payment
+type PaymentRefundResponse struct {
+	Status        string     `json:"status"` // pending, succeeded, failed
+	Amount        int64      `json:"amount"` // Smallest currency unit
+	Reason        string     `json:"reason"`
+	FailureReason string     `json:"failure_reason,omitempty"`
+	RequestedAt   *time.Time `json:"requested_at,omitempty"`
+	RefundedAt    *time.Time `json:"refunded_at,omitempty"`
+}
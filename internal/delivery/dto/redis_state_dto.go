@@ -0,0 +1,40 @@
+package dto
+
+// Request DTOs
+
+// UpdateRedisScheduleStateRequest manually corrects the Redis counters of a schedule.
+// Omitted counters are left untouched; at least one must be set.
+type UpdateRedisScheduleStateRequest struct {
+	Quota       *int   `json:"quota" validate:"omitempty,min=0"`
+	QueueNumber *int   `json:"queue_number" validate:"omitempty,min=0"`
+	Reason      string `json:"reason" validate:"required,min=10,max=500"`
+}
+
+// Response DTOs
+
+// RedisScheduleStateResponse compares the Redis counters of a schedule with the values derived from the DB
+type RedisScheduleStateResponse struct {
+	ScheduleID   int                        `json:"schedule_id"`
+	ScheduleDate string                     `json:"schedule_date"`
+	Redis        RedisCountersResponse      `json:"redis"`
+	Database     RedisExpectedStateResponse `json:"database"`
+	QuotaDrift   bool                       `json:"quota_drift"` // Quota key missing or not equal to the expected remaining quota
+	QueueDrift   bool                       `json:"queue_drift"` // Queue key missing or below the highest active queue number
+}
+
+type RedisCountersResponse struct {
+	Quota           *int   `json:"quota"`             // null when the key is missing
+	QuotaTTLSeconds *int64 `json:"quota_ttl_seconds"` // null when the key is missing or never expires
+	QueueNumber     *int   `json:"queue_number"`      // Last queue number handed out
+	QueueTTLSeconds *int64 `json:"queue_ttl_seconds"`
+	WaitlistLength  int64  `json:"waitlist_length"`
+	ReservedSlots   int64  `json:"reserved_slots"` // Time slots held (slot-mode schedules)
+}
+
+type RedisExpectedStateResponse struct {
+	TotalQuota       int  `json:"total_quota"`
+	Booked           int  `json:"booked"`
+	RemainingQuota   int  `json:"remaining_quota"`   // Expected value of the quota counter
+	MaxQueueNumber   int  `json:"max_queue_number"`  // The queue counter must not be below this
+	CurrentlyServing *int `json:"currently_serving"` // Queue number last called, null before the first call
+}
@@ -0,0 +1,18 @@
+package dto
+
+// ReminderDayResponse counts the appointment reminders of one day
+type ReminderDayResponse struct {
+	Date    string `json:"date"`
+	Sent    int64  `json:"sent"`    // Handed to the notification queue
+	Failed  int64  `json:"failed"`  // Not recorded, retried on the next scan
+	Skipped int64  `json:"skipped"` // Patients who turned reminders off
+}
+
+type ReminderReportResponse struct {
+	StartDate    string                `json:"start_date"`
+	EndDate      string                `json:"end_date"`
+	Days         []ReminderDayResponse `json:"days"`
+	TotalSent    int64                 `json:"total_sent"`
+	TotalFailed  int64                 `json:"total_failed"`
+	TotalSkipped int64                 `json:"total_skipped"`
+}
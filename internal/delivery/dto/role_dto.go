@@ -0,0 +1,60 @@
+package dto
+
+import "time"
+
+// Request DTOs
+
+type CreateRoleRequest struct {
+	RoleName    string   `json:"role_name" validate:"required,min=2,max=50"`
+	Description string   `json:"description" validate:"omitempty"`
+	Permissions []string `json:"permissions" validate:"omitempty,dive,required"`
+}
+
+// UpdateRolePermissionsRequest replaces all permissions of a role
+type UpdateRolePermissionsRequest struct {
+	Permissions []string `json:"permissions" validate:"omitempty,dive,required"`
+}
+
+// CreateStaffUserRequest creates an account with a staff role (e.g. nurse, receptionist).
+// Doctors and patients register with their profile instead.
+type CreateStaffUserRequest struct {
+	Email    string `json:"email" validate:"required,email"`
+	Password string `json:"password" validate:"required,min=6"`
+	FullName string `json:"full_name" validate:"required,min=2"`
+	RoleID   int    `json:"role_id" validate:"required"`
+}
+
+// UserFilter for query param filtering on the admin user list
+type UserFilter struct {
+	RoleID int    `json:"role_id"` // 0 = all roles
+	Locked *bool  `json:"locked"`  // Only (un)locked accounts
+	Search string `json:"search"`  // Email or full name
+}
+
+// Response DTOs
+
+// AdminUserResponse is a user in the admin user list, with its account status
+type AdminUserResponse struct {
+	UserResponse
+	IsActive     bool       `json:"is_active"`
+	Locked       bool       `json:"locked"`
+	LockedUntil  *time.Time `json:"locked_until,omitempty"`
+	LockoutCount int        `json:"lockout_count"`
+	LastLockedAt *time.Time `json:"last_locked_at,omitempty"`
+	LastLoginAt  *time.Time `json:"last_login_at,omitempty"`
+
+	MustChangePassword bool       `json:"must_change_password"`
+	PasswordChangedAt  *time.Time `json:"password_changed_at,omitempty"`
+}
+
+type PermissionResponse struct {
+	Code        string `json:"code"`
+	Description string `json:"description,omitempty"`
+}
+
+type RoleResponse struct {
+	ID          int      `json:"id"`
+	RoleName    string   `json:"role_name"`
+	Description string   `json:"description,omitempty"`
+	Permissions []string `json:"permissions"`
+}
@@ -0,0 +1,87 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Request DTOs
+
+// CreateScheduleTemplateRequest adds a weekly recurring schedule of a doctor.
+// Queue templates need total_quota, slot templates need slot_minutes (quota = number of slots).
+type CreateScheduleTemplateRequest struct {
+	DoctorID     uuid.UUID `json:"doctor_id" validate:"required"`
+	Weekday      *int      `json:"weekday" validate:"required,min=0,max=6"` // 0 = Sunday ... 6 = Saturday
+	StartTime    string    `json:"start_time" validate:"required"`          // Format: HH:MM
+	EndTime      string    `json:"end_time" validate:"required"`            // Format: HH:MM
+	TotalQuota   int       `json:"total_quota" validate:"omitempty,min=1"`
+	BookingMode  string    `json:"booking_mode" validate:"omitempty,oneof=queue slot"` // Default: queue
+	SlotMinutes  int       `json:"slot_minutes" validate:"omitempty,min=5,max=240"`
+	MinLeadHours *int      `json:"min_lead_hours" validate:"omitempty,min=0,max=720"`
+}
+
+// UpdateScheduleTemplateRequest changes a template. Already generated schedules are not changed;
+// changing the weekday or times, or re-activating, regenerates the dates it has not generated yet.
+type UpdateScheduleTemplateRequest struct {
+	Weekday           *int   `json:"weekday" validate:"omitempty,min=0,max=6"`
+	StartTime         string `json:"start_time" validate:"omitempty"` // Format: HH:MM
+	EndTime           string `json:"end_time" validate:"omitempty"`   // Format: HH:MM
+	TotalQuota        *int   `json:"total_quota" validate:"omitempty,min=1"`
+	SlotMinutes       *int   `json:"slot_minutes" validate:"omitempty,min=5,max=240"`
+	MinLeadHours      *int   `json:"min_lead_hours" validate:"omitempty,min=0,max=720"`
+	ClearMinLeadHours bool   `json:"clear_min_lead_hours" validate:"excluded_with=MinLeadHours"`
+	IsActive          *bool  `json:"is_active"`
+}
+
+// Response DTOs
+
+type ScheduleTemplateResponse struct {
+	ID             int             `json:"id"`
+	DoctorID       uuid.UUID       `json:"doctor_id"`
+	Doctor         *DoctorResponse `json:"doctor,omitempty"`
+	Weekday        int             `json:"weekday"`
+	WeekdayName    string          `json:"weekday_name"`
+	StartTime      string          `json:"start_time"`
+	EndTime        string          `json:"end_time"`
+	TotalQuota     int             `json:"total_quota,omitempty"`
+	BookingMode    string          `json:"booking_mode"`
+	SlotMinutes    int             `json:"slot_minutes,omitempty"`
+	MinLeadHours   *int            `json:"min_lead_hours,omitempty"`
+	IsActive       bool            `json:"is_active"`
+	GeneratedUntil string          `json:"generated_until,omitempty"` // Last date already generated
+	CreatedAt      time.Time       `json:"created_at"`
+	UpdatedAt      time.Time       `json:"updated_at"`
+}
+
+// ScheduleGenerationRunResponse is the report of one schedule generation run
+type ScheduleGenerationRunResponse struct {
+	ID            uuid.UUID                          `json:"id"`
+	Trigger       string                             `json:"trigger"` // nightly or manual
+	FromDate      string                             `json:"from_date"`
+	ToDate        string                             `json:"to_date"`
+	Status        string                             `json:"status"` // running, completed or failed
+	CreatedCount  int                                `json:"created_count"`
+	ExistingCount int                                `json:"existing_count"` // Already generated by an earlier run
+	SkippedCount  int                                `json:"skipped_count"`
+	FailedCount   int                                `json:"failed_count"`
+	Details       *ScheduleGenerationDetailsResponse `json:"details,omitempty"` // Single run only
+	Error         string                             `json:"error,omitempty"`
+	StartedAt     time.Time                          `json:"started_at"`
+	FinishedAt    *time.Time                         `json:"finished_at,omitempty"`
+}
+
+// ScheduleGenerationDetailsResponse lists the template dates a run created, skipped or failed
+type ScheduleGenerationDetailsResponse struct {
+	Created []ScheduleGenerationItemResponse `json:"created"`
+	Skipped []ScheduleGenerationItemResponse `json:"skipped"`
+	Failed  []ScheduleGenerationItemResponse `json:"failed"`
+}
+
+type ScheduleGenerationItemResponse struct {
+	TemplateID   int       `json:"template_id"`
+	DoctorID     uuid.UUID `json:"doctor_id"`
+	ScheduleDate string    `json:"schedule_date"`
+	ScheduleID   int       `json:"schedule_id,omitempty"`
+	Reason       string    `json:"reason,omitempty"` // holiday, conflict, or the error of a failed date
+}
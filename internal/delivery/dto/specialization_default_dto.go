@@ -0,0 +1,27 @@
+package dto
+
+import "time"
+
+// Request DTOs
+
+type UpsertSpecializationDefaultRequest struct {
+	DefaultQuota        int   `json:"default_quota" validate:"required,min=1"`
+	ConsultationMinutes int   `json:"consultation_minutes" validate:"required,min=1"`
+	Fee                 int64 `json:"fee" validate:"gte=0"`
+}
+
+// Response DTOs
+
+type SpecializationDefaultResponse struct {
+	Specialization      string    `json:"specialization"`
+	DefaultQuota        int       `json:"default_quota"`
+	ConsultationMinutes int       `json:"consultation_minutes"`
+	Fee                 int64     `json:"fee"`
+	CreatedAt           time.Time `json:"created_at"`
+	UpdatedAt           time.Time `json:"updated_at"`
+}
+
+type SpecializationDefaultListResponse struct {
+	Defaults []SpecializationDefaultResponse `json:"defaults"`
+	Total    int                             `json:"total"`
+}
@@ -0,0 +1,33 @@
+package dto
+
+import "time"
+
+// Request DTOs
+
+type CreateSpecializationRequest struct {
+	Name        string `json:"name" validate:"required,max=100"`
+	Slug        string `json:"slug" validate:"omitempty,max=100"` // Derived from the name when omitted
+	Description string `json:"description" validate:"omitempty"`
+}
+
+type UpdateSpecializationRequest struct {
+	Name        string  `json:"name" validate:"omitempty,max=100"`
+	Slug        string  `json:"slug" validate:"omitempty,max=100"` // Kept when renaming unless given
+	Description *string `json:"description" validate:"omitempty"`
+}
+
+// Response DTOs
+
+type SpecializationResponse struct {
+	ID          int       `json:"id"`
+	Name        string    `json:"name"`
+	Slug        string    `json:"slug"`
+	Description string    `json:"description,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+type SpecializationListResponse struct {
+	Specializations []SpecializationResponse `json:"specializations"`
+	Total           int                      `json:"total"`
+}
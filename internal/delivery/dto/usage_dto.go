@@ -0,0 +1,18 @@
+package dto
+
+// Response DTOs
+
+type UsageDayResponse struct {
+	Date            string `json:"date"`
+	APICalls        int64  `json:"api_calls"`
+	BookingsCreated int64  `json:"bookings_created"`
+}
+
+type UsageReportResponse struct {
+	TenantID             string             `json:"tenant_id"`
+	StartDate            string             `json:"start_date"`
+	EndDate              string             `json:"end_date"`
+	Days                 []UsageDayResponse `json:"days"`
+	TotalAPICalls        int64              `json:"total_api_calls"`
+	TotalBookingsCreated int64              `json:"total_bookings_created"`
+}
@@ -0,0 +1,37 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Request DTOs
+
+type WaitFeedbackRequest struct {
+	WaitMinutes int    `json:"wait_minutes" validate:"min=0,max=1440"`
+	Comment     string `json:"comment" validate:"omitempty,max=500"`
+}
+
+// Response DTOs
+
+type WaitFeedbackResponse struct {
+	BookingID   uuid.UUID `json:"booking_id"`
+	DoctorID    uuid.UUID `json:"doctor_id"`
+	ScheduleID  int       `json:"schedule_id"`
+	WaitMinutes int       `json:"wait_minutes"`
+	Comment     string    `json:"comment,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+type DoctorWaitTimeResponse struct {
+	DoctorID           uuid.UUID `json:"doctor_id"`
+	DoctorName         string    `json:"doctor_name"`
+	AverageWaitMinutes float64   `json:"average_wait_minutes"`
+	FeedbackCount      int64     `json:"feedback_count"`
+}
+
+type WaitTimeReportResponse struct {
+	Doctors []DoctorWaitTimeResponse `json:"doctors"`
+	Total   int                      `json:"total"`
+}
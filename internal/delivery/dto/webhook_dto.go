@@ -0,0 +1,75 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Request DTOs
+
+// CreateWebhookRequest registers an endpoint. A secret is generated when omitted.
+type CreateWebhookRequest struct {
+	URL         string   `json:"url" validate:"required,http_url,max=500"`
+	Secret      string   `json:"secret" validate:"omitempty,min=16,max=100"`
+	EventTypes  []string `json:"event_types" validate:"required,min=1,unique,dive,required"`
+	Description string   `json:"description" validate:"max=255"`
+}
+
+// UpdateWebhookRequest changes the endpoint, its event types or description; omitted fields are kept.
+// The secret cannot be read back, set a new one to rotate it.
+type UpdateWebhookRequest struct {
+	URL         *string  `json:"url" validate:"omitempty,http_url,max=500"`
+	Secret      *string  `json:"secret" validate:"omitempty,min=16,max=100"`
+	EventTypes  []string `json:"event_types" validate:"omitempty,min=1,unique,dive,required"`
+	Description *string  `json:"description" validate:"omitempty,max=255"`
+}
+
+type PauseWebhookRequest struct {
+	Reason string `json:"reason" validate:"max=255"`
+}
+
+// Response DTOs
+
+type WebhookResponse struct {
+	ID                  int        `json:"id"`
+	URL                 string     `json:"url"`
+	EventTypes          []string   `json:"event_types"`
+	Description         string     `json:"description,omitempty"`
+	Status              string     `json:"status"`               // active or paused
+	ConsecutiveFailures int        `json:"consecutive_failures"` // Failed attempts since the last success
+	PausedAt            *time.Time `json:"paused_at,omitempty"`
+	PauseReason         string     `json:"pause_reason,omitempty"`
+	CreatedBy           *uuid.UUID `json:"created_by,omitempty"`
+	CreatedAt           time.Time  `json:"created_at"`
+	UpdatedAt           time.Time  `json:"updated_at"`
+}
+
+// CreateWebhookResponse includes the signing secret, shown only once
+type CreateWebhookResponse struct {
+	*WebhookResponse
+	Secret string `json:"secret"`
+}
+
+type WebhookListResponse struct {
+	Webhooks   []WebhookResponse `json:"webhooks"`
+	EventTypes []string          `json:"event_types"` // Event types that can be subscribed to
+	Total      int               `json:"total"`
+}
+
+type WebhookDeliveryResponse struct {
+	ID             uuid.UUID              `json:"id"`
+	SubscriptionID int                    `json:"subscription_id"`
+	EventID        *int64                 `json:"event_id,omitempty"`
+	EventType      string                 `json:"event_type"`
+	Payload        map[string]interface{} `json:"payload"`
+	Status         string                 `json:"status"` // pending, succeeded or failed
+	Attempts       int                    `json:"attempts"`
+	NextAttemptAt  *time.Time             `json:"next_attempt_at,omitempty"` // Pending deliveries only
+	ResponseStatus *int                   `json:"response_status,omitempty"`
+	ResponseBody   string                 `json:"response_body,omitempty"`
+	LastError      string                 `json:"last_error,omitempty"`
+	DeliveredAt    *time.Time             `json:"delivered_at,omitempty"`
+	FailedAt       *time.Time             `json:"failed_at,omitempty"`
+	CreatedAt      time.Time              `json:"created_at"`
+}
@@ -0,0 +1,276 @@
+package http
+
+import "net/http"
+
+// contractCases are the requests of the contract tests, one per route in the order of
+// Router.Setup. Path parameters are valid IDs and bodies pass validation, so the
+// allowed request reaches the usecase.
+var contractCases = []contractCase{
+	// Token verification keys
+	{method: http.MethodGet, template: "/.well-known/jwks.json"},
+
+	// Health check
+	{method: http.MethodGet, template: "/api/v1/health"},
+
+	// Auth routes (public)
+	{method: http.MethodPost, template: "/api/v1/auth/register/patient", body: `{"email":"patient@example.com","password":"secret123","full_name":"Patient Contract","nik":"3201010101010001","date_of_birth":"1990-01-01","gender":"F"}`},
+	{method: http.MethodPost, template: "/api/v1/auth/register/doctor", body: `{"email":"doctor@example.com","password":"secret123","full_name":"Dr. Contract","str_number":"STR-001","specialization_id":1}`},
+	{method: http.MethodPost, template: "/api/v1/auth/login", body: `{"email":"patient@example.com","password":"secret123"}`},
+	{method: http.MethodPost, template: "/api/v1/auth/login/2fa", body: `{"challenge_token":"challenge","code":"123456"}`},
+	{method: http.MethodPost, template: "/api/v1/auth/login/otp/request", body: `{"phone_number":"081234567890"}`},
+	{method: http.MethodPost, template: "/api/v1/auth/login/otp/verify", body: `{"phone_number":"081234567890","code":"123456"}`},
+	{method: http.MethodPost, template: "/api/v1/auth/refresh-token", body: `{"refresh_token":"refresh"}`},
+	{method: http.MethodGet, template: "/api/v1/auth/oauth/google"},
+	{method: http.MethodGet, template: "/api/v1/auth/oauth/google/callback", path: "/api/v1/auth/oauth/google/callback?code=contract&state=contract"},
+	{method: http.MethodPost, template: "/api/v1/auth/oauth/google/register", body: `{"registration_token":"registration","nik":"3201010101010001","date_of_birth":"1990-01-01","gender":"F"}`},
+	{method: http.MethodPost, template: "/api/v1/auth/claim/request", body: `{"nik":"3201010101010001","date_of_birth":"1990-01-01"}`},
+	{method: http.MethodPost, template: "/api/v1/auth/claim/verify", body: `{"nik":"3201010101010001","code":"123456","email":"patient@example.com","password":"secret123"}`},
+
+	// Public routes
+	{method: http.MethodGet, template: "/api/v1/specializations"},
+	{method: http.MethodGet, template: "/api/v1/doctors"},
+	{method: http.MethodGet, template: "/api/v1/doctors/{id}", path: "/api/v1/doctors/00000000-0000-0000-0000-000000000001"},
+	{method: http.MethodGet, template: "/api/v1/doctors/{doctorId}/availability", path: "/api/v1/doctors/00000000-0000-0000-0000-000000000001/availability"},
+	{method: http.MethodGet, template: "/api/v1/doctors/{doctorId}/next-available", path: "/api/v1/doctors/00000000-0000-0000-0000-000000000001/next-available"},
+	{method: http.MethodGet, template: "/api/v1/doctors/{doctorId}/reviews", path: "/api/v1/doctors/00000000-0000-0000-0000-000000000001/reviews"},
+	{method: http.MethodGet, template: "/api/v1/schedules"},
+	{method: http.MethodGet, template: "/api/v1/schedules/{id}/slots", path: "/api/v1/schedules/1/slots"},
+
+	// Provider callbacks (authenticated with a shared secret)
+	{method: http.MethodPost, template: "/api/v1/webhooks/notifications/{provider}", path: "/api/v1/webhooks/notifications/twilio", body: `{"message_id":"msg-1","status":"delivered"}`},
+	{method: http.MethodPost, template: "/api/v1/webhooks/payments/{gateway}", path: "/api/v1/webhooks/payments/midtrans", body: `{"order_id":"contract","transaction_status":"settlement"}`},
+
+	// Auth routes (protected, also open to users who must change their password first)
+	{method: http.MethodPost, template: "/api/v1/auth/logout"},
+	{method: http.MethodPost, template: "/api/v1/auth/password", body: `{"current_password":"secret123","new_password":"secret456"}`},
+
+	// Auth routes (protected)
+	{method: http.MethodGet, template: "/api/v1/auth/me"},
+	{method: http.MethodGet, template: "/api/v1/auth/sessions"},
+	{method: http.MethodGet, template: "/api/v1/auth/login-history"},
+	{method: http.MethodDelete, template: "/api/v1/auth/sessions/{tokenId}", path: "/api/v1/auth/sessions/00000000-0000-0000-0000-000000000001"},
+	{method: http.MethodPost, template: "/api/v1/auth/2fa/setup"},
+	{method: http.MethodPost, template: "/api/v1/auth/2fa/enable", body: `{"code":"123456"}`},
+	{method: http.MethodPost, template: "/api/v1/auth/2fa/disable", body: `{"password":"secret123","code":"123456"}`},
+
+	// Users, roles and permissions (admin)
+	{method: http.MethodPost, template: "/api/v1/admin/users", body: `{"email":"nurse@example.com","password":"secret123","full_name":"Nurse Contract","role_id":4}`},
+	{method: http.MethodGet, template: "/api/v1/admin/users"},
+	{method: http.MethodDelete, template: "/api/v1/admin/users/{id}/2fa", path: "/api/v1/admin/users/00000000-0000-0000-0000-000000000001/2fa"},
+	{method: http.MethodGet, template: "/api/v1/admin/users/{id}/login-history", path: "/api/v1/admin/users/00000000-0000-0000-0000-000000000001/login-history"},
+	{method: http.MethodPost, template: "/api/v1/admin/users/{id}/unlock", path: "/api/v1/admin/users/00000000-0000-0000-0000-000000000001/unlock"},
+	{method: http.MethodPut, template: "/api/v1/admin/users/{id}/must-change-password", path: "/api/v1/admin/users/00000000-0000-0000-0000-000000000001/must-change-password", body: `{"required":true}`},
+	{method: http.MethodPost, template: "/api/v1/admin/users/{id}/impersonate", path: "/api/v1/admin/users/00000000-0000-0000-0000-000000000001/impersonate", body: `{"reason":"Support ticket 1234"}`},
+	{method: http.MethodDelete, template: "/api/v1/admin/sessions/remember-me"},
+	{method: http.MethodGet, template: "/api/v1/admin/roles"},
+	{method: http.MethodPost, template: "/api/v1/admin/roles", body: `{"role_name":"nurse","permissions":["booking:read"]}`},
+	{method: http.MethodPut, template: "/api/v1/admin/roles/{id}/permissions", path: "/api/v1/admin/roles/1/permissions", body: `{"permissions":["booking:read","booking:write"]}`},
+	{method: http.MethodGet, template: "/api/v1/admin/permissions"},
+
+	// API keys of machine clients (admin)
+	{method: http.MethodGet, template: "/api/v1/admin/api-keys"},
+	{method: http.MethodPost, template: "/api/v1/admin/api-keys", body: `{"name":"Kiosk","permissions":["booking:read"]}`},
+	{method: http.MethodGet, template: "/api/v1/admin/api-keys/{id}", path: "/api/v1/admin/api-keys/00000000-0000-0000-0000-000000000001"},
+	{method: http.MethodPut, template: "/api/v1/admin/api-keys/{id}", path: "/api/v1/admin/api-keys/00000000-0000-0000-0000-000000000001", body: `{"name":"Kiosk","permissions":["booking:read"]}`},
+	{method: http.MethodDelete, template: "/api/v1/admin/api-keys/{id}", path: "/api/v1/admin/api-keys/00000000-0000-0000-0000-000000000001"},
+
+	// Doctor management (admin)
+	{method: http.MethodPost, template: "/api/v1/admin/doctors", body: `{"email":"doctor@example.com","password":"secret123","full_name":"Dr. Contract","str_number":"STR-001","specialization_id":1}`},
+	{method: http.MethodGet, template: "/api/v1/admin/doctors"},
+	{method: http.MethodGet, template: "/api/v1/admin/doctors/{id}", path: "/api/v1/admin/doctors/00000000-0000-0000-0000-000000000001"},
+	{method: http.MethodPut, template: "/api/v1/admin/doctors/{id}", path: "/api/v1/admin/doctors/00000000-0000-0000-0000-000000000001", body: `{"full_name":"Dr. Contract","is_active":true}`},
+	{method: http.MethodDelete, template: "/api/v1/admin/doctors/{id}", path: "/api/v1/admin/doctors/00000000-0000-0000-0000-000000000001"},
+	{method: http.MethodPut, template: "/api/v1/admin/doctors/{id}/photo", path: "/api/v1/admin/doctors/00000000-0000-0000-0000-000000000001/photo", upload: &contractUpload{field: "photo", filename: "photo.png", content: "\x89PNG\r\n\x1a\n"}},
+	{method: http.MethodDelete, template: "/api/v1/admin/doctors/{id}/photo", path: "/api/v1/admin/doctors/00000000-0000-0000-0000-000000000001/photo"},
+
+	// Schedule management (admin)
+	{method: http.MethodPost, template: "/api/v1/admin/schedules", body: `{"doctor_id":"00000000-0000-0000-0000-000000000001","schedule_date":"2026-11-02","start_time":"09:00","end_time":"12:00","total_quota":20}`},
+	{method: http.MethodGet, template: "/api/v1/admin/schedules"},
+	{method: http.MethodPost, template: "/api/v1/admin/schedules/copy", body: `{"doctor_id":"00000000-0000-0000-0000-000000000001","source_week":"2026-11-02","target_week":"2026-11-09"}`},
+	{method: http.MethodPut, template: "/api/v1/admin/schedules/quota", body: `{"doctor_id":"00000000-0000-0000-0000-000000000001","total_quota":25}`},
+	{method: http.MethodGet, template: "/api/v1/admin/schedules/possibly-absent"},
+	{method: http.MethodGet, template: "/api/v1/admin/schedules/holiday-conflicts"},
+	{method: http.MethodGet, template: "/api/v1/admin/schedules/leave-conflicts"},
+	{method: http.MethodGet, template: "/api/v1/admin/schedules/proposals"},
+	{method: http.MethodGet, template: "/api/v1/admin/schedules/{id}", path: "/api/v1/admin/schedules/1"},
+	{method: http.MethodPut, template: "/api/v1/admin/schedules/{id}", path: "/api/v1/admin/schedules/1", body: `{"total_quota":25}`},
+	{method: http.MethodDelete, template: "/api/v1/admin/schedules/{id}", path: "/api/v1/admin/schedules/1"},
+	{method: http.MethodPost, template: "/api/v1/admin/schedules/{id}/reassign-bookings", path: "/api/v1/admin/schedules/1/reassign-bookings", body: `{"target_schedule_id":2,"cancel_overflow":true}`},
+	{method: http.MethodPut, template: "/api/v1/admin/schedules/{id}/booking-status", path: "/api/v1/admin/schedules/1/booking-status", body: `{"is_open":false}`},
+	{method: http.MethodPut, template: "/api/v1/admin/schedules/{id}/approve", path: "/api/v1/admin/schedules/1/approve"},
+	{method: http.MethodPut, template: "/api/v1/admin/schedules/{id}/reject", path: "/api/v1/admin/schedules/1/reject", body: `{"reason":"Clinic closed"}`},
+	{method: http.MethodGet, template: "/api/v1/admin/schedules/{id}/history", path: "/api/v1/admin/schedules/1/history"},
+	{method: http.MethodGet, template: "/api/v1/admin/doctors/{doctorId}/schedules", path: "/api/v1/admin/doctors/00000000-0000-0000-0000-000000000001/schedules"},
+	{method: http.MethodGet, template: "/api/v1/admin/doctors/{doctorId}/schedules/calendar.ics", path: "/api/v1/admin/doctors/00000000-0000-0000-0000-000000000001/schedules/calendar.ics"},
+
+	// Doctor leave (admin)
+	{method: http.MethodGet, template: "/api/v1/admin/doctors/{doctorId}/leaves", path: "/api/v1/admin/doctors/00000000-0000-0000-0000-000000000001/leaves"},
+	{method: http.MethodPost, template: "/api/v1/admin/doctors/{doctorId}/leaves", path: "/api/v1/admin/doctors/00000000-0000-0000-0000-000000000001/leaves", body: `{"start_date":"2026-11-02","end_date":"2026-11-06","reason":"Conference"}`},
+	{method: http.MethodDelete, template: "/api/v1/admin/doctors/{doctorId}/leaves/{id}", path: "/api/v1/admin/doctors/00000000-0000-0000-0000-000000000001/leaves/1"},
+
+	// Schedule templates and nightly generation (admin)
+	{method: http.MethodPost, template: "/api/v1/admin/schedule-templates", body: `{"doctor_id":"00000000-0000-0000-0000-000000000001","weekday":1,"start_time":"09:00","end_time":"12:00","total_quota":20}`},
+	{method: http.MethodGet, template: "/api/v1/admin/schedule-templates"},
+	{method: http.MethodPut, template: "/api/v1/admin/schedule-templates/{id}", path: "/api/v1/admin/schedule-templates/1", body: `{"total_quota":25}`},
+	{method: http.MethodDelete, template: "/api/v1/admin/schedule-templates/{id}", path: "/api/v1/admin/schedule-templates/1"},
+	{method: http.MethodPost, template: "/api/v1/admin/schedule-generation/runs"},
+	{method: http.MethodGet, template: "/api/v1/admin/schedule-generation/runs"},
+	{method: http.MethodGet, template: "/api/v1/admin/schedule-generation/runs/{id}", path: "/api/v1/admin/schedule-generation/runs/00000000-0000-0000-0000-000000000001"},
+
+	// Booking management (admin)
+	{method: http.MethodPost, template: "/api/v1/admin/bookings/import", upload: &contractUpload{field: "file", filename: "bookings.csv", content: "patient_email,schedule_id\npatient@example.com,1\n"}},
+	{method: http.MethodGet, template: "/api/v1/admin/bookings/deleted"},
+	{method: http.MethodGet, template: "/api/v1/admin/bookings/code/{bookingCode}", path: "/api/v1/admin/bookings/code/BK-260101-ABCDE"},
+	{method: http.MethodDelete, template: "/api/v1/admin/bookings/{id}", path: "/api/v1/admin/bookings/00000000-0000-0000-0000-000000000001"},
+	{method: http.MethodPost, template: "/api/v1/admin/bookings/{id}/restore", path: "/api/v1/admin/bookings/00000000-0000-0000-0000-000000000001/restore"},
+	{method: http.MethodPut, template: "/api/v1/admin/bookings/{id}/cancel", path: "/api/v1/admin/bookings/00000000-0000-0000-0000-000000000001/cancel"},
+	{method: http.MethodGet, template: "/api/v1/admin/bookings/{id}/notifications", path: "/api/v1/admin/bookings/00000000-0000-0000-0000-000000000001/notifications"},
+	{method: http.MethodGet, template: "/api/v1/admin/booking-sagas"},
+	{method: http.MethodGet, template: "/api/v1/admin/booking-sagas/{id}", path: "/api/v1/admin/booking-sagas/00000000-0000-0000-0000-000000000001"},
+
+	// Notification troubleshooting and dead letters (admin)
+	{method: http.MethodGet, template: "/api/v1/admin/notifications/dead-letters"},
+	{method: http.MethodPost, template: "/api/v1/admin/notifications/dead-letters/redrive", body: `{"channel":"sms","limit":10}`},
+	{method: http.MethodGet, template: "/api/v1/admin/notifications/{id}", path: "/api/v1/admin/notifications/00000000-0000-0000-0000-000000000001"},
+	{method: http.MethodPost, template: "/api/v1/admin/notifications/{id}/redrive", path: "/api/v1/admin/notifications/00000000-0000-0000-0000-000000000001/redrive"},
+
+	// Patient support (admin)
+	{method: http.MethodGet, template: "/api/v1/admin/patients/search"},
+	{method: http.MethodPost, template: "/api/v1/admin/patients/import", upload: &contractUpload{field: "file", filename: "roster.csv", content: "nik,full_name,date_of_birth\n3201010101010001,Patient Contract,1990-01-01\n", fields: map[string]string{"partner": "insurer"}}},
+	{method: http.MethodGet, template: "/api/v1/admin/patients/{id}/timeline", path: "/api/v1/admin/patients/00000000-0000-0000-0000-000000000001/timeline"},
+	{method: http.MethodGet, template: "/api/v1/admin/patients/{id}/notifications", path: "/api/v1/admin/patients/00000000-0000-0000-0000-000000000001/notifications"},
+	{method: http.MethodPut, template: "/api/v1/admin/patients/{id}/insurance", path: "/api/v1/admin/patients/00000000-0000-0000-0000-000000000001/insurance", body: `{"provider":"BPJS","policy_number":"0001234567890"}`},
+	{method: http.MethodDelete, template: "/api/v1/admin/patients/{id}/insurance", path: "/api/v1/admin/patients/00000000-0000-0000-0000-000000000001/insurance"},
+
+	// Patient broadcasts (admin)
+	{method: http.MethodPost, template: "/api/v1/admin/broadcasts", body: `{"title":"Clinic closed","message":"The clinic is closed on Friday","segment":{"upcoming_only":true}}`},
+	{method: http.MethodGet, template: "/api/v1/admin/broadcasts"},
+	{method: http.MethodGet, template: "/api/v1/admin/broadcasts/{id}", path: "/api/v1/admin/broadcasts/00000000-0000-0000-0000-000000000001"},
+
+	// Holidays / blackout dates (admin settings)
+	{method: http.MethodPost, template: "/api/v1/admin/settings/holidays", body: `{"date":"2026-12-25","name":"Christmas"}`},
+	{method: http.MethodGet, template: "/api/v1/admin/settings/holidays"},
+	{method: http.MethodGet, template: "/api/v1/admin/settings/holidays/{id}", path: "/api/v1/admin/settings/holidays/1"},
+	{method: http.MethodPut, template: "/api/v1/admin/settings/holidays/{id}", path: "/api/v1/admin/settings/holidays/1", body: `{"name":"Christmas Day"}`},
+	{method: http.MethodDelete, template: "/api/v1/admin/settings/holidays/{id}", path: "/api/v1/admin/settings/holidays/1"},
+
+	// Notification templates (admin settings)
+	{method: http.MethodPost, template: "/api/v1/admin/settings/notification-templates", body: `{"event_type":"booking.created","channel":"sms","body":"Booking {{booking_code}} confirmed"}`},
+	{method: http.MethodGet, template: "/api/v1/admin/settings/notification-templates"},
+	{method: http.MethodPost, template: "/api/v1/admin/settings/notification-templates/preview", body: `{"body":"Booking {{booking_code}} confirmed"}`},
+	{method: http.MethodGet, template: "/api/v1/admin/settings/notification-templates/{id}", path: "/api/v1/admin/settings/notification-templates/1"},
+	{method: http.MethodPut, template: "/api/v1/admin/settings/notification-templates/{id}", path: "/api/v1/admin/settings/notification-templates/1", body: `{"body":"Booking {{booking_code}} confirmed"}`},
+	{method: http.MethodDelete, template: "/api/v1/admin/settings/notification-templates/{id}", path: "/api/v1/admin/settings/notification-templates/1"},
+
+	// Webhook subscriptions of external systems (admin settings)
+	{method: http.MethodPost, template: "/api/v1/admin/settings/webhooks", body: `{"url":"https://example.com/hooks","event_types":["booking.created"]}`},
+	{method: http.MethodGet, template: "/api/v1/admin/settings/webhooks"},
+	{method: http.MethodGet, template: "/api/v1/admin/settings/webhooks/{id}", path: "/api/v1/admin/settings/webhooks/1"},
+	{method: http.MethodPut, template: "/api/v1/admin/settings/webhooks/{id}", path: "/api/v1/admin/settings/webhooks/1", body: `{"event_types":["booking.created","booking.cancelled"]}`},
+	{method: http.MethodDelete, template: "/api/v1/admin/settings/webhooks/{id}", path: "/api/v1/admin/settings/webhooks/1"},
+	{method: http.MethodPost, template: "/api/v1/admin/settings/webhooks/{id}/pause", path: "/api/v1/admin/settings/webhooks/1/pause", body: `{"reason":"Endpoint maintenance"}`},
+	{method: http.MethodPost, template: "/api/v1/admin/settings/webhooks/{id}/resume", path: "/api/v1/admin/settings/webhooks/1/resume"},
+	{method: http.MethodPost, template: "/api/v1/admin/settings/webhooks/{id}/test", path: "/api/v1/admin/settings/webhooks/1/test"},
+	{method: http.MethodGet, template: "/api/v1/admin/settings/webhooks/{id}/deliveries", path: "/api/v1/admin/settings/webhooks/1/deliveries"},
+
+	// Specialization taxonomy (admin settings, listed publicly at /specializations)
+	{method: http.MethodPost, template: "/api/v1/admin/settings/specializations", body: `{"name":"Cardiology"}`},
+	{method: http.MethodGet, template: "/api/v1/admin/settings/specializations"},
+	{method: http.MethodGet, template: "/api/v1/admin/settings/specializations/{id}", path: "/api/v1/admin/settings/specializations/1"},
+	{method: http.MethodPut, template: "/api/v1/admin/settings/specializations/{id}", path: "/api/v1/admin/settings/specializations/1", body: `{"name":"Cardiology"}`},
+	{method: http.MethodDelete, template: "/api/v1/admin/settings/specializations/{id}", path: "/api/v1/admin/settings/specializations/1"},
+
+	// Specialization defaults (admin settings, by specialization slug)
+	{method: http.MethodGet, template: "/api/v1/admin/settings/specialization-defaults"},
+	{method: http.MethodGet, template: "/api/v1/admin/settings/specialization-defaults/{specialization}", path: "/api/v1/admin/settings/specialization-defaults/cardiology"},
+	{method: http.MethodPut, template: "/api/v1/admin/settings/specialization-defaults/{specialization}", path: "/api/v1/admin/settings/specialization-defaults/cardiology", body: `{"default_quota":20,"consultation_minutes":15,"fee":150000}`},
+	{method: http.MethodDelete, template: "/api/v1/admin/settings/specialization-defaults/{specialization}", path: "/api/v1/admin/settings/specialization-defaults/cardiology"},
+
+	// Runtime log levels (admin settings, reset on restart / SIGHUP reload)
+	{method: http.MethodGet, template: "/api/v1/admin/settings/log-levels"},
+	{method: http.MethodPut, template: "/api/v1/admin/settings/log-levels/{package}", path: "/api/v1/admin/settings/log-levels/usecase", body: `{"level":"debug"}`},
+
+	// Redis booking counters (admin, drift inspection and manual correction)
+	{method: http.MethodGet, template: "/api/v1/admin/redis/schedules/{id}", path: "/api/v1/admin/redis/schedules/1"},
+	{method: http.MethodPut, template: "/api/v1/admin/redis/schedules/{id}", path: "/api/v1/admin/redis/schedules/1", body: `{"quota":10,"reason":"Counter drifted after outage"}`},
+
+	// Reports (admin)
+	{method: http.MethodGet, template: "/api/v1/admin/reports/wait-times"},
+	{method: http.MethodGet, template: "/api/v1/admin/reports/usage"},
+	{method: http.MethodGet, template: "/api/v1/admin/reports/reminders"},
+	{method: http.MethodGet, template: "/api/v1/admin/reports/schedule-utilization"},
+	{method: http.MethodGet, template: "/api/v1/admin/doctors/{doctorId}/stats", path: "/api/v1/admin/doctors/00000000-0000-0000-0000-000000000001/stats"},
+
+	// Finance (admin)
+	{method: http.MethodGet, template: "/api/v1/admin/reports/finance"},
+	{method: http.MethodGet, template: "/api/v1/admin/invoices"},
+	{method: http.MethodGet, template: "/api/v1/admin/invoices/{id}", path: "/api/v1/admin/invoices/00000000-0000-0000-0000-000000000001"},
+	{method: http.MethodGet, template: "/api/v1/admin/invoices/{id}/pdf", path: "/api/v1/admin/invoices/00000000-0000-0000-0000-000000000001/pdf"},
+	{method: http.MethodPost, template: "/api/v1/admin/payments/{id}/refund", path: "/api/v1/admin/payments/00000000-0000-0000-0000-000000000001/refund"},
+
+	// Audit Log
+	{method: http.MethodGet, template: "/api/v1/admin/audit-logs"},
+	{method: http.MethodGet, template: "/api/v1/admin/audit-logs/{id}", path: "/api/v1/admin/audit-logs/1"},
+
+	// Doctor routes (protected - doctor portal)
+	{method: http.MethodGet, template: "/api/v1/doctor/schedules"},
+	{method: http.MethodGet, template: "/api/v1/doctor/schedules/calendar.ics"},
+	{method: http.MethodPost, template: "/api/v1/doctor/schedules/proposals", body: `{"schedule_date":"2026-11-02","start_time":"09:00"}`},
+	{method: http.MethodPost, template: "/api/v1/doctor/schedules/{id}/check-in", path: "/api/v1/doctor/schedules/1/check-in"},
+	{method: http.MethodPost, template: "/api/v1/doctor/schedules/{id}/call-next", path: "/api/v1/doctor/schedules/1/call-next"},
+	{method: http.MethodGet, template: "/api/v1/doctor/schedules/{id}/summary", path: "/api/v1/doctor/schedules/1/summary"},
+	{method: http.MethodPut, template: "/api/v1/doctor/schedules/{id}/booking-status", path: "/api/v1/doctor/schedules/1/booking-status", body: `{"is_open":false}`},
+	{method: http.MethodGet, template: "/api/v1/doctor/stats"},
+	{method: http.MethodGet, template: "/api/v1/doctor/leaves"},
+	{method: http.MethodPost, template: "/api/v1/doctor/leaves", body: `{"start_date":"2026-11-02","end_date":"2026-11-06"}`},
+	{method: http.MethodDelete, template: "/api/v1/doctor/leaves/{id}", path: "/api/v1/doctor/leaves/1"},
+	{method: http.MethodGet, template: "/api/v1/doctor/bookings/code/{bookingCode}", path: "/api/v1/doctor/bookings/code/BK-260101-ABCDE"},
+	{method: http.MethodGet, template: "/api/v1/doctor/notification-preferences"},
+	{method: http.MethodPut, template: "/api/v1/doctor/notification-preferences", body: `{"channel":"email","agenda_digest":true}`},
+	{method: http.MethodPut, template: "/api/v1/doctor/profile", body: `{"biography":"General practitioner"}`},
+	{method: http.MethodPut, template: "/api/v1/doctor/profile/photo", upload: &contractUpload{field: "photo", filename: "photo.png", content: "\x89PNG\r\n\x1a\n"}},
+	{method: http.MethodDelete, template: "/api/v1/doctor/profile/photo"},
+
+	// Medical records of the doctor's visits (not under impersonation)
+	{method: http.MethodGet, template: "/api/v1/doctor/bookings/{id}/medical-record", path: "/api/v1/doctor/bookings/00000000-0000-0000-0000-000000000001/medical-record"},
+	{method: http.MethodPost, template: "/api/v1/doctor/bookings/{id}/medical-record", path: "/api/v1/doctor/bookings/00000000-0000-0000-0000-000000000001/medical-record", body: `{"diagnosis":"Common cold"}`},
+	{method: http.MethodPut, template: "/api/v1/doctor/bookings/{id}/medical-record", path: "/api/v1/doctor/bookings/00000000-0000-0000-0000-000000000001/medical-record", body: `{"diagnosis":"Common cold","version":1}`},
+	{method: http.MethodGet, template: "/api/v1/doctor/patients/{id}/history", path: "/api/v1/doctor/patients/00000000-0000-0000-0000-000000000001/history"},
+
+	// Patient routes (protected - patient portal)
+	{method: http.MethodGet, template: "/api/v1/patient/bookings"},
+	{method: http.MethodPost, template: "/api/v1/patient/bookings", body: `{"schedule_id":1}`},
+	{method: http.MethodPut, template: "/api/v1/patient/bookings/{id}/cancel", path: "/api/v1/patient/bookings/00000000-0000-0000-0000-000000000001/cancel"},
+	{method: http.MethodGet, template: "/api/v1/patient/bookings/{id}/payment", path: "/api/v1/patient/bookings/00000000-0000-0000-0000-000000000001/payment"},
+	{method: http.MethodGet, template: "/api/v1/patient/bookings/{id}/invoice", path: "/api/v1/patient/bookings/00000000-0000-0000-0000-000000000001/invoice"},
+	{method: http.MethodGet, template: "/api/v1/patient/bookings/{id}/invoice/pdf", path: "/api/v1/patient/bookings/00000000-0000-0000-0000-000000000001/invoice/pdf"},
+	{method: http.MethodPost, template: "/api/v1/patient/bookings/{id}/wait-feedback", path: "/api/v1/patient/bookings/00000000-0000-0000-0000-000000000001/wait-feedback", body: `{"wait_minutes":15}`},
+	{method: http.MethodPost, template: "/api/v1/patient/bookings/{id}/review", path: "/api/v1/patient/bookings/00000000-0000-0000-0000-000000000001/review", body: `{"rating":5,"comment":"Very helpful"}`},
+	{method: http.MethodGet, template: "/api/v1/patient/bookings/{id}/notifications", path: "/api/v1/patient/bookings/00000000-0000-0000-0000-000000000001/notifications"},
+	{method: http.MethodGet, template: "/api/v1/patient/notifications"},
+	{method: http.MethodGet, template: "/api/v1/patient/notifications/unread-count"},
+	{method: http.MethodPut, template: "/api/v1/patient/notifications/read-all"},
+	{method: http.MethodPut, template: "/api/v1/patient/notifications/{id}/read", path: "/api/v1/patient/notifications/00000000-0000-0000-0000-000000000001/read"},
+	{method: http.MethodDelete, template: "/api/v1/patient/notifications/{id}", path: "/api/v1/patient/notifications/00000000-0000-0000-0000-000000000001"},
+	{method: http.MethodGet, template: "/api/v1/patient/notification-preferences"},
+	{method: http.MethodPut, template: "/api/v1/patient/notification-preferences", body: `{"channel":"whatsapp","reminders":true}`},
+	{method: http.MethodGet, template: "/api/v1/patient/devices"},
+	{method: http.MethodPost, template: "/api/v1/patient/devices", body: `{"token":"device-token","platform":"android"}`},
+	{method: http.MethodDelete, template: "/api/v1/patient/devices/{id}", path: "/api/v1/patient/devices/1"},
+	{method: http.MethodPost, template: "/api/v1/patient/schedules/{id}/waitlist", path: "/api/v1/patient/schedules/1/waitlist"},
+	{method: http.MethodDelete, template: "/api/v1/patient/schedules/{id}/waitlist", path: "/api/v1/patient/schedules/1/waitlist"},
+	{method: http.MethodPut, template: "/api/v1/patient/profile", body: `{"phone_number":"081234567890"}`},
+	{method: http.MethodPut, template: "/api/v1/patient/insurance", body: `{"provider":"BPJS","policy_number":"0001234567890"}`},
+	{method: http.MethodDelete, template: "/api/v1/patient/insurance"},
+	{method: http.MethodPut, template: "/api/v1/patient/emergency-contact", body: `{"name":"Contract Relative","relationship":"spouse","phone":"081234567890"}`},
+	{method: http.MethodDelete, template: "/api/v1/patient/emergency-contact"},
+	{method: http.MethodDelete, template: "/api/v1/patient/account", body: `{"password":"secret123","reason":"Moving abroad"}`},
+
+	// Personal data export, generated in the background (not under impersonation)
+	{method: http.MethodGet, template: "/api/v1/patient/account/export"},
+	{method: http.MethodGet, template: "/api/v1/patient/account/export/download"},
+
+	// Own medical records, read only (not under impersonation)
+	{method: http.MethodGet, template: "/api/v1/patient/medical-records"},
+	{method: http.MethodGet, template: "/api/v1/patient/bookings/{id}/medical-record", path: "/api/v1/patient/bookings/00000000-0000-0000-0000-000000000001/medical-record"},
+}
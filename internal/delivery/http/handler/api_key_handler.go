@@ -0,0 +1,146 @@
+package handler
+
+import (
+	"net/http"
+
+	"go-template-clean-architecture/internal/delivery/dto"
+	"go-template-clean-architecture/internal/usecase"
+	"go-template-clean-architecture/pkg/response"
+	"go-template-clean-architecture/pkg/validator"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+type APIKeyHandler struct {
+	apiKeyUsecase usecase.APIKeyUsecase
+	validator     *validator.CustomValidator
+}
+
+func NewAPIKeyHandler(apiKeyUsecase usecase.APIKeyUsecase, validator *validator.CustomValidator) *APIKeyHandler {
+	return &APIKeyHandler{
+		apiKeyUsecase: apiKeyUsecase,
+		validator:     validator,
+	}
+}
+
+func (h *APIKeyHandler) GetAllAPIKeys(w http.ResponseWriter, r *http.Request) {
+	apiKeys, err := h.apiKeyUsecase.GetAllAPIKeys(r.Context())
+	if err != nil {
+		response.InternalServerError(w, "Failed to get API keys")
+		return
+	}
+
+	response.Success(w, http.StatusOK, "API keys retrieved successfully", apiKeys)
+}
+
+func (h *APIKeyHandler) GetAPIKey(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid API key ID", nil)
+		return
+	}
+
+	apiKey, err := h.apiKeyUsecase.GetAPIKey(r.Context(), id)
+	if err != nil {
+		switch err {
+		case usecase.ErrAPIKeyNotFound:
+			response.NotFound(w, "API key not found")
+		default:
+			response.InternalServerError(w, "Failed to get API key")
+		}
+		return
+	}
+
+	response.Success(w, http.StatusOK, "API key retrieved successfully", apiKey)
+}
+
+// CreateAPIKey issues a key for a machine client; the key is only in this response
+func (h *APIKeyHandler) CreateAPIKey(w http.ResponseWriter, r *http.Request) {
+	var req dto.CreateAPIKeyRequest
+	if err := h.validator.DecodeJSON(r.Body, &req); err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid request body", h.validator.FormatDecodeError(err))
+		return
+	}
+
+	if err := h.validator.Validate(&req); err != nil {
+		response.ValidationError(w, h.validator.FormatValidationErrors(err))
+		return
+	}
+
+	apiKey, err := h.apiKeyUsecase.CreateAPIKey(r.Context(), &req)
+	if err != nil {
+		switch err {
+		case usecase.ErrUnknownPermission:
+			response.Error(w, http.StatusBadRequest, "Unknown permission", nil)
+		case usecase.ErrAPIKeyPermissionNotAllowed, usecase.ErrAPIKeyExpiryInPast:
+			response.Error(w, http.StatusBadRequest, err.Error(), nil)
+		default:
+			response.InternalServerError(w, "Failed to create API key")
+		}
+		return
+	}
+
+	response.Success(w, http.StatusCreated, "API key created successfully, store the key now: it is not shown again", apiKey)
+}
+
+func (h *APIKeyHandler) UpdateAPIKey(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid API key ID", nil)
+		return
+	}
+
+	var req dto.UpdateAPIKeyRequest
+	if err := h.validator.DecodeJSON(r.Body, &req); err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid request body", h.validator.FormatDecodeError(err))
+		return
+	}
+
+	if err := h.validator.Validate(&req); err != nil {
+		response.ValidationError(w, h.validator.FormatValidationErrors(err))
+		return
+	}
+
+	apiKey, err := h.apiKeyUsecase.UpdateAPIKey(r.Context(), id, &req)
+	if err != nil {
+		switch err {
+		case usecase.ErrAPIKeyNotFound:
+			response.NotFound(w, "API key not found")
+		case usecase.ErrAPIKeyRevoked:
+			response.Error(w, http.StatusConflict, "API key is revoked", nil)
+		case usecase.ErrUnknownPermission:
+			response.Error(w, http.StatusBadRequest, "Unknown permission", nil)
+		case usecase.ErrAPIKeyPermissionNotAllowed, usecase.ErrAPIKeyExpiryInPast:
+			response.Error(w, http.StatusBadRequest, err.Error(), nil)
+		default:
+			response.InternalServerError(w, "Failed to update API key")
+		}
+		return
+	}
+
+	response.Success(w, http.StatusOK, "API key updated successfully", apiKey)
+}
+
+// RevokeAPIKey disables a key immediately and for good
+func (h *APIKeyHandler) RevokeAPIKey(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid API key ID", nil)
+		return
+	}
+
+	if err := h.apiKeyUsecase.RevokeAPIKey(r.Context(), id); err != nil {
+		switch err {
+		case usecase.ErrAPIKeyNotFound:
+			response.NotFound(w, "API key not found")
+		case usecase.ErrAPIKeyRevoked:
+			response.Error(w, http.StatusConflict, "API key is already revoked", nil)
+		default:
+			response.InternalServerError(w, "Failed to revoke API key")
+		}
+		return
+	}
+
+	response.Success(w, http.StatusOK, "API key revoked successfully", nil)
+}
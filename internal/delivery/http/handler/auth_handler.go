@@ -12,6 +12,9 @@ import (
 	"go-template-clean-architecture/pkg/jwt"
 	"go-template-clean-architecture/pkg/response"
 	"go-template-clean-architecture/pkg/validator"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
 )
 
 type AuthHandler struct {
@@ -41,8 +44,8 @@ func NewAuthHandler(authUsecase usecase.AuthUsecase, validator *validator.Custom
 // @Router /auth/register/patient [post]
 func (h *AuthHandler) RegisterPatient(w http.ResponseWriter, r *http.Request) {
 	var req dto.RegisterPatientRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		response.Error(w, http.StatusBadRequest, "Invalid request body", nil)
+	if err := h.validator.DecodeJSON(r.Body, &req); err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid request body", h.validator.FormatDecodeError(err))
 		return
 	}
 
@@ -104,8 +107,8 @@ func (h *AuthHandler) RegisterPatient(w http.ResponseWriter, r *http.Request) {
 // @Router /auth/register/doctor [post]
 func (h *AuthHandler) RegisterDoctor(w http.ResponseWriter, r *http.Request) {
 	var req dto.RegisterDoctorRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		response.Error(w, http.StatusBadRequest, "Invalid request body", nil)
+	if err := h.validator.DecodeJSON(r.Body, &req); err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid request body", h.validator.FormatDecodeError(err))
 		return
 	}
 
@@ -121,9 +124,9 @@ func (h *AuthHandler) RegisterDoctor(w http.ResponseWriter, r *http.Request) {
 		FullName: req.FullName,
 		RoleID:   entity.RoleIDDoctor,
 		DoctorProfile: &entity.DoctorProfile{
-			STRNumber:      req.STRNumber,
-			Specialization: req.Specialization,
-			Biography:      req.Biography,
+			STRNumber:        req.STRNumber,
+			SpecializationID: req.SpecializationID,
+			Biography:        req.Biography,
 		},
 	}
 
@@ -134,6 +137,8 @@ func (h *AuthHandler) RegisterDoctor(w http.ResponseWriter, r *http.Request) {
 			response.Error(w, http.StatusConflict, "Email already exists", nil)
 		case usecase.ErrSTRAlreadyExists:
 			response.Error(w, http.StatusConflict, "STR number already exists", nil)
+		case usecase.ErrSpecializationNotFound:
+			response.Error(w, http.StatusBadRequest, "Specialization not found", nil)
 		case usecase.ErrRoleNotFound:
 			response.InternalServerError(w, "Doctor role not found in system")
 		default:
@@ -147,7 +152,7 @@ func (h *AuthHandler) RegisterDoctor(w http.ResponseWriter, r *http.Request) {
 
 // Login handles user login
 // @Summary Login user
-// @Description Login with email and password
+// @Description Login with email and password. Accounts with two-factor authentication get a challenge token to complete at /auth/login/2fa
 // @Tags Auth
 // @Accept json
 // @Produce json
@@ -159,8 +164,8 @@ func (h *AuthHandler) RegisterDoctor(w http.ResponseWriter, r *http.Request) {
 // @Router /auth/login [post]
 func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
 	var req dto.LoginRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		response.Error(w, http.StatusBadRequest, "Invalid request body", nil)
+	if err := h.validator.DecodeJSON(r.Body, &req); err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid request body", h.validator.FormatDecodeError(err))
 		return
 	}
 
@@ -175,16 +180,190 @@ func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
 		case usecase.ErrInvalidCredentials:
 			response.Error(w, http.StatusUnauthorized, "Invalid email or password", nil)
 		case usecase.ErrAccountLocked:
-			response.Error(w, http.StatusTooManyRequests, "Too many login attempts, try again in 3 minutes", nil)
+			response.Error(w, http.StatusTooManyRequests, "Too many login attempts, account temporarily locked", nil)
 		default:
 			response.InternalServerError(w, "Failed to login")
 		}
 		return
 	}
 
+	if tokens.TwoFactorRequired {
+		response.Success(w, http.StatusOK, "Two-factor code required", tokens)
+		return
+	}
+
 	response.Success(w, http.StatusOK, "Login successful", tokens)
 }
 
+// LoginTwoFactor handles the second login step
+// @Summary Complete login with a two-factor code
+// @Description Exchange the challenge token of /auth/login and an authenticator or backup code for tokens
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param request body dto.LoginTwoFactorRequest true "Login Two-Factor Request"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 429 {object} response.Response
+// @Router /auth/login/2fa [post]
+func (h *AuthHandler) LoginTwoFactor(w http.ResponseWriter, r *http.Request) {
+	var req dto.LoginTwoFactorRequest
+	if err := h.validator.DecodeJSON(r.Body, &req); err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid request body", h.validator.FormatDecodeError(err))
+		return
+	}
+
+	if err := h.validator.Validate(&req); err != nil {
+		response.ValidationError(w, h.validator.FormatValidationErrors(err))
+		return
+	}
+
+	tokens, err := h.authUsecase.LoginTwoFactor(r.Context(), &req)
+	if err != nil {
+		switch err {
+		case usecase.ErrTwoFactorChallengeInvalid, usecase.ErrInvalidTwoFactorCode:
+			response.Error(w, http.StatusUnauthorized, err.Error(), nil)
+		case usecase.ErrTwoFactorTooManyAttempts:
+			response.Error(w, http.StatusTooManyRequests, err.Error(), nil)
+		case usecase.ErrAccountLocked:
+			response.Error(w, http.StatusTooManyRequests, "Too many login attempts, account temporarily locked", nil)
+		default:
+			response.InternalServerError(w, "Failed to login")
+		}
+		return
+	}
+
+	response.Success(w, http.StatusOK, "Login successful", tokens)
+}
+
+// GoogleLogin starts a Google sign-in
+// @Summary Login with Google
+// @Description Redirect to the Google consent page. Google redirects back to /auth/oauth/google/callback
+// @Tags Auth
+// @Success 302
+// @Failure 503 {object} response.Response
+// @Router /auth/oauth/google [get]
+func (h *AuthHandler) GoogleLogin(w http.ResponseWriter, r *http.Request) {
+	authURL, err := h.authUsecase.GoogleAuthURL(r.Context())
+	if err != nil {
+		switch err {
+		case usecase.ErrOAuthNotConfigured:
+			response.Error(w, http.StatusServiceUnavailable, "Google login is not available", nil)
+		default:
+			response.InternalServerError(w, "Failed to start Google login")
+		}
+		return
+	}
+
+	http.Redirect(w, r, authURL, http.StatusFound)
+}
+
+// GoogleCallback handles the redirect back from Google
+// @Summary Google login callback
+// @Description Map the Google account to a patient and return tokens (or a 2FA challenge).
+// @Description Accounts not registered yet get a registration token for /auth/oauth/google/register
+// @Tags Auth
+// @Produce json
+// @Param code query string true "Authorization code"
+// @Param state query string true "Login state"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 409 {object} response.Response
+// @Router /auth/oauth/google/callback [get]
+func (h *AuthHandler) GoogleCallback(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	if query.Get("error") != "" {
+		response.Error(w, http.StatusUnauthorized, "Google login was cancelled", nil)
+		return
+	}
+	code, state := query.Get("code"), query.Get("state")
+	if code == "" || state == "" {
+		response.Error(w, http.StatusBadRequest, "Missing code or state", nil)
+		return
+	}
+
+	result, err := h.authUsecase.GoogleCallback(r.Context(), code, state)
+	if err != nil {
+		switch err {
+		case usecase.ErrOAuthNotConfigured:
+			response.Error(w, http.StatusServiceUnavailable, "Google login is not available", nil)
+		case usecase.ErrOAuthStateInvalid:
+			response.Error(w, http.StatusBadRequest, err.Error(), nil)
+		case usecase.ErrOAuthFailed, usecase.ErrOAuthEmailNotVerified:
+			response.Error(w, http.StatusUnauthorized, err.Error(), nil)
+		case usecase.ErrOAuthNotPatient:
+			response.Forbidden(w, err.Error())
+		case usecase.ErrOAuthAccountLinked:
+			response.Error(w, http.StatusConflict, err.Error(), nil)
+		case usecase.ErrAccountLocked:
+			response.Error(w, http.StatusTooManyRequests, err.Error(), nil)
+		default:
+			response.InternalServerError(w, "Failed to login with Google")
+		}
+		return
+	}
+
+	switch {
+	case result.RegistrationRequired:
+		response.Success(w, http.StatusOK, "Registration required", result)
+	case result.TwoFactorRequired:
+		response.Success(w, http.StatusOK, "Two-factor code required", result)
+	default:
+		response.Success(w, http.StatusOK, "Login successful", result)
+	}
+}
+
+// GoogleRegister handles creating the patient account of a Google identity
+// @Summary Register a patient with Google
+// @Description Complete the patient profile of a Google identity returned by the callback and login
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param request body dto.GoogleRegisterRequest true "Google Register Request"
+// @Success 201 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 409 {object} response.Response
+// @Router /auth/oauth/google/register [post]
+func (h *AuthHandler) GoogleRegister(w http.ResponseWriter, r *http.Request) {
+	var req dto.GoogleRegisterRequest
+	if err := h.validator.DecodeJSON(r.Body, &req); err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid request body", h.validator.FormatDecodeError(err))
+		return
+	}
+
+	if err := h.validator.Validate(&req); err != nil {
+		response.ValidationError(w, h.validator.FormatValidationErrors(err))
+		return
+	}
+
+	tokens, err := h.authUsecase.GoogleRegister(r.Context(), &req)
+	if err != nil {
+		switch err {
+		case usecase.ErrOAuthRegistrationInvalid:
+			response.Error(w, http.StatusUnauthorized, err.Error(), nil)
+		case usecase.ErrInvalidDateFormat:
+			response.Error(w, http.StatusBadRequest, "Invalid date format, use YYYY-MM-DD", nil)
+		case usecase.ErrEmailAlreadyExists:
+			response.Error(w, http.StatusConflict, "Email already exists", nil)
+		case usecase.ErrNIKAlreadyExists:
+			response.Error(w, http.StatusConflict, "NIK already exists", nil)
+		case usecase.ErrOAuthAccountLinked:
+			response.Error(w, http.StatusConflict, err.Error(), nil)
+		case usecase.ErrRoleNotFound:
+			response.InternalServerError(w, "Patient role not found in system")
+		default:
+			response.InternalServerError(w, "Failed to register patient")
+		}
+		return
+	}
+
+	response.Success(w, http.StatusCreated, "Patient registered successfully", tokens)
+}
+
 // Logout handles user logout
 // @Summary Logout user
 // @Description Logout and revoke tokens
@@ -223,6 +402,128 @@ func (h *AuthHandler) Logout(w http.ResponseWriter, r *http.Request) {
 	response.Success(w, http.StatusOK, "Logout successful", nil)
 }
 
+// ChangePassword handles the logged-in user replacing their password
+// @Summary Change password
+// @Description Replace the password, also when an admin required a change. Every session is signed out and a new token pair is returned
+// @Tags Auth
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body dto.ChangePasswordRequest true "Change Password Request"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Router /auth/password [post]
+func (h *AuthHandler) ChangePassword(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		response.Unauthorized(w, "Invalid token")
+		return
+	}
+
+	var req dto.ChangePasswordRequest
+	if err := h.validator.DecodeJSON(r.Body, &req); err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid request body", h.validator.FormatDecodeError(err))
+		return
+	}
+
+	if err := h.validator.Validate(&req); err != nil {
+		response.ValidationError(w, h.validator.FormatValidationErrors(err))
+		return
+	}
+
+	tokens, err := h.authUsecase.ChangePassword(r.Context(), userID, &req)
+	if err != nil {
+		switch err {
+		case usecase.ErrUserNotFound:
+			response.NotFound(w, "User not found")
+		case usecase.ErrInvalidCredentials:
+			response.Error(w, http.StatusUnauthorized, "Invalid current password", nil)
+		default:
+			response.InternalServerError(w, "Failed to change password")
+		}
+		return
+	}
+
+	response.Success(w, http.StatusOK, "Password changed successfully", tokens)
+}
+
+// RequestLoginOTP handles sending a login code to a patient's phone
+// @Summary Request a phone login code
+// @Description Send a one-time login code by SMS or WhatsApp to the phone of a patient account. The response is the same whether or not the number belongs to an account
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param request body dto.RequestLoginOTPRequest true "Request Login OTP Request"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Router /auth/login/otp/request [post]
+func (h *AuthHandler) RequestLoginOTP(w http.ResponseWriter, r *http.Request) {
+	var req dto.RequestLoginOTPRequest
+	if err := h.validator.DecodeJSON(r.Body, &req); err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid request body", h.validator.FormatDecodeError(err))
+		return
+	}
+
+	if err := h.validator.Validate(&req); err != nil {
+		response.ValidationError(w, h.validator.FormatValidationErrors(err))
+		return
+	}
+
+	if err := h.authUsecase.RequestLoginOTP(r.Context(), &req); err != nil {
+		response.InternalServerError(w, "Failed to request login code")
+		return
+	}
+
+	// Same response whether or not the number belongs to an account
+	response.Success(w, http.StatusOK, "If the number belongs to a patient account, a login code was sent", nil)
+}
+
+// VerifyLoginOTP handles login with a phone code
+// @Summary Login with a phone code
+// @Description Exchange the phone number and the code of /auth/login/otp/request for tokens. Accounts with two-factor authentication get a challenge token to complete at /auth/login/2fa
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param request body dto.VerifyLoginOTPRequest true "Verify Login OTP Request"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 429 {object} response.Response
+// @Router /auth/login/otp/verify [post]
+func (h *AuthHandler) VerifyLoginOTP(w http.ResponseWriter, r *http.Request) {
+	var req dto.VerifyLoginOTPRequest
+	if err := h.validator.DecodeJSON(r.Body, &req); err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid request body", h.validator.FormatDecodeError(err))
+		return
+	}
+
+	if err := h.validator.Validate(&req); err != nil {
+		response.ValidationError(w, h.validator.FormatValidationErrors(err))
+		return
+	}
+
+	tokens, err := h.authUsecase.VerifyLoginOTP(r.Context(), &req)
+	if err != nil {
+		switch err {
+		case usecase.ErrInvalidLoginOTP:
+			response.Error(w, http.StatusUnauthorized, err.Error(), nil)
+		case usecase.ErrLoginOTPTooManyAttempts, usecase.ErrAccountLocked:
+			response.Error(w, http.StatusTooManyRequests, err.Error(), nil)
+		default:
+			response.InternalServerError(w, "Failed to login")
+		}
+		return
+	}
+
+	if tokens.TwoFactorRequired {
+		response.Success(w, http.StatusOK, "Two-factor code required", tokens)
+		return
+	}
+
+	response.Success(w, http.StatusOK, "Login successful", tokens)
+}
+
 // RefreshToken handles token refresh
 // @Summary Refresh access token
 // @Description Get new access token using refresh token
@@ -233,11 +534,12 @@ func (h *AuthHandler) Logout(w http.ResponseWriter, r *http.Request) {
 // @Success 200 {object} response.Response
 // @Failure 400 {object} response.Response
 // @Failure 401 {object} response.Response
+// @Failure 429 {object} response.Response
 // @Router /auth/refresh-token [post]
 func (h *AuthHandler) RefreshToken(w http.ResponseWriter, r *http.Request) {
 	var req dto.RefreshTokenRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		response.Error(w, http.StatusBadRequest, "Invalid request body", nil)
+	if err := h.validator.DecodeJSON(r.Body, &req); err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid request body", h.validator.FormatDecodeError(err))
 		return
 	}
 
@@ -249,8 +551,10 @@ func (h *AuthHandler) RefreshToken(w http.ResponseWriter, r *http.Request) {
 	tokens, err := h.authUsecase.RefreshToken(r.Context(), &req)
 	if err != nil {
 		switch err {
-		case usecase.ErrInvalidToken, usecase.ErrTokenRevoked:
+		case usecase.ErrInvalidToken, usecase.ErrTokenRevoked, usecase.ErrTokenReused:
 			response.Error(w, http.StatusUnauthorized, err.Error(), nil)
+		case usecase.ErrAccountLocked:
+			response.Error(w, http.StatusTooManyRequests, err.Error(), nil)
 		default:
 			response.InternalServerError(w, "Failed to refresh token")
 		}
@@ -289,3 +593,481 @@ func (h *AuthHandler) GetCurrentUser(w http.ResponseWriter, r *http.Request) {
 
 	response.Success(w, http.StatusOK, "User retrieved successfully", user)
 }
+
+// JWKS serves the public keys tokens are signed with, for services verifying them
+// @Summary JSON Web Key Set
+// @Description Public keys of the RS256 signing keys (RFC 7517), matched to tokens by kid. Empty with HS256
+// @Tags Auth
+// @Produce json
+// @Success 200 {object} jwt.JWKS
+// @Router /.well-known/jwks.json [get]
+func (h *AuthHandler) JWKS(w http.ResponseWriter, r *http.Request) {
+	// Verifiers may cache the keys; rotation publishes a new key before it signs (see config.JWTConfig)
+	w.Header().Set("Cache-Control", "public, max-age=300")
+	response.JSON(w, http.StatusOK, h.jwtService.JWKS())
+}
+
+// GetSessions handles listing the signed-in devices
+// @Summary List active sessions
+// @Description List the devices signed in to the account (device, IP, user agent, sign-in time). The session of this request is marked current
+// @Tags Auth
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Router /auth/sessions [get]
+func (h *AuthHandler) GetSessions(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		response.Unauthorized(w, "Invalid token")
+		return
+	}
+	tokenID, _ := middleware.GetTokenIDFromContext(r.Context())
+
+	sessions, err := h.authUsecase.GetSessions(r.Context(), userID, tokenID)
+	if err != nil {
+		response.InternalServerError(w, "Failed to get sessions")
+		return
+	}
+
+	response.Success(w, http.StatusOK, "Sessions retrieved successfully", sessions)
+}
+
+// GetLoginHistory handles listing the login attempts of the user
+// @Summary List login history
+// @Description List the login attempts of the account, newest first: method, success or failure reason, IP and device. Query params: page (default 1), limit (default 20, max 100)
+// @Tags Auth
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Router /auth/login-history [get]
+func (h *AuthHandler) GetLoginHistory(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		response.Unauthorized(w, "Invalid token")
+		return
+	}
+
+	h.writeLoginHistory(w, r, userID)
+}
+
+// GetUserLoginHistory handles an admin listing the login attempts of a user
+// @Summary List login history of a user
+// @Description List the login attempts of a user for security reviews, newest first (admin only). Query params: page (default 1), limit (default 20, max 100)
+// @Tags Auth
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "User ID"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Router /admin/users/{id}/login-history [get]
+func (h *AuthHandler) GetUserLoginHistory(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid user ID", nil)
+		return
+	}
+
+	h.writeLoginHistory(w, r, userID)
+}
+
+// writeLoginHistory writes the requested page of the login history of the user
+func (h *AuthHandler) writeLoginHistory(w http.ResponseWriter, r *http.Request, userID uuid.UUID) {
+	page, limit, ok := parsePagination(r)
+	if !ok {
+		response.Error(w, http.StatusBadRequest, "Invalid pagination, page and limit must be positive integers", nil)
+		return
+	}
+
+	history, total, err := h.authUsecase.GetLoginHistory(r.Context(), userID, page, limit)
+	if err != nil {
+		switch err {
+		case usecase.ErrUserNotFound:
+			response.NotFound(w, "User not found")
+		default:
+			response.InternalServerError(w, "Failed to get login history")
+		}
+		return
+	}
+
+	response.SuccessWithMeta(w, http.StatusOK, "Login history retrieved successfully", history, newPaginationMeta(page, limit, total))
+}
+
+// RevokeSession handles signing out one device
+// @Summary Revoke a session
+// @Description Sign out one device: its refresh and access tokens stop working
+// @Tags Auth
+// @Security BearerAuth
+// @Produce json
+// @Param tokenId path string true "Session token ID"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Router /auth/sessions/{tokenId} [delete]
+func (h *AuthHandler) RevokeSession(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		response.Unauthorized(w, "Invalid token")
+		return
+	}
+
+	tokenID, err := uuid.Parse(mux.Vars(r)["tokenId"])
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid session token ID", nil)
+		return
+	}
+
+	if err := h.authUsecase.RevokeSession(r.Context(), userID, tokenID.String()); err != nil {
+		switch err {
+		case usecase.ErrSessionNotFound:
+			response.NotFound(w, "Session not found")
+		default:
+			response.InternalServerError(w, "Failed to revoke session")
+		}
+		return
+	}
+
+	response.Success(w, http.StatusOK, "Session revoked successfully", nil)
+}
+
+// SetupTwoFactor handles generating a two-factor secret
+// @Summary Set up two-factor authentication
+// @Description Generate a secret and otpauth:// provisioning URI (for a QR code). Confirm with /auth/2fa/enable
+// @Tags Auth
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 409 {object} response.Response
+// @Router /auth/2fa/setup [post]
+func (h *AuthHandler) SetupTwoFactor(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		response.Unauthorized(w, "Invalid token")
+		return
+	}
+
+	setup, err := h.authUsecase.SetupTwoFactor(r.Context(), userID)
+	if err != nil {
+		switch err {
+		case usecase.ErrUserNotFound:
+			response.NotFound(w, "User not found")
+		case usecase.ErrTwoFactorAlreadyEnabled:
+			response.Error(w, http.StatusConflict, err.Error(), nil)
+		default:
+			response.InternalServerError(w, "Failed to set up two-factor authentication")
+		}
+		return
+	}
+
+	response.Success(w, http.StatusOK, "Two-factor secret generated successfully", setup)
+}
+
+// EnableTwoFactor handles confirming the two-factor enrollment
+// @Summary Enable two-factor authentication
+// @Description Confirm the secret of /auth/2fa/setup with a code; returns backup codes, shown only once
+// @Tags Auth
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body dto.EnableTwoFactorRequest true "Enable Two-Factor Request"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 409 {object} response.Response
+// @Router /auth/2fa/enable [post]
+func (h *AuthHandler) EnableTwoFactor(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		response.Unauthorized(w, "Invalid token")
+		return
+	}
+
+	var req dto.EnableTwoFactorRequest
+	if err := h.validator.DecodeJSON(r.Body, &req); err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid request body", h.validator.FormatDecodeError(err))
+		return
+	}
+
+	if err := h.validator.Validate(&req); err != nil {
+		response.ValidationError(w, h.validator.FormatValidationErrors(err))
+		return
+	}
+
+	backupCodes, err := h.authUsecase.EnableTwoFactor(r.Context(), userID, &req)
+	if err != nil {
+		switch err {
+		case usecase.ErrUserNotFound:
+			response.NotFound(w, "User not found")
+		case usecase.ErrTwoFactorAlreadyEnabled:
+			response.Error(w, http.StatusConflict, err.Error(), nil)
+		case usecase.ErrTwoFactorNotSetUp, usecase.ErrInvalidTwoFactorCode:
+			response.Error(w, http.StatusBadRequest, err.Error(), nil)
+		default:
+			response.InternalServerError(w, "Failed to enable two-factor authentication")
+		}
+		return
+	}
+
+	response.Success(w, http.StatusOK, "Two-factor authentication enabled successfully", backupCodes)
+}
+
+// DisableTwoFactor handles turning two-factor authentication off
+// @Summary Disable two-factor authentication
+// @Description Turn two-factor authentication off with the password and an authenticator or backup code
+// @Tags Auth
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body dto.DisableTwoFactorRequest true "Disable Two-Factor Request"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Router /auth/2fa/disable [post]
+func (h *AuthHandler) DisableTwoFactor(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		response.Unauthorized(w, "Invalid token")
+		return
+	}
+
+	var req dto.DisableTwoFactorRequest
+	if err := h.validator.DecodeJSON(r.Body, &req); err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid request body", h.validator.FormatDecodeError(err))
+		return
+	}
+
+	if err := h.validator.Validate(&req); err != nil {
+		response.ValidationError(w, h.validator.FormatValidationErrors(err))
+		return
+	}
+
+	if err := h.authUsecase.DisableTwoFactor(r.Context(), userID, &req); err != nil {
+		switch err {
+		case usecase.ErrUserNotFound:
+			response.NotFound(w, "User not found")
+		case usecase.ErrTwoFactorNotEnabled, usecase.ErrInvalidTwoFactorCode:
+			response.Error(w, http.StatusBadRequest, err.Error(), nil)
+		case usecase.ErrInvalidCredentials:
+			response.Error(w, http.StatusUnauthorized, "Invalid password", nil)
+		default:
+			response.InternalServerError(w, "Failed to disable two-factor authentication")
+		}
+		return
+	}
+
+	response.Success(w, http.StatusOK, "Two-factor authentication disabled successfully", nil)
+}
+
+// ResetTwoFactor handles an admin removing the two-factor authentication of a user
+// @Summary Reset two-factor authentication of a user
+// @Description Remove the secret and backup codes of a user who lost access to their authenticator (admin only)
+// @Tags Auth
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "User ID"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Router /admin/users/{id}/2fa [delete]
+func (h *AuthHandler) ResetTwoFactor(w http.ResponseWriter, r *http.Request) {
+	adminID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		response.Unauthorized(w, "Invalid token")
+		return
+	}
+
+	userID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid user ID", nil)
+		return
+	}
+
+	if err := h.authUsecase.ResetTwoFactor(r.Context(), adminID, userID); err != nil {
+		switch err {
+		case usecase.ErrUserNotFound:
+			response.NotFound(w, "User not found")
+		case usecase.ErrTwoFactorNotEnabled:
+			response.Error(w, http.StatusBadRequest, err.Error(), nil)
+		default:
+			response.InternalServerError(w, "Failed to reset two-factor authentication")
+		}
+		return
+	}
+
+	response.Success(w, http.StatusOK, "Two-factor authentication reset successfully", nil)
+}
+
+// UnlockUser handles an admin lifting the login lockout of a user
+// @Summary Unlock a user account
+// @Description Lift the login lockout of a user and reset the escalating lockout count (admin only)
+// @Tags Auth
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "User ID"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Router /admin/users/{id}/unlock [post]
+func (h *AuthHandler) UnlockUser(w http.ResponseWriter, r *http.Request) {
+	adminID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		response.Unauthorized(w, "Invalid token")
+		return
+	}
+
+	userID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid user ID", nil)
+		return
+	}
+
+	if err := h.authUsecase.UnlockUser(r.Context(), adminID, userID); err != nil {
+		switch err {
+		case usecase.ErrUserNotFound:
+			response.NotFound(w, "User not found")
+		case usecase.ErrAccountNotLocked:
+			response.Error(w, http.StatusBadRequest, err.Error(), nil)
+		default:
+			response.InternalServerError(w, "Failed to unlock account")
+		}
+		return
+	}
+
+	response.Success(w, http.StatusOK, "Account unlocked successfully", nil)
+}
+
+// RevokeRememberMeSessions handles an admin signing out all remember-me sessions
+// @Summary Revoke all remember-me sessions
+// @Description Sign out every long-lived "remember me" session of all users; regular sessions stay signed in (admin only)
+// @Tags Auth
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Router /admin/sessions/remember-me [delete]
+func (h *AuthHandler) RevokeRememberMeSessions(w http.ResponseWriter, r *http.Request) {
+	adminID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		response.Unauthorized(w, "Invalid token")
+		return
+	}
+
+	result, err := h.authUsecase.RevokeRememberMeSessions(r.Context(), adminID)
+	if err != nil {
+		response.InternalServerError(w, "Failed to revoke remember-me sessions")
+		return
+	}
+
+	response.Success(w, http.StatusOK, "Remember-me sessions revoked successfully", result)
+}
+
+// SetMustChangePassword handles an admin requiring a user to change the password
+// @Summary Require a password change
+// @Description Require (or stop requiring) the user to change the password; until then the user's tokens only allow changing it (admin only)
+// @Tags Auth
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "User ID"
+// @Param request body dto.SetMustChangePasswordRequest true "Set Must Change Password Request"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Router /admin/users/{id}/must-change-password [put]
+func (h *AuthHandler) SetMustChangePassword(w http.ResponseWriter, r *http.Request) {
+	adminID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		response.Unauthorized(w, "Invalid token")
+		return
+	}
+
+	userID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid user ID", nil)
+		return
+	}
+
+	var req dto.SetMustChangePasswordRequest
+	if err := h.validator.DecodeJSON(r.Body, &req); err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid request body", h.validator.FormatDecodeError(err))
+		return
+	}
+
+	if err := h.validator.Validate(&req); err != nil {
+		response.ValidationError(w, h.validator.FormatValidationErrors(err))
+		return
+	}
+
+	if err := h.authUsecase.SetMustChangePassword(r.Context(), adminID, userID, *req.Required); err != nil {
+		switch err {
+		case usecase.ErrUserNotFound:
+			response.NotFound(w, "User not found")
+		default:
+			response.InternalServerError(w, "Failed to update required password change")
+		}
+		return
+	}
+
+	response.Success(w, http.StatusOK, "Required password change updated successfully", nil)
+}
+
+// Impersonate handles an admin starting to act as a user for support
+// @Summary Impersonate a user
+// @Description Issue a short-lived access token acting as the user, without refresh token. Responses to its requests carry X-Impersonated-By and every request is audited with both identities (admin only)
+// @Tags Auth
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "User ID"
+// @Param request body dto.ImpersonateRequest true "Impersonate Request"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Router /admin/users/{id}/impersonate [post]
+func (h *AuthHandler) Impersonate(w http.ResponseWriter, r *http.Request) {
+	adminID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		response.Unauthorized(w, "Invalid token")
+		return
+	}
+
+	userID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid user ID", nil)
+		return
+	}
+
+	var req dto.ImpersonateRequest
+	if err := h.validator.DecodeJSON(r.Body, &req); err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid request body", h.validator.FormatDecodeError(err))
+		return
+	}
+
+	if err := h.validator.Validate(&req); err != nil {
+		response.ValidationError(w, h.validator.FormatValidationErrors(err))
+		return
+	}
+
+	result, err := h.authUsecase.Impersonate(r.Context(), adminID, userID, &req)
+	if err != nil {
+		switch err {
+		case usecase.ErrUserNotFound:
+			response.NotFound(w, "User not found")
+		case usecase.ErrImpersonateSelf:
+			response.Error(w, http.StatusBadRequest, err.Error(), nil)
+		case usecase.ErrImpersonateNotAllowed:
+			response.Forbidden(w, err.Error())
+		default:
+			response.InternalServerError(w, "Failed to impersonate user")
+		}
+		return
+	}
+
+	response.Success(w, http.StatusOK, "Impersonation started", result)
+}
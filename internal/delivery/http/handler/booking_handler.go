@@ -1,8 +1,8 @@
 package handler
 
 import (
-	"encoding/json"
 	"net/http"
+	"strconv"
 
 	"go-template-clean-architecture/internal/delivery/dto"
 	"go-template-clean-architecture/internal/service"
@@ -38,8 +38,8 @@ func (h *BookingHandler) GetMyBookings(w http.ResponseWriter, r *http.Request) {
 
 func (h *BookingHandler) CreateBooking(w http.ResponseWriter, r *http.Request) {
 	var req dto.CreateBookingRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		response.Error(w, http.StatusBadRequest, "Invalid request body", nil)
+	if err := h.validator.DecodeJSON(r.Body, &req); err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid request body", h.validator.FormatDecodeError(err))
 		return
 	}
 
@@ -55,10 +55,38 @@ func (h *BookingHandler) CreateBooking(w http.ResponseWriter, r *http.Request) {
 			response.NotFound(w, "Schedule not found")
 		case usecase.ErrSchedulePast:
 			response.Error(w, http.StatusBadRequest, "Cannot book a past schedule", nil)
+		case usecase.ErrScheduleEnded:
+			response.Error(w, http.StatusBadRequest, "Schedule has already ended", nil)
+		case usecase.ErrBookingClosed:
+			response.Error(w, http.StatusBadRequest, "Booking is closed for this schedule (minimum booking lead time has passed)", nil)
+		case usecase.ErrBookingTooFarAhead:
+			response.Error(w, http.StatusBadRequest, "Schedule is too far ahead, it cannot be booked yet", nil)
+		case usecase.ErrBookingPausedAbsent:
+			response.Error(w, http.StatusConflict, "Booking is paused, doctor possibly absent", nil)
+		case usecase.ErrScheduleClosed:
+			response.Error(w, http.StatusConflict, "Booking is paused for this schedule", nil)
 		case usecase.ErrAlreadyBooked:
 			response.Error(w, http.StatusConflict, "You have already booked this schedule", nil)
 		case service.ErrQuotaFull:
 			response.Error(w, http.StatusConflict, "Schedule slot is full, no remaining quota", nil)
+		case usecase.ErrSlotRequired:
+			response.Error(w, http.StatusBadRequest, "slot_id is required for time-slot schedules", nil)
+		case usecase.ErrSlotNotAllowed:
+			response.Error(w, http.StatusBadRequest, "slot_id is only accepted for time-slot schedules", nil)
+		case usecase.ErrSlotNotFound:
+			response.NotFound(w, "Time slot not found for this schedule")
+		case service.ErrTimeSlotTaken:
+			response.Error(w, http.StatusConflict, "Time slot is already booked, choose another slot", nil)
+		case usecase.ErrQueueNumberConflict:
+			response.Error(w, http.StatusConflict, "Could not assign a queue number, please try again", nil)
+		case usecase.ErrInsuranceMissing:
+			response.Error(w, http.StatusBadRequest, "No insurance registered on your profile", nil)
+		case usecase.ErrInsuranceNotValid:
+			response.Error(w, http.StatusBadRequest, "Insurance is not valid on the schedule date", nil)
+		case usecase.ErrProfileIncomplete:
+			response.Error(w, http.StatusForbidden, "Complete your profile before booking, see profile_completeness on /auth/me", nil)
+		case service.ErrPaymentUnavailable:
+			response.Error(w, http.StatusServiceUnavailable, "Payment could not be started, please try again later", nil)
 		default:
 			response.InternalServerError(w, "Failed to create booking")
 		}
@@ -85,6 +113,10 @@ func (h *BookingHandler) CancelBooking(w http.ResponseWriter, r *http.Request) {
 			response.Forbidden(w, "Booking does not belong to you")
 		case usecase.ErrBookingAlreadyCancelled:
 			response.Error(w, http.StatusConflict, "Booking is already cancelled", nil)
+		case usecase.ErrBookingVersionConflict:
+			response.Error(w, http.StatusConflict, "Booking was modified concurrently, reload and try again", nil)
+		case usecase.ErrCancellationDeadlinePassed:
+			response.Error(w, http.StatusUnprocessableEntity, "Cancellation deadline has passed for this booking", nil)
 		default:
 			response.InternalServerError(w, "Failed to cancel booking")
 		}
@@ -93,3 +125,231 @@ func (h *BookingHandler) CancelBooking(w http.ResponseWriter, r *http.Request) {
 
 	response.Success(w, http.StatusOK, "Booking cancelled successfully", nil)
 }
+
+// AdminCancelBooking cancels any booking regardless of the cancellation deadline (admin override)
+func (h *BookingHandler) AdminCancelBooking(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	bookingID, err := uuid.Parse(vars["id"])
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid booking ID", nil)
+		return
+	}
+
+	err = h.bookingUsecase.AdminCancelBooking(r.Context(), bookingID)
+	if err != nil {
+		switch err {
+		case usecase.ErrBookingNotFound:
+			response.NotFound(w, "Booking not found")
+		case usecase.ErrBookingAlreadyCancelled:
+			response.Error(w, http.StatusConflict, "Booking is already cancelled", nil)
+		case usecase.ErrBookingVersionConflict:
+			response.Error(w, http.StatusConflict, "Booking was modified concurrently, reload and try again", nil)
+		default:
+			response.InternalServerError(w, "Failed to cancel booking")
+		}
+		return
+	}
+
+	response.Success(w, http.StatusOK, "Booking cancelled successfully", nil)
+}
+
+// DeleteBooking soft-deletes a cancelled booking (admin)
+func (h *BookingHandler) DeleteBooking(w http.ResponseWriter, r *http.Request) {
+	bookingID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid booking ID", nil)
+		return
+	}
+
+	if err := h.bookingUsecase.DeleteBooking(r.Context(), bookingID); err != nil {
+		switch err {
+		case usecase.ErrBookingNotFound:
+			response.NotFound(w, "Booking not found")
+		case usecase.ErrBookingNotCancelled:
+			response.Error(w, http.StatusConflict, "Only cancelled bookings can be deleted, cancel the booking first", nil)
+		default:
+			response.InternalServerError(w, "Failed to delete booking")
+		}
+		return
+	}
+
+	response.Success(w, http.StatusOK, "Booking deleted successfully", nil)
+}
+
+// GetDeletedBookings lists the deleted bookings, most recently deleted first (admin).
+// Optional query params: page (default 1), limit (default 20, max 100)
+func (h *BookingHandler) GetDeletedBookings(w http.ResponseWriter, r *http.Request) {
+	page, limit, ok := parsePagination(r)
+	if !ok {
+		response.Error(w, http.StatusBadRequest, "Invalid pagination, page and limit must be positive integers", nil)
+		return
+	}
+
+	bookings, total, err := h.bookingUsecase.GetDeletedBookings(r.Context(), page, limit)
+	if err != nil {
+		response.InternalServerError(w, "Failed to get deleted bookings")
+		return
+	}
+
+	response.SuccessWithMeta(w, http.StatusOK, "Deleted bookings retrieved successfully", bookings, newPaginationMeta(page, limit, total))
+}
+
+// RestoreBooking restores a deleted booking (admin)
+func (h *BookingHandler) RestoreBooking(w http.ResponseWriter, r *http.Request) {
+	bookingID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid booking ID", nil)
+		return
+	}
+
+	booking, err := h.bookingUsecase.RestoreBooking(r.Context(), bookingID)
+	if err != nil {
+		switch err {
+		case usecase.ErrBookingNotFound:
+			response.NotFound(w, "Deleted booking not found")
+		case usecase.ErrBookingScheduleDeleted:
+			response.Error(w, http.StatusConflict, "Schedule of the booking is deleted, the booking cannot be restored", nil)
+		default:
+			response.InternalServerError(w, "Failed to restore booking")
+		}
+		return
+	}
+
+	response.Success(w, http.StatusOK, "Booking restored successfully", booking)
+}
+
+// SubmitWaitFeedback records the patient's actual waiting time for a booking
+func (h *BookingHandler) SubmitWaitFeedback(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	bookingID, err := uuid.Parse(vars["id"])
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid booking ID", nil)
+		return
+	}
+
+	var req dto.WaitFeedbackRequest
+	if err := h.validator.DecodeJSON(r.Body, &req); err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid request body", h.validator.FormatDecodeError(err))
+		return
+	}
+
+	if err := h.validator.Validate(&req); err != nil {
+		response.ValidationError(w, h.validator.FormatValidationErrors(err))
+		return
+	}
+
+	feedback, err := h.bookingUsecase.SubmitWaitFeedback(r.Context(), bookingID, &req)
+	if err != nil {
+		switch err {
+		case usecase.ErrBookingNotFound:
+			response.NotFound(w, "Booking not found")
+		case usecase.ErrBookingNotOwned:
+			response.Forbidden(w, "Booking does not belong to you")
+		case usecase.ErrBookingAlreadyCancelled:
+			response.Error(w, http.StatusConflict, "Booking is cancelled", nil)
+		case usecase.ErrWaitFeedbackTooEarly:
+			response.Error(w, http.StatusUnprocessableEntity, "Wait feedback is only accepted after the schedule has started", nil)
+		case usecase.ErrWaitFeedbackExists:
+			response.Error(w, http.StatusConflict, "Wait feedback already submitted for this booking", nil)
+		default:
+			response.InternalServerError(w, "Failed to submit wait feedback")
+		}
+		return
+	}
+
+	response.Success(w, http.StatusCreated, "Wait feedback submitted successfully", feedback)
+}
+
+// JoinWaitlist puts the patient on the waitlist of a fully booked schedule
+func (h *BookingHandler) JoinWaitlist(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	scheduleID, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid schedule ID", nil)
+		return
+	}
+
+	waitlist, err := h.bookingUsecase.JoinWaitlist(r.Context(), scheduleID)
+	if err != nil {
+		switch err {
+		case usecase.ErrScheduleNotFound:
+			response.NotFound(w, "Schedule not found")
+		case usecase.ErrScheduleEnded:
+			response.Error(w, http.StatusBadRequest, "Schedule has already ended", nil)
+		case usecase.ErrBookingClosed:
+			response.Error(w, http.StatusBadRequest, "Booking is closed for this schedule (minimum booking lead time has passed)", nil)
+		case usecase.ErrBookingTooFarAhead:
+			response.Error(w, http.StatusBadRequest, "Schedule is too far ahead, it cannot be booked yet", nil)
+		case usecase.ErrScheduleClosed:
+			response.Error(w, http.StatusConflict, "Booking is paused for this schedule", nil)
+		case usecase.ErrAlreadyBooked:
+			response.Error(w, http.StatusConflict, "You have already booked this schedule", nil)
+		case service.ErrWaitlistSlotsAvailable:
+			response.Error(w, http.StatusConflict, "Schedule still has remaining quota, book directly", nil)
+		case service.ErrAlreadyWaitlisted:
+			response.Error(w, http.StatusConflict, "You are already on the waitlist", nil)
+		case usecase.ErrWaitlistNotSupported:
+			response.Error(w, http.StatusBadRequest, "Waitlist is not available for time-slot schedules", nil)
+		default:
+			response.InternalServerError(w, "Failed to join waitlist")
+		}
+		return
+	}
+
+	response.Success(w, http.StatusCreated, "Joined waitlist successfully", waitlist)
+}
+
+// LeaveWaitlist removes the patient from the schedule waitlist
+func (h *BookingHandler) LeaveWaitlist(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	scheduleID, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid schedule ID", nil)
+		return
+	}
+
+	if err := h.bookingUsecase.LeaveWaitlist(r.Context(), scheduleID); err != nil {
+		if err == usecase.ErrNotWaitlisted {
+			response.NotFound(w, "You are not on the waitlist for this schedule")
+			return
+		}
+		response.InternalServerError(w, "Failed to leave waitlist")
+		return
+	}
+
+	response.Success(w, http.StatusOK, "Left waitlist successfully", nil)
+}
+
+// GetBookingByCode looks up any booking by its booking code (admin front desk)
+func (h *BookingHandler) GetBookingByCode(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	booking, err := h.bookingUsecase.GetBookingByCode(r.Context(), vars["bookingCode"])
+	if err != nil {
+		if err == usecase.ErrBookingNotFound {
+			response.NotFound(w, "Booking not found")
+			return
+		}
+		response.InternalServerError(w, "Failed to get booking")
+		return
+	}
+
+	response.Success(w, http.StatusOK, "Booking retrieved successfully", booking)
+}
+
+// GetDoctorBookingByCode looks up a booking of the logged-in doctor's schedules by its booking code
+func (h *BookingHandler) GetDoctorBookingByCode(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	booking, err := h.bookingUsecase.GetDoctorBookingByCode(r.Context(), vars["bookingCode"])
+	if err != nil {
+		if err == usecase.ErrBookingNotFound {
+			response.NotFound(w, "Booking not found")
+			return
+		}
+		response.InternalServerError(w, "Failed to get booking")
+		return
+	}
+
+	response.Success(w, http.StatusOK, "Booking retrieved successfully", booking)
+}
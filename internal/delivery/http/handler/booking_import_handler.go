@@ -0,0 +1,58 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"go-template-clean-architecture/internal/usecase"
+	"go-template-clean-architecture/pkg/response"
+)
+
+// maxBookingImportSize bounds the uploaded CSV file
+const maxBookingImportSize = 10 << 20 // 10 MB
+
+type BookingImportHandler struct {
+	importUsecase usecase.BookingImportUsecase
+}
+
+func NewBookingImportHandler(importUsecase usecase.BookingImportUsecase) *BookingImportHandler {
+	return &BookingImportHandler{
+		importUsecase: importUsecase,
+	}
+}
+
+// ImportBookings imports historical bookings from a CSV upload (multipart field "file").
+// Optional query param: dry_run=true validates the file without importing.
+func (h *BookingImportHandler) ImportBookings(w http.ResponseWriter, r *http.Request) {
+	dryRun, _ := strconv.ParseBool(r.URL.Query().Get("dry_run"))
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxBookingImportSize)
+	file, _, err := r.FormFile("file")
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "CSV file is required in form field 'file' (max 10 MB)", nil)
+		return
+	}
+	defer file.Close()
+
+	result, err := h.importUsecase.ImportBookings(r.Context(), file, dryRun)
+	if err != nil {
+		switch err {
+		case usecase.ErrImportInvalidFile:
+			response.Error(w, http.StatusBadRequest, "Import file is not a valid CSV", nil)
+		case usecase.ErrImportTooManyRows:
+			response.Error(w, http.StatusBadRequest, "Import file has too many rows (max 5000)", nil)
+		case usecase.ErrImportInvalidRows:
+			response.Error(w, http.StatusUnprocessableEntity, "Import file has invalid rows, nothing was imported", result.Errors)
+		default:
+			response.InternalServerError(w, "Failed to import bookings")
+		}
+		return
+	}
+
+	if dryRun {
+		response.Success(w, http.StatusOK, "Import file validated successfully", result)
+		return
+	}
+
+	response.Success(w, http.StatusCreated, "Bookings imported successfully", result)
+}
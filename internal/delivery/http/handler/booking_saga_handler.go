@@ -0,0 +1,65 @@
+package handler
+
+import (
+	"net/http"
+
+	"go-template-clean-architecture/internal/usecase"
+	"go-template-clean-architecture/pkg/response"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+type BookingSagaHandler struct {
+	sagaUsecase usecase.BookingSagaUsecase
+}
+
+func NewBookingSagaHandler(sagaUsecase usecase.BookingSagaUsecase) *BookingSagaHandler {
+	return &BookingSagaHandler{
+		sagaUsecase: sagaUsecase,
+	}
+}
+
+// GetAllSagas lists CreateBooking sagas (admin).
+// Query params: status (running, completed, compensating, compensated, failed), page (default 1), limit (default 20, max 100)
+func (h *BookingSagaHandler) GetAllSagas(w http.ResponseWriter, r *http.Request) {
+	page, limit, ok := parsePagination(r)
+	if !ok {
+		response.Error(w, http.StatusBadRequest, "Invalid pagination, page and limit must be positive integers", nil)
+		return
+	}
+
+	sagas, total, err := h.sagaUsecase.GetAllSagas(r.Context(), r.URL.Query().Get("status"), page, limit)
+	if err != nil {
+		switch err {
+		case usecase.ErrInvalidBookingSagaStatus:
+			response.Error(w, http.StatusBadRequest, "Invalid status, use running, completed, compensating, compensated or failed", nil)
+		default:
+			response.InternalServerError(w, "Failed to get booking sagas")
+		}
+		return
+	}
+
+	response.SuccessWithMeta(w, http.StatusOK, "Booking sagas retrieved successfully", sagas, newPaginationMeta(page, limit, total))
+}
+
+// GetSaga returns one CreateBooking saga (admin)
+func (h *BookingSagaHandler) GetSaga(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid saga ID", nil)
+		return
+	}
+
+	saga, err := h.sagaUsecase.GetSaga(r.Context(), id)
+	if err != nil {
+		if err == usecase.ErrBookingSagaNotFound {
+			response.NotFound(w, "Booking saga not found")
+			return
+		}
+		response.InternalServerError(w, "Failed to get booking saga")
+		return
+	}
+
+	response.Success(w, http.StatusOK, "Booking saga retrieved successfully", saga)
+}
@@ -0,0 +1,107 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"go-template-clean-architecture/internal/delivery/dto"
+	"go-template-clean-architecture/internal/usecase"
+	"go-template-clean-architecture/pkg/response"
+	"go-template-clean-architecture/pkg/validator"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+type BroadcastHandler struct {
+	broadcastUsecase usecase.BroadcastUsecase
+	validator        *validator.CustomValidator
+}
+
+func NewBroadcastHandler(broadcastUsecase usecase.BroadcastUsecase, validator *validator.CustomValidator) *BroadcastHandler {
+	return &BroadcastHandler{
+		broadcastUsecase: broadcastUsecase,
+		validator:        validator,
+	}
+}
+
+// CreateBroadcast sends an announcement to a patient segment.
+// Optional query param: dry_run=true only returns the recipient count and estimated completion.
+func (h *BroadcastHandler) CreateBroadcast(w http.ResponseWriter, r *http.Request) {
+	dryRun, _ := strconv.ParseBool(r.URL.Query().Get("dry_run"))
+
+	var req dto.CreateBroadcastRequest
+	if err := h.validator.DecodeJSON(r.Body, &req); err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid request body", h.validator.FormatDecodeError(err))
+		return
+	}
+
+	if err := h.validator.Validate(&req); err != nil {
+		response.ValidationError(w, h.validator.FormatValidationErrors(err))
+		return
+	}
+
+	if dryRun {
+		preview, err := h.broadcastUsecase.PreviewBroadcast(r.Context(), &req)
+		if err != nil {
+			h.writeError(w, err)
+			return
+		}
+		response.Success(w, http.StatusOK, "Broadcast segment resolved successfully", preview)
+		return
+	}
+
+	broadcast, err := h.broadcastUsecase.CreateBroadcast(r.Context(), &req)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	response.Success(w, http.StatusAccepted, "Broadcast queued for delivery", broadcast)
+}
+
+// GetBroadcast returns a broadcast with its delivery report
+func (h *BroadcastHandler) GetBroadcast(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	broadcastID, err := uuid.Parse(vars["id"])
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid broadcast ID", nil)
+		return
+	}
+
+	broadcast, err := h.broadcastUsecase.GetBroadcast(r.Context(), broadcastID)
+	if err != nil {
+		if err == usecase.ErrBroadcastNotFound {
+			response.NotFound(w, "Broadcast not found")
+			return
+		}
+		response.InternalServerError(w, "Failed to get broadcast")
+		return
+	}
+
+	response.Success(w, http.StatusOK, "Broadcast retrieved successfully", broadcast)
+}
+
+func (h *BroadcastHandler) GetAllBroadcasts(w http.ResponseWriter, r *http.Request) {
+	broadcasts, err := h.broadcastUsecase.GetAllBroadcasts(r.Context())
+	if err != nil {
+		response.InternalServerError(w, "Failed to get broadcasts")
+		return
+	}
+
+	response.Success(w, http.StatusOK, "Broadcasts retrieved successfully", broadcasts)
+}
+
+// writeError maps broadcast creation errors to responses
+func (h *BroadcastHandler) writeError(w http.ResponseWriter, err error) {
+	switch err {
+	case usecase.ErrBroadcastInvalidDate:
+		response.Error(w, http.StatusBadRequest, "Invalid segment date format, use YYYY-MM-DD", nil)
+	case usecase.ErrBroadcastInvalidRange:
+		response.Error(w, http.StatusBadRequest, "Segment date_from must not be after date_to", nil)
+	case usecase.ErrBroadcastNoRecipients:
+		response.Error(w, http.StatusUnprocessableEntity, "No patients match the broadcast segment", nil)
+	default:
+		response.InternalServerError(w, "Failed to send broadcast")
+	}
+}
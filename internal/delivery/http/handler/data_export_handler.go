@@ -0,0 +1,63 @@
+package handler
+
+import (
+	"net/http"
+
+	"go-template-clean-architecture/internal/domain/entity"
+	"go-template-clean-architecture/internal/usecase"
+	"go-template-clean-architecture/pkg/response"
+)
+
+type DataExportHandler struct {
+	dataExportUsecase usecase.DataExportUsecase
+}
+
+func NewDataExportHandler(dataExportUsecase usecase.DataExportUsecase) *DataExportHandler {
+	return &DataExportHandler{
+		dataExportUsecase: dataExportUsecase,
+	}
+}
+
+// RequestExport returns the logged-in patient's personal data export, requesting one when
+// none is in progress or downloadable. Generation is asynchronous: 202 while pending, the
+// patient is notified once the archive is ready to download.
+// Optional query param: refresh=true requests a new export even if one is ready
+func (h *DataExportHandler) RequestExport(w http.ResponseWriter, r *http.Request) {
+	export, err := h.dataExportUsecase.RequestExport(r.Context(), r.URL.Query().Get("refresh") == "true")
+	if err != nil {
+		response.InternalServerError(w, "Failed to request data export")
+		return
+	}
+
+	if export.Status == string(entity.DataExportStatusPending) {
+		response.Success(w, http.StatusAccepted, "Data export is being prepared, you will be notified when it is ready", export)
+		return
+	}
+	response.Success(w, http.StatusOK, "Data export is ready", export)
+}
+
+// DownloadExport sends the ZIP archive of the logged-in patient's data export
+func (h *DataExportHandler) DownloadExport(w http.ResponseWriter, r *http.Request) {
+	archive, err := h.dataExportUsecase.DownloadExport(r.Context())
+	if err != nil {
+		switch err {
+		case usecase.ErrDataExportNotFound:
+			response.NotFound(w, "No data export requested")
+		case usecase.ErrDataExportNotReady:
+			response.Error(w, http.StatusConflict, "Data export is not ready yet", nil)
+		case usecase.ErrDataExportExpired:
+			response.Error(w, http.StatusGone, "Data export expired, request a new one", nil)
+		case usecase.ErrDataExportFailed:
+			response.Error(w, http.StatusConflict, "Data export failed, request a new one", nil)
+		default:
+			response.InternalServerError(w, "Failed to download data export")
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/zip")
+	w.Header().Set("Content-Disposition", `attachment; filename="personal-data-export.zip"`)
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(http.StatusOK)
+	w.Write(archive)
+}
@@ -1,8 +1,11 @@
 package handler
 
 import (
-	"encoding/json"
+	"context"
+	"io"
 	"net/http"
+	"strconv"
+	"strings"
 
 	"go-template-clean-architecture/internal/delivery/dto"
 	"go-template-clean-architecture/internal/delivery/http/middleware"
@@ -14,6 +17,12 @@ import (
 	"github.com/gorilla/mux"
 )
 
+// Profile photo upload limits
+const (
+	maxDoctorPhotoSize = 5 << 20  // 5 MB image
+	multipartOverhead  = 64 << 10 // Form boundaries and headers
+)
+
 type DoctorHandler struct {
 	doctorUsecase usecase.DoctorProfileUsecase
 	validator     *validator.CustomValidator
@@ -28,8 +37,8 @@ func NewDoctorHandler(doctorUsecase usecase.DoctorProfileUsecase, validator *val
 
 func (h *DoctorHandler) CreateDoctor(w http.ResponseWriter, r *http.Request) {
 	var req dto.CreateDoctorRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		response.Error(w, http.StatusBadRequest, "Invalid request body", nil)
+	if err := h.validator.DecodeJSON(r.Body, &req); err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid request body", h.validator.FormatDecodeError(err))
 		return
 	}
 
@@ -47,6 +56,8 @@ func (h *DoctorHandler) CreateDoctor(w http.ResponseWriter, r *http.Request) {
 			response.Error(w, http.StatusConflict, "STR number already exists", nil)
 		case usecase.ErrDoctorRoleNotFound:
 			response.Error(w, http.StatusBadRequest, "Role not found", nil)
+		case usecase.ErrSpecializationNotFound:
+			response.Error(w, http.StatusBadRequest, "Specialization not found", nil)
 		default:
 			response.InternalServerError(w, "Failed to create doctor")
 		}
@@ -77,14 +88,90 @@ func (h *DoctorHandler) GetDoctor(w http.ResponseWriter, r *http.Request) {
 	response.Success(w, http.StatusOK, "Doctor retrieved successfully", doctor)
 }
 
+// GetPublicDoctor returns an active doctor with their upcoming bookable schedules (public).
+// Query params: schedules (number of schedules, default 5, max 20)
+func (h *DoctorHandler) GetPublicDoctor(w http.ResponseWriter, r *http.Request) {
+	doctorID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid doctor ID", nil)
+		return
+	}
+
+	scheduleLimit := usecase.DefaultDoctorDetailSchedules
+	if raw := r.URL.Query().Get("schedules"); raw != "" {
+		scheduleLimit, err = strconv.Atoi(raw)
+		if err != nil || scheduleLimit < 0 {
+			response.Error(w, http.StatusBadRequest, "Invalid schedules, use a non-negative number", nil)
+			return
+		}
+		scheduleLimit = min(scheduleLimit, usecase.MaxDoctorDetailSchedules)
+	}
+
+	doctor, err := h.doctorUsecase.GetPublicDoctor(r.Context(), doctorID, scheduleLimit)
+	if err != nil {
+		if err == usecase.ErrDoctorNotFound {
+			response.NotFound(w, "Doctor not found")
+			return
+		}
+		response.InternalServerError(w, "Failed to get doctor")
+		return
+	}
+
+	response.Success(w, http.StatusOK, "Doctor retrieved successfully", doctor)
+}
+
+// GetAllDoctors lists the doctors, including deactivated ones (admin).
+// Query params: specialization (slug), active (true/false), search (name), sort, page (default 1), limit (default 20, max 100)
 func (h *DoctorHandler) GetAllDoctors(w http.ResponseWriter, r *http.Request) {
-	doctors, err := h.doctorUsecase.GetAllDoctors(r.Context())
+	h.listDoctors(w, r, h.doctorUsecase.GetAllDoctors)
+}
+
+// GetPublicDoctors lists the active doctors (public).
+// Query params: specialization (slug), search (name), sort, page (default 1), limit (default 20, max 100)
+func (h *DoctorHandler) GetPublicDoctors(w http.ResponseWriter, r *http.Request) {
+	h.listDoctors(w, r, h.doctorUsecase.GetPublicDoctors)
+}
+
+// listDoctors parses the doctor list query params and writes one page of doctors.
+// sort is one of name (default), newest, fee_asc, fee_desc and rating.
+func (h *DoctorHandler) listDoctors(
+	w http.ResponseWriter,
+	r *http.Request,
+	list func(ctx context.Context, filter *dto.DoctorFilter, page, limit int) (*dto.DoctorListResponse, int64, error),
+) {
+	page, limit, ok := parsePagination(r)
+	if !ok {
+		response.Error(w, http.StatusBadRequest, "Invalid pagination, page and limit must be positive integers", nil)
+		return
+	}
+
+	query := r.URL.Query()
+	filter := &dto.DoctorFilter{
+		Specialization: strings.TrimSpace(query.Get("specialization")),
+		Search:         strings.TrimSpace(query.Get("search")),
+		Sort:           strings.TrimSpace(query.Get("sort")),
+	}
+	if raw := query.Get("active"); raw != "" {
+		active, err := strconv.ParseBool(raw)
+		if err != nil {
+			response.Error(w, http.StatusBadRequest, "Invalid active filter, use true or false", nil)
+			return
+		}
+		filter.Active = &active
+	}
+
+	doctors, total, err := list(r.Context(), filter, page, limit)
 	if err != nil {
-		response.InternalServerError(w, "Failed to get doctors")
+		switch err {
+		case usecase.ErrInvalidDoctorSort:
+			response.Error(w, http.StatusBadRequest, "Invalid sort, use name, newest, fee_asc, fee_desc or rating", nil)
+		default:
+			response.InternalServerError(w, "Failed to get doctors")
+		}
 		return
 	}
 
-	response.Success(w, http.StatusOK, "Doctors retrieved successfully", doctors)
+	response.SuccessWithMeta(w, http.StatusOK, "Doctors retrieved successfully", doctors, newPaginationMeta(page, limit, total))
 }
 
 func (h *DoctorHandler) UpdateDoctor(w http.ResponseWriter, r *http.Request) {
@@ -96,8 +183,8 @@ func (h *DoctorHandler) UpdateDoctor(w http.ResponseWriter, r *http.Request) {
 	}
 
 	var req dto.UpdateDoctorRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		response.Error(w, http.StatusBadRequest, "Invalid request body", nil)
+	if err := h.validator.DecodeJSON(r.Body, &req); err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid request body", h.validator.FormatDecodeError(err))
 		return
 	}
 
@@ -113,6 +200,8 @@ func (h *DoctorHandler) UpdateDoctor(w http.ResponseWriter, r *http.Request) {
 			response.NotFound(w, "Doctor not found")
 		case usecase.ErrDoctorSTRExists:
 			response.Error(w, http.StatusConflict, "STR number already exists", nil)
+		case usecase.ErrSpecializationNotFound:
+			response.Error(w, http.StatusBadRequest, "Specialization not found", nil)
 		default:
 			response.InternalServerError(w, "Failed to update doctor")
 		}
@@ -152,8 +241,8 @@ func (h *DoctorHandler) UpdateSelfProfile(w http.ResponseWriter, r *http.Request
 	}
 
 	var req dto.DoctorUpdateSelfRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		response.Error(w, http.StatusBadRequest, "Invalid request body", nil)
+	if err := h.validator.DecodeJSON(r.Body, &req); err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid request body", h.validator.FormatDecodeError(err))
 		return
 	}
 
@@ -177,3 +266,93 @@ func (h *DoctorHandler) UpdateSelfProfile(w http.ResponseWriter, r *http.Request
 
 	response.Success(w, http.StatusOK, "Profile updated successfully", doctor)
 }
+
+// UploadPhoto replaces the profile photo of a doctor (admin).
+// Multipart form field "photo": a JPEG or PNG of at most 5 MB, cropped to a square.
+func (h *DoctorHandler) UploadPhoto(w http.ResponseWriter, r *http.Request) {
+	doctorID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid doctor ID", nil)
+		return
+	}
+	h.uploadPhoto(w, r, doctorID)
+}
+
+// DeletePhoto removes the profile photo of a doctor (admin)
+func (h *DoctorHandler) DeletePhoto(w http.ResponseWriter, r *http.Request) {
+	doctorID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid doctor ID", nil)
+		return
+	}
+	h.deletePhoto(w, r, doctorID)
+}
+
+// UploadSelfPhoto replaces the logged-in doctor's profile photo, see UploadPhoto
+func (h *DoctorHandler) UploadSelfPhoto(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		response.Unauthorized(w, "Unauthorized")
+		return
+	}
+	h.uploadPhoto(w, r, userID)
+}
+
+// DeleteSelfPhoto removes the logged-in doctor's profile photo
+func (h *DoctorHandler) DeleteSelfPhoto(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		response.Unauthorized(w, "Unauthorized")
+		return
+	}
+	h.deletePhoto(w, r, userID)
+}
+
+func (h *DoctorHandler) uploadPhoto(w http.ResponseWriter, r *http.Request, doctorID uuid.UUID) {
+	r.Body = http.MaxBytesReader(w, r.Body, maxDoctorPhotoSize+multipartOverhead)
+	file, _, err := r.FormFile("photo")
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "Photo is required in form field 'photo' (max 5 MB)", nil)
+		return
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(io.LimitReader(file, maxDoctorPhotoSize+1))
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "Failed to read photo", nil)
+		return
+	}
+	if len(data) > maxDoctorPhotoSize {
+		response.Error(w, http.StatusRequestEntityTooLarge, "Photo must be at most 5 MB", nil)
+		return
+	}
+
+	doctor, err := h.doctorUsecase.UpdatePhoto(r.Context(), doctorID, data)
+	if err != nil {
+		switch err {
+		case usecase.ErrDoctorNotFound:
+			response.NotFound(w, "Doctor not found")
+		case usecase.ErrInvalidDoctorPhoto, usecase.ErrDoctorPhotoTooBig:
+			response.Error(w, http.StatusBadRequest, err.Error(), nil)
+		default:
+			response.InternalServerError(w, "Failed to upload photo")
+		}
+		return
+	}
+
+	response.Success(w, http.StatusOK, "Photo updated successfully", doctor)
+}
+
+func (h *DoctorHandler) deletePhoto(w http.ResponseWriter, r *http.Request, doctorID uuid.UUID) {
+	doctor, err := h.doctorUsecase.DeletePhoto(r.Context(), doctorID)
+	if err != nil {
+		if err == usecase.ErrDoctorNotFound {
+			response.NotFound(w, "Doctor not found")
+			return
+		}
+		response.InternalServerError(w, "Failed to delete photo")
+		return
+	}
+
+	response.Success(w, http.StatusOK, "Photo deleted successfully", doctor)
+}
@@ -0,0 +1,160 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"go-template-clean-architecture/internal/delivery/dto"
+	"go-template-clean-architecture/internal/delivery/http/middleware"
+	"go-template-clean-architecture/internal/usecase"
+	"go-template-clean-architecture/pkg/response"
+	"go-template-clean-architecture/pkg/validator"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+type DoctorLeaveHandler struct {
+	leaveUsecase usecase.DoctorLeaveUsecase
+	validator    *validator.CustomValidator
+}
+
+func NewDoctorLeaveHandler(leaveUsecase usecase.DoctorLeaveUsecase, validator *validator.CustomValidator) *DoctorLeaveHandler {
+	return &DoctorLeaveHandler{
+		leaveUsecase: leaveUsecase,
+		validator:    validator,
+	}
+}
+
+// CreateLeave records a leave of any doctor (admin)
+func (h *DoctorLeaveHandler) CreateLeave(w http.ResponseWriter, r *http.Request) {
+	doctorID, err := uuid.Parse(mux.Vars(r)["doctorId"])
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid doctor ID", nil)
+		return
+	}
+
+	h.createLeave(w, r, doctorID)
+}
+
+// CreateMyLeave records a leave of the logged-in doctor
+func (h *DoctorLeaveHandler) CreateMyLeave(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		response.Unauthorized(w, "Unauthorized")
+		return
+	}
+
+	h.createLeave(w, r, userID)
+}
+
+func (h *DoctorLeaveHandler) createLeave(w http.ResponseWriter, r *http.Request, doctorID uuid.UUID) {
+	var req dto.CreateDoctorLeaveRequest
+	if err := h.validator.DecodeJSON(r.Body, &req); err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid request body", h.validator.FormatDecodeError(err))
+		return
+	}
+
+	if err := h.validator.Validate(&req); err != nil {
+		response.ValidationError(w, h.validator.FormatValidationErrors(err))
+		return
+	}
+
+	leave, err := h.leaveUsecase.CreateLeave(r.Context(), doctorID, &req)
+	if err != nil {
+		switch err {
+		case usecase.ErrDoctorNotFound:
+			response.NotFound(w, "Doctor not found")
+		case usecase.ErrInvalidLeaveDate:
+			response.Error(w, http.StatusBadRequest, "Invalid leave date format, use YYYY-MM-DD", nil)
+		case usecase.ErrLeaveEndBeforeStart:
+			response.Error(w, http.StatusBadRequest, "Leave end date is before its start date", nil)
+		case usecase.ErrLeaveInPast:
+			response.Error(w, http.StatusBadRequest, "Leave cannot start in the past", nil)
+		case usecase.ErrLeaveOverlaps:
+			response.Error(w, http.StatusConflict, "Leave overlaps another leave of the doctor", nil)
+		default:
+			response.InternalServerError(w, "Failed to create leave")
+		}
+		return
+	}
+
+	response.Success(w, http.StatusCreated, "Leave created successfully", leave)
+}
+
+// GetLeaves lists the leaves of any doctor (admin). Optional query params: from, to (YYYY-MM-DD)
+func (h *DoctorLeaveHandler) GetLeaves(w http.ResponseWriter, r *http.Request) {
+	doctorID, err := uuid.Parse(mux.Vars(r)["doctorId"])
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid doctor ID", nil)
+		return
+	}
+
+	h.getLeaves(w, r, doctorID)
+}
+
+// GetMyLeaves lists the leaves of the logged-in doctor. Optional query params: from, to (YYYY-MM-DD)
+func (h *DoctorLeaveHandler) GetMyLeaves(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		response.Unauthorized(w, "Unauthorized")
+		return
+	}
+
+	h.getLeaves(w, r, userID)
+}
+
+func (h *DoctorLeaveHandler) getLeaves(w http.ResponseWriter, r *http.Request, doctorID uuid.UUID) {
+	leaves, err := h.leaveUsecase.GetLeaves(r.Context(), doctorID, r.URL.Query().Get("from"), r.URL.Query().Get("to"))
+	if err != nil {
+		if err == usecase.ErrInvalidLeaveDate {
+			response.Error(w, http.StatusBadRequest, "Invalid date format, use YYYY-MM-DD", nil)
+			return
+		}
+		response.InternalServerError(w, "Failed to get leaves")
+		return
+	}
+
+	response.Success(w, http.StatusOK, "Leaves retrieved successfully", leaves)
+}
+
+// DeleteLeave removes a leave of any doctor (admin)
+func (h *DoctorLeaveHandler) DeleteLeave(w http.ResponseWriter, r *http.Request) {
+	doctorID, err := uuid.Parse(mux.Vars(r)["doctorId"])
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid doctor ID", nil)
+		return
+	}
+
+	h.deleteLeave(w, r, doctorID)
+}
+
+// DeleteMyLeave removes a leave of the logged-in doctor
+func (h *DoctorLeaveHandler) DeleteMyLeave(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		response.Unauthorized(w, "Unauthorized")
+		return
+	}
+
+	h.deleteLeave(w, r, userID)
+}
+
+func (h *DoctorLeaveHandler) deleteLeave(w http.ResponseWriter, r *http.Request, doctorID uuid.UUID) {
+	leaveID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid leave ID", nil)
+		return
+	}
+
+	if err := h.leaveUsecase.DeleteLeave(r.Context(), doctorID, leaveID); err != nil {
+		if err == usecase.ErrDoctorLeaveNotFound {
+			response.NotFound(w, "Leave not found")
+			return
+		}
+		response.InternalServerError(w, "Failed to delete leave")
+		return
+	}
+
+	response.Success(w, http.StatusOK, "Leave deleted successfully", nil)
+}
@@ -0,0 +1,94 @@
+package handler
+
+import (
+	"net/http"
+
+	"go-template-clean-architecture/internal/delivery/dto"
+	"go-template-clean-architecture/internal/usecase"
+	"go-template-clean-architecture/pkg/response"
+	"go-template-clean-architecture/pkg/validator"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+type DoctorReviewHandler struct {
+	reviewUsecase usecase.DoctorReviewUsecase
+	validator     *validator.CustomValidator
+}
+
+func NewDoctorReviewHandler(reviewUsecase usecase.DoctorReviewUsecase, validator *validator.CustomValidator) *DoctorReviewHandler {
+	return &DoctorReviewHandler{
+		reviewUsecase: reviewUsecase,
+		validator:     validator,
+	}
+}
+
+// SubmitReview records the patient's rating of a visit (patient portal)
+func (h *DoctorReviewHandler) SubmitReview(w http.ResponseWriter, r *http.Request) {
+	bookingID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid booking ID", nil)
+		return
+	}
+
+	var req dto.DoctorReviewRequest
+	if err := h.validator.DecodeJSON(r.Body, &req); err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid request body", h.validator.FormatDecodeError(err))
+		return
+	}
+
+	if err := h.validator.Validate(&req); err != nil {
+		response.ValidationError(w, h.validator.FormatValidationErrors(err))
+		return
+	}
+
+	review, err := h.reviewUsecase.SubmitReview(r.Context(), bookingID, &req)
+	if err != nil {
+		switch err {
+		case usecase.ErrBookingNotFound:
+			response.NotFound(w, "Booking not found")
+		case usecase.ErrBookingNotOwned:
+			response.Forbidden(w, "Booking does not belong to you")
+		case usecase.ErrBookingAlreadyCancelled:
+			response.Error(w, http.StatusConflict, "Booking is cancelled", nil)
+		case usecase.ErrReviewVisitNotCompleted:
+			response.Error(w, http.StatusUnprocessableEntity, "Reviews are only accepted after the visit", nil)
+		case usecase.ErrReviewExists:
+			response.Error(w, http.StatusConflict, "Review already submitted for this booking", nil)
+		default:
+			response.InternalServerError(w, "Failed to submit review")
+		}
+		return
+	}
+
+	response.Success(w, http.StatusCreated, "Review submitted successfully", review)
+}
+
+// GetDoctorReviews lists the reviews of a doctor, newest first (public).
+// Optional query params: page (default 1), limit (default 20, max 100)
+func (h *DoctorReviewHandler) GetDoctorReviews(w http.ResponseWriter, r *http.Request) {
+	doctorID, err := uuid.Parse(mux.Vars(r)["doctorId"])
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid doctor ID", nil)
+		return
+	}
+
+	page, limit, ok := parsePagination(r)
+	if !ok {
+		response.Error(w, http.StatusBadRequest, "Invalid pagination, page and limit must be positive integers", nil)
+		return
+	}
+
+	reviews, total, err := h.reviewUsecase.GetDoctorReviews(r.Context(), doctorID, page, limit)
+	if err != nil {
+		if err == usecase.ErrDoctorNotFound {
+			response.NotFound(w, "Doctor not found")
+			return
+		}
+		response.InternalServerError(w, "Failed to get reviews")
+		return
+	}
+
+	response.SuccessWithMeta(w, http.StatusOK, "Reviews retrieved successfully", reviews, newPaginationMeta(page, limit, total))
+}
@@ -1,9 +1,10 @@
 package handler
 
 import (
-	"encoding/json"
+	"context"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"go-template-clean-architecture/internal/delivery/dto"
 	"go-template-clean-architecture/internal/delivery/http/middleware"
@@ -29,8 +30,8 @@ func NewDoctorScheduleHandler(scheduleUsecase usecase.DoctorScheduleUsecase, val
 
 func (h *DoctorScheduleHandler) CreateSchedule(w http.ResponseWriter, r *http.Request) {
 	var req dto.CreateScheduleRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		response.Error(w, http.StatusBadRequest, "Invalid request body", nil)
+	if err := h.validator.DecodeJSON(r.Body, &req); err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid request body", h.validator.FormatDecodeError(err))
 		return
 	}
 
@@ -41,22 +42,65 @@ func (h *DoctorScheduleHandler) CreateSchedule(w http.ResponseWriter, r *http.Re
 
 	schedule, err := h.scheduleUsecase.CreateSchedule(r.Context(), &req)
 	if err != nil {
-		switch err {
-		case usecase.ErrDoctorNotFound:
-			response.NotFound(w, "Doctor not found")
-		case usecase.ErrInvalidScheduleDate:
-			response.Error(w, http.StatusBadRequest, "Invalid schedule date format, use YYYY-MM-DD", nil)
-		case usecase.ErrInvalidTimeFormat:
-			response.Error(w, http.StatusBadRequest, "Invalid time format, use HH:MM", nil)
-		default:
-			response.InternalServerError(w, "Failed to create schedule")
-		}
+		writeCreateScheduleError(w, err, "Failed to create schedule")
 		return
 	}
 
 	response.Success(w, http.StatusCreated, "Schedule created successfully", schedule)
 }
 
+// ProposeSchedule lets a doctor propose a schedule for themselves; it waits for admin approval
+func (h *DoctorScheduleHandler) ProposeSchedule(w http.ResponseWriter, r *http.Request) {
+	var req dto.ProposeScheduleRequest
+	if err := h.validator.DecodeJSON(r.Body, &req); err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid request body", h.validator.FormatDecodeError(err))
+		return
+	}
+
+	if err := h.validator.Validate(&req); err != nil {
+		response.ValidationError(w, h.validator.FormatValidationErrors(err))
+		return
+	}
+
+	schedule, err := h.scheduleUsecase.ProposeSchedule(r.Context(), &req)
+	if err != nil {
+		writeCreateScheduleError(w, err, "Failed to propose schedule")
+		return
+	}
+
+	response.Success(w, http.StatusCreated, "Schedule proposed successfully, pending admin approval", schedule)
+}
+
+// writeCreateScheduleError maps the errors of creating or proposing a schedule
+func writeCreateScheduleError(w http.ResponseWriter, err error, failureMessage string) {
+	switch err {
+	case usecase.ErrDoctorNotFound:
+		response.NotFound(w, "Doctor not found")
+	case usecase.ErrInvalidScheduleDate:
+		response.Error(w, http.StatusBadRequest, "Invalid schedule date format, use YYYY-MM-DD", nil)
+	case usecase.ErrInvalidTimeFormat:
+		response.Error(w, http.StatusBadRequest, "Invalid time format, use HH:MM", nil)
+	case usecase.ErrScheduleDefaultsMissing:
+		response.Error(w, http.StatusBadRequest, "total_quota and end_time are required when no specialization defaults exist", nil)
+	case usecase.ErrScheduleExceedsDay:
+		response.Error(w, http.StatusBadRequest, "Schedule end time exceeds the schedule date", nil)
+	case usecase.ErrSlotMinutesMissing:
+		response.Error(w, http.StatusBadRequest, "slot_minutes is required when no specialization defaults exist", nil)
+	case usecase.ErrScheduleShorterThanSlot:
+		response.Error(w, http.StatusBadRequest, "Schedule is shorter than one time slot", nil)
+	case usecase.ErrInvalidScheduleBreak:
+		response.Error(w, http.StatusBadRequest, "Breaks must end after they start, lie inside the schedule and not overlap", nil)
+	case usecase.ErrScheduleOnHoliday:
+		response.Error(w, http.StatusConflict, "Schedule date is a clinic holiday", nil)
+	case usecase.ErrProposalInPast:
+		response.Error(w, http.StatusBadRequest, "Proposed schedule date is in the past", nil)
+	case usecase.ErrScheduleOverlaps:
+		response.Error(w, http.StatusConflict, "Schedule overlaps another schedule of the doctor", nil)
+	default:
+		response.InternalServerError(w, failureMessage)
+	}
+}
+
 func (h *DoctorScheduleHandler) GetSchedule(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	scheduleID, err := strconv.Atoi(vars["id"])
@@ -78,33 +122,88 @@ func (h *DoctorScheduleHandler) GetSchedule(w http.ResponseWriter, r *http.Reque
 	response.Success(w, http.StatusOK, "Schedule retrieved successfully", schedule)
 }
 
+// GetScheduleHistory lists every recorded change of a schedule, oldest first (admin).
+// Also available after the schedule was deleted.
+func (h *DoctorScheduleHandler) GetScheduleHistory(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	scheduleID, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid schedule ID", nil)
+		return
+	}
+
+	history, err := h.scheduleUsecase.GetScheduleHistory(r.Context(), scheduleID)
+	if err != nil {
+		if err == usecase.ErrScheduleNotFound {
+			response.NotFound(w, "Schedule not found")
+			return
+		}
+		response.InternalServerError(w, "Failed to get schedule history")
+		return
+	}
+
+	response.Success(w, http.StatusOK, "Schedule history retrieved successfully", history)
+}
+
+// GetAllSchedules lists schedules of all doctors (admin).
+// Query params: start_at, end_at (YYYY-MM-DD), doctor_name, specialization (slug), page (default 1), limit (default 20, max 100)
 func (h *DoctorScheduleHandler) GetAllSchedules(w http.ResponseWriter, r *http.Request) {
-	schedules, err := h.scheduleUsecase.GetAllSchedules(r.Context())
+	page, limit, ok := parsePagination(r)
+	if !ok {
+		response.Error(w, http.StatusBadRequest, "Invalid pagination, page and limit must be positive integers", nil)
+		return
+	}
+
+	schedules, total, err := h.scheduleUsecase.GetAllSchedules(r.Context(), parseScheduleFilter(r), page, limit)
 	if err != nil {
-		response.InternalServerError(w, "Failed to get schedules")
+		switch err {
+		case usecase.ErrInvalidScheduleFilter:
+			response.Error(w, http.StatusBadRequest, "Invalid date filter, use YYYY-MM-DD with start_at not after end_at", nil)
+		default:
+			response.InternalServerError(w, "Failed to get schedules")
+		}
 		return
 	}
 
-	response.Success(w, http.StatusOK, "Schedules retrieved successfully", schedules)
+	response.SuccessWithMeta(w, http.StatusOK, "Schedules retrieved successfully", schedules, newPaginationMeta(page, limit, total))
 }
 
+// GetPublicSchedules lists schedules of active doctors only.
+// Query params: start_at, end_at (YYYY-MM-DD), doctor_name, specialization (slug), page (default 1), limit (default 20, max 100)
 func (h *DoctorScheduleHandler) GetPublicSchedules(w http.ResponseWriter, r *http.Request) {
-	filter := &dto.PublicScheduleFilter{
-		StartAt:        r.URL.Query().Get("start_at"),
-		EndAt:          r.URL.Query().Get("end_at"),
-		DoctorName:     r.URL.Query().Get("doctor_name"),
-		Specialization: r.URL.Query().Get("specialization"),
+	page, limit, ok := parsePagination(r)
+	if !ok {
+		response.Error(w, http.StatusBadRequest, "Invalid pagination, page and limit must be positive integers", nil)
+		return
 	}
 
-	schedules, err := h.scheduleUsecase.GetPublicSchedules(r.Context(), filter)
+	schedules, total, err := h.scheduleUsecase.GetPublicSchedules(r.Context(), parseScheduleFilter(r), page, limit)
 	if err != nil {
-		response.InternalServerError(w, "Failed to get schedules")
+		switch err {
+		case usecase.ErrInvalidScheduleFilter:
+			response.Error(w, http.StatusBadRequest, "Invalid date filter, use YYYY-MM-DD with start_at not after end_at", nil)
+		default:
+			response.InternalServerError(w, "Failed to get schedules")
+		}
 		return
 	}
 
-	response.Success(w, http.StatusOK, "Schedules retrieved successfully", schedules)
+	response.SuccessWithMeta(w, http.StatusOK, "Schedules retrieved successfully", schedules, newPaginationMeta(page, limit, total))
+}
+
+// parseScheduleFilter reads the schedule listing filters from the query string
+func parseScheduleFilter(r *http.Request) *dto.ScheduleFilter {
+	query := r.URL.Query()
+	return &dto.ScheduleFilter{
+		StartAt:        strings.TrimSpace(query.Get("start_at")),
+		EndAt:          strings.TrimSpace(query.Get("end_at")),
+		DoctorName:     strings.TrimSpace(query.Get("doctor_name")),
+		Specialization: strings.TrimSpace(query.Get("specialization")),
+	}
 }
 
+// GetSchedulesByDoctor lists a doctor's schedules (admin).
+// Optional query params: page (default 1), limit (default 20, max 100)
 func (h *DoctorScheduleHandler) GetSchedulesByDoctor(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	doctorID, err := uuid.Parse(vars["doctorId"])
@@ -113,13 +212,19 @@ func (h *DoctorScheduleHandler) GetSchedulesByDoctor(w http.ResponseWriter, r *h
 		return
 	}
 
-	schedules, err := h.scheduleUsecase.GetSchedulesByDoctor(r.Context(), doctorID)
+	page, limit, ok := parsePagination(r)
+	if !ok {
+		response.Error(w, http.StatusBadRequest, "Invalid pagination, page and limit must be positive integers", nil)
+		return
+	}
+
+	schedules, total, err := h.scheduleUsecase.GetSchedulesByDoctor(r.Context(), doctorID, page, limit)
 	if err != nil {
 		response.InternalServerError(w, "Failed to get schedules")
 		return
 	}
 
-	response.Success(w, http.StatusOK, "Schedules retrieved successfully", schedules)
+	response.SuccessWithMeta(w, http.StatusOK, "Schedules retrieved successfully", schedules, newPaginationMeta(page, limit, total))
 }
 
 func (h *DoctorScheduleHandler) UpdateSchedule(w http.ResponseWriter, r *http.Request) {
@@ -131,8 +236,8 @@ func (h *DoctorScheduleHandler) UpdateSchedule(w http.ResponseWriter, r *http.Re
 	}
 
 	var req dto.UpdateScheduleRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		response.Error(w, http.StatusBadRequest, "Invalid request body", nil)
+	if err := h.validator.DecodeJSON(r.Body, &req); err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid request body", h.validator.FormatDecodeError(err))
 		return
 	}
 
@@ -152,6 +257,12 @@ func (h *DoctorScheduleHandler) UpdateSchedule(w http.ResponseWriter, r *http.Re
 			response.Error(w, http.StatusBadRequest, "Invalid schedule date format, use YYYY-MM-DD", nil)
 		case usecase.ErrInvalidTimeFormat:
 			response.Error(w, http.StatusBadRequest, "Invalid time format, use HH:MM", nil)
+		case usecase.ErrSlotScheduleLocked:
+			response.Error(w, http.StatusConflict, "Start time, end time, quota and breaks of a time-slot schedule cannot be changed", nil)
+		case usecase.ErrInvalidScheduleBreak:
+			response.Error(w, http.StatusBadRequest, "Breaks must end after they start, lie inside the schedule and not overlap", nil)
+		case usecase.ErrScheduleOnHoliday:
+			response.Error(w, http.StatusConflict, "Schedule date is a clinic holiday", nil)
 		default:
 			response.InternalServerError(w, "Failed to update schedule")
 		}
@@ -161,6 +272,8 @@ func (h *DoctorScheduleHandler) UpdateSchedule(w http.ResponseWriter, r *http.Re
 	response.Success(w, http.StatusOK, "Schedule updated successfully", schedule)
 }
 
+// DeleteSchedule deletes a schedule (admin).
+// Optional query param: force=true cancels active bookings (patients are notified) and deletes them with the schedule.
 func (h *DoctorScheduleHandler) DeleteSchedule(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	scheduleID, err := strconv.Atoi(vars["id"])
@@ -169,19 +282,28 @@ func (h *DoctorScheduleHandler) DeleteSchedule(w http.ResponseWriter, r *http.Re
 		return
 	}
 
-	err = h.scheduleUsecase.DeleteSchedule(r.Context(), scheduleID)
+	force, _ := strconv.ParseBool(r.URL.Query().Get("force"))
+
+	result, err := h.scheduleUsecase.DeleteSchedule(r.Context(), scheduleID, force)
 	if err != nil {
-		if err == usecase.ErrScheduleNotFound {
+		switch err {
+		case usecase.ErrScheduleNotFound:
 			response.NotFound(w, "Schedule not found")
-			return
+		case usecase.ErrScheduleHasBookings:
+			response.Error(w, http.StatusConflict, "Schedule has active bookings, pass force=true to cancel them and delete the schedule", result)
+		case usecase.ErrScheduleHasHistory:
+			response.Error(w, http.StatusConflict, "Schedule has cancelled bookings, pass force=true to delete them with the schedule", result)
+		default:
+			response.InternalServerError(w, "Failed to delete schedule")
 		}
-		response.InternalServerError(w, "Failed to delete schedule")
 		return
 	}
 
-	response.Success(w, http.StatusOK, "Schedule deleted successfully", nil)
+	response.Success(w, http.StatusOK, "Schedule deleted successfully", result)
 }
 
+// GetMySchedules lists the logged-in doctor's schedules.
+// Optional query params: page (default 1), limit (default 20, max 100)
 func (h *DoctorScheduleHandler) GetMySchedules(w http.ResponseWriter, r *http.Request) {
 	// Get user ID from context
 	userID, ok := middleware.GetUserIDFromContext(r.Context())
@@ -190,7 +312,159 @@ func (h *DoctorScheduleHandler) GetMySchedules(w http.ResponseWriter, r *http.Re
 		return
 	}
 
-	schedules, err := h.scheduleUsecase.GetSchedulesByDoctor(r.Context(), userID)
+	page, limit, ok := parsePagination(r)
+	if !ok {
+		response.Error(w, http.StatusBadRequest, "Invalid pagination, page and limit must be positive integers", nil)
+		return
+	}
+
+	schedules, total, err := h.scheduleUsecase.GetSchedulesByDoctor(r.Context(), userID, page, limit)
+	if err != nil {
+		response.InternalServerError(w, "Failed to get schedules")
+		return
+	}
+
+	response.SuccessWithMeta(w, http.StatusOK, "Schedules retrieved successfully", schedules, newPaginationMeta(page, limit, total))
+}
+
+// GetMyScheduleCalendar returns the logged-in doctor's upcoming schedules as an iCalendar feed
+func (h *DoctorScheduleHandler) GetMyScheduleCalendar(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		response.Unauthorized(w, "Unauthorized")
+		return
+	}
+
+	h.writeScheduleCalendar(w, r, userID)
+}
+
+// GetDoctorScheduleCalendar returns a doctor's upcoming schedules as an iCalendar feed (admin)
+func (h *DoctorScheduleHandler) GetDoctorScheduleCalendar(w http.ResponseWriter, r *http.Request) {
+	doctorID, err := uuid.Parse(mux.Vars(r)["doctorId"])
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid doctor ID", nil)
+		return
+	}
+
+	h.writeScheduleCalendar(w, r, doctorID)
+}
+
+func (h *DoctorScheduleHandler) writeScheduleCalendar(w http.ResponseWriter, r *http.Request, doctorID uuid.UUID) {
+	calendar, err := h.scheduleUsecase.GetScheduleCalendar(r.Context(), doctorID)
+	if err != nil {
+		response.InternalServerError(w, "Failed to get schedule calendar")
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	w.Header().Set("Content-Disposition", `inline; filename="schedules.ics"`)
+	w.WriteHeader(http.StatusOK)
+	w.Write(calendar)
+}
+
+// CheckIn records the logged-in doctor's arrival for a schedule
+func (h *DoctorScheduleHandler) CheckIn(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	scheduleID, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid schedule ID", nil)
+		return
+	}
+
+	schedule, err := h.scheduleUsecase.CheckIn(r.Context(), scheduleID)
+	if err != nil {
+		switch err {
+		case usecase.ErrScheduleNotFound:
+			response.NotFound(w, "Schedule not found")
+		case usecase.ErrScheduleNotOwned:
+			response.Forbidden(w, "Schedule does not belong to you")
+		case usecase.ErrAlreadyCheckedIn:
+			response.Error(w, http.StatusConflict, "Already checked in for this schedule", nil)
+		case usecase.ErrCheckInNotToday:
+			response.Error(w, http.StatusBadRequest, "Check-in is only allowed on the schedule date", nil)
+		default:
+			response.InternalServerError(w, "Failed to check in")
+		}
+		return
+	}
+
+	response.Success(w, http.StatusOK, "Checked in successfully", schedule)
+}
+
+// SetBookingOpen opens or pauses booking on a schedule (admin)
+func (h *DoctorScheduleHandler) SetBookingOpen(w http.ResponseWriter, r *http.Request) {
+	h.setBookingOpen(w, r, h.scheduleUsecase.SetBookingOpen)
+}
+
+// SetMyBookingOpen opens or pauses booking on the logged-in doctor's schedule
+func (h *DoctorScheduleHandler) SetMyBookingOpen(w http.ResponseWriter, r *http.Request) {
+	h.setBookingOpen(w, r, h.scheduleUsecase.SetMyBookingOpen)
+}
+
+func (h *DoctorScheduleHandler) setBookingOpen(w http.ResponseWriter, r *http.Request, setOpen func(ctx context.Context, scheduleID int, isOpen bool) (*dto.ScheduleResponse, error)) {
+	vars := mux.Vars(r)
+	scheduleID, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid schedule ID", nil)
+		return
+	}
+
+	var req dto.SetScheduleOpenRequest
+	if err := h.validator.DecodeJSON(r.Body, &req); err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid request body", h.validator.FormatDecodeError(err))
+		return
+	}
+
+	if err := h.validator.Validate(&req); err != nil {
+		response.ValidationError(w, h.validator.FormatValidationErrors(err))
+		return
+	}
+
+	schedule, err := setOpen(r.Context(), scheduleID, *req.IsOpen)
+	if err != nil {
+		switch err {
+		case usecase.ErrScheduleNotFound:
+			response.NotFound(w, "Schedule not found")
+		case usecase.ErrScheduleNotOwned:
+			response.Forbidden(w, "Schedule does not belong to you")
+		default:
+			response.InternalServerError(w, "Failed to update schedule booking status")
+		}
+		return
+	}
+
+	if *req.IsOpen {
+		response.Success(w, http.StatusOK, "Schedule opened for booking", schedule)
+		return
+	}
+	response.Success(w, http.StatusOK, "Schedule booking paused", schedule)
+}
+
+// GetPossiblyAbsentSchedules lists schedules flagged as doctor possibly absent
+func (h *DoctorScheduleHandler) GetPossiblyAbsentSchedules(w http.ResponseWriter, r *http.Request) {
+	schedules, err := h.scheduleUsecase.GetPossiblyAbsentSchedules(r.Context())
+	if err != nil {
+		response.InternalServerError(w, "Failed to get schedules")
+		return
+	}
+
+	response.Success(w, http.StatusOK, "Schedules retrieved successfully", schedules)
+}
+
+// GetHolidayFlaggedSchedules lists schedules on holidays added after they were created
+func (h *DoctorScheduleHandler) GetHolidayFlaggedSchedules(w http.ResponseWriter, r *http.Request) {
+	schedules, err := h.scheduleUsecase.GetHolidayFlaggedSchedules(r.Context())
+	if err != nil {
+		response.InternalServerError(w, "Failed to get schedules")
+		return
+	}
+
+	response.Success(w, http.StatusOK, "Schedules retrieved successfully", schedules)
+}
+
+// GetLeaveFlaggedSchedules lists schedules closed because their doctor took leave after they were created
+func (h *DoctorScheduleHandler) GetLeaveFlaggedSchedules(w http.ResponseWriter, r *http.Request) {
+	schedules, err := h.scheduleUsecase.GetLeaveFlaggedSchedules(r.Context())
 	if err != nil {
 		response.InternalServerError(w, "Failed to get schedules")
 		return
@@ -198,3 +472,303 @@ func (h *DoctorScheduleHandler) GetMySchedules(w http.ResponseWriter, r *http.Re
 
 	response.Success(w, http.StatusOK, "Schedules retrieved successfully", schedules)
 }
+
+// GetPendingProposals lists schedules proposed by doctors that wait for approval (admin).
+// Query params: page (default 1), limit (default 20, max 100)
+func (h *DoctorScheduleHandler) GetPendingProposals(w http.ResponseWriter, r *http.Request) {
+	page, limit, ok := parsePagination(r)
+	if !ok {
+		response.Error(w, http.StatusBadRequest, "Invalid pagination, page and limit must be positive integers", nil)
+		return
+	}
+
+	schedules, total, err := h.scheduleUsecase.GetPendingProposals(r.Context(), page, limit)
+	if err != nil {
+		response.InternalServerError(w, "Failed to get schedule proposals")
+		return
+	}
+
+	response.SuccessWithMeta(w, http.StatusOK, "Schedule proposals retrieved successfully", schedules, newPaginationMeta(page, limit, total))
+}
+
+// ApproveSchedule approves a doctor's schedule proposal, making it bookable (admin)
+func (h *DoctorScheduleHandler) ApproveSchedule(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	scheduleID, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid schedule ID", nil)
+		return
+	}
+
+	schedule, err := h.scheduleUsecase.ApproveSchedule(r.Context(), scheduleID)
+	if err != nil {
+		writeReviewScheduleError(w, err, "Failed to approve schedule")
+		return
+	}
+
+	response.Success(w, http.StatusOK, "Schedule approved successfully", schedule)
+}
+
+// RejectSchedule declines a doctor's schedule proposal with a reason (admin)
+func (h *DoctorScheduleHandler) RejectSchedule(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	scheduleID, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid schedule ID", nil)
+		return
+	}
+
+	var req dto.RejectScheduleRequest
+	if err := h.validator.DecodeJSON(r.Body, &req); err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid request body", h.validator.FormatDecodeError(err))
+		return
+	}
+
+	if err := h.validator.Validate(&req); err != nil {
+		response.ValidationError(w, h.validator.FormatValidationErrors(err))
+		return
+	}
+
+	schedule, err := h.scheduleUsecase.RejectSchedule(r.Context(), scheduleID, &req)
+	if err != nil {
+		writeReviewScheduleError(w, err, "Failed to reject schedule")
+		return
+	}
+
+	response.Success(w, http.StatusOK, "Schedule rejected successfully", schedule)
+}
+
+// writeReviewScheduleError maps the errors of approving or rejecting a proposal
+func writeReviewScheduleError(w http.ResponseWriter, err error, failureMessage string) {
+	switch err {
+	case usecase.ErrScheduleNotFound:
+		response.NotFound(w, "Schedule not found")
+	case usecase.ErrScheduleNotPending:
+		response.Error(w, http.StatusConflict, "Schedule is not pending approval", nil)
+	case usecase.ErrProposalInPast:
+		response.Error(w, http.StatusConflict, "Proposed schedule date has passed, reject it instead", nil)
+	case usecase.ErrScheduleOnHoliday:
+		response.Error(w, http.StatusConflict, "Schedule date is a clinic holiday", nil)
+	case usecase.ErrScheduleOverlaps:
+		response.Error(w, http.StatusConflict, "Schedule overlaps another schedule of the doctor", nil)
+	default:
+		response.InternalServerError(w, failureMessage)
+	}
+}
+
+// ReassignBookings moves a schedule's bookings to another schedule of the same doctor (or cancels them)
+func (h *DoctorScheduleHandler) ReassignBookings(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	scheduleID, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid schedule ID", nil)
+		return
+	}
+
+	var req dto.ReassignBookingsRequest
+	if err := h.validator.DecodeJSON(r.Body, &req); err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid request body", h.validator.FormatDecodeError(err))
+		return
+	}
+
+	if err := h.validator.Validate(&req); err != nil {
+		response.ValidationError(w, h.validator.FormatValidationErrors(err))
+		return
+	}
+
+	result, err := h.scheduleUsecase.ReassignBookings(r.Context(), scheduleID, &req)
+	if err != nil {
+		switch err {
+		case usecase.ErrScheduleNotFound:
+			response.NotFound(w, "Schedule not found")
+		case usecase.ErrInvalidTargetSchedule:
+			response.Error(w, http.StatusBadRequest, "Target schedule must be a different, upcoming schedule of the same doctor", nil)
+		case usecase.ErrTargetScheduleFull:
+			response.Error(w, http.StatusConflict, "Target schedule does not have enough remaining quota", nil)
+		case usecase.ErrBookingVersionConflict:
+			response.Error(w, http.StatusConflict, "Bookings were modified concurrently, reload and try again", nil)
+		default:
+			response.InternalServerError(w, "Failed to reassign bookings")
+		}
+		return
+	}
+
+	response.Success(w, http.StatusOK, "Bookings reassigned successfully", result)
+}
+
+// CopySchedules duplicates a doctor's schedules from one week to another (admin)
+func (h *DoctorScheduleHandler) CopySchedules(w http.ResponseWriter, r *http.Request) {
+	var req dto.CopySchedulesRequest
+	if err := h.validator.DecodeJSON(r.Body, &req); err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid request body", h.validator.FormatDecodeError(err))
+		return
+	}
+
+	if err := h.validator.Validate(&req); err != nil {
+		response.ValidationError(w, h.validator.FormatValidationErrors(err))
+		return
+	}
+
+	result, err := h.scheduleUsecase.CopySchedules(r.Context(), &req)
+	if err != nil {
+		switch err {
+		case usecase.ErrInvalidScheduleDate:
+			response.Error(w, http.StatusBadRequest, "Invalid week date format, use YYYY-MM-DD", nil)
+		case usecase.ErrSameCopyWeek:
+			response.Error(w, http.StatusBadRequest, "Source and target week must be different", nil)
+		default:
+			response.InternalServerError(w, "Failed to copy schedules")
+		}
+		return
+	}
+
+	response.Success(w, http.StatusCreated, "Schedules copied successfully", result)
+}
+
+// BulkUpdateQuota changes the quota of a doctor's upcoming schedules in one operation (admin)
+func (h *DoctorScheduleHandler) BulkUpdateQuota(w http.ResponseWriter, r *http.Request) {
+	var req dto.BulkUpdateQuotaRequest
+	if err := h.validator.DecodeJSON(r.Body, &req); err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid request body", h.validator.FormatDecodeError(err))
+		return
+	}
+
+	if err := h.validator.Validate(&req); err != nil {
+		response.ValidationError(w, h.validator.FormatValidationErrors(err))
+		return
+	}
+
+	result, err := h.scheduleUsecase.BulkUpdateQuota(r.Context(), &req)
+	if err != nil {
+		switch err {
+		case usecase.ErrInvalidQuotaDateRange:
+			response.Error(w, http.StatusBadRequest, "Invalid date range, use YYYY-MM-DD with start_date not after end_date", nil)
+		default:
+			response.InternalServerError(w, "Failed to update schedule quotas")
+		}
+		return
+	}
+
+	response.Success(w, http.StatusOK, "Schedule quotas updated successfully", result)
+}
+
+// CallNext calls the next waiting queue number of the doctor's schedule
+func (h *DoctorScheduleHandler) CallNext(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	scheduleID, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid schedule ID", nil)
+		return
+	}
+
+	booking, err := h.scheduleUsecase.CallNext(r.Context(), scheduleID)
+	if err != nil {
+		switch err {
+		case usecase.ErrScheduleNotFound:
+			response.NotFound(w, "Schedule not found")
+		case usecase.ErrScheduleNotOwned:
+			response.Forbidden(w, "Schedule does not belong to you")
+		case usecase.ErrCallNotToday:
+			response.Error(w, http.StatusBadRequest, "Queue can only be called on the schedule date", nil)
+		case usecase.ErrQueueEmpty:
+			response.NotFound(w, "No more patients waiting in the queue")
+		case usecase.ErrQueueCallConflict:
+			response.Error(w, http.StatusConflict, "Queue was updated concurrently, try again", nil)
+		default:
+			response.InternalServerError(w, "Failed to call next patient")
+		}
+		return
+	}
+
+	response.Success(w, http.StatusOK, "Next patient called successfully", booking)
+}
+
+// GetMyScheduleSummary returns booking counts and the currently served queue number of the doctor's own schedule
+func (h *DoctorScheduleHandler) GetMyScheduleSummary(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	scheduleID, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid schedule ID", nil)
+		return
+	}
+
+	summary, err := h.scheduleUsecase.GetMyScheduleSummary(r.Context(), scheduleID)
+	if err != nil {
+		switch err {
+		case usecase.ErrScheduleNotFound:
+			response.NotFound(w, "Schedule not found")
+		case usecase.ErrScheduleNotOwned:
+			response.Forbidden(w, "Schedule does not belong to you")
+		default:
+			response.InternalServerError(w, "Failed to get schedule summary")
+		}
+		return
+	}
+
+	response.Success(w, http.StatusOK, "Schedule summary retrieved successfully", summary)
+}
+
+// GetDoctorAvailability returns a doctor's bookable days of one month (public).
+// Query params: month (YYYY-MM, required)
+func (h *DoctorScheduleHandler) GetDoctorAvailability(w http.ResponseWriter, r *http.Request) {
+	doctorID, err := uuid.Parse(mux.Vars(r)["doctorId"])
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid doctor ID", nil)
+		return
+	}
+
+	availability, err := h.scheduleUsecase.GetDoctorAvailability(r.Context(), doctorID, r.URL.Query().Get("month"))
+	if err != nil {
+		switch err {
+		case usecase.ErrInvalidAvailabilityMonth:
+			response.Error(w, http.StatusBadRequest, "Invalid month format, use YYYY-MM", nil)
+		default:
+			response.InternalServerError(w, "Failed to get doctor availability")
+		}
+		return
+	}
+
+	response.Success(w, http.StatusOK, "Doctor availability retrieved successfully", availability)
+}
+
+// GetNextAvailable returns the earliest schedule of a doctor with remaining quota (public)
+func (h *DoctorScheduleHandler) GetNextAvailable(w http.ResponseWriter, r *http.Request) {
+	doctorID, err := uuid.Parse(mux.Vars(r)["doctorId"])
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid doctor ID", nil)
+		return
+	}
+
+	next, err := h.scheduleUsecase.GetNextAvailable(r.Context(), doctorID)
+	if err != nil {
+		response.InternalServerError(w, "Failed to get next available schedule")
+		return
+	}
+
+	response.Success(w, http.StatusOK, "Next available schedule retrieved successfully", next)
+}
+
+// GetScheduleSlots lists the appointment slots of a time-slot schedule with their availability
+func (h *DoctorScheduleHandler) GetScheduleSlots(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	scheduleID, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid schedule ID", nil)
+		return
+	}
+
+	slots, err := h.scheduleUsecase.GetScheduleSlots(r.Context(), scheduleID)
+	if err != nil {
+		switch err {
+		case usecase.ErrScheduleNotFound:
+			response.NotFound(w, "Schedule not found")
+		case usecase.ErrNotSlotSchedule:
+			response.Error(w, http.StatusBadRequest, "Schedule does not use time-slot booking", nil)
+		default:
+			response.InternalServerError(w, "Failed to get schedule slots")
+		}
+		return
+	}
+
+	response.Success(w, http.StatusOK, "Schedule slots retrieved successfully", slots)
+}
@@ -0,0 +1,146 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"go-template-clean-architecture/internal/delivery/dto"
+	"go-template-clean-architecture/internal/usecase"
+	"go-template-clean-architecture/pkg/response"
+	"go-template-clean-architecture/pkg/validator"
+
+	"github.com/gorilla/mux"
+)
+
+type HolidayHandler struct {
+	holidayUsecase usecase.HolidayUsecase
+	validator      *validator.CustomValidator
+}
+
+func NewHolidayHandler(holidayUsecase usecase.HolidayUsecase, validator *validator.CustomValidator) *HolidayHandler {
+	return &HolidayHandler{
+		holidayUsecase: holidayUsecase,
+		validator:      validator,
+	}
+}
+
+func (h *HolidayHandler) CreateHoliday(w http.ResponseWriter, r *http.Request) {
+	var req dto.CreateHolidayRequest
+	if err := h.validator.DecodeJSON(r.Body, &req); err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid request body", h.validator.FormatDecodeError(err))
+		return
+	}
+
+	if err := h.validator.Validate(&req); err != nil {
+		response.ValidationError(w, h.validator.FormatValidationErrors(err))
+		return
+	}
+
+	holiday, err := h.holidayUsecase.CreateHoliday(r.Context(), &req)
+	if err != nil {
+		switch err {
+		case usecase.ErrInvalidHolidayDate:
+			response.Error(w, http.StatusBadRequest, "Invalid holiday date format, use YYYY-MM-DD", nil)
+		case usecase.ErrHolidayExists:
+			response.Error(w, http.StatusConflict, "A holiday already exists on this date", nil)
+		default:
+			response.InternalServerError(w, "Failed to create holiday")
+		}
+		return
+	}
+
+	response.Success(w, http.StatusCreated, "Holiday created successfully", holiday)
+}
+
+// GetAllHolidays lists holidays. Optional query params: from, to (YYYY-MM-DD)
+func (h *HolidayHandler) GetAllHolidays(w http.ResponseWriter, r *http.Request) {
+	holidays, err := h.holidayUsecase.GetAllHolidays(r.Context(), r.URL.Query().Get("from"), r.URL.Query().Get("to"))
+	if err != nil {
+		if err == usecase.ErrInvalidHolidayDate {
+			response.Error(w, http.StatusBadRequest, "Invalid date format, use YYYY-MM-DD", nil)
+			return
+		}
+		response.InternalServerError(w, "Failed to get holidays")
+		return
+	}
+
+	response.Success(w, http.StatusOK, "Holidays retrieved successfully", holidays)
+}
+
+func (h *HolidayHandler) GetHoliday(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	holidayID, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid holiday ID", nil)
+		return
+	}
+
+	holiday, err := h.holidayUsecase.GetHoliday(r.Context(), holidayID)
+	if err != nil {
+		if err == usecase.ErrHolidayNotFound {
+			response.NotFound(w, "Holiday not found")
+			return
+		}
+		response.InternalServerError(w, "Failed to get holiday")
+		return
+	}
+
+	response.Success(w, http.StatusOK, "Holiday retrieved successfully", holiday)
+}
+
+func (h *HolidayHandler) UpdateHoliday(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	holidayID, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid holiday ID", nil)
+		return
+	}
+
+	var req dto.UpdateHolidayRequest
+	if err := h.validator.DecodeJSON(r.Body, &req); err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid request body", h.validator.FormatDecodeError(err))
+		return
+	}
+
+	if err := h.validator.Validate(&req); err != nil {
+		response.ValidationError(w, h.validator.FormatValidationErrors(err))
+		return
+	}
+
+	holiday, err := h.holidayUsecase.UpdateHoliday(r.Context(), holidayID, &req)
+	if err != nil {
+		switch err {
+		case usecase.ErrHolidayNotFound:
+			response.NotFound(w, "Holiday not found")
+		case usecase.ErrInvalidHolidayDate:
+			response.Error(w, http.StatusBadRequest, "Invalid holiday date format, use YYYY-MM-DD", nil)
+		case usecase.ErrHolidayExists:
+			response.Error(w, http.StatusConflict, "A holiday already exists on this date", nil)
+		default:
+			response.InternalServerError(w, "Failed to update holiday")
+		}
+		return
+	}
+
+	response.Success(w, http.StatusOK, "Holiday updated successfully", holiday)
+}
+
+func (h *HolidayHandler) DeleteHoliday(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	holidayID, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid holiday ID", nil)
+		return
+	}
+
+	if err := h.holidayUsecase.DeleteHoliday(r.Context(), holidayID); err != nil {
+		if err == usecase.ErrHolidayNotFound {
+			response.NotFound(w, "Holiday not found")
+			return
+		}
+		response.InternalServerError(w, "Failed to delete holiday")
+		return
+	}
+
+	response.Success(w, http.StatusOK, "Holiday deleted successfully", nil)
+}
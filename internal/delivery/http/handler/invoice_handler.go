@@ -0,0 +1,167 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+
+	"go-template-clean-architecture/internal/delivery/dto"
+	"go-template-clean-architecture/internal/usecase"
+	"go-template-clean-architecture/pkg/response"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+type InvoiceHandler struct {
+	invoiceUsecase usecase.InvoiceUsecase
+}
+
+func NewInvoiceHandler(invoiceUsecase usecase.InvoiceUsecase) *InvoiceHandler {
+	return &InvoiceHandler{
+		invoiceUsecase: invoiceUsecase,
+	}
+}
+
+// GetMyBookingInvoice returns the invoice of the logged-in patient's booking
+func (h *InvoiceHandler) GetMyBookingInvoice(w http.ResponseWriter, r *http.Request) {
+	bookingID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid booking ID", nil)
+		return
+	}
+
+	invoice, err := h.invoiceUsecase.GetMyBookingInvoice(r.Context(), bookingID)
+	if err != nil {
+		h.writeMyBookingError(w, err)
+		return
+	}
+
+	response.Success(w, http.StatusOK, "Invoice retrieved successfully", invoice)
+}
+
+// GetMyBookingInvoicePDF downloads the invoice of the logged-in patient's booking as PDF
+func (h *InvoiceHandler) GetMyBookingInvoicePDF(w http.ResponseWriter, r *http.Request) {
+	bookingID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid booking ID", nil)
+		return
+	}
+
+	document, number, err := h.invoiceUsecase.GetMyBookingInvoicePDF(r.Context(), bookingID)
+	if err != nil {
+		h.writeMyBookingError(w, err)
+		return
+	}
+
+	writePDF(w, document, number)
+}
+
+// GetInvoices lists invoices newest first (admin finance view).
+// Optional query params: status, doctor_id, patient_id, from, to (YYYY-MM-DD), page, limit
+func (h *InvoiceHandler) GetInvoices(w http.ResponseWriter, r *http.Request) {
+	page, limit, ok := parsePagination(r)
+	if !ok {
+		response.Error(w, http.StatusBadRequest, "Invalid pagination, page and limit must be positive integers", nil)
+		return
+	}
+
+	invoices, total, err := h.invoiceUsecase.GetInvoices(r.Context(), invoiceFilter(r), page, limit)
+	if err != nil {
+		if err == usecase.ErrInvalidInvoiceFilter {
+			response.Error(w, http.StatusBadRequest, "Invalid filter, use status paid, issued or refunded, UUIDs and dates as YYYY-MM-DD", nil)
+			return
+		}
+		response.InternalServerError(w, "Failed to get invoices")
+		return
+	}
+
+	response.SuccessWithMeta(w, http.StatusOK, "Invoices retrieved successfully", invoices, newPaginationMeta(page, limit, total))
+}
+
+// GetInvoice returns any invoice (admin)
+func (h *InvoiceHandler) GetInvoice(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid invoice ID", nil)
+		return
+	}
+
+	invoice, err := h.invoiceUsecase.GetInvoice(r.Context(), id)
+	if err != nil {
+		if err == usecase.ErrInvoiceNotFound {
+			response.NotFound(w, "Invoice not found")
+			return
+		}
+		response.InternalServerError(w, "Failed to get invoice")
+		return
+	}
+
+	response.Success(w, http.StatusOK, "Invoice retrieved successfully", invoice)
+}
+
+// GetInvoicePDF downloads any invoice as PDF (admin)
+func (h *InvoiceHandler) GetInvoicePDF(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid invoice ID", nil)
+		return
+	}
+
+	document, number, err := h.invoiceUsecase.GetInvoicePDF(r.Context(), id)
+	if err != nil {
+		if err == usecase.ErrInvoiceNotFound {
+			response.NotFound(w, "Invoice not found")
+			return
+		}
+		response.InternalServerError(w, "Failed to get invoice")
+		return
+	}
+
+	writePDF(w, document, number)
+}
+
+// GetFinanceReport totals the invoices per currency and status (admin finance view).
+// Optional query params: status, doctor_id, patient_id, from, to (YYYY-MM-DD)
+func (h *InvoiceHandler) GetFinanceReport(w http.ResponseWriter, r *http.Request) {
+	report, err := h.invoiceUsecase.GetFinanceReport(r.Context(), invoiceFilter(r))
+	if err != nil {
+		if err == usecase.ErrInvalidInvoiceFilter {
+			response.Error(w, http.StatusBadRequest, "Invalid filter, use status paid, issued or refunded, UUIDs and dates as YYYY-MM-DD", nil)
+			return
+		}
+		response.InternalServerError(w, "Failed to get finance report")
+		return
+	}
+
+	response.Success(w, http.StatusOK, "Finance report retrieved successfully", report)
+}
+
+func (h *InvoiceHandler) writeMyBookingError(w http.ResponseWriter, err error) {
+	switch err {
+	case usecase.ErrInvoiceNotFound:
+		response.NotFound(w, "Booking has no invoice yet")
+	case usecase.ErrBookingNotOwned:
+		response.Forbidden(w, "Booking does not belong to you")
+	default:
+		response.InternalServerError(w, "Failed to get invoice")
+	}
+}
+
+func invoiceFilter(r *http.Request) *dto.InvoiceFilter {
+	query := r.URL.Query()
+	return &dto.InvoiceFilter{
+		Status:    query.Get("status"),
+		DoctorID:  query.Get("doctor_id"),
+		PatientID: query.Get("patient_id"),
+		From:      query.Get("from"),
+		To:        query.Get("to"),
+	}
+}
+
+func writePDF(w http.ResponseWriter, document []byte, number string) {
+	w.Header().Set("Content-Type", "application/pdf")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`inline; filename="%s.pdf"`, number))
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(http.StatusOK)
+	w.Write(document)
+}
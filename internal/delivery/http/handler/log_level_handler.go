@@ -0,0 +1,60 @@
+package handler
+
+import (
+	"net/http"
+
+	"go-template-clean-architecture/internal/delivery/dto"
+	"go-template-clean-architecture/internal/usecase"
+	"go-template-clean-architecture/pkg/response"
+	"go-template-clean-architecture/pkg/validator"
+
+	"github.com/gorilla/mux"
+)
+
+type LogLevelHandler struct {
+	logLevelUsecase usecase.LogLevelUsecase
+	validator       *validator.CustomValidator
+}
+
+func NewLogLevelHandler(logLevelUsecase usecase.LogLevelUsecase, validator *validator.CustomValidator) *LogLevelHandler {
+	return &LogLevelHandler{
+		logLevelUsecase: logLevelUsecase,
+		validator:       validator,
+	}
+}
+
+// GetLogLevels returns the default log level and the effective level per package
+func (h *LogLevelHandler) GetLogLevels(w http.ResponseWriter, r *http.Request) {
+	response.Success(w, http.StatusOK, "Log levels retrieved successfully", h.logLevelUsecase.GetLogLevels(r.Context()))
+}
+
+// SetLogLevel changes the log level of a package at runtime ("default" for all packages without an override)
+func (h *LogLevelHandler) SetLogLevel(w http.ResponseWriter, r *http.Request) {
+	pkg := mux.Vars(r)["package"]
+
+	var req dto.SetLogLevelRequest
+	if err := h.validator.DecodeJSON(r.Body, &req); err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid request body", h.validator.FormatDecodeError(err))
+		return
+	}
+
+	if err := h.validator.Validate(&req); err != nil {
+		response.ValidationError(w, h.validator.FormatValidationErrors(err))
+		return
+	}
+
+	levels, err := h.logLevelUsecase.SetLogLevel(r.Context(), pkg, &req)
+	if err != nil {
+		switch err {
+		case usecase.ErrUnknownLogPackage:
+			response.NotFound(w, "Log package not found")
+		case usecase.ErrInvalidLogLevel:
+			response.Error(w, http.StatusBadRequest, "Invalid log level", nil)
+		default:
+			response.InternalServerError(w, "Failed to set log level")
+		}
+		return
+	}
+
+	response.Success(w, http.StatusOK, "Log level updated successfully", levels)
+}
@@ -0,0 +1,185 @@
+package handler
+
+import (
+	"net/http"
+
+	"go-template-clean-architecture/internal/delivery/dto"
+	"go-template-clean-architecture/internal/usecase"
+	"go-template-clean-architecture/pkg/response"
+	"go-template-clean-architecture/pkg/validator"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+type MedicalRecordHandler struct {
+	medicalRecordUsecase usecase.MedicalRecordUsecase
+	validator            *validator.CustomValidator
+}
+
+func NewMedicalRecordHandler(medicalRecordUsecase usecase.MedicalRecordUsecase, validator *validator.CustomValidator) *MedicalRecordHandler {
+	return &MedicalRecordHandler{
+		medicalRecordUsecase: medicalRecordUsecase,
+		validator:            validator,
+	}
+}
+
+// CreateRecord writes the visit notes of a booking of the logged-in doctor (doctor portal)
+func (h *MedicalRecordHandler) CreateRecord(w http.ResponseWriter, r *http.Request) {
+	bookingID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid booking ID", nil)
+		return
+	}
+
+	var req dto.MedicalRecordRequest
+	if err := h.validator.DecodeJSON(r.Body, &req); err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid request body", h.validator.FormatDecodeError(err))
+		return
+	}
+
+	if err := h.validator.Validate(&req); err != nil {
+		response.ValidationError(w, h.validator.FormatValidationErrors(err))
+		return
+	}
+
+	record, err := h.medicalRecordUsecase.CreateRecord(r.Context(), bookingID, &req)
+	if err != nil {
+		switch err {
+		case usecase.ErrBookingNotFound:
+			response.NotFound(w, "Booking not found")
+		case usecase.ErrMedicalRecordVisitNotFound:
+			response.Error(w, http.StatusUnprocessableEntity, err.Error(), nil)
+		case usecase.ErrMedicalRecordExists:
+			response.Error(w, http.StatusConflict, err.Error(), nil)
+		default:
+			response.InternalServerError(w, "Failed to create medical record")
+		}
+		return
+	}
+
+	response.Success(w, http.StatusCreated, "Medical record created successfully", record)
+}
+
+// UpdateRecord replaces the visit notes written by the logged-in doctor (doctor portal)
+func (h *MedicalRecordHandler) UpdateRecord(w http.ResponseWriter, r *http.Request) {
+	bookingID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid booking ID", nil)
+		return
+	}
+
+	var req dto.UpdateMedicalRecordRequest
+	if err := h.validator.DecodeJSON(r.Body, &req); err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid request body", h.validator.FormatDecodeError(err))
+		return
+	}
+
+	if err := h.validator.Validate(&req); err != nil {
+		response.ValidationError(w, h.validator.FormatValidationErrors(err))
+		return
+	}
+
+	record, err := h.medicalRecordUsecase.UpdateRecord(r.Context(), bookingID, &req)
+	if err != nil {
+		switch err {
+		case usecase.ErrMedicalRecordNotFound:
+			response.NotFound(w, "Medical record not found")
+		case usecase.ErrMedicalRecordConflict:
+			response.Error(w, http.StatusConflict, err.Error(), nil)
+		default:
+			response.InternalServerError(w, "Failed to update medical record")
+		}
+		return
+	}
+
+	response.Success(w, http.StatusOK, "Medical record updated successfully", record)
+}
+
+// GetDoctorRecord returns the visit notes of a booking written by the logged-in doctor (doctor portal)
+func (h *MedicalRecordHandler) GetDoctorRecord(w http.ResponseWriter, r *http.Request) {
+	bookingID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid booking ID", nil)
+		return
+	}
+
+	record, err := h.medicalRecordUsecase.GetDoctorRecord(r.Context(), bookingID)
+	if err != nil {
+		if err == usecase.ErrMedicalRecordNotFound {
+			response.NotFound(w, "Medical record not found")
+			return
+		}
+		response.InternalServerError(w, "Failed to get medical record")
+		return
+	}
+
+	response.Success(w, http.StatusOK, "Medical record retrieved successfully", record)
+}
+
+// GetMyRecord returns the visit notes of one of the logged-in patient's bookings (patient portal)
+func (h *MedicalRecordHandler) GetMyRecord(w http.ResponseWriter, r *http.Request) {
+	bookingID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid booking ID", nil)
+		return
+	}
+
+	record, err := h.medicalRecordUsecase.GetMyRecord(r.Context(), bookingID)
+	if err != nil {
+		if err == usecase.ErrMedicalRecordNotFound {
+			response.NotFound(w, "Medical record not found")
+			return
+		}
+		response.InternalServerError(w, "Failed to get medical record")
+		return
+	}
+
+	response.Success(w, http.StatusOK, "Medical record retrieved successfully", record)
+}
+
+// GetMyRecords lists the logged-in patient's medical records, newest first (patient portal).
+// Optional query params: page (default 1), limit (default 20, max 100)
+func (h *MedicalRecordHandler) GetMyRecords(w http.ResponseWriter, r *http.Request) {
+	page, limit, ok := parsePagination(r)
+	if !ok {
+		response.Error(w, http.StatusBadRequest, "Invalid pagination, page and limit must be positive integers", nil)
+		return
+	}
+
+	records, total, err := h.medicalRecordUsecase.GetMyRecords(r.Context(), page, limit)
+	if err != nil {
+		response.InternalServerError(w, "Failed to get medical records")
+		return
+	}
+
+	response.SuccessWithMeta(w, http.StatusOK, "Medical records retrieved successfully", records, newPaginationMeta(page, limit, total))
+}
+
+// GetPatientHistory returns the visit history of one of the logged-in doctor's patients.
+// Query params: page (default 1), limit (default 20, max 100)
+func (h *MedicalRecordHandler) GetPatientHistory(w http.ResponseWriter, r *http.Request) {
+	patientID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid patient ID", nil)
+		return
+	}
+
+	page, limit, ok := parsePagination(r)
+	if !ok {
+		response.Error(w, http.StatusBadRequest, "Invalid pagination, page and limit must be positive integers", nil)
+		return
+	}
+
+	history, total, err := h.medicalRecordUsecase.GetPatientHistory(r.Context(), patientID, page, limit)
+	if err != nil {
+		if err == usecase.ErrPatientHistoryNotFound {
+			response.NotFound(w, "Patient not found")
+			return
+		}
+		response.InternalServerError(w, "Failed to get patient history")
+		return
+	}
+
+	response.SuccessWithMeta(w, http.StatusOK, "Patient history retrieved successfully", history, newPaginationMeta(page, limit, total))
+}
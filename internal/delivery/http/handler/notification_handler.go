@@ -0,0 +1,454 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"go-template-clean-architecture/internal/delivery/dto"
+	"go-template-clean-architecture/internal/delivery/http/middleware"
+	"go-template-clean-architecture/internal/usecase"
+	"go-template-clean-architecture/pkg/response"
+	"go-template-clean-architecture/pkg/validator"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+type NotificationHandler struct {
+	notificationUsecase usecase.NotificationUsecase
+	validator           *validator.CustomValidator
+}
+
+func NewNotificationHandler(notificationUsecase usecase.NotificationUsecase, validator *validator.CustomValidator) *NotificationHandler {
+	return &NotificationHandler{
+		notificationUsecase: notificationUsecase,
+		validator:           validator,
+	}
+}
+
+// DeliveryReport receives delivery callbacks from a notification provider,
+// authenticated with the shared secret in the X-Webhook-Secret header
+func (h *NotificationHandler) DeliveryReport(w http.ResponseWriter, r *http.Request) {
+	provider := mux.Vars(r)["provider"]
+
+	var req dto.NotificationDeliveryReportRequest
+	if err := h.validator.DecodeJSON(r.Body, &req); err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid request body", h.validator.FormatDecodeError(err))
+		return
+	}
+
+	if err := h.validator.Validate(&req); err != nil {
+		response.ValidationError(w, h.validator.FormatValidationErrors(err))
+		return
+	}
+
+	err := h.notificationUsecase.HandleDeliveryReport(r.Context(), provider, r.Header.Get("X-Webhook-Secret"), &req)
+	if err != nil {
+		switch err {
+		case usecase.ErrInvalidWebhookSecret:
+			response.Unauthorized(w, "Invalid webhook secret")
+		case usecase.ErrNotificationNotFound:
+			response.NotFound(w, "Notification not found")
+		default:
+			response.InternalServerError(w, "Failed to record delivery report")
+		}
+		return
+	}
+
+	response.Success(w, http.StatusOK, "Delivery report recorded successfully", nil)
+}
+
+// GetBookingNotifications returns the notification history of any booking (admin)
+func (h *NotificationHandler) GetBookingNotifications(w http.ResponseWriter, r *http.Request) {
+	bookingID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid booking ID", nil)
+		return
+	}
+
+	notifications, err := h.notificationUsecase.GetBookingNotifications(r.Context(), bookingID)
+	if err != nil {
+		if err == usecase.ErrBookingNotFound {
+			response.NotFound(w, "Booking not found")
+			return
+		}
+		response.InternalServerError(w, "Failed to get notifications")
+		return
+	}
+
+	response.Success(w, http.StatusOK, "Notifications retrieved successfully", notifications)
+}
+
+// GetMyBookingNotifications returns the notification history of the logged-in patient's booking
+func (h *NotificationHandler) GetMyBookingNotifications(w http.ResponseWriter, r *http.Request) {
+	bookingID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid booking ID", nil)
+		return
+	}
+
+	notifications, err := h.notificationUsecase.GetMyBookingNotifications(r.Context(), bookingID)
+	if err != nil {
+		switch err {
+		case usecase.ErrBookingNotFound:
+			response.NotFound(w, "Booking not found")
+		case usecase.ErrBookingNotOwned:
+			response.Forbidden(w, "Booking does not belong to you")
+		default:
+			response.InternalServerError(w, "Failed to get notifications")
+		}
+		return
+	}
+
+	response.Success(w, http.StatusOK, "Notifications retrieved successfully", notifications)
+}
+
+// RegisterDevice registers the push token of the logged-in patient's mobile client
+func (h *NotificationHandler) RegisterDevice(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		response.Unauthorized(w, "Unauthorized")
+		return
+	}
+
+	var req dto.RegisterDeviceRequest
+	if err := h.validator.DecodeJSON(r.Body, &req); err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid request body", h.validator.FormatDecodeError(err))
+		return
+	}
+
+	if err := h.validator.Validate(&req); err != nil {
+		response.ValidationError(w, h.validator.FormatValidationErrors(err))
+		return
+	}
+
+	device, err := h.notificationUsecase.RegisterDevice(r.Context(), userID, &req)
+	if err != nil {
+		response.InternalServerError(w, "Failed to register device")
+		return
+	}
+
+	response.Success(w, http.StatusOK, "Device registered successfully", device)
+}
+
+// GetMyDevices lists the push devices of the logged-in patient
+func (h *NotificationHandler) GetMyDevices(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		response.Unauthorized(w, "Unauthorized")
+		return
+	}
+
+	devices, err := h.notificationUsecase.GetDevices(r.Context(), userID)
+	if err != nil {
+		response.InternalServerError(w, "Failed to get devices")
+		return
+	}
+
+	response.Success(w, http.StatusOK, "Devices retrieved successfully", devices)
+}
+
+// UnregisterDevice stops pushes to a device of the logged-in patient
+func (h *NotificationHandler) UnregisterDevice(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		response.Unauthorized(w, "Unauthorized")
+		return
+	}
+
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid device ID", nil)
+		return
+	}
+
+	if err := h.notificationUsecase.UnregisterDevice(r.Context(), userID, id); err != nil {
+		if err == usecase.ErrDeviceNotFound {
+			response.NotFound(w, "Device not found")
+			return
+		}
+		response.InternalServerError(w, "Failed to unregister device")
+		return
+	}
+
+	response.Success(w, http.StatusOK, "Device unregistered successfully", nil)
+}
+
+// GetMyNotifications lists the logged-in patient's notification center, newest first.
+// Optional query params: unread=true, page, limit
+func (h *NotificationHandler) GetMyNotifications(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		response.Unauthorized(w, "Unauthorized")
+		return
+	}
+
+	page, limit, ok := parsePagination(r)
+	if !ok {
+		response.Error(w, http.StatusBadRequest, "Invalid pagination, page and limit must be positive integers", nil)
+		return
+	}
+	unreadOnly := r.URL.Query().Get("unread") == "true"
+
+	notifications, total, err := h.notificationUsecase.GetInAppNotifications(r.Context(), userID, unreadOnly, page, limit)
+	if err != nil {
+		response.InternalServerError(w, "Failed to get notifications")
+		return
+	}
+
+	response.SuccessWithMeta(w, http.StatusOK, "Notifications retrieved successfully", notifications, newPaginationMeta(page, limit, total))
+}
+
+// GetMyUnreadCount returns the number of unread notifications of the logged-in patient
+func (h *NotificationHandler) GetMyUnreadCount(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		response.Unauthorized(w, "Unauthorized")
+		return
+	}
+
+	count, err := h.notificationUsecase.GetUnreadCount(r.Context(), userID)
+	if err != nil {
+		response.InternalServerError(w, "Failed to count unread notifications")
+		return
+	}
+
+	response.Success(w, http.StatusOK, "Unread notifications counted successfully", count)
+}
+
+// MarkNotificationRead marks a notification of the logged-in patient read
+func (h *NotificationHandler) MarkNotificationRead(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		response.Unauthorized(w, "Unauthorized")
+		return
+	}
+
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid notification ID", nil)
+		return
+	}
+
+	if err := h.notificationUsecase.MarkRead(r.Context(), userID, id); err != nil {
+		if err == usecase.ErrNotificationNotFound {
+			response.NotFound(w, "Notification not found")
+			return
+		}
+		response.InternalServerError(w, "Failed to mark notification read")
+		return
+	}
+
+	response.Success(w, http.StatusOK, "Notification marked read", nil)
+}
+
+// MarkAllNotificationsRead marks every notification of the logged-in patient read
+func (h *NotificationHandler) MarkAllNotificationsRead(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		response.Unauthorized(w, "Unauthorized")
+		return
+	}
+
+	count, err := h.notificationUsecase.MarkAllRead(r.Context(), userID)
+	if err != nil {
+		response.InternalServerError(w, "Failed to mark notifications read")
+		return
+	}
+
+	response.Success(w, http.StatusOK, "Notifications marked read", count)
+}
+
+// DeleteMyNotification removes a notification from the logged-in patient's notification center
+func (h *NotificationHandler) DeleteMyNotification(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		response.Unauthorized(w, "Unauthorized")
+		return
+	}
+
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid notification ID", nil)
+		return
+	}
+
+	if err := h.notificationUsecase.DeleteInAppNotification(r.Context(), userID, id); err != nil {
+		if err == usecase.ErrNotificationNotFound {
+			response.NotFound(w, "Notification not found")
+			return
+		}
+		response.InternalServerError(w, "Failed to delete notification")
+		return
+	}
+
+	response.Success(w, http.StatusOK, "Notification deleted successfully", nil)
+}
+
+// GetMyNotificationPreference returns how the logged-in patient or doctor wants to be notified
+func (h *NotificationHandler) GetMyNotificationPreference(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		response.Unauthorized(w, "Unauthorized")
+		return
+	}
+
+	preference, err := h.notificationUsecase.GetPreference(r.Context(), userID)
+	if err != nil {
+		response.InternalServerError(w, "Failed to get notification preferences")
+		return
+	}
+
+	response.Success(w, http.StatusOK, "Notification preferences retrieved successfully", preference)
+}
+
+// UpdateMyNotificationPreference changes the notification preferences of the logged-in patient or doctor
+func (h *NotificationHandler) UpdateMyNotificationPreference(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		response.Unauthorized(w, "Unauthorized")
+		return
+	}
+
+	var req dto.UpdateNotificationPreferenceRequest
+	if err := h.validator.DecodeJSON(r.Body, &req); err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid request body", h.validator.FormatDecodeError(err))
+		return
+	}
+
+	if err := h.validator.Validate(&req); err != nil {
+		response.ValidationError(w, h.validator.FormatValidationErrors(err))
+		return
+	}
+
+	preference, err := h.notificationUsecase.UpdatePreference(r.Context(), userID, &req)
+	if err != nil {
+		response.InternalServerError(w, "Failed to update notification preferences")
+		return
+	}
+
+	response.Success(w, http.StatusOK, "Notification preferences updated successfully", preference)
+}
+
+// GetDeadLetters lists the notifications that were rejected or ran out of attempts, most recently failed first.
+// Optional query params: channel, event_type, page, limit
+func (h *NotificationHandler) GetDeadLetters(w http.ResponseWriter, r *http.Request) {
+	page, limit, ok := parsePagination(r)
+	if !ok {
+		response.Error(w, http.StatusBadRequest, "Invalid pagination, page and limit must be positive integers", nil)
+		return
+	}
+
+	query := r.URL.Query()
+	notifications, total, err := h.notificationUsecase.GetDeadLetters(r.Context(), query.Get("channel"), query.Get("event_type"), page, limit)
+	if err != nil {
+		response.InternalServerError(w, "Failed to get dead-lettered notifications")
+		return
+	}
+
+	response.SuccessWithMeta(w, http.StatusOK, "Dead-lettered notifications retrieved successfully", notifications, newPaginationMeta(page, limit, total))
+}
+
+// GetPatientNotifications lists a patient's notifications on every channel, newest first (admin support).
+// Optional query params: status, event_type, channel, from, to (YYYY-MM-DD), page, limit
+func (h *NotificationHandler) GetPatientNotifications(w http.ResponseWriter, r *http.Request) {
+	patientID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid patient ID", nil)
+		return
+	}
+
+	page, limit, ok := parsePagination(r)
+	if !ok {
+		response.Error(w, http.StatusBadRequest, "Invalid pagination, page and limit must be positive integers", nil)
+		return
+	}
+
+	query := r.URL.Query()
+	filter := &dto.NotificationFilter{
+		Status:    query.Get("status"),
+		EventType: query.Get("event_type"),
+		Channel:   query.Get("channel"),
+		From:      query.Get("from"),
+		To:        query.Get("to"),
+	}
+
+	notifications, total, err := h.notificationUsecase.GetPatientNotifications(r.Context(), patientID, filter, page, limit)
+	if err != nil {
+		if err == usecase.ErrInvalidNotificationFilter {
+			response.Error(w, http.StatusBadRequest, "Invalid filter, use a known status and dates as YYYY-MM-DD", nil)
+			return
+		}
+		response.InternalServerError(w, "Failed to get notifications")
+		return
+	}
+
+	response.SuccessWithMeta(w, http.StatusOK, "Notifications retrieved successfully", notifications, newPaginationMeta(page, limit, total))
+}
+
+// GetNotification returns a notification with its send attempts and provider callbacks (admin)
+func (h *NotificationHandler) GetNotification(w http.ResponseWriter, r *http.Request) {
+	notificationID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid notification ID", nil)
+		return
+	}
+
+	notification, err := h.notificationUsecase.GetNotification(r.Context(), notificationID)
+	if err != nil {
+		if err == usecase.ErrNotificationNotFound {
+			response.NotFound(w, "Notification not found")
+			return
+		}
+		response.InternalServerError(w, "Failed to get notification")
+		return
+	}
+
+	response.Success(w, http.StatusOK, "Notification retrieved successfully", notification)
+}
+
+// RedriveNotification sends a dead-lettered notification again
+func (h *NotificationHandler) RedriveNotification(w http.ResponseWriter, r *http.Request) {
+	notificationID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid notification ID", nil)
+		return
+	}
+
+	notification, err := h.notificationUsecase.RedriveNotification(r.Context(), notificationID)
+	if err != nil {
+		switch err {
+		case usecase.ErrNotificationNotFound:
+			response.NotFound(w, "Notification not found")
+		case usecase.ErrNotDeadLetter:
+			response.Error(w, http.StatusConflict, "Notification is not a dead letter", nil)
+		default:
+			response.InternalServerError(w, "Failed to redrive notification")
+		}
+		return
+	}
+
+	response.Success(w, http.StatusOK, "Notification queued for redelivery", notification)
+}
+
+// RedriveNotifications sends the most recently failed dead letters matching the filters again
+func (h *NotificationHandler) RedriveNotifications(w http.ResponseWriter, r *http.Request) {
+	var req dto.RedriveNotificationsRequest
+	if err := h.validator.DecodeJSON(r.Body, &req); err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid request body", h.validator.FormatDecodeError(err))
+		return
+	}
+
+	if err := h.validator.Validate(&req); err != nil {
+		response.ValidationError(w, h.validator.FormatValidationErrors(err))
+		return
+	}
+
+	result, err := h.notificationUsecase.RedriveNotifications(r.Context(), &req)
+	if err != nil {
+		response.InternalServerError(w, "Failed to redrive notifications")
+		return
+	}
+
+	response.Success(w, http.StatusOK, "Notifications queued for redelivery", result)
+}
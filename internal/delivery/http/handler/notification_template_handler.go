@@ -0,0 +1,166 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"go-template-clean-architecture/internal/delivery/dto"
+	"go-template-clean-architecture/internal/usecase"
+	"go-template-clean-architecture/pkg/response"
+	"go-template-clean-architecture/pkg/validator"
+
+	"github.com/gorilla/mux"
+)
+
+type NotificationTemplateHandler struct {
+	templateUsecase usecase.NotificationTemplateUsecase
+	validator       *validator.CustomValidator
+}
+
+func NewNotificationTemplateHandler(templateUsecase usecase.NotificationTemplateUsecase, validator *validator.CustomValidator) *NotificationTemplateHandler {
+	return &NotificationTemplateHandler{
+		templateUsecase: templateUsecase,
+		validator:       validator,
+	}
+}
+
+func (h *NotificationTemplateHandler) CreateTemplate(w http.ResponseWriter, r *http.Request) {
+	var req dto.CreateNotificationTemplateRequest
+	if err := h.validator.DecodeJSON(r.Body, &req); err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid request body", h.validator.FormatDecodeError(err))
+		return
+	}
+
+	if err := h.validator.Validate(&req); err != nil {
+		response.ValidationError(w, h.validator.FormatValidationErrors(err))
+		return
+	}
+
+	template, err := h.templateUsecase.CreateTemplate(r.Context(), &req)
+	if err != nil {
+		switch err {
+		case usecase.ErrInvalidNotificationTemplate:
+			response.Error(w, http.StatusBadRequest, "Invalid template syntax", nil)
+		case usecase.ErrNotificationTemplateExists:
+			response.Error(w, http.StatusConflict, "A template already exists for this event type, channel and locale", nil)
+		default:
+			response.InternalServerError(w, "Failed to create notification template")
+		}
+		return
+	}
+
+	response.Success(w, http.StatusCreated, "Notification template created successfully", template)
+}
+
+// GetAllTemplates lists notification templates. Optional query param: event_type
+func (h *NotificationTemplateHandler) GetAllTemplates(w http.ResponseWriter, r *http.Request) {
+	templates, err := h.templateUsecase.GetAllTemplates(r.Context(), r.URL.Query().Get("event_type"))
+	if err != nil {
+		response.InternalServerError(w, "Failed to get notification templates")
+		return
+	}
+
+	response.Success(w, http.StatusOK, "Notification templates retrieved successfully", templates)
+}
+
+func (h *NotificationTemplateHandler) GetTemplate(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	templateID, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid notification template ID", nil)
+		return
+	}
+
+	template, err := h.templateUsecase.GetTemplate(r.Context(), templateID)
+	if err != nil {
+		if err == usecase.ErrNotificationTemplateNotFound {
+			response.NotFound(w, "Notification template not found")
+			return
+		}
+		response.InternalServerError(w, "Failed to get notification template")
+		return
+	}
+
+	response.Success(w, http.StatusOK, "Notification template retrieved successfully", template)
+}
+
+func (h *NotificationTemplateHandler) UpdateTemplate(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	templateID, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid notification template ID", nil)
+		return
+	}
+
+	var req dto.UpdateNotificationTemplateRequest
+	if err := h.validator.DecodeJSON(r.Body, &req); err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid request body", h.validator.FormatDecodeError(err))
+		return
+	}
+
+	if err := h.validator.Validate(&req); err != nil {
+		response.ValidationError(w, h.validator.FormatValidationErrors(err))
+		return
+	}
+
+	template, err := h.templateUsecase.UpdateTemplate(r.Context(), templateID, &req)
+	if err != nil {
+		switch err {
+		case usecase.ErrNotificationTemplateNotFound:
+			response.NotFound(w, "Notification template not found")
+		case usecase.ErrInvalidNotificationTemplate:
+			response.Error(w, http.StatusBadRequest, "Invalid template syntax", nil)
+		default:
+			response.InternalServerError(w, "Failed to update notification template")
+		}
+		return
+	}
+
+	response.Success(w, http.StatusOK, "Notification template updated successfully", template)
+}
+
+func (h *NotificationTemplateHandler) DeleteTemplate(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	templateID, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid notification template ID", nil)
+		return
+	}
+
+	if err := h.templateUsecase.DeleteTemplate(r.Context(), templateID); err != nil {
+		if err == usecase.ErrNotificationTemplateNotFound {
+			response.NotFound(w, "Notification template not found")
+			return
+		}
+		response.InternalServerError(w, "Failed to delete notification template")
+		return
+	}
+
+	response.Success(w, http.StatusOK, "Notification template deleted successfully", nil)
+}
+
+// PreviewTemplate renders a template with sample variables without saving it
+func (h *NotificationTemplateHandler) PreviewTemplate(w http.ResponseWriter, r *http.Request) {
+	var req dto.PreviewNotificationTemplateRequest
+	if err := h.validator.DecodeJSON(r.Body, &req); err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid request body", h.validator.FormatDecodeError(err))
+		return
+	}
+
+	if err := h.validator.Validate(&req); err != nil {
+		response.ValidationError(w, h.validator.FormatValidationErrors(err))
+		return
+	}
+
+	preview, err := h.templateUsecase.PreviewTemplate(r.Context(), &req)
+	if err != nil {
+		if err == usecase.ErrInvalidNotificationTemplate {
+			response.Error(w, http.StatusBadRequest, "Template cannot be rendered, check its syntax and variable names", nil)
+			return
+		}
+		response.InternalServerError(w, "Failed to preview notification template")
+		return
+	}
+
+	response.Success(w, http.StatusOK, "Notification template rendered successfully", preview)
+}
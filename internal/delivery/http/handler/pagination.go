@@ -0,0 +1,52 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"go-template-clean-architecture/pkg/response"
+)
+
+// Pagination defaults for list endpoints
+const (
+	defaultPageLimit = 20
+	maxPageLimit     = 100
+)
+
+// parsePagination reads the optional page and limit query params.
+// Missing values use the defaults, limit is capped at maxPageLimit; ok is false for invalid values.
+func parsePagination(r *http.Request) (page int, limit int, ok bool) {
+	page, limit = 1, defaultPageLimit
+	query := r.URL.Query()
+
+	if raw := query.Get("page"); raw != "" {
+		value, err := strconv.Atoi(raw)
+		if err != nil || value < 1 {
+			return 0, 0, false
+		}
+		page = value
+	}
+	if raw := query.Get("limit"); raw != "" {
+		value, err := strconv.Atoi(raw)
+		if err != nil || value < 1 {
+			return 0, 0, false
+		}
+		limit = value
+	}
+	if limit > maxPageLimit {
+		limit = maxPageLimit
+	}
+
+	return page, limit, true
+}
+
+// newPaginationMeta builds the response meta for a page of total items
+func newPaginationMeta(page, limit int, total int64) *response.Meta {
+	totalPages := int((total + int64(limit) - 1) / int64(limit))
+	return &response.Meta{
+		Page:       page,
+		Limit:      limit,
+		Total:      total,
+		TotalPages: totalPages,
+	}
+}
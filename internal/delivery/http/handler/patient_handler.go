@@ -1,13 +1,17 @@
 package handler
 
 import (
-	"encoding/json"
 	"net/http"
+	"strings"
 
 	"go-template-clean-architecture/internal/delivery/dto"
+	"go-template-clean-architecture/internal/delivery/http/middleware"
 	"go-template-clean-architecture/internal/usecase"
 	"go-template-clean-architecture/pkg/response"
 	"go-template-clean-architecture/pkg/validator"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
 )
 
 type PatientHandler struct {
@@ -24,8 +28,8 @@ func NewPatientHandler(patientUsecase usecase.PatientProfileUsecase, validator *
 
 func (h *PatientHandler) UpdateSelfProfile(w http.ResponseWriter, r *http.Request) {
 	var req dto.PatientUpdateSelfRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		response.Error(w, http.StatusBadRequest, "Invalid request body", nil)
+	if err := h.validator.DecodeJSON(r.Body, &req); err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid request body", h.validator.FormatDecodeError(err))
 		return
 	}
 
@@ -49,3 +53,229 @@ func (h *PatientHandler) UpdateSelfProfile(w http.ResponseWriter, r *http.Reques
 
 	response.Success(w, http.StatusOK, "Profile updated successfully", profile)
 }
+
+// DeleteAccount erases the logged-in patient's account and anonymizes its personal data.
+// Booking history is kept for statistics; upcoming bookings must be cancelled first.
+func (h *PatientHandler) DeleteAccount(w http.ResponseWriter, r *http.Request) {
+	var req dto.DeletePatientAccountRequest
+	if err := h.validator.DecodeJSON(r.Body, &req); err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid request body", h.validator.FormatDecodeError(err))
+		return
+	}
+
+	if err := h.validator.Validate(&req); err != nil {
+		response.ValidationError(w, h.validator.FormatValidationErrors(err))
+		return
+	}
+
+	if err := h.patientUsecase.DeleteAccount(r.Context(), &req); err != nil {
+		switch err {
+		case usecase.ErrPatientNotFound:
+			response.NotFound(w, "Patient profile not found")
+		case usecase.ErrInvalidOldPassword:
+			response.Unauthorized(w, "Invalid password")
+		case usecase.ErrPatientHasUpcomingBookings:
+			response.Error(w, http.StatusConflict, err.Error(), nil)
+		default:
+			response.InternalServerError(w, "Failed to delete account")
+		}
+		return
+	}
+
+	response.Success(w, http.StatusOK, "Account deleted successfully", nil)
+}
+
+// SearchPatients finds patients for staff (front desk lookup), ordered by name.
+// Query params (at least one): nik (exact), phone_number (exact), name (partial, min 3 characters);
+// optional page (default 1), limit (default 20, max 100)
+func (h *PatientHandler) SearchPatients(w http.ResponseWriter, r *http.Request) {
+	page, limit, ok := parsePagination(r)
+	if !ok {
+		response.Error(w, http.StatusBadRequest, "Invalid pagination, page and limit must be positive integers", nil)
+		return
+	}
+
+	query := r.URL.Query()
+	filter := &dto.PatientSearchFilter{
+		NIK:         strings.TrimSpace(query.Get("nik")),
+		PhoneNumber: strings.TrimSpace(query.Get("phone_number")),
+		Name:        strings.TrimSpace(query.Get("name")),
+	}
+
+	patients, total, err := h.patientUsecase.SearchPatients(r.Context(), filter, page, limit)
+	if err != nil {
+		switch err {
+		case usecase.ErrPatientSearchEmpty, usecase.ErrPatientSearchNameTooShort:
+			response.Error(w, http.StatusBadRequest, err.Error(), nil)
+		default:
+			response.InternalServerError(w, "Failed to search patients")
+		}
+		return
+	}
+
+	response.SuccessWithMeta(w, http.StatusOK, "Patients retrieved successfully", patients, newPaginationMeta(page, limit, total))
+}
+
+// GetPatientTimeline returns a patient's chronological journey (admin support view).
+// Optional query params: page (default 1), limit (default 20, max 100)
+func (h *PatientHandler) GetPatientTimeline(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	patientID, err := uuid.Parse(vars["id"])
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid patient ID", nil)
+		return
+	}
+
+	page, limit, ok := parsePagination(r)
+	if !ok {
+		response.Error(w, http.StatusBadRequest, "Invalid pagination, page and limit must be positive integers", nil)
+		return
+	}
+
+	timeline, total, err := h.patientUsecase.GetPatientTimeline(r.Context(), patientID, page, limit)
+	if err != nil {
+		if err == usecase.ErrPatientNotFound {
+			response.NotFound(w, "Patient not found")
+			return
+		}
+		response.InternalServerError(w, "Failed to get patient timeline")
+		return
+	}
+
+	response.SuccessWithMeta(w, http.StatusOK, "Patient timeline retrieved successfully", timeline, newPaginationMeta(page, limit, total))
+}
+
+// UpdateMyInsurance registers the logged-in patient's health insurance
+func (h *PatientHandler) UpdateMyInsurance(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		response.Unauthorized(w, "Invalid token")
+		return
+	}
+	h.updateInsurance(w, r, userID)
+}
+
+// RemoveMyInsurance removes the logged-in patient's health insurance
+func (h *PatientHandler) RemoveMyInsurance(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		response.Unauthorized(w, "Invalid token")
+		return
+	}
+	h.removeInsurance(w, r, userID)
+}
+
+// UpdateInsurance registers the health insurance of a patient (admin)
+func (h *PatientHandler) UpdateInsurance(w http.ResponseWriter, r *http.Request) {
+	patientID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid patient ID", nil)
+		return
+	}
+	h.updateInsurance(w, r, patientID)
+}
+
+// RemoveInsurance removes the health insurance of a patient (admin)
+func (h *PatientHandler) RemoveInsurance(w http.ResponseWriter, r *http.Request) {
+	patientID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid patient ID", nil)
+		return
+	}
+	h.removeInsurance(w, r, patientID)
+}
+
+func (h *PatientHandler) updateInsurance(w http.ResponseWriter, r *http.Request, patientID uuid.UUID) {
+	var req dto.UpdateInsuranceRequest
+	if err := h.validator.DecodeJSON(r.Body, &req); err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid request body", h.validator.FormatDecodeError(err))
+		return
+	}
+
+	if err := h.validator.Validate(&req); err != nil {
+		response.ValidationError(w, h.validator.FormatValidationErrors(err))
+		return
+	}
+
+	patient, err := h.patientUsecase.UpdateInsurance(r.Context(), patientID, &req)
+	if err != nil {
+		switch err {
+		case usecase.ErrPatientNotFound:
+			response.NotFound(w, "Patient profile not found")
+		case usecase.ErrInvalidInsuranceValidity:
+			response.Error(w, http.StatusBadRequest, err.Error(), nil)
+		default:
+			response.InternalServerError(w, "Failed to update insurance")
+		}
+		return
+	}
+
+	response.Success(w, http.StatusOK, "Insurance updated successfully", patient)
+}
+
+func (h *PatientHandler) removeInsurance(w http.ResponseWriter, r *http.Request, patientID uuid.UUID) {
+	patient, err := h.patientUsecase.RemoveInsurance(r.Context(), patientID)
+	if err != nil {
+		if err == usecase.ErrPatientNotFound {
+			response.NotFound(w, "Patient profile not found")
+			return
+		}
+		response.InternalServerError(w, "Failed to remove insurance")
+		return
+	}
+
+	response.Success(w, http.StatusOK, "Insurance removed successfully", patient)
+}
+
+// UpdateMyEmergencyContact registers the logged-in patient's emergency contact
+func (h *PatientHandler) UpdateMyEmergencyContact(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		response.Unauthorized(w, "Invalid token")
+		return
+	}
+
+	var req dto.UpdateEmergencyContactRequest
+	if err := h.validator.DecodeJSON(r.Body, &req); err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid request body", h.validator.FormatDecodeError(err))
+		return
+	}
+
+	if err := h.validator.Validate(&req); err != nil {
+		response.ValidationError(w, h.validator.FormatValidationErrors(err))
+		return
+	}
+
+	patient, err := h.patientUsecase.UpdateEmergencyContact(r.Context(), userID, &req)
+	if err != nil {
+		if err == usecase.ErrPatientNotFound {
+			response.NotFound(w, "Patient profile not found")
+			return
+		}
+		response.InternalServerError(w, "Failed to update emergency contact")
+		return
+	}
+
+	response.Success(w, http.StatusOK, "Emergency contact updated successfully", patient)
+}
+
+// RemoveMyEmergencyContact removes the logged-in patient's emergency contact
+func (h *PatientHandler) RemoveMyEmergencyContact(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		response.Unauthorized(w, "Invalid token")
+		return
+	}
+
+	patient, err := h.patientUsecase.RemoveEmergencyContact(r.Context(), userID)
+	if err != nil {
+		if err == usecase.ErrPatientNotFound {
+			response.NotFound(w, "Patient profile not found")
+			return
+		}
+		response.InternalServerError(w, "Failed to remove emergency contact")
+		return
+	}
+
+	response.Success(w, http.StatusOK, "Emergency contact removed successfully", patient)
+}
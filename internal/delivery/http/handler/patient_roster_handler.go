@@ -0,0 +1,121 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"go-template-clean-architecture/internal/delivery/dto"
+	"go-template-clean-architecture/internal/usecase"
+	"go-template-clean-architecture/pkg/response"
+	"go-template-clean-architecture/pkg/validator"
+)
+
+type PatientRosterHandler struct {
+	rosterUsecase usecase.PatientRosterUsecase
+	validator     *validator.CustomValidator
+}
+
+func NewPatientRosterHandler(rosterUsecase usecase.PatientRosterUsecase, validator *validator.CustomValidator) *PatientRosterHandler {
+	return &PatientRosterHandler{
+		rosterUsecase: rosterUsecase,
+		validator:     validator,
+	}
+}
+
+// ImportRoster pre-registers patients from a partner roster CSV upload (multipart field "file").
+// Required form/query param: partner. Optional query param: dry_run=true validates the file without importing.
+func (h *PatientRosterHandler) ImportRoster(w http.ResponseWriter, r *http.Request) {
+	dryRun, _ := strconv.ParseBool(r.URL.Query().Get("dry_run"))
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxBookingImportSize)
+	file, _, err := r.FormFile("file")
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "CSV file is required in form field 'file' (max 10 MB)", nil)
+		return
+	}
+	defer file.Close()
+
+	result, err := h.rosterUsecase.ImportRoster(r.Context(), file, r.FormValue("partner"), dryRun)
+	if err != nil {
+		switch err {
+		case usecase.ErrRosterPartnerRequired:
+			response.Error(w, http.StatusBadRequest, "Partner is required", nil)
+		case usecase.ErrImportInvalidFile:
+			response.Error(w, http.StatusBadRequest, "Import file is not a valid CSV", nil)
+		case usecase.ErrImportTooManyRows:
+			response.Error(w, http.StatusBadRequest, "Import file has too many rows (max 5000)", nil)
+		case usecase.ErrImportInvalidRows:
+			response.Error(w, http.StatusUnprocessableEntity, "Import file has invalid rows, nothing was imported", result.Errors)
+		case usecase.ErrNIKAlreadyExists:
+			response.Error(w, http.StatusConflict, "A NIK in the roster was registered during the import, nothing was imported", nil)
+		default:
+			response.InternalServerError(w, "Failed to import patient roster")
+		}
+		return
+	}
+
+	if dryRun {
+		response.Success(w, http.StatusOK, "Roster validated successfully", result)
+		return
+	}
+
+	response.Success(w, http.StatusCreated, "Patients pre-registered successfully", result)
+}
+
+// RequestClaim sends an activation code to the registered phone of a pre-registered patient
+func (h *PatientRosterHandler) RequestClaim(w http.ResponseWriter, r *http.Request) {
+	var req dto.RequestClaimRequest
+	if err := h.validator.DecodeJSON(r.Body, &req); err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid request body", h.validator.FormatDecodeError(err))
+		return
+	}
+
+	if err := h.validator.Validate(&req); err != nil {
+		response.ValidationError(w, h.validator.FormatValidationErrors(err))
+		return
+	}
+
+	if err := h.rosterUsecase.RequestClaim(r.Context(), &req); err != nil {
+		switch err {
+		case usecase.ErrInvalidDateFormat:
+			response.Error(w, http.StatusBadRequest, "Invalid date format, use YYYY-MM-DD", nil)
+		default:
+			response.InternalServerError(w, "Failed to request account claim")
+		}
+		return
+	}
+
+	// Same response whether or not a claimable account exists
+	response.Success(w, http.StatusOK, "If a pre-registered account matches, an activation code was sent to its phone", nil)
+}
+
+// VerifyClaim completes the claim with the activation code and sets the login credentials
+func (h *PatientRosterHandler) VerifyClaim(w http.ResponseWriter, r *http.Request) {
+	var req dto.VerifyClaimRequest
+	if err := h.validator.DecodeJSON(r.Body, &req); err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid request body", h.validator.FormatDecodeError(err))
+		return
+	}
+
+	if err := h.validator.Validate(&req); err != nil {
+		response.ValidationError(w, h.validator.FormatValidationErrors(err))
+		return
+	}
+
+	result, err := h.rosterUsecase.VerifyClaim(r.Context(), &req)
+	if err != nil {
+		switch err {
+		case usecase.ErrInvalidClaimCode:
+			response.Error(w, http.StatusBadRequest, "Invalid or expired activation code", nil)
+		case usecase.ErrClaimTooManyAttempts:
+			response.Error(w, http.StatusTooManyRequests, "Too many attempts, request a new code later", nil)
+		case usecase.ErrEmailAlreadyExists:
+			response.Error(w, http.StatusConflict, "Email already exists", nil)
+		default:
+			response.InternalServerError(w, "Failed to claim account")
+		}
+		return
+	}
+
+	response.Success(w, http.StatusOK, "Account claimed successfully", result)
+}
@@ -0,0 +1,109 @@
+package handler
+
+import (
+	"io"
+	"net/http"
+
+	"go-template-clean-architecture/internal/usecase"
+	"go-template-clean-architecture/pkg/response"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+// Callback bodies are small JSON or form documents
+const maxPaymentCallbackSize = 1 << 20
+
+type PaymentHandler struct {
+	paymentUsecase usecase.PaymentUsecase
+}
+
+func NewPaymentHandler(paymentUsecase usecase.PaymentUsecase) *PaymentHandler {
+	return &PaymentHandler{
+		paymentUsecase: paymentUsecase,
+	}
+}
+
+// Callback receives payment notifications from the payment gateway. Each gateway signs its
+// callbacks its own way, so the raw body is handed to the gateway for verification.
+func (h *PaymentHandler) Callback(w http.ResponseWriter, r *http.Request) {
+	gateway := mux.Vars(r)["gateway"]
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxPaymentCallbackSize))
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid request body", nil)
+		return
+	}
+
+	if err := h.paymentUsecase.HandleCallback(r.Context(), gateway, r.Header, body); err != nil {
+		switch err {
+		case usecase.ErrPaymentGatewayNotFound:
+			response.NotFound(w, "Payment gateway not found")
+		case usecase.ErrInvalidPaymentCallback:
+			response.Unauthorized(w, "Invalid payment callback")
+		case usecase.ErrPaymentNotFound:
+			response.NotFound(w, "Payment not found")
+		case usecase.ErrPaymentAmountMismatch:
+			response.Error(w, http.StatusBadRequest, "Paid amount does not match the payment", nil)
+		case usecase.ErrPaymentCurrencyMismatch:
+			response.Error(w, http.StatusBadRequest, "Paid currency does not match the payment", nil)
+		default:
+			response.InternalServerError(w, "Failed to process payment callback")
+		}
+		return
+	}
+
+	response.Success(w, http.StatusOK, "Payment callback processed successfully", nil)
+}
+
+// GetMyBookingPayment returns the latest payment of the logged-in patient's booking
+func (h *PaymentHandler) GetMyBookingPayment(w http.ResponseWriter, r *http.Request) {
+	bookingID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid booking ID", nil)
+		return
+	}
+
+	payment, err := h.paymentUsecase.GetMyBookingPayment(r.Context(), bookingID)
+	if err != nil {
+		switch err {
+		case usecase.ErrBookingNotFound:
+			response.NotFound(w, "Booking not found")
+		case usecase.ErrBookingNotOwned:
+			response.Forbidden(w, "Booking does not belong to you")
+		case usecase.ErrPaymentNotFound:
+			response.NotFound(w, "Booking has no payment")
+		default:
+			response.InternalServerError(w, "Failed to get payment")
+		}
+		return
+	}
+
+	response.Success(w, http.StatusOK, "Payment retrieved successfully", payment)
+}
+
+// RefundPayment requests again the refund of a paid payment of a cancelled booking (admin)
+func (h *PaymentHandler) RefundPayment(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid payment ID", nil)
+		return
+	}
+
+	payment, err := h.paymentUsecase.RefundPayment(r.Context(), id)
+	if err != nil {
+		switch err {
+		case usecase.ErrPaymentNotFound:
+			response.NotFound(w, "Payment not found")
+		case usecase.ErrPaymentNotRefundable:
+			response.Error(w, http.StatusConflict, "Payment is not paid, or refunded or being refunded already", nil)
+		case usecase.ErrPaymentBookingActive:
+			response.Error(w, http.StatusConflict, "Booking of the payment is not cancelled", nil)
+		default:
+			response.InternalServerError(w, "Failed to refund payment")
+		}
+		return
+	}
+
+	response.Success(w, http.StatusAccepted, "Payment refund requested successfully", payment)
+}
@@ -0,0 +1,87 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"go-template-clean-architecture/internal/delivery/dto"
+	"go-template-clean-architecture/internal/usecase"
+	"go-template-clean-architecture/pkg/response"
+	"go-template-clean-architecture/pkg/validator"
+
+	"github.com/gorilla/mux"
+)
+
+type RedisStateHandler struct {
+	redisStateUsecase usecase.RedisStateUsecase
+	validator         *validator.CustomValidator
+}
+
+func NewRedisStateHandler(redisStateUsecase usecase.RedisStateUsecase, validator *validator.CustomValidator) *RedisStateHandler {
+	return &RedisStateHandler{
+		redisStateUsecase: redisStateUsecase,
+		validator:         validator,
+	}
+}
+
+// GetScheduleState returns the Redis counters of a schedule next to the values expected from the DB
+func (h *RedisStateHandler) GetScheduleState(w http.ResponseWriter, r *http.Request) {
+	scheduleID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid schedule ID", nil)
+		return
+	}
+
+	state, err := h.redisStateUsecase.GetScheduleState(r.Context(), scheduleID)
+	if err != nil {
+		if err == usecase.ErrScheduleNotFound {
+			response.NotFound(w, "Schedule not found")
+			return
+		}
+		response.InternalServerError(w, "Failed to get Redis schedule state")
+		return
+	}
+
+	response.Success(w, http.StatusOK, "Redis schedule state retrieved successfully", state)
+}
+
+// UpdateScheduleState manually corrects the Redis quota and/or queue counter of a schedule
+func (h *RedisStateHandler) UpdateScheduleState(w http.ResponseWriter, r *http.Request) {
+	scheduleID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid schedule ID", nil)
+		return
+	}
+
+	var req dto.UpdateRedisScheduleStateRequest
+	if err := h.validator.DecodeJSON(r.Body, &req); err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid request body", h.validator.FormatDecodeError(err))
+		return
+	}
+
+	if err := h.validator.Validate(&req); err != nil {
+		response.ValidationError(w, h.validator.FormatValidationErrors(err))
+		return
+	}
+
+	state, err := h.redisStateUsecase.UpdateScheduleState(r.Context(), scheduleID, &req)
+	if err != nil {
+		switch err {
+		case usecase.ErrScheduleNotFound:
+			response.NotFound(w, "Schedule not found")
+		case usecase.ErrRedisNothingToCorrect:
+			response.Error(w, http.StatusBadRequest, "Set quota and/or queue_number to correct", nil)
+		case usecase.ErrRedisSchedulePast:
+			response.Error(w, http.StatusBadRequest, "Schedule date has passed, its Redis counters are no longer used", nil)
+		case usecase.ErrRedisQuotaOverBooked:
+			response.Error(w, http.StatusConflict, "Quota cannot exceed total quota minus active bookings", nil)
+		case usecase.ErrRedisQueueBelowBooked:
+			response.Error(w, http.StatusConflict, "Queue number cannot be below the highest active queue number", nil)
+		default:
+			response.InternalServerError(w, "Failed to update Redis schedule state")
+		}
+		return
+	}
+
+	response.Success(w, http.StatusOK, "Redis schedule state updated successfully", state)
+}
@@ -0,0 +1,153 @@
+package handler
+
+import (
+	"net/http"
+
+	"go-template-clean-architecture/internal/delivery/dto"
+	"go-template-clean-architecture/internal/delivery/http/middleware"
+	"go-template-clean-architecture/internal/usecase"
+	"go-template-clean-architecture/pkg/response"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+type ReportHandler struct {
+	reportUsecase usecase.ReportUsecase
+}
+
+func NewReportHandler(reportUsecase usecase.ReportUsecase) *ReportHandler {
+	return &ReportHandler{
+		reportUsecase: reportUsecase,
+	}
+}
+
+// GetWaitTimeReport returns average wait time per doctor.
+// Optional query params: start_date, end_date (YYYY-MM-DD)
+func (h *ReportHandler) GetWaitTimeReport(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	report, err := h.reportUsecase.GetWaitTimeReport(r.Context(), query.Get("start_date"), query.Get("end_date"))
+	if err != nil {
+		if err == usecase.ErrInvalidReportDateRange {
+			response.Error(w, http.StatusBadRequest, "Invalid date range, use YYYY-MM-DD", nil)
+			return
+		}
+		response.InternalServerError(w, "Failed to get wait time report")
+		return
+	}
+
+	response.Success(w, http.StatusOK, "Wait time report retrieved successfully", report)
+}
+
+// GetUsageReport returns daily usage for billing.
+// Optional query params: start_date, end_date (YYYY-MM-DD), defaults to the current month
+func (h *ReportHandler) GetUsageReport(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	report, err := h.reportUsecase.GetUsageReport(r.Context(), query.Get("start_date"), query.Get("end_date"))
+	if err != nil {
+		if err == usecase.ErrInvalidReportDateRange {
+			response.Error(w, http.StatusBadRequest, "Invalid date range, use YYYY-MM-DD (max 366 days)", nil)
+			return
+		}
+		response.InternalServerError(w, "Failed to get usage report")
+		return
+	}
+
+	response.Success(w, http.StatusOK, "Usage report retrieved successfully", report)
+}
+
+// GetReminderReport returns the daily sent, failed and skipped appointment reminders.
+// Optional query params: start_date, end_date (YYYY-MM-DD), defaults to the last 7 days
+func (h *ReportHandler) GetReminderReport(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	report, err := h.reportUsecase.GetReminderReport(r.Context(), query.Get("start_date"), query.Get("end_date"))
+	if err != nil {
+		if err == usecase.ErrInvalidReportDateRange {
+			response.Error(w, http.StatusBadRequest, "Invalid date range, use YYYY-MM-DD (max 35 days)", nil)
+			return
+		}
+		response.InternalServerError(w, "Failed to get reminder report")
+		return
+	}
+
+	response.Success(w, http.StatusOK, "Reminder report retrieved successfully", report)
+}
+
+// GetScheduleUtilizationReport returns booked vs total quota, no-shows and fill rate per schedule.
+// Optional query params: start_date, end_date (YYYY-MM-DD, defaults to the current month),
+// doctor_name, specialization (slug)
+func (h *ReportHandler) GetScheduleUtilizationReport(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	filter := &dto.ScheduleUtilizationFilter{
+		StartDate:      query.Get("start_date"),
+		EndDate:        query.Get("end_date"),
+		DoctorName:     query.Get("doctor_name"),
+		Specialization: query.Get("specialization"),
+	}
+
+	report, err := h.reportUsecase.GetScheduleUtilizationReport(r.Context(), filter)
+	if err != nil {
+		if err == usecase.ErrInvalidReportDateRange {
+			response.Error(w, http.StatusBadRequest, "Invalid date range, use YYYY-MM-DD (max 92 days)", nil)
+			return
+		}
+		response.InternalServerError(w, "Failed to get schedule utilization report")
+		return
+	}
+
+	response.Success(w, http.StatusOK, "Schedule utilization report retrieved successfully", report)
+}
+
+// GetDoctorStats returns the performance statistics of any doctor (admin).
+// Optional query params: start_date, end_date (YYYY-MM-DD, defaults to the last six months),
+// period (day, week or month, default month)
+func (h *ReportHandler) GetDoctorStats(w http.ResponseWriter, r *http.Request) {
+	doctorID, err := uuid.Parse(mux.Vars(r)["doctorId"])
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid doctor ID", nil)
+		return
+	}
+
+	h.getDoctorStats(w, r, doctorID)
+}
+
+// GetMyStats returns the performance statistics of the logged-in doctor.
+// Same query params as GetDoctorStats.
+func (h *ReportHandler) GetMyStats(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		response.Unauthorized(w, "Unauthorized")
+		return
+	}
+
+	h.getDoctorStats(w, r, userID)
+}
+
+func (h *ReportHandler) getDoctorStats(w http.ResponseWriter, r *http.Request, doctorID uuid.UUID) {
+	query := r.URL.Query()
+	filter := &dto.DoctorStatsFilter{
+		StartDate: query.Get("start_date"),
+		EndDate:   query.Get("end_date"),
+		Period:    query.Get("period"),
+	}
+
+	stats, err := h.reportUsecase.GetDoctorStats(r.Context(), doctorID, filter)
+	if err != nil {
+		switch err {
+		case usecase.ErrDoctorNotFound:
+			response.NotFound(w, "Doctor not found")
+		case usecase.ErrInvalidReportDateRange:
+			response.Error(w, http.StatusBadRequest, "Invalid date range, use YYYY-MM-DD (max 366 days)", nil)
+		case usecase.ErrInvalidStatsPeriod:
+			response.Error(w, http.StatusBadRequest, "Invalid period, use day, week or month", nil)
+		default:
+			response.InternalServerError(w, "Failed to get doctor statistics")
+		}
+		return
+	}
+
+	response.Success(w, http.StatusOK, "Doctor statistics retrieved successfully", stats)
+}
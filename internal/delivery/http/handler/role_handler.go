@@ -0,0 +1,181 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"go-template-clean-architecture/internal/delivery/dto"
+	"go-template-clean-architecture/internal/usecase"
+	"go-template-clean-architecture/pkg/response"
+	"go-template-clean-architecture/pkg/validator"
+
+	"github.com/gorilla/mux"
+)
+
+type RoleHandler struct {
+	roleUsecase usecase.RoleUsecase
+	validator   *validator.CustomValidator
+}
+
+func NewRoleHandler(roleUsecase usecase.RoleUsecase, validator *validator.CustomValidator) *RoleHandler {
+	return &RoleHandler{
+		roleUsecase: roleUsecase,
+		validator:   validator,
+	}
+}
+
+func (h *RoleHandler) GetAllRoles(w http.ResponseWriter, r *http.Request) {
+	roles, err := h.roleUsecase.GetAllRoles(r.Context())
+	if err != nil {
+		response.InternalServerError(w, "Failed to get roles")
+		return
+	}
+
+	response.Success(w, http.StatusOK, "Roles retrieved successfully", roles)
+}
+
+func (h *RoleHandler) GetAllPermissions(w http.ResponseWriter, r *http.Request) {
+	permissions, err := h.roleUsecase.GetAllPermissions(r.Context())
+	if err != nil {
+		response.InternalServerError(w, "Failed to get permissions")
+		return
+	}
+
+	response.Success(w, http.StatusOK, "Permissions retrieved successfully", permissions)
+}
+
+func (h *RoleHandler) CreateRole(w http.ResponseWriter, r *http.Request) {
+	var req dto.CreateRoleRequest
+	if err := h.validator.DecodeJSON(r.Body, &req); err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid request body", h.validator.FormatDecodeError(err))
+		return
+	}
+
+	if err := h.validator.Validate(&req); err != nil {
+		response.ValidationError(w, h.validator.FormatValidationErrors(err))
+		return
+	}
+
+	role, err := h.roleUsecase.CreateRole(r.Context(), &req)
+	if err != nil {
+		switch err {
+		case usecase.ErrUnknownPermission:
+			response.Error(w, http.StatusBadRequest, "Unknown permission", nil)
+		case usecase.ErrRoleNameExists:
+			response.Error(w, http.StatusConflict, "Role name already exists", nil)
+		default:
+			response.InternalServerError(w, "Failed to create role")
+		}
+		return
+	}
+
+	response.Success(w, http.StatusCreated, "Role created successfully", role)
+}
+
+func (h *RoleHandler) UpdateRolePermissions(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	roleID, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid role ID", nil)
+		return
+	}
+
+	var req dto.UpdateRolePermissionsRequest
+	if err := h.validator.DecodeJSON(r.Body, &req); err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid request body", h.validator.FormatDecodeError(err))
+		return
+	}
+
+	if err := h.validator.Validate(&req); err != nil {
+		response.ValidationError(w, h.validator.FormatValidationErrors(err))
+		return
+	}
+
+	role, err := h.roleUsecase.UpdateRolePermissions(r.Context(), roleID, &req)
+	if err != nil {
+		switch err {
+		case usecase.ErrRoleNotFound:
+			response.NotFound(w, "Role not found")
+		case usecase.ErrUnknownPermission:
+			response.Error(w, http.StatusBadRequest, "Unknown permission", nil)
+		case usecase.ErrRoleProtected:
+			response.Error(w, http.StatusConflict, "Permissions of the admin role cannot be changed", nil)
+		default:
+			response.InternalServerError(w, "Failed to update role permissions")
+		}
+		return
+	}
+
+	response.Success(w, http.StatusOK, "Role permissions updated successfully", role)
+}
+
+// CreateStaffUser creates an account with a staff role (e.g. nurse, receptionist)
+func (h *RoleHandler) CreateStaffUser(w http.ResponseWriter, r *http.Request) {
+	var req dto.CreateStaffUserRequest
+	if err := h.validator.DecodeJSON(r.Body, &req); err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid request body", h.validator.FormatDecodeError(err))
+		return
+	}
+
+	if err := h.validator.Validate(&req); err != nil {
+		response.ValidationError(w, h.validator.FormatValidationErrors(err))
+		return
+	}
+
+	user, err := h.roleUsecase.CreateStaffUser(r.Context(), &req)
+	if err != nil {
+		switch err {
+		case usecase.ErrRoleNotFound:
+			response.Error(w, http.StatusBadRequest, "Role not found", nil)
+		case usecase.ErrProfileRoleNotAllowed:
+			response.Error(w, http.StatusBadRequest, "Doctor and patient accounts are created by registration", nil)
+		case usecase.ErrEmailAlreadyExists:
+			response.Error(w, http.StatusConflict, "Email already exists", nil)
+		default:
+			response.InternalServerError(w, "Failed to create staff user")
+		}
+		return
+	}
+
+	response.Success(w, http.StatusCreated, "Staff user created successfully", user)
+}
+
+// GetAllUsers lists user accounts with their status, e.g. to find locked accounts.
+// Query params: role_id, locked (true/false), search (email or name), page (default 1), limit (default 20, max 100)
+func (h *RoleHandler) GetAllUsers(w http.ResponseWriter, r *http.Request) {
+	page, limit, ok := parsePagination(r)
+	if !ok {
+		response.Error(w, http.StatusBadRequest, "Invalid pagination, page and limit must be positive integers", nil)
+		return
+	}
+
+	query := r.URL.Query()
+	filter := &dto.UserFilter{
+		Search: strings.TrimSpace(query.Get("search")),
+	}
+	if raw := query.Get("role_id"); raw != "" {
+		roleID, err := strconv.Atoi(raw)
+		if err != nil || roleID < 1 {
+			response.Error(w, http.StatusBadRequest, "Invalid role ID", nil)
+			return
+		}
+		filter.RoleID = roleID
+	}
+	if raw := query.Get("locked"); raw != "" {
+		locked, err := strconv.ParseBool(raw)
+		if err != nil {
+			response.Error(w, http.StatusBadRequest, "Invalid locked filter, use true or false", nil)
+			return
+		}
+		filter.Locked = &locked
+	}
+
+	users, total, err := h.roleUsecase.GetAllUsers(r.Context(), filter, page, limit)
+	if err != nil {
+		response.InternalServerError(w, "Failed to get users")
+		return
+	}
+
+	response.SuccessWithMeta(w, http.StatusOK, "Users retrieved successfully", users, newPaginationMeta(page, limit, total))
+}
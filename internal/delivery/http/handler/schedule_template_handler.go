@@ -0,0 +1,200 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"go-template-clean-architecture/internal/delivery/dto"
+	"go-template-clean-architecture/internal/usecase"
+	"go-template-clean-architecture/pkg/response"
+	"go-template-clean-architecture/pkg/validator"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+type ScheduleTemplateHandler struct {
+	templateUsecase usecase.ScheduleTemplateUsecase
+	validator       *validator.CustomValidator
+}
+
+func NewScheduleTemplateHandler(templateUsecase usecase.ScheduleTemplateUsecase, validator *validator.CustomValidator) *ScheduleTemplateHandler {
+	return &ScheduleTemplateHandler{
+		templateUsecase: templateUsecase,
+		validator:       validator,
+	}
+}
+
+// CreateTemplate adds a weekly recurring schedule of a doctor (admin)
+func (h *ScheduleTemplateHandler) CreateTemplate(w http.ResponseWriter, r *http.Request) {
+	var req dto.CreateScheduleTemplateRequest
+	if err := h.validator.DecodeJSON(r.Body, &req); err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid request body", h.validator.FormatDecodeError(err))
+		return
+	}
+
+	if err := h.validator.Validate(&req); err != nil {
+		response.ValidationError(w, h.validator.FormatValidationErrors(err))
+		return
+	}
+
+	template, err := h.templateUsecase.CreateTemplate(r.Context(), &req)
+	if err != nil {
+		h.writeError(w, err, "Failed to create schedule template")
+		return
+	}
+
+	response.Success(w, http.StatusCreated, "Schedule template created successfully", template)
+}
+
+// GetTemplates lists schedule templates (admin).
+// Optional query params: doctor_id, page (default 1), limit (default 20, max 100)
+func (h *ScheduleTemplateHandler) GetTemplates(w http.ResponseWriter, r *http.Request) {
+	page, limit, ok := parsePagination(r)
+	if !ok {
+		response.Error(w, http.StatusBadRequest, "Invalid pagination, page and limit must be positive integers", nil)
+		return
+	}
+
+	var doctorID *uuid.UUID
+	if raw := r.URL.Query().Get("doctor_id"); raw != "" {
+		id, err := uuid.Parse(raw)
+		if err != nil {
+			response.Error(w, http.StatusBadRequest, "Invalid doctor ID", nil)
+			return
+		}
+		doctorID = &id
+	}
+
+	templates, total, err := h.templateUsecase.GetTemplates(r.Context(), doctorID, page, limit)
+	if err != nil {
+		response.InternalServerError(w, "Failed to get schedule templates")
+		return
+	}
+
+	response.SuccessWithMeta(w, http.StatusOK, "Schedule templates retrieved successfully", templates, newPaginationMeta(page, limit, total))
+}
+
+// UpdateTemplate changes a schedule template; already generated schedules are kept as they are (admin)
+func (h *ScheduleTemplateHandler) UpdateTemplate(w http.ResponseWriter, r *http.Request) {
+	templateID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid template ID", nil)
+		return
+	}
+
+	var req dto.UpdateScheduleTemplateRequest
+	if err := h.validator.DecodeJSON(r.Body, &req); err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid request body", h.validator.FormatDecodeError(err))
+		return
+	}
+
+	if err := h.validator.Validate(&req); err != nil {
+		response.ValidationError(w, h.validator.FormatValidationErrors(err))
+		return
+	}
+
+	template, err := h.templateUsecase.UpdateTemplate(r.Context(), templateID, &req)
+	if err != nil {
+		h.writeError(w, err, "Failed to update schedule template")
+		return
+	}
+
+	response.Success(w, http.StatusOK, "Schedule template updated successfully", template)
+}
+
+// DeleteTemplate removes a schedule template; schedules generated from it are kept (admin)
+func (h *ScheduleTemplateHandler) DeleteTemplate(w http.ResponseWriter, r *http.Request) {
+	templateID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid template ID", nil)
+		return
+	}
+
+	if err := h.templateUsecase.DeleteTemplate(r.Context(), templateID); err != nil {
+		if err == usecase.ErrScheduleTemplateNotFound {
+			response.NotFound(w, "Schedule template not found")
+			return
+		}
+		response.InternalServerError(w, "Failed to delete schedule template")
+		return
+	}
+
+	response.Success(w, http.StatusOK, "Schedule template deleted successfully", nil)
+}
+
+// GenerateSchedules expands the templates now instead of waiting for the nightly run (admin)
+func (h *ScheduleTemplateHandler) GenerateSchedules(w http.ResponseWriter, r *http.Request) {
+	run, err := h.templateUsecase.GenerateSchedules(r.Context())
+	if err != nil {
+		if err == usecase.ErrScheduleGenerationFailed {
+			response.Error(w, http.StatusInternalServerError, "Schedule generation failed", run)
+			return
+		}
+		response.InternalServerError(w, "Failed to generate schedules")
+		return
+	}
+
+	response.Success(w, http.StatusCreated, "Schedules generated successfully", run)
+}
+
+// GetGenerationRuns lists schedule generation run reports, newest first (admin).
+// Optional query params: page (default 1), limit (default 20, max 100)
+func (h *ScheduleTemplateHandler) GetGenerationRuns(w http.ResponseWriter, r *http.Request) {
+	page, limit, ok := parsePagination(r)
+	if !ok {
+		response.Error(w, http.StatusBadRequest, "Invalid pagination, page and limit must be positive integers", nil)
+		return
+	}
+
+	runs, total, err := h.templateUsecase.GetGenerationRuns(r.Context(), page, limit)
+	if err != nil {
+		response.InternalServerError(w, "Failed to get schedule generation runs")
+		return
+	}
+
+	response.SuccessWithMeta(w, http.StatusOK, "Schedule generation runs retrieved successfully", runs, newPaginationMeta(page, limit, total))
+}
+
+// GetGenerationRun returns one run report with the created, skipped and failed dates (admin)
+func (h *ScheduleTemplateHandler) GetGenerationRun(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid run ID", nil)
+		return
+	}
+
+	run, err := h.templateUsecase.GetGenerationRun(r.Context(), id)
+	if err != nil {
+		if err == usecase.ErrScheduleGenerationRunNotFound {
+			response.NotFound(w, "Schedule generation run not found")
+			return
+		}
+		response.InternalServerError(w, "Failed to get schedule generation run")
+		return
+	}
+
+	response.Success(w, http.StatusOK, "Schedule generation run retrieved successfully", run)
+}
+
+// writeError maps template create/update errors to responses
+func (h *ScheduleTemplateHandler) writeError(w http.ResponseWriter, err error, failureMessage string) {
+	switch err {
+	case usecase.ErrScheduleTemplateNotFound:
+		response.NotFound(w, "Schedule template not found")
+	case usecase.ErrDoctorNotFound:
+		response.NotFound(w, "Doctor not found")
+	case usecase.ErrInvalidTimeFormat:
+		response.Error(w, http.StatusBadRequest, "Invalid time format, use HH:MM", nil)
+	case usecase.ErrInvalidTemplateTimeRange:
+		response.Error(w, http.StatusBadRequest, "End time must be after start time", nil)
+	case usecase.ErrTemplateQuotaRequired:
+		response.Error(w, http.StatusBadRequest, "Total quota is required for queue templates", nil)
+	case usecase.ErrTemplateSlotMinutesRequired:
+		response.Error(w, http.StatusBadRequest, "Slot minutes are required for slot templates", nil)
+	case usecase.ErrScheduleShorterThanSlot:
+		response.Error(w, http.StatusBadRequest, "Template is shorter than one time slot", nil)
+	default:
+		response.InternalServerError(w, failureMessage)
+	}
+}
@@ -0,0 +1,101 @@
+package handler
+
+import (
+	"net/http"
+
+	"go-template-clean-architecture/internal/delivery/dto"
+	"go-template-clean-architecture/internal/usecase"
+	"go-template-clean-architecture/pkg/response"
+	"go-template-clean-architecture/pkg/validator"
+
+	"github.com/gorilla/mux"
+)
+
+type SpecializationDefaultHandler struct {
+	defaultsUsecase usecase.SpecializationDefaultUsecase
+	validator       *validator.CustomValidator
+}
+
+func NewSpecializationDefaultHandler(defaultsUsecase usecase.SpecializationDefaultUsecase, validator *validator.CustomValidator) *SpecializationDefaultHandler {
+	return &SpecializationDefaultHandler{
+		defaultsUsecase: defaultsUsecase,
+		validator:       validator,
+	}
+}
+
+func (h *SpecializationDefaultHandler) GetAllDefaults(w http.ResponseWriter, r *http.Request) {
+	defaults, err := h.defaultsUsecase.GetAllDefaults(r.Context())
+	if err != nil {
+		response.InternalServerError(w, "Failed to get specialization defaults")
+		return
+	}
+
+	response.Success(w, http.StatusOK, "Specialization defaults retrieved successfully", defaults)
+}
+
+func (h *SpecializationDefaultHandler) GetDefault(w http.ResponseWriter, r *http.Request) {
+	specialization := mux.Vars(r)["specialization"]
+
+	defaults, err := h.defaultsUsecase.GetDefault(r.Context(), specialization)
+	if err != nil {
+		switch err {
+		case usecase.ErrSpecializationNotFound:
+			response.NotFound(w, "Specialization not found")
+		case usecase.ErrSpecializationDefaultNotFound:
+			response.NotFound(w, "Specialization defaults not found")
+		default:
+			response.InternalServerError(w, "Failed to get specialization defaults")
+		}
+		return
+	}
+
+	response.Success(w, http.StatusOK, "Specialization defaults retrieved successfully", defaults)
+}
+
+func (h *SpecializationDefaultHandler) UpsertDefault(w http.ResponseWriter, r *http.Request) {
+	specialization := mux.Vars(r)["specialization"]
+
+	var req dto.UpsertSpecializationDefaultRequest
+	if err := h.validator.DecodeJSON(r.Body, &req); err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid request body", h.validator.FormatDecodeError(err))
+		return
+	}
+
+	if err := h.validator.Validate(&req); err != nil {
+		response.ValidationError(w, h.validator.FormatValidationErrors(err))
+		return
+	}
+
+	defaults, err := h.defaultsUsecase.UpsertDefault(r.Context(), specialization, &req)
+	if err != nil {
+		switch err {
+		case usecase.ErrInvalidSpecialization:
+			response.Error(w, http.StatusBadRequest, "Specialization is required", nil)
+		case usecase.ErrSpecializationNotFound:
+			response.NotFound(w, "Specialization not found")
+		default:
+			response.InternalServerError(w, "Failed to save specialization defaults")
+		}
+		return
+	}
+
+	response.Success(w, http.StatusOK, "Specialization defaults saved successfully", defaults)
+}
+
+func (h *SpecializationDefaultHandler) DeleteDefault(w http.ResponseWriter, r *http.Request) {
+	specialization := mux.Vars(r)["specialization"]
+
+	if err := h.defaultsUsecase.DeleteDefault(r.Context(), specialization); err != nil {
+		switch err {
+		case usecase.ErrSpecializationNotFound:
+			response.NotFound(w, "Specialization not found")
+		case usecase.ErrSpecializationDefaultNotFound:
+			response.NotFound(w, "Specialization defaults not found")
+		default:
+			response.InternalServerError(w, "Failed to delete specialization defaults")
+		}
+		return
+	}
+
+	response.Success(w, http.StatusOK, "Specialization defaults deleted successfully", nil)
+}
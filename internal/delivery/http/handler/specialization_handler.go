@@ -0,0 +1,149 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"go-template-clean-architecture/internal/delivery/dto"
+	"go-template-clean-architecture/internal/usecase"
+	"go-template-clean-architecture/pkg/response"
+	"go-template-clean-architecture/pkg/validator"
+
+	"github.com/gorilla/mux"
+)
+
+type SpecializationHandler struct {
+	specializationUsecase usecase.SpecializationUsecase
+	validator             *validator.CustomValidator
+}
+
+func NewSpecializationHandler(specializationUsecase usecase.SpecializationUsecase, validator *validator.CustomValidator) *SpecializationHandler {
+	return &SpecializationHandler{
+		specializationUsecase: specializationUsecase,
+		validator:             validator,
+	}
+}
+
+func (h *SpecializationHandler) CreateSpecialization(w http.ResponseWriter, r *http.Request) {
+	var req dto.CreateSpecializationRequest
+	if err := h.validator.DecodeJSON(r.Body, &req); err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid request body", h.validator.FormatDecodeError(err))
+		return
+	}
+
+	if err := h.validator.Validate(&req); err != nil {
+		response.ValidationError(w, h.validator.FormatValidationErrors(err))
+		return
+	}
+
+	specialization, err := h.specializationUsecase.CreateSpecialization(r.Context(), &req)
+	if err != nil {
+		switch err {
+		case usecase.ErrInvalidSpecializationSlug:
+			response.Error(w, http.StatusBadRequest, "Slug must contain letters or digits", nil)
+		case usecase.ErrSpecializationNameExists:
+			response.Error(w, http.StatusConflict, "A specialization with this name already exists", nil)
+		case usecase.ErrSpecializationSlugExists:
+			response.Error(w, http.StatusConflict, "A specialization with this slug already exists", nil)
+		default:
+			response.InternalServerError(w, "Failed to create specialization")
+		}
+		return
+	}
+
+	response.Success(w, http.StatusCreated, "Specialization created successfully", specialization)
+}
+
+// GetAllSpecializations lists the specializations ordered by name (public)
+func (h *SpecializationHandler) GetAllSpecializations(w http.ResponseWriter, r *http.Request) {
+	specializations, err := h.specializationUsecase.GetAllSpecializations(r.Context())
+	if err != nil {
+		response.InternalServerError(w, "Failed to get specializations")
+		return
+	}
+
+	response.Success(w, http.StatusOK, "Specializations retrieved successfully", specializations)
+}
+
+func (h *SpecializationHandler) GetSpecialization(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	specializationID, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid specialization ID", nil)
+		return
+	}
+
+	specialization, err := h.specializationUsecase.GetSpecialization(r.Context(), specializationID)
+	if err != nil {
+		if err == usecase.ErrSpecializationNotFound {
+			response.NotFound(w, "Specialization not found")
+			return
+		}
+		response.InternalServerError(w, "Failed to get specialization")
+		return
+	}
+
+	response.Success(w, http.StatusOK, "Specialization retrieved successfully", specialization)
+}
+
+func (h *SpecializationHandler) UpdateSpecialization(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	specializationID, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid specialization ID", nil)
+		return
+	}
+
+	var req dto.UpdateSpecializationRequest
+	if err := h.validator.DecodeJSON(r.Body, &req); err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid request body", h.validator.FormatDecodeError(err))
+		return
+	}
+
+	if err := h.validator.Validate(&req); err != nil {
+		response.ValidationError(w, h.validator.FormatValidationErrors(err))
+		return
+	}
+
+	specialization, err := h.specializationUsecase.UpdateSpecialization(r.Context(), specializationID, &req)
+	if err != nil {
+		switch err {
+		case usecase.ErrSpecializationNotFound:
+			response.NotFound(w, "Specialization not found")
+		case usecase.ErrInvalidSpecializationSlug:
+			response.Error(w, http.StatusBadRequest, "Slug must contain letters or digits", nil)
+		case usecase.ErrSpecializationNameExists:
+			response.Error(w, http.StatusConflict, "A specialization with this name already exists", nil)
+		case usecase.ErrSpecializationSlugExists:
+			response.Error(w, http.StatusConflict, "A specialization with this slug already exists", nil)
+		default:
+			response.InternalServerError(w, "Failed to update specialization")
+		}
+		return
+	}
+
+	response.Success(w, http.StatusOK, "Specialization updated successfully", specialization)
+}
+
+func (h *SpecializationHandler) DeleteSpecialization(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	specializationID, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid specialization ID", nil)
+		return
+	}
+
+	if err := h.specializationUsecase.DeleteSpecialization(r.Context(), specializationID); err != nil {
+		switch err {
+		case usecase.ErrSpecializationNotFound:
+			response.NotFound(w, "Specialization not found")
+		case usecase.ErrSpecializationInUse:
+			response.Error(w, http.StatusConflict, "Specialization is assigned to doctors", nil)
+		default:
+			response.InternalServerError(w, "Failed to delete specialization")
+		}
+		return
+	}
+
+	response.Success(w, http.StatusOK, "Specialization deleted successfully", nil)
+}
@@ -0,0 +1,249 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"go-template-clean-architecture/internal/delivery/dto"
+	"go-template-clean-architecture/internal/usecase"
+	"go-template-clean-architecture/pkg/response"
+	"go-template-clean-architecture/pkg/validator"
+
+	"github.com/gorilla/mux"
+)
+
+type WebhookHandler struct {
+	webhookUsecase usecase.WebhookUsecase
+	validator      *validator.CustomValidator
+}
+
+func NewWebhookHandler(webhookUsecase usecase.WebhookUsecase, validator *validator.CustomValidator) *WebhookHandler {
+	return &WebhookHandler{
+		webhookUsecase: webhookUsecase,
+		validator:      validator,
+	}
+}
+
+// CreateWebhook registers an endpoint, the response holds its signing secret (shown only once)
+func (h *WebhookHandler) CreateWebhook(w http.ResponseWriter, r *http.Request) {
+	var req dto.CreateWebhookRequest
+	if err := h.validator.DecodeJSON(r.Body, &req); err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid request body", h.validator.FormatDecodeError(err))
+		return
+	}
+
+	if err := h.validator.Validate(&req); err != nil {
+		response.ValidationError(w, h.validator.FormatValidationErrors(err))
+		return
+	}
+
+	webhook, err := h.webhookUsecase.CreateWebhook(r.Context(), &req)
+	if err != nil {
+		if err == usecase.ErrInvalidWebhookEventType {
+			response.Error(w, http.StatusBadRequest, "Unknown event type, see event_types of the webhook list", nil)
+			return
+		}
+		response.InternalServerError(w, "Failed to create webhook")
+		return
+	}
+
+	response.Success(w, http.StatusCreated, "Webhook created successfully", webhook)
+}
+
+// GetAllWebhooks lists the webhooks and the event types that can be subscribed to
+func (h *WebhookHandler) GetAllWebhooks(w http.ResponseWriter, r *http.Request) {
+	webhooks, err := h.webhookUsecase.GetAllWebhooks(r.Context())
+	if err != nil {
+		response.InternalServerError(w, "Failed to get webhooks")
+		return
+	}
+
+	response.Success(w, http.StatusOK, "Webhooks retrieved successfully", webhooks)
+}
+
+func (h *WebhookHandler) GetWebhook(w http.ResponseWriter, r *http.Request) {
+	webhookID, ok := parseWebhookID(w, r)
+	if !ok {
+		return
+	}
+
+	webhook, err := h.webhookUsecase.GetWebhook(r.Context(), webhookID)
+	if err != nil {
+		if err == usecase.ErrWebhookNotFound {
+			response.NotFound(w, "Webhook not found")
+			return
+		}
+		response.InternalServerError(w, "Failed to get webhook")
+		return
+	}
+
+	response.Success(w, http.StatusOK, "Webhook retrieved successfully", webhook)
+}
+
+func (h *WebhookHandler) UpdateWebhook(w http.ResponseWriter, r *http.Request) {
+	webhookID, ok := parseWebhookID(w, r)
+	if !ok {
+		return
+	}
+
+	var req dto.UpdateWebhookRequest
+	if err := h.validator.DecodeJSON(r.Body, &req); err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid request body", h.validator.FormatDecodeError(err))
+		return
+	}
+
+	if err := h.validator.Validate(&req); err != nil {
+		response.ValidationError(w, h.validator.FormatValidationErrors(err))
+		return
+	}
+
+	webhook, err := h.webhookUsecase.UpdateWebhook(r.Context(), webhookID, &req)
+	if err != nil {
+		switch err {
+		case usecase.ErrWebhookNotFound:
+			response.NotFound(w, "Webhook not found")
+		case usecase.ErrInvalidWebhookEventType:
+			response.Error(w, http.StatusBadRequest, "Unknown event type, see event_types of the webhook list", nil)
+		default:
+			response.InternalServerError(w, "Failed to update webhook")
+		}
+		return
+	}
+
+	response.Success(w, http.StatusOK, "Webhook updated successfully", webhook)
+}
+
+func (h *WebhookHandler) DeleteWebhook(w http.ResponseWriter, r *http.Request) {
+	webhookID, ok := parseWebhookID(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.webhookUsecase.DeleteWebhook(r.Context(), webhookID); err != nil {
+		if err == usecase.ErrWebhookNotFound {
+			response.NotFound(w, "Webhook not found")
+			return
+		}
+		response.InternalServerError(w, "Failed to delete webhook")
+		return
+	}
+
+	response.Success(w, http.StatusOK, "Webhook deleted successfully", nil)
+}
+
+// PauseWebhook stops the deliveries of a webhook. Body: {"reason": "..."}, the reason is optional
+func (h *WebhookHandler) PauseWebhook(w http.ResponseWriter, r *http.Request) {
+	webhookID, ok := parseWebhookID(w, r)
+	if !ok {
+		return
+	}
+
+	var req dto.PauseWebhookRequest
+	if err := h.validator.DecodeJSON(r.Body, &req); err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid request body", h.validator.FormatDecodeError(err))
+		return
+	}
+
+	if err := h.validator.Validate(&req); err != nil {
+		response.ValidationError(w, h.validator.FormatValidationErrors(err))
+		return
+	}
+
+	webhook, err := h.webhookUsecase.PauseWebhook(r.Context(), webhookID, &req)
+	if err != nil {
+		switch err {
+		case usecase.ErrWebhookNotFound:
+			response.NotFound(w, "Webhook not found")
+		case usecase.ErrWebhookAlreadyPaused:
+			response.Error(w, http.StatusConflict, "Webhook is already paused", nil)
+		default:
+			response.InternalServerError(w, "Failed to pause webhook")
+		}
+		return
+	}
+
+	response.Success(w, http.StatusOK, "Webhook paused successfully", webhook)
+}
+
+// ResumeWebhook reactivates a paused webhook, the deliveries held back meanwhile are sent
+func (h *WebhookHandler) ResumeWebhook(w http.ResponseWriter, r *http.Request) {
+	webhookID, ok := parseWebhookID(w, r)
+	if !ok {
+		return
+	}
+
+	webhook, err := h.webhookUsecase.ResumeWebhook(r.Context(), webhookID)
+	if err != nil {
+		switch err {
+		case usecase.ErrWebhookNotFound:
+			response.NotFound(w, "Webhook not found")
+		case usecase.ErrWebhookNotPaused:
+			response.Error(w, http.StatusConflict, "Webhook is not paused", nil)
+		default:
+			response.InternalServerError(w, "Failed to resume webhook")
+		}
+		return
+	}
+
+	response.Success(w, http.StatusOK, "Webhook resumed successfully", webhook)
+}
+
+// TestWebhook sends a webhook.test event to the endpoint and returns the delivery with the endpoint's answer
+func (h *WebhookHandler) TestWebhook(w http.ResponseWriter, r *http.Request) {
+	webhookID, ok := parseWebhookID(w, r)
+	if !ok {
+		return
+	}
+
+	delivery, err := h.webhookUsecase.TestWebhook(r.Context(), webhookID)
+	if err != nil {
+		if err == usecase.ErrWebhookNotFound {
+			response.NotFound(w, "Webhook not found")
+			return
+		}
+		response.InternalServerError(w, "Failed to test webhook")
+		return
+	}
+
+	response.Success(w, http.StatusOK, "Webhook test sent", delivery)
+}
+
+// GetDeliveries lists the delivery history of a webhook, newest first.
+// Optional query params: status (pending, succeeded, failed), page, limit
+func (h *WebhookHandler) GetDeliveries(w http.ResponseWriter, r *http.Request) {
+	webhookID, ok := parseWebhookID(w, r)
+	if !ok {
+		return
+	}
+
+	page, limit, ok := parsePagination(r)
+	if !ok {
+		response.Error(w, http.StatusBadRequest, "Invalid pagination, page and limit must be positive integers", nil)
+		return
+	}
+
+	deliveries, total, err := h.webhookUsecase.GetDeliveries(r.Context(), webhookID, r.URL.Query().Get("status"), page, limit)
+	if err != nil {
+		switch err {
+		case usecase.ErrWebhookNotFound:
+			response.NotFound(w, "Webhook not found")
+		case usecase.ErrInvalidWebhookDeliveryStatus:
+			response.Error(w, http.StatusBadRequest, "Invalid status, use pending, succeeded or failed", nil)
+		default:
+			response.InternalServerError(w, "Failed to get webhook deliveries")
+		}
+		return
+	}
+
+	response.SuccessWithMeta(w, http.StatusOK, "Webhook deliveries retrieved successfully", deliveries, newPaginationMeta(page, limit, total))
+}
+
+// parseWebhookID reads the {id} path variable, writing the error response when it is invalid
+func parseWebhookID(w http.ResponseWriter, r *http.Request) (int, bool) {
+	webhookID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid webhook ID", nil)
+		return 0, false
+	}
+	return webhookID, true
+}
@@ -0,0 +1,50 @@
+package middleware
+
+import (
+	"net/http"
+
+	"go-template-clean-architecture/internal/service"
+	"go-template-clean-architecture/pkg/response"
+)
+
+// HeaderAPIKey carries the API key of machine clients (kiosk, reporting service)
+const HeaderAPIKey = "X-API-Key"
+
+type APIKeyMiddleware struct {
+	apiKeyService service.APIKeyService
+}
+
+func NewAPIKeyMiddleware(apiKeyService service.APIKeyService) *APIKeyMiddleware {
+	return &APIKeyMiddleware{
+		apiKeyService: apiKeyService,
+	}
+}
+
+// AuthenticateOr authenticates requests with an X-API-Key header by their key, all
+// others with userAuth (the JWT middleware). API key requests have no user in context,
+// PermissionMiddleware checks the permissions of the key instead of a role.
+func (m *APIKeyMiddleware) AuthenticateOr(userAuth func(http.Handler) http.Handler) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		withUser := userAuth(next)
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key := r.Header.Get(HeaderAPIKey)
+			if key == "" {
+				withUser.ServeHTTP(w, r)
+				return
+			}
+
+			apiKey, err := m.apiKeyService.Authenticate(r.Context(), key)
+			if err != nil {
+				if err == service.ErrInvalidAPIKey {
+					response.Unauthorized(w, "Invalid, expired or revoked API key")
+					return
+				}
+				response.InternalServerError(w, "Failed to validate API key")
+				return
+			}
+
+			next.ServeHTTP(w, r.WithContext(service.ContextWithAPIKey(r.Context(), apiKey)))
+		})
+	}
+}
@@ -2,17 +2,30 @@ package middleware
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"strings"
 
+	"go-template-clean-architecture/internal/domain/entity"
+	"go-template-clean-architecture/internal/service"
 	"go-template-clean-architecture/pkg/jwt"
 	"go-template-clean-architecture/pkg/response"
 
 	"github.com/google/uuid"
 	"github.com/redis/go-redis/v9"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
 )
 
+// HeaderImpersonatedBy flags responses to requests made with an impersonation token
+const HeaderImpersonatedBy = "X-Impersonated-By"
+
+// PasswordChangeRequiredKeyPrefix marks a user who must change the password before using
+// the API: password_change_required:{userID}. Set on login and by admins while the
+// users.must_change_password flag is on, deleted by the password change.
+const PasswordChangeRequiredKeyPrefix = "password_change_required:"
+
 type contextKey string
 
 const (
@@ -20,21 +33,42 @@ const (
 	UserEmailKey contextKey = "user_email"
 	RoleIDKey    contextKey = "role_id"
 	TokenIDKey   contextKey = "token_id"
+
+	RoleNameKey    contextKey = "role_name"
+	PermissionsKey contextKey = "permissions"
 )
 
 type AuthMiddleware struct {
-	jwtService  *jwt.JWTService
-	redisClient *redis.Client
+	jwtService   *jwt.JWTService
+	redisClient  *redis.Client
+	db           *gorm.DB
+	log          *logrus.Logger
+	auditService service.AuditService
 }
 
-func NewAuthMiddleware(jwtService *jwt.JWTService, redisClient *redis.Client) *AuthMiddleware {
+func NewAuthMiddleware(jwtService *jwt.JWTService, redisClient *redis.Client, db *gorm.DB, log *logrus.Logger, auditService service.AuditService) *AuthMiddleware {
 	return &AuthMiddleware{
-		jwtService:  jwtService,
-		redisClient: redisClient,
+		jwtService:   jwtService,
+		redisClient:  redisClient,
+		db:           db,
+		log:          log,
+		auditService: auditService,
 	}
 }
 
+// Authenticate requires a valid access token. Users who must change their password
+// are refused, except on the routes behind AuthenticatePasswordChange.
 func (m *AuthMiddleware) Authenticate(next http.Handler) http.Handler {
+	return m.authenticate(next, false)
+}
+
+// AuthenticatePasswordChange is Authenticate for the routes still open to users who
+// must change their password: changing it and signing out
+func (m *AuthMiddleware) AuthenticatePasswordChange(next http.Handler) http.Handler {
+	return m.authenticate(next, true)
+}
+
+func (m *AuthMiddleware) authenticate(next http.Handler, allowPasswordChange bool) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		authHeader := r.Header.Get("Authorization")
 		if authHeader == "" {
@@ -64,28 +98,105 @@ func (m *AuthMiddleware) Authenticate(next http.Handler) http.Handler {
 			return
 		}
 
-		// Check if token exists in Redis (not revoked)
+		// Check if token exists in Redis (not revoked), whether the password must be changed
+		// and when the permissions of the role last changed
 		tokenKey := fmt.Sprintf("access_token:%s:%s", claims.UserID.String(), claims.TokenID)
-		exists, err := m.redisClient.Exists(r.Context(), tokenKey).Result()
-		if err != nil {
+		pipe := m.redisClient.Pipeline()
+		exists := pipe.Exists(r.Context(), tokenKey)
+		mustChangePassword := pipe.Exists(r.Context(), PasswordChangeRequiredKeyPrefix+claims.UserID.String())
+		permissionsChanged := pipe.Get(r.Context(), fmt.Sprintf("%s%d", service.RedisRolePermissionsChangedKeyPrefix, claims.RoleID))
+		if _, err := pipe.Exec(r.Context()); err != nil && !errors.Is(err, redis.Nil) {
 			response.InternalServerError(w, "Failed to validate token")
 			return
 		}
-		if exists == 0 {
+		if exists.Val() == 0 {
 			response.Unauthorized(w, "Token has been revoked")
 			return
 		}
 
+		// An admin impersonating the user is not asked for the user's password
+		if mustChangePassword.Val() > 0 && !allowPasswordChange && claims.ImpersonatorID == nil {
+			response.Forbidden(w, "Password change required")
+			return
+		}
+
 		// Add user info to context
 		ctx := context.WithValue(r.Context(), UserIDKey, claims.UserID)
 		ctx = context.WithValue(ctx, UserEmailKey, claims.Email)
 		ctx = context.WithValue(ctx, RoleIDKey, claims.RoleID)
 		ctx = context.WithValue(ctx, TokenIDKey, claims.TokenID)
+		if claims.RoleName != "" {
+			ctx = context.WithValue(ctx, RoleNameKey, claims.RoleName)
+			if !permissionsOutdated(claims, permissionsChanged) {
+				ctx = context.WithValue(ctx, PermissionsKey, claims.Permissions)
+			}
+		}
+
+		if claims.ImpersonatorID != nil {
+			m.serveImpersonated(w, r.WithContext(service.ContextWithImpersonator(ctx, *claims.ImpersonatorID)), next, claims)
+			return
+		}
 
 		next.ServeHTTP(w, r.WithContext(ctx))
 	})
 }
 
+// serveImpersonated flags the response and records the request in the audit log with
+// both identities, so every action of an admin acting as the user can be traced
+func (m *AuthMiddleware) serveImpersonated(w http.ResponseWriter, r *http.Request, next http.Handler, claims *jwt.Claims) {
+	w.Header().Set(HeaderImpersonatedBy, claims.ImpersonatorID.String())
+
+	recorder := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+	next.ServeHTTP(recorder, r)
+
+	userID := claims.UserID
+	ctx := service.ContextWithImpersonator(context.Background(), *claims.ImpersonatorID)
+	metadata := entity.JSON{
+		"method":   r.Method,
+		"path":     r.URL.Path,
+		"status":   recorder.status,
+		"token_id": claims.TokenID,
+	}
+	go func() {
+		if err := m.auditService.LogCreate(ctx, m.db, &userID, entity.AuditActionImpersonatedCall, "user", userID.String(), metadata); err != nil {
+			m.log.Warnf("Failed to create audit log: %+v", err)
+		}
+	}()
+}
+
+// RejectImpersonation refuses impersonation tokens on account security and admin
+// endpoints, e.g. changing 2FA or deleting the account on behalf of the user
+func (m *AuthMiddleware) RejectImpersonation(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := service.ImpersonatorFromContext(r.Context()); ok {
+			response.Forbidden(w, "Not allowed while impersonating a user")
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// permissionsOutdated reports whether the permissions of the role changed since the token
+// was issued, its embedded permissions are not used then
+func permissionsOutdated(claims *jwt.Claims, changed *redis.StringCmd) bool {
+	changedAt, err := changed.Int64()
+	if err != nil {
+		return false // Never changed (or the marker is unreadable, then the token is as good as the cache)
+	}
+	return claims.IssuedAt == nil || claims.IssuedAt.Unix() <= changedAt
+}
+
+// statusRecorder keeps the status code written by the handler
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
 // GetUserIDFromContext extracts user ID from context
 func GetUserIDFromContext(ctx context.Context) (uuid.UUID, bool) {
 	userID, ok := ctx.Value(UserIDKey).(uuid.UUID)
@@ -104,8 +215,26 @@ func GetTokenIDFromContext(ctx context.Context) (string, bool) {
 	return tokenID, ok
 }
 
+// GetImpersonatorIDFromContext extracts the admin impersonating the user, if any
+func GetImpersonatorIDFromContext(ctx context.Context) (uuid.UUID, bool) {
+	return service.ImpersonatorFromContext(ctx)
+}
+
 // GetRoleIDFromContext extracts role ID from context
 func GetRoleIDFromContext(ctx context.Context) (int, bool) {
 	roleID, ok := ctx.Value(RoleIDKey).(int)
 	return roleID, ok
 }
+
+// GetRoleNameFromContext extracts the role name from context (tokens with embedded permissions)
+func GetRoleNameFromContext(ctx context.Context) (string, bool) {
+	roleName, ok := ctx.Value(RoleNameKey).(string)
+	return roleName, ok
+}
+
+// GetPermissionsFromContext extracts the permissions embedded in the access token.
+// Not ok when the token has none or they are outdated, check the role instead.
+func GetPermissionsFromContext(ctx context.Context) ([]string, bool) {
+	permissions, ok := ctx.Value(PermissionsKey).([]string)
+	return permissions, ok
+}
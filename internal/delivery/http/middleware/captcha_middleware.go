@@ -0,0 +1,50 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+
+	"go-template-clean-architecture/pkg/captcha"
+	"go-template-clean-architecture/pkg/response"
+
+	"github.com/sirupsen/logrus"
+)
+
+// HeaderCaptchaToken carries the response token of the CAPTCHA widget
+const HeaderCaptchaToken = "X-Captcha-Token"
+
+type CaptchaMiddleware struct {
+	verifier *captcha.Verifier
+	log      *logrus.Logger
+}
+
+func NewCaptchaMiddleware(verifier *captcha.Verifier, log *logrus.Logger) *CaptchaMiddleware {
+	return &CaptchaMiddleware{
+		verifier: verifier,
+		log:      log,
+	}
+}
+
+// Require rejects requests without a valid CAPTCHA token, against bot registrations and
+// credential stuffing. Fails closed when the provider is down. No-op when CAPTCHA is disabled.
+func (m *CaptchaMiddleware) Require(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !m.verifier.Enabled() {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		info, _ := GetClientInfoFromContext(r.Context())
+		if err := m.verifier.Verify(r.Context(), r.Header.Get(HeaderCaptchaToken), info.IPAddress); err != nil {
+			if errors.Is(err, captcha.ErrInvalidToken) {
+				response.Error(w, http.StatusBadRequest, "CAPTCHA verification failed", nil)
+				return
+			}
+			m.log.Warnf("Failed to verify captcha: %+v", err)
+			response.Error(w, http.StatusServiceUnavailable, "CAPTCHA verification unavailable, try again later", nil)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
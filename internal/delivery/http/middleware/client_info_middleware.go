@@ -0,0 +1,105 @@
+package middleware
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"strings"
+)
+
+// HeaderDeviceName lets apps name the device shown in the session list (e.g. "Budi's iPhone")
+const HeaderDeviceName = "X-Device-Name"
+
+const ClientInfoKey contextKey = "client_info"
+
+// maxClientInfoLength bounds header values kept with a session
+const maxClientInfoLength = 255
+
+// ClientInfo describes the client of a request, recorded with the sessions it starts.
+// It is informational only: headers are client controlled, never use it for access decisions.
+type ClientInfo struct {
+	IPAddress string
+	UserAgent string
+	Device    string
+}
+
+type ClientInfoMiddleware struct {
+}
+
+func NewClientInfoMiddleware() *ClientInfoMiddleware {
+	return &ClientInfoMiddleware{}
+}
+
+// Handle adds the ClientInfo of the request to its context
+func (m *ClientInfoMiddleware) Handle(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		userAgent := truncateClientInfo(r.UserAgent())
+		device := truncateClientInfo(strings.TrimSpace(r.Header.Get(HeaderDeviceName)))
+		if device == "" {
+			device = deviceFromUserAgent(userAgent)
+		}
+
+		info := ClientInfo{
+			IPAddress: clientIP(r),
+			UserAgent: userAgent,
+			Device:    device,
+		}
+
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), ClientInfoKey, info)))
+	})
+}
+
+// GetClientInfoFromContext extracts the client info from context
+func GetClientInfoFromContext(ctx context.Context) (ClientInfo, bool) {
+	info, ok := ctx.Value(ClientInfoKey).(ClientInfo)
+	return info, ok
+}
+
+// clientIP prefers the address set by the reverse proxy, then the peer address
+func clientIP(r *http.Request) string {
+	if ip := strings.TrimSpace(r.Header.Get("X-Real-IP")); ip != "" {
+		return truncateClientInfo(ip)
+	}
+	if forwarded := r.Header.Get("X-Forwarded-For"); forwarded != "" {
+		first, _, _ := strings.Cut(forwarded, ",")
+		if ip := strings.TrimSpace(first); ip != "" {
+			return truncateClientInfo(ip)
+		}
+	}
+
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// deviceFromUserAgent names the platform of common browsers and apps
+func deviceFromUserAgent(userAgent string) string {
+	ua := strings.ToLower(userAgent)
+	switch {
+	case ua == "":
+		return "Unknown device"
+	case strings.Contains(ua, "iphone"):
+		return "iPhone"
+	case strings.Contains(ua, "ipad"):
+		return "iPad"
+	case strings.Contains(ua, "android"):
+		return "Android"
+	case strings.Contains(ua, "windows"):
+		return "Windows"
+	case strings.Contains(ua, "mac os"), strings.Contains(ua, "macintosh"):
+		return "Mac"
+	case strings.Contains(ua, "linux"):
+		return "Linux"
+	default:
+		return "Unknown device"
+	}
+}
+
+func truncateClientInfo(value string) string {
+	if len(value) > maxClientInfoLength {
+		return value[:maxClientInfoLength]
+	}
+	return value
+}
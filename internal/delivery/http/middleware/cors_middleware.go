@@ -13,7 +13,8 @@ func (r *CORSMiddleware) Handle(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
 		w.Header().Set("Access-Control-Allow-Origin", "*")
 		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
+		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, X-App-Platform, X-App-Version, X-Device-Name, X-API-Key, X-Captcha-Token")
+		w.Header().Set("Access-Control-Expose-Headers", "X-Impersonated-By")
 
 		if req.Method == http.MethodOptions {
 			w.WriteHeader(http.StatusOK)
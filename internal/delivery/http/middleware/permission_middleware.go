@@ -0,0 +1,65 @@
+package middleware
+
+import (
+	"net/http"
+	"slices"
+
+	"go-template-clean-architecture/internal/service"
+	"go-template-clean-architecture/pkg/response"
+)
+
+type PermissionMiddleware struct {
+	permissionService service.PermissionService
+}
+
+func NewPermissionMiddleware(permissionService service.PermissionService) *PermissionMiddleware {
+	return &PermissionMiddleware{
+		permissionService: permissionService,
+	}
+}
+
+// RequirePermission creates a middleware that checks if the role of the user has the permission.
+// The permissions embedded in the access token are used when current; otherwise the role
+// is read from context (set by AuthMiddleware from JWT claims) and checked. Requests
+// authenticated by an API key (APIKeyMiddleware) need the permission granted to the key.
+func (m *PermissionMiddleware) RequirePermission(permission string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if apiKey, ok := service.APIKeyFromContext(r.Context()); ok {
+				if !apiKey.HasPermission(permission) {
+					response.Forbidden(w, "API key does not have permission to access this resource")
+					return
+				}
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if permissions, ok := GetPermissionsFromContext(r.Context()); ok {
+				if !slices.Contains(permissions, permission) {
+					response.Forbidden(w, "You don't have permission to access this resource")
+					return
+				}
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			roleID, ok := GetRoleIDFromContext(r.Context())
+			if !ok {
+				response.Unauthorized(w, "Role information not found")
+				return
+			}
+
+			allowed, err := m.permissionService.HasPermission(r.Context(), roleID, permission)
+			if err != nil {
+				response.InternalServerError(w, "Failed to check permissions")
+				return
+			}
+			if !allowed {
+				response.Forbidden(w, "You don't have permission to access this resource")
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,38 @@
+package middleware
+
+import (
+	"net/http"
+
+	"go-template-clean-architecture/internal/service"
+)
+
+type UsageMiddleware struct {
+	usageMeter *service.UsageMeterService
+	skipPaths  map[string]bool
+}
+
+// NewUsageMiddleware creates the metering middleware.
+// skipPaths are never counted (e.g. health checks from load balancers).
+func NewUsageMiddleware(usageMeter *service.UsageMeterService, skipPaths ...string) *UsageMiddleware {
+	skip := make(map[string]bool, len(skipPaths))
+	for _, path := range skipPaths {
+		skip[path] = true
+	}
+
+	return &UsageMiddleware{
+		usageMeter: usageMeter,
+		skipPaths:  skip,
+	}
+}
+
+// Handle counts every API call for usage metering.
+// The call is handed to the usage meter's record loop, so Redis latency never delays the response.
+func (m *UsageMiddleware) Handle(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodOptions && !m.skipPaths[r.URL.Path] {
+			m.usageMeter.RecordAPICall()
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
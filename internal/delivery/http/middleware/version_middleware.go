@@ -0,0 +1,128 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"go-template-clean-architecture/config"
+	"go-template-clean-architecture/pkg/response"
+)
+
+// Client identification headers sent by the mobile/web apps
+const (
+	HeaderAppPlatform = "X-App-Platform"
+	HeaderAppVersion  = "X-App-Version"
+)
+
+// VersionGateMiddleware blocks obsolete app versions so breaking API changes
+// can be coordinated with app releases.
+type VersionGateMiddleware struct {
+	minVersions map[string]string
+	skipPaths   map[string]bool
+}
+
+// UpgradeRequiredError is the structured error of a 426 response
+type UpgradeRequiredError struct {
+	Platform       string `json:"platform"`
+	CurrentVersion string `json:"current_version"`
+	MinVersion     string `json:"min_version"`
+}
+
+// NewVersionGateMiddleware creates the version gate from the configured minimum versions per platform.
+// skipPaths are never gated (e.g. health checks from load balancers).
+func NewVersionGateMiddleware(cfg config.ClientConfig, skipPaths ...string) *VersionGateMiddleware {
+	skip := make(map[string]bool, len(skipPaths))
+	for _, path := range skipPaths {
+		skip[path] = true
+	}
+
+	return &VersionGateMiddleware{
+		minVersions: cfg.MinVersions,
+		skipPaths:   skip,
+	}
+}
+
+// Handle rejects requests from app versions below the platform minimum with 426 Upgrade Required.
+// Requests without X-App-Version, or from platforms without a minimum, pass through.
+func (m *VersionGateMiddleware) Handle(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		version := strings.TrimSpace(r.Header.Get(HeaderAppVersion))
+		if version == "" || r.Method == http.MethodOptions || m.skipPaths[r.URL.Path] {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		platform := strings.ToLower(strings.TrimSpace(r.Header.Get(HeaderAppPlatform)))
+		minVersion, gated := m.minVersions[platform]
+		if !gated {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		outdated, ok := versionLess(version, minVersion)
+		if !ok {
+			response.Error(w, http.StatusBadRequest, "Invalid X-App-Version header, use MAJOR.MINOR.PATCH", nil)
+			return
+		}
+		if outdated {
+			response.Error(w, http.StatusUpgradeRequired, "This app version is no longer supported, please update", UpgradeRequiredError{
+				Platform:       platform,
+				CurrentVersion: version,
+				MinVersion:     minVersion,
+			})
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// versionLess compares dotted numeric versions ("2.10.1" > "2.9"); missing parts count as 0.
+// A leading "v" and pre-release/build suffixes ("-beta", "+42") are ignored.
+// ok is false when either version is not numeric.
+func versionLess(version, min string) (less bool, ok bool) {
+	a, ok := parseVersion(version)
+	if !ok {
+		return false, false
+	}
+	b, ok := parseVersion(min)
+	if !ok {
+		return false, false
+	}
+
+	for i := 0; i < len(a) || i < len(b); i++ {
+		var x, y int
+		if i < len(a) {
+			x = a[i]
+		}
+		if i < len(b) {
+			y = b[i]
+		}
+		if x != y {
+			return x < y, true
+		}
+	}
+	return false, true
+}
+
+func parseVersion(version string) ([]int, bool) {
+	version = strings.TrimPrefix(strings.TrimSpace(version), "v")
+	if i := strings.IndexAny(version, "-+"); i >= 0 {
+		version = version[:i]
+	}
+	if version == "" {
+		return nil, false
+	}
+
+	parts := strings.Split(version, ".")
+	numbers := make([]int, len(parts))
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return nil, false
+		}
+		numbers[i] = n
+	}
+	return numbers, true
+}
@@ -5,20 +5,50 @@ import (
 
 	"go-template-clean-architecture/internal/delivery/http/handler"
 	"go-template-clean-architecture/internal/delivery/http/middleware"
+	"go-template-clean-architecture/internal/domain/entity"
 
 	"github.com/gorilla/mux"
 )
 
 type Router struct {
-	router                *mux.Router
-	authHandler           *handler.AuthHandler
-	doctorHandler         *handler.DoctorHandler
-	doctorScheduleHandler *handler.DoctorScheduleHandler
-	bookingHandler        *handler.BookingHandler
-	patientHandler        *handler.PatientHandler
-	authMiddleware        *middleware.AuthMiddleware
-	corsMiddleware        *middleware.CORSMiddleware
-	auditHandler          *handler.AuditLogHandler
+	router                  *mux.Router
+	authHandler             *handler.AuthHandler
+	doctorHandler           *handler.DoctorHandler
+	doctorScheduleHandler   *handler.DoctorScheduleHandler
+	bookingHandler          *handler.BookingHandler
+	patientHandler          *handler.PatientHandler
+	authMiddleware          *middleware.AuthMiddleware
+	corsMiddleware          *middleware.CORSMiddleware
+	auditHandler            *handler.AuditLogHandler
+	specDefaultHandler      *handler.SpecializationDefaultHandler
+	reportHandler           *handler.ReportHandler
+	usageMiddleware         *middleware.UsageMiddleware
+	bookingImportHandler    *handler.BookingImportHandler
+	broadcastHandler        *handler.BroadcastHandler
+	holidayHandler          *handler.HolidayHandler
+	versionMiddleware       *middleware.VersionGateMiddleware
+	logLevelHandler         *handler.LogLevelHandler
+	redisStateHandler       *handler.RedisStateHandler
+	notificationHandler     *handler.NotificationHandler
+	patientRosterHandler    *handler.PatientRosterHandler
+	bookingSagaHandler      *handler.BookingSagaHandler
+	scheduleTemplateHandler *handler.ScheduleTemplateHandler
+	roleHandler             *handler.RoleHandler
+	permissionMiddleware    *middleware.PermissionMiddleware
+	clientInfoMiddleware    *middleware.ClientInfoMiddleware
+	apiKeyHandler           *handler.APIKeyHandler
+	apiKeyMiddleware        *middleware.APIKeyMiddleware
+	captchaMiddleware       *middleware.CaptchaMiddleware
+	medicalRecordHandler    *handler.MedicalRecordHandler
+	doctorReviewHandler     *handler.DoctorReviewHandler
+	specializationHandler   *handler.SpecializationHandler
+	dataExportHandler       *handler.DataExportHandler
+	doctorLeaveHandler      *handler.DoctorLeaveHandler
+
+	notificationTemplateHandler *handler.NotificationTemplateHandler
+	webhookHandler              *handler.WebhookHandler
+	paymentHandler              *handler.PaymentHandler
+	invoiceHandler              *handler.InvoiceHandler
 }
 
 func NewRouter(
@@ -30,21 +60,81 @@ func NewRouter(
 	authMiddleware *middleware.AuthMiddleware,
 	corsMiddleware *middleware.CORSMiddleware,
 	auditHandler *handler.AuditLogHandler,
+	specDefaultHandler *handler.SpecializationDefaultHandler,
+	reportHandler *handler.ReportHandler,
+	usageMiddleware *middleware.UsageMiddleware,
+	bookingImportHandler *handler.BookingImportHandler,
+	broadcastHandler *handler.BroadcastHandler,
+	holidayHandler *handler.HolidayHandler,
+	versionMiddleware *middleware.VersionGateMiddleware,
+	logLevelHandler *handler.LogLevelHandler,
+	redisStateHandler *handler.RedisStateHandler,
+	notificationHandler *handler.NotificationHandler,
+	patientRosterHandler *handler.PatientRosterHandler,
+	bookingSagaHandler *handler.BookingSagaHandler,
+	scheduleTemplateHandler *handler.ScheduleTemplateHandler,
+	roleHandler *handler.RoleHandler,
+	permissionMiddleware *middleware.PermissionMiddleware,
+	clientInfoMiddleware *middleware.ClientInfoMiddleware,
+	apiKeyHandler *handler.APIKeyHandler,
+	apiKeyMiddleware *middleware.APIKeyMiddleware,
+	captchaMiddleware *middleware.CaptchaMiddleware,
+	medicalRecordHandler *handler.MedicalRecordHandler,
+	doctorReviewHandler *handler.DoctorReviewHandler,
+	specializationHandler *handler.SpecializationHandler,
+	dataExportHandler *handler.DataExportHandler,
+	doctorLeaveHandler *handler.DoctorLeaveHandler,
+	notificationTemplateHandler *handler.NotificationTemplateHandler,
+	webhookHandler *handler.WebhookHandler,
+	paymentHandler *handler.PaymentHandler,
+	invoiceHandler *handler.InvoiceHandler,
 ) *Router {
 	return &Router{
-		router:                mux.NewRouter(),
-		authHandler:           authHandler,
-		doctorHandler:         doctorHandler,
-		doctorScheduleHandler: doctorScheduleHandler,
-		bookingHandler:        bookingHandler,
-		patientHandler:        patientHandler,
-		authMiddleware:        authMiddleware,
-		corsMiddleware:        corsMiddleware,
-		auditHandler:          auditHandler,
+		router:                  mux.NewRouter(),
+		authHandler:             authHandler,
+		doctorHandler:           doctorHandler,
+		doctorScheduleHandler:   doctorScheduleHandler,
+		bookingHandler:          bookingHandler,
+		patientHandler:          patientHandler,
+		authMiddleware:          authMiddleware,
+		corsMiddleware:          corsMiddleware,
+		auditHandler:            auditHandler,
+		specDefaultHandler:      specDefaultHandler,
+		reportHandler:           reportHandler,
+		usageMiddleware:         usageMiddleware,
+		bookingImportHandler:    bookingImportHandler,
+		broadcastHandler:        broadcastHandler,
+		holidayHandler:          holidayHandler,
+		versionMiddleware:       versionMiddleware,
+		logLevelHandler:         logLevelHandler,
+		redisStateHandler:       redisStateHandler,
+		notificationHandler:     notificationHandler,
+		patientRosterHandler:    patientRosterHandler,
+		bookingSagaHandler:      bookingSagaHandler,
+		scheduleTemplateHandler: scheduleTemplateHandler,
+		roleHandler:             roleHandler,
+		permissionMiddleware:    permissionMiddleware,
+		clientInfoMiddleware:    clientInfoMiddleware,
+		apiKeyHandler:           apiKeyHandler,
+		apiKeyMiddleware:        apiKeyMiddleware,
+		captchaMiddleware:       captchaMiddleware,
+		medicalRecordHandler:    medicalRecordHandler,
+		doctorReviewHandler:     doctorReviewHandler,
+		specializationHandler:   specializationHandler,
+		dataExportHandler:       dataExportHandler,
+		doctorLeaveHandler:      doctorLeaveHandler,
+
+		notificationTemplateHandler: notificationTemplateHandler,
+		webhookHandler:              webhookHandler,
+		paymentHandler:              paymentHandler,
+		invoiceHandler:              invoiceHandler,
 	}
 }
 
 func (r *Router) Setup() *mux.Router {
+	// Token verification keys for other services, at the well-known location outside the versioned API
+	r.router.HandleFunc("/.well-known/jwks.json", r.authHandler.JWKS).Methods(http.MethodGet)
+
 	// API versioning
 	api := r.router.PathPrefix("/api/v1").Subrouter()
 
@@ -53,70 +143,316 @@ func (r *Router) Setup() *mux.Router {
 
 	// Auth routes (public)
 	auth := api.PathPrefix("/auth").Subrouter()
-	auth.HandleFunc("/register/patient", r.authHandler.RegisterPatient).Methods(http.MethodPost)
-	auth.HandleFunc("/register/doctor", r.authHandler.RegisterDoctor).Methods(http.MethodPost)
-	auth.HandleFunc("/login", r.authHandler.Login).Methods(http.MethodPost)
+	auth.Handle("/register/patient", r.captcha(r.authHandler.RegisterPatient)).Methods(http.MethodPost)
+	auth.Handle("/register/doctor", r.captcha(r.authHandler.RegisterDoctor)).Methods(http.MethodPost)
+	auth.Handle("/login", r.captcha(r.authHandler.Login)).Methods(http.MethodPost)
+	auth.HandleFunc("/login/2fa", r.authHandler.LoginTwoFactor).Methods(http.MethodPost)
+	auth.Handle("/login/otp/request", r.captcha(r.authHandler.RequestLoginOTP)).Methods(http.MethodPost)
+	auth.HandleFunc("/login/otp/verify", r.authHandler.VerifyLoginOTP).Methods(http.MethodPost)
 	auth.HandleFunc("/refresh-token", r.authHandler.RefreshToken).Methods(http.MethodPost)
+	auth.HandleFunc("/oauth/google", r.authHandler.GoogleLogin).Methods(http.MethodGet)
+	auth.HandleFunc("/oauth/google/callback", r.authHandler.GoogleCallback).Methods(http.MethodGet)
+	auth.Handle("/oauth/google/register", r.captcha(r.authHandler.GoogleRegister)).Methods(http.MethodPost)
+	auth.HandleFunc("/claim/request", r.patientRosterHandler.RequestClaim).Methods(http.MethodPost)
+	auth.HandleFunc("/claim/verify", r.patientRosterHandler.VerifyClaim).Methods(http.MethodPost)
 
 	// Public routes
 	public := api.PathPrefix("/").Subrouter()
-	public.HandleFunc("/doctors", r.doctorHandler.GetAllDoctors).Methods(http.MethodGet)
-	// public.HandleFunc("/doctors/{id}", r.doctorHandler.GetDoctor).Methods(http.MethodGet)
+	public.HandleFunc("/specializations", r.specializationHandler.GetAllSpecializations).Methods(http.MethodGet)
+	public.HandleFunc("/doctors", r.doctorHandler.GetPublicDoctors).Methods(http.MethodGet)
+	public.HandleFunc("/doctors/{id}", r.doctorHandler.GetPublicDoctor).Methods(http.MethodGet)
+	public.HandleFunc("/doctors/{doctorId}/availability", r.doctorScheduleHandler.GetDoctorAvailability).Methods(http.MethodGet)
+	public.HandleFunc("/doctors/{doctorId}/next-available", r.doctorScheduleHandler.GetNextAvailable).Methods(http.MethodGet)
+	public.HandleFunc("/doctors/{doctorId}/reviews", r.doctorReviewHandler.GetDoctorReviews).Methods(http.MethodGet)
 	public.HandleFunc("/schedules", r.doctorScheduleHandler.GetPublicSchedules).Methods(http.MethodGet)
 	// public.HandleFunc("/schedules/{id}", r.doctorScheduleHandler.GetSchedule).Methods(http.MethodGet)
+	public.HandleFunc("/schedules/{id}/slots", r.doctorScheduleHandler.GetScheduleSlots).Methods(http.MethodGet)
+
+	// Provider callbacks (authenticated with a shared secret)
+	public.HandleFunc("/webhooks/notifications/{provider}", r.notificationHandler.DeliveryReport).Methods(http.MethodPost)
+	public.HandleFunc("/webhooks/payments/{gateway}", r.paymentHandler.Callback).Methods(http.MethodPost)
+
+	// Auth routes (protected, also open to users who must change their password first)
+	passwordChange := api.PathPrefix("/auth").Subrouter()
+	passwordChange.Use(r.authMiddleware.AuthenticatePasswordChange)
+	passwordChange.HandleFunc("/logout", r.authHandler.Logout).Methods(http.MethodPost)
+	passwordChange.Handle("/password", r.notImpersonated(r.authHandler.ChangePassword)).Methods(http.MethodPost)
 
 	// Auth routes (protected)
 	authProtected := api.PathPrefix("/auth").Subrouter()
 	authProtected.Use(r.authMiddleware.Authenticate)
-	authProtected.HandleFunc("/logout", r.authHandler.Logout).Methods(http.MethodPost)
 	authProtected.HandleFunc("/me", r.authHandler.GetCurrentUser).Methods(http.MethodGet)
+	authProtected.HandleFunc("/sessions", r.authHandler.GetSessions).Methods(http.MethodGet)
+	authProtected.HandleFunc("/login-history", r.authHandler.GetLoginHistory).Methods(http.MethodGet)
+	authProtected.Handle("/sessions/{tokenId}", r.notImpersonated(r.authHandler.RevokeSession)).Methods(http.MethodDelete)
+	authProtected.Handle("/2fa/setup", r.notImpersonated(r.authHandler.SetupTwoFactor)).Methods(http.MethodPost)
+	authProtected.Handle("/2fa/enable", r.notImpersonated(r.authHandler.EnableTwoFactor)).Methods(http.MethodPost)
+	authProtected.Handle("/2fa/disable", r.notImpersonated(r.authHandler.DisableTwoFactor)).Methods(http.MethodPost)
 
-	// Admin routes (protected - admin only)
+	// Admin routes (protected - each route requires a permission of the user's role,
+	// or of the API key for machine clients sending X-API-Key). Impersonation tokens are
+	// refused, so an admin acting as a user cannot use that user's admin permissions.
 	admin := api.PathPrefix("/admin").Subrouter()
-	admin.Use(r.authMiddleware.Authenticate)
-	admin.Use(middleware.RequireAdmin)
+	admin.Use(r.apiKeyMiddleware.AuthenticateOr(r.authMiddleware.Authenticate))
+	admin.Use(r.authMiddleware.RejectImpersonation)
+
+	// Users, roles and permissions (admin)
+	admin.Handle("/users", r.can(entity.PermissionUserManage, r.roleHandler.CreateStaffUser)).Methods(http.MethodPost)
+	admin.Handle("/users", r.can(entity.PermissionUserManage, r.roleHandler.GetAllUsers)).Methods(http.MethodGet)
+	admin.Handle("/users/{id}/2fa", r.can(entity.PermissionUserManage, r.authHandler.ResetTwoFactor)).Methods(http.MethodDelete)
+	admin.Handle("/users/{id}/login-history", r.can(entity.PermissionUserManage, r.authHandler.GetUserLoginHistory)).Methods(http.MethodGet)
+	admin.Handle("/users/{id}/unlock", r.can(entity.PermissionUserManage, r.authHandler.UnlockUser)).Methods(http.MethodPost)
+	admin.Handle("/users/{id}/must-change-password", r.can(entity.PermissionUserManage, r.authHandler.SetMustChangePassword)).Methods(http.MethodPut)
+	admin.Handle("/users/{id}/impersonate", r.can(entity.PermissionUserImpersonate, r.authHandler.Impersonate)).Methods(http.MethodPost)
+	admin.Handle("/sessions/remember-me", r.can(entity.PermissionUserManage, r.authHandler.RevokeRememberMeSessions)).Methods(http.MethodDelete)
+	admin.Handle("/roles", r.can(entity.PermissionRoleManage, r.roleHandler.GetAllRoles)).Methods(http.MethodGet)
+	admin.Handle("/roles", r.can(entity.PermissionRoleManage, r.roleHandler.CreateRole)).Methods(http.MethodPost)
+	admin.Handle("/roles/{id}/permissions", r.can(entity.PermissionRoleManage, r.roleHandler.UpdateRolePermissions)).Methods(http.MethodPut)
+	admin.Handle("/permissions", r.can(entity.PermissionRoleManage, r.roleHandler.GetAllPermissions)).Methods(http.MethodGet)
+
+	// API keys of machine clients (admin)
+	admin.Handle("/api-keys", r.can(entity.PermissionAPIKeyManage, r.apiKeyHandler.GetAllAPIKeys)).Methods(http.MethodGet)
+	admin.Handle("/api-keys", r.can(entity.PermissionAPIKeyManage, r.apiKeyHandler.CreateAPIKey)).Methods(http.MethodPost)
+	admin.Handle("/api-keys/{id}", r.can(entity.PermissionAPIKeyManage, r.apiKeyHandler.GetAPIKey)).Methods(http.MethodGet)
+	admin.Handle("/api-keys/{id}", r.can(entity.PermissionAPIKeyManage, r.apiKeyHandler.UpdateAPIKey)).Methods(http.MethodPut)
+	admin.Handle("/api-keys/{id}", r.can(entity.PermissionAPIKeyManage, r.apiKeyHandler.RevokeAPIKey)).Methods(http.MethodDelete)
 
 	// Doctor management (admin)
-	admin.HandleFunc("/doctors", r.doctorHandler.CreateDoctor).Methods(http.MethodPost)
-	admin.HandleFunc("/doctors", r.doctorHandler.GetAllDoctors).Methods(http.MethodGet)
-	admin.HandleFunc("/doctors/{id}", r.doctorHandler.GetDoctor).Methods(http.MethodGet)
-	admin.HandleFunc("/doctors/{id}", r.doctorHandler.UpdateDoctor).Methods(http.MethodPut)
-	admin.HandleFunc("/doctors/{id}", r.doctorHandler.DeleteDoctor).Methods(http.MethodDelete)
+	admin.Handle("/doctors", r.can(entity.PermissionDoctorWrite, r.doctorHandler.CreateDoctor)).Methods(http.MethodPost)
+	admin.Handle("/doctors", r.can(entity.PermissionDoctorRead, r.doctorHandler.GetAllDoctors)).Methods(http.MethodGet)
+	admin.Handle("/doctors/{id}", r.can(entity.PermissionDoctorRead, r.doctorHandler.GetDoctor)).Methods(http.MethodGet)
+	admin.Handle("/doctors/{id}", r.can(entity.PermissionDoctorWrite, r.doctorHandler.UpdateDoctor)).Methods(http.MethodPut)
+	admin.Handle("/doctors/{id}", r.can(entity.PermissionDoctorWrite, r.doctorHandler.DeleteDoctor)).Methods(http.MethodDelete)
+	admin.Handle("/doctors/{id}/photo", r.can(entity.PermissionDoctorWrite, r.doctorHandler.UploadPhoto)).Methods(http.MethodPut)
+	admin.Handle("/doctors/{id}/photo", r.can(entity.PermissionDoctorWrite, r.doctorHandler.DeletePhoto)).Methods(http.MethodDelete)
 
 	// Schedule management (admin)
-	admin.HandleFunc("/schedules", r.doctorScheduleHandler.CreateSchedule).Methods(http.MethodPost)
-	admin.HandleFunc("/schedules", r.doctorScheduleHandler.GetAllSchedules).Methods(http.MethodGet)
-	admin.HandleFunc("/schedules/{id}", r.doctorScheduleHandler.GetSchedule).Methods(http.MethodGet)
-	admin.HandleFunc("/schedules/{id}", r.doctorScheduleHandler.UpdateSchedule).Methods(http.MethodPut)
-	admin.HandleFunc("/schedules/{id}", r.doctorScheduleHandler.DeleteSchedule).Methods(http.MethodDelete)
-	admin.HandleFunc("/doctors/{doctorId}/schedules", r.doctorScheduleHandler.GetSchedulesByDoctor).Methods(http.MethodGet)
+	admin.Handle("/schedules", r.can(entity.PermissionScheduleWrite, r.doctorScheduleHandler.CreateSchedule)).Methods(http.MethodPost)
+	admin.Handle("/schedules", r.can(entity.PermissionScheduleRead, r.doctorScheduleHandler.GetAllSchedules)).Methods(http.MethodGet)
+	admin.Handle("/schedules/copy", r.can(entity.PermissionScheduleWrite, r.doctorScheduleHandler.CopySchedules)).Methods(http.MethodPost)
+	admin.Handle("/schedules/quota", r.can(entity.PermissionScheduleWrite, r.doctorScheduleHandler.BulkUpdateQuota)).Methods(http.MethodPut)
+	admin.Handle("/schedules/possibly-absent", r.can(entity.PermissionScheduleRead, r.doctorScheduleHandler.GetPossiblyAbsentSchedules)).Methods(http.MethodGet)
+	admin.Handle("/schedules/holiday-conflicts", r.can(entity.PermissionScheduleRead, r.doctorScheduleHandler.GetHolidayFlaggedSchedules)).Methods(http.MethodGet)
+	admin.Handle("/schedules/leave-conflicts", r.can(entity.PermissionScheduleRead, r.doctorScheduleHandler.GetLeaveFlaggedSchedules)).Methods(http.MethodGet)
+	admin.Handle("/schedules/proposals", r.can(entity.PermissionScheduleRead, r.doctorScheduleHandler.GetPendingProposals)).Methods(http.MethodGet)
+	admin.Handle("/schedules/{id}", r.can(entity.PermissionScheduleRead, r.doctorScheduleHandler.GetSchedule)).Methods(http.MethodGet)
+	admin.Handle("/schedules/{id}", r.can(entity.PermissionScheduleWrite, r.doctorScheduleHandler.UpdateSchedule)).Methods(http.MethodPut)
+	admin.Handle("/schedules/{id}", r.can(entity.PermissionScheduleWrite, r.doctorScheduleHandler.DeleteSchedule)).Methods(http.MethodDelete)
+	admin.Handle("/schedules/{id}/reassign-bookings", r.can(entity.PermissionScheduleWrite, r.doctorScheduleHandler.ReassignBookings)).Methods(http.MethodPost)
+	admin.Handle("/schedules/{id}/booking-status", r.can(entity.PermissionScheduleWrite, r.doctorScheduleHandler.SetBookingOpen)).Methods(http.MethodPut)
+	admin.Handle("/schedules/{id}/approve", r.can(entity.PermissionScheduleApprove, r.doctorScheduleHandler.ApproveSchedule)).Methods(http.MethodPut)
+	admin.Handle("/schedules/{id}/reject", r.can(entity.PermissionScheduleApprove, r.doctorScheduleHandler.RejectSchedule)).Methods(http.MethodPut)
+	admin.Handle("/schedules/{id}/history", r.can(entity.PermissionScheduleRead, r.doctorScheduleHandler.GetScheduleHistory)).Methods(http.MethodGet)
+	admin.Handle("/doctors/{doctorId}/schedules", r.can(entity.PermissionScheduleRead, r.doctorScheduleHandler.GetSchedulesByDoctor)).Methods(http.MethodGet)
+	admin.Handle("/doctors/{doctorId}/schedules/calendar.ics", r.can(entity.PermissionScheduleRead, r.doctorScheduleHandler.GetDoctorScheduleCalendar)).Methods(http.MethodGet)
+
+	// Doctor leave (admin)
+	admin.Handle("/doctors/{doctorId}/leaves", r.can(entity.PermissionScheduleRead, r.doctorLeaveHandler.GetLeaves)).Methods(http.MethodGet)
+	admin.Handle("/doctors/{doctorId}/leaves", r.can(entity.PermissionScheduleWrite, r.doctorLeaveHandler.CreateLeave)).Methods(http.MethodPost)
+	admin.Handle("/doctors/{doctorId}/leaves/{id}", r.can(entity.PermissionScheduleWrite, r.doctorLeaveHandler.DeleteLeave)).Methods(http.MethodDelete)
+
+	// Schedule templates and nightly generation (admin)
+	admin.Handle("/schedule-templates", r.can(entity.PermissionScheduleWrite, r.scheduleTemplateHandler.CreateTemplate)).Methods(http.MethodPost)
+	admin.Handle("/schedule-templates", r.can(entity.PermissionScheduleRead, r.scheduleTemplateHandler.GetTemplates)).Methods(http.MethodGet)
+	admin.Handle("/schedule-templates/{id}", r.can(entity.PermissionScheduleWrite, r.scheduleTemplateHandler.UpdateTemplate)).Methods(http.MethodPut)
+	admin.Handle("/schedule-templates/{id}", r.can(entity.PermissionScheduleWrite, r.scheduleTemplateHandler.DeleteTemplate)).Methods(http.MethodDelete)
+	admin.Handle("/schedule-generation/runs", r.can(entity.PermissionScheduleWrite, r.scheduleTemplateHandler.GenerateSchedules)).Methods(http.MethodPost)
+	admin.Handle("/schedule-generation/runs", r.can(entity.PermissionScheduleRead, r.scheduleTemplateHandler.GetGenerationRuns)).Methods(http.MethodGet)
+	admin.Handle("/schedule-generation/runs/{id}", r.can(entity.PermissionScheduleRead, r.scheduleTemplateHandler.GetGenerationRun)).Methods(http.MethodGet)
+
+	// Booking management (admin)
+	admin.Handle("/bookings/import", r.can(entity.PermissionBookingWrite, r.bookingImportHandler.ImportBookings)).Methods(http.MethodPost)
+	admin.Handle("/bookings/deleted", r.can(entity.PermissionBookingRead, r.bookingHandler.GetDeletedBookings)).Methods(http.MethodGet)
+	admin.Handle("/bookings/code/{bookingCode}", r.can(entity.PermissionBookingRead, r.bookingHandler.GetBookingByCode)).Methods(http.MethodGet)
+	admin.Handle("/bookings/{id}", r.can(entity.PermissionBookingWrite, r.bookingHandler.DeleteBooking)).Methods(http.MethodDelete)
+	admin.Handle("/bookings/{id}/restore", r.can(entity.PermissionBookingWrite, r.bookingHandler.RestoreBooking)).Methods(http.MethodPost)
+	admin.Handle("/bookings/{id}/cancel", r.can(entity.PermissionBookingWrite, r.bookingHandler.AdminCancelBooking)).Methods(http.MethodPut)
+	admin.Handle("/bookings/{id}/notifications", r.can(entity.PermissionBookingRead, r.notificationHandler.GetBookingNotifications)).Methods(http.MethodGet)
+	admin.Handle("/booking-sagas", r.can(entity.PermissionBookingRead, r.bookingSagaHandler.GetAllSagas)).Methods(http.MethodGet)
+	admin.Handle("/booking-sagas/{id}", r.can(entity.PermissionBookingRead, r.bookingSagaHandler.GetSaga)).Methods(http.MethodGet)
+
+	// Notification troubleshooting and dead letters (admin)
+	admin.Handle("/notifications/dead-letters", r.can(entity.PermissionSystemManage, r.notificationHandler.GetDeadLetters)).Methods(http.MethodGet)
+	admin.Handle("/notifications/dead-letters/redrive", r.can(entity.PermissionSystemManage, r.notificationHandler.RedriveNotifications)).Methods(http.MethodPost)
+	admin.Handle("/notifications/{id}", r.can(entity.PermissionPatientRead, r.notificationHandler.GetNotification)).Methods(http.MethodGet)
+	admin.Handle("/notifications/{id}/redrive", r.can(entity.PermissionSystemManage, r.notificationHandler.RedriveNotification)).Methods(http.MethodPost)
+
+	// Patient support (admin)
+	admin.Handle("/patients/search", r.can(entity.PermissionPatientRead, r.patientHandler.SearchPatients)).Methods(http.MethodGet)
+	admin.Handle("/patients/import", r.can(entity.PermissionPatientWrite, r.patientRosterHandler.ImportRoster)).Methods(http.MethodPost)
+	admin.Handle("/patients/{id}/timeline", r.can(entity.PermissionPatientRead, r.patientHandler.GetPatientTimeline)).Methods(http.MethodGet)
+	admin.Handle("/patients/{id}/notifications", r.can(entity.PermissionPatientRead, r.notificationHandler.GetPatientNotifications)).Methods(http.MethodGet)
+	admin.Handle("/patients/{id}/insurance", r.can(entity.PermissionPatientWrite, r.patientHandler.UpdateInsurance)).Methods(http.MethodPut)
+	admin.Handle("/patients/{id}/insurance", r.can(entity.PermissionPatientWrite, r.patientHandler.RemoveInsurance)).Methods(http.MethodDelete)
+
+	// Patient broadcasts (admin)
+	admin.Handle("/broadcasts", r.can(entity.PermissionBroadcastSend, r.broadcastHandler.CreateBroadcast)).Methods(http.MethodPost)
+	admin.Handle("/broadcasts", r.can(entity.PermissionBroadcastRead, r.broadcastHandler.GetAllBroadcasts)).Methods(http.MethodGet)
+	admin.Handle("/broadcasts/{id}", r.can(entity.PermissionBroadcastRead, r.broadcastHandler.GetBroadcast)).Methods(http.MethodGet)
+
+	// Holidays / blackout dates (admin settings)
+	admin.Handle("/settings/holidays", r.can(entity.PermissionSettingsWrite, r.holidayHandler.CreateHoliday)).Methods(http.MethodPost)
+	admin.Handle("/settings/holidays", r.can(entity.PermissionSettingsRead, r.holidayHandler.GetAllHolidays)).Methods(http.MethodGet)
+	admin.Handle("/settings/holidays/{id}", r.can(entity.PermissionSettingsRead, r.holidayHandler.GetHoliday)).Methods(http.MethodGet)
+	admin.Handle("/settings/holidays/{id}", r.can(entity.PermissionSettingsWrite, r.holidayHandler.UpdateHoliday)).Methods(http.MethodPut)
+	admin.Handle("/settings/holidays/{id}", r.can(entity.PermissionSettingsWrite, r.holidayHandler.DeleteHoliday)).Methods(http.MethodDelete)
+
+	// Notification templates (admin settings)
+	admin.Handle("/settings/notification-templates", r.can(entity.PermissionSettingsWrite, r.notificationTemplateHandler.CreateTemplate)).Methods(http.MethodPost)
+	admin.Handle("/settings/notification-templates", r.can(entity.PermissionSettingsRead, r.notificationTemplateHandler.GetAllTemplates)).Methods(http.MethodGet)
+	admin.Handle("/settings/notification-templates/preview", r.can(entity.PermissionSettingsRead, r.notificationTemplateHandler.PreviewTemplate)).Methods(http.MethodPost)
+	admin.Handle("/settings/notification-templates/{id}", r.can(entity.PermissionSettingsRead, r.notificationTemplateHandler.GetTemplate)).Methods(http.MethodGet)
+	admin.Handle("/settings/notification-templates/{id}", r.can(entity.PermissionSettingsWrite, r.notificationTemplateHandler.UpdateTemplate)).Methods(http.MethodPut)
+	admin.Handle("/settings/notification-templates/{id}", r.can(entity.PermissionSettingsWrite, r.notificationTemplateHandler.DeleteTemplate)).Methods(http.MethodDelete)
+
+	// Webhook subscriptions of external systems (admin settings)
+	admin.Handle("/settings/webhooks", r.can(entity.PermissionSettingsWrite, r.webhookHandler.CreateWebhook)).Methods(http.MethodPost)
+	admin.Handle("/settings/webhooks", r.can(entity.PermissionSettingsRead, r.webhookHandler.GetAllWebhooks)).Methods(http.MethodGet)
+	admin.Handle("/settings/webhooks/{id}", r.can(entity.PermissionSettingsRead, r.webhookHandler.GetWebhook)).Methods(http.MethodGet)
+	admin.Handle("/settings/webhooks/{id}", r.can(entity.PermissionSettingsWrite, r.webhookHandler.UpdateWebhook)).Methods(http.MethodPut)
+	admin.Handle("/settings/webhooks/{id}", r.can(entity.PermissionSettingsWrite, r.webhookHandler.DeleteWebhook)).Methods(http.MethodDelete)
+	admin.Handle("/settings/webhooks/{id}/pause", r.can(entity.PermissionSettingsWrite, r.webhookHandler.PauseWebhook)).Methods(http.MethodPost)
+	admin.Handle("/settings/webhooks/{id}/resume", r.can(entity.PermissionSettingsWrite, r.webhookHandler.ResumeWebhook)).Methods(http.MethodPost)
+	admin.Handle("/settings/webhooks/{id}/test", r.can(entity.PermissionSettingsWrite, r.webhookHandler.TestWebhook)).Methods(http.MethodPost)
+	admin.Handle("/settings/webhooks/{id}/deliveries", r.can(entity.PermissionSettingsRead, r.webhookHandler.GetDeliveries)).Methods(http.MethodGet)
+
+	// Specialization taxonomy (admin settings, listed publicly at /specializations)
+	admin.Handle("/settings/specializations", r.can(entity.PermissionSettingsWrite, r.specializationHandler.CreateSpecialization)).Methods(http.MethodPost)
+	admin.Handle("/settings/specializations", r.can(entity.PermissionSettingsRead, r.specializationHandler.GetAllSpecializations)).Methods(http.MethodGet)
+	admin.Handle("/settings/specializations/{id}", r.can(entity.PermissionSettingsRead, r.specializationHandler.GetSpecialization)).Methods(http.MethodGet)
+	admin.Handle("/settings/specializations/{id}", r.can(entity.PermissionSettingsWrite, r.specializationHandler.UpdateSpecialization)).Methods(http.MethodPut)
+	admin.Handle("/settings/specializations/{id}", r.can(entity.PermissionSettingsWrite, r.specializationHandler.DeleteSpecialization)).Methods(http.MethodDelete)
+
+	// Specialization defaults (admin settings, by specialization slug)
+	admin.Handle("/settings/specialization-defaults", r.can(entity.PermissionSettingsRead, r.specDefaultHandler.GetAllDefaults)).Methods(http.MethodGet)
+	admin.Handle("/settings/specialization-defaults/{specialization}", r.can(entity.PermissionSettingsRead, r.specDefaultHandler.GetDefault)).Methods(http.MethodGet)
+	admin.Handle("/settings/specialization-defaults/{specialization}", r.can(entity.PermissionSettingsWrite, r.specDefaultHandler.UpsertDefault)).Methods(http.MethodPut)
+	admin.Handle("/settings/specialization-defaults/{specialization}", r.can(entity.PermissionSettingsWrite, r.specDefaultHandler.DeleteDefault)).Methods(http.MethodDelete)
+
+	// Runtime log levels (admin settings, reset on restart / SIGHUP reload)
+	admin.Handle("/settings/log-levels", r.can(entity.PermissionSystemManage, r.logLevelHandler.GetLogLevels)).Methods(http.MethodGet)
+	admin.Handle("/settings/log-levels/{package}", r.can(entity.PermissionSystemManage, r.logLevelHandler.SetLogLevel)).Methods(http.MethodPut)
+
+	// Redis booking counters (admin, drift inspection and manual correction)
+	admin.Handle("/redis/schedules/{id}", r.can(entity.PermissionSystemManage, r.redisStateHandler.GetScheduleState)).Methods(http.MethodGet)
+	admin.Handle("/redis/schedules/{id}", r.can(entity.PermissionSystemManage, r.redisStateHandler.UpdateScheduleState)).Methods(http.MethodPut)
+
+	// Reports (admin)
+	admin.Handle("/reports/wait-times", r.can(entity.PermissionReportRead, r.reportHandler.GetWaitTimeReport)).Methods(http.MethodGet)
+	admin.Handle("/reports/usage", r.can(entity.PermissionReportRead, r.reportHandler.GetUsageReport)).Methods(http.MethodGet)
+	admin.Handle("/reports/reminders", r.can(entity.PermissionReportRead, r.reportHandler.GetReminderReport)).Methods(http.MethodGet)
+	admin.Handle("/reports/schedule-utilization", r.can(entity.PermissionReportRead, r.reportHandler.GetScheduleUtilizationReport)).Methods(http.MethodGet)
+	admin.Handle("/doctors/{doctorId}/stats", r.can(entity.PermissionReportRead, r.reportHandler.GetDoctorStats)).Methods(http.MethodGet)
+
+	// Finance (admin)
+	admin.Handle("/reports/finance", r.can(entity.PermissionReportRead, r.invoiceHandler.GetFinanceReport)).Methods(http.MethodGet)
+	admin.Handle("/invoices", r.can(entity.PermissionReportRead, r.invoiceHandler.GetInvoices)).Methods(http.MethodGet)
+	admin.Handle("/invoices/{id}", r.can(entity.PermissionReportRead, r.invoiceHandler.GetInvoice)).Methods(http.MethodGet)
+	admin.Handle("/invoices/{id}/pdf", r.can(entity.PermissionReportRead, r.invoiceHandler.GetInvoicePDF)).Methods(http.MethodGet)
+	admin.Handle("/payments/{id}/refund", r.can(entity.PermissionBookingWrite, r.paymentHandler.RefundPayment)).Methods(http.MethodPost)
 
 	// Audit Log
-	admin.HandleFunc("/audit-logs", r.auditHandler.GetAllAuditLogs).Methods(http.MethodGet)
-	admin.HandleFunc("/audit-logs/{id}", r.auditHandler.GetAuditLog).Methods(http.MethodGet)
+	admin.Handle("/audit-logs", r.can(entity.PermissionAuditRead, r.auditHandler.GetAllAuditLogs)).Methods(http.MethodGet)
+	admin.Handle("/audit-logs/{id}", r.can(entity.PermissionAuditRead, r.auditHandler.GetAuditLog)).Methods(http.MethodGet)
 
-	// Doctor routes (protected - doctor only)
+	// Doctor routes (protected - doctor portal)
 	doctor := api.PathPrefix("/doctor").Subrouter()
 	doctor.Use(r.authMiddleware.Authenticate)
-	doctor.Use(middleware.RequireDoctor)
+	doctor.Use(r.permissionMiddleware.RequirePermission(entity.PermissionDoctorPortal))
 	doctor.HandleFunc("/schedules", r.doctorScheduleHandler.GetMySchedules).Methods(http.MethodGet)
+	doctor.HandleFunc("/schedules/calendar.ics", r.doctorScheduleHandler.GetMyScheduleCalendar).Methods(http.MethodGet)
+	doctor.HandleFunc("/schedules/proposals", r.doctorScheduleHandler.ProposeSchedule).Methods(http.MethodPost)
+	doctor.HandleFunc("/schedules/{id}/check-in", r.doctorScheduleHandler.CheckIn).Methods(http.MethodPost)
+	doctor.HandleFunc("/schedules/{id}/call-next", r.doctorScheduleHandler.CallNext).Methods(http.MethodPost)
+	doctor.HandleFunc("/schedules/{id}/summary", r.doctorScheduleHandler.GetMyScheduleSummary).Methods(http.MethodGet)
+	doctor.HandleFunc("/schedules/{id}/booking-status", r.doctorScheduleHandler.SetMyBookingOpen).Methods(http.MethodPut)
+	doctor.HandleFunc("/stats", r.reportHandler.GetMyStats).Methods(http.MethodGet)
+	doctor.HandleFunc("/leaves", r.doctorLeaveHandler.GetMyLeaves).Methods(http.MethodGet)
+	doctor.HandleFunc("/leaves", r.doctorLeaveHandler.CreateMyLeave).Methods(http.MethodPost)
+	doctor.HandleFunc("/leaves/{id}", r.doctorLeaveHandler.DeleteMyLeave).Methods(http.MethodDelete)
+	doctor.HandleFunc("/bookings/code/{bookingCode}", r.bookingHandler.GetDoctorBookingByCode).Methods(http.MethodGet)
+	doctor.HandleFunc("/notification-preferences", r.notificationHandler.GetMyNotificationPreference).Methods(http.MethodGet)
+	doctor.Handle("/notification-preferences", r.notImpersonated(r.notificationHandler.UpdateMyNotificationPreference)).Methods(http.MethodPut)
 	doctor.HandleFunc("/profile", r.doctorHandler.UpdateSelfProfile).Methods(http.MethodPut)
+	doctor.HandleFunc("/profile/photo", r.doctorHandler.UploadSelfPhoto).Methods(http.MethodPut)
+	doctor.HandleFunc("/profile/photo", r.doctorHandler.DeleteSelfPhoto).Methods(http.MethodDelete)
 
-	// Patient routes (protected - patient only)
+	// Medical records of the doctor's visits (not under impersonation)
+	doctor.Handle("/bookings/{id}/medical-record", r.notImpersonated(r.medicalRecordHandler.GetDoctorRecord)).Methods(http.MethodGet)
+	doctor.Handle("/bookings/{id}/medical-record", r.notImpersonated(r.medicalRecordHandler.CreateRecord)).Methods(http.MethodPost)
+	doctor.Handle("/bookings/{id}/medical-record", r.notImpersonated(r.medicalRecordHandler.UpdateRecord)).Methods(http.MethodPut)
+	doctor.Handle("/patients/{id}/history", r.notImpersonated(r.medicalRecordHandler.GetPatientHistory)).Methods(http.MethodGet)
+
+	// Patient routes (protected - patient portal)
 	patient := api.PathPrefix("/patient").Subrouter()
 	patient.Use(r.authMiddleware.Authenticate)
-	patient.Use(middleware.RequirePatient)
+	patient.Use(r.permissionMiddleware.RequirePermission(entity.PermissionPatientPortal))
 	patient.HandleFunc("/bookings", r.bookingHandler.GetMyBookings).Methods(http.MethodGet)
 	patient.HandleFunc("/bookings", r.bookingHandler.CreateBooking).Methods(http.MethodPost)
 	patient.HandleFunc("/bookings/{id}/cancel", r.bookingHandler.CancelBooking).Methods(http.MethodPut)
+	patient.HandleFunc("/bookings/{id}/payment", r.paymentHandler.GetMyBookingPayment).Methods(http.MethodGet)
+	patient.HandleFunc("/bookings/{id}/invoice", r.invoiceHandler.GetMyBookingInvoice).Methods(http.MethodGet)
+	patient.HandleFunc("/bookings/{id}/invoice/pdf", r.invoiceHandler.GetMyBookingInvoicePDF).Methods(http.MethodGet)
+	patient.HandleFunc("/bookings/{id}/wait-feedback", r.bookingHandler.SubmitWaitFeedback).Methods(http.MethodPost)
+	patient.Handle("/bookings/{id}/review", r.notImpersonated(r.doctorReviewHandler.SubmitReview)).Methods(http.MethodPost)
+	patient.HandleFunc("/bookings/{id}/notifications", r.notificationHandler.GetMyBookingNotifications).Methods(http.MethodGet)
+	patient.HandleFunc("/notifications", r.notificationHandler.GetMyNotifications).Methods(http.MethodGet)
+	patient.HandleFunc("/notifications/unread-count", r.notificationHandler.GetMyUnreadCount).Methods(http.MethodGet)
+	patient.Handle("/notifications/read-all", r.notImpersonated(r.notificationHandler.MarkAllNotificationsRead)).Methods(http.MethodPut)
+	patient.Handle("/notifications/{id}/read", r.notImpersonated(r.notificationHandler.MarkNotificationRead)).Methods(http.MethodPut)
+	patient.Handle("/notifications/{id}", r.notImpersonated(r.notificationHandler.DeleteMyNotification)).Methods(http.MethodDelete)
+	patient.HandleFunc("/notification-preferences", r.notificationHandler.GetMyNotificationPreference).Methods(http.MethodGet)
+	patient.Handle("/notification-preferences", r.notImpersonated(r.notificationHandler.UpdateMyNotificationPreference)).Methods(http.MethodPut)
+	patient.HandleFunc("/devices", r.notificationHandler.GetMyDevices).Methods(http.MethodGet)
+	patient.Handle("/devices", r.notImpersonated(r.notificationHandler.RegisterDevice)).Methods(http.MethodPost)
+	patient.Handle("/devices/{id}", r.notImpersonated(r.notificationHandler.UnregisterDevice)).Methods(http.MethodDelete)
+	patient.HandleFunc("/schedules/{id}/waitlist", r.bookingHandler.JoinWaitlist).Methods(http.MethodPost)
+	patient.HandleFunc("/schedules/{id}/waitlist", r.bookingHandler.LeaveWaitlist).Methods(http.MethodDelete)
 	patient.HandleFunc("/profile", r.patientHandler.UpdateSelfProfile).Methods(http.MethodPut)
+	patient.HandleFunc("/insurance", r.patientHandler.UpdateMyInsurance).Methods(http.MethodPut)
+	patient.HandleFunc("/insurance", r.patientHandler.RemoveMyInsurance).Methods(http.MethodDelete)
+	patient.HandleFunc("/emergency-contact", r.patientHandler.UpdateMyEmergencyContact).Methods(http.MethodPut)
+	patient.HandleFunc("/emergency-contact", r.patientHandler.RemoveMyEmergencyContact).Methods(http.MethodDelete)
+	patient.Handle("/account", r.notImpersonated(r.patientHandler.DeleteAccount)).Methods(http.MethodDelete)
+
+	// Personal data export, generated in the background (not under impersonation)
+	patient.Handle("/account/export", r.notImpersonated(r.dataExportHandler.RequestExport)).Methods(http.MethodGet)
+	patient.Handle("/account/export/download", r.notImpersonated(r.dataExportHandler.DownloadExport)).Methods(http.MethodGet)
+
+	// Own medical records, read only (not under impersonation)
+	patient.Handle("/medical-records", r.notImpersonated(r.medicalRecordHandler.GetMyRecords)).Methods(http.MethodGet)
+	patient.Handle("/bookings/{id}/medical-record", r.notImpersonated(r.medicalRecordHandler.GetMyRecord)).Methods(http.MethodGet)
 
 	// Add CORS middleware
 	r.router.Use(r.corsMiddleware.Handle)
 
+	// Add usage metering middleware (billing)
+	r.router.Use(r.usageMiddleware.Handle)
+
+	// Block obsolete app versions (426 Upgrade Required)
+	r.router.Use(r.versionMiddleware.Handle)
+
+	// Record the client (IP, user agent, device) for session tracking
+	r.router.Use(r.clientInfoMiddleware.Handle)
+
 	return r.router
 }
 
+// can wraps a handler with the permission its route requires
+func (r *Router) can(permission string, handler http.HandlerFunc) http.Handler {
+	return r.permissionMiddleware.RequirePermission(permission)(handler)
+}
+
+// captcha guards a public endpoint bots abuse (registration, login) with a CAPTCHA check
+func (r *Router) captcha(handler http.HandlerFunc) http.Handler {
+	return r.captchaMiddleware.Require(handler)
+}
+
+// notImpersonated keeps account security actions to the user themselves
+func (r *Router) notImpersonated(handler http.HandlerFunc) http.Handler {
+	return r.authMiddleware.RejectImpersonation(handler)
+}
+
 func (r *Router) healthCheck(w http.ResponseWriter, req *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
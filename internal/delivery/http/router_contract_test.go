@@ -0,0 +1,387 @@
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"go-template-clean-architecture/config"
+	"go-template-clean-architecture/internal/delivery/http/handler"
+	"go-template-clean-architecture/internal/delivery/http/middleware"
+	"go-template-clean-architecture/internal/domain/entity"
+	"go-template-clean-architecture/internal/service"
+	"go-template-clean-architecture/pkg/captcha"
+	"go-template-clean-architecture/pkg/jwt"
+	"go-template-clean-architecture/pkg/validator"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/redis/go-redis/v9"
+	"github.com/sirupsen/logrus"
+)
+
+// Contract tests pin what clients see of every route: the status code and the response
+// envelope (success, message, data, error, meta) for a request without credentials, with
+// credentials lacking the permission of the route, with a successful usecase and with a
+// failing one. The responses are compared with the golden files in testdata/contract,
+// regenerate them after an intended change with:
+//
+//	go test ./internal/delivery/http -run TestRouterContract -update
+var updateGolden = flag.Bool("update", false, "rewrite the golden files of the contract tests")
+
+const contractGoldenDir = "testdata/contract"
+
+// usecaseStub is the state shared by all usecase stubs: the error they return
+type usecaseStub struct {
+	err error
+}
+
+var errStubUsecase = errors.New("stub usecase failure")
+
+// stubValue is the result of a stub usecase: empty structs for pointers, empty slices and
+// readers, so success envelopes show the full shape of their data
+func stubValue[T any]() T {
+	var v T
+	value := reflect.ValueOf(&v).Elem()
+	switch t := value.Type(); {
+	case t.Kind() == reflect.Pointer:
+		value.Set(reflect.New(t.Elem()))
+	case t.Kind() == reflect.Slice && t.Elem().Kind() != reflect.Uint8:
+		value.Set(reflect.MakeSlice(t, 0, 0))
+	case t == reflect.TypeOf((*io.Reader)(nil)).Elem():
+		value.Set(reflect.ValueOf(bytes.NewReader(nil)))
+	}
+	return v
+}
+
+// contractCase is the request sent to a route
+type contractCase struct {
+	method   string
+	template string // Path template as registered on the router
+	path     string // Path requested (with query), defaults to the template
+	body     string // JSON body
+	upload   *contractUpload
+}
+
+// contractUpload is a multipart/form-data body with one file
+type contractUpload struct {
+	field    string
+	filename string
+	content  string
+	fields   map[string]string
+}
+
+func (u *contractUpload) encode(t *testing.T) (string, string) {
+	t.Helper()
+	var buf bytes.Buffer
+	form := multipart.NewWriter(&buf)
+	if err := form.SetBoundary("contract-boundary"); err != nil {
+		t.Fatalf("multipart boundary: %v", err)
+	}
+	for name, value := range u.fields {
+		if err := form.WriteField(name, value); err != nil {
+			t.Fatalf("multipart field: %v", err)
+		}
+	}
+	file, err := form.CreateFormFile(u.field, u.filename)
+	if err != nil {
+		t.Fatalf("multipart file: %v", err)
+	}
+	if _, err := io.WriteString(file, u.content); err != nil {
+		t.Fatalf("multipart file: %v", err)
+	}
+	if err := form.Close(); err != nil {
+		t.Fatalf("multipart close: %v", err)
+	}
+	return buf.String(), form.FormDataContentType()
+}
+
+func (c contractCase) requestPath() string {
+	if c.path != "" {
+		return c.path
+	}
+	return c.template
+}
+
+func (c contractCase) withBody(body string) contractCase {
+	c.body = body
+	return c
+}
+
+// contractResponse is what a golden file records of a response
+type contractResponse struct {
+	Case        string          `json:"case"`
+	Status      int             `json:"status"`
+	ContentType string          `json:"content_type,omitempty"`
+	Body        json.RawMessage `json:"body,omitempty"`
+	RawBody     string          `json:"raw_body,omitempty"` // Responses that are not JSON
+}
+
+type contractServer struct {
+	handler    http.Handler
+	stub       *usecaseStub
+	allowed    string // Token with every permission
+	notAllowed string // Token without permissions
+}
+
+func newContractServer(t *testing.T) *contractServer {
+	t.Helper()
+
+	mr := miniredis.RunT(t)
+	redisClient := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { redisClient.Close() })
+
+	log := logrus.New()
+	log.SetOutput(io.Discard)
+
+	cfg := &config.Config{}
+	cfg.App.Location = time.UTC
+
+	jwtService, err := jwt.NewJWTService(config.JWTConfig{
+		Secret:       "contract-test-secret",
+		Algorithm:    jwt.AlgorithmHS256,
+		AccessExpiry: time.Hour,
+	})
+	if err != nil {
+		t.Fatalf("jwt service: %v", err)
+	}
+	verifier, err := captcha.NewVerifier(config.CaptchaConfig{})
+	if err != nil {
+		t.Fatalf("captcha verifier: %v", err)
+	}
+
+	stub := &usecaseStub{}
+	v := validator.NewValidator(true)
+	router := NewRouter(
+		handler.NewAuthHandler(&authUsecaseStub{stub}, v, jwtService),
+		handler.NewDoctorHandler(&doctorProfileUsecaseStub{stub}, v),
+		handler.NewDoctorScheduleHandler(&doctorScheduleUsecaseStub{stub}, v),
+		handler.NewBookingHandler(&patientBookingUsecaseStub{stub}, v),
+		handler.NewPatientHandler(&patientProfileUsecaseStub{stub}, v),
+		middleware.NewAuthMiddleware(jwtService, redisClient, nil, log, nil),
+		middleware.NewCORSMiddleware(),
+		handler.NewAuditLogHandler(&auditLogUsecaseStub{stub}),
+		handler.NewSpecializationDefaultHandler(&specializationDefaultUsecaseStub{stub}, v),
+		handler.NewReportHandler(&reportUsecaseStub{stub}),
+		middleware.NewUsageMiddleware(service.NewUsageMeterService(nil, redisClient, log, cfg, nil)),
+		handler.NewBookingImportHandler(&bookingImportUsecaseStub{stub}),
+		handler.NewBroadcastHandler(&broadcastUsecaseStub{stub}, v),
+		handler.NewHolidayHandler(&holidayUsecaseStub{stub}, v),
+		middleware.NewVersionGateMiddleware(config.ClientConfig{}),
+		handler.NewLogLevelHandler(&logLevelUsecaseStub{stub}, v),
+		handler.NewRedisStateHandler(&redisStateUsecaseStub{stub}, v),
+		handler.NewNotificationHandler(&notificationUsecaseStub{stub}, v),
+		handler.NewPatientRosterHandler(&patientRosterUsecaseStub{stub}, v),
+		handler.NewBookingSagaHandler(&bookingSagaUsecaseStub{stub}),
+		handler.NewScheduleTemplateHandler(&scheduleTemplateUsecaseStub{stub}, v),
+		handler.NewRoleHandler(&roleUsecaseStub{stub}, v),
+		middleware.NewPermissionMiddleware(nil),
+		middleware.NewClientInfoMiddleware(),
+		handler.NewAPIKeyHandler(&apiKeyUsecaseStub{stub}, v),
+		middleware.NewAPIKeyMiddleware(nil),
+		middleware.NewCaptchaMiddleware(verifier, log),
+		handler.NewMedicalRecordHandler(&medicalRecordUsecaseStub{stub}, v),
+		handler.NewDoctorReviewHandler(&doctorReviewUsecaseStub{stub}, v),
+		handler.NewSpecializationHandler(&specializationUsecaseStub{stub}, v),
+		handler.NewDataExportHandler(&dataExportUsecaseStub{stub}),
+		handler.NewDoctorLeaveHandler(&doctorLeaveUsecaseStub{stub}, v),
+		handler.NewNotificationTemplateHandler(&notificationTemplateUsecaseStub{stub}, v),
+		handler.NewWebhookHandler(&webhookUsecaseStub{stub}, v),
+		handler.NewPaymentHandler(&paymentUsecaseStub{stub}),
+		handler.NewInvoiceHandler(&invoiceUsecaseStub{stub}),
+	)
+
+	server := &contractServer{handler: router.Setup(), stub: stub}
+	server.allowed = contractToken(t, mr, jwtService, allPermissions)
+	server.notAllowed = contractToken(t, mr, jwtService, []string{})
+	return server
+}
+
+// contractUserID is the user of the tokens of the contract tests
+var contractUserID = uuid.MustParse("00000000-0000-0000-0000-0000000000aa")
+
+var allPermissions = []string{
+	entity.PermissionUserManage, entity.PermissionRoleManage,
+	entity.PermissionDoctorRead, entity.PermissionDoctorWrite,
+	entity.PermissionScheduleRead, entity.PermissionScheduleWrite, entity.PermissionScheduleApprove,
+	entity.PermissionBookingRead, entity.PermissionBookingWrite,
+	entity.PermissionPatientRead, entity.PermissionPatientWrite,
+	entity.PermissionBroadcastRead, entity.PermissionBroadcastSend,
+	entity.PermissionSettingsRead, entity.PermissionSettingsWrite,
+	entity.PermissionSystemManage, entity.PermissionReportRead, entity.PermissionAuditRead,
+	entity.PermissionAPIKeyManage, entity.PermissionUserImpersonate,
+	entity.PermissionDoctorPortal, entity.PermissionPatientPortal,
+}
+
+// contractToken issues an access token with the permissions and registers it in Redis
+func contractToken(t *testing.T, mr *miniredis.Miniredis, jwtService *jwt.JWTService, permissions []string) string {
+	t.Helper()
+	token, tokenID, err := jwtService.GenerateAccessToken(contractUserID, "contract@example.com", entity.RoleIDAdmin, "contract", permissions)
+	if err != nil {
+		t.Fatalf("access token: %v", err)
+	}
+	if err := mr.Set(fmt.Sprintf("access_token:%s:%s", contractUserID, tokenID), "1"); err != nil {
+		t.Fatalf("register token: %v", err)
+	}
+	return "Bearer " + token
+}
+
+func (s *contractServer) do(t *testing.T, c contractCase, name, authorization string, usecaseErr error) contractResponse {
+	t.Helper()
+	s.stub.err = usecaseErr
+
+	body, contentType := c.body, "application/json"
+	if c.upload != nil {
+		body, contentType = c.upload.encode(t)
+	}
+	req := httptest.NewRequest(c.method, c.requestPath(), strings.NewReader(body))
+	if body != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	if authorization != "" {
+		req.Header.Set("Authorization", authorization)
+	}
+
+	rec := httptest.NewRecorder()
+	s.handler.ServeHTTP(rec, req)
+
+	res := contractResponse{
+		Case:        name,
+		Status:      rec.Code,
+		ContentType: rec.Header().Get("Content-Type"),
+	}
+	if raw := bytes.TrimSpace(rec.Body.Bytes()); json.Valid(raw) {
+		res.Body = raw
+	} else {
+		res.RawBody = string(raw)
+	}
+	return res
+}
+
+func TestRouterContract(t *testing.T) {
+	server := newContractServer(t)
+
+	registered := map[string]bool{}
+	err := server.handler.(*mux.Router).Walk(func(route *mux.Route, _ *mux.Router, _ []*mux.Route) error {
+		template, err := route.GetPathTemplate()
+		if err != nil {
+			return nil
+		}
+		methods, err := route.GetMethods()
+		if err != nil {
+			return nil // Subrouter prefix
+		}
+		for _, method := range methods {
+			registered[method+" "+template] = true
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("walk routes: %v", err)
+	}
+
+	covered := map[string]bool{}
+	for _, c := range contractCases {
+		key := c.method + " " + c.template
+		if !registered[key] {
+			t.Errorf("contract case %s is not a route", key)
+			continue
+		}
+		covered[key] = true
+
+		t.Run(key, func(t *testing.T) {
+			checkGolden(t, c, server.responses(t, c))
+		})
+	}
+	for key := range registered {
+		if !covered[key] {
+			t.Errorf("route %s has no contract case", key)
+		}
+	}
+}
+
+// responses sends the request of the case anonymously, then (when the route requires
+// credentials) without and with the permission, with a malformed and an empty JSON body
+// when it takes one, and finally with the usecase failing
+func (s *contractServer) responses(t *testing.T, c contractCase) []contractResponse {
+	anonymous := s.do(t, c, "anonymous", "", nil)
+	responses := []contractResponse{anonymous}
+
+	authorization := ""
+	if anonymous.Status == http.StatusUnauthorized {
+		authorization = s.allowed
+		responses = append(responses,
+			s.do(t, c, "not_allowed", s.notAllowed, nil),
+			s.do(t, c, "allowed", authorization, nil),
+		)
+	}
+	if c.body != "" {
+		responses = append(responses,
+			s.do(t, c.withBody("{"), "malformed_body", authorization, nil),
+			s.do(t, c.withBody("{}"), "empty_body", authorization, nil),
+		)
+	}
+	return append(responses, s.do(t, c, "usecase_error", authorization, errStubUsecase))
+}
+
+var goldenNameReplacer = regexp.MustCompile(`[^a-z0-9]+`)
+
+// goldenPath is the golden file of a route, e.g. get_admin_schedules_id.json
+func goldenPath(c contractCase) string {
+	name := strings.ToLower(c.method + " " + strings.TrimPrefix(c.template, "/api/v1"))
+	name = strings.Trim(goldenNameReplacer.ReplaceAllString(name, "_"), "_")
+	return filepath.Join(contractGoldenDir, name+".json")
+}
+
+func checkGolden(t *testing.T, c contractCase, responses []contractResponse) {
+	t.Helper()
+
+	got, err := json.MarshalIndent(struct {
+		Route     string             `json:"route"`
+		Request   string             `json:"request"`
+		Body      json.RawMessage    `json:"body,omitempty"`
+		Responses []contractResponse `json:"responses"`
+	}{
+		Route:     c.method + " " + c.template,
+		Request:   c.method + " " + c.requestPath(),
+		Body:      json.RawMessage(c.body),
+		Responses: responses,
+	}, "", "  ")
+	if err != nil {
+		t.Fatalf("marshal responses: %v", err)
+	}
+	got = append(got, '\n')
+
+	path := goldenPath(c)
+	if *updateGolden {
+		if err := os.MkdirAll(contractGoldenDir, 0o755); err != nil {
+			t.Fatalf("create golden dir: %v", err)
+		}
+		if err := os.WriteFile(path, got, 0o644); err != nil {
+			t.Fatalf("write golden file: %v", err)
+		}
+		return
+	}
+
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read golden file (run with -update to create it): %v", err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("response contract of %s %s changed, got:\n%s\nwant (%s):\n%s", c.method, c.template, got, path, want)
+	}
+}
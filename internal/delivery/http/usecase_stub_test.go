@@ -0,0 +1,824 @@
+package http
+
+import (
+	"context"
+	"io"
+	"net/http"
+
+	"go-template-clean-architecture/internal/delivery/dto"
+	"go-template-clean-architecture/internal/domain/entity"
+
+	"github.com/google/uuid"
+)
+
+// Usecase stubs of the contract tests. Each method returns an empty value of its
+// result (see stubValue) and the configured error.
+
+type apiKeyUsecaseStub struct{ *usecaseStub }
+
+func (s *apiKeyUsecaseStub) GetAllAPIKeys(ctx context.Context) ([]dto.APIKeyResponse, error) {
+	return stubValue[[]dto.APIKeyResponse](), s.err
+}
+
+func (s *apiKeyUsecaseStub) GetAPIKey(ctx context.Context, id uuid.UUID) (*dto.APIKeyResponse, error) {
+	return stubValue[*dto.APIKeyResponse](), s.err
+}
+
+func (s *apiKeyUsecaseStub) CreateAPIKey(ctx context.Context, req *dto.CreateAPIKeyRequest) (*dto.CreateAPIKeyResponse, error) {
+	return stubValue[*dto.CreateAPIKeyResponse](), s.err
+}
+
+func (s *apiKeyUsecaseStub) UpdateAPIKey(ctx context.Context, id uuid.UUID, req *dto.UpdateAPIKeyRequest) (*dto.APIKeyResponse, error) {
+	return stubValue[*dto.APIKeyResponse](), s.err
+}
+
+func (s *apiKeyUsecaseStub) RevokeAPIKey(ctx context.Context, id uuid.UUID) error {
+	return s.err
+}
+
+type auditLogUsecaseStub struct{ *usecaseStub }
+
+func (s *auditLogUsecaseStub) GetAllAuditLogs(ctx context.Context) (*dto.AuditLogListResponse, error) {
+	return stubValue[*dto.AuditLogListResponse](), s.err
+}
+
+func (s *auditLogUsecaseStub) GetAuditLog(ctx context.Context, id int64) (*dto.AuditLogResponse, error) {
+	return stubValue[*dto.AuditLogResponse](), s.err
+}
+
+type authUsecaseStub struct{ *usecaseStub }
+
+func (s *authUsecaseStub) Register(ctx context.Context, user *entity.User) (*dto.UserResponse, error) {
+	return stubValue[*dto.UserResponse](), s.err
+}
+
+func (s *authUsecaseStub) Login(ctx context.Context, req *dto.LoginRequest) (*dto.LoginResponse, error) {
+	return stubValue[*dto.LoginResponse](), s.err
+}
+
+func (s *authUsecaseStub) LoginTwoFactor(ctx context.Context, req *dto.LoginTwoFactorRequest) (*dto.TokenResponse, error) {
+	return stubValue[*dto.TokenResponse](), s.err
+}
+
+func (s *authUsecaseStub) Logout(ctx context.Context, accessTokenID, refreshTokenID string) error {
+	return s.err
+}
+
+func (s *authUsecaseStub) RefreshToken(ctx context.Context, req *dto.RefreshTokenRequest) (*dto.TokenResponse, error) {
+	return stubValue[*dto.TokenResponse](), s.err
+}
+
+func (s *authUsecaseStub) GetCurrentUser(ctx context.Context, userID uuid.UUID) (*dto.UserResponse, error) {
+	return stubValue[*dto.UserResponse](), s.err
+}
+
+func (s *authUsecaseStub) SetupTwoFactor(ctx context.Context, userID uuid.UUID) (*dto.TwoFactorSetupResponse, error) {
+	return stubValue[*dto.TwoFactorSetupResponse](), s.err
+}
+
+func (s *authUsecaseStub) EnableTwoFactor(ctx context.Context, userID uuid.UUID, req *dto.EnableTwoFactorRequest) (*dto.TwoFactorBackupCodesResponse, error) {
+	return stubValue[*dto.TwoFactorBackupCodesResponse](), s.err
+}
+
+func (s *authUsecaseStub) DisableTwoFactor(ctx context.Context, userID uuid.UUID, req *dto.DisableTwoFactorRequest) error {
+	return s.err
+}
+
+func (s *authUsecaseStub) ResetTwoFactor(ctx context.Context, adminID, userID uuid.UUID) error {
+	return s.err
+}
+
+func (s *authUsecaseStub) UnlockUser(ctx context.Context, adminID, userID uuid.UUID) error {
+	return s.err
+}
+
+func (s *authUsecaseStub) ChangePassword(ctx context.Context, userID uuid.UUID, req *dto.ChangePasswordRequest) (*dto.TokenResponse, error) {
+	return stubValue[*dto.TokenResponse](), s.err
+}
+
+func (s *authUsecaseStub) SetMustChangePassword(ctx context.Context, adminID, userID uuid.UUID, required bool) error {
+	return s.err
+}
+
+func (s *authUsecaseStub) Impersonate(ctx context.Context, adminID, userID uuid.UUID, req *dto.ImpersonateRequest) (*dto.ImpersonationResponse, error) {
+	return stubValue[*dto.ImpersonationResponse](), s.err
+}
+
+func (s *authUsecaseStub) GetSessions(ctx context.Context, userID uuid.UUID, currentAccessTokenID string) ([]dto.SessionResponse, error) {
+	return stubValue[[]dto.SessionResponse](), s.err
+}
+
+func (s *authUsecaseStub) GetLoginHistory(ctx context.Context, userID uuid.UUID, page, limit int) ([]dto.LoginHistoryResponse, int64, error) {
+	return stubValue[[]dto.LoginHistoryResponse](), stubValue[int64](), s.err
+}
+
+func (s *authUsecaseStub) RevokeSession(ctx context.Context, userID uuid.UUID, tokenID string) error {
+	return s.err
+}
+
+func (s *authUsecaseStub) RevokeRememberMeSessions(ctx context.Context, adminID uuid.UUID) (*dto.RevokeRememberMeSessionsResponse, error) {
+	return stubValue[*dto.RevokeRememberMeSessionsResponse](), s.err
+}
+
+func (s *authUsecaseStub) GoogleAuthURL(ctx context.Context) (string, error) {
+	return stubValue[string](), s.err
+}
+
+func (s *authUsecaseStub) GoogleCallback(ctx context.Context, code, state string) (*dto.OAuthLoginResponse, error) {
+	// Logins always carry the embedded LoginResponse, unlike registrations
+	return &dto.OAuthLoginResponse{LoginResponse: stubValue[*dto.LoginResponse]()}, s.err
+}
+
+func (s *authUsecaseStub) GoogleRegister(ctx context.Context, req *dto.GoogleRegisterRequest) (*dto.LoginResponse, error) {
+	return stubValue[*dto.LoginResponse](), s.err
+}
+
+func (s *authUsecaseStub) RequestLoginOTP(ctx context.Context, req *dto.RequestLoginOTPRequest) error {
+	return s.err
+}
+
+func (s *authUsecaseStub) VerifyLoginOTP(ctx context.Context, req *dto.VerifyLoginOTPRequest) (*dto.LoginResponse, error) {
+	return stubValue[*dto.LoginResponse](), s.err
+}
+
+type patientBookingUsecaseStub struct{ *usecaseStub }
+
+func (s *patientBookingUsecaseStub) GetMyBookings(ctx context.Context) (*dto.BookingListResponse, error) {
+	return stubValue[*dto.BookingListResponse](), s.err
+}
+
+func (s *patientBookingUsecaseStub) CreateBooking(ctx context.Context, req *dto.CreateBookingRequest) (*dto.BookingResponse, error) {
+	return stubValue[*dto.BookingResponse](), s.err
+}
+
+func (s *patientBookingUsecaseStub) CancelBooking(ctx context.Context, bookingID uuid.UUID) error {
+	return s.err
+}
+
+func (s *patientBookingUsecaseStub) AdminCancelBooking(ctx context.Context, bookingID uuid.UUID) error {
+	return s.err
+}
+
+func (s *patientBookingUsecaseStub) DeleteBooking(ctx context.Context, bookingID uuid.UUID) error {
+	return s.err
+}
+
+func (s *patientBookingUsecaseStub) GetDeletedBookings(ctx context.Context, page, limit int) ([]dto.BookingResponse, int64, error) {
+	return stubValue[[]dto.BookingResponse](), stubValue[int64](), s.err
+}
+
+func (s *patientBookingUsecaseStub) RestoreBooking(ctx context.Context, bookingID uuid.UUID) (*dto.BookingResponse, error) {
+	return stubValue[*dto.BookingResponse](), s.err
+}
+
+func (s *patientBookingUsecaseStub) GetBookingByCode(ctx context.Context, bookingCode string) (*dto.BookingResponse, error) {
+	return stubValue[*dto.BookingResponse](), s.err
+}
+
+func (s *patientBookingUsecaseStub) GetDoctorBookingByCode(ctx context.Context, bookingCode string) (*dto.BookingResponse, error) {
+	return stubValue[*dto.BookingResponse](), s.err
+}
+
+func (s *patientBookingUsecaseStub) SubmitWaitFeedback(ctx context.Context, bookingID uuid.UUID, req *dto.WaitFeedbackRequest) (*dto.WaitFeedbackResponse, error) {
+	return stubValue[*dto.WaitFeedbackResponse](), s.err
+}
+
+func (s *patientBookingUsecaseStub) JoinWaitlist(ctx context.Context, scheduleID int) (*dto.WaitlistResponse, error) {
+	return stubValue[*dto.WaitlistResponse](), s.err
+}
+
+func (s *patientBookingUsecaseStub) LeaveWaitlist(ctx context.Context, scheduleID int) error {
+	return s.err
+}
+
+type bookingImportUsecaseStub struct{ *usecaseStub }
+
+func (s *bookingImportUsecaseStub) ImportBookings(ctx context.Context, file io.Reader, dryRun bool) (*dto.BookingImportResponse, error) {
+	return stubValue[*dto.BookingImportResponse](), s.err
+}
+
+type bookingSagaUsecaseStub struct{ *usecaseStub }
+
+func (s *bookingSagaUsecaseStub) GetAllSagas(ctx context.Context, status string, page, limit int) ([]dto.BookingSagaResponse, int64, error) {
+	return stubValue[[]dto.BookingSagaResponse](), stubValue[int64](), s.err
+}
+
+func (s *bookingSagaUsecaseStub) GetSaga(ctx context.Context, id uuid.UUID) (*dto.BookingSagaResponse, error) {
+	return stubValue[*dto.BookingSagaResponse](), s.err
+}
+
+type broadcastUsecaseStub struct{ *usecaseStub }
+
+func (s *broadcastUsecaseStub) CreateBroadcast(ctx context.Context, req *dto.CreateBroadcastRequest) (*dto.BroadcastResponse, error) {
+	return stubValue[*dto.BroadcastResponse](), s.err
+}
+
+func (s *broadcastUsecaseStub) PreviewBroadcast(ctx context.Context, req *dto.CreateBroadcastRequest) (*dto.BroadcastPreviewResponse, error) {
+	return stubValue[*dto.BroadcastPreviewResponse](), s.err
+}
+
+func (s *broadcastUsecaseStub) GetBroadcast(ctx context.Context, id uuid.UUID) (*dto.BroadcastResponse, error) {
+	return stubValue[*dto.BroadcastResponse](), s.err
+}
+
+func (s *broadcastUsecaseStub) GetAllBroadcasts(ctx context.Context) (*dto.BroadcastListResponse, error) {
+	return stubValue[*dto.BroadcastListResponse](), s.err
+}
+
+type dataExportUsecaseStub struct{ *usecaseStub }
+
+func (s *dataExportUsecaseStub) RequestExport(ctx context.Context, refresh bool) (*dto.DataExportResponse, error) {
+	return stubValue[*dto.DataExportResponse](), s.err
+}
+
+func (s *dataExportUsecaseStub) DownloadExport(ctx context.Context) ([]byte, error) {
+	return stubValue[[]byte](), s.err
+}
+
+type doctorProfileUsecaseStub struct{ *usecaseStub }
+
+func (s *doctorProfileUsecaseStub) CreateDoctor(ctx context.Context, req *dto.CreateDoctorRequest) (*dto.DoctorResponse, error) {
+	return stubValue[*dto.DoctorResponse](), s.err
+}
+
+func (s *doctorProfileUsecaseStub) GetDoctor(ctx context.Context, doctorID uuid.UUID) (*dto.DoctorResponse, error) {
+	return stubValue[*dto.DoctorResponse](), s.err
+}
+
+func (s *doctorProfileUsecaseStub) GetPublicDoctor(ctx context.Context, doctorID uuid.UUID, scheduleLimit int) (*dto.DoctorDetailResponse, error) {
+	return stubValue[*dto.DoctorDetailResponse](), s.err
+}
+
+func (s *doctorProfileUsecaseStub) GetAllDoctors(ctx context.Context, filter *dto.DoctorFilter, page, limit int) (*dto.DoctorListResponse, int64, error) {
+	return stubValue[*dto.DoctorListResponse](), stubValue[int64](), s.err
+}
+
+func (s *doctorProfileUsecaseStub) GetPublicDoctors(ctx context.Context, filter *dto.DoctorFilter, page, limit int) (*dto.DoctorListResponse, int64, error) {
+	return stubValue[*dto.DoctorListResponse](), stubValue[int64](), s.err
+}
+
+func (s *doctorProfileUsecaseStub) UpdateDoctor(ctx context.Context, doctorID uuid.UUID, req *dto.UpdateDoctorRequest) (*dto.DoctorResponse, error) {
+	return stubValue[*dto.DoctorResponse](), s.err
+}
+
+func (s *doctorProfileUsecaseStub) UpdateSelfProfile(ctx context.Context, doctorID uuid.UUID, req *dto.DoctorUpdateSelfRequest) (*dto.DoctorResponse, error) {
+	return stubValue[*dto.DoctorResponse](), s.err
+}
+
+func (s *doctorProfileUsecaseStub) DeleteDoctor(ctx context.Context, doctorID uuid.UUID) error {
+	return s.err
+}
+
+func (s *doctorProfileUsecaseStub) UpdatePhoto(ctx context.Context, doctorID uuid.UUID, data []byte) (*dto.DoctorResponse, error) {
+	return stubValue[*dto.DoctorResponse](), s.err
+}
+
+func (s *doctorProfileUsecaseStub) DeletePhoto(ctx context.Context, doctorID uuid.UUID) (*dto.DoctorResponse, error) {
+	return stubValue[*dto.DoctorResponse](), s.err
+}
+
+type doctorLeaveUsecaseStub struct{ *usecaseStub }
+
+func (s *doctorLeaveUsecaseStub) CreateLeave(ctx context.Context, doctorID uuid.UUID, req *dto.CreateDoctorLeaveRequest) (*dto.DoctorLeaveResponse, error) {
+	return stubValue[*dto.DoctorLeaveResponse](), s.err
+}
+
+func (s *doctorLeaveUsecaseStub) GetLeaves(ctx context.Context, doctorID uuid.UUID, from, to string) (*dto.DoctorLeaveListResponse, error) {
+	return stubValue[*dto.DoctorLeaveListResponse](), s.err
+}
+
+func (s *doctorLeaveUsecaseStub) DeleteLeave(ctx context.Context, doctorID uuid.UUID, id int) error {
+	return s.err
+}
+
+type doctorReviewUsecaseStub struct{ *usecaseStub }
+
+func (s *doctorReviewUsecaseStub) SubmitReview(ctx context.Context, bookingID uuid.UUID, req *dto.DoctorReviewRequest) (*dto.DoctorReviewResponse, error) {
+	return stubValue[*dto.DoctorReviewResponse](), s.err
+}
+
+func (s *doctorReviewUsecaseStub) GetDoctorReviews(ctx context.Context, doctorID uuid.UUID, page, limit int) ([]dto.DoctorReviewItemResponse, int64, error) {
+	return stubValue[[]dto.DoctorReviewItemResponse](), stubValue[int64](), s.err
+}
+
+type doctorScheduleUsecaseStub struct{ *usecaseStub }
+
+func (s *doctorScheduleUsecaseStub) CreateSchedule(ctx context.Context, req *dto.CreateScheduleRequest) (*dto.ScheduleResponse, error) {
+	return stubValue[*dto.ScheduleResponse](), s.err
+}
+
+func (s *doctorScheduleUsecaseStub) ProposeSchedule(ctx context.Context, req *dto.ProposeScheduleRequest) (*dto.ScheduleResponse, error) {
+	return stubValue[*dto.ScheduleResponse](), s.err
+}
+
+func (s *doctorScheduleUsecaseStub) GetPendingProposals(ctx context.Context, page, limit int) (*dto.ScheduleListResponse, int64, error) {
+	return stubValue[*dto.ScheduleListResponse](), stubValue[int64](), s.err
+}
+
+func (s *doctorScheduleUsecaseStub) ApproveSchedule(ctx context.Context, scheduleID int) (*dto.ScheduleResponse, error) {
+	return stubValue[*dto.ScheduleResponse](), s.err
+}
+
+func (s *doctorScheduleUsecaseStub) RejectSchedule(ctx context.Context, scheduleID int, req *dto.RejectScheduleRequest) (*dto.ScheduleResponse, error) {
+	return stubValue[*dto.ScheduleResponse](), s.err
+}
+
+func (s *doctorScheduleUsecaseStub) GetSchedule(ctx context.Context, scheduleID int) (*dto.ScheduleResponse, error) {
+	return stubValue[*dto.ScheduleResponse](), s.err
+}
+
+func (s *doctorScheduleUsecaseStub) GetScheduleHistory(ctx context.Context, scheduleID int) (*dto.ScheduleHistoryResponse, error) {
+	return stubValue[*dto.ScheduleHistoryResponse](), s.err
+}
+
+func (s *doctorScheduleUsecaseStub) GetSchedulesByDoctor(ctx context.Context, doctorID uuid.UUID, page, limit int) (*dto.ScheduleListResponse, int64, error) {
+	return stubValue[*dto.ScheduleListResponse](), stubValue[int64](), s.err
+}
+
+func (s *doctorScheduleUsecaseStub) GetAllSchedules(ctx context.Context, filter *dto.ScheduleFilter, page, limit int) (*dto.ScheduleListResponse, int64, error) {
+	return stubValue[*dto.ScheduleListResponse](), stubValue[int64](), s.err
+}
+
+func (s *doctorScheduleUsecaseStub) GetPublicSchedules(ctx context.Context, filter *dto.ScheduleFilter, page, limit int) (*dto.ScheduleListResponse, int64, error) {
+	return stubValue[*dto.ScheduleListResponse](), stubValue[int64](), s.err
+}
+
+func (s *doctorScheduleUsecaseStub) GetDoctorAvailability(ctx context.Context, doctorID uuid.UUID, month string) (*dto.DoctorAvailabilityResponse, error) {
+	return stubValue[*dto.DoctorAvailabilityResponse](), s.err
+}
+
+func (s *doctorScheduleUsecaseStub) GetNextAvailable(ctx context.Context, doctorID uuid.UUID) (*dto.NextAvailableResponse, error) {
+	return stubValue[*dto.NextAvailableResponse](), s.err
+}
+
+func (s *doctorScheduleUsecaseStub) UpdateSchedule(ctx context.Context, scheduleID int, req *dto.UpdateScheduleRequest) (*dto.ScheduleResponse, error) {
+	return stubValue[*dto.ScheduleResponse](), s.err
+}
+
+func (s *doctorScheduleUsecaseStub) DeleteSchedule(ctx context.Context, scheduleID int, force bool) (*dto.DeleteScheduleResponse, error) {
+	return stubValue[*dto.DeleteScheduleResponse](), s.err
+}
+
+func (s *doctorScheduleUsecaseStub) CheckIn(ctx context.Context, scheduleID int) (*dto.ScheduleResponse, error) {
+	return stubValue[*dto.ScheduleResponse](), s.err
+}
+
+func (s *doctorScheduleUsecaseStub) SetBookingOpen(ctx context.Context, scheduleID int, isOpen bool) (*dto.ScheduleResponse, error) {
+	return stubValue[*dto.ScheduleResponse](), s.err
+}
+
+func (s *doctorScheduleUsecaseStub) SetMyBookingOpen(ctx context.Context, scheduleID int, isOpen bool) (*dto.ScheduleResponse, error) {
+	return stubValue[*dto.ScheduleResponse](), s.err
+}
+
+func (s *doctorScheduleUsecaseStub) GetPossiblyAbsentSchedules(ctx context.Context) (*dto.ScheduleListResponse, error) {
+	return stubValue[*dto.ScheduleListResponse](), s.err
+}
+
+func (s *doctorScheduleUsecaseStub) GetHolidayFlaggedSchedules(ctx context.Context) (*dto.ScheduleListResponse, error) {
+	return stubValue[*dto.ScheduleListResponse](), s.err
+}
+
+func (s *doctorScheduleUsecaseStub) GetLeaveFlaggedSchedules(ctx context.Context) (*dto.ScheduleListResponse, error) {
+	return stubValue[*dto.ScheduleListResponse](), s.err
+}
+
+func (s *doctorScheduleUsecaseStub) ReassignBookings(ctx context.Context, scheduleID int, req *dto.ReassignBookingsRequest) (*dto.ReassignBookingsResponse, error) {
+	return stubValue[*dto.ReassignBookingsResponse](), s.err
+}
+
+func (s *doctorScheduleUsecaseStub) CallNext(ctx context.Context, scheduleID int) (*dto.BookingResponse, error) {
+	return stubValue[*dto.BookingResponse](), s.err
+}
+
+func (s *doctorScheduleUsecaseStub) GetScheduleSlots(ctx context.Context, scheduleID int) (*dto.ScheduleSlotListResponse, error) {
+	return stubValue[*dto.ScheduleSlotListResponse](), s.err
+}
+
+func (s *doctorScheduleUsecaseStub) GetMyScheduleSummary(ctx context.Context, scheduleID int) (*dto.ScheduleSummaryResponse, error) {
+	return stubValue[*dto.ScheduleSummaryResponse](), s.err
+}
+
+func (s *doctorScheduleUsecaseStub) CopySchedules(ctx context.Context, req *dto.CopySchedulesRequest) (*dto.CopySchedulesResponse, error) {
+	return stubValue[*dto.CopySchedulesResponse](), s.err
+}
+
+func (s *doctorScheduleUsecaseStub) BulkUpdateQuota(ctx context.Context, req *dto.BulkUpdateQuotaRequest) (*dto.BulkUpdateQuotaResponse, error) {
+	return stubValue[*dto.BulkUpdateQuotaResponse](), s.err
+}
+
+func (s *doctorScheduleUsecaseStub) GetScheduleCalendar(ctx context.Context, doctorID uuid.UUID) ([]byte, error) {
+	return stubValue[[]byte](), s.err
+}
+
+type holidayUsecaseStub struct{ *usecaseStub }
+
+func (s *holidayUsecaseStub) CreateHoliday(ctx context.Context, req *dto.CreateHolidayRequest) (*dto.HolidayResponse, error) {
+	return stubValue[*dto.HolidayResponse](), s.err
+}
+
+func (s *holidayUsecaseStub) GetHoliday(ctx context.Context, id int) (*dto.HolidayResponse, error) {
+	return stubValue[*dto.HolidayResponse](), s.err
+}
+
+func (s *holidayUsecaseStub) GetAllHolidays(ctx context.Context, from, to string) (*dto.HolidayListResponse, error) {
+	return stubValue[*dto.HolidayListResponse](), s.err
+}
+
+func (s *holidayUsecaseStub) UpdateHoliday(ctx context.Context, id int, req *dto.UpdateHolidayRequest) (*dto.HolidayResponse, error) {
+	return stubValue[*dto.HolidayResponse](), s.err
+}
+
+func (s *holidayUsecaseStub) DeleteHoliday(ctx context.Context, id int) error {
+	return s.err
+}
+
+type invoiceUsecaseStub struct{ *usecaseStub }
+
+func (s *invoiceUsecaseStub) GetMyBookingInvoice(ctx context.Context, bookingID uuid.UUID) (*dto.InvoiceResponse, error) {
+	return stubValue[*dto.InvoiceResponse](), s.err
+}
+
+func (s *invoiceUsecaseStub) GetMyBookingInvoicePDF(ctx context.Context, bookingID uuid.UUID) ([]byte, string, error) {
+	return stubValue[[]byte](), stubValue[string](), s.err
+}
+
+func (s *invoiceUsecaseStub) GetInvoice(ctx context.Context, id uuid.UUID) (*dto.InvoiceResponse, error) {
+	return stubValue[*dto.InvoiceResponse](), s.err
+}
+
+func (s *invoiceUsecaseStub) GetInvoicePDF(ctx context.Context, id uuid.UUID) ([]byte, string, error) {
+	return stubValue[[]byte](), stubValue[string](), s.err
+}
+
+func (s *invoiceUsecaseStub) GetInvoices(ctx context.Context, filter *dto.InvoiceFilter, page, limit int) ([]dto.InvoiceResponse, int64, error) {
+	return stubValue[[]dto.InvoiceResponse](), stubValue[int64](), s.err
+}
+
+func (s *invoiceUsecaseStub) GetFinanceReport(ctx context.Context, filter *dto.InvoiceFilter) (*dto.FinanceReportResponse, error) {
+	return stubValue[*dto.FinanceReportResponse](), s.err
+}
+
+type logLevelUsecaseStub struct{ *usecaseStub }
+
+func (s *logLevelUsecaseStub) GetLogLevels(ctx context.Context) *dto.LogLevelsResponse {
+	return stubValue[*dto.LogLevelsResponse]()
+}
+
+func (s *logLevelUsecaseStub) SetLogLevel(ctx context.Context, pkg string, req *dto.SetLogLevelRequest) (*dto.LogLevelsResponse, error) {
+	return stubValue[*dto.LogLevelsResponse](), s.err
+}
+
+type medicalRecordUsecaseStub struct{ *usecaseStub }
+
+func (s *medicalRecordUsecaseStub) CreateRecord(ctx context.Context, bookingID uuid.UUID, req *dto.MedicalRecordRequest) (*dto.MedicalRecordResponse, error) {
+	return stubValue[*dto.MedicalRecordResponse](), s.err
+}
+
+func (s *medicalRecordUsecaseStub) UpdateRecord(ctx context.Context, bookingID uuid.UUID, req *dto.UpdateMedicalRecordRequest) (*dto.MedicalRecordResponse, error) {
+	return stubValue[*dto.MedicalRecordResponse](), s.err
+}
+
+func (s *medicalRecordUsecaseStub) GetDoctorRecord(ctx context.Context, bookingID uuid.UUID) (*dto.MedicalRecordResponse, error) {
+	return stubValue[*dto.MedicalRecordResponse](), s.err
+}
+
+func (s *medicalRecordUsecaseStub) GetMyRecord(ctx context.Context, bookingID uuid.UUID) (*dto.MedicalRecordResponse, error) {
+	return stubValue[*dto.MedicalRecordResponse](), s.err
+}
+
+func (s *medicalRecordUsecaseStub) GetMyRecords(ctx context.Context, page, limit int) ([]dto.MedicalRecordResponse, int64, error) {
+	return stubValue[[]dto.MedicalRecordResponse](), stubValue[int64](), s.err
+}
+
+func (s *medicalRecordUsecaseStub) GetPatientHistory(ctx context.Context, patientID uuid.UUID, page, limit int) (*dto.PatientHistoryResponse, int64, error) {
+	return stubValue[*dto.PatientHistoryResponse](), stubValue[int64](), s.err
+}
+
+type notificationUsecaseStub struct{ *usecaseStub }
+
+func (s *notificationUsecaseStub) HandleDeliveryReport(ctx context.Context, provider string, secret string, req *dto.NotificationDeliveryReportRequest) error {
+	return s.err
+}
+
+func (s *notificationUsecaseStub) GetBookingNotifications(ctx context.Context, bookingID uuid.UUID) (*dto.NotificationListResponse, error) {
+	return stubValue[*dto.NotificationListResponse](), s.err
+}
+
+func (s *notificationUsecaseStub) GetMyBookingNotifications(ctx context.Context, bookingID uuid.UUID) (*dto.NotificationListResponse, error) {
+	return stubValue[*dto.NotificationListResponse](), s.err
+}
+
+func (s *notificationUsecaseStub) RegisterDevice(ctx context.Context, userID uuid.UUID, req *dto.RegisterDeviceRequest) (*dto.DeviceResponse, error) {
+	return stubValue[*dto.DeviceResponse](), s.err
+}
+
+func (s *notificationUsecaseStub) GetDevices(ctx context.Context, userID uuid.UUID) ([]dto.DeviceResponse, error) {
+	return stubValue[[]dto.DeviceResponse](), s.err
+}
+
+func (s *notificationUsecaseStub) UnregisterDevice(ctx context.Context, userID uuid.UUID, id int) error {
+	return s.err
+}
+
+func (s *notificationUsecaseStub) GetInAppNotifications(ctx context.Context, userID uuid.UUID, unreadOnly bool, page, limit int) ([]dto.InAppNotificationResponse, int64, error) {
+	return stubValue[[]dto.InAppNotificationResponse](), stubValue[int64](), s.err
+}
+
+func (s *notificationUsecaseStub) GetUnreadCount(ctx context.Context, userID uuid.UUID) (*dto.UnreadNotificationCountResponse, error) {
+	return stubValue[*dto.UnreadNotificationCountResponse](), s.err
+}
+
+func (s *notificationUsecaseStub) MarkRead(ctx context.Context, userID, id uuid.UUID) error {
+	return s.err
+}
+
+func (s *notificationUsecaseStub) MarkAllRead(ctx context.Context, userID uuid.UUID) (*dto.UnreadNotificationCountResponse, error) {
+	return stubValue[*dto.UnreadNotificationCountResponse](), s.err
+}
+
+func (s *notificationUsecaseStub) DeleteInAppNotification(ctx context.Context, userID, id uuid.UUID) error {
+	return s.err
+}
+
+func (s *notificationUsecaseStub) GetPreference(ctx context.Context, userID uuid.UUID) (*dto.NotificationPreferenceResponse, error) {
+	return stubValue[*dto.NotificationPreferenceResponse](), s.err
+}
+
+func (s *notificationUsecaseStub) UpdatePreference(ctx context.Context, userID uuid.UUID, req *dto.UpdateNotificationPreferenceRequest) (*dto.NotificationPreferenceResponse, error) {
+	return stubValue[*dto.NotificationPreferenceResponse](), s.err
+}
+
+func (s *notificationUsecaseStub) GetDeadLetters(ctx context.Context, channel string, eventType string, page, limit int) ([]dto.NotificationResponse, int64, error) {
+	return stubValue[[]dto.NotificationResponse](), stubValue[int64](), s.err
+}
+
+func (s *notificationUsecaseStub) GetNotification(ctx context.Context, id uuid.UUID) (*dto.NotificationDetailResponse, error) {
+	return stubValue[*dto.NotificationDetailResponse](), s.err
+}
+
+func (s *notificationUsecaseStub) GetPatientNotifications(ctx context.Context, patientID uuid.UUID, filter *dto.NotificationFilter, page, limit int) ([]dto.NotificationResponse, int64, error) {
+	return stubValue[[]dto.NotificationResponse](), stubValue[int64](), s.err
+}
+
+func (s *notificationUsecaseStub) RedriveNotification(ctx context.Context, id uuid.UUID) (*dto.NotificationResponse, error) {
+	return stubValue[*dto.NotificationResponse](), s.err
+}
+
+func (s *notificationUsecaseStub) RedriveNotifications(ctx context.Context, req *dto.RedriveNotificationsRequest) (*dto.RedriveNotificationsResponse, error) {
+	return stubValue[*dto.RedriveNotificationsResponse](), s.err
+}
+
+type notificationTemplateUsecaseStub struct{ *usecaseStub }
+
+func (s *notificationTemplateUsecaseStub) CreateTemplate(ctx context.Context, req *dto.CreateNotificationTemplateRequest) (*dto.NotificationTemplateResponse, error) {
+	return stubValue[*dto.NotificationTemplateResponse](), s.err
+}
+
+func (s *notificationTemplateUsecaseStub) GetTemplate(ctx context.Context, id int) (*dto.NotificationTemplateResponse, error) {
+	return stubValue[*dto.NotificationTemplateResponse](), s.err
+}
+
+func (s *notificationTemplateUsecaseStub) GetAllTemplates(ctx context.Context, eventType string) (*dto.NotificationTemplateListResponse, error) {
+	return stubValue[*dto.NotificationTemplateListResponse](), s.err
+}
+
+func (s *notificationTemplateUsecaseStub) UpdateTemplate(ctx context.Context, id int, req *dto.UpdateNotificationTemplateRequest) (*dto.NotificationTemplateResponse, error) {
+	return stubValue[*dto.NotificationTemplateResponse](), s.err
+}
+
+func (s *notificationTemplateUsecaseStub) DeleteTemplate(ctx context.Context, id int) error {
+	return s.err
+}
+
+func (s *notificationTemplateUsecaseStub) PreviewTemplate(ctx context.Context, req *dto.PreviewNotificationTemplateRequest) (*dto.NotificationTemplatePreviewResponse, error) {
+	return stubValue[*dto.NotificationTemplatePreviewResponse](), s.err
+}
+
+type patientProfileUsecaseStub struct{ *usecaseStub }
+
+func (s *patientProfileUsecaseStub) UpdateSelfProfile(ctx context.Context, req *dto.PatientUpdateSelfRequest) (*dto.PatientResponse, error) {
+	return stubValue[*dto.PatientResponse](), s.err
+}
+
+func (s *patientProfileUsecaseStub) GetPatientTimeline(ctx context.Context, patientID uuid.UUID, page, limit int) (*dto.PatientTimelineResponse, int64, error) {
+	return stubValue[*dto.PatientTimelineResponse](), stubValue[int64](), s.err
+}
+
+func (s *patientProfileUsecaseStub) DeleteAccount(ctx context.Context, req *dto.DeletePatientAccountRequest) error {
+	return s.err
+}
+
+func (s *patientProfileUsecaseStub) SearchPatients(ctx context.Context, filter *dto.PatientSearchFilter, page, limit int) ([]dto.PatientResponse, int64, error) {
+	return stubValue[[]dto.PatientResponse](), stubValue[int64](), s.err
+}
+
+func (s *patientProfileUsecaseStub) UpdateInsurance(ctx context.Context, patientID uuid.UUID, req *dto.UpdateInsuranceRequest) (*dto.PatientResponse, error) {
+	return stubValue[*dto.PatientResponse](), s.err
+}
+
+func (s *patientProfileUsecaseStub) RemoveInsurance(ctx context.Context, patientID uuid.UUID) (*dto.PatientResponse, error) {
+	return stubValue[*dto.PatientResponse](), s.err
+}
+
+func (s *patientProfileUsecaseStub) UpdateEmergencyContact(ctx context.Context, patientID uuid.UUID, req *dto.UpdateEmergencyContactRequest) (*dto.PatientResponse, error) {
+	return stubValue[*dto.PatientResponse](), s.err
+}
+
+func (s *patientProfileUsecaseStub) RemoveEmergencyContact(ctx context.Context, patientID uuid.UUID) (*dto.PatientResponse, error) {
+	return stubValue[*dto.PatientResponse](), s.err
+}
+
+type patientRosterUsecaseStub struct{ *usecaseStub }
+
+func (s *patientRosterUsecaseStub) ImportRoster(ctx context.Context, file io.Reader, partner string, dryRun bool) (*dto.PatientRosterImportResponse, error) {
+	return stubValue[*dto.PatientRosterImportResponse](), s.err
+}
+
+func (s *patientRosterUsecaseStub) RequestClaim(ctx context.Context, req *dto.RequestClaimRequest) error {
+	return s.err
+}
+
+func (s *patientRosterUsecaseStub) VerifyClaim(ctx context.Context, req *dto.VerifyClaimRequest) (*dto.UserResponse, error) {
+	return stubValue[*dto.UserResponse](), s.err
+}
+
+type paymentUsecaseStub struct{ *usecaseStub }
+
+func (s *paymentUsecaseStub) HandleCallback(ctx context.Context, gateway string, header http.Header, body []byte) error {
+	return s.err
+}
+
+func (s *paymentUsecaseStub) GetMyBookingPayment(ctx context.Context, bookingID uuid.UUID) (*dto.PaymentResponse, error) {
+	return stubValue[*dto.PaymentResponse](), s.err
+}
+
+func (s *paymentUsecaseStub) RefundPayment(ctx context.Context, id uuid.UUID) (*dto.PaymentResponse, error) {
+	return stubValue[*dto.PaymentResponse](), s.err
+}
+
+type redisStateUsecaseStub struct{ *usecaseStub }
+
+func (s *redisStateUsecaseStub) GetScheduleState(ctx context.Context, scheduleID int) (*dto.RedisScheduleStateResponse, error) {
+	return stubValue[*dto.RedisScheduleStateResponse](), s.err
+}
+
+func (s *redisStateUsecaseStub) UpdateScheduleState(ctx context.Context, scheduleID int, req *dto.UpdateRedisScheduleStateRequest) (*dto.RedisScheduleStateResponse, error) {
+	return stubValue[*dto.RedisScheduleStateResponse](), s.err
+}
+
+type reportUsecaseStub struct{ *usecaseStub }
+
+func (s *reportUsecaseStub) GetWaitTimeReport(ctx context.Context, startDate, endDate string) (*dto.WaitTimeReportResponse, error) {
+	return stubValue[*dto.WaitTimeReportResponse](), s.err
+}
+
+func (s *reportUsecaseStub) GetUsageReport(ctx context.Context, startDate, endDate string) (*dto.UsageReportResponse, error) {
+	return stubValue[*dto.UsageReportResponse](), s.err
+}
+
+func (s *reportUsecaseStub) GetScheduleUtilizationReport(ctx context.Context, filter *dto.ScheduleUtilizationFilter) (*dto.ScheduleUtilizationReportResponse, error) {
+	return stubValue[*dto.ScheduleUtilizationReportResponse](), s.err
+}
+
+func (s *reportUsecaseStub) GetDoctorStats(ctx context.Context, doctorID uuid.UUID, filter *dto.DoctorStatsFilter) (*dto.DoctorStatsResponse, error) {
+	return stubValue[*dto.DoctorStatsResponse](), s.err
+}
+
+func (s *reportUsecaseStub) GetReminderReport(ctx context.Context, startDate, endDate string) (*dto.ReminderReportResponse, error) {
+	return stubValue[*dto.ReminderReportResponse](), s.err
+}
+
+type roleUsecaseStub struct{ *usecaseStub }
+
+func (s *roleUsecaseStub) GetAllRoles(ctx context.Context) ([]dto.RoleResponse, error) {
+	return stubValue[[]dto.RoleResponse](), s.err
+}
+
+func (s *roleUsecaseStub) GetAllPermissions(ctx context.Context) ([]dto.PermissionResponse, error) {
+	return stubValue[[]dto.PermissionResponse](), s.err
+}
+
+func (s *roleUsecaseStub) CreateRole(ctx context.Context, req *dto.CreateRoleRequest) (*dto.RoleResponse, error) {
+	return stubValue[*dto.RoleResponse](), s.err
+}
+
+func (s *roleUsecaseStub) UpdateRolePermissions(ctx context.Context, roleID int, req *dto.UpdateRolePermissionsRequest) (*dto.RoleResponse, error) {
+	return stubValue[*dto.RoleResponse](), s.err
+}
+
+func (s *roleUsecaseStub) CreateStaffUser(ctx context.Context, req *dto.CreateStaffUserRequest) (*dto.UserResponse, error) {
+	return stubValue[*dto.UserResponse](), s.err
+}
+
+func (s *roleUsecaseStub) GetAllUsers(ctx context.Context, filter *dto.UserFilter, page, limit int) ([]dto.AdminUserResponse, int64, error) {
+	return stubValue[[]dto.AdminUserResponse](), stubValue[int64](), s.err
+}
+
+type scheduleTemplateUsecaseStub struct{ *usecaseStub }
+
+func (s *scheduleTemplateUsecaseStub) CreateTemplate(ctx context.Context, req *dto.CreateScheduleTemplateRequest) (*dto.ScheduleTemplateResponse, error) {
+	return stubValue[*dto.ScheduleTemplateResponse](), s.err
+}
+
+func (s *scheduleTemplateUsecaseStub) GetTemplates(ctx context.Context, doctorID *uuid.UUID, page, limit int) ([]dto.ScheduleTemplateResponse, int64, error) {
+	return stubValue[[]dto.ScheduleTemplateResponse](), stubValue[int64](), s.err
+}
+
+func (s *scheduleTemplateUsecaseStub) UpdateTemplate(ctx context.Context, id int, req *dto.UpdateScheduleTemplateRequest) (*dto.ScheduleTemplateResponse, error) {
+	return stubValue[*dto.ScheduleTemplateResponse](), s.err
+}
+
+func (s *scheduleTemplateUsecaseStub) DeleteTemplate(ctx context.Context, id int) error {
+	return s.err
+}
+
+func (s *scheduleTemplateUsecaseStub) GenerateSchedules(ctx context.Context) (*dto.ScheduleGenerationRunResponse, error) {
+	return stubValue[*dto.ScheduleGenerationRunResponse](), s.err
+}
+
+func (s *scheduleTemplateUsecaseStub) GetGenerationRuns(ctx context.Context, page, limit int) ([]dto.ScheduleGenerationRunResponse, int64, error) {
+	return stubValue[[]dto.ScheduleGenerationRunResponse](), stubValue[int64](), s.err
+}
+
+func (s *scheduleTemplateUsecaseStub) GetGenerationRun(ctx context.Context, id uuid.UUID) (*dto.ScheduleGenerationRunResponse, error) {
+	return stubValue[*dto.ScheduleGenerationRunResponse](), s.err
+}
+
+type specializationDefaultUsecaseStub struct{ *usecaseStub }
+
+func (s *specializationDefaultUsecaseStub) GetAllDefaults(ctx context.Context) (*dto.SpecializationDefaultListResponse, error) {
+	return stubValue[*dto.SpecializationDefaultListResponse](), s.err
+}
+
+func (s *specializationDefaultUsecaseStub) GetDefault(ctx context.Context, specialization string) (*dto.SpecializationDefaultResponse, error) {
+	return stubValue[*dto.SpecializationDefaultResponse](), s.err
+}
+
+func (s *specializationDefaultUsecaseStub) UpsertDefault(ctx context.Context, specialization string, req *dto.UpsertSpecializationDefaultRequest) (*dto.SpecializationDefaultResponse, error) {
+	return stubValue[*dto.SpecializationDefaultResponse](), s.err
+}
+
+func (s *specializationDefaultUsecaseStub) DeleteDefault(ctx context.Context, specialization string) error {
+	return s.err
+}
+
+type specializationUsecaseStub struct{ *usecaseStub }
+
+func (s *specializationUsecaseStub) CreateSpecialization(ctx context.Context, req *dto.CreateSpecializationRequest) (*dto.SpecializationResponse, error) {
+	return stubValue[*dto.SpecializationResponse](), s.err
+}
+
+func (s *specializationUsecaseStub) GetSpecialization(ctx context.Context, id int) (*dto.SpecializationResponse, error) {
+	return stubValue[*dto.SpecializationResponse](), s.err
+}
+
+func (s *specializationUsecaseStub) GetAllSpecializations(ctx context.Context) (*dto.SpecializationListResponse, error) {
+	return stubValue[*dto.SpecializationListResponse](), s.err
+}
+
+func (s *specializationUsecaseStub) UpdateSpecialization(ctx context.Context, id int, req *dto.UpdateSpecializationRequest) (*dto.SpecializationResponse, error) {
+	return stubValue[*dto.SpecializationResponse](), s.err
+}
+
+func (s *specializationUsecaseStub) DeleteSpecialization(ctx context.Context, id int) error {
+	return s.err
+}
+
+type webhookUsecaseStub struct{ *usecaseStub }
+
+func (s *webhookUsecaseStub) CreateWebhook(ctx context.Context, req *dto.CreateWebhookRequest) (*dto.CreateWebhookResponse, error) {
+	return stubValue[*dto.CreateWebhookResponse](), s.err
+}
+
+func (s *webhookUsecaseStub) GetWebhook(ctx context.Context, id int) (*dto.WebhookResponse, error) {
+	return stubValue[*dto.WebhookResponse](), s.err
+}
+
+func (s *webhookUsecaseStub) GetAllWebhooks(ctx context.Context) (*dto.WebhookListResponse, error) {
+	return stubValue[*dto.WebhookListResponse](), s.err
+}
+
+func (s *webhookUsecaseStub) UpdateWebhook(ctx context.Context, id int, req *dto.UpdateWebhookRequest) (*dto.WebhookResponse, error) {
+	return stubValue[*dto.WebhookResponse](), s.err
+}
+
+func (s *webhookUsecaseStub) DeleteWebhook(ctx context.Context, id int) error {
+	return s.err
+}
+
+func (s *webhookUsecaseStub) PauseWebhook(ctx context.Context, id int, req *dto.PauseWebhookRequest) (*dto.WebhookResponse, error) {
+	return stubValue[*dto.WebhookResponse](), s.err
+}
+
+func (s *webhookUsecaseStub) ResumeWebhook(ctx context.Context, id int) (*dto.WebhookResponse, error) {
+	return stubValue[*dto.WebhookResponse](), s.err
+}
+
+func (s *webhookUsecaseStub) TestWebhook(ctx context.Context, id int) (*dto.WebhookDeliveryResponse, error) {
+	return stubValue[*dto.WebhookDeliveryResponse](), s.err
+}
+
+func (s *webhookUsecaseStub) GetDeliveries(ctx context.Context, id int, status string, page, limit int) ([]dto.WebhookDeliveryResponse, int64, error) {
+	return stubValue[[]dto.WebhookDeliveryResponse](), stubValue[int64](), s.err
+}
@@ -0,0 +1,67 @@
+package entity
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// APIKey authenticates a machine client (kiosk, reporting service) sent as X-API-Key.
+// It may call the admin routes its permissions allow. Only the SHA-256 hash of the
+// key is stored; the key itself is shown once, when it is created.
+type APIKey struct {
+	ID         uuid.UUID  `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	Name       string     `gorm:"type:varchar(100);not null" json:"name"`
+	KeyPrefix  string     `gorm:"type:varchar(20);not null" json:"key_prefix"`
+	KeyHash    string     `gorm:"type:varchar(64);uniqueIndex;not null" json:"-"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty"` // nil = never expires
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+	CreatedBy  *uuid.UUID `gorm:"type:uuid" json:"created_by,omitempty"`
+	CreatedAt  time.Time  `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt  time.Time  `gorm:"autoUpdateTime" json:"updated_at"`
+
+	// Relationships
+	Permissions []Permission `gorm:"many2many:api_key_permissions;" json:"permissions,omitempty"`
+}
+
+func (APIKey) TableName() string {
+	return "api_keys"
+}
+
+// IsActive reports whether the key authenticates requests at the given time
+func (k *APIKey) IsActive(at time.Time) bool {
+	if k.RevokedAt != nil {
+		return false
+	}
+	return k.ExpiresAt == nil || at.Before(*k.ExpiresAt)
+}
+
+// HasPermission reports whether the key was granted the permission. Permissions keys can
+// no longer be granted (IsAPIKeyPermissionAllowed) are refused also on keys granted them before.
+func (k *APIKey) HasPermission(code string) bool {
+	if !IsAPIKeyPermissionAllowed(code) {
+		return false
+	}
+	for _, permission := range k.Permissions {
+		if permission.Code == code {
+			return true
+		}
+	}
+	return false
+}
+
+// IsAPIKeyPermissionAllowed reports whether a permission can be granted to an API key.
+// Portals act for a signed-in doctor or patient, keys never create other keys or accounts,
+// never change roles, and impersonation needs an admin to answer for it.
+func IsAPIKeyPermissionAllowed(code string) bool {
+	if strings.HasPrefix(code, "portal:") {
+		return false
+	}
+	switch code {
+	case PermissionAPIKeyManage, PermissionUserManage, PermissionRoleManage, PermissionUserImpersonate:
+		return false
+	}
+	return true
+}
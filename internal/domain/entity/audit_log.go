@@ -16,6 +16,7 @@ type AuditLog struct {
 	UserID    *uuid.UUID `gorm:"type:uuid;index" json:"user_id,omitempty"`
 	Action    string     `gorm:"type:varchar(100);not null;index" json:"action"`
 	Metadata  JSON       `gorm:"type:jsonb" json:"metadata,omitempty"`
+	EventID   *string    `gorm:"type:varchar(64);uniqueIndex:uq_audit_logs_event" json:"event_id,omitempty"` // Domain event it was recorded for, nil for actions logged directly
 	CreatedAt time.Time  `gorm:"autoCreateTime;index" json:"created_at"`
 
 	// Relationships
@@ -61,17 +62,115 @@ func (j *JSON) Scan(value interface{}) error {
 
 // Common audit actions
 const (
-	AuditActionUserLogin      = "user.login"
-	AuditActionUserLogout     = "user.logout"
-	AuditActionUserRegister   = "user.register"
-	AuditActionBookingCreate  = "booking.create"
-	AuditActionBookingConfirm = "booking.confirm"
-	AuditActionBookingCancel  = "booking.cancel"
-	AuditActionScheduleCreate = "schedule.create"
-	AuditActionScheduleUpdate = "schedule.update"
-	AuditActionScheduleDelete = "schedule.delete"
-	AuditActionProfileUpdate  = "profile.update"
-	AuditActionDoctorCreate   = "doctor.create"
-	AuditActionDoctorUpdate   = "doctor.update"
-	AuditActionDoctorDelete   = "doctor.delete"
+	AuditActionUserLogin        = "user.login"
+	AuditActionLoginFailed      = "user.login_failed"
+	AuditActionLoginLocked      = "user.login_locked"
+	AuditActionNewDeviceLogin   = "user.new_device_login"
+	AuditActionUserLogout       = "user.logout"
+	AuditActionUserRegister     = "user.register"
+	AuditActionSessionRevoke    = "user.session_revoke"
+	AuditActionSessionEvict     = "user.session_evict"
+	AuditActionRememberMeRevoke = "user.remember_me_revoke"
+	AuditActionTokenReuse       = "user.refresh_token_reuse"
+	AuditActionImpersonate      = "user.impersonate"
+	AuditActionImpersonatedCall = "user.impersonated_request"
+	AuditActionAccountLock      = "user.account_lock"
+	AuditActionAccountUnlock    = "user.account_unlock"
+	AuditActionPasswordChange   = "user.password_change"
+	AuditActionPasswordRequire  = "user.password_change_required"
+	AuditActionTwoFactorEnable  = "user.two_factor_enable"
+	AuditActionTwoFactorDisable = "user.two_factor_disable"
+	AuditActionTwoFactorReset   = "user.two_factor_reset"
+	AuditActionUserGoogleLink   = "user.google_link"
+	AuditActionStaffCreate      = "user.create_staff"
+	AuditActionRoleCreate       = "role.create"
+	AuditActionRolePermissions  = "role.update_permissions"
+	AuditActionAPIKeyCreate     = "api_key.create"
+	AuditActionAPIKeyUpdate     = "api_key.update"
+	AuditActionAPIKeyRevoke     = "api_key.revoke"
+	AuditActionBookingCreate    = "booking.create"
+	AuditActionBookingConfirm   = "booking.confirm"
+	AuditActionBookingCancel    = "booking.cancel"
+	AuditActionBookingPromote   = "booking.waitlist_promote"
+	AuditActionBookingImport    = "booking.import"
+	AuditActionBookingDelete    = "booking.delete"
+	AuditActionBookingRestore   = "booking.restore"
+	AuditActionBroadcastSend    = "broadcast.send"
+	AuditActionScheduleCreate   = "schedule.create"
+	AuditActionScheduleUpdate   = "schedule.update"
+	AuditActionScheduleDelete   = "schedule.delete"
+	AuditActionScheduleCopy     = "schedule.copy_week"
+	AuditActionScheduleAbsent   = "schedule.doctor_possibly_absent"
+	AuditActionScheduleReassign = "schedule.reassign_bookings"
+	AuditActionDoctorCheckIn    = "schedule.doctor_check_in"
+	AuditActionScheduleOpen     = "schedule.booking_open"
+	AuditActionScheduleGenerate = "schedule.generate"
+	AuditActionSchedulePropose  = "schedule.propose"
+	AuditActionScheduleApprove  = "schedule.approve"
+	AuditActionScheduleReject   = "schedule.reject"
+	AuditActionScheduleQuota    = "schedule.bulk_quota"
+	AuditActionHolidayCreate    = "holiday.create"
+	AuditActionHolidayUpdate    = "holiday.update"
+	AuditActionHolidayDelete    = "holiday.delete"
+	AuditActionProfileUpdate    = "profile.update"
+	AuditActionAccountErase     = "patient.account_erase"
+	AuditActionAccountExport    = "user.data_export"
+	AuditActionDoctorCreate     = "doctor.create"
+	AuditActionDoctorUpdate     = "doctor.update"
+	AuditActionDoctorDelete     = "doctor.delete"
+	AuditActionDoctorPhoto      = "doctor.photo_update"
+
+	AuditActionSpecializationDefaultUpdate = "specialization_default.update"
+	AuditActionSpecializationDefaultDelete = "specialization_default.delete"
+	AuditActionSpecializationCreate        = "specialization.create"
+	AuditActionSpecializationUpdate        = "specialization.update"
+	AuditActionSpecializationDelete        = "specialization.delete"
+	AuditActionLogLevelUpdate              = "log_level.update"
+	AuditActionRedisStateUpdate            = "redis.schedule_state_update"
+	AuditActionPatientPreRegister          = "patient.pre_register"
+	AuditActionPatientClaim                = "patient.claim"
+	AuditActionPatientInsuranceUpdate      = "patient.insurance_update"
+	AuditActionPatientInsuranceRemove      = "patient.insurance_remove"
+	AuditActionPatientEmergencyContact     = "patient.emergency_contact_update"
+	AuditActionScheduleTemplateCreate      = "schedule_template.create"
+	AuditActionScheduleTemplateUpdate      = "schedule_template.update"
+	AuditActionScheduleTemplateDelete      = "schedule_template.delete"
+	AuditActionMedicalRecordCreate         = "medical_record.create"
+	AuditActionMedicalRecordUpdate         = "medical_record.update"
+	AuditActionMedicalRecordView           = "medical_record.view"
+	AuditActionPatientHistoryView          = "patient.history_view"
+	AuditActionDoctorLeaveCreate           = "doctor_leave.create"
+	AuditActionDoctorLeaveDelete           = "doctor_leave.delete"
+	AuditActionNotificationTemplateCreate  = "notification_template.create"
+	AuditActionNotificationTemplateUpdate  = "notification_template.update"
+	AuditActionNotificationTemplateDelete  = "notification_template.delete"
+	AuditActionWebhookCreate               = "webhook.create"
+	AuditActionWebhookUpdate               = "webhook.update"
+	AuditActionWebhookDelete               = "webhook.delete"
+	AuditActionWebhookPause                = "webhook.pause"
+	AuditActionWebhookResume               = "webhook.resume"
+	AuditActionNotificationRedrive         = "notification.redrive"
+	AuditActionPaymentPaid                 = "payment.paid"
+	AuditActionPaymentFail                 = "payment.fail"
+	AuditActionPaymentExpire               = "payment.expire"
+	AuditActionInvoiceIssue                = "invoice.issue"
+	AuditActionPaymentRefundRequest        = "payment.refund_request"
+	AuditActionPaymentRefund               = "payment.refund"
+	AuditActionPaymentRefundFail           = "payment.refund_fail"
 )
+
+// securityAlertActions are the audited actions on an account ("user" entity) that the
+// user is told about, in case it was not them (see OutboxEventSecurityAlert)
+var securityAlertActions = map[string]bool{
+	AuditActionNewDeviceLogin:   true,
+	AuditActionPasswordChange:   true,
+	AuditActionTwoFactorDisable: true,
+	AuditActionTwoFactorReset:   true,
+	AuditActionAccountLock:      true,
+	AuditActionAccountExport:    true,
+}
+
+// IsSecurityAlertAction reports whether the user affected by an audited action is notified
+func IsSecurityAlertAction(action string) bool {
+	return securityAlertActions[action]
+}
@@ -0,0 +1,38 @@
+package entity
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// BackupCodeHashes are the SHA-256 hashes (hex) of a user's unused 2FA backup codes,
+// stored as a JSONB array
+type BackupCodeHashes []string
+
+// Value returns json value, implement driver.Valuer interface
+func (h BackupCodeHashes) Value() (driver.Value, error) {
+	if h == nil {
+		return "[]", nil
+	}
+	return json.Marshal(h)
+}
+
+// Scan scan value into BackupCodeHashes, implements sql.Scanner interface
+func (h *BackupCodeHashes) Scan(value interface{}) error {
+	if value == nil {
+		*h = nil
+		return nil
+	}
+	var bytes []byte
+	switch v := value.(type) {
+	case []byte:
+		bytes = v
+	case string:
+		bytes = []byte(v)
+	default:
+		return errors.New(fmt.Sprint("Failed to unmarshal JSONB value:", value))
+	}
+	return json.Unmarshal(bytes, h)
+}
@@ -4,31 +4,51 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 )
 
 // BookingStatus represents the status of a booking
 type BookingStatus string
 
 const (
-	BookingStatusPending   BookingStatus = "pending"
-	BookingStatusConfirmed BookingStatus = "confirmed"
-	BookingStatusCancelled BookingStatus = "cancelled"
+	BookingStatusPending         BookingStatus = "pending"
+	BookingStatusAwaitingPayment BookingStatus = "awaiting_payment" // Holds the slot until the consultation fee is paid
+	BookingStatusConfirmed       BookingStatus = "confirmed"
+	BookingStatusCancelled       BookingStatus = "cancelled"
 )
 
-// Booking represents a patient booking transaction
+// Booking represents a patient booking transaction.
+// Deleted bookings are soft-deleted (DeletedAt): GORM leaves them out of every query
+// unless Unscoped, and admins can restore them.
 type Booking struct {
-	ID          uuid.UUID     `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
-	PatientID   uuid.UUID     `gorm:"type:uuid;not null;index" json:"patient_id"`
-	ScheduleID  int           `gorm:"not null;index" json:"schedule_id"`
-	BookingCode string        `gorm:"type:varchar(50);uniqueIndex;not null" json:"booking_code"`
-	QueueNumber int           `gorm:"not null;default:0" json:"queue_number"`
-	Status      BookingStatus `gorm:"type:booking_status;not null;default:'pending';index" json:"status"`
-	CreatedAt   time.Time     `gorm:"autoCreateTime" json:"created_at"`
-	UpdatedAt   time.Time     `gorm:"autoUpdateTime" json:"updated_at"`
+	ID          uuid.UUID      `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	PatientID   uuid.UUID      `gorm:"type:uuid;not null;index" json:"patient_id"`
+	ScheduleID  int            `gorm:"not null;index" json:"schedule_id"`
+	BookingCode string         `gorm:"type:varchar(50);uniqueIndex;not null" json:"booking_code"`
+	QueueNumber int            `gorm:"not null;default:0" json:"queue_number"`
+	SlotID      *int64         `gorm:"index" json:"slot_id,omitempty"` // Appointment slot (slot-mode schedules only)
+	Status      BookingStatus  `gorm:"type:booking_status;not null;default:'pending';index" json:"status"`
+	CalledAt    *time.Time     `json:"called_at,omitempty"`               // Set when the doctor calls this queue number
+	Version     int            `gorm:"not null;default:1" json:"version"` // Optimistic lock, incremented on every update
+	CreatedAt   time.Time      `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt   time.Time      `gorm:"autoUpdateTime" json:"updated_at"`
+	DeletedAt   gorm.DeletedAt `gorm:"index" json:"-"`
+
+	// Insurance the patient used for the visit, copied from the profile when booking (claims)
+	InsuranceProvider     string `gorm:"type:varchar(100)" json:"insurance_provider,omitempty"`
+	InsurancePolicyNumber string `gorm:"type:varchar(50)" json:"insurance_policy_number,omitempty"`
 
 	// Relationships
 	Patient  PatientProfile `gorm:"foreignKey:PatientID" json:"patient,omitempty"`
 	Schedule DoctorSchedule `gorm:"foreignKey:ScheduleID" json:"schedule,omitempty"`
+	Slot     *ScheduleSlot  `gorm:"foreignKey:SlotID" json:"slot,omitempty"`
+}
+
+// ScheduleBookingCounts aggregates the active bookings of one schedule (doctor day view)
+type ScheduleBookingCounts struct {
+	Booked    int64 // Non-cancelled bookings
+	Confirmed int64 // Bookings confirmed at the clinic
+	Called    int64 // Bookings whose queue number has been called
 }
 
 func (Booking) TableName() string {
@@ -40,6 +60,11 @@ func (b *Booking) IsPending() bool {
 	return b.Status == BookingStatusPending
 }
 
+// IsAwaitingPayment checks if the booking waits for its consultation fee to be paid
+func (b *Booking) IsAwaitingPayment() bool {
+	return b.Status == BookingStatusAwaitingPayment
+}
+
 // IsConfirmed checks if booking is confirmed
 func (b *Booking) IsConfirmed() bool {
 	return b.Status == BookingStatusConfirmed
@@ -50,6 +75,11 @@ func (b *Booking) IsCancelled() bool {
 	return b.Status == BookingStatusCancelled
 }
 
+// IsCalled checks if the doctor has called this booking's queue number
+func (b *Booking) IsCalled() bool {
+	return b.CalledAt != nil
+}
+
 // Confirm changes booking status to confirmed
 func (b *Booking) Confirm() {
 	b.Status = BookingStatusConfirmed
@@ -0,0 +1,61 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// BookingSagaStatus represents the progress of a booking saga
+type BookingSagaStatus string
+
+const (
+	BookingSagaStatusRunning      BookingSagaStatus = "running"      // Executing steps
+	BookingSagaStatusCompleted    BookingSagaStatus = "completed"    // Every step succeeded
+	BookingSagaStatusCompensating BookingSagaStatus = "compensating" // Undoing completed steps after a failure
+	BookingSagaStatusCompensated  BookingSagaStatus = "compensated"  // Every completed step was undone
+	BookingSagaStatusFailed       BookingSagaStatus = "failed"       // A compensation failed, needs manual correction
+)
+
+// Booking saga steps, in execution order
+const (
+	BookingSagaStepReserveSlot   = "reserve_slot"   // Redis quota/queue or time-slot reservation
+	BookingSagaStepCreateBooking = "create_booking" // Booking + booking.created outbox event (notification)
+)
+
+// BookingSaga is the persisted state of one CreateBooking run.
+//
+// Step is the last completed step that is not compensated yet, so a saga interrupted
+// by a crash can be finished by the recovery worker. BookingID is assigned before the
+// booking is inserted, which tells recovery whether the insert committed.
+type BookingSaga struct {
+	ID          uuid.UUID         `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	BookingID   uuid.UUID         `gorm:"type:uuid;not null" json:"booking_id"`
+	BookingCode string            `gorm:"type:varchar(50);not null" json:"booking_code"`
+	PatientID   uuid.UUID         `gorm:"type:uuid;not null" json:"patient_id"`
+	ScheduleID  int               `gorm:"not null" json:"schedule_id"`
+	SlotID      *int64            `json:"slot_id,omitempty"`
+	QueueNumber int               `gorm:"not null;default:0" json:"queue_number"` // Queue mode: 0 while no slot is held
+	Status      BookingSagaStatus `gorm:"type:varchar(20);not null;default:'running'" json:"status"`
+	Step        string            `gorm:"type:varchar(50);not null;default:''" json:"step"`
+	LastError   string            `gorm:"type:text" json:"last_error,omitempty"`
+	CreatedAt   time.Time         `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt   time.Time         `gorm:"autoUpdateTime" json:"updated_at"`
+	FinishedAt  *time.Time        `json:"finished_at,omitempty"`
+
+	// Insurance attached to the booking (empty = none)
+	InsuranceProvider     string `gorm:"type:varchar(100)" json:"insurance_provider,omitempty"`
+	InsurancePolicyNumber string `gorm:"type:varchar(50)" json:"insurance_policy_number,omitempty"`
+
+	// AwaitingPayment creates the booking in awaiting_payment, confirmed once the fee is paid
+	AwaitingPayment bool `gorm:"not null;default:false" json:"awaiting_payment,omitempty"`
+}
+
+func (BookingSaga) TableName() string {
+	return "booking_sagas"
+}
+
+// IsFinished reports whether the saga reached a final status
+func (s *BookingSaga) IsFinished() bool {
+	return s.Status == BookingSagaStatusCompleted || s.Status == BookingSagaStatusCompensated || s.Status == BookingSagaStatusFailed
+}
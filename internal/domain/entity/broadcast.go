@@ -0,0 +1,73 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// BroadcastRecipientStatus represents the delivery state of a broadcast to one patient
+type BroadcastRecipientStatus string
+
+const (
+	BroadcastRecipientPending BroadcastRecipientStatus = "pending"
+	BroadcastRecipientSent    BroadcastRecipientStatus = "sent"
+	BroadcastRecipientFailed  BroadcastRecipientStatus = "failed"
+)
+
+// Broadcast is an admin announcement (e.g. clinic closure) sent to a patient segment
+type Broadcast struct {
+	ID              uuid.UUID  `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	Title           string     `gorm:"type:varchar(150);not null" json:"title"`
+	Message         string     `gorm:"type:text;not null" json:"message"`
+	Segment         JSON       `gorm:"type:jsonb" json:"segment,omitempty"`
+	TotalRecipients int        `gorm:"not null;default:0" json:"total_recipients"`
+	CreatedBy       *uuid.UUID `gorm:"type:uuid" json:"created_by,omitempty"`
+	CreatedAt       time.Time  `gorm:"autoCreateTime" json:"created_at"`
+}
+
+func (Broadcast) TableName() string {
+	return "broadcasts"
+}
+
+// BroadcastRecipient tracks the delivery of a broadcast to one patient
+type BroadcastRecipient struct {
+	ID          int64                    `gorm:"primaryKey;autoIncrement" json:"id"`
+	BroadcastID uuid.UUID                `gorm:"type:uuid;not null;index" json:"broadcast_id"`
+	PatientID   uuid.UUID                `gorm:"type:uuid;not null" json:"patient_id"`
+	Status      BroadcastRecipientStatus `gorm:"type:varchar(20);not null;default:'pending'" json:"status"`
+	Attempts    int                      `gorm:"not null;default:0" json:"attempts"`
+	LastError   string                   `gorm:"type:text" json:"last_error,omitempty"`
+	ScheduledAt time.Time                `gorm:"not null" json:"scheduled_at"` // Throttled delivery time
+	SentAt      *time.Time               `json:"sent_at,omitempty"`
+	CreatedAt   time.Time                `gorm:"autoCreateTime" json:"created_at"`
+
+	// Relationships
+	Patient *User `gorm:"foreignKey:PatientID" json:"patient,omitempty"`
+}
+
+func (BroadcastRecipient) TableName() string {
+	return "broadcast_recipients"
+}
+
+// BroadcastSegment filters the patients a broadcast is sent to.
+// Without any filter every active patient is selected; otherwise only patients
+// with an active booking matching all given filters.
+type BroadcastSegment struct {
+	DoctorID     *uuid.UUID
+	DateFrom     *time.Time
+	DateTo       *time.Time
+	UpcomingFrom *time.Time // Only bookings on schedules from this date on
+}
+
+// IsEmpty reports whether the segment selects every active patient
+func (s *BroadcastSegment) IsEmpty() bool {
+	return s.DoctorID == nil && s.DateFrom == nil && s.DateTo == nil && s.UpcomingFrom == nil
+}
+
+// BroadcastStatusCount is the number of recipients in one delivery status
+type BroadcastStatusCount struct {
+	BroadcastID uuid.UUID
+	Status      BroadcastRecipientStatus
+	Count       int64
+}
@@ -0,0 +1,40 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DataExportStatus represents the state of a personal data export
+type DataExportStatus string
+
+const (
+	DataExportStatusPending DataExportStatus = "pending" // Archive being generated
+	DataExportStatusReady   DataExportStatus = "ready"   // Archive downloadable until ExpiresAt
+	DataExportStatusFailed  DataExportStatus = "failed"  // Generation gave up, a new export can be requested
+	DataExportStatusExpired DataExportStatus = "expired" // Archive deleted
+)
+
+// DataExport is an archive of the personal data of a user (right of access), generated
+// in the background and kept in private storage for a limited time
+type DataExport struct {
+	ID          uuid.UUID        `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	UserID      uuid.UUID        `gorm:"type:uuid;not null;index" json:"user_id"`
+	Status      DataExportStatus `gorm:"type:varchar(20);not null;default:'pending'" json:"status"`
+	FileKey     string           `gorm:"type:varchar(255)" json:"-"` // Storage key of the archive
+	FileSize    int64            `gorm:"not null;default:0" json:"file_size"`
+	LastError   string           `gorm:"type:text" json:"last_error,omitempty"`
+	CreatedAt   time.Time        `gorm:"autoCreateTime" json:"created_at"`
+	CompletedAt *time.Time       `json:"completed_at,omitempty"`
+	ExpiresAt   *time.Time       `json:"expires_at,omitempty"`
+}
+
+func (DataExport) TableName() string {
+	return "data_exports"
+}
+
+// IsDownloadable reports whether the archive is ready and not expired at the given time
+func (e *DataExport) IsDownloadable(now time.Time) bool {
+	return e.Status == DataExportStatusReady && e.ExpiresAt != nil && now.Before(*e.ExpiresAt)
+}
@@ -0,0 +1,29 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Device platforms
+const (
+	DevicePlatformAndroid = "android"
+	DevicePlatformIOS     = "ios"
+	DevicePlatformWeb     = "web"
+)
+
+// DeviceToken is a push token (FCM registration token) of a mobile client the user is signed
+// in on. A token belongs to one user: registering it again moves it to the new user.
+type DeviceToken struct {
+	ID        int       `gorm:"primaryKey" json:"id"`
+	UserID    uuid.UUID `gorm:"type:uuid;not null;index" json:"user_id"`
+	Token     string    `gorm:"type:varchar(512);not null;uniqueIndex" json:"-"`
+	Platform  string    `gorm:"type:varchar(20);not null" json:"platform"`
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt time.Time `gorm:"autoUpdateTime" json:"updated_at"` // Last registration
+}
+
+func (DeviceToken) TableName() string {
+	return "device_tokens"
+}
@@ -0,0 +1,27 @@
+package entity
+
+// Doctor list sort orders
+const (
+	DoctorSortName    = "name"     // Full name A-Z (default)
+	DoctorSortNewest  = "newest"   // Most recently added first
+	DoctorSortFeeAsc  = "fee_asc"  // Cheapest consultation first
+	DoctorSortFeeDesc = "fee_desc" // Most expensive consultation first
+	DoctorSortRating  = "rating"   // Best average review rating first, unrated last
+)
+
+// DoctorFilter is a domain-level filter for querying doctors (public and admin doctor list).
+type DoctorFilter struct {
+	Specialization string // Filter by specialization slug
+	Active         *bool  // nil = all, true = active accounts, false = deactivated accounts
+	Search         string // Filter by full name (ILIKE)
+	Sort           string // One of the DoctorSort* orders, empty = DoctorSortName
+}
+
+// IsValidDoctorSort reports whether sort is a known doctor list order (empty = default)
+func IsValidDoctorSort(sort string) bool {
+	switch sort {
+	case "", DoctorSortName, DoctorSortNewest, DoctorSortFeeAsc, DoctorSortFeeDesc, DoctorSortRating:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,30 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DoctorLeave is a period in which a doctor is away (vacation, sick leave, conference).
+// Schedules are not generated on its dates and existing ones are closed for booking.
+type DoctorLeave struct {
+	ID        int        `gorm:"primaryKey;autoIncrement" json:"id"`
+	DoctorID  uuid.UUID  `gorm:"type:uuid;not null;index" json:"doctor_id"`
+	StartDate time.Time  `gorm:"type:date;not null" json:"start_date"`
+	EndDate   time.Time  `gorm:"type:date;not null" json:"end_date"` // Inclusive
+	Reason    string     `gorm:"type:text" json:"reason,omitempty"`
+	CreatedBy *uuid.UUID `gorm:"type:uuid" json:"created_by,omitempty"` // Doctor or admin who recorded it
+	CreatedAt time.Time  `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt time.Time  `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+func (DoctorLeave) TableName() string {
+	return "doctor_leaves"
+}
+
+// Covers reports whether the date (time of day ignored) falls within the leave
+func (l *DoctorLeave) Covers(date time.Time) bool {
+	day := date.Format("2006-01-02")
+	return day >= l.StartDate.Format("2006-01-02") && day <= l.EndDate.Format("2006-01-02")
+}
@@ -4,14 +4,22 @@ import "github.com/google/uuid"
 
 // DoctorProfile represents doctor-specific profile data
 type DoctorProfile struct {
-	UserID         uuid.UUID `gorm:"type:uuid;primaryKey" json:"user_id"`
-	STRNumber      string    `gorm:"column:str_number;type:varchar(50);uniqueIndex;not null" json:"str_number"`
-	Specialization string    `gorm:"type:varchar(100);not null;index" json:"specialization"`
-	Biography      string    `gorm:"type:text" json:"biography,omitempty"`
+	UserID           uuid.UUID `gorm:"type:uuid;primaryKey" json:"user_id"`
+	STRNumber        string    `gorm:"column:str_number;type:varchar(50);uniqueIndex;not null" json:"str_number"`
+	SpecializationID int       `gorm:"not null;index" json:"specialization_id"`
+	Biography        string    `gorm:"type:text" json:"biography,omitempty"`
+
+	// Fee of one consultation in the smallest currency unit, charged with the admin fee of invoices
+	ConsultationFee int64 `gorm:"not null;default:0" json:"consultation_fee"`
+
+	// Profile photo in the file storage; the key deletes it when replaced
+	PhotoKey string `gorm:"type:varchar(255);not null;default:''" json:"-"`
+	PhotoURL string `gorm:"type:varchar(1024);not null;default:''" json:"photo_url,omitempty"`
 
 	// Relationships
-	User      User             `gorm:"foreignKey:UserID" json:"user,omitempty"`
-	Schedules []DoctorSchedule `gorm:"foreignKey:DoctorID" json:"schedules,omitempty"`
+	User           User             `gorm:"foreignKey:UserID" json:"user,omitempty"`
+	Specialization *Specialization  `gorm:"foreignKey:SpecializationID" json:"specialization,omitempty"`
+	Schedules      []DoctorSchedule `gorm:"foreignKey:DoctorID" json:"schedules,omitempty"`
 }
 
 func (DoctorProfile) TableName() string {
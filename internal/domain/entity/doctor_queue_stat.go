@@ -0,0 +1,22 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DoctorQueueStatWindow is the number of recent call intervals the rolling average approximates
+const DoctorQueueStatWindow = 50
+
+// DoctorQueueStat holds the rolling average minutes between queue calls of a doctor
+type DoctorQueueStat struct {
+	DoctorID           uuid.UUID `gorm:"type:uuid;primaryKey" json:"doctor_id"`
+	AvgMinutesPerQueue float64   `gorm:"not null;default:0" json:"avg_minutes_per_queue"`
+	SampleCount        int       `gorm:"not null;default:0" json:"sample_count"`
+	UpdatedAt          time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+func (DoctorQueueStat) TableName() string {
+	return "doctor_queue_stats"
+}
@@ -0,0 +1,44 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DoctorReview is the patient's rating of a visit, one review per booking
+type DoctorReview struct {
+	BookingID  uuid.UUID `gorm:"type:uuid;primaryKey" json:"booking_id"`
+	PatientID  uuid.UUID `gorm:"type:uuid;not null;index" json:"patient_id"`
+	DoctorID   uuid.UUID `gorm:"type:uuid;not null;index" json:"doctor_id"`
+	ScheduleID int       `gorm:"not null" json:"schedule_id"`
+	Rating     int       `gorm:"type:smallint;not null" json:"rating"` // 1 to 5 stars
+	Comment    string    `gorm:"type:text" json:"comment,omitempty"`
+	CreatedAt  time.Time `gorm:"autoCreateTime" json:"created_at"`
+}
+
+func (DoctorReview) TableName() string {
+	return "doctor_reviews"
+}
+
+// Review rating bounds (stars)
+const (
+	MinReviewRating = 1
+	MaxReviewRating = 5
+)
+
+// DoctorRatingStat is the aggregated rating of a doctor
+type DoctorRatingStat struct {
+	DoctorID      uuid.UUID `json:"doctor_id"`
+	AverageRating float64   `json:"average_rating"`
+	ReviewCount   int64     `json:"review_count"`
+}
+
+// DoctorReviewItem is a review as listed publicly, with the name of its author
+type DoctorReviewItem struct {
+	Rating       int       `json:"rating"`
+	Comment      string    `json:"comment"`
+	ReviewerName string    `json:"reviewer_name"`
+	Erased       bool      `json:"erased"` // The author erased their account
+	CreatedAt    time.Time `json:"created_at"`
+}
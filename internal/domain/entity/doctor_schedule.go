@@ -4,25 +4,180 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Schedule approval statuses. Schedules created by admins are approved right away;
+// schedules proposed by doctors wait for an admin decision.
+const (
+	ScheduleApprovalApproved = "approved"
+	ScheduleApprovalPending  = "pending_approval"
+	ScheduleApprovalRejected = "rejected"
 )
 
 // DoctorSchedule represents doctor availability with quota management
-// Note: RemainingQuota is calculated from Redis/DB query, not stored in entity
+// Note: RemainingQuota is calculated from Redis/DB query, not stored in entity.
+// Deleted schedules are soft-deleted, as their bookings keep referencing them.
 type DoctorSchedule struct {
-	ID           int       `gorm:"primaryKey;autoIncrement" json:"id"`
-	DoctorID     uuid.UUID `gorm:"type:uuid;not null;index" json:"doctor_id"`
-	ScheduleDate time.Time `gorm:"type:date;not null;index" json:"schedule_date"`
-	StartTime    string    `gorm:"type:time;not null" json:"start_time"`
-	EndTime      string    `gorm:"type:time;not null" json:"end_time"`
-	TotalQuota   int       `gorm:"not null" json:"total_quota"`
-	CreatedAt    time.Time `gorm:"autoCreateTime" json:"created_at"`
-	UpdatedAt    time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+	ID           int            `gorm:"primaryKey;autoIncrement" json:"id"`
+	DoctorID     uuid.UUID      `gorm:"type:uuid;not null;index" json:"doctor_id"`
+	ScheduleDate time.Time      `gorm:"type:date;not null;index" json:"schedule_date"`
+	StartTime    string         `gorm:"type:time;not null" json:"start_time"`
+	EndTime      string         `gorm:"type:time;not null" json:"end_time"`
+	TotalQuota   int            `gorm:"not null" json:"total_quota"`
+	BookingMode  string         `gorm:"type:varchar(10);not null;default:'queue'" json:"booking_mode"`
+	SlotMinutes  int            `gorm:"not null;default:0" json:"slot_minutes"` // Slot mode only
+	IsOpen       *bool          `gorm:"not null;default:true" json:"is_open"`   // false = booking paused
+	MinLeadHours *int           `json:"min_lead_hours,omitempty"`               // nil = global booking cutoff
+	CreatedAt    time.Time      `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt    time.Time      `gorm:"autoUpdateTime" json:"updated_at"`
+	DeletedAt    gorm.DeletedAt `gorm:"index" json:"-"`
+
+	// Doctor attendance tracking
+	DoctorCheckedInAt *time.Time `json:"doctor_checked_in_at,omitempty"`
+	AbsenceFlaggedAt  *time.Time `json:"absence_flagged_at,omitempty"`
+
+	// Set when a holiday is added on the schedule date (needs admin action)
+	HolidayFlaggedAt *time.Time `json:"holiday_flagged_at,omitempty"`
+
+	// Set when the doctor takes leave over the schedule date (booking is closed meanwhile)
+	LeaveFlaggedAt *time.Time `json:"leave_flagged_at,omitempty"`
+
+	// Pauses inside the schedule (e.g. lunch), ordered by start time
+	Breaks ScheduleBreaks `gorm:"type:jsonb;not null;default:'[]'" json:"breaks"`
+
+	// Template the schedule was generated from (nil = created manually)
+	TemplateID *int `gorm:"index" json:"template_id,omitempty"`
+
+	// Approval workflow: only approved schedules are bookable and synced to Redis
+	ApprovalStatus  string     `gorm:"type:varchar(20);not null;default:'approved'" json:"approval_status"`
+	ReviewedBy      *uuid.UUID `gorm:"type:uuid" json:"reviewed_by,omitempty"`
+	ReviewedAt      *time.Time `json:"reviewed_at,omitempty"`
+	RejectionReason string     `gorm:"type:text" json:"rejection_reason,omitempty"`
 
 	// Relationships
 	Doctor   DoctorProfile `gorm:"foreignKey:DoctorID" json:"doctor,omitempty"`
 	Bookings []Booking     `gorm:"foreignKey:ScheduleID" json:"bookings,omitempty"`
 }
 
+// ScheduleUtilizationStat aggregates the bookings of one schedule (utilization report)
+type ScheduleUtilizationStat struct {
+	ScheduleID   int
+	DoctorID     uuid.UUID
+	DoctorName   string
+	ScheduleDate time.Time
+	StartTime    string
+	EndTime      string
+	TotalQuota   int
+	Booked       int64 // Non-cancelled bookings
+	Cancelled    int64
+	NoShow       int64 // Bookings never called by the time the schedule ended
+}
+
+// Periods the doctor statistics are grouped by (PostgreSQL date_trunc fields, weeks start on Monday)
+const (
+	StatsPeriodDay   = "day"
+	StatsPeriodWeek  = "week"
+	StatsPeriodMonth = "month"
+)
+
+// DoctorPeriodStat aggregates the approved schedules of one doctor in one period (doctor statistics)
+type DoctorPeriodStat struct {
+	PeriodStart time.Time
+	Schedules   int64
+	TotalQuota  int64
+	Booked      int64 // Non-cancelled bookings
+	Served      int64 // Bookings whose queue number was called
+	Cancelled   int64
+	NoShow      int64 // Bookings never called by the time the schedule ended
+
+	// Sum and number of the intervals between consecutive queue calls (consultation time)
+	ConsultationMinutes float64
+	ConsultationSamples int64
+}
+
 func (DoctorSchedule) TableName() string {
 	return "doctor_schedules"
 }
+
+// IsPossiblyAbsent reports whether the schedule was flagged and the doctor has not checked in since
+func (s *DoctorSchedule) IsPossiblyAbsent() bool {
+	return s.AbsenceFlaggedAt != nil && s.DoctorCheckedInAt == nil
+}
+
+// IsBookingOpen reports whether the schedule accepts new bookings (not paused by an admin or the doctor)
+func (s *DoctorSchedule) IsBookingOpen() bool {
+	return s.IsOpen == nil || *s.IsOpen
+}
+
+// IsApproved reports whether the schedule was created by an admin or approved after a doctor's proposal
+func (s *DoctorSchedule) IsApproved() bool {
+	return s.ApprovalStatus == "" || s.ApprovalStatus == ScheduleApprovalApproved
+}
+
+// IsPendingApproval reports whether the schedule is a doctor's proposal waiting for an admin decision
+func (s *DoctorSchedule) IsPendingApproval() bool {
+	return s.ApprovalStatus == ScheduleApprovalPending
+}
+
+// IsSlotMode reports whether patients book fixed appointment times instead of queue numbers
+func (s *DoctorSchedule) IsSlotMode() bool {
+	return s.BookingMode == BookingModeSlot
+}
+
+// StartDateTime combines ScheduleDate and StartTime in the given location
+func (s *DoctorSchedule) StartDateTime(loc *time.Location) (time.Time, error) {
+	return combineDateAndClock(s.ScheduleDate, s.StartTime, loc)
+}
+
+// EndDateTime combines ScheduleDate and EndTime in the given location
+func (s *DoctorSchedule) EndDateTime(loc *time.Location) (time.Time, error) {
+	return combineDateAndClock(s.ScheduleDate, s.EndTime, loc)
+}
+
+// BreakIntervals returns the breaks as timestamps on the schedule date in the given location
+// (none when a break time cannot be parsed)
+func (s *DoctorSchedule) BreakIntervals(loc *time.Location) []Interval {
+	intervals, err := s.Breaks.Intervals(s.ScheduleDate, loc)
+	if err != nil {
+		return nil
+	}
+	return intervals
+}
+
+// WorkingTimeAfter returns the moment d of consultation time after from, skipping the breaks
+func (s *DoctorSchedule) WorkingTimeAfter(from time.Time, d time.Duration, loc *time.Location) time.Time {
+	return AddWorkingTime(from, d, s.BreakIntervals(loc))
+}
+
+// BookingLeadTime returns how long before the start bookings close: the schedule's own
+// minimum lead time when set, defaultLead otherwise
+func (s *DoctorSchedule) BookingLeadTime(defaultLead time.Duration) time.Duration {
+	if s.MinLeadHours != nil {
+		return time.Duration(*s.MinLeadHours) * time.Hour
+	}
+	return defaultLead
+}
+
+// BookingDeadline returns the last moment a booking can be made, in the given location
+func (s *DoctorSchedule) BookingDeadline(loc *time.Location, defaultLead time.Duration) (time.Time, error) {
+	startAt, err := s.StartDateTime(loc)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return startAt.Add(-s.BookingLeadTime(defaultLead)), nil
+}
+
+// combineDateAndClock builds a timestamp from a DATE column and a TIME column.
+// TIME columns are read back as HH:MM:SS while requests use HH:MM, so both are accepted.
+func combineDateAndClock(date time.Time, clock string, loc *time.Location) (time.Time, error) {
+	parsed, err := time.Parse("15:04:05", clock)
+	if err != nil {
+		parsed, err = time.Parse("15:04", clock)
+		if err != nil {
+			return time.Time{}, err
+		}
+	}
+
+	return time.Date(date.Year(), date.Month(), date.Day(), parsed.Hour(), parsed.Minute(), parsed.Second(), 0, loc), nil
+}
@@ -0,0 +1,66 @@
+package entity
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Account event types published on the event bus after commit
+const (
+	EventUserRegistered     = "user.registered"
+	EventUserLoggedIn       = "user.logged_in"
+	EventUserLoginFailed    = "user.login_failed"
+	EventUserLoginLocked    = "user.login_locked"
+	EventRefreshTokenReused = "user.refresh_token_reused"
+	EventSessionRevoked     = "user.session_revoked"
+	EventSessionEvicted     = "user.session_evicted" // Signed out by the session limit
+	EventRememberMeRevoked  = "user.remember_me_revoked"
+)
+
+// DomainEvent is something that happened, delivered to the subscribers of its type by the
+// event bus. Events recorded in the outbox with the change that caused them (booking.*,
+// schedule.*) reach the bus once the outbox publishes them, with OutboxID set; the others
+// are published right after the change is committed.
+type DomainEvent struct {
+	ID            string     `json:"id"`                 // "outbox:<outbox id>" for outbox events, a random UUID otherwise
+	Type          string     `json:"type"`               // e.g. booking.created, user.registered
+	AggregateType string     `json:"aggregate_type"`     // Kind of the entity the event is about, e.g. booking, user
+	AggregateID   string     `json:"aggregate_id"`       // Empty when the event is about no single entity
+	ActorID       *uuid.UUID `json:"actor_id,omitempty"` // Who caused it, nil for anonymous requests and background jobs
+	Payload       JSON       `json:"payload,omitempty"`
+	OccurredAt    time.Time  `json:"occurred_at"`
+	OutboxID      int64      `json:"outbox_id,omitempty"` // 0 when not published through the outbox
+}
+
+// DecodePayload unmarshals the event payload into v
+func (e *DomainEvent) DecodePayload(v interface{}) error {
+	raw, err := json.Marshal(e.Payload)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(raw, v)
+}
+
+// DomainEvent returns the event the outbox event records, as delivered to the bus subscribers
+func (e *OutboxEvent) DomainEvent() *DomainEvent {
+	return &DomainEvent{
+		ID:            fmt.Sprintf("outbox:%d", e.ID),
+		Type:          e.EventType,
+		AggregateType: e.AggregateType,
+		AggregateID:   e.AggregateID,
+		Payload:       e.Payload,
+		OccurredAt:    e.CreatedAt,
+		OutboxID:      e.ID,
+	}
+}
+
+// SessionEvictedPayload is the payload of user.session_evicted events, also the audited value
+type SessionEvictedPayload struct {
+	TokenID   string `json:"token_id"`
+	Device    string `json:"device"`
+	IPAddress string `json:"ip_address"`
+	Limit     int    `json:"limit"`
+}
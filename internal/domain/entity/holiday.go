@@ -0,0 +1,17 @@
+package entity
+
+import "time"
+
+// Holiday is a clinic holiday or blackout date on which no schedules are held
+type Holiday struct {
+	ID          int       `gorm:"primaryKey;autoIncrement" json:"id"`
+	Date        time.Time `gorm:"column:holiday_date;type:date;not null;uniqueIndex" json:"date"`
+	Name        string    `gorm:"type:varchar(150);not null" json:"name"`
+	Description string    `gorm:"type:text" json:"description,omitempty"`
+	CreatedAt   time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt   time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+func (Holiday) TableName() string {
+	return "holidays"
+}
@@ -0,0 +1,82 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// InvoiceStatus represents whether the invoiced amount was collected
+type InvoiceStatus string
+
+const (
+	InvoiceStatusPaid     InvoiceStatus = "paid"     // Paid through the payment gateway
+	InvoiceStatusIssued   InvoiceStatus = "issued"   // Completed visit without an online payment, settled at the clinic
+	InvoiceStatusRefunded InvoiceStatus = "refunded" // Payment refunded after the booking was cancelled
+)
+
+// Invoice line item types
+const (
+	InvoiceItemConsultationFee = "consultation_fee"
+	InvoiceItemAdminFee        = "admin_fee"
+)
+
+// Invoice is the bill of one booking, issued once it is paid or the visit completed.
+// Numbers are sequential per year without gaps; names and amounts are copied when the
+// invoice is issued so it reads the same after profiles or fees change.
+type Invoice struct {
+	ID          uuid.UUID     `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	Number      string        `gorm:"type:varchar(50);uniqueIndex;not null" json:"number"` // e.g. INV-2026-000001
+	BookingID   uuid.UUID     `gorm:"type:uuid;uniqueIndex;not null" json:"booking_id"`
+	PaymentID   *uuid.UUID    `gorm:"type:uuid" json:"payment_id,omitempty"` // Nil when settled at the clinic
+	PatientID   uuid.UUID     `gorm:"type:uuid;not null;index" json:"patient_id"`
+	DoctorID    uuid.UUID     `gorm:"type:uuid;not null;index" json:"doctor_id"`
+	PatientName string        `gorm:"type:varchar(255);not null" json:"patient_name"`
+	DoctorName  string        `gorm:"type:varchar(255);not null" json:"doctor_name"`
+	BookingCode string        `gorm:"type:varchar(50);not null" json:"booking_code"`
+	ServiceDate time.Time     `gorm:"type:date;not null" json:"service_date"` // Schedule date of the visit
+	Currency    string        `gorm:"type:varchar(3);not null" json:"currency"`
+	Total       int64         `gorm:"not null" json:"total"` // Sum of the items, smallest currency unit
+	Status      InvoiceStatus `gorm:"type:varchar(20);not null" json:"status"`
+	IssuedAt    time.Time     `gorm:"not null;index" json:"issued_at"`
+	CreatedAt   time.Time     `gorm:"autoCreateTime" json:"created_at"`
+
+	// Relationships
+	Items []InvoiceItem `gorm:"foreignKey:InvoiceID" json:"items,omitempty"`
+}
+
+func (Invoice) TableName() string {
+	return "invoices"
+}
+
+// InvoiceItem is one line of an invoice
+type InvoiceItem struct {
+	ID          int64     `gorm:"primaryKey" json:"id"`
+	InvoiceID   uuid.UUID `gorm:"type:uuid;not null;index" json:"invoice_id"`
+	Type        string    `gorm:"type:varchar(30);not null" json:"type"` // consultation_fee or admin_fee
+	Description string    `gorm:"type:varchar(255);not null" json:"description"`
+	Amount      int64     `gorm:"not null" json:"amount"`
+}
+
+func (InvoiceItem) TableName() string {
+	return "invoice_items"
+}
+
+// InvoiceFilter for listing invoices in the admin finance views
+type InvoiceFilter struct {
+	Status    InvoiceStatus
+	DoctorID  *uuid.UUID
+	PatientID *uuid.UUID
+	From      *time.Time // Issued at or after
+	To        *time.Time // Issued before
+}
+
+// InvoiceTotal aggregates the invoices of one currency and status (finance report)
+type InvoiceTotal struct {
+	Currency         string
+	Status           InvoiceStatus
+	Invoices         int64
+	Total            int64
+	ConsultationFees int64
+	AdminFees        int64
+}
@@ -0,0 +1,41 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Login methods
+const (
+	LoginMethodPassword  = "password"
+	LoginMethodPhoneOTP  = "phone_otp"
+	LoginMethodGoogle    = "google"
+	LoginMethodTwoFactor = "two_factor" // Second step after password, phone code or Google
+)
+
+// Login failure reasons
+const (
+	LoginFailureUnknownEmail    = "unknown_email"
+	LoginFailureInvalidPassword = "invalid_password"
+	LoginFailureAccountLocked   = "account_locked"
+	LoginFailureInvalidCode     = "invalid_code"
+)
+
+// LoginHistory is one login attempt and the client it came from
+type LoginHistory struct {
+	ID            int64      `gorm:"primaryKey;autoIncrement" json:"id"`
+	UserID        *uuid.UUID `gorm:"type:uuid;index" json:"user_id,omitempty"` // nil = email of no account
+	Email         string     `gorm:"type:varchar(255);not null;default:''" json:"email"`
+	Method        string     `gorm:"type:varchar(20);not null" json:"method"`
+	Success       bool       `gorm:"not null" json:"success"`
+	FailureReason string     `gorm:"type:varchar(50);not null;default:''" json:"failure_reason,omitempty"`
+	IPAddress     string     `gorm:"type:varchar(255);not null;default:''" json:"ip_address"`
+	UserAgent     string     `gorm:"type:varchar(255);not null;default:''" json:"user_agent"`
+	Device        string     `gorm:"type:varchar(255);not null;default:''" json:"device"`
+	CreatedAt     time.Time  `gorm:"autoCreateTime" json:"created_at"`
+}
+
+func (LoginHistory) TableName() string {
+	return "login_history"
+}
@@ -0,0 +1,42 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MedicalRecord holds the doctor's notes of a visit, one record per booking.
+// It is written by the doctor of the booking once the patient was called, and
+// readable by the patient and the doctors the patient has (or had) a booking with.
+type MedicalRecord struct {
+	BookingID     uuid.UUID           `gorm:"type:uuid;primaryKey" json:"booking_id"`
+	PatientID     uuid.UUID           `gorm:"type:uuid;not null;index" json:"patient_id"`
+	DoctorID      uuid.UUID           `gorm:"type:uuid;not null;index" json:"doctor_id"` // Author of the record
+	ScheduleID    int                 `gorm:"not null" json:"schedule_id"`
+	Diagnosis     string              `gorm:"type:text;not null" json:"diagnosis"`
+	ClinicalNotes string              `gorm:"type:text" json:"clinical_notes,omitempty"`
+	Vitals        MedicalRecordVitals `gorm:"embedded" json:"vitals"`
+	Version       int                 `gorm:"not null;default:1" json:"version"` // Incremented on every update
+	CreatedAt     time.Time           `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt     time.Time           `gorm:"autoUpdateTime" json:"updated_at"`
+
+	// Relationships
+	Booking Booking `gorm:"foreignKey:BookingID" json:"booking,omitempty"`
+}
+
+// MedicalRecordVitals are the vital signs measured at the visit, nil when not measured
+type MedicalRecordVitals struct {
+	SystolicBP       *int     `gorm:"column:systolic_bp" json:"systolic_bp,omitempty"`   // mmHg
+	DiastolicBP      *int     `gorm:"column:diastolic_bp" json:"diastolic_bp,omitempty"` // mmHg
+	HeartRate        *int     `json:"heart_rate,omitempty"`                              // Beats per minute
+	RespiratoryRate  *int     `json:"respiratory_rate,omitempty"`                        // Breaths per minute
+	Temperature      *float64 `gorm:"type:numeric(4,1)" json:"temperature,omitempty"`    // °C
+	OxygenSaturation *int     `json:"oxygen_saturation,omitempty"`                       // SpO2 %
+	WeightKg         *float64 `gorm:"type:numeric(5,1)" json:"weight_kg,omitempty"`
+	HeightCm         *float64 `gorm:"type:numeric(4,1)" json:"height_cm,omitempty"`
+}
+
+func (MedicalRecord) TableName() string {
+	return "medical_records"
+}
@@ -0,0 +1,95 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// NotificationStatus represents the delivery state of a notification
+type NotificationStatus string
+
+const (
+	NotificationStatusPending    NotificationStatus = "pending"     // Waiting for (re)delivery
+	NotificationStatusSent       NotificationStatus = "sent"        // Accepted by the provider
+	NotificationStatusDelivered  NotificationStatus = "delivered"   // Confirmed by the provider callback
+	NotificationStatusFailed     NotificationStatus = "failed"      // Reported undeliverable by the provider, or the account is inactive
+	NotificationStatusBounced    NotificationStatus = "bounced"     // The recipient address does not exist or refused it (provider callback)
+	NotificationStatusDeadLetter NotificationStatus = "dead_letter" // Rejected by the provider or gave up after max attempts, admins can re-drive it
+)
+
+// Notification channels
+const (
+	NotificationChannelSMS      = "sms"
+	NotificationChannelWhatsApp = "whatsapp"
+	NotificationChannelEmail    = "email"
+	NotificationChannelPush     = "push"   // Sent to each registered device, in addition to the main channel
+	NotificationChannelInApp    = "in_app" // Shown in the notification center of the app, delivered on creation
+)
+
+// Notification is one outbound message to a patient and its delivery status.
+// DedupeKey identifies the source (e.g. "outbox:<event id>") so a redelivered
+// source event does not notify the patient twice.
+type Notification struct {
+	ID                uuid.UUID          `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	PatientID         uuid.UUID          `gorm:"type:uuid;not null" json:"patient_id"`
+	BookingID         *uuid.UUID         `gorm:"type:uuid;index" json:"booking_id,omitempty"`
+	DedupeKey         string             `gorm:"type:varchar(150);not null;uniqueIndex" json:"-"`
+	EventType         string             `gorm:"type:varchar(100);not null" json:"event_type"`
+	Channel           string             `gorm:"type:varchar(20);not null" json:"channel"`
+	Recipient         string             `gorm:"type:varchar(512);not null" json:"recipient"`
+	Subject           string             `gorm:"type:varchar(255);not null;default:''" json:"subject,omitempty"` // Email only
+	Message           string             `gorm:"type:text;not null" json:"message"`
+	Status            NotificationStatus `gorm:"type:varchar(20);not null;default:'pending'" json:"status"`
+	Provider          string             `gorm:"type:varchar(50)" json:"provider,omitempty"`
+	ProviderMessageID *string            `gorm:"type:varchar(255)" json:"provider_message_id,omitempty"`
+	Attempts          int                `gorm:"not null;default:0" json:"attempts"`
+	NextAttemptAt     time.Time          `gorm:"not null" json:"next_attempt_at"`
+	LastError         string             `gorm:"type:text" json:"last_error,omitempty"`
+	SentAt            *time.Time         `json:"sent_at,omitempty"`
+	DeliveredAt       *time.Time         `json:"delivered_at,omitempty"`
+	FailedAt          *time.Time         `json:"failed_at,omitempty"`
+	ReadAt            *time.Time         `json:"read_at,omitempty"`     // In-app only
+	RedrivenAt        *time.Time         `json:"redriven_at,omitempty"` // Last time an admin sent it again from the dead letters
+	CreatedAt         time.Time          `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt         time.Time          `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+func (Notification) TableName() string {
+	return "notifications"
+}
+
+// NotificationAttempt is one send of a notification to its provider
+type NotificationAttempt struct {
+	ID                int64     `gorm:"primaryKey;autoIncrement" json:"id"`
+	NotificationID    uuid.UUID `gorm:"type:uuid;not null;index" json:"notification_id"`
+	Attempt           int       `gorm:"not null" json:"attempt"` // 1 for the first send, restarts after a re-drive
+	Channel           string    `gorm:"type:varchar(20);not null" json:"channel"`
+	Recipient         string    `gorm:"type:varchar(512);not null" json:"recipient"`
+	Provider          string    `gorm:"type:varchar(50);not null" json:"provider"`
+	Succeeded         bool      `gorm:"not null" json:"succeeded"`
+	ProviderMessageID string    `gorm:"type:varchar(255)" json:"provider_message_id,omitempty"`
+	Error             string    `gorm:"type:text" json:"error,omitempty"`
+	DurationMs        int64     `gorm:"not null;default:0" json:"duration_ms"`
+	CreatedAt         time.Time `gorm:"autoCreateTime" json:"created_at"`
+}
+
+func (NotificationAttempt) TableName() string {
+	return "notification_attempts"
+}
+
+// NotificationDeliveryReport is a provider callback about a sent notification. Applied is
+// false for callbacks ignored because the notification already had a final status.
+type NotificationDeliveryReport struct {
+	ID             int64              `gorm:"primaryKey;autoIncrement" json:"id"`
+	NotificationID uuid.UUID          `gorm:"type:uuid;not null;index" json:"notification_id"`
+	Provider       string             `gorm:"type:varchar(50);not null" json:"provider"`
+	Status         NotificationStatus `gorm:"type:varchar(20);not null" json:"status"`
+	Error          string             `gorm:"type:text" json:"error,omitempty"`
+	Applied        bool               `gorm:"not null" json:"applied"`
+	ReceivedAt     time.Time          `gorm:"autoCreateTime" json:"received_at"`
+}
+
+func (NotificationDeliveryReport) TableName() string {
+	return "notification_delivery_reports"
+}
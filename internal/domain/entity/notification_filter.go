@@ -0,0 +1,13 @@
+package entity
+
+import "time"
+
+// NotificationFilter is a domain-level filter for the notification history of a patient.
+// Empty fields match everything.
+type NotificationFilter struct {
+	Status    NotificationStatus
+	EventType string
+	Channel   string
+	From      *time.Time // Created at or after
+	To        *time.Time // Created before
+}
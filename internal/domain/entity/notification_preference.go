@@ -0,0 +1,30 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// NotificationPreference holds how a user wants to be notified. Users without a row
+// use the defaults (see DefaultNotificationPreference).
+type NotificationPreference struct {
+	UserID       uuid.UUID `gorm:"type:uuid;primaryKey" json:"user_id"`
+	Channel      string    `gorm:"type:varchar(20);not null;default:''" json:"channel"` // sms, whatsapp or email; empty = the notification's own channel
+	Reminders    bool      `gorm:"not null;default:true" json:"reminders"`              // Appointment reminders
+	AgendaDigest bool      `gorm:"not null;default:true" json:"agenda_digest"`          // Doctors' daily agenda email
+	UpdatedAt    time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+func (NotificationPreference) TableName() string {
+	return "notification_preferences"
+}
+
+// DefaultNotificationPreference is the preference of a user who never changed it
+func DefaultNotificationPreference(userID uuid.UUID) *NotificationPreference {
+	return &NotificationPreference{
+		UserID:       userID,
+		Reminders:    true,
+		AgendaDigest: true,
+	}
+}
@@ -0,0 +1,77 @@
+package entity
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Variables available to notification templates as {{.Name}}. Which ones are set depends
+// on the event, a template using one the event does not have falls back to the default text.
+const (
+	TemplateVarPatientName  = "PatientName"  // Every event
+	TemplateVarSubject      = "Subject"      // Every event, the default subject
+	TemplateVarMessage      = "Message"      // Every event, the default message (the announcement of broadcasts)
+	TemplateVarBookingCode  = "BookingCode"  // Booking and schedule events
+	TemplateVarQueueNumber  = "QueueNumber"  // booking.created, booking.called, reminders
+	TemplateVarDoctorName   = "DoctorName"   // booking.created, booking.cancelled, booking.rescheduled, reminders, doctor.agenda_digest
+	TemplateVarScheduleSlot = "ScheduleSlot" // Booking and schedule events, the new slot on changes
+	TemplateVarOldSlot      = "OldSlot"      // booking.rescheduled, schedule.updated
+	TemplateVarTitle        = "Title"        // Broadcasts
+	TemplateVarAgendaDate   = "AgendaDate"   // doctor.agenda_digest
+	TemplateVarAgenda       = "Agenda"       // doctor.agenda_digest, the day's schedules with their patients in queue order
+)
+
+// NotificationTemplate is admin-editable content of the notifications of an event type.
+// Empty Channel or Locale match any; the most specific template wins (see BestNotificationTemplate).
+type NotificationTemplate struct {
+	ID        int        `gorm:"primaryKey;autoIncrement" json:"id"`
+	EventType string     `gorm:"type:varchar(100);not null;uniqueIndex:uq_notification_templates_variant" json:"event_type"`
+	Channel   string     `gorm:"type:varchar(20);not null;default:'';uniqueIndex:uq_notification_templates_variant" json:"channel"`
+	Locale    string     `gorm:"type:varchar(20);not null;default:'';uniqueIndex:uq_notification_templates_variant" json:"locale"`
+	Subject   string     `gorm:"type:varchar(255);not null;default:''" json:"subject"` // Empty keeps the default subject
+	Body      string     `gorm:"type:text;not null" json:"body"`
+	UpdatedBy *uuid.UUID `gorm:"type:uuid" json:"updated_by,omitempty"`
+	CreatedAt time.Time  `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt time.Time  `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+func (NotificationTemplate) TableName() string {
+	return "notification_templates"
+}
+
+// BestNotificationTemplate picks the template of a channel and locale among the templates
+// of one event type. A matching channel beats any locale match; within it an exact locale
+// beats its language ("id" for "id-ID"), which beats a template for any locale.
+// Returns nil when none applies.
+func BestNotificationTemplate(templates []NotificationTemplate, channel, locale string) *NotificationTemplate {
+	language, _, _ := strings.Cut(locale, "-")
+
+	var best *NotificationTemplate
+	bestScore := -1
+	for i := range templates {
+		t := &templates[i]
+		score := 0
+		switch t.Channel {
+		case channel:
+			score += 10
+		case "":
+		default:
+			continue
+		}
+		switch {
+		case strings.EqualFold(t.Locale, locale):
+			score += 2
+		case strings.EqualFold(t.Locale, language):
+			score++
+		case t.Locale == "":
+		default:
+			continue
+		}
+		if score > bestScore {
+			best, bestScore = t, score
+		}
+	}
+	return best
+}
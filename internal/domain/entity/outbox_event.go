@@ -0,0 +1,186 @@
+package entity
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// OutboxStatus represents the publishing state of an outbox event
+type OutboxStatus string
+
+const (
+	OutboxStatusPending   OutboxStatus = "pending"
+	OutboxStatusPublished OutboxStatus = "published"
+	OutboxStatusFailed    OutboxStatus = "failed" // Gave up after max attempts
+)
+
+// Booking event types published through the outbox
+const (
+	OutboxEventBookingCreated     = "booking.created"
+	OutboxEventBookingCancelled   = "booking.cancelled"
+	OutboxEventBookingRescheduled = "booking.rescheduled"
+	OutboxEventBookingCalled      = "booking.called"
+	OutboxEventBookingConfirmed   = "booking.confirmed" // Consultation fee paid
+)
+
+// Schedule event types published through the outbox
+const (
+	OutboxEventScheduleUpdated     = "schedule.updated"
+	OutboxEventScheduleDoctorLeave = "schedule.doctor_leave" // Closed because the doctor took leave
+)
+
+// Payment event types published through the outbox
+const (
+	OutboxEventPaymentExpire = "payment.expire" // Due when the payment window of a booking closes
+	OutboxEventPaymentRefund = "payment.refund" // Refund requested, sent to the gateway by the outbox worker
+)
+
+// Broadcast event types published through the outbox
+const (
+	OutboxEventBroadcastDelivery = "broadcast.delivery"
+)
+
+// Security event types published through the outbox
+const (
+	OutboxEventSecurityAlert = "security.alert"
+)
+
+// Personal data export event types published through the outbox
+const (
+	OutboxEventDataExportRequested = "data_export.requested" // Generate the archive
+	OutboxEventDataExportExpire    = "data_export.expire"    // Due at expiry, deletes the archive
+)
+
+// OutboxEvent is a side effect recorded in the same transaction as the state change
+// that caused it, and published later by the outbox worker (at-least-once).
+type OutboxEvent struct {
+	ID            int64        `gorm:"primaryKey;autoIncrement" json:"id"`
+	AggregateType string       `gorm:"type:varchar(50);not null" json:"aggregate_type"`
+	AggregateID   string       `gorm:"type:varchar(100);not null" json:"aggregate_id"`
+	EventType     string       `gorm:"type:varchar(100);not null" json:"event_type"`
+	Payload       JSON         `gorm:"type:jsonb" json:"payload,omitempty"`
+	Status        OutboxStatus `gorm:"type:varchar(20);not null;default:'pending'" json:"status"`
+	Attempts      int          `gorm:"not null;default:0" json:"attempts"`
+	NextAttemptAt time.Time    `gorm:"not null" json:"next_attempt_at"`
+	LastError     string       `gorm:"type:text" json:"last_error,omitempty"`
+	CreatedAt     time.Time    `gorm:"autoCreateTime" json:"created_at"`
+	PublishedAt   *time.Time   `json:"published_at,omitempty"`
+}
+
+func (OutboxEvent) TableName() string {
+	return "outbox_events"
+}
+
+// DecodePayload unmarshals the event payload into v
+func (e *OutboxEvent) DecodePayload(v interface{}) error {
+	raw, err := json.Marshal(e.Payload)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(raw, v)
+}
+
+// Booking cancellation reasons carried by booking.cancelled events
+const (
+	BookingCancelReasonPatient  = "patient"
+	BookingCancelReasonAdmin    = "admin"
+	BookingCancelReasonReassign = "reassign"
+	BookingCancelReasonPayment  = "payment"  // Fee not paid in time, declined, or the charge could not be opened
+	BookingCancelReasonSchedule = "schedule" // Schedule deleted by force
+)
+
+// BookingEventPayload is the payload of every booking.* outbox event
+type BookingEventPayload struct {
+	BookingID      uuid.UUID `json:"booking_id"`
+	BookingCode    string    `json:"booking_code"`
+	PatientID      uuid.UUID `json:"patient_id"`
+	ScheduleID     int       `json:"schedule_id"`
+	FromScheduleID int       `json:"from_schedule_id,omitempty"` // booking.rescheduled only
+	QueueNumber    int       `json:"queue_number"`
+	SlotID         *int64    `json:"slot_id,omitempty"` // Appointment slot held by the booking (slot-mode schedules)
+
+	// Promoted marks a booking.created from the waitlist
+	Promoted bool `json:"promoted,omitempty"`
+
+	// AwaitingPayment marks a booking.created that is confirmed once the fee is paid (booking.confirmed)
+	AwaitingPayment bool `json:"awaiting_payment,omitempty"`
+
+	// CancelReason is set on booking.cancelled (patient, admin, reassign, payment, schedule)
+	CancelReason string `json:"cancel_reason,omitempty"`
+
+	// ReleaseSlot returns the freed slot to Redis (ScheduleID, or FromScheduleID when rescheduled);
+	// PromoteWaitlist offers it to the schedule's waitlist first
+	ReleaseSlot     bool `json:"release_slot,omitempty"`
+	PromoteWaitlist bool `json:"promote_waitlist,omitempty"`
+}
+
+// NotifiesPatient reports whether a booking event of eventType results in a patient notification
+func (p *BookingEventPayload) NotifiesPatient(eventType string) bool {
+	switch eventType {
+	case OutboxEventBookingCancelled:
+		return p.CancelReason != BookingCancelReasonPatient
+	case OutboxEventBookingCreated:
+		// Promoted patients learn about their booking (and the fee to pay) only this way
+		return !p.AwaitingPayment || p.Promoted
+	case OutboxEventBookingConfirmed, OutboxEventBookingRescheduled, OutboxEventBookingCalled:
+		return true
+	}
+	return false
+}
+
+// ScheduleEventPayload is the payload of schedule.updated events (date/time changes only).
+// Dates are YYYY-MM-DD, times HH:MM or HH:MM:SS.
+type ScheduleEventPayload struct {
+	ScheduleID      int    `json:"schedule_id"`
+	OldScheduleDate string `json:"old_schedule_date"`
+	OldStartTime    string `json:"old_start_time"`
+	OldEndTime      string `json:"old_end_time"`
+	ScheduleDate    string `json:"schedule_date"`
+	StartTime       string `json:"start_time"`
+	EndTime         string `json:"end_time"`
+}
+
+// ScheduleLeavePayload is the payload of schedule.doctor_leave events (one per closed schedule).
+// The date is YYYY-MM-DD, times HH:MM or HH:MM:SS.
+type ScheduleLeavePayload struct {
+	ScheduleID   int    `json:"schedule_id"`
+	LeaveID      int    `json:"leave_id"`
+	ScheduleDate string `json:"schedule_date"`
+	StartTime    string `json:"start_time"`
+	EndTime      string `json:"end_time"`
+}
+
+// PaymentExpirePayload is the payload of payment.expire events
+type PaymentExpirePayload struct {
+	BookingID uuid.UUID `json:"booking_id"`
+}
+
+// PaymentRefundPayload is the payload of payment.refund events
+type PaymentRefundPayload struct {
+	PaymentID uuid.UUID `json:"payment_id"`
+}
+
+// BroadcastDeliveryPayload is the payload of broadcast.delivery events (one per recipient)
+type BroadcastDeliveryPayload struct {
+	BroadcastID uuid.UUID `json:"broadcast_id"`
+	RecipientID int64     `json:"recipient_id"`
+	PatientID   uuid.UUID `json:"patient_id"`
+}
+
+// SecurityAlertPayload is the payload of security.alert events: an audited
+// security-sensitive action on the account of UserID
+type SecurityAlertPayload struct {
+	UserID     uuid.UUID  `json:"user_id"`
+	Action     string     `json:"action"`
+	ActorID    *uuid.UUID `json:"actor_id,omitempty"` // Who acted when not the user, e.g. an admin
+	Details    JSON       `json:"details,omitempty"`  // The audited values
+	OccurredAt time.Time  `json:"occurred_at"`
+}
+
+// DataExportPayload is the payload of data_export.* events
+type DataExportPayload struct {
+	ExportID uuid.UUID `json:"export_id"`
+	UserID   uuid.UUID `json:"user_id"`
+}
@@ -0,0 +1,9 @@
+package entity
+
+// PatientFilter is a domain-level filter for the staff patient search.
+// Set criteria must all match; at least one is expected.
+type PatientFilter struct {
+	NIK         string // Exact NIK
+	PhoneNumber string // Exact phone number
+	Name        string // Part of the full name (ILIKE, trigram indexed)
+}
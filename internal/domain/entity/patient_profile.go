@@ -1,6 +1,7 @@
 package entity
 
 import (
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -14,6 +15,18 @@ type PatientProfile struct {
 	DateOfBirth time.Time `gorm:"type:date;not null" json:"date_of_birth"`
 	Gender      string    `gorm:"type:char(1);not null" json:"gender"`
 	Address     string    `gorm:"type:text" json:"address,omitempty"`
+	Partner     string    `gorm:"type:varchar(100)" json:"partner,omitempty"` // Roster the patient was pre-registered from
+
+	// Health insurance the patient can attach to bookings for claims (empty provider = none)
+	InsuranceProvider     string     `gorm:"type:varchar(100)" json:"insurance_provider,omitempty"`
+	InsurancePolicyNumber string     `gorm:"type:varchar(50)" json:"insurance_policy_number,omitempty"`
+	InsuranceValidFrom    *time.Time `gorm:"type:date" json:"insurance_valid_from,omitempty"`  // nil = no start limit
+	InsuranceValidUntil   *time.Time `gorm:"type:date" json:"insurance_valid_until,omitempty"` // nil = no end limit
+
+	// Who to contact in an emergency, shown to doctors and admins (empty name = none)
+	EmergencyContactName         string `gorm:"type:varchar(100)" json:"emergency_contact_name,omitempty"`
+	EmergencyContactRelationship string `gorm:"type:varchar(50)" json:"emergency_contact_relationship,omitempty"`
+	EmergencyContactPhone        string `gorm:"type:varchar(20)" json:"emergency_contact_phone,omitempty"`
 
 	// Relationships
 	User     User      `gorm:"foreignKey:UserID" json:"user,omitempty"`
@@ -29,3 +42,49 @@ const (
 	GenderMale   = "M"
 	GenderFemale = "F"
 )
+
+// HasInsurance reports whether the patient registered a health insurance
+func (p *PatientProfile) HasInsurance() bool {
+	return p.InsuranceProvider != ""
+}
+
+// InsuranceValidOn reports whether the registered insurance covers the given date
+func (p *PatientProfile) InsuranceValidOn(date time.Time) bool {
+	if !p.HasInsurance() {
+		return false
+	}
+	day := date.Format("2006-01-02")
+	if p.InsuranceValidFrom != nil && day < p.InsuranceValidFrom.Format("2006-01-02") {
+		return false
+	}
+	if p.InsuranceValidUntil != nil && day > p.InsuranceValidUntil.Format("2006-01-02") {
+		return false
+	}
+	return true
+}
+
+// ClearInsurance removes the registered health insurance
+func (p *PatientProfile) ClearInsurance() {
+	p.InsuranceProvider = ""
+	p.InsurancePolicyNumber = ""
+	p.InsuranceValidFrom = nil
+	p.InsuranceValidUntil = nil
+}
+
+// HasEmergencyContact reports whether the patient registered an emergency contact
+func (p *PatientProfile) HasEmergencyContact() bool {
+	return p.EmergencyContactName != ""
+}
+
+// ClearEmergencyContact removes the registered emergency contact
+func (p *PatientProfile) ClearEmergencyContact() {
+	p.EmergencyContactName = ""
+	p.EmergencyContactRelationship = ""
+	p.EmergencyContactPhone = ""
+}
+
+// ErasedNIK is the placeholder NIK of an erased account. NIKs are numeric, so the
+// X prefix never collides with a real one; the rest keeps it unique per user.
+func ErasedNIK(userID uuid.UUID) string {
+	return "X" + strings.ReplaceAll(userID.String(), "-", "")[:15]
+}
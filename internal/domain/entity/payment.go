@@ -0,0 +1,78 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PaymentStatus represents the state of a booking fee payment
+type PaymentStatus string
+
+const (
+	PaymentStatusPending   PaymentStatus = "pending"   // Waiting for the patient to pay on the gateway page
+	PaymentStatusPaid      PaymentStatus = "paid"      // Confirmed by a gateway callback
+	PaymentStatusFailed    PaymentStatus = "failed"    // Declined at the gateway, or the charge could not be opened
+	PaymentStatusExpired   PaymentStatus = "expired"   // Not paid in time
+	PaymentStatusCancelled PaymentStatus = "cancelled" // Booking cancelled before it was paid
+)
+
+// PaymentRefundStatus represents the state of the refund of a paid payment
+type PaymentRefundStatus string
+
+const (
+	PaymentRefundNone      PaymentRefundStatus = ""          // Not refunded
+	PaymentRefundPending   PaymentRefundStatus = "pending"   // Requested, waiting for the gateway
+	PaymentRefundSucceeded PaymentRefundStatus = "succeeded" // Returned to the payer
+	PaymentRefundFailed    PaymentRefundStatus = "failed"    // Rejected by the gateway, may be requested again
+)
+
+// Payment is the consultation fee of a booking, charged through the payment gateway.
+// The booking waits in awaiting_payment until a gateway callback reports the payment.
+// A paid payment is refunded when its booking is cancelled within the cancellation policy.
+type Payment struct {
+	ID               uuid.UUID     `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	BookingID        uuid.UUID     `gorm:"type:uuid;not null;index" json:"booking_id"`
+	PatientID        uuid.UUID     `gorm:"type:uuid;not null;index" json:"patient_id"`
+	Gateway          string        `gorm:"type:varchar(20);not null" json:"gateway"`
+	Amount           int64         `gorm:"not null" json:"amount"`              // Smallest currency unit
+	AdminFee         int64         `gorm:"not null;default:0" json:"admin_fee"` // Part of Amount, the rest is the consultation fee
+	Currency         string        `gorm:"type:varchar(3);not null" json:"currency"`
+	Status           PaymentStatus `gorm:"type:varchar(20);not null;default:'pending'" json:"status"`
+	GatewayReference string        `gorm:"type:varchar(255)" json:"gateway_reference,omitempty"` // Charge, then transaction ID at the gateway
+	PaymentURL       string        `gorm:"type:varchar(1024)" json:"payment_url,omitempty"`      // Hosted payment page
+	FailureReason    string        `gorm:"type:text" json:"failure_reason,omitempty"`
+	ExpiresAt        time.Time     `gorm:"not null" json:"expires_at"`
+	PaidAt           *time.Time    `json:"paid_at,omitempty"`
+
+	// Refund of a paid payment
+	RefundStatus        PaymentRefundStatus `gorm:"type:varchar(20);not null;default:''" json:"refund_status,omitempty"`
+	RefundAmount        int64               `gorm:"not null;default:0" json:"refund_amount,omitempty"`
+	RefundReason        string              `gorm:"type:varchar(255)" json:"refund_reason,omitempty"`
+	RefundReference     string              `gorm:"type:varchar(255)" json:"refund_reference,omitempty"` // ID of the refund at the gateway
+	RefundFailureReason string              `gorm:"type:text" json:"refund_failure_reason,omitempty"`
+	RefundRequestedAt   *time.Time          `json:"refund_requested_at,omitempty"`
+	RefundedAt          *time.Time          `json:"refunded_at,omitempty"`
+
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+func (Payment) TableName() string {
+	return "payments"
+}
+
+// IsPending checks if the payment is still waiting for the patient
+func (p *Payment) IsPending() bool {
+	return p.Status == PaymentStatusPending
+}
+
+// IsPaid checks if the payment is confirmed
+func (p *Payment) IsPaid() bool {
+	return p.Status == PaymentStatusPaid
+}
+
+// IsRefundable checks if the payment is paid and not refunded, nor being refunded
+func (p *Payment) IsRefundable() bool {
+	return p.IsPaid() && (p.RefundStatus == PaymentRefundNone || p.RefundStatus == PaymentRefundFailed)
+}
@@ -0,0 +1,40 @@
+package entity
+
+// Permission is an action on a resource (e.g. schedule:write) granted to roles.
+// Routes require permissions instead of role IDs, so staff roles (nurse, receptionist)
+// are configured in the database without code changes.
+type Permission struct {
+	ID          int    `gorm:"primaryKey;autoIncrement" json:"id"`
+	Code        string `gorm:"type:varchar(100);uniqueIndex;not null" json:"code"`
+	Description string `gorm:"type:text" json:"description,omitempty"`
+}
+
+func (Permission) TableName() string {
+	return "permissions"
+}
+
+// Permission codes (seeded by migration 000033)
+const (
+	PermissionUserManage      = "user:manage"
+	PermissionRoleManage      = "role:manage"
+	PermissionDoctorRead      = "doctor:read"
+	PermissionDoctorWrite     = "doctor:write"
+	PermissionScheduleRead    = "schedule:read"
+	PermissionScheduleWrite   = "schedule:write"
+	PermissionScheduleApprove = "schedule:approve"
+	PermissionBookingRead     = "booking:read"
+	PermissionBookingWrite    = "booking:write"
+	PermissionPatientRead     = "patient:read"
+	PermissionPatientWrite    = "patient:write"
+	PermissionBroadcastRead   = "broadcast:read"
+	PermissionBroadcastSend   = "broadcast:send"
+	PermissionSettingsRead    = "settings:read"
+	PermissionSettingsWrite   = "settings:write"
+	PermissionSystemManage    = "system:manage"
+	PermissionReportRead      = "report:read"
+	PermissionAuditRead       = "audit:read"
+	PermissionAPIKeyManage    = "api_key:manage"   // Seeded by migration 000035
+	PermissionUserImpersonate = "user:impersonate" // Seeded by migration 000037
+	PermissionDoctorPortal    = "portal:doctor"    // Own schedules, queue and profile of a doctor
+	PermissionPatientPortal   = "portal:patient"   // Own bookings and profile of a patient
+)
@@ -0,0 +1,75 @@
+package entity
+
+import "strings"
+
+// Profile fields checked by ProfileCompleteness, also the names BOOKING_REQUIRED_PROFILE_FIELDS accepts
+const (
+	ProfileFieldEmail            = "email" // a real address, not the placeholder of an unclaimed patient
+	ProfileFieldPhoneNumber      = "phone_number"
+	ProfileFieldAddress          = "address"
+	ProfileFieldEmergencyContact = "emergency_contact"
+	ProfileFieldBiography        = "biography"
+	ProfileFieldPhoto            = "photo"
+	ProfileFieldConsultationFee  = "consultation_fee"
+)
+
+// ProfileCompleteness is how much of the profile the user filled in.
+// Score is the percentage of the checked fields present, Missing lists the others.
+type ProfileCompleteness struct {
+	Score   int
+	Missing []string
+}
+
+// MissingOf returns the missing fields among the given ones, in the order of Missing
+func (c ProfileCompleteness) MissingOf(fields []string) []string {
+	var missing []string
+	for _, field := range c.Missing {
+		for _, wanted := range fields {
+			if field == wanted {
+				missing = append(missing, field)
+				break
+			}
+		}
+	}
+	return missing
+}
+
+type profileCheck struct {
+	field   string
+	present bool
+}
+
+// ProfileCompleteness checks the fields of the user's role profile. Admins have no
+// role profile, only their email is checked. The role profile must be loaded.
+func (u *User) ProfileCompleteness() ProfileCompleteness {
+	checks := []profileCheck{
+		{ProfileFieldEmail, !strings.HasSuffix(u.Email, ".invalid")},
+	}
+
+	if p := u.PatientProfile; p != nil {
+		checks = append(checks, []profileCheck{
+			{ProfileFieldPhoneNumber, p.PhoneNumber != ""},
+			{ProfileFieldAddress, strings.TrimSpace(p.Address) != ""},
+			{ProfileFieldEmergencyContact, p.HasEmergencyContact()},
+		}...)
+	}
+	if d := u.DoctorProfile; d != nil {
+		checks = append(checks, []profileCheck{
+			{ProfileFieldBiography, strings.TrimSpace(d.Biography) != ""},
+			{ProfileFieldPhoto, d.PhotoURL != ""},
+			{ProfileFieldConsultationFee, d.ConsultationFee > 0},
+		}...)
+	}
+
+	completeness := ProfileCompleteness{Missing: []string{}}
+	present := 0
+	for _, check := range checks {
+		if check.present {
+			present++
+		} else {
+			completeness.Missing = append(completeness.Missing, check.field)
+		}
+	}
+	completeness.Score = present * 100 / len(checks)
+	return completeness
+}
@@ -7,14 +7,16 @@ type Role struct {
 	Description string `gorm:"type:text" json:"description,omitempty"`
 
 	// Relationships
-	Users []User `gorm:"foreignKey:RoleID" json:"users,omitempty"`
+	Users       []User       `gorm:"foreignKey:RoleID" json:"users,omitempty"`
+	Permissions []Permission `gorm:"many2many:role_permissions" json:"permissions,omitempty"`
 }
 
 func (Role) TableName() string {
 	return "roles"
 }
 
-// Role ID constants
+// Role ID constants. Doctor and patient accounts carry a profile, so these roles are
+// assigned on registration; access checks use permissions (see Permission).
 const (
 	RoleIDAdmin   = 1
 	RoleIDDoctor  = 2
@@ -27,3 +29,8 @@ const (
 	RoleDoctor  = "doctor"
 	RolePatient = "patient"
 )
+
+// IsProfileRole reports whether accounts of the role need a doctor or patient profile
+func IsProfileRole(roleID int) bool {
+	return roleID == RoleIDDoctor || roleID == RoleIDPatient
+}
@@ -0,0 +1,105 @@
+package entity
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ScheduleBreak is a pause inside a schedule (e.g. lunch 12:00-13:00).
+// No time slots are generated in it and queue waits are estimated around it.
+type ScheduleBreak struct {
+	StartTime string `json:"start_time"` // Format: HH:MM
+	EndTime   string `json:"end_time"`   // Format: HH:MM
+}
+
+// ScheduleBreaks are the breaks of a schedule ordered by start time, stored as a JSONB array
+type ScheduleBreaks []ScheduleBreak
+
+// Interval is the half-open time range [Start, End)
+type Interval struct {
+	Start time.Time
+	End   time.Time
+}
+
+// Value returns json value, implement driver.Valuer interface
+func (b ScheduleBreaks) Value() (driver.Value, error) {
+	if b == nil {
+		return "[]", nil
+	}
+	return json.Marshal(b)
+}
+
+// Scan scan value into ScheduleBreaks, implements sql.Scanner interface
+func (b *ScheduleBreaks) Scan(value interface{}) error {
+	if value == nil {
+		*b = nil
+		return nil
+	}
+	var bytes []byte
+	switch v := value.(type) {
+	case []byte:
+		bytes = v
+	case string:
+		bytes = []byte(v)
+	default:
+		return errors.New(fmt.Sprint("Failed to unmarshal JSONB value:", value))
+	}
+	return json.Unmarshal(bytes, b)
+}
+
+// Intervals returns the breaks as timestamps on the given date, in the given location
+func (b ScheduleBreaks) Intervals(date time.Time, loc *time.Location) ([]Interval, error) {
+	intervals := make([]Interval, 0, len(b))
+	for _, brk := range b {
+		start, err := combineDateAndClock(date, brk.StartTime, loc)
+		if err != nil {
+			return nil, err
+		}
+		end, err := combineDateAndClock(date, brk.EndTime, loc)
+		if err != nil {
+			return nil, err
+		}
+		intervals = append(intervals, Interval{Start: start, End: end})
+	}
+	return intervals, nil
+}
+
+// AddWorkingTime returns the moment d of working time after from, skipping the breaks.
+// A from inside a break starts counting when the break ends. breaks must be ordered.
+func AddWorkingTime(from time.Time, d time.Duration, breaks []Interval) time.Time {
+	at := from
+	for _, brk := range breaks {
+		if !brk.End.After(at) {
+			continue
+		}
+		if brk.Start.After(at) {
+			if !at.Add(d).After(brk.Start) {
+				break
+			}
+			d -= brk.Start.Sub(at)
+		}
+		at = brk.End
+	}
+	return at.Add(d)
+}
+
+// BreakTimeBetween returns how much of [from, to) falls into the breaks
+func BreakTimeBetween(from, to time.Time, breaks []Interval) time.Duration {
+	var total time.Duration
+	for _, brk := range breaks {
+		start, end := brk.Start, brk.End
+		if start.Before(from) {
+			start = from
+		}
+		if end.After(to) {
+			end = to
+		}
+		if end.After(start) {
+			total += end.Sub(start)
+		}
+	}
+	return total
+}
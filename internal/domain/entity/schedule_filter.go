@@ -6,5 +6,5 @@ type ScheduleFilter struct {
 	StartAt        string // Format: YYYY-MM-DD
 	EndAt          string // Format: YYYY-MM-DD
 	DoctorName     string // Filter by doctor name (ILIKE)
-	Specialization string // Filter by specialization (ILIKE)
+	Specialization string // Filter by specialization slug
 }
@@ -0,0 +1,93 @@
+package entity
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ScheduleGenerationStatus represents the progress of a generation run
+type ScheduleGenerationStatus string
+
+const (
+	ScheduleGenerationStatusRunning   ScheduleGenerationStatus = "running"
+	ScheduleGenerationStatusCompleted ScheduleGenerationStatus = "completed" // Individual dates may still have failed
+	ScheduleGenerationStatusFailed    ScheduleGenerationStatus = "failed"    // The run could not start generating
+)
+
+// What started a generation run
+const (
+	ScheduleGenerationTriggerNightly = "nightly"
+	ScheduleGenerationTriggerManual  = "manual"
+)
+
+// Reasons a template date was not generated
+const (
+	ScheduleGenerationSkipHoliday  = "holiday"
+	ScheduleGenerationSkipLeave    = "doctor_leave"
+	ScheduleGenerationSkipConflict = "conflict" // Overlaps another schedule of the doctor
+)
+
+// ScheduleGenerationRun is the report of one expansion of the schedule templates
+type ScheduleGenerationRun struct {
+	ID            uuid.UUID                 `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	Trigger       string                    `gorm:"type:varchar(20);not null" json:"trigger"`
+	FromDate      time.Time                 `gorm:"type:date;not null" json:"from_date"`
+	ToDate        time.Time                 `gorm:"type:date;not null" json:"to_date"`
+	Status        ScheduleGenerationStatus  `gorm:"type:varchar(20);not null;default:'running'" json:"status"`
+	CreatedCount  int                       `gorm:"not null;default:0" json:"created_count"`
+	ExistingCount int                       `gorm:"not null;default:0" json:"existing_count"` // Generated by an earlier run
+	SkippedCount  int                       `gorm:"not null;default:0" json:"skipped_count"`
+	FailedCount   int                       `gorm:"not null;default:0" json:"failed_count"`
+	Details       ScheduleGenerationDetails `gorm:"type:jsonb" json:"details"`
+	Error         string                    `gorm:"type:text" json:"error,omitempty"`
+	StartedAt     time.Time                 `gorm:"not null" json:"started_at"`
+	FinishedAt    *time.Time                `json:"finished_at,omitempty"`
+}
+
+func (ScheduleGenerationRun) TableName() string {
+	return "schedule_generation_runs"
+}
+
+// ScheduleGenerationItem is one template date of a generation run
+type ScheduleGenerationItem struct {
+	TemplateID   int       `json:"template_id"`
+	DoctorID     uuid.UUID `json:"doctor_id"`
+	ScheduleDate string    `json:"schedule_date"`
+	ScheduleID   int       `json:"schedule_id,omitempty"` // Created only
+	Reason       string    `json:"reason,omitempty"`      // Skipped and failed only
+}
+
+// ScheduleGenerationDetails lists what a generation run did per template date (JSONB)
+type ScheduleGenerationDetails struct {
+	Created []ScheduleGenerationItem `json:"created"`
+	Skipped []ScheduleGenerationItem `json:"skipped"`
+	Failed  []ScheduleGenerationItem `json:"failed"`
+}
+
+// Value returns json value, implement driver.Valuer interface
+func (d ScheduleGenerationDetails) Value() (driver.Value, error) {
+	return json.Marshal(d)
+}
+
+// Scan scan value into ScheduleGenerationDetails, implements sql.Scanner interface
+func (d *ScheduleGenerationDetails) Scan(value interface{}) error {
+	if value == nil {
+		*d = ScheduleGenerationDetails{}
+		return nil
+	}
+	var bytes []byte
+	switch v := value.(type) {
+	case []byte:
+		bytes = v
+	case string:
+		bytes = []byte(v)
+	default:
+		return errors.New(fmt.Sprint("Failed to unmarshal JSONB value:", value))
+	}
+	return json.Unmarshal(bytes, d)
+}
@@ -0,0 +1,29 @@
+package entity
+
+import "time"
+
+// Schedule booking modes
+const (
+	BookingModeQueue = "queue" // Numbered queue, patients are seen in arrival order
+	BookingModeSlot  = "slot"  // Fixed-length appointment times, patients book a specific time
+)
+
+// ScheduleSlot is a fixed-length appointment time of a slot-mode schedule.
+// Generated when the schedule is created; Position doubles as the booking queue number.
+type ScheduleSlot struct {
+	ID         int64     `gorm:"primaryKey;autoIncrement" json:"id"`
+	ScheduleID int       `gorm:"not null;index" json:"schedule_id"`
+	Position   int       `gorm:"not null" json:"position"` // 1-based, in time order
+	StartTime  string    `gorm:"type:time;not null" json:"start_time"`
+	EndTime    string    `gorm:"type:time;not null" json:"end_time"`
+	CreatedAt  time.Time `gorm:"autoCreateTime" json:"created_at"`
+}
+
+func (ScheduleSlot) TableName() string {
+	return "schedule_slots"
+}
+
+// StartDateTime combines the schedule date and the slot start time in the given location
+func (s *ScheduleSlot) StartDateTime(scheduleDate time.Time, loc *time.Location) (time.Time, error) {
+	return combineDateAndClock(scheduleDate, s.StartTime, loc)
+}
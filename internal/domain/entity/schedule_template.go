@@ -0,0 +1,75 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ScheduleTemplate is a weekly recurring schedule of a doctor.
+// The generation worker expands active templates into DoctorSchedules for the coming days.
+type ScheduleTemplate struct {
+	ID           int       `gorm:"primaryKey;autoIncrement" json:"id"`
+	DoctorID     uuid.UUID `gorm:"type:uuid;not null;index" json:"doctor_id"`
+	Weekday      int       `gorm:"not null" json:"weekday"` // time.Weekday: 0 = Sunday
+	StartTime    string    `gorm:"type:time;not null" json:"start_time"`
+	EndTime      string    `gorm:"type:time;not null" json:"end_time"`
+	TotalQuota   int       `gorm:"not null;default:0" json:"total_quota"` // Queue mode only
+	BookingMode  string    `gorm:"type:varchar(10);not null;default:'queue'" json:"booking_mode"`
+	SlotMinutes  int       `gorm:"not null;default:0" json:"slot_minutes"` // Slot mode only
+	MinLeadHours *int      `json:"min_lead_hours,omitempty"`
+	IsActive     *bool     `gorm:"not null;default:true" json:"is_active"`
+	// Last date already expanded; later runs only generate after it, so schedules
+	// an admin deleted are not recreated. Reset when the template times change.
+	GeneratedUntil *time.Time `gorm:"type:date" json:"generated_until,omitempty"`
+	CreatedAt      time.Time  `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt      time.Time  `gorm:"autoUpdateTime" json:"updated_at"`
+
+	// Relationships
+	Doctor DoctorProfile `gorm:"foreignKey:DoctorID" json:"doctor,omitempty"`
+}
+
+func (ScheduleTemplate) TableName() string {
+	return "schedule_templates"
+}
+
+// IsEnabled reports whether the template generates schedules
+func (t *ScheduleTemplate) IsEnabled() bool {
+	return t.IsActive == nil || *t.IsActive
+}
+
+// IsGenerated reports whether date was already expanded by an earlier run
+func (t *ScheduleTemplate) IsGenerated(date time.Time) bool {
+	return t.GeneratedUntil != nil && !date.After(*t.GeneratedUntil)
+}
+
+// TimeRange returns the start and end times of day (on the zero date, UTC)
+func (t *ScheduleTemplate) TimeRange() (time.Time, time.Time, error) {
+	start, err := combineDateAndClock(time.Time{}, t.StartTime, time.UTC)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	end, err := combineDateAndClock(time.Time{}, t.EndTime, time.UTC)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	return start, end, nil
+}
+
+// ScheduleFor builds the (unsaved) schedule the template generates on date.
+// Slot-mode quotas are set when the slots are generated.
+func (t *ScheduleTemplate) ScheduleFor(date time.Time) DoctorSchedule {
+	templateID := t.ID
+	return DoctorSchedule{
+		DoctorID:       t.DoctorID,
+		ScheduleDate:   date,
+		StartTime:      t.StartTime,
+		EndTime:        t.EndTime,
+		TotalQuota:     t.TotalQuota,
+		BookingMode:    t.BookingMode,
+		SlotMinutes:    t.SlotMinutes,
+		MinLeadHours:   t.MinLeadHours,
+		TemplateID:     &templateID,
+		ApprovalStatus: ScheduleApprovalApproved,
+	}
+}
@@ -0,0 +1,211 @@
+package entity
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ScheduleVersion records one mutation of a doctor schedule: who made it, when, and the
+// schedule before and after. Versions are kept after the schedule is deleted.
+type ScheduleVersion struct {
+	ID            int64            `gorm:"primaryKey;autoIncrement" json:"id"`
+	ScheduleID    int              `gorm:"not null;index" json:"schedule_id"`
+	Action        string           `gorm:"type:varchar(100);not null" json:"action"` // Audit action, e.g. schedule.update
+	ChangedBy     *uuid.UUID       `gorm:"type:uuid" json:"changed_by,omitempty"`    // nil = system
+	OldValue      ScheduleSnapshot `gorm:"type:jsonb" json:"old_value"`              // Empty on create
+	NewValue      ScheduleSnapshot `gorm:"type:jsonb" json:"new_value"`              // Empty on delete
+	ChangedFields ScheduleFields   `gorm:"type:jsonb;not null;default:'[]'" json:"changed_fields"`
+	CreatedAt     time.Time        `gorm:"autoCreateTime" json:"created_at"`
+
+	// Relationships
+	User *User `gorm:"foreignKey:ChangedBy" json:"user,omitempty"`
+}
+
+func (ScheduleVersion) TableName() string {
+	return "schedule_versions"
+}
+
+// ScheduleSnapshot is the state of a schedule recorded in its history.
+// Times are normalized to HH:MM so unchanged values compare equal.
+type ScheduleSnapshot struct {
+	DoctorID          uuid.UUID      `json:"doctor_id"`
+	ScheduleDate      string         `json:"schedule_date"`
+	StartTime         string         `json:"start_time"`
+	EndTime           string         `json:"end_time"`
+	TotalQuota        int            `json:"total_quota"`
+	BookingMode       string         `json:"booking_mode"`
+	SlotMinutes       int            `json:"slot_minutes"`
+	IsOpen            bool           `json:"is_open"`
+	MinLeadHours      *int           `json:"min_lead_hours"`
+	Breaks            ScheduleBreaks `json:"breaks"`
+	ApprovalStatus    string         `json:"approval_status"`
+	DoctorCheckedInAt *time.Time     `json:"doctor_checked_in_at"`
+	AbsenceFlaggedAt  *time.Time     `json:"absence_flagged_at"`
+	HolidayFlaggedAt  *time.Time     `json:"holiday_flagged_at"`
+	LeaveFlaggedAt    *time.Time     `json:"leave_flagged_at"`
+}
+
+// ScheduleFields lists snapshot fields (JSON names), stored as a JSONB array
+type ScheduleFields []string
+
+// NewScheduleVersion records the change from oldSchedule to newSchedule.
+// oldSchedule is nil for a created schedule, newSchedule is nil for a deleted one.
+func NewScheduleVersion(scheduleID int, changedBy *uuid.UUID, action string, oldSchedule, newSchedule *DoctorSchedule) *ScheduleVersion {
+	version := &ScheduleVersion{
+		ScheduleID: scheduleID,
+		ChangedBy:  changedBy,
+		Action:     action,
+	}
+	if oldSchedule != nil {
+		version.OldValue = oldSchedule.Snapshot()
+	}
+	if newSchedule != nil {
+		version.NewValue = newSchedule.Snapshot()
+	}
+	version.ChangedFields = version.OldValue.diff(version.NewValue)
+	return version
+}
+
+// Snapshot returns the recorded state of the schedule
+func (s *DoctorSchedule) Snapshot() ScheduleSnapshot {
+	breaks := s.Breaks
+	if breaks == nil {
+		breaks = ScheduleBreaks{}
+	}
+	approvalStatus := s.ApprovalStatus
+	if approvalStatus == "" {
+		approvalStatus = ScheduleApprovalApproved
+	}
+
+	return ScheduleSnapshot{
+		DoctorID:          s.DoctorID,
+		ScheduleDate:      s.ScheduleDate.Format("2006-01-02"),
+		StartTime:         normalizeClock(s.StartTime),
+		EndTime:           normalizeClock(s.EndTime),
+		TotalQuota:        s.TotalQuota,
+		BookingMode:       s.BookingMode,
+		SlotMinutes:       s.SlotMinutes,
+		IsOpen:            s.IsBookingOpen(),
+		MinLeadHours:      s.MinLeadHours,
+		Breaks:            breaks,
+		ApprovalStatus:    approvalStatus,
+		DoctorCheckedInAt: s.DoctorCheckedInAt,
+		AbsenceFlaggedAt:  s.AbsenceFlaggedAt,
+		HolidayFlaggedAt:  s.HolidayFlaggedAt,
+		LeaveFlaggedAt:    s.LeaveFlaggedAt,
+	}
+}
+
+// IsEmpty reports whether no schedule state was recorded (old value of a create, new value of a delete)
+func (s ScheduleSnapshot) IsEmpty() bool {
+	return s.ScheduleDate == ""
+}
+
+// diff returns the fields whose values differ between the snapshots, in name order.
+// An empty snapshot counts as all fields null.
+func (s ScheduleSnapshot) diff(other ScheduleSnapshot) ScheduleFields {
+	before, after := s.fields(), other.fields()
+
+	changed := ScheduleFields{}
+	for name, value := range after {
+		if string(before[name]) != string(value) {
+			changed = append(changed, name)
+		}
+	}
+	for name := range before {
+		if _, ok := after[name]; !ok {
+			changed = append(changed, name)
+		}
+	}
+	sort.Strings(changed)
+	return changed
+}
+
+// fields returns the non-null JSON values of the snapshot by field name
+func (s ScheduleSnapshot) fields() map[string]json.RawMessage {
+	fields := map[string]json.RawMessage{}
+	if s.IsEmpty() {
+		return fields
+	}
+
+	raw, err := json.Marshal(s)
+	if err != nil {
+		return fields
+	}
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		return fields
+	}
+	for name, value := range fields {
+		if string(value) == "null" {
+			delete(fields, name)
+		}
+	}
+	return fields
+}
+
+// Value returns json value, implement driver.Valuer interface
+func (s ScheduleSnapshot) Value() (driver.Value, error) {
+	if s.IsEmpty() {
+		return nil, nil
+	}
+	return json.Marshal(s)
+}
+
+// Scan scan value into ScheduleSnapshot, implements sql.Scanner interface
+func (s *ScheduleSnapshot) Scan(value interface{}) error {
+	if value == nil {
+		*s = ScheduleSnapshot{}
+		return nil
+	}
+	var bytes []byte
+	switch v := value.(type) {
+	case []byte:
+		bytes = v
+	case string:
+		bytes = []byte(v)
+	default:
+		return errors.New(fmt.Sprint("Failed to unmarshal JSONB value:", value))
+	}
+	return json.Unmarshal(bytes, s)
+}
+
+// Value returns json value, implement driver.Valuer interface
+func (f ScheduleFields) Value() (driver.Value, error) {
+	if f == nil {
+		return "[]", nil
+	}
+	return json.Marshal(f)
+}
+
+// Scan scan value into ScheduleFields, implements sql.Scanner interface
+func (f *ScheduleFields) Scan(value interface{}) error {
+	if value == nil {
+		*f = nil
+		return nil
+	}
+	var bytes []byte
+	switch v := value.(type) {
+	case []byte:
+		bytes = v
+	case string:
+		bytes = []byte(v)
+	default:
+		return errors.New(fmt.Sprint("Failed to unmarshal JSONB value:", value))
+	}
+	return json.Unmarshal(bytes, f)
+}
+
+// normalizeClock formats a TIME column value (HH:MM:SS) or request time (HH:MM) as HH:MM
+func normalizeClock(clock string) string {
+	at, err := combineDateAndClock(time.Time{}, clock, time.UTC)
+	if err != nil {
+		return clock
+	}
+	return at.Format("15:04")
+}
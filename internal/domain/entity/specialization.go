@@ -0,0 +1,41 @@
+package entity
+
+import (
+	"strings"
+	"time"
+)
+
+// Specialization is a managed medical specialization doctors are assigned to.
+// The slug identifies it in public filters and URLs and does not change when renamed.
+type Specialization struct {
+	ID          int       `gorm:"primaryKey;autoIncrement" json:"id"`
+	Name        string    `gorm:"type:varchar(100);not null" json:"name"`
+	Slug        string    `gorm:"type:varchar(100);not null;uniqueIndex" json:"slug"`
+	Description string    `gorm:"type:text;not null;default:''" json:"description,omitempty"`
+	CreatedAt   time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt   time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+func (Specialization) TableName() string {
+	return "specializations"
+}
+
+// SpecializationSlug derives a slug from a name or a user supplied slug ("Ear, Nose & Throat"
+// becomes "ear-nose-throat"): lower case ASCII letters and digits joined by single dashes.
+// Migration 000044 backfills the slugs with the same rule.
+func SpecializationSlug(value string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(value) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			if dash && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			b.WriteRune(r)
+			dash = false
+			continue
+		}
+		dash = true
+	}
+	return b.String()
+}
@@ -0,0 +1,18 @@
+package entity
+
+import "time"
+
+// SpecializationDefault stores default schedule settings per doctor specialization.
+// Used to prefill schedule creation when quota or end time are omitted.
+type SpecializationDefault struct {
+	Specialization      string    `gorm:"type:varchar(100);primaryKey" json:"specialization"`
+	DefaultQuota        int       `gorm:"not null" json:"default_quota"`
+	ConsultationMinutes int       `gorm:"not null" json:"consultation_minutes"`
+	Fee                 int64     `gorm:"not null;default:0" json:"fee"`
+	CreatedAt           time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt           time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+func (SpecializationDefault) TableName() string {
+	return "specialization_defaults"
+}
@@ -0,0 +1,16 @@
+package entity
+
+import "time"
+
+// UsageRecord holds the metered usage of a tenant for one day (billing)
+type UsageRecord struct {
+	TenantID        string    `gorm:"type:varchar(64);primaryKey" json:"tenant_id"`
+	UsageDate       time.Time `gorm:"type:date;primaryKey" json:"usage_date"`
+	APICalls        int64     `gorm:"column:api_calls;not null;default:0" json:"api_calls"`
+	BookingsCreated int64     `gorm:"not null;default:0" json:"bookings_created"`
+	UpdatedAt       time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+func (UsageRecord) TableName() string {
+	return "usage_records"
+}
@@ -1,6 +1,7 @@
 package entity
 
 import (
+	"fmt"
 	"time"
 
 	"github.com/google/uuid"
@@ -17,6 +18,35 @@ type User struct {
 	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
 	UpdatedAt time.Time `gorm:"autoUpdateTime" json:"updated_at"`
 
+	// Pre-registered patients (partner roster) are unclaimed until they verify their phone
+	IsClaimed *bool      `gorm:"not null;default:true" json:"is_claimed"`
+	ClaimedAt *time.Time `json:"claimed_at,omitempty"`
+
+	// TOTP two-factor authentication: the secret is set on enrollment, 2FA is on once
+	// the first code was verified (TwoFactorEnabledAt)
+	TwoFactorSecret      string           `gorm:"type:text" json:"-"`
+	TwoFactorEnabledAt   *time.Time       `json:"two_factor_enabled_at,omitempty"`
+	TwoFactorBackupCodes BackupCodeHashes `gorm:"type:jsonb;not null;default:'[]'" json:"-"`
+
+	// Set when the patient erased the account: PII is anonymized, bookings are kept
+	ErasedAt *time.Time `json:"erased_at,omitempty"`
+
+	// Google account linked for SSO (OpenID subject), nil = not linked
+	GoogleSubject *string `gorm:"type:varchar(255);uniqueIndex" json:"-"`
+
+	// Login lockout after too many wrong passwords. LockoutCount escalates the duration
+	// of the next lockout until a successful login or an admin unlock.
+	LockedUntil  *time.Time `json:"locked_until,omitempty"`
+	LockoutCount int        `gorm:"not null;default:0" json:"lockout_count"`
+	LastLockedAt *time.Time `json:"last_locked_at,omitempty"`
+
+	// Last successful login, the attempts are kept in login_history
+	LastLoginAt *time.Time `json:"last_login_at,omitempty"`
+
+	// Set by admins and on admin-created accounts: only a password change is allowed until done
+	MustChangePassword bool       `gorm:"not null;default:false" json:"must_change_password"`
+	PasswordChangedAt  *time.Time `json:"password_changed_at,omitempty"`
+
 	// Relationships
 	Role           Role            `gorm:"foreignKey:RoleID" json:"role,omitempty"`
 	DoctorProfile  *DoctorProfile  `gorm:"foreignKey:UserID" json:"doctor_profile,omitempty"`
@@ -26,3 +56,37 @@ type User struct {
 func (User) TableName() string {
 	return "users"
 }
+
+// IsPendingClaim reports whether the account was pre-registered and not claimed yet
+func (u *User) IsPendingClaim() bool {
+	return u.IsClaimed != nil && !*u.IsClaimed
+}
+
+// IsTwoFactorEnabled reports whether logins need a TOTP or backup code
+func (u *User) IsTwoFactorEnabled() bool {
+	return u.TwoFactorEnabledAt != nil
+}
+
+// IsErased reports whether the account was erased on request of the patient
+func (u *User) IsErased() bool {
+	return u.ErasedAt != nil
+}
+
+// IsLocked reports whether logins are refused at the given time
+func (u *User) IsLocked(at time.Time) bool {
+	return u.LockedUntil != nil && u.LockedUntil.After(at)
+}
+
+// ErasedFullName replaces the name of an erased account
+const ErasedFullName = "Deleted Patient"
+
+// ErasedEmail is the placeholder email of an erased account, unique per user
+func ErasedEmail(userID uuid.UUID) string {
+	return fmt.Sprintf("erased-%s@erased.invalid", userID)
+}
+
+// UnclaimedEmail is the placeholder email of a pre-registered patient, replaced on claim.
+// The .invalid TLD is reserved, so it can never collide with a real address.
+func UnclaimedEmail(nik string) string {
+	return fmt.Sprintf("%s@unclaimed.invalid", nik)
+}
@@ -0,0 +1,8 @@
+package entity
+
+// UserFilter is a domain-level filter for querying users (admin user list).
+type UserFilter struct {
+	RoleID int    // 0 = all roles
+	Locked *bool  // nil = all, true = currently locked, false = not locked
+	Search string // Filter by email or full name (ILIKE)
+}
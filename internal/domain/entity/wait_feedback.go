@@ -0,0 +1,30 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// WaitFeedback stores the patient's reported waiting time for a booking
+type WaitFeedback struct {
+	BookingID   uuid.UUID `gorm:"type:uuid;primaryKey" json:"booking_id"`
+	PatientID   uuid.UUID `gorm:"type:uuid;not null;index" json:"patient_id"`
+	DoctorID    uuid.UUID `gorm:"type:uuid;not null;index" json:"doctor_id"`
+	ScheduleID  int       `gorm:"not null" json:"schedule_id"`
+	WaitMinutes int       `gorm:"not null" json:"wait_minutes"`
+	Comment     string    `gorm:"type:text" json:"comment,omitempty"`
+	CreatedAt   time.Time `gorm:"autoCreateTime" json:"created_at"`
+}
+
+func (WaitFeedback) TableName() string {
+	return "wait_feedbacks"
+}
+
+// DoctorWaitStat is the aggregated waiting time of a doctor
+type DoctorWaitStat struct {
+	DoctorID           uuid.UUID
+	DoctorName         string
+	AverageWaitMinutes float64
+	FeedbackCount      int64
+}
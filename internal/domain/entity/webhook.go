@@ -0,0 +1,137 @@
+package entity
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// WebhookStatus is whether a subscription receives deliveries
+type WebhookStatus string
+
+const (
+	WebhookStatusActive WebhookStatus = "active"
+	WebhookStatusPaused WebhookStatus = "paused" // By an admin or after repeated failures, deliveries wait
+)
+
+// WebhookDeliveryStatus represents the state of one webhook delivery
+type WebhookDeliveryStatus string
+
+const (
+	WebhookDeliveryPending   WebhookDeliveryStatus = "pending"   // Waiting for (re)delivery
+	WebhookDeliverySucceeded WebhookDeliveryStatus = "succeeded" // The endpoint answered 2xx
+	WebhookDeliveryFailed    WebhookDeliveryStatus = "failed"    // Gave up after max attempts
+)
+
+// WebhookEventTest is the event type of deliveries test-fired by admins
+const WebhookEventTest = "webhook.test"
+
+// WebhookEventTypes are the outbox events external systems can subscribe to
+var WebhookEventTypes = WebhookEvents{
+	OutboxEventBookingCreated,
+	OutboxEventBookingCancelled,
+	OutboxEventBookingRescheduled,
+	OutboxEventBookingCalled,
+	OutboxEventBookingConfirmed,
+	OutboxEventScheduleUpdated,
+	OutboxEventScheduleDoctorLeave,
+}
+
+// IsWebhookEventType reports whether the event type can be subscribed to
+func IsWebhookEventType(eventType string) bool {
+	return WebhookEventTypes.Contains(eventType)
+}
+
+// WebhookEvents are the event types a subscription receives, stored as a JSONB array
+type WebhookEvents []string
+
+// Contains reports whether the event type is subscribed
+func (e WebhookEvents) Contains(eventType string) bool {
+	for _, t := range e {
+		if t == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// Value returns json value, implement driver.Valuer interface
+func (e WebhookEvents) Value() (driver.Value, error) {
+	if e == nil {
+		return "[]", nil
+	}
+	return json.Marshal(e)
+}
+
+// Scan scan value into WebhookEvents, implements sql.Scanner interface
+func (e *WebhookEvents) Scan(value interface{}) error {
+	if value == nil {
+		*e = nil
+		return nil
+	}
+	var bytes []byte
+	switch v := value.(type) {
+	case []byte:
+		bytes = v
+	case string:
+		bytes = []byte(v)
+	default:
+		return errors.New(fmt.Sprint("Failed to unmarshal JSONB value:", value))
+	}
+	return json.Unmarshal(bytes, e)
+}
+
+// WebhookSubscription is an external endpoint receiving the subscribed outbox events as
+// signed HTTP POSTs. Secret signs the deliveries (HMAC-SHA256), it is never returned
+// after creation.
+type WebhookSubscription struct {
+	ID                  int           `gorm:"primaryKey;autoIncrement" json:"id"`
+	URL                 string        `gorm:"type:varchar(500);not null" json:"url"`
+	Secret              string        `gorm:"type:varchar(100);not null" json:"-"`
+	EventTypes          WebhookEvents `gorm:"type:jsonb;not null" json:"event_types"`
+	Description         string        `gorm:"type:varchar(255)" json:"description,omitempty"`
+	Status              WebhookStatus `gorm:"type:varchar(20);not null;default:'active'" json:"status"`
+	ConsecutiveFailures int           `gorm:"not null;default:0" json:"consecutive_failures"` // Failed attempts since the last success
+	PausedAt            *time.Time    `json:"paused_at,omitempty"`
+	PauseReason         string        `gorm:"type:varchar(255)" json:"pause_reason,omitempty"`
+	CreatedBy           *uuid.UUID    `gorm:"type:uuid" json:"created_by,omitempty"`
+	CreatedAt           time.Time     `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt           time.Time     `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+func (WebhookSubscription) TableName() string {
+	return "webhook_subscriptions"
+}
+
+// IsActive reports whether the subscription receives deliveries
+func (s *WebhookSubscription) IsActive() bool {
+	return s.Status == WebhookStatusActive
+}
+
+// WebhookDelivery is one event sent, or to be sent, to a subscription. Payload is the
+// exact request body, so retries send the same bytes and signature input.
+type WebhookDelivery struct {
+	ID             uuid.UUID             `gorm:"type:uuid;primaryKey" json:"id"`
+	SubscriptionID int                   `gorm:"not null;index" json:"subscription_id"`
+	EventID        *int64                `json:"event_id,omitempty"` // Outbox event, nil for test deliveries and events published after commit
+	EventType      string                `gorm:"type:varchar(100);not null" json:"event_type"`
+	Payload        JSON                  `gorm:"type:jsonb;not null" json:"payload"`
+	Status         WebhookDeliveryStatus `gorm:"type:varchar(20);not null;default:'pending'" json:"status"`
+	Attempts       int                   `gorm:"not null;default:0" json:"attempts"`
+	NextAttemptAt  time.Time             `gorm:"not null" json:"next_attempt_at"`
+	ResponseStatus *int                  `json:"response_status,omitempty"`                // HTTP status of the last attempt
+	ResponseBody   string                `gorm:"type:text" json:"response_body,omitempty"` // Start of the last response body
+	LastError      string                `gorm:"type:text" json:"last_error,omitempty"`
+	DeliveredAt    *time.Time            `json:"delivered_at,omitempty"`
+	FailedAt       *time.Time            `json:"failed_at,omitempty"`
+	CreatedAt      time.Time             `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt      time.Time             `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+func (WebhookDelivery) TableName() string {
+	return "webhook_deliveries"
+}
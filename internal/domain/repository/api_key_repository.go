@@ -0,0 +1,21 @@
+package repository
+
+import (
+	"time"
+
+	"go-template-clean-architecture/internal/domain/entity"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type APIKeyRepository interface {
+	Create(db *gorm.DB, apiKey *entity.APIKey) error
+	FindByID(db *gorm.DB, id uuid.UUID) (*entity.APIKey, error)
+	FindByHash(db *gorm.DB, keyHash string) (*entity.APIKey, error)
+	FindAll(db *gorm.DB) ([]entity.APIKey, error)
+	Update(db *gorm.DB, apiKey *entity.APIKey) error
+	ReplacePermissions(db *gorm.DB, apiKey *entity.APIKey, permissions []entity.Permission) error
+	Revoke(db *gorm.DB, id uuid.UUID, revokedAt time.Time) (int64, error)
+	UpdateLastUsed(db *gorm.DB, id uuid.UUID, usedAt time.Time) error
+}
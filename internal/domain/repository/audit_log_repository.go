@@ -3,11 +3,14 @@ package repository
 import (
 	"go-template-clean-architecture/internal/domain/entity"
 
+	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
 
 type AuditLogRepository interface {
 	Create(db *gorm.DB, log *entity.AuditLog) error
+	CreateIfAbsent(db *gorm.DB, log *entity.AuditLog) (bool, error)
 	FindAll(db *gorm.DB) ([]entity.AuditLog, error)
 	FindByID(db *gorm.DB, id int64) (*entity.AuditLog, error)
+	FindByUserOrEntities(db *gorm.DB, userID uuid.UUID, entityName string, entityIDs []string) ([]entity.AuditLog, error)
 }
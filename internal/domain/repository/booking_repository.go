@@ -1,6 +1,8 @@
 package repository
 
 import (
+	"time"
+
 	"go-template-clean-architecture/internal/domain/entity"
 
 	"github.com/google/uuid"
@@ -10,7 +12,32 @@ import (
 type BookingRepository interface {
 	Create(db *gorm.DB, booking *entity.Booking) error
 	FindByID(db *gorm.DB, id uuid.UUID) (*entity.Booking, error)
+	FindByBookingCode(db *gorm.DB, bookingCode string) (*entity.Booking, error)
 	FindByPatientID(db *gorm.DB, patientID uuid.UUID) ([]entity.Booking, error)
-	CancelBooking(db *gorm.DB, id uuid.UUID) (int64, error)
+	CountUpcomingByPatient(db *gorm.DB, patientID uuid.UUID, from time.Time) (int64, error)
+	ExistsByPatientAndDoctor(db *gorm.DB, patientID, doctorID uuid.UUID) (bool, error)
+	FindHistoryByPatientID(db *gorm.DB, patientID uuid.UUID, page, limit int) ([]entity.Booking, int64, error)
+	CancelBooking(db *gorm.DB, id uuid.UUID, version int) (int64, error)
+	ConfirmPayment(db *gorm.DB, id uuid.UUID) (int64, error)
 	FindByPatientAndSchedule(db *gorm.DB, patientID uuid.UUID, scheduleID int) (*entity.Booking, error)
+	FindActiveByScheduleID(db *gorm.DB, scheduleID int) ([]entity.Booking, error)
+	Reassign(db *gorm.DB, id uuid.UUID, version int, scheduleID int, queueNumber int) (int64, error)
+	FindNextUncalled(db *gorm.DB, scheduleID int) (*entity.Booking, error)
+	MarkCalled(db *gorm.DB, id uuid.UUID, version int, at time.Time) (int64, error)
+	FindLastCalledAt(db *gorm.DB, scheduleID int) (*time.Time, error)
+	FindLastCalled(db *gorm.DB, scheduleID int) (*entity.Booking, error)
+	CountBySchedule(db *gorm.DB, scheduleID int) (*entity.ScheduleBookingCounts, error)
+	CountWaitingAhead(db *gorm.DB, scheduleID int, queueNumber int) (int64, error)
+	CountByScheduleID(db *gorm.DB, scheduleID int) (int64, error)
+	Delete(db *gorm.DB, id uuid.UUID) (int64, error)
+	FindDeleted(db *gorm.DB, page, limit int) ([]entity.Booking, int64, error)
+	FindDeletedByID(db *gorm.DB, id uuid.UUID) (*entity.Booking, error)
+	Restore(db *gorm.DB, id uuid.UUID) (int64, error)
+	CreateBatch(db *gorm.DB, bookings []entity.Booking) error
+	FindQueueNumbersByScheduleID(db *gorm.DB, scheduleID int) ([]int, error)
+	FindExistingBookingCodes(db *gorm.DB, bookingCodes []string) ([]string, error)
+	FindReservedSlotIDs(db *gorm.DB, scheduleID int) ([]int64, error)
+	DeleteByScheduleID(db *gorm.DB, scheduleID int) (int64, error)
+	FindActiveByScheduleDate(db *gorm.DB, scheduleDate time.Time) ([]entity.Booking, error)
+	FindActiveByScheduleIDs(db *gorm.DB, scheduleIDs []int) ([]entity.Booking, error)
 }
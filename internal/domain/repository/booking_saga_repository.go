@@ -0,0 +1,18 @@
+package repository
+
+import (
+	"time"
+
+	"go-template-clean-architecture/internal/domain/entity"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type BookingSagaRepository interface {
+	Create(db *gorm.DB, saga *entity.BookingSaga) error
+	Save(db *gorm.DB, saga *entity.BookingSaga) error
+	FindByID(db *gorm.DB, id uuid.UUID) (*entity.BookingSaga, error)
+	FindAll(db *gorm.DB, status string, page, limit int) ([]entity.BookingSaga, int64, error)
+	ClaimStale(db *gorm.DB, updatedBefore time.Time, limit int) ([]entity.BookingSaga, error)
+}
@@ -0,0 +1,25 @@
+package repository
+
+import (
+	"time"
+
+	"go-template-clean-architecture/internal/domain/entity"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type BroadcastRepository interface {
+	Create(db *gorm.DB, broadcast *entity.Broadcast) error
+	FindByID(db *gorm.DB, id uuid.UUID) (*entity.Broadcast, error)
+	FindAll(db *gorm.DB) ([]entity.Broadcast, error)
+	FindSegmentPatientIDs(db *gorm.DB, segment *entity.BroadcastSegment) ([]uuid.UUID, error)
+
+	CreateRecipients(db *gorm.DB, recipients []entity.BroadcastRecipient) error
+	FindRecipientByID(db *gorm.DB, id int64) (*entity.BroadcastRecipient, error)
+	FindFailedRecipients(db *gorm.DB, broadcastID uuid.UUID) ([]entity.BroadcastRecipient, error)
+	FindLastPendingScheduledAt(db *gorm.DB) (*time.Time, error)
+	CountRecipientsByStatus(db *gorm.DB, broadcastIDs []uuid.UUID) ([]entity.BroadcastStatusCount, error)
+	MarkRecipientSent(db *gorm.DB, id int64, at time.Time) error
+	MarkRecipientFailed(db *gorm.DB, id int64, lastError string) error
+}
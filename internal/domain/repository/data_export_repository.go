@@ -0,0 +1,15 @@
+package repository
+
+import (
+	"go-template-clean-architecture/internal/domain/entity"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type DataExportRepository interface {
+	Create(db *gorm.DB, export *entity.DataExport) error
+	Update(db *gorm.DB, export *entity.DataExport) error
+	FindByID(db *gorm.DB, id uuid.UUID) (*entity.DataExport, error)
+	FindLatestByUserID(db *gorm.DB, userID uuid.UUID) (*entity.DataExport, error)
+}
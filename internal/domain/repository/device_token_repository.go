@@ -0,0 +1,16 @@
+package repository
+
+import (
+	"go-template-clean-architecture/internal/domain/entity"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type DeviceTokenRepository interface {
+	Upsert(db *gorm.DB, token *entity.DeviceToken) error
+	FindByUserID(db *gorm.DB, userID uuid.UUID) ([]entity.DeviceToken, error)
+	DeleteByUserAndID(db *gorm.DB, userID uuid.UUID, id int) (int64, error)
+	DeleteByToken(db *gorm.DB, token string) (int64, error)
+	DeleteByUserID(db *gorm.DB, userID uuid.UUID) (int64, error)
+}
@@ -0,0 +1,19 @@
+package repository
+
+import (
+	"time"
+
+	"go-template-clean-architecture/internal/domain/entity"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type DoctorLeaveRepository interface {
+	Create(db *gorm.DB, leave *entity.DoctorLeave) error
+	FindByID(db *gorm.DB, id int) (*entity.DoctorLeave, error)
+	FindByDoctorID(db *gorm.DB, doctorID uuid.UUID, from, to string) ([]entity.DoctorLeave, error)
+	FindOverlapping(db *gorm.DB, doctorID uuid.UUID, from, to time.Time) ([]entity.DoctorLeave, error)
+	FindByDateRange(db *gorm.DB, from, to time.Time) ([]entity.DoctorLeave, error)
+	Delete(db *gorm.DB, id int) (int64, error)
+}
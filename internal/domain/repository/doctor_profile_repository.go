@@ -10,7 +10,8 @@ import (
 type DoctorProfileRepository interface {
 	Create(db *gorm.DB, profile *entity.DoctorProfile) error
 	FindByUserID(db *gorm.DB, userID uuid.UUID) (*entity.DoctorProfile, error)
-	FindAll(db *gorm.DB) ([]entity.DoctorProfile, error)
+	FindAll(db *gorm.DB, filter *entity.DoctorFilter, page, limit int) ([]entity.DoctorProfile, int64, error)
 	Update(db *gorm.DB, profile *entity.DoctorProfile) error
+	UpdatePhoto(db *gorm.DB, userID uuid.UUID, photoKey, photoURL string) error
 	Delete(db *gorm.DB, userID uuid.UUID) error
 }
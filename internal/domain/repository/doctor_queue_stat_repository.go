@@ -0,0 +1,13 @@
+package repository
+
+import (
+	"go-template-clean-architecture/internal/domain/entity"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type DoctorQueueStatRepository interface {
+	RecordInterval(db *gorm.DB, doctorID uuid.UUID, minutes float64) error
+	FindByDoctorID(db *gorm.DB, doctorID uuid.UUID) (*entity.DoctorQueueStat, error)
+}
@@ -0,0 +1,14 @@
+package repository
+
+import (
+	"go-template-clean-architecture/internal/domain/entity"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type DoctorReviewRepository interface {
+	Create(db *gorm.DB, review *entity.DoctorReview) error
+	RatingByDoctorIDs(db *gorm.DB, doctorIDs []uuid.UUID) ([]entity.DoctorRatingStat, error)
+	FindByDoctorID(db *gorm.DB, doctorID uuid.UUID, page, limit int) ([]entity.DoctorReviewItem, int64, error)
+}
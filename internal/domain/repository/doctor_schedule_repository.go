@@ -1,6 +1,8 @@
 package repository
 
 import (
+	"time"
+
 	"go-template-clean-architecture/internal/domain/entity"
 
 	"github.com/google/uuid"
@@ -10,9 +12,29 @@ import (
 type DoctorScheduleRepository interface {
 	Create(db *gorm.DB, schedule *entity.DoctorSchedule) error
 	FindByID(db *gorm.DB, id int) (*entity.DoctorSchedule, error)
-	FindByDoctorID(db *gorm.DB, doctorID uuid.UUID) ([]entity.DoctorSchedule, error)
-	FindAll(db *gorm.DB) ([]entity.DoctorSchedule, error)
-	FindAllWithActiveDoctor(db *gorm.DB, filter *entity.ScheduleFilter) ([]entity.DoctorSchedule, error)
+	FindByDoctorID(db *gorm.DB, doctorID uuid.UUID, page, limit int) ([]entity.DoctorSchedule, int64, error)
+	FindAll(db *gorm.DB, filter *entity.ScheduleFilter, page, limit int) ([]entity.DoctorSchedule, int64, error)
+	FindAllWithActiveDoctor(db *gorm.DB, filter *entity.ScheduleFilter, page, limit int) ([]entity.DoctorSchedule, int64, error)
+	FindByDoctorAndDateRange(db *gorm.DB, doctorID uuid.UUID, from, to time.Time) ([]entity.DoctorSchedule, error)
+	FindApprovedByActiveDoctorAndDateRange(db *gorm.DB, doctorID uuid.UUID, from, to time.Time) ([]entity.DoctorSchedule, error)
+	FindByDateRange(db *gorm.DB, from, to time.Time) ([]entity.DoctorSchedule, error)
+	FindAgendaByDate(db *gorm.DB, scheduleDate time.Time) ([]entity.DoctorSchedule, error)
+	LockByDoctorFromDate(db *gorm.DB, doctorID uuid.UUID, from time.Time, to *time.Time) ([]entity.DoctorSchedule, error)
 	Update(db *gorm.DB, schedule *entity.DoctorSchedule) error
 	Delete(db *gorm.DB, id int) (int64, error)
+	CheckIn(db *gorm.DB, id int, doctorID uuid.UUID, at time.Time) (int64, error)
+	SetOpen(db *gorm.DB, id int, isOpen bool) error
+	FindPendingCheckIn(db *gorm.DB, scheduleDate string, startedBefore string) ([]entity.DoctorSchedule, error)
+	MarkAbsenceFlagged(db *gorm.DB, id int, at time.Time) (int64, error)
+	FindPossiblyAbsent(db *gorm.DB) ([]entity.DoctorSchedule, error)
+	MarkHolidayFlagged(db *gorm.DB, scheduleDate time.Time, at time.Time) (int64, error)
+	ClearHolidayFlag(db *gorm.DB, scheduleDate time.Time) (int64, error)
+	FindHolidayFlagged(db *gorm.DB) ([]entity.DoctorSchedule, error)
+	MarkLeaveFlagged(db *gorm.DB, ids []int, at time.Time) (int64, error)
+	ClearLeaveFlag(db *gorm.DB, ids []int) (int64, error)
+	FindLeaveFlagged(db *gorm.DB) ([]entity.DoctorSchedule, error)
+	FindPendingApproval(db *gorm.DB, page, limit int) ([]entity.DoctorSchedule, int64, error)
+	Review(db *gorm.DB, id int, status string, reviewedBy uuid.UUID, reason string, at time.Time) (int64, error)
+	UtilizationStats(db *gorm.DB, filter *entity.ScheduleFilter, endedBefore string) ([]entity.ScheduleUtilizationStat, error)
+	DoctorPeriodStats(db *gorm.DB, doctorID uuid.UUID, from, to, period, endedBefore string, maxCallInterval time.Duration) ([]entity.DoctorPeriodStat, error)
 }
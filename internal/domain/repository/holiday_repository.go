@@ -0,0 +1,18 @@
+package repository
+
+import (
+	"time"
+
+	"go-template-clean-architecture/internal/domain/entity"
+
+	"gorm.io/gorm"
+)
+
+type HolidayRepository interface {
+	Create(db *gorm.DB, holiday *entity.Holiday) error
+	FindByID(db *gorm.DB, id int) (*entity.Holiday, error)
+	FindByDate(db *gorm.DB, date time.Time) (*entity.Holiday, error)
+	FindAll(db *gorm.DB, from, to string) ([]entity.Holiday, error)
+	Update(db *gorm.DB, holiday *entity.Holiday) error
+	Delete(db *gorm.DB, id int) (int64, error)
+}
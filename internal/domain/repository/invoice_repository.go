@@ -0,0 +1,18 @@
+package repository
+
+import (
+	"go-template-clean-architecture/internal/domain/entity"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type InvoiceRepository interface {
+	NextNumber(db *gorm.DB, year int) (int64, error)
+	Create(db *gorm.DB, invoice *entity.Invoice) error
+	FindByID(db *gorm.DB, id uuid.UUID) (*entity.Invoice, error)
+	FindByBookingID(db *gorm.DB, bookingID uuid.UUID) (*entity.Invoice, error)
+	MarkRefunded(db *gorm.DB, paymentID uuid.UUID) (int64, error)
+	FindAll(db *gorm.DB, filter *entity.InvoiceFilter, page, limit int) ([]entity.Invoice, int64, error)
+	Totals(db *gorm.DB, filter *entity.InvoiceFilter) ([]entity.InvoiceTotal, error)
+}
@@ -0,0 +1,14 @@
+package repository
+
+import (
+	"go-template-clean-architecture/internal/domain/entity"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type LoginHistoryRepository interface {
+	Create(db *gorm.DB, entry *entity.LoginHistory) error
+	FindByUserID(db *gorm.DB, userID uuid.UUID, page, limit int) ([]entity.LoginHistory, int64, error)
+	CountSuccessful(db *gorm.DB, userID uuid.UUID, userAgent string) (int64, int64, error)
+}
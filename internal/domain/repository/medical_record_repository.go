@@ -0,0 +1,16 @@
+package repository
+
+import (
+	"go-template-clean-architecture/internal/domain/entity"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type MedicalRecordRepository interface {
+	Create(db *gorm.DB, record *entity.MedicalRecord) error
+	Update(db *gorm.DB, record *entity.MedicalRecord, version int) (int64, error)
+	FindByBookingID(db *gorm.DB, bookingID uuid.UUID) (*entity.MedicalRecord, error)
+	FindByPatientID(db *gorm.DB, patientID uuid.UUID, page, limit int) ([]entity.MedicalRecord, int64, error)
+	FindByBookingIDs(db *gorm.DB, bookingIDs []uuid.UUID) ([]entity.MedicalRecord, error)
+}
@@ -0,0 +1,14 @@
+package repository
+
+import (
+	"go-template-clean-architecture/internal/domain/entity"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type NotificationPreferenceRepository interface {
+	FindByUserID(db *gorm.DB, userID uuid.UUID) (*entity.NotificationPreference, error)
+	FindByUserIDs(db *gorm.DB, userIDs []uuid.UUID) ([]entity.NotificationPreference, error)
+	Upsert(db *gorm.DB, preference *entity.NotificationPreference) error
+}
@@ -0,0 +1,35 @@
+package repository
+
+import (
+	"time"
+
+	"go-template-clean-architecture/internal/domain/entity"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type NotificationRepository interface {
+	CreateIfAbsent(db *gorm.DB, notification *entity.Notification) (bool, error)
+	ClaimDue(db *gorm.DB, now time.Time, limit int) ([]entity.Notification, error)
+	Lease(db *gorm.DB, ids []uuid.UUID, until time.Time) error
+	MarkSent(db *gorm.DB, id uuid.UUID, attempts int, provider string, providerMessageID string, at time.Time) error
+	MarkRetry(db *gorm.DB, id uuid.UUID, attempts int, nextAttemptAt time.Time, lastError string) error
+	MarkDeadLetter(db *gorm.DB, id uuid.UUID, attempts int, lastError string, at time.Time) error
+	FindByID(db *gorm.DB, id uuid.UUID) (*entity.Notification, error)
+	FindByProviderMessageID(db *gorm.DB, provider string, providerMessageID string) (*entity.Notification, error)
+	MarkDeliveryReport(db *gorm.DB, id uuid.UUID, status entity.NotificationStatus, lastError string, at time.Time) (int64, error)
+	FindByBookingID(db *gorm.DB, bookingID uuid.UUID) ([]entity.Notification, error)
+	FindInApp(db *gorm.DB, patientID uuid.UUID, unreadOnly bool, page, limit int) ([]entity.Notification, int64, error)
+	CountUnreadInApp(db *gorm.DB, patientID uuid.UUID) (int64, error)
+	MarkInAppRead(db *gorm.DB, patientID uuid.UUID, id uuid.UUID, at time.Time) (int64, error)
+	MarkAllInAppRead(db *gorm.DB, patientID uuid.UUID, at time.Time) (int64, error)
+	DeleteInApp(db *gorm.DB, patientID uuid.UUID, id uuid.UUID) (int64, error)
+	FindDeadLetters(db *gorm.DB, channel string, eventType string, page, limit int) ([]entity.Notification, int64, error)
+	Redrive(db *gorm.DB, id uuid.UUID, channel string, recipient string, at time.Time) (int64, error)
+	CreateAttempt(db *gorm.DB, attempt *entity.NotificationAttempt) error
+	FindAttempts(db *gorm.DB, notificationID uuid.UUID) ([]entity.NotificationAttempt, error)
+	CreateDeliveryReport(db *gorm.DB, report *entity.NotificationDeliveryReport) error
+	FindDeliveryReports(db *gorm.DB, notificationID uuid.UUID) ([]entity.NotificationDeliveryReport, error)
+	FindByPatientID(db *gorm.DB, patientID uuid.UUID, filter *entity.NotificationFilter, page, limit int) ([]entity.Notification, int64, error)
+}
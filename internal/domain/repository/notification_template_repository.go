@@ -0,0 +1,16 @@
+package repository
+
+import (
+	"go-template-clean-architecture/internal/domain/entity"
+
+	"gorm.io/gorm"
+)
+
+type NotificationTemplateRepository interface {
+	Create(db *gorm.DB, template *entity.NotificationTemplate) error
+	FindByID(db *gorm.DB, id int) (*entity.NotificationTemplate, error)
+	FindAll(db *gorm.DB, eventType string) ([]entity.NotificationTemplate, error)
+	FindByEventType(db *gorm.DB, eventType string) ([]entity.NotificationTemplate, error)
+	Update(db *gorm.DB, template *entity.NotificationTemplate) error
+	Delete(db *gorm.DB, id int) (int64, error)
+}
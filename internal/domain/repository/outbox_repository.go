@@ -0,0 +1,19 @@
+package repository
+
+import (
+	"time"
+
+	"go-template-clean-architecture/internal/domain/entity"
+
+	"gorm.io/gorm"
+)
+
+type OutboxRepository interface {
+	Create(db *gorm.DB, event *entity.OutboxEvent) error
+	ClaimDue(db *gorm.DB, now time.Time, limit int) ([]entity.OutboxEvent, error)
+	Lease(db *gorm.DB, ids []int64, until time.Time) error
+	MarkPublished(db *gorm.DB, id int64, at time.Time) error
+	MarkRetry(db *gorm.DB, id int64, attempts int, nextAttemptAt time.Time, lastError string) error
+	MarkFailed(db *gorm.DB, id int64, attempts int, lastError string) error
+	FindByAggregates(db *gorm.DB, aggregateType string, aggregateIDs []string) ([]entity.OutboxEvent, error)
+}
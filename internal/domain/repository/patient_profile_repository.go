@@ -12,7 +12,12 @@ import (
 type PatientProfileRepository interface {
 	Create(ctx context.Context, db *gorm.DB, profile *entity.PatientProfile) error
 	FindByUserID(ctx context.Context, db *gorm.DB, userID uuid.UUID) (*entity.PatientProfile, error)
+	FindByNIK(ctx context.Context, db *gorm.DB, nik string) (*entity.PatientProfile, error)
+	FindByPhoneNumbers(ctx context.Context, db *gorm.DB, phoneNumbers []string) ([]entity.PatientProfile, error)
+	FindExistingNIKs(ctx context.Context, db *gorm.DB, niks []string) ([]string, error)
 	FindAll(ctx context.Context, db *gorm.DB) ([]entity.PatientProfile, error)
+	Search(ctx context.Context, db *gorm.DB, filter *entity.PatientFilter, page, limit int) ([]entity.PatientProfile, int64, error)
 	Update(ctx context.Context, db *gorm.DB, profile *entity.PatientProfile) error
 	Delete(ctx context.Context, db *gorm.DB, userID uuid.UUID) error
+	Anonymize(ctx context.Context, db *gorm.DB, profile *entity.PatientProfile) error
 }
@@ -0,0 +1,24 @@
+package repository
+
+import (
+	"time"
+
+	"go-template-clean-architecture/internal/domain/entity"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type PaymentRepository interface {
+	Create(db *gorm.DB, payment *entity.Payment) error
+	UpdateCharge(db *gorm.DB, id uuid.UUID, reference string, paymentURL string) error
+	FindByID(db *gorm.DB, id uuid.UUID) (*entity.Payment, error)
+	FindLatestByBookingID(db *gorm.DB, bookingID uuid.UUID) (*entity.Payment, error)
+	FindLatestByBookingIDs(db *gorm.DB, bookingIDs []uuid.UUID) ([]entity.Payment, error)
+	MarkPaid(db *gorm.DB, id uuid.UUID, reference string, at time.Time) (int64, error)
+	Close(db *gorm.DB, id uuid.UUID, status entity.PaymentStatus, reason string) (int64, error)
+	ClosePendingByBookingID(db *gorm.DB, bookingID uuid.UUID, status entity.PaymentStatus, reason string) (int64, error)
+	RequestRefund(db *gorm.DB, id uuid.UUID, amount int64, reason string, at time.Time) (int64, error)
+	SetRefundReference(db *gorm.DB, id uuid.UUID, reference string) error
+	CompleteRefund(db *gorm.DB, id uuid.UUID, status entity.PaymentRefundStatus, reference string, failureReason string, at time.Time) (int64, error)
+}
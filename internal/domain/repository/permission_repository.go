@@ -0,0 +1,13 @@
+package repository
+
+import (
+	"go-template-clean-architecture/internal/domain/entity"
+
+	"gorm.io/gorm"
+)
+
+type PermissionRepository interface {
+	FindAll(db *gorm.DB) ([]entity.Permission, error)
+	FindByCodes(db *gorm.DB, codes []string) ([]entity.Permission, error)
+	FindCodesByRoleID(db *gorm.DB, roleID int) ([]string, error)
+}
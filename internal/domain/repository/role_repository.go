@@ -10,4 +10,8 @@ import (
 
 type RoleRepository interface {
 	FindByName(ctx context.Context, db *gorm.DB, name string) (*entity.Role, error)
+	FindByID(ctx context.Context, db *gorm.DB, id int) (*entity.Role, error)
+	FindAll(ctx context.Context, db *gorm.DB) ([]entity.Role, error)
+	Create(ctx context.Context, db *gorm.DB, role *entity.Role) error
+	ReplacePermissions(ctx context.Context, db *gorm.DB, role *entity.Role, permissions []entity.Permission) error
 }
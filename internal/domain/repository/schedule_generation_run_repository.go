@@ -0,0 +1,15 @@
+package repository
+
+import (
+	"go-template-clean-architecture/internal/domain/entity"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type ScheduleGenerationRunRepository interface {
+	Create(db *gorm.DB, run *entity.ScheduleGenerationRun) error
+	Save(db *gorm.DB, run *entity.ScheduleGenerationRun) error
+	FindByID(db *gorm.DB, id uuid.UUID) (*entity.ScheduleGenerationRun, error)
+	FindAll(db *gorm.DB, page, limit int) ([]entity.ScheduleGenerationRun, int64, error)
+}
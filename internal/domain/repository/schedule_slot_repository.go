@@ -0,0 +1,13 @@
+package repository
+
+import (
+	"go-template-clean-architecture/internal/domain/entity"
+
+	"gorm.io/gorm"
+)
+
+type ScheduleSlotRepository interface {
+	CreateBatch(db *gorm.DB, slots []entity.ScheduleSlot) error
+	FindByID(db *gorm.DB, id int64) (*entity.ScheduleSlot, error)
+	FindByScheduleID(db *gorm.DB, scheduleID int) ([]entity.ScheduleSlot, error)
+}
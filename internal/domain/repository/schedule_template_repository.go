@@ -0,0 +1,20 @@
+package repository
+
+import (
+	"time"
+
+	"go-template-clean-architecture/internal/domain/entity"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type ScheduleTemplateRepository interface {
+	Create(db *gorm.DB, template *entity.ScheduleTemplate) error
+	FindByID(db *gorm.DB, id int) (*entity.ScheduleTemplate, error)
+	FindAll(db *gorm.DB, doctorID *uuid.UUID, page, limit int) ([]entity.ScheduleTemplate, int64, error)
+	FindActive(db *gorm.DB) ([]entity.ScheduleTemplate, error)
+	Update(db *gorm.DB, template *entity.ScheduleTemplate) error
+	MarkGeneratedUntil(db *gorm.DB, ids []int, until time.Time) error
+	Delete(db *gorm.DB, id int) (int64, error)
+}
@@ -0,0 +1,13 @@
+package repository
+
+import (
+	"go-template-clean-architecture/internal/domain/entity"
+
+	"gorm.io/gorm"
+)
+
+type ScheduleVersionRepository interface {
+	Create(db *gorm.DB, version *entity.ScheduleVersion) error
+	CreateBatch(db *gorm.DB, versions []entity.ScheduleVersion) error
+	FindByScheduleID(db *gorm.DB, scheduleID int) ([]entity.ScheduleVersion, error)
+}
@@ -0,0 +1,17 @@
+package repository
+
+import (
+	"go-template-clean-architecture/internal/domain/entity"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type SpecializationDefaultRepository interface {
+	Upsert(db *gorm.DB, defaults *entity.SpecializationDefault) error
+	FindBySpecialization(db *gorm.DB, specialization string) (*entity.SpecializationDefault, error)
+	FindByDoctorID(db *gorm.DB, doctorID uuid.UUID) (*entity.SpecializationDefault, error)
+	FindBySpecializationID(db *gorm.DB, specializationID int) (*entity.SpecializationDefault, error)
+	FindAll(db *gorm.DB) ([]entity.SpecializationDefault, error)
+	Delete(db *gorm.DB, specialization string) (int64, error)
+}
@@ -0,0 +1,12 @@
+package repository
+
+import (
+	"go-template-clean-architecture/internal/domain/entity"
+
+	"gorm.io/gorm"
+)
+
+type UsageRecordRepository interface {
+	Upsert(db *gorm.DB, record *entity.UsageRecord) error
+	FindByTenantAndRange(db *gorm.DB, tenantID string, startDate, endDate string) ([]entity.UsageRecord, error)
+}
@@ -1,6 +1,8 @@
 package repository
 
 import (
+	"time"
+
 	"go-template-clean-architecture/internal/domain/entity"
 
 	"github.com/google/uuid"
@@ -9,8 +11,21 @@ import (
 
 type UserRepository interface {
 	Create(db *gorm.DB, user *entity.User) error
+	CreateBatch(db *gorm.DB, users []entity.User) error
 	FindByEmail(db *gorm.DB, email string) (*entity.User, error)
 	FindByID(db *gorm.DB, id uuid.UUID) (*entity.User, error)
 	Update(db *gorm.DB, user *entity.User) error
+	Claim(db *gorm.DB, userID uuid.UUID, email, password string, claimedAt time.Time) (int64, error)
 	Delete(db *gorm.DB, userID uuid.UUID) (int64, error)
+	UpdateTwoFactor(db *gorm.DB, user *entity.User) error
+	ConsumeBackupCode(db *gorm.DB, userID uuid.UUID, codeHash string) (int64, error)
+	FindByGoogleSubject(db *gorm.DB, subject string) (*entity.User, error)
+	LinkGoogleSubject(db *gorm.DB, userID uuid.UUID, subject string) (int64, error)
+	Erase(db *gorm.DB, userID uuid.UUID, erasedAt time.Time) (int64, error)
+	FindAll(db *gorm.DB, filter *entity.UserFilter, page, limit int) ([]entity.User, int64, error)
+	Lock(db *gorm.DB, userID uuid.UUID, lockedAt, lockedUntil time.Time) error
+	Unlock(db *gorm.DB, userID uuid.UUID) error
+	UpdateLastLogin(db *gorm.DB, userID uuid.UUID, at time.Time) error
+	ChangePassword(db *gorm.DB, userID uuid.UUID, password string, changedAt time.Time) error
+	SetMustChangePassword(db *gorm.DB, userID uuid.UUID, required bool) error
 }
@@ -0,0 +1,14 @@
+package repository
+
+import (
+	"go-template-clean-architecture/internal/domain/entity"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type WaitFeedbackRepository interface {
+	Create(db *gorm.DB, feedback *entity.WaitFeedback) error
+	AverageByDoctorIDs(db *gorm.DB, doctorIDs []uuid.UUID) ([]entity.DoctorWaitStat, error)
+	AverageAllDoctors(db *gorm.DB, startAt, endAt string) ([]entity.DoctorWaitStat, error)
+}
@@ -0,0 +1,32 @@
+package repository
+
+import (
+	"time"
+
+	"go-template-clean-architecture/internal/domain/entity"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type WebhookSubscriptionRepository interface {
+	Create(db *gorm.DB, subscription *entity.WebhookSubscription) error
+	FindByID(db *gorm.DB, id int) (*entity.WebhookSubscription, error)
+	FindAll(db *gorm.DB) ([]entity.WebhookSubscription, error)
+	FindActiveByEventType(db *gorm.DB, eventType string) ([]entity.WebhookSubscription, error)
+	Update(db *gorm.DB, subscription *entity.WebhookSubscription) error
+	Delete(db *gorm.DB, id int) (int64, error)
+	RecordSuccess(db *gorm.DB, id int) error
+	RecordFailure(db *gorm.DB, id int) (int, error)
+	Pause(db *gorm.DB, id int, reason string, at time.Time) (int64, error)
+}
+
+type WebhookDeliveryRepository interface {
+	Create(db *gorm.DB, delivery *entity.WebhookDelivery) error
+	CreateIfAbsent(db *gorm.DB, delivery *entity.WebhookDelivery) (bool, error)
+	ClaimDue(db *gorm.DB, now time.Time, limit int) ([]entity.WebhookDelivery, error)
+	MarkSucceeded(db *gorm.DB, id uuid.UUID, attempts int, responseStatus int, responseBody string, at time.Time) error
+	MarkRetry(db *gorm.DB, id uuid.UUID, attempts int, nextAttemptAt time.Time, responseStatus *int, responseBody string, lastError string) error
+	MarkFailed(db *gorm.DB, id uuid.UUID, attempts int, responseStatus *int, responseBody string, lastError string, at time.Time) error
+	FindBySubscriptionID(db *gorm.DB, subscriptionID int, status string, page, limit int) ([]entity.WebhookDelivery, int64, error)
+}
@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"go-template-clean-architecture/internal/domain/entity"
+	domainRepo "go-template-clean-architecture/internal/domain/repository"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type apiKeyRepository struct{}
+
+func NewAPIKeyRepository() domainRepo.APIKeyRepository {
+	return &apiKeyRepository{}
+}
+
+// Create inserts an API key with its permissions
+func (r *apiKeyRepository) Create(db *gorm.DB, apiKey *entity.APIKey) error {
+	return db.Create(apiKey).Error
+}
+
+// FindByID finds an API key with its permissions
+func (r *apiKeyRepository) FindByID(db *gorm.DB, id uuid.UUID) (*entity.APIKey, error) {
+	var apiKey entity.APIKey
+	err := db.Preload("Permissions", func(db *gorm.DB) *gorm.DB { return db.Order("permissions.code ASC") }).
+		Where("id = ?", id).First(&apiKey).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &apiKey, nil
+}
+
+// FindByHash finds the API key of a presented key, revoked and expired keys included
+func (r *apiKeyRepository) FindByHash(db *gorm.DB, keyHash string) (*entity.APIKey, error) {
+	var apiKey entity.APIKey
+	err := db.Preload("Permissions").Where("key_hash = ?", keyHash).First(&apiKey).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &apiKey, nil
+}
+
+// FindAll returns all API keys with their permissions, newest first
+func (r *apiKeyRepository) FindAll(db *gorm.DB) ([]entity.APIKey, error) {
+	var apiKeys []entity.APIKey
+	err := db.Preload("Permissions", func(db *gorm.DB) *gorm.DB { return db.Order("permissions.code ASC") }).
+		Order("created_at DESC").Find(&apiKeys).Error
+	return apiKeys, err
+}
+
+// Update saves the name and expiry of an API key
+func (r *apiKeyRepository) Update(db *gorm.DB, apiKey *entity.APIKey) error {
+	return db.Model(apiKey).Select("name", "expires_at", "updated_at").Updates(apiKey).Error
+}
+
+// ReplacePermissions sets the permissions of an API key, removing the ones not listed
+func (r *apiKeyRepository) ReplacePermissions(db *gorm.DB, apiKey *entity.APIKey, permissions []entity.Permission) error {
+	return db.Model(apiKey).Association("Permissions").Replace(permissions)
+}
+
+// Revoke disables an API key for good. Returns 0 rows when it does not exist or is already revoked.
+func (r *apiKeyRepository) Revoke(db *gorm.DB, id uuid.UUID, revokedAt time.Time) (int64, error) {
+	result := db.Model(&entity.APIKey{}).
+		Where("id = ? AND revoked_at IS NULL", id).
+		Updates(map[string]interface{}{"revoked_at": revokedAt, "updated_at": revokedAt})
+	return result.RowsAffected, result.Error
+}
+
+// UpdateLastUsed records when the API key last authenticated a request
+func (r *apiKeyRepository) UpdateLastUsed(db *gorm.DB, id uuid.UUID, usedAt time.Time) error {
+	return db.Model(&entity.APIKey{}).Where("id = ?", id).UpdateColumn("last_used_at", usedAt).Error
+}
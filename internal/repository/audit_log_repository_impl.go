@@ -4,7 +4,9 @@ import (
 	"go-template-clean-architecture/internal/domain/entity"
 	domainRepo "go-template-clean-architecture/internal/domain/repository"
 
+	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type auditLogRepository struct{}
@@ -17,6 +19,19 @@ func (r *auditLogRepository) Create(db *gorm.DB, log *entity.AuditLog) error {
 	return db.Create(log).Error
 }
 
+// CreateIfAbsent inserts the log unless one was already recorded for its event.
+// Returns false when it already existed (domain event redelivered).
+func (r *auditLogRepository) CreateIfAbsent(db *gorm.DB, log *entity.AuditLog) (bool, error) {
+	result := db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "event_id"}},
+		DoNothing: true,
+	}).Create(log)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
 func (r *auditLogRepository) FindAll(db *gorm.DB) ([]entity.AuditLog, error) {
 	var logs []entity.AuditLog
 	err := db.Preload("User.Role").Find(&logs).Error
@@ -34,3 +49,18 @@ func (r *auditLogRepository) FindByID(db *gorm.DB, id int64) (*entity.AuditLog,
 	}
 	return &log, nil
 }
+
+// FindByUserOrEntities returns audit logs performed by userID, or about any of the
+// given entities (metadata entity/entity_id), e.g. an admin cancelling a patient's booking.
+func (r *auditLogRepository) FindByUserOrEntities(db *gorm.DB, userID uuid.UUID, entityName string, entityIDs []string) ([]entity.AuditLog, error) {
+	var logs []entity.AuditLog
+	query := db.Preload("User.Role").Where("user_id = ?", userID)
+	if len(entityIDs) > 0 {
+		query = query.Or("metadata->>'entity' = ? AND metadata->>'entity_id' IN ?", entityName, entityIDs)
+	}
+	err := query.Order("created_at ASC").Find(&logs).Error
+	if err != nil {
+		return nil, err
+	}
+	return logs, nil
+}
@@ -2,6 +2,7 @@ package repository
 
 import (
 	"errors"
+	"time"
 
 	"go-template-clean-architecture/internal/domain/entity"
 	domainRepo "go-template-clean-architecture/internal/domain/repository"
@@ -22,7 +23,25 @@ func (r *bookingRepository) Create(db *gorm.DB, booking *entity.Booking) error {
 
 func (r *bookingRepository) FindByID(db *gorm.DB, id uuid.UUID) (*entity.Booking, error) {
 	var booking entity.Booking
-	err := db.Preload("Schedule.Doctor").Where("id = ?", id).First(&booking).Error
+	err := db.Preload("Schedule.Doctor").Preload("Slot").Where("id = ?", id).First(&booking).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &booking, nil
+}
+
+// FindByBookingCode returns a booking with patient and schedule details for front-desk lookup.
+func (r *bookingRepository) FindByBookingCode(db *gorm.DB, bookingCode string) (*entity.Booking, error) {
+	var booking entity.Booking
+	err := db.Preload("Patient.User").
+		Preload("Schedule.Doctor.User").
+		Preload("Schedule.Doctor.Specialization").
+		Preload("Slot").
+		Where("booking_code = ?", bookingCode).
+		First(&booking).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, nil
@@ -35,6 +54,7 @@ func (r *bookingRepository) FindByID(db *gorm.DB, id uuid.UUID) (*entity.Booking
 func (r *bookingRepository) FindByPatientID(db *gorm.DB, patientID uuid.UUID) ([]entity.Booking, error) {
 	var bookings []entity.Booking
 	err := db.Preload("Schedule.Doctor").
+		Preload("Slot").
 		Where("patient_id = ?", patientID).
 		Order("created_at DESC").
 		Find(&bookings).Error
@@ -46,10 +66,73 @@ func (r *bookingRepository) FindByPatientID(db *gorm.DB, patientID uuid.UUID) ([
 
 // CancelBooking atomically cancels a booking ONLY if it's not already cancelled.
 // Returns affected rows: 1 = success, 0 = already cancelled (prevents double-cancel race).
-func (r *bookingRepository) CancelBooking(db *gorm.DB, id uuid.UUID) (int64, error) {
+// CancelBooking atomically cancels a booking at the given version (optimistic lock).
+// Returns affected rows: 0 = already cancelled or modified concurrently.
+// CountUpcomingByPatient counts the non-cancelled bookings of the patient on schedules from the date on
+func (r *bookingRepository) CountUpcomingByPatient(db *gorm.DB, patientID uuid.UUID, from time.Time) (int64, error) {
+	var count int64
+	err := db.Model(&entity.Booking{}).
+		Joins("JOIN doctor_schedules ON doctor_schedules.id = bookings.schedule_id").
+		Where("bookings.patient_id = ? AND bookings.status <> ? AND doctor_schedules.schedule_date >= ?",
+			patientID, entity.BookingStatusCancelled, from).
+		Count(&count).Error
+	return count, err
+}
+
+// ExistsByPatientAndDoctor reports whether the patient has a non-cancelled booking, past or upcoming,
+// on a schedule of the doctor
+func (r *bookingRepository) ExistsByPatientAndDoctor(db *gorm.DB, patientID, doctorID uuid.UUID) (bool, error) {
+	var count int64
+	err := db.Model(&entity.Booking{}).
+		Joins("JOIN doctor_schedules ON doctor_schedules.id = bookings.schedule_id").
+		Where("bookings.patient_id = ? AND bookings.status <> ? AND doctor_schedules.doctor_id = ?",
+			patientID, entity.BookingStatusCancelled, doctorID).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// FindHistoryByPatientID returns one page of the patient's bookings with any doctor, newest visit
+// first, with their schedule and doctor, and the total count
+func (r *bookingRepository) FindHistoryByPatientID(db *gorm.DB, patientID uuid.UUID, page, limit int) ([]entity.Booking, int64, error) {
+	query := db.Model(&entity.Booking{}).
+		Joins("JOIN doctor_schedules ON doctor_schedules.id = bookings.schedule_id").
+		Where("bookings.patient_id = ?", patientID)
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var bookings []entity.Booking
+	err := query.Preload("Schedule.Doctor.User").
+		Order("doctor_schedules.schedule_date DESC, doctor_schedules.start_time DESC, bookings.id ASC").
+		Scopes(paginate(page, limit)).
+		Find(&bookings).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	return bookings, total, nil
+}
+
+func (r *bookingRepository) CancelBooking(db *gorm.DB, id uuid.UUID, version int) (int64, error) {
+	result := db.Model(&entity.Booking{}).
+		Where("id = ? AND version = ? AND status != ?", id, version, entity.BookingStatusCancelled).
+		Updates(map[string]interface{}{
+			"status":  entity.BookingStatusCancelled,
+			"version": gorm.Expr("version + 1"),
+		})
+	return result.RowsAffected, result.Error
+}
+
+// ConfirmPayment confirms a booking awaiting payment once its fee is paid.
+// Returns affected rows: 0 = booking is not awaiting payment (e.g. cancelled meanwhile).
+func (r *bookingRepository) ConfirmPayment(db *gorm.DB, id uuid.UUID) (int64, error) {
 	result := db.Model(&entity.Booking{}).
-		Where("id = ? AND status != ?", id, entity.BookingStatusCancelled).
-		Update("status", entity.BookingStatusCancelled)
+		Where("id = ? AND status = ?", id, entity.BookingStatusAwaitingPayment).
+		Updates(map[string]interface{}{
+			"status":  entity.BookingStatusConfirmed,
+			"version": gorm.Expr("version + 1"),
+		})
 	return result.RowsAffected, result.Error
 }
 
@@ -65,3 +148,263 @@ func (r *bookingRepository) FindByPatientAndSchedule(db *gorm.DB, patientID uuid
 	}
 	return &booking, nil
 }
+
+// FindActiveByScheduleID returns non-cancelled bookings of a schedule ordered by queue number.
+func (r *bookingRepository) FindActiveByScheduleID(db *gorm.DB, scheduleID int) ([]entity.Booking, error) {
+	var bookings []entity.Booking
+	err := db.Where("schedule_id = ? AND status != ?", scheduleID, entity.BookingStatusCancelled).
+		Order("queue_number ASC").
+		Find(&bookings).Error
+	if err != nil {
+		return nil, err
+	}
+	return bookings, nil
+}
+
+// FindActiveByScheduleIDs returns the non-cancelled bookings of the schedules with their patient
+// and slot, ordered by schedule and queue number.
+func (r *bookingRepository) FindActiveByScheduleIDs(db *gorm.DB, scheduleIDs []int) ([]entity.Booking, error) {
+	var bookings []entity.Booking
+	if len(scheduleIDs) == 0 {
+		return bookings, nil
+	}
+	err := db.Preload("Patient.User").Preload("Slot").
+		Where("schedule_id IN ? AND status != ?", scheduleIDs, entity.BookingStatusCancelled).
+		Order("schedule_id ASC, queue_number ASC").
+		Find(&bookings).Error
+	if err != nil {
+		return nil, err
+	}
+	return bookings, nil
+}
+
+// Reassign moves an active booking to another schedule with a new queue number.
+// Returns affected rows: 0 = booking was cancelled or modified concurrently.
+func (r *bookingRepository) Reassign(db *gorm.DB, id uuid.UUID, version int, scheduleID int, queueNumber int) (int64, error) {
+	result := db.Model(&entity.Booking{}).
+		Where("id = ? AND version = ? AND status != ?", id, version, entity.BookingStatusCancelled).
+		Updates(map[string]interface{}{
+			"schedule_id":  scheduleID,
+			"queue_number": queueNumber,
+			"slot_id":      nil, // Target schedules are queue mode
+			"version":      gorm.Expr("version + 1"),
+		})
+	return result.RowsAffected, result.Error
+}
+
+// FindNextUncalled returns the active booking with the lowest queue number not called yet.
+// Bookings whose fee is not paid yet are skipped.
+func (r *bookingRepository) FindNextUncalled(db *gorm.DB, scheduleID int) (*entity.Booking, error) {
+	var booking entity.Booking
+	err := db.Where("schedule_id = ? AND status NOT IN ? AND called_at IS NULL", scheduleID,
+		[]entity.BookingStatus{entity.BookingStatusCancelled, entity.BookingStatusAwaitingPayment}).
+		Order("queue_number ASC").
+		First(&booking).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &booking, nil
+}
+
+// MarkCalled atomically sets called_at on an active, uncalled booking at the given version.
+// Returns affected rows: 0 = booking was called, cancelled or modified concurrently.
+func (r *bookingRepository) MarkCalled(db *gorm.DB, id uuid.UUID, version int, at time.Time) (int64, error) {
+	result := db.Model(&entity.Booking{}).
+		Where("id = ? AND version = ? AND status != ? AND called_at IS NULL", id, version, entity.BookingStatusCancelled).
+		Updates(map[string]interface{}{
+			"called_at": at,
+			"version":   gorm.Expr("version + 1"),
+		})
+	return result.RowsAffected, result.Error
+}
+
+// FindLastCalledAt returns the latest call time of a schedule, or nil if nobody was called yet.
+func (r *bookingRepository) FindLastCalledAt(db *gorm.DB, scheduleID int) (*time.Time, error) {
+	var lastCalledAt *time.Time
+	err := db.Model(&entity.Booking{}).
+		Select("MAX(called_at)").
+		Where("schedule_id = ?", scheduleID).
+		Scan(&lastCalledAt).Error
+	if err != nil {
+		return nil, err
+	}
+	return lastCalledAt, nil
+}
+
+// FindLastCalled returns the most recently called active booking of a schedule (currently serving)
+func (r *bookingRepository) FindLastCalled(db *gorm.DB, scheduleID int) (*entity.Booking, error) {
+	var booking entity.Booking
+	err := db.Where("schedule_id = ? AND status != ? AND called_at IS NOT NULL", scheduleID, entity.BookingStatusCancelled).
+		Order("called_at DESC").
+		First(&booking).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &booking, nil
+}
+
+// CountBySchedule counts the active, confirmed and called bookings of a schedule in one query
+func (r *bookingRepository) CountBySchedule(db *gorm.DB, scheduleID int) (*entity.ScheduleBookingCounts, error) {
+	var counts entity.ScheduleBookingCounts
+	err := db.Model(&entity.Booking{}).
+		Select(`
+			COUNT(*) as booked,
+			COUNT(CASE WHEN status = ? THEN 1 END) as confirmed,
+			COUNT(CASE WHEN called_at IS NOT NULL THEN 1 END) as called
+		`, entity.BookingStatusConfirmed).
+		Where("schedule_id = ? AND status != ?", scheduleID, entity.BookingStatusCancelled).
+		Scan(&counts).Error
+	if err != nil {
+		return nil, err
+	}
+	return &counts, nil
+}
+
+// CountWaitingAhead counts active, uncalled bookings queued before the given queue number.
+func (r *bookingRepository) CountWaitingAhead(db *gorm.DB, scheduleID int, queueNumber int) (int64, error) {
+	var count int64
+	err := db.Model(&entity.Booking{}).
+		Where("schedule_id = ? AND queue_number < ? AND status != ? AND called_at IS NULL", scheduleID, queueNumber, entity.BookingStatusCancelled).
+		Count(&count).Error
+	return count, err
+}
+
+// CountByScheduleID counts the bookings of a schedule, cancelled ones included
+func (r *bookingRepository) CountByScheduleID(db *gorm.DB, scheduleID int) (int64, error) {
+	var count int64
+	err := db.Model(&entity.Booking{}).
+		Where("schedule_id = ?", scheduleID).
+		Count(&count).Error
+	return count, err
+}
+
+// Delete soft-deletes a cancelled booking.
+// Returns affected rows: 0 = booking not found, deleted already or not cancelled.
+func (r *bookingRepository) Delete(db *gorm.DB, id uuid.UUID) (int64, error) {
+	result := db.Where("id = ? AND status = ?", id, entity.BookingStatusCancelled).Delete(&entity.Booking{})
+	return result.RowsAffected, result.Error
+}
+
+// FindDeleted returns one page of the soft-deleted bookings, most recently deleted first,
+// with their patient and schedule (deleted schedules included), and the total count
+func (r *bookingRepository) FindDeleted(db *gorm.DB, page, limit int) ([]entity.Booking, int64, error) {
+	query := db.Unscoped().Model(&entity.Booking{}).Where("deleted_at IS NOT NULL")
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var bookings []entity.Booking
+	err := query.Preload("Patient.User").
+		Preload("Schedule", func(db *gorm.DB) *gorm.DB { return db.Unscoped() }).
+		Preload("Schedule.Doctor.User").
+		Preload("Slot").
+		Order("deleted_at DESC, id ASC").
+		Scopes(paginate(page, limit)).
+		Find(&bookings).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	return bookings, total, nil
+}
+
+// FindDeletedByID returns a soft-deleted booking, nil when it does not exist or is not deleted
+func (r *bookingRepository) FindDeletedByID(db *gorm.DB, id uuid.UUID) (*entity.Booking, error) {
+	var booking entity.Booking
+	err := db.Unscoped().Where("id = ? AND deleted_at IS NOT NULL", id).First(&booking).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &booking, nil
+}
+
+// Restore clears the soft delete of a booking.
+// Returns affected rows: 0 = booking is not deleted.
+func (r *bookingRepository) Restore(db *gorm.DB, id uuid.UUID) (int64, error) {
+	result := db.Unscoped().Model(&entity.Booking{}).
+		Where("id = ? AND deleted_at IS NOT NULL", id).
+		Updates(map[string]interface{}{
+			"deleted_at": nil,
+			"version":    gorm.Expr("version + 1"),
+		})
+	return result.RowsAffected, result.Error
+}
+
+// CreateBatch inserts bookings in chunks (bulk import)
+func (r *bookingRepository) CreateBatch(db *gorm.DB, bookings []entity.Booking) error {
+	return db.CreateInBatches(bookings, 500).Error
+}
+
+// FindQueueNumbersByScheduleID returns every queue number used on a schedule,
+// including cancelled and deleted bookings (their queue numbers are never reused).
+func (r *bookingRepository) FindQueueNumbersByScheduleID(db *gorm.DB, scheduleID int) ([]int, error) {
+	var queueNumbers []int
+	err := db.Unscoped().Model(&entity.Booking{}).
+		Where("schedule_id = ?", scheduleID).
+		Pluck("queue_number", &queueNumbers).Error
+	if err != nil {
+		return nil, err
+	}
+	return queueNumbers, nil
+}
+
+// FindExistingBookingCodes returns the subset of bookingCodes already in use, deleted bookings included
+func (r *bookingRepository) FindExistingBookingCodes(db *gorm.DB, bookingCodes []string) ([]string, error) {
+	var existing []string
+	if len(bookingCodes) == 0 {
+		return existing, nil
+	}
+	err := db.Unscoped().Model(&entity.Booking{}).
+		Where("booking_code IN ?", bookingCodes).
+		Pluck("booking_code", &existing).Error
+	if err != nil {
+		return nil, err
+	}
+	return existing, nil
+}
+
+// FindReservedSlotIDs returns the appointment slots held by active bookings of a schedule
+func (r *bookingRepository) FindReservedSlotIDs(db *gorm.DB, scheduleID int) ([]int64, error) {
+	var slotIDs []int64
+	err := db.Model(&entity.Booking{}).
+		Where("schedule_id = ? AND slot_id IS NOT NULL AND status != ?", scheduleID, entity.BookingStatusCancelled).
+		Pluck("slot_id", &slotIDs).Error
+	if err != nil {
+		return nil, err
+	}
+	return slotIDs, nil
+}
+
+// DeleteByScheduleID soft-deletes every booking of a schedule (forced schedule deletion)
+func (r *bookingRepository) DeleteByScheduleID(db *gorm.DB, scheduleID int) (int64, error) {
+	result := db.Where("schedule_id = ?", scheduleID).Delete(&entity.Booking{})
+	return result.RowsAffected, result.Error
+}
+
+// FindActiveByScheduleDate returns the non-cancelled bookings on the date's approved schedules
+// that are not closed by a holiday or a doctor's leave, with the schedule, doctor and slot.
+func (r *bookingRepository) FindActiveByScheduleDate(db *gorm.DB, scheduleDate time.Time) ([]entity.Booking, error) {
+	var bookings []entity.Booking
+	err := db.Joins("JOIN doctor_schedules ON doctor_schedules.id = bookings.schedule_id").
+		Preload("Schedule.Doctor.User").Preload("Slot").
+		Where("doctor_schedules.schedule_date = ? AND doctor_schedules.approval_status = ?",
+			scheduleDate.Format("2006-01-02"), entity.ScheduleApprovalApproved).
+		Where("doctor_schedules.holiday_flagged_at IS NULL AND doctor_schedules.leave_flagged_at IS NULL").
+		Where("bookings.status <> ?", entity.BookingStatusCancelled).
+		Order("doctor_schedules.start_time ASC, bookings.queue_number ASC").
+		Find(&bookings).Error
+	if err != nil {
+		return nil, err
+	}
+	return bookings, nil
+}
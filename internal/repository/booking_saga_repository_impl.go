@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"go-template-clean-architecture/internal/domain/entity"
+	domainRepo "go-template-clean-architecture/internal/domain/repository"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type bookingSagaRepository struct{}
+
+func NewBookingSagaRepository() domainRepo.BookingSagaRepository {
+	return &bookingSagaRepository{}
+}
+
+func (r *bookingSagaRepository) Create(db *gorm.DB, saga *entity.BookingSaga) error {
+	return db.Create(saga).Error
+}
+
+func (r *bookingSagaRepository) Save(db *gorm.DB, saga *entity.BookingSaga) error {
+	return db.Save(saga).Error
+}
+
+func (r *bookingSagaRepository) FindByID(db *gorm.DB, id uuid.UUID) (*entity.BookingSaga, error) {
+	var saga entity.BookingSaga
+	err := db.Where("id = ?", id).First(&saga).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &saga, nil
+}
+
+// FindAll returns one page of sagas, newest first, optionally filtered by status
+func (r *bookingSagaRepository) FindAll(db *gorm.DB, status string, page, limit int) ([]entity.BookingSaga, int64, error) {
+	query := db.Model(&entity.BookingSaga{})
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var sagas []entity.BookingSaga
+	err := query.Order("created_at DESC").
+		Offset((page - 1) * limit).
+		Limit(limit).
+		Find(&sagas).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	return sagas, total, nil
+}
+
+// ClaimStale locks unfinished sagas not updated since updatedBefore (their process stopped)
+// with FOR UPDATE SKIP LOCKED, so several API instances can run the recovery worker.
+// Must be called inside a transaction.
+func (r *bookingSagaRepository) ClaimStale(db *gorm.DB, updatedBefore time.Time, limit int) ([]entity.BookingSaga, error) {
+	var sagas []entity.BookingSaga
+	err := db.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+		Where("status IN ? AND updated_at < ?", []entity.BookingSagaStatus{entity.BookingSagaStatusRunning, entity.BookingSagaStatusCompensating}, updatedBefore).
+		Order("updated_at ASC").
+		Limit(limit).
+		Find(&sagas).Error
+	if err != nil {
+		return nil, err
+	}
+	return sagas, nil
+}
@@ -0,0 +1,177 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"go-template-clean-architecture/internal/domain/entity"
+	domainRepo "go-template-clean-architecture/internal/domain/repository"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// broadcastRecipientBatchSize bounds the rows per INSERT when creating recipients
+const broadcastRecipientBatchSize = 500
+
+type broadcastRepository struct{}
+
+func NewBroadcastRepository() domainRepo.BroadcastRepository {
+	return &broadcastRepository{}
+}
+
+func (r *broadcastRepository) Create(db *gorm.DB, broadcast *entity.Broadcast) error {
+	return db.Create(broadcast).Error
+}
+
+func (r *broadcastRepository) FindByID(db *gorm.DB, id uuid.UUID) (*entity.Broadcast, error) {
+	var broadcast entity.Broadcast
+	err := db.Where("id = ?", id).First(&broadcast).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &broadcast, nil
+}
+
+func (r *broadcastRepository) FindAll(db *gorm.DB) ([]entity.Broadcast, error) {
+	var broadcasts []entity.Broadcast
+	err := db.Order("created_at DESC").Find(&broadcasts).Error
+	if err != nil {
+		return nil, err
+	}
+	return broadcasts, nil
+}
+
+// FindSegmentPatientIDs returns the active patients selected by the segment.
+// An empty segment selects every active patient; otherwise patients need an
+// active (not cancelled) booking on a schedule matching all filters.
+func (r *broadcastRepository) FindSegmentPatientIDs(db *gorm.DB, segment *entity.BroadcastSegment) ([]uuid.UUID, error) {
+	var patientIDs []uuid.UUID
+
+	if segment.IsEmpty() {
+		err := db.Table("patient_profiles").
+			Joins("JOIN users ON users.id = patient_profiles.user_id").
+			Where("users.is_active = ?", true).
+			Order("patient_profiles.user_id").
+			Pluck("patient_profiles.user_id", &patientIDs).Error
+		if err != nil {
+			return nil, err
+		}
+		return patientIDs, nil
+	}
+
+	query := db.Table("bookings").
+		Joins("JOIN doctor_schedules ON doctor_schedules.id = bookings.schedule_id").
+		Joins("JOIN users ON users.id = bookings.patient_id").
+		Where("bookings.status != ? AND bookings.deleted_at IS NULL", entity.BookingStatusCancelled).
+		Where("users.is_active = ?", true)
+
+	if segment.DoctorID != nil {
+		query = query.Where("doctor_schedules.doctor_id = ?", *segment.DoctorID)
+	}
+	if segment.DateFrom != nil {
+		query = query.Where("doctor_schedules.schedule_date >= ?", *segment.DateFrom)
+	}
+	if segment.DateTo != nil {
+		query = query.Where("doctor_schedules.schedule_date <= ?", *segment.DateTo)
+	}
+	if segment.UpcomingFrom != nil {
+		query = query.Where("doctor_schedules.schedule_date >= ?", *segment.UpcomingFrom)
+	}
+
+	err := query.Distinct("bookings.patient_id").
+		Order("bookings.patient_id").
+		Pluck("bookings.patient_id", &patientIDs).Error
+	if err != nil {
+		return nil, err
+	}
+	return patientIDs, nil
+}
+
+func (r *broadcastRepository) CreateRecipients(db *gorm.DB, recipients []entity.BroadcastRecipient) error {
+	if len(recipients) == 0 {
+		return nil
+	}
+	return db.CreateInBatches(recipients, broadcastRecipientBatchSize).Error
+}
+
+func (r *broadcastRepository) FindRecipientByID(db *gorm.DB, id int64) (*entity.BroadcastRecipient, error) {
+	var recipient entity.BroadcastRecipient
+	err := db.Where("id = ?", id).First(&recipient).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &recipient, nil
+}
+
+// FindFailedRecipients returns the undeliverable recipients of a broadcast with their user
+func (r *broadcastRepository) FindFailedRecipients(db *gorm.DB, broadcastID uuid.UUID) ([]entity.BroadcastRecipient, error) {
+	var recipients []entity.BroadcastRecipient
+	err := db.Preload("Patient").
+		Where("broadcast_id = ? AND status = ?", broadcastID, entity.BroadcastRecipientFailed).
+		Order("id ASC").
+		Find(&recipients).Error
+	if err != nil {
+		return nil, err
+	}
+	return recipients, nil
+}
+
+// FindLastPendingScheduledAt returns the latest delivery time queued by any broadcast (nil when idle),
+// so a new broadcast is throttled behind the ones still being delivered.
+func (r *broadcastRepository) FindLastPendingScheduledAt(db *gorm.DB) (*time.Time, error) {
+	var last *time.Time
+	err := db.Model(&entity.BroadcastRecipient{}).
+		Where("status = ?", entity.BroadcastRecipientPending).
+		Select("MAX(scheduled_at)").
+		Scan(&last).Error
+	if err != nil {
+		return nil, err
+	}
+	return last, nil
+}
+
+// CountRecipientsByStatus returns recipient counts per broadcast and delivery status
+func (r *broadcastRepository) CountRecipientsByStatus(db *gorm.DB, broadcastIDs []uuid.UUID) ([]entity.BroadcastStatusCount, error) {
+	var counts []entity.BroadcastStatusCount
+	if len(broadcastIDs) == 0 {
+		return counts, nil
+	}
+
+	err := db.Model(&entity.BroadcastRecipient{}).
+		Select("broadcast_id, status, COUNT(*) as count").
+		Where("broadcast_id IN ?", broadcastIDs).
+		Group("broadcast_id, status").
+		Scan(&counts).Error
+	if err != nil {
+		return nil, err
+	}
+	return counts, nil
+}
+
+func (r *broadcastRepository) MarkRecipientSent(db *gorm.DB, id int64, at time.Time) error {
+	return db.Model(&entity.BroadcastRecipient{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"status":     entity.BroadcastRecipientSent,
+			"attempts":   gorm.Expr("attempts + 1"),
+			"last_error": "",
+			"sent_at":    at,
+		}).Error
+}
+
+func (r *broadcastRepository) MarkRecipientFailed(db *gorm.DB, id int64, lastError string) error {
+	return db.Model(&entity.BroadcastRecipient{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"status":     entity.BroadcastRecipientFailed,
+			"attempts":   gorm.Expr("attempts + 1"),
+			"last_error": lastError,
+		}).Error
+}
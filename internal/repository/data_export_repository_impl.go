@@ -0,0 +1,50 @@
+package repository
+
+import (
+	"errors"
+
+	"go-template-clean-architecture/internal/domain/entity"
+	domainRepo "go-template-clean-architecture/internal/domain/repository"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type dataExportRepository struct{}
+
+func NewDataExportRepository() domainRepo.DataExportRepository {
+	return &dataExportRepository{}
+}
+
+func (r *dataExportRepository) Create(db *gorm.DB, export *entity.DataExport) error {
+	return db.Create(export).Error
+}
+
+func (r *dataExportRepository) Update(db *gorm.DB, export *entity.DataExport) error {
+	return db.Save(export).Error
+}
+
+func (r *dataExportRepository) FindByID(db *gorm.DB, id uuid.UUID) (*entity.DataExport, error) {
+	var export entity.DataExport
+	err := db.Where("id = ?", id).First(&export).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &export, nil
+}
+
+// FindLatestByUserID returns the most recently requested export of the user
+func (r *dataExportRepository) FindLatestByUserID(db *gorm.DB, userID uuid.UUID) (*entity.DataExport, error) {
+	var export entity.DataExport
+	err := db.Where("user_id = ?", userID).Order("created_at DESC").First(&export).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &export, nil
+}
@@ -0,0 +1,51 @@
+package repository
+
+import (
+	"go-template-clean-architecture/internal/domain/entity"
+	domainRepo "go-template-clean-architecture/internal/domain/repository"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type deviceTokenRepository struct{}
+
+func NewDeviceTokenRepository() domainRepo.DeviceTokenRepository {
+	return &deviceTokenRepository{}
+}
+
+// Upsert registers the token, or moves an already registered token to the user and platform.
+// The token is filled with the stored row.
+func (r *deviceTokenRepository) Upsert(db *gorm.DB, token *entity.DeviceToken) error {
+	return db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "token"}},
+		DoUpdates: clause.AssignmentColumns([]string{"user_id", "platform", "updated_at"}),
+	}, clause.Returning{}).Create(token).Error
+}
+
+// FindByUserID returns the user's devices, most recently registered first
+func (r *deviceTokenRepository) FindByUserID(db *gorm.DB, userID uuid.UUID) ([]entity.DeviceToken, error) {
+	var tokens []entity.DeviceToken
+	err := db.Where("user_id = ?", userID).Order("updated_at DESC").Find(&tokens).Error
+	if err != nil {
+		return nil, err
+	}
+	return tokens, nil
+}
+
+func (r *deviceTokenRepository) DeleteByUserAndID(db *gorm.DB, userID uuid.UUID, id int) (int64, error) {
+	result := db.Where("user_id = ? AND id = ?", userID, id).Delete(&entity.DeviceToken{})
+	return result.RowsAffected, result.Error
+}
+
+// DeleteByToken removes a token the push provider reported as no longer registered
+func (r *deviceTokenRepository) DeleteByToken(db *gorm.DB, token string) (int64, error) {
+	result := db.Where("token = ?", token).Delete(&entity.DeviceToken{})
+	return result.RowsAffected, result.Error
+}
+
+func (r *deviceTokenRepository) DeleteByUserID(db *gorm.DB, userID uuid.UUID) (int64, error) {
+	result := db.Where("user_id = ?", userID).Delete(&entity.DeviceToken{})
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,85 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"go-template-clean-architecture/internal/domain/entity"
+	domainRepo "go-template-clean-architecture/internal/domain/repository"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type doctorLeaveRepository struct{}
+
+func NewDoctorLeaveRepository() domainRepo.DoctorLeaveRepository {
+	return &doctorLeaveRepository{}
+}
+
+func (r *doctorLeaveRepository) Create(db *gorm.DB, leave *entity.DoctorLeave) error {
+	return db.Create(leave).Error
+}
+
+func (r *doctorLeaveRepository) FindByID(db *gorm.DB, id int) (*entity.DoctorLeave, error) {
+	var leave entity.DoctorLeave
+	err := db.Where("id = ?", id).First(&leave).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &leave, nil
+}
+
+// FindByDoctorID returns a doctor's leaves ordered by start date, optionally only those
+// overlapping a date range (YYYY-MM-DD).
+func (r *doctorLeaveRepository) FindByDoctorID(db *gorm.DB, doctorID uuid.UUID, from, to string) ([]entity.DoctorLeave, error) {
+	var leaves []entity.DoctorLeave
+	query := db.Where("doctor_id = ?", doctorID)
+
+	if from != "" {
+		query = query.Where("end_date >= ?", from)
+	}
+	if to != "" {
+		query = query.Where("start_date <= ?", to)
+	}
+
+	err := query.Order("start_date ASC").Find(&leaves).Error
+	if err != nil {
+		return nil, err
+	}
+	return leaves, nil
+}
+
+// FindOverlapping returns a doctor's leaves overlapping from..to (inclusive dates).
+func (r *doctorLeaveRepository) FindOverlapping(db *gorm.DB, doctorID uuid.UUID, from, to time.Time) ([]entity.DoctorLeave, error) {
+	var leaves []entity.DoctorLeave
+	err := db.
+		Where("doctor_id = ? AND start_date <= ? AND end_date >= ?", doctorID, to.Format("2006-01-02"), from.Format("2006-01-02")).
+		Order("start_date ASC").
+		Find(&leaves).Error
+	if err != nil {
+		return nil, err
+	}
+	return leaves, nil
+}
+
+// FindByDateRange returns the leaves of every doctor overlapping from..to (inclusive dates).
+func (r *doctorLeaveRepository) FindByDateRange(db *gorm.DB, from, to time.Time) ([]entity.DoctorLeave, error) {
+	var leaves []entity.DoctorLeave
+	err := db.
+		Where("start_date <= ? AND end_date >= ?", to.Format("2006-01-02"), from.Format("2006-01-02")).
+		Order("doctor_id ASC, start_date ASC").
+		Find(&leaves).Error
+	if err != nil {
+		return nil, err
+	}
+	return leaves, nil
+}
+
+func (r *doctorLeaveRepository) Delete(db *gorm.DB, id int) (int64, error) {
+	result := db.Where("id = ?", id).Delete(&entity.DoctorLeave{})
+	return result.RowsAffected, result.Error
+}
@@ -21,7 +21,7 @@ func (r *doctorProfileRepository) Create(db *gorm.DB, profile *entity.DoctorProf
 
 func (r *doctorProfileRepository) FindByUserID(db *gorm.DB, doctorID uuid.UUID) (*entity.DoctorProfile, error) {
 	var profile entity.DoctorProfile
-	err := db.Preload("User").Where("user_id = ?", doctorID).First(&profile).Error
+	err := db.Preload("User").Preload("Specialization").Where("user_id = ?", doctorID).First(&profile).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, nil
@@ -31,17 +31,67 @@ func (r *doctorProfileRepository) FindByUserID(db *gorm.DB, doctorID uuid.UUID)
 	return &profile, nil
 }
 
-func (r *doctorProfileRepository) FindAll(db *gorm.DB) ([]entity.DoctorProfile, error) {
+// FindAll returns one page of the doctors matched by filter, and the total count
+func (r *doctorProfileRepository) FindAll(db *gorm.DB, filter *entity.DoctorFilter, page, limit int) ([]entity.DoctorProfile, int64, error) {
+	query := db.Model(&entity.DoctorProfile{}).
+		Joins("JOIN users ON users.id = doctor_profiles.user_id")
+	if filter.Specialization != "" {
+		query = query.Where("doctor_profiles.specialization_id IN (SELECT id FROM specializations WHERE slug = ?)", filter.Specialization)
+	}
+	if filter.Active != nil {
+		query = query.Where("users.is_active = ?", *filter.Active)
+	}
+	if filter.Search != "" {
+		query = query.Where("users.full_name ILIKE ?", "%"+filter.Search+"%")
+	}
+	query = query.Session(&gorm.Session{})
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	switch filter.Sort {
+	case entity.DoctorSortNewest:
+		query = query.Order("users.created_at DESC")
+	case entity.DoctorSortFeeAsc:
+		query = query.Order("doctor_profiles.consultation_fee ASC").Order("users.full_name ASC")
+	case entity.DoctorSortFeeDesc:
+		query = query.Order("doctor_profiles.consultation_fee DESC").Order("users.full_name ASC")
+	case entity.DoctorSortRating:
+		query = query.Order("(SELECT AVG(rating) FROM doctor_reviews WHERE doctor_reviews.doctor_id = doctor_profiles.user_id) DESC NULLS LAST").
+			Order("users.full_name ASC")
+	default:
+		query = query.Order("users.full_name ASC")
+	}
+
 	var profiles []entity.DoctorProfile
-	err := db.Preload("User").Find(&profiles).Error
+	// doctor_profiles.user_id keeps the order stable across pages
+	err := query.
+		Order("doctor_profiles.user_id ASC").
+		Preload("User").
+		Preload("Specialization").
+		Scopes(paginate(page, limit)).
+		Find(&profiles).Error
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
-	return profiles, nil
+	return profiles, total, nil
 }
 
+// Update saves the profile and its user; the specialization is only referenced, never saved
 func (r *doctorProfileRepository) Update(db *gorm.DB, profile *entity.DoctorProfile) error {
-	return db.Session(&gorm.Session{FullSaveAssociations: true}).Save(profile).Error
+	return db.Session(&gorm.Session{FullSaveAssociations: true}).Omit("Specialization").Save(profile).Error
+}
+
+// UpdatePhoto sets (or with empty values removes) the profile photo
+func (r *doctorProfileRepository) UpdatePhoto(db *gorm.DB, userID uuid.UUID, photoKey, photoURL string) error {
+	return db.Model(&entity.DoctorProfile{}).
+		Where("user_id = ?", userID).
+		Updates(map[string]interface{}{
+			"photo_key": photoKey,
+			"photo_url": photoURL,
+		}).Error
 }
 
 func (r *doctorProfileRepository) Delete(db *gorm.DB, doctorID uuid.UUID) error {
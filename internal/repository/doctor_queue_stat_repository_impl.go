@@ -0,0 +1,52 @@
+package repository
+
+import (
+	"errors"
+
+	"go-template-clean-architecture/internal/domain/entity"
+	domainRepo "go-template-clean-architecture/internal/domain/repository"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type doctorQueueStatRepository struct{}
+
+func NewDoctorQueueStatRepository() domainRepo.DoctorQueueStatRepository {
+	return &doctorQueueStatRepository{}
+}
+
+// RecordInterval folds a new call interval into the doctor's rolling average in a single upsert.
+// The average moves by 1/min(n, window) of the difference, approximating a moving
+// average over the last DoctorQueueStatWindow intervals.
+func (r *doctorQueueStatRepository) RecordInterval(db *gorm.DB, doctorID uuid.UUID, minutes float64) error {
+	stat := &entity.DoctorQueueStat{
+		DoctorID:           doctorID,
+		AvgMinutesPerQueue: minutes,
+		SampleCount:        1,
+	}
+	return db.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "doctor_id"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"avg_minutes_per_queue": gorm.Expr(
+				"doctor_queue_stats.avg_minutes_per_queue + (EXCLUDED.avg_minutes_per_queue - doctor_queue_stats.avg_minutes_per_queue) / LEAST(doctor_queue_stats.sample_count + 1, ?)",
+				entity.DoctorQueueStatWindow,
+			),
+			"sample_count": gorm.Expr("doctor_queue_stats.sample_count + 1"),
+			"updated_at":   gorm.Expr("CURRENT_TIMESTAMP"),
+		}),
+	}).Create(stat).Error
+}
+
+func (r *doctorQueueStatRepository) FindByDoctorID(db *gorm.DB, doctorID uuid.UUID) (*entity.DoctorQueueStat, error) {
+	var stat entity.DoctorQueueStat
+	err := db.Where("doctor_id = ?", doctorID).First(&stat).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &stat, nil
+}
@@ -0,0 +1,59 @@
+package repository
+
+import (
+	"go-template-clean-architecture/internal/domain/entity"
+	domainRepo "go-template-clean-architecture/internal/domain/repository"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type doctorReviewRepository struct{}
+
+func NewDoctorReviewRepository() domainRepo.DoctorReviewRepository {
+	return &doctorReviewRepository{}
+}
+
+func (r *doctorReviewRepository) Create(db *gorm.DB, review *entity.DoctorReview) error {
+	return db.Create(review).Error
+}
+
+// RatingByDoctorIDs returns the average rating per doctor, doctors without reviews are left out
+func (r *doctorReviewRepository) RatingByDoctorIDs(db *gorm.DB, doctorIDs []uuid.UUID) ([]entity.DoctorRatingStat, error) {
+	var stats []entity.DoctorRatingStat
+	if len(doctorIDs) == 0 {
+		return stats, nil
+	}
+
+	err := db.Model(&entity.DoctorReview{}).
+		Select("doctor_id, AVG(rating) as average_rating, COUNT(*) as review_count").
+		Where("doctor_id IN ?", doctorIDs).
+		Group("doctor_id").
+		Scan(&stats).Error
+	if err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
+
+// FindByDoctorID returns one page of the doctor's reviews with their author, newest first
+func (r *doctorReviewRepository) FindByDoctorID(db *gorm.DB, doctorID uuid.UUID, page, limit int) ([]entity.DoctorReviewItem, int64, error) {
+	query := db.Model(&entity.DoctorReview{}).Where("doctor_reviews.doctor_id = ?", doctorID)
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var items []entity.DoctorReviewItem
+	err := query.
+		Select("doctor_reviews.rating, COALESCE(doctor_reviews.comment, '') as comment, users.full_name as reviewer_name, users.erased_at IS NOT NULL as erased, doctor_reviews.created_at").
+		Joins("JOIN users ON users.id = doctor_reviews.patient_id").
+		Order("doctor_reviews.created_at DESC").
+		Scopes(paginate(page, limit)).
+		Scan(&items).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	return items, total, nil
+}
@@ -2,12 +2,14 @@ package repository
 
 import (
 	"errors"
+	"time"
 
 	"go-template-clean-architecture/internal/domain/entity"
 	domainRepo "go-template-clean-architecture/internal/domain/repository"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type doctorScheduleRepository struct{}
@@ -22,7 +24,7 @@ func (r *doctorScheduleRepository) Create(db *gorm.DB, schedule *entity.DoctorSc
 
 func (r *doctorScheduleRepository) FindByID(db *gorm.DB, id int) (*entity.DoctorSchedule, error) {
 	var schedule entity.DoctorSchedule
-	err := db.Preload("Doctor.User").Where("id = ?", id).First(&schedule).Error
+	err := db.Preload("Doctor.User").Preload("Doctor.Specialization").Where("id = ?", id).First(&schedule).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, nil
@@ -32,50 +34,75 @@ func (r *doctorScheduleRepository) FindByID(db *gorm.DB, id int) (*entity.Doctor
 	return &schedule, nil
 }
 
-func (r *doctorScheduleRepository) FindByDoctorID(db *gorm.DB, doctorID uuid.UUID) ([]entity.DoctorSchedule, error) {
+// FindByDoctorID returns one page of a doctor's schedules and the total count.
+func (r *doctorScheduleRepository) FindByDoctorID(db *gorm.DB, doctorID uuid.UUID, page, limit int) ([]entity.DoctorSchedule, int64, error) {
+	query := db.Model(&entity.DoctorSchedule{}).Where("doctor_id = ?", doctorID)
+	return findSchedulePage(query, false, page, limit)
+}
+
+// FindAll returns one page of the schedules of all doctors (including deactivated ones)
+// and the total count. Supports the same optional filters as FindAllWithActiveDoctor.
+func (r *doctorScheduleRepository) FindAll(db *gorm.DB, filter *entity.ScheduleFilter, page, limit int) ([]entity.DoctorSchedule, int64, error) {
+	query := db.Model(&entity.DoctorSchedule{}).
+		Joins("JOIN doctor_profiles ON doctor_profiles.user_id = doctor_schedules.doctor_id").
+		Joins("JOIN users ON users.id = doctor_profiles.user_id")
+
+	return findSchedulePage(applyScheduleFilter(query, filter), true, page, limit)
+}
+
+// FindAllWithActiveDoctor returns one page of approved schedules for doctors whose user account
+// is active, and the total count. Supports optional filters: date range, doctor name, and specialization.
+func (r *doctorScheduleRepository) FindAllWithActiveDoctor(db *gorm.DB, filter *entity.ScheduleFilter, page, limit int) ([]entity.DoctorSchedule, int64, error) {
+	query := db.Model(&entity.DoctorSchedule{}).
+		Joins("JOIN doctor_profiles ON doctor_profiles.user_id = doctor_schedules.doctor_id").
+		Joins("JOIN users ON users.id = doctor_profiles.user_id").
+		Where("users.is_active = ?", true).
+		Where("doctor_schedules.approval_status = ?", entity.ScheduleApprovalApproved)
+
+	return findSchedulePage(applyScheduleFilter(query, filter), true, page, limit)
+}
+
+// FindByDoctorAndDateRange returns a doctor's schedules between from and to (inclusive dates).
+func (r *doctorScheduleRepository) FindByDoctorAndDateRange(db *gorm.DB, doctorID uuid.UUID, from, to time.Time) ([]entity.DoctorSchedule, error) {
 	var schedules []entity.DoctorSchedule
-	err := db.Where("doctor_id = ?", doctorID).Order("schedule_date ASC, start_time ASC").Find(&schedules).Error
+	err := db.
+		Where("doctor_id = ? AND schedule_date BETWEEN ? AND ?", doctorID, from.Format("2006-01-02"), to.Format("2006-01-02")).
+		Order("schedule_date ASC, start_time ASC").
+		Find(&schedules).Error
 	if err != nil {
 		return nil, err
 	}
 	return schedules, nil
 }
 
-func (r *doctorScheduleRepository) FindAll(db *gorm.DB) ([]entity.DoctorSchedule, error) {
+// FindApprovedByActiveDoctorAndDateRange returns the approved schedules of an active doctor
+// between from and to (inclusive dates). Nothing is returned for an inactive doctor.
+func (r *doctorScheduleRepository) FindApprovedByActiveDoctorAndDateRange(db *gorm.DB, doctorID uuid.UUID, from, to time.Time) ([]entity.DoctorSchedule, error) {
 	var schedules []entity.DoctorSchedule
-	err := db.Preload("Doctor").Preload("Doctor.User").Order("schedule_date ASC, start_time ASC").Find(&schedules).Error
+	err := db.
+		Joins("JOIN users ON users.id = doctor_schedules.doctor_id").
+		Where("users.is_active = ?", true).
+		Where("doctor_schedules.doctor_id = ? AND doctor_schedules.schedule_date BETWEEN ? AND ?", doctorID, from.Format("2006-01-02"), to.Format("2006-01-02")).
+		Where("doctor_schedules.approval_status = ?", entity.ScheduleApprovalApproved).
+		Order("doctor_schedules.schedule_date ASC, doctor_schedules.start_time ASC").
+		Find(&schedules).Error
 	if err != nil {
 		return nil, err
 	}
 	return schedules, nil
 }
 
-// FindAllWithActiveDoctor returns schedules only for doctors whose user account is active.
-// Supports optional filters: date range, doctor name, and specialization.
-func (r *doctorScheduleRepository) FindAllWithActiveDoctor(db *gorm.DB, filter *entity.ScheduleFilter) ([]entity.DoctorSchedule, error) {
-	var schedules []entity.DoctorSchedule
-	query := db.
-		Joins("JOIN doctor_profiles ON doctor_profiles.user_id = doctor_schedules.doctor_id").
-		Joins("JOIN users ON users.id = doctor_profiles.user_id").
-		Where("users.is_active = ?", true)
-
-	if filter != nil {
-		if filter.StartAt != "" {
-			query = query.Where("doctor_schedules.schedule_date >= ?", filter.StartAt)
-		}
-		if filter.EndAt != "" {
-			query = query.Where("doctor_schedules.schedule_date <= ?", filter.EndAt)
-		}
-		if filter.DoctorName != "" {
-			query = query.Where("users.full_name ILIKE ?", "%"+filter.DoctorName+"%")
-		}
-		if filter.Specialization != "" {
-			query = query.Where("doctor_profiles.specialization ILIKE ?", "%"+filter.Specialization+"%")
-		}
+// LockByDoctorFromDate returns a doctor's schedules from the given date on (up to to when set,
+// inclusive dates) and locks them with FOR UPDATE until the transaction ends.
+func (r *doctorScheduleRepository) LockByDoctorFromDate(db *gorm.DB, doctorID uuid.UUID, from time.Time, to *time.Time) ([]entity.DoctorSchedule, error) {
+	query := db.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("doctor_id = ? AND schedule_date >= ?", doctorID, from.Format("2006-01-02"))
+	if to != nil {
+		query = query.Where("schedule_date <= ?", to.Format("2006-01-02"))
 	}
 
+	var schedules []entity.DoctorSchedule
 	err := query.
-		Preload("Doctor").Preload("Doctor.User").
 		Order("schedule_date ASC, start_time ASC").
 		Find(&schedules).Error
 	if err != nil {
@@ -84,12 +111,339 @@ func (r *doctorScheduleRepository) FindAllWithActiveDoctor(db *gorm.DB, filter *
 	return schedules, nil
 }
 
+// FindByDateRange returns the schedules of all doctors between from and to (inclusive dates).
+func (r *doctorScheduleRepository) FindByDateRange(db *gorm.DB, from, to time.Time) ([]entity.DoctorSchedule, error) {
+	var schedules []entity.DoctorSchedule
+	err := db.
+		Where("schedule_date BETWEEN ? AND ?", from.Format("2006-01-02"), to.Format("2006-01-02")).
+		Order("schedule_date ASC, start_time ASC").
+		Find(&schedules).Error
+	if err != nil {
+		return nil, err
+	}
+	return schedules, nil
+}
+
+// FindAgendaByDate returns the approved schedules on the date that are not closed by a holiday
+// or a doctor's leave, with the doctor, ordered by doctor and start time.
+func (r *doctorScheduleRepository) FindAgendaByDate(db *gorm.DB, scheduleDate time.Time) ([]entity.DoctorSchedule, error) {
+	var schedules []entity.DoctorSchedule
+	err := db.
+		Where("schedule_date = ? AND approval_status = ?", scheduleDate.Format("2006-01-02"), entity.ScheduleApprovalApproved).
+		Where("holiday_flagged_at IS NULL AND leave_flagged_at IS NULL").
+		Preload("Doctor.User").
+		Order("doctor_id ASC, start_time ASC").
+		Find(&schedules).Error
+	if err != nil {
+		return nil, err
+	}
+	return schedules, nil
+}
+
 func (r *doctorScheduleRepository) Update(db *gorm.DB, schedule *entity.DoctorSchedule) error {
 	return db.Omit("Doctor").Save(schedule).Error
 }
 
+// Delete soft-deletes a schedule
 func (r *doctorScheduleRepository) Delete(db *gorm.DB, id int) (int64, error) {
 	affected := db.Where("id = ?", id).Delete(&entity.DoctorSchedule{})
 	return affected.RowsAffected, affected.Error
+}
+
+// CheckIn records the doctor's check-in ONLY if the schedule belongs to the doctor and is not checked in yet.
+// Returns affected rows: 1 = success, 0 = not found / not owned / already checked in.
+func (r *doctorScheduleRepository) CheckIn(db *gorm.DB, id int, doctorID uuid.UUID, at time.Time) (int64, error) {
+	result := db.Model(&entity.DoctorSchedule{}).
+		Where("id = ? AND doctor_id = ? AND doctor_checked_in_at IS NULL", id, doctorID).
+		Update("doctor_checked_in_at", at)
+	return result.RowsAffected, result.Error
+}
+
+// SetOpen opens or pauses booking on a schedule
+func (r *doctorScheduleRepository) SetOpen(db *gorm.DB, id int, isOpen bool) error {
+	return db.Model(&entity.DoctorSchedule{}).
+		Where("id = ?", id).
+		Update("is_open", isOpen).Error
+}
+
+// FindPendingCheckIn returns approved schedules on the given date that started before the given
+// time without a doctor check-in and that have not been flagged yet.
+func (r *doctorScheduleRepository) FindPendingCheckIn(db *gorm.DB, scheduleDate string, startedBefore string) ([]entity.DoctorSchedule, error) {
+	var schedules []entity.DoctorSchedule
+	err := db.
+		Where("schedule_date = ? AND start_time <= ?", scheduleDate, startedBefore).
+		Where("doctor_checked_in_at IS NULL AND absence_flagged_at IS NULL").
+		Where("approval_status = ?", entity.ScheduleApprovalApproved).
+		Preload("Doctor.User").
+		Preload("Doctor.Specialization").
+		Find(&schedules).Error
+	if err != nil {
+		return nil, err
+	}
+	return schedules, nil
+}
+
+// MarkAbsenceFlagged flags a schedule as doctor possibly absent (idempotent).
+func (r *doctorScheduleRepository) MarkAbsenceFlagged(db *gorm.DB, id int, at time.Time) (int64, error) {
+	result := db.Model(&entity.DoctorSchedule{}).
+		Where("id = ? AND doctor_checked_in_at IS NULL AND absence_flagged_at IS NULL", id).
+		Update("absence_flagged_at", at)
+	return result.RowsAffected, result.Error
+}
+
+// FindPossiblyAbsent returns flagged schedules where the doctor still has not checked in.
+func (r *doctorScheduleRepository) FindPossiblyAbsent(db *gorm.DB) ([]entity.DoctorSchedule, error) {
+	var schedules []entity.DoctorSchedule
+	err := db.
+		Where("absence_flagged_at IS NOT NULL AND doctor_checked_in_at IS NULL").
+		Preload("Doctor.User").
+		Preload("Doctor.Specialization").
+		Order("schedule_date DESC, start_time ASC").
+		Find(&schedules).Error
+	if err != nil {
+		return nil, err
+	}
+	return schedules, nil
+}
 
+// MarkHolidayFlagged flags every schedule on the given date as falling on a holiday (idempotent).
+func (r *doctorScheduleRepository) MarkHolidayFlagged(db *gorm.DB, scheduleDate time.Time, at time.Time) (int64, error) {
+	result := db.Model(&entity.DoctorSchedule{}).
+		Where("schedule_date = ? AND holiday_flagged_at IS NULL", scheduleDate.Format("2006-01-02")).
+		Update("holiday_flagged_at", at)
+	return result.RowsAffected, result.Error
+}
+
+// ClearHolidayFlag removes the holiday flag from schedules on the given date.
+func (r *doctorScheduleRepository) ClearHolidayFlag(db *gorm.DB, scheduleDate time.Time) (int64, error) {
+	result := db.Model(&entity.DoctorSchedule{}).
+		Where("schedule_date = ? AND holiday_flagged_at IS NOT NULL", scheduleDate.Format("2006-01-02")).
+		Update("holiday_flagged_at", nil)
+	return result.RowsAffected, result.Error
+}
+
+// FindHolidayFlagged returns schedules that fall on a holiday and still need admin action.
+func (r *doctorScheduleRepository) FindHolidayFlagged(db *gorm.DB) ([]entity.DoctorSchedule, error) {
+	var schedules []entity.DoctorSchedule
+	err := db.
+		Where("holiday_flagged_at IS NOT NULL").
+		Preload("Doctor.User").
+		Preload("Doctor.Specialization").
+		Order("schedule_date ASC, start_time ASC").
+		Find(&schedules).Error
+	if err != nil {
+		return nil, err
+	}
+	return schedules, nil
+}
+
+// MarkLeaveFlagged flags the given schedules as falling in a leave of their doctor and closes
+// them for booking (idempotent).
+func (r *doctorScheduleRepository) MarkLeaveFlagged(db *gorm.DB, ids []int, at time.Time) (int64, error) {
+	if len(ids) == 0 {
+		return 0, nil
+	}
+	result := db.Model(&entity.DoctorSchedule{}).
+		Where("id IN ? AND leave_flagged_at IS NULL", ids).
+		Updates(map[string]interface{}{
+			"leave_flagged_at": at,
+			"is_open":          false,
+		})
+	return result.RowsAffected, result.Error
+}
+
+// ClearLeaveFlag removes the leave flag from the given schedules and reopens them for booking.
+// Schedules without the flag are left untouched.
+func (r *doctorScheduleRepository) ClearLeaveFlag(db *gorm.DB, ids []int) (int64, error) {
+	if len(ids) == 0 {
+		return 0, nil
+	}
+	result := db.Model(&entity.DoctorSchedule{}).
+		Where("id IN ? AND leave_flagged_at IS NOT NULL", ids).
+		Updates(map[string]interface{}{
+			"leave_flagged_at": nil,
+			"is_open":          true,
+		})
+	return result.RowsAffected, result.Error
+}
+
+// FindLeaveFlagged returns schedules that fall in a leave of their doctor and still need admin action.
+func (r *doctorScheduleRepository) FindLeaveFlagged(db *gorm.DB) ([]entity.DoctorSchedule, error) {
+	var schedules []entity.DoctorSchedule
+	err := db.
+		Where("leave_flagged_at IS NOT NULL").
+		Preload("Doctor.User").
+		Preload("Doctor.Specialization").
+		Order("schedule_date ASC, start_time ASC").
+		Find(&schedules).Error
+	if err != nil {
+		return nil, err
+	}
+	return schedules, nil
+}
+
+// FindPendingApproval returns one page of the schedules proposed by doctors that wait for an
+// admin decision, and the total count.
+func (r *doctorScheduleRepository) FindPendingApproval(db *gorm.DB, page, limit int) ([]entity.DoctorSchedule, int64, error) {
+	query := db.Model(&entity.DoctorSchedule{}).
+		Where("approval_status = ?", entity.ScheduleApprovalPending)
+	return findSchedulePage(query, true, page, limit)
+}
+
+// Review records an admin decision ONLY if the schedule is still pending approval.
+// Returns affected rows: 1 = success, 0 = not found / already reviewed.
+func (r *doctorScheduleRepository) Review(db *gorm.DB, id int, status string, reviewedBy uuid.UUID, reason string, at time.Time) (int64, error) {
+	result := db.Model(&entity.DoctorSchedule{}).
+		Where("id = ? AND approval_status = ?", id, entity.ScheduleApprovalPending).
+		Updates(map[string]interface{}{
+			"approval_status":  status,
+			"reviewed_by":      reviewedBy,
+			"reviewed_at":      at,
+			"rejection_reason": reason,
+		})
+	return result.RowsAffected, result.Error
+}
+
+// UtilizationStats aggregates the bookings of every approved schedule matched by filter in one query.
+// endedBefore (YYYY-MM-DD HH:MM:SS, local) decides which schedules have ended: only their
+// uncalled bookings count as no-shows.
+func (r *doctorScheduleRepository) UtilizationStats(db *gorm.DB, filter *entity.ScheduleFilter, endedBefore string) ([]entity.ScheduleUtilizationStat, error) {
+	var stats []entity.ScheduleUtilizationStat
+	query := db.Model(&entity.DoctorSchedule{}).
+		Select(`
+			doctor_schedules.id as schedule_id,
+			doctor_schedules.doctor_id,
+			users.full_name as doctor_name,
+			doctor_schedules.schedule_date,
+			doctor_schedules.start_time,
+			doctor_schedules.end_time,
+			doctor_schedules.total_quota,
+			COUNT(CASE WHEN bookings.status != ? THEN 1 END) as booked,
+			COUNT(CASE WHEN bookings.status = ? THEN 1 END) as cancelled,
+			COUNT(CASE WHEN bookings.status != ? AND bookings.called_at IS NULL
+				AND doctor_schedules.schedule_date + doctor_schedules.end_time <= ? THEN 1 END) as no_show
+		`, entity.BookingStatusCancelled, entity.BookingStatusCancelled, entity.BookingStatusCancelled, endedBefore).
+		Joins("JOIN doctor_profiles ON doctor_profiles.user_id = doctor_schedules.doctor_id").
+		Joins("JOIN users ON users.id = doctor_profiles.user_id").
+		Joins("LEFT JOIN bookings ON bookings.schedule_id = doctor_schedules.id AND bookings.deleted_at IS NULL").
+		Where("doctor_schedules.approval_status = ?", entity.ScheduleApprovalApproved)
+
+	err := applyScheduleFilter(query, filter).
+		Group("doctor_schedules.id, users.full_name").
+		Order("doctor_schedules.schedule_date ASC, doctor_schedules.start_time ASC, doctor_schedules.id ASC").
+		Scan(&stats).Error
+	if err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
+
+// DoctorPeriodStats aggregates a doctor's approved schedules between from and to (YYYY-MM-DD,
+// inclusive) per period (day, week or month) in one query. endedBefore (YYYY-MM-DD HH:MM:SS, local)
+// decides which schedules have ended: only their uncalled bookings count as no-shows.
+// Consultation time is the interval between consecutive queue calls of a schedule; intervals
+// longer than maxCallInterval (e.g. the doctor stepped out) are left out.
+func (r *doctorScheduleRepository) DoctorPeriodStats(db *gorm.DB, doctorID uuid.UUID, from, to, period, endedBefore string, maxCallInterval time.Duration) ([]entity.DoctorPeriodStat, error) {
+	var stats []entity.DoctorPeriodStat
+	err := db.Raw(`
+		WITH schedules AS (
+			SELECT id, schedule_date, end_time, total_quota
+			FROM doctor_schedules
+			WHERE doctor_id = ? AND approval_status = ? AND schedule_date BETWEEN ? AND ? AND deleted_at IS NULL
+		),
+		booking_counts AS (
+			SELECT s.id AS schedule_id,
+				COUNT(CASE WHEN b.status != ? THEN 1 END) AS booked,
+				COUNT(CASE WHEN b.status != ? AND b.called_at IS NOT NULL THEN 1 END) AS served,
+				COUNT(CASE WHEN b.status = ? THEN 1 END) AS cancelled,
+				COUNT(CASE WHEN b.status != ? AND b.called_at IS NULL
+					AND s.schedule_date + s.end_time <= ? THEN 1 END) AS no_show
+			FROM schedules s
+			LEFT JOIN bookings b ON b.schedule_id = s.id AND b.deleted_at IS NULL
+			GROUP BY s.id
+		),
+		call_intervals AS (
+			SELECT b.schedule_id,
+				EXTRACT(EPOCH FROM b.called_at - LAG(b.called_at) OVER (PARTITION BY b.schedule_id ORDER BY b.called_at)) / 60 AS minutes
+			FROM bookings b
+			JOIN schedules s ON s.id = b.schedule_id
+			WHERE b.called_at IS NOT NULL AND b.deleted_at IS NULL
+		),
+		consultations AS (
+			SELECT schedule_id, SUM(minutes) AS minutes, COUNT(*) AS samples
+			FROM call_intervals
+			WHERE minutes > 0 AND minutes <= ?
+			GROUP BY schedule_id
+		)
+		SELECT date_trunc(?, s.schedule_date)::date AS period_start,
+			COUNT(*) AS schedules,
+			SUM(s.total_quota) AS total_quota,
+			SUM(c.booked) AS booked,
+			SUM(c.served) AS served,
+			SUM(c.cancelled) AS cancelled,
+			SUM(c.no_show) AS no_show,
+			COALESCE(SUM(cs.minutes), 0) AS consultation_minutes,
+			COALESCE(SUM(cs.samples), 0) AS consultation_samples
+		FROM schedules s
+		JOIN booking_counts c ON c.schedule_id = s.id
+		LEFT JOIN consultations cs ON cs.schedule_id = s.id
+		GROUP BY 1
+		ORDER BY 1
+	`,
+		doctorID, entity.ScheduleApprovalApproved, from, to,
+		entity.BookingStatusCancelled, entity.BookingStatusCancelled, entity.BookingStatusCancelled, entity.BookingStatusCancelled, endedBefore,
+		maxCallInterval.Minutes(),
+		period,
+	).Scan(&stats).Error
+	if err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
+
+// applyScheduleFilter adds the optional ScheduleFilter conditions.
+// The query must already join doctor_profiles and users.
+func applyScheduleFilter(query *gorm.DB, filter *entity.ScheduleFilter) *gorm.DB {
+	if filter == nil {
+		return query
+	}
+	if filter.StartAt != "" {
+		query = query.Where("doctor_schedules.schedule_date >= ?", filter.StartAt)
+	}
+	if filter.EndAt != "" {
+		query = query.Where("doctor_schedules.schedule_date <= ?", filter.EndAt)
+	}
+	if filter.DoctorName != "" {
+		query = query.Where("users.full_name ILIKE ?", "%"+filter.DoctorName+"%")
+	}
+	if filter.Specialization != "" {
+		query = query.Where("doctor_profiles.specialization_id IN (SELECT id FROM specializations WHERE slug = ?)", filter.Specialization)
+	}
+	return query
+}
+
+// findSchedulePage counts the schedules matched by query, then loads the requested page
+// ordered by date and start time (with the doctor preloaded when withDoctor is set).
+func findSchedulePage(query *gorm.DB, withDoctor bool, page, limit int) ([]entity.DoctorSchedule, int64, error) {
+	query = query.Session(&gorm.Session{})
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if withDoctor {
+		query = query.Preload("Doctor").Preload("Doctor.User").Preload("Doctor.Specialization")
+	}
+
+	var schedules []entity.DoctorSchedule
+	// doctor_schedules.id keeps the order stable across pages
+	err := query.
+		Order("schedule_date ASC, start_time ASC, doctor_schedules.id ASC").
+		Scopes(paginate(page, limit)).
+		Find(&schedules).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	return schedules, total, nil
 }
@@ -0,0 +1,57 @@
+package repository
+
+import (
+	"testing"
+
+	"go-template-clean-architecture/internal/domain/entity"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// newDryRunDB returns a PostgreSQL session that builds statements without sending them,
+// so the benchmarks measure the query building of the repository and not the database
+func newDryRunDB(b *testing.B) *gorm.DB {
+	b.Helper()
+
+	db, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=localhost dbname=benchmark sslmode=disable"}), &gorm.Config{
+		DryRun:               true,
+		DisableAutomaticPing: true,
+		Logger:               logger.Discard,
+	})
+	if err != nil {
+		b.Fatalf("open dry run db: %v", err)
+	}
+	return db
+}
+
+// BenchmarkFindAllWithActiveDoctor measures building the public schedule list query
+// (count and page) with and without filters
+func BenchmarkFindAllWithActiveDoctor(b *testing.B) {
+	db := newDryRunDB(b)
+	repo := NewDoctorScheduleRepository()
+
+	filters := []struct {
+		name   string
+		filter *entity.ScheduleFilter
+	}{
+		{name: "unfiltered"},
+		{name: "filtered", filter: &entity.ScheduleFilter{
+			StartAt:        "2026-01-01",
+			EndAt:          "2026-01-31",
+			DoctorName:     "benchmark",
+			Specialization: "cardiology",
+		}},
+	}
+	for _, f := range filters {
+		b.Run(f.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if _, _, err := repo.FindAllWithActiveDoctor(db, f.filter, 2, 20); err != nil {
+					b.Fatalf("FindAllWithActiveDoctor: %v", err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,73 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"go-template-clean-architecture/internal/domain/entity"
+	domainRepo "go-template-clean-architecture/internal/domain/repository"
+
+	"gorm.io/gorm"
+)
+
+type holidayRepository struct{}
+
+func NewHolidayRepository() domainRepo.HolidayRepository {
+	return &holidayRepository{}
+}
+
+func (r *holidayRepository) Create(db *gorm.DB, holiday *entity.Holiday) error {
+	return db.Create(holiday).Error
+}
+
+func (r *holidayRepository) FindByID(db *gorm.DB, id int) (*entity.Holiday, error) {
+	var holiday entity.Holiday
+	err := db.Where("id = ?", id).First(&holiday).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &holiday, nil
+}
+
+func (r *holidayRepository) FindByDate(db *gorm.DB, date time.Time) (*entity.Holiday, error) {
+	var holiday entity.Holiday
+	err := db.Where("holiday_date = ?", date.Format("2006-01-02")).First(&holiday).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &holiday, nil
+}
+
+// FindAll returns holidays ordered by date, optionally within a date range (YYYY-MM-DD).
+func (r *holidayRepository) FindAll(db *gorm.DB, from, to string) ([]entity.Holiday, error) {
+	var holidays []entity.Holiday
+	query := db.Model(&entity.Holiday{})
+
+	if from != "" {
+		query = query.Where("holiday_date >= ?", from)
+	}
+	if to != "" {
+		query = query.Where("holiday_date <= ?", to)
+	}
+
+	err := query.Order("holiday_date ASC").Find(&holidays).Error
+	if err != nil {
+		return nil, err
+	}
+	return holidays, nil
+}
+
+func (r *holidayRepository) Update(db *gorm.DB, holiday *entity.Holiday) error {
+	return db.Save(holiday).Error
+}
+
+func (r *holidayRepository) Delete(db *gorm.DB, id int) (int64, error) {
+	result := db.Where("id = ?", id).Delete(&entity.Holiday{})
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,128 @@
+package repository
+
+import (
+	"errors"
+
+	"go-template-clean-architecture/internal/domain/entity"
+	domainRepo "go-template-clean-architecture/internal/domain/repository"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type invoiceRepository struct{}
+
+func NewInvoiceRepository() domainRepo.InvoiceRepository {
+	return &invoiceRepository{}
+}
+
+// NextNumber takes the next invoice number of the year. The sequence row stays locked
+// until the transaction ends, and a rolled back invoice gives its number back, so the
+// numbers have no gaps.
+func (r *invoiceRepository) NextNumber(db *gorm.DB, year int) (int64, error) {
+	var number int64
+	err := db.Raw(`
+		INSERT INTO invoice_sequences (year, last_number) VALUES (?, 1)
+		ON CONFLICT (year) DO UPDATE SET last_number = invoice_sequences.last_number + 1
+		RETURNING last_number
+	`, year).Scan(&number).Error
+	return number, err
+}
+
+// Create inserts the invoice with its items
+func (r *invoiceRepository) Create(db *gorm.DB, invoice *entity.Invoice) error {
+	return db.Create(invoice).Error
+}
+
+func (r *invoiceRepository) FindByID(db *gorm.DB, id uuid.UUID) (*entity.Invoice, error) {
+	var invoice entity.Invoice
+	err := db.Preload("Items", func(db *gorm.DB) *gorm.DB {
+		return db.Order("id ASC")
+	}).Where("id = ?", id).First(&invoice).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &invoice, nil
+}
+
+func (r *invoiceRepository) FindByBookingID(db *gorm.DB, bookingID uuid.UUID) (*entity.Invoice, error) {
+	var invoice entity.Invoice
+	err := db.Preload("Items", func(db *gorm.DB) *gorm.DB {
+		return db.Order("id ASC")
+	}).Where("booking_id = ?", bookingID).First(&invoice).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &invoice, nil
+}
+
+// MarkRefunded marks the paid invoice of a refunded payment.
+// Returns affected rows: 0 = the payment was not invoiced, or is refunded already.
+func (r *invoiceRepository) MarkRefunded(db *gorm.DB, paymentID uuid.UUID) (int64, error) {
+	result := db.Model(&entity.Invoice{}).
+		Where("payment_id = ? AND status = ?", paymentID, entity.InvoiceStatusPaid).
+		Update("status", entity.InvoiceStatusRefunded)
+	return result.RowsAffected, result.Error
+}
+
+// FindAll lists invoices newest first, with their items
+func (r *invoiceRepository) FindAll(db *gorm.DB, filter *entity.InvoiceFilter, page, limit int) ([]entity.Invoice, int64, error) {
+	query := r.filtered(db.Model(&entity.Invoice{}), filter)
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var invoices []entity.Invoice
+	err := query.Preload("Items", func(db *gorm.DB) *gorm.DB {
+		return db.Order("id ASC")
+	}).Order("issued_at DESC").
+		Scopes(paginate(page, limit)).
+		Find(&invoices).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	return invoices, total, nil
+}
+
+// Totals sums the invoices matching the filter per currency and status
+func (r *invoiceRepository) Totals(db *gorm.DB, filter *entity.InvoiceFilter) ([]entity.InvoiceTotal, error) {
+	var totals []entity.InvoiceTotal
+	err := r.filtered(db.Table("invoices"), filter).
+		Select(`invoices.currency, invoices.status,
+			COUNT(*) AS invoices,
+			COALESCE(SUM(invoices.total), 0) AS total,
+			COALESCE(SUM((SELECT SUM(amount) FROM invoice_items WHERE invoice_id = invoices.id AND type = ?)), 0) AS consultation_fees,
+			COALESCE(SUM((SELECT SUM(amount) FROM invoice_items WHERE invoice_id = invoices.id AND type = ?)), 0) AS admin_fees`,
+			entity.InvoiceItemConsultationFee, entity.InvoiceItemAdminFee).
+		Group("invoices.currency, invoices.status").
+		Order("invoices.currency, invoices.status").
+		Scan(&totals).Error
+	return totals, err
+}
+
+func (r *invoiceRepository) filtered(query *gorm.DB, filter *entity.InvoiceFilter) *gorm.DB {
+	if filter.Status != "" {
+		query = query.Where("invoices.status = ?", filter.Status)
+	}
+	if filter.DoctorID != nil {
+		query = query.Where("invoices.doctor_id = ?", *filter.DoctorID)
+	}
+	if filter.PatientID != nil {
+		query = query.Where("invoices.patient_id = ?", *filter.PatientID)
+	}
+	if filter.From != nil {
+		query = query.Where("invoices.issued_at >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		query = query.Where("invoices.issued_at < ?", *filter.To)
+	}
+	return query
+}
@@ -0,0 +1,55 @@
+package repository
+
+import (
+	"go-template-clean-architecture/internal/domain/entity"
+	domainRepo "go-template-clean-architecture/internal/domain/repository"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type loginHistoryRepository struct{}
+
+func NewLoginHistoryRepository() domainRepo.LoginHistoryRepository {
+	return &loginHistoryRepository{}
+}
+
+func (r *loginHistoryRepository) Create(db *gorm.DB, entry *entity.LoginHistory) error {
+	return db.Create(entry).Error
+}
+
+// FindByUserID returns one page of the login attempts of the user, newest first
+func (r *loginHistoryRepository) FindByUserID(db *gorm.DB, userID uuid.UUID, page, limit int) ([]entity.LoginHistory, int64, error) {
+	query := db.Model(&entity.LoginHistory{}).Where("user_id = ?", userID)
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var entries []entity.LoginHistory
+	err := query.Order("created_at DESC").
+		Offset((page - 1) * limit).
+		Limit(limit).
+		Find(&entries).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	return entries, total, nil
+}
+
+// CountSuccessful returns the successful logins of the user, in total and from the user agent
+func (r *loginHistoryRepository) CountSuccessful(db *gorm.DB, userID uuid.UUID, userAgent string) (int64, int64, error) {
+	var counts struct {
+		Total         int64
+		FromUserAgent int64
+	}
+	err := db.Model(&entity.LoginHistory{}).
+		Select("COUNT(*) AS total, COUNT(*) FILTER (WHERE user_agent = ?) AS from_user_agent", userAgent).
+		Where("user_id = ? AND success", userID).
+		Scan(&counts).Error
+	if err != nil {
+		return 0, 0, err
+	}
+	return counts.Total, counts.FromUserAgent, nil
+}
@@ -0,0 +1,100 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"go-template-clean-architecture/internal/domain/entity"
+	domainRepo "go-template-clean-architecture/internal/domain/repository"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type medicalRecordRepository struct{}
+
+func NewMedicalRecordRepository() domainRepo.MedicalRecordRepository {
+	return &medicalRecordRepository{}
+}
+
+func (r *medicalRecordRepository) Create(db *gorm.DB, record *entity.MedicalRecord) error {
+	return db.Create(record).Error
+}
+
+// Update saves the notes and vitals of the record at the given version (optimistic lock)
+// and increments it. Returns 0 rows affected when the record changed in the meantime.
+func (r *medicalRecordRepository) Update(db *gorm.DB, record *entity.MedicalRecord, version int) (int64, error) {
+	vitals := record.Vitals
+	now := time.Now()
+	result := db.Model(&entity.MedicalRecord{}).
+		Where("booking_id = ? AND version = ?", record.BookingID, version).
+		Updates(map[string]interface{}{
+			"diagnosis":         record.Diagnosis,
+			"clinical_notes":    record.ClinicalNotes,
+			"systolic_bp":       vitals.SystolicBP,
+			"diastolic_bp":      vitals.DiastolicBP,
+			"heart_rate":        vitals.HeartRate,
+			"respiratory_rate":  vitals.RespiratoryRate,
+			"temperature":       vitals.Temperature,
+			"oxygen_saturation": vitals.OxygenSaturation,
+			"weight_kg":         vitals.WeightKg,
+			"height_cm":         vitals.HeightCm,
+			"version":           gorm.Expr("version + 1"),
+			"updated_at":        now,
+		})
+	if result.Error != nil {
+		return 0, result.Error
+	}
+	if result.RowsAffected > 0 {
+		record.Version = version + 1
+		record.UpdatedAt = now
+	}
+	return result.RowsAffected, nil
+}
+
+// FindByBookingID returns the record with its booking, schedule and doctor, or nil if the
+// booking has no record
+func (r *medicalRecordRepository) FindByBookingID(db *gorm.DB, bookingID uuid.UUID) (*entity.MedicalRecord, error) {
+	var record entity.MedicalRecord
+	err := db.Preload("Booking.Schedule.Doctor.User").Where("booking_id = ?", bookingID).First(&record).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &record, nil
+}
+
+// FindByPatientID returns one page of the patient's records, newest first
+func (r *medicalRecordRepository) FindByPatientID(db *gorm.DB, patientID uuid.UUID, page, limit int) ([]entity.MedicalRecord, int64, error) {
+	query := db.Model(&entity.MedicalRecord{}).Where("patient_id = ?", patientID)
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var records []entity.MedicalRecord
+	err := query.Preload("Booking.Schedule.Doctor.User").
+		Order("created_at DESC").
+		Scopes(paginate(page, limit)).
+		Find(&records).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	return records, total, nil
+}
+
+// FindByBookingIDs returns the records of the given bookings (without their booking)
+func (r *medicalRecordRepository) FindByBookingIDs(db *gorm.DB, bookingIDs []uuid.UUID) ([]entity.MedicalRecord, error) {
+	var records []entity.MedicalRecord
+	if len(bookingIDs) == 0 {
+		return records, nil
+	}
+	err := db.Where("booking_id IN ?", bookingIDs).Find(&records).Error
+	if err != nil {
+		return nil, err
+	}
+	return records, nil
+}
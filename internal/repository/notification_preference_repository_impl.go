@@ -0,0 +1,51 @@
+package repository
+
+import (
+	"errors"
+
+	"go-template-clean-architecture/internal/domain/entity"
+	domainRepo "go-template-clean-architecture/internal/domain/repository"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type notificationPreferenceRepository struct{}
+
+func NewNotificationPreferenceRepository() domainRepo.NotificationPreferenceRepository {
+	return &notificationPreferenceRepository{}
+}
+
+func (r *notificationPreferenceRepository) FindByUserID(db *gorm.DB, userID uuid.UUID) (*entity.NotificationPreference, error) {
+	var preference entity.NotificationPreference
+	err := db.Where("user_id = ?", userID).First(&preference).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &preference, nil
+}
+
+// FindByUserIDs returns the stored preferences among the users, users on the defaults have none
+func (r *notificationPreferenceRepository) FindByUserIDs(db *gorm.DB, userIDs []uuid.UUID) ([]entity.NotificationPreference, error) {
+	var preferences []entity.NotificationPreference
+	if len(userIDs) == 0 {
+		return preferences, nil
+	}
+	err := db.Where("user_id IN ?", userIDs).Find(&preferences).Error
+	if err != nil {
+		return nil, err
+	}
+	return preferences, nil
+}
+
+// Upsert stores the user's preference, replacing the previous one
+func (r *notificationPreferenceRepository) Upsert(db *gorm.DB, preference *entity.NotificationPreference) error {
+	return db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"channel", "reminders", "agenda_digest", "updated_at"}),
+	}).Create(preference).Error
+}
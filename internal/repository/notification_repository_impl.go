@@ -0,0 +1,310 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"go-template-clean-architecture/internal/domain/entity"
+	domainRepo "go-template-clean-architecture/internal/domain/repository"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type notificationRepository struct{}
+
+func NewNotificationRepository() domainRepo.NotificationRepository {
+	return &notificationRepository{}
+}
+
+// CreateIfAbsent inserts the notification unless one with the same dedupe key exists.
+// Returns false when it already existed (source event redelivered).
+func (r *notificationRepository) CreateIfAbsent(db *gorm.DB, notification *entity.Notification) (bool, error) {
+	result := db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "dedupe_key"}},
+		DoNothing: true,
+	}).Create(notification)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// ClaimDue locks due pending notifications with FOR UPDATE SKIP LOCKED, so several
+// API instances can run the worker without sending the same notification twice.
+// Must be called inside a transaction, followed by Lease.
+func (r *notificationRepository) ClaimDue(db *gorm.DB, now time.Time, limit int) ([]entity.Notification, error) {
+	var notifications []entity.Notification
+	err := db.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+		Where("status = ? AND next_attempt_at <= ?", entity.NotificationStatusPending, now).
+		Order("next_attempt_at ASC, created_at ASC").
+		Limit(limit).
+		Find(&notifications).Error
+	if err != nil {
+		return nil, err
+	}
+	return notifications, nil
+}
+
+// Lease postpones the next attempt of claimed notifications while they are being sent
+func (r *notificationRepository) Lease(db *gorm.DB, ids []uuid.UUID, until time.Time) error {
+	return db.Model(&entity.Notification{}).
+		Where("id IN ?", ids).
+		Update("next_attempt_at", until).Error
+}
+
+func (r *notificationRepository) MarkSent(db *gorm.DB, id uuid.UUID, attempts int, provider string, providerMessageID string, at time.Time) error {
+	return db.Model(&entity.Notification{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"status":              entity.NotificationStatusSent,
+			"attempts":            attempts,
+			"provider":            provider,
+			"provider_message_id": providerMessageID,
+			"sent_at":             at,
+			"last_error":          "",
+		}).Error
+}
+
+func (r *notificationRepository) MarkRetry(db *gorm.DB, id uuid.UUID, attempts int, nextAttemptAt time.Time, lastError string) error {
+	return db.Model(&entity.Notification{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"attempts":        attempts,
+			"next_attempt_at": nextAttemptAt,
+			"last_error":      lastError,
+		}).Error
+}
+
+// MarkDeadLetter gives up on a notification the provider rejected or that ran out of attempts
+func (r *notificationRepository) MarkDeadLetter(db *gorm.DB, id uuid.UUID, attempts int, lastError string, at time.Time) error {
+	return db.Model(&entity.Notification{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"status":     entity.NotificationStatusDeadLetter,
+			"attempts":   attempts,
+			"last_error": lastError,
+			"failed_at":  at,
+		}).Error
+}
+
+func (r *notificationRepository) FindByID(db *gorm.DB, id uuid.UUID) (*entity.Notification, error) {
+	var notification entity.Notification
+	err := db.Where("id = ?", id).First(&notification).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &notification, nil
+}
+
+func (r *notificationRepository) FindByProviderMessageID(db *gorm.DB, provider string, providerMessageID string) (*entity.Notification, error) {
+	var notification entity.Notification
+	err := db.Where("provider = ? AND provider_message_id = ?", provider, providerMessageID).First(&notification).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &notification, nil
+}
+
+// MarkDeliveryReport records a provider delivery callback (delivered, failed or bounced).
+// Only sent notifications are updated, so repeated or out-of-order callbacks never
+// overwrite a final status. Returns affected rows: 0 = already final.
+func (r *notificationRepository) MarkDeliveryReport(db *gorm.DB, id uuid.UUID, status entity.NotificationStatus, lastError string, at time.Time) (int64, error) {
+	updates := map[string]interface{}{
+		"status":     status,
+		"last_error": lastError,
+	}
+	if status == entity.NotificationStatusDelivered {
+		updates["delivered_at"] = at
+	} else {
+		updates["failed_at"] = at
+	}
+
+	result := db.Model(&entity.Notification{}).
+		Where("id = ? AND status = ?", id, entity.NotificationStatusSent).
+		Updates(updates)
+	return result.RowsAffected, result.Error
+}
+
+// FindByBookingID returns the notifications of a booking, oldest first
+func (r *notificationRepository) FindByBookingID(db *gorm.DB, bookingID uuid.UUID) ([]entity.Notification, error) {
+	var notifications []entity.Notification
+	err := db.Where("booking_id = ?", bookingID).
+		Order("created_at ASC").
+		Find(&notifications).Error
+	if err != nil {
+		return nil, err
+	}
+	return notifications, nil
+}
+
+// FindInApp returns one page of the patient's notification center, newest first, and the total count
+func (r *notificationRepository) FindInApp(db *gorm.DB, patientID uuid.UUID, unreadOnly bool, page, limit int) ([]entity.Notification, int64, error) {
+	query := db.Model(&entity.Notification{}).
+		Where("patient_id = ? AND channel = ?", patientID, entity.NotificationChannelInApp)
+	if unreadOnly {
+		query = query.Where("read_at IS NULL")
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var notifications []entity.Notification
+	err := query.Order("created_at DESC").
+		Scopes(paginate(page, limit)).
+		Find(&notifications).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	return notifications, total, nil
+}
+
+func (r *notificationRepository) CountUnreadInApp(db *gorm.DB, patientID uuid.UUID) (int64, error) {
+	var count int64
+	err := db.Model(&entity.Notification{}).
+		Where("patient_id = ? AND channel = ? AND read_at IS NULL", patientID, entity.NotificationChannelInApp).
+		Count(&count).Error
+	return count, err
+}
+
+// MarkInAppRead marks an in-app notification of the patient read.
+// Returns affected rows: 0 = not found (an already read notification is updated again).
+func (r *notificationRepository) MarkInAppRead(db *gorm.DB, patientID uuid.UUID, id uuid.UUID, at time.Time) (int64, error) {
+	result := db.Model(&entity.Notification{}).
+		Where("id = ? AND patient_id = ? AND channel = ?", id, patientID, entity.NotificationChannelInApp).
+		Update("read_at", gorm.Expr("COALESCE(read_at, ?)", at))
+	return result.RowsAffected, result.Error
+}
+
+// MarkAllInAppRead marks every unread in-app notification of the patient read
+func (r *notificationRepository) MarkAllInAppRead(db *gorm.DB, patientID uuid.UUID, at time.Time) (int64, error) {
+	result := db.Model(&entity.Notification{}).
+		Where("patient_id = ? AND channel = ? AND read_at IS NULL", patientID, entity.NotificationChannelInApp).
+		Update("read_at", at)
+	return result.RowsAffected, result.Error
+}
+
+// DeleteInApp removes an in-app notification of the patient from the notification center
+func (r *notificationRepository) DeleteInApp(db *gorm.DB, patientID uuid.UUID, id uuid.UUID) (int64, error) {
+	result := db.Where("id = ? AND patient_id = ? AND channel = ?", id, patientID, entity.NotificationChannelInApp).
+		Delete(&entity.Notification{})
+	return result.RowsAffected, result.Error
+}
+
+// FindDeadLetters returns one page of the dead letters, most recently failed first, and the total count.
+// Optional filters: channel, eventType.
+func (r *notificationRepository) FindDeadLetters(db *gorm.DB, channel string, eventType string, page, limit int) ([]entity.Notification, int64, error) {
+	query := db.Model(&entity.Notification{}).Where("status = ?", entity.NotificationStatusDeadLetter)
+	if channel != "" {
+		query = query.Where("channel = ?", channel)
+	}
+	if eventType != "" {
+		query = query.Where("event_type = ?", eventType)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var notifications []entity.Notification
+	err := query.Order("failed_at DESC").
+		Scopes(paginate(page, limit)).
+		Find(&notifications).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	return notifications, total, nil
+}
+
+// Redrive queues a dead letter again with a fresh attempt budget, to the given channel and recipient.
+// Returns affected rows: 0 = not a dead letter (anymore).
+func (r *notificationRepository) Redrive(db *gorm.DB, id uuid.UUID, channel string, recipient string, at time.Time) (int64, error) {
+	result := db.Model(&entity.Notification{}).
+		Where("id = ? AND status = ?", id, entity.NotificationStatusDeadLetter).
+		Updates(map[string]interface{}{
+			"status":          entity.NotificationStatusPending,
+			"channel":         channel,
+			"recipient":       recipient,
+			"attempts":        0,
+			"next_attempt_at": at,
+			"failed_at":       nil,
+			"redriven_at":     at,
+		})
+	return result.RowsAffected, result.Error
+}
+
+func (r *notificationRepository) CreateAttempt(db *gorm.DB, attempt *entity.NotificationAttempt) error {
+	return db.Create(attempt).Error
+}
+
+// FindAttempts returns the sends of a notification, oldest first
+func (r *notificationRepository) FindAttempts(db *gorm.DB, notificationID uuid.UUID) ([]entity.NotificationAttempt, error) {
+	var attempts []entity.NotificationAttempt
+	err := db.Where("notification_id = ?", notificationID).
+		Order("created_at ASC, id ASC").
+		Find(&attempts).Error
+	if err != nil {
+		return nil, err
+	}
+	return attempts, nil
+}
+
+func (r *notificationRepository) CreateDeliveryReport(db *gorm.DB, report *entity.NotificationDeliveryReport) error {
+	return db.Create(report).Error
+}
+
+// FindDeliveryReports returns the provider callbacks of a notification, oldest first
+func (r *notificationRepository) FindDeliveryReports(db *gorm.DB, notificationID uuid.UUID) ([]entity.NotificationDeliveryReport, error) {
+	var reports []entity.NotificationDeliveryReport
+	err := db.Where("notification_id = ?", notificationID).
+		Order("received_at ASC, id ASC").
+		Find(&reports).Error
+	if err != nil {
+		return nil, err
+	}
+	return reports, nil
+}
+
+// FindByPatientID returns one page of the patient's notifications on every channel, newest first, and the total count
+func (r *notificationRepository) FindByPatientID(db *gorm.DB, patientID uuid.UUID, filter *entity.NotificationFilter, page, limit int) ([]entity.Notification, int64, error) {
+	query := db.Model(&entity.Notification{}).Where("patient_id = ?", patientID)
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+	if filter.EventType != "" {
+		query = query.Where("event_type = ?", filter.EventType)
+	}
+	if filter.Channel != "" {
+		query = query.Where("channel = ?", filter.Channel)
+	}
+	if filter.From != nil {
+		query = query.Where("created_at >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		query = query.Where("created_at < ?", *filter.To)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var notifications []entity.Notification
+	err := query.Order("created_at DESC").
+		Scopes(paginate(page, limit)).
+		Find(&notifications).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	return notifications, total, nil
+}
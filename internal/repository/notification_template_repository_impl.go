@@ -0,0 +1,67 @@
+package repository
+
+import (
+	"errors"
+
+	"go-template-clean-architecture/internal/domain/entity"
+	domainRepo "go-template-clean-architecture/internal/domain/repository"
+
+	"gorm.io/gorm"
+)
+
+type notificationTemplateRepository struct{}
+
+func NewNotificationTemplateRepository() domainRepo.NotificationTemplateRepository {
+	return &notificationTemplateRepository{}
+}
+
+func (r *notificationTemplateRepository) Create(db *gorm.DB, template *entity.NotificationTemplate) error {
+	return db.Create(template).Error
+}
+
+func (r *notificationTemplateRepository) FindByID(db *gorm.DB, id int) (*entity.NotificationTemplate, error) {
+	var template entity.NotificationTemplate
+	err := db.Where("id = ?", id).First(&template).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &template, nil
+}
+
+// FindAll returns templates ordered by event type, channel and locale, optionally of one event type.
+func (r *notificationTemplateRepository) FindAll(db *gorm.DB, eventType string) ([]entity.NotificationTemplate, error) {
+	var templates []entity.NotificationTemplate
+	query := db.Model(&entity.NotificationTemplate{})
+
+	if eventType != "" {
+		query = query.Where("event_type = ?", eventType)
+	}
+
+	err := query.Order("event_type ASC, channel ASC, locale ASC").Find(&templates).Error
+	if err != nil {
+		return nil, err
+	}
+	return templates, nil
+}
+
+// FindByEventType returns every channel and locale variant of an event type's templates.
+func (r *notificationTemplateRepository) FindByEventType(db *gorm.DB, eventType string) ([]entity.NotificationTemplate, error) {
+	var templates []entity.NotificationTemplate
+	err := db.Where("event_type = ?", eventType).Find(&templates).Error
+	if err != nil {
+		return nil, err
+	}
+	return templates, nil
+}
+
+func (r *notificationTemplateRepository) Update(db *gorm.DB, template *entity.NotificationTemplate) error {
+	return db.Save(template).Error
+}
+
+func (r *notificationTemplateRepository) Delete(db *gorm.DB, id int) (int64, error) {
+	result := db.Where("id = ?", id).Delete(&entity.NotificationTemplate{})
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,94 @@
+package repository
+
+import (
+	"time"
+
+	"go-template-clean-architecture/internal/domain/entity"
+	domainRepo "go-template-clean-architecture/internal/domain/repository"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type outboxRepository struct{}
+
+func NewOutboxRepository() domainRepo.OutboxRepository {
+	return &outboxRepository{}
+}
+
+func (r *outboxRepository) Create(db *gorm.DB, event *entity.OutboxEvent) error {
+	return db.Create(event).Error
+}
+
+// ClaimDue locks due pending events with FOR UPDATE SKIP LOCKED, so several
+// API instances can run the worker without publishing the same event twice.
+// Must be called inside a transaction.
+func (r *outboxRepository) ClaimDue(db *gorm.DB, now time.Time, limit int) ([]entity.OutboxEvent, error) {
+	var events []entity.OutboxEvent
+	err := db.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+		Where("status = ? AND next_attempt_at <= ?", entity.OutboxStatusPending, now).
+		Order("id ASC").
+		Limit(limit).
+		Find(&events).Error
+	if err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+// Lease postpones the next attempt of claimed events while their handlers run outside
+// the claim transaction, so other workers skip them until the lease ends
+func (r *outboxRepository) Lease(db *gorm.DB, ids []int64, until time.Time) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	return db.Model(&entity.OutboxEvent{}).
+		Where("id IN ?", ids).
+		Update("next_attempt_at", until).Error
+}
+
+func (r *outboxRepository) MarkPublished(db *gorm.DB, id int64, at time.Time) error {
+	return db.Model(&entity.OutboxEvent{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"status":       entity.OutboxStatusPublished,
+			"attempts":     gorm.Expr("attempts + 1"),
+			"published_at": at,
+			"last_error":   "",
+		}).Error
+}
+
+func (r *outboxRepository) MarkRetry(db *gorm.DB, id int64, attempts int, nextAttemptAt time.Time, lastError string) error {
+	return db.Model(&entity.OutboxEvent{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"attempts":        attempts,
+			"next_attempt_at": nextAttemptAt,
+			"last_error":      lastError,
+		}).Error
+}
+
+func (r *outboxRepository) MarkFailed(db *gorm.DB, id int64, attempts int, lastError string) error {
+	return db.Model(&entity.OutboxEvent{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"status":     entity.OutboxStatusFailed,
+			"attempts":   attempts,
+			"last_error": lastError,
+		}).Error
+}
+
+// FindByAggregates returns all events of the given aggregates, oldest first
+func (r *outboxRepository) FindByAggregates(db *gorm.DB, aggregateType string, aggregateIDs []string) ([]entity.OutboxEvent, error) {
+	var events []entity.OutboxEvent
+	if len(aggregateIDs) == 0 {
+		return events, nil
+	}
+	err := db.Where("aggregate_type = ? AND aggregate_id IN ?", aggregateType, aggregateIDs).
+		Order("id ASC").
+		Find(&events).Error
+	if err != nil {
+		return nil, err
+	}
+	return events, nil
+}
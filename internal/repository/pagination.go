@@ -0,0 +1,10 @@
+package repository
+
+import "gorm.io/gorm"
+
+// paginate is a scope applying 1-based page / limit as OFFSET / LIMIT
+func paginate(page, limit int) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Offset((page - 1) * limit).Limit(limit)
+	}
+}
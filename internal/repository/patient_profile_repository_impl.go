@@ -3,6 +3,7 @@ package repository
 import (
 	"context"
 	"errors"
+	"time"
 
 	"go-template-clean-architecture/internal/domain/entity"
 	domainRepo "go-template-clean-architecture/internal/domain/repository"
@@ -33,6 +34,44 @@ func (r *patientProfileRepository) FindByUserID(ctx context.Context, db *gorm.DB
 	return &profile, nil
 }
 
+// FindByNIK returns the profile with its user, or nil if no patient has the NIK
+func (r *patientProfileRepository) FindByNIK(ctx context.Context, db *gorm.DB, nik string) (*entity.PatientProfile, error) {
+	var profile entity.PatientProfile
+	err := db.WithContext(ctx).Preload("User").Where("nik = ?", nik).First(&profile).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &profile, nil
+}
+
+// FindByPhoneNumbers returns the profiles (with user) registered with any of the phone numbers
+func (r *patientProfileRepository) FindByPhoneNumbers(ctx context.Context, db *gorm.DB, phoneNumbers []string) ([]entity.PatientProfile, error) {
+	var profiles []entity.PatientProfile
+	if len(phoneNumbers) == 0 {
+		return profiles, nil
+	}
+	err := db.WithContext(ctx).Preload("User").Where("phone_number IN ?", phoneNumbers).Find(&profiles).Error
+	return profiles, err
+}
+
+// FindExistingNIKs returns the subset of niks already registered
+func (r *patientProfileRepository) FindExistingNIKs(ctx context.Context, db *gorm.DB, niks []string) ([]string, error) {
+	var existing []string
+	if len(niks) == 0 {
+		return existing, nil
+	}
+	err := db.WithContext(ctx).Model(&entity.PatientProfile{}).
+		Where("nik IN ?", niks).
+		Pluck("nik", &existing).Error
+	if err != nil {
+		return nil, err
+	}
+	return existing, nil
+}
+
 func (r *patientProfileRepository) FindAll(ctx context.Context, db *gorm.DB) ([]entity.PatientProfile, error) {
 	var profiles []entity.PatientProfile
 	err := db.WithContext(ctx).Preload("User").Find(&profiles).Error
@@ -42,6 +81,38 @@ func (r *patientProfileRepository) FindAll(ctx context.Context, db *gorm.DB) ([]
 	return profiles, nil
 }
 
+// Search returns one page of the profiles (with user) matching the filter, by name.
+// Erased accounts are left out.
+func (r *patientProfileRepository) Search(ctx context.Context, db *gorm.DB, filter *entity.PatientFilter, page, limit int) ([]entity.PatientProfile, int64, error) {
+	query := db.WithContext(ctx).Model(&entity.PatientProfile{}).
+		Joins("JOIN users ON users.id = patient_profiles.user_id").
+		Where("users.erased_at IS NULL")
+	if filter.NIK != "" {
+		query = query.Where("patient_profiles.nik = ?", filter.NIK)
+	}
+	if filter.PhoneNumber != "" {
+		query = query.Where("patient_profiles.phone_number = ?", filter.PhoneNumber)
+	}
+	if filter.Name != "" {
+		query = query.Where("users.full_name ILIKE ?", "%"+filter.Name+"%")
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var profiles []entity.PatientProfile
+	err := query.Preload("User").
+		Order("users.full_name, patient_profiles.user_id").
+		Scopes(paginate(page, limit)).
+		Find(&profiles).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	return profiles, total, nil
+}
+
 func (r *patientProfileRepository) Update(ctx context.Context, db *gorm.DB, profile *entity.PatientProfile) error {
 	return db.WithContext(ctx).Save(profile).Error
 }
@@ -49,3 +120,20 @@ func (r *patientProfileRepository) Update(ctx context.Context, db *gorm.DB, prof
 func (r *patientProfileRepository) Delete(ctx context.Context, db *gorm.DB, userID uuid.UUID) error {
 	return db.WithContext(ctx).Where("user_id = ?", userID).Delete(&entity.PatientProfile{}).Error
 }
+
+// Anonymize replaces the identifying fields of an erased patient. Gender and the
+// birth year are kept, so booking statistics stay meaningful.
+func (r *patientProfileRepository) Anonymize(ctx context.Context, db *gorm.DB, profile *entity.PatientProfile) error {
+	profile.NIK = entity.ErasedNIK(profile.UserID)
+	profile.PhoneNumber = ""
+	profile.Address = ""
+	profile.DateOfBirth = time.Date(profile.DateOfBirth.Year(), time.January, 1, 0, 0, 0, 0, time.UTC)
+	profile.ClearInsurance()
+	profile.ClearEmergencyContact()
+
+	return db.WithContext(ctx).Model(profile).
+		Select("nik", "phone_number", "address", "date_of_birth",
+			"insurance_provider", "insurance_policy_number", "insurance_valid_from", "insurance_valid_until",
+			"emergency_contact_name", "emergency_contact_relationship", "emergency_contact_phone").
+		Updates(profile).Error
+}
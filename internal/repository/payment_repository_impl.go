@@ -0,0 +1,154 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"go-template-clean-architecture/internal/domain/entity"
+	domainRepo "go-template-clean-architecture/internal/domain/repository"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type paymentRepository struct{}
+
+func NewPaymentRepository() domainRepo.PaymentRepository {
+	return &paymentRepository{}
+}
+
+func (r *paymentRepository) Create(db *gorm.DB, payment *entity.Payment) error {
+	return db.Create(payment).Error
+}
+
+// UpdateCharge records the charge opened at the gateway, leaving the status to the callbacks
+func (r *paymentRepository) UpdateCharge(db *gorm.DB, id uuid.UUID, reference string, paymentURL string) error {
+	return db.Model(&entity.Payment{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"gateway_reference": reference,
+			"payment_url":       paymentURL,
+		}).Error
+}
+
+func (r *paymentRepository) FindByID(db *gorm.DB, id uuid.UUID) (*entity.Payment, error) {
+	var payment entity.Payment
+	err := db.Where("id = ?", id).First(&payment).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &payment, nil
+}
+
+// FindLatestByBookingID returns the most recent payment of the booking
+func (r *paymentRepository) FindLatestByBookingID(db *gorm.DB, bookingID uuid.UUID) (*entity.Payment, error) {
+	var payment entity.Payment
+	err := db.Where("booking_id = ?", bookingID).Order("created_at DESC").First(&payment).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &payment, nil
+}
+
+// MarkPaid records a payment as paid, keeping the reference when the gateway reported none.
+// Payments closed before the gateway reported them paid are recorded too, so they can be refunded.
+// Returns affected rows: 0 = payment is already paid.
+func (r *paymentRepository) MarkPaid(db *gorm.DB, id uuid.UUID, reference string, at time.Time) (int64, error) {
+	updates := map[string]interface{}{
+		"status":  entity.PaymentStatusPaid,
+		"paid_at": at,
+	}
+	if reference != "" {
+		updates["gateway_reference"] = reference
+	}
+	result := db.Model(&entity.Payment{}).
+		Where("id = ? AND status <> ?", id, entity.PaymentStatusPaid).
+		Updates(updates)
+	return result.RowsAffected, result.Error
+}
+
+// Close ends a pending payment as failed, expired or cancelled.
+// Returns affected rows: 0 = payment is no longer pending.
+func (r *paymentRepository) Close(db *gorm.DB, id uuid.UUID, status entity.PaymentStatus, reason string) (int64, error) {
+	result := db.Model(&entity.Payment{}).
+		Where("id = ? AND status = ?", id, entity.PaymentStatusPending).
+		Updates(map[string]interface{}{
+			"status":         status,
+			"failure_reason": reason,
+		})
+	return result.RowsAffected, result.Error
+}
+
+// ClosePendingByBookingID ends the pending payments of a booking, e.g. when it is cancelled
+func (r *paymentRepository) ClosePendingByBookingID(db *gorm.DB, bookingID uuid.UUID, status entity.PaymentStatus, reason string) (int64, error) {
+	result := db.Model(&entity.Payment{}).
+		Where("booking_id = ? AND status = ?", bookingID, entity.PaymentStatusPending).
+		Updates(map[string]interface{}{
+			"status":         status,
+			"failure_reason": reason,
+		})
+	return result.RowsAffected, result.Error
+}
+
+// FindLatestByBookingIDs returns the most recent payment of each booking that has one
+func (r *paymentRepository) FindLatestByBookingIDs(db *gorm.DB, bookingIDs []uuid.UUID) ([]entity.Payment, error) {
+	var payments []entity.Payment
+	if len(bookingIDs) == 0 {
+		return payments, nil
+	}
+	err := db.Raw(`
+		SELECT DISTINCT ON (booking_id) * FROM payments
+		WHERE booking_id IN ?
+		ORDER BY booking_id, created_at DESC
+	`, bookingIDs).Scan(&payments).Error
+	return payments, err
+}
+
+// RequestRefund starts the refund of a paid payment not refunded yet, or whose refund failed.
+// Returns affected rows: 0 = payment is not paid, or refunded or being refunded already.
+func (r *paymentRepository) RequestRefund(db *gorm.DB, id uuid.UUID, amount int64, reason string, at time.Time) (int64, error) {
+	result := db.Model(&entity.Payment{}).
+		Where("id = ? AND status = ? AND refund_status IN ?", id, entity.PaymentStatusPaid,
+			[]entity.PaymentRefundStatus{entity.PaymentRefundNone, entity.PaymentRefundFailed}).
+		Updates(map[string]interface{}{
+			"refund_status":         entity.PaymentRefundPending,
+			"refund_amount":         amount,
+			"refund_reason":         reason,
+			"refund_reference":      "",
+			"refund_failure_reason": "",
+			"refund_requested_at":   at,
+		})
+	return result.RowsAffected, result.Error
+}
+
+// SetRefundReference records the refund accepted by the gateway, the outcome follows with a callback
+func (r *paymentRepository) SetRefundReference(db *gorm.DB, id uuid.UUID, reference string) error {
+	return db.Model(&entity.Payment{}).
+		Where("id = ? AND refund_status = ?", id, entity.PaymentRefundPending).
+		Update("refund_reference", reference).Error
+}
+
+// CompleteRefund ends a pending refund as succeeded or failed, keeping the reference when none is given.
+// Returns affected rows: 0 = no refund is pending.
+func (r *paymentRepository) CompleteRefund(db *gorm.DB, id uuid.UUID, status entity.PaymentRefundStatus, reference string, failureReason string, at time.Time) (int64, error) {
+	updates := map[string]interface{}{
+		"refund_status":         status,
+		"refund_failure_reason": failureReason,
+	}
+	if reference != "" {
+		updates["refund_reference"] = reference
+	}
+	if status == entity.PaymentRefundSucceeded {
+		updates["refunded_at"] = at
+	}
+	result := db.Model(&entity.Payment{}).
+		Where("id = ? AND refund_status = ?", id, entity.PaymentRefundPending).
+		Updates(updates)
+	return result.RowsAffected, result.Error
+}
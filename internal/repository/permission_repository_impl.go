@@ -0,0 +1,41 @@
+package repository
+
+import (
+	"go-template-clean-architecture/internal/domain/entity"
+	domainRepo "go-template-clean-architecture/internal/domain/repository"
+
+	"gorm.io/gorm"
+)
+
+type permissionRepository struct{}
+
+func NewPermissionRepository() domainRepo.PermissionRepository {
+	return &permissionRepository{}
+}
+
+func (r *permissionRepository) FindAll(db *gorm.DB) ([]entity.Permission, error) {
+	var permissions []entity.Permission
+	err := db.Order("code ASC").Find(&permissions).Error
+	return permissions, err
+}
+
+// FindByCodes returns the known permissions among codes
+func (r *permissionRepository) FindByCodes(db *gorm.DB, codes []string) ([]entity.Permission, error) {
+	var permissions []entity.Permission
+	if len(codes) == 0 {
+		return permissions, nil
+	}
+	err := db.Where("code IN ?", codes).Order("code ASC").Find(&permissions).Error
+	return permissions, err
+}
+
+// FindCodesByRoleID returns the permission codes granted to a role
+func (r *permissionRepository) FindCodesByRoleID(db *gorm.DB, roleID int) ([]string, error) {
+	var codes []string
+	err := db.Table("permissions").
+		Joins("JOIN role_permissions ON role_permissions.permission_id = permissions.id").
+		Where("role_permissions.role_id = ?", roleID).
+		Order("permissions.code ASC").
+		Pluck("permissions.code", &codes).Error
+	return codes, err
+}
@@ -27,3 +27,37 @@ func (r *roleRepository) FindByName(ctx context.Context, db *gorm.DB, name strin
 	}
 	return &role, nil
 }
+
+// FindByID finds a role with its permissions
+func (r *roleRepository) FindByID(ctx context.Context, db *gorm.DB, id int) (*entity.Role, error) {
+	var role entity.Role
+	err := db.WithContext(ctx).
+		Preload("Permissions", func(db *gorm.DB) *gorm.DB { return db.Order("permissions.code ASC") }).
+		Where("id = ?", id).First(&role).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &role, nil
+}
+
+// FindAll returns all roles with their permissions
+func (r *roleRepository) FindAll(ctx context.Context, db *gorm.DB) ([]entity.Role, error) {
+	var roles []entity.Role
+	err := db.WithContext(ctx).
+		Preload("Permissions", func(db *gorm.DB) *gorm.DB { return db.Order("permissions.code ASC") }).
+		Order("id ASC").Find(&roles).Error
+	return roles, err
+}
+
+// Create inserts a role with its permissions
+func (r *roleRepository) Create(ctx context.Context, db *gorm.DB, role *entity.Role) error {
+	return db.WithContext(ctx).Create(role).Error
+}
+
+// ReplacePermissions sets the permissions of a role, removing the ones not listed
+func (r *roleRepository) ReplacePermissions(ctx context.Context, db *gorm.DB, role *entity.Role, permissions []entity.Permission) error {
+	return db.WithContext(ctx).Model(role).Association("Permissions").Replace(permissions)
+}
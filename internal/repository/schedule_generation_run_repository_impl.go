@@ -0,0 +1,57 @@
+package repository
+
+import (
+	"errors"
+
+	"go-template-clean-architecture/internal/domain/entity"
+	domainRepo "go-template-clean-architecture/internal/domain/repository"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type scheduleGenerationRunRepository struct{}
+
+func NewScheduleGenerationRunRepository() domainRepo.ScheduleGenerationRunRepository {
+	return &scheduleGenerationRunRepository{}
+}
+
+func (r *scheduleGenerationRunRepository) Create(db *gorm.DB, run *entity.ScheduleGenerationRun) error {
+	return db.Create(run).Error
+}
+
+func (r *scheduleGenerationRunRepository) Save(db *gorm.DB, run *entity.ScheduleGenerationRun) error {
+	return db.Save(run).Error
+}
+
+func (r *scheduleGenerationRunRepository) FindByID(db *gorm.DB, id uuid.UUID) (*entity.ScheduleGenerationRun, error) {
+	var run entity.ScheduleGenerationRun
+	err := db.Where("id = ?", id).First(&run).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &run, nil
+}
+
+// FindAll returns one page of runs, newest first, and the total count
+func (r *scheduleGenerationRunRepository) FindAll(db *gorm.DB, page, limit int) ([]entity.ScheduleGenerationRun, int64, error) {
+	query := db.Model(&entity.ScheduleGenerationRun{})
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var runs []entity.ScheduleGenerationRun
+	err := query.Order("started_at DESC").
+		Offset((page - 1) * limit).
+		Limit(limit).
+		Find(&runs).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	return runs, total, nil
+}
@@ -0,0 +1,46 @@
+package repository
+
+import (
+	"errors"
+
+	"go-template-clean-architecture/internal/domain/entity"
+	domainRepo "go-template-clean-architecture/internal/domain/repository"
+
+	"gorm.io/gorm"
+)
+
+type scheduleSlotRepository struct{}
+
+func NewScheduleSlotRepository() domainRepo.ScheduleSlotRepository {
+	return &scheduleSlotRepository{}
+}
+
+func (r *scheduleSlotRepository) CreateBatch(db *gorm.DB, slots []entity.ScheduleSlot) error {
+	if len(slots) == 0 {
+		return nil
+	}
+	return db.Create(&slots).Error
+}
+
+func (r *scheduleSlotRepository) FindByID(db *gorm.DB, id int64) (*entity.ScheduleSlot, error) {
+	var slot entity.ScheduleSlot
+	err := db.Where("id = ?", id).First(&slot).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &slot, nil
+}
+
+func (r *scheduleSlotRepository) FindByScheduleID(db *gorm.DB, scheduleID int) ([]entity.ScheduleSlot, error) {
+	var slots []entity.ScheduleSlot
+	err := db.Where("schedule_id = ?", scheduleID).
+		Order("position ASC").
+		Find(&slots).Error
+	if err != nil {
+		return nil, err
+	}
+	return slots, nil
+}
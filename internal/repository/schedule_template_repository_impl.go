@@ -0,0 +1,94 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"go-template-clean-architecture/internal/domain/entity"
+	domainRepo "go-template-clean-architecture/internal/domain/repository"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type scheduleTemplateRepository struct{}
+
+func NewScheduleTemplateRepository() domainRepo.ScheduleTemplateRepository {
+	return &scheduleTemplateRepository{}
+}
+
+func (r *scheduleTemplateRepository) Create(db *gorm.DB, template *entity.ScheduleTemplate) error {
+	return db.Omit("Doctor").Create(template).Error
+}
+
+func (r *scheduleTemplateRepository) FindByID(db *gorm.DB, id int) (*entity.ScheduleTemplate, error) {
+	var template entity.ScheduleTemplate
+	err := db.Preload("Doctor.User").Preload("Doctor.Specialization").Where("id = ?", id).First(&template).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &template, nil
+}
+
+// FindAll returns one page of templates ordered by doctor, weekday and start time,
+// optionally limited to one doctor, and the total count.
+func (r *scheduleTemplateRepository) FindAll(db *gorm.DB, doctorID *uuid.UUID, page, limit int) ([]entity.ScheduleTemplate, int64, error) {
+	query := db.Model(&entity.ScheduleTemplate{})
+	if doctorID != nil {
+		query = query.Where("doctor_id = ?", *doctorID)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var templates []entity.ScheduleTemplate
+	err := query.Preload("Doctor.User").
+		Preload("Doctor.Specialization").
+		Order("doctor_id ASC, weekday ASC, start_time ASC").
+		Offset((page - 1) * limit).
+		Limit(limit).
+		Find(&templates).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	return templates, total, nil
+}
+
+// FindActive returns the active templates of doctors whose user account is active
+func (r *scheduleTemplateRepository) FindActive(db *gorm.DB) ([]entity.ScheduleTemplate, error) {
+	var templates []entity.ScheduleTemplate
+	err := db.
+		Joins("JOIN users ON users.id = schedule_templates.doctor_id").
+		Where("schedule_templates.is_active = ? AND users.is_active = ?", true, true).
+		Order("schedule_templates.id ASC").
+		Find(&templates).Error
+	if err != nil {
+		return nil, err
+	}
+	return templates, nil
+}
+
+func (r *scheduleTemplateRepository) Update(db *gorm.DB, template *entity.ScheduleTemplate) error {
+	return db.Omit("Doctor").Save(template).Error
+}
+
+// MarkGeneratedUntil advances generated_until of the templates (never moves it back)
+func (r *scheduleTemplateRepository) MarkGeneratedUntil(db *gorm.DB, ids []int, until time.Time) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	date := until.Format("2006-01-02")
+	return db.Model(&entity.ScheduleTemplate{}).
+		Where("id IN ? AND (generated_until IS NULL OR generated_until < ?)", ids, date).
+		UpdateColumn("generated_until", date).Error
+}
+
+func (r *scheduleTemplateRepository) Delete(db *gorm.DB, id int) (int64, error) {
+	result := db.Where("id = ?", id).Delete(&entity.ScheduleTemplate{})
+	return result.RowsAffected, result.Error
+}
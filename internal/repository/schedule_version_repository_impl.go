@@ -0,0 +1,38 @@
+package repository
+
+import (
+	"go-template-clean-architecture/internal/domain/entity"
+	domainRepo "go-template-clean-architecture/internal/domain/repository"
+
+	"gorm.io/gorm"
+)
+
+type scheduleVersionRepository struct{}
+
+func NewScheduleVersionRepository() domainRepo.ScheduleVersionRepository {
+	return &scheduleVersionRepository{}
+}
+
+func (r *scheduleVersionRepository) Create(db *gorm.DB, version *entity.ScheduleVersion) error {
+	return db.Omit("User").Create(version).Error
+}
+
+func (r *scheduleVersionRepository) CreateBatch(db *gorm.DB, versions []entity.ScheduleVersion) error {
+	if len(versions) == 0 {
+		return nil
+	}
+	return db.Omit("User").Create(&versions).Error
+}
+
+// FindByScheduleID returns the history of a schedule, oldest first
+func (r *scheduleVersionRepository) FindByScheduleID(db *gorm.DB, scheduleID int) ([]entity.ScheduleVersion, error) {
+	var versions []entity.ScheduleVersion
+	err := db.Preload("User.Role").
+		Where("schedule_id = ?", scheduleID).
+		Order("id ASC").
+		Find(&versions).Error
+	if err != nil {
+		return nil, err
+	}
+	return versions, nil
+}
@@ -0,0 +1,84 @@
+package repository
+
+import (
+	"errors"
+
+	"go-template-clean-architecture/internal/domain/entity"
+	domainRepo "go-template-clean-architecture/internal/domain/repository"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type specializationDefaultRepository struct{}
+
+func NewSpecializationDefaultRepository() domainRepo.SpecializationDefaultRepository {
+	return &specializationDefaultRepository{}
+}
+
+func (r *specializationDefaultRepository) Upsert(db *gorm.DB, defaults *entity.SpecializationDefault) error {
+	return db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "specialization"}},
+		DoUpdates: clause.AssignmentColumns([]string{"default_quota", "consultation_minutes", "fee", "updated_at"}),
+	}).Create(defaults).Error
+}
+
+func (r *specializationDefaultRepository) FindBySpecialization(db *gorm.DB, specialization string) (*entity.SpecializationDefault, error) {
+	var defaults entity.SpecializationDefault
+	err := db.Where("LOWER(specialization) = LOWER(?)", specialization).First(&defaults).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &defaults, nil
+}
+
+// FindByDoctorID returns the defaults of the doctor's specialization.
+func (r *specializationDefaultRepository) FindByDoctorID(db *gorm.DB, doctorID uuid.UUID) (*entity.SpecializationDefault, error) {
+	var defaults entity.SpecializationDefault
+	err := db.
+		Joins("JOIN specializations ON specializations.name = specialization_defaults.specialization").
+		Joins("JOIN doctor_profiles ON doctor_profiles.specialization_id = specializations.id").
+		Where("doctor_profiles.user_id = ?", doctorID).
+		First(&defaults).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &defaults, nil
+}
+
+// FindBySpecializationID returns the defaults of a managed specialization.
+func (r *specializationDefaultRepository) FindBySpecializationID(db *gorm.DB, specializationID int) (*entity.SpecializationDefault, error) {
+	var defaults entity.SpecializationDefault
+	err := db.
+		Joins("JOIN specializations ON specializations.name = specialization_defaults.specialization").
+		Where("specializations.id = ?", specializationID).
+		First(&defaults).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &defaults, nil
+}
+
+func (r *specializationDefaultRepository) FindAll(db *gorm.DB) ([]entity.SpecializationDefault, error) {
+	var defaults []entity.SpecializationDefault
+	err := db.Order("specialization ASC").Find(&defaults).Error
+	if err != nil {
+		return nil, err
+	}
+	return defaults, nil
+}
+
+func (r *specializationDefaultRepository) Delete(db *gorm.DB, specialization string) (int64, error) {
+	affected := db.Where("LOWER(specialization) = LOWER(?)", specialization).Delete(&entity.SpecializationDefault{})
+	return affected.RowsAffected, affected.Error
+}
@@ -0,0 +1,35 @@
+package repository
+
+import (
+	"go-template-clean-architecture/internal/domain/entity"
+	domainRepo "go-template-clean-architecture/internal/domain/repository"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type usageRecordRepository struct{}
+
+func NewUsageRecordRepository() domainRepo.UsageRecordRepository {
+	return &usageRecordRepository{}
+}
+
+// Upsert writes the final counters of a day. Values are replaced (not added),
+// so flushing the same day twice is idempotent.
+func (r *usageRecordRepository) Upsert(db *gorm.DB, record *entity.UsageRecord) error {
+	return db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "tenant_id"}, {Name: "usage_date"}},
+		DoUpdates: clause.AssignmentColumns([]string{"api_calls", "bookings_created", "updated_at"}),
+	}).Create(record).Error
+}
+
+func (r *usageRecordRepository) FindByTenantAndRange(db *gorm.DB, tenantID string, startDate, endDate string) ([]entity.UsageRecord, error) {
+	var records []entity.UsageRecord
+	err := db.Where("tenant_id = ? AND usage_date BETWEEN ? AND ?", tenantID, startDate, endDate).
+		Order("usage_date ASC").
+		Find(&records).Error
+	if err != nil {
+		return nil, err
+	}
+	return records, nil
+}
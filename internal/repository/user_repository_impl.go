@@ -1,6 +1,8 @@
 package repository
 
 import (
+	"time"
+
 	"go-template-clean-architecture/internal/domain/entity"
 	domainRepo "go-template-clean-architecture/internal/domain/repository"
 
@@ -18,6 +20,11 @@ func (r *userRepository) Create(db *gorm.DB, user *entity.User) error {
 	return db.Create(user).Error
 }
 
+// CreateBatch inserts users with their profiles in chunks (roster pre-registration)
+func (r *userRepository) CreateBatch(db *gorm.DB, users []entity.User) error {
+	return db.CreateInBatches(users, 500).Error
+}
+
 func (r *userRepository) FindByEmail(db *gorm.DB, email string) (*entity.User, error) {
 	var user entity.User
 	err := db.Where("email = ?", email).First(&user).Error
@@ -26,7 +33,7 @@ func (r *userRepository) FindByEmail(db *gorm.DB, email string) (*entity.User, e
 
 func (r *userRepository) FindByID(db *gorm.DB, id uuid.UUID) (*entity.User, error) {
 	var user entity.User
-	err := db.Preload("Role").Preload("DoctorProfile").Preload("PatientProfile").Where("id = ?", id).First(&user).Error
+	err := db.Preload("Role").Preload("DoctorProfile.Specialization").Preload("PatientProfile").Where("id = ?", id).First(&user).Error
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
 			return nil, nil
@@ -40,7 +47,155 @@ func (r *userRepository) Update(db *gorm.DB, user *entity.User) error {
 	return db.Save(user).Error
 }
 
+// Claim sets the credentials of a pre-registered user.
+// Returns 0 rows affected when the account was already claimed.
+func (r *userRepository) Claim(db *gorm.DB, userID uuid.UUID, email, password string, claimedAt time.Time) (int64, error) {
+	result := db.Model(&entity.User{}).
+		Where("id = ? AND is_claimed = false", userID).
+		Updates(map[string]interface{}{
+			"email":      email,
+			"password":   password,
+			"is_claimed": true,
+			"claimed_at": claimedAt,
+		})
+	return result.RowsAffected, result.Error
+}
+
 func (r *userRepository) Delete(db *gorm.DB, userID uuid.UUID) (int64, error) {
 	affected := db.Where("id = ?", userID).Delete(&entity.User{})
 	return affected.RowsAffected, affected.Error
 }
+
+// UpdateTwoFactor saves the two-factor state of the user (secret, enabled at, backup codes)
+func (r *userRepository) UpdateTwoFactor(db *gorm.DB, user *entity.User) error {
+	return db.Model(user).
+		Select("two_factor_secret", "two_factor_enabled_at", "two_factor_backup_codes").
+		Updates(user).Error
+}
+
+// ConsumeBackupCode removes one backup code of the user.
+// Returns 0 rows affected when the code is unknown or was used already.
+func (r *userRepository) ConsumeBackupCode(db *gorm.DB, userID uuid.UUID, codeHash string) (int64, error) {
+	result := db.Model(&entity.User{}).
+		Where("id = ? AND jsonb_exists(two_factor_backup_codes, ?)", userID, codeHash).
+		Update("two_factor_backup_codes", gorm.Expr("two_factor_backup_codes - ?::text", codeHash))
+	return result.RowsAffected, result.Error
+}
+
+// FindByGoogleSubject finds the user linked to a Google account
+func (r *userRepository) FindByGoogleSubject(db *gorm.DB, subject string) (*entity.User, error) {
+	var user entity.User
+	err := db.Where("google_subject = ?", subject).First(&user).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &user, nil
+}
+
+// LinkGoogleSubject links a Google account to the user.
+// Returns 0 rows affected when the user is already linked to a Google account.
+func (r *userRepository) LinkGoogleSubject(db *gorm.DB, userID uuid.UUID, subject string) (int64, error) {
+	result := db.Model(&entity.User{}).
+		Where("id = ? AND google_subject IS NULL", userID).
+		Update("google_subject", subject)
+	return result.RowsAffected, result.Error
+}
+
+// Erase deactivates the user and anonymizes its credentials and name.
+// Returns 0 rows affected when the user was already erased.
+func (r *userRepository) Erase(db *gorm.DB, userID uuid.UUID, erasedAt time.Time) (int64, error) {
+	result := db.Model(&entity.User{}).
+		Where("id = ? AND erased_at IS NULL", userID).
+		Updates(map[string]interface{}{
+			"email":                   entity.ErasedEmail(userID),
+			"full_name":               entity.ErasedFullName,
+			"password":                "", // never matches a bcrypt hash
+			"is_active":               false,
+			"google_subject":          nil,
+			"two_factor_secret":       "",
+			"two_factor_enabled_at":   nil,
+			"two_factor_backup_codes": entity.BackupCodeHashes{},
+			"erased_at":               erasedAt,
+		})
+	return result.RowsAffected, result.Error
+}
+
+// FindAll returns one page of users with their role, newest first
+func (r *userRepository) FindAll(db *gorm.DB, filter *entity.UserFilter, page, limit int) ([]entity.User, int64, error) {
+	query := db.Model(&entity.User{})
+	if filter.RoleID != 0 {
+		query = query.Where("role_id = ?", filter.RoleID)
+	}
+	if filter.Locked != nil {
+		if *filter.Locked {
+			query = query.Where("locked_until > NOW()")
+		} else {
+			query = query.Where("locked_until IS NULL OR locked_until <= NOW()")
+		}
+	}
+	if filter.Search != "" {
+		search := "%" + filter.Search + "%"
+		query = query.Where("email ILIKE ? OR full_name ILIKE ?", search, search)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var users []entity.User
+	err := query.Preload("Role").
+		Order("created_at DESC").
+		Offset((page - 1) * limit).
+		Limit(limit).
+		Find(&users).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	return users, total, nil
+}
+
+// Lock refuses logins of the user until lockedUntil and counts the lockout
+func (r *userRepository) Lock(db *gorm.DB, userID uuid.UUID, lockedAt, lockedUntil time.Time) error {
+	return db.Model(&entity.User{}).
+		Where("id = ?", userID).
+		Updates(map[string]interface{}{
+			"locked_until":   lockedUntil,
+			"last_locked_at": lockedAt,
+			"lockout_count":  gorm.Expr("lockout_count + 1"),
+		}).Error
+}
+
+// Unlock lifts the lockout of the user and resets the lockout count
+func (r *userRepository) Unlock(db *gorm.DB, userID uuid.UUID) error {
+	return db.Model(&entity.User{}).
+		Where("id = ?", userID).
+		Updates(map[string]interface{}{
+			"locked_until":  nil,
+			"lockout_count": 0,
+		}).Error
+}
+
+// UpdateLastLogin sets the last successful login of the user
+func (r *userRepository) UpdateLastLogin(db *gorm.DB, userID uuid.UUID, at time.Time) error {
+	return db.Model(&entity.User{}).Where("id = ?", userID).Update("last_login_at", at).Error
+}
+
+// ChangePassword sets the (hashed) password chosen by the user, which satisfies a required change
+func (r *userRepository) ChangePassword(db *gorm.DB, userID uuid.UUID, password string, changedAt time.Time) error {
+	return db.Model(&entity.User{}).
+		Where("id = ?", userID).
+		Updates(map[string]interface{}{
+			"password":             password,
+			"must_change_password": false,
+			"password_changed_at":  changedAt,
+		}).Error
+}
+
+// SetMustChangePassword requires (or no longer requires) the user to change the password
+func (r *userRepository) SetMustChangePassword(db *gorm.DB, userID uuid.UUID, required bool) error {
+	return db.Model(&entity.User{}).Where("id = ?", userID).Update("must_change_password", required).Error
+}
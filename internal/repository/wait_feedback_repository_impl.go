@@ -0,0 +1,63 @@
+package repository
+
+import (
+	"go-template-clean-architecture/internal/domain/entity"
+	domainRepo "go-template-clean-architecture/internal/domain/repository"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type waitFeedbackRepository struct{}
+
+func NewWaitFeedbackRepository() domainRepo.WaitFeedbackRepository {
+	return &waitFeedbackRepository{}
+}
+
+func (r *waitFeedbackRepository) Create(db *gorm.DB, feedback *entity.WaitFeedback) error {
+	return db.Create(feedback).Error
+}
+
+// AverageByDoctorIDs returns average reported wait per doctor for the given doctors.
+func (r *waitFeedbackRepository) AverageByDoctorIDs(db *gorm.DB, doctorIDs []uuid.UUID) ([]entity.DoctorWaitStat, error) {
+	var stats []entity.DoctorWaitStat
+	if len(doctorIDs) == 0 {
+		return stats, nil
+	}
+
+	err := db.Model(&entity.WaitFeedback{}).
+		Select("doctor_id, AVG(wait_minutes) as average_wait_minutes, COUNT(*) as feedback_count").
+		Where("doctor_id IN ?", doctorIDs).
+		Group("doctor_id").
+		Scan(&stats).Error
+	if err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
+
+// AverageAllDoctors returns average reported wait for every doctor with feedback,
+// optionally limited to schedules within a date range (YYYY-MM-DD).
+func (r *waitFeedbackRepository) AverageAllDoctors(db *gorm.DB, startAt, endAt string) ([]entity.DoctorWaitStat, error) {
+	var stats []entity.DoctorWaitStat
+	query := db.Model(&entity.WaitFeedback{}).
+		Select("wait_feedbacks.doctor_id, users.full_name as doctor_name, AVG(wait_feedbacks.wait_minutes) as average_wait_minutes, COUNT(*) as feedback_count").
+		Joins("JOIN users ON users.id = wait_feedbacks.doctor_id").
+		Joins("JOIN doctor_schedules ON doctor_schedules.id = wait_feedbacks.schedule_id AND doctor_schedules.deleted_at IS NULL")
+
+	if startAt != "" {
+		query = query.Where("doctor_schedules.schedule_date >= ?", startAt)
+	}
+	if endAt != "" {
+		query = query.Where("doctor_schedules.schedule_date <= ?", endAt)
+	}
+
+	err := query.
+		Group("wait_feedbacks.doctor_id, users.full_name").
+		Order("average_wait_minutes DESC").
+		Scan(&stats).Error
+	if err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
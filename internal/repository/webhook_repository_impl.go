@@ -0,0 +1,197 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"go-template-clean-architecture/internal/domain/entity"
+	domainRepo "go-template-clean-architecture/internal/domain/repository"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type webhookSubscriptionRepository struct{}
+
+func NewWebhookSubscriptionRepository() domainRepo.WebhookSubscriptionRepository {
+	return &webhookSubscriptionRepository{}
+}
+
+func (r *webhookSubscriptionRepository) Create(db *gorm.DB, subscription *entity.WebhookSubscription) error {
+	return db.Create(subscription).Error
+}
+
+func (r *webhookSubscriptionRepository) FindByID(db *gorm.DB, id int) (*entity.WebhookSubscription, error) {
+	var subscription entity.WebhookSubscription
+	err := db.Where("id = ?", id).First(&subscription).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &subscription, nil
+}
+
+func (r *webhookSubscriptionRepository) FindAll(db *gorm.DB) ([]entity.WebhookSubscription, error) {
+	var subscriptions []entity.WebhookSubscription
+	err := db.Order("created_at ASC").Find(&subscriptions).Error
+	if err != nil {
+		return nil, err
+	}
+	return subscriptions, nil
+}
+
+// FindActiveByEventType returns the active subscriptions receiving the event type
+func (r *webhookSubscriptionRepository) FindActiveByEventType(db *gorm.DB, eventType string) ([]entity.WebhookSubscription, error) {
+	var subscriptions []entity.WebhookSubscription
+	err := db.Where("status = ? AND event_types @> ?::jsonb", entity.WebhookStatusActive, entity.WebhookEvents{eventType}).
+		Order("id ASC").
+		Find(&subscriptions).Error
+	if err != nil {
+		return nil, err
+	}
+	return subscriptions, nil
+}
+
+func (r *webhookSubscriptionRepository) Update(db *gorm.DB, subscription *entity.WebhookSubscription) error {
+	return db.Save(subscription).Error
+}
+
+func (r *webhookSubscriptionRepository) Delete(db *gorm.DB, id int) (int64, error) {
+	result := db.Where("id = ?", id).Delete(&entity.WebhookSubscription{})
+	return result.RowsAffected, result.Error
+}
+
+// RecordSuccess resets the consecutive failures of the subscription
+func (r *webhookSubscriptionRepository) RecordSuccess(db *gorm.DB, id int) error {
+	return db.Model(&entity.WebhookSubscription{}).
+		Where("id = ? AND consecutive_failures > 0", id).
+		Update("consecutive_failures", 0).Error
+}
+
+// RecordFailure increments the consecutive failures of the subscription and returns the new count
+func (r *webhookSubscriptionRepository) RecordFailure(db *gorm.DB, id int) (int, error) {
+	var failures int
+	err := db.Raw(
+		"UPDATE webhook_subscriptions SET consecutive_failures = consecutive_failures + 1, updated_at = ? WHERE id = ? RETURNING consecutive_failures",
+		time.Now(), id,
+	).Scan(&failures).Error
+	return failures, err
+}
+
+// Pause stops the deliveries of an active subscription.
+// Returns affected rows: 0 = not found or already paused.
+func (r *webhookSubscriptionRepository) Pause(db *gorm.DB, id int, reason string, at time.Time) (int64, error) {
+	result := db.Model(&entity.WebhookSubscription{}).
+		Where("id = ? AND status = ?", id, entity.WebhookStatusActive).
+		Updates(map[string]interface{}{
+			"status":       entity.WebhookStatusPaused,
+			"paused_at":    at,
+			"pause_reason": reason,
+		})
+	return result.RowsAffected, result.Error
+}
+
+type webhookDeliveryRepository struct{}
+
+func NewWebhookDeliveryRepository() domainRepo.WebhookDeliveryRepository {
+	return &webhookDeliveryRepository{}
+}
+
+func (r *webhookDeliveryRepository) Create(db *gorm.DB, delivery *entity.WebhookDelivery) error {
+	return db.Create(delivery).Error
+}
+
+// CreateIfAbsent inserts the delivery unless the subscription already has one for the event.
+// Returns false when it already existed (outbox event redelivered).
+func (r *webhookDeliveryRepository) CreateIfAbsent(db *gorm.DB, delivery *entity.WebhookDelivery) (bool, error) {
+	result := db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "subscription_id"}, {Name: "event_id"}},
+		DoNothing: true,
+	}).Create(delivery)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// ClaimDue locks due pending deliveries of active subscriptions with FOR UPDATE SKIP LOCKED,
+// so several API instances can run the worker without sending the same delivery twice.
+// Deliveries of paused subscriptions wait until the subscription is resumed.
+// Must be called inside a transaction.
+func (r *webhookDeliveryRepository) ClaimDue(db *gorm.DB, now time.Time, limit int) ([]entity.WebhookDelivery, error) {
+	var deliveries []entity.WebhookDelivery
+	err := db.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED", Table: clause.Table{Name: "webhook_deliveries"}}).
+		Joins("JOIN webhook_subscriptions ON webhook_subscriptions.id = webhook_deliveries.subscription_id").
+		Where("webhook_deliveries.status = ? AND webhook_deliveries.next_attempt_at <= ?", entity.WebhookDeliveryPending, now).
+		Where("webhook_subscriptions.status = ?", entity.WebhookStatusActive).
+		Order("webhook_deliveries.next_attempt_at ASC, webhook_deliveries.created_at ASC").
+		Limit(limit).
+		Find(&deliveries).Error
+	if err != nil {
+		return nil, err
+	}
+	return deliveries, nil
+}
+
+func (r *webhookDeliveryRepository) MarkSucceeded(db *gorm.DB, id uuid.UUID, attempts int, responseStatus int, responseBody string, at time.Time) error {
+	return db.Model(&entity.WebhookDelivery{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"status":          entity.WebhookDeliverySucceeded,
+			"attempts":        attempts,
+			"response_status": responseStatus,
+			"response_body":   responseBody,
+			"delivered_at":    at,
+			"last_error":      "",
+		}).Error
+}
+
+func (r *webhookDeliveryRepository) MarkRetry(db *gorm.DB, id uuid.UUID, attempts int, nextAttemptAt time.Time, responseStatus *int, responseBody string, lastError string) error {
+	return db.Model(&entity.WebhookDelivery{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"attempts":        attempts,
+			"next_attempt_at": nextAttemptAt,
+			"response_status": responseStatus,
+			"response_body":   responseBody,
+			"last_error":      lastError,
+		}).Error
+}
+
+func (r *webhookDeliveryRepository) MarkFailed(db *gorm.DB, id uuid.UUID, attempts int, responseStatus *int, responseBody string, lastError string, at time.Time) error {
+	return db.Model(&entity.WebhookDelivery{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"status":          entity.WebhookDeliveryFailed,
+			"attempts":        attempts,
+			"response_status": responseStatus,
+			"response_body":   responseBody,
+			"last_error":      lastError,
+			"failed_at":       at,
+		}).Error
+}
+
+// FindBySubscriptionID returns one page of the subscription's deliveries, newest first, and the total count
+func (r *webhookDeliveryRepository) FindBySubscriptionID(db *gorm.DB, subscriptionID int, status string, page, limit int) ([]entity.WebhookDelivery, int64, error) {
+	query := db.Model(&entity.WebhookDelivery{}).Where("subscription_id = ?", subscriptionID)
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var deliveries []entity.WebhookDelivery
+	err := query.Order("created_at DESC").
+		Scopes(paginate(page, limit)).
+		Find(&deliveries).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	return deliveries, total, nil
+}
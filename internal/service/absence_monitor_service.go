@@ -0,0 +1,149 @@
+package service
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go-template-clean-architecture/config"
+	"go-template-clean-architecture/internal/domain/entity"
+	"go-template-clean-architecture/internal/domain/repository"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// Interval between absence detection scans
+const absenceScanInterval = 1 * time.Minute
+
+// AbsenceMonitorService flags schedules whose doctor has not checked in
+// within the configured threshold after the schedule start.
+//
+// Flagged schedules are:
+// - Marked with absence_flagged_at in the database
+// - Reported to admins through the audit log (action schedule.doctor_possibly_absent)
+// - Optionally closed for new bookings (see AbsenceConfig.PauseBookings)
+type AbsenceMonitorService struct {
+	db           *gorm.DB
+	log          *logrus.Logger
+	cfg          *config.Config
+	scheduleRepo repository.DoctorScheduleRepository
+	auditService AuditService
+	versionRepo  repository.ScheduleVersionRepository
+
+	// Graceful shutdown
+	stopChan chan struct{}
+	wg       sync.WaitGroup
+	stopped  atomic.Bool
+}
+
+// NewAbsenceMonitorService creates a new AbsenceMonitorService.
+// Call Start() to begin scanning and Stop() during graceful shutdown.
+func NewAbsenceMonitorService(
+	db *gorm.DB,
+	log *logrus.Logger,
+	cfg *config.Config,
+	scheduleRepo repository.DoctorScheduleRepository,
+	auditService AuditService,
+	versionRepo repository.ScheduleVersionRepository,
+) *AbsenceMonitorService {
+	return &AbsenceMonitorService{
+		db:           db,
+		log:          log,
+		cfg:          cfg,
+		scheduleRepo: scheduleRepo,
+		auditService: auditService,
+		versionRepo:  versionRepo,
+		stopChan:     make(chan struct{}),
+	}
+}
+
+// Start launches the background scan loop.
+func (s *AbsenceMonitorService) Start() {
+	s.wg.Add(1)
+	go s.scanLoop()
+}
+
+// Stop gracefully shuts down the service.
+// Safe to call multiple times.
+func (s *AbsenceMonitorService) Stop() {
+	if s.stopped.CompareAndSwap(false, true) {
+		close(s.stopChan)
+		s.wg.Wait()
+		s.log.Info("AbsenceMonitorService stopped")
+	}
+}
+
+// scanLoop runs DetectAbsences on every tick until stopped
+func (s *AbsenceMonitorService) scanLoop() {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(absenceScanInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.stopChan:
+			s.log.Debug("Absence monitor goroutine stopping")
+			return
+		case <-ticker.C:
+			ctx, cancel := context.WithTimeout(context.Background(), absenceScanInterval)
+			if err := s.DetectAbsences(ctx); err != nil {
+				s.log.Warnf("Absence detection scan failed: %+v", err)
+			}
+			cancel()
+		}
+	}
+}
+
+// DetectAbsences flags today's schedules that started more than Threshold ago
+// without a doctor check-in.
+func (s *AbsenceMonitorService) DetectAbsences(ctx context.Context) error {
+	now := time.Now().In(s.cfg.App.Location)
+	cutoff := now.Add(-s.cfg.Absence.Threshold)
+
+	// Threshold crossed midnight - nothing from today can be late yet
+	if cutoff.Day() != now.Day() {
+		return nil
+	}
+
+	schedules, err := s.scheduleRepo.FindPendingCheckIn(s.db.WithContext(ctx), now.Format("2006-01-02"), cutoff.Format("15:04:05"))
+	if err != nil {
+		return err
+	}
+
+	for _, schedule := range schedules {
+		affected, err := s.scheduleRepo.MarkAbsenceFlagged(s.db.WithContext(ctx), schedule.ID, now)
+		if err != nil {
+			s.log.Warnf("Failed to flag schedule %d as possibly absent: %+v", schedule.ID, err)
+			continue
+		}
+		if affected == 0 {
+			// Doctor checked in between scan and update
+			continue
+		}
+
+		// Alert admins via audit trail (system action, no user)
+		s.log.Warnf("ALERT: doctor %s possibly absent for schedule %d (%s %s)",
+			schedule.DoctorID, schedule.ID, schedule.ScheduleDate.Format("2006-01-02"), schedule.StartTime)
+		if err := s.auditService.LogCreate(ctx, s.db.WithContext(ctx), nil, entity.AuditActionScheduleAbsent, "doctor_schedule", strconv.Itoa(schedule.ID), entity.JSON{
+			"doctor_id":       schedule.DoctorID,
+			"doctor_name":     schedule.Doctor.User.FullName,
+			"schedule_date":   schedule.ScheduleDate.Format("2006-01-02"),
+			"start_time":      schedule.StartTime,
+			"bookings_paused": s.cfg.Absence.PauseBookings,
+		}); err != nil {
+			s.log.Warnf("Failed to create audit log: %+v", err)
+		}
+
+		flagged := schedule
+		flagged.AbsenceFlaggedAt = &now
+		if err := s.versionRepo.Create(s.db.WithContext(ctx), entity.NewScheduleVersion(schedule.ID, nil, entity.AuditActionScheduleAbsent, &schedule, &flagged)); err != nil {
+			s.log.Warnf("Failed to record version of schedule %d: %+v", schedule.ID, err)
+		}
+	}
+
+	return nil
+}
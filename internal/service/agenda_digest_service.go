@@ -0,0 +1,288 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go-template-clean-architecture/config"
+	"go-template-clean-architecture/internal/domain/entity"
+	"go-template-clean-architecture/internal/domain/repository"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+const (
+	// Redis set of the doctors already sent their agenda: agenda_digest:{YYYY-MM-DD}
+	RedisAgendaDigestSentKeyPrefix = "agenda_digest:"
+
+	// Interval between agenda digest scans
+	agendaDigestScanInterval = 1 * time.Minute
+
+	// The sent sets outlive the day, so a late scan cannot send twice
+	agendaDigestSentKeyTTL = 48 * time.Hour
+
+	// Notification event type of the digest
+	agendaDigestEventType = "doctor.agenda_digest"
+)
+
+// AgendaDigestService emails doctors the agenda of their day.
+//
+// Every minute after the configured local hour each instance looks up today's open
+// schedules. Every doctor with one gets a single email listing the schedules with the booked
+// patients in queue order. A doctor is claimed in a Redis set per date before the digest is
+// recorded, so every instance can scan without sending twice. Doctors turn the digest off in
+// their notification preferences.
+type AgendaDigestService struct {
+	db                  *gorm.DB
+	redisClient         *redis.Client
+	log                 *logrus.Logger
+	cfg                 *config.Config
+	scheduleRepo        repository.DoctorScheduleRepository
+	bookingRepo         repository.BookingRepository
+	preferenceRepo      repository.NotificationPreferenceRepository
+	notificationService *NotificationService
+	formatService       FormatService
+
+	// Graceful shutdown
+	stopChan chan struct{}
+	wg       sync.WaitGroup
+	stopped  atomic.Bool
+}
+
+// NewAgendaDigestService creates a new AgendaDigestService.
+// Call Start() to begin scanning and Stop() during graceful shutdown.
+func NewAgendaDigestService(
+	db *gorm.DB,
+	redisClient *redis.Client,
+	log *logrus.Logger,
+	cfg *config.Config,
+	scheduleRepo repository.DoctorScheduleRepository,
+	bookingRepo repository.BookingRepository,
+	preferenceRepo repository.NotificationPreferenceRepository,
+	notificationService *NotificationService,
+	formatService FormatService,
+) *AgendaDigestService {
+	return &AgendaDigestService{
+		db:                  db,
+		redisClient:         redisClient,
+		log:                 log,
+		cfg:                 cfg,
+		scheduleRepo:        scheduleRepo,
+		bookingRepo:         bookingRepo,
+		preferenceRepo:      preferenceRepo,
+		notificationService: notificationService,
+		formatService:       formatService,
+		stopChan:            make(chan struct{}),
+	}
+}
+
+// Start launches the background scan loop.
+func (s *AgendaDigestService) Start() {
+	s.wg.Add(1)
+	go s.scanLoop()
+}
+
+// Stop gracefully shuts down the service.
+// Safe to call multiple times.
+func (s *AgendaDigestService) Stop() {
+	if s.stopped.CompareAndSwap(false, true) {
+		close(s.stopChan)
+		s.wg.Wait()
+		s.log.Info("AgendaDigestService stopped")
+	}
+}
+
+// scanLoop runs DispatchDue on every tick until stopped
+func (s *AgendaDigestService) scanLoop() {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(agendaDigestScanInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.stopChan:
+			s.log.Debug("Agenda digest goroutine stopping")
+			return
+		case <-ticker.C:
+			ctx, cancel := context.WithTimeout(context.Background(), agendaDigestScanInterval)
+			if err := s.DispatchDue(ctx); err != nil {
+				s.log.Warnf("Agenda digest scan failed: %+v", err)
+			}
+			cancel()
+		}
+	}
+}
+
+// DispatchDue records today's digests not sent yet, once the configured hour has passed
+func (s *AgendaDigestService) DispatchDue(ctx context.Context) error {
+	hour := s.cfg.Reminder.AgendaDigestHour
+	now := time.Now().In(s.cfg.App.Location)
+	if hour < 0 || now.Hour() < hour {
+		return nil
+	}
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+
+	schedules, err := s.scheduleRepo.FindAgendaByDate(s.db.WithContext(ctx), today)
+	if err != nil {
+		return fmt.Errorf("find schedules on %s: %w", today.Format("2006-01-02"), err)
+	}
+	if len(schedules) == 0 {
+		return nil
+	}
+
+	sentKey := RedisAgendaDigestSentKeyPrefix + today.Format("2006-01-02")
+	sent, err := s.redisClient.SMembers(ctx, sentKey).Result()
+	if err != nil {
+		return fmt.Errorf("get sent agenda digests: %w", err)
+	}
+	done := make(map[string]bool, len(sent))
+	for _, doctorID := range sent {
+		done[doctorID] = true
+	}
+
+	// Schedules come ordered by doctor
+	var doctorIDs []uuid.UUID
+	agendas := make(map[uuid.UUID][]entity.DoctorSchedule)
+	for _, schedule := range schedules {
+		if done[schedule.DoctorID.String()] {
+			continue
+		}
+		if _, ok := agendas[schedule.DoctorID]; !ok {
+			doctorIDs = append(doctorIDs, schedule.DoctorID)
+		}
+		agendas[schedule.DoctorID] = append(agendas[schedule.DoctorID], schedule)
+	}
+	if len(doctorIDs) == 0 {
+		return nil
+	}
+
+	preferences, err := s.preferenceRepo.FindByUserIDs(s.db.WithContext(ctx), doctorIDs)
+	if err != nil {
+		return fmt.Errorf("find notification preferences: %w", err)
+	}
+	optedOut := make(map[uuid.UUID]bool)
+	for _, preference := range preferences {
+		if !preference.AgendaDigest {
+			optedOut[preference.UserID] = true
+		}
+	}
+
+	var scheduleIDs []int
+	for _, doctorID := range doctorIDs {
+		if optedOut[doctorID] {
+			continue
+		}
+		for _, schedule := range agendas[doctorID] {
+			scheduleIDs = append(scheduleIDs, schedule.ID)
+		}
+	}
+	bookings, err := s.bookingRepo.FindActiveByScheduleIDs(s.db.WithContext(ctx), scheduleIDs)
+	if err != nil {
+		return fmt.Errorf("find bookings of today's schedules: %w", err)
+	}
+	bookingsBySchedule := make(map[int][]entity.Booking)
+	for _, b := range bookings {
+		bookingsBySchedule[b.ScheduleID] = append(bookingsBySchedule[b.ScheduleID], b)
+	}
+
+	var delivered, failed, skipped int64
+	for _, doctorID := range doctorIDs {
+		claimed, err := s.claim(ctx, sentKey, doctorID)
+		if err != nil {
+			return err
+		}
+		if !claimed {
+			continue
+		}
+
+		if optedOut[doctorID] {
+			skipped++
+			continue
+		}
+
+		if err := s.send(ctx, today, agendas[doctorID], bookingsBySchedule); err != nil {
+			s.log.Warnf("Failed to record agenda digest of doctor %s: %+v", doctorID, err)
+			failed++
+			// Released so the next scan tries again
+			if err := s.redisClient.SRem(ctx, sentKey, doctorID.String()).Err(); err != nil {
+				s.log.Warnf("Failed to release agenda digest claim of doctor %s: %+v", doctorID, err)
+			}
+			continue
+		}
+		delivered++
+	}
+
+	if delivered+failed+skipped > 0 {
+		s.log.Infof("Agenda digests for %s: %d sent, %d failed, %d skipped", today.Format("2006-01-02"), delivered, failed, skipped)
+	}
+	return nil
+}
+
+// claim adds the doctor to the sent set, false when another scan already claimed it
+func (s *AgendaDigestService) claim(ctx context.Context, key string, doctorID uuid.UUID) (bool, error) {
+	pipe := s.redisClient.TxPipeline()
+	added := pipe.SAdd(ctx, key, doctorID.String())
+	pipe.Expire(ctx, key, agendaDigestSentKeyTTL)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return false, fmt.Errorf("claim agenda digest of doctor %s: %w", doctorID, err)
+	}
+	return added.Val() == 1, nil
+}
+
+// send records the digest email of one doctor's schedules
+func (s *AgendaDigestService) send(ctx context.Context, date time.Time, schedules []entity.DoctorSchedule, bookings map[int][]entity.Booking) error {
+	doctor := schedules[0].Doctor
+	day := s.formatService.Date(time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, s.cfg.App.Location))
+	agenda := s.agenda(schedules, bookings)
+
+	return s.notificationService.Notify(ctx, s.db.WithContext(ctx), NotificationRequest{
+		PatientID: doctor.UserID,
+		EventType: agendaDigestEventType,
+		Subject:   fmt.Sprintf("Your agenda for %s", day),
+		Message:   fmt.Sprintf("Hello %s, here is your agenda for %s.\n\n%s", doctor.User.FullName, day, agenda),
+		DedupeKey: fmt.Sprintf("agenda_digest:%s:%s", doctor.UserID, date.Format("2006-01-02")),
+		Channel:   entity.NotificationChannelEmail,
+		Data: map[string]string{
+			entity.TemplateVarDoctorName: doctor.User.FullName,
+			entity.TemplateVarAgendaDate: day,
+			entity.TemplateVarAgenda:     agenda,
+		},
+	})
+}
+
+// agenda lists the schedules, each with its booked patients in queue order
+func (s *AgendaDigestService) agenda(schedules []entity.DoctorSchedule, bookings map[int][]entity.Booking) string {
+	var sb strings.Builder
+	for i := range schedules {
+		schedule := &schedules[i]
+		if i > 0 {
+			sb.WriteString("\n")
+		}
+		booked := bookings[schedule.ID]
+		fmt.Fprintf(&sb, "%s (%d booked)\n", s.formatService.ScheduleSlot(schedule), len(booked))
+		if len(booked) == 0 {
+			sb.WriteString("  No bookings yet\n")
+			continue
+		}
+		for j := range booked {
+			b := &booked[j]
+			b.Schedule = *schedule
+			line := fmt.Sprintf("  %d. %s (booking %s)", b.QueueNumber, b.Patient.User.FullName, b.BookingCode)
+			if b.Slot != nil {
+				if startAt, err := reminderVisit(b).StartDateTime(s.cfg.App.Location); err == nil {
+					line = fmt.Sprintf("  %d. %s %s (booking %s)", b.QueueNumber, s.formatService.Time(startAt), b.Patient.User.FullName, b.BookingCode)
+				}
+			}
+			sb.WriteString(line + "\n")
+		}
+	}
+	return strings.TrimRight(sb.String(), "\n")
+}
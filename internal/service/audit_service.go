@@ -2,6 +2,8 @@ package service
 
 import (
 	"context"
+	"fmt"
+	"time"
 
 	"go-template-clean-architecture/internal/domain/entity"
 	"go-template-clean-architecture/internal/domain/repository"
@@ -18,17 +20,80 @@ type AuditService interface {
 }
 
 type auditService struct {
-	db        *gorm.DB
-	log       *logrus.Logger
-	auditRepo repository.AuditLogRepository
+	db            *gorm.DB
+	log           *logrus.Logger
+	auditRepo     repository.AuditLogRepository
+	outboxService *OutboxService
 }
 
-func NewAuditService(db *gorm.DB, log *logrus.Logger, auditRepo repository.AuditLogRepository) AuditService {
-	return &auditService{
-		db:        db,
-		log:       log,
-		auditRepo: auditRepo,
+// auditedEvent is the audit log entry recorded for a domain event
+type auditedEvent struct {
+	action  string
+	removal bool // Logged as a delete: the payload is the old value
+}
+
+// auditedEvents are the domain events recorded in the audit trail, with their audit action
+var auditedEvents = map[string]auditedEvent{
+	entity.EventUserRegistered:     {action: entity.AuditActionUserRegister},
+	entity.EventUserLoggedIn:       {action: entity.AuditActionUserLogin},
+	entity.EventUserLoginFailed:    {action: entity.AuditActionLoginFailed},
+	entity.EventUserLoginLocked:    {action: entity.AuditActionLoginLocked},
+	entity.EventRefreshTokenReused: {action: entity.AuditActionTokenReuse},
+	entity.EventSessionRevoked:     {action: entity.AuditActionSessionRevoke, removal: true},
+	entity.EventSessionEvicted:     {action: entity.AuditActionSessionEvict, removal: true},
+	entity.EventRememberMeRevoked:  {action: entity.AuditActionRememberMeRevoke, removal: true},
+}
+
+// NewAuditService creates the audit trail and subscribes it to the audited domain events.
+// Security-sensitive actions on an account (entity.IsSecurityAlertAction) also enqueue a
+// security.alert event in the same transaction, so the user is told about them.
+func NewAuditService(db *gorm.DB, log *logrus.Logger, auditRepo repository.AuditLogRepository, outboxService *OutboxService, eventBus *EventBus) AuditService {
+	s := &auditService{
+		db:            db,
+		log:           log,
+		auditRepo:     auditRepo,
+		outboxService: outboxService,
 	}
+
+	for eventType := range auditedEvents {
+		eventBus.Subscribe(eventType, s.handleEvent)
+	}
+	return s
+}
+
+// handleEvent records an audited domain event, the event payload is the audited value.
+// The event is audited once however often it is handled (see EventBus), together with
+// its security alert.
+func (s *auditService) handleEvent(ctx context.Context, event *entity.DomainEvent) error {
+	audited := auditedEvents[event.Type]
+	metadata := entity.JSON{
+		"entity":    event.AggregateType,
+		"entity_id": event.AggregateID,
+		"old_value": nil,
+		"new_value": event.Payload,
+	}
+	if audited.removal {
+		metadata["old_value"], metadata["new_value"] = event.Payload, nil
+	}
+
+	auditLog := newAuditLog(ctx, event.ActorID, audited.action, metadata)
+	auditLog.EventID = &event.ID
+
+	tx := s.db.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	created, err := s.auditRepo.CreateIfAbsent(tx, auditLog)
+	if err != nil {
+		s.log.Warnf("Failed to create audit log of event %s: %+v", event.ID, err)
+		return err
+	}
+	if !created {
+		return nil
+	}
+	if err := s.enqueueSecurityAlert(tx, auditLog, event.AggregateType, event.AggregateID); err != nil {
+		return err
+	}
+	return tx.Commit().Error
 }
 
 // LogCreate logs a create action
@@ -40,18 +105,14 @@ func (s *auditService) LogCreate(ctx context.Context, tx *gorm.DB, userID *uuid.
 		"new_value": newValue,
 	}
 
-	auditLog := &entity.AuditLog{
-		UserID:   userID,
-		Action:   action,
-		Metadata: metadata,
-	}
+	auditLog := newAuditLog(ctx, userID, action, metadata)
 
 	if err := s.auditRepo.Create(tx, auditLog); err != nil {
 		s.log.Warnf("Failed to create audit log: %+v", err)
 		return err
 	}
 
-	return nil
+	return s.enqueueSecurityAlert(tx, auditLog, entityName, entityID)
 }
 
 // LogUpdate logs an update action with old and new values
@@ -63,18 +124,14 @@ func (s *auditService) LogUpdate(ctx context.Context, tx *gorm.DB, userID *uuid.
 		"new_value": newValue,
 	}
 
-	auditLog := &entity.AuditLog{
-		UserID:   userID,
-		Action:   action,
-		Metadata: metadata,
-	}
+	auditLog := newAuditLog(ctx, userID, action, metadata)
 
 	if err := s.auditRepo.Create(tx, auditLog); err != nil {
 		s.log.Warnf("Failed to create audit log: %+v", err)
 		return err
 	}
 
-	return nil
+	return s.enqueueSecurityAlert(tx, auditLog, entityName, entityID)
 }
 
 // LogDelete logs a delete action with old value
@@ -86,16 +143,75 @@ func (s *auditService) LogDelete(ctx context.Context, tx *gorm.DB, userID *uuid.
 		"new_value": nil,
 	}
 
-	auditLog := &entity.AuditLog{
-		UserID:   userID,
-		Action:   action,
-		Metadata: metadata,
-	}
+	auditLog := newAuditLog(ctx, userID, action, metadata)
 
 	if err := s.auditRepo.Create(tx, auditLog); err != nil {
 		s.log.Warnf("Failed to create audit log: %+v", err)
 		return err
 	}
 
+	return s.enqueueSecurityAlert(tx, auditLog, entityName, entityID)
+}
+
+// enqueueSecurityAlert publishes a security.alert event for the user of the account an
+// audited security-sensitive action was done on
+func (s *auditService) enqueueSecurityAlert(tx *gorm.DB, auditLog *entity.AuditLog, entityName, entityID string) error {
+	if !entity.IsSecurityAlertAction(auditLog.Action) || entityName != "user" {
+		return nil
+	}
+	userID, err := uuid.Parse(entityID)
+	if err != nil {
+		return nil
+	}
+
+	details, _ := auditLog.Metadata["new_value"].(entity.JSON)
+	payload := entity.SecurityAlertPayload{
+		UserID:     userID,
+		Action:     auditLog.Action,
+		Details:    details,
+		OccurredAt: time.Now(),
+	}
+	if auditLog.UserID != nil && *auditLog.UserID != userID {
+		payload.ActorID = auditLog.UserID
+	}
+
+	if err := s.outboxService.Enqueue(tx, entity.OutboxEventSecurityAlert, "user", userID.String(), payload); err != nil {
+		s.log.Warnf("Failed to enqueue security alert: %+v", err)
+		return fmt.Errorf("enqueue security alert %s: %w", auditLog.Action, err)
+	}
 	return nil
 }
+
+type impersonatorContextKey struct{}
+
+// ContextWithImpersonator returns a context for a request of an admin impersonating a user
+func ContextWithImpersonator(ctx context.Context, adminID uuid.UUID) context.Context {
+	return context.WithValue(ctx, impersonatorContextKey{}, adminID)
+}
+
+// ImpersonatorFromContext returns the admin impersonating the user of the request
+func ImpersonatorFromContext(ctx context.Context) (uuid.UUID, bool) {
+	adminID, ok := ctx.Value(impersonatorContextKey{}).(uuid.UUID)
+	return adminID, ok
+}
+
+// newAuditLog builds the audit log of an action. Requests authenticated by an API key
+// have no user: the key is recorded instead. Under impersonation the user is the
+// impersonated account and the admin is recorded as impersonator.
+func newAuditLog(ctx context.Context, userID *uuid.UUID, action string, metadata entity.JSON) *entity.AuditLog {
+	if userID != nil && *userID == uuid.Nil {
+		userID = nil
+	}
+	if apiKey, ok := APIKeyFromContext(ctx); ok {
+		metadata["api_key_id"] = apiKey.ID.String()
+	}
+	if adminID, ok := ImpersonatorFromContext(ctx); ok {
+		metadata["impersonator_id"] = adminID.String()
+	}
+
+	return &entity.AuditLog{
+		UserID:   userID,
+		Action:   action,
+		Metadata: metadata,
+	}
+}
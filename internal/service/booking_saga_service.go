@@ -0,0 +1,258 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go-template-clean-architecture/internal/domain/entity"
+	"go-template-clean-architecture/internal/domain/repository"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+const (
+	// Interval between recovery polls
+	bookingSagaRecoveryInterval = 30 * time.Second
+
+	// Unfinished sagas not updated for this long belong to a stopped process
+	bookingSagaStaleAfter = 2 * time.Minute
+
+	// Sagas recovered per poll
+	bookingSagaRecoveryBatch = 20
+
+	// Max time the compensation of one saga may take
+	bookingSagaCompensateTimeout = 10 * time.Second
+)
+
+// BookingSagaStep is one step of the booking saga.
+type BookingSagaStep struct {
+	Name string
+
+	// Execute performs the step and records its results on the saga
+	Execute func(ctx context.Context, saga *entity.BookingSaga) error
+
+	// Compensate undoes a completed step; nil when there is nothing to undo
+	Compensate func(ctx context.Context, saga *entity.BookingSaga) error
+
+	// Done reports whether the step took effect although the saga does not record it
+	// (the process stopped mid-step). Used by recovery; nil means it did not.
+	Done func(ctx context.Context, saga *entity.BookingSaga) (bool, error)
+}
+
+// BookingSagaService orchestrates the CreateBooking saga.
+//
+// Steps run in registration order and the saga state is persisted after each of them.
+// When a step fails, the completed steps are compensated in reverse order. A saga left
+// unfinished by a crash is picked up by the recovery worker, which rolls it forward when
+// its last step took effect and compensates it otherwise. Failed compensations leave the
+// saga in the failed status for an admin to correct (see RedisStateUsecase).
+type BookingSagaService struct {
+	db       *gorm.DB
+	log      *logrus.Logger
+	sagaRepo repository.BookingSagaRepository
+
+	stepsMu sync.RWMutex
+	steps   []BookingSagaStep
+
+	// Graceful shutdown
+	stopChan chan struct{}
+	wg       sync.WaitGroup
+	stopped  atomic.Bool
+}
+
+// NewBookingSagaService creates a new BookingSagaService.
+// Call Start() to begin recovering stale sagas and Stop() during graceful shutdown.
+func NewBookingSagaService(db *gorm.DB, log *logrus.Logger, sagaRepo repository.BookingSagaRepository) *BookingSagaService {
+	return &BookingSagaService{
+		db:       db,
+		log:      log,
+		sagaRepo: sagaRepo,
+		stopChan: make(chan struct{}),
+	}
+}
+
+// RegisterSteps appends steps to the saga
+func (s *BookingSagaService) RegisterSteps(steps ...BookingSagaStep) {
+	s.stepsMu.Lock()
+	defer s.stepsMu.Unlock()
+	s.steps = append(s.steps, steps...)
+}
+
+// Run persists the saga and executes every step.
+// On a step failure the completed steps are compensated and the step error is returned.
+func (s *BookingSagaService) Run(ctx context.Context, saga *entity.BookingSaga) error {
+	steps := s.registeredSteps()
+
+	saga.Status = entity.BookingSagaStatusRunning
+	saga.Step = ""
+	if err := s.sagaRepo.Create(s.db.WithContext(ctx), saga); err != nil {
+		return fmt.Errorf("create booking saga: %w", err)
+	}
+
+	for _, step := range steps {
+		if err := step.Execute(ctx, saga); err != nil {
+			// The request context may be cancelled already, compensation must still run
+			compensateCtx, cancel := context.WithTimeout(context.Background(), bookingSagaCompensateTimeout)
+			s.compensate(compensateCtx, s.db.WithContext(compensateCtx), saga, steps, fmt.Errorf("%s: %w", step.Name, err))
+			cancel()
+			return err
+		}
+
+		saga.Step = step.Name
+		s.save(s.db.WithContext(ctx), saga)
+	}
+
+	s.finish(s.db.WithContext(ctx), saga, entity.BookingSagaStatusCompleted)
+	return nil
+}
+
+// Start launches the background recovery loop.
+func (s *BookingSagaService) Start() {
+	s.wg.Add(1)
+	go s.recoveryLoop()
+}
+
+// Stop gracefully shuts down the service.
+// Safe to call multiple times.
+func (s *BookingSagaService) Stop() {
+	if s.stopped.CompareAndSwap(false, true) {
+		close(s.stopChan)
+		s.wg.Wait()
+		s.log.Info("BookingSagaService stopped")
+	}
+}
+
+// RecoverStale finishes one batch of sagas left unfinished by a stopped process.
+// Returns the number of sagas processed.
+func (s *BookingSagaService) RecoverStale(ctx context.Context) (int, error) {
+	steps := s.registeredSteps()
+
+	tx := s.db.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	sagas, err := s.sagaRepo.ClaimStale(tx, time.Now().Add(-bookingSagaStaleAfter), bookingSagaRecoveryBatch)
+	if err != nil {
+		return 0, fmt.Errorf("claim stale booking sagas: %w", err)
+	}
+
+	for i := range sagas {
+		saga := &sagas[i]
+		s.log.Warnf("Recovering booking saga %s (status=%s, step=%q)", saga.ID, saga.Status, saga.Step)
+
+		if saga.Status == entity.BookingSagaStatusCompensating {
+			s.compensate(ctx, tx, saga, steps, nil)
+			continue
+		}
+
+		// Roll forward over steps that took effect before the crash
+		next := stepIndex(steps, saga.Step) + 1
+		for ; next < len(steps) && steps[next].Done != nil; next++ {
+			done, err := steps[next].Done(ctx, saga)
+			if err != nil {
+				return i, fmt.Errorf("check step %s of booking saga %s: %w", steps[next].Name, saga.ID, err)
+			}
+			if !done {
+				break
+			}
+			saga.Step = steps[next].Name
+		}
+
+		if next == len(steps) {
+			s.finish(tx, saga, entity.BookingSagaStatusCompleted)
+			continue
+		}
+		s.compensate(ctx, tx, saga, steps, fmt.Errorf("interrupted before step %s", steps[next].Name))
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return 0, fmt.Errorf("commit booking saga recovery: %w", err)
+	}
+	return len(sagas), nil
+}
+
+// compensate undoes the completed steps in reverse order, persisting progress after each.
+// cause is recorded as the saga error; nil keeps the recorded one (resumed compensation).
+func (s *BookingSagaService) compensate(ctx context.Context, db *gorm.DB, saga *entity.BookingSaga, steps []BookingSagaStep, cause error) {
+	saga.Status = entity.BookingSagaStatusCompensating
+	if cause != nil {
+		saga.LastError = cause.Error()
+	}
+	s.save(db, saga)
+
+	for i := stepIndex(steps, saga.Step); i >= 0; i-- {
+		if steps[i].Compensate != nil {
+			if err := steps[i].Compensate(ctx, saga); err != nil {
+				s.log.Errorf("CRITICAL: Failed to compensate step %s of booking saga %s (schedule %d): %+v", steps[i].Name, saga.ID, saga.ScheduleID, err)
+				saga.LastError = fmt.Sprintf("compensate %s: %v", steps[i].Name, err)
+				s.finish(db, saga, entity.BookingSagaStatusFailed)
+				return
+			}
+		}
+
+		saga.Step = ""
+		if i > 0 {
+			saga.Step = steps[i-1].Name
+		}
+		s.save(db, saga)
+	}
+
+	s.finish(db, saga, entity.BookingSagaStatusCompensated)
+}
+
+// finish records a final status
+func (s *BookingSagaService) finish(db *gorm.DB, saga *entity.BookingSaga, status entity.BookingSagaStatus) {
+	now := time.Now()
+	saga.Status = status
+	saga.FinishedAt = &now
+	s.save(db, saga)
+}
+
+// save persists the saga state. Failures are logged only: the state is advisory for
+// recovery, which re-checks steps with Done before compensating.
+func (s *BookingSagaService) save(db *gorm.DB, saga *entity.BookingSaga) {
+	if err := s.sagaRepo.Save(db, saga); err != nil {
+		s.log.Warnf("Failed to save booking saga %s (status=%s, step=%q): %+v", saga.ID, saga.Status, saga.Step, err)
+	}
+}
+
+func (s *BookingSagaService) registeredSteps() []BookingSagaStep {
+	s.stepsMu.RLock()
+	defer s.stepsMu.RUnlock()
+	return s.steps
+}
+
+// recoveryLoop recovers stale sagas on every tick until stopped.
+func (s *BookingSagaService) recoveryLoop() {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(bookingSagaRecoveryInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.stopChan:
+			s.log.Debug("Booking saga recovery goroutine stopping")
+			return
+		case <-ticker.C:
+			ctx, cancel := context.WithTimeout(context.Background(), bookingSagaRecoveryBatch*bookingSagaCompensateTimeout)
+			if _, err := s.RecoverStale(ctx); err != nil {
+				s.log.Warnf("Booking saga recovery failed: %+v", err)
+			}
+			cancel()
+		}
+	}
+}
+
+// stepIndex returns the position of the named step, -1 for "" (no step completed)
+func stepIndex(steps []BookingSagaStep, name string) int {
+	for i, step := range steps {
+		if step.Name == name {
+			return i
+		}
+	}
+	return -1
+}
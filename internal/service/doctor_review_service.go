@@ -0,0 +1,163 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"go-template-clean-architecture/internal/domain/entity"
+	"go-template-clean-architecture/internal/domain/repository"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+const (
+	// Redis hash of the cached review data of a doctor: doctor_reviews:{doctorID}.
+	// Field "rating" holds the aggregated rating, "page:{page}:{limit}" the review pages.
+	// A new review deletes the hash.
+	RedisDoctorReviewsKeyPrefix = "doctor_reviews:"
+
+	doctorRatingField = "rating"
+
+	// Cached reviews expire even without invalidation (e.g. reviews removed in SQL)
+	doctorReviewsTTL = 10 * time.Minute
+)
+
+// DoctorReviewService serves the ratings and reviews of doctors from a Redis cache,
+// loading misses from the database. Falls back to the database when Redis is unavailable.
+type DoctorReviewService interface {
+	RatingStats(ctx context.Context, doctorIDs []uuid.UUID) (map[uuid.UUID]entity.DoctorRatingStat, error)
+	Reviews(ctx context.Context, doctorID uuid.UUID, page, limit int) ([]entity.DoctorReviewItem, int64, error)
+	Invalidate(ctx context.Context, doctorID uuid.UUID) error
+}
+
+type doctorReviewService struct {
+	db          *gorm.DB
+	log         *logrus.Logger
+	redisClient *redis.Client
+	reviewRepo  repository.DoctorReviewRepository
+}
+
+func NewDoctorReviewService(db *gorm.DB, log *logrus.Logger, redisClient *redis.Client, reviewRepo repository.DoctorReviewRepository) DoctorReviewService {
+	return &doctorReviewService{
+		db:          db,
+		log:         log,
+		redisClient: redisClient,
+		reviewRepo:  reviewRepo,
+	}
+}
+
+// cachedReviewPage is a page of reviews as cached
+type cachedReviewPage struct {
+	Items []entity.DoctorReviewItem `json:"items"`
+	Total int64                     `json:"total"`
+}
+
+// RatingStats returns the rating of each doctor. Doctors without reviews have a zero stat,
+// which is cached as well.
+func (s *doctorReviewService) RatingStats(ctx context.Context, doctorIDs []uuid.UUID) (map[uuid.UUID]entity.DoctorRatingStat, error) {
+	stats := make(map[uuid.UUID]entity.DoctorRatingStat, len(doctorIDs))
+	if len(doctorIDs) == 0 {
+		return stats, nil
+	}
+
+	pipe := s.redisClient.Pipeline()
+	cached := make([]*redis.StringCmd, len(doctorIDs))
+	for i, doctorID := range doctorIDs {
+		cached[i] = pipe.HGet(ctx, doctorReviewsKey(doctorID), doctorRatingField)
+	}
+	if _, err := pipe.Exec(ctx); err != nil && err != redis.Nil {
+		s.log.Warnf("Failed to get cached doctor ratings: %+v", err)
+	}
+
+	missing := make([]uuid.UUID, 0, len(doctorIDs))
+	for i, doctorID := range doctorIDs {
+		var stat entity.DoctorRatingStat
+		raw, err := cached[i].Result()
+		if err == nil && json.Unmarshal([]byte(raw), &stat) == nil {
+			stats[doctorID] = stat
+			continue
+		}
+		missing = append(missing, doctorID)
+	}
+	if len(missing) == 0 {
+		return stats, nil
+	}
+
+	loaded, err := s.reviewRepo.RatingByDoctorIDs(s.db.WithContext(ctx), missing)
+	if err != nil {
+		s.log.Warnf("Failed to get doctor ratings: %+v", err)
+		return nil, err
+	}
+	for _, doctorID := range missing {
+		stats[doctorID] = entity.DoctorRatingStat{DoctorID: doctorID}
+	}
+	for _, stat := range loaded {
+		stats[stat.DoctorID] = stat
+	}
+
+	cache := s.redisClient.Pipeline()
+	for _, doctorID := range missing {
+		s.cache(ctx, cache, doctorID, doctorRatingField, stats[doctorID])
+	}
+	if _, err := cache.Exec(ctx); err != nil {
+		s.log.Warnf("Failed to cache doctor ratings: %+v", err)
+	}
+
+	return stats, nil
+}
+
+// Reviews returns one page of the doctor's reviews, newest first, and the number of reviews
+func (s *doctorReviewService) Reviews(ctx context.Context, doctorID uuid.UUID, page, limit int) ([]entity.DoctorReviewItem, int64, error) {
+	key := doctorReviewsKey(doctorID)
+	field := fmt.Sprintf("page:%d:%d", page, limit)
+
+	var cached cachedReviewPage
+	raw, err := s.redisClient.HGet(ctx, key, field).Result()
+	if err != nil && err != redis.Nil {
+		s.log.Warnf("Failed to get cached reviews of doctor %s: %+v", doctorID, err)
+	}
+	if err == nil && json.Unmarshal([]byte(raw), &cached) == nil {
+		return cached.Items, cached.Total, nil
+	}
+
+	items, total, err := s.reviewRepo.FindByDoctorID(s.db.WithContext(ctx), doctorID, page, limit)
+	if err != nil {
+		s.log.Warnf("Failed to find reviews of doctor %s: %+v", doctorID, err)
+		return nil, 0, err
+	}
+
+	cache := s.redisClient.Pipeline()
+	s.cache(ctx, cache, doctorID, field, cachedReviewPage{Items: items, Total: total})
+	if _, err := cache.Exec(ctx); err != nil {
+		s.log.Warnf("Failed to cache reviews of doctor %s: %+v", doctorID, err)
+	}
+
+	return items, total, nil
+}
+
+// Invalidate drops the cached rating and review pages of the doctor
+func (s *doctorReviewService) Invalidate(ctx context.Context, doctorID uuid.UUID) error {
+	return s.redisClient.Del(ctx, doctorReviewsKey(doctorID)).Err()
+}
+
+// cache queues a field of the doctor's hash. The hash expires doctorReviewsTTL after
+// its first field was cached, later fields do not extend it.
+func (s *doctorReviewService) cache(ctx context.Context, pipe redis.Pipeliner, doctorID uuid.UUID, field string, value interface{}) {
+	raw, err := json.Marshal(value)
+	if err != nil {
+		s.log.Warnf("Failed to encode cached reviews of doctor %s: %+v", doctorID, err)
+		return
+	}
+	key := doctorReviewsKey(doctorID)
+	pipe.HSet(ctx, key, field, raw)
+	pipe.ExpireNX(ctx, key, doctorReviewsTTL)
+}
+
+func doctorReviewsKey(doctorID uuid.UUID) string {
+	return RedisDoctorReviewsKeyPrefix + doctorID.String()
+}
@@ -0,0 +1,352 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go-template-clean-architecture/config"
+	"go-template-clean-architecture/internal/domain/entity"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// Event bus backends (EVENT_BUS_BACKEND)
+	EventBusBackendMemory = "memory"
+	EventBusBackendRedis  = "redis"
+
+	// Redis stream of the published events and the consumer group of the instances
+	RedisEventStreamKey   = "domain_events"
+	redisEventStreamGroup = "subscribers"
+
+	// Approximate number of events kept in the stream
+	eventStreamMaxLen = 100000
+
+	// Events waiting for the in-process dispatcher
+	eventQueueSize = 1000
+
+	// Events read from the stream per call, and how long a read waits for new ones
+	eventReadBatchSize = 50
+	eventReadBlock     = 2 * time.Second
+
+	// Unacknowledged stream events are retried once idle for this long, until eventMaxDeliveries
+	eventRetryIdle     = 1 * time.Minute
+	eventMaxDeliveries = 5
+
+	// Max time the handlers of one event may run
+	eventHandlerTimeout = 10 * time.Second
+)
+
+// EventHandler handles one domain event. Events may be delivered more than once, so
+// handlers must tolerate repeats.
+type EventHandler func(ctx context.Context, event *entity.DomainEvent) error
+
+// EventBus delivers domain events to the handlers subscribed to their type, so usecases
+// announce what happened instead of running every side effect themselves.
+//
+// Events that must not be lost with a failed request are recorded with OutboxService.Enqueue
+// in the transaction of the change; once the outbox publishes them the bus relays them to the
+// subscribers, and a failing handler retries the event through the outbox. Publish hands
+// over events right after commit: with the memory backend they are dispatched by the
+// publishing instance and a failing handler is only logged; with the redis backend they are
+// appended to a Redis stream read by a consumer group of all instances, so each is handled
+// once and retried when a handler fails, up to eventMaxDeliveries times.
+type EventBus struct {
+	redisClient   *redis.Client
+	log           *logrus.Logger
+	outboxService *OutboxService
+	backend       string
+	consumer      string
+
+	handlersMu sync.RWMutex
+	handlers   map[string][]EventHandler
+
+	// Memory backend, also the fallback when the stream is unavailable
+	queue chan *entity.DomainEvent
+
+	// Graceful shutdown
+	stopChan chan struct{}
+	wg       sync.WaitGroup
+	stopped  atomic.Bool
+}
+
+// NewEventBus creates a new EventBus.
+// Subscribe the handlers, then call Start() to begin dispatching and Stop() during graceful shutdown.
+func NewEventBus(redisClient *redis.Client, log *logrus.Logger, cfg *config.Config, outboxService *OutboxService) *EventBus {
+	backend := cfg.EventBus.Backend
+	if backend != EventBusBackendRedis {
+		backend = EventBusBackendMemory
+	}
+	host, _ := os.Hostname()
+
+	return &EventBus{
+		redisClient:   redisClient,
+		log:           log,
+		outboxService: outboxService,
+		backend:       backend,
+		consumer:      fmt.Sprintf("%s-%d", host, os.Getpid()),
+		handlers:      make(map[string][]EventHandler),
+		queue:         make(chan *entity.DomainEvent, eventQueueSize),
+		stopChan:      make(chan struct{}),
+	}
+}
+
+// Subscribe adds a handler for an event type, also for the events of the type published
+// through the outbox. Handlers of one type run in the order they subscribed.
+func (b *EventBus) Subscribe(eventType string, handler EventHandler) {
+	b.handlersMu.Lock()
+	defer b.handlersMu.Unlock()
+
+	if _, ok := b.handlers[eventType]; !ok {
+		b.outboxService.RegisterHandler(eventType, b.relay)
+	}
+	b.handlers[eventType] = append(b.handlers[eventType], handler)
+}
+
+// Publish hands over an event whose change is committed. Fail-safe: events that cannot be
+// handed over are logged only, the change stands. Sets the event's ID and OccurredAt when empty.
+func (b *EventBus) Publish(ctx context.Context, event *entity.DomainEvent) {
+	if event.ID == "" {
+		event.ID = uuid.NewString()
+	}
+	if event.OccurredAt.IsZero() {
+		event.OccurredAt = time.Now()
+	}
+
+	if b.backend == EventBusBackendRedis {
+		err := b.appendToStream(ctx, event)
+		if err == nil {
+			return
+		}
+		b.log.Warnf("Failed to append event %s (%s) to the stream, dispatching in process: %+v", event.ID, event.Type, err)
+	}
+
+	if b.stopped.Load() {
+		b.log.Warnf("Event bus stopped, dropping event %s (%s)", event.ID, event.Type)
+		return
+	}
+	select {
+	case b.queue <- event:
+	default:
+		b.log.Warnf("Event queue full, dropping event %s (%s)", event.ID, event.Type)
+	}
+}
+
+// Start launches the dispatcher, and the stream consumer with the redis backend.
+func (b *EventBus) Start() {
+	b.wg.Add(1)
+	go b.dispatchLoop()
+
+	if b.backend == EventBusBackendRedis {
+		b.wg.Add(1)
+		go b.consumeLoop()
+	}
+	b.log.Infof("Event bus started (%s)", b.backend)
+}
+
+// Stop gracefully shuts down the bus, handling the events still queued in process.
+// Safe to call multiple times.
+func (b *EventBus) Stop() {
+	if b.stopped.CompareAndSwap(false, true) {
+		close(b.stopChan)
+		b.wg.Wait()
+		b.log.Info("EventBus stopped")
+	}
+}
+
+// relay delivers an event published by the outbox to the bus subscribers
+func (b *EventBus) relay(ctx context.Context, event *entity.OutboxEvent) error {
+	return b.dispatch(ctx, event.DomainEvent())
+}
+
+// dispatch runs every handler subscribed to the event type, stopping at the first failure
+func (b *EventBus) dispatch(ctx context.Context, event *entity.DomainEvent) error {
+	b.handlersMu.RLock()
+	handlers := b.handlers[event.Type]
+	b.handlersMu.RUnlock()
+
+	for _, handler := range handlers {
+		if err := handler(ctx, event); err != nil {
+			return fmt.Errorf("handle event %s (%s): %w", event.ID, event.Type, err)
+		}
+	}
+	return nil
+}
+
+// dispatchLoop handles the events published in process until stopped, then the ones left in the queue
+func (b *EventBus) dispatchLoop() {
+	defer b.wg.Done()
+
+	for {
+		select {
+		case event := <-b.queue:
+			b.dispatchQueued(event)
+		case <-b.stopChan:
+			for {
+				select {
+				case event := <-b.queue:
+					b.dispatchQueued(event)
+				default:
+					b.log.Debug("Event dispatcher goroutine stopping")
+					return
+				}
+			}
+		}
+	}
+}
+
+func (b *EventBus) dispatchQueued(event *entity.DomainEvent) {
+	ctx, cancel := context.WithTimeout(context.Background(), eventHandlerTimeout)
+	defer cancel()
+
+	if err := b.dispatch(ctx, event); err != nil {
+		b.log.Warnf("Event handler failed: %+v", err)
+	}
+}
+
+// appendToStream adds the event to the Redis stream
+func (b *EventBus) appendToStream(ctx context.Context, event *entity.DomainEvent) error {
+	raw, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("marshal event: %w", err)
+	}
+	return b.redisClient.XAdd(ctx, &redis.XAddArgs{
+		Stream: RedisEventStreamKey,
+		MaxLen: eventStreamMaxLen,
+		Approx: true,
+		Values: map[string]interface{}{"event": raw},
+	}).Err()
+}
+
+// consumeLoop reads the stream as a member of the consumer group until stopped, and retries
+// the events left unacknowledged by failed handlers or crashed instances
+func (b *EventBus) consumeLoop() {
+	defer b.wg.Done()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		<-b.stopChan
+		cancel()
+	}()
+
+	err := b.redisClient.XGroupCreateMkStream(ctx, RedisEventStreamKey, redisEventStreamGroup, "$").Err()
+	if err != nil && !strings.Contains(err.Error(), "BUSYGROUP") {
+		b.log.Errorf("Failed to create the event stream consumer group, stream events are not handled: %+v", err)
+		return
+	}
+
+	lastRetry := time.Now()
+	for ctx.Err() == nil {
+		if time.Since(lastRetry) >= eventRetryIdle/2 {
+			b.retryPending(ctx)
+			lastRetry = time.Now()
+		}
+
+		streams, err := b.redisClient.XReadGroup(ctx, &redis.XReadGroupArgs{
+			Group:    redisEventStreamGroup,
+			Consumer: b.consumer,
+			Streams:  []string{RedisEventStreamKey, ">"},
+			Count:    eventReadBatchSize,
+			Block:    eventReadBlock,
+		}).Result()
+		if err != nil {
+			if errors.Is(err, redis.Nil) || ctx.Err() != nil {
+				continue
+			}
+			b.log.Warnf("Failed to read the event stream: %+v", err)
+			select {
+			case <-ctx.Done():
+			case <-time.After(eventReadBlock):
+			}
+			continue
+		}
+
+		for _, stream := range streams {
+			for _, message := range stream.Messages {
+				b.handleMessage(ctx, message)
+			}
+		}
+	}
+	b.log.Debug("Event stream consumer goroutine stopping")
+}
+
+// retryPending claims the stream events unacknowledged for eventRetryIdle and handles them
+// again, events out of deliveries are dropped
+func (b *EventBus) retryPending(ctx context.Context) {
+	pending, err := b.redisClient.XPendingExt(ctx, &redis.XPendingExtArgs{
+		Stream: RedisEventStreamKey,
+		Group:  redisEventStreamGroup,
+		Idle:   eventRetryIdle,
+		Start:  "-",
+		End:    "+",
+		Count:  eventReadBatchSize,
+	}).Result()
+	if err != nil {
+		b.log.Warnf("Failed to list pending stream events: %+v", err)
+		return
+	}
+
+	var retry []string
+	for _, entry := range pending {
+		if entry.RetryCount >= eventMaxDeliveries {
+			b.log.Errorf("Stream event %s failed permanently after %d deliveries", entry.ID, entry.RetryCount)
+			if err := b.redisClient.XAck(ctx, RedisEventStreamKey, redisEventStreamGroup, entry.ID).Err(); err != nil {
+				b.log.Warnf("Failed to drop stream event %s: %+v", entry.ID, err)
+			}
+			continue
+		}
+		retry = append(retry, entry.ID)
+	}
+	if len(retry) == 0 {
+		return
+	}
+
+	messages, err := b.redisClient.XClaim(ctx, &redis.XClaimArgs{
+		Stream:   RedisEventStreamKey,
+		Group:    redisEventStreamGroup,
+		Consumer: b.consumer,
+		MinIdle:  eventRetryIdle,
+		Messages: retry,
+	}).Result()
+	if err != nil {
+		b.log.Warnf("Failed to claim pending stream events: %+v", err)
+		return
+	}
+	for _, message := range messages {
+		b.handleMessage(ctx, message)
+	}
+}
+
+// handleMessage dispatches a stream event and acknowledges it once handled.
+// Failed events stay pending for retryPending. A started event is finished during Stop.
+func (b *EventBus) handleMessage(ctx context.Context, message redis.XMessage) {
+	ctx = context.WithoutCancel(ctx)
+
+	var event entity.DomainEvent
+	raw, _ := message.Values["event"].(string)
+	if err := json.Unmarshal([]byte(raw), &event); err != nil {
+		b.log.Errorf("Dropping malformed stream event %s: %+v", message.ID, err)
+	} else {
+		handlerCtx, cancel := context.WithTimeout(ctx, eventHandlerTimeout)
+		err := b.dispatch(handlerCtx, &event)
+		cancel()
+		if err != nil {
+			b.log.Warnf("Event handler failed, retrying later: %+v", err)
+			return
+		}
+	}
+
+	if err := b.redisClient.XAck(ctx, RedisEventStreamKey, redisEventStreamGroup, message.ID).Err(); err != nil {
+		b.log.Warnf("Failed to acknowledge stream event %s: %+v", message.ID, err)
+	}
+}
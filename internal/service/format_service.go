@@ -0,0 +1,173 @@
+package service
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"go-template-clean-architecture/config"
+	"go-template-clean-architecture/internal/domain/entity"
+
+	"github.com/sirupsen/logrus"
+)
+
+// DefaultLocale is used when APP_LOCALE is empty or not supported
+const DefaultLocale = "id-ID"
+
+// FormatService formats dates and times for patient-facing output
+// (notifications, calendar exports, printable tickets) following the clinic locale.
+// All values are converted to the clinic timezone first.
+type FormatService interface {
+	Locale() string
+	Date(t time.Time) string
+	Time(t time.Time) string
+	DateTime(t time.Time) string
+	ScheduleSlot(schedule *entity.DoctorSchedule) string
+	Amount(amount int64, currency string) string
+}
+
+// localeFormat holds the naming and ordering conventions of a locale
+type localeFormat struct {
+	days      [7]string  // Sunday first, matches time.Weekday
+	months    [12]string // January first
+	dayFirst  bool       // "2 January 2006" vs "January 2, 2006"
+	hour12    bool       // "2:30 PM" vs "14.30"
+	timeSep   string
+	rangeJoin string
+	digitSep  string // Thousands separator of amounts
+	fractSep  string // Decimal separator of amounts
+}
+
+var supportedLocales = map[string]localeFormat{
+	"id-ID": {
+		days:      [7]string{"Minggu", "Senin", "Selasa", "Rabu", "Kamis", "Jumat", "Sabtu"},
+		months:    [12]string{"Januari", "Februari", "Maret", "April", "Mei", "Juni", "Juli", "Agustus", "September", "Oktober", "November", "Desember"},
+		dayFirst:  true,
+		timeSep:   ".",
+		rangeJoin: "–",
+		digitSep:  ".",
+		fractSep:  ",",
+	},
+	"en-US": {
+		days:      [7]string{"Sunday", "Monday", "Tuesday", "Wednesday", "Thursday", "Friday", "Saturday"},
+		months:    [12]string{"January", "February", "March", "April", "May", "June", "July", "August", "September", "October", "November", "December"},
+		hour12:    true,
+		timeSep:   ":",
+		rangeJoin: " – ",
+		digitSep:  ",",
+		fractSep:  ".",
+	},
+	"en-GB": {
+		days:      [7]string{"Sunday", "Monday", "Tuesday", "Wednesday", "Thursday", "Friday", "Saturday"},
+		months:    [12]string{"January", "February", "March", "April", "May", "June", "July", "August", "September", "October", "November", "December"},
+		dayFirst:  true,
+		timeSep:   ":",
+		rangeJoin: "–",
+		digitSep:  ",",
+		fractSep:  ".",
+	},
+}
+
+// Currencies whose amounts are kept in cents, the others (e.g. IDR) in whole units
+var centCurrencies = map[string]bool{
+	"USD": true, "EUR": true, "GBP": true, "SGD": true, "AUD": true, "MYR": true,
+}
+
+type formatService struct {
+	locale   string
+	format   localeFormat
+	location *time.Location
+}
+
+// NewFormatService creates a FormatService for the configured clinic locale.
+// Unsupported locales fall back to DefaultLocale with a warning.
+func NewFormatService(cfg *config.Config, log *logrus.Logger) FormatService {
+	locale := cfg.App.Locale
+	format, ok := supportedLocales[locale]
+	if !ok {
+		log.Warnf("Unsupported locale %q, falling back to %s", locale, DefaultLocale)
+		locale = DefaultLocale
+		format = supportedLocales[DefaultLocale]
+	}
+
+	return &formatService{
+		locale:   locale,
+		format:   format,
+		location: cfg.App.Location,
+	}
+}
+
+func (s *formatService) Locale() string {
+	return s.locale
+}
+
+// Date formats as e.g. "Senin, 2 Februari 2026" (id-ID) or "Monday, February 2, 2026" (en-US)
+func (s *formatService) Date(t time.Time) string {
+	t = t.In(s.location)
+	day := s.format.days[t.Weekday()]
+	month := s.format.months[t.Month()-1]
+
+	if s.format.dayFirst {
+		return fmt.Sprintf("%s, %d %s %d", day, t.Day(), month, t.Year())
+	}
+	return fmt.Sprintf("%s, %s %d, %d", day, month, t.Day(), t.Year())
+}
+
+// Time formats as e.g. "14.30" (id-ID) or "2:30 PM" (en-US)
+func (s *formatService) Time(t time.Time) string {
+	t = t.In(s.location)
+	if s.format.hour12 {
+		return t.Format("3" + s.format.timeSep + "04 PM")
+	}
+	return t.Format("15" + s.format.timeSep + "04")
+}
+
+// DateTime formats date and time with the timezone abbreviation, e.g. "Senin, 2 Februari 2026 14.30 WIB"
+func (s *formatService) DateTime(t time.Time) string {
+	return fmt.Sprintf("%s %s %s", s.Date(t), s.Time(t), t.In(s.location).Format("MST"))
+}
+
+// ScheduleSlot formats a schedule as e.g. "Senin, 2 Februari 2026, 08.00–12.00 WIB".
+// Falls back to the raw stored values if the schedule times cannot be parsed.
+func (s *formatService) ScheduleSlot(schedule *entity.DoctorSchedule) string {
+	startAt, err := schedule.StartDateTime(s.location)
+	if err != nil {
+		return strings.TrimSpace(fmt.Sprintf("%s %s-%s", schedule.ScheduleDate.Format("2006-01-02"), schedule.StartTime, schedule.EndTime))
+	}
+	endAt, err := schedule.EndDateTime(s.location)
+	if err != nil {
+		return s.DateTime(startAt)
+	}
+
+	return fmt.Sprintf("%s, %s%s%s %s", s.Date(startAt), s.Time(startAt), s.format.rangeJoin, s.Time(endAt), startAt.Format("MST"))
+}
+
+// Amount formats an amount in the smallest currency unit as e.g. "IDR 150.000" (id-ID)
+// or "USD 1,250.00" (en-US)
+func (s *formatService) Amount(amount int64, currency string) string {
+	sign := ""
+	if amount < 0 {
+		sign = "-"
+		amount = -amount
+	}
+
+	units, cents := amount, int64(-1)
+	if centCurrencies[currency] {
+		units, cents = amount/100, amount%100
+	}
+
+	digits := strconv.FormatInt(units, 10)
+	var grouped strings.Builder
+	for i, digit := range digits {
+		if i > 0 && (len(digits)-i)%3 == 0 {
+			grouped.WriteString(s.format.digitSep)
+		}
+		grouped.WriteRune(digit)
+	}
+	if cents >= 0 {
+		fmt.Fprintf(&grouped, "%s%02d", s.format.fractSep, cents)
+	}
+
+	return strings.TrimSpace(fmt.Sprintf("%s %s%s", currency, sign, grouped.String()))
+}
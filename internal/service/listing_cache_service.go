@@ -0,0 +1,96 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/sirupsen/logrus"
+)
+
+// Public listings cached by ListingCacheService
+const (
+	ListingDoctors   = "doctors"   // GET /doctors
+	ListingSchedules = "schedules" // GET /schedules
+)
+
+const (
+	// Redis hash of the cached pages of a public listing: public_listings:{listing}.
+	// Fields are the normalized query (filters, sort, page and limit), values the JSON pages.
+	// Mutations of doctors or schedules delete the hash.
+	RedisPublicListingsKeyPrefix = "public_listings:"
+)
+
+// listingTTLs bounds how long a listing is served from the cache. Schedules carry the live
+// remaining quota, which bookings change without invalidating, so they expire quickly.
+var listingTTLs = map[string]time.Duration{
+	ListingDoctors:   5 * time.Minute,
+	ListingSchedules: 30 * time.Second,
+}
+
+// ListingCacheService caches the pages of read-heavy public listings in Redis.
+// Caching is best-effort: Redis failures are logged and the caller reads the database.
+type ListingCacheService interface {
+	Get(ctx context.Context, listing, query string, dest interface{}) bool
+	Set(ctx context.Context, listing, query string, value interface{})
+	Invalidate(ctx context.Context, listings ...string)
+}
+
+type listingCacheService struct {
+	log         *logrus.Logger
+	redisClient *redis.Client
+}
+
+func NewListingCacheService(log *logrus.Logger, redisClient *redis.Client) ListingCacheService {
+	return &listingCacheService{
+		log:         log,
+		redisClient: redisClient,
+	}
+}
+
+// Get decodes the cached page of the query into dest, reporting whether it was cached
+func (s *listingCacheService) Get(ctx context.Context, listing, query string, dest interface{}) bool {
+	raw, err := s.redisClient.HGet(ctx, publicListingKey(listing), query).Result()
+	if err != nil {
+		if err != redis.Nil {
+			s.log.Warnf("Failed to get cached %s listing: %+v", listing, err)
+		}
+		return false
+	}
+	return json.Unmarshal([]byte(raw), dest) == nil
+}
+
+// Set caches a page of the listing. The hash expires with the TTL of the listing after its
+// first page was cached, later pages do not extend it.
+func (s *listingCacheService) Set(ctx context.Context, listing, query string, value interface{}) {
+	raw, err := json.Marshal(value)
+	if err != nil {
+		s.log.Warnf("Failed to encode cached %s listing: %+v", listing, err)
+		return
+	}
+
+	key := publicListingKey(listing)
+	pipe := s.redisClient.Pipeline()
+	pipe.HSet(ctx, key, query, raw)
+	pipe.ExpireNX(ctx, key, listingTTLs[listing])
+	if _, err := pipe.Exec(ctx); err != nil {
+		s.log.Warnf("Failed to cache %s listing: %+v", listing, err)
+	}
+}
+
+// Invalidate drops every cached page of the listings. Call it after the change is committed,
+// otherwise a concurrent read may cache the old data again.
+func (s *listingCacheService) Invalidate(ctx context.Context, listings ...string) {
+	keys := make([]string, len(listings))
+	for i, listing := range listings {
+		keys[i] = publicListingKey(listing)
+	}
+	if err := s.redisClient.Del(ctx, keys...).Err(); err != nil {
+		s.log.Warnf("Failed to invalidate cached %v listings: %+v", listings, err)
+	}
+}
+
+func publicListingKey(listing string) string {
+	return RedisPublicListingsKeyPrefix + listing
+}
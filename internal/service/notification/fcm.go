@@ -0,0 +1,228 @@
+package notification
+
+import (
+	"bytes"
+	"context"
+	"crypto/rsa"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"go-template-clean-architecture/config"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	fcmSendURL         = "https://fcm.googleapis.com/v1/projects/%s/messages:send"
+	fcmScope           = "https://www.googleapis.com/auth/firebase.messaging"
+	fcmDefaultTokenURI = "https://oauth2.googleapis.com/token"
+
+	fcmRequestTimeout = 10 * time.Second
+
+	// Access tokens are renewed this long before they expire
+	fcmTokenExpiryMargin = time.Minute
+)
+
+// fcmServiceAccount holds the fields of a Google service account key file
+type fcmServiceAccount struct {
+	ProjectID   string `json:"project_id"`
+	ClientEmail string `json:"client_email"`
+	PrivateKey  string `json:"private_key"`
+	TokenURI    string `json:"token_uri"`
+}
+
+// FCMSender pushes messages to devices through the Firebase Cloud Messaging HTTP v1 API.
+// The recipient is the FCM registration token of the device. It authenticates as a
+// service account and caches the access token until shortly before it expires.
+type FCMSender struct {
+	account      fcmServiceAccount
+	privateKey   *rsa.PrivateKey
+	defaultTitle string
+	httpClient   *http.Client
+
+	mu          sync.Mutex
+	accessToken string
+	expiresAt   time.Time
+}
+
+// NewFCMSender reads the service account key file of the Firebase project
+func NewFCMSender(cfg config.FCMConfig) (*FCMSender, error) {
+	raw, err := os.ReadFile(cfg.CredentialsFile)
+	if err != nil {
+		return nil, fmt.Errorf("read FCM credentials: %w", err)
+	}
+
+	var account fcmServiceAccount
+	if err := json.Unmarshal(raw, &account); err != nil {
+		return nil, fmt.Errorf("parse FCM credentials: %w", err)
+	}
+	if account.ProjectID == "" || account.ClientEmail == "" {
+		return nil, errors.New("FCM credentials miss project_id or client_email")
+	}
+	if account.TokenURI == "" {
+		account.TokenURI = fcmDefaultTokenURI
+	}
+
+	privateKey, err := jwt.ParseRSAPrivateKeyFromPEM([]byte(account.PrivateKey))
+	if err != nil {
+		return nil, fmt.Errorf("parse FCM private key: %w", err)
+	}
+
+	return &FCMSender{
+		account:      account,
+		privateKey:   privateKey,
+		defaultTitle: cfg.DefaultTitle,
+		httpClient:   &http.Client{Timeout: fcmRequestTimeout},
+	}, nil
+}
+
+func (s *FCMSender) Name() string {
+	return "fcm"
+}
+
+// Send pushes the message to the device. Tokens FCM no longer knows (the app was
+// uninstalled or the token rotated) and invalid messages are rejections.
+func (s *FCMSender) Send(ctx context.Context, message *Message) (string, error) {
+	title := message.Subject
+	if title == "" {
+		title = s.defaultTitle
+	}
+
+	body, err := json.Marshal(map[string]interface{}{
+		"message": map[string]interface{}{
+			"token": message.Recipient,
+			"notification": map[string]string{
+				"title": title,
+				"body":  message.Body,
+			},
+			// Lets the app open the matching screen
+			"data": map[string]string{
+				"notification_id": message.ID,
+				"event_type":      message.EventType,
+			},
+		},
+	})
+	if err != nil {
+		return "", err
+	}
+
+	accessToken, err := s.token(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, fmt.Sprintf(fcmSendURL, s.account.ProjectID), bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+accessToken)
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("send fcm message: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusOK:
+		var result struct {
+			Name string `json:"name"` // projects/{project}/messages/{id}
+		}
+		if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+			return "", fmt.Errorf("decode fcm response: %w", err)
+		}
+		return result.Name, nil
+	case resp.StatusCode == http.StatusNotFound, resp.StatusCode == http.StatusBadRequest:
+		return "", fmt.Errorf("%w: fcm returned %d: %s", ErrRejected, resp.StatusCode, fcmErrorStatus(resp.Body))
+	case resp.StatusCode == http.StatusUnauthorized:
+		s.resetToken()
+		return "", fmt.Errorf("fcm rejected the access token: %s", fcmErrorStatus(resp.Body))
+	default:
+		return "", fmt.Errorf("fcm returned %d: %s", resp.StatusCode, fcmErrorStatus(resp.Body))
+	}
+}
+
+// token returns a cached access token, or signs a new assertion and exchanges it
+func (s *FCMSender) token(ctx context.Context) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	if s.accessToken != "" && now.Before(s.expiresAt) {
+		return s.accessToken, nil
+	}
+
+	assertion, err := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{
+		"iss":   s.account.ClientEmail,
+		"scope": fcmScope,
+		"aud":   s.account.TokenURI,
+		"iat":   now.Unix(),
+		"exp":   now.Add(time.Hour).Unix(),
+	}).SignedString(s.privateKey)
+	if err != nil {
+		return "", fmt.Errorf("sign fcm assertion: %w", err)
+	}
+
+	form := url.Values{}
+	form.Set("grant_type", "urn:ietf:params:oauth:grant-type:jwt-bearer")
+	form.Set("assertion", assertion)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.account.TokenURI, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("get fcm access token: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("get fcm access token: %s returned %d", req.URL.Host, resp.StatusCode)
+	}
+
+	var token struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int    `json:"expires_in"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&token); err != nil {
+		return "", fmt.Errorf("decode fcm access token: %w", err)
+	}
+	if token.AccessToken == "" {
+		return "", errors.New("get fcm access token: no access token")
+	}
+
+	s.accessToken = token.AccessToken
+	s.expiresAt = now.Add(time.Duration(token.ExpiresIn)*time.Second - fcmTokenExpiryMargin)
+	return s.accessToken, nil
+}
+
+func (s *FCMSender) resetToken() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.accessToken = ""
+}
+
+// fcmErrorStatus extracts the error status of an FCM error response (e.g. UNREGISTERED)
+func fcmErrorStatus(body io.Reader) string {
+	var result struct {
+		Error struct {
+			Status  string `json:"status"`
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	if err := json.NewDecoder(io.LimitReader(body, 64<<10)).Decode(&result); err != nil {
+		return "unknown error"
+	}
+	return strings.TrimSpace(result.Error.Status + " " + result.Error.Message)
+}
@@ -0,0 +1,28 @@
+package notification
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+// LogSender writes messages to the log instead of sending them. It is the fallback for
+// channels without a provider; staff follow up on the logged messages. It never receives
+// delivery callbacks, so its notifications stay sent.
+type LogSender struct {
+	log *logrus.Logger
+}
+
+func NewLogSender(log *logrus.Logger) *LogSender {
+	return &LogSender{log: log}
+}
+
+func (s *LogSender) Name() string {
+	return "log"
+}
+
+func (s *LogSender) Send(ctx context.Context, message *Message) (string, error) {
+	s.log.Infof("Notification %s via %s %s: %s", message.ID, message.Channel, message.Recipient, message.Body)
+	return "log-" + uuid.NewString(), nil
+}
@@ -0,0 +1,59 @@
+// Package notification hands outbound messages to delivery providers (SMTP for email,
+// FCM for push, SMS/WhatsApp gateways). service.NotificationService persists and retries the
+// messages, a Sender only delivers one.
+package notification
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrRejected marks a permanent provider failure (invalid number, unknown mailbox...).
+// Senders wrap it with %w; any other error is treated as transient and retried.
+var ErrRejected = errors.New("notification rejected by provider")
+
+// Message is one notification to deliver. Channel is one of the entity.NotificationChannel*
+// values, Recipient a phone number or an email address depending on it.
+type Message struct {
+	ID        string // Notification ID, lets providers deduplicate retries
+	EventType string
+	Channel   string
+	Recipient string
+	Subject   string // Email subject or push title, senders fall back to a default when empty
+	Body      string
+}
+
+// Sender delivers messages through one provider
+type Sender interface {
+	// Name identifies the provider in delivery callbacks
+	Name() string
+	// Send delivers the message and returns the provider message ID
+	Send(ctx context.Context, message *Message) (string, error)
+}
+
+// Senders picks the sender of each channel; channels without one use the fallback
+type Senders struct {
+	fallback Sender
+	channels map[string]Sender
+}
+
+func NewSenders(fallback Sender) *Senders {
+	return &Senders{
+		fallback: fallback,
+		channels: make(map[string]Sender),
+	}
+}
+
+// Register sends the messages of the channel through the sender.
+// Call it during startup, before the notification worker starts.
+func (s *Senders) Register(channel string, sender Sender) {
+	s.channels[channel] = sender
+}
+
+// For returns the sender of the channel
+func (s *Senders) For(channel string) Sender {
+	if sender, ok := s.channels[channel]; ok {
+		return sender
+	}
+	return s.fallback
+}
@@ -0,0 +1,165 @@
+package notification
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"mime"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"time"
+
+	"go-template-clean-architecture/config"
+
+	"github.com/google/uuid"
+)
+
+// smtpImplicitTLSPort is the SMTPS port, connections to it are TLS from the start.
+// Other ports upgrade with STARTTLS when the server offers it.
+const smtpImplicitTLSPort = 465
+
+// SMTPSender sends email messages through an SMTP server
+type SMTPSender struct {
+	cfg  config.SMTPConfig
+	from mail.Address
+}
+
+// NewSMTPSender checks the sender address, the server is only contacted when sending
+func NewSMTPSender(cfg config.SMTPConfig) (*SMTPSender, error) {
+	from, err := mail.ParseAddress(cfg.From)
+	if err != nil {
+		return nil, fmt.Errorf("invalid SMTP_FROM %q: %w", cfg.From, err)
+	}
+	from.Name = cfg.FromName
+	return &SMTPSender{
+		cfg:  cfg,
+		from: *from,
+	}, nil
+}
+
+func (s *SMTPSender) Name() string {
+	return "smtp"
+}
+
+// Send delivers the message in one SMTP session. Invalid addresses and permanent
+// (5xx) replies are rejections, connection failures and 4xx replies are retried.
+func (s *SMTPSender) Send(ctx context.Context, message *Message) (string, error) {
+	to, err := mail.ParseAddress(message.Recipient)
+	if err != nil {
+		return "", fmt.Errorf("%w: invalid email address %q", ErrRejected, message.Recipient)
+	}
+
+	messageID := fmt.Sprintf("<%s@%s>", uuid.NewString(), s.domain())
+	data, err := s.compose(to, messageID, message)
+	if err != nil {
+		return "", err
+	}
+
+	if err := s.deliver(ctx, to.Address, data); err != nil {
+		var reply *textproto.Error
+		if errors.As(err, &reply) && reply.Code >= 500 {
+			return "", fmt.Errorf("%w: %v", ErrRejected, err)
+		}
+		return "", err
+	}
+	return messageID, nil
+}
+
+func (s *SMTPSender) deliver(ctx context.Context, to string, data []byte) error {
+	addr := net.JoinHostPort(s.cfg.Host, strconv.Itoa(s.cfg.Port))
+	dialer := &net.Dialer{}
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return fmt.Errorf("connect smtp server: %w", err)
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	tlsConfig := &tls.Config{ServerName: s.cfg.Host}
+	if s.cfg.Port == smtpImplicitTLSPort {
+		conn = tls.Client(conn, tlsConfig)
+	}
+
+	client, err := smtp.NewClient(conn, s.cfg.Host)
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("start smtp session: %w", err)
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok && s.cfg.Port != smtpImplicitTLSPort {
+		if err := client.StartTLS(tlsConfig); err != nil {
+			return fmt.Errorf("smtp starttls: %w", err)
+		}
+	}
+	if s.cfg.Username != "" {
+		if err := client.Auth(smtp.PlainAuth("", s.cfg.Username, s.cfg.Password, s.cfg.Host)); err != nil {
+			return fmt.Errorf("smtp auth: %w", err)
+		}
+	}
+
+	if err := client.Mail(s.from.Address); err != nil {
+		return err
+	}
+	if err := client.Rcpt(to); err != nil {
+		return err
+	}
+	w, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(data); err != nil {
+		w.Close()
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return client.Quit()
+}
+
+// compose builds a plain text UTF-8 message
+func (s *SMTPSender) compose(to *mail.Address, messageID string, message *Message) ([]byte, error) {
+	subject := message.Subject
+	if subject == "" {
+		subject = s.cfg.DefaultSubject
+	}
+
+	var buf bytes.Buffer
+	headers := []struct{ name, value string }{
+		{"From", s.from.String()},
+		{"To", to.String()},
+		{"Subject", mime.QEncoding.Encode("utf-8", subject)},
+		{"Date", time.Now().Format(time.RFC1123Z)},
+		{"Message-ID", messageID},
+		{"MIME-Version", "1.0"},
+		{"Content-Type", `text/plain; charset="utf-8"`},
+		{"Content-Transfer-Encoding", "8bit"},
+	}
+	for _, header := range headers {
+		if strings.ContainsAny(header.value, "\r\n") {
+			return nil, fmt.Errorf("%w: invalid %s header", ErrRejected, header.name)
+		}
+		fmt.Fprintf(&buf, "%s: %s\r\n", header.name, header.value)
+	}
+	buf.WriteString("\r\n")
+
+	// SMTP lines end with CRLF
+	body := strings.ReplaceAll(strings.ReplaceAll(message.Body, "\r\n", "\n"), "\n", "\r\n")
+	buf.WriteString(body)
+	buf.WriteString("\r\n")
+	return buf.Bytes(), nil
+}
+
+// domain is the domain of the sender address, used in message IDs
+func (s *SMTPSender) domain() string {
+	_, domain, _ := strings.Cut(s.from.Address, "@")
+	return domain
+}
@@ -0,0 +1,26 @@
+package notification
+
+import (
+	"strings"
+	"text/template"
+)
+
+// ParseTemplate compiles notification template text (Go text/template, variables as {{.Name}}).
+// Executing it with data that lacks a referenced variable fails instead of printing "<no value>".
+func ParseTemplate(name, text string) (*template.Template, error) {
+	return template.New(name).Option("missingkey=error").Parse(text)
+}
+
+// Render executes notification template text with the variables
+func Render(name, text string, data map[string]string) (string, error) {
+	tmpl, err := ParseTemplate(name, text)
+	if err != nil {
+		return "", err
+	}
+
+	var out strings.Builder
+	if err := tmpl.Execute(&out, data); err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(out.String()), nil
+}
@@ -0,0 +1,509 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go-template-clean-architecture/config"
+	"go-template-clean-architecture/internal/domain/entity"
+	"go-template-clean-architecture/internal/domain/repository"
+	"go-template-clean-architecture/internal/service/notification"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+const (
+	// Interval between notification polls
+	notificationPollInterval = 2 * time.Second
+
+	// Queued notifications per worker
+	notificationQueuePerWorker = 5
+
+	// Max time a single provider call may take
+	notificationSendTimeout = 10 * time.Second
+
+	// How long a claimed notification is reserved for the claiming instance. Covers the
+	// wait in the queue (notificationQueuePerWorker sends) and the send itself.
+	notificationLease = 2 * time.Minute
+
+	// Max time Stop waits for the workers to send the queued notifications
+	notificationDrainTimeout = 20 * time.Second
+
+	// Retry backoff: base * 2^(attempts-1), capped
+	notificationRetryBase = 30 * time.Second
+	notificationRetryMax  = time.Hour
+)
+
+// ErrNotificationRejected marks a permanent provider failure, retries stop (see notification.ErrRejected)
+var ErrNotificationRejected = notification.ErrRejected
+
+// NotificationRequest is a message to one patient.
+// DedupeKey identifies its source (e.g. "outbox:<event id>"), a repeated key is ignored.
+type NotificationRequest struct {
+	PatientID uuid.UUID
+	BookingID *uuid.UUID
+	EventType string
+	Subject   string // Email subject, the provider default when empty
+	Message   string
+	DedupeKey string
+	// Channel is the preferred channel, default the patient's preferred channel (see
+	// entity.NotificationPreference), else sms. Phone channels (sms, whatsapp) fall back
+	// to email for patients without a phone number, email falls back to sms for accounts
+	// without a real address.
+	Channel string
+	// Push also sends the message to every device the patient registered (FCM)
+	Push bool
+	// InApp also lists the message in the patient's notification center
+	InApp bool
+	// Data holds the template variables of the event (entity.TemplateVar*). PatientName,
+	// Subject and Message are added by Notify.
+	Data map[string]string
+}
+
+// NotificationService persists patient notifications and delivers them in the background.
+//
+// Notify only records the notification, so request handlers never wait for a provider.
+// A dispatcher claims due notifications into a bounded queue and a pool of
+// NOTIFICATION_WORKERS workers sends them through the sender of their channel, retrying
+// transient failures with exponential backoff until Notification.MaxAttempts. Every send
+// is logged as a NotificationAttempt. Rejected notifications and those out of attempts
+// become dead letters, which admins re-drive (Redrive). Providers later confirm delivery
+// through callbacks (NotificationUsecase), which move sent notifications to delivered or failed.
+//
+// The subject and message are rendered from the admin-defined template of the event,
+// channel and clinic locale when there is one, the request's text is the default.
+type NotificationService struct {
+	db               *gorm.DB
+	log              *logrus.Logger
+	cfg              *config.Config
+	notificationRepo repository.NotificationRepository
+	userRepo         repository.UserRepository
+	senders          *notification.Senders
+	deviceTokenRepo  repository.DeviceTokenRepository
+	templateRepo     repository.NotificationTemplateRepository
+	preferenceRepo   repository.NotificationPreferenceRepository
+
+	// Worker pool: the dispatcher queues claimed notifications to the workers
+	jobs        chan entity.Notification
+	workers     sync.WaitGroup
+	sendCtx     context.Context
+	cancelSends context.CancelFunc
+
+	// Graceful shutdown
+	stopChan chan struct{}
+	wg       sync.WaitGroup
+	stopped  atomic.Bool
+}
+
+// NewNotificationService creates a new NotificationService.
+// Call Start() to begin delivering and Stop() during graceful shutdown.
+func NewNotificationService(
+	db *gorm.DB,
+	log *logrus.Logger,
+	cfg *config.Config,
+	notificationRepo repository.NotificationRepository,
+	userRepo repository.UserRepository,
+	senders *notification.Senders,
+	deviceTokenRepo repository.DeviceTokenRepository,
+	templateRepo repository.NotificationTemplateRepository,
+	preferenceRepo repository.NotificationPreferenceRepository,
+) *NotificationService {
+	s := &NotificationService{
+		db:               db,
+		log:              log,
+		cfg:              cfg,
+		notificationRepo: notificationRepo,
+		userRepo:         userRepo,
+		senders:          senders,
+		deviceTokenRepo:  deviceTokenRepo,
+		templateRepo:     templateRepo,
+		preferenceRepo:   preferenceRepo,
+		stopChan:         make(chan struct{}),
+	}
+	s.jobs = make(chan entity.Notification, cfg.Notification.Workers*notificationQueuePerWorker)
+	s.sendCtx, s.cancelSends = context.WithCancel(context.Background())
+	return s
+}
+
+// Notify records a notification for delivery on the requested channel (see NotificationRequest.Channel)
+//
+// Safe to call again for the same DedupeKey (outbox handlers run at least once).
+// Patients with an inactive account get a failed notification for the history.
+// With Push, one more notification per registered device is recorded, and with InApp
+// one for the notification center, each deduplicated on its own key.
+func (s *NotificationService) Notify(ctx context.Context, db *gorm.DB, req NotificationRequest) error {
+	patient, err := s.userRepo.FindByID(db, req.PatientID)
+	if err != nil {
+		return fmt.Errorf("find notification recipient %s: %w", req.PatientID, err)
+	}
+	if patient == nil {
+		s.log.Warnf("Skipping %s notification, patient %s not found", req.EventType, req.PatientID)
+		return nil
+	}
+
+	templates, err := s.templateRepo.FindByEventType(db, req.EventType)
+	if err != nil {
+		return fmt.Errorf("find %s notification templates: %w", req.EventType, err)
+	}
+	data := map[string]string{
+		entity.TemplateVarPatientName: patient.FullName,
+		entity.TemplateVarSubject:     req.Subject,
+		entity.TemplateVarMessage:     req.Message,
+	}
+	for name, value := range req.Data {
+		data[name] = value
+	}
+
+	now := time.Now()
+	record := &entity.Notification{
+		PatientID:     req.PatientID,
+		BookingID:     req.BookingID,
+		DedupeKey:     req.DedupeKey,
+		EventType:     req.EventType,
+		Status:        entity.NotificationStatusPending,
+		NextAttemptAt: now,
+	}
+	channel := req.Channel
+	if channel == "" {
+		preference, err := s.preferenceRepo.FindByUserID(db, req.PatientID)
+		if err != nil {
+			return fmt.Errorf("find notification preference of %s: %w", req.PatientID, err)
+		}
+		if preference != nil {
+			channel = preference.Channel
+		}
+	}
+	record.Channel, record.Recipient = notificationRecipient(patient, channel)
+	s.renderContent(record, &req, templates, data)
+	if patient.IsActive != nil && !*patient.IsActive {
+		record.Status = entity.NotificationStatusFailed
+		record.LastError = "patient account is inactive"
+		record.FailedAt = &now
+	}
+
+	created, err := s.notificationRepo.CreateIfAbsent(db, record)
+	if err != nil {
+		return fmt.Errorf("record %s notification: %w", req.EventType, err)
+	}
+	if !created {
+		s.log.Debugf("Notification %s already recorded, skipping", req.DedupeKey)
+	}
+
+	if record.Status == entity.NotificationStatusFailed {
+		return nil
+	}
+
+	if req.InApp {
+		inApp := *record
+		inApp.ID = uuid.Nil
+		inApp.DedupeKey = req.DedupeKey + ":in_app"
+		inApp.Channel = entity.NotificationChannelInApp
+		inApp.Recipient = req.PatientID.String()
+		inApp.Status = entity.NotificationStatusDelivered
+		inApp.Provider = entity.NotificationChannelInApp
+		inApp.SentAt = &now
+		inApp.DeliveredAt = &now
+		s.renderContent(&inApp, &req, templates, data)
+		if _, err := s.notificationRepo.CreateIfAbsent(db, &inApp); err != nil {
+			return fmt.Errorf("record %s in-app notification: %w", req.EventType, err)
+		}
+	}
+
+	if req.Push {
+		devices, err := s.deviceTokenRepo.FindByUserID(db, req.PatientID)
+		if err != nil {
+			return fmt.Errorf("find devices of %s: %w", req.PatientID, err)
+		}
+		for _, device := range devices {
+			push := *record
+			push.ID = uuid.Nil
+			push.DedupeKey = fmt.Sprintf("%s:push:%d", req.DedupeKey, device.ID)
+			push.Channel = entity.NotificationChannelPush
+			push.Recipient = device.Token
+			s.renderContent(&push, &req, templates, data)
+			if _, err := s.notificationRepo.CreateIfAbsent(db, &push); err != nil {
+				return fmt.Errorf("record %s push notification: %w", req.EventType, err)
+			}
+		}
+	}
+	return nil
+}
+
+// Start launches the dispatcher and the worker pool.
+func (s *NotificationService) Start() {
+	for i := 0; i < s.cfg.Notification.Workers; i++ {
+		s.workers.Add(1)
+		go s.worker()
+	}
+	s.wg.Add(1)
+	go s.dispatchLoop()
+}
+
+// Stop gracefully shuts down the service: claiming stops and the workers send the queued
+// notifications. Sends still running after notificationDrainTimeout are cancelled, their
+// notifications are retried once their lease expires. Safe to call multiple times.
+func (s *NotificationService) Stop() {
+	if s.stopped.CompareAndSwap(false, true) {
+		close(s.stopChan)
+		s.wg.Wait()
+
+		drained := make(chan struct{})
+		go func() {
+			s.workers.Wait()
+			close(drained)
+		}()
+		select {
+		case <-drained:
+		case <-time.After(notificationDrainTimeout):
+			s.log.Warnf("Notification queue not drained after %s, cancelling sends", notificationDrainTimeout)
+			s.cancelSends()
+			<-drained
+		}
+		s.cancelSends()
+		s.log.Info("NotificationService stopped")
+	}
+}
+
+// ClaimDue leases up to limit due notifications to this instance. A leased notification is
+// not claimed again before notificationLease passes, so the jobs of a crashed instance are
+// picked up by another one.
+func (s *NotificationService) ClaimDue(ctx context.Context, limit int) ([]entity.Notification, error) {
+	tx := s.db.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	now := time.Now()
+	notifications, err := s.notificationRepo.ClaimDue(tx, now, limit)
+	if err != nil {
+		return nil, fmt.Errorf("claim notifications: %w", err)
+	}
+	if len(notifications) == 0 {
+		return nil, nil
+	}
+
+	ids := make([]uuid.UUID, len(notifications))
+	for i := range notifications {
+		ids[i] = notifications[i].ID
+	}
+	if err := s.notificationRepo.Lease(tx, ids, now.Add(notificationLease)); err != nil {
+		return nil, fmt.Errorf("lease notifications: %w", err)
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return nil, fmt.Errorf("commit notification claim: %w", err)
+	}
+	return notifications, nil
+}
+
+// Deliver sends one claimed notification through the sender of its channel and records the
+// attempt: sent, retried with backoff, or dead-lettered when rejected or out of attempts.
+func (s *NotificationService) Deliver(ctx context.Context, notification *entity.Notification) error {
+	attempts := notification.Attempts + 1
+
+	sender := s.senders.For(notification.Channel)
+	sendCtx, cancel := context.WithTimeout(ctx, notificationSendTimeout)
+	startedAt := time.Now()
+	messageID, sendErr := sender.Send(sendCtx, notificationMessage(notification))
+	cancel()
+
+	// The outcome is recorded even when the send was cut short by shutdown
+	tx := s.db.WithContext(context.WithoutCancel(ctx)).Begin()
+	defer tx.Rollback()
+
+	attempt := &entity.NotificationAttempt{
+		NotificationID:    notification.ID,
+		Attempt:           attempts,
+		Channel:           notification.Channel,
+		Recipient:         notification.Recipient,
+		Provider:          sender.Name(),
+		Succeeded:         sendErr == nil,
+		ProviderMessageID: messageID,
+		DurationMs:        time.Since(startedAt).Milliseconds(),
+	}
+	if sendErr != nil {
+		attempt.Error = sendErr.Error()
+	}
+	if err := s.notificationRepo.CreateAttempt(tx, attempt); err != nil {
+		return err
+	}
+
+	switch {
+	case sendErr == nil:
+		if err := s.notificationRepo.MarkSent(tx, notification.ID, attempts, sender.Name(), messageID, time.Now()); err != nil {
+			return err
+		}
+	case errors.Is(sendErr, ErrNotificationRejected) || attempts >= s.cfg.Notification.MaxAttempts:
+		s.log.Errorf("Notification %s (%s) dead-lettered after %d attempts: %+v", notification.ID, notification.EventType, attempts, sendErr)
+		if err := s.notificationRepo.MarkDeadLetter(tx, notification.ID, attempts, sendErr.Error(), time.Now()); err != nil {
+			return err
+		}
+		// The app was uninstalled or its token rotated, stop pushing to it
+		if errors.Is(sendErr, ErrNotificationRejected) && notification.Channel == entity.NotificationChannelPush {
+			if _, err := s.deviceTokenRepo.DeleteByToken(tx, notification.Recipient); err != nil {
+				return err
+			}
+		}
+	default:
+		s.log.Warnf("Notification %s (%s) failed, attempt %d: %+v", notification.ID, notification.EventType, attempts, sendErr)
+		if err := s.notificationRepo.MarkRetry(tx, notification.ID, attempts, time.Now().Add(notificationBackoff(attempts)), sendErr.Error()); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return fmt.Errorf("commit notification %s: %w", notification.ID, err)
+	}
+	return nil
+}
+
+// Redrive queues a dead letter again with a fresh attempt budget. Phone and email
+// notifications go to the patient's current address, so a corrected phone number or
+// email is used. Returns false when the notification is no longer a dead letter.
+func (s *NotificationService) Redrive(db *gorm.DB, n *entity.Notification) (bool, error) {
+	channel, recipient := n.Channel, n.Recipient
+	if channel != entity.NotificationChannelPush {
+		patient, err := s.userRepo.FindByID(db, n.PatientID)
+		if err != nil {
+			return false, fmt.Errorf("find notification recipient %s: %w", n.PatientID, err)
+		}
+		if patient != nil {
+			channel, recipient = notificationRecipient(patient, n.Channel)
+		}
+	}
+
+	affected, err := s.notificationRepo.Redrive(db, n.ID, channel, recipient, time.Now())
+	if err != nil {
+		return false, fmt.Errorf("redrive notification %s: %w", n.ID, err)
+	}
+	return affected > 0, nil
+}
+
+// dispatchLoop claims due notifications on every tick and queues them to the workers until
+// stopped, then closes the queue. It claims no more than the queue has room for, so a slow
+// provider holds back claiming instead of leases running out in the queue.
+func (s *NotificationService) dispatchLoop() {
+	defer s.wg.Done()
+	defer close(s.jobs)
+
+	ticker := time.NewTicker(notificationPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.stopChan:
+			s.log.Debug("Notification dispatcher stopping")
+			return
+		case <-ticker.C:
+			for !s.stopped.Load() {
+				room := cap(s.jobs) - len(s.jobs)
+				if room == 0 {
+					break
+				}
+
+				ctx, cancel := context.WithTimeout(context.Background(), notificationSendTimeout)
+				notifications, err := s.ClaimDue(ctx, room)
+				cancel()
+				if err != nil {
+					s.log.Warnf("Notification claim failed: %+v", err)
+					break
+				}
+				// Only the dispatcher sends to the queue, so there is room for every claimed job
+				for i := range notifications {
+					s.jobs <- notifications[i]
+				}
+				if len(notifications) < room {
+					break
+				}
+			}
+		}
+	}
+}
+
+// worker sends queued notifications until the queue is closed and drained
+func (s *NotificationService) worker() {
+	defer s.workers.Done()
+
+	for notification := range s.jobs {
+		if s.sendCtx.Err() != nil {
+			// Drain timed out, the lease expires and another instance sends it
+			continue
+		}
+		if err := s.Deliver(s.sendCtx, &notification); err != nil {
+			s.log.Warnf("Failed to record delivery of notification %s: %+v", notification.ID, err)
+		}
+	}
+}
+
+// notificationBackoff returns the delay before the given retry attempt
+func notificationBackoff(attempts int) time.Duration {
+	delay := notificationRetryBase << (attempts - 1)
+	if delay <= 0 || delay > notificationRetryMax {
+		return notificationRetryMax
+	}
+	return delay
+}
+
+// renderContent sets the subject and message of the notification from the best template for its
+// channel. The request's text is kept when there is no template or it fails to render.
+func (s *NotificationService) renderContent(n *entity.Notification, req *NotificationRequest, templates []entity.NotificationTemplate, data map[string]string) {
+	n.Subject, n.Message = req.Subject, req.Message
+
+	locale := s.cfg.App.Locale
+	if locale == "" {
+		locale = DefaultLocale
+	}
+	tmpl := entity.BestNotificationTemplate(templates, n.Channel, locale)
+	if tmpl == nil {
+		return
+	}
+
+	message, err := notification.Render("body", tmpl.Body, data)
+	if err != nil {
+		s.log.Warnf("Failed to render notification template %d for %s, using the default text: %+v", tmpl.ID, n.EventType, err)
+		return
+	}
+	subject := n.Subject
+	if tmpl.Subject != "" {
+		if subject, err = notification.Render("subject", tmpl.Subject, data); err != nil {
+			s.log.Warnf("Failed to render notification template %d for %s, using the default text: %+v", tmpl.ID, n.EventType, err)
+			return
+		}
+	}
+	n.Subject, n.Message = subject, message
+}
+
+// notificationRecipient picks the channel and address of a notification to the user
+func notificationRecipient(user *entity.User, channel string) (string, string) {
+	phone := ""
+	if user.PatientProfile != nil {
+		phone = user.PatientProfile.PhoneNumber
+	}
+	hasEmail := !strings.HasSuffix(user.Email, ".invalid")
+
+	switch {
+	case channel == entity.NotificationChannelEmail && hasEmail, phone == "":
+		return entity.NotificationChannelEmail, user.Email
+	case channel == entity.NotificationChannelWhatsApp:
+		return entity.NotificationChannelWhatsApp, phone
+	default:
+		return entity.NotificationChannelSMS, phone
+	}
+}
+
+// notificationMessage is the message a sender delivers for the notification
+func notificationMessage(n *entity.Notification) *notification.Message {
+	return &notification.Message{
+		ID:        n.ID.String(),
+		EventType: n.EventType,
+		Channel:   n.Channel,
+		Recipient: n.Recipient,
+		Subject:   n.Subject,
+		Body:      n.Message,
+	}
+}
@@ -0,0 +1,253 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go-template-clean-architecture/internal/domain/entity"
+	"go-template-clean-architecture/internal/domain/repository"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+const (
+	// Interval between outbox polls
+	outboxPollInterval = 2 * time.Second
+
+	// Events claimed per poll
+	outboxBatchSize = 50
+
+	// Max time a single handler may run
+	outboxHandlerTimeout = 10 * time.Second
+
+	// How long claimed events are skipped by other workers: the handlers of a whole batch
+	outboxClaimLease = outboxBatchSize * outboxHandlerTimeout
+
+	// Attempts before an event is marked failed
+	outboxMaxAttempts = 10
+
+	// Retry backoff: base * 2^(attempts-1), capped
+	outboxRetryBase = 5 * time.Second
+	outboxRetryMax  = 30 * time.Minute
+)
+
+// OutboxHandler publishes one event. Returning an error schedules a retry,
+// so handlers must tolerate being called more than once (at-least-once delivery).
+type OutboxHandler func(ctx context.Context, event *entity.OutboxEvent) error
+
+// OutboxService implements the transactional outbox.
+//
+// Producers call Enqueue with the SAME transaction as their state change, so an
+// event exists if and only if the change was committed. A background worker
+// polls due events and runs the handler registered for the event type,
+// retrying failures with exponential backoff.
+type OutboxService struct {
+	db         *gorm.DB
+	log        *logrus.Logger
+	outboxRepo repository.OutboxRepository
+
+	handlersMu sync.RWMutex
+	handlers   map[string][]OutboxHandler
+
+	// Graceful shutdown
+	stopChan chan struct{}
+	wg       sync.WaitGroup
+	stopped  atomic.Bool
+}
+
+// NewOutboxService creates a new OutboxService.
+// Register handlers, then call Start() to begin publishing and Stop() during graceful shutdown.
+func NewOutboxService(db *gorm.DB, log *logrus.Logger, outboxRepo repository.OutboxRepository) *OutboxService {
+	return &OutboxService{
+		db:         db,
+		log:        log,
+		outboxRepo: outboxRepo,
+		handlers:   make(map[string][]OutboxHandler),
+		stopChan:   make(chan struct{}),
+	}
+}
+
+// RegisterHandler adds a handler for an event type.
+// With several handlers, an event is retried as a whole if any of them fails.
+func (s *OutboxService) RegisterHandler(eventType string, handler OutboxHandler) {
+	s.handlersMu.Lock()
+	defer s.handlersMu.Unlock()
+	s.handlers[eventType] = append(s.handlers[eventType], handler)
+}
+
+// Enqueue records an event in the given transaction.
+// payload must be JSON-serializable; handlers read it back with OutboxEvent.DecodePayload.
+func (s *OutboxService) Enqueue(tx *gorm.DB, eventType string, aggregateType string, aggregateID string, payload interface{}) error {
+	return s.EnqueueAt(tx, time.Now(), eventType, aggregateType, aggregateID, payload)
+}
+
+// EnqueueAt records an event that is not published before the given time.
+// Used to spread bulk deliveries over time (throttling).
+func (s *OutboxService) EnqueueAt(tx *gorm.DB, at time.Time, eventType string, aggregateType string, aggregateID string, payload interface{}) error {
+	raw, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("marshal outbox payload %s: %w", eventType, err)
+	}
+	var data entity.JSON
+	if err := json.Unmarshal(raw, &data); err != nil {
+		return fmt.Errorf("marshal outbox payload %s: %w", eventType, err)
+	}
+
+	event := &entity.OutboxEvent{
+		AggregateType: aggregateType,
+		AggregateID:   aggregateID,
+		EventType:     eventType,
+		Payload:       data,
+		Status:        entity.OutboxStatusPending,
+		NextAttemptAt: at,
+	}
+	if err := s.outboxRepo.Create(tx, event); err != nil {
+		return fmt.Errorf("enqueue outbox event %s: %w", eventType, err)
+	}
+	return nil
+}
+
+// Start launches the background publish loop.
+func (s *OutboxService) Start() {
+	s.wg.Add(1)
+	go s.pollLoop()
+}
+
+// Stop gracefully shuts down the service.
+// Safe to call multiple times.
+func (s *OutboxService) Stop() {
+	if s.stopped.CompareAndSwap(false, true) {
+		close(s.stopChan)
+		s.wg.Wait()
+		s.log.Info("OutboxService stopped")
+	}
+}
+
+// PublishDue claims and publishes one batch of due events.
+// The claim is committed before the handlers run, so no row lock or transaction is held
+// while they work; each result is then recorded on its own. Events of a worker that
+// dies mid-batch are published again once their lease ends.
+// Returns the number of events processed.
+func (s *OutboxService) PublishDue(ctx context.Context) (int, error) {
+	now := time.Now()
+	events, err := s.claimDue(ctx, now)
+	if err != nil {
+		return 0, err
+	}
+
+	db := s.db.WithContext(ctx)
+	for i := range events {
+		event := &events[i]
+		if err := s.publish(ctx, event); err != nil {
+			attempts := event.Attempts + 1
+			if attempts >= outboxMaxAttempts {
+				s.log.Errorf("Outbox event %d (%s) failed permanently after %d attempts: %+v", event.ID, event.EventType, attempts, err)
+				if err := s.outboxRepo.MarkFailed(db, event.ID, attempts, err.Error()); err != nil {
+					return i, err
+				}
+				continue
+			}
+
+			s.log.Warnf("Outbox event %d (%s) failed, attempt %d: %+v", event.ID, event.EventType, attempts, err)
+			if err := s.outboxRepo.MarkRetry(db, event.ID, attempts, now.Add(outboxBackoff(attempts)), err.Error()); err != nil {
+				return i, err
+			}
+			continue
+		}
+
+		if err := s.outboxRepo.MarkPublished(db, event.ID, time.Now()); err != nil {
+			return i, err
+		}
+	}
+	return len(events), nil
+}
+
+// claimDue claims one batch of due events and leases them for outboxClaimLease
+func (s *OutboxService) claimDue(ctx context.Context, now time.Time) ([]entity.OutboxEvent, error) {
+	tx := s.db.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	events, err := s.outboxRepo.ClaimDue(tx, now, outboxBatchSize)
+	if err != nil {
+		return nil, fmt.Errorf("claim outbox events: %w", err)
+	}
+
+	ids := make([]int64, len(events))
+	for i := range events {
+		ids[i] = events[i].ID
+	}
+	if err := s.outboxRepo.Lease(tx, ids, now.Add(outboxClaimLease)); err != nil {
+		return nil, fmt.Errorf("lease outbox events: %w", err)
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return nil, fmt.Errorf("commit outbox claim: %w", err)
+	}
+	return events, nil
+}
+
+// publish runs every handler registered for the event type
+func (s *OutboxService) publish(ctx context.Context, event *entity.OutboxEvent) error {
+	s.handlersMu.RLock()
+	handlers := s.handlers[event.EventType]
+	s.handlersMu.RUnlock()
+
+	if len(handlers) == 0 {
+		s.log.Debugf("No outbox handler for %s, marking event %d published", event.EventType, event.ID)
+		return nil
+	}
+
+	handlerCtx, cancel := context.WithTimeout(ctx, outboxHandlerTimeout)
+	defer cancel()
+
+	for _, handler := range handlers {
+		if err := handler(handlerCtx, event); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// pollLoop publishes due events on every tick until stopped.
+// A full batch is followed immediately by the next one to drain backlogs.
+func (s *OutboxService) pollLoop() {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(outboxPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.stopChan:
+			s.log.Debug("Outbox goroutine stopping")
+			return
+		case <-ticker.C:
+			for {
+				ctx, cancel := context.WithTimeout(context.Background(), outboxClaimLease)
+				processed, err := s.PublishDue(ctx)
+				cancel()
+				if err != nil {
+					s.log.Warnf("Outbox publish failed: %+v", err)
+					break
+				}
+				if processed < outboxBatchSize || s.stopped.Load() {
+					break
+				}
+			}
+		}
+	}
+}
+
+// outboxBackoff returns the delay before the given retry attempt
+func outboxBackoff(attempts int) time.Duration {
+	delay := outboxRetryBase << (attempts - 1)
+	if delay <= 0 || delay > outboxRetryMax {
+		return outboxRetryMax
+	}
+	return delay
+}
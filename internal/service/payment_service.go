@@ -0,0 +1,145 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"go-template-clean-architecture/config"
+	"go-template-clean-architecture/internal/domain/entity"
+	"go-template-clean-architecture/internal/domain/repository"
+	"go-template-clean-architecture/pkg/payment"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// Callbacks reported shortly after the payment window closed still confirm the booking
+const paymentCallbackGrace = 2 * time.Minute
+
+var (
+	ErrPaymentUnavailable    = errors.New("payment could not be started, please try again later")
+	ErrUnknownPaymentGateway = errors.New("unknown payment gateway")
+)
+
+// PaymentService charges the consultation fees of bookings through the payment gateway
+// (PAYMENT_GATEWAY). Without a gateway bookings are free of charge.
+//
+// A charge is recorded as a pending payment before it is opened at the gateway, so the
+// callbacks always find it. The booking waits in awaiting_payment until a callback
+// reports the outcome, or is cancelled once the payment window closes.
+type PaymentService struct {
+	db          *gorm.DB
+	log         *logrus.Logger
+	cfg         *config.Config
+	paymentRepo repository.PaymentRepository
+	gateway     payment.Gateway
+}
+
+// NewPaymentService creates a new PaymentService, gateway is nil when payments are disabled
+func NewPaymentService(db *gorm.DB, log *logrus.Logger, cfg *config.Config, paymentRepo repository.PaymentRepository, gateway payment.Gateway) *PaymentService {
+	return &PaymentService{
+		db:          db,
+		log:         log,
+		cfg:         cfg,
+		paymentRepo: paymentRepo,
+		gateway:     gateway,
+	}
+}
+
+// Required reports whether bookings of the schedule are paid before they are confirmed.
+// The schedule must have its doctor loaded.
+func (s *PaymentService) Required(schedule *entity.DoctorSchedule) bool {
+	return s.gateway != nil && schedule.Doctor.ConsultationFee > 0
+}
+
+// Deadline is when a booking charged now is cancelled if still not paid
+func (s *PaymentService) Deadline() time.Time {
+	return time.Now().Add(s.cfg.Payment.Expiry + paymentCallbackGrace)
+}
+
+// Charge records the pending payment of a booking awaiting payment, the consultation fee plus
+// the admin fee of invoices (INVOICE_ADMIN_FEE), and opens it at the gateway. A charge the
+// gateway cannot open is recorded as failed and ErrPaymentUnavailable is returned; the caller
+// cancels the booking.
+func (s *PaymentService) Charge(ctx context.Context, booking *entity.Booking, consultationFee int64, payer *entity.User, description string) (*entity.Payment, error) {
+	if s.gateway == nil {
+		return nil, ErrPaymentUnavailable
+	}
+
+	p := &entity.Payment{
+		BookingID: booking.ID,
+		PatientID: booking.PatientID,
+		Gateway:   s.gateway.Name(),
+		Amount:    consultationFee + s.cfg.Invoice.AdminFee,
+		AdminFee:  s.cfg.Invoice.AdminFee,
+		Currency:  s.cfg.Payment.Currency,
+		Status:    entity.PaymentStatusPending,
+		ExpiresAt: time.Now().Add(s.cfg.Payment.Expiry),
+	}
+	if err := s.paymentRepo.Create(s.db.WithContext(ctx), p); err != nil {
+		return nil, fmt.Errorf("create payment of booking %s: %w", booking.ID, err)
+	}
+
+	charge := &payment.Charge{
+		OrderID:     p.ID.String(),
+		Amount:      p.Amount,
+		Currency:    p.Currency,
+		Description: description,
+		ExpiresAt:   p.ExpiresAt,
+	}
+	if payer != nil {
+		charge.PayerName = payer.FullName
+		charge.PayerEmail = payer.Email
+	}
+
+	result, err := s.gateway.CreateCharge(ctx, charge)
+	if err != nil {
+		s.log.Warnf("Failed to open payment %s of booking %s at %s: %+v", p.ID, booking.ID, p.Gateway, err)
+		if _, err := s.paymentRepo.Close(s.db.WithContext(ctx), p.ID, entity.PaymentStatusFailed, "payment gateway unavailable"); err != nil {
+			s.log.Warnf("Failed to close payment %s: %+v", p.ID, err)
+		}
+		return nil, ErrPaymentUnavailable
+	}
+
+	if err := s.paymentRepo.UpdateCharge(s.db.WithContext(ctx), p.ID, result.Reference, result.PaymentURL); err != nil {
+		return nil, fmt.Errorf("record charge of payment %s: %w", p.ID, err)
+	}
+	p.GatewayReference = result.Reference
+	p.PaymentURL = result.PaymentURL
+
+	s.log.Infof("Payment opened: id=%s, booking=%s, gateway=%s, amount=%d %s", p.ID, booking.ID, p.Gateway, p.Amount, p.Currency)
+	return p, nil
+}
+
+// Refund sends the requested refund of a payment to the gateway. Each request of the payment
+// is sent with its own key, so repeats of a request are refunded once. Errors wrap
+// payment.ErrUnavailable when the refund may be retried.
+func (s *PaymentService) Refund(ctx context.Context, p *entity.Payment) (*payment.RefundResult, error) {
+	if s.gateway == nil || p.Gateway != s.gateway.Name() {
+		return nil, fmt.Errorf("%w: payment %s was charged through %s", ErrUnknownPaymentGateway, p.ID, p.Gateway)
+	}
+	if p.RefundRequestedAt == nil {
+		return nil, fmt.Errorf("refund of payment %s was not requested", p.ID)
+	}
+
+	return s.gateway.Refund(ctx, &payment.Refund{
+		OrderID:   p.ID.String(),
+		Reference: p.GatewayReference,
+		Key:       strconv.FormatInt(p.RefundRequestedAt.UnixMilli(), 10),
+		Amount:    p.RefundAmount,
+		Currency:  p.Currency,
+		Reason:    p.RefundReason,
+	})
+}
+
+// ParseCallback verifies a callback posted to the URL of the named gateway
+func (s *PaymentService) ParseCallback(gatewayName string, header http.Header, body []byte) (*payment.Callback, error) {
+	if s.gateway == nil || gatewayName != s.gateway.Name() {
+		return nil, ErrUnknownPaymentGateway
+	}
+	return s.gateway.ParseCallback(header, body)
+}
@@ -0,0 +1,130 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"go-template-clean-architecture/internal/domain/repository"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+const (
+	// Redis set of the permission codes of a role: role_permissions:{roleID}
+	RedisRolePermissionsKeyPrefix = "role_permissions:"
+
+	// Unix time the permissions of a role last changed: role_permissions_changed:{roleID}.
+	// Permissions embedded in access tokens issued until then are outdated.
+	RedisRolePermissionsChangedKeyPrefix = "role_permissions_changed:"
+
+	// Marks a cached set, so a role without permissions is not reloaded on every request
+	rolePermissionsLoadedMarker = "*loaded*"
+
+	// Cached permissions expire even without invalidation (e.g. grants changed in SQL)
+	rolePermissionsTTL = 10 * time.Minute
+)
+
+// PermissionService answers whether a role has a permission.
+// The permissions of a role are cached in Redis and invalidated when they change.
+type PermissionService interface {
+	HasPermission(ctx context.Context, roleID int, permission string) (bool, error)
+	GetPermissions(ctx context.Context, roleID int) ([]string, error)
+	InvalidateRole(ctx context.Context, roleID int) error
+}
+
+type permissionService struct {
+	db             *gorm.DB
+	log            *logrus.Logger
+	redisClient    *redis.Client
+	permissionRepo repository.PermissionRepository
+}
+
+func NewPermissionService(db *gorm.DB, log *logrus.Logger, redisClient *redis.Client, permissionRepo repository.PermissionRepository) PermissionService {
+	return &permissionService{
+		db:             db,
+		log:            log,
+		redisClient:    redisClient,
+		permissionRepo: permissionRepo,
+	}
+}
+
+// HasPermission checks the cached permissions of the role, loading them on a miss.
+// Falls back to the database when Redis is unavailable.
+func (s *permissionService) HasPermission(ctx context.Context, roleID int, permission string) (bool, error) {
+	key := fmt.Sprintf("%s%d", RedisRolePermissionsKeyPrefix, roleID)
+
+	pipe := s.redisClient.Pipeline()
+	loaded := pipe.SIsMember(ctx, key, rolePermissionsLoadedMarker)
+	granted := pipe.SIsMember(ctx, key, permission)
+	_, err := pipe.Exec(ctx)
+	if err != nil {
+		s.log.Warnf("Failed to get cached permissions of role %d: %+v", roleID, err)
+	}
+	if err == nil && loaded.Val() {
+		return granted.Val(), nil
+	}
+
+	codes, err := s.loadPermissions(ctx, roleID)
+	if err != nil {
+		return false, err
+	}
+	return slices.Contains(codes, permission), nil
+}
+
+// GetPermissions returns the permission codes of the role, loading them on a miss
+func (s *permissionService) GetPermissions(ctx context.Context, roleID int) ([]string, error) {
+	key := fmt.Sprintf("%s%d", RedisRolePermissionsKeyPrefix, roleID)
+
+	members, err := s.redisClient.SMembers(ctx, key).Result()
+	if err != nil {
+		s.log.Warnf("Failed to get cached permissions of role %d: %+v", roleID, err)
+	}
+	if err == nil && slices.Contains(members, rolePermissionsLoadedMarker) {
+		return slices.DeleteFunc(members, func(code string) bool {
+			return code == rolePermissionsLoadedMarker
+		}), nil
+	}
+
+	return s.loadPermissions(ctx, roleID)
+}
+
+// loadPermissions reads the permissions of the role from the database and caches them
+func (s *permissionService) loadPermissions(ctx context.Context, roleID int) ([]string, error) {
+	key := fmt.Sprintf("%s%d", RedisRolePermissionsKeyPrefix, roleID)
+
+	codes, err := s.permissionRepo.FindCodesByRoleID(s.db.WithContext(ctx), roleID)
+	if err != nil {
+		s.log.Warnf("Failed to find permissions of role %d: %+v", roleID, err)
+		return nil, err
+	}
+
+	members := make([]interface{}, 0, len(codes)+1)
+	members = append(members, rolePermissionsLoadedMarker)
+	for _, code := range codes {
+		members = append(members, code)
+	}
+
+	cache := s.redisClient.TxPipeline()
+	cache.Del(ctx, key)
+	cache.SAdd(ctx, key, members...)
+	cache.Expire(ctx, key, rolePermissionsTTL)
+	if _, err := cache.Exec(ctx); err != nil {
+		s.log.Warnf("Failed to cache permissions of role %d: %+v", roleID, err)
+	}
+
+	return codes, nil
+}
+
+// InvalidateRole drops the cached permissions of the role, the next check reloads them.
+// Access tokens issued before no longer count on their embedded permissions.
+func (s *permissionService) InvalidateRole(ctx context.Context, roleID int) error {
+	pipe := s.redisClient.TxPipeline()
+	pipe.Del(ctx, fmt.Sprintf("%s%d", RedisRolePermissionsKeyPrefix, roleID))
+	pipe.Set(ctx, fmt.Sprintf("%s%d", RedisRolePermissionsChangedKeyPrefix, roleID), time.Now().Unix(), 0)
+	_, err := pipe.Exec(ctx)
+	return err
+}
@@ -4,12 +4,14 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strconv"
 	"sync"
 	"sync/atomic"
 	"time"
 
 	"go-template-clean-architecture/internal/domain/entity"
 
+	"github.com/google/uuid"
 	"github.com/redis/go-redis/v9"
 	"github.com/sirupsen/logrus"
 	"gorm.io/gorm"
@@ -22,6 +24,15 @@ import (
 // ErrQuotaFull is returned when schedule slot is fully booked
 var ErrQuotaFull = errors.New("schedule quota is full")
 
+// ErrWaitlistSlotsAvailable is returned when joining the waitlist of a schedule that still has quota
+var ErrWaitlistSlotsAvailable = errors.New("schedule still has remaining quota")
+
+// ErrAlreadyWaitlisted is returned when the patient is already on the schedule waitlist
+var ErrAlreadyWaitlisted = errors.New("patient is already on the waitlist")
+
+// ErrTimeSlotTaken is returned when the appointment slot is already booked
+var ErrTimeSlotTaken = errors.New("time slot is already booked")
+
 // decrQuotaIncrQueueScript is a package-level Lua script.
 // Redis Go client automatically uses EVALSHA (send SHA hash only) after the first call,
 // instead of EVAL (send full script text every time). This is significant for high-concurrency.
@@ -40,6 +51,112 @@ var decrQuotaIncrQueueScript = redis.NewScript(`
 	return queue
 `)
 
+// reserveSlotsScript reserves N slots at once (bulk reassignment).
+//
+// Logic:
+// 1. Read remaining quota; if less than N → return {-1, 0} (or reserve what is left when partial)
+// 2. DECRBY quota by N, INCRBY queue by N
+// 3. Return {reserved, first queue number}
+var reserveSlotsScript = redis.NewScript(`
+	local remaining = tonumber(redis.call('GET', KEYS[1]) or '0')
+	local n = tonumber(ARGV[1])
+	if remaining < n then
+		if ARGV[2] ~= '1' then
+			return {-1, 0}
+		end
+		n = remaining
+	end
+	if n <= 0 then
+		return {0, 0}
+	end
+	redis.call('DECRBY', KEYS[1], n)
+	local last = redis.call('INCRBY', KEYS[2], n)
+	return {n, last - n + 1}
+`)
+
+// joinWaitlistScript appends a patient to the schedule waitlist.
+//
+// Logic:
+// 1. If quota > 0 → return -2 (patient should book directly)
+// 2. If patient already in list → return -1
+// 3. RPUSH patient, refresh TTL and return position (1-based)
+var joinWaitlistScript = redis.NewScript(`
+	local remaining = tonumber(redis.call('GET', KEYS[1]) or '0')
+	if remaining > 0 then
+		return -2
+	end
+	if redis.call('LPOS', KEYS[2], ARGV[1]) then
+		return -1
+	end
+	local position = redis.call('RPUSH', KEYS[2], ARGV[1])
+	redis.call('EXPIRE', KEYS[2], tonumber(ARGV[2]))
+	return position
+`)
+
+// restoreOrPromoteScript hands a freed slot to the next waitlisted patient.
+//
+// Logic:
+// 1. LPOP waitlist; if empty → INCR quota and return {0, ”}
+// 2. Otherwise the slot is transferred: quota unchanged, INCR queue
+// 3. Return {queue number, patient id}
+var restoreOrPromoteScript = redis.NewScript(`
+	local patient = redis.call('LPOP', KEYS[3])
+	if not patient then
+		redis.call('INCR', KEYS[1])
+		return {0, ''}
+	end
+	local queue = redis.call('INCR', KEYS[2])
+	return {queue, patient}
+`)
+
+// reserveTimeSlotScript books one appointment slot of a slot-mode schedule.
+//
+// Logic:
+// 1. If slot ID already in the reserved set → return -2 (slot taken)
+// 2. DECR quota; if < 0 → INCR back and return -1 (quota full)
+// 3. SADD slot ID, align the set TTL with the quota key and return 1
+var reserveTimeSlotScript = redis.NewScript(`
+	if redis.call('SISMEMBER', KEYS[2], ARGV[1]) == 1 then
+		return -2
+	end
+	local remaining = redis.call('DECR', KEYS[1])
+	if remaining < 0 then
+		redis.call('INCR', KEYS[1])
+		return -1
+	end
+	redis.call('SADD', KEYS[2], ARGV[1])
+	local ttl = redis.call('PTTL', KEYS[1])
+	if ttl > 0 then
+		redis.call('PEXPIRE', KEYS[2], ttl)
+	end
+	return 1
+`)
+
+// releaseTimeSlotScript frees an appointment slot.
+// Idempotent: quota is only restored when the slot was still reserved (SREM returned 1).
+var releaseTimeSlotScript = redis.NewScript(`
+	if redis.call('SREM', KEYS[2], ARGV[1]) == 1 then
+		redis.call('INCR', KEYS[1])
+		return 1
+	end
+	return 0
+`)
+
+// advanceQueueScript raises the queue counter to at least ARGV[1] (never lowers it).
+// A missing key is left alone - the schedule is not synced and bookings fail anyway.
+var advanceQueueScript = redis.NewScript(`
+	local current = redis.call('GET', KEYS[1])
+	if not current then
+		return -1
+	end
+	local target = tonumber(ARGV[1])
+	if tonumber(current) < target then
+		redis.call('SET', KEYS[1], target, 'KEEPTTL')
+		return target
+	end
+	return tonumber(current)
+`)
+
 // =============================================================================
 // Constants
 // =============================================================================
@@ -48,6 +165,9 @@ const (
 	// Redis key prefixes for booking system
 	RedisQuotaKeyPrefix = "schedule:quota:"
 	RedisQueueKeyPrefix = "booking:queue:"
+	RedisWaitlistPrefix = "schedule:waitlist:"
+	RedisTimeSlotPrefix = "schedule:timeslots:" // SET of reserved appointment slot IDs
+	RedisReleasedPrefix = "schedule:released:"  // Events whose freed slot was released, per event ID
 
 	// Timeout for individual Redis operations
 	redisSyncTimeout = 5 * time.Second
@@ -106,6 +226,23 @@ type QuotaResult struct {
 	ScheduleDate   time.Time
 }
 
+// ScheduleRedisState is a snapshot of the Redis keys of one schedule.
+// A nil counter means the key does not exist; a nil TTL means the key is missing or never expires.
+type ScheduleRedisState struct {
+	Quota          *int
+	QuotaTTL       *time.Duration
+	QueueNumber    *int
+	QueueTTL       *time.Duration
+	WaitlistLength int64
+	ReservedSlots  int64
+}
+
+// WaitlistPromotion is the waitlisted patient who received a freed slot
+type WaitlistPromotion struct {
+	PatientID   uuid.UUID
+	QueueNumber int
+}
+
 // =============================================================================
 // Constructor
 // =============================================================================
@@ -150,7 +287,7 @@ func (s *RedisSyncService) Stop() {
 //
 // CRITICAL Fixes:
 // - Calculates MAX(queue_number) from bookings table (not reset to 0)
-// - Processes records in batches of 500
+// - Processes records in batches of 500 with keyset pagination (id > last_id, no OFFSET rescans)
 // - Creates and executes NEW pipeline INSIDE each batch loop
 //
 // Should be called BEFORE accepting traffic (during startup/disaster recovery).
@@ -165,11 +302,14 @@ func (s *RedisSyncService) SyncOnStartup(ctx context.Context) error {
 	}
 
 	today := time.Now().UTC().Truncate(24 * time.Hour)
-	offset := 0
+	lastID := 0
 	totalSynced := 0
+	batches := 0
+	var queryTime time.Duration
 
 	for {
 		var results []QuotaResult
+		queryStart := time.Now()
 
 		// Batch query: get schedules with calculated remaining quota AND max queue number
 		// CRITICAL FIX: Calculate MAX(queue_number) from bookings, not reset to 0
@@ -177,31 +317,45 @@ func (s *RedisSyncService) SyncOnStartup(ctx context.Context) error {
 			Select(`
 				doctor_schedules.id as schedule_id,
 				doctor_schedules.total_quota,
-				doctor_schedules.total_quota - COUNT(CASE WHEN bookings.status IS NOT NULL AND bookings.status != ? THEN 1 END) as remaining_quota,
+				doctor_schedules.total_quota - COUNT(CASE WHEN bookings.deleted_at IS NULL AND bookings.status IS NOT NULL AND bookings.status != ? THEN 1 END) as remaining_quota,
 				COALESCE(MAX(bookings.queue_number), 0) as max_queue_number,
 				doctor_schedules.schedule_date
 			`, string(entity.BookingStatusCancelled)).
+			// Deleted bookings keep their queue number (they can be restored), so they count toward
+			// the max queue number but not toward the quota
 			Joins("LEFT JOIN bookings ON bookings.schedule_id = doctor_schedules.id").
-			Where("doctor_schedules.schedule_date >= ?", today).
+			Where("doctor_schedules.schedule_date >= ? AND doctor_schedules.id > ?", today, lastID).
+			Where("doctor_schedules.approval_status = ?", entity.ScheduleApprovalApproved). // Proposals get keys on approval
 			Group("doctor_schedules.id, doctor_schedules.total_quota, doctor_schedules.schedule_date").
 			Order("doctor_schedules.id").
 			Limit(syncBatchSize).
-			Offset(offset).
 			Scan(&results).Error
+		queryTime += time.Since(queryStart)
 
 		if err != nil {
-			s.log.Errorf("Failed to query schedules at offset %d: %+v", offset, err)
-			return fmt.Errorf("query schedules at offset %d: %w", offset, err)
+			s.log.Errorf("Failed to query schedules after id %d: %+v", lastID, err)
+			return fmt.Errorf("query schedules after id %d: %w", lastID, err)
 		}
 
 		if len(results) == 0 {
-			if offset == 0 {
+			if lastID == 0 {
 				s.log.Info("No active schedules found for sync")
 			}
 			break
 		}
 
-		s.log.Infof("Processing batch: offset=%d, count=%d", offset, len(results))
+		batches++
+		s.log.Infof("Processing batch: after_id=%d, count=%d", lastID, len(results))
+
+		scheduleIDs := make([]int, len(results))
+		for i, result := range results {
+			scheduleIDs[i] = result.ScheduleID
+		}
+		reservedSlots, err := s.findReservedTimeSlots(ctx, scheduleIDs)
+		if err != nil {
+			s.log.Errorf("Failed to query reserved time slots after id %d: %+v", lastID, err)
+			return fmt.Errorf("query reserved time slots after id %d: %w", lastID, err)
+		}
 
 		// CRITICAL: Create NEW pipeline for THIS batch only
 		// This prevents memory accumulation across batches
@@ -218,12 +372,15 @@ func (s *RedisSyncService) SyncOnStartup(ctx context.Context) error {
 			// SET queue key with MAX(queue_number) from DB
 			// CRITICAL FIX: Use actual max queue number, not 0
 			pipe.Set(ctx, queueKey, result.MaxQueueNumber, ttl)
+
+			// Rebuild reserved time slot set (slot-mode schedules)
+			setReservedTimeSlots(ctx, pipe, result.ScheduleID, reservedSlots[result.ScheduleID], ttl)
 		}
 
 		// Execute pipeline for THIS batch
 		if _, err := pipe.Exec(ctx); err != nil {
-			s.log.Errorf("Failed to execute pipeline for batch at offset %d: %+v", offset, err)
-			return fmt.Errorf("pipeline exec at offset %d: %w", offset, err)
+			s.log.Errorf("Failed to execute pipeline for batch after id %d: %+v", lastID, err)
+			return fmt.Errorf("pipeline exec after id %d: %w", lastID, err)
 		}
 
 		totalSynced += len(results)
@@ -234,7 +391,7 @@ func (s *RedisSyncService) SyncOnStartup(ctx context.Context) error {
 			break
 		}
 
-		offset += syncBatchSize
+		lastID = results[len(results)-1].ScheduleID
 
 		// Respect context cancellation
 		select {
@@ -245,7 +402,12 @@ func (s *RedisSyncService) SyncOnStartup(ctx context.Context) error {
 	}
 
 	elapsed := time.Since(startTime)
-	s.log.Infof("Redis re-sync completed: %d schedules synced in %v", totalSynced, elapsed)
+	s.log.WithFields(logrus.Fields{
+		"schedules":  totalSynced,
+		"batches":    batches,
+		"duration":   elapsed.String(),
+		"query_time": queryTime.String(),
+	}).Infof("Redis re-sync completed: %d schedules synced in %v", totalSynced, elapsed)
 
 	return nil
 }
@@ -295,6 +457,13 @@ func (s *RedisSyncService) SyncScheduleQuota(ctx context.Context, scheduleID int
 		remainingQuota = 0
 	}
 
+	// Appointment slots held by active bookings (slot-mode schedules)
+	reservedSlots, err := s.findReservedTimeSlots(ctx, []int{scheduleID})
+	if err != nil {
+		s.log.Warnf("Failed to query reserved time slots for schedule %d: %+v", scheduleID, err)
+		return fmt.Errorf("query reserved time slots for schedule %d: %w", scheduleID, err)
+	}
+
 	quotaKey := fmt.Sprintf("%s%d", RedisQuotaKeyPrefix, scheduleID)
 	queueKey := fmt.Sprintf("%s%d", RedisQueueKeyPrefix, scheduleID)
 	ttl := s.calculateTTL(scheduleDate)
@@ -308,6 +477,9 @@ func (s *RedisSyncService) SyncScheduleQuota(ctx context.Context, scheduleID int
 	// SET queue with actual max from DB (not 0)
 	pipe.Set(ctx, queueKey, data.MaxQueueNumber, ttl)
 
+	// Rebuild reserved time slot set
+	setReservedTimeSlots(ctx, pipe, scheduleID, reservedSlots[scheduleID], ttl)
+
 	if _, err := pipe.Exec(ctx); err != nil {
 		s.log.Warnf("Failed to sync Redis for schedule %d: %+v", scheduleID, err)
 		return fmt.Errorf("redis sync for schedule %d: %w", scheduleID, err)
@@ -390,8 +562,10 @@ func (s *RedisSyncService) DeleteScheduleKeys(ctx context.Context, scheduleID in
 
 	quotaKey := fmt.Sprintf("%s%d", RedisQuotaKeyPrefix, scheduleID)
 	queueKey := fmt.Sprintf("%s%d", RedisQueueKeyPrefix, scheduleID)
+	waitlistKey := fmt.Sprintf("%s%d", RedisWaitlistPrefix, scheduleID)
+	timeSlotKey := fmt.Sprintf("%s%d", RedisTimeSlotPrefix, scheduleID)
 
-	if err := s.redisClient.Del(ctx, quotaKey, queueKey).Err(); err != nil {
+	if err := s.redisClient.Del(ctx, quotaKey, queueKey, waitlistKey, timeSlotKey).Err(); err != nil {
 		s.log.Warnf("Failed to delete Redis keys for schedule %d: %+v", scheduleID, err)
 		return fmt.Errorf("delete redis keys for schedule %d: %w", scheduleID, err)
 	}
@@ -431,6 +605,35 @@ func (s *RedisSyncService) DecrQuotaAndIncrQueue(ctx context.Context, scheduleID
 	return result, nil
 }
 
+// AdvanceQueueToDB raises the queue counter past the highest queue number held
+// by an active booking, so the next DecrQuotaAndIncrQueue hands out a free number.
+//
+// Used to recover from a duplicate queue number (e.g. the counter was resynced
+// below a number already taken). The counter is never lowered.
+//
+// Called by: CreateBooking usecase (retry on queue number conflict)
+func (s *RedisSyncService) AdvanceQueueToDB(ctx context.Context, scheduleID int) error {
+	var maxQueueNumber int
+	err := s.db.WithContext(ctx).Model(&entity.Booking{}).
+		Select("COALESCE(MAX(queue_number), 0)").
+		Where("schedule_id = ? AND status != ?", scheduleID, entity.BookingStatusCancelled).
+		Scan(&maxQueueNumber).Error
+	if err != nil {
+		s.log.Warnf("Failed to query max queue number for schedule %d: %+v", scheduleID, err)
+		return fmt.Errorf("query max queue number for schedule %d: %w", scheduleID, err)
+	}
+
+	queueKey := fmt.Sprintf("%s%d", RedisQueueKeyPrefix, scheduleID)
+	current, err := advanceQueueScript.Run(ctx, s.redisClient, []string{queueKey}, maxQueueNumber).Int()
+	if err != nil {
+		s.log.Warnf("Failed Lua script AdvanceQueueToDB for schedule %d: %+v", scheduleID, err)
+		return fmt.Errorf("lua advance_queue for schedule %d: %w", scheduleID, err)
+	}
+
+	s.log.Debugf("Advanced queue for schedule %d: db_max=%d, queue=%d", scheduleID, maxQueueNumber, current)
+	return nil
+}
+
 // RestoreQuota restores a booking slot when a booking is cancelled.
 //
 // IMPORTANT: Only increments quota, does NOT decrement queue number.
@@ -454,10 +657,416 @@ func (s *RedisSyncService) RestoreQuota(ctx context.Context, scheduleID int) err
 	return nil
 }
 
+// ClaimSlotRelease marks the slot freed by an event as released (SETNX on the event ID), so a
+// redelivered event does not release it again. The mark expires with the schedule's keys.
+//
+// Returns: false when the event already released its slot
+func (s *RedisSyncService) ClaimSlotRelease(ctx context.Context, eventID string, scheduleDate time.Time) (bool, error) {
+	claimed, err := s.redisClient.SetNX(ctx, RedisReleasedPrefix+eventID, "1", s.calculateTTL(scheduleDate)).Result()
+	if err != nil {
+		return false, fmt.Errorf("claim slot release of event %s: %w", eventID, err)
+	}
+	return claimed, nil
+}
+
+// UnclaimSlotRelease drops the mark of an event whose slot release failed, so the retry of
+// the event releases the slot. Fail-safe: errors are logged only.
+func (s *RedisSyncService) UnclaimSlotRelease(ctx context.Context, eventID string) {
+	if err := s.redisClient.Del(ctx, RedisReleasedPrefix+eventID).Err(); err != nil {
+		s.log.Warnf("Failed to drop slot release mark of event %s: %+v", eventID, err)
+	}
+}
+
+// RestoreQuotaOrPromote frees a booking slot, giving it to the next waitlisted patient if any.
+//
+// Executes LPOP waitlist + INCR queue (or INCR quota when the waitlist is empty)
+// as a single Lua script, so a freed slot is never visible to regular bookings
+// while a patient is waiting for it.
+//
+// Called by: CancelBooking usecase
+//
+// Returns: the promotion, or nil if the slot went back to the quota
+func (s *RedisSyncService) RestoreQuotaOrPromote(ctx context.Context, scheduleID int) (*WaitlistPromotion, error) {
+	// Acquire per-schedule mutex
+	mt := s.getScheduleMutex(scheduleID)
+	mt.mu.Lock()
+	defer mt.mu.Unlock()
+
+	quotaKey := fmt.Sprintf("%s%d", RedisQuotaKeyPrefix, scheduleID)
+	queueKey := fmt.Sprintf("%s%d", RedisQueueKeyPrefix, scheduleID)
+	waitlistKey := fmt.Sprintf("%s%d", RedisWaitlistPrefix, scheduleID)
+
+	result, err := restoreOrPromoteScript.Run(ctx, s.redisClient, []string{quotaKey, queueKey, waitlistKey}).Slice()
+	if err != nil {
+		s.log.Warnf("Failed Lua script RestoreQuotaOrPromote for schedule %d: %+v", scheduleID, err)
+		return nil, fmt.Errorf("lua restore_or_promote for schedule %d: %w", scheduleID, err)
+	}
+
+	queueNumber, _ := result[0].(int64)
+	if queueNumber == 0 {
+		s.log.Debugf("Restored quota for schedule %d (cancel)", scheduleID)
+		return nil, nil
+	}
+
+	rawPatientID, _ := result[1].(string)
+	patientID, err := uuid.Parse(rawPatientID)
+	if err != nil {
+		// Corrupt entry: the slot is already consumed from the waitlist, give it back to the quota
+		s.log.Warnf("Invalid waitlist entry %q for schedule %d, restoring quota", rawPatientID, scheduleID)
+		if err := s.redisClient.Incr(ctx, quotaKey).Err(); err != nil {
+			return nil, fmt.Errorf("restore quota for schedule %d: %w", scheduleID, err)
+		}
+		return nil, nil
+	}
+
+	s.log.Debugf("Promoted waitlisted patient %s on schedule %d: queue_number=%d", patientID, scheduleID, queueNumber)
+	return &WaitlistPromotion{PatientID: patientID, QueueNumber: int(queueNumber)}, nil
+}
+
+// JoinWaitlist adds a patient to the waitlist of a fully booked schedule.
+//
+// Returns: the patient's 1-based position on the waitlist
+func (s *RedisSyncService) JoinWaitlist(ctx context.Context, scheduleID int, patientID uuid.UUID, scheduleDate time.Time) (int, error) {
+	quotaKey := fmt.Sprintf("%s%d", RedisQuotaKeyPrefix, scheduleID)
+	waitlistKey := fmt.Sprintf("%s%d", RedisWaitlistPrefix, scheduleID)
+	ttl := s.calculateTTL(scheduleDate)
+
+	position, err := joinWaitlistScript.Run(ctx, s.redisClient, []string{quotaKey, waitlistKey}, patientID.String(), int(ttl.Seconds())).Int()
+	if err != nil {
+		s.log.Warnf("Failed Lua script JoinWaitlist for schedule %d: %+v", scheduleID, err)
+		return 0, fmt.Errorf("lua join_waitlist for schedule %d: %w", scheduleID, err)
+	}
+
+	switch position {
+	case -2:
+		return 0, ErrWaitlistSlotsAvailable
+	case -1:
+		return 0, ErrAlreadyWaitlisted
+	}
+
+	s.log.Debugf("Patient %s joined waitlist of schedule %d at position %d", patientID, scheduleID, position)
+	return position, nil
+}
+
+// LeaveWaitlist removes a patient from the schedule waitlist.
+//
+// Returns: true if the patient was on the waitlist
+func (s *RedisSyncService) LeaveWaitlist(ctx context.Context, scheduleID int, patientID uuid.UUID) (bool, error) {
+	waitlistKey := fmt.Sprintf("%s%d", RedisWaitlistPrefix, scheduleID)
+
+	removed, err := s.redisClient.LRem(ctx, waitlistKey, 0, patientID.String()).Result()
+	if err != nil {
+		s.log.Warnf("Failed to remove patient %s from waitlist of schedule %d: %+v", patientID, scheduleID, err)
+		return false, fmt.Errorf("leave waitlist for schedule %d: %w", scheduleID, err)
+	}
+
+	return removed > 0, nil
+}
+
+// LeaveAllWaitlists removes a patient from the waitlists of all schedules (account erasure).
+//
+// Returns: number of waitlists the patient was removed from
+func (s *RedisSyncService) LeaveAllWaitlists(ctx context.Context, patientID uuid.UUID) (int, error) {
+	left := 0
+	iter := s.redisClient.Scan(ctx, 0, RedisWaitlistPrefix+"*", 100).Iterator()
+	for iter.Next(ctx) {
+		removed, err := s.redisClient.LRem(ctx, iter.Val(), 0, patientID.String()).Result()
+		if err != nil {
+			return left, fmt.Errorf("leave waitlist %s: %w", iter.Val(), err)
+		}
+		if removed > 0 {
+			left++
+		}
+	}
+	if err := iter.Err(); err != nil {
+		return left, fmt.Errorf("scan waitlists: %w", err)
+	}
+	return left, nil
+}
+
+// ReserveSlots atomically reserves up to count slots on a schedule.
+//
+// If partial is false, either all slots are reserved or ErrQuotaFull is returned.
+// If partial is true, reserves as many slots as remain (possibly 0).
+//
+// Called by: bulk booking reassignment
+//
+// Returns: number of reserved slots and the first queue number of the reserved range
+func (s *RedisSyncService) ReserveSlots(ctx context.Context, scheduleID int, count int, partial bool) (int, int, error) {
+	quotaKey := fmt.Sprintf("%s%d", RedisQuotaKeyPrefix, scheduleID)
+	queueKey := fmt.Sprintf("%s%d", RedisQueueKeyPrefix, scheduleID)
+
+	partialArg := "0"
+	if partial {
+		partialArg = "1"
+	}
+
+	result, err := reserveSlotsScript.Run(ctx, s.redisClient, []string{quotaKey, queueKey}, count, partialArg).Int64Slice()
+	if err != nil {
+		s.log.Warnf("Failed Lua script ReserveSlots for schedule %d: %+v", scheduleID, err)
+		return 0, 0, fmt.Errorf("lua reserve_slots for schedule %d: %w", scheduleID, err)
+	}
+
+	if result[0] == -1 {
+		return 0, 0, ErrQuotaFull
+	}
+
+	s.log.Debugf("Reserved %d slots for schedule %d starting at queue %d", result[0], scheduleID, result[1])
+	return int(result[0]), int(result[1]), nil
+}
+
+// ReleaseSlots restores count slots on a schedule (bulk counterpart of RestoreQuota).
+//
+// IMPORTANT: Only increments quota, does NOT decrement queue number.
+//
+// Called by: bulk booking reassignment (source schedule and compensation)
+func (s *RedisSyncService) ReleaseSlots(ctx context.Context, scheduleID int, count int) error {
+	if count <= 0 {
+		return nil
+	}
+
+	// Acquire per-schedule mutex
+	mt := s.getScheduleMutex(scheduleID)
+	mt.mu.Lock()
+	defer mt.mu.Unlock()
+
+	quotaKey := fmt.Sprintf("%s%d", RedisQuotaKeyPrefix, scheduleID)
+
+	if err := s.redisClient.IncrBy(ctx, quotaKey, int64(count)).Err(); err != nil {
+		s.log.Warnf("Failed to release %d slots for schedule %d: %+v", count, scheduleID, err)
+		return fmt.Errorf("release slots for schedule %d: %w", scheduleID, err)
+	}
+
+	s.log.Debugf("Released %d slots for schedule %d", count, scheduleID)
+	return nil
+}
+
+// ReserveTimeSlot books an appointment slot of a slot-mode schedule (quota DECR + slot SADD in one Lua call).
+// Returns ErrTimeSlotTaken if the slot is booked, ErrQuotaFull if the schedule is full.
+func (s *RedisSyncService) ReserveTimeSlot(ctx context.Context, scheduleID int, slotID int64) error {
+	quotaKey := fmt.Sprintf("%s%d", RedisQuotaKeyPrefix, scheduleID)
+	timeSlotKey := fmt.Sprintf("%s%d", RedisTimeSlotPrefix, scheduleID)
+
+	result, err := reserveTimeSlotScript.Run(ctx, s.redisClient, []string{quotaKey, timeSlotKey}, slotID).Int()
+	if err != nil {
+		s.log.Warnf("Failed Lua script ReserveTimeSlot for schedule %d: %+v", scheduleID, err)
+		return fmt.Errorf("lua reserve_time_slot for schedule %d: %w", scheduleID, err)
+	}
+
+	switch result {
+	case -2:
+		return ErrTimeSlotTaken
+	case -1:
+		return ErrQuotaFull
+	}
+
+	s.log.Debugf("Reserved time slot %d for schedule %d", slotID, scheduleID)
+	return nil
+}
+
+// ReleaseTimeSlot frees an appointment slot and restores its quota.
+// Safe to call more than once for the same slot.
+func (s *RedisSyncService) ReleaseTimeSlot(ctx context.Context, scheduleID int, slotID int64) error {
+	// Acquire per-schedule mutex
+	mt := s.getScheduleMutex(scheduleID)
+	mt.mu.Lock()
+	defer mt.mu.Unlock()
+
+	quotaKey := fmt.Sprintf("%s%d", RedisQuotaKeyPrefix, scheduleID)
+	timeSlotKey := fmt.Sprintf("%s%d", RedisTimeSlotPrefix, scheduleID)
+
+	if err := releaseTimeSlotScript.Run(ctx, s.redisClient, []string{quotaKey, timeSlotKey}, slotID).Err(); err != nil {
+		s.log.Warnf("Failed to release time slot %d for schedule %d: %+v", slotID, scheduleID, err)
+		return fmt.Errorf("release time slot for schedule %d: %w", scheduleID, err)
+	}
+
+	s.log.Debugf("Released time slot %d for schedule %d", slotID, scheduleID)
+	return nil
+}
+
+// GetRemainingQuotas returns the remaining quota of several schedules, keyed by schedule ID.
+//
+// Reads all quota keys with a single MGET. Schedules without a key (past, expired
+// or not synced yet) fall back to TotalQuota - COUNT(non-cancelled bookings) from
+// the DB, as does the whole batch when Redis is unavailable.
+//
+// Called by: schedule listing usecases (read-only, no mutex needed)
+func (s *RedisSyncService) GetRemainingQuotas(ctx context.Context, schedules []entity.DoctorSchedule) (map[int]int, error) {
+	remaining := make(map[int]int, len(schedules))
+	if len(schedules) == 0 {
+		return remaining, nil
+	}
+
+	keys := make([]string, len(schedules))
+	for i, schedule := range schedules {
+		keys[i] = fmt.Sprintf("%s%d", RedisQuotaKeyPrefix, schedule.ID)
+	}
+
+	values, err := s.redisClient.MGet(ctx, keys...).Result()
+	if err != nil {
+		s.log.Warnf("Failed MGET remaining quota for %d schedules, falling back to DB: %+v", len(schedules), err)
+		values = nil
+	}
+
+	var missing []int
+	for i, schedule := range schedules {
+		if values != nil {
+			if raw, ok := values[i].(string); ok {
+				if quota, err := strconv.Atoi(raw); err == nil {
+					remaining[schedule.ID] = quota
+					continue
+				}
+			}
+		}
+		missing = append(missing, schedule.ID)
+	}
+	if len(missing) == 0 {
+		return remaining, nil
+	}
+
+	// DB fallback: count active bookings of the schedules Redis could not answer for
+	var rows []struct {
+		ScheduleID  int
+		BookedCount int
+	}
+	err = s.db.WithContext(ctx).Model(&entity.Booking{}).
+		Select("schedule_id, COUNT(*) as booked_count").
+		Where("schedule_id IN ? AND status != ?", missing, entity.BookingStatusCancelled).
+		Group("schedule_id").
+		Scan(&rows).Error
+	if err != nil {
+		s.log.Warnf("Failed to count bookings for %d schedules: %+v", len(missing), err)
+		return nil, fmt.Errorf("count bookings for remaining quota: %w", err)
+	}
+
+	booked := make(map[int]int, len(rows))
+	for _, row := range rows {
+		booked[row.ScheduleID] = row.BookedCount
+	}
+	for _, schedule := range schedules {
+		if _, ok := remaining[schedule.ID]; ok {
+			continue
+		}
+		quota := schedule.TotalQuota - booked[schedule.ID]
+		if quota < 0 {
+			quota = 0
+		}
+		remaining[schedule.ID] = quota
+	}
+
+	return remaining, nil
+}
+
+// InspectSchedule reads the quota and queue counters (with TTLs), the waitlist length
+// and the number of reserved time slots of a schedule in one pipeline.
+//
+// Called by: admin Redis state usecase (read-only, no mutex needed)
+func (s *RedisSyncService) InspectSchedule(ctx context.Context, scheduleID int) (*ScheduleRedisState, error) {
+	quotaKey := fmt.Sprintf("%s%d", RedisQuotaKeyPrefix, scheduleID)
+	queueKey := fmt.Sprintf("%s%d", RedisQueueKeyPrefix, scheduleID)
+	waitlistKey := fmt.Sprintf("%s%d", RedisWaitlistPrefix, scheduleID)
+	timeSlotKey := fmt.Sprintf("%s%d", RedisTimeSlotPrefix, scheduleID)
+
+	pipe := s.redisClient.Pipeline()
+	quotaCmd := pipe.Get(ctx, quotaKey)
+	quotaTTLCmd := pipe.PTTL(ctx, quotaKey)
+	queueCmd := pipe.Get(ctx, queueKey)
+	queueTTLCmd := pipe.PTTL(ctx, queueKey)
+	waitlistCmd := pipe.LLen(ctx, waitlistKey)
+	timeSlotCmd := pipe.SCard(ctx, timeSlotKey)
+
+	// redis.Nil from a missing counter is expected, checked per command below
+	if _, err := pipe.Exec(ctx); err != nil && err != redis.Nil {
+		s.log.Warnf("Failed to inspect Redis state of schedule %d: %+v", scheduleID, err)
+		return nil, fmt.Errorf("inspect redis state of schedule %d: %w", scheduleID, err)
+	}
+
+	state := &ScheduleRedisState{
+		QuotaTTL:       positiveTTL(quotaTTLCmd.Val()),
+		QueueTTL:       positiveTTL(queueTTLCmd.Val()),
+		WaitlistLength: waitlistCmd.Val(),
+		ReservedSlots:  timeSlotCmd.Val(),
+	}
+	if quota, err := quotaCmd.Int(); err == nil {
+		state.Quota = &quota
+	}
+	if queueNumber, err := queueCmd.Int(); err == nil {
+		state.QueueNumber = &queueNumber
+	}
+	return state, nil
+}
+
+// SetScheduleCounters overwrites the quota and/or queue counter of a schedule
+// (manual drift correction). A nil value leaves that counter untouched.
+// The TTL is reset from the schedule date, as in SyncScheduleQuota.
+//
+// Called by: admin Redis state usecase (after validating the values against the DB)
+func (s *RedisSyncService) SetScheduleCounters(ctx context.Context, scheduleID int, quota, queueNumber *int, scheduleDate time.Time) error {
+	// Acquire per-schedule mutex
+	mt := s.getScheduleMutex(scheduleID)
+	mt.mu.Lock()
+	defer mt.mu.Unlock()
+
+	ttl := s.calculateTTL(scheduleDate)
+
+	pipe := s.redisClient.TxPipeline()
+	if quota != nil {
+		pipe.Set(ctx, fmt.Sprintf("%s%d", RedisQuotaKeyPrefix, scheduleID), *quota, ttl)
+	}
+	if queueNumber != nil {
+		pipe.Set(ctx, fmt.Sprintf("%s%d", RedisQueueKeyPrefix, scheduleID), *queueNumber, ttl)
+	}
+
+	if _, err := pipe.Exec(ctx); err != nil {
+		s.log.Warnf("Failed to set Redis counters of schedule %d: %+v", scheduleID, err)
+		return fmt.Errorf("set redis counters of schedule %d: %w", scheduleID, err)
+	}
+
+	s.log.Infof("Manually set Redis counters of schedule %d: quota=%s, queue=%s, TTL=%v", scheduleID, derefInt(quota), derefInt(queueNumber), ttl)
+	return nil
+}
+
 // =============================================================================
 // Private Helper Methods
 // =============================================================================
 
+// findReservedTimeSlots returns the appointment slot IDs held by active bookings, per schedule
+func (s *RedisSyncService) findReservedTimeSlots(ctx context.Context, scheduleIDs []int) (map[int][]int64, error) {
+	var rows []struct {
+		ScheduleID int
+		SlotID     int64
+	}
+	err := s.db.WithContext(ctx).Model(&entity.Booking{}).
+		Select("schedule_id, slot_id").
+		Where("schedule_id IN ? AND slot_id IS NOT NULL AND status != ?", scheduleIDs, entity.BookingStatusCancelled).
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	reserved := make(map[int][]int64)
+	for _, row := range rows {
+		reserved[row.ScheduleID] = append(reserved[row.ScheduleID], row.SlotID)
+	}
+	return reserved, nil
+}
+
+// setReservedTimeSlots queues a rebuild of the reserved time slot set on pipe
+func setReservedTimeSlots(ctx context.Context, pipe redis.Pipeliner, scheduleID int, slotIDs []int64, ttl time.Duration) {
+	timeSlotKey := fmt.Sprintf("%s%d", RedisTimeSlotPrefix, scheduleID)
+	pipe.Del(ctx, timeSlotKey)
+	if len(slotIDs) == 0 {
+		return
+	}
+
+	members := make([]interface{}, len(slotIDs))
+	for i, slotID := range slotIDs {
+		members[i] = slotID
+	}
+	pipe.SAdd(ctx, timeSlotKey, members...)
+	pipe.Expire(ctx, timeSlotKey, ttl)
+}
+
 // getScheduleMutex returns mutex for a specific schedule ID
 func (s *RedisSyncService) getScheduleMutex(scheduleID int) *mutexWithTimestamp {
 	mt, _ := s.scheduleMu.LoadOrStore(scheduleID, &mutexWithTimestamp{})
@@ -531,3 +1140,19 @@ func (s *RedisSyncService) calculateTTL(scheduleDate time.Time) time.Duration {
 
 	return ttl
 }
+
+// positiveTTL converts a PTTL reply to a TTL, nil for a missing key (-2) or no expiry (-1)
+func positiveTTL(ttl time.Duration) *time.Duration {
+	if ttl <= 0 {
+		return nil
+	}
+	return &ttl
+}
+
+// derefInt formats an optional counter for logging
+func derefInt(value *int) string {
+	if value == nil {
+		return "unchanged"
+	}
+	return strconv.Itoa(*value)
+}
@@ -0,0 +1,95 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/sirupsen/logrus"
+)
+
+// benchScheduleID is the schedule whose keys the benchmarks use, far above real ids
+// so a run against a shared Redis does not touch live schedules
+const benchScheduleID = 900000001
+
+// newBenchRedisSyncService returns a RedisSyncService on miniredis, or on the Redis at
+// BENCH_REDIS_ADDR when set, with the quota of benchScheduleID set to quota
+func newBenchRedisSyncService(b *testing.B, quota int) *RedisSyncService {
+	b.Helper()
+
+	addr := os.Getenv("BENCH_REDIS_ADDR")
+	if addr == "" {
+		addr = miniredis.RunT(b).Addr()
+	}
+	redisClient := redis.NewClient(&redis.Options{Addr: addr})
+	b.Cleanup(func() { redisClient.Close() })
+
+	log := logrus.New()
+	log.SetOutput(io.Discard)
+
+	svc := NewRedisSyncService(nil, redisClient, log)
+	b.Cleanup(svc.Stop)
+
+	ctx := context.Background()
+	quotaKey := fmt.Sprintf("%s%d", RedisQuotaKeyPrefix, benchScheduleID)
+	queueKey := fmt.Sprintf("%s%d", RedisQueueKeyPrefix, benchScheduleID)
+	if err := redisClient.Set(ctx, quotaKey, strconv.Itoa(quota), 0).Err(); err != nil {
+		b.Fatalf("set quota: %v", err)
+	}
+	if err := redisClient.Del(ctx, queueKey).Err(); err != nil {
+		b.Fatalf("reset queue: %v", err)
+	}
+	b.Cleanup(func() { redisClient.Del(context.Background(), quotaKey, queueKey) })
+
+	return svc
+}
+
+// BenchmarkDecrQuotaAndIncrQueue measures reserving a slot on a schedule with quota left
+func BenchmarkDecrQuotaAndIncrQueue(b *testing.B) {
+	svc := newBenchRedisSyncService(b, b.N)
+	ctx := context.Background()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := svc.DecrQuotaAndIncrQueue(ctx, benchScheduleID); err != nil {
+			b.Fatalf("DecrQuotaAndIncrQueue: %v", err)
+		}
+	}
+}
+
+// BenchmarkDecrQuotaAndIncrQueueParallel measures concurrent bookings of the same schedule
+func BenchmarkDecrQuotaAndIncrQueueParallel(b *testing.B) {
+	svc := newBenchRedisSyncService(b, b.N)
+	ctx := context.Background()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			if _, err := svc.DecrQuotaAndIncrQueue(ctx, benchScheduleID); err != nil {
+				b.Errorf("DecrQuotaAndIncrQueue: %v", err)
+				return
+			}
+		}
+	})
+}
+
+// BenchmarkDecrQuotaAndIncrQueueFull measures the rejection of a fully booked schedule
+func BenchmarkDecrQuotaAndIncrQueueFull(b *testing.B) {
+	svc := newBenchRedisSyncService(b, 0)
+	ctx := context.Background()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := svc.DecrQuotaAndIncrQueue(ctx, benchScheduleID); err != ErrQuotaFull {
+			b.Fatalf("DecrQuotaAndIncrQueue: got %v, want ErrQuotaFull", err)
+		}
+	}
+}
@@ -0,0 +1,318 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go-template-clean-architecture/config"
+	"go-template-clean-architecture/internal/domain/entity"
+	"go-template-clean-architecture/internal/domain/repository"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+const (
+	// Redis set of the bookings already reminded: booking_reminder:{kind}:{visit date YYYY-MM-DD}
+	RedisReminderSentKeyPrefix = "booking_reminder:"
+
+	// Redis hash of the reminder counters of a day: booking_reminder_stats:{YYYY-MM-DD}
+	RedisReminderStatsKeyPrefix = "booking_reminder_stats:"
+
+	reminderFieldSent    = "sent"
+	reminderFieldFailed  = "failed"
+	reminderFieldSkipped = "skipped"
+
+	// Interval between reminder scans
+	reminderScanInterval = 1 * time.Minute
+
+	// The sent sets outlive the visit day, so a late scan cannot remind twice
+	reminderSentKeyTTL = 72 * time.Hour
+
+	// Counters are kept for the reminder report
+	reminderStatsKeyTTL = 35 * 24 * time.Hour
+)
+
+// Reminder kinds, also the suffix of their notification event types
+const (
+	ReminderKindDayBefore = "day_before" // The evening before the visit
+	ReminderKindSameDay   = "same_day"   // Shortly before the visit
+)
+
+// ReminderStats counts the reminders of one day. Sent reminders were handed to the
+// notification queue, failed ones could not be recorded and are retried on the next scan,
+// skipped ones went to patients who turned reminders off.
+type ReminderStats struct {
+	Date    string
+	Sent    int64
+	Failed  int64
+	Skipped int64
+}
+
+// ReminderService reminds patients of their appointments.
+//
+// Every minute each instance scans the active bookings of tomorrow (after the configured
+// local hour) and of today (within the configured lead time before the visit). A booking is
+// claimed in a Redis set per kind and date before its reminder is recorded, so every
+// instance can scan without reminding twice. Reminders go through NotificationService on
+// the patient's preferred channel; patients can turn them off in their notification preferences.
+type ReminderService struct {
+	db                  *gorm.DB
+	redisClient         *redis.Client
+	log                 *logrus.Logger
+	cfg                 *config.Config
+	bookingRepo         repository.BookingRepository
+	preferenceRepo      repository.NotificationPreferenceRepository
+	notificationService *NotificationService
+	formatService       FormatService
+
+	// Graceful shutdown
+	stopChan chan struct{}
+	wg       sync.WaitGroup
+	stopped  atomic.Bool
+}
+
+// NewReminderService creates a new ReminderService.
+// Call Start() to begin scanning and Stop() during graceful shutdown.
+func NewReminderService(
+	db *gorm.DB,
+	redisClient *redis.Client,
+	log *logrus.Logger,
+	cfg *config.Config,
+	bookingRepo repository.BookingRepository,
+	preferenceRepo repository.NotificationPreferenceRepository,
+	notificationService *NotificationService,
+	formatService FormatService,
+) *ReminderService {
+	return &ReminderService{
+		db:                  db,
+		redisClient:         redisClient,
+		log:                 log,
+		cfg:                 cfg,
+		bookingRepo:         bookingRepo,
+		preferenceRepo:      preferenceRepo,
+		notificationService: notificationService,
+		formatService:       formatService,
+		stopChan:            make(chan struct{}),
+	}
+}
+
+// Start launches the background scan loop.
+func (s *ReminderService) Start() {
+	s.wg.Add(1)
+	go s.scanLoop()
+}
+
+// Stop gracefully shuts down the service.
+// Safe to call multiple times.
+func (s *ReminderService) Stop() {
+	if s.stopped.CompareAndSwap(false, true) {
+		close(s.stopChan)
+		s.wg.Wait()
+		s.log.Info("ReminderService stopped")
+	}
+}
+
+// scanLoop runs DispatchDue on every tick until stopped
+func (s *ReminderService) scanLoop() {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(reminderScanInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.stopChan:
+			s.log.Debug("Reminder goroutine stopping")
+			return
+		case <-ticker.C:
+			ctx, cancel := context.WithTimeout(context.Background(), reminderScanInterval)
+			if err := s.DispatchDue(ctx); err != nil {
+				s.log.Warnf("Reminder scan failed: %+v", err)
+			}
+			cancel()
+		}
+	}
+}
+
+// DispatchDue records the reminders that are due and not sent yet
+func (s *ReminderService) DispatchDue(ctx context.Context) error {
+	now := time.Now().In(s.cfg.App.Location)
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+
+	if hour := s.cfg.Reminder.DayBeforeHour; hour >= 0 && now.Hour() >= hour {
+		if err := s.dispatch(ctx, ReminderKindDayBefore, today.AddDate(0, 0, 1), func(time.Time) bool { return true }); err != nil {
+			return err
+		}
+	}
+
+	if lead := s.cfg.Reminder.SameDayLead; lead > 0 {
+		due := func(startAt time.Time) bool {
+			return startAt.After(now) && startAt.Sub(now) <= lead
+		}
+		if err := s.dispatch(ctx, ReminderKindSameDay, today, due); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Stats returns the reminder counters of a day (YYYY-MM-DD), zero when nothing was sent
+func (s *ReminderService) Stats(ctx context.Context, date string) (*ReminderStats, error) {
+	values, err := s.redisClient.HGetAll(ctx, RedisReminderStatsKeyPrefix+date).Result()
+	if err != nil {
+		return nil, fmt.Errorf("get reminder stats for %s: %w", date, err)
+	}
+
+	stats := &ReminderStats{Date: date}
+	fmt.Sscan(values[reminderFieldSent], &stats.Sent)
+	fmt.Sscan(values[reminderFieldFailed], &stats.Failed)
+	fmt.Sscan(values[reminderFieldSkipped], &stats.Skipped)
+	return stats, nil
+}
+
+// dispatch reminds the patients booked on the date whose visit start is due
+func (s *ReminderService) dispatch(ctx context.Context, kind string, date time.Time, due func(startAt time.Time) bool) error {
+	bookings, err := s.bookingRepo.FindActiveByScheduleDate(s.db.WithContext(ctx), date)
+	if err != nil {
+		return fmt.Errorf("find bookings on %s: %w", date.Format("2006-01-02"), err)
+	}
+
+	var pending []entity.Booking
+	for _, b := range bookings {
+		// Already called in, no point in reminding
+		if b.CalledAt != nil {
+			continue
+		}
+		startAt, err := reminderVisit(&b).StartDateTime(s.cfg.App.Location)
+		if err != nil || !due(startAt) {
+			continue
+		}
+		pending = append(pending, b)
+	}
+	if len(pending) == 0 {
+		return nil
+	}
+
+	patientIDs := make([]uuid.UUID, len(pending))
+	for i := range pending {
+		patientIDs[i] = pending[i].PatientID
+	}
+	preferences, err := s.preferenceRepo.FindByUserIDs(s.db.WithContext(ctx), patientIDs)
+	if err != nil {
+		return fmt.Errorf("find notification preferences: %w", err)
+	}
+	optedOut := make(map[uuid.UUID]bool)
+	for _, preference := range preferences {
+		if !preference.Reminders {
+			optedOut[preference.UserID] = true
+		}
+	}
+
+	sentKey := fmt.Sprintf("%s%s:%s", RedisReminderSentKeyPrefix, kind, date.Format("2006-01-02"))
+	var sent, failed, skipped int64
+	for i := range pending {
+		b := &pending[i]
+
+		claimed, err := s.claim(ctx, sentKey, b.ID)
+		if err != nil {
+			return err
+		}
+		if !claimed {
+			continue
+		}
+
+		if optedOut[b.PatientID] {
+			skipped++
+			continue
+		}
+
+		if err := s.remind(ctx, kind, b); err != nil {
+			s.log.Warnf("Failed to record %s reminder of booking %s: %+v", kind, b.ID, err)
+			failed++
+			// Released so the next scan tries again
+			if err := s.redisClient.SRem(ctx, sentKey, b.ID.String()).Err(); err != nil {
+				s.log.Warnf("Failed to release reminder claim of booking %s: %+v", b.ID, err)
+			}
+			continue
+		}
+		sent++
+	}
+
+	s.recordStats(ctx, sent, failed, skipped)
+	if sent+failed+skipped > 0 {
+		s.log.Infof("Reminders %s for %s: %d sent, %d failed, %d skipped", kind, date.Format("2006-01-02"), sent, failed, skipped)
+	}
+	return nil
+}
+
+// claim adds the booking to the sent set, false when another scan already claimed it
+func (s *ReminderService) claim(ctx context.Context, key string, bookingID uuid.UUID) (bool, error) {
+	pipe := s.redisClient.TxPipeline()
+	added := pipe.SAdd(ctx, key, bookingID.String())
+	pipe.Expire(ctx, key, reminderSentKeyTTL)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return false, fmt.Errorf("claim reminder of booking %s: %w", bookingID, err)
+	}
+	return added.Val() == 1, nil
+}
+
+// remind records the reminder notification of the booking
+func (s *ReminderService) remind(ctx context.Context, kind string, b *entity.Booking) error {
+	slot := s.formatService.ScheduleSlot(reminderVisit(b))
+	doctorName := b.Schedule.Doctor.User.FullName
+
+	message := fmt.Sprintf("Reminder: your visit to %s is tomorrow, %s, queue number %d (booking %s)", doctorName, slot, b.QueueNumber, b.BookingCode)
+	if kind == ReminderKindSameDay {
+		message = fmt.Sprintf("Reminder: your visit to %s is today, %s, queue number %d (booking %s). Please arrive before your turn.", doctorName, slot, b.QueueNumber, b.BookingCode)
+	}
+
+	return s.notificationService.Notify(ctx, s.db.WithContext(ctx), NotificationRequest{
+		PatientID: b.PatientID,
+		BookingID: &b.ID,
+		EventType: "booking.reminder_" + kind,
+		Subject:   fmt.Sprintf("Appointment reminder: %s", b.BookingCode),
+		Message:   message,
+		DedupeKey: fmt.Sprintf("reminder:%s:%s", kind, b.ID),
+		Push:      true,
+		InApp:     true,
+		Data: map[string]string{
+			entity.TemplateVarBookingCode:  b.BookingCode,
+			entity.TemplateVarQueueNumber:  strconv.Itoa(b.QueueNumber),
+			entity.TemplateVarDoctorName:   doctorName,
+			entity.TemplateVarScheduleSlot: slot,
+		},
+	})
+}
+
+// recordStats adds the scan's counts to today's reminder counters. Fail-safe: errors are logged only.
+func (s *ReminderService) recordStats(ctx context.Context, sent, failed, skipped int64) {
+	if sent+failed+skipped == 0 {
+		return
+	}
+
+	key := RedisReminderStatsKeyPrefix + time.Now().In(s.cfg.App.Location).Format("2006-01-02")
+	pipe := s.redisClient.TxPipeline()
+	pipe.HIncrBy(ctx, key, reminderFieldSent, sent)
+	pipe.HIncrBy(ctx, key, reminderFieldFailed, failed)
+	pipe.HIncrBy(ctx, key, reminderFieldSkipped, skipped)
+	pipe.Expire(ctx, key, reminderStatsKeyTTL)
+	if _, err := pipe.Exec(ctx); err != nil {
+		s.log.Warnf("Failed to record reminder stats (non-fatal): %+v", err)
+	}
+}
+
+// reminderVisit is the booked time of the visit: the appointment slot, or the whole schedule
+func reminderVisit(b *entity.Booking) *entity.DoctorSchedule {
+	visit := b.Schedule
+	if b.Slot != nil {
+		visit.StartTime, visit.EndTime = b.Slot.StartTime, b.Slot.EndTime
+	}
+	return &visit
+}
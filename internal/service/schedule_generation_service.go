@@ -0,0 +1,143 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go-template-clean-architecture/config"
+	"go-template-clean-architecture/internal/domain/entity"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// Redis key claiming the nightly run of a date: schedule_generation:nightly:{YYYY-MM-DD}
+	RedisScheduleGenerationKeyPrefix = "schedule_generation:nightly:"
+
+	// Interval between checks whether the nightly run is due
+	scheduleGenerationCheckInterval = 1 * time.Minute
+
+	// Max time one generation run may take
+	scheduleGenerationTimeout = 10 * time.Minute
+
+	// The nightly claim outlives the day, so a late check cannot run it twice
+	scheduleGenerationClaimTTL = 36 * time.Hour
+)
+
+// ScheduleGenerationFunc expands the schedule templates and records the run report
+type ScheduleGenerationFunc func(ctx context.Context, trigger string) error
+
+// ScheduleGenerationService triggers the nightly generation of schedules from templates.
+//
+// Once per day, after the configured local hour, one instance claims the date in Redis
+// and runs the registered generator (see ScheduleTemplateUsecase). An instance started
+// after the hour runs the missed generation on its first check. Generation itself is
+// idempotent, so a manual run the same day only adds what is missing.
+type ScheduleGenerationService struct {
+	redisClient *redis.Client
+	log         *logrus.Logger
+	cfg         *config.Config
+
+	generateMu sync.RWMutex
+	generate   ScheduleGenerationFunc
+
+	// Date of the last nightly run seen by this instance (YYYY-MM-DD)
+	lastRunDate string
+
+	// Graceful shutdown
+	stopChan chan struct{}
+	wg       sync.WaitGroup
+	stopped  atomic.Bool
+}
+
+// NewScheduleGenerationService creates a new ScheduleGenerationService.
+// Call Start() to begin the nightly runs and Stop() during graceful shutdown.
+func NewScheduleGenerationService(redisClient *redis.Client, log *logrus.Logger, cfg *config.Config) *ScheduleGenerationService {
+	return &ScheduleGenerationService{
+		redisClient: redisClient,
+		log:         log,
+		cfg:         cfg,
+		stopChan:    make(chan struct{}),
+	}
+}
+
+// RegisterGenerator sets the function the nightly run calls
+func (s *ScheduleGenerationService) RegisterGenerator(generate ScheduleGenerationFunc) {
+	s.generateMu.Lock()
+	defer s.generateMu.Unlock()
+	s.generate = generate
+}
+
+// Start launches the background loop.
+func (s *ScheduleGenerationService) Start() {
+	s.wg.Add(1)
+	go s.runLoop()
+}
+
+// Stop gracefully shuts down the service, waiting for a run in progress.
+// Safe to call multiple times.
+func (s *ScheduleGenerationService) Stop() {
+	if s.stopped.CompareAndSwap(false, true) {
+		close(s.stopChan)
+		s.wg.Wait()
+		s.log.Info("ScheduleGenerationService stopped")
+	}
+}
+
+// runLoop checks whether the nightly run is due on start and every tick until stopped
+func (s *ScheduleGenerationService) runLoop() {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(scheduleGenerationCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		s.runNightlyIfDue()
+
+		select {
+		case <-s.stopChan:
+			s.log.Debug("Schedule generation goroutine stopping")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// runNightlyIfDue runs the generator once per day after the configured hour,
+// on the instance that claims the date first
+func (s *ScheduleGenerationService) runNightlyIfDue() {
+	now := time.Now().In(s.cfg.App.Location)
+	today := now.Format("2006-01-02")
+	if now.Hour() < s.cfg.Generation.Hour || s.lastRunDate == today {
+		return
+	}
+
+	s.generateMu.RLock()
+	generate := s.generate
+	s.generateMu.RUnlock()
+	if generate == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), scheduleGenerationTimeout)
+	defer cancel()
+
+	claimed, err := s.redisClient.SetNX(ctx, RedisScheduleGenerationKeyPrefix+today, "1", scheduleGenerationClaimTTL).Result()
+	if err != nil {
+		s.log.Warnf("Failed to claim nightly schedule generation for %s: %+v", today, err)
+		return
+	}
+	s.lastRunDate = today
+	if !claimed {
+		s.log.Debugf("Nightly schedule generation for %s already claimed by another instance", today)
+		return
+	}
+
+	s.log.Infof("Starting nightly schedule generation for %s", today)
+	if err := generate(ctx, entity.ScheduleGenerationTriggerNightly); err != nil {
+		s.log.Warnf("Nightly schedule generation failed: %+v", err)
+	}
+}
@@ -0,0 +1,264 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go-template-clean-architecture/config"
+	"go-template-clean-architecture/internal/domain/entity"
+	"go-template-clean-architecture/internal/domain/repository"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+const (
+	// Redis hash per tenant per day: usage:{tenant}:{YYYY-MM-DD}
+	RedisUsageKeyPrefix = "usage:"
+
+	usageFieldAPICalls        = "api_calls"
+	usageFieldBookingsCreated = "bookings_created"
+
+	// Interval between flushes of completed days to the database
+	usageFlushInterval = 1 * time.Hour
+
+	// Counters expire if never flushed (e.g. database down for weeks)
+	usageKeyTTL = 35 * 24 * time.Hour
+
+	// Timeout for fire-and-forget counter increments
+	usageRecordTimeout = 1 * time.Second
+
+	// API calls buffered for the record loop; a full buffer is counted inline
+	usageAPICallQueueSize = 1024
+)
+
+// DailyUsage is the usage counted for one day
+type DailyUsage struct {
+	Date            string
+	APICalls        int64
+	BookingsCreated int64
+}
+
+// UsageMeterService meters API calls and bookings per tenant for billing.
+//
+// Counting is a Redis HINCRBY on the current day's hash (cheap, hot path). API calls are
+// handed to a single record loop that counts them in batches, off the request path.
+// A background loop flushes completed days to usage_records and deletes the
+// Redis hash; the upsert replaces values, so a crash between the two is safe.
+type UsageMeterService struct {
+	db          *gorm.DB
+	redisClient *redis.Client
+	log         *logrus.Logger
+	cfg         *config.Config
+	usageRepo   repository.UsageRecordRepository
+
+	apiCalls chan struct{} // API calls not counted yet, see recordLoop
+
+	// Graceful shutdown
+	stopChan chan struct{}
+	wg       sync.WaitGroup
+	stopped  atomic.Bool
+}
+
+// NewUsageMeterService creates a new UsageMeterService.
+// Call Start() to begin flushing and Stop() during graceful shutdown.
+func NewUsageMeterService(
+	db *gorm.DB,
+	redisClient *redis.Client,
+	log *logrus.Logger,
+	cfg *config.Config,
+	usageRepo repository.UsageRecordRepository,
+) *UsageMeterService {
+	return &UsageMeterService{
+		db:          db,
+		redisClient: redisClient,
+		log:         log,
+		cfg:         cfg,
+		usageRepo:   usageRepo,
+		apiCalls:    make(chan struct{}, usageAPICallQueueSize),
+		stopChan:    make(chan struct{}),
+	}
+}
+
+// Start launches the background flush and record loops.
+func (s *UsageMeterService) Start() {
+	s.wg.Add(2)
+	go s.flushLoop()
+	go s.recordLoop()
+}
+
+// Stop gracefully shuts down the service, counting the API calls still buffered.
+// Safe to call multiple times.
+func (s *UsageMeterService) Stop() {
+	if s.stopped.CompareAndSwap(false, true) {
+		close(s.stopChan)
+		s.wg.Wait()
+		s.log.Info("UsageMeterService stopped")
+	}
+}
+
+// TenantID returns the tenant usage is metered for
+func (s *UsageMeterService) TenantID() string {
+	return s.cfg.App.TenantID
+}
+
+// RecordAPICall counts one API call for today. It is handed to the record loop and counted
+// inline only when the buffer is full or the service stopped. Fail-safe: errors are logged only.
+func (s *UsageMeterService) RecordAPICall() {
+	if !s.stopped.Load() {
+		select {
+		case s.apiCalls <- struct{}{}:
+			return
+		default:
+		}
+	}
+	s.increment(usageFieldAPICalls, 1)
+}
+
+// RecordBookingCreated counts one created booking for today. Fail-safe: errors are logged only.
+func (s *UsageMeterService) RecordBookingCreated() {
+	s.increment(usageFieldBookingsCreated, 1)
+}
+
+// LiveUsage returns counters still held in Redis for a day (today or not flushed yet).
+// Returns nil if there is no counter for the day.
+func (s *UsageMeterService) LiveUsage(ctx context.Context, date string) (*DailyUsage, error) {
+	values, err := s.redisClient.HGetAll(ctx, s.usageKey(date)).Result()
+	if err != nil {
+		return nil, fmt.Errorf("get usage for %s: %w", date, err)
+	}
+	if len(values) == 0 {
+		return nil, nil
+	}
+	return parseDailyUsage(date, values), nil
+}
+
+// FlushCompletedDays moves counters of every day before today from Redis to the database.
+func (s *UsageMeterService) FlushCompletedDays(ctx context.Context) error {
+	today := time.Now().In(s.cfg.App.Location).Format("2006-01-02")
+	prefix := fmt.Sprintf("%s%s:", RedisUsageKeyPrefix, s.TenantID())
+
+	iter := s.redisClient.Scan(ctx, 0, prefix+"*", 100).Iterator()
+	for iter.Next(ctx) {
+		key := iter.Val()
+		date := strings.TrimPrefix(key, prefix)
+		if date >= today {
+			continue
+		}
+
+		usageDate, err := time.Parse("2006-01-02", date)
+		if err != nil {
+			s.log.Warnf("Skipping malformed usage key %s", key)
+			continue
+		}
+
+		values, err := s.redisClient.HGetAll(ctx, key).Result()
+		if err != nil {
+			return fmt.Errorf("get usage key %s: %w", key, err)
+		}
+		usage := parseDailyUsage(date, values)
+
+		if err := s.usageRepo.Upsert(s.db.WithContext(ctx), &entity.UsageRecord{
+			TenantID:        s.TenantID(),
+			UsageDate:       usageDate,
+			APICalls:        usage.APICalls,
+			BookingsCreated: usage.BookingsCreated,
+		}); err != nil {
+			return fmt.Errorf("save usage for %s: %w", date, err)
+		}
+
+		if err := s.redisClient.Del(ctx, key).Err(); err != nil {
+			return fmt.Errorf("delete usage key %s: %w", key, err)
+		}
+
+		s.log.Infof("Flushed usage for %s: api_calls=%d, bookings_created=%d", date, usage.APICalls, usage.BookingsCreated)
+	}
+
+	return iter.Err()
+}
+
+// flushLoop flushes once at startup and then on every tick until stopped
+func (s *UsageMeterService) flushLoop() {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(usageFlushInterval)
+	defer ticker.Stop()
+
+	for {
+		ctx, cancel := context.WithTimeout(context.Background(), usageFlushInterval)
+		if err := s.FlushCompletedDays(ctx); err != nil {
+			s.log.Warnf("Usage flush failed: %+v", err)
+		}
+		cancel()
+
+		select {
+		case <-s.stopChan:
+			s.log.Debug("Usage meter goroutine stopping")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// recordLoop counts the buffered API calls, all calls waiting at once in one increment,
+// until stopped and then the ones left in the buffer
+func (s *UsageMeterService) recordLoop() {
+	defer s.wg.Done()
+
+	for {
+		select {
+		case <-s.apiCalls:
+			s.increment(usageFieldAPICalls, 1+s.drainAPICalls())
+		case <-s.stopChan:
+			if n := s.drainAPICalls(); n > 0 {
+				s.increment(usageFieldAPICalls, n)
+			}
+			s.log.Debug("Usage record goroutine stopping")
+			return
+		}
+	}
+}
+
+// drainAPICalls empties the API call buffer, returning how many calls it held
+func (s *UsageMeterService) drainAPICalls() int64 {
+	var n int64
+	for {
+		select {
+		case <-s.apiCalls:
+			n++
+		default:
+			return n
+		}
+	}
+}
+
+// increment bumps a counter of today's hash by n
+func (s *UsageMeterService) increment(field string, n int64) {
+	ctx, cancel := context.WithTimeout(context.Background(), usageRecordTimeout)
+	defer cancel()
+
+	key := s.usageKey(time.Now().In(s.cfg.App.Location).Format("2006-01-02"))
+
+	pipe := s.redisClient.TxPipeline()
+	pipe.HIncrBy(ctx, key, field, n)
+	pipe.Expire(ctx, key, usageKeyTTL)
+	if _, err := pipe.Exec(ctx); err != nil {
+		s.log.Warnf("Failed to record usage %s (non-fatal): %+v", field, err)
+	}
+}
+
+func (s *UsageMeterService) usageKey(date string) string {
+	return fmt.Sprintf("%s%s:%s", RedisUsageKeyPrefix, s.TenantID(), date)
+}
+
+func parseDailyUsage(date string, values map[string]string) *DailyUsage {
+	usage := &DailyUsage{Date: date}
+	fmt.Sscan(values[usageFieldAPICalls], &usage.APICalls)
+	fmt.Sscan(values[usageFieldBookingsCreated], &usage.BookingsCreated)
+	return usage
+}
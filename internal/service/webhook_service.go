@@ -0,0 +1,379 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go-template-clean-architecture/internal/domain/entity"
+	"go-template-clean-architecture/internal/domain/repository"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+const (
+	// Interval between webhook delivery polls
+	webhookPollInterval = 2 * time.Second
+
+	// Deliveries claimed per poll
+	webhookBatchSize = 20
+
+	// Max time an endpoint may take to answer
+	webhookRequestTimeout = 10 * time.Second
+
+	// Attempts before a delivery is marked failed
+	webhookMaxAttempts = 8
+
+	// Retry backoff: base * 2^(attempts-1), capped
+	webhookRetryBase = 30 * time.Second
+	webhookRetryMax  = 2 * time.Hour
+
+	// Failed attempts in a row, over all deliveries, after which a subscription is paused
+	webhookPauseAfterFailures = 20
+
+	// Part of the endpoint response kept on the delivery
+	webhookResponseBodyLimit = 1024
+
+	// Signature header: t=<unix seconds>,v1=<hex HMAC-SHA256(secret, "<t>.<body>")>
+	WebhookSignatureHeader = "X-Webhook-Signature"
+	WebhookEventHeader     = "X-Webhook-Event"
+	WebhookDeliveryHeader  = "X-Webhook-Delivery"
+)
+
+// Internal processing flags of event payloads, not sent to subscribers
+var webhookInternalPayloadFields = []string{"release_slot", "promote_waitlist"}
+
+// ErrWebhookSubscriptionNotFound is returned by Test for an unknown subscription
+var ErrWebhookSubscriptionNotFound = errors.New("webhook subscription not found")
+
+// WebhookService delivers domain events to the webhook subscriptions of external systems.
+//
+// An event bus handler records one delivery per active subscription of the event type; a
+// worker POSTs due deliveries as signed JSON, retrying failures with exponential backoff
+// until webhookMaxAttempts. Subscriptions failing webhookPauseAfterFailures times in a
+// row are paused, their pending deliveries wait until an admin resumes them.
+type WebhookService struct {
+	db               *gorm.DB
+	log              *logrus.Logger
+	subscriptionRepo repository.WebhookSubscriptionRepository
+	deliveryRepo     repository.WebhookDeliveryRepository
+	httpClient       *http.Client
+
+	// Graceful shutdown
+	stopChan chan struct{}
+	wg       sync.WaitGroup
+	stopped  atomic.Bool
+}
+
+// NewWebhookService creates a new WebhookService and subscribes it to the webhook event types.
+// An outbox event is handled again by all its subscribers when one of them fails, so the
+// deliveries are recorded once per subscription and event, and the handlers subscribed
+// before this one must be idempotent as well.
+// Call Start() to begin delivering and Stop() during graceful shutdown.
+func NewWebhookService(
+	db *gorm.DB,
+	log *logrus.Logger,
+	subscriptionRepo repository.WebhookSubscriptionRepository,
+	deliveryRepo repository.WebhookDeliveryRepository,
+	eventBus *EventBus,
+) *WebhookService {
+	s := &WebhookService{
+		db:               db,
+		log:              log,
+		subscriptionRepo: subscriptionRepo,
+		deliveryRepo:     deliveryRepo,
+		httpClient:       &http.Client{Timeout: webhookRequestTimeout},
+		stopChan:         make(chan struct{}),
+	}
+
+	for _, eventType := range entity.WebhookEventTypes {
+		eventBus.Subscribe(eventType, s.handleEvent)
+	}
+	return s
+}
+
+// GenerateWebhookSecret returns a new random signing secret
+func GenerateWebhookSecret() (string, error) {
+	raw := make([]byte, 24)
+	if _, err := rand.Read(raw); err != nil {
+		return "", err
+	}
+	return "whsec_" + hex.EncodeToString(raw), nil
+}
+
+// SignWebhookPayload returns the X-Webhook-Signature value of the body sent at the given time
+func SignWebhookPayload(secret string, at time.Time, body []byte) string {
+	timestamp := strconv.FormatInt(at.Unix(), 10)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(body)
+	return "t=" + timestamp + ",v1=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Start launches the background delivery loop.
+func (s *WebhookService) Start() {
+	s.wg.Add(1)
+	go s.pollLoop()
+}
+
+// Stop gracefully shuts down the service.
+// Safe to call multiple times.
+func (s *WebhookService) Stop() {
+	if s.stopped.CompareAndSwap(false, true) {
+		close(s.stopChan)
+		s.wg.Wait()
+		s.log.Info("WebhookService stopped")
+	}
+}
+
+// handleEvent records a delivery of the event for every active subscription of its type.
+// Deliveries of outbox events are recorded once per subscription however often the event is handled.
+func (s *WebhookService) handleEvent(ctx context.Context, event *entity.DomainEvent) error {
+	db := s.db.WithContext(ctx)
+	subscriptions, err := s.subscriptionRepo.FindActiveByEventType(db, event.Type)
+	if err != nil {
+		return fmt.Errorf("find webhook subscriptions of %s: %w", event.Type, err)
+	}
+
+	data := make(entity.JSON, len(event.Payload))
+	for key, value := range event.Payload {
+		data[key] = value
+	}
+	for _, field := range webhookInternalPayloadFields {
+		delete(data, field)
+	}
+
+	now := time.Now()
+	for _, subscription := range subscriptions {
+		delivery := newWebhookDelivery(subscription.ID, event.Type, event.OccurredAt, data)
+		if event.OutboxID != 0 {
+			delivery.EventID = &event.OutboxID
+		}
+		delivery.NextAttemptAt = now
+		if _, err := s.deliveryRepo.CreateIfAbsent(db, delivery); err != nil {
+			return fmt.Errorf("record webhook delivery of event %s to subscription %d: %w", event.ID, subscription.ID, err)
+		}
+	}
+	return nil
+}
+
+// Test sends a webhook.test delivery to the subscription right away, also when it is paused.
+// The delivery is recorded but not retried and does not count toward pausing the subscription.
+func (s *WebhookService) Test(ctx context.Context, subscriptionID int) (*entity.WebhookDelivery, error) {
+	db := s.db.WithContext(ctx)
+	subscription, err := s.subscriptionRepo.FindByID(db, subscriptionID)
+	if err != nil {
+		return nil, err
+	}
+	if subscription == nil {
+		return nil, ErrWebhookSubscriptionNotFound
+	}
+
+	now := time.Now()
+	delivery := newWebhookDelivery(subscription.ID, entity.WebhookEventTest, now, entity.JSON{
+		"subscription_id": subscription.ID,
+		"message":         "This is a test delivery",
+	})
+	delivery.NextAttemptAt = now
+
+	result := s.send(ctx, subscription, delivery)
+	delivery.Attempts = 1
+	delivery.ResponseStatus = result.status
+	delivery.ResponseBody = result.body
+	if result.err != nil {
+		delivery.Status = entity.WebhookDeliveryFailed
+		delivery.LastError = result.err.Error()
+		delivery.FailedAt = &now
+	} else {
+		delivery.Status = entity.WebhookDeliverySucceeded
+		delivery.DeliveredAt = &now
+	}
+
+	if err := s.deliveryRepo.Create(db, delivery); err != nil {
+		return nil, fmt.Errorf("record webhook test delivery: %w", err)
+	}
+	return delivery, nil
+}
+
+// DeliverDue claims and sends one batch of due deliveries.
+// Returns the number of deliveries processed.
+func (s *WebhookService) DeliverDue(ctx context.Context) (int, error) {
+	tx := s.db.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	now := time.Now()
+	deliveries, err := s.deliveryRepo.ClaimDue(tx, now, webhookBatchSize)
+	if err != nil {
+		return 0, fmt.Errorf("claim webhook deliveries: %w", err)
+	}
+
+	subscriptions := make(map[int]*entity.WebhookSubscription)
+	for i := range deliveries {
+		delivery := &deliveries[i]
+		attempts := delivery.Attempts + 1
+
+		subscription, ok := subscriptions[delivery.SubscriptionID]
+		if !ok {
+			if subscription, err = s.subscriptionRepo.FindByID(tx, delivery.SubscriptionID); err != nil {
+				return i, err
+			}
+			subscriptions[delivery.SubscriptionID] = subscription
+		}
+		// Paused by an earlier delivery of this batch
+		if subscription == nil || !subscription.IsActive() {
+			continue
+		}
+
+		result := s.send(ctx, subscription, delivery)
+		if result.err == nil {
+			if err := s.deliveryRepo.MarkSucceeded(tx, delivery.ID, attempts, *result.status, result.body, time.Now()); err != nil {
+				return i, err
+			}
+			if err := s.subscriptionRepo.RecordSuccess(tx, subscription.ID); err != nil {
+				return i, err
+			}
+			continue
+		}
+
+		if attempts >= webhookMaxAttempts {
+			s.log.Errorf("Webhook delivery %s (%s) to subscription %d failed permanently after %d attempts: %+v", delivery.ID, delivery.EventType, subscription.ID, attempts, result.err)
+			if err := s.deliveryRepo.MarkFailed(tx, delivery.ID, attempts, result.status, result.body, result.err.Error(), time.Now()); err != nil {
+				return i, err
+			}
+		} else {
+			s.log.Warnf("Webhook delivery %s (%s) to subscription %d failed, attempt %d: %+v", delivery.ID, delivery.EventType, subscription.ID, attempts, result.err)
+			if err := s.deliveryRepo.MarkRetry(tx, delivery.ID, attempts, now.Add(webhookBackoff(attempts)), result.status, result.body, result.err.Error()); err != nil {
+				return i, err
+			}
+		}
+
+		failures, err := s.subscriptionRepo.RecordFailure(tx, subscription.ID)
+		if err != nil {
+			return i, err
+		}
+		if failures >= webhookPauseAfterFailures {
+			reason := fmt.Sprintf("Paused after %d failed deliveries in a row", failures)
+			if _, err := s.subscriptionRepo.Pause(tx, subscription.ID, reason, time.Now()); err != nil {
+				return i, err
+			}
+			subscription.Status = entity.WebhookStatusPaused
+			s.log.Warnf("Webhook subscription %d paused after %d failed deliveries in a row", subscription.ID, failures)
+		}
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return 0, fmt.Errorf("commit webhook batch: %w", err)
+	}
+	return len(deliveries), nil
+}
+
+// webhookResult is the outcome of one delivery attempt. status is nil when no response was received.
+type webhookResult struct {
+	status *int
+	body   string
+	err    error
+}
+
+// send POSTs the delivery payload to the subscription endpoint, any 2xx answer is a success
+func (s *WebhookService) send(ctx context.Context, subscription *entity.WebhookSubscription, delivery *entity.WebhookDelivery) webhookResult {
+	body, err := json.Marshal(delivery.Payload)
+	if err != nil {
+		return webhookResult{err: fmt.Errorf("marshal webhook payload: %w", err)}
+	}
+
+	sendCtx, cancel := context.WithTimeout(ctx, webhookRequestTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(sendCtx, http.MethodPost, subscription.URL, bytes.NewReader(body))
+	if err != nil {
+		return webhookResult{err: err}
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(WebhookSignatureHeader, SignWebhookPayload(subscription.Secret, time.Now(), body))
+	req.Header.Set(WebhookEventHeader, delivery.EventType)
+	req.Header.Set(WebhookDeliveryHeader, delivery.ID.String())
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return webhookResult{err: fmt.Errorf("send webhook: %w", err)}
+	}
+	defer resp.Body.Close()
+
+	raw, _ := io.ReadAll(io.LimitReader(resp.Body, webhookResponseBodyLimit))
+	result := webhookResult{status: &resp.StatusCode, body: string(raw)}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		result.err = fmt.Errorf("endpoint returned %d", resp.StatusCode)
+	}
+	return result
+}
+
+// pollLoop sends due deliveries on every tick until stopped.
+// A full batch is followed immediately by the next one to drain backlogs.
+func (s *WebhookService) pollLoop() {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(webhookPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.stopChan:
+			s.log.Debug("Webhook goroutine stopping")
+			return
+		case <-ticker.C:
+			for {
+				ctx, cancel := context.WithTimeout(context.Background(), webhookBatchSize*webhookRequestTimeout)
+				processed, err := s.DeliverDue(ctx)
+				cancel()
+				if err != nil {
+					s.log.Warnf("Webhook delivery failed: %+v", err)
+					break
+				}
+				if processed < webhookBatchSize || s.stopped.Load() {
+					break
+				}
+			}
+		}
+	}
+}
+
+// newWebhookDelivery builds a pending delivery whose payload is the request body:
+// {"id": delivery id, "event": event type, "occurred_at": ..., "data": event payload}
+func newWebhookDelivery(subscriptionID int, eventType string, occurredAt time.Time, data entity.JSON) *entity.WebhookDelivery {
+	id := uuid.New()
+	return &entity.WebhookDelivery{
+		ID:             id,
+		SubscriptionID: subscriptionID,
+		EventType:      eventType,
+		Status:         entity.WebhookDeliveryPending,
+		Payload: entity.JSON{
+			"id":          id.String(),
+			"event":       eventType,
+			"occurred_at": occurredAt.UTC().Format(time.RFC3339),
+			"data":        data,
+		},
+	}
+}
+
+// webhookBackoff returns the delay before the given retry attempt
+func webhookBackoff(attempts int) time.Duration {
+	delay := webhookRetryBase << (attempts - 1)
+	if delay <= 0 || delay > webhookRetryMax {
+		return webhookRetryMax
+	}
+	return delay
+}
@@ -0,0 +1,235 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"go-template-clean-architecture/internal/converter"
+	"go-template-clean-architecture/internal/delivery/dto"
+	"go-template-clean-architecture/internal/delivery/http/middleware"
+	"go-template-clean-architecture/internal/domain/entity"
+	"go-template-clean-architecture/internal/domain/repository"
+	"go-template-clean-architecture/internal/service"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrAPIKeyNotFound             = errors.New("API key not found")
+	ErrAPIKeyRevoked              = errors.New("API key is revoked")
+	ErrAPIKeyExpiryInPast         = errors.New("expires_at must be in the future")
+	ErrAPIKeyPermissionNotAllowed = errors.New("portal and api_key:manage permissions cannot be granted to API keys")
+)
+
+type APIKeyUsecase interface {
+	GetAllAPIKeys(ctx context.Context) ([]dto.APIKeyResponse, error)
+	GetAPIKey(ctx context.Context, id uuid.UUID) (*dto.APIKeyResponse, error)
+	CreateAPIKey(ctx context.Context, req *dto.CreateAPIKeyRequest) (*dto.CreateAPIKeyResponse, error)
+	UpdateAPIKey(ctx context.Context, id uuid.UUID, req *dto.UpdateAPIKeyRequest) (*dto.APIKeyResponse, error)
+	RevokeAPIKey(ctx context.Context, id uuid.UUID) error
+}
+
+type apiKeyUsecase struct {
+	db             *gorm.DB
+	log            *logrus.Logger
+	apiKeyRepo     repository.APIKeyRepository
+	permissionRepo repository.PermissionRepository
+	auditService   service.AuditService
+}
+
+func NewAPIKeyUsecase(
+	db *gorm.DB,
+	log *logrus.Logger,
+	apiKeyRepo repository.APIKeyRepository,
+	permissionRepo repository.PermissionRepository,
+	auditService service.AuditService,
+) APIKeyUsecase {
+	return &apiKeyUsecase{
+		db:             db,
+		log:            log,
+		apiKeyRepo:     apiKeyRepo,
+		permissionRepo: permissionRepo,
+		auditService:   auditService,
+	}
+}
+
+func (u *apiKeyUsecase) GetAllAPIKeys(ctx context.Context) ([]dto.APIKeyResponse, error) {
+	apiKeys, err := u.apiKeyRepo.FindAll(u.db.WithContext(ctx))
+	if err != nil {
+		u.log.Warnf("Failed to find API keys: %+v", err)
+		return nil, err
+	}
+	return converter.APIKeysToResponses(apiKeys), nil
+}
+
+func (u *apiKeyUsecase) GetAPIKey(ctx context.Context, id uuid.UUID) (*dto.APIKeyResponse, error) {
+	apiKey, err := u.apiKeyRepo.FindByID(u.db.WithContext(ctx), id)
+	if err != nil {
+		u.log.Warnf("Failed to find API key by ID: %+v", err)
+		return nil, err
+	}
+	if apiKey == nil {
+		return nil, ErrAPIKeyNotFound
+	}
+	return converter.APIKeyToResponse(apiKey), nil
+}
+
+// CreateAPIKey issues a key for a machine client. The key is only returned here,
+// afterwards it is known by its prefix.
+func (u *apiKeyUsecase) CreateAPIKey(ctx context.Context, req *dto.CreateAPIKeyRequest) (*dto.CreateAPIKeyResponse, error) {
+	userID, _ := middleware.GetUserIDFromContext(ctx)
+
+	if req.ExpiresAt != nil && !req.ExpiresAt.After(time.Now()) {
+		return nil, ErrAPIKeyExpiryInPast
+	}
+
+	key, keyPrefix, keyHash, err := service.GenerateAPIKey()
+	if err != nil {
+		u.log.Warnf("Failed to generate API key: %+v", err)
+		return nil, err
+	}
+
+	tx := u.db.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	permissions, err := u.findPermissions(tx, req.Permissions)
+	if err != nil {
+		return nil, err
+	}
+
+	apiKey := &entity.APIKey{
+		Name:        strings.TrimSpace(req.Name),
+		KeyPrefix:   keyPrefix,
+		KeyHash:     keyHash,
+		ExpiresAt:   req.ExpiresAt,
+		CreatedBy:   &userID,
+		Permissions: permissions,
+	}
+	if err := u.apiKeyRepo.Create(tx, apiKey); err != nil {
+		u.log.Warnf("Failed to create API key: %+v", err)
+		return nil, err
+	}
+
+	response := converter.APIKeyToResponse(apiKey)
+	if err := u.auditService.LogCreate(ctx, tx, &userID, entity.AuditActionAPIKeyCreate, "api_key", apiKey.ID.String(), response); err != nil {
+		u.log.Warnf("Failed to create audit log: %+v", err)
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		u.log.Warnf("Failed commit transaction: %+v", err)
+		return nil, err
+	}
+
+	return &dto.CreateAPIKeyResponse{APIKeyResponse: response, Key: key}, nil
+}
+
+// UpdateAPIKey replaces the name, permissions and expiry of an active or expired key.
+// Changes apply to the next request of the client.
+func (u *apiKeyUsecase) UpdateAPIKey(ctx context.Context, id uuid.UUID, req *dto.UpdateAPIKeyRequest) (*dto.APIKeyResponse, error) {
+	userID, _ := middleware.GetUserIDFromContext(ctx)
+
+	if req.ExpiresAt != nil && !req.ExpiresAt.After(time.Now()) {
+		return nil, ErrAPIKeyExpiryInPast
+	}
+
+	tx := u.db.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	apiKey, err := u.apiKeyRepo.FindByID(tx, id)
+	if err != nil {
+		u.log.Warnf("Failed to find API key by ID: %+v", err)
+		return nil, err
+	}
+	if apiKey == nil {
+		return nil, ErrAPIKeyNotFound
+	}
+	if apiKey.RevokedAt != nil {
+		return nil, ErrAPIKeyRevoked
+	}
+	oldValue := converter.APIKeyToResponse(apiKey)
+
+	permissions, err := u.findPermissions(tx, req.Permissions)
+	if err != nil {
+		return nil, err
+	}
+
+	apiKey.Name = strings.TrimSpace(req.Name)
+	apiKey.ExpiresAt = req.ExpiresAt
+	if err := u.apiKeyRepo.Update(tx, apiKey); err != nil {
+		u.log.Warnf("Failed to update API key: %+v", err)
+		return nil, err
+	}
+	if err := u.apiKeyRepo.ReplacePermissions(tx, apiKey, permissions); err != nil {
+		u.log.Warnf("Failed to update API key permissions: %+v", err)
+		return nil, err
+	}
+	apiKey.Permissions = permissions
+
+	response := converter.APIKeyToResponse(apiKey)
+	if err := u.auditService.LogUpdate(ctx, tx, &userID, entity.AuditActionAPIKeyUpdate, "api_key", apiKey.ID.String(), oldValue, response); err != nil {
+		u.log.Warnf("Failed to create audit log: %+v", err)
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		u.log.Warnf("Failed commit transaction: %+v", err)
+		return nil, err
+	}
+
+	return response, nil
+}
+
+// RevokeAPIKey disables a key for good; the record is kept for the audit trail
+func (u *apiKeyUsecase) RevokeAPIKey(ctx context.Context, id uuid.UUID) error {
+	userID, _ := middleware.GetUserIDFromContext(ctx)
+
+	tx := u.db.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	apiKey, err := u.apiKeyRepo.FindByID(tx, id)
+	if err != nil {
+		u.log.Warnf("Failed to find API key by ID: %+v", err)
+		return err
+	}
+	if apiKey == nil {
+		return ErrAPIKeyNotFound
+	}
+
+	revoked, err := u.apiKeyRepo.Revoke(tx, id, time.Now())
+	if err != nil {
+		u.log.Warnf("Failed to revoke API key: %+v", err)
+		return err
+	}
+	if revoked == 0 {
+		return ErrAPIKeyRevoked
+	}
+
+	if err := u.auditService.LogDelete(ctx, tx, &userID, entity.AuditActionAPIKeyRevoke, "api_key", apiKey.ID.String(), converter.APIKeyToResponse(apiKey)); err != nil {
+		u.log.Warnf("Failed to create audit log: %+v", err)
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		u.log.Warnf("Failed commit transaction: %+v", err)
+		return err
+	}
+
+	return nil
+}
+
+// findPermissions loads the permissions of the codes, rejecting the ones keys cannot hold
+func (u *apiKeyUsecase) findPermissions(db *gorm.DB, codes []string) ([]entity.Permission, error) {
+	for _, code := range codes {
+		if !entity.IsAPIKeyPermissionAllowed(strings.TrimSpace(code)) {
+			return nil, ErrAPIKeyPermissionNotAllowed
+		}
+	}
+
+	permissions, err := findPermissionsByCodes(db, u.permissionRepo, codes)
+	if err != nil && err != ErrUnknownPermission {
+		u.log.Warnf("Failed to find permissions: %+v", err)
+	}
+	return permissions, err
+}
@@ -2,17 +2,28 @@ package usecase
 
 import (
 	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"slices"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 
+	"go-template-clean-architecture/config"
 	"go-template-clean-architecture/internal/converter"
 	"go-template-clean-architecture/internal/delivery/dto"
+	"go-template-clean-architecture/internal/delivery/http/middleware"
 	"go-template-clean-architecture/internal/domain/entity"
 	"go-template-clean-architecture/internal/domain/repository"
 	"go-template-clean-architecture/internal/service"
 	"go-template-clean-architecture/pkg/jwt"
+	"go-template-clean-architecture/pkg/oauth"
+	"go-template-clean-architecture/pkg/totp"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5/pgconn"
@@ -27,12 +38,36 @@ var (
 	ErrInvalidCredentials = errors.New("invalid email or password")
 	ErrInvalidToken       = errors.New("invalid or expired token")
 	ErrTokenRevoked       = errors.New("token has been revoked")
+	ErrTokenReused        = errors.New("refresh token reuse detected, sign in again")
 	ErrUserNotFound       = errors.New("user not found")
 	ErrRoleNotFound       = errors.New("role not found")
 	ErrNIKAlreadyExists   = errors.New("NIK already exists")
 	ErrSTRAlreadyExists   = errors.New("STR number already exists")
 	ErrInvalidDateFormat  = errors.New("invalid date format, use YYYY-MM-DD")
 	ErrAccountLocked      = errors.New("account temporarily locked, try again later")
+	ErrSessionNotFound    = errors.New("session not found")
+	ErrAccountNotLocked   = errors.New("account is not locked")
+
+	ErrImpersonateSelf       = errors.New("cannot impersonate yourself")
+	ErrImpersonateNotAllowed = errors.New("this account cannot be impersonated")
+
+	ErrTwoFactorAlreadyEnabled   = errors.New("two-factor authentication is already enabled")
+	ErrTwoFactorNotEnabled       = errors.New("two-factor authentication is not enabled")
+	ErrTwoFactorNotSetUp         = errors.New("two-factor authentication has not been set up")
+	ErrInvalidTwoFactorCode      = errors.New("invalid two-factor code")
+	ErrTwoFactorChallengeInvalid = errors.New("invalid or expired two-factor challenge")
+	ErrTwoFactorTooManyAttempts  = errors.New("too many two-factor attempts, login again")
+
+	ErrOAuthNotConfigured       = errors.New("google login is not configured")
+	ErrOAuthStateInvalid        = errors.New("invalid or expired login state")
+	ErrOAuthFailed              = errors.New("google login failed")
+	ErrOAuthEmailNotVerified    = errors.New("google account email is not verified")
+	ErrOAuthNotPatient          = errors.New("google login is only available for patient accounts")
+	ErrOAuthAccountLinked       = errors.New("account is linked to another google account")
+	ErrOAuthRegistrationInvalid = errors.New("invalid or expired registration token")
+
+	ErrInvalidLoginOTP         = errors.New("invalid or expired login code")
+	ErrLoginOTPTooManyAttempts = errors.New("too many login code attempts, try again later")
 )
 
 // =============================================================================
@@ -43,8 +78,84 @@ const (
 	maxLoginAttempts    = 5
 	loginLockoutPeriod  = 3 * time.Minute
 	loginAttemptsPrefix = "login_attempts:"
+
+	// Two-step login: the password step issues a challenge completed with a 2FA code
+	twoFactorChallengePrefix = "two_factor_challenge:" // two_factor_challenge:{sha256(token)} -> user ID
+	twoFactorAttemptsPrefix  = "two_factor_attempts:"  // two_factor_attempts:{sha256(token)} -> failed codes
+	twoFactorUsedPrefix      = "two_factor_used:"      // two_factor_used:{userID}:{time step}, blocks code replay
+	twoFactorChallengeTTL    = 5 * time.Minute
+	maxTwoFactorAttempts     = 5
+
+	// Accepted clock drift of authenticator apps, in time steps either way
+	twoFactorSkew = 1
+
+	backupCodeCount = 10
+
+	// Google sign-in: the state ties the callback to the login started here, an
+	// identity without an account gets a registration token to complete the profile
+	oauthStatePrefix        = "oauth_state:"        // oauth_state:{state} -> PKCE verifier
+	oauthRegistrationPrefix = "oauth_registration:" // oauth_registration:{sha256(token)} -> identity JSON
+	oauthStateTTL           = 10 * time.Minute
+	oauthRegistrationTTL    = 15 * time.Minute
+
+	// Sessions: one per signed-in device, keyed by its current refresh token and
+	// moved to the new refresh token on every refresh
+	sessionPrefix = "session:" // session:{userID}:{refresh token ID} -> hash of the device metadata
+
+	// Refresh token rotation: a redeemed refresh token is remembered until it expires.
+	// Presenting it again means it was copied, so its whole family (sign-in) is revoked.
+	refreshTokenUsedPrefix = "refresh_token_used:" // refresh_token_used:{userID}:{token ID} -> family ID
+	tokenFamilyPrefix      = "token_family:"       // token_family:{userID}:{family ID} -> current refresh token ID
+
+	// Remember me: long-lived sessions are also indexed across users, so they can be
+	// revoked in bulk without touching regular sessions
+	rememberMeSessionsKey  = "remember_me_sessions" // sorted set of {userID}:{family ID}, scored by expiry
+	rememberMeChallengeTag = ":remember_me"         // Appended to the user ID of a two-factor challenge
+	rememberMeRevokeBatch  = 100
+
+	// Session limit: the sign-ins of a user by start time, so a login beyond
+	// SESSION_MAX_PER_USER can sign out the oldest one
+	userSessionsPrefix      = "user_sessions:" // user_sessions:{userID} -> sorted set of family IDs, scored by sign-in time (ms)
+	sessionEvictedEventType = "auth.session_evicted"
+
+	// Keys examined per SCAN call when revoking all tokens of a user
+	revokeScanBatchSize = 500
+
+	// Phone login: a one-time code sent by SMS or WhatsApp. Failed codes count per
+	// account and are not reset by resending, so resends do not buy extra guesses.
+	// Support impersonation: a short-lived access token acting as the user, without
+	// refresh token so it cannot outlive impersonationTTL
+	impersonationTTL = 15 * time.Minute
+
+	loginOTPCodePrefix      = "login_otp:code:"     // login_otp:code:{userID} -> sha256(code)
+	loginOTPCooldownPrefix  = "login_otp:cooldown:" // login_otp:cooldown:{userID}, blocks resends
+	loginOTPAttemptsPrefix  = "login_otp:attempts:" // login_otp:attempts:{userID} -> failed codes
+	loginOTPTTL             = 5 * time.Minute
+	loginOTPResendCooldown  = 1 * time.Minute
+	loginOTPAttemptsWindow  = 15 * time.Minute
+	maxLoginOTPAttempts     = 5
+	loginOTPEventType       = "auth.login_otp"
+	defaultPhoneCountryCode = "62"
 )
 
+// Persistent lockouts escalate: the nth consecutive lockout lasts loginLockoutDurations[n-1],
+// the last duration repeats
+var loginLockoutDurations = []time.Duration{
+	loginLockoutPeriod,
+	15 * time.Minute,
+	1 * time.Hour,
+	24 * time.Hour,
+}
+
+// Accounts whose role grants any of these permissions cannot be impersonated, so
+// impersonation never widens what the admin is allowed to do
+var impersonationBlockedPermissions = []string{
+	entity.PermissionUserImpersonate,
+	entity.PermissionUserManage,
+	entity.PermissionRoleManage,
+	entity.PermissionSystemManage,
+}
+
 // Lua script: atomically INCR attempt count and set TTL on first attempt
 var loginRateLimitScript = redis.NewScript(`
 	local current = redis.call('INCR', KEYS[1])
@@ -60,10 +171,28 @@ var loginRateLimitScript = redis.NewScript(`
 
 type AuthUsecase interface {
 	Register(ctx context.Context, user *entity.User) (*dto.UserResponse, error)
-	Login(ctx context.Context, req *dto.LoginRequest) (*dto.TokenResponse, error)
+	Login(ctx context.Context, req *dto.LoginRequest) (*dto.LoginResponse, error)
+	LoginTwoFactor(ctx context.Context, req *dto.LoginTwoFactorRequest) (*dto.TokenResponse, error)
 	Logout(ctx context.Context, accessTokenID, refreshTokenID string) error
 	RefreshToken(ctx context.Context, req *dto.RefreshTokenRequest) (*dto.TokenResponse, error)
 	GetCurrentUser(ctx context.Context, userID uuid.UUID) (*dto.UserResponse, error)
+	SetupTwoFactor(ctx context.Context, userID uuid.UUID) (*dto.TwoFactorSetupResponse, error)
+	EnableTwoFactor(ctx context.Context, userID uuid.UUID, req *dto.EnableTwoFactorRequest) (*dto.TwoFactorBackupCodesResponse, error)
+	DisableTwoFactor(ctx context.Context, userID uuid.UUID, req *dto.DisableTwoFactorRequest) error
+	ResetTwoFactor(ctx context.Context, adminID, userID uuid.UUID) error
+	UnlockUser(ctx context.Context, adminID, userID uuid.UUID) error
+	ChangePassword(ctx context.Context, userID uuid.UUID, req *dto.ChangePasswordRequest) (*dto.TokenResponse, error)
+	SetMustChangePassword(ctx context.Context, adminID, userID uuid.UUID, required bool) error
+	Impersonate(ctx context.Context, adminID, userID uuid.UUID, req *dto.ImpersonateRequest) (*dto.ImpersonationResponse, error)
+	GetSessions(ctx context.Context, userID uuid.UUID, currentAccessTokenID string) ([]dto.SessionResponse, error)
+	GetLoginHistory(ctx context.Context, userID uuid.UUID, page, limit int) ([]dto.LoginHistoryResponse, int64, error)
+	RevokeSession(ctx context.Context, userID uuid.UUID, tokenID string) error
+	RevokeRememberMeSessions(ctx context.Context, adminID uuid.UUID) (*dto.RevokeRememberMeSessionsResponse, error)
+	GoogleAuthURL(ctx context.Context) (string, error)
+	GoogleCallback(ctx context.Context, code, state string) (*dto.OAuthLoginResponse, error)
+	GoogleRegister(ctx context.Context, req *dto.GoogleRegisterRequest) (*dto.LoginResponse, error)
+	RequestLoginOTP(ctx context.Context, req *dto.RequestLoginOTPRequest) error
+	VerifyLoginOTP(ctx context.Context, req *dto.VerifyLoginOTPRequest) (*dto.LoginResponse, error)
 }
 
 type authUsecase struct {
@@ -74,6 +203,17 @@ type authUsecase struct {
 	jwtService   *jwt.JWTService
 	redisClient  *redis.Client
 	auditService service.AuditService
+	cfg          *config.Config
+	google       *oauth.GoogleProvider
+
+	patientProfileRepo  repository.PatientProfileRepository
+	notificationService *service.NotificationService
+	loginHistoryRepo    repository.LoginHistoryRepository
+	permissionService   service.PermissionService
+	outboxService       *service.OutboxService
+	formatService       service.FormatService
+	specDefaultRepo     repository.SpecializationDefaultRepository
+	eventBus            *service.EventBus
 }
 
 func NewAuthUsecase(
@@ -84,8 +224,18 @@ func NewAuthUsecase(
 	jwtService *jwt.JWTService,
 	redisClient *redis.Client,
 	auditService service.AuditService,
+	cfg *config.Config,
+	google *oauth.GoogleProvider,
+	patientProfileRepo repository.PatientProfileRepository,
+	notificationService *service.NotificationService,
+	loginHistoryRepo repository.LoginHistoryRepository,
+	permissionService service.PermissionService,
+	outboxService *service.OutboxService,
+	formatService service.FormatService,
+	specDefaultRepo repository.SpecializationDefaultRepository,
+	eventBus *service.EventBus,
 ) AuthUsecase {
-	return &authUsecase{
+	u := &authUsecase{
 		db:           db,
 		log:          log,
 		userRepo:     userRepo,
@@ -93,7 +243,20 @@ func NewAuthUsecase(
 		jwtService:   jwtService,
 		redisClient:  redisClient,
 		auditService: auditService,
+		cfg:          cfg,
+		google:       google,
+
+		patientProfileRepo:  patientProfileRepo,
+		notificationService: notificationService,
+		loginHistoryRepo:    loginHistoryRepo,
+		permissionService:   permissionService,
+		outboxService:       outboxService,
+		formatService:       formatService,
+		specDefaultRepo:     specDefaultRepo,
+		eventBus:            eventBus,
 	}
+	u.registerEventHandlers()
+	return u
 }
 
 // =============================================================================
@@ -119,6 +282,18 @@ func (u *authUsecase) Register(ctx context.Context, user *entity.User) (*dto.Use
 	tx := u.db.WithContext(ctx).Begin()
 	defer tx.Rollback()
 
+	// Doctors start at the consultation fee of their specialization
+	if user.DoctorProfile != nil {
+		defaults, err := u.specDefaultRepo.FindBySpecializationID(tx, user.DoctorProfile.SpecializationID)
+		if err != nil {
+			go u.log.Warnf("Failed to find specialization defaults: %+v", err)
+			return nil, err
+		}
+		if defaults != nil {
+			user.DoctorProfile.ConsultationFee = defaults.Fee
+		}
+	}
+
 	if err := u.userRepo.Create(tx, user); err != nil {
 		go u.log.Warnf("Failed to create user: %+v", err)
 		if isDuplicateKeyError(err, "email") {
@@ -133,6 +308,9 @@ func (u *authUsecase) Register(ctx context.Context, user *entity.User) (*dto.Use
 		if isForeignKeyError(err, "role") {
 			return nil, ErrRoleNotFound
 		}
+		if isForeignKeyError(err, "specialization") {
+			return nil, ErrSpecializationNotFound
+		}
 		return nil, err
 	}
 
@@ -141,16 +319,16 @@ func (u *authUsecase) Register(ctx context.Context, user *entity.User) (*dto.Use
 		return nil, err
 	}
 
-	// Non-blocking audit log: register success
-	go func() {
-		ctx := context.Background()
-		if err := u.auditService.LogCreate(ctx, u.db, &user.ID, entity.AuditActionUserRegister, "user", user.ID.String(), entity.JSON{
+	u.eventBus.Publish(ctx, &entity.DomainEvent{
+		Type:          entity.EventUserRegistered,
+		AggregateType: "user",
+		AggregateID:   user.ID.String(),
+		ActorID:       &user.ID,
+		Payload: entity.JSON{
 			"email":   user.Email,
 			"role_id": user.RoleID,
-		}); err != nil {
-			u.log.Warnf("Failed to log register audit: %+v", err)
-		}
-	}()
+		},
+	})
 
 	return converter.UserToResponse(user), nil
 }
@@ -159,7 +337,12 @@ func (u *authUsecase) Register(ctx context.Context, user *entity.User) (*dto.Use
 // Login — with Redis rate limiting
 // =============================================================================
 
-func (u *authUsecase) Login(ctx context.Context, req *dto.LoginRequest) (*dto.TokenResponse, error) {
+// Login checks the password and returns the token pair. Accounts with two-factor
+// authentication get a challenge instead, completed in LoginTwoFactor.
+//
+// Wrong passwords are counted in Redis; the last allowed attempt locks the account in
+// the database (see recordFailedPassword). Unknown emails only get the Redis lock.
+func (u *authUsecase) Login(ctx context.Context, req *dto.LoginRequest) (*dto.LoginResponse, error) {
 	// ---- Rate Limit Check ----
 	attemptsKey := fmt.Sprintf("%s%s", loginAttemptsPrefix, req.Email)
 
@@ -170,14 +353,15 @@ func (u *authUsecase) Login(ctx context.Context, req *dto.LoginRequest) (*dto.To
 	}
 	if count >= maxLoginAttempts {
 		go u.log.Warnf("Account locked for email %s: too many login attempts", req.Email)
-		// Non-blocking audit log: account locked
-		go func() {
-			ctx := context.Background()
-			u.auditService.LogCreate(ctx, u.db, nil, "user.login_locked", "user", "", entity.JSON{
+		u.recordLogin(ctx, nil, req.Email, entity.LoginMethodPassword, false, entity.LoginFailureAccountLocked)
+		u.eventBus.Publish(ctx, &entity.DomainEvent{
+			Type:          entity.EventUserLoginLocked,
+			AggregateType: "user",
+			Payload: entity.JSON{
 				"email":  req.Email,
 				"reason": "too many login attempts",
-			})
-		}()
+			},
+		})
 		return nil, ErrAccountLocked
 	}
 
@@ -187,21 +371,49 @@ func (u *authUsecase) Login(ctx context.Context, req *dto.LoginRequest) (*dto.To
 		go u.log.Warnf("Failed to find user by email: %+v", err)
 		// Increment attempt on user-not-found to prevent enumeration
 		u.incrementLoginAttempts(ctx, attemptsKey)
+		u.recordLogin(ctx, nil, req.Email, entity.LoginMethodPassword, false, entity.LoginFailureUnknownEmail)
+		return nil, ErrInvalidCredentials
+	}
+
+	// ---- Persistent lockout: checked before the password, so it cannot be probed ----
+	if user.IsLocked(time.Now()) {
+		u.recordLogin(ctx, &user.ID, req.Email, entity.LoginMethodPassword, false, entity.LoginFailureAccountLocked)
+		u.eventBus.Publish(ctx, &entity.DomainEvent{
+			Type:          entity.EventUserLoginLocked,
+			AggregateType: "user",
+			AggregateID:   user.ID.String(),
+			ActorID:       &user.ID,
+			Payload: entity.JSON{
+				"email":        req.Email,
+				"reason":       "account locked",
+				"locked_until": user.LockedUntil,
+			},
+		})
+		return nil, ErrAccountLocked
+	}
+
+	// Pre-registered accounts have no password until the patient claims them
+	if user.IsPendingClaim() {
+		u.incrementLoginAttempts(ctx, attemptsKey)
+		u.recordLogin(ctx, &user.ID, req.Email, entity.LoginMethodPassword, false, entity.LoginFailureInvalidPassword)
 		return nil, ErrInvalidCredentials
 	}
 
 	// ---- Verify Password ----
 	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(req.Password)); err != nil {
 		go u.log.Warnf("Invalid credentials for email %s: %+v", req.Email, err)
-		u.incrementLoginAttempts(ctx, attemptsKey)
-		// Non-blocking audit log: login failed
-		go func() {
-			ctx := context.Background()
-			u.auditService.LogCreate(ctx, u.db, &user.ID, "user.login_failed", "user", user.ID.String(), entity.JSON{
+		u.recordFailedPassword(ctx, user, attemptsKey)
+		u.recordLogin(ctx, &user.ID, req.Email, entity.LoginMethodPassword, false, entity.LoginFailureInvalidPassword)
+		u.eventBus.Publish(ctx, &entity.DomainEvent{
+			Type:          entity.EventUserLoginFailed,
+			AggregateType: "user",
+			AggregateID:   user.ID.String(),
+			ActorID:       &user.ID,
+			Payload: entity.JSON{
 				"email":  req.Email,
 				"reason": "invalid password",
-			})
-		}()
+			},
+		})
 		return nil, ErrInvalidCredentials
 	}
 
@@ -209,47 +421,224 @@ func (u *authUsecase) Login(ctx context.Context, req *dto.LoginRequest) (*dto.To
 	if delErr := u.redisClient.Del(ctx, attemptsKey).Err(); delErr != nil {
 		go u.log.Warnf("Failed to reset login attempts: %+v", delErr)
 	}
+	if user.LockoutCount > 0 || user.LockedUntil != nil {
+		if err := u.userRepo.Unlock(u.db.WithContext(ctx), user.ID); err != nil {
+			go u.log.Warnf("Failed to reset lockout count: %+v", err)
+		}
+	}
+
+	return u.completeLogin(ctx, user, entity.LoginMethodPassword, req.RememberMe, entity.JSON{
+		"email":       user.Email,
+		"remember_me": req.RememberMe,
+	})
+}
+
+// completeLogin issues the tokens of an authenticated user, or a challenge
+// when the account has two-factor authentication on (remembering rememberMe)
+func (u *authUsecase) completeLogin(ctx context.Context, user *entity.User, method string, rememberMe bool, auditValue entity.JSON) (*dto.LoginResponse, error) {
+	// A locked account cannot sign in by other methods either (phone code, Google)
+	if user.IsLocked(time.Now()) {
+		u.recordLogin(ctx, &user.ID, user.Email, method, false, entity.LoginFailureAccountLocked)
+		return nil, ErrAccountLocked
+	}
+
+	// ---- Second factor: no tokens until a valid code ----
+	if user.IsTwoFactorEnabled() {
+		challengeToken, err := u.createTwoFactorChallenge(ctx, user.ID, rememberMe)
+		if err != nil {
+			return nil, err
+		}
+		return &dto.LoginResponse{
+			TwoFactorRequired:  true,
+			ChallengeToken:     challengeToken,
+			ChallengeExpiresIn: int64(twoFactorChallengeTTL.Seconds()),
+		}, nil
+	}
+
+	tokens, err := u.issueTokens(ctx, user, rememberMe)
+	if err != nil {
+		return nil, err
+	}
+	u.recordLogin(ctx, &user.ID, user.Email, method, true, "")
+
+	u.eventBus.Publish(ctx, &entity.DomainEvent{
+		Type:          entity.EventUserLoggedIn,
+		AggregateType: "user",
+		AggregateID:   user.ID.String(),
+		ActorID:       &user.ID,
+		Payload:       auditValue,
+	})
+
+	return &dto.LoginResponse{TokenResponse: tokens}, nil
+}
 
+// issueTokens generates the token pair of the user and stores it in Redis
+func (u *authUsecase) issueTokens(ctx context.Context, user *entity.User, rememberMe bool) (*dto.TokenResponse, error) {
 	// ---- Generate Tokens ----
-	accessToken, accessTokenID, err := u.jwtService.GenerateAccessToken(user.ID, user.Email, user.RoleID)
+	roleName, permissions, err := u.roleClaims(ctx, user.RoleID)
+	if err != nil {
+		return nil, err
+	}
+	accessToken, accessTokenID, err := u.jwtService.GenerateAccessToken(user.ID, user.Email, user.RoleID, roleName, permissions)
 	if err != nil {
 		go u.log.Warnf("Failed to generate access token: %+v", err)
 		return nil, err
 	}
 
-	refreshToken, refreshTokenID, err := u.jwtService.GenerateRefreshToken(user.ID, user.Email, user.RoleID)
+	// Every sign-in starts a new token family
+	familyID := uuid.New().String()
+	refreshToken, refreshTokenID, err := u.jwtService.GenerateRefreshToken(user.ID, user.Email, user.RoleID, familyID, rememberMe)
 	if err != nil {
 		go u.log.Warnf("Failed to generate refresh token: %+v", err)
 		return nil, err
 	}
 
 	// ---- Store tokens in Redis ----
-	accessKey := fmt.Sprintf("access_token:%s:%s", user.ID.String(), accessTokenID)
-	refreshKey := fmt.Sprintf("refresh_token:%s:%s", user.ID.String(), refreshTokenID)
-
-	if err := u.redisClient.Set(ctx, accessKey, "valid", u.jwtService.GetAccessExpiry()).Err(); err != nil {
-		go u.log.Warnf("Failed to store access token in Redis: %+v", err)
+	// The marker restricts the tokens to the password change (see the auth middleware)
+	if user.MustChangePassword {
+		if err := u.redisClient.Set(ctx, passwordChangeRequiredKey(user.ID), 1, 0).Err(); err != nil {
+			go u.log.Warnf("Failed to mark required password change: %+v", err)
+			return nil, err
+		}
+	}
+	if err := u.storeSession(ctx, user.ID, accessTokenID, refreshTokenID, familyID, time.Now(), rememberMe); err != nil {
+		go u.log.Warnf("Failed to store tokens in Redis: %+v", err)
 		return nil, err
 	}
+	u.enforceSessionLimit(ctx, user)
 
-	if err := u.redisClient.Set(ctx, refreshKey, "valid", u.jwtService.GetRefreshExpiry()).Err(); err != nil {
-		go u.log.Warnf("Failed to store refresh token in Redis: %+v", err)
-		return nil, err
+	return &dto.TokenResponse{
+		AccessToken:            accessToken,
+		RefreshToken:           refreshToken,
+		ExpiresIn:              int64(u.jwtService.GetAccessExpiry().Seconds()),
+		PasswordChangeRequired: user.MustChangePassword,
+	}, nil
+}
+
+// roleClaims returns the role name and permission codes embedded in access tokens of the role
+func (u *authUsecase) roleClaims(ctx context.Context, roleID int) (string, []string, error) {
+	role, err := u.roleRepo.FindByID(ctx, u.db, roleID)
+	if err != nil {
+		go u.log.Warnf("Failed to find role by ID: %+v", err)
+		return "", nil, err
+	}
+	if role == nil {
+		return "", nil, ErrRoleNotFound
+	}
+
+	permissions, err := u.permissionService.GetPermissions(ctx, roleID)
+	if err != nil {
+		return "", nil, err
+	}
+	return role.RoleName, permissions, nil
+}
+
+// recordFailedPassword counts a wrong password of an existing account. Reaching
+// maxLoginAttempts locks the account in the database, each consecutive lockout longer
+// than the previous one; the lock then replaces the Redis attempt counter.
+func (u *authUsecase) recordFailedPassword(ctx context.Context, user *entity.User, attemptsKey string) {
+	lockoutSeconds := int(loginLockoutPeriod.Seconds())
+	count, err := loginRateLimitScript.Run(ctx, u.redisClient, []string{attemptsKey}, lockoutSeconds).Int()
+	if err != nil {
+		go u.log.Warnf("Failed to increment login attempts: %+v", err)
+		return
+	}
+	if count < maxLoginAttempts {
+		return
+	}
+
+	lockoutCount := user.LockoutCount + 1
+	duration := loginLockoutDurations[len(loginLockoutDurations)-1]
+	if lockoutCount <= len(loginLockoutDurations) {
+		duration = loginLockoutDurations[lockoutCount-1]
+	}
+	lockedAt := time.Now()
+	lockedUntil := lockedAt.Add(duration)
+
+	tx := u.db.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := u.userRepo.Lock(tx, user.ID, lockedAt, lockedUntil); err != nil {
+		u.log.Warnf("Failed to lock account %s: %+v", user.ID, err)
+		return
+	}
+
+	if err := u.auditService.LogUpdate(ctx, tx, &user.ID, entity.AuditActionAccountLock, "user", user.ID.String(), entity.JSON{
+		"locked_until":  user.LockedUntil,
+		"lockout_count": user.LockoutCount,
+	}, entity.JSON{
+		"locked_until":  lockedUntil,
+		"lockout_count": lockoutCount,
+		"reason":        "too many login attempts",
+	}); err != nil {
+		u.log.Warnf("Failed to create audit log: %+v", err)
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		u.log.Warnf("Failed commit transaction: %+v", err)
+		return
+	}
+
+	if err := u.redisClient.Del(ctx, attemptsKey).Err(); err != nil {
+		go u.log.Warnf("Failed to reset login attempts: %+v", err)
+	}
+	u.log.Infof("Account %s locked until %s (lockout %d)", user.ID, lockedUntil.Format(time.RFC3339), lockoutCount)
+}
+
+// recordLogin keeps a login attempt in the login history with the client it came from,
+// and the last login of successful ones. Non-blocking: a failed write never fails a login.
+func (u *authUsecase) recordLogin(ctx context.Context, userID *uuid.UUID, email, method string, success bool, failureReason string) {
+	info, _ := middleware.GetClientInfoFromContext(ctx)
+	entry := &entity.LoginHistory{
+		UserID:        userID,
+		Email:         email,
+		Method:        method,
+		Success:       success,
+		FailureReason: failureReason,
+		IPAddress:     info.IPAddress,
+		UserAgent:     info.UserAgent,
+		Device:        info.Device,
 	}
 
-	// Non-blocking audit log: login success
 	go func() {
 		ctx := context.Background()
-		u.auditService.LogCreate(ctx, u.db, &user.ID, entity.AuditActionUserLogin, "user", user.ID.String(), entity.JSON{
-			"email": user.Email,
-		})
+		db := u.db.WithContext(ctx)
+
+		// Checked before this login is recorded, which makes its client known
+		newDevice := success && userID != nil && u.isNewDevice(db, *userID, info.UserAgent)
+
+		if err := u.loginHistoryRepo.Create(db, entry); err != nil {
+			u.log.Warnf("Failed to record login history: %+v", err)
+		}
+		if success && userID != nil {
+			if err := u.userRepo.UpdateLastLogin(db, *userID, time.Now()); err != nil {
+				u.log.Warnf("Failed to update last login: %+v", err)
+			}
+		}
+
+		// The audit log alerts the user (security.alert)
+		if newDevice {
+			if err := u.auditService.LogCreate(ctx, db, userID, entity.AuditActionNewDeviceLogin, "user", userID.String(), entity.JSON{
+				"method":     method,
+				"device":     info.Device,
+				"ip_address": info.IPAddress,
+				"user_agent": info.UserAgent,
+			}); err != nil {
+				u.log.Warnf("Failed to create audit log: %+v", err)
+			}
+		}
 	}()
+}
 
-	return &dto.TokenResponse{
-		AccessToken:  accessToken,
-		RefreshToken: refreshToken,
-		ExpiresIn:    int64(u.jwtService.GetAccessExpiry().Seconds()),
-	}, nil
+// isNewDevice reports whether the user signed in before, but never from this client.
+// The first login of an account is not a new device.
+func (u *authUsecase) isNewDevice(db *gorm.DB, userID uuid.UUID, userAgent string) bool {
+	total, fromUserAgent, err := u.loginHistoryRepo.CountSuccessful(db, userID, userAgent)
+	if err != nil {
+		u.log.Warnf("Failed to count successful logins: %+v", err)
+		return false
+	}
+	return total > 0 && fromUserAgent == 0
 }
 
 // incrementLoginAttempts atomically increments the login attempt counter.
@@ -267,32 +656,19 @@ func (u *authUsecase) incrementLoginAttempts(ctx context.Context, key string) {
 // =============================================================================
 
 func (u *authUsecase) Logout(ctx context.Context, accessTokenID, refreshTokenID string) error {
-	// Delete tokens from Redis (pattern matching to find and delete)
-	accessPattern := fmt.Sprintf("access_token:*:%s", accessTokenID)
-	refreshPattern := fmt.Sprintf("refresh_token:*:%s", refreshTokenID)
+	// Tokens are keyed by their user, so no keyspace lookup is needed
+	userID, _ := middleware.GetUserIDFromContext(ctx)
 
-	// Delete access token
-	accessKeys, err := u.redisClient.Keys(ctx, accessPattern).Result()
-	if err != nil {
-		u.log.Warnf("Failed to get access token keys: %+v", err)
+	accessKey := fmt.Sprintf("access_token:%s:%s", userID.String(), accessTokenID)
+	if err := u.redisClient.Del(ctx, accessKey).Err(); err != nil {
+		u.log.Warnf("Failed to delete access token: %+v", err)
 		return err
 	}
-	if len(accessKeys) > 0 {
-		if err := u.redisClient.Del(ctx, accessKeys...).Err(); err != nil {
-			u.log.Warnf("Failed to delete access token: %+v", err)
-			return err
-		}
-	}
 
-	// Delete refresh token
-	refreshKeys, err := u.redisClient.Keys(ctx, refreshPattern).Result()
-	if err != nil {
-		u.log.Warnf("Failed to get refresh token keys: %+v", err)
-		return err
-	}
-	if len(refreshKeys) > 0 {
-		if err := u.redisClient.Del(ctx, refreshKeys...).Err(); err != nil {
-			u.log.Warnf("Failed to delete refresh token: %+v", err)
+	// The refresh token, its session and token family of the device
+	if refreshTokenID != "" {
+		if _, err := u.deleteSession(ctx, userID, refreshTokenID); err != nil {
+			u.log.Warnf("Failed to delete session: %+v", err)
 			return err
 		}
 	}
@@ -315,47 +691,92 @@ func (u *authUsecase) RefreshToken(ctx context.Context, req *dto.RefreshTokenReq
 		return nil, ErrInvalidToken
 	}
 
-	// Check if refresh token exists in Redis
+	// The new tokens follow the account as it is now, not the claims of the old token:
+	// erased or deactivated accounts get none, a locked account waits for the lock to end
+	user, err := u.userRepo.FindByID(u.db.WithContext(ctx), claims.UserID)
+	if err != nil {
+		u.log.Warnf("Failed to find user by ID: %+v", err)
+		return nil, err
+	}
+	if user == nil || user.IsErased() || (user.IsActive != nil && !*user.IsActive) {
+		return nil, ErrInvalidToken
+	}
+	if user.IsLocked(time.Now()) {
+		return nil, ErrAccountLocked
+	}
+
+	// Redeem the refresh token: deleting it is atomic, so only one request can use it
 	refreshKey := fmt.Sprintf("refresh_token:%s:%s", claims.UserID.String(), claims.TokenID)
-	exists, err := u.redisClient.Exists(ctx, refreshKey).Result()
+	redeemed, err := u.redisClient.Del(ctx, refreshKey).Result()
+	if err != nil {
+		u.log.Warnf("Failed to redeem refresh token in Redis: %+v", err)
+		return nil, err
+	}
+	if redeemed == 0 {
+		return nil, u.checkRefreshTokenReuse(ctx, claims)
+	}
+
+	// Tokens issued before rotation tracking start their family here
+	familyID := claims.FamilyID
+	if familyID == "" {
+		familyID = uuid.New().String()
+	}
+
+	usedKey := fmt.Sprintf("%s%s:%s", refreshTokenUsedPrefix, claims.UserID.String(), claims.TokenID)
+	usedTTL := u.jwtService.GetRefreshExpiryFor(claims.RememberMe)
+	if claims.ExpiresAt != nil {
+		usedTTL = time.Until(claims.ExpiresAt.Time)
+	}
+	if usedTTL > 0 {
+		if err := u.redisClient.Set(ctx, usedKey, familyID, usedTTL).Err(); err != nil {
+			u.log.Warnf("Failed to mark refresh token as used: %+v", err)
+		}
+	}
+
+	// The session moves to the new refresh token, keeping its sign-in time
+	oldSessionKey := sessionKey(claims.UserID, claims.TokenID)
+	session, err := u.redisClient.HGetAll(ctx, oldSessionKey).Result()
 	if err != nil {
-		u.log.Warnf("Failed to check refresh token in Redis: %+v", err)
+		u.log.Warnf("Failed to get session: %+v", err)
 		return nil, err
 	}
-	if exists == 0 {
-		return nil, ErrTokenRevoked
+	issuedAt := unixField(session["issued_at"])
+	if issuedAt.IsZero() {
+		issuedAt = time.Now() // Tokens issued before sessions were tracked
 	}
 
-	// Delete old refresh token
-	if err := u.redisClient.Del(ctx, refreshKey).Err(); err != nil {
-		u.log.Warnf("Failed to delete old refresh token: %+v", err)
+	// Delete the old session, with the access token it superseded so a revoked
+	// session never leaves an older access token behind
+	oldKeys := []string{oldSessionKey}
+	if oldAccessTokenID := session["access_token_id"]; oldAccessTokenID != "" {
+		oldKeys = append(oldKeys, fmt.Sprintf("access_token:%s:%s", claims.UserID.String(), oldAccessTokenID))
+	}
+	if err := u.redisClient.Del(ctx, oldKeys...).Err(); err != nil {
+		u.log.Warnf("Failed to delete old session: %+v", err)
 		return nil, err
 	}
 
-	// Generate new tokens
-	accessToken, accessTokenID, err := u.jwtService.GenerateAccessToken(claims.UserID, claims.Email, claims.RoleID)
+	// Generate new tokens, with the current role and its permissions
+	roleName, permissions, err := u.roleClaims(ctx, user.RoleID)
+	if err != nil {
+		return nil, err
+	}
+	accessToken, accessTokenID, err := u.jwtService.GenerateAccessToken(user.ID, user.Email, user.RoleID, roleName, permissions)
 	if err != nil {
 		u.log.Warnf("Failed to generate access token: %+v", err)
 		return nil, err
 	}
 
-	refreshToken, refreshTokenID, err := u.jwtService.GenerateRefreshToken(claims.UserID, claims.Email, claims.RoleID)
+	// A remember-me session stays one on rotation
+	refreshToken, refreshTokenID, err := u.jwtService.GenerateRefreshToken(user.ID, user.Email, user.RoleID, familyID, claims.RememberMe)
 	if err != nil {
 		u.log.Warnf("Failed to generate refresh token: %+v", err)
 		return nil, err
 	}
 
 	// Store new tokens in Redis
-	accessKeyNew := fmt.Sprintf("access_token:%s:%s", claims.UserID.String(), accessTokenID)
-	refreshKeyNew := fmt.Sprintf("refresh_token:%s:%s", claims.UserID.String(), refreshTokenID)
-
-	if err := u.redisClient.Set(ctx, accessKeyNew, "valid", u.jwtService.GetAccessExpiry()).Err(); err != nil {
-		u.log.Warnf("Failed to store access token in Redis: %+v", err)
-		return nil, err
-	}
-
-	if err := u.redisClient.Set(ctx, refreshKeyNew, "valid", u.jwtService.GetRefreshExpiry()).Err(); err != nil {
-		u.log.Warnf("Failed to store refresh token in Redis: %+v", err)
+	if err := u.storeSession(ctx, claims.UserID, accessTokenID, refreshTokenID, familyID, issuedAt, claims.RememberMe); err != nil {
+		u.log.Warnf("Failed to store tokens in Redis: %+v", err)
 		return nil, err
 	}
 
@@ -366,104 +787,1557 @@ func (u *authUsecase) RefreshToken(ctx context.Context, req *dto.RefreshTokenReq
 	}, nil
 }
 
-// =============================================================================
-// GetCurrentUser
-// =============================================================================
-
-func (u *authUsecase) GetCurrentUser(ctx context.Context, userID uuid.UUID) (*dto.UserResponse, error) {
-	user, err := u.userRepo.FindByID(u.db, userID)
+// checkRefreshTokenReuse tells an unknown refresh token apart from a redeemed one.
+// A redeemed token presented again was stolen (or the thief already rotated it):
+// the current token of its family is revoked and a security event is audited.
+func (u *authUsecase) checkRefreshTokenReuse(ctx context.Context, claims *jwt.Claims) error {
+	usedKey := fmt.Sprintf("%s%s:%s", refreshTokenUsedPrefix, claims.UserID.String(), claims.TokenID)
+	familyID, err := u.redisClient.Get(ctx, usedKey).Result()
 	if err != nil {
-		u.log.Warnf("Failed to find user by ID: %+v", err)
-		return nil, err
+		if errors.Is(err, redis.Nil) {
+			return ErrTokenRevoked // Logged out or revoked, not reused
+		}
+		u.log.Warnf("Failed to check refresh token reuse: %+v", err)
+		return err
 	}
-	if user == nil {
-		return nil, ErrUserNotFound
+
+	familyKey := fmt.Sprintf("%s%s:%s", tokenFamilyPrefix, claims.UserID.String(), familyID)
+	currentTokenID, err := u.redisClient.GetDel(ctx, familyKey).Result()
+	if err != nil && !errors.Is(err, redis.Nil) {
+		u.log.Warnf("Failed to get token family: %+v", err)
+		return err
+	}
+	if currentTokenID != "" {
+		if _, err := u.deleteSession(ctx, claims.UserID, currentTokenID); err != nil {
+			u.log.Warnf("Failed to revoke token family: %+v", err)
+			return err
+		}
 	}
 
-	return converter.UserToResponse(user), nil
+	u.log.Warnf("Refresh token reuse detected for user %s, token family %s revoked", claims.UserID, familyID)
+
+	info, _ := middleware.GetClientInfoFromContext(ctx)
+	userID := claims.UserID
+
+	u.eventBus.Publish(ctx, &entity.DomainEvent{
+		Type:          entity.EventRefreshTokenReused,
+		AggregateType: "user",
+		AggregateID:   userID.String(),
+		ActorID:       &userID,
+		Payload: entity.JSON{
+			"token_id":         claims.TokenID,
+			"family_id":        familyID,
+			"revoked_token_id": currentTokenID,
+			"ip_address":       info.IPAddress,
+			"user_agent":       info.UserAgent,
+		},
+	})
+
+	return ErrTokenReused
 }
 
 // =============================================================================
-// Helper: Token Validation
+// Sessions
 // =============================================================================
 
-// IsTokenValid checks if a token is valid in Redis
-func (u *authUsecase) IsTokenValid(ctx context.Context, userID uuid.UUID, tokenID string, tokenType jwt.TokenType) (bool, error) {
-	var key string
-	if tokenType == jwt.AccessToken {
-		key = fmt.Sprintf("access_token:%s:%s", userID.String(), tokenID)
-	} else {
-		key = fmt.Sprintf("refresh_token:%s:%s", userID.String(), tokenID)
+// GetSessions lists the signed-in devices of the user, most recently used first.
+// The session of currentAccessTokenID is marked as current.
+func (u *authUsecase) GetSessions(ctx context.Context, userID uuid.UUID, currentAccessTokenID string) ([]dto.SessionResponse, error) {
+	prefix := sessionPrefix + userID.String() + ":"
+
+	var keys []string
+	iter := u.redisClient.Scan(ctx, 0, prefix+"*", 100).Iterator()
+	for iter.Next(ctx) {
+		keys = append(keys, iter.Val())
+	}
+	if err := iter.Err(); err != nil {
+		u.log.Warnf("Failed to scan sessions: %+v", err)
+		return nil, err
 	}
 
-	exists, err := u.redisClient.Exists(ctx, key).Result()
-	if err != nil {
-		u.log.Warnf("Failed to check token validity: %+v", err)
-		return false, err
+	pipe := u.redisClient.Pipeline()
+	cmds := make([]*redis.MapStringStringCmd, len(keys))
+	for i, key := range keys {
+		cmds[i] = pipe.HGetAll(ctx, key)
+	}
+	if len(keys) > 0 {
+		if _, err := pipe.Exec(ctx); err != nil {
+			u.log.Warnf("Failed to get sessions: %+v", err)
+			return nil, err
+		}
 	}
 
-	return exists > 0, nil
+	sessions := make([]dto.SessionResponse, 0, len(keys))
+	for i, cmd := range cmds {
+		fields := cmd.Val()
+		if len(fields) == 0 {
+			continue // Expired since the scan
+		}
+		sessions = append(sessions, dto.SessionResponse{
+			TokenID:    strings.TrimPrefix(keys[i], prefix),
+			Device:     fields["device"],
+			IPAddress:  fields["ip_address"],
+			UserAgent:  fields["user_agent"],
+			IssuedAt:   unixField(fields["issued_at"]),
+			LastUsedAt: unixField(fields["last_used_at"]),
+			RememberMe: fields["remember_me"] == "1",
+			Current:    currentAccessTokenID != "" && fields["access_token_id"] == currentAccessTokenID,
+		})
+	}
+
+	sort.Slice(sessions, func(i, j int) bool {
+		return sessions[i].LastUsedAt.After(sessions[j].LastUsedAt)
+	})
+
+	return sessions, nil
 }
 
-// RevokeAllUserTokens revokes all tokens for a user (useful when password changed or account compromised)
-func (u *authUsecase) RevokeAllUserTokens(ctx context.Context, userID uuid.UUID) error {
-	// Delete all access tokens for user
-	accessPattern := fmt.Sprintf("access_token:%s:*", userID.String())
-	accessKeys, err := u.redisClient.Keys(ctx, accessPattern).Result()
+// GetLoginHistory returns one page of the login attempts of the user, newest first
+func (u *authUsecase) GetLoginHistory(ctx context.Context, userID uuid.UUID, page, limit int) ([]dto.LoginHistoryResponse, int64, error) {
+	user, err := u.userRepo.FindByID(u.db, userID)
 	if err != nil {
-		u.log.Warnf("Failed to get access token keys: %+v", err)
-		return err
+		u.log.Warnf("Failed to find user by ID: %+v", err)
+		return nil, 0, err
 	}
-	if len(accessKeys) > 0 {
-		if err := u.redisClient.Del(ctx, accessKeys...).Err(); err != nil {
-			u.log.Warnf("Failed to delete access tokens: %+v", err)
-			return err
-		}
+	if user == nil {
+		return nil, 0, ErrUserNotFound
+	}
+
+	entries, total, err := u.loginHistoryRepo.FindByUserID(u.db.WithContext(ctx), userID, page, limit)
+	if err != nil {
+		u.log.Warnf("Failed to find login history: %+v", err)
+		return nil, 0, err
 	}
+	return converter.LoginHistoriesToResponse(entries), total, nil
+}
 
-	// Delete all refresh tokens for user
-	refreshPattern := fmt.Sprintf("refresh_token:%s:*", userID.String())
-	refreshKeys, err := u.redisClient.Keys(ctx, refreshPattern).Result()
+// RevokeSession signs out one device of the user: its refresh token, its current
+// access token and the session itself are deleted
+func (u *authUsecase) RevokeSession(ctx context.Context, userID uuid.UUID, tokenID string) error {
+	session, err := u.deleteSession(ctx, userID, tokenID)
 	if err != nil {
-		u.log.Warnf("Failed to get refresh token keys: %+v", err)
+		u.log.Warnf("Failed to revoke session: %+v", err)
 		return err
 	}
-	if len(refreshKeys) > 0 {
-		if err := u.redisClient.Del(ctx, refreshKeys...).Err(); err != nil {
-			u.log.Warnf("Failed to delete refresh tokens: %+v", err)
-			return err
-		}
+	if session == nil {
+		return ErrSessionNotFound
 	}
 
+	u.eventBus.Publish(ctx, &entity.DomainEvent{
+		Type:          entity.EventSessionRevoked,
+		AggregateType: "user",
+		AggregateID:   userID.String(),
+		ActorID:       &userID,
+		Payload: entity.JSON{
+			"token_id":   tokenID,
+			"device":     session["device"],
+			"ip_address": session["ip_address"],
+		},
+	})
+
 	return nil
 }
 
-// =============================================================================
-// Helper: PostgreSQL Error Checks
-// =============================================================================
+// RevokeRememberMeSessions signs out every remember-me session of all users, e.g. after
+// shortening JWT_REMEMBER_ME_EXPIRY. Regular sessions are kept. Sessions started while
+// it runs may survive; only the sessions indexed when it starts are counted in.
+func (u *authUsecase) RevokeRememberMeSessions(ctx context.Context, adminID uuid.UUID) (*dto.RevokeRememberMeSessionsResponse, error) {
+	if err := u.redisClient.ZRemRangeByScore(ctx, rememberMeSessionsKey, "-inf", strconv.FormatInt(time.Now().Unix(), 10)).Err(); err != nil {
+		u.log.Warnf("Failed to drop expired remember-me sessions: %+v", err)
+	}
 
-// isDuplicateKeyError checks if the error is a PostgreSQL unique constraint violation
-// containing the specified constraint name
-func isDuplicateKeyError(err error, constraintName string) bool {
-	var pgErr *pgconn.PgError
-	if errors.As(err, &pgErr) {
-		// PostgreSQL error code 23505 = unique_violation
-		if pgErr.Code == "23505" && strings.Contains(strings.ToLower(pgErr.ConstraintName), strings.ToLower(constraintName)) {
-			return true
+	total, err := u.redisClient.ZCard(ctx, rememberMeSessionsKey).Result()
+	if err != nil {
+		u.log.Warnf("Failed to count remember-me sessions: %+v", err)
+		return nil, err
+	}
+
+	revoked := 0
+	for processed := int64(0); processed < total; {
+		members, err := u.redisClient.ZRange(ctx, rememberMeSessionsKey, 0, rememberMeRevokeBatch-1).Result()
+		if err != nil {
+			u.log.Warnf("Failed to get remember-me sessions: %+v", err)
+			return nil, err
+		}
+		if len(members) == 0 {
+			break
+		}
+
+		for _, member := range members {
+			deleted, err := u.revokeTokenFamily(ctx, member)
+			if err != nil {
+				u.log.Warnf("Failed to revoke remember-me session %s: %+v", member, err)
+				return nil, err
+			}
+			if deleted {
+				revoked++
+			}
+		}
+
+		// deleteSession unindexes the sessions it finds, this also drops the stale entries
+		removed := make([]interface{}, len(members))
+		for i, member := range members {
+			removed[i] = member
+		}
+		if err := u.redisClient.ZRem(ctx, rememberMeSessionsKey, removed...).Err(); err != nil {
+			u.log.Warnf("Failed to unindex remember-me sessions: %+v", err)
+			return nil, err
 		}
+		processed += int64(len(members))
 	}
-	return false
+
+	u.eventBus.Publish(ctx, &entity.DomainEvent{
+		Type:          entity.EventRememberMeRevoked,
+		AggregateType: "session",
+		ActorID:       &adminID,
+		Payload: entity.JSON{
+			"revoked": revoked,
+		},
+	})
+
+	return &dto.RevokeRememberMeSessionsResponse{Revoked: revoked}, nil
 }
 
-// isForeignKeyError checks if the error is a PostgreSQL foreign key violation
-// containing the specified constraint name
-func isForeignKeyError(err error, constraintName string) bool {
-	var pgErr *pgconn.PgError
-	if errors.As(err, &pgErr) {
-		// PostgreSQL error code 23503 = foreign_key_violation
-		if pgErr.Code == "23503" && strings.Contains(strings.ToLower(pgErr.ConstraintName), strings.ToLower(constraintName)) {
-			return true
+// revokeTokenFamily deletes the current session of a remember-me entry ({userID}:{family ID}).
+// Returns false when the session is already gone.
+func (u *authUsecase) revokeTokenFamily(ctx context.Context, member string) (bool, error) {
+	rawUserID, familyID, ok := strings.Cut(member, ":")
+	userID, err := uuid.Parse(rawUserID)
+	if !ok || err != nil {
+		return false, nil
+	}
+
+	familyKey := fmt.Sprintf("%s%s:%s", tokenFamilyPrefix, userID.String(), familyID)
+	refreshTokenID, err := u.redisClient.GetDel(ctx, familyKey).Result()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return false, nil
 		}
+		return false, err
+	}
+
+	session, err := u.deleteSession(ctx, userID, refreshTokenID)
+	if err != nil {
+		return false, err
+	}
+	return session != nil, nil
+}
+
+// deleteSession deletes the session of a refresh token with its tokens and family.
+// Returns the deleted session, nil when there is none.
+func (u *authUsecase) deleteSession(ctx context.Context, userID uuid.UUID, refreshTokenID string) (map[string]string, error) {
+	key := sessionKey(userID, refreshTokenID)
+	session, err := u.redisClient.HGetAll(ctx, key).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	// The refresh token goes even without a session (issued before sessions were tracked)
+	keys := []string{key, fmt.Sprintf("refresh_token:%s:%s", userID.String(), refreshTokenID)}
+	if accessTokenID := session["access_token_id"]; accessTokenID != "" {
+		keys = append(keys, fmt.Sprintf("access_token:%s:%s", userID.String(), accessTokenID))
+	}
+	if familyID := session["family_id"]; familyID != "" {
+		keys = append(keys, fmt.Sprintf("%s%s:%s", tokenFamilyPrefix, userID.String(), familyID))
+	}
+
+	pipe := u.redisClient.TxPipeline()
+	pipe.Del(ctx, keys...)
+	if familyID := session["family_id"]; familyID != "" {
+		pipe.ZRem(ctx, userSessionsKey(userID), familyID)
+		if session["remember_me"] == "1" {
+			pipe.ZRem(ctx, rememberMeSessionsKey, rememberMeMember(userID, familyID))
+		}
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return nil, err
+	}
+
+	if len(session) == 0 {
+		return nil, nil
+	}
+	return session, nil
+}
+
+// storeSession stores the token pair of a device with its session metadata, all or nothing.
+// The client of the request (see middleware.ClientInfo) describes the device.
+// Remember-me sessions live longer and are indexed in rememberMeSessionsKey.
+func (u *authUsecase) storeSession(ctx context.Context, userID uuid.UUID, accessTokenID, refreshTokenID, familyID string, issuedAt time.Time, rememberMe bool) error {
+	info, _ := middleware.GetClientInfoFromContext(ctx)
+	key := sessionKey(userID, refreshTokenID)
+	refreshExpiry := u.jwtService.GetRefreshExpiryFor(rememberMe)
+
+	pipe := u.redisClient.TxPipeline()
+	pipe.Set(ctx, fmt.Sprintf("access_token:%s:%s", userID.String(), accessTokenID), "valid", u.jwtService.GetAccessExpiry())
+	pipe.Set(ctx, fmt.Sprintf("refresh_token:%s:%s", userID.String(), refreshTokenID), "valid", refreshExpiry)
+	pipe.HSet(ctx, key, map[string]interface{}{
+		"access_token_id": accessTokenID,
+		"family_id":       familyID,
+		"device":          info.Device,
+		"ip_address":      info.IPAddress,
+		"user_agent":      info.UserAgent,
+		"issued_at":       issuedAt.Unix(),
+		"last_used_at":    time.Now().Unix(),
+		"remember_me":     rememberMe,
+	})
+	pipe.Expire(ctx, key, refreshExpiry)
+	pipe.Set(ctx, fmt.Sprintf("%s%s:%s", tokenFamilyPrefix, userID.String(), familyID), refreshTokenID, refreshExpiry)
+	// NX: a refresh keeps the sign-in time of the session
+	pipe.ZAddNX(ctx, userSessionsKey(userID), redis.Z{
+		Score:  float64(issuedAt.UnixMilli()),
+		Member: familyID,
+	})
+	pipe.Expire(ctx, userSessionsKey(userID), u.jwtService.GetRefreshExpiryFor(true))
+	if rememberMe {
+		// Each rotation extends the entry; expired entries are dropped on the way
+		now := time.Now()
+		pipe.ZAdd(ctx, rememberMeSessionsKey, redis.Z{
+			Score:  float64(now.Add(refreshExpiry).Unix()),
+			Member: rememberMeMember(userID, familyID),
+		})
+		pipe.ZRemRangeByScore(ctx, rememberMeSessionsKey, "-inf", strconv.FormatInt(now.Unix(), 10))
+	}
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+func sessionKey(userID uuid.UUID, refreshTokenID string) string {
+	return fmt.Sprintf("%s%s:%s", sessionPrefix, userID.String(), refreshTokenID)
+}
+
+func userSessionsKey(userID uuid.UUID) string {
+	return userSessionsPrefix + userID.String()
+}
+
+// enforceSessionLimit signs out the oldest sessions of the user beyond
+// SESSION_MAX_PER_USER and tells the user. Called after a sign-in; failures are
+// logged only, the login goes on.
+func (u *authUsecase) enforceSessionLimit(ctx context.Context, user *entity.User) {
+	limit := u.cfg.Session.MaxPerUser
+	if limit <= 0 {
+		return
+	}
+
+	key := userSessionsKey(user.ID)
+	familyIDs, err := u.redisClient.ZRange(ctx, key, 0, -1).Result() // Oldest first
+	if err != nil {
+		u.log.Warnf("Failed to get sessions of user %s: %+v", user.ID, err)
+		return
+	}
+	if len(familyIDs) <= limit {
+		return
+	}
+
+	// Sessions that expired are still indexed, they do not count
+	pipe := u.redisClient.Pipeline()
+	cmds := make([]*redis.StringCmd, len(familyIDs))
+	for i, familyID := range familyIDs {
+		cmds[i] = pipe.Get(ctx, fmt.Sprintf("%s%s:%s", tokenFamilyPrefix, user.ID.String(), familyID))
+	}
+	if _, err := pipe.Exec(ctx); err != nil && !errors.Is(err, redis.Nil) {
+		u.log.Warnf("Failed to get token families of user %s: %+v", user.ID, err)
+		return
+	}
+
+	var refreshTokenIDs []string
+	var expired []interface{}
+	for i, cmd := range cmds {
+		if errors.Is(cmd.Err(), redis.Nil) {
+			expired = append(expired, familyIDs[i])
+			continue
+		}
+		refreshTokenIDs = append(refreshTokenIDs, cmd.Val())
+	}
+	if len(expired) > 0 {
+		if err := u.redisClient.ZRem(ctx, key, expired...).Err(); err != nil {
+			u.log.Warnf("Failed to unindex expired sessions: %+v", err)
+		}
+	}
+
+	for i := 0; i < len(refreshTokenIDs)-limit; i++ {
+		session, err := u.deleteSession(ctx, user.ID, refreshTokenIDs[i])
+		if err != nil {
+			u.log.Warnf("Failed to sign out oldest session of user %s: %+v", user.ID, err)
+			return
+		}
+		if session == nil {
+			continue
+		}
+		u.publishSessionEvicted(ctx, user, refreshTokenIDs[i], session, limit)
+	}
+}
+
+// publishSessionEvicted announces a session signed out by the session limit
+// (audited, and the user is told by handleSessionEvicted)
+func (u *authUsecase) publishSessionEvicted(ctx context.Context, user *entity.User, tokenID string, session map[string]string, limit int) {
+	u.eventBus.Publish(ctx, &entity.DomainEvent{
+		Type:          entity.EventSessionEvicted,
+		AggregateType: "user",
+		AggregateID:   user.ID.String(),
+		ActorID:       &user.ID,
+		Payload: entity.JSON{
+			"token_id":   tokenID,
+			"device":     session["device"],
+			"ip_address": session["ip_address"],
+			"limit":      limit,
+		},
+	})
+}
+
+// rememberMeMember identifies a remember-me session by its token family, which
+// unlike the refresh token ID does not change on rotation
+func rememberMeMember(userID uuid.UUID, familyID string) string {
+	return userID.String() + ":" + familyID
+}
+
+// unixField parses a Unix timestamp stored in a Redis hash, zero when missing
+func unixField(value string) time.Time {
+	unix, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return time.Time{}
+	}
+	return time.Unix(unix, 0)
+}
+
+// =============================================================================
+// GetCurrentUser
+// =============================================================================
+
+func (u *authUsecase) GetCurrentUser(ctx context.Context, userID uuid.UUID) (*dto.UserResponse, error) {
+	user, err := u.userRepo.FindByID(u.db, userID)
+	if err != nil {
+		u.log.Warnf("Failed to find user by ID: %+v", err)
+		return nil, err
+	}
+	if user == nil {
+		return nil, ErrUserNotFound
+	}
+
+	// Only patients book, so only they have required fields
+	var required []string
+	if user.PatientProfile != nil {
+		required = u.cfg.Booking.RequiredProfileFields
+	}
+
+	response := converter.UserToResponse(user)
+	response.ProfileCompleteness = converter.ProfileCompletenessToResponse(user.ProfileCompleteness(), required)
+	return response, nil
+}
+
+// =============================================================================
+// Two-Factor Authentication (TOTP)
+// =============================================================================
+
+// LoginTwoFactor completes a two-step login with an authenticator or backup code.
+// A challenge allows maxTwoFactorAttempts codes, after that the password step is repeated.
+func (u *authUsecase) LoginTwoFactor(ctx context.Context, req *dto.LoginTwoFactorRequest) (*dto.TokenResponse, error) {
+	challengeHash := hashClaimCode(req.ChallengeToken)
+	challengeKey := twoFactorChallengePrefix + challengeHash
+	attemptsKey := twoFactorAttemptsPrefix + challengeHash
+
+	challenge, err := u.redisClient.Get(ctx, challengeKey).Result()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, ErrTwoFactorChallengeInvalid
+		}
+		u.log.Warnf("Failed to get two-factor challenge: %+v", err)
+		return nil, err
+	}
+	rawUserID, rememberMe := strings.CutSuffix(challenge, rememberMeChallengeTag)
+	userID, err := uuid.Parse(rawUserID)
+	if err != nil {
+		return nil, ErrTwoFactorChallengeInvalid
+	}
+
+	attempts, err := loginRateLimitScript.Run(ctx, u.redisClient, []string{attemptsKey}, int(twoFactorChallengeTTL.Seconds())).Int()
+	if err != nil {
+		u.log.Warnf("Failed to increment two-factor attempts: %+v", err)
+		return nil, err
+	}
+	if attempts > maxTwoFactorAttempts {
+		if err := u.redisClient.Del(ctx, challengeKey, attemptsKey).Err(); err != nil {
+			u.log.Warnf("Failed to delete two-factor challenge: %+v", err)
+		}
+		return nil, ErrTwoFactorTooManyAttempts
+	}
+
+	user, err := u.userRepo.FindByID(u.db, userID)
+	if err != nil {
+		u.log.Warnf("Failed to find user by ID: %+v", err)
+		return nil, err
+	}
+	// The account may have changed since the password step: erased or deactivated
+	// accounts get no tokens, a locked account waits for the lock to end (as completeLogin)
+	if user == nil || !user.IsTwoFactorEnabled() || user.IsErased() || (user.IsActive != nil && !*user.IsActive) {
+		return nil, ErrTwoFactorChallengeInvalid
+	}
+	if user.IsLocked(time.Now()) {
+		u.recordLogin(ctx, &user.ID, user.Email, entity.LoginMethodTwoFactor, false, entity.LoginFailureAccountLocked)
+		return nil, ErrAccountLocked
+	}
+
+	valid, err := u.verifyTwoFactorCode(ctx, user, req.Code)
+	if err != nil {
+		return nil, err
+	}
+	if !valid {
+		u.recordLogin(ctx, &user.ID, user.Email, entity.LoginMethodTwoFactor, false, entity.LoginFailureInvalidCode)
+		u.eventBus.Publish(ctx, &entity.DomainEvent{
+			Type:          entity.EventUserLoginFailed,
+			AggregateType: "user",
+			AggregateID:   user.ID.String(),
+			ActorID:       &user.ID,
+			Payload: entity.JSON{
+				"email":  user.Email,
+				"reason": "invalid two-factor code",
+			},
+		})
+		return nil, ErrInvalidTwoFactorCode
+	}
+
+	// The challenge is single use
+	if err := u.redisClient.Del(ctx, challengeKey, attemptsKey).Err(); err != nil {
+		u.log.Warnf("Failed to delete two-factor challenge: %+v", err)
+	}
+
+	tokens, err := u.issueTokens(ctx, user, rememberMe)
+	if err != nil {
+		return nil, err
+	}
+	u.recordLogin(ctx, &user.ID, user.Email, entity.LoginMethodTwoFactor, true, "")
+
+	u.eventBus.Publish(ctx, &entity.DomainEvent{
+		Type:          entity.EventUserLoggedIn,
+		AggregateType: "user",
+		AggregateID:   user.ID.String(),
+		ActorID:       &user.ID,
+		Payload: entity.JSON{
+			"email":       user.Email,
+			"two_factor":  true,
+			"remember_me": rememberMe,
+		},
+	})
+
+	return tokens, nil
+}
+
+// SetupTwoFactor generates a new secret for the user to add to an authenticator app.
+// 2FA stays off until EnableTwoFactor confirms a code of the secret.
+func (u *authUsecase) SetupTwoFactor(ctx context.Context, userID uuid.UUID) (*dto.TwoFactorSetupResponse, error) {
+	user, err := u.userRepo.FindByID(u.db, userID)
+	if err != nil {
+		u.log.Warnf("Failed to find user by ID: %+v", err)
+		return nil, err
+	}
+	if user == nil {
+		return nil, ErrUserNotFound
+	}
+	if user.IsTwoFactorEnabled() {
+		return nil, ErrTwoFactorAlreadyEnabled
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		u.log.Warnf("Failed to generate two-factor secret: %+v", err)
+		return nil, err
+	}
+
+	user.TwoFactorSecret = secret
+	if err := u.userRepo.UpdateTwoFactor(u.db.WithContext(ctx), user); err != nil {
+		u.log.Warnf("Failed to update two-factor state: %+v", err)
+		return nil, err
+	}
+
+	return &dto.TwoFactorSetupResponse{
+		Secret:          secret,
+		ProvisioningURI: totp.ProvisioningURI(u.cfg.TwoFactor.Issuer, user.Email, secret),
+	}, nil
+}
+
+// EnableTwoFactor turns 2FA on after a valid code of the pending secret and returns
+// the backup codes. Only their hashes are stored, so they are shown this one time.
+func (u *authUsecase) EnableTwoFactor(ctx context.Context, userID uuid.UUID, req *dto.EnableTwoFactorRequest) (*dto.TwoFactorBackupCodesResponse, error) {
+	tx := u.db.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	user, err := u.userRepo.FindByID(tx, userID)
+	if err != nil {
+		u.log.Warnf("Failed to find user by ID: %+v", err)
+		return nil, err
+	}
+	if user == nil {
+		return nil, ErrUserNotFound
+	}
+	if user.IsTwoFactorEnabled() {
+		return nil, ErrTwoFactorAlreadyEnabled
+	}
+	if user.TwoFactorSecret == "" {
+		return nil, ErrTwoFactorNotSetUp
+	}
+
+	if _, ok := totp.Validate(user.TwoFactorSecret, req.Code, time.Now(), twoFactorSkew); !ok {
+		return nil, ErrInvalidTwoFactorCode
+	}
+
+	codes, hashes, err := generateBackupCodes()
+	if err != nil {
+		u.log.Warnf("Failed to generate backup codes: %+v", err)
+		return nil, err
+	}
+
+	now := time.Now()
+	user.TwoFactorEnabledAt = &now
+	user.TwoFactorBackupCodes = hashes
+	if err := u.userRepo.UpdateTwoFactor(tx, user); err != nil {
+		u.log.Warnf("Failed to update two-factor state: %+v", err)
+		return nil, err
+	}
+
+	if err := u.auditService.LogUpdate(ctx, tx, &userID, entity.AuditActionTwoFactorEnable, "user", userID.String(), entity.JSON{
+		"two_factor_enabled": false,
+	}, entity.JSON{
+		"two_factor_enabled": true,
+	}); err != nil {
+		u.log.Warnf("Failed to create audit log: %+v", err)
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		u.log.Warnf("Failed commit transaction: %+v", err)
+		return nil, err
+	}
+
+	return &dto.TwoFactorBackupCodesResponse{BackupCodes: codes}, nil
+}
+
+// DisableTwoFactor turns 2FA off. Needs the password and a current or backup code,
+// so a stolen session alone cannot remove the second factor.
+func (u *authUsecase) DisableTwoFactor(ctx context.Context, userID uuid.UUID, req *dto.DisableTwoFactorRequest) error {
+	user, err := u.userRepo.FindByID(u.db, userID)
+	if err != nil {
+		u.log.Warnf("Failed to find user by ID: %+v", err)
+		return err
+	}
+	if user == nil {
+		return ErrUserNotFound
+	}
+	if !user.IsTwoFactorEnabled() {
+		return ErrTwoFactorNotEnabled
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(req.Password)); err != nil {
+		return ErrInvalidCredentials
+	}
+
+	valid, err := u.verifyTwoFactorCode(ctx, user, req.Code)
+	if err != nil {
+		return err
+	}
+	if !valid {
+		return ErrInvalidTwoFactorCode
+	}
+
+	return u.clearTwoFactor(ctx, user, userID, entity.AuditActionTwoFactorDisable)
+}
+
+// ResetTwoFactor removes the 2FA of a user who lost their authenticator and backup codes (admin)
+func (u *authUsecase) ResetTwoFactor(ctx context.Context, adminID, userID uuid.UUID) error {
+	user, err := u.userRepo.FindByID(u.db, userID)
+	if err != nil {
+		u.log.Warnf("Failed to find user by ID: %+v", err)
+		return err
+	}
+	if user == nil {
+		return ErrUserNotFound
+	}
+	if !user.IsTwoFactorEnabled() && user.TwoFactorSecret == "" {
+		return ErrTwoFactorNotEnabled
+	}
+
+	return u.clearTwoFactor(ctx, user, adminID, entity.AuditActionTwoFactorReset)
+}
+
+// UnlockUser lifts the login lockout of a user and resets the lockout count (admin)
+func (u *authUsecase) UnlockUser(ctx context.Context, adminID, userID uuid.UUID) error {
+	user, err := u.userRepo.FindByID(u.db, userID)
+	if err != nil {
+		u.log.Warnf("Failed to find user by ID: %+v", err)
+		return err
+	}
+	if user == nil {
+		return ErrUserNotFound
+	}
+	attemptsKey := fmt.Sprintf("%s%s", loginAttemptsPrefix, user.Email)
+
+	attempts, err := u.redisClient.Get(ctx, attemptsKey).Int()
+	if err != nil && !errors.Is(err, redis.Nil) {
+		u.log.Warnf("Failed to get login attempts: %+v", err)
+	}
+	if !user.IsLocked(time.Now()) && user.LockoutCount == 0 && attempts == 0 {
+		return ErrAccountNotLocked
+	}
+
+	tx := u.db.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := u.userRepo.Unlock(tx, user.ID); err != nil {
+		u.log.Warnf("Failed to unlock account: %+v", err)
+		return err
+	}
+
+	if err := u.auditService.LogUpdate(ctx, tx, &adminID, entity.AuditActionAccountUnlock, "user", user.ID.String(), entity.JSON{
+		"locked_until":  user.LockedUntil,
+		"lockout_count": user.LockoutCount,
+	}, entity.JSON{
+		"locked_until":  nil,
+		"lockout_count": 0,
+	}); err != nil {
+		u.log.Warnf("Failed to create audit log: %+v", err)
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		u.log.Warnf("Failed commit transaction: %+v", err)
+		return err
+	}
+
+	if err := u.redisClient.Del(ctx, attemptsKey).Err(); err != nil {
+		u.log.Warnf("Failed to reset login attempts: %+v", err)
+	}
+	return nil
+}
+
+// =============================================================================
+// Password
+// =============================================================================
+
+// ChangePassword replaces the password of the user, which also satisfies a required
+// change. Every session is signed out; the new tokens returned replace the current ones.
+func (u *authUsecase) ChangePassword(ctx context.Context, userID uuid.UUID, req *dto.ChangePasswordRequest) (*dto.TokenResponse, error) {
+	user, err := u.userRepo.FindByID(u.db, userID)
+	if err != nil {
+		u.log.Warnf("Failed to find user by ID: %+v", err)
+		return nil, err
+	}
+	if user == nil {
+		return nil, ErrUserNotFound
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(req.CurrentPassword)); err != nil {
+		return nil, ErrInvalidCredentials
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(req.NewPassword), bcrypt.DefaultCost)
+	if err != nil {
+		u.log.Warnf("Failed to hash password: %+v", err)
+		return nil, err
+	}
+
+	tx := u.db.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := u.userRepo.ChangePassword(tx, user.ID, string(hashedPassword), time.Now()); err != nil {
+		u.log.Warnf("Failed to change password: %+v", err)
+		return nil, err
+	}
+
+	if err := u.auditService.LogUpdate(ctx, tx, &user.ID, entity.AuditActionPasswordChange, "user", user.ID.String(), entity.JSON{
+		"must_change_password": user.MustChangePassword,
+	}, entity.JSON{
+		"must_change_password": false,
+	}); err != nil {
+		u.log.Warnf("Failed to create audit log: %+v", err)
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		u.log.Warnf("Failed commit transaction: %+v", err)
+		return nil, err
+	}
+
+	// Sessions started with the old password end, including the one of this request
+	if err := u.redisClient.Del(ctx, passwordChangeRequiredKey(user.ID)).Err(); err != nil {
+		u.log.Warnf("Failed to clear required password change: %+v", err)
+		return nil, err
+	}
+	if err := revokeUserTokens(ctx, u.redisClient, u.log, user.ID); err != nil {
+		return nil, err
+	}
+
+	user.MustChangePassword = false
+	return u.issueTokens(ctx, user, false)
+}
+
+// SetMustChangePassword requires the user to change the password before using the API
+// any further, effective immediately for signed-in sessions too. required=false lifts it.
+func (u *authUsecase) SetMustChangePassword(ctx context.Context, adminID, userID uuid.UUID, required bool) error {
+	user, err := u.userRepo.FindByID(u.db, userID)
+	if err != nil {
+		u.log.Warnf("Failed to find user by ID: %+v", err)
+		return err
+	}
+	if user == nil || user.IsErased() {
+		return ErrUserNotFound
+	}
+
+	tx := u.db.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := u.userRepo.SetMustChangePassword(tx, user.ID, required); err != nil {
+		u.log.Warnf("Failed to set required password change: %+v", err)
+		return err
+	}
+
+	if err := u.auditService.LogUpdate(ctx, tx, &adminID, entity.AuditActionPasswordRequire, "user", user.ID.String(), entity.JSON{
+		"must_change_password": user.MustChangePassword,
+	}, entity.JSON{
+		"must_change_password": required,
+	}); err != nil {
+		u.log.Warnf("Failed to create audit log: %+v", err)
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		u.log.Warnf("Failed commit transaction: %+v", err)
+		return err
+	}
+
+	key := passwordChangeRequiredKey(user.ID)
+	if required {
+		err = u.redisClient.Set(ctx, key, 1, 0).Err()
+	} else {
+		err = u.redisClient.Del(ctx, key).Err()
+	}
+	if err != nil {
+		u.log.Warnf("Failed to update required password change marker: %+v", err)
+		return err
+	}
+	return nil
+}
+
+func passwordChangeRequiredKey(userID uuid.UUID) string {
+	return middleware.PasswordChangeRequiredKeyPrefix + userID.String()
+}
+
+// Impersonate issues an access token acting as the user, for support staff to see and do
+// what the user does. The token names the admin, the auth middleware records every
+// request made with it. Accounts with administrative permissions (see
+// impersonationBlockedPermissions) and inactive accounts cannot be impersonated.
+func (u *authUsecase) Impersonate(ctx context.Context, adminID, userID uuid.UUID, req *dto.ImpersonateRequest) (*dto.ImpersonationResponse, error) {
+	if adminID == userID {
+		return nil, ErrImpersonateSelf
+	}
+
+	user, err := u.userRepo.FindByID(u.db, userID)
+	if err != nil {
+		u.log.Warnf("Failed to find user by ID: %+v", err)
+		return nil, err
+	}
+	if user == nil {
+		return nil, ErrUserNotFound
+	}
+	if user.IsErased() || user.IsPendingClaim() || (user.IsActive != nil && !*user.IsActive) {
+		return nil, ErrImpersonateNotAllowed
+	}
+
+	roleName, permissions, err := u.roleClaims(ctx, user.RoleID)
+	if err != nil {
+		return nil, err
+	}
+	for _, permission := range impersonationBlockedPermissions {
+		if slices.Contains(permissions, permission) {
+			return nil, ErrImpersonateNotAllowed
+		}
+	}
+	accessToken, tokenID, err := u.jwtService.GenerateImpersonationToken(user.ID, user.Email, user.RoleID, roleName, permissions, adminID, impersonationTTL)
+	if err != nil {
+		u.log.Warnf("Failed to generate impersonation token: %+v", err)
+		return nil, err
+	}
+	expiresAt := time.Now().Add(impersonationTTL)
+
+	// No token without its audit trail
+	if err := u.auditService.LogCreate(ctx, u.db.WithContext(ctx), &adminID, entity.AuditActionImpersonate, "user", user.ID.String(), entity.JSON{
+		"reason":     req.Reason,
+		"token_id":   tokenID,
+		"expires_at": expiresAt,
+	}); err != nil {
+		u.log.Warnf("Failed to create audit log: %+v", err)
+		return nil, err
+	}
+
+	accessKey := fmt.Sprintf("access_token:%s:%s", user.ID.String(), tokenID)
+	if err := u.redisClient.Set(ctx, accessKey, "valid", impersonationTTL).Err(); err != nil {
+		u.log.Warnf("Failed to store impersonation token: %+v", err)
+		return nil, err
+	}
+
+	u.log.Infof("Admin %s impersonating user %s until %s", adminID, user.ID, expiresAt.Format(time.RFC3339))
+	return &dto.ImpersonationResponse{
+		AccessToken: accessToken,
+		ExpiresIn:   int64(impersonationTTL.Seconds()),
+		ExpiresAt:   expiresAt,
+		User:        converter.UserToResponse(user),
+	}, nil
+}
+
+// clearTwoFactor removes the secret and backup codes of the user and records who did it
+func (u *authUsecase) clearTwoFactor(ctx context.Context, user *entity.User, actorID uuid.UUID, action string) error {
+	wasEnabled := user.IsTwoFactorEnabled()
+
+	tx := u.db.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	user.TwoFactorSecret = ""
+	user.TwoFactorEnabledAt = nil
+	user.TwoFactorBackupCodes = entity.BackupCodeHashes{}
+	if err := u.userRepo.UpdateTwoFactor(tx, user); err != nil {
+		u.log.Warnf("Failed to update two-factor state: %+v", err)
+		return err
+	}
+
+	if err := u.auditService.LogUpdate(ctx, tx, &actorID, action, "user", user.ID.String(), entity.JSON{
+		"two_factor_enabled": wasEnabled,
+	}, entity.JSON{
+		"two_factor_enabled": false,
+	}); err != nil {
+		u.log.Warnf("Failed to create audit log: %+v", err)
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		u.log.Warnf("Failed commit transaction: %+v", err)
+		return err
+	}
+
+	return nil
+}
+
+// createTwoFactorChallenge stores a random single-use token standing for a passed password step.
+// Only its hash is kept in Redis, with the user and whether the login asked to be remembered.
+func (u *authUsecase) createTwoFactorChallenge(ctx context.Context, userID uuid.UUID, rememberMe bool) (string, error) {
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		u.log.Warnf("Failed to generate two-factor challenge: %+v", err)
+		return "", err
+	}
+	token := hex.EncodeToString(raw)
+
+	value := userID.String()
+	if rememberMe {
+		value += rememberMeChallengeTag
+	}
+
+	key := twoFactorChallengePrefix + hashClaimCode(token)
+	if err := u.redisClient.Set(ctx, key, value, twoFactorChallengeTTL).Err(); err != nil {
+		u.log.Warnf("Failed to store two-factor challenge: %+v", err)
+		return "", err
+	}
+	return token, nil
+}
+
+// verifyTwoFactorCode accepts a current authenticator code, used at most once, or an unused backup code
+func (u *authUsecase) verifyTwoFactorCode(ctx context.Context, user *entity.User, code string) (bool, error) {
+	if step, ok := totp.Validate(user.TwoFactorSecret, code, time.Now(), twoFactorSkew); ok {
+		// A code stays valid for a few steps; remember its step so it cannot be replayed
+		usedKey := fmt.Sprintf("%s%s:%d", twoFactorUsedPrefix, user.ID.String(), step)
+		fresh, err := u.redisClient.SetNX(ctx, usedKey, "1", (2*twoFactorSkew+1)*totp.Period).Result()
+		if err != nil {
+			u.log.Warnf("Failed to mark two-factor code as used: %+v", err)
+			return false, err
+		}
+		return fresh, nil
+	}
+
+	consumed, err := u.userRepo.ConsumeBackupCode(u.db.WithContext(ctx), user.ID, hashBackupCode(code))
+	if err != nil {
+		u.log.Warnf("Failed to consume backup code: %+v", err)
+		return false, err
+	}
+	return consumed > 0, nil
+}
+
+// generateBackupCodes returns backupCodeCount codes formatted xxxxx-xxxxx and their hashes
+func generateBackupCodes() ([]string, entity.BackupCodeHashes, error) {
+	codes := make([]string, 0, backupCodeCount)
+	hashes := make(entity.BackupCodeHashes, 0, backupCodeCount)
+	for i := 0; i < backupCodeCount; i++ {
+		raw := make([]byte, 5)
+		if _, err := rand.Read(raw); err != nil {
+			return nil, nil, err
+		}
+		encoded := hex.EncodeToString(raw)
+		code := encoded[:5] + "-" + encoded[5:]
+		codes = append(codes, code)
+		hashes = append(hashes, hashBackupCode(code))
+	}
+	return codes, hashes, nil
+}
+
+// hashBackupCode hashes a backup code for storage, ignoring case, spaces and dashes
+func hashBackupCode(code string) string {
+	normalized := strings.ToLower(strings.NewReplacer("-", "", " ", "").Replace(code))
+	return hashClaimCode(normalized)
+}
+
+// =============================================================================
+// Google Sign-In (patients)
+// =============================================================================
+
+// GoogleAuthURL starts a Google login and returns the consent page to redirect to
+func (u *authUsecase) GoogleAuthURL(ctx context.Context) (string, error) {
+	if !u.google.Enabled() {
+		return "", ErrOAuthNotConfigured
+	}
+
+	state, err := oauth.RandomToken()
+	if err != nil {
+		u.log.Warnf("Failed to generate oauth state: %+v", err)
+		return "", err
+	}
+	verifier, err := oauth.RandomToken()
+	if err != nil {
+		u.log.Warnf("Failed to generate oauth verifier: %+v", err)
+		return "", err
+	}
+
+	if err := u.redisClient.Set(ctx, oauthStatePrefix+state, verifier, oauthStateTTL).Err(); err != nil {
+		u.log.Warnf("Failed to store oauth state: %+v", err)
+		return "", err
+	}
+
+	return u.google.AuthCodeURL(state, verifier), nil
+}
+
+// GoogleCallback finishes a Google login. The identity maps to the patient linked to the
+// Google account, else to the patient with the same (verified) email, which gets linked.
+// An identity without an account gets a registration token for GoogleRegister.
+func (u *authUsecase) GoogleCallback(ctx context.Context, code, state string) (*dto.OAuthLoginResponse, error) {
+	if !u.google.Enabled() {
+		return nil, ErrOAuthNotConfigured
+	}
+
+	// The state is single use
+	verifier, err := u.redisClient.GetDel(ctx, oauthStatePrefix+state).Result()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, ErrOAuthStateInvalid
+		}
+		u.log.Warnf("Failed to get oauth state: %+v", err)
+		return nil, err
+	}
+
+	identity, err := u.google.Exchange(ctx, code, verifier)
+	if err != nil {
+		u.log.Warnf("Failed to exchange google authorization code: %+v", err)
+		return nil, ErrOAuthFailed
+	}
+
+	auditValue := entity.JSON{
+		"email":    identity.Email,
+		"provider": "google",
+	}
+
+	// ---- Linked account ----
+	user, err := u.userRepo.FindByGoogleSubject(u.db.WithContext(ctx), identity.Subject)
+	if err != nil {
+		u.log.Warnf("Failed to find user by google subject: %+v", err)
+		return nil, err
+	}
+	if user != nil {
+		if user.RoleID != entity.RoleIDPatient {
+			return nil, ErrOAuthNotPatient
+		}
+		login, err := u.completeLogin(ctx, user, entity.LoginMethodGoogle, false, auditValue)
+		if err != nil {
+			return nil, err
+		}
+		return &dto.OAuthLoginResponse{LoginResponse: login}, nil
+	}
+
+	// An unverified email could belong to someone else, never match or register it
+	if !identity.EmailVerified || identity.Email == "" {
+		return nil, ErrOAuthEmailNotVerified
+	}
+
+	// ---- Existing account with the same email: link it ----
+	user, err = u.userRepo.FindByEmail(u.db.WithContext(ctx), identity.Email)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		u.log.Warnf("Failed to find user by email: %+v", err)
+		return nil, err
+	}
+	if err == nil {
+		if user.RoleID != entity.RoleIDPatient {
+			return nil, ErrOAuthNotPatient
+		}
+
+		linked, err := u.userRepo.LinkGoogleSubject(u.db.WithContext(ctx), user.ID, identity.Subject)
+		if err != nil {
+			u.log.Warnf("Failed to link google account: %+v", err)
+			if isDuplicateKeyError(err, "google_subject") {
+				return nil, ErrOAuthAccountLinked
+			}
+			return nil, err
+		}
+		if linked == 0 {
+			return nil, ErrOAuthAccountLinked
+		}
+
+		if err := u.auditService.LogUpdate(ctx, u.db, &user.ID, entity.AuditActionUserGoogleLink, "user", user.ID.String(), nil, entity.JSON{
+			"email": identity.Email,
+		}); err != nil {
+			u.log.Warnf("Failed to create audit log: %+v", err)
+		}
+
+		login, err := u.completeLogin(ctx, user, entity.LoginMethodGoogle, false, auditValue)
+		if err != nil {
+			return nil, err
+		}
+		return &dto.OAuthLoginResponse{LoginResponse: login}, nil
+	}
+
+	// ---- New patient: the profile is completed with the registration token ----
+	token, err := u.createOAuthRegistration(ctx, identity)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.OAuthLoginResponse{
+		RegistrationRequired: true,
+		RegistrationToken:    token,
+		Email:                identity.Email,
+		FullName:             identity.Name,
+	}, nil
+}
+
+// GoogleRegister creates the patient account of a Google identity and logs it in.
+// The account has no usable password, the patient logs in with Google.
+func (u *authUsecase) GoogleRegister(ctx context.Context, req *dto.GoogleRegisterRequest) (*dto.LoginResponse, error) {
+	registrationKey := oauthRegistrationPrefix + hashClaimCode(req.RegistrationToken)
+
+	raw, err := u.redisClient.Get(ctx, registrationKey).Result()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, ErrOAuthRegistrationInvalid
+		}
+		u.log.Warnf("Failed to get oauth registration: %+v", err)
+		return nil, err
+	}
+	var identity oauth.Identity
+	if err := json.Unmarshal([]byte(raw), &identity); err != nil {
+		return nil, ErrOAuthRegistrationInvalid
+	}
+
+	dob, err := time.Parse("2006-01-02", req.DateOfBirth)
+	if err != nil {
+		return nil, ErrInvalidDateFormat
+	}
+
+	fullName := req.FullName
+	if fullName == "" {
+		fullName = identity.Name
+	}
+	if fullName == "" {
+		fullName = identity.Email
+	}
+
+	password, err := oauth.RandomToken()
+	if err != nil {
+		u.log.Warnf("Failed to generate password: %+v", err)
+		return nil, err
+	}
+
+	user := &entity.User{
+		Email:         identity.Email,
+		Password:      password, // random, Register hashes it
+		FullName:      fullName,
+		RoleID:        entity.RoleIDPatient,
+		GoogleSubject: &identity.Subject,
+		PatientProfile: &entity.PatientProfile{
+			NIK:         req.NIK,
+			PhoneNumber: req.PhoneNumber,
+			DateOfBirth: dob,
+			Gender:      req.Gender,
+			Address:     req.Address,
+		},
+	}
+	if _, err := u.Register(ctx, user); err != nil {
+		if isDuplicateKeyError(err, "google_subject") {
+			return nil, ErrOAuthAccountLinked
+		}
+		return nil, err
+	}
+
+	// The registration token is single use, kept until now so a taken NIK can be corrected
+	if err := u.redisClient.Del(ctx, registrationKey).Err(); err != nil {
+		u.log.Warnf("Failed to delete oauth registration: %+v", err)
+	}
+
+	return u.completeLogin(ctx, user, entity.LoginMethodGoogle, false, entity.JSON{
+		"email":    user.Email,
+		"provider": "google",
+	})
+}
+
+// createOAuthRegistration stores the identity under a random token, only its hash is kept in Redis
+func (u *authUsecase) createOAuthRegistration(ctx context.Context, identity *oauth.Identity) (string, error) {
+	token, err := oauth.RandomToken()
+	if err != nil {
+		u.log.Warnf("Failed to generate registration token: %+v", err)
+		return "", err
+	}
+
+	value, err := json.Marshal(identity)
+	if err != nil {
+		return "", err
+	}
+
+	key := oauthRegistrationPrefix + hashClaimCode(token)
+	if err := u.redisClient.Set(ctx, key, value, oauthRegistrationTTL).Err(); err != nil {
+		u.log.Warnf("Failed to store oauth registration: %+v", err)
+		return "", err
+	}
+	return token, nil
+}
+
+// =============================================================================
+// Phone Login — one-time code by SMS / WhatsApp
+// =============================================================================
+
+// RequestLoginOTP sends a login code to the phone of a patient account.
+//
+// To prevent enumeration the result is the same whether or not the number belongs to
+// an account; only codes actually sent are logged.
+func (u *authUsecase) RequestLoginOTP(ctx context.Context, req *dto.RequestLoginOTPRequest) error {
+	user, err := u.findLoginOTPUser(ctx, req.PhoneNumber)
+	if err != nil || user == nil {
+		return err
+	}
+
+	// One code per cooldown period, so the endpoint cannot be used to flood a phone
+	ok, err := u.redisClient.SetNX(ctx, loginOTPCooldownPrefix+user.ID.String(), "1", loginOTPResendCooldown).Result()
+	if err != nil {
+		u.log.Warnf("Failed to set login code cooldown: %+v", err)
+		return err
+	}
+	if !ok {
+		return nil
+	}
+
+	code, err := generateClaimCode()
+	if err != nil {
+		return err
+	}
+
+	// A new code replaces the previous one
+	if err := u.redisClient.Set(ctx, loginOTPCodePrefix+user.ID.String(), hashClaimCode(code), loginOTPTTL).Err(); err != nil {
+		u.log.Warnf("Failed to store login code: %+v", err)
+		return err
+	}
+
+	if err := u.notificationService.Notify(ctx, u.db.WithContext(ctx), service.NotificationRequest{
+		PatientID: user.ID,
+		EventType: loginOTPEventType,
+		Message:   fmt.Sprintf("Your login code is %s. It expires in %d minutes, do not share it.", code, int(loginOTPTTL.Minutes())),
+		DedupeKey: fmt.Sprintf("login_otp:%s:%s", user.ID, uuid.NewString()),
+		Channel:   req.Channel,
+	}); err != nil {
+		u.log.Warnf("Failed to send login code to patient %s: %+v", user.ID, err)
+		return err
+	}
+
+	u.log.Infof("Login code sent to patient %s", user.ID)
+	return nil
+}
+
+// VerifyLoginOTP checks the login code and returns the token pair, or a challenge
+// when the account has two-factor authentication on. A code can be used once.
+func (u *authUsecase) VerifyLoginOTP(ctx context.Context, req *dto.VerifyLoginOTPRequest) (*dto.LoginResponse, error) {
+	user, err := u.findLoginOTPUser(ctx, req.PhoneNumber)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, ErrInvalidLoginOTP
+	}
+	codeKey := loginOTPCodePrefix + user.ID.String()
+	attemptsKey := loginOTPAttemptsPrefix + user.ID.String()
+
+	attempts, err := loginRateLimitScript.Run(ctx, u.redisClient, []string{attemptsKey}, int(loginOTPAttemptsWindow.Seconds())).Int()
+	if err != nil {
+		u.log.Warnf("Failed to increment login code attempts: %+v", err)
+		return nil, err
+	}
+	if attempts > maxLoginOTPAttempts {
+		return nil, ErrLoginOTPTooManyAttempts
+	}
+
+	stored, err := u.redisClient.Get(ctx, codeKey).Result()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, ErrInvalidLoginOTP
+		}
+		u.log.Warnf("Failed to get login code: %+v", err)
+		return nil, err
+	}
+	if subtle.ConstantTimeCompare([]byte(stored), []byte(hashClaimCode(req.Code))) != 1 {
+		u.recordLogin(ctx, &user.ID, user.Email, entity.LoginMethodPhoneOTP, false, entity.LoginFailureInvalidCode)
+		return nil, ErrInvalidLoginOTP
+	}
+
+	// Redeem the code: only the request that deletes it may log in
+	deleted, err := u.redisClient.Del(ctx, codeKey).Result()
+	if err != nil {
+		u.log.Warnf("Failed to delete login code: %+v", err)
+		return nil, err
+	}
+	if deleted == 0 {
+		return nil, ErrInvalidLoginOTP
+	}
+	if err := u.redisClient.Del(ctx, attemptsKey).Err(); err != nil {
+		u.log.Warnf("Failed to reset login code attempts: %+v", err)
+	}
+
+	return u.completeLogin(ctx, user, entity.LoginMethodPhoneOTP, req.RememberMe, entity.JSON{
+		"email":       user.Email,
+		"method":      entity.LoginMethodPhoneOTP,
+		"remember_me": req.RememberMe,
+	})
+}
+
+// findLoginOTPUser returns the patient who can log in with the phone number, or nil.
+// A number shared by several accounts (e.g. family members) cannot be used to log in.
+func (u *authUsecase) findLoginOTPUser(ctx context.Context, phoneNumber string) (*entity.User, error) {
+	profiles, err := u.patientProfileRepo.FindByPhoneNumbers(ctx, u.db, phoneNumberVariants(phoneNumber))
+	if err != nil {
+		u.log.Warnf("Failed to find patient by phone number: %+v", err)
+		return nil, err
+	}
+	if len(profiles) != 1 || profiles[0].User.ID == uuid.Nil {
+		return nil, nil
+	}
+
+	user := &profiles[0].User
+	if user.IsPendingClaim() || user.IsErased() || (user.IsActive != nil && !*user.IsActive) {
+		return nil, nil
+	}
+	return user, nil
+}
+
+// phoneNumberVariants returns the forms a phone number may be stored in, local
+// (08…) and international (+628…, 628…). Spaces, dashes, dots and brackets are ignored.
+func phoneNumberVariants(phoneNumber string) []string {
+	cleaned := strings.Map(func(r rune) rune {
+		switch r {
+		case ' ', '-', '.', '(', ')':
+			return -1
+		}
+		return r
+	}, strings.TrimSpace(phoneNumber))
+
+	national := cleaned
+	switch {
+	case strings.HasPrefix(national, "+"+defaultPhoneCountryCode):
+		national = strings.TrimPrefix(national, "+"+defaultPhoneCountryCode)
+	case strings.HasPrefix(national, defaultPhoneCountryCode):
+		national = strings.TrimPrefix(national, defaultPhoneCountryCode)
+	case strings.HasPrefix(national, "0"):
+		national = strings.TrimPrefix(national, "0")
+	default:
+		return []string{cleaned}
+	}
+
+	return []string{
+		"0" + national,
+		"+" + defaultPhoneCountryCode + national,
+		defaultPhoneCountryCode + national,
+	}
+}
+
+// =============================================================================
+// Helper: Token Validation
+// =============================================================================
+
+// IsTokenValid checks if a token is valid in Redis
+func (u *authUsecase) IsTokenValid(ctx context.Context, userID uuid.UUID, tokenID string, tokenType jwt.TokenType) (bool, error) {
+	var key string
+	if tokenType == jwt.AccessToken {
+		key = fmt.Sprintf("access_token:%s:%s", userID.String(), tokenID)
+	} else {
+		key = fmt.Sprintf("refresh_token:%s:%s", userID.String(), tokenID)
+	}
+
+	exists, err := u.redisClient.Exists(ctx, key).Result()
+	if err != nil {
+		u.log.Warnf("Failed to check token validity: %+v", err)
+		return false, err
+	}
+
+	return exists > 0, nil
+}
+
+// RevokeAllUserTokens revokes all tokens for a user (useful when password changed or account compromised)
+func (u *authUsecase) RevokeAllUserTokens(ctx context.Context, userID uuid.UUID) error {
+	return revokeUserTokens(ctx, u.redisClient, u.log, userID)
+}
+
+// revokeUserTokens deletes all access and refresh tokens of a user from Redis,
+// so every session of the user ends with its next request
+func revokeUserTokens(ctx context.Context, redisClient *redis.Client, log *logrus.Logger, userID uuid.UUID) error {
+	patterns := []string{
+		fmt.Sprintf("access_token:%s:*", userID.String()),
+		fmt.Sprintf("refresh_token:%s:*", userID.String()),
+		fmt.Sprintf("%s%s:*", sessionPrefix, userID.String()),
+		fmt.Sprintf("%s%s:*", tokenFamilyPrefix, userID.String()),
+	}
+	for _, pattern := range patterns {
+		if err := deleteKeysByPattern(ctx, redisClient, pattern); err != nil {
+			log.Warnf("Failed to delete keys %s: %+v", pattern, err)
+			return err
+		}
+	}
+	if err := redisClient.Del(ctx, userSessionsKey(userID)).Err(); err != nil {
+		log.Warnf("Failed to delete session index: %+v", err)
+		return err
+	}
+
+	return nil
+}
+
+// deleteKeysByPattern deletes the keys matching pattern. SCAN walks the keyspace in
+// batches, unlike KEYS it never blocks Redis; UNLINK frees the values in the background.
+func deleteKeysByPattern(ctx context.Context, redisClient *redis.Client, pattern string) error {
+	batch := make([]string, 0, revokeScanBatchSize)
+	iter := redisClient.Scan(ctx, 0, pattern, revokeScanBatchSize).Iterator()
+	for iter.Next(ctx) {
+		batch = append(batch, iter.Val())
+		if len(batch) == revokeScanBatchSize {
+			if err := redisClient.Unlink(ctx, batch...).Err(); err != nil {
+				return err
+			}
+			batch = batch[:0]
+		}
+	}
+	if err := iter.Err(); err != nil {
+		return err
+	}
+
+	if len(batch) > 0 {
+		return redisClient.Unlink(ctx, batch...).Err()
+	}
+	return nil
+}
+
+// =============================================================================
+// Helper: PostgreSQL Error Checks
+// =============================================================================
+
+// isDuplicateKeyError checks if the error is a PostgreSQL unique constraint violation
+// containing the specified constraint name
+func isDuplicateKeyError(err error, constraintName string) bool {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		// PostgreSQL error code 23505 = unique_violation
+		if pgErr.Code == "23505" && strings.Contains(strings.ToLower(pgErr.ConstraintName), strings.ToLower(constraintName)) {
+			return true
+		}
+	}
+	return false
+}
+
+// isForeignKeyError checks if the error is a PostgreSQL foreign key violation
+// containing the specified constraint name
+func isForeignKeyError(err error, constraintName string) bool {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		// PostgreSQL error code 23503 = foreign_key_violation
+		if pgErr.Code == "23503" && strings.Contains(strings.ToLower(pgErr.ConstraintName), strings.ToLower(constraintName)) {
+			return true
+		}
+	}
+	return false
+}
+
+// =============================================================================
+// Security alerts
+// =============================================================================
+
+// registerEventHandlers subscribes the security alerts to the outbox worker and the
+// signed-out session notice to the event bus.
+// Handlers may run more than once per event (at-least-once delivery).
+func (u *authUsecase) registerEventHandlers() {
+	u.outboxService.RegisterHandler(entity.OutboxEventSecurityAlert, u.handleSecurityAlert)
+	u.eventBus.Subscribe(entity.EventSessionEvicted, u.handleSessionEvicted)
+}
+
+// handleSessionEvicted tells the user a session was signed out because they signed in on
+// more devices than the session limit allows
+func (u *authUsecase) handleSessionEvicted(ctx context.Context, event *entity.DomainEvent) error {
+	var payload entity.SessionEvictedPayload
+	if err := event.DecodePayload(&payload); err != nil {
+		return err
+	}
+	device := payload.Device
+	if device == "" {
+		device = "Unknown device"
+	}
+
+	return u.notificationService.Notify(ctx, u.db.WithContext(ctx), service.NotificationRequest{
+		PatientID: *event.ActorID,
+		EventType: sessionEvictedEventType,
+		Subject:   "A session of your account was signed out",
+		Message: fmt.Sprintf("You signed in on a new device, so your session on %s was signed out (at most %d devices can be signed in at a time). If this was not you, change your password.",
+			device, payload.Limit),
+		DedupeKey: fmt.Sprintf("session_evicted:%s:%s", event.AggregateID, payload.TokenID),
+		Channel:   entity.NotificationChannelEmail,
+	})
+}
+
+// handleSecurityAlert emails the user about a security-sensitive action on their account
+// (see entity.IsSecurityAlertAction), so they can react when it was not them
+func (u *authUsecase) handleSecurityAlert(ctx context.Context, event *entity.OutboxEvent) error {
+	var payload entity.SecurityAlertPayload
+	if err := event.DecodePayload(&payload); err != nil {
+		return err
+	}
+
+	message := u.securityAlertMessage(&payload)
+	if message == "" {
+		return nil
+	}
+
+	return u.notificationService.Notify(ctx, u.db.WithContext(ctx), service.NotificationRequest{
+		PatientID: payload.UserID,
+		EventType: event.EventType,
+		Subject:   "Security alert for your account",
+		Message:   message,
+		DedupeKey: outboxDedupeKey(event),
+		Channel:   entity.NotificationChannelEmail,
+	})
+}
+
+// securityAlertMessage describes the action for the user, empty for unknown actions
+func (u *authUsecase) securityAlertMessage(payload *entity.SecurityAlertPayload) string {
+	at := u.formatService.DateTime(payload.OccurredAt)
+	notYou := "If this was not you, change your password and sign out your other sessions."
+
+	switch payload.Action {
+	case entity.AuditActionNewDeviceLogin:
+		device, _ := payload.Details["device"].(string)
+		ip, _ := payload.Details["ip_address"].(string)
+		return fmt.Sprintf("New sign-in to your account on %s from %s (IP %s). %s", at, device, ip, notYou)
+	case entity.AuditActionPasswordChange:
+		return fmt.Sprintf("Your password was changed on %s. If this was not you, contact us immediately.", at)
+	case entity.AuditActionTwoFactorDisable:
+		return fmt.Sprintf("Two-factor authentication was turned off for your account on %s. %s", at, notYou)
+	case entity.AuditActionTwoFactorReset:
+		return fmt.Sprintf("Two-factor authentication of your account was reset by an administrator on %s. Turn it on again to keep your account protected.", at)
+	case entity.AuditActionAccountLock:
+		message := fmt.Sprintf("Your account was locked on %s after too many wrong passwords", at)
+		if raw, ok := payload.Details["locked_until"].(string); ok {
+			if lockedUntil, err := time.Parse(time.RFC3339, raw); err == nil {
+				message += ", until " + u.formatService.DateTime(lockedUntil)
+			}
+		}
+		return message + ". " + notYou
+	case entity.AuditActionAccountExport:
+		return fmt.Sprintf("A copy of your personal data was requested on %s. %s", at, notYou)
+	default:
+		return ""
 	}
-	return false
 }
@@ -0,0 +1,484 @@
+package usecase
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"go-template-clean-architecture/config"
+	"go-template-clean-architecture/internal/delivery/dto"
+	"go-template-clean-architecture/internal/delivery/http/middleware"
+	"go-template-clean-architecture/internal/domain/entity"
+	"go-template-clean-architecture/internal/domain/repository"
+	"go-template-clean-architecture/internal/service"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrImportInvalidFile = errors.New("import file is not a valid CSV")
+	ErrImportTooManyRows = errors.New("import file has too many rows")
+	ErrImportInvalidRows = errors.New("import file has invalid rows")
+)
+
+// maxBookingImportRows bounds a single import file (one DB transaction)
+const maxBookingImportRows = 5000
+
+// Booking import CSV columns (header row required, any order)
+const (
+	importColPatientEmail = "patient_email"
+	importColScheduleID   = "schedule_id"
+	importColBookingCode  = "booking_code"
+	importColQueueNumber  = "queue_number"
+	importColStatus       = "status"
+	importColCreatedAt    = "created_at"
+)
+
+type BookingImportUsecase interface {
+	ImportBookings(ctx context.Context, file io.Reader, dryRun bool) (*dto.BookingImportResponse, error)
+}
+
+type bookingImportUsecase struct {
+	db                 *gorm.DB
+	log                *logrus.Logger
+	cfg                *config.Config
+	userRepo           repository.UserRepository
+	patientProfileRepo repository.PatientProfileRepository
+	scheduleRepo       repository.DoctorScheduleRepository
+	bookingRepo        repository.BookingRepository
+	redisSyncService   *service.RedisSyncService
+	auditService       service.AuditService
+}
+
+func NewBookingImportUsecase(
+	db *gorm.DB,
+	log *logrus.Logger,
+	cfg *config.Config,
+	userRepo repository.UserRepository,
+	patientProfileRepo repository.PatientProfileRepository,
+	scheduleRepo repository.DoctorScheduleRepository,
+	bookingRepo repository.BookingRepository,
+	redisSyncService *service.RedisSyncService,
+	auditService service.AuditService,
+) BookingImportUsecase {
+	return &bookingImportUsecase{
+		db:                 db,
+		log:                log,
+		cfg:                cfg,
+		userRepo:           userRepo,
+		patientProfileRepo: patientProfileRepo,
+		scheduleRepo:       scheduleRepo,
+		bookingRepo:        bookingRepo,
+		redisSyncService:   redisSyncService,
+		auditService:       auditService,
+	}
+}
+
+// importRow is one parsed CSV row
+type importRow struct {
+	line     int
+	booking  entity.Booking
+	hasQueue bool
+}
+
+// importValidator collects per-row errors and caches reference lookups
+type importValidator struct {
+	errors    []dto.BookingImportRowError
+	patients  map[string]*uuid.UUID // email -> patient user ID (nil = invalid)
+	schedules map[int]*entity.DoctorSchedule
+}
+
+func (v *importValidator) add(line int, field string, format string, args ...interface{}) {
+	v.errors = append(v.errors, dto.BookingImportRowError{Row: line, Field: field, Message: fmt.Sprintf(format, args...)})
+}
+
+// ImportBookings imports historical bookings from CSV (clinic migration).
+//
+// Columns: patient_email, schedule_id (required), booking_code, queue_number, status, created_at (optional).
+//
+// Flow:
+// 1. Parse and validate every row (patient and schedule references, duplicates, status, dates)
+// 2. Per schedule: check explicit queue numbers are free, enforce quota, number the rest after the highest used one
+// 3. Any invalid row → nothing is imported (ErrImportInvalidRows with row errors)
+// 4. Insert all bookings in ONE DB transaction (skipped on dry run)
+// 5. After commit → re-sync Redis quota/queue for every affected schedule
+//
+// Imported bookings do not emit outbox events - no notifications for historical data.
+func (u *bookingImportUsecase) ImportBookings(ctx context.Context, file io.Reader, dryRun bool) (*dto.BookingImportResponse, error) {
+	reader := csv.NewReader(file)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, ErrImportInvalidFile
+	}
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, ErrImportInvalidFile
+	}
+	if len(records) > maxBookingImportRows {
+		return nil, ErrImportTooManyRows
+	}
+
+	result := &dto.BookingImportResponse{
+		DryRun:      dryRun,
+		TotalRows:   len(records),
+		ScheduleIDs: []int{},
+	}
+
+	v := &importValidator{
+		patients:  make(map[string]*uuid.UUID),
+		schedules: make(map[int]*entity.DoctorSchedule),
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))] = i
+	}
+	for _, required := range []string{importColPatientEmail, importColScheduleID} {
+		if _, ok := columns[required]; !ok {
+			v.add(1, required, "missing required column")
+		}
+	}
+	if len(v.errors) > 0 {
+		result.Errors = v.errors
+		return result, ErrImportInvalidRows
+	}
+
+	// Step 1: Parse and validate rows
+	rows, err := u.parseRows(ctx, records, columns, v)
+	if err != nil {
+		return nil, err
+	}
+
+	// Step 2: Queue numbers and quota per schedule
+	bySchedule := make(map[int][]*importRow)
+	for _, row := range rows {
+		bySchedule[row.booking.ScheduleID] = append(bySchedule[row.booking.ScheduleID], row)
+	}
+	for scheduleID, scheduleRows := range bySchedule {
+		if err := u.assignQueueNumbers(ctx, v.schedules[scheduleID], scheduleRows, v); err != nil {
+			return nil, err
+		}
+		result.ScheduleIDs = append(result.ScheduleIDs, scheduleID)
+	}
+	sort.Ints(result.ScheduleIDs)
+
+	// Step 3: All or nothing
+	if len(v.errors) > 0 {
+		sort.SliceStable(v.errors, func(i, j int) bool { return v.errors[i].Row < v.errors[j].Row })
+		result.Errors = v.errors
+		return result, ErrImportInvalidRows
+	}
+
+	bookings := make([]entity.Booking, 0, len(rows))
+	for _, row := range rows {
+		if row.booking.BookingCode == "" {
+			row.booking.BookingCode = generateBookingCode(v.schedules[row.booking.ScheduleID].ScheduleDate)
+		}
+		bookings = append(bookings, row.booking)
+	}
+
+	if dryRun {
+		return result, nil
+	}
+
+	// Step 4: Insert in one transaction
+	tx := u.db.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := u.bookingRepo.CreateBatch(tx, bookings); err != nil {
+		u.log.Warnf("Failed to import bookings: %+v", err)
+		return nil, err
+	}
+
+	// Audit log - single consolidated entry
+	adminID, _ := middleware.GetUserIDFromContext(ctx)
+	if err := u.auditService.LogCreate(ctx, tx, &adminID, entity.AuditActionBookingImport, "booking", "import",
+		entity.JSON{"imported": len(bookings), "schedule_ids": result.ScheduleIDs},
+	); err != nil {
+		u.log.Warnf("Failed to create audit log: %+v", err)
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		u.log.Warnf("Failed commit transaction: %+v", err)
+		return nil, err
+	}
+	result.Imported = len(bookings)
+
+	// Step 5: Re-sync Redis for affected schedules (fail-safe: logged only, past dates are skipped)
+	syncCtx, syncCancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer syncCancel()
+	for _, scheduleID := range result.ScheduleIDs {
+		schedule := v.schedules[scheduleID]
+		if err := u.redisSyncService.SyncScheduleQuota(syncCtx, schedule.ID, schedule.TotalQuota, schedule.ScheduleDate); err != nil {
+			u.log.Warnf("Failed to re-sync Redis for schedule %d after import (non-fatal): %+v", scheduleID, err)
+		}
+	}
+
+	u.log.Infof("Bookings imported: rows=%d, schedules=%d", result.Imported, len(result.ScheduleIDs))
+	return result, nil
+}
+
+// parseRows converts CSV records to bookings, recording row errors in v.
+// Returns an error only for database failures.
+func (u *bookingImportUsecase) parseRows(ctx context.Context, records [][]string, columns map[string]int, v *importValidator) ([]*importRow, error) {
+	db := u.db.WithContext(ctx)
+	rows := make([]*importRow, 0, len(records))
+	codeLines := make(map[string]int)
+	activeKeys := make(map[string]int)
+
+	field := func(record []string, name string) string {
+		i, ok := columns[name]
+		if !ok || i >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[i])
+	}
+
+	for i, record := range records {
+		line := i + 2
+		row := &importRow{line: line}
+		valid := true
+
+		// Patient reference
+		email := field(record, importColPatientEmail)
+		patientID, err := u.lookupPatient(ctx, email, v)
+		if err != nil {
+			return nil, err
+		}
+		if email == "" {
+			v.add(line, importColPatientEmail, "is required")
+			valid = false
+		} else if patientID == nil {
+			v.add(line, importColPatientEmail, "no patient registered with email %s", email)
+			valid = false
+		} else {
+			row.booking.PatientID = *patientID
+		}
+
+		// Schedule reference
+		scheduleID, err := strconv.Atoi(field(record, importColScheduleID))
+		if err != nil || scheduleID < 1 {
+			v.add(line, importColScheduleID, "must be a positive integer")
+			valid = false
+		} else {
+			schedule, err := u.lookupSchedule(db, scheduleID, v)
+			if err != nil {
+				return nil, err
+			}
+			if schedule == nil {
+				v.add(line, importColScheduleID, "schedule %d not found", scheduleID)
+				valid = false
+			} else if !schedule.IsApproved() {
+				v.add(line, importColScheduleID, "schedule %d is not approved", scheduleID)
+				valid = false
+			} else if schedule.IsSlotMode() {
+				v.add(line, importColScheduleID, "schedule %d uses time-slot booking and cannot be imported into", scheduleID)
+				valid = false
+			}
+			row.booking.ScheduleID = scheduleID
+		}
+
+		// Status (default pending)
+		row.booking.Status = entity.BookingStatusPending
+		if status := strings.ToLower(field(record, importColStatus)); status != "" {
+			switch entity.BookingStatus(status) {
+			case entity.BookingStatusPending, entity.BookingStatusConfirmed, entity.BookingStatusCancelled:
+				row.booking.Status = entity.BookingStatus(status)
+			default:
+				v.add(line, importColStatus, "must be one of pending, confirmed, cancelled")
+				valid = false
+			}
+		}
+
+		// Booking code (generated when empty)
+		if code := field(record, importColBookingCode); code != "" {
+			code = normalizeBookingCode(code)
+			if first, ok := codeLines[code]; ok {
+				v.add(line, importColBookingCode, "duplicate of row %d", first)
+				valid = false
+			} else {
+				codeLines[code] = line
+			}
+			row.booking.BookingCode = code
+		}
+
+		// Queue number (assigned when empty)
+		if raw := field(record, importColQueueNumber); raw != "" {
+			queueNumber, err := strconv.Atoi(raw)
+			if err != nil || queueNumber < 1 {
+				v.add(line, importColQueueNumber, "must be a positive integer")
+				valid = false
+			}
+			row.booking.QueueNumber = queueNumber
+			row.hasQueue = true
+		}
+
+		// Created at (RFC3339, or "YYYY-MM-DD HH:MM:SS" in the app timezone)
+		if raw := field(record, importColCreatedAt); raw != "" {
+			createdAt, err := time.Parse(time.RFC3339, raw)
+			if err != nil {
+				createdAt, err = time.ParseInLocation("2006-01-02 15:04:05", raw, u.cfg.App.Location)
+			}
+			if err != nil {
+				v.add(line, importColCreatedAt, "must be RFC3339 or YYYY-MM-DD HH:MM:SS")
+				valid = false
+			}
+			row.booking.CreatedAt = createdAt
+		}
+
+		// One active booking per patient and schedule (file and database)
+		if valid && !row.booking.IsCancelled() {
+			key := fmt.Sprintf("%s:%d", row.booking.PatientID, row.booking.ScheduleID)
+			if first, ok := activeKeys[key]; ok {
+				v.add(line, importColPatientEmail, "patient already has an active booking for this schedule in row %d", first)
+				valid = false
+			} else {
+				activeKeys[key] = line
+				existing, err := u.bookingRepo.FindByPatientAndSchedule(db, row.booking.PatientID, row.booking.ScheduleID)
+				if err != nil {
+					return nil, err
+				}
+				if existing != nil {
+					v.add(line, importColPatientEmail, "patient already has an active booking for this schedule (%s)", existing.BookingCode)
+					valid = false
+				}
+			}
+		}
+
+		if valid {
+			rows = append(rows, row)
+		}
+	}
+
+	// Booking codes already in the database
+	codes := make([]string, 0, len(codeLines))
+	for code := range codeLines {
+		codes = append(codes, code)
+	}
+	existing, err := u.bookingRepo.FindExistingBookingCodes(db, codes)
+	if err != nil {
+		return nil, err
+	}
+	for _, code := range existing {
+		v.add(codeLines[code], importColBookingCode, "booking code %s already exists", code)
+	}
+
+	return rows, nil
+}
+
+// assignQueueNumbers validates explicit queue numbers and quota, then numbers the remaining rows
+func (u *bookingImportUsecase) assignQueueNumbers(ctx context.Context, schedule *entity.DoctorSchedule, rows []*importRow, v *importValidator) error {
+	db := u.db.WithContext(ctx)
+
+	existing, err := u.bookingRepo.FindQueueNumbersByScheduleID(db, schedule.ID)
+	if err != nil {
+		return err
+	}
+	active, err := u.bookingRepo.FindActiveByScheduleID(db, schedule.ID)
+	if err != nil {
+		return err
+	}
+
+	used := make(map[int]bool, len(existing)+len(rows))
+	maxQueue := 0
+	for _, queueNumber := range existing {
+		used[queueNumber] = true
+		if queueNumber > maxQueue {
+			maxQueue = queueNumber
+		}
+	}
+
+	activeCount := len(active)
+	pending := []*importRow{}
+	for _, row := range rows {
+		if !row.booking.IsCancelled() {
+			activeCount++
+			if activeCount > schedule.TotalQuota {
+				v.add(row.line, importColScheduleID, "schedule %d quota of %d exceeded", schedule.ID, schedule.TotalQuota)
+			}
+		}
+
+		if !row.hasQueue {
+			pending = append(pending, row)
+			continue
+		}
+		if used[row.booking.QueueNumber] {
+			v.add(row.line, importColQueueNumber, "queue number %d is already used on schedule %d", row.booking.QueueNumber, schedule.ID)
+			continue
+		}
+		used[row.booking.QueueNumber] = true
+		if row.booking.QueueNumber > maxQueue {
+			maxQueue = row.booking.QueueNumber
+		}
+	}
+
+	// Rows without queue numbers follow booking time, undated rows keep file order at the end
+	sort.SliceStable(pending, func(i, j int) bool {
+		a, b := pending[i].booking.CreatedAt, pending[j].booking.CreatedAt
+		if a.IsZero() || b.IsZero() {
+			return !a.IsZero() && b.IsZero()
+		}
+		return a.Before(b)
+	})
+	for _, row := range pending {
+		maxQueue++
+		row.booking.QueueNumber = maxQueue
+	}
+
+	return nil
+}
+
+// lookupPatient resolves a patient email to the patient's user ID (nil when not a patient)
+func (u *bookingImportUsecase) lookupPatient(ctx context.Context, email string, v *importValidator) (*uuid.UUID, error) {
+	if email == "" {
+		return nil, nil
+	}
+	if patientID, ok := v.patients[email]; ok {
+		return patientID, nil
+	}
+
+	user, err := u.userRepo.FindByEmail(u.db.WithContext(ctx), email)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			v.patients[email] = nil
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	profile, err := u.patientProfileRepo.FindByUserID(ctx, u.db, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	if profile == nil {
+		v.patients[email] = nil
+		return nil, nil
+	}
+
+	v.patients[email] = &user.ID
+	return &user.ID, nil
+}
+
+// lookupSchedule finds a schedule (nil when not found), cached per import
+func (u *bookingImportUsecase) lookupSchedule(db *gorm.DB, scheduleID int, v *importValidator) (*entity.DoctorSchedule, error) {
+	if schedule, ok := v.schedules[scheduleID]; ok {
+		return schedule, nil
+	}
+
+	schedule, err := u.scheduleRepo.FindByID(db, scheduleID)
+	if err != nil {
+		return nil, err
+	}
+	v.schedules[scheduleID] = schedule
+	return schedule, nil
+}
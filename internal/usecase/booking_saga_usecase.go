@@ -0,0 +1,72 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+
+	"go-template-clean-architecture/internal/converter"
+	"go-template-clean-architecture/internal/delivery/dto"
+	"go-template-clean-architecture/internal/domain/entity"
+	"go-template-clean-architecture/internal/domain/repository"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrBookingSagaNotFound      = errors.New("booking saga not found")
+	ErrInvalidBookingSagaStatus = errors.New("invalid booking saga status")
+)
+
+// BookingSagaUsecase lets admins observe CreateBooking sagas, e.g. failed compensations
+// whose Redis counters need correcting.
+type BookingSagaUsecase interface {
+	GetAllSagas(ctx context.Context, status string, page, limit int) ([]dto.BookingSagaResponse, int64, error)
+	GetSaga(ctx context.Context, id uuid.UUID) (*dto.BookingSagaResponse, error)
+}
+
+type bookingSagaUsecase struct {
+	db       *gorm.DB
+	log      *logrus.Logger
+	sagaRepo repository.BookingSagaRepository
+}
+
+func NewBookingSagaUsecase(db *gorm.DB, log *logrus.Logger, sagaRepo repository.BookingSagaRepository) BookingSagaUsecase {
+	return &bookingSagaUsecase{
+		db:       db,
+		log:      log,
+		sagaRepo: sagaRepo,
+	}
+}
+
+// GetAllSagas returns one page of sagas, newest first, optionally filtered by status
+func (u *bookingSagaUsecase) GetAllSagas(ctx context.Context, status string, page, limit int) ([]dto.BookingSagaResponse, int64, error) {
+	switch entity.BookingSagaStatus(status) {
+	case "", entity.BookingSagaStatusRunning, entity.BookingSagaStatusCompleted, entity.BookingSagaStatusCompensating,
+		entity.BookingSagaStatusCompensated, entity.BookingSagaStatusFailed:
+	default:
+		return nil, 0, ErrInvalidBookingSagaStatus
+	}
+
+	sagas, total, err := u.sagaRepo.FindAll(u.db.WithContext(ctx), status, page, limit)
+	if err != nil {
+		u.log.Warnf("Failed to find booking sagas: %+v", err)
+		return nil, 0, err
+	}
+
+	return converter.BookingSagasToResponses(sagas), total, nil
+}
+
+func (u *bookingSagaUsecase) GetSaga(ctx context.Context, id uuid.UUID) (*dto.BookingSagaResponse, error) {
+	saga, err := u.sagaRepo.FindByID(u.db.WithContext(ctx), id)
+	if err != nil {
+		u.log.Warnf("Failed to find booking saga %s: %+v", id, err)
+		return nil, err
+	}
+	if saga == nil {
+		return nil, ErrBookingSagaNotFound
+	}
+
+	return converter.BookingSagaToResponse(saga), nil
+}
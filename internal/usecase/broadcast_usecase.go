@@ -0,0 +1,398 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"go-template-clean-architecture/config"
+	"go-template-clean-architecture/internal/converter"
+	"go-template-clean-architecture/internal/delivery/dto"
+	"go-template-clean-architecture/internal/delivery/http/middleware"
+	"go-template-clean-architecture/internal/domain/entity"
+	"go-template-clean-architecture/internal/domain/repository"
+	"go-template-clean-architecture/internal/service"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrBroadcastNotFound     = errors.New("broadcast not found")
+	ErrBroadcastInvalidDate  = errors.New("invalid segment date format, use YYYY-MM-DD")
+	ErrBroadcastInvalidRange = errors.New("segment date_from must not be after date_to")
+	ErrBroadcastNoRecipients = errors.New("no patients match the broadcast segment")
+)
+
+type BroadcastUsecase interface {
+	CreateBroadcast(ctx context.Context, req *dto.CreateBroadcastRequest) (*dto.BroadcastResponse, error)
+	PreviewBroadcast(ctx context.Context, req *dto.CreateBroadcastRequest) (*dto.BroadcastPreviewResponse, error)
+	GetBroadcast(ctx context.Context, id uuid.UUID) (*dto.BroadcastResponse, error)
+	GetAllBroadcasts(ctx context.Context) (*dto.BroadcastListResponse, error)
+}
+
+type broadcastUsecase struct {
+	db                  *gorm.DB
+	log                 *logrus.Logger
+	cfg                 *config.Config
+	broadcastRepo       repository.BroadcastRepository
+	userRepo            repository.UserRepository
+	auditService        service.AuditService
+	outboxService       *service.OutboxService
+	notificationService *service.NotificationService
+}
+
+func NewBroadcastUsecase(
+	db *gorm.DB,
+	log *logrus.Logger,
+	cfg *config.Config,
+	broadcastRepo repository.BroadcastRepository,
+	userRepo repository.UserRepository,
+	auditService service.AuditService,
+	outboxService *service.OutboxService,
+	notificationService *service.NotificationService,
+) BroadcastUsecase {
+	u := &broadcastUsecase{
+		db:                  db,
+		log:                 log,
+		cfg:                 cfg,
+		broadcastRepo:       broadcastRepo,
+		userRepo:            userRepo,
+		auditService:        auditService,
+		outboxService:       outboxService,
+		notificationService: notificationService,
+	}
+	u.outboxService.RegisterHandler(entity.OutboxEventBroadcastDelivery, u.handleBroadcastDelivery)
+	return u
+}
+
+// CreateBroadcast sends an announcement to every patient of the segment.
+//
+// Flow:
+// 1. Resolve the segment to patient IDs
+// 2. Insert broadcast + one recipient row per patient, each with a throttled delivery time
+// 3. Enqueue one broadcast.delivery outbox event per recipient, due at its delivery time
+// 4. The outbox worker delivers them (with retries); GetBroadcast reports the progress
+func (u *broadcastUsecase) CreateBroadcast(ctx context.Context, req *dto.CreateBroadcastRequest) (*dto.BroadcastResponse, error) {
+	adminID, ok := middleware.GetUserIDFromContext(ctx)
+	if !ok {
+		return nil, errors.New("user not found in context")
+	}
+
+	segment, err := u.parseSegment(&req.Segment)
+	if err != nil {
+		return nil, err
+	}
+
+	tx := u.db.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	patientIDs, err := u.broadcastRepo.FindSegmentPatientIDs(tx, segment)
+	if err != nil {
+		u.log.Warnf("Failed to find broadcast segment patients: %+v", err)
+		return nil, err
+	}
+	if len(patientIDs) == 0 {
+		return nil, ErrBroadcastNoRecipients
+	}
+
+	segmentJSON, err := segmentToJSON(&req.Segment)
+	if err != nil {
+		return nil, err
+	}
+
+	broadcast := &entity.Broadcast{
+		Title:           req.Title,
+		Message:         req.Message,
+		Segment:         segmentJSON,
+		TotalRecipients: len(patientIDs),
+		CreatedBy:       &adminID,
+	}
+	if err := u.broadcastRepo.Create(tx, broadcast); err != nil {
+		u.log.Warnf("Failed to create broadcast: %+v", err)
+		return nil, err
+	}
+
+	start, err := u.deliveryStart(tx)
+	if err != nil {
+		return nil, err
+	}
+	interval := u.deliveryInterval()
+
+	recipients := make([]entity.BroadcastRecipient, len(patientIDs))
+	for i, patientID := range patientIDs {
+		recipients[i] = entity.BroadcastRecipient{
+			BroadcastID: broadcast.ID,
+			PatientID:   patientID,
+			Status:      entity.BroadcastRecipientPending,
+			ScheduledAt: start.Add(time.Duration(i) * interval),
+		}
+	}
+	if err := u.broadcastRepo.CreateRecipients(tx, recipients); err != nil {
+		u.log.Warnf("Failed to create broadcast recipients: %+v", err)
+		return nil, err
+	}
+
+	for _, recipient := range recipients {
+		if err := u.outboxService.EnqueueAt(tx, recipient.ScheduledAt, entity.OutboxEventBroadcastDelivery, "broadcast_recipient", strconv.FormatInt(recipient.ID, 10), entity.BroadcastDeliveryPayload{
+			BroadcastID: broadcast.ID,
+			RecipientID: recipient.ID,
+			PatientID:   recipient.PatientID,
+		}); err != nil {
+			u.log.Warnf("Failed to enqueue broadcast delivery: %+v", err)
+			return nil, err
+		}
+	}
+
+	// Audit log - broadcast
+	if err := u.auditService.LogCreate(ctx, tx, &adminID, entity.AuditActionBroadcastSend, "broadcast", broadcast.ID.String(), map[string]interface{}{
+		"title":      broadcast.Title,
+		"segment":    broadcast.Segment,
+		"recipients": broadcast.TotalRecipients,
+	}); err != nil {
+		u.log.Warnf("Failed to create audit log: %+v", err)
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		u.log.Warnf("Failed commit transaction: %+v", err)
+		return nil, err
+	}
+
+	u.log.Infof("Broadcast %s queued for %d patients", broadcast.ID, broadcast.TotalRecipients)
+
+	response := converter.BroadcastToResponse(broadcast)
+	response.Report.Pending = int64(len(recipients))
+	return response, nil
+}
+
+// PreviewBroadcast resolves the segment without sending anything
+func (u *broadcastUsecase) PreviewBroadcast(ctx context.Context, req *dto.CreateBroadcastRequest) (*dto.BroadcastPreviewResponse, error) {
+	segment, err := u.parseSegment(&req.Segment)
+	if err != nil {
+		return nil, err
+	}
+
+	patientIDs, err := u.broadcastRepo.FindSegmentPatientIDs(u.db.WithContext(ctx), segment)
+	if err != nil {
+		u.log.Warnf("Failed to find broadcast segment patients: %+v", err)
+		return nil, err
+	}
+
+	start, err := u.deliveryStart(u.db.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.BroadcastPreviewResponse{
+		TotalRecipients:       len(patientIDs),
+		EstimatedCompletionAt: start.Add(time.Duration(len(patientIDs)) * u.deliveryInterval()),
+	}, nil
+}
+
+// GetBroadcast returns a broadcast with its delivery report and failed recipients
+func (u *broadcastUsecase) GetBroadcast(ctx context.Context, id uuid.UUID) (*dto.BroadcastResponse, error) {
+	broadcast, err := u.broadcastRepo.FindByID(u.db.WithContext(ctx), id)
+	if err != nil {
+		u.log.Warnf("Failed to find broadcast %s: %+v", id, err)
+		return nil, err
+	}
+	if broadcast == nil {
+		return nil, ErrBroadcastNotFound
+	}
+
+	responses, err := u.withReports(ctx, []entity.Broadcast{*broadcast})
+	if err != nil {
+		return nil, err
+	}
+	response := &responses[0]
+
+	if response.Report.Failed > 0 {
+		failed, err := u.broadcastRepo.FindFailedRecipients(u.db.WithContext(ctx), id)
+		if err != nil {
+			u.log.Warnf("Failed to find failed recipients of broadcast %s: %+v", id, err)
+			return nil, err
+		}
+		response.Report.Failures = converter.BroadcastFailuresToResponses(failed)
+	}
+
+	return response, nil
+}
+
+// GetAllBroadcasts returns every broadcast, newest first, with delivery counts
+func (u *broadcastUsecase) GetAllBroadcasts(ctx context.Context) (*dto.BroadcastListResponse, error) {
+	broadcasts, err := u.broadcastRepo.FindAll(u.db.WithContext(ctx))
+	if err != nil {
+		u.log.Warnf("Failed to find broadcasts: %+v", err)
+		return nil, err
+	}
+
+	responses, err := u.withReports(ctx, broadcasts)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.BroadcastListResponse{
+		Broadcasts: responses,
+		Total:      len(responses),
+	}, nil
+}
+
+// withReports converts broadcasts and fills their delivery counts
+func (u *broadcastUsecase) withReports(ctx context.Context, broadcasts []entity.Broadcast) ([]dto.BroadcastResponse, error) {
+	ids := make([]uuid.UUID, len(broadcasts))
+	for i := range broadcasts {
+		ids[i] = broadcasts[i].ID
+	}
+
+	counts, err := u.broadcastRepo.CountRecipientsByStatus(u.db.WithContext(ctx), ids)
+	if err != nil {
+		u.log.Warnf("Failed to count broadcast recipients: %+v", err)
+		return nil, err
+	}
+
+	reports := make(map[uuid.UUID]*dto.BroadcastDeliveryReport, len(broadcasts))
+	for _, id := range ids {
+		reports[id] = &dto.BroadcastDeliveryReport{}
+	}
+	for _, c := range counts {
+		report := reports[c.BroadcastID]
+		switch c.Status {
+		case entity.BroadcastRecipientPending:
+			report.Pending = c.Count
+		case entity.BroadcastRecipientSent:
+			report.Sent = c.Count
+		case entity.BroadcastRecipientFailed:
+			report.Failed = c.Count
+		}
+	}
+
+	responses := make([]dto.BroadcastResponse, len(broadcasts))
+	for i := range broadcasts {
+		responses[i] = *converter.BroadcastToResponse(&broadcasts[i])
+		report := reports[broadcasts[i].ID]
+		report.Completed = report.Pending == 0
+		responses[i].Report = *report
+	}
+	return responses, nil
+}
+
+// handleBroadcastDelivery delivers a broadcast to one patient.
+// Already delivered recipients are skipped (at-least-once delivery);
+// patients whose account is gone or inactive are marked failed without retry.
+func (u *broadcastUsecase) handleBroadcastDelivery(ctx context.Context, event *entity.OutboxEvent) error {
+	var payload entity.BroadcastDeliveryPayload
+	if err := event.DecodePayload(&payload); err != nil {
+		return err
+	}
+
+	recipient, err := u.broadcastRepo.FindRecipientByID(u.db.WithContext(ctx), payload.RecipientID)
+	if err != nil {
+		return err
+	}
+	if recipient == nil || recipient.Status == entity.BroadcastRecipientSent {
+		return nil
+	}
+
+	broadcast, err := u.broadcastRepo.FindByID(u.db.WithContext(ctx), recipient.BroadcastID)
+	if err != nil {
+		return err
+	}
+	if broadcast == nil {
+		return nil
+	}
+
+	patient, err := u.userRepo.FindByID(u.db.WithContext(ctx), recipient.PatientID)
+	if err != nil {
+		return err
+	}
+	if patient == nil || (patient.IsActive != nil && !*patient.IsActive) {
+		return u.broadcastRepo.MarkRecipientFailed(u.db.WithContext(ctx), recipient.ID, "patient account not found or inactive")
+	}
+
+	// Recipient is sent once the notification is recorded, the notification worker delivers it
+	err = u.notificationService.Notify(ctx, u.db.WithContext(ctx), service.NotificationRequest{
+		PatientID: recipient.PatientID,
+		EventType: event.EventType,
+		Subject:   broadcast.Title,
+		Message:   fmt.Sprintf("[%s] %s", broadcast.Title, broadcast.Message),
+		DedupeKey: fmt.Sprintf("broadcast:%d", recipient.ID),
+		InApp:     true,
+		Data: map[string]string{
+			entity.TemplateVarTitle:   broadcast.Title,
+			entity.TemplateVarMessage: broadcast.Message,
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	return u.broadcastRepo.MarkRecipientSent(u.db.WithContext(ctx), recipient.ID, time.Now())
+}
+
+// parseSegment validates the segment request and converts it to a repository filter
+func (u *broadcastUsecase) parseSegment(req *dto.BroadcastSegmentRequest) (*entity.BroadcastSegment, error) {
+	segment := &entity.BroadcastSegment{DoctorID: req.DoctorID}
+
+	if req.DateFrom != "" {
+		dateFrom, err := time.Parse("2006-01-02", req.DateFrom)
+		if err != nil {
+			return nil, ErrBroadcastInvalidDate
+		}
+		segment.DateFrom = &dateFrom
+	}
+	if req.DateTo != "" {
+		dateTo, err := time.Parse("2006-01-02", req.DateTo)
+		if err != nil {
+			return nil, ErrBroadcastInvalidDate
+		}
+		segment.DateTo = &dateTo
+	}
+	if segment.DateFrom != nil && segment.DateTo != nil && segment.DateFrom.After(*segment.DateTo) {
+		return nil, ErrBroadcastInvalidRange
+	}
+
+	if req.UpcomingOnly {
+		now := time.Now().In(u.cfg.App.Location)
+		today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+		segment.UpcomingFrom = &today
+	}
+
+	return segment, nil
+}
+
+// deliveryStart returns when the first notification of a new broadcast is due:
+// now, or right after the last notification still queued by earlier broadcasts.
+func (u *broadcastUsecase) deliveryStart(db *gorm.DB) (time.Time, error) {
+	start := time.Now()
+	last, err := u.broadcastRepo.FindLastPendingScheduledAt(db)
+	if err != nil {
+		u.log.Warnf("Failed to find queued broadcast deliveries: %+v", err)
+		return time.Time{}, err
+	}
+	if last != nil && last.After(start) {
+		start = last.Add(u.deliveryInterval())
+	}
+	return start, nil
+}
+
+// deliveryInterval is the delay between two notifications (BROADCAST_RATE_PER_MINUTE)
+func (u *broadcastUsecase) deliveryInterval() time.Duration {
+	return time.Minute / time.Duration(u.cfg.Broadcast.RatePerMinute)
+}
+
+// segmentToJSON stores the segment request as given by the admin
+func segmentToJSON(req *dto.BroadcastSegmentRequest) (entity.JSON, error) {
+	raw, err := json.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+	var data entity.JSON
+	if err := json.Unmarshal(raw, &data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
@@ -0,0 +1,379 @@
+package usecase
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"go-template-clean-architecture/internal/converter"
+	"go-template-clean-architecture/internal/delivery/dto"
+	"go-template-clean-architecture/internal/delivery/http/middleware"
+	"go-template-clean-architecture/internal/domain/entity"
+	"go-template-clean-architecture/internal/domain/repository"
+	"go-template-clean-architecture/internal/service"
+	"go-template-clean-architecture/pkg/storage"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrDataExportNotFound = errors.New("no data export requested")
+	ErrDataExportNotReady = errors.New("data export is not ready yet")
+	ErrDataExportExpired  = errors.New("data export expired, request a new one")
+	ErrDataExportFailed   = errors.New("data export failed, request a new one")
+)
+
+const (
+	// dataExportTTL is how long a generated archive can be downloaded
+	dataExportTTL = 7 * 24 * time.Hour
+
+	// dataExportStaleAfter gives up on an export that is still pending (outbox retries
+	// exhausted), so the user can request a new one
+	dataExportStaleAfter = time.Hour
+
+	// dataExportPageSize is the number of medical records read per query
+	dataExportPageSize = 100
+
+	dataExportReadyEventType = "patient.data_export_ready"
+)
+
+type DataExportUsecase interface {
+	RequestExport(ctx context.Context, refresh bool) (*dto.DataExportResponse, error)
+	DownloadExport(ctx context.Context) ([]byte, error)
+}
+
+type dataExportUsecase struct {
+	db                  *gorm.DB
+	log                 *logrus.Logger
+	dataExportRepo      repository.DataExportRepository
+	userRepo            repository.UserRepository
+	bookingRepo         repository.BookingRepository
+	medicalRecordRepo   repository.MedicalRecordRepository
+	auditRepo           repository.AuditLogRepository
+	auditService        service.AuditService
+	outboxService       *service.OutboxService
+	notificationService *service.NotificationService
+	formatService       service.FormatService
+	fileStorage         storage.Storage
+}
+
+func NewDataExportUsecase(
+	db *gorm.DB,
+	log *logrus.Logger,
+	dataExportRepo repository.DataExportRepository,
+	userRepo repository.UserRepository,
+	bookingRepo repository.BookingRepository,
+	medicalRecordRepo repository.MedicalRecordRepository,
+	auditRepo repository.AuditLogRepository,
+	auditService service.AuditService,
+	outboxService *service.OutboxService,
+	notificationService *service.NotificationService,
+	formatService service.FormatService,
+	fileStorage storage.Storage,
+) DataExportUsecase {
+	u := &dataExportUsecase{
+		db:                  db,
+		log:                 log,
+		dataExportRepo:      dataExportRepo,
+		userRepo:            userRepo,
+		bookingRepo:         bookingRepo,
+		medicalRecordRepo:   medicalRecordRepo,
+		auditRepo:           auditRepo,
+		auditService:        auditService,
+		outboxService:       outboxService,
+		notificationService: notificationService,
+		formatService:       formatService,
+		fileStorage:         fileStorage,
+	}
+	u.outboxService.RegisterHandler(entity.OutboxEventDataExportRequested, u.handleExportRequested)
+	u.outboxService.RegisterHandler(entity.OutboxEventDataExportExpire, u.handleExportExpire)
+	return u
+}
+
+// RequestExport returns the personal data export of the logged-in user, requesting a new
+// one when there is none in progress or downloadable (or refresh is set and the last one
+// is ready). The archive is generated by the outbox worker and the user is notified.
+func (u *dataExportUsecase) RequestExport(ctx context.Context, refresh bool) (*dto.DataExportResponse, error) {
+	userID, ok := middleware.GetUserIDFromContext(ctx)
+	if !ok {
+		return nil, errors.New("user not found in context")
+	}
+
+	tx := u.db.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	latest, err := u.dataExportRepo.FindLatestByUserID(tx, userID)
+	if err != nil {
+		u.log.Warnf("Failed to find data export of user %s: %+v", userID, err)
+		return nil, err
+	}
+
+	now := time.Now()
+	if latest != nil {
+		switch {
+		case latest.Status == entity.DataExportStatusPending && now.Sub(latest.CreatedAt) < dataExportStaleAfter:
+			return converter.DataExportToResponse(latest), nil
+		case latest.Status == entity.DataExportStatusPending:
+			latest.Status = entity.DataExportStatusFailed
+			latest.LastError = "generation did not finish"
+			if err := u.dataExportRepo.Update(tx, latest); err != nil {
+				u.log.Warnf("Failed to update data export %s: %+v", latest.ID, err)
+				return nil, err
+			}
+		case latest.IsDownloadable(now) && !refresh:
+			return converter.DataExportToResponse(latest), nil
+		}
+	}
+
+	export := &entity.DataExport{
+		UserID: userID,
+		Status: entity.DataExportStatusPending,
+	}
+	if err := u.dataExportRepo.Create(tx, export); err != nil {
+		// A concurrent request created the pending export first
+		if isDuplicateKeyError(err, "uq_data_exports_pending") {
+			tx.Rollback()
+			return u.latestExport(ctx, userID)
+		}
+		u.log.Warnf("Failed to create data export: %+v", err)
+		return nil, err
+	}
+
+	if err := u.outboxService.Enqueue(tx, entity.OutboxEventDataExportRequested, "data_export", export.ID.String(), entity.DataExportPayload{
+		ExportID: export.ID,
+		UserID:   userID,
+	}); err != nil {
+		u.log.Warnf("Failed to enqueue data export: %+v", err)
+		return nil, err
+	}
+
+	// Audited on the account, the user is alerted in case it was not them
+	if err := u.auditService.LogCreate(ctx, tx, &userID, entity.AuditActionAccountExport, "user", userID.String(), entity.JSON{
+		"export_id": export.ID,
+	}); err != nil {
+		u.log.Warnf("Failed to create audit log: %+v", err)
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		u.log.Warnf("Failed commit transaction: %+v", err)
+		return nil, err
+	}
+
+	return converter.DataExportToResponse(export), nil
+}
+
+// DownloadExport returns the ZIP archive of the logged-in user's latest export
+func (u *dataExportUsecase) DownloadExport(ctx context.Context) ([]byte, error) {
+	userID, ok := middleware.GetUserIDFromContext(ctx)
+	if !ok {
+		return nil, errors.New("user not found in context")
+	}
+
+	export, err := u.dataExportRepo.FindLatestByUserID(u.db.WithContext(ctx), userID)
+	if err != nil {
+		u.log.Warnf("Failed to find data export of user %s: %+v", userID, err)
+		return nil, err
+	}
+	if export == nil {
+		return nil, ErrDataExportNotFound
+	}
+	switch export.Status {
+	case entity.DataExportStatusPending:
+		return nil, ErrDataExportNotReady
+	case entity.DataExportStatusFailed:
+		return nil, ErrDataExportFailed
+	}
+	if !export.IsDownloadable(time.Now()) {
+		return nil, ErrDataExportExpired
+	}
+
+	data, err := u.fileStorage.Get(ctx, export.FileKey)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return nil, ErrDataExportExpired
+		}
+		u.log.Warnf("Failed to read data export %s: %+v", export.ID, err)
+		return nil, err
+	}
+	return data, nil
+}
+
+func (u *dataExportUsecase) latestExport(ctx context.Context, userID uuid.UUID) (*dto.DataExportResponse, error) {
+	export, err := u.dataExportRepo.FindLatestByUserID(u.db.WithContext(ctx), userID)
+	if err != nil {
+		u.log.Warnf("Failed to find data export of user %s: %+v", userID, err)
+		return nil, err
+	}
+	if export == nil {
+		return nil, ErrDataExportNotFound
+	}
+	return converter.DataExportToResponse(export), nil
+}
+
+// handleExportRequested generates the archive of a pending export, stores it privately
+// and notifies the user. Exports no longer pending are skipped (at-least-once delivery).
+func (u *dataExportUsecase) handleExportRequested(ctx context.Context, event *entity.OutboxEvent) error {
+	var payload entity.DataExportPayload
+	if err := event.DecodePayload(&payload); err != nil {
+		return err
+	}
+
+	export, err := u.dataExportRepo.FindByID(u.db.WithContext(ctx), payload.ExportID)
+	if err != nil {
+		return err
+	}
+	if export == nil || export.Status != entity.DataExportStatusPending {
+		return nil
+	}
+
+	archive, err := u.buildArchive(ctx, export.UserID)
+	if err != nil {
+		return err
+	}
+	if archive == nil {
+		export.Status = entity.DataExportStatusFailed
+		export.LastError = "user not found or erased"
+		return u.dataExportRepo.Update(u.db.WithContext(ctx), export)
+	}
+
+	key := fmt.Sprintf("%sexports/%s/%s.zip", storage.PrivatePrefix, export.UserID, export.ID)
+	if err := u.fileStorage.Put(ctx, key, archive, "application/zip"); err != nil {
+		return fmt.Errorf("store data export %s: %w", export.ID, err)
+	}
+
+	tx := u.db.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	now := time.Now()
+	expiresAt := now.Add(dataExportTTL)
+	export.Status = entity.DataExportStatusReady
+	export.FileKey = key
+	export.FileSize = int64(len(archive))
+	export.LastError = ""
+	export.CompletedAt = &now
+	export.ExpiresAt = &expiresAt
+	if err := u.dataExportRepo.Update(tx, export); err != nil {
+		return err
+	}
+
+	if err := u.outboxService.EnqueueAt(tx, expiresAt, entity.OutboxEventDataExportExpire, "data_export", export.ID.String(), payload); err != nil {
+		return err
+	}
+
+	if err := u.notificationService.Notify(ctx, tx, service.NotificationRequest{
+		PatientID: export.UserID,
+		EventType: dataExportReadyEventType,
+		Subject:   "Your personal data export is ready",
+		Message:   fmt.Sprintf("Your personal data export is ready. Download it in the app before %s.", u.formatService.DateTime(expiresAt)),
+		DedupeKey: outboxDedupeKey(event),
+		Channel:   entity.NotificationChannelEmail,
+	}); err != nil {
+		return err
+	}
+
+	return tx.Commit().Error
+}
+
+// handleExportExpire deletes the archive of an export once it expired
+func (u *dataExportUsecase) handleExportExpire(ctx context.Context, event *entity.OutboxEvent) error {
+	var payload entity.DataExportPayload
+	if err := event.DecodePayload(&payload); err != nil {
+		return err
+	}
+
+	export, err := u.dataExportRepo.FindByID(u.db.WithContext(ctx), payload.ExportID)
+	if err != nil {
+		return err
+	}
+	if export == nil || export.Status != entity.DataExportStatusReady {
+		return nil
+	}
+
+	if err := u.fileStorage.Delete(ctx, export.FileKey); err != nil {
+		return fmt.Errorf("delete data export %s: %w", export.ID, err)
+	}
+
+	export.Status = entity.DataExportStatusExpired
+	export.FileKey = ""
+	return u.dataExportRepo.Update(u.db.WithContext(ctx), export)
+}
+
+// buildArchive assembles the personal data of the user into a ZIP of JSON files:
+// profile, bookings, medical records and audit trail. Returns nil for missing or
+// erased users.
+func (u *dataExportUsecase) buildArchive(ctx context.Context, userID uuid.UUID) ([]byte, error) {
+	db := u.db.WithContext(ctx)
+
+	user, err := u.userRepo.FindByID(db, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil || user.IsErased() {
+		return nil, nil
+	}
+
+	var profile interface{} = converter.UserToResponse(user)
+	if user.PatientProfile != nil {
+		profile = converter.PatientProfileToResponse(user.PatientProfile, user)
+	}
+
+	bookings, err := u.bookingRepo.FindByPatientID(db, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var records []entity.MedicalRecord
+	for page := 1; ; page++ {
+		batch, total, err := u.medicalRecordRepo.FindByPatientID(db, userID, page, dataExportPageSize)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, batch...)
+		if len(batch) < dataExportPageSize || int64(len(records)) >= total {
+			break
+		}
+	}
+
+	bookingIDs := make([]string, len(bookings))
+	for i := range bookings {
+		bookingIDs[i] = bookings[i].ID.String()
+	}
+	auditLogs, err := u.auditRepo.FindByUserOrEntities(db, userID, "booking", bookingIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	files := []struct {
+		name string
+		data interface{}
+	}{
+		{"profile.json", profile},
+		{"bookings.json", converter.BookingsToResponses(bookings)},
+		{"medical_records.json", converter.MedicalRecordsToResponses(records)},
+		{"audit_trail.json", converter.AuditLogsToDataExportEntries(auditLogs, userID)},
+	}
+
+	var buf bytes.Buffer
+	archive := zip.NewWriter(&buf)
+	for _, file := range files {
+		w, err := archive.Create(file.name)
+		if err != nil {
+			return nil, err
+		}
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(file.data); err != nil {
+			return nil, fmt.Errorf("encode %s: %w", file.name, err)
+		}
+	}
+	if err := archive.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
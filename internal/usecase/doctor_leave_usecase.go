@@ -0,0 +1,281 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"go-template-clean-architecture/internal/converter"
+	"go-template-clean-architecture/internal/delivery/dto"
+	"go-template-clean-architecture/internal/delivery/http/middleware"
+	"go-template-clean-architecture/internal/domain/entity"
+	"go-template-clean-architecture/internal/domain/repository"
+	"go-template-clean-architecture/internal/service"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrDoctorLeaveNotFound = errors.New("doctor leave not found")
+	ErrInvalidLeaveDate    = errors.New("invalid leave date format, use YYYY-MM-DD")
+	ErrLeaveEndBeforeStart = errors.New("leave end date is before its start date")
+	ErrLeaveInPast         = errors.New("leave cannot start in the past")
+	ErrLeaveOverlaps       = errors.New("leave overlaps another leave of the doctor")
+)
+
+type DoctorLeaveUsecase interface {
+	CreateLeave(ctx context.Context, doctorID uuid.UUID, req *dto.CreateDoctorLeaveRequest) (*dto.DoctorLeaveResponse, error)
+	GetLeaves(ctx context.Context, doctorID uuid.UUID, from, to string) (*dto.DoctorLeaveListResponse, error)
+	DeleteLeave(ctx context.Context, doctorID uuid.UUID, id int) error
+}
+
+type doctorLeaveUsecase struct {
+	db            *gorm.DB
+	log           *logrus.Logger
+	leaveRepo     repository.DoctorLeaveRepository
+	doctorRepo    repository.DoctorProfileRepository
+	scheduleRepo  repository.DoctorScheduleRepository
+	versionRepo   repository.ScheduleVersionRepository
+	auditService  service.AuditService
+	outboxService *service.OutboxService
+	listingCache  service.ListingCacheService
+}
+
+func NewDoctorLeaveUsecase(
+	db *gorm.DB,
+	log *logrus.Logger,
+	leaveRepo repository.DoctorLeaveRepository,
+	doctorRepo repository.DoctorProfileRepository,
+	scheduleRepo repository.DoctorScheduleRepository,
+	versionRepo repository.ScheduleVersionRepository,
+	auditService service.AuditService,
+	outboxService *service.OutboxService,
+	listingCache service.ListingCacheService,
+) DoctorLeaveUsecase {
+	return &doctorLeaveUsecase{
+		db:            db,
+		log:           log,
+		leaveRepo:     leaveRepo,
+		doctorRepo:    doctorRepo,
+		scheduleRepo:  scheduleRepo,
+		versionRepo:   versionRepo,
+		auditService:  auditService,
+		outboxService: outboxService,
+		listingCache:  listingCache,
+	}
+}
+
+// CreateLeave records a leave of the doctor. The doctor's schedules in the leave are flagged
+// and closed for booking, and the patients booked on them are notified by the outbox worker.
+// Existing bookings are kept: admins resolve them by reassigning or deleting the schedule.
+func (u *doctorLeaveUsecase) CreateLeave(ctx context.Context, doctorID uuid.UUID, req *dto.CreateDoctorLeaveRequest) (*dto.DoctorLeaveResponse, error) {
+	startDate, err := time.Parse("2006-01-02", req.StartDate)
+	if err != nil {
+		return nil, ErrInvalidLeaveDate
+	}
+	endDate, err := time.Parse("2006-01-02", req.EndDate)
+	if err != nil {
+		return nil, ErrInvalidLeaveDate
+	}
+	if endDate.Before(startDate) {
+		return nil, ErrLeaveEndBeforeStart
+	}
+	if startDate.Before(time.Now().UTC().Truncate(24 * time.Hour)) {
+		return nil, ErrLeaveInPast
+	}
+
+	tx := u.db.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	doctor, err := u.doctorRepo.FindByUserID(tx, doctorID)
+	if err != nil {
+		u.log.Warnf("Failed to find doctor %s: %+v", doctorID, err)
+		return nil, err
+	}
+	if doctor == nil {
+		return nil, ErrDoctorNotFound
+	}
+
+	overlapping, err := u.leaveRepo.FindOverlapping(tx, doctorID, startDate, endDate)
+	if err != nil {
+		u.log.Warnf("Failed to find overlapping leaves: %+v", err)
+		return nil, err
+	}
+	if len(overlapping) > 0 {
+		return nil, ErrLeaveOverlaps
+	}
+
+	userID, _ := middleware.GetUserIDFromContext(ctx)
+	leave := &entity.DoctorLeave{
+		DoctorID:  doctorID,
+		StartDate: startDate,
+		EndDate:   endDate,
+		Reason:    strings.TrimSpace(req.Reason),
+		CreatedBy: &userID,
+	}
+	if err := u.leaveRepo.Create(tx, leave); err != nil {
+		u.log.Warnf("Failed to create doctor leave: %+v", err)
+		return nil, err
+	}
+
+	now := time.Now()
+	flagged, err := u.setLeaveFlags(tx, leave, &now, userID, entity.AuditActionDoctorLeaveCreate)
+	if err != nil {
+		u.log.Warnf("Failed to flag schedules in leave %d: %+v", leave.ID, err)
+		return nil, err
+	}
+
+	// Audit log - create leave
+	response := converter.DoctorLeaveToResponse(leave)
+	if err := u.auditService.LogCreate(ctx, tx, &userID, entity.AuditActionDoctorLeaveCreate, "doctor_leave", strconv.Itoa(leave.ID), response); err != nil {
+		u.log.Warnf("Failed to create audit log: %+v", err)
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		u.log.Warnf("Failed commit transaction: %+v", err)
+		return nil, err
+	}
+	u.listingCache.Invalidate(ctx, service.ListingSchedules)
+
+	if flagged > 0 {
+		u.log.Warnf("Doctor %s on leave %s to %s: %d existing schedules closed and flagged for admin action",
+			doctorID, req.StartDate, req.EndDate, flagged)
+	}
+
+	response.FlaggedSchedules = flagged
+	return response, nil
+}
+
+// GetLeaves returns the doctor's leaves ordered by start date, optionally only those
+// overlapping a date range (YYYY-MM-DD)
+func (u *doctorLeaveUsecase) GetLeaves(ctx context.Context, doctorID uuid.UUID, from, to string) (*dto.DoctorLeaveListResponse, error) {
+	for _, date := range []string{from, to} {
+		if date == "" {
+			continue
+		}
+		if _, err := time.Parse("2006-01-02", date); err != nil {
+			return nil, ErrInvalidLeaveDate
+		}
+	}
+
+	leaves, err := u.leaveRepo.FindByDoctorID(u.db.WithContext(ctx), doctorID, from, to)
+	if err != nil {
+		u.log.Warnf("Failed to find leaves of doctor %s: %+v", doctorID, err)
+		return nil, err
+	}
+
+	return &dto.DoctorLeaveListResponse{
+		Leaves: converter.DoctorLeavesToResponses(leaves),
+		Total:  len(leaves),
+	}, nil
+}
+
+// DeleteLeave removes a leave of the doctor. Its schedules lose the flag and are open
+// for booking again.
+func (u *doctorLeaveUsecase) DeleteLeave(ctx context.Context, doctorID uuid.UUID, id int) error {
+	tx := u.db.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	leave, err := u.leaveRepo.FindByID(tx, id)
+	if err != nil {
+		u.log.Warnf("Failed to find doctor leave %d: %+v", id, err)
+		return err
+	}
+	if leave == nil || leave.DoctorID != doctorID {
+		return ErrDoctorLeaveNotFound
+	}
+
+	if _, err := u.leaveRepo.Delete(tx, id); err != nil {
+		u.log.Warnf("Failed to delete doctor leave: %+v", err)
+		return err
+	}
+
+	userID, _ := middleware.GetUserIDFromContext(ctx)
+	if _, err := u.setLeaveFlags(tx, leave, nil, userID, entity.AuditActionDoctorLeaveDelete); err != nil {
+		u.log.Warnf("Failed to clear leave flags: %+v", err)
+		return err
+	}
+
+	// Audit log - delete leave
+	if err := u.auditService.LogDelete(ctx, tx, &userID, entity.AuditActionDoctorLeaveDelete, "doctor_leave", strconv.Itoa(id), converter.DoctorLeaveToResponse(leave)); err != nil {
+		u.log.Warnf("Failed to create audit log: %+v", err)
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		u.log.Warnf("Failed commit transaction: %+v", err)
+		return err
+	}
+	u.listingCache.Invalidate(ctx, service.ListingSchedules)
+
+	return nil
+}
+
+// setLeaveFlags flags the doctor's schedules in the leave and closes them for booking (at != nil),
+// or clears their flag and reopens them. Each schedule it changed gets a history entry, and
+// the patients booked on a newly closed schedule are notified through the outbox.
+func (u *doctorLeaveUsecase) setLeaveFlags(tx *gorm.DB, leave *entity.DoctorLeave, at *time.Time, userID uuid.UUID, action string) (int64, error) {
+	schedules, err := u.scheduleRepo.FindByDoctorAndDateRange(tx, leave.DoctorID, leave.StartDate, leave.EndDate)
+	if err != nil {
+		return 0, err
+	}
+
+	var ids []int
+	versions := make([]entity.ScheduleVersion, 0, len(schedules))
+	for i := range schedules {
+		oldSchedule := &schedules[i]
+		// Mark and Clear leave schedules already in the wanted state untouched
+		if (oldSchedule.LeaveFlaggedAt != nil) == (at != nil) {
+			continue
+		}
+		isOpen := at == nil
+		newSchedule := *oldSchedule
+		newSchedule.LeaveFlaggedAt = at
+		newSchedule.IsOpen = &isOpen
+		ids = append(ids, oldSchedule.ID)
+		versions = append(versions, *entity.NewScheduleVersion(oldSchedule.ID, &userID, action, oldSchedule, &newSchedule))
+
+		// Proposals have no bookings to notify
+		if at == nil || !oldSchedule.IsApproved() {
+			continue
+		}
+		if err := u.outboxService.Enqueue(tx, entity.OutboxEventScheduleDoctorLeave, "doctor_schedule", strconv.Itoa(oldSchedule.ID), entity.ScheduleLeavePayload{
+			ScheduleID:   oldSchedule.ID,
+			LeaveID:      leave.ID,
+			ScheduleDate: oldSchedule.ScheduleDate.Format("2006-01-02"),
+			StartTime:    oldSchedule.StartTime,
+			EndTime:      oldSchedule.EndTime,
+		}); err != nil {
+			return 0, err
+		}
+	}
+
+	var affected int64
+	if at != nil {
+		affected, err = u.scheduleRepo.MarkLeaveFlagged(tx, ids, *at)
+	} else {
+		affected, err = u.scheduleRepo.ClearLeaveFlag(tx, ids)
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	if err := u.versionRepo.CreateBatch(tx, versions); err != nil {
+		return 0, err
+	}
+
+	return affected, nil
+}
+
+// onLeave reports whether the date falls within one of the leaves
+func onLeave(leaves []entity.DoctorLeave, date time.Time) bool {
+	for i := range leaves {
+		if leaves[i].Covers(date) {
+			return true
+		}
+	}
+	return false
+}
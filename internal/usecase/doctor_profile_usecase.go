@@ -3,13 +3,19 @@ package usecase
 import (
 	"context"
 	"errors"
+	"fmt"
+	"math"
+	"strings"
 
+	"go-template-clean-architecture/config"
 	"go-template-clean-architecture/internal/converter"
 	"go-template-clean-architecture/internal/delivery/dto"
 	"go-template-clean-architecture/internal/delivery/http/middleware"
 	"go-template-clean-architecture/internal/domain/entity"
 	"go-template-clean-architecture/internal/domain/repository"
 	"go-template-clean-architecture/internal/service"
+	"go-template-clean-architecture/pkg/imaging"
+	"go-template-clean-architecture/pkg/storage"
 
 	"github.com/google/uuid"
 	"github.com/sirupsen/logrus"
@@ -23,23 +29,48 @@ var (
 	ErrDoctorSTRExists    = errors.New("STR number already exists")
 	ErrDoctorRoleNotFound = errors.New("role not found")
 	ErrInvalidOldPassword = errors.New("invalid old password")
+	ErrInvalidDoctorPhoto = errors.New("photo must be a JPEG or PNG image")
+	ErrDoctorPhotoTooBig  = errors.New("photo dimensions are too large")
+	ErrInvalidDoctorSort  = errors.New("invalid sort, use name, newest, fee_asc, fee_desc or rating")
+)
+
+// doctorPhotoSize is the side in pixels of the square profile photos
+const doctorPhotoSize = 512
+
+// Number of upcoming schedules embedded in the public doctor detail
+const (
+	DefaultDoctorDetailSchedules = 5
+	MaxDoctorDetailSchedules     = 20
 )
 
 type DoctorProfileUsecase interface {
 	CreateDoctor(ctx context.Context, req *dto.CreateDoctorRequest) (*dto.DoctorResponse, error)
 	GetDoctor(ctx context.Context, doctorID uuid.UUID) (*dto.DoctorResponse, error)
-	GetAllDoctors(ctx context.Context) (*dto.DoctorListResponse, error)
+	GetPublicDoctor(ctx context.Context, doctorID uuid.UUID, scheduleLimit int) (*dto.DoctorDetailResponse, error)
+	GetAllDoctors(ctx context.Context, filter *dto.DoctorFilter, page, limit int) (*dto.DoctorListResponse, int64, error)
+	GetPublicDoctors(ctx context.Context, filter *dto.DoctorFilter, page, limit int) (*dto.DoctorListResponse, int64, error)
 	UpdateDoctor(ctx context.Context, doctorID uuid.UUID, req *dto.UpdateDoctorRequest) (*dto.DoctorResponse, error)
 	UpdateSelfProfile(ctx context.Context, doctorID uuid.UUID, req *dto.DoctorUpdateSelfRequest) (*dto.DoctorResponse, error)
 	DeleteDoctor(ctx context.Context, doctorID uuid.UUID) error
+	UpdatePhoto(ctx context.Context, doctorID uuid.UUID, data []byte) (*dto.DoctorResponse, error)
+	DeletePhoto(ctx context.Context, doctorID uuid.UUID) (*dto.DoctorResponse, error)
 }
 
 type doctorProfileUsecase struct {
-	db                *gorm.DB
-	log               *logrus.Logger
-	userRepo          repository.UserRepository
-	doctorProfileRepo repository.DoctorProfileRepository
-	auditService      service.AuditService
+	db                 *gorm.DB
+	log                *logrus.Logger
+	userRepo           repository.UserRepository
+	doctorProfileRepo  repository.DoctorProfileRepository
+	waitFeedbackRepo   repository.WaitFeedbackRepository
+	auditService       service.AuditService
+	fileStorage        storage.Storage
+	reviewService      service.DoctorReviewService
+	specializationRepo repository.SpecializationRepository
+	specDefaultRepo    repository.SpecializationDefaultRepository
+	cfg                *config.Config
+	scheduleRepo       repository.DoctorScheduleRepository
+	redisSyncService   *service.RedisSyncService
+	listingCache       service.ListingCacheService
 }
 
 func NewDoctorProfileUsecase(
@@ -47,14 +78,32 @@ func NewDoctorProfileUsecase(
 	log *logrus.Logger,
 	userRepo repository.UserRepository,
 	doctorProfileRepo repository.DoctorProfileRepository,
+	waitFeedbackRepo repository.WaitFeedbackRepository,
 	auditService service.AuditService,
+	fileStorage storage.Storage,
+	reviewService service.DoctorReviewService,
+	specializationRepo repository.SpecializationRepository,
+	specDefaultRepo repository.SpecializationDefaultRepository,
+	cfg *config.Config,
+	scheduleRepo repository.DoctorScheduleRepository,
+	redisSyncService *service.RedisSyncService,
+	listingCache service.ListingCacheService,
 ) DoctorProfileUsecase {
 	return &doctorProfileUsecase{
-		db:                db,
-		log:               log,
-		userRepo:          userRepo,
-		doctorProfileRepo: doctorProfileRepo,
-		auditService:      auditService,
+		db:                 db,
+		log:                log,
+		userRepo:           userRepo,
+		doctorProfileRepo:  doctorProfileRepo,
+		waitFeedbackRepo:   waitFeedbackRepo,
+		auditService:       auditService,
+		fileStorage:        fileStorage,
+		reviewService:      reviewService,
+		specializationRepo: specializationRepo,
+		specDefaultRepo:    specDefaultRepo,
+		cfg:                cfg,
+		scheduleRepo:       scheduleRepo,
+		redisSyncService:   redisSyncService,
+		listingCache:       listingCache,
 	}
 }
 
@@ -69,16 +118,39 @@ func (u *doctorProfileUsecase) CreateDoctor(ctx context.Context, req *dto.Create
 		return nil, err
 	}
 
+	specialization, err := u.findSpecialization(tx, req.SpecializationID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Without a fee of their own, doctors start at the fee of their specialization
+	var consultationFee int64
+	if req.ConsultationFee != nil {
+		consultationFee = *req.ConsultationFee
+	} else {
+		defaults, err := u.specDefaultRepo.FindBySpecializationID(tx, specialization.ID)
+		if err != nil {
+			u.log.Warnf("Failed to find specialization defaults: %+v", err)
+			return nil, err
+		}
+		if defaults != nil {
+			consultationFee = defaults.Fee
+		}
+	}
+
 	// Create user with doctor profile in single insert using GORM association
 	doctorProfile := &entity.DoctorProfile{
-		STRNumber:      req.STRNumber,
-		Specialization: req.Specialization,
-		Biography:      req.Biography,
+		STRNumber:        req.STRNumber,
+		SpecializationID: specialization.ID,
+		Biography:        req.Biography,
+		ConsultationFee:  consultationFee,
 		User: entity.User{
 			Email:    req.Email,
 			Password: string(hashedPassword),
 			FullName: req.FullName,
 			RoleID:   entity.RoleIDDoctor,
+			// Created by an admin: the doctor replaces the password on first login
+			MustChangePassword: true,
 		},
 	}
 	if err := u.doctorProfileRepo.Create(tx, doctorProfile); err != nil {
@@ -94,6 +166,7 @@ func (u *doctorProfileUsecase) CreateDoctor(ctx context.Context, req *dto.Create
 		}
 		return nil, err
 	}
+	doctorProfile.Specialization = specialization
 
 	// Audit log - create doctor
 	userID, _ := middleware.GetUserIDFromContext(ctx)
@@ -106,6 +179,7 @@ func (u *doctorProfileUsecase) CreateDoctor(ctx context.Context, req *dto.Create
 		u.log.Warnf("Failed commit transaction: %+v", err)
 		return nil, err
 	}
+	u.listingCache.Invalidate(ctx, service.ListingDoctors)
 
 	return converter.DoctorProfileToResponse(doctorProfile), nil
 }
@@ -121,22 +195,105 @@ func (u *doctorProfileUsecase) GetDoctor(ctx context.Context, userID uuid.UUID)
 		return nil, ErrDoctorNotFound
 	}
 
-	return converter.DoctorProfileToResponse(profile), nil
+	doctors := []dto.DoctorResponse{*converter.DoctorProfileToResponse(profile)}
+	u.applyWaitStats(ctx, doctors)
+	u.applyRatingStats(ctx, doctors)
+
+	return &doctors[0], nil
 }
 
-func (u *doctorProfileUsecase) GetAllDoctors(ctx context.Context) (*dto.DoctorListResponse, error) {
-	profiles, err := u.doctorProfileRepo.FindAll(u.db)
+// GetAllDoctors lists the doctors, only those of the specialization (slug) when given.
+// An unknown specialization lists no doctors.
+// GetPublicDoctor returns the profile of an active doctor with their next scheduleLimit schedules
+// that accept bookings and have remaining quota. Deactivated doctors are not found.
+func (u *doctorProfileUsecase) GetPublicDoctor(ctx context.Context, doctorID uuid.UUID, scheduleLimit int) (*dto.DoctorDetailResponse, error) {
+	profile, err := u.doctorProfileRepo.FindByUserID(u.db.WithContext(ctx), doctorID)
 	if err != nil {
-		u.log.Warnf("Failed to find all doctor profiles: %+v", err)
+		u.log.Warnf("Failed to find doctor profile: %+v", err)
 		return nil, err
 	}
+	if profile == nil || profile.User.IsActive == nil || !*profile.User.IsActive {
+		return nil, ErrDoctorNotFound
+	}
+
+	doctors := []dto.DoctorResponse{*converter.DoctorProfileToResponse(profile)}
+	u.applyWaitStats(ctx, doctors)
+	u.applyRatingStats(ctx, doctors)
+
+	schedules, err := findBookableSchedules(ctx, u.db, u.cfg, u.scheduleRepo, u.redisSyncService, doctorID, scheduleLimit)
+	if err != nil {
+		u.log.Warnf("Failed to find bookable schedules of doctor %s: %+v", doctorID, err)
+		return nil, err
+	}
+
+	return &dto.DoctorDetailResponse{
+		DoctorResponse:    doctors[0],
+		UpcomingSchedules: schedules,
+	}, nil
+}
+
+// GetAllDoctors returns one page of the doctors matched by filter (including deactivated ones
+// unless filtered out), and the total count.
+func (u *doctorProfileUsecase) GetAllDoctors(ctx context.Context, filter *dto.DoctorFilter, page, limit int) (*dto.DoctorListResponse, int64, error) {
+	if !entity.IsValidDoctorSort(filter.Sort) {
+		return nil, 0, ErrInvalidDoctorSort
+	}
+
+	return u.findDoctors(ctx, &entity.DoctorFilter{
+		Specialization: entity.SpecializationSlug(filter.Specialization),
+		Active:         filter.Active,
+		Search:         filter.Search,
+		Sort:           filter.Sort,
+	}, page, limit)
+}
+
+// GetPublicDoctors returns one page of the active doctors matched by filter, and the total count.
+// The active filter of the request is ignored: deactivated doctors are never listed publicly.
+// Pages are cached briefly in Redis (see ListingCacheService), doctor changes invalidate them.
+func (u *doctorProfileUsecase) GetPublicDoctors(ctx context.Context, filter *dto.DoctorFilter, page, limit int) (*dto.DoctorListResponse, int64, error) {
+	if !entity.IsValidDoctorSort(filter.Sort) {
+		return nil, 0, ErrInvalidDoctorSort
+	}
+
+	active := true
+	entityFilter := &entity.DoctorFilter{
+		Specialization: entity.SpecializationSlug(filter.Specialization),
+		Active:         &active,
+		Search:         strings.ToLower(filter.Search),
+		Sort:           filter.Sort,
+	}
+
+	query := fmt.Sprintf("specialization=%s&search=%s&sort=%s&page=%d&limit=%d",
+		entityFilter.Specialization, entityFilter.Search, entityFilter.Sort, page, limit)
+	var cached dto.DoctorListResponse
+	if u.listingCache.Get(ctx, service.ListingDoctors, query, &cached) {
+		return &cached, int64(cached.Total), nil
+	}
+
+	doctors, total, err := u.findDoctors(ctx, entityFilter, page, limit)
+	if err != nil {
+		return nil, 0, err
+	}
+	u.listingCache.Set(ctx, service.ListingDoctors, query, doctors)
+	return doctors, total, nil
+}
+
+// findDoctors loads one page of doctors with their wait and rating stats
+func (u *doctorProfileUsecase) findDoctors(ctx context.Context, filter *entity.DoctorFilter, page, limit int) (*dto.DoctorListResponse, int64, error) {
+	profiles, total, err := u.doctorProfileRepo.FindAll(u.db.WithContext(ctx), filter, page, limit)
+	if err != nil {
+		u.log.Warnf("Failed to find doctor profiles: %+v", err)
+		return nil, 0, err
+	}
 
 	doctors := converter.DoctorProfilesToResponses(profiles)
+	u.applyWaitStats(ctx, doctors)
+	u.applyRatingStats(ctx, doctors)
 
 	return &dto.DoctorListResponse{
 		Doctors: doctors,
-		Total:   len(doctors),
-	}, nil
+		Total:   int(total),
+	}, total, nil
 }
 
 func (u *doctorProfileUsecase) UpdateDoctor(ctx context.Context, userID uuid.UUID, req *dto.UpdateDoctorRequest) (*dto.DoctorResponse, error) {
@@ -174,12 +331,20 @@ func (u *doctorProfileUsecase) UpdateDoctor(ctx context.Context, userID uuid.UUI
 	if req.STRNumber != "" {
 		profile.STRNumber = req.STRNumber
 	}
-	if req.Specialization != "" {
-		profile.Specialization = req.Specialization
+	if req.SpecializationID != 0 {
+		specialization, err := u.findSpecialization(tx, req.SpecializationID)
+		if err != nil {
+			return nil, err
+		}
+		profile.SpecializationID = specialization.ID
+		profile.Specialization = specialization
 	}
 	if req.Biography != "" {
 		profile.Biography = req.Biography
 	}
+	if req.ConsultationFee != nil {
+		profile.ConsultationFee = *req.ConsultationFee
+	}
 
 	// Update profile
 	if err := u.doctorProfileRepo.Update(tx, profile); err != nil {
@@ -201,6 +366,7 @@ func (u *doctorProfileUsecase) UpdateDoctor(ctx context.Context, userID uuid.UUI
 		u.log.Warnf("Failed commit transaction: %+v", err)
 		return nil, err
 	}
+	u.listingCache.Invalidate(ctx, service.ListingDoctors, service.ListingSchedules)
 
 	return converter.DoctorProfileToResponse(profile), nil
 }
@@ -266,6 +432,7 @@ func (u *doctorProfileUsecase) UpdateSelfProfile(ctx context.Context, userID uui
 		u.log.Warnf("Failed commit transaction: %+v", err)
 		return nil, err
 	}
+	u.listingCache.Invalidate(ctx, service.ListingDoctors)
 
 	return converter.DoctorProfileToResponse(profile), nil
 }
@@ -306,6 +473,180 @@ func (u *doctorProfileUsecase) DeleteDoctor(ctx context.Context, userID uuid.UUI
 		u.log.Warnf("Failed commit transaction: %+v", err)
 		return err
 	}
+	u.listingCache.Invalidate(ctx, service.ListingDoctors, service.ListingSchedules)
+
+	u.deletePhotoFile(ctx, profile.PhotoKey)
+	return nil
+}
+
+// UpdatePhoto replaces the profile photo of the doctor. The image is cropped to a
+// square, scaled down to doctorPhotoSize and stored under a new key, so cached
+// copies of the old photo are never served for the new one.
+func (u *doctorProfileUsecase) UpdatePhoto(ctx context.Context, doctorID uuid.UUID, data []byte) (*dto.DoctorResponse, error) {
+	photo, err := imaging.Square(data, doctorPhotoSize)
+	if err != nil {
+		switch err {
+		case imaging.ErrUnsupportedFormat:
+			return nil, ErrInvalidDoctorPhoto
+		case imaging.ErrTooLarge:
+			return nil, ErrDoctorPhotoTooBig
+		}
+		u.log.Warnf("Failed to process doctor photo: %+v", err)
+		return nil, err
+	}
+
+	profile, err := u.doctorProfileRepo.FindByUserID(u.db.WithContext(ctx), doctorID)
+	if err != nil {
+		u.log.Warnf("Failed to find doctor profile: %+v", err)
+		return nil, err
+	}
+	if profile == nil {
+		return nil, ErrDoctorNotFound
+	}
+
+	key := fmt.Sprintf("doctors/%s/%s.jpg", doctorID, uuid.NewString())
+	if err := u.fileStorage.Put(ctx, key, photo, imaging.ContentType); err != nil {
+		u.log.Warnf("Failed to store photo of doctor %s: %+v", doctorID, err)
+		return nil, err
+	}
+
+	oldKey := profile.PhotoKey
+	if err := u.setPhoto(ctx, profile, key, u.fileStorage.URL(key)); err != nil {
+		u.deletePhotoFile(ctx, key)
+		return nil, err
+	}
+
+	u.deletePhotoFile(ctx, oldKey)
+	return converter.DoctorProfileToResponse(profile), nil
+}
+
+// DeletePhoto removes the profile photo of the doctor
+func (u *doctorProfileUsecase) DeletePhoto(ctx context.Context, doctorID uuid.UUID) (*dto.DoctorResponse, error) {
+	profile, err := u.doctorProfileRepo.FindByUserID(u.db.WithContext(ctx), doctorID)
+	if err != nil {
+		u.log.Warnf("Failed to find doctor profile: %+v", err)
+		return nil, err
+	}
+	if profile == nil {
+		return nil, ErrDoctorNotFound
+	}
+	if profile.PhotoKey == "" {
+		return converter.DoctorProfileToResponse(profile), nil
+	}
+
+	oldKey := profile.PhotoKey
+	if err := u.setPhoto(ctx, profile, "", ""); err != nil {
+		return nil, err
+	}
+
+	u.deletePhotoFile(ctx, oldKey)
+	return converter.DoctorProfileToResponse(profile), nil
+}
+
+// setPhoto links the stored photo to the profile, audited as done by the caller
+func (u *doctorProfileUsecase) setPhoto(ctx context.Context, profile *entity.DoctorProfile, key, url string) error {
+	tx := u.db.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	oldURL := profile.PhotoURL
+	if err := u.doctorProfileRepo.UpdatePhoto(tx, profile.UserID, key, url); err != nil {
+		u.log.Warnf("Failed to update photo of doctor %s: %+v", profile.UserID, err)
+		return err
+	}
+	profile.PhotoKey = key
+	profile.PhotoURL = url
 
+	actorID, _ := middleware.GetUserIDFromContext(ctx)
+	if err := u.auditService.LogUpdate(ctx, tx, &actorID, entity.AuditActionDoctorPhoto, "doctor_profile", profile.UserID.String(),
+		entity.JSON{"photo_url": oldURL},
+		entity.JSON{"photo_url": url},
+	); err != nil {
+		u.log.Warnf("Failed to create audit log: %+v", err)
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		u.log.Warnf("Failed commit transaction: %+v", err)
+		return err
+	}
+	u.listingCache.Invalidate(ctx, service.ListingDoctors, service.ListingSchedules)
 	return nil
 }
+
+// deletePhotoFile removes a photo no profile links anymore. Failures only leave an
+// orphaned file behind, so they are logged.
+func (u *doctorProfileUsecase) deletePhotoFile(ctx context.Context, key string) {
+	if key == "" {
+		return
+	}
+	if err := u.fileStorage.Delete(ctx, key); err != nil {
+		u.log.Warnf("Failed to delete photo %s: %+v", key, err)
+	}
+}
+
+// findSpecialization returns the specialization a doctor is assigned to
+func (u *doctorProfileUsecase) findSpecialization(tx *gorm.DB, id int) (*entity.Specialization, error) {
+	specialization, err := u.specializationRepo.FindByID(tx, id)
+	if err != nil {
+		u.log.Warnf("Failed to find specialization %d: %+v", id, err)
+		return nil, err
+	}
+	if specialization == nil {
+		return nil, ErrSpecializationNotFound
+	}
+	return specialization, nil
+}
+
+// applyWaitStats fills average wait time from patient feedback into the doctor responses.
+// Fail-safe: doctors are still returned without wait stats if the lookup fails.
+func (u *doctorProfileUsecase) applyWaitStats(ctx context.Context, doctors []dto.DoctorResponse) {
+	doctorIDs := make([]uuid.UUID, len(doctors))
+	for i, doctor := range doctors {
+		doctorIDs[i] = doctor.ID
+	}
+
+	stats, err := u.waitFeedbackRepo.AverageByDoctorIDs(u.db.WithContext(ctx), doctorIDs)
+	if err != nil {
+		u.log.Warnf("Failed to get wait stats for doctors: %+v", err)
+		return
+	}
+
+	statByDoctor := make(map[uuid.UUID]entity.DoctorWaitStat, len(stats))
+	for _, stat := range stats {
+		statByDoctor[stat.DoctorID] = stat
+	}
+
+	for i := range doctors {
+		stat, ok := statByDoctor[doctors[i].ID]
+		if !ok {
+			continue
+		}
+		average := math.Round(stat.AverageWaitMinutes*10) / 10
+		doctors[i].AverageWaitMinutes = &average
+		doctors[i].WaitFeedbackCount = stat.FeedbackCount
+	}
+}
+
+// applyRatingStats fills the average rating from patient reviews into the doctor responses.
+// Fail-safe: doctors are still returned without ratings if the lookup fails.
+func (u *doctorProfileUsecase) applyRatingStats(ctx context.Context, doctors []dto.DoctorResponse) {
+	doctorIDs := make([]uuid.UUID, len(doctors))
+	for i, doctor := range doctors {
+		doctorIDs[i] = doctor.ID
+	}
+
+	stats, err := u.reviewService.RatingStats(ctx, doctorIDs)
+	if err != nil {
+		u.log.Warnf("Failed to get rating stats for doctors: %+v", err)
+		return
+	}
+
+	for i := range doctors {
+		stat, ok := stats[doctors[i].ID]
+		if !ok || stat.ReviewCount == 0 {
+			continue
+		}
+		average := math.Round(stat.AverageRating*10) / 10
+		doctors[i].AverageRating = &average
+		doctors[i].ReviewCount = stat.ReviewCount
+	}
+}
@@ -0,0 +1,125 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+
+	"go-template-clean-architecture/internal/converter"
+	"go-template-clean-architecture/internal/delivery/dto"
+	"go-template-clean-architecture/internal/delivery/http/middleware"
+	"go-template-clean-architecture/internal/domain/entity"
+	"go-template-clean-architecture/internal/domain/repository"
+	"go-template-clean-architecture/internal/service"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrReviewVisitNotCompleted = errors.New("reviews are only accepted after the visit")
+	ErrReviewExists            = errors.New("review already submitted for this booking")
+)
+
+// DoctorReviewUsecase manages the patients' ratings of their visits and the public
+// review listing of doctors. Ratings and reviews are served through the review cache.
+type DoctorReviewUsecase interface {
+	SubmitReview(ctx context.Context, bookingID uuid.UUID, req *dto.DoctorReviewRequest) (*dto.DoctorReviewResponse, error)
+	GetDoctorReviews(ctx context.Context, doctorID uuid.UUID, page, limit int) ([]dto.DoctorReviewItemResponse, int64, error)
+}
+
+type doctorReviewUsecase struct {
+	db                *gorm.DB
+	log               *logrus.Logger
+	reviewRepo        repository.DoctorReviewRepository
+	bookingRepo       repository.BookingRepository
+	doctorProfileRepo repository.DoctorProfileRepository
+	reviewService     service.DoctorReviewService
+}
+
+func NewDoctorReviewUsecase(
+	db *gorm.DB,
+	log *logrus.Logger,
+	reviewRepo repository.DoctorReviewRepository,
+	bookingRepo repository.BookingRepository,
+	doctorProfileRepo repository.DoctorProfileRepository,
+	reviewService service.DoctorReviewService,
+) DoctorReviewUsecase {
+	return &doctorReviewUsecase{
+		db:                db,
+		log:               log,
+		reviewRepo:        reviewRepo,
+		bookingRepo:       bookingRepo,
+		doctorProfileRepo: doctorProfileRepo,
+		reviewService:     reviewService,
+	}
+}
+
+// SubmitReview records the patient's rating of a visit, once per booking.
+// The booking must be active and its queue number called (the patient was seen).
+func (u *doctorReviewUsecase) SubmitReview(ctx context.Context, bookingID uuid.UUID, req *dto.DoctorReviewRequest) (*dto.DoctorReviewResponse, error) {
+	userID, ok := middleware.GetUserIDFromContext(ctx)
+	if !ok {
+		return nil, errors.New("user not found in context")
+	}
+
+	booking, err := u.bookingRepo.FindByID(u.db.WithContext(ctx), bookingID)
+	if err != nil {
+		u.log.Warnf("Failed to find booking %s: %+v", bookingID, err)
+		return nil, err
+	}
+	if booking == nil {
+		return nil, ErrBookingNotFound
+	}
+	if booking.PatientID != userID {
+		return nil, ErrBookingNotOwned
+	}
+	if booking.IsCancelled() {
+		return nil, ErrBookingAlreadyCancelled
+	}
+	if !booking.IsCalled() {
+		return nil, ErrReviewVisitNotCompleted
+	}
+
+	review := &entity.DoctorReview{
+		BookingID:  booking.ID,
+		PatientID:  booking.PatientID,
+		DoctorID:   booking.Schedule.DoctorID,
+		ScheduleID: booking.ScheduleID,
+		Rating:     req.Rating,
+		Comment:    req.Comment,
+	}
+	if err := u.reviewRepo.Create(u.db.WithContext(ctx), review); err != nil {
+		if isDuplicateKeyError(err, "doctor_reviews_pkey") {
+			return nil, ErrReviewExists
+		}
+		u.log.Warnf("Failed to create review for booking %s: %+v", booking.ID, err)
+		return nil, err
+	}
+
+	// A stale cache only delays the review until the cached entries expire
+	if err := u.reviewService.Invalidate(ctx, review.DoctorID); err != nil {
+		u.log.Warnf("Failed to invalidate cached reviews of doctor %s: %+v", review.DoctorID, err)
+	}
+
+	return converter.DoctorReviewToResponse(review), nil
+}
+
+// GetDoctorReviews lists the reviews of a doctor, newest first
+func (u *doctorReviewUsecase) GetDoctorReviews(ctx context.Context, doctorID uuid.UUID, page, limit int) ([]dto.DoctorReviewItemResponse, int64, error) {
+	profile, err := u.doctorProfileRepo.FindByUserID(u.db.WithContext(ctx), doctorID)
+	if err != nil {
+		u.log.Warnf("Failed to find doctor profile: %+v", err)
+		return nil, 0, err
+	}
+	if profile == nil {
+		return nil, 0, ErrDoctorNotFound
+	}
+
+	items, total, err := u.reviewService.Reviews(ctx, doctorID, page, limit)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return converter.DoctorReviewItemsToResponses(items), total, nil
+}
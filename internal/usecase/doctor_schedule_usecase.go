@@ -3,15 +3,20 @@ package usecase
 import (
 	"context"
 	"errors"
+	"fmt"
+	"sort"
 	"strconv"
+	"strings"
 	"time"
 
+	"go-template-clean-architecture/config"
 	"go-template-clean-architecture/internal/converter"
 	"go-template-clean-architecture/internal/delivery/dto"
 	"go-template-clean-architecture/internal/delivery/http/middleware"
 	"go-template-clean-architecture/internal/domain/entity"
 	"go-template-clean-architecture/internal/domain/repository"
 	"go-template-clean-architecture/internal/service"
+	"go-template-clean-architecture/pkg/ical"
 
 	"github.com/google/uuid"
 	"github.com/sirupsen/logrus"
@@ -19,52 +24,187 @@ import (
 )
 
 var (
-	ErrScheduleNotFound    = errors.New("schedule not found")
-	ErrInvalidScheduleDate = errors.New("invalid schedule date format, use YYYY-MM-DD")
-	ErrInvalidTimeFormat   = errors.New("invalid time format, use HH:MM")
+	ErrScheduleNotFound         = errors.New("schedule not found")
+	ErrInvalidScheduleDate      = errors.New("invalid schedule date format, use YYYY-MM-DD")
+	ErrInvalidTimeFormat        = errors.New("invalid time format, use HH:MM")
+	ErrScheduleDefaultsMissing  = errors.New("total_quota and end_time are required when no specialization defaults exist")
+	ErrScheduleExceedsDay       = errors.New("schedule end time exceeds the schedule date")
+	ErrScheduleNotOwned         = errors.New("schedule does not belong to you")
+	ErrAlreadyCheckedIn         = errors.New("already checked in for this schedule")
+	ErrCheckInNotToday          = errors.New("check-in is only allowed on the schedule date")
+	ErrInvalidTargetSchedule    = errors.New("target schedule must be a different, upcoming schedule of the same doctor")
+	ErrTargetScheduleFull       = errors.New("target schedule does not have enough remaining quota")
+	ErrCallNotToday             = errors.New("queue can only be called on the schedule date")
+	ErrQueueEmpty               = errors.New("no more patients waiting in the queue")
+	ErrQueueCallConflict        = errors.New("queue was updated concurrently, try again")
+	ErrSlotMinutesMissing       = errors.New("slot_minutes is required when no specialization defaults exist")
+	ErrScheduleShorterThanSlot  = errors.New("schedule is shorter than one time slot")
+	ErrSlotScheduleLocked       = errors.New("start time, end time, quota and breaks of a time-slot schedule cannot be changed")
+	ErrNotSlotSchedule          = errors.New("schedule does not use time-slot booking")
+	ErrScheduleOnHoliday        = errors.New("schedule date is a clinic holiday")
+	ErrInvalidScheduleFilter    = errors.New("invalid date filter, use YYYY-MM-DD with start_at not after end_at")
+	ErrSameCopyWeek             = errors.New("source and target week must be different")
+	ErrScheduleHasBookings      = errors.New("schedule has active bookings, pass force=true to cancel them")
+	ErrScheduleHasHistory       = errors.New("schedule has cancelled bookings, pass force=true to delete them with the schedule")
+	ErrProposalInPast           = errors.New("proposed schedule date is in the past")
+	ErrScheduleOverlaps         = errors.New("schedule overlaps another schedule of the doctor")
+	ErrScheduleNotPending       = errors.New("schedule is not pending approval")
+	ErrInvalidScheduleBreak     = errors.New("breaks must end after they start, lie inside the schedule and not overlap")
+	ErrInvalidQuotaDateRange    = errors.New("invalid date range, use YYYY-MM-DD with start_date not after end_date")
+	ErrInvalidAvailabilityMonth = errors.New("invalid month format, use YYYY-MM")
 )
 
+// maxQueueCallInterval drops call intervals longer than this from the rolling
+// average (breaks, doctor stepping out) so they do not skew estimated waits.
+const maxQueueCallInterval = 3 * time.Hour
+
+// calendarFeedDays is how far ahead the iCalendar feed lists schedules
+const calendarFeedDays = 90
+
+// nextAvailableScanDays is how far ahead bookable schedules are searched
+// when the advance booking window is unlimited
+const nextAvailableScanDays = 90
+
 type DoctorScheduleUsecase interface {
 	CreateSchedule(ctx context.Context, req *dto.CreateScheduleRequest) (*dto.ScheduleResponse, error)
+	ProposeSchedule(ctx context.Context, req *dto.ProposeScheduleRequest) (*dto.ScheduleResponse, error)
+	GetPendingProposals(ctx context.Context, page, limit int) (*dto.ScheduleListResponse, int64, error)
+	ApproveSchedule(ctx context.Context, scheduleID int) (*dto.ScheduleResponse, error)
+	RejectSchedule(ctx context.Context, scheduleID int, req *dto.RejectScheduleRequest) (*dto.ScheduleResponse, error)
 	GetSchedule(ctx context.Context, scheduleID int) (*dto.ScheduleResponse, error)
-	GetSchedulesByDoctor(ctx context.Context, doctorID uuid.UUID) (*dto.ScheduleListResponse, error)
-	GetAllSchedules(ctx context.Context) (*dto.ScheduleListResponse, error)
-	GetPublicSchedules(ctx context.Context, filter *dto.PublicScheduleFilter) (*dto.ScheduleListResponse, error)
+	GetScheduleHistory(ctx context.Context, scheduleID int) (*dto.ScheduleHistoryResponse, error)
+	GetSchedulesByDoctor(ctx context.Context, doctorID uuid.UUID, page, limit int) (*dto.ScheduleListResponse, int64, error)
+	GetAllSchedules(ctx context.Context, filter *dto.ScheduleFilter, page, limit int) (*dto.ScheduleListResponse, int64, error)
+	GetPublicSchedules(ctx context.Context, filter *dto.ScheduleFilter, page, limit int) (*dto.ScheduleListResponse, int64, error)
+	GetDoctorAvailability(ctx context.Context, doctorID uuid.UUID, month string) (*dto.DoctorAvailabilityResponse, error)
+	GetNextAvailable(ctx context.Context, doctorID uuid.UUID) (*dto.NextAvailableResponse, error)
 	UpdateSchedule(ctx context.Context, scheduleID int, req *dto.UpdateScheduleRequest) (*dto.ScheduleResponse, error)
-	DeleteSchedule(ctx context.Context, scheduleID int) error
+	DeleteSchedule(ctx context.Context, scheduleID int, force bool) (*dto.DeleteScheduleResponse, error)
+	CheckIn(ctx context.Context, scheduleID int) (*dto.ScheduleResponse, error)
+	SetBookingOpen(ctx context.Context, scheduleID int, isOpen bool) (*dto.ScheduleResponse, error)
+	SetMyBookingOpen(ctx context.Context, scheduleID int, isOpen bool) (*dto.ScheduleResponse, error)
+	GetPossiblyAbsentSchedules(ctx context.Context) (*dto.ScheduleListResponse, error)
+	GetHolidayFlaggedSchedules(ctx context.Context) (*dto.ScheduleListResponse, error)
+	GetLeaveFlaggedSchedules(ctx context.Context) (*dto.ScheduleListResponse, error)
+	ReassignBookings(ctx context.Context, scheduleID int, req *dto.ReassignBookingsRequest) (*dto.ReassignBookingsResponse, error)
+	CallNext(ctx context.Context, scheduleID int) (*dto.BookingResponse, error)
+	GetScheduleSlots(ctx context.Context, scheduleID int) (*dto.ScheduleSlotListResponse, error)
+	GetMyScheduleSummary(ctx context.Context, scheduleID int) (*dto.ScheduleSummaryResponse, error)
+	CopySchedules(ctx context.Context, req *dto.CopySchedulesRequest) (*dto.CopySchedulesResponse, error)
+	BulkUpdateQuota(ctx context.Context, req *dto.BulkUpdateQuotaRequest) (*dto.BulkUpdateQuotaResponse, error)
+	GetScheduleCalendar(ctx context.Context, doctorID uuid.UUID) ([]byte, error)
 }
 
 type doctorScheduleUsecase struct {
-	db               *gorm.DB
-	log              *logrus.Logger
-	scheduleRepo     repository.DoctorScheduleRepository
-	auditService     service.AuditService
-	redisSyncService *service.RedisSyncService
+	db                  *gorm.DB
+	log                 *logrus.Logger
+	cfg                 *config.Config
+	scheduleRepo        repository.DoctorScheduleRepository
+	specDefaultRepo     repository.SpecializationDefaultRepository
+	bookingRepo         repository.BookingRepository
+	queueStatRepo       repository.DoctorQueueStatRepository
+	slotRepo            repository.ScheduleSlotRepository
+	holidayRepo         repository.HolidayRepository
+	auditService        service.AuditService
+	redisSyncService    *service.RedisSyncService
+	formatService       service.FormatService
+	outboxService       *service.OutboxService
+	notificationService *service.NotificationService
+	versionRepo         repository.ScheduleVersionRepository
+	listingCache        service.ListingCacheService
+	leaveRepo           repository.DoctorLeaveRepository
+	paymentRepo         repository.PaymentRepository
 }
 
 func NewDoctorScheduleUsecase(
 	db *gorm.DB,
 	log *logrus.Logger,
+	cfg *config.Config,
 	scheduleRepo repository.DoctorScheduleRepository,
+	specDefaultRepo repository.SpecializationDefaultRepository,
+	bookingRepo repository.BookingRepository,
+	queueStatRepo repository.DoctorQueueStatRepository,
+	slotRepo repository.ScheduleSlotRepository,
+	holidayRepo repository.HolidayRepository,
 	auditService service.AuditService,
 	redisSyncService *service.RedisSyncService,
+	formatService service.FormatService,
+	outboxService *service.OutboxService,
+	notificationService *service.NotificationService,
+	versionRepo repository.ScheduleVersionRepository,
+	listingCache service.ListingCacheService,
+	leaveRepo repository.DoctorLeaveRepository,
+	paymentRepo repository.PaymentRepository,
 ) DoctorScheduleUsecase {
 	return &doctorScheduleUsecase{
-		db:               db,
-		log:              log,
-		scheduleRepo:     scheduleRepo,
-		auditService:     auditService,
-		redisSyncService: redisSyncService,
+		db:                  db,
+		log:                 log,
+		cfg:                 cfg,
+		scheduleRepo:        scheduleRepo,
+		specDefaultRepo:     specDefaultRepo,
+		bookingRepo:         bookingRepo,
+		queueStatRepo:       queueStatRepo,
+		slotRepo:            slotRepo,
+		holidayRepo:         holidayRepo,
+		auditService:        auditService,
+		redisSyncService:    redisSyncService,
+		formatService:       formatService,
+		outboxService:       outboxService,
+		notificationService: notificationService,
+		versionRepo:         versionRepo,
+		listingCache:        listingCache,
+		leaveRepo:           leaveRepo,
+		paymentRepo:         paymentRepo,
 	}
 }
 
 // CreateSchedule creates a new doctor schedule and syncs to Redis SYNCHRONOUSLY.
 //
+// Prefill:
+// - If TotalQuota or EndTime is omitted, the doctor's specialization defaults are used
+// - EndTime is derived as StartTime + TotalQuota * ConsultationMinutes, pushed back by the breaks
+//
+// Time-slot mode (booking_mode = slot):
+// - The schedule is split into SlotMinutes appointments (default: specialization ConsultationMinutes)
+// - TotalQuota becomes the number of slots; EndTime can be derived as StartTime + TotalQuota * SlotMinutes
+//
+// Breaks (e.g. lunch 12:00-13:00) must lie inside the schedule; no slots are generated in them.
+//
 // Sync Strategy:
 // - After DB commit, calls SyncScheduleQuota synchronously (no goroutine)
 // - Redis sync failure is logged but does not rollback DB (fail-safe)
 // - Admin reliability > speed, so we wait for Redis response
 func (u *doctorScheduleUsecase) CreateSchedule(ctx context.Context, req *dto.CreateScheduleRequest) (*dto.ScheduleResponse, error) {
+	return u.createSchedule(ctx, req, entity.ScheduleApprovalApproved, entity.AuditActionScheduleCreate)
+}
+
+// ProposeSchedule creates a schedule for the logged-in doctor that waits for admin approval.
+//
+// Prefill and time-slot mode work as in CreateSchedule. The proposal must not be in the past
+// or overlap another schedule of the doctor. It is not listed publicly, cannot be booked and
+// gets no Redis keys until an admin approves it (see ApproveSchedule).
+func (u *doctorScheduleUsecase) ProposeSchedule(ctx context.Context, req *dto.ProposeScheduleRequest) (*dto.ScheduleResponse, error) {
+	doctorID, ok := middleware.GetUserIDFromContext(ctx)
+	if !ok {
+		return nil, errors.New("user not found in context")
+	}
+
+	return u.createSchedule(ctx, &dto.CreateScheduleRequest{
+		DoctorID:     doctorID,
+		ScheduleDate: req.ScheduleDate,
+		StartTime:    req.StartTime,
+		EndTime:      req.EndTime,
+		TotalQuota:   req.TotalQuota,
+		BookingMode:  req.BookingMode,
+		SlotMinutes:  req.SlotMinutes,
+		MinLeadHours: req.MinLeadHours,
+		Breaks:       req.Breaks,
+	}, entity.ScheduleApprovalPending, entity.AuditActionSchedulePropose)
+}
+
+// createSchedule creates a schedule with the given approval status.
+// Only approved schedules are synced to Redis.
+func (u *doctorScheduleUsecase) createSchedule(ctx context.Context, req *dto.CreateScheduleRequest, approvalStatus string, auditAction string) (*dto.ScheduleResponse, error) {
 	tx := u.db.WithContext(ctx).Begin()
 	defer tx.Rollback()
 
@@ -75,22 +215,127 @@ func (u *doctorScheduleUsecase) CreateSchedule(ctx context.Context, req *dto.Cre
 		return nil, ErrInvalidScheduleDate
 	}
 
+	proposal := approvalStatus == entity.ScheduleApprovalPending
+	if proposal && scheduleDate.Before(time.Now().UTC().Truncate(24*time.Hour)) {
+		return nil, ErrProposalInPast
+	}
+
+	// Refuse blackout dates
+	if err := u.checkNotHoliday(tx, scheduleDate); err != nil {
+		return nil, err
+	}
+
 	// Validate time format
-	if _, err := time.Parse("15:04", req.StartTime); err != nil {
+	startTime, err := time.Parse("15:04", req.StartTime)
+	if err != nil {
 		u.log.Warnf("Failed to parse start time: %+v", err)
 		return nil, ErrInvalidTimeFormat
 	}
-	if _, err := time.Parse("15:04", req.EndTime); err != nil {
+
+	// Breaks extend a derived end time and are skipped by the time slots
+	breaks, err := toScheduleBreaks(req.Breaks)
+	if err != nil {
+		return nil, err
+	}
+	breakIntervals, err := breaks.Intervals(startTime, time.UTC)
+	if err != nil {
+		return nil, ErrInvalidTimeFormat
+	}
+
+	totalQuota := req.TotalQuota
+	endTime := req.EndTime
+
+	bookingMode := req.BookingMode
+	if bookingMode == "" {
+		bookingMode = entity.BookingModeQueue
+	}
+	if bookingMode == entity.BookingModeSlot && endTime == "" && totalQuota > 0 && req.SlotMinutes > 0 {
+		end := entity.AddWorkingTime(startTime, time.Duration(totalQuota*req.SlotMinutes)*time.Minute, breakIntervals)
+		if end.Day() != startTime.Day() {
+			return nil, ErrScheduleExceedsDay
+		}
+		endTime = end.Format("15:04")
+	}
+
+	// Prefill omitted fields from specialization defaults (slot mode derives its quota from the slots)
+	if (totalQuota == 0 && bookingMode != entity.BookingModeSlot) || endTime == "" {
+		defaults, err := u.specDefaultRepo.FindByDoctorID(tx, req.DoctorID)
+		if err != nil {
+			u.log.Warnf("Failed to find specialization defaults: %+v", err)
+			return nil, err
+		}
+		if defaults == nil {
+			return nil, ErrScheduleDefaultsMissing
+		}
+
+		if totalQuota == 0 {
+			totalQuota = defaults.DefaultQuota
+		}
+		if endTime == "" {
+			end := entity.AddWorkingTime(startTime, time.Duration(totalQuota*defaults.ConsultationMinutes)*time.Minute, breakIntervals)
+			if end.Day() != startTime.Day() {
+				return nil, ErrScheduleExceedsDay
+			}
+			endTime = end.Format("15:04")
+		}
+	}
+
+	end, err := time.Parse("15:04", endTime)
+	if err != nil {
 		u.log.Warnf("Failed to parse end time: %+v", err)
 		return nil, ErrInvalidTimeFormat
 	}
+	if err := validateScheduleBreaks(breaks, startTime, end); err != nil {
+		return nil, err
+	}
+
+	// Split into appointment slots (slot mode)
+	var slots []entity.ScheduleSlot
+	slotMinutes := 0
+	if bookingMode == entity.BookingModeSlot {
+		slotMinutes = req.SlotMinutes
+		if slotMinutes == 0 {
+			defaults, err := u.specDefaultRepo.FindByDoctorID(tx, req.DoctorID)
+			if err != nil {
+				u.log.Warnf("Failed to find specialization defaults: %+v", err)
+				return nil, err
+			}
+			if defaults == nil {
+				return nil, ErrSlotMinutesMissing
+			}
+			slotMinutes = defaults.ConsultationMinutes
+		}
+
+		slots = generateScheduleSlots(startTime, end, slotMinutes, breakIntervals)
+		if len(slots) == 0 {
+			return nil, ErrScheduleShorterThanSlot
+		}
+		totalQuota = len(slots)
+	}
 
 	schedule := &entity.DoctorSchedule{
-		DoctorID:     req.DoctorID,
-		ScheduleDate: scheduleDate,
-		StartTime:    req.StartTime,
-		EndTime:      req.EndTime,
-		TotalQuota:   req.TotalQuota,
+		DoctorID:       req.DoctorID,
+		ScheduleDate:   scheduleDate,
+		StartTime:      req.StartTime,
+		EndTime:        endTime,
+		TotalQuota:     totalQuota,
+		BookingMode:    bookingMode,
+		SlotMinutes:    slotMinutes,
+		MinLeadHours:   req.MinLeadHours,
+		Breaks:         breaks,
+		ApprovalStatus: approvalStatus,
+	}
+
+	// Doctors cannot propose over their own schedules
+	if proposal {
+		sameDay, err := u.scheduleRepo.FindByDoctorAndDateRange(tx, req.DoctorID, scheduleDate, scheduleDate)
+		if err != nil {
+			u.log.Warnf("Failed to find schedules of doctor %s: %+v", req.DoctorID, err)
+			return nil, err
+		}
+		if overlapsAny(schedule, sameDay) {
+			return nil, ErrScheduleOverlaps
+		}
 	}
 
 	if err := u.scheduleRepo.Create(tx, schedule); err != nil {
@@ -101,16 +346,33 @@ func (u *doctorScheduleUsecase) CreateSchedule(ctx context.Context, req *dto.Cre
 		return nil, err
 	}
 
-	// Audit log - create schedule
+	for i := range slots {
+		slots[i].ScheduleID = schedule.ID
+	}
+	if err := u.slotRepo.CreateBatch(tx, slots); err != nil {
+		u.log.Warnf("Failed to create schedule slots: %+v", err)
+		return nil, err
+	}
+
+	// Audit log - create or propose schedule
 	userID, _ := middleware.GetUserIDFromContext(ctx)
-	if err := u.auditService.LogCreate(ctx, tx, &userID, entity.AuditActionScheduleCreate, "doctor_schedule", strconv.Itoa(schedule.ID), converter.ScheduleToResponse(schedule)); err != nil {
+	if err := u.auditService.LogCreate(ctx, tx, &userID, auditAction, "doctor_schedule", strconv.Itoa(schedule.ID), converter.ScheduleToResponse(schedule)); err != nil {
 		u.log.Warnf("Failed to create audit log: %+v", err)
 	}
+	if err := u.recordVersion(tx, entity.NewScheduleVersion(schedule.ID, &userID, auditAction, nil, schedule)); err != nil {
+		return nil, err
+	}
 
 	if err := tx.Commit().Error; err != nil {
 		u.log.Warnf("Failed commit transaction: %+v", err)
 		return nil, err
 	}
+	u.listingCache.Invalidate(ctx, service.ListingSchedules)
+
+	if proposal {
+		u.log.Infof("Schedule %d proposed by doctor %s, pending approval", schedule.ID, schedule.DoctorID)
+		return converter.ScheduleToResponse(schedule), nil
+	}
 
 	// SYNCHRONOUS Redis sync - no goroutine
 	// Reliability > Speed for Admin operations
@@ -138,61 +400,185 @@ func (u *doctorScheduleUsecase) GetSchedule(ctx context.Context, scheduleID int)
 		return nil, ErrScheduleNotFound
 	}
 
-	return converter.ScheduleToResponse(schedule), nil
+	responses := u.schedulesToResponsesWithQuota(ctx, []entity.DoctorSchedule{*schedule})
+	return &responses[0], nil
 }
 
-func (u *doctorScheduleUsecase) GetSchedulesByDoctor(ctx context.Context, doctorID uuid.UUID) (*dto.ScheduleListResponse, error) {
-	schedules, err := u.scheduleRepo.FindByDoctorID(u.db, doctorID)
+// GetScheduleHistory returns every recorded change of a schedule, oldest first.
+// The history of a deleted schedule stays available.
+func (u *doctorScheduleUsecase) GetScheduleHistory(ctx context.Context, scheduleID int) (*dto.ScheduleHistoryResponse, error) {
+	db := u.db.WithContext(ctx)
+
+	versions, err := u.versionRepo.FindByScheduleID(db, scheduleID)
 	if err != nil {
-		u.log.Warnf("Failed to find schedules: %+v", err)
+		u.log.Warnf("Failed to find versions of schedule %d: %+v", scheduleID, err)
 		return nil, err
 	}
 
+	// Schedules created before the history was recorded have no versions yet
+	if len(versions) == 0 {
+		schedule, err := u.scheduleRepo.FindByID(db, scheduleID)
+		if err != nil {
+			u.log.Warnf("Failed to find schedule: %+v", err)
+			return nil, err
+		}
+		if schedule == nil {
+			return nil, ErrScheduleNotFound
+		}
+	}
+
+	return converter.ScheduleVersionsToHistory(scheduleID, versions), nil
+}
+
+// GetSchedulesByDoctor returns one page of a doctor's schedules and the total count.
+func (u *doctorScheduleUsecase) GetSchedulesByDoctor(ctx context.Context, doctorID uuid.UUID, page, limit int) (*dto.ScheduleListResponse, int64, error) {
+	schedules, total, err := u.scheduleRepo.FindByDoctorID(u.db, doctorID, page, limit)
+	if err != nil {
+		u.log.Warnf("Failed to find schedules: %+v", err)
+		return nil, 0, err
+	}
+
 	return &dto.ScheduleListResponse{
-		Schedules: converter.SchedulesToResponses(schedules),
-		Total:     len(schedules),
-	}, nil
+		Schedules: u.schedulesToResponsesWithQuota(ctx, schedules),
+		Total:     int(total),
+	}, total, nil
 }
 
-func (u *doctorScheduleUsecase) GetAllSchedules(ctx context.Context) (*dto.ScheduleListResponse, error) {
-	schedules, err := u.scheduleRepo.FindAll(u.db)
+// GetAllSchedules returns one page of the schedules of all doctors, including deactivated ones
+// (admin listing), and the total count.
+func (u *doctorScheduleUsecase) GetAllSchedules(ctx context.Context, filter *dto.ScheduleFilter, page, limit int) (*dto.ScheduleListResponse, int64, error) {
+	entityFilter, err := toEntityScheduleFilter(filter)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	schedules, total, err := u.scheduleRepo.FindAll(u.db, entityFilter, page, limit)
 	if err != nil {
 		u.log.Warnf("Failed to find all schedules: %+v", err)
-		return nil, err
+		return nil, 0, err
 	}
 
 	return &dto.ScheduleListResponse{
-		Schedules: converter.SchedulesToResponses(schedules),
-		Total:     len(schedules),
-	}, nil
+		Schedules: u.schedulesToResponsesWithQuota(ctx, schedules),
+		Total:     int(total),
+	}, total, nil
 }
 
-// GetPublicSchedules returns schedules only for active doctors.
-// Used by public-facing endpoints to hide schedules from deactivated doctors.
-func (u *doctorScheduleUsecase) GetPublicSchedules(ctx context.Context, filter *dto.PublicScheduleFilter) (*dto.ScheduleListResponse, error) {
-	// Convert DTO filter to entity filter
-	var entityFilter *entity.ScheduleFilter
-	if filter != nil {
-		entityFilter = &entity.ScheduleFilter{
-			StartAt:        filter.StartAt,
-			EndAt:          filter.EndAt,
-			DoctorName:     filter.DoctorName,
-			Specialization: filter.Specialization,
+// GetPublicSchedules returns one page of schedules of active doctors only, and the total count.
+// Used by public-facing endpoints to hide schedules from deactivated doctors. Schedules beyond
+// the advance booking window (BOOKING_MAX_ADVANCE_DAYS) are left out as well.
+//
+// Pages are cached in Redis for a few seconds (see ListingCacheService): schedule changes
+// invalidate them, the remaining quota of a cached page may lag behind bookings until it expires.
+func (u *doctorScheduleUsecase) GetPublicSchedules(ctx context.Context, filter *dto.ScheduleFilter, page, limit int) (*dto.ScheduleListResponse, int64, error) {
+	entityFilter, err := toEntityScheduleFilter(filter)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// Patients only see schedules inside the advance booking window
+	if last := lastBookableDate(u.cfg); last != "" {
+		if entityFilter == nil {
+			entityFilter = &entity.ScheduleFilter{}
+		}
+		if entityFilter.EndAt == "" || entityFilter.EndAt > last {
+			entityFilter.EndAt = last
 		}
 	}
 
-	schedules, err := u.scheduleRepo.FindAllWithActiveDoctor(u.db, entityFilter)
+	var cacheFilter entity.ScheduleFilter
+	if entityFilter != nil {
+		cacheFilter = *entityFilter
+	}
+	query := fmt.Sprintf("start_at=%s&end_at=%s&doctor_name=%s&specialization=%s&page=%d&limit=%d",
+		cacheFilter.StartAt, cacheFilter.EndAt, strings.ToLower(cacheFilter.DoctorName), cacheFilter.Specialization, page, limit)
+	var cached dto.ScheduleListResponse
+	if u.listingCache.Get(ctx, service.ListingSchedules, query, &cached) {
+		return &cached, int64(cached.Total), nil
+	}
+
+	schedules, total, err := u.scheduleRepo.FindAllWithActiveDoctor(u.db, entityFilter, page, limit)
 	if err != nil {
 		u.log.Warnf("Failed to find public schedules: %+v", err)
+		return nil, 0, err
+	}
+
+	result := &dto.ScheduleListResponse{
+		Schedules: u.schedulesToResponsesWithQuota(ctx, schedules),
+		Total:     int(total),
+	}
+	u.listingCache.Set(ctx, service.ListingSchedules, query, result)
+	return result, total, nil
+}
+
+// GetDoctorAvailability returns, for every day of the month, whether the doctor has a schedule
+// accepting bookings and how many slots those schedules have left.
+//
+// The schedules come from one query and their remaining quotas from a single Redis MGET
+// (DB fallback for missing keys, see RedisSyncService.GetRemainingQuotas). Inactive or unknown
+// doctors have no available days.
+func (u *doctorScheduleUsecase) GetDoctorAvailability(ctx context.Context, doctorID uuid.UUID, month string) (*dto.DoctorAvailabilityResponse, error) {
+	monthStart, err := time.Parse("2006-01", month)
+	if err != nil {
+		return nil, ErrInvalidAvailabilityMonth
+	}
+	monthEnd := monthStart.AddDate(0, 1, -1)
+
+	schedules, err := u.scheduleRepo.FindApprovedByActiveDoctorAndDateRange(u.db.WithContext(ctx), doctorID, monthStart, monthEnd)
+	if err != nil {
+		u.log.Warnf("Failed to find schedules of doctor %s: %+v", doctorID, err)
 		return nil, err
 	}
 
-	return &dto.ScheduleListResponse{
-		Schedules: converter.SchedulesToResponses(schedules),
-		Total:     len(schedules),
+	remaining, err := u.redisSyncService.GetRemainingQuotas(ctx, schedules)
+	if err != nil {
+		u.log.Warnf("Failed to get remaining quota for schedules of doctor %s: %+v", doctorID, err)
+		return nil, err
+	}
+
+	days := make([]dto.DayAvailabilityResponse, 0, monthEnd.Day())
+	byDate := make(map[string]int, monthEnd.Day())
+	for date := monthStart; !date.After(monthEnd); date = date.AddDate(0, 0, 1) {
+		byDate[date.Format("2006-01-02")] = len(days)
+		days = append(days, dto.DayAvailabilityResponse{Date: date.Format("2006-01-02")})
+	}
+
+	now := time.Now().In(u.cfg.App.Location)
+	for i := range schedules {
+		schedule := &schedules[i]
+		if !acceptsBookings(u.cfg, schedule, now) {
+			continue
+		}
+		day := &days[byDate[schedule.ScheduleDate.Format("2006-01-02")]]
+		day.HasOpenSchedule = true
+		if quota := remaining[schedule.ID]; quota > 0 {
+			day.RemainingSlots += quota
+		}
+	}
+
+	return &dto.DoctorAvailabilityResponse{
+		DoctorID: doctorID,
+		Month:    monthStart.Format("2006-01"),
+		Days:     days,
 	}, nil
 }
 
+// GetNextAvailable returns the earliest schedule of the doctor that accepts bookings and has
+// remaining quota (see findBookableSchedules). Inactive or unknown doctors have none.
+func (u *doctorScheduleUsecase) GetNextAvailable(ctx context.Context, doctorID uuid.UUID) (*dto.NextAvailableResponse, error) {
+	schedules, err := findBookableSchedules(ctx, u.db, u.cfg, u.scheduleRepo, u.redisSyncService, doctorID, 1)
+	if err != nil {
+		u.log.Warnf("Failed to find bookable schedules of doctor %s: %+v", doctorID, err)
+		return nil, err
+	}
+
+	result := &dto.NextAvailableResponse{DoctorID: doctorID}
+	if len(schedules) > 0 {
+		result.Schedule = &schedules[0]
+	}
+	return result, nil
+}
+
 // UpdateSchedule updates a schedule and syncs to Redis SYNCHRONOUSLY.
 //
 // Delta Strategy for TotalQuota changes:
@@ -218,9 +604,16 @@ func (u *doctorScheduleUsecase) UpdateSchedule(ctx context.Context, scheduleID i
 
 	// Capture old values for audit and delta calculation
 	oldValue := converter.ScheduleToResponse(schedule)
+	oldSchedule := *schedule
 	oldTotalQuota := schedule.TotalQuota
 	oldScheduleDate := schedule.ScheduleDate
 
+	// Slots are generated from the time range and breaks - only date and doctor may change
+	if schedule.IsSlotMode() && (req.StartTime != "" || req.EndTime != "" || (req.TotalQuota != nil && *req.TotalQuota != schedule.TotalQuota) ||
+		len(req.Breaks) > 0 || req.ClearBreaks) {
+		return nil, ErrSlotScheduleLocked
+	}
+
 	// Update fields
 	if req.DoctorID != uuid.Nil {
 		schedule.DoctorID = req.DoctorID
@@ -232,6 +625,13 @@ func (u *doctorScheduleUsecase) UpdateSchedule(ctx context.Context, scheduleID i
 			u.log.Warnf("Failed to parse schedule date: %+v", err)
 			return nil, ErrInvalidScheduleDate
 		}
+		if !scheduleDate.Equal(oldScheduleDate) {
+			if err := u.checkNotHoliday(tx, scheduleDate); err != nil {
+				return nil, err
+			}
+			// Moved off the holiday - resolves the flag
+			schedule.HolidayFlaggedAt = nil
+		}
 		schedule.ScheduleDate = scheduleDate
 	}
 	if req.StartTime != "" {
@@ -249,6 +649,37 @@ func (u *doctorScheduleUsecase) UpdateSchedule(ctx context.Context, scheduleID i
 		schedule.EndTime = req.EndTime
 	}
 
+	if req.MinLeadHours != nil {
+		schedule.MinLeadHours = req.MinLeadHours
+	} else if req.ClearMinLeadHours {
+		schedule.MinLeadHours = nil
+	}
+
+	if len(req.Breaks) > 0 {
+		breaks, err := toScheduleBreaks(req.Breaks)
+		if err != nil {
+			return nil, err
+		}
+		schedule.Breaks = breaks
+	} else if req.ClearBreaks {
+		schedule.Breaks = nil
+	}
+
+	// The breaks must still fit the (possibly changed) time range
+	if req.StartTime != "" || req.EndTime != "" || len(req.Breaks) > 0 {
+		start, err := schedule.StartDateTime(time.UTC)
+		if err != nil {
+			return nil, ErrInvalidTimeFormat
+		}
+		end, err := schedule.EndDateTime(time.UTC)
+		if err != nil {
+			return nil, ErrInvalidTimeFormat
+		}
+		if err := validateScheduleBreaks(schedule.Breaks, start, end); err != nil {
+			return nil, err
+		}
+	}
+
 	// Handle TotalQuota change with delta strategy
 	var quotaDelta int
 	quotaChanged := false
@@ -274,11 +705,36 @@ func (u *doctorScheduleUsecase) UpdateSchedule(ctx context.Context, scheduleID i
 	if err := u.auditService.LogUpdate(ctx, tx, &userID, entity.AuditActionScheduleUpdate, "doctor_schedule", strconv.Itoa(scheduleID), oldValue, newValue); err != nil {
 		u.log.Warnf("Failed to create audit log: %+v", err)
 	}
+	if err := u.recordVersion(tx, entity.NewScheduleVersion(scheduleID, &userID, entity.AuditActionScheduleUpdate, &oldSchedule, schedule)); err != nil {
+		return nil, err
+	}
+
+	// Date/time change → booked patients are notified by the outbox worker
+	if scheduleTimeChanged(&oldSchedule, schedule) {
+		if err := u.outboxService.Enqueue(tx, entity.OutboxEventScheduleUpdated, "doctor_schedule", strconv.Itoa(scheduleID), entity.ScheduleEventPayload{
+			ScheduleID:      scheduleID,
+			OldScheduleDate: oldValue.ScheduleDate,
+			OldStartTime:    oldValue.StartTime,
+			OldEndTime:      oldValue.EndTime,
+			ScheduleDate:    newValue.ScheduleDate,
+			StartTime:       newValue.StartTime,
+			EndTime:         newValue.EndTime,
+		}); err != nil {
+			u.log.Warnf("Failed to enqueue schedule event: %+v", err)
+			return nil, err
+		}
+	}
 
 	if err := tx.Commit().Error; err != nil {
 		u.log.Warnf("Failed commit transaction: %+v", err)
 		return nil, err
 	}
+	u.listingCache.Invalidate(ctx, service.ListingSchedules)
+
+	// Proposals have no Redis keys until they are approved
+	if !schedule.IsApproved() {
+		return converter.ScheduleToResponse(schedule), nil
+	}
 
 	// SYNCHRONOUS Redis sync - no goroutine
 	// Use detached context so Redis sync is not cancelled by HTTP request timeout
@@ -314,12 +770,19 @@ func (u *doctorScheduleUsecase) UpdateSchedule(ctx context.Context, scheduleID i
 	return converter.ScheduleToResponse(schedule), nil
 }
 
-// DeleteSchedule deletes a schedule and removes Redis keys SYNCHRONOUSLY.
+// DeleteSchedule soft-deletes a schedule and removes Redis keys SYNCHRONOUSLY.
+//
+// Schedules with active bookings are refused (ErrScheduleHasBookings with the count) unless
+// force is set, and so are schedules with only cancelled bookings left (ErrScheduleHasHistory).
+// Forcing cancels the active bookings like any other cancellation (pending payments closed,
+// booking.cancelled enqueued so paid fees are refunded) and notifies their patients. Every
+// booking of the schedule is then soft-deleted with it, as bookings cannot outlive their
+// schedule. The booking codes are kept in the audit log.
 //
 // Sync Strategy:
-// - After DB commit, calls DeleteScheduleKeys synchronously
+// - After DB commit, calls DeleteScheduleKeys synchronously (quota, queue, waitlist, time slots)
 // - Redis cleanup failure is logged but does not fail request (fail-safe)
-func (u *doctorScheduleUsecase) DeleteSchedule(ctx context.Context, scheduleID int) error {
+func (u *doctorScheduleUsecase) DeleteSchedule(ctx context.Context, scheduleID int, force bool) (*dto.DeleteScheduleResponse, error) {
 	tx := u.db.WithContext(ctx).Begin()
 	defer tx.Rollback()
 
@@ -327,37 +790,122 @@ func (u *doctorScheduleUsecase) DeleteSchedule(ctx context.Context, scheduleID i
 	schedule, err := u.scheduleRepo.FindByID(tx, scheduleID)
 	if err != nil {
 		u.log.Warnf("Failed to find schedule for delete: %+v", err)
-		return err
+		return nil, err
+	}
+	if schedule == nil {
+		return nil, ErrScheduleNotFound
 	}
 
-	var oldValue *dto.ScheduleResponse
-	if schedule != nil {
-		oldValue = converter.ScheduleToResponse(schedule)
+	active, err := u.bookingRepo.FindActiveByScheduleID(tx, scheduleID)
+	if err != nil {
+		u.log.Warnf("Failed to find active bookings of schedule %d: %+v", scheduleID, err)
+		return nil, err
+	}
+
+	result := &dto.DeleteScheduleResponse{
+		ScheduleID:     scheduleID,
+		ActiveBookings: len(active),
+	}
+	if len(active) > 0 && !force {
+		return result, ErrScheduleHasBookings
+	}
+	if !force {
+		bookings, err := u.bookingRepo.CountByScheduleID(tx, scheduleID)
+		if err != nil {
+			u.log.Warnf("Failed to count bookings of schedule %d: %+v", scheduleID, err)
+			return nil, err
+		}
+		if bookings > 0 {
+			// Only cancelled bookings are left
+			return result, ErrScheduleHasHistory
+		}
+	}
+
+	var oldValue interface{} = converter.ScheduleToResponse(schedule)
+	if force {
+		codes := make([]string, 0, len(active))
+		for _, b := range active {
+			affected, err := u.bookingRepo.CancelBooking(tx, b.ID, b.Version)
+			if err != nil {
+				u.log.Warnf("Failed to cancel booking %s: %+v", b.ID, err)
+				return nil, err
+			}
+			if affected == 0 {
+				return nil, ErrBookingVersionConflict
+			}
+			if _, err := u.paymentRepo.ClosePendingByBookingID(tx, b.ID, entity.PaymentStatusCancelled, "booking cancelled"); err != nil {
+				u.log.Warnf("Failed to cancel pending payment of booking %s: %+v", b.ID, err)
+				return nil, err
+			}
+			// Refunds a paid fee; the slot is not released - the schedule's Redis keys are removed below
+			if err := u.outboxService.Enqueue(tx, entity.OutboxEventBookingCancelled, "booking", b.ID.String(), entity.BookingEventPayload{
+				BookingID:    b.ID,
+				BookingCode:  b.BookingCode,
+				PatientID:    b.PatientID,
+				ScheduleID:   scheduleID,
+				QueueNumber:  b.QueueNumber,
+				SlotID:       b.SlotID,
+				CancelReason: entity.BookingCancelReasonSchedule,
+			}); err != nil {
+				u.log.Warnf("Failed to enqueue booking cancellation: %+v", err)
+				return nil, err
+			}
+			// Notified here, the booking.cancelled handler skips deleted schedules
+			if err := u.notificationService.Notify(ctx, tx, service.NotificationRequest{
+				PatientID: b.PatientID,
+				BookingID: &b.ID,
+				EventType: entity.OutboxEventBookingCancelled,
+				Message:   fmt.Sprintf("Your booking %s on %s was cancelled because the schedule was removed. Please book another schedule.", b.BookingCode, u.formatService.ScheduleSlot(schedule)),
+				DedupeKey: fmt.Sprintf("schedule_delete:%s", b.ID),
+				Push:      true,
+				InApp:     true,
+				Data: map[string]string{
+					entity.TemplateVarBookingCode:  b.BookingCode,
+					entity.TemplateVarDoctorName:   schedule.Doctor.User.FullName,
+					entity.TemplateVarScheduleSlot: u.formatService.ScheduleSlot(schedule),
+				},
+			}); err != nil {
+				u.log.Warnf("Failed to notify patient of booking %s: %+v", b.ID, err)
+				return nil, err
+			}
+			codes = append(codes, b.BookingCode)
+		}
+
+		removed, err := u.bookingRepo.DeleteByScheduleID(tx, scheduleID)
+		if err != nil {
+			u.log.Warnf("Failed to delete bookings of schedule %d: %+v", scheduleID, err)
+			return nil, err
+		}
+		result.CancelledBookings = len(active)
+		result.RemovedBookings = removed
+		oldValue = entity.JSON{"schedule": oldValue, "cancelled_bookings": codes, "removed_bookings": removed}
 	}
 
 	deleted, err := u.scheduleRepo.Delete(tx, scheduleID)
 	if err != nil {
 		u.log.Warnf("Failed to delete schedule: %+v", err)
-		return err
+		return nil, err
 	}
 
 	if deleted == 0 {
 		u.log.Warnf("Schedule not found")
-		return ErrScheduleNotFound
+		return nil, ErrScheduleNotFound
 	}
 
 	// Audit log - delete schedule
-	if oldValue != nil {
-		userID, _ := middleware.GetUserIDFromContext(ctx)
-		if err := u.auditService.LogDelete(ctx, tx, &userID, entity.AuditActionScheduleDelete, "doctor_schedule", strconv.Itoa(scheduleID), oldValue); err != nil {
-			u.log.Warnf("Failed to create audit log: %+v", err)
-		}
+	userID, _ := middleware.GetUserIDFromContext(ctx)
+	if err := u.auditService.LogDelete(ctx, tx, &userID, entity.AuditActionScheduleDelete, "doctor_schedule", strconv.Itoa(scheduleID), oldValue); err != nil {
+		u.log.Warnf("Failed to create audit log: %+v", err)
+	}
+	if err := u.recordVersion(tx, entity.NewScheduleVersion(scheduleID, &userID, entity.AuditActionScheduleDelete, schedule, nil)); err != nil {
+		return nil, err
 	}
 
 	if err := tx.Commit().Error; err != nil {
 		u.log.Warnf("Failed commit transaction: %+v", err)
-		return err
+		return nil, err
 	}
+	u.listingCache.Invalidate(ctx, service.ListingSchedules)
 
 	// SYNCHRONOUS Redis cleanup - no goroutine
 	// Use detached context so Redis cleanup is not cancelled by HTTP request timeout
@@ -369,8 +917,1321 @@ func (u *doctorScheduleUsecase) DeleteSchedule(ctx context.Context, scheduleID i
 		// Keys will expire via TTL anyway
 		u.log.Warnf("Failed to delete Redis keys for schedule %d (non-fatal): %+v", scheduleID, err)
 	} else {
-		u.log.Infof("Schedule %d deleted and Redis keys removed", scheduleID)
+		u.log.Infof("Schedule %d deleted and Redis keys removed (cancelled bookings: %d)", scheduleID, result.CancelledBookings)
 	}
 
-	return nil
+	return result, nil
+}
+
+// CheckIn records the doctor's arrival for their own schedule.
+// Check-in is the activity signal used by AbsenceMonitorService; it also lifts
+// the "possibly absent" booking pause.
+func (u *doctorScheduleUsecase) CheckIn(ctx context.Context, scheduleID int) (*dto.ScheduleResponse, error) {
+	doctorID, ok := middleware.GetUserIDFromContext(ctx)
+	if !ok {
+		return nil, errors.New("user not found in context")
+	}
+
+	tx := u.db.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	schedule, err := u.scheduleRepo.FindByID(tx, scheduleID)
+	if err != nil {
+		u.log.Warnf("Failed to find schedule: %+v", err)
+		return nil, err
+	}
+	if schedule == nil {
+		return nil, ErrScheduleNotFound
+	}
+	if schedule.DoctorID != doctorID {
+		return nil, ErrScheduleNotOwned
+	}
+	if schedule.DoctorCheckedInAt != nil {
+		return nil, ErrAlreadyCheckedIn
+	}
+
+	now := time.Now().In(u.cfg.App.Location)
+	if schedule.ScheduleDate.Format("2006-01-02") != now.Format("2006-01-02") {
+		return nil, ErrCheckInNotToday
+	}
+
+	affected, err := u.scheduleRepo.CheckIn(tx, scheduleID, doctorID, now)
+	if err != nil {
+		u.log.Warnf("Failed to check in schedule %d: %+v", scheduleID, err)
+		return nil, err
+	}
+	if affected == 0 {
+		return nil, ErrAlreadyCheckedIn
+	}
+	oldSchedule := *schedule
+	schedule.DoctorCheckedInAt = &now
+
+	// Audit log - doctor check-in
+	if err := u.auditService.LogCreate(ctx, tx, &doctorID, entity.AuditActionDoctorCheckIn, "doctor_schedule", strconv.Itoa(scheduleID), entity.JSON{
+		"checked_in_at":      now,
+		"was_flagged_absent": schedule.AbsenceFlaggedAt != nil,
+	}); err != nil {
+		u.log.Warnf("Failed to create audit log: %+v", err)
+	}
+	if err := u.recordVersion(tx, entity.NewScheduleVersion(scheduleID, &doctorID, entity.AuditActionDoctorCheckIn, &oldSchedule, schedule)); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		u.log.Warnf("Failed commit transaction: %+v", err)
+		return nil, err
+	}
+	u.listingCache.Invalidate(ctx, service.ListingSchedules)
+
+	return converter.ScheduleToResponse(schedule), nil
+}
+
+// SetBookingOpen opens or pauses booking on any schedule (admin)
+func (u *doctorScheduleUsecase) SetBookingOpen(ctx context.Context, scheduleID int, isOpen bool) (*dto.ScheduleResponse, error) {
+	return u.setBookingOpen(ctx, scheduleID, isOpen, false)
+}
+
+// SetMyBookingOpen opens or pauses booking on one of the logged-in doctor's schedules
+func (u *doctorScheduleUsecase) SetMyBookingOpen(ctx context.Context, scheduleID int, isOpen bool) (*dto.ScheduleResponse, error) {
+	return u.setBookingOpen(ctx, scheduleID, isOpen, true)
+}
+
+// setBookingOpen toggles the is_open flag. Existing bookings are kept; only new bookings
+// and waitlist joins are refused while the schedule is paused.
+func (u *doctorScheduleUsecase) setBookingOpen(ctx context.Context, scheduleID int, isOpen bool, ownerOnly bool) (*dto.ScheduleResponse, error) {
+	userID, ok := middleware.GetUserIDFromContext(ctx)
+	if !ok {
+		return nil, errors.New("user not found in context")
+	}
+
+	tx := u.db.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	schedule, err := u.scheduleRepo.FindByID(tx, scheduleID)
+	if err != nil {
+		u.log.Warnf("Failed to find schedule: %+v", err)
+		return nil, err
+	}
+	if schedule == nil {
+		return nil, ErrScheduleNotFound
+	}
+	if ownerOnly && schedule.DoctorID != userID {
+		return nil, ErrScheduleNotOwned
+	}
+
+	if schedule.IsBookingOpen() == isOpen {
+		return converter.ScheduleToResponse(schedule), nil
+	}
+
+	if err := u.scheduleRepo.SetOpen(tx, scheduleID, isOpen); err != nil {
+		u.log.Warnf("Failed to set booking open for schedule %d: %+v", scheduleID, err)
+		return nil, err
+	}
+	oldSchedule := *schedule
+	schedule.IsOpen = &isOpen
+
+	// Audit log - booking open/paused
+	if err := u.auditService.LogUpdate(ctx, tx, &userID, entity.AuditActionScheduleOpen, "doctor_schedule", strconv.Itoa(scheduleID),
+		entity.JSON{"is_open": !isOpen},
+		entity.JSON{"is_open": isOpen},
+	); err != nil {
+		u.log.Warnf("Failed to create audit log: %+v", err)
+	}
+	if err := u.recordVersion(tx, entity.NewScheduleVersion(scheduleID, &userID, entity.AuditActionScheduleOpen, &oldSchedule, schedule)); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		u.log.Warnf("Failed commit transaction: %+v", err)
+		return nil, err
+	}
+	u.listingCache.Invalidate(ctx, service.ListingSchedules)
+
+	return converter.ScheduleToResponse(schedule), nil
+}
+
+// GetPossiblyAbsentSchedules returns schedules flagged by the absence monitor
+// where the doctor still has not checked in.
+func (u *doctorScheduleUsecase) GetPossiblyAbsentSchedules(ctx context.Context) (*dto.ScheduleListResponse, error) {
+	schedules, err := u.scheduleRepo.FindPossiblyAbsent(u.db.WithContext(ctx))
+	if err != nil {
+		u.log.Warnf("Failed to find possibly absent schedules: %+v", err)
+		return nil, err
+	}
+
+	return &dto.ScheduleListResponse{
+		Schedules: converter.SchedulesToResponses(schedules),
+		Total:     len(schedules),
+	}, nil
+}
+
+// GetHolidayFlaggedSchedules returns schedules that fall on a holiday added after they were
+// created. Admins resolve them by moving, reassigning or deleting the schedule.
+func (u *doctorScheduleUsecase) GetHolidayFlaggedSchedules(ctx context.Context) (*dto.ScheduleListResponse, error) {
+	schedules, err := u.scheduleRepo.FindHolidayFlagged(u.db.WithContext(ctx))
+	if err != nil {
+		u.log.Warnf("Failed to find holiday flagged schedules: %+v", err)
+		return nil, err
+	}
+
+	return &dto.ScheduleListResponse{
+		Schedules: converter.SchedulesToResponses(schedules),
+		Total:     len(schedules),
+	}, nil
+}
+
+// GetLeaveFlaggedSchedules returns schedules closed for booking because their doctor took leave
+// after they were created. Admins resolve them by reassigning the bookings or deleting the schedule.
+func (u *doctorScheduleUsecase) GetLeaveFlaggedSchedules(ctx context.Context) (*dto.ScheduleListResponse, error) {
+	schedules, err := u.scheduleRepo.FindLeaveFlagged(u.db.WithContext(ctx))
+	if err != nil {
+		u.log.Warnf("Failed to find leave flagged schedules: %+v", err)
+		return nil, err
+	}
+
+	return &dto.ScheduleListResponse{
+		Schedules: converter.SchedulesToResponses(schedules),
+		Total:     len(schedules),
+	}, nil
+}
+
+// GetPendingProposals returns one page of the schedules proposed by doctors that wait for
+// an admin decision, and the total count.
+func (u *doctorScheduleUsecase) GetPendingProposals(ctx context.Context, page, limit int) (*dto.ScheduleListResponse, int64, error) {
+	schedules, total, err := u.scheduleRepo.FindPendingApproval(u.db.WithContext(ctx), page, limit)
+	if err != nil {
+		u.log.Warnf("Failed to find pending schedule proposals: %+v", err)
+		return nil, 0, err
+	}
+
+	return &dto.ScheduleListResponse{
+		Schedules: converter.SchedulesToResponses(schedules),
+		Total:     int(total),
+	}, total, nil
+}
+
+// ApproveSchedule approves a doctor's schedule proposal and syncs it to Redis SYNCHRONOUSLY.
+//
+// The proposal is re-checked first: its date must not have passed or become a clinic holiday,
+// and it must not overlap a schedule the doctor got since it was proposed.
+func (u *doctorScheduleUsecase) ApproveSchedule(ctx context.Context, scheduleID int) (*dto.ScheduleResponse, error) {
+	return u.reviewSchedule(ctx, scheduleID, entity.ScheduleApprovalApproved, "")
+}
+
+// RejectSchedule declines a doctor's schedule proposal with a reason shown to the doctor.
+// The rejected schedule is kept for reference but never becomes bookable.
+func (u *doctorScheduleUsecase) RejectSchedule(ctx context.Context, scheduleID int, req *dto.RejectScheduleRequest) (*dto.ScheduleResponse, error) {
+	return u.reviewSchedule(ctx, scheduleID, entity.ScheduleApprovalRejected, req.Reason)
+}
+
+// reviewSchedule records an admin decision on a pending proposal
+func (u *doctorScheduleUsecase) reviewSchedule(ctx context.Context, scheduleID int, status string, reason string) (*dto.ScheduleResponse, error) {
+	tx := u.db.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	schedule, err := u.scheduleRepo.FindByID(tx, scheduleID)
+	if err != nil {
+		u.log.Warnf("Failed to find schedule: %+v", err)
+		return nil, err
+	}
+	if schedule == nil {
+		return nil, ErrScheduleNotFound
+	}
+	if !schedule.IsPendingApproval() {
+		return nil, ErrScheduleNotPending
+	}
+
+	approve := status == entity.ScheduleApprovalApproved
+	if approve {
+		if schedule.ScheduleDate.Before(time.Now().UTC().Truncate(24 * time.Hour)) {
+			return nil, ErrProposalInPast
+		}
+		if err := u.checkNotHoliday(tx, schedule.ScheduleDate); err != nil {
+			return nil, err
+		}
+
+		sameDay, err := u.scheduleRepo.FindByDoctorAndDateRange(tx, schedule.DoctorID, schedule.ScheduleDate, schedule.ScheduleDate)
+		if err != nil {
+			u.log.Warnf("Failed to find schedules of doctor %s: %+v", schedule.DoctorID, err)
+			return nil, err
+		}
+		others := make([]entity.DoctorSchedule, 0, len(sameDay))
+		for _, other := range sameDay {
+			if other.ID != schedule.ID {
+				others = append(others, other)
+			}
+		}
+		if overlapsAny(schedule, others) {
+			return nil, ErrScheduleOverlaps
+		}
+	}
+
+	oldValue := converter.ScheduleToResponse(schedule)
+	oldSchedule := *schedule
+	userID, _ := middleware.GetUserIDFromContext(ctx)
+	now := time.Now()
+
+	// Guarded on the pending status: a concurrent review of the same proposal loses
+	affected, err := u.scheduleRepo.Review(tx, scheduleID, status, userID, reason, now)
+	if err != nil {
+		u.log.Warnf("Failed to review schedule %d: %+v", scheduleID, err)
+		return nil, err
+	}
+	if affected == 0 {
+		return nil, ErrScheduleNotPending
+	}
+	schedule.ApprovalStatus = status
+	schedule.ReviewedBy = &userID
+	schedule.ReviewedAt = &now
+	schedule.RejectionReason = reason
+
+	action := entity.AuditActionScheduleReject
+	if approve {
+		action = entity.AuditActionScheduleApprove
+	}
+	if err := u.auditService.LogUpdate(ctx, tx, &userID, action, "doctor_schedule", strconv.Itoa(scheduleID), oldValue, converter.ScheduleToResponse(schedule)); err != nil {
+		u.log.Warnf("Failed to create audit log: %+v", err)
+	}
+	if err := u.recordVersion(tx, entity.NewScheduleVersion(scheduleID, &userID, action, &oldSchedule, schedule)); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		u.log.Warnf("Failed commit transaction: %+v", err)
+		return nil, err
+	}
+	u.listingCache.Invalidate(ctx, service.ListingSchedules)
+
+	if !approve {
+		return converter.ScheduleToResponse(schedule), nil
+	}
+
+	// Approved schedules become bookable - create their Redis keys now
+	syncCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := u.redisSyncService.SyncScheduleQuota(syncCtx, schedule.ID, schedule.TotalQuota, schedule.ScheduleDate); err != nil {
+		u.log.Warnf("Redis sync failed for approved schedule %d (non-fatal): %+v", schedule.ID, err)
+	} else {
+		u.log.Infof("Schedule %d approved and synced to Redis", schedule.ID)
+	}
+
+	return converter.ScheduleToResponse(schedule), nil
+}
+
+// Reasons a schedule is skipped by CopySchedules
+const (
+	copySkipNotApproved = "not_approved"
+	copySkipPastDate    = "past_date"
+	copySkipHoliday     = "holiday"
+	copySkipLeave       = "doctor_leave"
+	copySkipConflict    = "conflict"
+)
+
+// CopySchedules duplicates a doctor's schedules from the source week to the same weekdays
+// of the target week, then syncs the new schedules to Redis.
+//
+// A schedule is skipped (and reported) when it is a proposal that was not approved, its target
+// date is in the past, is a clinic holiday, falls in a leave of the doctor, or overlaps a schedule the doctor already has on
+// that date. Bookings, check-ins and flags are not copied; the copies are approved and open
+// for booking.
+func (u *doctorScheduleUsecase) CopySchedules(ctx context.Context, req *dto.CopySchedulesRequest) (*dto.CopySchedulesResponse, error) {
+	sourceDate, err := time.Parse("2006-01-02", req.SourceWeek)
+	if err != nil {
+		return nil, ErrInvalidScheduleDate
+	}
+	targetDate, err := time.Parse("2006-01-02", req.TargetWeek)
+	if err != nil {
+		return nil, ErrInvalidScheduleDate
+	}
+
+	sourceStart := weekStart(sourceDate)
+	targetStart := weekStart(targetDate)
+	if sourceStart.Equal(targetStart) {
+		return nil, ErrSameCopyWeek
+	}
+	shiftDays := int(targetStart.Sub(sourceStart).Hours() / 24)
+
+	tx := u.db.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	sources, err := u.scheduleRepo.FindByDoctorAndDateRange(tx, req.DoctorID, sourceStart, sourceStart.AddDate(0, 0, 6))
+	if err != nil {
+		u.log.Warnf("Failed to find source week schedules: %+v", err)
+		return nil, err
+	}
+
+	// Schedules already in the target week - copies must not overlap them (or each other)
+	occupied, err := u.scheduleRepo.FindByDoctorAndDateRange(tx, req.DoctorID, targetStart, targetStart.AddDate(0, 0, 6))
+	if err != nil {
+		u.log.Warnf("Failed to find target week schedules: %+v", err)
+		return nil, err
+	}
+
+	holidays, err := u.holidayRepo.FindAll(tx, targetStart.Format("2006-01-02"), targetStart.AddDate(0, 0, 6).Format("2006-01-02"))
+	if err != nil {
+		u.log.Warnf("Failed to find holidays: %+v", err)
+		return nil, err
+	}
+	holidayDates := make(map[string]bool, len(holidays))
+	for _, holiday := range holidays {
+		holidayDates[holiday.Date.Format("2006-01-02")] = true
+	}
+
+	leaves, err := u.leaveRepo.FindOverlapping(tx, req.DoctorID, targetStart, targetStart.AddDate(0, 0, 6))
+	if err != nil {
+		u.log.Warnf("Failed to find doctor leaves: %+v", err)
+		return nil, err
+	}
+
+	result := &dto.CopySchedulesResponse{
+		SourceWeekStart: sourceStart.Format("2006-01-02"),
+		TargetWeekStart: targetStart.Format("2006-01-02"),
+		Created:         []dto.ScheduleResponse{},
+		Skipped:         []dto.CopySkipResponse{},
+	}
+	userID, _ := middleware.GetUserIDFromContext(ctx)
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+
+	var created []entity.DoctorSchedule
+	for _, source := range sources {
+		copied := entity.DoctorSchedule{
+			DoctorID:       source.DoctorID,
+			ScheduleDate:   source.ScheduleDate.AddDate(0, 0, shiftDays),
+			StartTime:      source.StartTime,
+			EndTime:        source.EndTime,
+			TotalQuota:     source.TotalQuota,
+			BookingMode:    source.BookingMode,
+			SlotMinutes:    source.SlotMinutes,
+			MinLeadHours:   source.MinLeadHours,
+			Breaks:         source.Breaks,
+			ApprovalStatus: entity.ScheduleApprovalApproved,
+		}
+		targetDay := copied.ScheduleDate.Format("2006-01-02")
+
+		reason := ""
+		switch {
+		case !source.IsApproved():
+			reason = copySkipNotApproved
+		case copied.ScheduleDate.Before(today):
+			reason = copySkipPastDate
+		case holidayDates[targetDay]:
+			reason = copySkipHoliday
+		case onLeave(leaves, copied.ScheduleDate):
+			reason = copySkipLeave
+		case overlapsAny(&copied, occupied):
+			reason = copySkipConflict
+		}
+		if reason != "" {
+			result.Skipped = append(result.Skipped, dto.CopySkipResponse{
+				SourceScheduleID: source.ID,
+				ScheduleDate:     targetDay,
+				Reason:           reason,
+			})
+			continue
+		}
+
+		if err := u.scheduleRepo.Create(tx, &copied); err != nil {
+			u.log.Warnf("Failed to create copied schedule: %+v", err)
+			return nil, err
+		}
+
+		// Slot-mode copies get their own appointment slots
+		if copied.IsSlotMode() {
+			start, err := copied.StartDateTime(time.UTC)
+			if err != nil {
+				return nil, err
+			}
+			end, err := copied.EndDateTime(time.UTC)
+			if err != nil {
+				return nil, err
+			}
+			slots := generateScheduleSlots(start, end, copied.SlotMinutes, copied.BreakIntervals(time.UTC))
+			for i := range slots {
+				slots[i].ScheduleID = copied.ID
+			}
+			if err := u.slotRepo.CreateBatch(tx, slots); err != nil {
+				u.log.Warnf("Failed to create schedule slots: %+v", err)
+				return nil, err
+			}
+		}
+
+		// Audit log - one entry per copy, linked to its source
+		if err := u.auditService.LogCreate(ctx, tx, &userID, entity.AuditActionScheduleCopy, "doctor_schedule", strconv.Itoa(copied.ID), map[string]interface{}{
+			"source_schedule_id": source.ID,
+			"schedule":           converter.ScheduleToResponse(&copied),
+		}); err != nil {
+			u.log.Warnf("Failed to create audit log: %+v", err)
+		}
+		if err := u.recordVersion(tx, entity.NewScheduleVersion(copied.ID, &userID, entity.AuditActionScheduleCopy, nil, &copied)); err != nil {
+			return nil, err
+		}
+
+		occupied = append(occupied, copied)
+		created = append(created, copied)
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		u.log.Warnf("Failed commit transaction: %+v", err)
+		return nil, err
+	}
+	u.listingCache.Invalidate(ctx, service.ListingSchedules)
+
+	// SYNCHRONOUS Redis sync of the new schedules (fail-safe, same as CreateSchedule)
+	syncCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	for _, schedule := range created {
+		if err := u.redisSyncService.SyncScheduleQuota(syncCtx, schedule.ID, schedule.TotalQuota, schedule.ScheduleDate); err != nil {
+			u.log.Warnf("Redis sync failed for copied schedule %d (non-fatal): %+v", schedule.ID, err)
+		}
+	}
+
+	result.Created = converter.SchedulesToResponses(created)
+	u.log.Infof("Copied %d schedules of doctor %s from week %s to %s (%d skipped)", len(created), req.DoctorID, result.SourceWeekStart, result.TargetWeekStart, len(result.Skipped))
+	return result, nil
+}
+
+// Reasons a schedule keeps its quota in a bulk quota change
+const (
+	quotaSkipNotApproved  = "not_approved"
+	quotaSkipSlotMode     = "slot_mode"
+	quotaSkipUnchanged    = "unchanged"
+	quotaSkipBelowMinimum = "below_minimum"
+	quotaSkipBelowBooked  = "below_booked"
+)
+
+// BulkUpdateQuota changes the quota of a doctor's schedules from StartDate (default and at
+// the earliest today) up to EndDate (default: all future schedules) in one transaction.
+//
+// A schedule is skipped (and reported) when it is a proposal that was not approved, uses
+// time-slot booking (its quota follows the slots), already has the requested quota, or the
+// new quota would drop below 1 or below its active bookings.
+//
+// Every change is recorded in the schedule history; the audit log gets one entry for the
+// whole operation. After commit each Redis quota is adjusted with the delta strategy (INCRBY),
+// the same as UpdateSchedule, so concurrent bookings are not lost.
+func (u *doctorScheduleUsecase) BulkUpdateQuota(ctx context.Context, req *dto.BulkUpdateQuotaRequest) (*dto.BulkUpdateQuotaResponse, error) {
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+
+	var startDate time.Time
+	if req.StartDate != "" {
+		parsed, err := time.Parse("2006-01-02", req.StartDate)
+		if err != nil {
+			return nil, ErrInvalidQuotaDateRange
+		}
+		startDate = parsed
+	}
+	var to *time.Time
+	if req.EndDate != "" {
+		endDate, err := time.Parse("2006-01-02", req.EndDate)
+		if err != nil || endDate.Before(startDate) {
+			return nil, ErrInvalidQuotaDateRange
+		}
+		to = &endDate
+	}
+
+	// Past schedules keep their quota
+	from := today
+	if startDate.After(today) {
+		from = startDate
+	}
+
+	result := &dto.BulkUpdateQuotaResponse{
+		DoctorID:  req.DoctorID,
+		StartDate: from.Format("2006-01-02"),
+		Updated:   []dto.QuotaChangeResponse{},
+		Skipped:   []dto.QuotaChangeSkipResponse{},
+	}
+	if to != nil {
+		result.EndDate = to.Format("2006-01-02")
+		// The whole range is in the past
+		if to.Before(from) {
+			return result, nil
+		}
+	}
+
+	tx := u.db.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	// Locked so a concurrent schedule update cannot overwrite the new quotas
+	schedules, err := u.scheduleRepo.LockByDoctorFromDate(tx, req.DoctorID, from, to)
+	if err != nil {
+		u.log.Warnf("Failed to find schedules of doctor %s: %+v", req.DoctorID, err)
+		return nil, err
+	}
+
+	userID, _ := middleware.GetUserIDFromContext(ctx)
+	var updated []entity.DoctorSchedule
+	for i := range schedules {
+		schedule := &schedules[i]
+
+		newQuota := schedule.TotalQuota
+		if req.TotalQuota != nil {
+			newQuota = *req.TotalQuota
+		} else {
+			newQuota += *req.QuotaDelta
+		}
+
+		reason := ""
+		switch {
+		case !schedule.IsApproved():
+			reason = quotaSkipNotApproved
+		case schedule.IsSlotMode():
+			reason = quotaSkipSlotMode
+		case newQuota == schedule.TotalQuota:
+			reason = quotaSkipUnchanged
+		case newQuota < 1:
+			reason = quotaSkipBelowMinimum
+		}
+		if reason == "" && newQuota < schedule.TotalQuota {
+			counts, err := u.bookingRepo.CountBySchedule(tx, schedule.ID)
+			if err != nil {
+				u.log.Warnf("Failed to count bookings of schedule %d: %+v", schedule.ID, err)
+				return nil, err
+			}
+			if int64(newQuota) < counts.Booked {
+				reason = quotaSkipBelowBooked
+			}
+		}
+		if reason != "" {
+			result.Skipped = append(result.Skipped, dto.QuotaChangeSkipResponse{
+				ScheduleID:   schedule.ID,
+				ScheduleDate: schedule.ScheduleDate.Format("2006-01-02"),
+				Reason:       reason,
+			})
+			continue
+		}
+
+		oldSchedule := *schedule
+		schedule.TotalQuota = newQuota
+		if err := u.scheduleRepo.Update(tx, schedule); err != nil {
+			u.log.Warnf("Failed to update quota of schedule %d: %+v", schedule.ID, err)
+			return nil, err
+		}
+		if err := u.recordVersion(tx, entity.NewScheduleVersion(schedule.ID, &userID, entity.AuditActionScheduleQuota, &oldSchedule, schedule)); err != nil {
+			return nil, err
+		}
+
+		result.Updated = append(result.Updated, dto.QuotaChangeResponse{
+			ScheduleID:   schedule.ID,
+			ScheduleDate: schedule.ScheduleDate.Format("2006-01-02"),
+			OldQuota:     oldSchedule.TotalQuota,
+			NewQuota:     newQuota,
+		})
+		updated = append(updated, *schedule)
+	}
+
+	if len(updated) == 0 {
+		return result, nil
+	}
+
+	// Audit log - one entry for the whole operation
+	if err := u.auditService.LogUpdate(ctx, tx, &userID, entity.AuditActionScheduleQuota, "doctor", req.DoctorID.String(),
+		entity.JSON{"start_date": result.StartDate, "end_date": result.EndDate, "total_quota": req.TotalQuota, "quota_delta": req.QuotaDelta},
+		entity.JSON{"updated": result.Updated, "skipped": len(result.Skipped)},
+	); err != nil {
+		u.log.Warnf("Failed to create audit log: %+v", err)
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		u.log.Warnf("Failed commit transaction: %+v", err)
+		return nil, err
+	}
+	u.listingCache.Invalidate(ctx, service.ListingSchedules)
+
+	// SYNCHRONOUS Redis sync - delta strategy per schedule (fail-safe, same as UpdateSchedule)
+	syncCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	for i, schedule := range updated {
+		delta := result.Updated[i].NewQuota - result.Updated[i].OldQuota
+		if err := u.redisSyncService.UpdateScheduleQuotaDelta(syncCtx, schedule.ID, delta, schedule.ScheduleDate); err != nil {
+			u.log.Warnf("Failed to update Redis quota for schedule %d (non-fatal): %+v", schedule.ID, err)
+		}
+	}
+
+	u.log.Infof("Bulk quota change for doctor %s: %d schedules updated, %d skipped", req.DoctorID, len(updated), len(result.Skipped))
+	return result, nil
+}
+
+// GetScheduleCalendar renders a doctor's schedules from today up to calendarFeedDays ahead
+// as an iCalendar document, for subscription from calendar apps.
+//
+// Event UIDs are derived from the schedule ID, so apps update (not duplicate) events
+// whose schedule changed; deleted schedules disappear on the next refresh.
+func (u *doctorScheduleUsecase) GetScheduleCalendar(ctx context.Context, doctorID uuid.UUID) ([]byte, error) {
+	today := time.Now().In(u.cfg.App.Location)
+	from := time.Date(today.Year(), today.Month(), today.Day(), 0, 0, 0, 0, time.UTC)
+
+	schedules, err := u.scheduleRepo.FindByDoctorAndDateRange(u.db.WithContext(ctx), doctorID, from, from.AddDate(0, 0, calendarFeedDays))
+	if err != nil {
+		u.log.Warnf("Failed to find schedules of doctor %s: %+v", doctorID, err)
+		return nil, err
+	}
+
+	calendar := &ical.Calendar{
+		ProdID: "-//go-medical-booking//Doctor Schedules//EN",
+		Name:   "Practice schedule",
+		Events: make([]ical.Event, 0, len(schedules)),
+	}
+	for _, schedule := range schedules {
+		start, err := schedule.StartDateTime(u.cfg.App.Location)
+		if err != nil {
+			u.log.Warnf("Skipping schedule %d in calendar feed, invalid start time %q", schedule.ID, schedule.StartTime)
+			continue
+		}
+		end, err := schedule.EndDateTime(u.cfg.App.Location)
+		if err != nil {
+			u.log.Warnf("Skipping schedule %d in calendar feed, invalid end time %q", schedule.ID, schedule.EndTime)
+			continue
+		}
+
+		description := fmt.Sprintf("Booking mode: %s\nQuota: %d patients", schedule.BookingMode, schedule.TotalQuota)
+		if !schedule.IsBookingOpen() {
+			description += "\nBooking is paused"
+		}
+
+		calendar.Events = append(calendar.Events, ical.Event{
+			UID:         fmt.Sprintf("doctor-schedule-%d@%s", schedule.ID, u.cfg.App.TenantID),
+			Start:       start,
+			End:         end,
+			Stamp:       schedule.UpdatedAt,
+			Summary:     "Practice hours",
+			Description: description,
+		})
+	}
+
+	return calendar.Marshal(), nil
+}
+
+// ReassignBookings moves all active bookings of a schedule to an alternative schedule
+// of the same doctor, or cancels them when no target is given.
+//
+// Redis Strategy:
+// 1. Reserve slots on the target in ONE Lua call (quota DECRBY + queue INCRBY)
+// 2. Update bookings in a single DB transaction
+// 3. If the DB transaction fails → compensate: release reserved target slots
+// 4. Source slot release and patient notification are outbox events written in the same transaction
+//
+// Patients who already hold a booking on the target schedule are cancelled instead of moved
+// (partial unique index on patient_id + schedule_id).
+func (u *doctorScheduleUsecase) ReassignBookings(ctx context.Context, scheduleID int, req *dto.ReassignBookingsRequest) (*dto.ReassignBookingsResponse, error) {
+	source, err := u.scheduleRepo.FindByID(u.db.WithContext(ctx), scheduleID)
+	if err != nil {
+		u.log.Warnf("Failed to find schedule: %+v", err)
+		return nil, err
+	}
+	if source == nil {
+		return nil, ErrScheduleNotFound
+	}
+
+	bookings, err := u.bookingRepo.FindActiveByScheduleID(u.db.WithContext(ctx), scheduleID)
+	if err != nil {
+		u.log.Warnf("Failed to find bookings for schedule %d: %+v", scheduleID, err)
+		return nil, err
+	}
+
+	result := &dto.ReassignBookingsResponse{
+		SourceScheduleID: scheduleID,
+		TargetScheduleID: req.TargetScheduleID,
+		Moved:            []uuid.UUID{},
+		Cancelled:        []uuid.UUID{},
+	}
+
+	// Split bookings into "move" and "cancel" sets
+	toMove := []entity.Booking{}
+	toCancel := []entity.Booking{}
+
+	var target *entity.DoctorSchedule
+	if req.TargetScheduleID != 0 {
+		target, err = u.scheduleRepo.FindByID(u.db.WithContext(ctx), req.TargetScheduleID)
+		if err != nil {
+			u.log.Warnf("Failed to find target schedule: %+v", err)
+			return nil, err
+		}
+		today := time.Now().UTC().Truncate(24 * time.Hour)
+		if target == nil || target.ID == source.ID || target.DoctorID != source.DoctorID || target.ScheduleDate.Before(today) || target.IsSlotMode() || !target.IsApproved() {
+			return nil, ErrInvalidTargetSchedule
+		}
+
+		targetBookings, err := u.bookingRepo.FindActiveByScheduleID(u.db.WithContext(ctx), target.ID)
+		if err != nil {
+			u.log.Warnf("Failed to find bookings for schedule %d: %+v", target.ID, err)
+			return nil, err
+		}
+		alreadyBooked := make(map[uuid.UUID]bool, len(targetBookings))
+		for _, b := range targetBookings {
+			alreadyBooked[b.PatientID] = true
+		}
+
+		for _, b := range bookings {
+			if alreadyBooked[b.PatientID] {
+				toCancel = append(toCancel, b)
+			} else {
+				toMove = append(toMove, b)
+			}
+		}
+	} else {
+		toCancel = bookings
+	}
+
+	// Reserve target slots in Redis BEFORE touching the DB
+	reserved, firstQueue := 0, 0
+	if len(toMove) > 0 {
+		reserved, firstQueue, err = u.redisSyncService.ReserveSlots(ctx, req.TargetScheduleID, len(toMove), req.CancelOverflow)
+		if err != nil {
+			if errors.Is(err, service.ErrQuotaFull) {
+				return nil, ErrTargetScheduleFull
+			}
+			return nil, err
+		}
+		// Overflow bookings that did not fit are cancelled (only when CancelOverflow)
+		toCancel = append(toCancel, toMove[reserved:]...)
+		toMove = toMove[:reserved]
+	}
+
+	tx := u.db.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	txErr := func() error {
+		for i, b := range toMove {
+			affected, err := u.bookingRepo.Reassign(tx, b.ID, b.Version, req.TargetScheduleID, firstQueue+i)
+			if err != nil {
+				return err
+			}
+			if affected == 0 {
+				return ErrBookingVersionConflict
+			}
+			if err := u.outboxService.Enqueue(tx, entity.OutboxEventBookingRescheduled, "booking", b.ID.String(), entity.BookingEventPayload{
+				BookingID:      b.ID,
+				BookingCode:    b.BookingCode,
+				PatientID:      b.PatientID,
+				ScheduleID:     req.TargetScheduleID,
+				FromScheduleID: scheduleID,
+				QueueNumber:    firstQueue + i,
+				SlotID:         b.SlotID,
+				ReleaseSlot:    true,
+			}); err != nil {
+				return err
+			}
+			result.Moved = append(result.Moved, b.ID)
+		}
+		for _, b := range toCancel {
+			affected, err := u.bookingRepo.CancelBooking(tx, b.ID, b.Version)
+			if err != nil {
+				return err
+			}
+			if affected == 0 {
+				return ErrBookingVersionConflict
+			}
+			// No waitlist promotion - the source schedule is being emptied
+			if err := u.outboxService.Enqueue(tx, entity.OutboxEventBookingCancelled, "booking", b.ID.String(), entity.BookingEventPayload{
+				BookingID:    b.ID,
+				BookingCode:  b.BookingCode,
+				PatientID:    b.PatientID,
+				ScheduleID:   scheduleID,
+				QueueNumber:  b.QueueNumber,
+				SlotID:       b.SlotID,
+				CancelReason: entity.BookingCancelReasonReassign,
+				ReleaseSlot:  true,
+			}); err != nil {
+				return err
+			}
+			result.Cancelled = append(result.Cancelled, b.ID)
+		}
+
+		// Audit log - single consolidated entry
+		userID, _ := middleware.GetUserIDFromContext(ctx)
+		if err := u.auditService.LogUpdate(ctx, tx, &userID, entity.AuditActionScheduleReassign, "doctor_schedule", strconv.Itoa(scheduleID),
+			entity.JSON{"schedule_id": scheduleID, "active_bookings": len(bookings)},
+			entity.JSON{"target_schedule_id": req.TargetScheduleID, "moved": result.Moved, "cancelled": result.Cancelled},
+		); err != nil {
+			u.log.Warnf("Failed to create audit log: %+v", err)
+		}
+
+		return tx.Commit().Error
+	}()
+
+	if txErr != nil {
+		u.log.Errorf("Failed to reassign bookings for schedule %d, compensating Redis: %+v", scheduleID, txErr)
+
+		// COMPENSATE - release target slots reserved above
+		syncCtx, syncCancel := context.WithTimeout(context.Background(), 5*time.Second)
+		if err := u.redisSyncService.ReleaseSlots(syncCtx, req.TargetScheduleID, reserved); err != nil {
+			u.log.Errorf("CRITICAL: Failed to release Redis slots for schedule %d: %+v", req.TargetScheduleID, err)
+		}
+		syncCancel()
+		return nil, txErr
+	}
+
+	u.log.Infof("Schedule %d bookings reassigned: moved=%d, cancelled=%d", scheduleID, len(result.Moved), len(result.Cancelled))
+	return result, nil
+}
+
+// CallNext calls the next waiting queue number of the doctor's own schedule.
+//
+// Each call is a queue-calling event: the interval since the previous call on the
+// same schedule is folded into the doctor's rolling average minutes per queue
+// number, which drives the estimated wait shown to patients.
+func (u *doctorScheduleUsecase) CallNext(ctx context.Context, scheduleID int) (*dto.BookingResponse, error) {
+	doctorID, ok := middleware.GetUserIDFromContext(ctx)
+	if !ok {
+		return nil, errors.New("user not found in context")
+	}
+
+	tx := u.db.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	schedule, err := u.scheduleRepo.FindByID(tx, scheduleID)
+	if err != nil {
+		u.log.Warnf("Failed to find schedule: %+v", err)
+		return nil, err
+	}
+	if schedule == nil {
+		return nil, ErrScheduleNotFound
+	}
+	if schedule.DoctorID != doctorID {
+		return nil, ErrScheduleNotOwned
+	}
+
+	now := time.Now().In(u.cfg.App.Location)
+	if schedule.ScheduleDate.Format("2006-01-02") != now.Format("2006-01-02") {
+		return nil, ErrCallNotToday
+	}
+
+	lastCalledAt, err := u.bookingRepo.FindLastCalledAt(tx, scheduleID)
+	if err != nil {
+		u.log.Warnf("Failed to find last called booking for schedule %d: %+v", scheduleID, err)
+		return nil, err
+	}
+
+	booking, err := u.bookingRepo.FindNextUncalled(tx, scheduleID)
+	if err != nil {
+		u.log.Warnf("Failed to find next booking for schedule %d: %+v", scheduleID, err)
+		return nil, err
+	}
+	if booking == nil {
+		return nil, ErrQueueEmpty
+	}
+
+	affected, err := u.bookingRepo.MarkCalled(tx, booking.ID, booking.Version, now)
+	if err != nil {
+		u.log.Warnf("Failed to mark booking %s as called: %+v", booking.ID, err)
+		return nil, err
+	}
+	if affected == 0 {
+		return nil, ErrQueueCallConflict
+	}
+	booking.CalledAt = &now
+	booking.Version++
+
+	if err := u.outboxService.Enqueue(tx, entity.OutboxEventBookingCalled, "booking", booking.ID.String(), entity.BookingEventPayload{
+		BookingID:   booking.ID,
+		BookingCode: booking.BookingCode,
+		PatientID:   booking.PatientID,
+		ScheduleID:  scheduleID,
+		QueueNumber: booking.QueueNumber,
+	}); err != nil {
+		u.log.Warnf("Failed to enqueue booking event: %+v", err)
+		return nil, err
+	}
+
+	// Update rolling average from the interval since the previous call
+	if lastCalledAt != nil {
+		// Time spent on a break is not consultation time
+		interval := now.Sub(*lastCalledAt) - entity.BreakTimeBetween(*lastCalledAt, now, schedule.BreakIntervals(u.cfg.App.Location))
+		if interval > 0 && interval <= maxQueueCallInterval {
+			if err := u.queueStatRepo.RecordInterval(tx, doctorID, interval.Minutes()); err != nil {
+				u.log.Warnf("Failed to record queue interval for doctor %s: %+v", doctorID, err)
+				return nil, err
+			}
+		}
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		u.log.Warnf("Failed commit transaction: %+v", err)
+		return nil, err
+	}
+
+	return converter.BookingToResponse(booking), nil
+}
+
+// recordVersion adds an entry to the schedule history inside the mutation's transaction.
+// Unlike the audit log, a failure fails the mutation so the history has no gaps.
+func (u *doctorScheduleUsecase) recordVersion(tx *gorm.DB, version *entity.ScheduleVersion) error {
+	if err := u.versionRepo.Create(tx, version); err != nil {
+		u.log.Warnf("Failed to record version of schedule %d: %+v", version.ScheduleID, err)
+		return err
+	}
+	return nil
+}
+
+// scheduleTimeChanged reports whether the schedule date, start or end time changed.
+// TIME columns read back as HH:MM:SS while requests use HH:MM, so compare parsed timestamps.
+func scheduleTimeChanged(oldSchedule, newSchedule *entity.DoctorSchedule) bool {
+	oldStart, err1 := oldSchedule.StartDateTime(time.UTC)
+	newStart, err2 := newSchedule.StartDateTime(time.UTC)
+	oldEnd, err3 := oldSchedule.EndDateTime(time.UTC)
+	newEnd, err4 := newSchedule.EndDateTime(time.UTC)
+	if err1 != nil || err2 != nil || err3 != nil || err4 != nil {
+		return oldSchedule.StartTime != newSchedule.StartTime || oldSchedule.EndTime != newSchedule.EndTime ||
+			!oldSchedule.ScheduleDate.Equal(newSchedule.ScheduleDate)
+	}
+	return !oldStart.Equal(newStart) || !oldEnd.Equal(newEnd)
+}
+
+// GetScheduleSlots lists the appointment slots of a time-slot schedule with their availability
+func (u *doctorScheduleUsecase) GetScheduleSlots(ctx context.Context, scheduleID int) (*dto.ScheduleSlotListResponse, error) {
+	schedule, err := u.scheduleRepo.FindByID(u.db.WithContext(ctx), scheduleID)
+	if err != nil {
+		u.log.Warnf("Failed to find schedule: %+v", err)
+		return nil, err
+	}
+	if schedule == nil || !schedule.IsApproved() {
+		return nil, ErrScheduleNotFound
+	}
+	if !schedule.IsSlotMode() {
+		return nil, ErrNotSlotSchedule
+	}
+
+	slots, err := u.slotRepo.FindByScheduleID(u.db.WithContext(ctx), scheduleID)
+	if err != nil {
+		u.log.Warnf("Failed to find slots for schedule %d: %+v", scheduleID, err)
+		return nil, err
+	}
+
+	reservedIDs, err := u.bookingRepo.FindReservedSlotIDs(u.db.WithContext(ctx), scheduleID)
+	if err != nil {
+		u.log.Warnf("Failed to find reserved slots for schedule %d: %+v", scheduleID, err)
+		return nil, err
+	}
+	reserved := make(map[int64]bool, len(reservedIDs))
+	for _, id := range reservedIDs {
+		reserved[id] = true
+	}
+
+	return &dto.ScheduleSlotListResponse{
+		ScheduleID: scheduleID,
+		Slots:      converter.ScheduleSlotsToResponses(slots, reserved),
+		Total:      len(slots),
+		Available:  len(slots) - len(reservedIDs),
+	}, nil
+}
+
+// GetMyScheduleSummary returns the day view of the doctor's own schedule.
+//
+// Counts are derived from the queue:
+// - checked_in: confirmed bookings
+// - completed: called bookings, except the one currently being served
+// - waiting: bookings not called yet; once the schedule has ended they count as no_show instead
+func (u *doctorScheduleUsecase) GetMyScheduleSummary(ctx context.Context, scheduleID int) (*dto.ScheduleSummaryResponse, error) {
+	doctorID, ok := middleware.GetUserIDFromContext(ctx)
+	if !ok {
+		return nil, errors.New("user not found in context")
+	}
+
+	db := u.db.WithContext(ctx)
+
+	schedule, err := u.scheduleRepo.FindByID(db, scheduleID)
+	if err != nil {
+		u.log.Warnf("Failed to find schedule: %+v", err)
+		return nil, err
+	}
+	if schedule == nil {
+		return nil, ErrScheduleNotFound
+	}
+	if schedule.DoctorID != doctorID {
+		return nil, ErrScheduleNotOwned
+	}
+
+	counts, err := u.bookingRepo.CountBySchedule(db, scheduleID)
+	if err != nil {
+		u.log.Warnf("Failed to count bookings for schedule %d: %+v", scheduleID, err)
+		return nil, err
+	}
+
+	serving, err := u.bookingRepo.FindLastCalled(db, scheduleID)
+	if err != nil {
+		u.log.Warnf("Failed to find last called booking for schedule %d: %+v", scheduleID, err)
+		return nil, err
+	}
+
+	summary := &dto.ScheduleSummaryResponse{
+		ScheduleID:   schedule.ID,
+		ScheduleDate: schedule.ScheduleDate.Format("2006-01-02"),
+		StartTime:    schedule.StartTime,
+		EndTime:      schedule.EndTime,
+		Booked:       int(counts.Booked),
+		CheckedIn:    int(counts.Confirmed),
+		Completed:    int(counts.Called),
+	}
+	if serving != nil {
+		summary.CurrentlyServing = &serving.QueueNumber
+		summary.Completed--
+	}
+
+	uncalled := int(counts.Booked - counts.Called)
+	endAt, err := schedule.EndDateTime(u.cfg.App.Location)
+	if err == nil && !time.Now().Before(endAt) {
+		summary.NoShow = uncalled
+	} else {
+		summary.Waiting = uncalled
+	}
+
+	return summary, nil
+}
+
+// generateScheduleSlots splits [start, end) into consecutive slots of the given length.
+// A slot that would overlap a break starts when the break ends instead; a trailing
+// remainder shorter than one slot is dropped.
+func generateScheduleSlots(start, end time.Time, minutes int, breaks []entity.Interval) []entity.ScheduleSlot {
+	length := time.Duration(minutes) * time.Minute
+	slots := []entity.ScheduleSlot{}
+	for at := start; !at.Add(length).After(end); {
+		if brk, ok := overlappingBreak(at, at.Add(length), breaks); ok {
+			at = brk.End
+			continue
+		}
+		slots = append(slots, entity.ScheduleSlot{
+			Position:  len(slots) + 1,
+			StartTime: at.Format("15:04"),
+			EndTime:   at.Add(length).Format("15:04"),
+		})
+		at = at.Add(length)
+	}
+	return slots
+}
+
+// overlappingBreak returns the first break overlapping [start, end)
+func overlappingBreak(start, end time.Time, breaks []entity.Interval) (entity.Interval, bool) {
+	for _, brk := range breaks {
+		if start.Before(brk.End) && brk.Start.Before(end) {
+			return brk, true
+		}
+	}
+	return entity.Interval{}, false
+}
+
+// toScheduleBreaks parses the requested breaks, normalized to HH:MM and ordered by start time
+func toScheduleBreaks(reqs []dto.ScheduleBreakRequest) (entity.ScheduleBreaks, error) {
+	breaks := make(entity.ScheduleBreaks, 0, len(reqs))
+	for _, req := range reqs {
+		start, err := time.Parse("15:04", req.StartTime)
+		if err != nil {
+			return nil, ErrInvalidTimeFormat
+		}
+		end, err := time.Parse("15:04", req.EndTime)
+		if err != nil {
+			return nil, ErrInvalidTimeFormat
+		}
+		if !end.After(start) {
+			return nil, ErrInvalidScheduleBreak
+		}
+		breaks = append(breaks, entity.ScheduleBreak{
+			StartTime: start.Format("15:04"),
+			EndTime:   end.Format("15:04"),
+		})
+	}
+
+	sort.Slice(breaks, func(i, j int) bool {
+		return breaks[i].StartTime < breaks[j].StartTime
+	})
+	return breaks, nil
+}
+
+// validateScheduleBreaks checks that the ordered breaks lie inside [start, end] and do not
+// overlap each other. The breaks are placed on the date of start.
+func validateScheduleBreaks(breaks entity.ScheduleBreaks, start, end time.Time) error {
+	intervals, err := breaks.Intervals(start, start.Location())
+	if err != nil {
+		return ErrInvalidTimeFormat
+	}
+	for i, brk := range intervals {
+		if brk.Start.Before(start) || brk.End.After(end) {
+			return ErrInvalidScheduleBreak
+		}
+		if i > 0 && brk.Start.Before(intervals[i-1].End) {
+			return ErrInvalidScheduleBreak
+		}
+	}
+	return nil
+}
+
+// toEntityScheduleFilter converts the DTO filter to the domain filter, validating the date range
+func toEntityScheduleFilter(filter *dto.ScheduleFilter) (*entity.ScheduleFilter, error) {
+	if filter == nil {
+		return nil, nil
+	}
+
+	var startAt, endAt time.Time
+	var err error
+	if filter.StartAt != "" {
+		if startAt, err = time.Parse("2006-01-02", filter.StartAt); err != nil {
+			return nil, ErrInvalidScheduleFilter
+		}
+	}
+	if filter.EndAt != "" {
+		if endAt, err = time.Parse("2006-01-02", filter.EndAt); err != nil {
+			return nil, ErrInvalidScheduleFilter
+		}
+	}
+	if filter.StartAt != "" && filter.EndAt != "" && startAt.After(endAt) {
+		return nil, ErrInvalidScheduleFilter
+	}
+
+	return &entity.ScheduleFilter{
+		StartAt:        filter.StartAt,
+		EndAt:          filter.EndAt,
+		DoctorName:     filter.DoctorName,
+		Specialization: entity.SpecializationSlug(filter.Specialization),
+	}, nil
+}
+
+// checkNotHoliday returns ErrScheduleOnHoliday when the date is a clinic holiday
+func (u *doctorScheduleUsecase) checkNotHoliday(db *gorm.DB, date time.Time) error {
+	holiday, err := u.holidayRepo.FindByDate(db, date)
+	if err != nil {
+		u.log.Warnf("Failed to find holiday: %+v", err)
+		return err
+	}
+	if holiday != nil {
+		return ErrScheduleOnHoliday
+	}
+	return nil
+}
+
+// schedulesToResponsesWithQuota converts schedules and fills in their live remaining quota
+// and whether they can be booked right now.
+// Availability is best-effort: on failure the fields are omitted and the listing still succeeds.
+func (u *doctorScheduleUsecase) schedulesToResponsesWithQuota(ctx context.Context, schedules []entity.DoctorSchedule) []dto.ScheduleResponse {
+	responses := converter.SchedulesToResponses(schedules)
+
+	remaining, err := u.redisSyncService.GetRemainingQuotas(ctx, schedules)
+	if err != nil {
+		u.log.Warnf("Failed to get remaining quota for schedules (non-fatal): %+v", err)
+		return responses
+	}
+
+	now := time.Now().In(u.cfg.App.Location)
+	for i := range responses {
+		quota, ok := remaining[responses[i].ID]
+		if !ok {
+			continue
+		}
+		isFull := quota <= 0
+		bookable := !isFull && acceptsBookings(u.cfg, &schedules[i], now)
+		responses[i].RemainingQuota = &quota
+		responses[i].IsFull = &isFull
+		responses[i].Bookable = &bookable
+	}
+	return responses
+}
+
+// acceptsBookings mirrors the booking window checks of CreateBooking (quota aside):
+// approved, open, not paused for absence, inside the advance booking window and before the
+// minimum lead time deadline
+func acceptsBookings(cfg *config.Config, schedule *entity.DoctorSchedule, now time.Time) bool {
+	if !schedule.IsApproved() || !schedule.IsBookingOpen() || !withinAdvanceWindow(cfg, schedule) {
+		return false
+	}
+	if cfg.Absence.PauseBookings && schedule.IsPossiblyAbsent() {
+		return false
+	}
+
+	deadline, err := schedule.BookingDeadline(cfg.App.Location, cfg.Booking.Cutoff)
+	if err != nil {
+		return false
+	}
+	return !now.After(deadline)
+}
+
+// findBookableSchedules returns up to limit upcoming schedules of the doctor that accept bookings
+// and have remaining quota, with their live quota filled in. It searches from today to the end of
+// the advance booking window (nextAvailableScanDays ahead when the window is unlimited).
+//
+// The schedules come from one query and their remaining quotas from a single Redis MGET
+// (DB fallback for missing keys, see RedisSyncService.GetRemainingQuotas).
+func findBookableSchedules(
+	ctx context.Context,
+	db *gorm.DB,
+	cfg *config.Config,
+	scheduleRepo repository.DoctorScheduleRepository,
+	redisSyncService *service.RedisSyncService,
+	doctorID uuid.UUID,
+	limit int,
+) ([]dto.ScheduleResponse, error) {
+	now := time.Now().In(cfg.App.Location)
+	from := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 0, nextAvailableScanDays)
+	if cfg.Booking.MaxAdvanceDays > 0 {
+		to = from.AddDate(0, 0, cfg.Booking.MaxAdvanceDays)
+	}
+
+	schedules, err := scheduleRepo.FindApprovedByActiveDoctorAndDateRange(db.WithContext(ctx), doctorID, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	remaining, err := redisSyncService.GetRemainingQuotas(ctx, schedules)
+	if err != nil {
+		return nil, err
+	}
+
+	// Schedules are ordered by date and start time
+	responses := make([]dto.ScheduleResponse, 0, limit)
+	for i := range schedules {
+		if len(responses) == limit {
+			break
+		}
+		schedule := &schedules[i]
+		quota := remaining[schedule.ID]
+		if quota <= 0 || !acceptsBookings(cfg, schedule, now) {
+			continue
+		}
+
+		response := converter.ScheduleToResponse(schedule)
+		isFull := false
+		bookable := true
+		response.RemainingQuota = &quota
+		response.IsFull = &isFull
+		response.Bookable = &bookable
+		responses = append(responses, *response)
+	}
+	return responses, nil
+}
+
+// weekStart returns the Monday of the week containing date
+func weekStart(date time.Time) time.Time {
+	offset := (int(date.Weekday()) + 6) % 7
+	return date.AddDate(0, 0, -offset)
+}
+
+// overlapsAny reports whether the schedule's time range overlaps another schedule on the same date.
+// Rejected proposals do not occupy their time range.
+func overlapsAny(schedule *entity.DoctorSchedule, others []entity.DoctorSchedule) bool {
+	start, err := schedule.StartDateTime(time.UTC)
+	if err != nil {
+		return true
+	}
+	end, err := schedule.EndDateTime(time.UTC)
+	if err != nil {
+		return true
+	}
+
+	for i := range others {
+		if others[i].ScheduleDate.Format("2006-01-02") != schedule.ScheduleDate.Format("2006-01-02") ||
+			others[i].ApprovalStatus == entity.ScheduleApprovalRejected {
+			continue
+		}
+		otherStart, err := others[i].StartDateTime(time.UTC)
+		if err != nil {
+			continue
+		}
+		otherEnd, err := others[i].EndDateTime(time.UTC)
+		if err != nil {
+			continue
+		}
+		if start.Before(otherEnd) && otherStart.Before(end) {
+			return true
+		}
+	}
+	return false
 }
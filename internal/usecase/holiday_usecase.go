@@ -0,0 +1,298 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"go-template-clean-architecture/internal/converter"
+	"go-template-clean-architecture/internal/delivery/dto"
+	"go-template-clean-architecture/internal/delivery/http/middleware"
+	"go-template-clean-architecture/internal/domain/entity"
+	"go-template-clean-architecture/internal/domain/repository"
+	"go-template-clean-architecture/internal/service"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrHolidayNotFound    = errors.New("holiday not found")
+	ErrHolidayExists      = errors.New("a holiday already exists on this date")
+	ErrInvalidHolidayDate = errors.New("invalid holiday date format, use YYYY-MM-DD")
+)
+
+type HolidayUsecase interface {
+	CreateHoliday(ctx context.Context, req *dto.CreateHolidayRequest) (*dto.HolidayResponse, error)
+	GetHoliday(ctx context.Context, id int) (*dto.HolidayResponse, error)
+	GetAllHolidays(ctx context.Context, from, to string) (*dto.HolidayListResponse, error)
+	UpdateHoliday(ctx context.Context, id int, req *dto.UpdateHolidayRequest) (*dto.HolidayResponse, error)
+	DeleteHoliday(ctx context.Context, id int) error
+}
+
+type holidayUsecase struct {
+	db           *gorm.DB
+	log          *logrus.Logger
+	holidayRepo  repository.HolidayRepository
+	scheduleRepo repository.DoctorScheduleRepository
+	auditService service.AuditService
+	versionRepo  repository.ScheduleVersionRepository
+	listingCache service.ListingCacheService
+}
+
+func NewHolidayUsecase(
+	db *gorm.DB,
+	log *logrus.Logger,
+	holidayRepo repository.HolidayRepository,
+	scheduleRepo repository.DoctorScheduleRepository,
+	auditService service.AuditService,
+	versionRepo repository.ScheduleVersionRepository,
+	listingCache service.ListingCacheService,
+) HolidayUsecase {
+	return &holidayUsecase{
+		db:           db,
+		log:          log,
+		holidayRepo:  holidayRepo,
+		scheduleRepo: scheduleRepo,
+		auditService: auditService,
+		versionRepo:  versionRepo,
+		listingCache: listingCache,
+	}
+}
+
+// CreateHoliday adds a blackout date and flags the schedules already on it for admin action
+func (u *holidayUsecase) CreateHoliday(ctx context.Context, req *dto.CreateHolidayRequest) (*dto.HolidayResponse, error) {
+	date, err := time.Parse("2006-01-02", req.Date)
+	if err != nil {
+		u.log.Warnf("Failed to parse holiday date: %+v", err)
+		return nil, ErrInvalidHolidayDate
+	}
+
+	tx := u.db.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	holiday := &entity.Holiday{
+		Date:        date,
+		Name:        strings.TrimSpace(req.Name),
+		Description: req.Description,
+	}
+	if err := u.holidayRepo.Create(tx, holiday); err != nil {
+		u.log.Warnf("Failed to create holiday: %+v", err)
+		if isDuplicateKeyError(err, "holiday") {
+			return nil, ErrHolidayExists
+		}
+		return nil, err
+	}
+
+	userID, _ := middleware.GetUserIDFromContext(ctx)
+	now := time.Now()
+	flagged, err := u.setHolidayFlags(tx, date, &now, userID, entity.AuditActionHolidayCreate)
+	if err != nil {
+		u.log.Warnf("Failed to flag schedules on holiday %s: %+v", req.Date, err)
+		return nil, err
+	}
+
+	// Audit log - create holiday
+	response := converter.HolidayToResponse(holiday)
+	if err := u.auditService.LogCreate(ctx, tx, &userID, entity.AuditActionHolidayCreate, "holiday", strconv.Itoa(holiday.ID), response); err != nil {
+		u.log.Warnf("Failed to create audit log: %+v", err)
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		u.log.Warnf("Failed commit transaction: %+v", err)
+		return nil, err
+	}
+	u.listingCache.Invalidate(ctx, service.ListingSchedules)
+
+	if flagged > 0 {
+		u.log.Warnf("Holiday %s (%s): %d existing schedules flagged for admin action", holiday.Name, req.Date, flagged)
+	}
+
+	response.FlaggedSchedules = flagged
+	return response, nil
+}
+
+func (u *holidayUsecase) GetHoliday(ctx context.Context, id int) (*dto.HolidayResponse, error) {
+	holiday, err := u.holidayRepo.FindByID(u.db.WithContext(ctx), id)
+	if err != nil {
+		u.log.Warnf("Failed to find holiday %d: %+v", id, err)
+		return nil, err
+	}
+	if holiday == nil {
+		return nil, ErrHolidayNotFound
+	}
+
+	return converter.HolidayToResponse(holiday), nil
+}
+
+// GetAllHolidays returns holidays ordered by date, optionally within a date range (YYYY-MM-DD)
+func (u *holidayUsecase) GetAllHolidays(ctx context.Context, from, to string) (*dto.HolidayListResponse, error) {
+	for _, date := range []string{from, to} {
+		if date == "" {
+			continue
+		}
+		if _, err := time.Parse("2006-01-02", date); err != nil {
+			return nil, ErrInvalidHolidayDate
+		}
+	}
+
+	holidays, err := u.holidayRepo.FindAll(u.db.WithContext(ctx), from, to)
+	if err != nil {
+		u.log.Warnf("Failed to find holidays: %+v", err)
+		return nil, err
+	}
+
+	return &dto.HolidayListResponse{
+		Holidays: converter.HolidaysToResponses(holidays),
+		Total:    len(holidays),
+	}, nil
+}
+
+// UpdateHoliday changes a holiday. Moving it to another date moves the schedule flags along.
+func (u *holidayUsecase) UpdateHoliday(ctx context.Context, id int, req *dto.UpdateHolidayRequest) (*dto.HolidayResponse, error) {
+	tx := u.db.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	holiday, err := u.holidayRepo.FindByID(tx, id)
+	if err != nil {
+		u.log.Warnf("Failed to find holiday %d: %+v", id, err)
+		return nil, err
+	}
+	if holiday == nil {
+		return nil, ErrHolidayNotFound
+	}
+
+	oldValue := converter.HolidayToResponse(holiday)
+	oldDate := holiday.Date
+
+	if req.Date != "" {
+		date, err := time.Parse("2006-01-02", req.Date)
+		if err != nil {
+			u.log.Warnf("Failed to parse holiday date: %+v", err)
+			return nil, ErrInvalidHolidayDate
+		}
+		holiday.Date = date
+	}
+	if req.Name != "" {
+		holiday.Name = strings.TrimSpace(req.Name)
+	}
+	if req.Description != nil {
+		holiday.Description = *req.Description
+	}
+
+	if err := u.holidayRepo.Update(tx, holiday); err != nil {
+		u.log.Warnf("Failed to update holiday: %+v", err)
+		if isDuplicateKeyError(err, "holiday") {
+			return nil, ErrHolidayExists
+		}
+		return nil, err
+	}
+
+	userID, _ := middleware.GetUserIDFromContext(ctx)
+	var flagged int64
+	if !holiday.Date.Equal(oldDate) {
+		if _, err := u.setHolidayFlags(tx, oldDate, nil, userID, entity.AuditActionHolidayUpdate); err != nil {
+			u.log.Warnf("Failed to clear holiday flags: %+v", err)
+			return nil, err
+		}
+		now := time.Now()
+		flagged, err = u.setHolidayFlags(tx, holiday.Date, &now, userID, entity.AuditActionHolidayUpdate)
+		if err != nil {
+			u.log.Warnf("Failed to flag schedules on holiday: %+v", err)
+			return nil, err
+		}
+	}
+
+	// Audit log - update holiday
+	newValue := converter.HolidayToResponse(holiday)
+	if err := u.auditService.LogUpdate(ctx, tx, &userID, entity.AuditActionHolidayUpdate, "holiday", strconv.Itoa(id), oldValue, newValue); err != nil {
+		u.log.Warnf("Failed to create audit log: %+v", err)
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		u.log.Warnf("Failed commit transaction: %+v", err)
+		return nil, err
+	}
+	u.listingCache.Invalidate(ctx, service.ListingSchedules)
+
+	newValue.FlaggedSchedules = flagged
+	return newValue, nil
+}
+
+// DeleteHoliday removes a holiday and clears the flags of the schedules on its date
+func (u *holidayUsecase) DeleteHoliday(ctx context.Context, id int) error {
+	tx := u.db.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	holiday, err := u.holidayRepo.FindByID(tx, id)
+	if err != nil {
+		u.log.Warnf("Failed to find holiday %d: %+v", id, err)
+		return err
+	}
+	if holiday == nil {
+		return ErrHolidayNotFound
+	}
+
+	if _, err := u.holidayRepo.Delete(tx, id); err != nil {
+		u.log.Warnf("Failed to delete holiday: %+v", err)
+		return err
+	}
+
+	userID, _ := middleware.GetUserIDFromContext(ctx)
+	if _, err := u.setHolidayFlags(tx, holiday.Date, nil, userID, entity.AuditActionHolidayDelete); err != nil {
+		u.log.Warnf("Failed to clear holiday flags: %+v", err)
+		return err
+	}
+
+	// Audit log - delete holiday
+	if err := u.auditService.LogDelete(ctx, tx, &userID, entity.AuditActionHolidayDelete, "holiday", strconv.Itoa(id), converter.HolidayToResponse(holiday)); err != nil {
+		u.log.Warnf("Failed to create audit log: %+v", err)
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		u.log.Warnf("Failed commit transaction: %+v", err)
+		return err
+	}
+	u.listingCache.Invalidate(ctx, service.ListingSchedules)
+
+	return nil
+}
+
+// setHolidayFlags flags the schedules on date as falling on a holiday (at != nil) or clears
+// their flag, and records the change in the history of each schedule it changed.
+func (u *holidayUsecase) setHolidayFlags(tx *gorm.DB, date time.Time, at *time.Time, userID uuid.UUID, action string) (int64, error) {
+	schedules, err := u.scheduleRepo.FindByDateRange(tx, date, date)
+	if err != nil {
+		return 0, err
+	}
+
+	var affected int64
+	if at != nil {
+		affected, err = u.scheduleRepo.MarkHolidayFlagged(tx, date, *at)
+	} else {
+		affected, err = u.scheduleRepo.ClearHolidayFlag(tx, date)
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	versions := make([]entity.ScheduleVersion, 0, len(schedules))
+	for i := range schedules {
+		oldSchedule := &schedules[i]
+		// Mark and Clear leave schedules already in the wanted state untouched
+		if (oldSchedule.HolidayFlaggedAt != nil) == (at != nil) {
+			continue
+		}
+		newSchedule := *oldSchedule
+		newSchedule.HolidayFlaggedAt = at
+		versions = append(versions, *entity.NewScheduleVersion(oldSchedule.ID, &userID, action, oldSchedule, &newSchedule))
+	}
+	if err := u.versionRepo.CreateBatch(tx, versions); err != nil {
+		return 0, err
+	}
+
+	return affected, nil
+}
@@ -0,0 +1,452 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"go-template-clean-architecture/config"
+	"go-template-clean-architecture/internal/converter"
+	"go-template-clean-architecture/internal/delivery/dto"
+	"go-template-clean-architecture/internal/delivery/http/middleware"
+	"go-template-clean-architecture/internal/domain/entity"
+	"go-template-clean-architecture/internal/domain/repository"
+	"go-template-clean-architecture/internal/service"
+	"go-template-clean-architecture/pkg/pdf"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrInvoiceNotFound      = errors.New("invoice not found")
+	ErrInvalidInvoiceFilter = errors.New("invalid invoice filter")
+)
+
+type InvoiceUsecase interface {
+	GetMyBookingInvoice(ctx context.Context, bookingID uuid.UUID) (*dto.InvoiceResponse, error)
+	GetMyBookingInvoicePDF(ctx context.Context, bookingID uuid.UUID) ([]byte, string, error)
+	GetInvoice(ctx context.Context, id uuid.UUID) (*dto.InvoiceResponse, error)
+	GetInvoicePDF(ctx context.Context, id uuid.UUID) ([]byte, string, error)
+	GetInvoices(ctx context.Context, filter *dto.InvoiceFilter, page, limit int) ([]dto.InvoiceResponse, int64, error)
+	GetFinanceReport(ctx context.Context, filter *dto.InvoiceFilter) (*dto.FinanceReportResponse, error)
+}
+
+type invoiceUsecase struct {
+	db            *gorm.DB
+	log           *logrus.Logger
+	cfg           *config.Config
+	invoiceRepo   repository.InvoiceRepository
+	bookingRepo   repository.BookingRepository
+	paymentRepo   repository.PaymentRepository
+	userRepo      repository.UserRepository
+	auditService  service.AuditService
+	formatService service.FormatService
+	eventBus      *service.EventBus
+}
+
+func NewInvoiceUsecase(
+	db *gorm.DB,
+	log *logrus.Logger,
+	cfg *config.Config,
+	invoiceRepo repository.InvoiceRepository,
+	bookingRepo repository.BookingRepository,
+	paymentRepo repository.PaymentRepository,
+	userRepo repository.UserRepository,
+	auditService service.AuditService,
+	formatService service.FormatService,
+	eventBus *service.EventBus,
+) InvoiceUsecase {
+	u := &invoiceUsecase{
+		db:            db,
+		log:           log,
+		cfg:           cfg,
+		invoiceRepo:   invoiceRepo,
+		bookingRepo:   bookingRepo,
+		paymentRepo:   paymentRepo,
+		userRepo:      userRepo,
+		auditService:  auditService,
+		formatService: formatService,
+		eventBus:      eventBus,
+	}
+	u.registerEventHandlers()
+	return u
+}
+
+// registerEventHandlers issues invoices once bookings are paid (booking.confirmed) or their
+// visit completed (booking.called). Events may be delivered more than once, a booking keeps
+// its first invoice.
+func (u *invoiceUsecase) registerEventHandlers() {
+	u.eventBus.Subscribe(entity.OutboxEventBookingConfirmed, u.handleBookingPaid)
+	u.eventBus.Subscribe(entity.OutboxEventBookingCalled, u.handleBookingCompleted)
+}
+
+// GetMyBookingInvoice returns the invoice of the logged-in patient's booking
+func (u *invoiceUsecase) GetMyBookingInvoice(ctx context.Context, bookingID uuid.UUID) (*dto.InvoiceResponse, error) {
+	invoice, err := u.findMyBookingInvoice(ctx, bookingID)
+	if err != nil {
+		return nil, err
+	}
+	return converter.InvoiceToResponse(invoice), nil
+}
+
+// GetMyBookingInvoicePDF renders the invoice of the logged-in patient's booking, with its number
+func (u *invoiceUsecase) GetMyBookingInvoicePDF(ctx context.Context, bookingID uuid.UUID) ([]byte, string, error) {
+	invoice, err := u.findMyBookingInvoice(ctx, bookingID)
+	if err != nil {
+		return nil, "", err
+	}
+	return u.renderPDF(invoice), invoice.Number, nil
+}
+
+// GetInvoice returns any invoice (admin)
+func (u *invoiceUsecase) GetInvoice(ctx context.Context, id uuid.UUID) (*dto.InvoiceResponse, error) {
+	invoice, err := u.findInvoice(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return converter.InvoiceToResponse(invoice), nil
+}
+
+// GetInvoicePDF renders any invoice (admin), with its number
+func (u *invoiceUsecase) GetInvoicePDF(ctx context.Context, id uuid.UUID) ([]byte, string, error) {
+	invoice, err := u.findInvoice(ctx, id)
+	if err != nil {
+		return nil, "", err
+	}
+	return u.renderPDF(invoice), invoice.Number, nil
+}
+
+// GetInvoices lists invoices newest first (admin finance view)
+func (u *invoiceUsecase) GetInvoices(ctx context.Context, filter *dto.InvoiceFilter, page, limit int) ([]dto.InvoiceResponse, int64, error) {
+	domainFilter, err := u.invoiceFilter(filter)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	invoices, total, err := u.invoiceRepo.FindAll(u.db.WithContext(ctx), domainFilter, page, limit)
+	if err != nil {
+		u.log.Warnf("Failed to find invoices: %+v", err)
+		return nil, 0, err
+	}
+
+	return converter.InvoicesToResponses(invoices), total, nil
+}
+
+// GetFinanceReport totals the invoices matching the filter per currency and status (admin finance view)
+func (u *invoiceUsecase) GetFinanceReport(ctx context.Context, filter *dto.InvoiceFilter) (*dto.FinanceReportResponse, error) {
+	domainFilter, err := u.invoiceFilter(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	totals, err := u.invoiceRepo.Totals(u.db.WithContext(ctx), domainFilter)
+	if err != nil {
+		u.log.Warnf("Failed to total invoices: %+v", err)
+		return nil, err
+	}
+
+	return &dto.FinanceReportResponse{
+		From:   filter.From,
+		To:     filter.To,
+		Totals: converter.InvoiceTotalsToFinanceTotals(totals),
+	}, nil
+}
+
+// handleBookingPaid issues the invoice of a booking confirmed by its payment
+func (u *invoiceUsecase) handleBookingPaid(ctx context.Context, event *entity.DomainEvent) error {
+	var payload entity.BookingEventPayload
+	if err := event.DecodePayload(&payload); err != nil {
+		return err
+	}
+
+	payment, err := u.paymentRepo.FindLatestByBookingID(u.db.WithContext(ctx), payload.BookingID)
+	if err != nil {
+		return err
+	}
+	if payment == nil || !payment.IsPaid() {
+		return nil
+	}
+
+	return u.issueInvoice(ctx, payload.BookingID, payment)
+}
+
+// handleBookingCompleted issues the invoice of a visit once the doctor calls the booking.
+// Bookings paid online are normally invoiced already; the others are settled at the clinic.
+func (u *invoiceUsecase) handleBookingCompleted(ctx context.Context, event *entity.DomainEvent) error {
+	var payload entity.BookingEventPayload
+	if err := event.DecodePayload(&payload); err != nil {
+		return err
+	}
+
+	payment, err := u.paymentRepo.FindLatestByBookingID(u.db.WithContext(ctx), payload.BookingID)
+	if err != nil {
+		return err
+	}
+	if payment != nil && !payment.IsPaid() {
+		payment = nil
+	}
+
+	return u.issueInvoice(ctx, payload.BookingID, payment)
+}
+
+// issueInvoice issues the invoice of a booking: the paid payment split into the consultation
+// and admin fee, or the current fees when there is no payment. Bookings already invoiced and
+// free consultations are skipped.
+func (u *invoiceUsecase) issueInvoice(ctx context.Context, bookingID uuid.UUID, payment *entity.Payment) error {
+	tx := u.db.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	existing, err := u.invoiceRepo.FindByBookingID(tx, bookingID)
+	if err != nil {
+		return err
+	}
+	if existing != nil {
+		return nil
+	}
+
+	booking, err := u.bookingRepo.FindByID(tx, bookingID)
+	if err != nil {
+		return err
+	}
+	if booking == nil {
+		return nil
+	}
+
+	invoice := &entity.Invoice{
+		BookingID:   booking.ID,
+		PatientID:   booking.PatientID,
+		DoctorID:    booking.Schedule.DoctorID,
+		BookingCode: booking.BookingCode,
+		ServiceDate: booking.Schedule.ScheduleDate,
+	}
+
+	var consultationFee, adminFee int64
+	if payment != nil {
+		consultationFee = payment.Amount - payment.AdminFee
+		adminFee = payment.AdminFee
+		invoice.PaymentID = &payment.ID
+		invoice.Currency = payment.Currency
+		invoice.Status = entity.InvoiceStatusPaid
+		if payment.RefundStatus == entity.PaymentRefundSucceeded {
+			// Refunded before the invoice was issued
+			invoice.Status = entity.InvoiceStatusRefunded
+		}
+	} else {
+		if booking.IsCancelled() || booking.Schedule.Doctor.ConsultationFee == 0 {
+			// Nothing to bill
+			return nil
+		}
+		consultationFee = booking.Schedule.Doctor.ConsultationFee
+		adminFee = u.cfg.Invoice.AdminFee
+		invoice.Currency = u.cfg.Payment.Currency
+		invoice.Status = entity.InvoiceStatusIssued
+	}
+
+	patient, err := u.userRepo.FindByID(tx, booking.PatientID)
+	if err != nil {
+		return err
+	}
+	doctor, err := u.userRepo.FindByID(tx, booking.Schedule.DoctorID)
+	if err != nil {
+		return err
+	}
+	if patient != nil {
+		invoice.PatientName = patient.FullName
+	}
+	if doctor != nil {
+		invoice.DoctorName = doctor.FullName
+	}
+
+	invoice.Items = append(invoice.Items, entity.InvoiceItem{
+		Type:        entity.InvoiceItemConsultationFee,
+		Description: fmt.Sprintf("Consultation with %s", invoice.DoctorName),
+		Amount:      consultationFee,
+	})
+	if adminFee > 0 {
+		invoice.Items = append(invoice.Items, entity.InvoiceItem{
+			Type:        entity.InvoiceItemAdminFee,
+			Description: "Administration fee",
+			Amount:      adminFee,
+		})
+	}
+	invoice.Total = consultationFee + adminFee
+
+	invoice.IssuedAt = time.Now()
+	year := invoice.IssuedAt.In(u.cfg.App.Location).Year()
+	sequence, err := u.invoiceRepo.NextNumber(tx, year)
+	if err != nil {
+		return err
+	}
+	invoice.Number = fmt.Sprintf("%s-%d-%06d", u.cfg.Invoice.Prefix, year, sequence)
+
+	if err := u.invoiceRepo.Create(tx, invoice); err != nil {
+		if isDuplicateKeyError(err, "uq_invoices_booking") {
+			// Issued by a concurrent event, the rollback returns the number
+			return nil
+		}
+		return err
+	}
+
+	// Audit log - issued by the system (no acting user)
+	if err := u.auditService.LogCreate(ctx, tx, nil, entity.AuditActionInvoiceIssue, "invoice", invoice.ID.String(), entity.JSON{
+		"number":     invoice.Number,
+		"booking_id": invoice.BookingID,
+		"status":     invoice.Status,
+		"total":      invoice.Total,
+		"currency":   invoice.Currency,
+	}); err != nil {
+		u.log.Warnf("Failed to create audit log: %+v", err)
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return err
+	}
+
+	u.log.Infof("Invoice issued: number=%s, booking=%s, total=%d %s", invoice.Number, invoice.BookingID, invoice.Total, invoice.Currency)
+	return nil
+}
+
+// findMyBookingInvoice finds the invoice of a booking owned by the logged-in patient
+func (u *invoiceUsecase) findMyBookingInvoice(ctx context.Context, bookingID uuid.UUID) (*entity.Invoice, error) {
+	userID, ok := middleware.GetUserIDFromContext(ctx)
+	if !ok {
+		return nil, errors.New("user not found in context")
+	}
+
+	invoice, err := u.invoiceRepo.FindByBookingID(u.db.WithContext(ctx), bookingID)
+	if err != nil {
+		u.log.Warnf("Failed to find invoice of booking %s: %+v", bookingID, err)
+		return nil, err
+	}
+	if invoice == nil {
+		return nil, ErrInvoiceNotFound
+	}
+	if invoice.PatientID != userID {
+		return nil, ErrBookingNotOwned
+	}
+	return invoice, nil
+}
+
+func (u *invoiceUsecase) findInvoice(ctx context.Context, id uuid.UUID) (*entity.Invoice, error) {
+	invoice, err := u.invoiceRepo.FindByID(u.db.WithContext(ctx), id)
+	if err != nil {
+		u.log.Warnf("Failed to find invoice %s: %+v", id, err)
+		return nil, err
+	}
+	if invoice == nil {
+		return nil, ErrInvoiceNotFound
+	}
+	return invoice, nil
+}
+
+// invoiceFilter validates the query filter, dates are days in the clinic time zone
+func (u *invoiceUsecase) invoiceFilter(filter *dto.InvoiceFilter) (*entity.InvoiceFilter, error) {
+	domainFilter := &entity.InvoiceFilter{
+		Status: entity.InvoiceStatus(filter.Status),
+	}
+
+	switch domainFilter.Status {
+	case "", entity.InvoiceStatusPaid, entity.InvoiceStatusIssued, entity.InvoiceStatusRefunded:
+	default:
+		return nil, ErrInvalidInvoiceFilter
+	}
+
+	if filter.DoctorID != "" {
+		doctorID, err := uuid.Parse(filter.DoctorID)
+		if err != nil {
+			return nil, ErrInvalidInvoiceFilter
+		}
+		domainFilter.DoctorID = &doctorID
+	}
+	if filter.PatientID != "" {
+		patientID, err := uuid.Parse(filter.PatientID)
+		if err != nil {
+			return nil, ErrInvalidInvoiceFilter
+		}
+		domainFilter.PatientID = &patientID
+	}
+	if filter.From != "" {
+		from, err := time.ParseInLocation("2006-01-02", filter.From, u.cfg.App.Location)
+		if err != nil {
+			return nil, ErrInvalidInvoiceFilter
+		}
+		domainFilter.From = &from
+	}
+	if filter.To != "" {
+		to, err := time.ParseInLocation("2006-01-02", filter.To, u.cfg.App.Location)
+		if err != nil {
+			return nil, ErrInvalidInvoiceFilter
+		}
+		to = to.AddDate(0, 0, 1)
+		domainFilter.To = &to
+	}
+	return domainFilter, nil
+}
+
+// renderPDF lays out an invoice on one A4 page
+func (u *invoiceUsecase) renderPDF(invoice *entity.Invoice) []byte {
+	const (
+		left  = 50.0
+		right = pdf.PageWidth - 50
+	)
+	doc := pdf.New()
+
+	// Issuer and invoice number
+	doc.Text(left, 70, pdf.HelveticaBold, 18, u.cfg.Invoice.IssuerName)
+	y := 88.0
+	for _, line := range strings.Split(u.cfg.Invoice.IssuerAddress, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			doc.Text(left, y, pdf.Helvetica, 10, line)
+			y += 13
+		}
+	}
+	doc.Text(right-150, 70, pdf.HelveticaBold, 18, "INVOICE")
+	doc.Text(right-150, 88, pdf.Helvetica, 10, invoice.Number)
+
+	// Visit details
+	serviceDate := time.Date(invoice.ServiceDate.Year(), invoice.ServiceDate.Month(), invoice.ServiceDate.Day(), 0, 0, 0, 0, u.cfg.App.Location)
+	status := "Paid"
+	switch invoice.Status {
+	case entity.InvoiceStatusIssued:
+		status = "Payable at the clinic"
+	case entity.InvoiceStatusRefunded:
+		status = "Refunded"
+	}
+	details := [][2]string{
+		{"Billed to", invoice.PatientName},
+		{"Doctor", invoice.DoctorName},
+		{"Booking", invoice.BookingCode},
+		{"Visit date", u.formatService.Date(serviceDate)},
+		{"Issued", u.formatService.Date(invoice.IssuedAt)},
+		{"Status", status},
+	}
+	y = 150
+	for _, detail := range details {
+		doc.Text(left, y, pdf.HelveticaBold, 10, detail[0])
+		doc.Text(left+90, y, pdf.Helvetica, 10, detail[1])
+		y += 16
+	}
+
+	// Line items
+	y += 20
+	doc.Text(left, y, pdf.HelveticaBold, 10, "Description")
+	doc.Text(right-60, y, pdf.HelveticaBold, 10, "Amount")
+	y += 8
+	doc.Line(left, y, right, y)
+	for _, item := range invoice.Items {
+		y += 18
+		doc.Text(left, y, pdf.Helvetica, 10, item.Description)
+		doc.TextRight(right, y, 10, u.formatService.Amount(item.Amount, invoice.Currency))
+	}
+	y += 10
+	doc.Line(left, y, right, y)
+	y += 18
+	doc.Text(left, y, pdf.HelveticaBold, 11, "Total")
+	doc.TextRight(right, y, 11, u.formatService.Amount(invoice.Total, invoice.Currency))
+
+	return doc.Bytes()
+}
@@ -0,0 +1,87 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+
+	"go-template-clean-architecture/internal/delivery/dto"
+	"go-template-clean-architecture/internal/delivery/http/middleware"
+	"go-template-clean-architecture/internal/domain/entity"
+	"go-template-clean-architecture/internal/service"
+	"go-template-clean-architecture/pkg/logger"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrUnknownLogPackage = errors.New("unknown log package")
+	ErrInvalidLogLevel   = errors.New("invalid log level")
+)
+
+type LogLevelUsecase interface {
+	GetLogLevels(ctx context.Context) *dto.LogLevelsResponse
+	SetLogLevel(ctx context.Context, pkg string, req *dto.SetLogLevelRequest) (*dto.LogLevelsResponse, error)
+}
+
+type logLevelUsecase struct {
+	db           *gorm.DB
+	log          *logrus.Logger
+	logs         *logger.Manager
+	auditService service.AuditService
+}
+
+func NewLogLevelUsecase(
+	db *gorm.DB,
+	log *logrus.Logger,
+	logs *logger.Manager,
+	auditService service.AuditService,
+) LogLevelUsecase {
+	return &logLevelUsecase{
+		db:           db,
+		log:          log,
+		logs:         logs,
+		auditService: auditService,
+	}
+}
+
+func (u *logLevelUsecase) GetLogLevels(ctx context.Context) *dto.LogLevelsResponse {
+	level, packages := u.logs.Levels()
+
+	response := &dto.LogLevelsResponse{
+		Level:    level.String(),
+		Packages: make(map[string]string, len(packages)),
+	}
+	for pkg, pkgLevel := range packages {
+		response.Packages[pkg] = pkgLevel.String()
+	}
+	return response
+}
+
+// SetLogLevel changes the level of one package until the next restart or SIGHUP reload.
+// The "default" package changes the level of every package without an override.
+func (u *logLevelUsecase) SetLogLevel(ctx context.Context, pkg string, req *dto.SetLogLevelRequest) (*dto.LogLevelsResponse, error) {
+	level, err := logrus.ParseLevel(req.Level)
+	if err != nil {
+		return nil, ErrInvalidLogLevel
+	}
+
+	oldValue := u.GetLogLevels(ctx)
+	if err := u.logs.SetLevel(pkg, level); err != nil {
+		if errors.Is(err, logger.ErrUnknownPackage) {
+			return nil, ErrUnknownLogPackage
+		}
+		return nil, err
+	}
+	newValue := u.GetLogLevels(ctx)
+
+	u.log.Infof("Log level of package %s set to %s", pkg, level)
+
+	// Audit log
+	userID, _ := middleware.GetUserIDFromContext(ctx)
+	if err := u.auditService.LogUpdate(ctx, u.db.WithContext(ctx), &userID, entity.AuditActionLogLevelUpdate, "log_level", pkg, oldValue, newValue); err != nil {
+		u.log.Warnf("Failed to create audit log: %+v", err)
+	}
+
+	return newValue, nil
+}
@@ -0,0 +1,346 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"reflect"
+
+	"go-template-clean-architecture/internal/converter"
+	"go-template-clean-architecture/internal/delivery/dto"
+	"go-template-clean-architecture/internal/delivery/http/middleware"
+	"go-template-clean-architecture/internal/domain/entity"
+	"go-template-clean-architecture/internal/domain/repository"
+	"go-template-clean-architecture/internal/service"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrMedicalRecordNotFound      = errors.New("medical record not found")
+	ErrMedicalRecordExists        = errors.New("the booking already has a medical record")
+	ErrMedicalRecordVisitNotFound = errors.New("the patient has not been called for this booking")
+	ErrMedicalRecordConflict      = errors.New("medical record was modified concurrently, reload and try again")
+	ErrPatientHistoryNotFound     = errors.New("patient not found among your patients")
+)
+
+// MedicalRecordUsecase manages the visit notes of bookings.
+//
+// Access is limited to the doctor of the booking (who writes the record) and the
+// patient (read only); records of others are reported as not found. Doctors the patient
+// has (or had) a booking with may read the patient's visit history, records written by
+// other doctors included. Every write and read is audited, without the clinical content.
+type MedicalRecordUsecase interface {
+	CreateRecord(ctx context.Context, bookingID uuid.UUID, req *dto.MedicalRecordRequest) (*dto.MedicalRecordResponse, error)
+	UpdateRecord(ctx context.Context, bookingID uuid.UUID, req *dto.UpdateMedicalRecordRequest) (*dto.MedicalRecordResponse, error)
+	GetDoctorRecord(ctx context.Context, bookingID uuid.UUID) (*dto.MedicalRecordResponse, error)
+	GetMyRecord(ctx context.Context, bookingID uuid.UUID) (*dto.MedicalRecordResponse, error)
+	GetMyRecords(ctx context.Context, page, limit int) ([]dto.MedicalRecordResponse, int64, error)
+	GetPatientHistory(ctx context.Context, patientID uuid.UUID, page, limit int) (*dto.PatientHistoryResponse, int64, error)
+}
+
+type medicalRecordUsecase struct {
+	db                *gorm.DB
+	log               *logrus.Logger
+	medicalRecordRepo repository.MedicalRecordRepository
+	bookingRepo       repository.BookingRepository
+	auditService      service.AuditService
+	userRepo          repository.UserRepository
+}
+
+func NewMedicalRecordUsecase(
+	db *gorm.DB,
+	log *logrus.Logger,
+	medicalRecordRepo repository.MedicalRecordRepository,
+	bookingRepo repository.BookingRepository,
+	auditService service.AuditService,
+	userRepo repository.UserRepository,
+) MedicalRecordUsecase {
+	return &medicalRecordUsecase{
+		db:                db,
+		log:               log,
+		medicalRecordRepo: medicalRecordRepo,
+		bookingRepo:       bookingRepo,
+		auditService:      auditService,
+		userRepo:          userRepo,
+	}
+}
+
+// CreateRecord writes the visit notes of a booking of the logged-in doctor.
+// The booking must be active and its queue number called (the patient was seen).
+func (u *medicalRecordUsecase) CreateRecord(ctx context.Context, bookingID uuid.UUID, req *dto.MedicalRecordRequest) (*dto.MedicalRecordResponse, error) {
+	doctorID, ok := middleware.GetUserIDFromContext(ctx)
+	if !ok {
+		return nil, errors.New("user not found in context")
+	}
+
+	tx := u.db.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	booking, err := u.bookingRepo.FindByID(tx, bookingID)
+	if err != nil {
+		u.log.Warnf("Failed to find booking %s: %+v", bookingID, err)
+		return nil, err
+	}
+	if booking == nil || booking.Schedule.DoctorID != doctorID {
+		return nil, ErrBookingNotFound
+	}
+	if booking.IsCancelled() || !booking.IsCalled() {
+		return nil, ErrMedicalRecordVisitNotFound
+	}
+
+	record := &entity.MedicalRecord{
+		BookingID:     booking.ID,
+		PatientID:     booking.PatientID,
+		DoctorID:      doctorID,
+		ScheduleID:    booking.ScheduleID,
+		Diagnosis:     req.Diagnosis,
+		ClinicalNotes: req.ClinicalNotes,
+		Vitals:        converter.MedicalRecordVitalsToEntity(req.Vitals),
+		Version:       1,
+	}
+	if err := u.medicalRecordRepo.Create(tx, record); err != nil {
+		if isDuplicateKeyError(err, "medical_records_pkey") {
+			return nil, ErrMedicalRecordExists
+		}
+		u.log.Warnf("Failed to create medical record for booking %s: %+v", bookingID, err)
+		return nil, err
+	}
+
+	// Clinical content stays out of the audit trail, it is readable by every auditor
+	if err := u.auditService.LogCreate(ctx, tx, &doctorID, entity.AuditActionMedicalRecordCreate, "medical_record", bookingID.String(), entity.JSON{
+		"patient_id":  record.PatientID,
+		"schedule_id": record.ScheduleID,
+		"version":     record.Version,
+	}); err != nil {
+		u.log.Warnf("Failed to create audit log: %+v", err)
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		u.log.Warnf("Failed commit transaction: %+v", err)
+		return nil, err
+	}
+
+	return u.findRecord(ctx, bookingID)
+}
+
+// UpdateRecord replaces the visit notes at the given version, only the doctor who wrote them may
+func (u *medicalRecordUsecase) UpdateRecord(ctx context.Context, bookingID uuid.UUID, req *dto.UpdateMedicalRecordRequest) (*dto.MedicalRecordResponse, error) {
+	doctorID, ok := middleware.GetUserIDFromContext(ctx)
+	if !ok {
+		return nil, errors.New("user not found in context")
+	}
+
+	tx := u.db.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	record, err := u.medicalRecordRepo.FindByBookingID(tx, bookingID)
+	if err != nil {
+		u.log.Warnf("Failed to find medical record of booking %s: %+v", bookingID, err)
+		return nil, err
+	}
+	if record == nil || record.DoctorID != doctorID {
+		return nil, ErrMedicalRecordNotFound
+	}
+	if record.Version != req.Version {
+		return nil, ErrMedicalRecordConflict
+	}
+
+	vitals := converter.MedicalRecordVitalsToEntity(req.Vitals)
+	changed := make([]string, 0, 3)
+	if record.Diagnosis != req.Diagnosis {
+		changed = append(changed, "diagnosis")
+	}
+	if record.ClinicalNotes != req.ClinicalNotes {
+		changed = append(changed, "clinical_notes")
+	}
+	if !reflect.DeepEqual(record.Vitals, vitals) {
+		changed = append(changed, "vitals")
+	}
+	if len(changed) == 0 {
+		return converter.MedicalRecordToResponse(record), nil
+	}
+
+	oldVersion := record.Version
+	record.Diagnosis = req.Diagnosis
+	record.ClinicalNotes = req.ClinicalNotes
+	record.Vitals = vitals
+	updated, err := u.medicalRecordRepo.Update(tx, record, oldVersion)
+	if err != nil {
+		u.log.Warnf("Failed to update medical record of booking %s: %+v", bookingID, err)
+		return nil, err
+	}
+	if updated == 0 {
+		return nil, ErrMedicalRecordConflict
+	}
+
+	if err := u.auditService.LogUpdate(ctx, tx, &doctorID, entity.AuditActionMedicalRecordUpdate, "medical_record", bookingID.String(),
+		entity.JSON{"version": oldVersion},
+		entity.JSON{"version": record.Version, "changed_fields": changed},
+	); err != nil {
+		u.log.Warnf("Failed to create audit log: %+v", err)
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		u.log.Warnf("Failed commit transaction: %+v", err)
+		return nil, err
+	}
+
+	return converter.MedicalRecordToResponse(record), nil
+}
+
+// GetDoctorRecord returns the record of a booking to the doctor who wrote it
+func (u *medicalRecordUsecase) GetDoctorRecord(ctx context.Context, bookingID uuid.UUID) (*dto.MedicalRecordResponse, error) {
+	doctorID, ok := middleware.GetUserIDFromContext(ctx)
+	if !ok {
+		return nil, errors.New("user not found in context")
+	}
+
+	record, err := u.medicalRecordRepo.FindByBookingID(u.db.WithContext(ctx), bookingID)
+	if err != nil {
+		u.log.Warnf("Failed to find medical record of booking %s: %+v", bookingID, err)
+		return nil, err
+	}
+	if record == nil || record.DoctorID != doctorID {
+		return nil, ErrMedicalRecordNotFound
+	}
+
+	u.logView(ctx, doctorID, record)
+	return converter.MedicalRecordToResponse(record), nil
+}
+
+// GetMyRecord returns the record of one of the logged-in patient's bookings
+func (u *medicalRecordUsecase) GetMyRecord(ctx context.Context, bookingID uuid.UUID) (*dto.MedicalRecordResponse, error) {
+	patientID, ok := middleware.GetUserIDFromContext(ctx)
+	if !ok {
+		return nil, errors.New("user not found in context")
+	}
+
+	record, err := u.medicalRecordRepo.FindByBookingID(u.db.WithContext(ctx), bookingID)
+	if err != nil {
+		u.log.Warnf("Failed to find medical record of booking %s: %+v", bookingID, err)
+		return nil, err
+	}
+	if record == nil || record.PatientID != patientID {
+		return nil, ErrMedicalRecordNotFound
+	}
+
+	u.logView(ctx, patientID, record)
+	return converter.MedicalRecordToResponse(record), nil
+}
+
+// GetMyRecords returns one page of the logged-in patient's records, newest first
+func (u *medicalRecordUsecase) GetMyRecords(ctx context.Context, page, limit int) ([]dto.MedicalRecordResponse, int64, error) {
+	patientID, ok := middleware.GetUserIDFromContext(ctx)
+	if !ok {
+		return nil, 0, errors.New("user not found in context")
+	}
+
+	records, total, err := u.medicalRecordRepo.FindByPatientID(u.db.WithContext(ctx), patientID, page, limit)
+	if err != nil {
+		u.log.Warnf("Failed to find medical records of patient %s: %+v", patientID, err)
+		return nil, 0, err
+	}
+
+	for i := range records {
+		u.logView(ctx, patientID, &records[i])
+	}
+	return converter.MedicalRecordsToResponses(records), total, nil
+}
+
+// GetPatientHistory returns one page of a patient's bookings, newest visit first, with their
+// medical records, to the logged-in doctor. Only doctors with a non-cancelled booking of the
+// patient (past or upcoming) have access, other patients are reported as not found.
+// The access and every record shown are audited.
+func (u *medicalRecordUsecase) GetPatientHistory(ctx context.Context, patientID uuid.UUID, page, limit int) (*dto.PatientHistoryResponse, int64, error) {
+	doctorID, ok := middleware.GetUserIDFromContext(ctx)
+	if !ok {
+		return nil, 0, errors.New("user not found in context")
+	}
+
+	db := u.db.WithContext(ctx)
+	allowed, err := u.bookingRepo.ExistsByPatientAndDoctor(db, patientID, doctorID)
+	if err != nil {
+		u.log.Warnf("Failed to check bookings of patient %s with doctor %s: %+v", patientID, doctorID, err)
+		return nil, 0, err
+	}
+	if !allowed {
+		return nil, 0, ErrPatientHistoryNotFound
+	}
+
+	user, err := u.userRepo.FindByID(db, patientID)
+	if err != nil {
+		u.log.Warnf("Failed to find patient %s: %+v", patientID, err)
+		return nil, 0, err
+	}
+	if user == nil || user.IsErased() || user.PatientProfile == nil {
+		return nil, 0, ErrPatientHistoryNotFound
+	}
+
+	bookings, total, err := u.bookingRepo.FindHistoryByPatientID(db, patientID, page, limit)
+	if err != nil {
+		u.log.Warnf("Failed to find bookings of patient %s: %+v", patientID, err)
+		return nil, 0, err
+	}
+
+	bookingIDs := make([]uuid.UUID, len(bookings))
+	for i := range bookings {
+		bookingIDs[i] = bookings[i].ID
+	}
+	records, err := u.medicalRecordRepo.FindByBookingIDs(db, bookingIDs)
+	if err != nil {
+		u.log.Warnf("Failed to find medical records of patient %s: %+v", patientID, err)
+		return nil, 0, err
+	}
+	recordsByBooking := make(map[uuid.UUID]*entity.MedicalRecord, len(records))
+	for i := range records {
+		recordsByBooking[records[i].BookingID] = &records[i]
+	}
+
+	// Audit log - who looked at the patient's history, then each record shown
+	if err := u.auditService.LogCreate(ctx, db, &doctorID, entity.AuditActionPatientHistoryView, "patient", patientID.String(), entity.JSON{
+		"page":            page,
+		"visits":          len(bookings),
+		"medical_records": len(records),
+	}); err != nil {
+		u.log.Warnf("Failed to create audit log: %+v", err)
+	}
+
+	response := &dto.PatientHistoryResponse{
+		Patient: converter.PatientProfileToResponse(user.PatientProfile, user),
+		Visits:  make([]dto.PatientVisitResponse, len(bookings)),
+	}
+	for i := range bookings {
+		record := recordsByBooking[bookings[i].ID]
+		if record != nil {
+			u.logView(ctx, doctorID, record)
+		}
+		response.Visits[i] = converter.PatientVisitToResponse(&bookings[i], record)
+	}
+
+	return response, total, nil
+}
+
+// findRecord loads a record with the booking details of the response
+func (u *medicalRecordUsecase) findRecord(ctx context.Context, bookingID uuid.UUID) (*dto.MedicalRecordResponse, error) {
+	record, err := u.medicalRecordRepo.FindByBookingID(u.db.WithContext(ctx), bookingID)
+	if err != nil {
+		u.log.Warnf("Failed to find medical record of booking %s: %+v", bookingID, err)
+		return nil, err
+	}
+	if record == nil {
+		return nil, ErrMedicalRecordNotFound
+	}
+	return converter.MedicalRecordToResponse(record), nil
+}
+
+// logView records who read a medical record
+func (u *medicalRecordUsecase) logView(ctx context.Context, userID uuid.UUID, record *entity.MedicalRecord) {
+	if err := u.auditService.LogCreate(ctx, u.db.WithContext(ctx), &userID, entity.AuditActionMedicalRecordView, "medical_record", record.BookingID.String(), entity.JSON{
+		"patient_id": record.PatientID,
+		"version":    record.Version,
+	}); err != nil {
+		u.log.Warnf("Failed to create audit log: %+v", err)
+	}
+}
@@ -0,0 +1,257 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"go-template-clean-architecture/internal/converter"
+	"go-template-clean-architecture/internal/delivery/dto"
+	"go-template-clean-architecture/internal/delivery/http/middleware"
+	"go-template-clean-architecture/internal/domain/entity"
+	"go-template-clean-architecture/internal/domain/repository"
+	"go-template-clean-architecture/internal/service"
+	"go-template-clean-architecture/internal/service/notification"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrNotificationTemplateNotFound = errors.New("notification template not found")
+	ErrNotificationTemplateExists   = errors.New("a template already exists for this event type, channel and locale")
+	ErrInvalidNotificationTemplate  = errors.New("invalid notification template")
+)
+
+type NotificationTemplateUsecase interface {
+	CreateTemplate(ctx context.Context, req *dto.CreateNotificationTemplateRequest) (*dto.NotificationTemplateResponse, error)
+	GetTemplate(ctx context.Context, id int) (*dto.NotificationTemplateResponse, error)
+	GetAllTemplates(ctx context.Context, eventType string) (*dto.NotificationTemplateListResponse, error)
+	UpdateTemplate(ctx context.Context, id int, req *dto.UpdateNotificationTemplateRequest) (*dto.NotificationTemplateResponse, error)
+	DeleteTemplate(ctx context.Context, id int) error
+	PreviewTemplate(ctx context.Context, req *dto.PreviewNotificationTemplateRequest) (*dto.NotificationTemplatePreviewResponse, error)
+}
+
+type notificationTemplateUsecase struct {
+	db            *gorm.DB
+	log           *logrus.Logger
+	templateRepo  repository.NotificationTemplateRepository
+	auditService  service.AuditService
+	formatService service.FormatService
+}
+
+func NewNotificationTemplateUsecase(
+	db *gorm.DB,
+	log *logrus.Logger,
+	templateRepo repository.NotificationTemplateRepository,
+	auditService service.AuditService,
+	formatService service.FormatService,
+) NotificationTemplateUsecase {
+	return &notificationTemplateUsecase{
+		db:            db,
+		log:           log,
+		templateRepo:  templateRepo,
+		auditService:  auditService,
+		formatService: formatService,
+	}
+}
+
+// CreateTemplate adds the template of an event type, channel and locale
+func (u *notificationTemplateUsecase) CreateTemplate(ctx context.Context, req *dto.CreateNotificationTemplateRequest) (*dto.NotificationTemplateResponse, error) {
+	if err := validateNotificationTemplate(req.Subject, req.Body); err != nil {
+		return nil, err
+	}
+
+	tx := u.db.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	userID, _ := middleware.GetUserIDFromContext(ctx)
+	template := &entity.NotificationTemplate{
+		EventType: strings.TrimSpace(req.EventType),
+		Channel:   req.Channel,
+		Locale:    strings.TrimSpace(req.Locale),
+		Subject:   req.Subject,
+		Body:      req.Body,
+		UpdatedBy: &userID,
+	}
+	if err := u.templateRepo.Create(tx, template); err != nil {
+		u.log.Warnf("Failed to create notification template: %+v", err)
+		if isDuplicateKeyError(err, "uq_notification_templates_variant") {
+			return nil, ErrNotificationTemplateExists
+		}
+		return nil, err
+	}
+
+	// Audit log - create notification template
+	response := converter.NotificationTemplateToResponse(template)
+	if err := u.auditService.LogCreate(ctx, tx, &userID, entity.AuditActionNotificationTemplateCreate, "notification_template", strconv.Itoa(template.ID), response); err != nil {
+		u.log.Warnf("Failed to create audit log: %+v", err)
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		u.log.Warnf("Failed commit transaction: %+v", err)
+		return nil, err
+	}
+
+	return response, nil
+}
+
+func (u *notificationTemplateUsecase) GetTemplate(ctx context.Context, id int) (*dto.NotificationTemplateResponse, error) {
+	template, err := u.templateRepo.FindByID(u.db.WithContext(ctx), id)
+	if err != nil {
+		u.log.Warnf("Failed to find notification template %d: %+v", id, err)
+		return nil, err
+	}
+	if template == nil {
+		return nil, ErrNotificationTemplateNotFound
+	}
+
+	return converter.NotificationTemplateToResponse(template), nil
+}
+
+// GetAllTemplates returns the templates, optionally of one event type
+func (u *notificationTemplateUsecase) GetAllTemplates(ctx context.Context, eventType string) (*dto.NotificationTemplateListResponse, error) {
+	templates, err := u.templateRepo.FindAll(u.db.WithContext(ctx), eventType)
+	if err != nil {
+		u.log.Warnf("Failed to find notification templates: %+v", err)
+		return nil, err
+	}
+
+	return &dto.NotificationTemplateListResponse{
+		Templates: converter.NotificationTemplatesToResponses(templates),
+		Total:     len(templates),
+	}, nil
+}
+
+// UpdateTemplate changes the content of a template. Notifications recorded before keep their text.
+func (u *notificationTemplateUsecase) UpdateTemplate(ctx context.Context, id int, req *dto.UpdateNotificationTemplateRequest) (*dto.NotificationTemplateResponse, error) {
+	tx := u.db.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	template, err := u.templateRepo.FindByID(tx, id)
+	if err != nil {
+		u.log.Warnf("Failed to find notification template %d: %+v", id, err)
+		return nil, err
+	}
+	if template == nil {
+		return nil, ErrNotificationTemplateNotFound
+	}
+
+	oldValue := converter.NotificationTemplateToResponse(template)
+
+	if req.Subject != nil {
+		template.Subject = *req.Subject
+	}
+	if req.Body != "" {
+		template.Body = req.Body
+	}
+	if err := validateNotificationTemplate(template.Subject, template.Body); err != nil {
+		return nil, err
+	}
+
+	userID, _ := middleware.GetUserIDFromContext(ctx)
+	template.UpdatedBy = &userID
+	if err := u.templateRepo.Update(tx, template); err != nil {
+		u.log.Warnf("Failed to update notification template: %+v", err)
+		return nil, err
+	}
+
+	// Audit log - update notification template
+	newValue := converter.NotificationTemplateToResponse(template)
+	if err := u.auditService.LogUpdate(ctx, tx, &userID, entity.AuditActionNotificationTemplateUpdate, "notification_template", strconv.Itoa(id), oldValue, newValue); err != nil {
+		u.log.Warnf("Failed to create audit log: %+v", err)
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		u.log.Warnf("Failed commit transaction: %+v", err)
+		return nil, err
+	}
+
+	return newValue, nil
+}
+
+// DeleteTemplate removes a template, its notifications fall back to a less specific one or the default text
+func (u *notificationTemplateUsecase) DeleteTemplate(ctx context.Context, id int) error {
+	tx := u.db.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	template, err := u.templateRepo.FindByID(tx, id)
+	if err != nil {
+		u.log.Warnf("Failed to find notification template %d: %+v", id, err)
+		return err
+	}
+	if template == nil {
+		return ErrNotificationTemplateNotFound
+	}
+
+	if _, err := u.templateRepo.Delete(tx, id); err != nil {
+		u.log.Warnf("Failed to delete notification template: %+v", err)
+		return err
+	}
+
+	// Audit log - delete notification template
+	userID, _ := middleware.GetUserIDFromContext(ctx)
+	if err := u.auditService.LogDelete(ctx, tx, &userID, entity.AuditActionNotificationTemplateDelete, "notification_template", strconv.Itoa(id), converter.NotificationTemplateToResponse(template)); err != nil {
+		u.log.Warnf("Failed to create audit log: %+v", err)
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		u.log.Warnf("Failed commit transaction: %+v", err)
+		return err
+	}
+
+	return nil
+}
+
+// PreviewTemplate renders a template with sample values of every variable, overridden by req.Data
+func (u *notificationTemplateUsecase) PreviewTemplate(ctx context.Context, req *dto.PreviewNotificationTemplateRequest) (*dto.NotificationTemplatePreviewResponse, error) {
+	tomorrow := time.Now().AddDate(0, 0, 1)
+	sample := &entity.DoctorSchedule{ScheduleDate: tomorrow, StartTime: "08:00", EndTime: "12:00"}
+	earlier := &entity.DoctorSchedule{ScheduleDate: tomorrow.AddDate(0, 0, -1), StartTime: "13:00", EndTime: "16:00"}
+
+	data := map[string]string{
+		entity.TemplateVarPatientName:  "Budi Santoso",
+		entity.TemplateVarSubject:      "Booking confirmed: BK-" + tomorrow.Format("20060102") + "-A1B2C3",
+		entity.TemplateVarMessage:      "Your booking is confirmed.",
+		entity.TemplateVarBookingCode:  "BK-" + tomorrow.Format("20060102") + "-A1B2C3",
+		entity.TemplateVarQueueNumber:  "7",
+		entity.TemplateVarDoctorName:   "dr. Siti Rahma, Sp.A",
+		entity.TemplateVarScheduleSlot: u.formatService.ScheduleSlot(sample),
+		entity.TemplateVarOldSlot:      u.formatService.ScheduleSlot(earlier),
+		entity.TemplateVarTitle:        "Clinic announcement",
+	}
+	for name, value := range req.Data {
+		data[name] = value
+	}
+
+	body, err := notification.Render("body", req.Body, data)
+	if err != nil {
+		u.log.Debugf("Failed to render notification template preview: %+v", err)
+		return nil, ErrInvalidNotificationTemplate
+	}
+	subject := data[entity.TemplateVarSubject]
+	if req.Subject != "" {
+		if subject, err = notification.Render("subject", req.Subject, data); err != nil {
+			u.log.Debugf("Failed to render notification template preview: %+v", err)
+			return nil, ErrInvalidNotificationTemplate
+		}
+	}
+
+	return &dto.NotificationTemplatePreviewResponse{
+		Subject: subject,
+		Body:    body,
+	}, nil
+}
+
+// validateNotificationTemplate checks that the subject and body parse as templates
+func validateNotificationTemplate(subject, body string) error {
+	if _, err := notification.ParseTemplate("subject", subject); err != nil {
+		return ErrInvalidNotificationTemplate
+	}
+	if _, err := notification.ParseTemplate("body", body); err != nil {
+		return ErrInvalidNotificationTemplate
+	}
+	return nil
+}
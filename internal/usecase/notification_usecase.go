@@ -0,0 +1,532 @@
+package usecase
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"time"
+
+	"go-template-clean-architecture/config"
+	"go-template-clean-architecture/internal/converter"
+	"go-template-clean-architecture/internal/delivery/dto"
+	"go-template-clean-architecture/internal/delivery/http/middleware"
+	"go-template-clean-architecture/internal/domain/entity"
+	"go-template-clean-architecture/internal/domain/repository"
+	"go-template-clean-architecture/internal/service"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrInvalidWebhookSecret      = errors.New("invalid webhook secret")
+	ErrNotificationNotFound      = errors.New("notification not found")
+	ErrDeviceNotFound            = errors.New("device not found")
+	ErrNotDeadLetter             = errors.New("notification is not a dead letter")
+	ErrInvalidNotificationFilter = errors.New("invalid notification filter")
+)
+
+// Dead letters re-driven by one bulk request when no limit is given
+const defaultRedriveLimit = 100
+
+type NotificationUsecase interface {
+	HandleDeliveryReport(ctx context.Context, provider string, secret string, req *dto.NotificationDeliveryReportRequest) error
+	GetBookingNotifications(ctx context.Context, bookingID uuid.UUID) (*dto.NotificationListResponse, error)
+	GetMyBookingNotifications(ctx context.Context, bookingID uuid.UUID) (*dto.NotificationListResponse, error)
+	RegisterDevice(ctx context.Context, userID uuid.UUID, req *dto.RegisterDeviceRequest) (*dto.DeviceResponse, error)
+	GetDevices(ctx context.Context, userID uuid.UUID) ([]dto.DeviceResponse, error)
+	UnregisterDevice(ctx context.Context, userID uuid.UUID, id int) error
+	GetInAppNotifications(ctx context.Context, userID uuid.UUID, unreadOnly bool, page, limit int) ([]dto.InAppNotificationResponse, int64, error)
+	GetUnreadCount(ctx context.Context, userID uuid.UUID) (*dto.UnreadNotificationCountResponse, error)
+	MarkRead(ctx context.Context, userID, id uuid.UUID) error
+	MarkAllRead(ctx context.Context, userID uuid.UUID) (*dto.UnreadNotificationCountResponse, error)
+	DeleteInAppNotification(ctx context.Context, userID, id uuid.UUID) error
+	GetPreference(ctx context.Context, userID uuid.UUID) (*dto.NotificationPreferenceResponse, error)
+	UpdatePreference(ctx context.Context, userID uuid.UUID, req *dto.UpdateNotificationPreferenceRequest) (*dto.NotificationPreferenceResponse, error)
+	GetDeadLetters(ctx context.Context, channel string, eventType string, page, limit int) ([]dto.NotificationResponse, int64, error)
+	GetNotification(ctx context.Context, id uuid.UUID) (*dto.NotificationDetailResponse, error)
+	GetPatientNotifications(ctx context.Context, patientID uuid.UUID, filter *dto.NotificationFilter, page, limit int) ([]dto.NotificationResponse, int64, error)
+	RedriveNotification(ctx context.Context, id uuid.UUID) (*dto.NotificationResponse, error)
+	RedriveNotifications(ctx context.Context, req *dto.RedriveNotificationsRequest) (*dto.RedriveNotificationsResponse, error)
+}
+
+type notificationUsecase struct {
+	db                  *gorm.DB
+	log                 *logrus.Logger
+	cfg                 *config.Config
+	notificationRepo    repository.NotificationRepository
+	bookingRepo         repository.BookingRepository
+	deviceTokenRepo     repository.DeviceTokenRepository
+	preferenceRepo      repository.NotificationPreferenceRepository
+	notificationService *service.NotificationService
+	auditService        service.AuditService
+}
+
+func NewNotificationUsecase(
+	db *gorm.DB,
+	log *logrus.Logger,
+	cfg *config.Config,
+	notificationRepo repository.NotificationRepository,
+	bookingRepo repository.BookingRepository,
+	deviceTokenRepo repository.DeviceTokenRepository,
+	preferenceRepo repository.NotificationPreferenceRepository,
+	notificationService *service.NotificationService,
+	auditService service.AuditService,
+) NotificationUsecase {
+	return &notificationUsecase{
+		db:                  db,
+		log:                 log,
+		cfg:                 cfg,
+		notificationRepo:    notificationRepo,
+		bookingRepo:         bookingRepo,
+		deviceTokenRepo:     deviceTokenRepo,
+		preferenceRepo:      preferenceRepo,
+		notificationService: notificationService,
+		auditService:        auditService,
+	}
+}
+
+// HandleDeliveryReport records a provider callback confirming delivery or reporting a failure
+// or bounce. Callbacks for notifications that already reached a final status do not change it,
+// so providers may safely repeat them; every callback is kept for troubleshooting.
+func (u *notificationUsecase) HandleDeliveryReport(ctx context.Context, provider string, secret string, req *dto.NotificationDeliveryReportRequest) error {
+	expected := u.cfg.Notification.WebhookSecret
+	if expected == "" || subtle.ConstantTimeCompare([]byte(secret), []byte(expected)) != 1 {
+		return ErrInvalidWebhookSecret
+	}
+
+	notification, err := u.notificationRepo.FindByProviderMessageID(u.db.WithContext(ctx), provider, req.MessageID)
+	if err != nil {
+		u.log.Warnf("Failed to find notification by provider message %s/%s: %+v", provider, req.MessageID, err)
+		return err
+	}
+	if notification == nil {
+		return ErrNotificationNotFound
+	}
+
+	tx := u.db.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	status := entity.NotificationStatus(req.Status)
+	updated, err := u.notificationRepo.MarkDeliveryReport(tx, notification.ID, status, req.Error, time.Now())
+	if err != nil {
+		u.log.Warnf("Failed to record delivery report of notification %s: %+v", notification.ID, err)
+		return err
+	}
+
+	// Kept also when ignored, so support sees every callback the provider made
+	report := &entity.NotificationDeliveryReport{
+		NotificationID: notification.ID,
+		Provider:       provider,
+		Status:         status,
+		Error:          req.Error,
+		Applied:        updated > 0,
+	}
+	if err := u.notificationRepo.CreateDeliveryReport(tx, report); err != nil {
+		u.log.Warnf("Failed to record delivery report of notification %s: %+v", notification.ID, err)
+		return err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		u.log.Warnf("Failed commit transaction: %+v", err)
+		return err
+	}
+
+	if updated == 0 {
+		u.log.Debugf("Ignoring %s delivery report of notification %s, status is already %s", status, notification.ID, notification.Status)
+		return nil
+	}
+
+	if status != entity.NotificationStatusDelivered {
+		u.log.Warnf("Notification %s to patient %s was not delivered (%s): %s", notification.ID, notification.PatientID, status, req.Error)
+	}
+	return nil
+}
+
+// GetBookingNotifications returns every notification sent about a booking (admin)
+func (u *notificationUsecase) GetBookingNotifications(ctx context.Context, bookingID uuid.UUID) (*dto.NotificationListResponse, error) {
+	booking, err := u.bookingRepo.FindByID(u.db.WithContext(ctx), bookingID)
+	if err != nil {
+		u.log.Warnf("Failed to find booking %s: %+v", bookingID, err)
+		return nil, err
+	}
+	if booking == nil {
+		return nil, ErrBookingNotFound
+	}
+
+	return u.findBookingNotifications(ctx, bookingID)
+}
+
+// GetMyBookingNotifications returns the notifications of one of the logged-in patient's bookings
+func (u *notificationUsecase) GetMyBookingNotifications(ctx context.Context, bookingID uuid.UUID) (*dto.NotificationListResponse, error) {
+	userID, ok := middleware.GetUserIDFromContext(ctx)
+	if !ok {
+		return nil, errors.New("user not found in context")
+	}
+
+	booking, err := u.bookingRepo.FindByID(u.db.WithContext(ctx), bookingID)
+	if err != nil {
+		u.log.Warnf("Failed to find booking %s: %+v", bookingID, err)
+		return nil, err
+	}
+	if booking == nil {
+		return nil, ErrBookingNotFound
+	}
+	if booking.PatientID != userID {
+		return nil, ErrBookingNotOwned
+	}
+
+	return u.findBookingNotifications(ctx, bookingID)
+}
+
+func (u *notificationUsecase) findBookingNotifications(ctx context.Context, bookingID uuid.UUID) (*dto.NotificationListResponse, error) {
+	notifications, err := u.notificationRepo.FindByBookingID(u.db.WithContext(ctx), bookingID)
+	if err != nil {
+		u.log.Warnf("Failed to find notifications of booking %s: %+v", bookingID, err)
+		return nil, err
+	}
+
+	return &dto.NotificationListResponse{
+		Notifications: converter.NotificationsToResponses(notifications),
+		Total:         len(notifications),
+	}, nil
+}
+
+// RegisterDevice registers the push token of the user's mobile client. Clients call it after
+// every sign-in and token refresh; a token registered by another user moves to this one.
+func (u *notificationUsecase) RegisterDevice(ctx context.Context, userID uuid.UUID, req *dto.RegisterDeviceRequest) (*dto.DeviceResponse, error) {
+	device := &entity.DeviceToken{
+		UserID:   userID,
+		Token:    req.Token,
+		Platform: req.Platform,
+	}
+	if err := u.deviceTokenRepo.Upsert(u.db.WithContext(ctx), device); err != nil {
+		u.log.Warnf("Failed to register device of user %s: %+v", userID, err)
+		return nil, err
+	}
+
+	return converter.DeviceTokenToResponse(device), nil
+}
+
+// GetDevices returns the push devices of the user
+func (u *notificationUsecase) GetDevices(ctx context.Context, userID uuid.UUID) ([]dto.DeviceResponse, error) {
+	devices, err := u.deviceTokenRepo.FindByUserID(u.db.WithContext(ctx), userID)
+	if err != nil {
+		u.log.Warnf("Failed to find devices of user %s: %+v", userID, err)
+		return nil, err
+	}
+
+	return converter.DeviceTokensToResponses(devices), nil
+}
+
+// UnregisterDevice stops pushes to one of the user's devices, e.g. on sign-out
+func (u *notificationUsecase) UnregisterDevice(ctx context.Context, userID uuid.UUID, id int) error {
+	deleted, err := u.deviceTokenRepo.DeleteByUserAndID(u.db.WithContext(ctx), userID, id)
+	if err != nil {
+		u.log.Warnf("Failed to delete device %d: %+v", id, err)
+		return err
+	}
+	if deleted == 0 {
+		return ErrDeviceNotFound
+	}
+	return nil
+}
+
+// GetInAppNotifications returns one page of the user's notification center, newest first
+func (u *notificationUsecase) GetInAppNotifications(ctx context.Context, userID uuid.UUID, unreadOnly bool, page, limit int) ([]dto.InAppNotificationResponse, int64, error) {
+	notifications, total, err := u.notificationRepo.FindInApp(u.db.WithContext(ctx), userID, unreadOnly, page, limit)
+	if err != nil {
+		u.log.Warnf("Failed to find in-app notifications of user %s: %+v", userID, err)
+		return nil, 0, err
+	}
+
+	return converter.InAppNotificationsToResponses(notifications), total, nil
+}
+
+// GetUnreadCount returns how many notifications of the user's notification center are unread
+func (u *notificationUsecase) GetUnreadCount(ctx context.Context, userID uuid.UUID) (*dto.UnreadNotificationCountResponse, error) {
+	unread, err := u.notificationRepo.CountUnreadInApp(u.db.WithContext(ctx), userID)
+	if err != nil {
+		u.log.Warnf("Failed to count unread notifications of user %s: %+v", userID, err)
+		return nil, err
+	}
+
+	return &dto.UnreadNotificationCountResponse{Unread: unread}, nil
+}
+
+// MarkRead marks a notification of the user's notification center read, reading it again keeps the first read time
+func (u *notificationUsecase) MarkRead(ctx context.Context, userID, id uuid.UUID) error {
+	updated, err := u.notificationRepo.MarkInAppRead(u.db.WithContext(ctx), userID, id, time.Now())
+	if err != nil {
+		u.log.Warnf("Failed to mark notification %s read: %+v", id, err)
+		return err
+	}
+	if updated == 0 {
+		return ErrNotificationNotFound
+	}
+	return nil
+}
+
+// MarkAllRead marks every notification of the user's notification center read
+func (u *notificationUsecase) MarkAllRead(ctx context.Context, userID uuid.UUID) (*dto.UnreadNotificationCountResponse, error) {
+	if _, err := u.notificationRepo.MarkAllInAppRead(u.db.WithContext(ctx), userID, time.Now()); err != nil {
+		u.log.Warnf("Failed to mark notifications of user %s read: %+v", userID, err)
+		return nil, err
+	}
+
+	return &dto.UnreadNotificationCountResponse{Unread: 0}, nil
+}
+
+// DeleteInAppNotification removes a notification from the user's notification center
+func (u *notificationUsecase) DeleteInAppNotification(ctx context.Context, userID, id uuid.UUID) error {
+	deleted, err := u.notificationRepo.DeleteInApp(u.db.WithContext(ctx), userID, id)
+	if err != nil {
+		u.log.Warnf("Failed to delete notification %s: %+v", id, err)
+		return err
+	}
+	if deleted == 0 {
+		return ErrNotificationNotFound
+	}
+	return nil
+}
+
+// GetPreference returns how the user wants to be notified, the defaults when never changed
+func (u *notificationUsecase) GetPreference(ctx context.Context, userID uuid.UUID) (*dto.NotificationPreferenceResponse, error) {
+	preference, err := u.preferenceRepo.FindByUserID(u.db.WithContext(ctx), userID)
+	if err != nil {
+		u.log.Warnf("Failed to find notification preference of user %s: %+v", userID, err)
+		return nil, err
+	}
+	if preference == nil {
+		preference = entity.DefaultNotificationPreference(userID)
+	}
+
+	return converter.NotificationPreferenceToResponse(preference), nil
+}
+
+// UpdatePreference changes the preferred channel, the appointment reminder opt-in and the
+// daily agenda opt-in of doctors
+func (u *notificationUsecase) UpdatePreference(ctx context.Context, userID uuid.UUID, req *dto.UpdateNotificationPreferenceRequest) (*dto.NotificationPreferenceResponse, error) {
+	tx := u.db.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	preference, err := u.preferenceRepo.FindByUserID(tx, userID)
+	if err != nil {
+		u.log.Warnf("Failed to find notification preference of user %s: %+v", userID, err)
+		return nil, err
+	}
+	if preference == nil {
+		preference = entity.DefaultNotificationPreference(userID)
+	}
+
+	if req.Channel != nil {
+		preference.Channel = *req.Channel
+	}
+	if req.Reminders != nil {
+		preference.Reminders = *req.Reminders
+	}
+	if req.AgendaDigest != nil {
+		preference.AgendaDigest = *req.AgendaDigest
+	}
+
+	if err := u.preferenceRepo.Upsert(tx, preference); err != nil {
+		u.log.Warnf("Failed to update notification preference of user %s: %+v", userID, err)
+		return nil, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		u.log.Warnf("Failed commit transaction: %+v", err)
+		return nil, err
+	}
+
+	return converter.NotificationPreferenceToResponse(preference), nil
+}
+
+// GetDeadLetters returns one page of the dead-lettered notifications, most recently failed first
+func (u *notificationUsecase) GetDeadLetters(ctx context.Context, channel string, eventType string, page, limit int) ([]dto.NotificationResponse, int64, error) {
+	notifications, total, err := u.notificationRepo.FindDeadLetters(u.db.WithContext(ctx), channel, eventType, page, limit)
+	if err != nil {
+		u.log.Warnf("Failed to find dead-lettered notifications: %+v", err)
+		return nil, 0, err
+	}
+
+	return converter.NotificationsToResponses(notifications), total, nil
+}
+
+// GetNotification returns a notification with its send attempts and provider callbacks (admin)
+func (u *notificationUsecase) GetNotification(ctx context.Context, id uuid.UUID) (*dto.NotificationDetailResponse, error) {
+	db := u.db.WithContext(ctx)
+	notification, err := u.notificationRepo.FindByID(db, id)
+	if err != nil {
+		u.log.Warnf("Failed to find notification %s: %+v", id, err)
+		return nil, err
+	}
+	if notification == nil {
+		return nil, ErrNotificationNotFound
+	}
+
+	attempts, err := u.notificationRepo.FindAttempts(db, id)
+	if err != nil {
+		u.log.Warnf("Failed to find attempts of notification %s: %+v", id, err)
+		return nil, err
+	}
+
+	reports, err := u.notificationRepo.FindDeliveryReports(db, id)
+	if err != nil {
+		u.log.Warnf("Failed to find delivery reports of notification %s: %+v", id, err)
+		return nil, err
+	}
+
+	return &dto.NotificationDetailResponse{
+		NotificationResponse: converter.NotificationToResponse(notification),
+		AttemptLog:           converter.NotificationAttemptsToResponses(attempts),
+		DeliveryReports:      converter.NotificationDeliveryReportsToResponses(reports),
+	}, nil
+}
+
+// GetPatientNotifications returns one page of a patient's notifications on every channel, newest first (admin support)
+func (u *notificationUsecase) GetPatientNotifications(ctx context.Context, patientID uuid.UUID, filter *dto.NotificationFilter, page, limit int) ([]dto.NotificationResponse, int64, error) {
+	domainFilter, err := u.notificationFilter(filter)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	notifications, total, err := u.notificationRepo.FindByPatientID(u.db.WithContext(ctx), patientID, domainFilter, page, limit)
+	if err != nil {
+		u.log.Warnf("Failed to find notifications of patient %s: %+v", patientID, err)
+		return nil, 0, err
+	}
+
+	return converter.NotificationsToResponses(notifications), total, nil
+}
+
+// notificationFilter validates the query filter, dates are days in the clinic time zone
+func (u *notificationUsecase) notificationFilter(filter *dto.NotificationFilter) (*entity.NotificationFilter, error) {
+	domainFilter := &entity.NotificationFilter{
+		Status:    entity.NotificationStatus(filter.Status),
+		EventType: filter.EventType,
+		Channel:   filter.Channel,
+	}
+
+	switch domainFilter.Status {
+	case "", entity.NotificationStatusPending, entity.NotificationStatusSent, entity.NotificationStatusDelivered,
+		entity.NotificationStatusFailed, entity.NotificationStatusBounced, entity.NotificationStatusDeadLetter:
+	default:
+		return nil, ErrInvalidNotificationFilter
+	}
+
+	if filter.From != "" {
+		from, err := time.ParseInLocation("2006-01-02", filter.From, u.cfg.App.Location)
+		if err != nil {
+			return nil, ErrInvalidNotificationFilter
+		}
+		domainFilter.From = &from
+	}
+	if filter.To != "" {
+		to, err := time.ParseInLocation("2006-01-02", filter.To, u.cfg.App.Location)
+		if err != nil {
+			return nil, ErrInvalidNotificationFilter
+		}
+		to = to.AddDate(0, 0, 1)
+		domainFilter.To = &to
+	}
+	return domainFilter, nil
+}
+
+// RedriveNotification queues a dead letter again, the worker sends it with a fresh attempt budget
+func (u *notificationUsecase) RedriveNotification(ctx context.Context, id uuid.UUID) (*dto.NotificationResponse, error) {
+	tx := u.db.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	notification, err := u.notificationRepo.FindByID(tx, id)
+	if err != nil {
+		u.log.Warnf("Failed to find notification %s: %+v", id, err)
+		return nil, err
+	}
+	if notification == nil {
+		return nil, ErrNotificationNotFound
+	}
+	if notification.Status != entity.NotificationStatusDeadLetter {
+		return nil, ErrNotDeadLetter
+	}
+
+	redriven, err := u.redrive(ctx, tx, notification)
+	if err != nil {
+		return nil, err
+	}
+	if redriven == nil {
+		return nil, ErrNotDeadLetter
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		u.log.Warnf("Failed commit transaction: %+v", err)
+		return nil, err
+	}
+
+	return converter.NotificationToResponse(redriven), nil
+}
+
+// RedriveNotifications queues the most recently failed dead letters matching the filters again
+func (u *notificationUsecase) RedriveNotifications(ctx context.Context, req *dto.RedriveNotificationsRequest) (*dto.RedriveNotificationsResponse, error) {
+	limit := req.Limit
+	if limit == 0 {
+		limit = defaultRedriveLimit
+	}
+
+	tx := u.db.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	notifications, _, err := u.notificationRepo.FindDeadLetters(tx, req.Channel, req.EventType, 1, limit)
+	if err != nil {
+		u.log.Warnf("Failed to find dead-lettered notifications: %+v", err)
+		return nil, err
+	}
+
+	count := 0
+	for i := range notifications {
+		redriven, err := u.redrive(ctx, tx, &notifications[i])
+		if err != nil {
+			return nil, err
+		}
+		if redriven != nil {
+			count++
+		}
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		u.log.Warnf("Failed commit transaction: %+v", err)
+		return nil, err
+	}
+
+	u.log.Infof("Re-drove %d dead-lettered notifications", count)
+	return &dto.RedriveNotificationsResponse{Redriven: count}, nil
+}
+
+// redrive queues the dead letter again and audits it. Returns the re-driven notification,
+// nil when it was re-driven meanwhile.
+func (u *notificationUsecase) redrive(ctx context.Context, tx *gorm.DB, notification *entity.Notification) (*entity.Notification, error) {
+	oldValue := converter.NotificationToResponse(notification)
+
+	redriven, err := u.notificationService.Redrive(tx, notification)
+	if err != nil {
+		u.log.Warnf("Failed to redrive notification %s: %+v", notification.ID, err)
+		return nil, err
+	}
+	if !redriven {
+		return nil, nil
+	}
+
+	updated, err := u.notificationRepo.FindByID(tx, notification.ID)
+	if err != nil {
+		u.log.Warnf("Failed to find notification %s: %+v", notification.ID, err)
+		return nil, err
+	}
+
+	// Audit log - redrive notification
+	userID, _ := middleware.GetUserIDFromContext(ctx)
+	if err := u.auditService.LogUpdate(ctx, tx, &userID, entity.AuditActionNotificationRedrive, "notification", notification.ID.String(), oldValue, converter.NotificationToResponse(updated)); err != nil {
+		u.log.Warnf("Failed to create audit log: %+v", err)
+	}
+	return updated, nil
+}
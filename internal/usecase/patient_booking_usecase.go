@@ -5,8 +5,12 @@ import (
 	"crypto/rand"
 	"errors"
 	"fmt"
+	"math"
+	"strconv"
+	"strings"
 	"time"
 
+	"go-template-clean-architecture/config"
 	"go-template-clean-architecture/internal/converter"
 	"go-template-clean-architecture/internal/delivery/dto"
 	"go-template-clean-architecture/internal/delivery/http/middleware"
@@ -24,37 +28,128 @@ var (
 	ErrAlreadyBooked           = errors.New("you have already booked this schedule")
 	ErrBookingAlreadyCancelled = errors.New("booking is already cancelled")
 	ErrBookingNotOwned         = errors.New("booking does not belong to you")
+	ErrBookingNotCancelled     = errors.New("only cancelled bookings can be deleted")
+	ErrBookingScheduleDeleted  = errors.New("schedule of the booking is deleted")
 	ErrSchedulePast            = errors.New("cannot book a past schedule")
+	ErrScheduleEnded           = errors.New("schedule has already ended")
+	ErrBookingClosed           = errors.New("booking is closed for this schedule")
+	ErrBookingTooFarAhead      = errors.New("schedule is beyond the advance booking window")
+	ErrBookingPausedAbsent     = errors.New("booking is paused: doctor possibly absent")
+	ErrScheduleClosed          = errors.New("booking is paused for this schedule")
+
+	ErrCancellationDeadlinePassed = errors.New("cancellation deadline has passed for this booking")
+
+	ErrWaitFeedbackTooEarly = errors.New("wait feedback is only accepted after the schedule has started")
+	ErrWaitFeedbackExists   = errors.New("wait feedback already submitted for this booking")
+
+	ErrNotWaitlisted = errors.New("you are not on the waitlist for this schedule")
+
+	ErrBookingVersionConflict = errors.New("booking was modified concurrently, reload and try again")
+	ErrQueueNumberConflict    = errors.New("could not assign a queue number, please try again")
+
+	ErrSlotRequired         = errors.New("slot_id is required for time-slot schedules")
+	ErrSlotNotAllowed       = errors.New("slot_id is only accepted for time-slot schedules")
+	ErrSlotNotFound         = errors.New("time slot not found for this schedule")
+	ErrWaitlistNotSupported = errors.New("waitlist is not available for time-slot schedules")
+
+	ErrInsuranceMissing  = errors.New("no insurance registered on your profile")
+	ErrInsuranceNotValid = errors.New("insurance is not valid on the schedule date")
+	ErrProfileIncomplete = errors.New("complete your profile before booking")
 )
 
+// maxWaitlistPromotionAttempts bounds how many waitlisted patients a freed slot is offered to
+// when creating the promoted booking keeps failing.
+const maxWaitlistPromotionAttempts = 3
+
+// maxQueueConflictRetries bounds how many times CreateBooking re-reserves a queue number
+// after the DB rejects a duplicate (Redis counter behind the bookings table).
+const maxQueueConflictRetries = 3
+
 type PatientBookingUsecase interface {
 	GetMyBookings(ctx context.Context) (*dto.BookingListResponse, error)
 	CreateBooking(ctx context.Context, req *dto.CreateBookingRequest) (*dto.BookingResponse, error)
 	CancelBooking(ctx context.Context, bookingID uuid.UUID) error
+	AdminCancelBooking(ctx context.Context, bookingID uuid.UUID) error
+	DeleteBooking(ctx context.Context, bookingID uuid.UUID) error
+	GetDeletedBookings(ctx context.Context, page, limit int) ([]dto.BookingResponse, int64, error)
+	RestoreBooking(ctx context.Context, bookingID uuid.UUID) (*dto.BookingResponse, error)
+	GetBookingByCode(ctx context.Context, bookingCode string) (*dto.BookingResponse, error)
+	GetDoctorBookingByCode(ctx context.Context, bookingCode string) (*dto.BookingResponse, error)
+	SubmitWaitFeedback(ctx context.Context, bookingID uuid.UUID, req *dto.WaitFeedbackRequest) (*dto.WaitFeedbackResponse, error)
+	JoinWaitlist(ctx context.Context, scheduleID int) (*dto.WaitlistResponse, error)
+	LeaveWaitlist(ctx context.Context, scheduleID int) error
 }
 
 type patientBookingUsecase struct {
-	db               *gorm.DB
-	log              *logrus.Logger
-	bookingRepo      repository.BookingRepository
-	scheduleRepo     repository.DoctorScheduleRepository
-	redisSyncService *service.RedisSyncService
+	db                  *gorm.DB
+	log                 *logrus.Logger
+	cfg                 *config.Config
+	bookingRepo         repository.BookingRepository
+	scheduleRepo        repository.DoctorScheduleRepository
+	waitFeedbackRepo    repository.WaitFeedbackRepository
+	queueStatRepo       repository.DoctorQueueStatRepository
+	slotRepo            repository.ScheduleSlotRepository
+	redisSyncService    *service.RedisSyncService
+	auditService        service.AuditService
+	formatService       service.FormatService
+	usageMeter          *service.UsageMeterService
+	outboxService       *service.OutboxService
+	notificationService *service.NotificationService
+	bookingSagaService  *service.BookingSagaService
+	patientProfileRepo  repository.PatientProfileRepository
+	userRepo            repository.UserRepository
+	eventBus            *service.EventBus
+	paymentService      *service.PaymentService
+	paymentRepo         repository.PaymentRepository
 }
 
 func NewPatientBookingUsecase(
 	db *gorm.DB,
 	log *logrus.Logger,
+	cfg *config.Config,
 	bookingRepo repository.BookingRepository,
 	scheduleRepo repository.DoctorScheduleRepository,
+	waitFeedbackRepo repository.WaitFeedbackRepository,
+	queueStatRepo repository.DoctorQueueStatRepository,
+	slotRepo repository.ScheduleSlotRepository,
 	redisSyncService *service.RedisSyncService,
+	auditService service.AuditService,
+	formatService service.FormatService,
+	usageMeter *service.UsageMeterService,
+	outboxService *service.OutboxService,
+	notificationService *service.NotificationService,
+	bookingSagaService *service.BookingSagaService,
+	patientProfileRepo repository.PatientProfileRepository,
+	userRepo repository.UserRepository,
+	eventBus *service.EventBus,
+	paymentService *service.PaymentService,
+	paymentRepo repository.PaymentRepository,
 ) PatientBookingUsecase {
-	return &patientBookingUsecase{
-		db:               db,
-		log:              log,
-		bookingRepo:      bookingRepo,
-		scheduleRepo:     scheduleRepo,
-		redisSyncService: redisSyncService,
+	u := &patientBookingUsecase{
+		db:                  db,
+		log:                 log,
+		cfg:                 cfg,
+		bookingRepo:         bookingRepo,
+		scheduleRepo:        scheduleRepo,
+		waitFeedbackRepo:    waitFeedbackRepo,
+		queueStatRepo:       queueStatRepo,
+		slotRepo:            slotRepo,
+		redisSyncService:    redisSyncService,
+		auditService:        auditService,
+		formatService:       formatService,
+		usageMeter:          usageMeter,
+		outboxService:       outboxService,
+		notificationService: notificationService,
+		bookingSagaService:  bookingSagaService,
+		patientProfileRepo:  patientProfileRepo,
+		userRepo:            userRepo,
+		eventBus:            eventBus,
+		paymentService:      paymentService,
+		paymentRepo:         paymentRepo,
 	}
+	u.registerEventHandlers()
+	u.registerSagaSteps()
+	return u
 }
 
 // GetMyBookings returns all bookings for the logged-in patient
@@ -70,21 +165,116 @@ func (u *patientBookingUsecase) GetMyBookings(ctx context.Context) (*dto.Booking
 		return nil, err
 	}
 
+	responses := converter.BookingsToResponses(bookings)
+	u.applyEstimatedWait(ctx, bookings, responses)
+	u.applyPayments(ctx, bookings, responses)
+
 	return &dto.BookingListResponse{
-		Bookings: converter.BookingsToResponses(bookings),
+		Bookings: responses,
 		Total:    len(bookings),
 	}, nil
 }
 
+// applyPayments fills the latest payment of each booking, with its refund once cancelled.
+// Fail-safe: the bookings are listed without payments on lookup errors.
+func (u *patientBookingUsecase) applyPayments(ctx context.Context, bookings []entity.Booking, responses []dto.BookingResponse) {
+	ids := make([]uuid.UUID, len(bookings))
+	for i := range bookings {
+		ids[i] = bookings[i].ID
+	}
+
+	payments, err := u.paymentRepo.FindLatestByBookingIDs(u.db.WithContext(ctx), ids)
+	if err != nil {
+		u.log.Warnf("Failed to find payments of bookings: %+v", err)
+		return
+	}
+
+	byBooking := make(map[uuid.UUID]*entity.Payment, len(payments))
+	for i := range payments {
+		byBooking[payments[i].BookingID] = &payments[i]
+	}
+	for i := range bookings {
+		responses[i].Payment = converter.PaymentToResponse(byBooking[bookings[i].ID])
+	}
+}
+
+// applyEstimatedWait fills the estimated wait of upcoming, uncalled bookings:
+// waiting patients ahead × the doctor's rolling average minutes per queue number,
+// counted from now or from the schedule start, whichever is later, and pushed back
+// by the schedule breaks it spans.
+// Fail-safe: bookings without history or with lookup errors get no estimate.
+func (u *patientBookingUsecase) applyEstimatedWait(ctx context.Context, bookings []entity.Booking, responses []dto.BookingResponse) {
+	now := time.Now().In(u.cfg.App.Location)
+	stats := make(map[uuid.UUID]*entity.DoctorQueueStat)
+
+	for i := range bookings {
+		booking := &bookings[i]
+		if booking.IsCancelled() || booking.IsCalled() {
+			continue
+		}
+
+		endAt, err := booking.Schedule.EndDateTime(u.cfg.App.Location)
+		if err != nil || !now.Before(endAt) {
+			continue
+		}
+
+		// Time-slot bookings are expected at their appointment time
+		if booking.Slot != nil {
+			callAt, err := booking.Slot.StartDateTime(booking.Schedule.ScheduleDate, u.cfg.App.Location)
+			if err != nil {
+				continue
+			}
+			waitMinutes := int(math.Max(0, math.Round(callAt.Sub(now).Minutes())))
+			responses[i].EstimatedCallAt = &callAt
+			responses[i].EstimatedWaitMinutes = &waitMinutes
+			continue
+		}
+
+		stat, ok := stats[booking.Schedule.DoctorID]
+		if !ok {
+			stat, err = u.queueStatRepo.FindByDoctorID(u.db.WithContext(ctx), booking.Schedule.DoctorID)
+			if err != nil {
+				u.log.Warnf("Failed to find queue stats for doctor %s: %+v", booking.Schedule.DoctorID, err)
+				continue
+			}
+			stats[booking.Schedule.DoctorID] = stat
+		}
+		if stat == nil || stat.SampleCount == 0 {
+			continue
+		}
+
+		ahead, err := u.bookingRepo.CountWaitingAhead(u.db.WithContext(ctx), booking.ScheduleID, booking.QueueNumber)
+		if err != nil {
+			u.log.Warnf("Failed to count waiting patients for booking %s: %+v", booking.ID, err)
+			continue
+		}
+
+		base := now
+		if startAt, err := booking.Schedule.StartDateTime(u.cfg.App.Location); err == nil && startAt.After(now) {
+			base = startAt
+		}
+
+		wait := time.Duration(float64(ahead) * stat.AvgMinutesPerQueue * float64(time.Minute))
+		callAt := booking.Schedule.WorkingTimeAfter(base, wait, u.cfg.App.Location)
+		waitMinutes := int(math.Round(callAt.Sub(now).Minutes()))
+		responses[i].EstimatedCallAt = &callAt
+		responses[i].EstimatedWaitMinutes = &waitMinutes
+	}
+}
+
 // CreateBooking creates a new booking with high-concurrency Redis-first approach.
 //
 // Flow:
-// 1. Validate schedule exists and is not in the past
+// 1. Validate schedule exists, is not in the past, and is still open (cutoff before start)
 // 2. Check patient hasn't already booked this schedule
-// 3. Redis DecrQuotaAndIncrQueue (atomic slot reservation)
-// 4. Generate booking code
-// 5. Insert booking to DB
-// 6. If DB fails -> compensate: RestoreQuota in Redis
+// 3. Saga step reserve_slot: Redis DecrQuotaAndIncrQueue (atomic slot reservation), or ReserveTimeSlot for time-slot schedules
+// 4. Saga step create_booking: insert booking + booking.created outbox event in one DB transaction
+// 5. On a duplicate queue number -> advance the Redis queue past the DB max and re-reserve
+// 6. If a step fails -> the saga compensates: RestoreQuota (or ReleaseTimeSlot) in Redis
+// 7. Doctors with a consultation fee: booking created awaiting_payment + charge opened at the gateway, confirmed once paid
+//
+// The saga state is persisted (booking_sagas), so a crash between the steps is
+// compensated by the recovery worker instead of leaking a reserved slot.
 func (u *patientBookingUsecase) CreateBooking(ctx context.Context, req *dto.CreateBookingRequest) (*dto.BookingResponse, error) {
 	userID, ok := middleware.GetUserIDFromContext(ctx)
 	if !ok {
@@ -97,7 +287,7 @@ func (u *patientBookingUsecase) CreateBooking(ctx context.Context, req *dto.Crea
 		u.log.Warnf("Failed to find schedule %d: %+v", req.ScheduleID, err)
 		return nil, err
 	}
-	if schedule == nil {
+	if schedule == nil || !schedule.IsApproved() {
 		return nil, ErrScheduleNotFound
 	}
 
@@ -107,6 +297,65 @@ func (u *patientBookingUsecase) CreateBooking(ctx context.Context, req *dto.Crea
 		return nil, ErrSchedulePast
 	}
 
+	// Validate against schedule start/end time and the booking cutoff
+	if err := u.validateBookingWindow(schedule); err != nil {
+		return nil, err
+	}
+	if !withinAdvanceWindow(u.cfg, schedule) {
+		return nil, ErrBookingTooFarAhead
+	}
+
+	// Optionally pause bookings while the doctor is flagged as possibly absent
+	if u.cfg.Absence.PauseBookings && schedule.IsPossiblyAbsent() {
+		return nil, ErrBookingPausedAbsent
+	}
+
+	// Optionally require profile fields (BOOKING_REQUIRED_PROFILE_FIELDS), listed on /auth/me
+	if required := u.cfg.Booking.RequiredProfileFields; len(required) > 0 {
+		user, err := u.userRepo.FindByID(u.db.WithContext(ctx), userID)
+		if err != nil {
+			u.log.Warnf("Failed to find user %s: %+v", userID, err)
+			return nil, err
+		}
+		if user == nil || len(user.ProfileCompleteness().MissingOf(required)) > 0 {
+			return nil, ErrProfileIncomplete
+		}
+	}
+
+	// Time-slot schedules book a specific appointment slot
+	var slot *entity.ScheduleSlot
+	if schedule.IsSlotMode() {
+		if req.SlotID == nil {
+			return nil, ErrSlotRequired
+		}
+		slot, err = u.slotRepo.FindByID(u.db.WithContext(ctx), *req.SlotID)
+		if err != nil {
+			u.log.Warnf("Failed to find slot %d: %+v", *req.SlotID, err)
+			return nil, err
+		}
+		if slot == nil || slot.ScheduleID != schedule.ID {
+			return nil, ErrSlotNotFound
+		}
+	} else if req.SlotID != nil {
+		return nil, ErrSlotNotAllowed
+	}
+
+	// Optionally attach the insurance of the profile, copied to the booking for claims
+	var insured *entity.PatientProfile
+	if req.UseInsurance {
+		insured, err = u.patientProfileRepo.FindByUserID(ctx, u.db.WithContext(ctx), userID)
+		if err != nil {
+			u.log.Warnf("Failed to find patient profile: %+v", err)
+			return nil, err
+		}
+		if insured == nil || !insured.HasInsurance() {
+			return nil, ErrInsuranceMissing
+		}
+		if !insured.InsuranceValidOn(schedule.ScheduleDate) {
+			return nil, ErrInsuranceNotValid
+		}
+	}
+
 	// Step 2: Check patient hasn't already booked this schedule (prevent duplicate)
 	existing, err := u.bookingRepo.FindByPatientAndSchedule(u.db.WithContext(ctx), userID, req.ScheduleID)
 	if err != nil {
@@ -117,47 +366,42 @@ func (u *patientBookingUsecase) CreateBooking(ctx context.Context, req *dto.Crea
 		return nil, ErrAlreadyBooked
 	}
 
-	// Step 3: Redis atomic slot reservation (HIGH CONCURRENCY)
-	// This is the critical section - thousands of users hit Redis instead of DB locks
-	queueNumber, err := u.redisSyncService.DecrQuotaAndIncrQueue(ctx, req.ScheduleID)
-	if err != nil {
-		if errors.Is(err, service.ErrQuotaFull) {
-			return nil, service.ErrQuotaFull
-		}
-		u.log.Warnf("Failed Redis slot reservation for schedule %d: %+v", req.ScheduleID, err)
-		return nil, err
-	}
-
-	// Step 4: Generate booking code
-	bookingCode := generateBookingCode(schedule.ScheduleDate)
-
-	// Step 5: Insert booking to DB
-	booking := &entity.Booking{
+	// Steps 3-5: reservation and insert run as a saga, compensated on failure
+	saga := &entity.BookingSaga{
+		BookingID:   uuid.New(),
+		BookingCode: generateBookingCode(schedule.ScheduleDate),
 		PatientID:   userID,
 		ScheduleID:  req.ScheduleID,
-		BookingCode: bookingCode,
-		QueueNumber: queueNumber,
-		Status:      entity.BookingStatusPending,
 	}
+	if slot != nil {
+		// Appointment slots are numbered in time order - the position doubles as the queue number
+		saga.SlotID = &slot.ID
+		saga.QueueNumber = slot.Position
+	}
+	if insured != nil {
+		saga.InsuranceProvider = insured.InsuranceProvider
+		saga.InsurancePolicyNumber = insured.InsurancePolicyNumber
+	}
+	saga.AwaitingPayment = u.paymentService.Required(schedule)
+	if err := u.bookingSagaService.Run(ctx, saga); err != nil {
+		return nil, err
+	}
+	booking := sagaBooking(saga)
 
-	if err := u.bookingRepo.Create(u.db.WithContext(ctx), booking); err != nil {
-		u.log.Errorf("Failed to insert booking to DB, compensating Redis: %+v", err)
+	u.usageMeter.RecordBookingCreated()
 
-		// COMPENSATE - restore Redis quota since DB insert failed
-		syncCtx, syncCancel := context.WithTimeout(context.Background(), 5*time.Second)
-		restoreErr := u.redisSyncService.RestoreQuota(syncCtx, req.ScheduleID)
-		syncCancel() // explicit cancel instead of defer (Fix #2)
-		if restoreErr != nil {
-			u.log.Errorf("CRITICAL: Failed to restore Redis quota after DB failure for schedule %d: %+v", req.ScheduleID, restoreErr)
-		}
+	// Drop any stale waitlist entry (e.g. slot freed by a quota increase) - non-fatal
+	if _, err := u.redisSyncService.LeaveWaitlist(ctx, req.ScheduleID, userID); err != nil {
+		u.log.Warnf("Failed to clear waitlist entry for patient %s on schedule %d (non-fatal): %+v", userID, req.ScheduleID, err)
+	}
 
-		// Handle unique constraint violation (race condition safety net from DB)
-		// Uses PostgreSQL error code 23505 (unique_violation) — migration-proof
-		if isDuplicateKeyError(err, "booking") {
-			return nil, ErrAlreadyBooked
+	// Step 7: the patient pays the consultation fee on the page of the gateway
+	var charged *entity.Payment
+	if saga.AwaitingPayment {
+		charged, err = u.chargeBooking(ctx, booking, schedule)
+		if err != nil {
+			return nil, err
 		}
-
-		return nil, err
 	}
 
 	// Reload booking with schedule+doctor info for response
@@ -165,11 +409,42 @@ func (u *patientBookingUsecase) CreateBooking(ctx context.Context, req *dto.Crea
 	if err != nil || fullBooking == nil {
 		// Return basic response if reload fails
 		u.log.Warnf("Failed to reload booking %s: %+v", booking.ID, err)
-		return converter.BookingToResponse(booking), nil
+		fullBooking = booking
 	}
 
-	u.log.Infof("Booking created: id=%s, schedule=%d, queue=%d, code=%s", booking.ID, req.ScheduleID, queueNumber, bookingCode)
-	return converter.BookingToResponse(fullBooking), nil
+	u.log.Infof("Booking created: id=%s, schedule=%d, queue=%d, code=%s, status=%s", booking.ID, req.ScheduleID, booking.QueueNumber, booking.BookingCode, booking.Status)
+	response := converter.BookingToResponse(fullBooking)
+	response.Payment = converter.PaymentToResponse(charged)
+	return response, nil
+}
+
+// chargeBooking opens the payment of the consultation fee of a booking awaiting payment.
+// When the gateway cannot open it the booking is cancelled, which releases the slot, and
+// ErrPaymentUnavailable is returned.
+func (u *patientBookingUsecase) chargeBooking(ctx context.Context, booking *entity.Booking, schedule *entity.DoctorSchedule) (*entity.Payment, error) {
+	payer, err := u.userRepo.FindByID(u.db.WithContext(ctx), booking.PatientID)
+	if err != nil {
+		// The gateway asks for the contact details itself
+		u.log.Warnf("Failed to find user %s (non-fatal): %+v", booking.PatientID, err)
+	}
+
+	description := fmt.Sprintf("Consultation with %s, %s (booking %s)", schedule.Doctor.User.FullName, u.formatService.ScheduleSlot(schedule), booking.BookingCode)
+	charged, err := u.paymentService.Charge(ctx, booking, schedule.Doctor.ConsultationFee, payer, description)
+	if err == nil {
+		return charged, nil
+	}
+	u.log.Warnf("Failed to charge booking %s, cancelling it: %+v", booking.ID, err)
+
+	current, findErr := u.bookingRepo.FindByID(u.db.WithContext(ctx), booking.ID)
+	if findErr != nil || current == nil {
+		// The payment.expire event cancels it
+		u.log.Warnf("Failed to reload booking %s: %+v", booking.ID, findErr)
+		return nil, service.ErrPaymentUnavailable
+	}
+	if cancelErr := u.cancelBooking(ctx, current, entity.BookingCancelReasonPayment); cancelErr != nil {
+		u.log.Warnf("Failed to cancel unpaid booking %s, left to the payment.expire event: %+v", booking.ID, cancelErr)
+	}
+	return nil, service.ErrPaymentUnavailable
 }
 
 // CancelBooking cancels a booking and restores the schedule slot.
@@ -179,9 +454,10 @@ func (u *patientBookingUsecase) CreateBooking(ctx context.Context, req *dto.Crea
 //
 // Flow:
 // 1. Find booking and verify ownership
-// 2. Atomic DB update: SET cancelled WHERE status != cancelled (returns rows affected)
-// 3. If affected == 0 → already cancelled, skip Redis restore
-// 4. If affected == 1 → RestoreQuota in Redis (queue number NOT decremented)
+// 2. Enforce cancellation deadline (no patient cancel within X hours of schedule start)
+// 3. Atomic DB update: SET cancelled WHERE status != cancelled (returns rows affected)
+// 4. If affected == 0 → already cancelled, skip Redis restore
+// 5. If affected == 1 → RestoreQuota in Redis (queue number NOT decremented)
 func (u *patientBookingUsecase) CancelBooking(ctx context.Context, bookingID uuid.UUID) error {
 	userID, ok := middleware.GetUserIDFromContext(ctx)
 	if !ok {
@@ -202,32 +478,678 @@ func (u *patientBookingUsecase) CancelBooking(ctx context.Context, bookingID uui
 		return ErrBookingNotOwned
 	}
 
-	// Step 2: Atomic cancel — UPDATE WHERE status != 'cancelled'
-	// Returns rows affected: 1 = success, 0 = already cancelled
-	affected, err := u.bookingRepo.CancelBooking(u.db.WithContext(ctx), bookingID)
+	// Step 2: Enforce cancellation deadline for patient-initiated cancellation
+	startAt, err := booking.Schedule.StartDateTime(u.cfg.App.Location)
+	if err != nil {
+		u.log.Warnf("Failed to parse start time for schedule %d: %+v", booking.ScheduleID, err)
+		return err
+	}
+	if time.Now().After(startAt.Add(-u.cfg.Booking.CancellationDeadline)) {
+		return ErrCancellationDeadlinePassed
+	}
+
+	return u.cancelBooking(ctx, booking, entity.BookingCancelReasonPatient)
+}
+
+// AdminCancelBooking cancels any booking on behalf of an admin.
+// Bypasses ownership and the cancellation deadline policy (admin override).
+func (u *patientBookingUsecase) AdminCancelBooking(ctx context.Context, bookingID uuid.UUID) error {
+	booking, err := u.bookingRepo.FindByID(u.db.WithContext(ctx), bookingID)
+	if err != nil {
+		u.log.Warnf("Failed to find booking %s: %+v", bookingID, err)
+		return err
+	}
+	if booking == nil {
+		return ErrBookingNotFound
+	}
+
+	if err := u.cancelBooking(ctx, booking, entity.BookingCancelReasonAdmin); err != nil {
+		return err
+	}
+
+	// Audit log - admin override cancel
+	adminID, _ := middleware.GetUserIDFromContext(ctx)
+	if err := u.auditService.LogUpdate(ctx, u.db.WithContext(ctx), &adminID, entity.AuditActionBookingCancel, "booking", bookingID.String(),
+		entity.JSON{"status": booking.Status},
+		entity.JSON{"status": entity.BookingStatusCancelled, "admin_override": true},
+	); err != nil {
+		u.log.Warnf("Failed to create audit log: %+v", err)
+	}
+
+	return nil
+}
+
+// DeleteBooking soft-deletes a booking (admin). Only cancelled bookings can be deleted
+// (ErrBookingNotCancelled otherwise): active ones must be cancelled first, so deleting never frees quota.
+func (u *patientBookingUsecase) DeleteBooking(ctx context.Context, bookingID uuid.UUID) error {
+	booking, err := u.bookingRepo.FindByID(u.db.WithContext(ctx), bookingID)
 	if err != nil {
-		u.log.Warnf("Failed to cancel booking %s: %+v", bookingID, err)
+		u.log.Warnf("Failed to find booking %s: %+v", bookingID, err)
 		return err
 	}
+	if booking == nil {
+		return ErrBookingNotFound
+	}
+	if !booking.IsCancelled() {
+		return ErrBookingNotCancelled
+	}
 
-	// If 0 rows affected, booking was already cancelled — do NOT restore quota
+	tx := u.db.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	affected, err := u.bookingRepo.Delete(tx, booking.ID)
+	if err != nil {
+		u.log.Warnf("Failed to delete booking %s: %+v", booking.ID, err)
+		return err
+	}
 	if affected == 0 {
+		return ErrBookingNotFound
+	}
+
+	adminID, _ := middleware.GetUserIDFromContext(ctx)
+	if err := u.auditService.LogDelete(ctx, tx, &adminID, entity.AuditActionBookingDelete, "booking", booking.ID.String(),
+		converter.BookingToResponse(booking),
+	); err != nil {
+		u.log.Warnf("Failed to create audit log: %+v", err)
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		u.log.Warnf("Failed commit transaction: %+v", err)
+		return err
+	}
+
+	u.log.Infof("Booking deleted: id=%s, schedule=%d", booking.ID, booking.ScheduleID)
+	return nil
+}
+
+// GetDeletedBookings lists the soft-deleted bookings, most recently deleted first (admin)
+func (u *patientBookingUsecase) GetDeletedBookings(ctx context.Context, page, limit int) ([]dto.BookingResponse, int64, error) {
+	bookings, total, err := u.bookingRepo.FindDeleted(u.db.WithContext(ctx), page, limit)
+	if err != nil {
+		u.log.Warnf("Failed to find deleted bookings: %+v", err)
+		return nil, 0, err
+	}
+
+	return converter.BookingsToResponses(bookings), total, nil
+}
+
+// RestoreBooking brings back a soft-deleted booking (admin). Bookings of a deleted schedule
+// stay deleted: the schedule is gone, and its quota with it.
+func (u *patientBookingUsecase) RestoreBooking(ctx context.Context, bookingID uuid.UUID) (*dto.BookingResponse, error) {
+	tx := u.db.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	booking, err := u.bookingRepo.FindDeletedByID(tx, bookingID)
+	if err != nil {
+		u.log.Warnf("Failed to find deleted booking %s: %+v", bookingID, err)
+		return nil, err
+	}
+	if booking == nil {
+		return nil, ErrBookingNotFound
+	}
+
+	schedule, err := u.scheduleRepo.FindByID(tx, booking.ScheduleID)
+	if err != nil {
+		u.log.Warnf("Failed to find schedule %d: %+v", booking.ScheduleID, err)
+		return nil, err
+	}
+	if schedule == nil {
+		return nil, ErrBookingScheduleDeleted
+	}
+
+	affected, err := u.bookingRepo.Restore(tx, booking.ID)
+	if err != nil {
+		u.log.Warnf("Failed to restore booking %s: %+v", booking.ID, err)
+		return nil, err
+	}
+	if affected == 0 {
+		return nil, ErrBookingNotFound
+	}
+
+	adminID, _ := middleware.GetUserIDFromContext(ctx)
+	if err := u.auditService.LogUpdate(ctx, tx, &adminID, entity.AuditActionBookingRestore, "booking", booking.ID.String(),
+		entity.JSON{"deleted_at": booking.DeletedAt.Time},
+		entity.JSON{"deleted_at": nil, "status": booking.Status},
+	); err != nil {
+		u.log.Warnf("Failed to create audit log: %+v", err)
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		u.log.Warnf("Failed commit transaction: %+v", err)
+		return nil, err
+	}
+
+	restored, err := u.bookingRepo.FindByID(u.db.WithContext(ctx), booking.ID)
+	if err != nil {
+		u.log.Warnf("Failed to reload booking %s: %+v", booking.ID, err)
+		return nil, err
+	}
+	if restored == nil {
+		return nil, ErrBookingNotFound
+	}
+
+	u.log.Infof("Booking restored: id=%s, schedule=%d", booking.ID, booking.ScheduleID)
+	return converter.BookingToResponse(restored), nil
+}
+
+// GetBookingByCode looks up a booking from its printed/QR code (front-desk staff)
+func (u *patientBookingUsecase) GetBookingByCode(ctx context.Context, bookingCode string) (*dto.BookingResponse, error) {
+	booking, err := u.bookingRepo.FindByBookingCode(u.db.WithContext(ctx), normalizeBookingCode(bookingCode))
+	if err != nil {
+		u.log.Warnf("Failed to find booking by code %s: %+v", bookingCode, err)
+		return nil, err
+	}
+	if booking == nil {
+		return nil, ErrBookingNotFound
+	}
+
+	return converter.BookingToResponse(booking), nil
+}
+
+// GetDoctorBookingByCode looks up a booking from its code, limited to the logged-in doctor's schedules.
+// Bookings of other doctors are reported as not found.
+func (u *patientBookingUsecase) GetDoctorBookingByCode(ctx context.Context, bookingCode string) (*dto.BookingResponse, error) {
+	doctorID, ok := middleware.GetUserIDFromContext(ctx)
+	if !ok {
+		return nil, errors.New("user not found in context")
+	}
+
+	booking, err := u.bookingRepo.FindByBookingCode(u.db.WithContext(ctx), normalizeBookingCode(bookingCode))
+	if err != nil {
+		u.log.Warnf("Failed to find booking by code %s: %+v", bookingCode, err)
+		return nil, err
+	}
+	if booking == nil || booking.Schedule.DoctorID != doctorID {
+		return nil, ErrBookingNotFound
+	}
+
+	return converter.BookingToResponse(booking), nil
+}
+
+// cancelBooking performs the atomic cancel and records a booking.cancelled outbox event
+// in the same transaction. The outbox worker releases the slot in Redis.
+func (u *patientBookingUsecase) cancelBooking(ctx context.Context, booking *entity.Booking, reason string) error {
+	if booking.IsCancelled() {
 		return ErrBookingAlreadyCancelled
 	}
 
-	// Step 3: Restore quota in Redis (queue number NOT decremented)
-	syncCtx, syncCancel := context.WithTimeout(context.Background(), 5*time.Second)
-	err = u.redisSyncService.RestoreQuota(syncCtx, booking.ScheduleID)
-	syncCancel() // explicit cancel instead of defer (Fix #2)
+	tx := u.db.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	// Atomic cancel — UPDATE WHERE version = ? AND status != 'cancelled' (optimistic lock)
+	// Returns rows affected: 1 = success, 0 = already cancelled or modified concurrently
+	affected, err := u.bookingRepo.CancelBooking(tx, booking.ID, booking.Version)
+	if err != nil {
+		u.log.Warnf("Failed to cancel booking %s: %+v", booking.ID, err)
+		return err
+	}
+
+	// If 0 rows affected, do NOT restore quota — tell a concurrent cancel apart from another update
+	if affected == 0 {
+		current, err := u.bookingRepo.FindByID(u.db.WithContext(ctx), booking.ID)
+		if err != nil {
+			u.log.Warnf("Failed to reload booking %s: %+v", booking.ID, err)
+			return err
+		}
+		if current == nil || current.IsCancelled() {
+			return ErrBookingAlreadyCancelled
+		}
+		return ErrBookingVersionConflict
+	}
+
+	// A fee not paid yet is no longer collected
+	if _, err := u.paymentRepo.ClosePendingByBookingID(tx, booking.ID, entity.PaymentStatusCancelled, "booking cancelled"); err != nil {
+		u.log.Warnf("Failed to cancel pending payment of booking %s: %+v", booking.ID, err)
+		return err
+	}
+
+	// Slot goes to the next waitlisted patient, or back to the Redis quota (queue number NOT decremented)
+	if err := u.outboxService.Enqueue(tx, entity.OutboxEventBookingCancelled, "booking", booking.ID.String(), entity.BookingEventPayload{
+		BookingID:       booking.ID,
+		BookingCode:     booking.BookingCode,
+		PatientID:       booking.PatientID,
+		ScheduleID:      booking.ScheduleID,
+		QueueNumber:     booking.QueueNumber,
+		SlotID:          booking.SlotID,
+		CancelReason:    reason,
+		ReleaseSlot:     true,
+		PromoteWaitlist: true,
+	}); err != nil {
+		u.log.Warnf("Failed to enqueue booking event: %+v", err)
+		return err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		u.log.Warnf("Failed commit transaction: %+v", err)
+		return err
+	}
+
+	u.log.Infof("Booking cancelled: id=%s, schedule=%d", booking.ID, booking.ScheduleID)
+	return nil
+}
+
+// releaseSlot hands a freed slot to the next waitlisted patient and creates their booking.
+// Called by the outbox worker for booking.cancelled events.
+//
+// Flow:
+// 1. Booking closed for the schedule → plain RestoreQuota, no promotion
+// 2. Redis RestoreQuotaOrPromote (atomic LPOP waitlist, or INCR quota when empty)
+// 3. Insert booking + booking.created event for the promoted patient, charged when there is a fee
+// 4. If DB fails → COMPENSATE: offer the still-held slot to the next patient (or the quota)
+//
+// A Redis error is returned so the outbox retries the release; the slot is not held at that point.
+func (u *patientBookingUsecase) releaseSlot(ctx context.Context, schedule *entity.DoctorSchedule) error {
+	if err := u.validateBookingWindow(schedule); err != nil {
+		if err := u.redisSyncService.RestoreQuota(ctx, schedule.ID); err != nil {
+			return fmt.Errorf("restore quota for schedule %d: %w", schedule.ID, err)
+		}
+		return nil
+	}
+
+	for attempt := 0; attempt < maxWaitlistPromotionAttempts; attempt++ {
+		promotion, err := u.redisSyncService.RestoreQuotaOrPromote(ctx, schedule.ID)
+		if err != nil {
+			return fmt.Errorf("restore quota for schedule %d: %w", schedule.ID, err)
+		}
+		if promotion == nil {
+			return nil
+		}
+
+		booking := &entity.Booking{
+			PatientID:   promotion.PatientID,
+			ScheduleID:  schedule.ID,
+			BookingCode: generateBookingCode(schedule.ScheduleDate),
+			QueueNumber: promotion.QueueNumber,
+			Status:      entity.BookingStatusPending,
+		}
+		if u.paymentService.Required(schedule) {
+			booking.Status = entity.BookingStatusAwaitingPayment
+		}
+		if err := u.createBookingWithEvent(ctx, booking, true); err != nil {
+			// Slot is still held by this promotion - the next iteration passes it on
+			u.log.Warnf("Failed to create booking for waitlisted patient %s on schedule %d, promoting next: %+v", promotion.PatientID, schedule.ID, err)
+			continue
+		}
+
+		u.usageMeter.RecordBookingCreated()
+
+		// The promoted patient pays like any other; a charge that cannot be opened
+		// cancels the booking, whose booking.cancelled event promotes the next patient
+		if booking.IsAwaitingPayment() {
+			if _, err := u.chargeBooking(ctx, booking, schedule); err != nil {
+				u.log.Warnf("Failed to charge promoted booking %s (non-fatal): %+v", booking.ID, err)
+			}
+		}
+		return nil
+	}
+
+	// Out of attempts: the last failed promotion still holds the slot
+	if err := u.redisSyncService.RestoreQuota(ctx, schedule.ID); err != nil {
+		u.log.Errorf("CRITICAL: Failed to restore Redis quota after waitlist promotion failures for schedule %d: %+v", schedule.ID, err)
+		return err
+	}
+	return nil
+}
+
+// reserveQueueNumberAgain replaces a queue number the DB rejected as a duplicate.
+// The held slot goes back to the quota, the Redis queue counter is advanced past
+// the highest active queue number in the DB, and the Lua reservation runs again.
+// On error no slot is held.
+func (u *patientBookingUsecase) reserveQueueNumberAgain(ctx context.Context, scheduleID int) (int, error) {
+	if err := u.redisSyncService.RestoreQuota(ctx, scheduleID); err != nil {
+		u.log.Errorf("CRITICAL: Failed to restore Redis quota after queue conflict for schedule %d: %+v", scheduleID, err)
+		return 0, err
+	}
+
+	if err := u.redisSyncService.AdvanceQueueToDB(ctx, scheduleID); err != nil {
+		return 0, err
+	}
+
+	queueNumber, err := u.redisSyncService.DecrQuotaAndIncrQueue(ctx, scheduleID)
 	if err != nil {
-		// Log but don't fail - Redis will be re-synced on next startup
-		u.log.Warnf("Failed to restore Redis quota for schedule %d (non-fatal): %+v", booking.ScheduleID, err)
+		if !errors.Is(err, service.ErrQuotaFull) {
+			u.log.Warnf("Failed Redis slot reservation for schedule %d: %+v", scheduleID, err)
+		}
+		return 0, err
+	}
+	return queueNumber, nil
+}
+
+// registerSagaSteps defines the CreateBooking saga steps, run by BookingSagaService.
+// Patient notifications follow from the booking.created outbox event recorded by
+// create_booking, so they are delivered at-least-once without a step of their own.
+func (u *patientBookingUsecase) registerSagaSteps() {
+	u.bookingSagaService.RegisterSteps(
+		service.BookingSagaStep{
+			Name:       entity.BookingSagaStepReserveSlot,
+			Execute:    u.sagaReserveSlot,
+			Compensate: u.sagaReleaseSlot,
+		},
+		service.BookingSagaStep{
+			Name:    entity.BookingSagaStepCreateBooking,
+			Execute: u.sagaCreateBooking,
+			Done:    u.sagaBookingCreated,
+		},
+	)
+}
+
+// sagaReserveSlot reserves the time slot, or a quota unit and queue number, in Redis.
+// This is the critical section - thousands of users hit Redis instead of DB locks.
+func (u *patientBookingUsecase) sagaReserveSlot(ctx context.Context, saga *entity.BookingSaga) error {
+	if saga.SlotID != nil {
+		if err := u.redisSyncService.ReserveTimeSlot(ctx, saga.ScheduleID, *saga.SlotID); err != nil {
+			if !errors.Is(err, service.ErrTimeSlotTaken) && !errors.Is(err, service.ErrQuotaFull) {
+				u.log.Warnf("Failed Redis time slot reservation for schedule %d: %+v", saga.ScheduleID, err)
+			}
+			return err
+		}
+		return nil
 	}
 
-	u.log.Infof("Booking cancelled: id=%s, schedule=%d", bookingID, booking.ScheduleID)
+	queueNumber, err := u.redisSyncService.DecrQuotaAndIncrQueue(ctx, saga.ScheduleID)
+	if err != nil {
+		if !errors.Is(err, service.ErrQuotaFull) {
+			u.log.Warnf("Failed Redis slot reservation for schedule %d: %+v", saga.ScheduleID, err)
+		}
+		return err
+	}
+	saga.QueueNumber = queueNumber
 	return nil
 }
 
+// sagaReleaseSlot compensates sagaReserveSlot
+func (u *patientBookingUsecase) sagaReleaseSlot(ctx context.Context, saga *entity.BookingSaga) error {
+	if saga.SlotID != nil {
+		return u.redisSyncService.ReleaseTimeSlot(ctx, saga.ScheduleID, *saga.SlotID)
+	}
+	if saga.QueueNumber == 0 {
+		// A failed queue re-reservation already returned the slot to the quota
+		return nil
+	}
+	return u.redisSyncService.RestoreQuota(ctx, saga.ScheduleID)
+}
+
+// sagaCreateBooking inserts the booking with its outbox event, re-reserving the queue
+// number while the DB rejects it as a duplicate (Redis counter behind the bookings table).
+func (u *patientBookingUsecase) sagaCreateBooking(ctx context.Context, saga *entity.BookingSaga) error {
+	booking := sagaBooking(saga)
+
+	err := u.createBookingWithEvent(ctx, booking, false)
+	for attempt := 1; err != nil && saga.SlotID == nil && isDuplicateKeyError(err, "schedule_queue"); attempt++ {
+		if attempt > maxQueueConflictRetries {
+			u.log.Errorf("Queue number conflict on schedule %d persisted after %d retries", saga.ScheduleID, maxQueueConflictRetries)
+			return ErrQueueNumberConflict
+		}
+
+		u.log.Warnf("Duplicate queue number %d on schedule %d, retrying (attempt %d)", booking.QueueNumber, saga.ScheduleID, attempt)
+		// On error the held slot is already back in the quota (QueueNumber 0)
+		saga.QueueNumber, err = u.reserveQueueNumberAgain(ctx, saga.ScheduleID)
+		if err != nil {
+			return err
+		}
+		booking.QueueNumber = saga.QueueNumber
+		err = u.createBookingWithEvent(ctx, booking, false)
+	}
+
+	if err != nil {
+		u.log.Errorf("Failed to insert booking to DB, compensating Redis: %+v", err)
+
+		// Handle unique constraint violation (race condition safety net from DB)
+		// Uses PostgreSQL error code 23505 (unique_violation) — migration-proof
+		if isDuplicateKeyError(err, "slot_active") {
+			return service.ErrTimeSlotTaken
+		}
+		if isDuplicateKeyError(err, "booking") {
+			return ErrAlreadyBooked
+		}
+		return err
+	}
+	return nil
+}
+
+// sagaBookingCreated reports whether the saga's booking was committed (recovery)
+func (u *patientBookingUsecase) sagaBookingCreated(ctx context.Context, saga *entity.BookingSaga) (bool, error) {
+	booking, err := u.bookingRepo.FindByID(u.db.WithContext(ctx), saga.BookingID)
+	if err != nil {
+		return false, err
+	}
+	if booking == nil {
+		return false, nil
+	}
+	saga.QueueNumber = booking.QueueNumber
+	return true, nil
+}
+
+// sagaBooking builds the booking a saga creates
+func sagaBooking(saga *entity.BookingSaga) *entity.Booking {
+	status := entity.BookingStatusPending
+	if saga.AwaitingPayment {
+		status = entity.BookingStatusAwaitingPayment
+	}
+	return &entity.Booking{
+		ID:          saga.BookingID,
+		PatientID:   saga.PatientID,
+		ScheduleID:  saga.ScheduleID,
+		SlotID:      saga.SlotID,
+		BookingCode: saga.BookingCode,
+		QueueNumber: saga.QueueNumber,
+		Status:      status,
+
+		InsuranceProvider:     saga.InsuranceProvider,
+		InsurancePolicyNumber: saga.InsurancePolicyNumber,
+	}
+}
+
+// createBookingWithEvent inserts a booking and its booking.created outbox event in one transaction.
+// Promoted bookings (from the waitlist) are audited as a system action. Bookings awaiting payment
+// get a payment.expire event at the payment deadline, which cancels them when still not paid
+// (also when the payment could not be opened).
+func (u *patientBookingUsecase) createBookingWithEvent(ctx context.Context, booking *entity.Booking, promoted bool) error {
+	tx := u.db.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := u.bookingRepo.Create(tx, booking); err != nil {
+		return err
+	}
+
+	if err := u.outboxService.Enqueue(tx, entity.OutboxEventBookingCreated, "booking", booking.ID.String(), entity.BookingEventPayload{
+		BookingID:   booking.ID,
+		BookingCode: booking.BookingCode,
+		PatientID:   booking.PatientID,
+		ScheduleID:  booking.ScheduleID,
+		QueueNumber: booking.QueueNumber,
+		Promoted:    promoted,
+
+		AwaitingPayment: booking.IsAwaitingPayment(),
+	}); err != nil {
+		return err
+	}
+
+	if booking.IsAwaitingPayment() {
+		if err := u.outboxService.EnqueueAt(tx, u.paymentService.Deadline(), entity.OutboxEventPaymentExpire, "booking", booking.ID.String(), entity.PaymentExpirePayload{
+			BookingID: booking.ID,
+		}); err != nil {
+			return err
+		}
+	}
+
+	if promoted {
+		// Audit log - system promotion (no acting user)
+		if err := u.auditService.LogCreate(ctx, tx, nil, entity.AuditActionBookingPromote, "booking", booking.ID.String(), converter.BookingToResponse(booking)); err != nil {
+			u.log.Warnf("Failed to create audit log: %+v", err)
+		}
+	}
+
+	return tx.Commit().Error
+}
+
+// JoinWaitlist puts the patient on the waitlist of a fully booked schedule.
+// The patient is booked automatically when a slot is freed by a cancellation.
+func (u *patientBookingUsecase) JoinWaitlist(ctx context.Context, scheduleID int) (*dto.WaitlistResponse, error) {
+	userID, ok := middleware.GetUserIDFromContext(ctx)
+	if !ok {
+		return nil, errors.New("user not found in context")
+	}
+
+	schedule, err := u.scheduleRepo.FindByID(u.db.WithContext(ctx), scheduleID)
+	if err != nil {
+		u.log.Warnf("Failed to find schedule %d: %+v", scheduleID, err)
+		return nil, err
+	}
+	if schedule == nil || !schedule.IsApproved() {
+		return nil, ErrScheduleNotFound
+	}
+	if schedule.IsSlotMode() {
+		return nil, ErrWaitlistNotSupported
+	}
+
+	if err := u.validateBookingWindow(schedule); err != nil {
+		return nil, err
+	}
+	if !withinAdvanceWindow(u.cfg, schedule) {
+		return nil, ErrBookingTooFarAhead
+	}
+
+	existing, err := u.bookingRepo.FindByPatientAndSchedule(u.db.WithContext(ctx), userID, scheduleID)
+	if err != nil {
+		u.log.Warnf("Failed to check existing booking: %+v", err)
+		return nil, err
+	}
+	if existing != nil {
+		return nil, ErrAlreadyBooked
+	}
+
+	position, err := u.redisSyncService.JoinWaitlist(ctx, scheduleID, userID, schedule.ScheduleDate)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.WaitlistResponse{
+		ScheduleID: scheduleID,
+		Position:   position,
+	}, nil
+}
+
+// LeaveWaitlist removes the patient from the schedule waitlist
+func (u *patientBookingUsecase) LeaveWaitlist(ctx context.Context, scheduleID int) error {
+	userID, ok := middleware.GetUserIDFromContext(ctx)
+	if !ok {
+		return errors.New("user not found in context")
+	}
+
+	removed, err := u.redisSyncService.LeaveWaitlist(ctx, scheduleID, userID)
+	if err != nil {
+		return err
+	}
+	if !removed {
+		return ErrNotWaitlisted
+	}
+
+	return nil
+}
+
+// SubmitWaitFeedback records the patient's actual waiting time for a booking.
+// Only accepted once the schedule has started, and only once per booking.
+func (u *patientBookingUsecase) SubmitWaitFeedback(ctx context.Context, bookingID uuid.UUID, req *dto.WaitFeedbackRequest) (*dto.WaitFeedbackResponse, error) {
+	userID, ok := middleware.GetUserIDFromContext(ctx)
+	if !ok {
+		return nil, errors.New("user not found in context")
+	}
+
+	booking, err := u.bookingRepo.FindByID(u.db.WithContext(ctx), bookingID)
+	if err != nil {
+		u.log.Warnf("Failed to find booking %s: %+v", bookingID, err)
+		return nil, err
+	}
+	if booking == nil {
+		return nil, ErrBookingNotFound
+	}
+	if booking.PatientID != userID {
+		return nil, ErrBookingNotOwned
+	}
+	if booking.IsCancelled() {
+		return nil, ErrBookingAlreadyCancelled
+	}
+
+	startAt, err := booking.Schedule.StartDateTime(u.cfg.App.Location)
+	if err != nil {
+		u.log.Warnf("Failed to parse start time for schedule %d: %+v", booking.ScheduleID, err)
+		return nil, err
+	}
+	if time.Now().Before(startAt) {
+		return nil, ErrWaitFeedbackTooEarly
+	}
+
+	feedback := &entity.WaitFeedback{
+		BookingID:   booking.ID,
+		PatientID:   booking.PatientID,
+		DoctorID:    booking.Schedule.DoctorID,
+		ScheduleID:  booking.ScheduleID,
+		WaitMinutes: req.WaitMinutes,
+		Comment:     req.Comment,
+	}
+	if err := u.waitFeedbackRepo.Create(u.db.WithContext(ctx), feedback); err != nil {
+		if isDuplicateKeyError(err, "wait_feedbacks_pkey") {
+			return nil, ErrWaitFeedbackExists
+		}
+		u.log.Warnf("Failed to create wait feedback for booking %s: %+v", booking.ID, err)
+		return nil, err
+	}
+
+	return converter.WaitFeedbackToResponse(feedback), nil
+}
+
+// validateBookingWindow rejects bookings for schedules that are paused, that already ended
+// or that start within the configured cutoff window.
+func (u *patientBookingUsecase) validateBookingWindow(schedule *entity.DoctorSchedule) error {
+	if !schedule.IsBookingOpen() {
+		return ErrScheduleClosed
+	}
+
+	now := time.Now().In(u.cfg.App.Location)
+
+	endAt, err := schedule.EndDateTime(u.cfg.App.Location)
+	if err != nil {
+		u.log.Warnf("Failed to parse end time for schedule %d: %+v", schedule.ID, err)
+		return err
+	}
+	if !now.Before(endAt) {
+		return ErrScheduleEnded
+	}
+
+	// Minimum lead time: per-schedule override or the global cutoff
+	deadline, err := schedule.BookingDeadline(u.cfg.App.Location, u.cfg.Booking.Cutoff)
+	if err != nil {
+		u.log.Warnf("Failed to parse start time for schedule %d: %+v", schedule.ID, err)
+		return err
+	}
+	if now.After(deadline) {
+		return ErrBookingClosed
+	}
+
+	return nil
+}
+
+// lastBookableDate returns the last schedule date (YYYY-MM-DD) patients may book,
+// "" when the advance booking window is unlimited
+func lastBookableDate(cfg *config.Config) string {
+	if cfg.Booking.MaxAdvanceDays <= 0 {
+		return ""
+	}
+	return time.Now().In(cfg.App.Location).AddDate(0, 0, cfg.Booking.MaxAdvanceDays).Format("2006-01-02")
+}
+
+// withinAdvanceWindow reports whether the schedule date is inside the advance booking window
+func withinAdvanceWindow(cfg *config.Config, schedule *entity.DoctorSchedule) bool {
+	last := lastBookableDate(cfg)
+	return last == "" || schedule.ScheduleDate.Format("2006-01-02") <= last
+}
+
+// normalizeBookingCode trims and upper-cases a scanned or typed booking code
+func normalizeBookingCode(bookingCode string) string {
+	return strings.ToUpper(strings.TrimSpace(bookingCode))
+}
+
 // generateBookingCode generates a unique booking code: BK-YYYYMMDD-XXXXXX
 func generateBookingCode(scheduleDate time.Time) string {
 	dateStr := scheduleDate.Format("20060102")
@@ -236,3 +1158,314 @@ func generateBookingCode(scheduleDate time.Time) string {
 	randomStr := fmt.Sprintf("%06X", randomBytes)
 	return fmt.Sprintf("BK-%s-%s", dateStr, randomStr)
 }
+
+// registerEventHandlers subscribes the booking side effects to the booking and schedule events.
+// They are published through the outbox, so handlers may run more than once per event
+// (at-least-once delivery) and notifications are deduplicated on the event ID.
+func (u *patientBookingUsecase) registerEventHandlers() {
+	u.eventBus.Subscribe(entity.OutboxEventBookingCreated, u.handleBookingCreated)
+	u.eventBus.Subscribe(entity.OutboxEventBookingConfirmed, u.handleBookingCreated)
+	u.eventBus.Subscribe(entity.OutboxEventBookingCancelled, u.handleBookingCancelled)
+	u.eventBus.Subscribe(entity.OutboxEventBookingRescheduled, u.handleBookingRescheduled)
+	u.eventBus.Subscribe(entity.OutboxEventBookingCalled, u.handleBookingCalled)
+	u.eventBus.Subscribe(entity.OutboxEventScheduleUpdated, u.handleScheduleUpdated)
+	u.eventBus.Subscribe(entity.OutboxEventScheduleDoctorLeave, u.handleScheduleDoctorLeave)
+}
+
+// handleBookingCreated emails the booking confirmation, or tells patients promoted
+// from the waitlist about their booking (and the fee to pay). Bookings awaiting payment
+// are confirmed by their booking.confirmed event once paid.
+func (u *patientBookingUsecase) handleBookingCreated(ctx context.Context, event *entity.DomainEvent) error {
+	var payload entity.BookingEventPayload
+	if err := event.DecodePayload(&payload); err != nil {
+		return err
+	}
+	if !payload.NotifiesPatient(event.Type) {
+		return nil
+	}
+
+	schedule, err := u.scheduleRepo.FindByID(u.db.WithContext(ctx), payload.ScheduleID)
+	if err != nil {
+		return err
+	}
+	if schedule == nil {
+		return nil
+	}
+
+	data := map[string]string{
+		entity.TemplateVarBookingCode:  payload.BookingCode,
+		entity.TemplateVarQueueNumber:  strconv.Itoa(payload.QueueNumber),
+		entity.TemplateVarDoctorName:   schedule.Doctor.User.FullName,
+		entity.TemplateVarScheduleSlot: u.formatService.ScheduleSlot(schedule),
+	}
+
+	if payload.Promoted {
+		message := fmt.Sprintf("You were promoted from the waitlist: booking %s on %s, queue %d", payload.BookingCode, u.formatService.ScheduleSlot(schedule), payload.QueueNumber)
+		if payload.AwaitingPayment {
+			message += ". Please pay the consultation fee to confirm it, unpaid bookings are cancelled"
+		}
+		return u.notificationService.Notify(ctx, u.db.WithContext(ctx), service.NotificationRequest{
+			PatientID: payload.PatientID,
+			BookingID: &payload.BookingID,
+			EventType: event.Type,
+			Message:   message,
+			DedupeKey: event.ID,
+			Push:      true,
+			InApp:     true,
+			Data:      data,
+		})
+	}
+
+	return u.notificationService.Notify(ctx, u.db.WithContext(ctx), service.NotificationRequest{
+		PatientID: payload.PatientID,
+		BookingID: &payload.BookingID,
+		EventType: event.Type,
+		Subject:   fmt.Sprintf("Booking confirmed: %s", payload.BookingCode),
+		Message: fmt.Sprintf("Your booking %s is confirmed for %s, queue number %d. Please arrive before your turn and show the booking code at the front desk.",
+			payload.BookingCode, u.formatService.ScheduleSlot(schedule), payload.QueueNumber),
+		DedupeKey: event.ID,
+		Channel:   entity.NotificationChannelEmail,
+		Push:      true,
+		InApp:     true,
+		Data:      data,
+	})
+}
+
+// handleBookingCancelled notifies the patient when the booking was not cancelled
+// by the patient themselves, and releases the freed slot.
+//
+// The event is retried as a whole when this or another subscriber fails: the notification
+// is deduplicated per event and the slot is released once per event (releaseOnce).
+func (u *patientBookingUsecase) handleBookingCancelled(ctx context.Context, event *entity.DomainEvent) error {
+	var payload entity.BookingEventPayload
+	if err := event.DecodePayload(&payload); err != nil {
+		return err
+	}
+
+	schedule, err := u.scheduleRepo.FindByID(u.db.WithContext(ctx), payload.ScheduleID)
+	if err != nil {
+		return err
+	}
+	if schedule == nil {
+		// Schedule deleted - its Redis keys are gone as well
+		return nil
+	}
+
+	if payload.NotifiesPatient(event.Type) {
+		err := u.notificationService.Notify(ctx, u.db.WithContext(ctx), service.NotificationRequest{
+			PatientID: payload.PatientID,
+			BookingID: &payload.BookingID,
+			EventType: event.Type,
+			Message:   fmt.Sprintf("Your booking %s on %s was cancelled", payload.BookingCode, u.formatService.ScheduleSlot(schedule)),
+			DedupeKey: event.ID,
+			Push:      true,
+			InApp:     true,
+			Data: map[string]string{
+				entity.TemplateVarBookingCode:  payload.BookingCode,
+				entity.TemplateVarDoctorName:   schedule.Doctor.User.FullName,
+				entity.TemplateVarScheduleSlot: u.formatService.ScheduleSlot(schedule),
+			},
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	if !payload.ReleaseSlot {
+		return nil
+	}
+	return u.releaseOnce(ctx, event, schedule, func() error {
+		if payload.SlotID != nil {
+			// Appointment slots have no waitlist - the slot simply becomes bookable again
+			return u.redisSyncService.ReleaseTimeSlot(ctx, schedule.ID, *payload.SlotID)
+		}
+		if payload.PromoteWaitlist {
+			return u.releaseSlot(ctx, schedule)
+		}
+		return u.redisSyncService.RestoreQuota(ctx, schedule.ID)
+	})
+}
+
+// releaseOnce runs release at most once per event. Quota restores are not idempotent, and
+// an event is redelivered to all its subscribers when one of them fails; the release is
+// claimed again when it fails itself.
+func (u *patientBookingUsecase) releaseOnce(ctx context.Context, event *entity.DomainEvent, schedule *entity.DoctorSchedule, release func() error) error {
+	claimed, err := u.redisSyncService.ClaimSlotRelease(ctx, event.ID, schedule.ScheduleDate)
+	if err != nil {
+		return err
+	}
+	if !claimed {
+		return nil
+	}
+
+	if err := release(); err != nil {
+		u.redisSyncService.UnclaimSlotRelease(ctx, event.ID)
+		return err
+	}
+	return nil
+}
+
+// handleBookingRescheduled notifies the patient and releases the slot on the previous schedule
+func (u *patientBookingUsecase) handleBookingRescheduled(ctx context.Context, event *entity.DomainEvent) error {
+	var payload entity.BookingEventPayload
+	if err := event.DecodePayload(&payload); err != nil {
+		return err
+	}
+
+	source, err := u.scheduleRepo.FindByID(u.db.WithContext(ctx), payload.FromScheduleID)
+	if err != nil {
+		return err
+	}
+	target, err := u.scheduleRepo.FindByID(u.db.WithContext(ctx), payload.ScheduleID)
+	if err != nil {
+		return err
+	}
+
+	if source != nil && target != nil {
+		err := u.notificationService.Notify(ctx, u.db.WithContext(ctx), service.NotificationRequest{
+			PatientID: payload.PatientID,
+			BookingID: &payload.BookingID,
+			EventType: event.Type,
+			Message:   fmt.Sprintf("Your booking %s was moved from %s to %s", payload.BookingCode, u.formatService.ScheduleSlot(source), u.formatService.ScheduleSlot(target)),
+			DedupeKey: event.ID,
+			Push:      true,
+			InApp:     true,
+			Data: map[string]string{
+				entity.TemplateVarBookingCode:  payload.BookingCode,
+				entity.TemplateVarDoctorName:   target.Doctor.User.FullName,
+				entity.TemplateVarScheduleSlot: u.formatService.ScheduleSlot(target),
+				entity.TemplateVarOldSlot:      u.formatService.ScheduleSlot(source),
+			},
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	if !payload.ReleaseSlot || source == nil {
+		return nil
+	}
+	return u.releaseOnce(ctx, event, source, func() error {
+		if payload.SlotID != nil {
+			return u.redisSyncService.ReleaseTimeSlot(ctx, source.ID, *payload.SlotID)
+		}
+		return u.redisSyncService.RestoreQuota(ctx, source.ID)
+	})
+}
+
+// handleBookingCalled notifies the patient that their queue number was called
+func (u *patientBookingUsecase) handleBookingCalled(ctx context.Context, event *entity.DomainEvent) error {
+	var payload entity.BookingEventPayload
+	if err := event.DecodePayload(&payload); err != nil {
+		return err
+	}
+
+	return u.notificationService.Notify(ctx, u.db.WithContext(ctx), service.NotificationRequest{
+		PatientID: payload.PatientID,
+		BookingID: &payload.BookingID,
+		EventType: event.Type,
+		Message:   fmt.Sprintf("Queue number %d (booking %s) is being called, please come to the consultation room", payload.QueueNumber, payload.BookingCode),
+		DedupeKey: event.ID,
+		Push:      true,
+		InApp:     true,
+		Data: map[string]string{
+			entity.TemplateVarBookingCode: payload.BookingCode,
+			entity.TemplateVarQueueNumber: strconv.Itoa(payload.QueueNumber),
+		},
+	})
+}
+
+// handleScheduleUpdated notifies every patient with an active booking of the new schedule time
+// and prompts them to cancel or rebook if it no longer suits them.
+func (u *patientBookingUsecase) handleScheduleUpdated(ctx context.Context, event *entity.DomainEvent) error {
+	var payload entity.ScheduleEventPayload
+	if err := event.DecodePayload(&payload); err != nil {
+		return err
+	}
+
+	oldDate, err := time.Parse("2006-01-02", payload.OldScheduleDate)
+	if err != nil {
+		return err
+	}
+	newDate, err := time.Parse("2006-01-02", payload.ScheduleDate)
+	if err != nil {
+		return err
+	}
+	oldSlot := u.formatService.ScheduleSlot(&entity.DoctorSchedule{ScheduleDate: oldDate, StartTime: payload.OldStartTime, EndTime: payload.OldEndTime})
+	newSlot := u.formatService.ScheduleSlot(&entity.DoctorSchedule{ScheduleDate: newDate, StartTime: payload.StartTime, EndTime: payload.EndTime})
+
+	bookings, err := u.bookingRepo.FindActiveByScheduleID(u.db.WithContext(ctx), payload.ScheduleID)
+	if err != nil {
+		return err
+	}
+
+	for _, b := range bookings {
+		err := u.notificationService.Notify(ctx, u.db.WithContext(ctx), service.NotificationRequest{
+			PatientID: b.PatientID,
+			BookingID: &b.ID,
+			EventType: event.Type,
+			Message:   fmt.Sprintf("Your booking %s changed from %s to %s, cancel or book another schedule if the new time does not suit you", b.BookingCode, oldSlot, newSlot),
+			DedupeKey: fmt.Sprintf("%s:%s", event.ID, b.ID),
+			Push:      true,
+			InApp:     true,
+			Data: map[string]string{
+				entity.TemplateVarBookingCode:  b.BookingCode,
+				entity.TemplateVarScheduleSlot: newSlot,
+				entity.TemplateVarOldSlot:      oldSlot,
+			},
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	u.log.Infof("Schedule %d time change notified to %d patients", payload.ScheduleID, len(bookings))
+	return nil
+}
+
+// handleScheduleDoctorLeave notifies every patient with an active booking on a schedule closed
+// by a doctor's leave, and prompts them to cancel or book another schedule.
+func (u *patientBookingUsecase) handleScheduleDoctorLeave(ctx context.Context, event *entity.DomainEvent) error {
+	var payload entity.ScheduleLeavePayload
+	if err := event.DecodePayload(&payload); err != nil {
+		return err
+	}
+
+	date, err := time.Parse("2006-01-02", payload.ScheduleDate)
+	if err != nil {
+		return err
+	}
+	slot := u.formatService.ScheduleSlot(&entity.DoctorSchedule{ScheduleDate: date, StartTime: payload.StartTime, EndTime: payload.EndTime})
+
+	bookings, err := u.bookingRepo.FindActiveByScheduleID(u.db.WithContext(ctx), payload.ScheduleID)
+	if err != nil {
+		return err
+	}
+
+	for _, b := range bookings {
+		err := u.notificationService.Notify(ctx, u.db.WithContext(ctx), service.NotificationRequest{
+			PatientID: b.PatientID,
+			BookingID: &b.ID,
+			EventType: event.Type,
+			Message:   fmt.Sprintf("The doctor of your booking %s on %s is on leave, please cancel or book another schedule", b.BookingCode, slot),
+			DedupeKey: fmt.Sprintf("%s:%s", event.ID, b.ID),
+			Push:      true,
+			InApp:     true,
+			Data: map[string]string{
+				entity.TemplateVarBookingCode:  b.BookingCode,
+				entity.TemplateVarScheduleSlot: slot,
+			},
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	u.log.Infof("Schedule %d closed by doctor leave %d, notified %d patients", payload.ScheduleID, payload.LeaveID, len(bookings))
+	return nil
+}
+
+// outboxDedupeKey identifies the notification sent for an outbox event
+func outboxDedupeKey(event *entity.OutboxEvent) string {
+	return fmt.Sprintf("outbox:%d", event.ID)
+}
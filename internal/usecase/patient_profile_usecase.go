@@ -3,6 +3,11 @@ package usecase
 import (
 	"context"
 	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+	"unicode/utf8"
 
 	"go-template-clean-architecture/internal/converter"
 	"go-template-clean-architecture/internal/delivery/dto"
@@ -11,17 +16,39 @@ import (
 	"go-template-clean-architecture/internal/domain/repository"
 	"go-template-clean-architecture/internal/service"
 
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
 	"github.com/sirupsen/logrus"
 	"golang.org/x/crypto/bcrypt"
 	"gorm.io/gorm"
 )
 
 var (
-	ErrPatientNotFound = errors.New("patient profile not found")
+	ErrPatientNotFound            = errors.New("patient profile not found")
+	ErrPatientHasUpcomingBookings = errors.New("cancel upcoming bookings before deleting the account")
+	ErrPatientSearchEmpty         = errors.New("search by nik, phone_number or name")
+	ErrPatientSearchNameTooShort  = errors.New("name must have at least 3 characters")
+	ErrInvalidInsuranceValidity   = errors.New("invalid insurance validity, use YYYY-MM-DD with valid_from not after valid_until")
 )
 
+// Timeline pagination defaults
+const (
+	defaultTimelineLimit = 20
+	maxTimelineLimit     = 100
+)
+
+// minPatientSearchNameLength keeps name searches selective (and usable by the trigram index)
+const minPatientSearchNameLength = 3
+
 type PatientProfileUsecase interface {
 	UpdateSelfProfile(ctx context.Context, req *dto.PatientUpdateSelfRequest) (*dto.PatientResponse, error)
+	GetPatientTimeline(ctx context.Context, patientID uuid.UUID, page, limit int) (*dto.PatientTimelineResponse, int64, error)
+	DeleteAccount(ctx context.Context, req *dto.DeletePatientAccountRequest) error
+	SearchPatients(ctx context.Context, filter *dto.PatientSearchFilter, page, limit int) ([]dto.PatientResponse, int64, error)
+	UpdateInsurance(ctx context.Context, patientID uuid.UUID, req *dto.UpdateInsuranceRequest) (*dto.PatientResponse, error)
+	RemoveInsurance(ctx context.Context, patientID uuid.UUID) (*dto.PatientResponse, error)
+	UpdateEmergencyContact(ctx context.Context, patientID uuid.UUID, req *dto.UpdateEmergencyContactRequest) (*dto.PatientResponse, error)
+	RemoveEmergencyContact(ctx context.Context, patientID uuid.UUID) (*dto.PatientResponse, error)
 }
 
 type patientProfileUsecase struct {
@@ -29,7 +56,13 @@ type patientProfileUsecase struct {
 	log                *logrus.Logger
 	userRepo           repository.UserRepository
 	patientProfileRepo repository.PatientProfileRepository
+	bookingRepo        repository.BookingRepository
+	auditRepo          repository.AuditLogRepository
+	outboxRepo         repository.OutboxRepository
 	auditService       service.AuditService
+	redisClient        *redis.Client
+	redisSyncService   *service.RedisSyncService
+	deviceTokenRepo    repository.DeviceTokenRepository
 }
 
 func NewPatientProfileUsecase(
@@ -37,14 +70,26 @@ func NewPatientProfileUsecase(
 	log *logrus.Logger,
 	userRepo repository.UserRepository,
 	patientProfileRepo repository.PatientProfileRepository,
+	bookingRepo repository.BookingRepository,
+	auditRepo repository.AuditLogRepository,
+	outboxRepo repository.OutboxRepository,
 	auditService service.AuditService,
+	redisClient *redis.Client,
+	redisSyncService *service.RedisSyncService,
+	deviceTokenRepo repository.DeviceTokenRepository,
 ) PatientProfileUsecase {
 	return &patientProfileUsecase{
 		db:                 db,
 		log:                log,
 		userRepo:           userRepo,
 		patientProfileRepo: patientProfileRepo,
+		bookingRepo:        bookingRepo,
+		auditRepo:          auditRepo,
+		outboxRepo:         outboxRepo,
 		auditService:       auditService,
+		redisClient:        redisClient,
+		redisSyncService:   redisSyncService,
+		deviceTokenRepo:    deviceTokenRepo,
 	}
 }
 
@@ -137,3 +182,421 @@ func (u *patientProfileUsecase) UpdateSelfProfile(ctx context.Context, req *dto.
 
 	return converter.PatientProfileToResponse(profile, user), nil
 }
+
+// UpdateInsurance registers the health insurance of a patient, replacing the previous one.
+// Used by the patient (own profile) and by admins; the actor is taken from the context.
+func (u *patientProfileUsecase) UpdateInsurance(ctx context.Context, patientID uuid.UUID, req *dto.UpdateInsuranceRequest) (*dto.PatientResponse, error) {
+	validFrom, err := parseOptionalDate(req.ValidFrom)
+	if err != nil {
+		return nil, ErrInvalidInsuranceValidity
+	}
+	validUntil, err := parseOptionalDate(req.ValidUntil)
+	if err != nil {
+		return nil, ErrInvalidInsuranceValidity
+	}
+	if validFrom != nil && validUntil != nil && validFrom.After(*validUntil) {
+		return nil, ErrInvalidInsuranceValidity
+	}
+
+	return u.changePatientProfile(ctx, patientID, entity.AuditActionPatientInsuranceUpdate, insuranceAuditValue, func(profile *entity.PatientProfile) {
+		profile.InsuranceProvider = req.Provider
+		profile.InsurancePolicyNumber = req.PolicyNumber
+		profile.InsuranceValidFrom = validFrom
+		profile.InsuranceValidUntil = validUntil
+	})
+}
+
+// RemoveInsurance removes the health insurance of a patient. Bookings keep the insurance
+// they were made with.
+func (u *patientProfileUsecase) RemoveInsurance(ctx context.Context, patientID uuid.UUID) (*dto.PatientResponse, error) {
+	return u.changePatientProfile(ctx, patientID, entity.AuditActionPatientInsuranceRemove, insuranceAuditValue, func(profile *entity.PatientProfile) {
+		profile.ClearInsurance()
+	})
+}
+
+// UpdateEmergencyContact registers the emergency contact of a patient, replacing the previous one
+func (u *patientProfileUsecase) UpdateEmergencyContact(ctx context.Context, patientID uuid.UUID, req *dto.UpdateEmergencyContactRequest) (*dto.PatientResponse, error) {
+	return u.changePatientProfile(ctx, patientID, entity.AuditActionPatientEmergencyContact, emergencyContactAuditValue, func(profile *entity.PatientProfile) {
+		profile.EmergencyContactName = req.Name
+		profile.EmergencyContactRelationship = req.Relationship
+		profile.EmergencyContactPhone = req.Phone
+	})
+}
+
+// RemoveEmergencyContact removes the emergency contact of a patient
+func (u *patientProfileUsecase) RemoveEmergencyContact(ctx context.Context, patientID uuid.UUID) (*dto.PatientResponse, error) {
+	return u.changePatientProfile(ctx, patientID, entity.AuditActionPatientEmergencyContact, emergencyContactAuditValue, func(profile *entity.PatientProfile) {
+		profile.ClearEmergencyContact()
+	})
+}
+
+func insuranceAuditValue(profile *entity.PatientProfile) interface{} {
+	return converter.PatientInsuranceToResponse(profile)
+}
+
+func emergencyContactAuditValue(profile *entity.PatientProfile) interface{} {
+	return converter.PatientEmergencyContactToResponse(profile)
+}
+
+// changePatientProfile applies change to the patient profile and audits the part of the
+// profile returned by auditValue
+func (u *patientProfileUsecase) changePatientProfile(ctx context.Context, patientID uuid.UUID, action string, auditValue func(profile *entity.PatientProfile) interface{}, change func(profile *entity.PatientProfile)) (*dto.PatientResponse, error) {
+	actorID, _ := middleware.GetUserIDFromContext(ctx)
+
+	tx := u.db.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	profile, err := u.patientProfileRepo.FindByUserID(ctx, tx, patientID)
+	if err != nil {
+		u.log.Warnf("Failed to find patient profile: %+v", err)
+		return nil, err
+	}
+	if profile == nil {
+		return nil, ErrPatientNotFound
+	}
+
+	user, err := u.userRepo.FindByID(tx, patientID)
+	if err != nil {
+		u.log.Warnf("Failed to find user: %+v", err)
+		return nil, err
+	}
+	if user == nil || user.IsErased() {
+		return nil, ErrPatientNotFound
+	}
+
+	oldValue := auditValue(profile)
+	change(profile)
+
+	if err := u.patientProfileRepo.Update(ctx, tx, profile); err != nil {
+		u.log.Warnf("Failed to update patient profile: %+v", err)
+		return nil, err
+	}
+
+	newValue := auditValue(profile)
+	if err := u.auditService.LogUpdate(ctx, tx, &actorID, action, "patient_profile", patientID.String(), oldValue, newValue); err != nil {
+		u.log.Warnf("Failed to create audit log: %+v", err)
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		u.log.Warnf("Failed commit transaction: %+v", err)
+		return nil, err
+	}
+
+	return converter.PatientProfileToResponse(profile, user), nil
+}
+
+// parseOptionalDate parses a YYYY-MM-DD date, an empty value is nil
+func parseOptionalDate(value string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+	date, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return nil, err
+	}
+	return &date, nil
+}
+
+// DeleteAccount erases the patient's own account (right to erasure).
+//
+// The account is deactivated and its PII anonymized: name, email, NIK, phone and
+// address are replaced, the date of birth is reduced to the year, the insurance and
+// emergency contact are removed, and so are the push devices. Bookings are kept (linked to the anonymized profile)
+// so booking statistics stay intact. Upcoming bookings must be cancelled first,
+// waitlist entries are dropped and every session of the patient ends. Needs the
+// password, except for Google-linked accounts.
+func (u *patientProfileUsecase) DeleteAccount(ctx context.Context, req *dto.DeletePatientAccountRequest) error {
+	userID, ok := middleware.GetUserIDFromContext(ctx)
+	if !ok {
+		return errors.New("user not found in context")
+	}
+
+	tx := u.db.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	user, err := u.userRepo.FindByID(tx, userID)
+	if err != nil {
+		u.log.Warnf("Failed to find user: %+v", err)
+		return err
+	}
+	if user == nil || user.PatientProfile == nil || user.IsErased() {
+		return ErrPatientNotFound
+	}
+
+	// Google accounts have a random password, the login session confirms them
+	if user.GoogleSubject == nil {
+		if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(req.Password)); err != nil {
+			return ErrInvalidOldPassword
+		}
+	}
+
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	upcoming, err := u.bookingRepo.CountUpcomingByPatient(tx, userID, today)
+	if err != nil {
+		u.log.Warnf("Failed to count upcoming bookings: %+v", err)
+		return err
+	}
+	if upcoming > 0 {
+		return ErrPatientHasUpcomingBookings
+	}
+
+	erasedAt := time.Now()
+	erased, err := u.userRepo.Erase(tx, userID, erasedAt)
+	if err != nil {
+		u.log.Warnf("Failed to erase user: %+v", err)
+		return err
+	}
+	if erased == 0 {
+		return ErrPatientNotFound
+	}
+
+	if err := u.patientProfileRepo.Anonymize(ctx, tx, user.PatientProfile); err != nil {
+		u.log.Warnf("Failed to anonymize patient profile: %+v", err)
+		return err
+	}
+
+	if _, err := u.deviceTokenRepo.DeleteByUserID(tx, userID); err != nil {
+		u.log.Warnf("Failed to delete devices of erased user: %+v", err)
+		return err
+	}
+
+	// The erasure request itself is recorded, without the erased data
+	if err := u.auditService.LogDelete(ctx, tx, &userID, entity.AuditActionAccountErase, "user", userID.String(), entity.JSON{
+		"reason":    req.Reason,
+		"erased_at": erasedAt,
+	}); err != nil {
+		u.log.Warnf("Failed to create audit log: %+v", err)
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		u.log.Warnf("Failed commit transaction: %+v", err)
+		return err
+	}
+
+	// The account is erased; Redis cleanup failures are logged, tokens also expire on their own
+	if err := revokeUserTokens(ctx, u.redisClient, u.log, userID); err != nil {
+		u.log.Warnf("Failed to revoke tokens of erased user %s: %+v", userID, err)
+	}
+	if _, err := u.redisSyncService.LeaveAllWaitlists(ctx, userID); err != nil {
+		u.log.Warnf("Failed to remove erased user %s from waitlists: %+v", userID, err)
+	}
+
+	return nil
+}
+
+// SearchPatients returns one page of the patients matching every given criterion, by name.
+// NIK and phone number match exactly, the name partially.
+func (u *patientProfileUsecase) SearchPatients(ctx context.Context, filter *dto.PatientSearchFilter, page, limit int) ([]dto.PatientResponse, int64, error) {
+	if filter.NIK == "" && filter.PhoneNumber == "" && filter.Name == "" {
+		return nil, 0, ErrPatientSearchEmpty
+	}
+	if filter.Name != "" && utf8.RuneCountInString(filter.Name) < minPatientSearchNameLength {
+		return nil, 0, ErrPatientSearchNameTooShort
+	}
+
+	profiles, total, err := u.patientProfileRepo.Search(ctx, u.db, &entity.PatientFilter{
+		NIK:         filter.NIK,
+		PhoneNumber: filter.PhoneNumber,
+		Name:        filter.Name,
+	}, page, limit)
+	if err != nil {
+		u.log.Warnf("Failed to search patients: %+v", err)
+		return nil, 0, err
+	}
+
+	response := make([]dto.PatientResponse, 0, len(profiles))
+	for i := range profiles {
+		response = append(response, *converter.PatientProfileToResponse(&profiles[i], &profiles[i].User))
+	}
+	return response, total, nil
+}
+
+// GetPatientTimeline returns a patient's journey for support investigation, oldest first.
+//
+// Sources:
+// - users.created_at → registration
+// - bookings → booking created (current status in details)
+// - audit_logs by the patient, or about the patient's bookings (logins, profile edits, admin cancels)
+// - outbox events of the patient's bookings → status changes and notifications with delivery status
+// - schedule.updated events of booked schedules → schedule-change notifications
+//
+// Returns the page of events and the total number of events.
+func (u *patientProfileUsecase) GetPatientTimeline(ctx context.Context, patientID uuid.UUID, page, limit int) (*dto.PatientTimelineResponse, int64, error) {
+	db := u.db.WithContext(ctx)
+
+	profile, err := u.patientProfileRepo.FindByUserID(ctx, u.db, patientID)
+	if err != nil {
+		u.log.Warnf("Failed to find patient profile: %+v", err)
+		return nil, 0, err
+	}
+	if profile == nil {
+		return nil, 0, ErrPatientNotFound
+	}
+	user, err := u.userRepo.FindByID(db, patientID)
+	if err != nil || user == nil {
+		u.log.Warnf("Failed to find user %s: %+v", patientID, err)
+		return nil, 0, ErrPatientNotFound
+	}
+
+	bookings, err := u.bookingRepo.FindByPatientID(db, patientID)
+	if err != nil {
+		u.log.Warnf("Failed to find bookings for patient %s: %+v", patientID, err)
+		return nil, 0, err
+	}
+
+	events := []dto.PatientTimelineEventResponse{{
+		Type:        "patient.registered",
+		Source:      "patient",
+		OccurredAt:  user.CreatedAt,
+		ActorID:     &user.ID,
+		Description: "Patient registered",
+	}}
+
+	bookingIDs := make([]string, 0, len(bookings))
+	scheduleBookings := make(map[string][]entity.Booking)
+	for _, b := range bookings {
+		bookingID := b.ID
+		bookingIDs = append(bookingIDs, b.ID.String())
+		scheduleBookings[strconv.Itoa(b.ScheduleID)] = append(scheduleBookings[strconv.Itoa(b.ScheduleID)], b)
+		events = append(events, dto.PatientTimelineEventResponse{
+			Type:        entity.OutboxEventBookingCreated,
+			Source:      "booking",
+			OccurredAt:  b.CreatedAt,
+			BookingID:   &bookingID,
+			Description: fmt.Sprintf("Booking %s created for schedule %d, queue %d", b.BookingCode, b.ScheduleID, b.QueueNumber),
+			Details:     entity.JSON{"booking_code": b.BookingCode, "schedule_id": b.ScheduleID, "queue_number": b.QueueNumber, "current_status": b.Status},
+		})
+	}
+
+	// Audit trail (registration and booking creation are already covered above)
+	auditLogs, err := u.auditRepo.FindByUserOrEntities(db, patientID, "booking", bookingIDs)
+	if err != nil {
+		u.log.Warnf("Failed to find audit logs for patient %s: %+v", patientID, err)
+		return nil, 0, err
+	}
+	for _, auditLog := range auditLogs {
+		if auditLog.Action == entity.AuditActionUserRegister || auditLog.Action == entity.AuditActionBookingCreate {
+			continue
+		}
+		item := dto.PatientTimelineEventResponse{
+			Type:        auditLog.Action,
+			Source:      "audit_log",
+			OccurredAt:  auditLog.CreatedAt,
+			ActorID:     auditLog.UserID,
+			Description: fmt.Sprintf("Audit: %s", auditLog.Action),
+			Details:     auditLog.Metadata,
+		}
+		if auditLog.Metadata["entity"] == "booking" {
+			if id, err := uuid.Parse(fmt.Sprint(auditLog.Metadata["entity_id"])); err == nil {
+				item.BookingID = &id
+			}
+		}
+		events = append(events, item)
+	}
+
+	// Booking status changes and notifications
+	bookingEvents, err := u.outboxRepo.FindByAggregates(db, "booking", bookingIDs)
+	if err != nil {
+		u.log.Warnf("Failed to find booking events for patient %s: %+v", patientID, err)
+		return nil, 0, err
+	}
+	for i := range bookingEvents {
+		event := &bookingEvents[i]
+		var payload entity.BookingEventPayload
+		if err := event.DecodePayload(&payload); err != nil {
+			u.log.Warnf("Failed to decode outbox event %d: %+v", event.ID, err)
+			continue
+		}
+		bookingID := payload.BookingID
+
+		if event.EventType != entity.OutboxEventBookingCreated {
+			events = append(events, dto.PatientTimelineEventResponse{
+				Type:        event.EventType,
+				Source:      "booking",
+				OccurredAt:  event.CreatedAt,
+				BookingID:   &bookingID,
+				Description: fmt.Sprintf("Booking %s: %s", payload.BookingCode, event.EventType),
+				Details:     event.Payload,
+			})
+		}
+		if payload.NotifiesPatient(event.EventType) {
+			events = append(events, notificationTimelineEvent(event, &bookingID, fmt.Sprintf("Notification for booking %s (%s)", payload.BookingCode, event.EventType)))
+		}
+	}
+
+	// Schedule changes notified while the booking was active
+	scheduleIDs := make([]string, 0, len(scheduleBookings))
+	for scheduleID := range scheduleBookings {
+		scheduleIDs = append(scheduleIDs, scheduleID)
+	}
+	scheduleEvents, err := u.outboxRepo.FindByAggregates(db, "doctor_schedule", scheduleIDs)
+	if err != nil {
+		u.log.Warnf("Failed to find schedule events for patient %s: %+v", patientID, err)
+		return nil, 0, err
+	}
+	for i := range scheduleEvents {
+		event := &scheduleEvents[i]
+		for _, b := range scheduleBookings[event.AggregateID] {
+			if event.CreatedAt.Before(b.CreatedAt) || (b.IsCancelled() && event.CreatedAt.After(b.UpdatedAt)) {
+				continue
+			}
+			bookingID := b.ID
+			events = append(events, notificationTimelineEvent(event, &bookingID, fmt.Sprintf("Notification for booking %s (%s)", b.BookingCode, event.EventType)))
+		}
+	}
+
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].OccurredAt.Before(events[j].OccurredAt)
+	})
+
+	// Paginate
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = defaultTimelineLimit
+	}
+	if limit > maxTimelineLimit {
+		limit = maxTimelineLimit
+	}
+	total := int64(len(events))
+	start := (page - 1) * limit
+	if start > len(events) {
+		start = len(events)
+	}
+	end := start + limit
+	if end > len(events) {
+		end = len(events)
+	}
+
+	return &dto.PatientTimelineResponse{
+		Patient: *converter.PatientProfileToResponse(profile, user),
+		Events:  events[start:end],
+	}, total, nil
+}
+
+// notificationTimelineEvent describes the delivery of an outbox-driven notification
+func notificationTimelineEvent(event *entity.OutboxEvent, bookingID *uuid.UUID, description string) dto.PatientTimelineEventResponse {
+	occurredAt := event.CreatedAt
+	if event.PublishedAt != nil {
+		occurredAt = *event.PublishedAt
+	}
+	var lastError interface{}
+	if event.LastError != "" {
+		lastError = event.LastError
+	}
+	return dto.PatientTimelineEventResponse{
+		Type:        "notification",
+		Source:      "notification",
+		OccurredAt:  occurredAt,
+		BookingID:   bookingID,
+		Description: description,
+		Details: entity.JSON{
+			"event_type": event.EventType,
+			"outbox_id":  event.ID,
+			"status":     event.Status,
+			"attempts":   event.Attempts,
+			"last_error": lastError,
+		},
+	}
+}
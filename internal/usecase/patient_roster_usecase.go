@@ -0,0 +1,450 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/csv"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"go-template-clean-architecture/internal/converter"
+	"go-template-clean-architecture/internal/delivery/dto"
+	"go-template-clean-architecture/internal/delivery/http/middleware"
+	"go-template-clean-architecture/internal/domain/entity"
+	"go-template-clean-architecture/internal/domain/repository"
+	"go-template-clean-architecture/internal/service"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrRosterPartnerRequired = errors.New("partner is required")
+	ErrInvalidClaimCode      = errors.New("invalid or expired claim code")
+	ErrClaimTooManyAttempts  = errors.New("too many claim attempts, request a new code later")
+)
+
+// maxRosterImportRows bounds a single roster file (one DB transaction)
+const maxRosterImportRows = 5000
+
+// Roster CSV columns (header row required, any order)
+const (
+	rosterColNIK         = "nik"
+	rosterColFullName    = "full_name"
+	rosterColDateOfBirth = "date_of_birth"
+	rosterColPhoneNumber = "phone_number"
+	rosterColGender      = "gender"
+	rosterColAddress     = "address"
+)
+
+const (
+	claimCodeTTL        = 10 * time.Minute
+	claimResendCooldown = time.Minute
+	maxClaimAttempts    = 5
+	claimCodePrefix     = "patient_claim:code:"
+	claimCooldownPrefix = "patient_claim:cooldown:"
+	claimAttemptsPrefix = "patient_claim:attempts:"
+	claimCodeEventType  = "patient.claim_code"
+)
+
+var nikPattern = regexp.MustCompile(`^[0-9]{16}$`)
+
+// PatientRosterUsecase pre-registers patients from a corporate/insurance partner roster
+// and lets them claim the account later by verifying their registered phone.
+type PatientRosterUsecase interface {
+	ImportRoster(ctx context.Context, file io.Reader, partner string, dryRun bool) (*dto.PatientRosterImportResponse, error)
+	RequestClaim(ctx context.Context, req *dto.RequestClaimRequest) error
+	VerifyClaim(ctx context.Context, req *dto.VerifyClaimRequest) (*dto.UserResponse, error)
+}
+
+type patientRosterUsecase struct {
+	db                  *gorm.DB
+	log                 *logrus.Logger
+	userRepo            repository.UserRepository
+	patientProfileRepo  repository.PatientProfileRepository
+	redisClient         *redis.Client
+	notificationService *service.NotificationService
+	auditService        service.AuditService
+}
+
+func NewPatientRosterUsecase(
+	db *gorm.DB,
+	log *logrus.Logger,
+	userRepo repository.UserRepository,
+	patientProfileRepo repository.PatientProfileRepository,
+	redisClient *redis.Client,
+	notificationService *service.NotificationService,
+	auditService service.AuditService,
+) PatientRosterUsecase {
+	return &patientRosterUsecase{
+		db:                  db,
+		log:                 log,
+		userRepo:            userRepo,
+		patientProfileRepo:  patientProfileRepo,
+		redisClient:         redisClient,
+		notificationService: notificationService,
+		auditService:        auditService,
+	}
+}
+
+// ImportRoster pre-creates unclaimed patient accounts from a partner roster CSV.
+//
+// Columns: nik, full_name, date_of_birth, phone_number, gender (required), address (optional).
+//
+// Accounts get a placeholder email and no password, so nobody can log in until the
+// patient claims the account. Staff can book on their behalf in the meantime; the
+// bookings stay with the account when it is claimed. Any invalid row → nothing is imported.
+func (u *patientRosterUsecase) ImportRoster(ctx context.Context, file io.Reader, partner string, dryRun bool) (*dto.PatientRosterImportResponse, error) {
+	partner = strings.TrimSpace(partner)
+	if partner == "" {
+		return nil, ErrRosterPartnerRequired
+	}
+
+	reader := csv.NewReader(file)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, ErrImportInvalidFile
+	}
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, ErrImportInvalidFile
+	}
+	if len(records) > maxRosterImportRows {
+		return nil, ErrImportTooManyRows
+	}
+
+	result := &dto.PatientRosterImportResponse{
+		DryRun:    dryRun,
+		Partner:   partner,
+		TotalRows: len(records),
+	}
+
+	v := &importValidator{}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))] = i
+	}
+	for _, required := range []string{rosterColNIK, rosterColFullName, rosterColDateOfBirth, rosterColPhoneNumber, rosterColGender} {
+		if _, ok := columns[required]; !ok {
+			v.add(1, required, "missing required column")
+		}
+	}
+	if len(v.errors) > 0 {
+		result.Errors = v.errors
+		return result, ErrImportInvalidRows
+	}
+
+	users, err := u.parseRoster(ctx, records, columns, partner, v)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(v.errors) > 0 {
+		sort.SliceStable(v.errors, func(i, j int) bool { return v.errors[i].Row < v.errors[j].Row })
+		result.Errors = v.errors
+		return result, ErrImportInvalidRows
+	}
+
+	if dryRun {
+		return result, nil
+	}
+
+	tx := u.db.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := u.userRepo.CreateBatch(tx, users); err != nil {
+		u.log.Warnf("Failed to import patient roster: %+v", err)
+		if isDuplicateKeyError(err, "nik") || isDuplicateKeyError(err, "email") {
+			return nil, ErrNIKAlreadyExists
+		}
+		return nil, err
+	}
+
+	// Audit log - single consolidated entry
+	adminID, _ := middleware.GetUserIDFromContext(ctx)
+	if err := u.auditService.LogCreate(ctx, tx, &adminID, entity.AuditActionPatientPreRegister, "user", "roster",
+		entity.JSON{"imported": len(users), "partner": partner},
+	); err != nil {
+		u.log.Warnf("Failed to create audit log: %+v", err)
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		u.log.Warnf("Failed commit transaction: %+v", err)
+		return nil, err
+	}
+	result.Imported = len(users)
+
+	u.log.Infof("Patient roster imported: partner=%s, rows=%d", partner, result.Imported)
+	return result, nil
+}
+
+// parseRoster converts CSV records to unclaimed patient users, recording row errors in v.
+// Returns an error only for database failures.
+func (u *patientRosterUsecase) parseRoster(ctx context.Context, records [][]string, columns map[string]int, partner string, v *importValidator) ([]entity.User, error) {
+	users := make([]entity.User, 0, len(records))
+	nikLines := make(map[string]int)
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	claimed := false
+
+	field := func(record []string, name string) string {
+		i, ok := columns[name]
+		if !ok || i >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[i])
+	}
+
+	for i, record := range records {
+		line := i + 2
+		valid := true
+
+		nik := field(record, rosterColNIK)
+		if !nikPattern.MatchString(nik) {
+			v.add(line, rosterColNIK, "must be 16 digits")
+			valid = false
+		} else if first, ok := nikLines[nik]; ok {
+			v.add(line, rosterColNIK, "duplicate of row %d", first)
+			valid = false
+		} else {
+			nikLines[nik] = line
+		}
+
+		fullName := field(record, rosterColFullName)
+		if fullName == "" {
+			v.add(line, rosterColFullName, "is required")
+			valid = false
+		} else if len(fullName) > 255 {
+			v.add(line, rosterColFullName, "must be at most 255 characters")
+			valid = false
+		}
+
+		dob, err := time.Parse("2006-01-02", field(record, rosterColDateOfBirth))
+		if err != nil {
+			v.add(line, rosterColDateOfBirth, "must be YYYY-MM-DD")
+			valid = false
+		} else if dob.After(today) {
+			v.add(line, rosterColDateOfBirth, "cannot be in the future")
+			valid = false
+		}
+
+		// The phone receives the claim code, so it is required here (optional on self-registration)
+		phone := field(record, rosterColPhoneNumber)
+		if len(phone) < 10 || len(phone) > 20 {
+			v.add(line, rosterColPhoneNumber, "must be 10-20 characters")
+			valid = false
+		}
+
+		gender := strings.ToUpper(field(record, rosterColGender))
+		if gender != entity.GenderMale && gender != entity.GenderFemale {
+			v.add(line, rosterColGender, "must be M or F")
+			valid = false
+		}
+
+		if valid {
+			users = append(users, entity.User{
+				RoleID:    entity.RoleIDPatient,
+				Email:     entity.UnclaimedEmail(nik),
+				FullName:  fullName,
+				IsClaimed: &claimed,
+				PatientProfile: &entity.PatientProfile{
+					NIK:         nik,
+					PhoneNumber: phone,
+					DateOfBirth: dob,
+					Gender:      gender,
+					Address:     field(record, rosterColAddress),
+					Partner:     partner,
+				},
+			})
+		}
+	}
+
+	// NIKs already registered (self-registered or an earlier roster)
+	niks := make([]string, 0, len(nikLines))
+	for nik := range nikLines {
+		niks = append(niks, nik)
+	}
+	existing, err := u.patientProfileRepo.FindExistingNIKs(ctx, u.db, niks)
+	if err != nil {
+		return nil, err
+	}
+	for _, nik := range existing {
+		v.add(nikLines[nik], rosterColNIK, "NIK %s is already registered", nik)
+	}
+
+	return users, nil
+}
+
+// RequestClaim sends a claim code to the registered phone of a pre-registered patient.
+//
+// The NIK and date of birth must match the roster. To prevent enumeration the result is
+// the same whether or not a claimable account exists; only codes actually sent are logged.
+func (u *patientRosterUsecase) RequestClaim(ctx context.Context, req *dto.RequestClaimRequest) error {
+	if _, err := time.Parse("2006-01-02", req.DateOfBirth); err != nil {
+		return ErrInvalidDateFormat
+	}
+
+	profile, err := u.patientProfileRepo.FindByNIK(ctx, u.db, req.NIK)
+	if err != nil {
+		u.log.Warnf("Failed to find patient by NIK: %+v", err)
+		return err
+	}
+	if profile == nil || !profile.User.IsPendingClaim() || profile.PhoneNumber == "" ||
+		profile.DateOfBirth.Format("2006-01-02") != req.DateOfBirth {
+		return nil
+	}
+	userID := profile.UserID
+
+	// One code per cooldown period, so the endpoint cannot be used to flood a phone
+	ok, err := u.redisClient.SetNX(ctx, claimCooldownPrefix+userID.String(), "1", claimResendCooldown).Result()
+	if err != nil {
+		u.log.Warnf("Failed to set claim cooldown: %+v", err)
+		return err
+	}
+	if !ok {
+		return nil
+	}
+
+	code, err := generateClaimCode()
+	if err != nil {
+		return err
+	}
+
+	// A new code replaces the previous one and resets the attempt counter
+	pipe := u.redisClient.TxPipeline()
+	pipe.Set(ctx, claimCodePrefix+userID.String(), hashClaimCode(code), claimCodeTTL)
+	pipe.Del(ctx, claimAttemptsPrefix+userID.String())
+	if _, err := pipe.Exec(ctx); err != nil {
+		u.log.Warnf("Failed to store claim code: %+v", err)
+		return err
+	}
+
+	if err := u.notificationService.Notify(ctx, u.db.WithContext(ctx), service.NotificationRequest{
+		PatientID: userID,
+		EventType: claimCodeEventType,
+		Message:   fmt.Sprintf("Your account activation code is %s. It expires in %d minutes, do not share it.", code, int(claimCodeTTL.Minutes())),
+		DedupeKey: fmt.Sprintf("claim:%s:%s", userID, uuid.NewString()),
+	}); err != nil {
+		u.log.Warnf("Failed to send claim code to patient %s: %+v", userID, err)
+		return err
+	}
+
+	u.log.Infof("Claim code sent to pre-registered patient %s", userID)
+	return nil
+}
+
+// VerifyClaim checks the claim code and sets the patient's email and password.
+//
+// The account keeps its ID, so bookings made on the patient's behalf stay linked.
+// After maxClaimAttempts wrong codes the code is unusable until it expires.
+func (u *patientRosterUsecase) VerifyClaim(ctx context.Context, req *dto.VerifyClaimRequest) (*dto.UserResponse, error) {
+	profile, err := u.patientProfileRepo.FindByNIK(ctx, u.db, req.NIK)
+	if err != nil {
+		u.log.Warnf("Failed to find patient by NIK: %+v", err)
+		return nil, err
+	}
+	if profile == nil || !profile.User.IsPendingClaim() {
+		return nil, ErrInvalidClaimCode
+	}
+	userID := profile.UserID
+	codeKey := claimCodePrefix + userID.String()
+	attemptsKey := claimAttemptsPrefix + userID.String()
+
+	attempts, err := loginRateLimitScript.Run(ctx, u.redisClient, []string{attemptsKey}, int(claimCodeTTL.Seconds())).Int()
+	if err != nil {
+		u.log.Warnf("Failed to increment claim attempts: %+v", err)
+		return nil, err
+	}
+	if attempts > maxClaimAttempts {
+		return nil, ErrClaimTooManyAttempts
+	}
+
+	stored, err := u.redisClient.Get(ctx, codeKey).Result()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, ErrInvalidClaimCode
+		}
+		u.log.Warnf("Failed to get claim code: %+v", err)
+		return nil, err
+	}
+	if subtle.ConstantTimeCompare([]byte(stored), []byte(hashClaimCode(req.Code))) != 1 {
+		return nil, ErrInvalidClaimCode
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
+	if err != nil {
+		u.log.Warnf("Failed to hash password: %+v", err)
+		return nil, err
+	}
+
+	tx := u.db.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	claimedAt := time.Now()
+	updated, err := u.userRepo.Claim(tx, userID, req.Email, string(hashedPassword), claimedAt)
+	if err != nil {
+		if isDuplicateKeyError(err, "email") {
+			return nil, ErrEmailAlreadyExists
+		}
+		u.log.Warnf("Failed to claim user %s: %+v", userID, err)
+		return nil, err
+	}
+	if updated == 0 {
+		// Claimed concurrently
+		return nil, ErrInvalidClaimCode
+	}
+
+	if err := u.auditService.LogUpdate(ctx, tx, &userID, entity.AuditActionPatientClaim, "user", userID.String(),
+		entity.JSON{"email": profile.User.Email, "is_claimed": false},
+		entity.JSON{"email": req.Email, "is_claimed": true, "partner": profile.Partner},
+	); err != nil {
+		u.log.Warnf("Failed to create audit log: %+v", err)
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		u.log.Warnf("Failed commit transaction: %+v", err)
+		return nil, err
+	}
+
+	if err := u.redisClient.Del(ctx, codeKey, attemptsKey, claimCooldownPrefix+userID.String()).Err(); err != nil {
+		u.log.Warnf("Failed to delete claim keys of user %s (non-fatal): %+v", userID, err)
+	}
+
+	user, err := u.userRepo.FindByID(u.db.WithContext(ctx), userID)
+	if err != nil {
+		u.log.Warnf("Failed to find claimed user %s: %+v", userID, err)
+		return nil, err
+	}
+
+	u.log.Infof("Pre-registered patient %s claimed the account", userID)
+	return converter.UserToResponse(user), nil
+}
+
+// generateClaimCode returns a random 6-digit code
+func generateClaimCode() (string, error) {
+	n, err := rand.Int(rand.Reader, big.NewInt(1000000))
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%06d", n.Int64()), nil
+}
+
+// hashClaimCode hashes a claim code for storage, so a Redis dump does not reveal live codes
+func hashClaimCode(code string) string {
+	sum := sha256.Sum256([]byte(code))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,565 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"go-template-clean-architecture/internal/converter"
+	"go-template-clean-architecture/internal/delivery/dto"
+	"go-template-clean-architecture/internal/delivery/http/middleware"
+	"go-template-clean-architecture/internal/domain/entity"
+	"go-template-clean-architecture/internal/domain/repository"
+	"go-template-clean-architecture/internal/service"
+	"go-template-clean-architecture/pkg/payment"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrPaymentNotFound         = errors.New("payment not found")
+	ErrPaymentGatewayNotFound  = errors.New("payment gateway not found")
+	ErrInvalidPaymentCallback  = errors.New("invalid payment callback")
+	ErrPaymentAmountMismatch   = errors.New("paid amount does not match the payment")
+	ErrPaymentCurrencyMismatch = errors.New("paid currency does not match the payment")
+	ErrPaymentNotRefundable    = errors.New("payment is not paid, or refunded or being refunded already")
+	ErrPaymentBookingActive    = errors.New("booking of the payment is not cancelled")
+	errPaymentBookingNotClosed = errors.New("unpaid booking was modified concurrently")
+)
+
+type PaymentUsecase interface {
+	HandleCallback(ctx context.Context, gateway string, header http.Header, body []byte) error
+	GetMyBookingPayment(ctx context.Context, bookingID uuid.UUID) (*dto.PaymentResponse, error)
+	RefundPayment(ctx context.Context, id uuid.UUID) (*dto.PaymentResponse, error)
+}
+
+// Refund reasons recorded on the payment
+const (
+	refundReasonCancelled = "booking cancelled (%s)"
+	refundReasonClosed    = "paid after the booking was closed"
+	refundReasonRetry     = "refund retried by admin"
+)
+
+type paymentUsecase struct {
+	db             *gorm.DB
+	log            *logrus.Logger
+	paymentRepo    repository.PaymentRepository
+	bookingRepo    repository.BookingRepository
+	paymentService *service.PaymentService
+	outboxService  *service.OutboxService
+	auditService   service.AuditService
+	invoiceRepo    repository.InvoiceRepository
+	eventBus       *service.EventBus
+}
+
+func NewPaymentUsecase(
+	db *gorm.DB,
+	log *logrus.Logger,
+	paymentRepo repository.PaymentRepository,
+	bookingRepo repository.BookingRepository,
+	paymentService *service.PaymentService,
+	outboxService *service.OutboxService,
+	auditService service.AuditService,
+	invoiceRepo repository.InvoiceRepository,
+	eventBus *service.EventBus,
+) PaymentUsecase {
+	u := &paymentUsecase{
+		db:             db,
+		log:            log,
+		paymentRepo:    paymentRepo,
+		bookingRepo:    bookingRepo,
+		paymentService: paymentService,
+		outboxService:  outboxService,
+		auditService:   auditService,
+		invoiceRepo:    invoiceRepo,
+		eventBus:       eventBus,
+	}
+	u.outboxService.RegisterHandler(entity.OutboxEventPaymentExpire, u.handlePaymentExpire)
+	u.outboxService.RegisterHandler(entity.OutboxEventPaymentRefund, u.handlePaymentRefund)
+	u.eventBus.Subscribe(entity.OutboxEventBookingCancelled, u.handleBookingCancelled)
+	return u
+}
+
+// HandleCallback records the outcome of a payment reported by the gateway.
+//
+// Paid: the payment, the booking confirmation and its booking.confirmed event are recorded in
+// one transaction. Failed or expired: the payment is closed and the booking cancelled, which
+// releases its slot. Callbacks are repeated by the gateways, repeats of a final outcome are
+// accepted without effect. Refund outcomes complete the pending refund of the payment.
+func (u *paymentUsecase) HandleCallback(ctx context.Context, gateway string, header http.Header, body []byte) error {
+	callback, err := u.paymentService.ParseCallback(gateway, header, body)
+	if err != nil {
+		if errors.Is(err, service.ErrUnknownPaymentGateway) {
+			return ErrPaymentGatewayNotFound
+		}
+		u.log.Warnf("Rejected payment callback of %s: %+v", gateway, err)
+		return ErrInvalidPaymentCallback
+	}
+
+	paymentID, err := uuid.Parse(callback.OrderID)
+	if err != nil {
+		return ErrPaymentNotFound
+	}
+
+	switch callback.Status {
+	case payment.StatusPaid:
+		return u.confirmPayment(ctx, paymentID, callback)
+	case payment.StatusFailed:
+		return u.closePayment(ctx, paymentID, entity.PaymentStatusFailed, callback.Reason, entity.AuditActionPaymentFail)
+	case payment.StatusExpired:
+		return u.closePayment(ctx, paymentID, entity.PaymentStatusExpired, "not paid in time", entity.AuditActionPaymentExpire)
+	case payment.StatusRefunded:
+		return u.completeRefund(ctx, paymentID, entity.PaymentRefundSucceeded, callback.Reference, "")
+	case payment.StatusRefundFailed:
+		return u.completeRefund(ctx, paymentID, entity.PaymentRefundFailed, callback.Reference, callback.Reason)
+	}
+	// Not final yet
+	return nil
+}
+
+// GetMyBookingPayment returns the latest payment of the logged-in patient's booking
+func (u *paymentUsecase) GetMyBookingPayment(ctx context.Context, bookingID uuid.UUID) (*dto.PaymentResponse, error) {
+	userID, ok := middleware.GetUserIDFromContext(ctx)
+	if !ok {
+		return nil, errors.New("user not found in context")
+	}
+
+	booking, err := u.bookingRepo.FindByID(u.db.WithContext(ctx), bookingID)
+	if err != nil {
+		u.log.Warnf("Failed to find booking %s: %+v", bookingID, err)
+		return nil, err
+	}
+	if booking == nil {
+		return nil, ErrBookingNotFound
+	}
+	if booking.PatientID != userID {
+		return nil, ErrBookingNotOwned
+	}
+
+	p, err := u.paymentRepo.FindLatestByBookingID(u.db.WithContext(ctx), bookingID)
+	if err != nil {
+		u.log.Warnf("Failed to find payment of booking %s: %+v", bookingID, err)
+		return nil, err
+	}
+	if p == nil {
+		return nil, ErrPaymentNotFound
+	}
+
+	return converter.PaymentToResponse(p), nil
+}
+
+// RefundPayment requests again the refund of a paid payment of a cancelled booking (admin),
+// after the gateway rejected it or when it was never requested
+func (u *paymentUsecase) RefundPayment(ctx context.Context, id uuid.UUID) (*dto.PaymentResponse, error) {
+	actorID, ok := middleware.GetUserIDFromContext(ctx)
+	if !ok {
+		return nil, errors.New("user not found in context")
+	}
+
+	tx := u.db.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	p, err := u.paymentRepo.FindByID(tx, id)
+	if err != nil {
+		u.log.Warnf("Failed to find payment %s: %+v", id, err)
+		return nil, err
+	}
+	if p == nil {
+		return nil, ErrPaymentNotFound
+	}
+	if !p.IsRefundable() {
+		return nil, ErrPaymentNotRefundable
+	}
+
+	booking, err := u.bookingRepo.FindByID(tx, p.BookingID)
+	if err != nil {
+		u.log.Warnf("Failed to find booking %s: %+v", p.BookingID, err)
+		return nil, err
+	}
+	if booking != nil && !booking.IsCancelled() {
+		return nil, ErrPaymentBookingActive
+	}
+
+	if err := u.requestRefund(ctx, tx, p, refundReasonRetry, &actorID); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		u.log.Warnf("Failed commit transaction: %+v", err)
+		return nil, err
+	}
+
+	p, err = u.paymentRepo.FindByID(u.db.WithContext(ctx), id)
+	if err != nil || p == nil {
+		u.log.Warnf("Failed to reload payment %s: %+v", id, err)
+		return nil, errors.Join(ErrPaymentNotFound, err)
+	}
+	return converter.PaymentToResponse(p), nil
+}
+
+// confirmPayment records a paid payment and confirms its booking
+func (u *paymentUsecase) confirmPayment(ctx context.Context, paymentID uuid.UUID, callback *payment.Callback) error {
+	tx := u.db.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	p, err := u.paymentRepo.FindByID(tx, paymentID)
+	if err != nil {
+		u.log.Warnf("Failed to find payment %s: %+v", paymentID, err)
+		return err
+	}
+	if p == nil {
+		return ErrPaymentNotFound
+	}
+	if callback.Amount != 0 && callback.Amount != p.Amount {
+		u.log.Errorf("Payment %s reported paid with %d instead of %d %s", p.ID, callback.Amount, p.Amount, p.Currency)
+		return ErrPaymentAmountMismatch
+	}
+	// The amount alone proves nothing when paid in another currency
+	if callback.Currency != "" && !strings.EqualFold(callback.Currency, p.Currency) {
+		u.log.Errorf("Payment %s reported paid in %s instead of %s", p.ID, callback.Currency, p.Currency)
+		return ErrPaymentCurrencyMismatch
+	}
+
+	affected, err := u.paymentRepo.MarkPaid(tx, p.ID, callback.Reference, time.Now())
+	if err != nil {
+		u.log.Warnf("Failed to mark payment %s paid: %+v", p.ID, err)
+		return err
+	}
+	if affected == 0 {
+		// Already paid
+		return nil
+	}
+
+	confirmed, err := u.bookingRepo.ConfirmPayment(tx, p.BookingID)
+	if err != nil {
+		u.log.Warnf("Failed to confirm booking %s: %+v", p.BookingID, err)
+		return err
+	}
+	if confirmed == 0 {
+		// Cancelled while the patient was paying, or the payment was closed first
+		u.log.Warnf("Payment %s was paid after booking %s was closed, refunding it", p.ID, p.BookingID)
+		if err := u.requestRefund(ctx, tx, p, refundReasonClosed, nil); err != nil {
+			return err
+		}
+	} else {
+		booking, err := u.bookingRepo.FindByID(tx, p.BookingID)
+		if err != nil || booking == nil {
+			u.log.Warnf("Failed to reload booking %s: %+v", p.BookingID, err)
+			return errors.Join(ErrBookingNotFound, err)
+		}
+
+		if err := u.outboxService.Enqueue(tx, entity.OutboxEventBookingConfirmed, "booking", booking.ID.String(), entity.BookingEventPayload{
+			BookingID:   booking.ID,
+			BookingCode: booking.BookingCode,
+			PatientID:   booking.PatientID,
+			ScheduleID:  booking.ScheduleID,
+			QueueNumber: booking.QueueNumber,
+			SlotID:      booking.SlotID,
+		}); err != nil {
+			u.log.Warnf("Failed to enqueue booking event: %+v", err)
+			return err
+		}
+
+		// Audit log - confirmed by the gateway (no acting user)
+		if err := u.auditService.LogUpdate(ctx, tx, nil, entity.AuditActionBookingConfirm, "booking", booking.ID.String(),
+			entity.JSON{"status": entity.BookingStatusAwaitingPayment},
+			entity.JSON{"status": entity.BookingStatusConfirmed, "payment_id": p.ID},
+		); err != nil {
+			u.log.Warnf("Failed to create audit log: %+v", err)
+		}
+	}
+
+	if err := u.auditService.LogUpdate(ctx, tx, nil, entity.AuditActionPaymentPaid, "payment", p.ID.String(),
+		entity.JSON{"status": p.Status},
+		entity.JSON{"status": entity.PaymentStatusPaid, "gateway": p.Gateway, "gateway_reference": callback.Reference, "amount": p.Amount, "currency": p.Currency},
+	); err != nil {
+		u.log.Warnf("Failed to create audit log: %+v", err)
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		u.log.Warnf("Failed commit transaction: %+v", err)
+		return err
+	}
+
+	u.log.Infof("Payment paid: id=%s, booking=%s, gateway=%s", p.ID, p.BookingID, p.Gateway)
+	return nil
+}
+
+// closePayment records a failed or expired payment and cancels its unpaid booking
+func (u *paymentUsecase) closePayment(ctx context.Context, paymentID uuid.UUID, status entity.PaymentStatus, reason string, action string) error {
+	tx := u.db.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	p, err := u.paymentRepo.FindByID(tx, paymentID)
+	if err != nil {
+		u.log.Warnf("Failed to find payment %s: %+v", paymentID, err)
+		return err
+	}
+	if p == nil {
+		return ErrPaymentNotFound
+	}
+
+	affected, err := u.paymentRepo.Close(tx, p.ID, status, reason)
+	if err != nil {
+		u.log.Warnf("Failed to close payment %s: %+v", p.ID, err)
+		return err
+	}
+	if affected == 0 {
+		// Already final
+		return nil
+	}
+
+	if err := u.cancelUnpaidBooking(tx, p.BookingID); err != nil {
+		return err
+	}
+
+	if err := u.auditService.LogUpdate(ctx, tx, nil, action, "payment", p.ID.String(),
+		entity.JSON{"status": p.Status},
+		entity.JSON{"status": status, "reason": reason, "booking_id": p.BookingID},
+	); err != nil {
+		u.log.Warnf("Failed to create audit log: %+v", err)
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		u.log.Warnf("Failed commit transaction: %+v", err)
+		return err
+	}
+
+	u.log.Infof("Payment %s: id=%s, booking=%s", status, p.ID, p.BookingID)
+	return nil
+}
+
+// handlePaymentExpire cancels a booking still awaiting payment at its payment deadline.
+// Called by the outbox worker for payment.expire events.
+func (u *paymentUsecase) handlePaymentExpire(ctx context.Context, event *entity.OutboxEvent) error {
+	var payload entity.PaymentExpirePayload
+	if err := event.DecodePayload(&payload); err != nil {
+		return err
+	}
+
+	tx := u.db.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	expired, err := u.paymentRepo.ClosePendingByBookingID(tx, payload.BookingID, entity.PaymentStatusExpired, "not paid in time")
+	if err != nil {
+		return err
+	}
+
+	booking, err := u.bookingRepo.FindByID(tx, payload.BookingID)
+	if err != nil {
+		return err
+	}
+	if booking == nil || !booking.IsAwaitingPayment() {
+		// Paid or cancelled in time
+		return tx.Commit().Error
+	}
+
+	if err := u.cancelUnpaidBooking(tx, booking.ID); err != nil {
+		return err
+	}
+
+	// Audit log - system expiry (no acting user)
+	if err := u.auditService.LogUpdate(ctx, tx, nil, entity.AuditActionPaymentExpire, "booking", booking.ID.String(),
+		entity.JSON{"status": booking.Status},
+		entity.JSON{"status": entity.BookingStatusCancelled, "expired_payments": expired},
+	); err != nil {
+		u.log.Warnf("Failed to create audit log: %+v", err)
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return err
+	}
+
+	u.log.Infof("Unpaid booking cancelled: id=%s, schedule=%d", booking.ID, booking.ScheduleID)
+	return nil
+}
+
+// cancelUnpaidBooking cancels a booking still awaiting payment with its booking.cancelled
+// event, which releases the slot. Bookings no longer awaiting payment are left alone.
+func (u *paymentUsecase) cancelUnpaidBooking(tx *gorm.DB, bookingID uuid.UUID) error {
+	booking, err := u.bookingRepo.FindByID(tx, bookingID)
+	if err != nil {
+		u.log.Warnf("Failed to find booking %s: %+v", bookingID, err)
+		return err
+	}
+	if booking == nil || !booking.IsAwaitingPayment() {
+		return nil
+	}
+
+	affected, err := u.bookingRepo.CancelBooking(tx, booking.ID, booking.Version)
+	if err != nil {
+		u.log.Warnf("Failed to cancel booking %s: %+v", booking.ID, err)
+		return err
+	}
+	if affected == 0 {
+		return errPaymentBookingNotClosed
+	}
+
+	return u.outboxService.Enqueue(tx, entity.OutboxEventBookingCancelled, "booking", booking.ID.String(), entity.BookingEventPayload{
+		BookingID:       booking.ID,
+		BookingCode:     booking.BookingCode,
+		PatientID:       booking.PatientID,
+		ScheduleID:      booking.ScheduleID,
+		QueueNumber:     booking.QueueNumber,
+		SlotID:          booking.SlotID,
+		CancelReason:    entity.BookingCancelReasonPayment,
+		ReleaseSlot:     true,
+		PromoteWaitlist: true,
+	})
+}
+
+// handleBookingCancelled refunds the paid payment of a booking cancelled within the
+// cancellation policy: by the patient before the cancellation deadline, or by the clinic.
+// Bookings cancelled for not being paid have nothing to refund. Events may be delivered more
+// than once, a payment is refunded once.
+func (u *paymentUsecase) handleBookingCancelled(ctx context.Context, event *entity.DomainEvent) error {
+	var payload entity.BookingEventPayload
+	if err := event.DecodePayload(&payload); err != nil {
+		return err
+	}
+	switch payload.CancelReason {
+	case entity.BookingCancelReasonPatient, entity.BookingCancelReasonAdmin, entity.BookingCancelReasonReassign, entity.BookingCancelReasonSchedule:
+	default:
+		return nil
+	}
+
+	tx := u.db.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	p, err := u.paymentRepo.FindLatestByBookingID(tx, payload.BookingID)
+	if err != nil {
+		return err
+	}
+	if p == nil || !p.IsPaid() || p.RefundStatus != entity.PaymentRefundNone {
+		return nil
+	}
+
+	if err := u.requestRefund(ctx, tx, p, fmt.Sprintf(refundReasonCancelled, payload.CancelReason), event.ActorID); err != nil {
+		return err
+	}
+
+	return tx.Commit().Error
+}
+
+// requestRefund records the refund of the whole paid payment as pending, with the
+// payment.refund event that sends it to the gateway. Payments refunded or being refunded
+// already are left alone.
+func (u *paymentUsecase) requestRefund(ctx context.Context, tx *gorm.DB, p *entity.Payment, reason string, actorID *uuid.UUID) error {
+	affected, err := u.paymentRepo.RequestRefund(tx, p.ID, p.Amount, reason, time.Now())
+	if err != nil {
+		u.log.Warnf("Failed to request refund of payment %s: %+v", p.ID, err)
+		return err
+	}
+	if affected == 0 {
+		return nil
+	}
+
+	if err := u.outboxService.Enqueue(tx, entity.OutboxEventPaymentRefund, "payment", p.ID.String(), entity.PaymentRefundPayload{
+		PaymentID: p.ID,
+	}); err != nil {
+		u.log.Warnf("Failed to enqueue payment refund: %+v", err)
+		return err
+	}
+
+	if err := u.auditService.LogUpdate(ctx, tx, actorID, entity.AuditActionPaymentRefundRequest, "payment", p.ID.String(),
+		entity.JSON{"refund_status": p.RefundStatus},
+		entity.JSON{"refund_status": entity.PaymentRefundPending, "amount": p.Amount, "currency": p.Currency, "reason": reason, "booking_id": p.BookingID},
+	); err != nil {
+		u.log.Warnf("Failed to create audit log: %+v", err)
+	}
+
+	u.log.Infof("Payment refund requested: id=%s, booking=%s, reason=%s", p.ID, p.BookingID, reason)
+	return nil
+}
+
+// handlePaymentRefund sends a requested refund to the gateway. Called by the outbox worker
+// for payment.refund events: an unavailable gateway is retried, a rejected refund is recorded
+// as failed. Refunds the gateway completes later are completed by their callback.
+func (u *paymentUsecase) handlePaymentRefund(ctx context.Context, event *entity.OutboxEvent) error {
+	var payload entity.PaymentRefundPayload
+	if err := event.DecodePayload(&payload); err != nil {
+		return err
+	}
+
+	p, err := u.paymentRepo.FindByID(u.db.WithContext(ctx), payload.PaymentID)
+	if err != nil {
+		return err
+	}
+	if p == nil || p.RefundStatus != entity.PaymentRefundPending {
+		// Completed by a callback, or a repeat
+		return nil
+	}
+
+	result, err := u.paymentService.Refund(ctx, p)
+	if err != nil {
+		if errors.Is(err, payment.ErrUnavailable) {
+			return err
+		}
+		u.log.Errorf("Refund of payment %s rejected by %s: %+v", p.ID, p.Gateway, err)
+		return u.completeRefund(ctx, p.ID, entity.PaymentRefundFailed, "", err.Error())
+	}
+
+	if result.Status == payment.StatusRefunded {
+		return u.completeRefund(ctx, p.ID, entity.PaymentRefundSucceeded, result.Reference, "")
+	}
+	return u.paymentRepo.SetRefundReference(u.db.WithContext(ctx), p.ID, result.Reference)
+}
+
+// completeRefund records the outcome of a pending refund. A refunded payment also marks its
+// invoice refunded. Repeated outcomes are accepted without effect.
+func (u *paymentUsecase) completeRefund(ctx context.Context, paymentID uuid.UUID, status entity.PaymentRefundStatus, reference string, failureReason string) error {
+	tx := u.db.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	p, err := u.paymentRepo.FindByID(tx, paymentID)
+	if err != nil {
+		u.log.Warnf("Failed to find payment %s: %+v", paymentID, err)
+		return err
+	}
+	if p == nil {
+		return ErrPaymentNotFound
+	}
+	if p.RefundReference != "" {
+		// Callbacks report the ID of the paid transaction on some gateways
+		reference = ""
+	}
+
+	affected, err := u.paymentRepo.CompleteRefund(tx, p.ID, status, reference, failureReason, time.Now())
+	if err != nil {
+		u.log.Warnf("Failed to complete refund of payment %s: %+v", p.ID, err)
+		return err
+	}
+	if affected == 0 {
+		// No refund pending
+		return nil
+	}
+
+	action := entity.AuditActionPaymentRefundFail
+	if status == entity.PaymentRefundSucceeded {
+		action = entity.AuditActionPaymentRefund
+		if _, err := u.invoiceRepo.MarkRefunded(tx, p.ID); err != nil {
+			u.log.Warnf("Failed to mark invoice of payment %s refunded: %+v", p.ID, err)
+			return err
+		}
+	}
+
+	if err := u.auditService.LogUpdate(ctx, tx, nil, action, "payment", p.ID.String(),
+		entity.JSON{"refund_status": p.RefundStatus},
+		entity.JSON{"refund_status": status, "amount": p.RefundAmount, "currency": p.Currency, "failure_reason": failureReason, "booking_id": p.BookingID},
+	); err != nil {
+		u.log.Warnf("Failed to create audit log: %+v", err)
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		u.log.Warnf("Failed commit transaction: %+v", err)
+		return err
+	}
+
+	u.log.Infof("Payment refund %s: id=%s, booking=%s", status, p.ID, p.BookingID)
+	return nil
+}
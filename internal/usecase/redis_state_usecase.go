@@ -0,0 +1,190 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"go-template-clean-architecture/internal/delivery/dto"
+	"go-template-clean-architecture/internal/delivery/http/middleware"
+	"go-template-clean-architecture/internal/domain/entity"
+	"go-template-clean-architecture/internal/domain/repository"
+	"go-template-clean-architecture/internal/service"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrRedisNothingToCorrect = errors.New("set quota and/or queue_number to correct")
+	ErrRedisSchedulePast     = errors.New("schedule date has passed, its Redis counters are no longer used")
+	ErrRedisQuotaOverBooked  = errors.New("quota cannot exceed total quota minus active bookings")
+	ErrRedisQueueBelowBooked = errors.New("queue_number cannot be below the highest active queue number")
+)
+
+// RedisStateUsecase lets admins inspect and correct the Redis booking counters of a schedule
+// when they drift from the DB (e.g. after a Redis restore or a failed sync).
+type RedisStateUsecase interface {
+	GetScheduleState(ctx context.Context, scheduleID int) (*dto.RedisScheduleStateResponse, error)
+	UpdateScheduleState(ctx context.Context, scheduleID int, req *dto.UpdateRedisScheduleStateRequest) (*dto.RedisScheduleStateResponse, error)
+}
+
+type redisStateUsecase struct {
+	db               *gorm.DB
+	log              *logrus.Logger
+	scheduleRepo     repository.DoctorScheduleRepository
+	bookingRepo      repository.BookingRepository
+	auditService     service.AuditService
+	redisSyncService *service.RedisSyncService
+}
+
+func NewRedisStateUsecase(
+	db *gorm.DB,
+	log *logrus.Logger,
+	scheduleRepo repository.DoctorScheduleRepository,
+	bookingRepo repository.BookingRepository,
+	auditService service.AuditService,
+	redisSyncService *service.RedisSyncService,
+) RedisStateUsecase {
+	return &redisStateUsecase{
+		db:               db,
+		log:              log,
+		scheduleRepo:     scheduleRepo,
+		bookingRepo:      bookingRepo,
+		auditService:     auditService,
+		redisSyncService: redisSyncService,
+	}
+}
+
+func (u *redisStateUsecase) GetScheduleState(ctx context.Context, scheduleID int) (*dto.RedisScheduleStateResponse, error) {
+	schedule, err := u.scheduleRepo.FindByID(u.db.WithContext(ctx), scheduleID)
+	if err != nil {
+		u.log.Warnf("Failed find schedule by id: %+v", err)
+		return nil, err
+	}
+	if schedule == nil {
+		return nil, ErrScheduleNotFound
+	}
+
+	return u.loadState(ctx, schedule)
+}
+
+// UpdateScheduleState overwrites the quota and/or queue counter of a schedule.
+//
+// Values are checked against the DB so a correction cannot cause overbooking
+// (quota above TotalQuota - active bookings) or duplicate queue numbers (queue
+// counter below the highest active queue number). The reason is stored in the audit log.
+func (u *redisStateUsecase) UpdateScheduleState(ctx context.Context, scheduleID int, req *dto.UpdateRedisScheduleStateRequest) (*dto.RedisScheduleStateResponse, error) {
+	if req.Quota == nil && req.QueueNumber == nil {
+		return nil, ErrRedisNothingToCorrect
+	}
+
+	schedule, err := u.scheduleRepo.FindByID(u.db.WithContext(ctx), scheduleID)
+	if err != nil {
+		u.log.Warnf("Failed find schedule by id: %+v", err)
+		return nil, err
+	}
+	if schedule == nil {
+		return nil, ErrScheduleNotFound
+	}
+
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	if schedule.ScheduleDate.Before(today) {
+		return nil, ErrRedisSchedulePast
+	}
+
+	before, err := u.loadState(ctx, schedule)
+	if err != nil {
+		return nil, err
+	}
+	if req.Quota != nil && *req.Quota > before.Database.RemainingQuota {
+		return nil, ErrRedisQuotaOverBooked
+	}
+	if req.QueueNumber != nil && *req.QueueNumber < before.Database.MaxQueueNumber {
+		return nil, ErrRedisQueueBelowBooked
+	}
+
+	if err := u.redisSyncService.SetScheduleCounters(ctx, scheduleID, req.Quota, req.QueueNumber, schedule.ScheduleDate); err != nil {
+		return nil, err
+	}
+
+	after, err := u.loadState(ctx, schedule)
+	if err != nil {
+		return nil, err
+	}
+
+	u.log.Infof("Redis counters of schedule %d corrected manually: %s", scheduleID, req.Reason)
+
+	// Audit log - Redis counters are not stored in the DB, so the audit entry is the only record
+	userID, _ := middleware.GetUserIDFromContext(ctx)
+	if err := u.auditService.LogUpdate(ctx, u.db.WithContext(ctx), &userID, entity.AuditActionRedisStateUpdate, "doctor_schedule", strconv.Itoa(scheduleID),
+		entity.JSON{"quota": before.Redis.Quota, "queue_number": before.Redis.QueueNumber},
+		entity.JSON{"quota": after.Redis.Quota, "queue_number": after.Redis.QueueNumber, "reason": req.Reason},
+	); err != nil {
+		u.log.Warnf("Failed to create audit log: %+v", err)
+	}
+
+	return after, nil
+}
+
+// loadState reads the Redis counters of a schedule and the values they should have according to the DB
+func (u *redisStateUsecase) loadState(ctx context.Context, schedule *entity.DoctorSchedule) (*dto.RedisScheduleStateResponse, error) {
+	redisState, err := u.redisSyncService.InspectSchedule(ctx, schedule.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	db := u.db.WithContext(ctx)
+	bookings, err := u.bookingRepo.FindActiveByScheduleID(db, schedule.ID)
+	if err != nil {
+		u.log.Warnf("Failed find active bookings of schedule %d: %+v", schedule.ID, err)
+		return nil, err
+	}
+	lastCalled, err := u.bookingRepo.FindLastCalled(db, schedule.ID)
+	if err != nil {
+		u.log.Warnf("Failed find last called booking of schedule %d: %+v", schedule.ID, err)
+		return nil, err
+	}
+
+	expected := dto.RedisExpectedStateResponse{
+		TotalQuota: schedule.TotalQuota,
+		Booked:     len(bookings),
+	}
+	expected.RemainingQuota = schedule.TotalQuota - len(bookings)
+	if expected.RemainingQuota < 0 {
+		expected.RemainingQuota = 0
+	}
+	for _, booking := range bookings {
+		if booking.QueueNumber > expected.MaxQueueNumber {
+			expected.MaxQueueNumber = booking.QueueNumber
+		}
+	}
+	if lastCalled != nil {
+		expected.CurrentlyServing = &lastCalled.QueueNumber
+	}
+
+	return &dto.RedisScheduleStateResponse{
+		ScheduleID:   schedule.ID,
+		ScheduleDate: schedule.ScheduleDate.Format("2006-01-02"),
+		Redis: dto.RedisCountersResponse{
+			Quota:           redisState.Quota,
+			QuotaTTLSeconds: ttlSeconds(redisState.QuotaTTL),
+			QueueNumber:     redisState.QueueNumber,
+			QueueTTLSeconds: ttlSeconds(redisState.QueueTTL),
+			WaitlistLength:  redisState.WaitlistLength,
+			ReservedSlots:   redisState.ReservedSlots,
+		},
+		Database:   expected,
+		QuotaDrift: redisState.Quota == nil || *redisState.Quota != expected.RemainingQuota,
+		QueueDrift: redisState.QueueNumber == nil || *redisState.QueueNumber < expected.MaxQueueNumber,
+	}, nil
+}
+
+func ttlSeconds(ttl *time.Duration) *int64 {
+	if ttl == nil {
+		return nil
+	}
+	seconds := int64(ttl.Seconds())
+	return &seconds
+}
@@ -0,0 +1,325 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go-template-clean-architecture/config"
+	"go-template-clean-architecture/internal/converter"
+	"go-template-clean-architecture/internal/delivery/dto"
+	"go-template-clean-architecture/internal/domain/entity"
+	"go-template-clean-architecture/internal/domain/repository"
+	"go-template-clean-architecture/internal/service"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrInvalidReportDateRange = errors.New("invalid report date range")
+	ErrInvalidStatsPeriod     = errors.New("invalid stats period, use day, week or month")
+)
+
+const (
+	// maxUsageReportDays bounds the usage report range
+	maxUsageReportDays = 366
+
+	// maxUtilizationReportDays bounds the schedule utilization report range (one row per schedule)
+	maxUtilizationReportDays = 92
+
+	// maxDoctorStatsDays bounds the doctor statistics range
+	maxDoctorStatsDays = 366
+
+	// maxReminderReportDays bounds the reminder report range (counters are kept 35 days)
+	maxReminderReportDays = 35
+
+	// defaultDoctorStatsMonths is the number of months the doctor statistics cover by default
+	defaultDoctorStatsMonths = 6
+)
+
+type ReportUsecase interface {
+	GetWaitTimeReport(ctx context.Context, startDate, endDate string) (*dto.WaitTimeReportResponse, error)
+	GetUsageReport(ctx context.Context, startDate, endDate string) (*dto.UsageReportResponse, error)
+	GetScheduleUtilizationReport(ctx context.Context, filter *dto.ScheduleUtilizationFilter) (*dto.ScheduleUtilizationReportResponse, error)
+	GetDoctorStats(ctx context.Context, doctorID uuid.UUID, filter *dto.DoctorStatsFilter) (*dto.DoctorStatsResponse, error)
+	GetReminderReport(ctx context.Context, startDate, endDate string) (*dto.ReminderReportResponse, error)
+}
+
+type reportUsecase struct {
+	db               *gorm.DB
+	log              *logrus.Logger
+	cfg              *config.Config
+	waitFeedbackRepo repository.WaitFeedbackRepository
+	usageRepo        repository.UsageRecordRepository
+	usageMeter       *service.UsageMeterService
+	scheduleRepo     repository.DoctorScheduleRepository
+	doctorRepo       repository.DoctorProfileRepository
+	reminderService  *service.ReminderService
+}
+
+func NewReportUsecase(
+	db *gorm.DB,
+	log *logrus.Logger,
+	cfg *config.Config,
+	waitFeedbackRepo repository.WaitFeedbackRepository,
+	usageRepo repository.UsageRecordRepository,
+	usageMeter *service.UsageMeterService,
+	scheduleRepo repository.DoctorScheduleRepository,
+	doctorRepo repository.DoctorProfileRepository,
+	reminderService *service.ReminderService,
+) ReportUsecase {
+	return &reportUsecase{
+		db:               db,
+		log:              log,
+		cfg:              cfg,
+		waitFeedbackRepo: waitFeedbackRepo,
+		usageRepo:        usageRepo,
+		usageMeter:       usageMeter,
+		scheduleRepo:     scheduleRepo,
+		doctorRepo:       doctorRepo,
+		reminderService:  reminderService,
+	}
+}
+
+// GetWaitTimeReport returns average patient-reported wait time per doctor.
+// startDate and endDate (YYYY-MM-DD) are optional and filter by schedule date.
+func (u *reportUsecase) GetWaitTimeReport(ctx context.Context, startDate, endDate string) (*dto.WaitTimeReportResponse, error) {
+	if err := validateReportDateRange(startDate, endDate); err != nil {
+		return nil, err
+	}
+
+	stats, err := u.waitFeedbackRepo.AverageAllDoctors(u.db.WithContext(ctx), startDate, endDate)
+	if err != nil {
+		u.log.Warnf("Failed to get wait time report: %+v", err)
+		return nil, err
+	}
+
+	doctors := converter.DoctorWaitStatsToResponses(stats)
+
+	return &dto.WaitTimeReportResponse{
+		Doctors: doctors,
+		Total:   len(doctors),
+	}, nil
+}
+
+// GetUsageReport returns daily API calls and bookings created for billing.
+// Defaults to the current month. Days not flushed to the database yet
+// (including today) are read from the live Redis counters.
+func (u *reportUsecase) GetUsageReport(ctx context.Context, startDate, endDate string) (*dto.UsageReportResponse, error) {
+	now := time.Now().In(u.cfg.App.Location)
+	if startDate == "" {
+		startDate = time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location()).Format("2006-01-02")
+	}
+	if endDate == "" {
+		endDate = now.Format("2006-01-02")
+	}
+	if err := validateReportDateRange(startDate, endDate); err != nil {
+		return nil, err
+	}
+
+	start, _ := time.Parse("2006-01-02", startDate)
+	end, _ := time.Parse("2006-01-02", endDate)
+	if end.Sub(start) > maxUsageReportDays*24*time.Hour {
+		return nil, ErrInvalidReportDateRange
+	}
+
+	records, err := u.usageRepo.FindByTenantAndRange(u.db.WithContext(ctx), u.usageMeter.TenantID(), startDate, endDate)
+	if err != nil {
+		u.log.Warnf("Failed to get usage records: %+v", err)
+		return nil, err
+	}
+
+	flushed := make(map[string]entity.UsageRecord, len(records))
+	for _, record := range records {
+		flushed[record.UsageDate.Format("2006-01-02")] = record
+	}
+
+	report := &dto.UsageReportResponse{
+		TenantID:  u.usageMeter.TenantID(),
+		StartDate: startDate,
+		EndDate:   endDate,
+		Days:      []dto.UsageDayResponse{},
+	}
+
+	today := now.Format("2006-01-02")
+	for day := start; !day.After(end); day = day.AddDate(0, 0, 1) {
+		date := day.Format("2006-01-02")
+		entry := dto.UsageDayResponse{Date: date}
+
+		if record, ok := flushed[date]; ok {
+			entry.APICalls = record.APICalls
+			entry.BookingsCreated = record.BookingsCreated
+		} else if date <= today {
+			live, err := u.usageMeter.LiveUsage(ctx, date)
+			if err != nil {
+				u.log.Warnf("Failed to get live usage for %s: %+v", date, err)
+			} else if live != nil {
+				entry.APICalls = live.APICalls
+				entry.BookingsCreated = live.BookingsCreated
+			}
+		}
+
+		report.Days = append(report.Days, entry)
+		report.TotalAPICalls += entry.APICalls
+		report.TotalBookingsCreated += entry.BookingsCreated
+	}
+
+	return report, nil
+}
+
+// GetReminderReport returns the daily counts of appointment reminders.
+// startDate and endDate (YYYY-MM-DD) are optional, the default is the last 7 days.
+func (u *reportUsecase) GetReminderReport(ctx context.Context, startDate, endDate string) (*dto.ReminderReportResponse, error) {
+	now := time.Now().In(u.cfg.App.Location)
+	if endDate == "" {
+		endDate = now.Format("2006-01-02")
+	}
+	if startDate == "" {
+		startDate = now.AddDate(0, 0, -6).Format("2006-01-02")
+	}
+	if err := validateReportDateRange(startDate, endDate); err != nil {
+		return nil, err
+	}
+
+	start, _ := time.Parse("2006-01-02", startDate)
+	end, _ := time.Parse("2006-01-02", endDate)
+	if end.Sub(start) > maxReminderReportDays*24*time.Hour {
+		return nil, ErrInvalidReportDateRange
+	}
+
+	report := &dto.ReminderReportResponse{
+		StartDate: startDate,
+		EndDate:   endDate,
+		Days:      []dto.ReminderDayResponse{},
+	}
+	for day := start; !day.After(end); day = day.AddDate(0, 0, 1) {
+		stats, err := u.reminderService.Stats(ctx, day.Format("2006-01-02"))
+		if err != nil {
+			u.log.Warnf("Failed to get reminder stats: %+v", err)
+			return nil, err
+		}
+
+		report.Days = append(report.Days, dto.ReminderDayResponse{
+			Date:    stats.Date,
+			Sent:    stats.Sent,
+			Failed:  stats.Failed,
+			Skipped: stats.Skipped,
+		})
+		report.TotalSent += stats.Sent
+		report.TotalFailed += stats.Failed
+		report.TotalSkipped += stats.Skipped
+	}
+
+	return report, nil
+}
+
+// GetScheduleUtilizationReport returns booked vs total quota, cancellations, no-shows and
+// fill rate per schedule. Defaults to the current month up to today; the counts are
+// aggregated in the database.
+func (u *reportUsecase) GetScheduleUtilizationReport(ctx context.Context, filter *dto.ScheduleUtilizationFilter) (*dto.ScheduleUtilizationReportResponse, error) {
+	now := time.Now().In(u.cfg.App.Location)
+	startDate, endDate := filter.StartDate, filter.EndDate
+	if startDate == "" {
+		startDate = time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location()).Format("2006-01-02")
+	}
+	if endDate == "" {
+		endDate = now.Format("2006-01-02")
+	}
+	if err := validateReportDateRange(startDate, endDate); err != nil {
+		return nil, err
+	}
+
+	start, _ := time.Parse("2006-01-02", startDate)
+	end, _ := time.Parse("2006-01-02", endDate)
+	if end.Sub(start) > maxUtilizationReportDays*24*time.Hour {
+		return nil, ErrInvalidReportDateRange
+	}
+
+	scheduleFilter := &entity.ScheduleFilter{
+		StartAt:        startDate,
+		EndAt:          endDate,
+		DoctorName:     filter.DoctorName,
+		Specialization: entity.SpecializationSlug(filter.Specialization),
+	}
+
+	stats, err := u.scheduleRepo.UtilizationStats(u.db.WithContext(ctx), scheduleFilter, now.Format("2006-01-02 15:04:05"))
+	if err != nil {
+		u.log.Warnf("Failed to get schedule utilization report: %+v", err)
+		return nil, err
+	}
+
+	return converter.ScheduleUtilizationStatsToReport(stats, startDate, endDate), nil
+}
+
+// GetDoctorStats returns the bookings served, no-shows, average consultation time and utilization
+// of a doctor's schedules per period (day, week or month). Defaults to the last six months
+// by month; the counts are aggregated in the database.
+func (u *reportUsecase) GetDoctorStats(ctx context.Context, doctorID uuid.UUID, filter *dto.DoctorStatsFilter) (*dto.DoctorStatsResponse, error) {
+	period := filter.Period
+	switch period {
+	case "":
+		period = entity.StatsPeriodMonth
+	case entity.StatsPeriodDay, entity.StatsPeriodWeek, entity.StatsPeriodMonth:
+	default:
+		return nil, ErrInvalidStatsPeriod
+	}
+
+	now := time.Now().In(u.cfg.App.Location)
+	startDate, endDate := filter.StartDate, filter.EndDate
+	if startDate == "" {
+		startDate = time.Date(now.Year(), now.Month()-(defaultDoctorStatsMonths-1), 1, 0, 0, 0, 0, now.Location()).Format("2006-01-02")
+	}
+	if endDate == "" {
+		endDate = now.Format("2006-01-02")
+	}
+	if err := validateReportDateRange(startDate, endDate); err != nil {
+		return nil, err
+	}
+
+	start, _ := time.Parse("2006-01-02", startDate)
+	end, _ := time.Parse("2006-01-02", endDate)
+	if end.Sub(start) > maxDoctorStatsDays*24*time.Hour {
+		return nil, ErrInvalidReportDateRange
+	}
+
+	doctor, err := u.doctorRepo.FindByUserID(u.db.WithContext(ctx), doctorID)
+	if err != nil {
+		u.log.Warnf("Failed to find doctor %s: %+v", doctorID, err)
+		return nil, err
+	}
+	if doctor == nil {
+		return nil, ErrDoctorNotFound
+	}
+
+	stats, err := u.scheduleRepo.DoctorPeriodStats(u.db.WithContext(ctx), doctorID, startDate, endDate, period, now.Format("2006-01-02 15:04:05"), maxQueueCallInterval)
+	if err != nil {
+		u.log.Warnf("Failed to get stats of doctor %s: %+v", doctorID, err)
+		return nil, err
+	}
+
+	return converter.DoctorPeriodStatsToResponse(doctorID, stats, startDate, endDate, period), nil
+}
+
+// validateReportDateRange checks optional YYYY-MM-DD bounds and their order
+func validateReportDateRange(startDate, endDate string) error {
+	var start, end time.Time
+	var err error
+
+	if startDate != "" {
+		if start, err = time.Parse("2006-01-02", startDate); err != nil {
+			return ErrInvalidReportDateRange
+		}
+	}
+	if endDate != "" {
+		if end, err = time.Parse("2006-01-02", endDate); err != nil {
+			return ErrInvalidReportDateRange
+		}
+	}
+	if startDate != "" && endDate != "" && end.Before(start) {
+		return ErrInvalidReportDateRange
+	}
+
+	return nil
+}
@@ -0,0 +1,284 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"time"
+
+	"go-template-clean-architecture/internal/converter"
+	"go-template-clean-architecture/internal/delivery/dto"
+	"go-template-clean-architecture/internal/delivery/http/middleware"
+	"go-template-clean-architecture/internal/domain/entity"
+	"go-template-clean-architecture/internal/domain/repository"
+	"go-template-clean-architecture/internal/service"
+
+	"github.com/sirupsen/logrus"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrRoleNameExists        = errors.New("role name already exists")
+	ErrUnknownPermission     = errors.New("unknown permission")
+	ErrRoleProtected         = errors.New("permissions of the admin role cannot be changed")
+	ErrProfileRoleNotAllowed = errors.New("doctor and patient accounts are created by registration")
+)
+
+type RoleUsecase interface {
+	GetAllRoles(ctx context.Context) ([]dto.RoleResponse, error)
+	GetAllPermissions(ctx context.Context) ([]dto.PermissionResponse, error)
+	CreateRole(ctx context.Context, req *dto.CreateRoleRequest) (*dto.RoleResponse, error)
+	UpdateRolePermissions(ctx context.Context, roleID int, req *dto.UpdateRolePermissionsRequest) (*dto.RoleResponse, error)
+	CreateStaffUser(ctx context.Context, req *dto.CreateStaffUserRequest) (*dto.UserResponse, error)
+	GetAllUsers(ctx context.Context, filter *dto.UserFilter, page, limit int) ([]dto.AdminUserResponse, int64, error)
+}
+
+type roleUsecase struct {
+	db                *gorm.DB
+	log               *logrus.Logger
+	roleRepo          repository.RoleRepository
+	permissionRepo    repository.PermissionRepository
+	userRepo          repository.UserRepository
+	auditService      service.AuditService
+	permissionService service.PermissionService
+}
+
+func NewRoleUsecase(
+	db *gorm.DB,
+	log *logrus.Logger,
+	roleRepo repository.RoleRepository,
+	permissionRepo repository.PermissionRepository,
+	userRepo repository.UserRepository,
+	auditService service.AuditService,
+	permissionService service.PermissionService,
+) RoleUsecase {
+	return &roleUsecase{
+		db:                db,
+		log:               log,
+		roleRepo:          roleRepo,
+		permissionRepo:    permissionRepo,
+		userRepo:          userRepo,
+		auditService:      auditService,
+		permissionService: permissionService,
+	}
+}
+
+func (u *roleUsecase) GetAllRoles(ctx context.Context) ([]dto.RoleResponse, error) {
+	roles, err := u.roleRepo.FindAll(ctx, u.db)
+	if err != nil {
+		u.log.Warnf("Failed to find roles: %+v", err)
+		return nil, err
+	}
+	return converter.RolesToResponses(roles), nil
+}
+
+func (u *roleUsecase) GetAllPermissions(ctx context.Context) ([]dto.PermissionResponse, error) {
+	permissions, err := u.permissionRepo.FindAll(u.db.WithContext(ctx))
+	if err != nil {
+		u.log.Warnf("Failed to find permissions: %+v", err)
+		return nil, err
+	}
+	return converter.PermissionsToResponses(permissions), nil
+}
+
+// CreateRole adds a role, e.g. a staff role like nurse or receptionist
+func (u *roleUsecase) CreateRole(ctx context.Context, req *dto.CreateRoleRequest) (*dto.RoleResponse, error) {
+	userID, _ := middleware.GetUserIDFromContext(ctx)
+
+	tx := u.db.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	permissions, err := u.findPermissions(tx, req.Permissions)
+	if err != nil {
+		return nil, err
+	}
+
+	role := &entity.Role{
+		RoleName:    strings.ToLower(strings.TrimSpace(req.RoleName)),
+		Description: req.Description,
+		Permissions: permissions,
+	}
+	if err := u.roleRepo.Create(ctx, tx, role); err != nil {
+		u.log.Warnf("Failed to create role: %+v", err)
+		if isDuplicateKeyError(err, "role_name") {
+			return nil, ErrRoleNameExists
+		}
+		return nil, err
+	}
+
+	response := converter.RoleToResponse(role)
+	if err := u.auditService.LogCreate(ctx, tx, &userID, entity.AuditActionRoleCreate, "role", role.RoleName, response); err != nil {
+		u.log.Warnf("Failed to create audit log: %+v", err)
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		u.log.Warnf("Failed commit transaction: %+v", err)
+		return nil, err
+	}
+
+	return response, nil
+}
+
+// UpdateRolePermissions replaces the permissions of a role. They apply to the
+// next request of its users, tokens do not need to be reissued.
+func (u *roleUsecase) UpdateRolePermissions(ctx context.Context, roleID int, req *dto.UpdateRolePermissionsRequest) (*dto.RoleResponse, error) {
+	userID, _ := middleware.GetUserIDFromContext(ctx)
+
+	// The admin role keeps every permission, so no change can lock all admins out
+	if roleID == entity.RoleIDAdmin {
+		return nil, ErrRoleProtected
+	}
+
+	tx := u.db.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	role, err := u.roleRepo.FindByID(ctx, tx, roleID)
+	if err != nil {
+		u.log.Warnf("Failed to find role by ID: %+v", err)
+		return nil, err
+	}
+	if role == nil {
+		return nil, ErrRoleNotFound
+	}
+	oldValue := converter.RoleToResponse(role)
+
+	permissions, err := u.findPermissions(tx, req.Permissions)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := u.roleRepo.ReplacePermissions(ctx, tx, role, permissions); err != nil {
+		u.log.Warnf("Failed to update role permissions: %+v", err)
+		return nil, err
+	}
+	role.Permissions = permissions
+
+	response := converter.RoleToResponse(role)
+	if err := u.auditService.LogUpdate(ctx, tx, &userID, entity.AuditActionRolePermissions, "role", role.RoleName, oldValue, response); err != nil {
+		u.log.Warnf("Failed to create audit log: %+v", err)
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		u.log.Warnf("Failed commit transaction: %+v", err)
+		return nil, err
+	}
+
+	// Cached permissions would otherwise apply until they expire
+	if err := u.permissionService.InvalidateRole(ctx, roleID); err != nil {
+		u.log.Warnf("Failed to invalidate cached permissions of role %d: %+v", roleID, err)
+	}
+
+	return response, nil
+}
+
+// CreateStaffUser creates an account with a staff role
+func (u *roleUsecase) CreateStaffUser(ctx context.Context, req *dto.CreateStaffUserRequest) (*dto.UserResponse, error) {
+	adminID, _ := middleware.GetUserIDFromContext(ctx)
+
+	if entity.IsProfileRole(req.RoleID) {
+		return nil, ErrProfileRoleNotAllowed
+	}
+
+	role, err := u.roleRepo.FindByID(ctx, u.db, req.RoleID)
+	if err != nil {
+		u.log.Warnf("Failed to find role by ID: %+v", err)
+		return nil, err
+	}
+	if role == nil {
+		return nil, ErrRoleNotFound
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
+	if err != nil {
+		u.log.Warnf("Failed to hash password: %+v", err)
+		return nil, err
+	}
+
+	tx := u.db.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	user := &entity.User{
+		Email:    req.Email,
+		Password: string(hashedPassword),
+		FullName: req.FullName,
+		RoleID:   role.ID,
+		// The admin chose the password, the staff member replaces it on first login
+		MustChangePassword: true,
+	}
+	if err := u.userRepo.Create(tx, user); err != nil {
+		u.log.Warnf("Failed to create user: %+v", err)
+		if isDuplicateKeyError(err, "email") {
+			return nil, ErrEmailAlreadyExists
+		}
+		return nil, err
+	}
+
+	if err := u.auditService.LogCreate(ctx, tx, &adminID, entity.AuditActionStaffCreate, "user", user.ID.String(), entity.JSON{
+		"email": user.Email,
+		"role":  role.RoleName,
+	}); err != nil {
+		u.log.Warnf("Failed to create audit log: %+v", err)
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		u.log.Warnf("Failed commit transaction: %+v", err)
+		return nil, err
+	}
+
+	user.Role = *role
+	return converter.UserToResponse(user), nil
+}
+
+// GetAllUsers returns one page of users with their account status, newest first
+func (u *roleUsecase) GetAllUsers(ctx context.Context, filter *dto.UserFilter, page, limit int) ([]dto.AdminUserResponse, int64, error) {
+	users, total, err := u.userRepo.FindAll(u.db.WithContext(ctx), &entity.UserFilter{
+		RoleID: filter.RoleID,
+		Locked: filter.Locked,
+		Search: filter.Search,
+	}, page, limit)
+	if err != nil {
+		u.log.Warnf("Failed to find users: %+v", err)
+		return nil, 0, err
+	}
+
+	now := time.Now()
+	response := make([]dto.AdminUserResponse, 0, len(users))
+	for i := range users {
+		response = append(response, converter.UserToAdminResponse(&users[i], now))
+	}
+	return response, total, nil
+}
+
+// findPermissions loads the permissions of the codes, failing on unknown codes
+func (u *roleUsecase) findPermissions(db *gorm.DB, codes []string) ([]entity.Permission, error) {
+	permissions, err := findPermissionsByCodes(db, u.permissionRepo, codes)
+	if err != nil && err != ErrUnknownPermission {
+		u.log.Warnf("Failed to find permissions: %+v", err)
+	}
+	return permissions, err
+}
+
+// findPermissionsByCodes loads the permissions of the distinct codes, ErrUnknownPermission
+// when one does not exist
+func findPermissionsByCodes(db *gorm.DB, permissionRepo repository.PermissionRepository, codes []string) ([]entity.Permission, error) {
+	unique := make(map[string]bool, len(codes))
+	for _, code := range codes {
+		unique[strings.TrimSpace(code)] = true
+	}
+	distinct := make([]string, 0, len(unique))
+	for code := range unique {
+		distinct = append(distinct, code)
+	}
+	sort.Strings(distinct)
+
+	permissions, err := permissionRepo.FindByCodes(db, distinct)
+	if err != nil {
+		return nil, err
+	}
+	if len(permissions) != len(distinct) {
+		return nil, ErrUnknownPermission
+	}
+	return permissions, nil
+}
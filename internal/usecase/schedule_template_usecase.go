@@ -0,0 +1,566 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"go-template-clean-architecture/config"
+	"go-template-clean-architecture/internal/converter"
+	"go-template-clean-architecture/internal/delivery/dto"
+	"go-template-clean-architecture/internal/delivery/http/middleware"
+	"go-template-clean-architecture/internal/domain/entity"
+	"go-template-clean-architecture/internal/domain/repository"
+	"go-template-clean-architecture/internal/service"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrScheduleTemplateNotFound      = errors.New("schedule template not found")
+	ErrScheduleGenerationRunNotFound = errors.New("schedule generation run not found")
+	ErrInvalidTemplateTimeRange      = errors.New("template end time must be after its start time")
+	ErrTemplateQuotaRequired         = errors.New("total_quota is required for queue templates")
+	ErrTemplateSlotMinutesRequired   = errors.New("slot_minutes is required for slot templates")
+	ErrScheduleGenerationFailed      = errors.New("schedule generation failed")
+)
+
+// ScheduleTemplateUsecase manages weekly schedule templates and expands them into schedules.
+// The nightly expansion is triggered by ScheduleGenerationService.
+type ScheduleTemplateUsecase interface {
+	CreateTemplate(ctx context.Context, req *dto.CreateScheduleTemplateRequest) (*dto.ScheduleTemplateResponse, error)
+	GetTemplates(ctx context.Context, doctorID *uuid.UUID, page, limit int) ([]dto.ScheduleTemplateResponse, int64, error)
+	UpdateTemplate(ctx context.Context, id int, req *dto.UpdateScheduleTemplateRequest) (*dto.ScheduleTemplateResponse, error)
+	DeleteTemplate(ctx context.Context, id int) error
+	GenerateSchedules(ctx context.Context) (*dto.ScheduleGenerationRunResponse, error)
+	GetGenerationRuns(ctx context.Context, page, limit int) ([]dto.ScheduleGenerationRunResponse, int64, error)
+	GetGenerationRun(ctx context.Context, id uuid.UUID) (*dto.ScheduleGenerationRunResponse, error)
+}
+
+type scheduleTemplateUsecase struct {
+	db               *gorm.DB
+	log              *logrus.Logger
+	cfg              *config.Config
+	templateRepo     repository.ScheduleTemplateRepository
+	runRepo          repository.ScheduleGenerationRunRepository
+	scheduleRepo     repository.DoctorScheduleRepository
+	slotRepo         repository.ScheduleSlotRepository
+	holidayRepo      repository.HolidayRepository
+	auditService     service.AuditService
+	redisSyncService *service.RedisSyncService
+	versionRepo      repository.ScheduleVersionRepository
+	listingCache     service.ListingCacheService
+	leaveRepo        repository.DoctorLeaveRepository
+}
+
+func NewScheduleTemplateUsecase(
+	db *gorm.DB,
+	log *logrus.Logger,
+	cfg *config.Config,
+	templateRepo repository.ScheduleTemplateRepository,
+	runRepo repository.ScheduleGenerationRunRepository,
+	scheduleRepo repository.DoctorScheduleRepository,
+	slotRepo repository.ScheduleSlotRepository,
+	holidayRepo repository.HolidayRepository,
+	auditService service.AuditService,
+	redisSyncService *service.RedisSyncService,
+	generationService *service.ScheduleGenerationService,
+	versionRepo repository.ScheduleVersionRepository,
+	listingCache service.ListingCacheService,
+	leaveRepo repository.DoctorLeaveRepository,
+) ScheduleTemplateUsecase {
+	u := &scheduleTemplateUsecase{
+		db:               db,
+		log:              log,
+		cfg:              cfg,
+		templateRepo:     templateRepo,
+		runRepo:          runRepo,
+		scheduleRepo:     scheduleRepo,
+		slotRepo:         slotRepo,
+		holidayRepo:      holidayRepo,
+		auditService:     auditService,
+		redisSyncService: redisSyncService,
+		versionRepo:      versionRepo,
+		listingCache:     listingCache,
+		leaveRepo:        leaveRepo,
+	}
+
+	generationService.RegisterGenerator(func(ctx context.Context, trigger string) error {
+		_, err := u.generate(ctx, trigger)
+		return err
+	})
+
+	return u
+}
+
+func (u *scheduleTemplateUsecase) CreateTemplate(ctx context.Context, req *dto.CreateScheduleTemplateRequest) (*dto.ScheduleTemplateResponse, error) {
+	bookingMode := req.BookingMode
+	if bookingMode == "" {
+		bookingMode = entity.BookingModeQueue
+	}
+
+	template := &entity.ScheduleTemplate{
+		DoctorID:     req.DoctorID,
+		Weekday:      *req.Weekday,
+		StartTime:    req.StartTime,
+		EndTime:      req.EndTime,
+		TotalQuota:   req.TotalQuota,
+		BookingMode:  bookingMode,
+		SlotMinutes:  req.SlotMinutes,
+		MinLeadHours: req.MinLeadHours,
+	}
+	if err := validateScheduleTemplate(template); err != nil {
+		return nil, err
+	}
+
+	tx := u.db.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := u.templateRepo.Create(tx, template); err != nil {
+		u.log.Warnf("Failed to create schedule template: %+v", err)
+		if isForeignKeyError(err, "doctor") {
+			return nil, ErrDoctorNotFound
+		}
+		return nil, err
+	}
+
+	// Audit log - create template
+	userID, _ := middleware.GetUserIDFromContext(ctx)
+	if err := u.auditService.LogCreate(ctx, tx, &userID, entity.AuditActionScheduleTemplateCreate, "schedule_template", strconv.Itoa(template.ID), converter.ScheduleTemplateToResponse(template)); err != nil {
+		u.log.Warnf("Failed to create audit log: %+v", err)
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		u.log.Warnf("Failed commit transaction: %+v", err)
+		return nil, err
+	}
+
+	return converter.ScheduleTemplateToResponse(template), nil
+}
+
+// GetTemplates returns one page of templates, optionally of one doctor
+func (u *scheduleTemplateUsecase) GetTemplates(ctx context.Context, doctorID *uuid.UUID, page, limit int) ([]dto.ScheduleTemplateResponse, int64, error) {
+	templates, total, err := u.templateRepo.FindAll(u.db.WithContext(ctx), doctorID, page, limit)
+	if err != nil {
+		u.log.Warnf("Failed to find schedule templates: %+v", err)
+		return nil, 0, err
+	}
+
+	return converter.ScheduleTemplatesToResponses(templates), total, nil
+}
+
+// UpdateTemplate changes a template. Only schedules generated afterwards follow the change.
+func (u *scheduleTemplateUsecase) UpdateTemplate(ctx context.Context, id int, req *dto.UpdateScheduleTemplateRequest) (*dto.ScheduleTemplateResponse, error) {
+	tx := u.db.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	template, err := u.templateRepo.FindByID(tx, id)
+	if err != nil {
+		u.log.Warnf("Failed to find schedule template %d: %+v", id, err)
+		return nil, err
+	}
+	if template == nil {
+		return nil, ErrScheduleTemplateNotFound
+	}
+
+	oldValue := converter.ScheduleTemplateToResponse(template)
+
+	if req.Weekday != nil {
+		template.Weekday = *req.Weekday
+	}
+	if req.StartTime != "" {
+		template.StartTime = req.StartTime
+	}
+	if req.EndTime != "" {
+		template.EndTime = req.EndTime
+	}
+	if req.TotalQuota != nil {
+		template.TotalQuota = *req.TotalQuota
+	}
+	if req.SlotMinutes != nil {
+		template.SlotMinutes = *req.SlotMinutes
+	}
+	if req.MinLeadHours != nil {
+		template.MinLeadHours = req.MinLeadHours
+	} else if req.ClearMinLeadHours {
+		template.MinLeadHours = nil
+	}
+	if req.IsActive != nil {
+		template.IsActive = req.IsActive
+	}
+
+	// The template now generates different schedules - expand the covered dates again
+	// (dates it already generated are still skipped as existing)
+	if req.Weekday != nil || req.StartTime != "" || req.EndTime != "" || (req.IsActive != nil && *req.IsActive) {
+		template.GeneratedUntil = nil
+	}
+
+	if err := validateScheduleTemplate(template); err != nil {
+		return nil, err
+	}
+
+	if err := u.templateRepo.Update(tx, template); err != nil {
+		u.log.Warnf("Failed to update schedule template %d: %+v", id, err)
+		return nil, err
+	}
+
+	// Audit log - update template
+	userID, _ := middleware.GetUserIDFromContext(ctx)
+	if err := u.auditService.LogUpdate(ctx, tx, &userID, entity.AuditActionScheduleTemplateUpdate, "schedule_template", strconv.Itoa(template.ID), oldValue, converter.ScheduleTemplateToResponse(template)); err != nil {
+		u.log.Warnf("Failed to create audit log: %+v", err)
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		u.log.Warnf("Failed commit transaction: %+v", err)
+		return nil, err
+	}
+
+	return converter.ScheduleTemplateToResponse(template), nil
+}
+
+// DeleteTemplate removes a template. Schedules generated from it are kept.
+func (u *scheduleTemplateUsecase) DeleteTemplate(ctx context.Context, id int) error {
+	tx := u.db.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	template, err := u.templateRepo.FindByID(tx, id)
+	if err != nil {
+		u.log.Warnf("Failed to find schedule template %d: %+v", id, err)
+		return err
+	}
+	if template == nil {
+		return ErrScheduleTemplateNotFound
+	}
+
+	if _, err := u.templateRepo.Delete(tx, id); err != nil {
+		u.log.Warnf("Failed to delete schedule template %d: %+v", id, err)
+		return err
+	}
+
+	// Audit log - delete template
+	userID, _ := middleware.GetUserIDFromContext(ctx)
+	if err := u.auditService.LogDelete(ctx, tx, &userID, entity.AuditActionScheduleTemplateDelete, "schedule_template", strconv.Itoa(id), converter.ScheduleTemplateToResponse(template)); err != nil {
+		u.log.Warnf("Failed to create audit log: %+v", err)
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		u.log.Warnf("Failed commit transaction: %+v", err)
+		return err
+	}
+
+	return nil
+}
+
+// GenerateSchedules runs the template expansion now (admin) and returns its report.
+// A run that could not generate returns its report with ErrScheduleGenerationFailed.
+func (u *scheduleTemplateUsecase) GenerateSchedules(ctx context.Context) (*dto.ScheduleGenerationRunResponse, error) {
+	run, err := u.generate(ctx, entity.ScheduleGenerationTriggerManual)
+	if run == nil {
+		return nil, err
+	}
+	if err != nil {
+		return converter.ScheduleGenerationRunToResponse(run), ErrScheduleGenerationFailed
+	}
+
+	return converter.ScheduleGenerationRunToResponse(run), nil
+}
+
+// GetGenerationRuns returns one page of generation run reports, newest first
+func (u *scheduleTemplateUsecase) GetGenerationRuns(ctx context.Context, page, limit int) ([]dto.ScheduleGenerationRunResponse, int64, error) {
+	runs, total, err := u.runRepo.FindAll(u.db.WithContext(ctx), page, limit)
+	if err != nil {
+		u.log.Warnf("Failed to find schedule generation runs: %+v", err)
+		return nil, 0, err
+	}
+
+	return converter.ScheduleGenerationRunsToResponses(runs), total, nil
+}
+
+func (u *scheduleTemplateUsecase) GetGenerationRun(ctx context.Context, id uuid.UUID) (*dto.ScheduleGenerationRunResponse, error) {
+	run, err := u.runRepo.FindByID(u.db.WithContext(ctx), id)
+	if err != nil {
+		u.log.Warnf("Failed to find schedule generation run %s: %+v", id, err)
+		return nil, err
+	}
+	if run == nil {
+		return nil, ErrScheduleGenerationRunNotFound
+	}
+
+	return converter.ScheduleGenerationRunToResponse(run), nil
+}
+
+// generate expands the active templates into schedules from tomorrow through the configured
+// number of days, and records the run report.
+//
+// Idempotent: template dates up to a template's generated_until, or with a schedule from the
+// template, are counted as existing (and the unique template/date index rejects concurrent
+// duplicates), so schedules an admin deleted are not recreated. Dates on a holiday, in a leave of
+// the doctor or overlapping another schedule of the doctor are skipped. Each schedule is created in its own transaction,
+// so one failing date does not abort the run.
+func (u *scheduleTemplateUsecase) generate(ctx context.Context, trigger string) (*entity.ScheduleGenerationRun, error) {
+	now := time.Now().In(u.cfg.App.Location)
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	from := today.AddDate(0, 0, 1)
+	to := today.AddDate(0, 0, u.cfg.Generation.Days)
+
+	run := &entity.ScheduleGenerationRun{
+		Trigger:   trigger,
+		FromDate:  from,
+		ToDate:    to,
+		Status:    entity.ScheduleGenerationStatusRunning,
+		StartedAt: now,
+	}
+	if err := u.runRepo.Create(u.db.WithContext(ctx), run); err != nil {
+		u.log.Warnf("Failed to create schedule generation run: %+v", err)
+		return nil, err
+	}
+
+	if err := u.expandTemplates(ctx, run); err != nil {
+		u.log.Warnf("Schedule generation run %s failed: %+v", run.ID, err)
+		run.Status = entity.ScheduleGenerationStatusFailed
+		run.Error = err.Error()
+	} else {
+		run.Status = entity.ScheduleGenerationStatusCompleted
+	}
+
+	finishedAt := time.Now()
+	run.FinishedAt = &finishedAt
+	if err := u.runRepo.Save(u.db.WithContext(ctx), run); err != nil {
+		u.log.Warnf("Failed to save schedule generation run %s: %+v", run.ID, err)
+	}
+
+	u.log.Infof("Schedule generation run %s (%s) %s: %d created, %d existing, %d skipped, %d failed",
+		run.ID, trigger, run.Status, run.CreatedCount, run.ExistingCount, run.SkippedCount, run.FailedCount)
+
+	if run.CreatedCount > 0 {
+		u.listingCache.Invalidate(ctx, service.ListingSchedules)
+	}
+
+	// Audit log - system action for nightly runs
+	var actorID *uuid.UUID
+	if userID, ok := middleware.GetUserIDFromContext(ctx); ok {
+		actorID = &userID
+	}
+	if err := u.auditService.LogCreate(ctx, u.db.WithContext(ctx), actorID, entity.AuditActionScheduleGenerate, "schedule_generation_run", run.ID.String(), converter.ScheduleGenerationRunToSummaryResponse(run)); err != nil {
+		u.log.Warnf("Failed to create audit log: %+v", err)
+	}
+
+	if run.Status == entity.ScheduleGenerationStatusFailed {
+		return run, errors.New(run.Error)
+	}
+	return run, nil
+}
+
+// expandTemplates generates the run's date range and records the outcome on the run
+func (u *scheduleTemplateUsecase) expandTemplates(ctx context.Context, run *entity.ScheduleGenerationRun) error {
+	db := u.db.WithContext(ctx)
+
+	templates, err := u.templateRepo.FindActive(db)
+	if err != nil {
+		return fmt.Errorf("find active templates: %w", err)
+	}
+
+	holidays, err := u.holidayRepo.FindAll(db, run.FromDate.Format("2006-01-02"), run.ToDate.Format("2006-01-02"))
+	if err != nil {
+		return fmt.Errorf("find holidays: %w", err)
+	}
+	holidayDates := make(map[string]bool, len(holidays))
+	for _, holiday := range holidays {
+		holidayDates[holiday.Date.Format("2006-01-02")] = true
+	}
+
+	leaves, err := u.leaveRepo.FindByDateRange(db, run.FromDate, run.ToDate)
+	if err != nil {
+		return fmt.Errorf("find doctor leaves: %w", err)
+	}
+	doctorLeaves := make(map[uuid.UUID][]entity.DoctorLeave)
+	for _, leave := range leaves {
+		doctorLeaves[leave.DoctorID] = append(doctorLeaves[leave.DoctorID], leave)
+	}
+
+	existing, err := u.scheduleRepo.FindByDateRange(db, run.FromDate, run.ToDate)
+	if err != nil {
+		return fmt.Errorf("find existing schedules: %w", err)
+	}
+	occupied := make(map[uuid.UUID][]entity.DoctorSchedule)
+	generated := make(map[string]bool)
+	for _, schedule := range existing {
+		occupied[schedule.DoctorID] = append(occupied[schedule.DoctorID], schedule)
+		if schedule.TemplateID != nil {
+			generated[templateDateKey(*schedule.TemplateID, schedule.ScheduleDate)] = true
+		}
+	}
+
+	details := entity.ScheduleGenerationDetails{
+		Created: []entity.ScheduleGenerationItem{},
+		Skipped: []entity.ScheduleGenerationItem{},
+		Failed:  []entity.ScheduleGenerationItem{},
+	}
+
+	// Templates with a failed date are not marked generated, the next run retries them
+	retry := make(map[int]bool)
+
+	for day := run.FromDate; !day.After(run.ToDate); day = day.AddDate(0, 0, 1) {
+		date := day.Format("2006-01-02")
+
+		for i := range templates {
+			template := &templates[i]
+			if template.Weekday != int(day.Weekday()) {
+				continue
+			}
+
+			item := entity.ScheduleGenerationItem{
+				TemplateID:   template.ID,
+				DoctorID:     template.DoctorID,
+				ScheduleDate: date,
+			}
+			schedule := template.ScheduleFor(day)
+
+			switch {
+			case template.IsGenerated(day) || generated[templateDateKey(template.ID, day)]:
+				run.ExistingCount++
+				continue
+			case holidayDates[date]:
+				item.Reason = entity.ScheduleGenerationSkipHoliday
+				details.Skipped = append(details.Skipped, item)
+				continue
+			case onLeave(doctorLeaves[template.DoctorID], day):
+				item.Reason = entity.ScheduleGenerationSkipLeave
+				details.Skipped = append(details.Skipped, item)
+				continue
+			case overlapsAny(&schedule, occupied[template.DoctorID]):
+				item.Reason = entity.ScheduleGenerationSkipConflict
+				details.Skipped = append(details.Skipped, item)
+				continue
+			}
+
+			created, err := u.createGeneratedSchedule(ctx, &schedule)
+			if err != nil {
+				u.log.Warnf("Failed to generate schedule from template %d on %s: %+v", template.ID, date, err)
+				item.Reason = err.Error()
+				details.Failed = append(details.Failed, item)
+				retry[template.ID] = true
+				continue
+			}
+			if !created {
+				// Generated concurrently (e.g. a manual run during the nightly one)
+				run.ExistingCount++
+				continue
+			}
+
+			item.ScheduleID = schedule.ID
+			details.Created = append(details.Created, item)
+			occupied[template.DoctorID] = append(occupied[template.DoctorID], schedule)
+			generated[templateDateKey(template.ID, day)] = true
+
+			// Bookable right away, like a schedule created by an admin
+			if err := u.redisSyncService.SyncScheduleQuota(ctx, schedule.ID, schedule.TotalQuota, schedule.ScheduleDate); err != nil {
+				u.log.Warnf("Redis sync failed for generated schedule %d (non-fatal): %+v", schedule.ID, err)
+			}
+		}
+	}
+
+	var done []int
+	for i := range templates {
+		if !retry[templates[i].ID] {
+			done = append(done, templates[i].ID)
+		}
+	}
+	if err := u.templateRepo.MarkGeneratedUntil(db, done, run.ToDate); err != nil {
+		// Next run counts the created schedules as existing instead
+		u.log.Warnf("Failed to mark templates generated until %s: %+v", run.ToDate.Format("2006-01-02"), err)
+	}
+
+	run.Details = details
+	run.CreatedCount = len(details.Created)
+	run.SkippedCount = len(details.Skipped)
+	run.FailedCount = len(details.Failed)
+	return nil
+}
+
+// createGeneratedSchedule inserts a generated schedule with its slots (slot mode).
+// Returns false when the template date was generated in the meantime.
+func (u *scheduleTemplateUsecase) createGeneratedSchedule(ctx context.Context, schedule *entity.DoctorSchedule) (bool, error) {
+	var slots []entity.ScheduleSlot
+	if schedule.IsSlotMode() {
+		start, err := schedule.StartDateTime(time.UTC)
+		if err != nil {
+			return false, err
+		}
+		end, err := schedule.EndDateTime(time.UTC)
+		if err != nil {
+			return false, err
+		}
+		slots = generateScheduleSlots(start, end, schedule.SlotMinutes, nil)
+		if len(slots) == 0 {
+			return false, ErrScheduleShorterThanSlot
+		}
+		schedule.TotalQuota = len(slots)
+	}
+
+	tx := u.db.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := u.scheduleRepo.Create(tx, schedule); err != nil {
+		if isDuplicateKeyError(err, "template_date") {
+			return false, nil
+		}
+		return false, err
+	}
+
+	for i := range slots {
+		slots[i].ScheduleID = schedule.ID
+	}
+	if err := u.slotRepo.CreateBatch(tx, slots); err != nil {
+		return false, err
+	}
+
+	// History - system change for nightly runs
+	var actorID *uuid.UUID
+	if userID, ok := middleware.GetUserIDFromContext(ctx); ok {
+		actorID = &userID
+	}
+	if err := u.versionRepo.Create(tx, entity.NewScheduleVersion(schedule.ID, actorID, entity.AuditActionScheduleGenerate, nil, schedule)); err != nil {
+		return false, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// validateScheduleTemplate checks the template times and its booking mode settings
+func validateScheduleTemplate(template *entity.ScheduleTemplate) error {
+	start, end, err := template.TimeRange()
+	if err != nil {
+		return ErrInvalidTimeFormat
+	}
+	if !end.After(start) {
+		return ErrInvalidTemplateTimeRange
+	}
+
+	if template.BookingMode == entity.BookingModeSlot {
+		if template.SlotMinutes == 0 {
+			return ErrTemplateSlotMinutesRequired
+		}
+		if len(generateScheduleSlots(start, end, template.SlotMinutes, nil)) == 0 {
+			return ErrScheduleShorterThanSlot
+		}
+		return nil
+	}
+
+	if template.TotalQuota < 1 {
+		return ErrTemplateQuotaRequired
+	}
+	return nil
+}
+
+// templateDateKey identifies the schedule a template generates on a date
+func templateDateKey(templateID int, date time.Time) string {
+	return strconv.Itoa(templateID) + "/" + date.Format("2006-01-02")
+}
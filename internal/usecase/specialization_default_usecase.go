@@ -0,0 +1,188 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+
+	"go-template-clean-architecture/internal/converter"
+	"go-template-clean-architecture/internal/delivery/dto"
+	"go-template-clean-architecture/internal/delivery/http/middleware"
+	"go-template-clean-architecture/internal/domain/entity"
+	"go-template-clean-architecture/internal/domain/repository"
+	"go-template-clean-architecture/internal/service"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrSpecializationDefaultNotFound = errors.New("specialization defaults not found")
+	ErrInvalidSpecialization         = errors.New("specialization is required")
+)
+
+type SpecializationDefaultUsecase interface {
+	GetAllDefaults(ctx context.Context) (*dto.SpecializationDefaultListResponse, error)
+	GetDefault(ctx context.Context, specialization string) (*dto.SpecializationDefaultResponse, error)
+	UpsertDefault(ctx context.Context, specialization string, req *dto.UpsertSpecializationDefaultRequest) (*dto.SpecializationDefaultResponse, error)
+	DeleteDefault(ctx context.Context, specialization string) error
+}
+
+type specializationDefaultUsecase struct {
+	db                 *gorm.DB
+	log                *logrus.Logger
+	defaultsRepo       repository.SpecializationDefaultRepository
+	auditService       service.AuditService
+	specializationRepo repository.SpecializationRepository
+}
+
+func NewSpecializationDefaultUsecase(
+	db *gorm.DB,
+	log *logrus.Logger,
+	defaultsRepo repository.SpecializationDefaultRepository,
+	auditService service.AuditService,
+	specializationRepo repository.SpecializationRepository,
+) SpecializationDefaultUsecase {
+	return &specializationDefaultUsecase{
+		db:                 db,
+		log:                log,
+		defaultsRepo:       defaultsRepo,
+		auditService:       auditService,
+		specializationRepo: specializationRepo,
+	}
+}
+
+func (u *specializationDefaultUsecase) GetAllDefaults(ctx context.Context) (*dto.SpecializationDefaultListResponse, error) {
+	defaults, err := u.defaultsRepo.FindAll(u.db.WithContext(ctx))
+	if err != nil {
+		u.log.Warnf("Failed to find specialization defaults: %+v", err)
+		return nil, err
+	}
+
+	return &dto.SpecializationDefaultListResponse{
+		Defaults: converter.SpecializationDefaultsToResponses(defaults),
+		Total:    len(defaults),
+	}, nil
+}
+
+func (u *specializationDefaultUsecase) GetDefault(ctx context.Context, specialization string) (*dto.SpecializationDefaultResponse, error) {
+	found, err := u.findSpecialization(u.db.WithContext(ctx), specialization)
+	if err != nil {
+		return nil, err
+	}
+
+	defaults, err := u.defaultsRepo.FindBySpecialization(u.db.WithContext(ctx), found.Name)
+	if err != nil {
+		u.log.Warnf("Failed to find specialization defaults: %+v", err)
+		return nil, err
+	}
+	if defaults == nil {
+		return nil, ErrSpecializationDefaultNotFound
+	}
+
+	return converter.SpecializationDefaultToResponse(defaults), nil
+}
+
+// UpsertDefault creates or replaces the defaults for a specialization (by slug).
+func (u *specializationDefaultUsecase) UpsertDefault(ctx context.Context, specialization string, req *dto.UpsertSpecializationDefaultRequest) (*dto.SpecializationDefaultResponse, error) {
+	if entity.SpecializationSlug(specialization) == "" {
+		return nil, ErrInvalidSpecialization
+	}
+
+	tx := u.db.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	found, err := u.findSpecialization(tx, specialization)
+	if err != nil {
+		return nil, err
+	}
+	// Defaults are keyed by the managed name
+	specialization = found.Name
+
+	existing, err := u.defaultsRepo.FindBySpecialization(tx, specialization)
+	if err != nil {
+		u.log.Warnf("Failed to find specialization defaults: %+v", err)
+		return nil, err
+	}
+
+	var oldValue *dto.SpecializationDefaultResponse
+	if existing != nil {
+		oldValue = converter.SpecializationDefaultToResponse(existing)
+	}
+
+	defaults := &entity.SpecializationDefault{
+		Specialization:      specialization,
+		DefaultQuota:        req.DefaultQuota,
+		ConsultationMinutes: req.ConsultationMinutes,
+		Fee:                 req.Fee,
+	}
+
+	if err := u.defaultsRepo.Upsert(tx, defaults); err != nil {
+		u.log.Warnf("Failed to upsert specialization defaults: %+v", err)
+		return nil, err
+	}
+
+	// Audit log - update specialization defaults
+	userID, _ := middleware.GetUserIDFromContext(ctx)
+	newValue := converter.SpecializationDefaultToResponse(defaults)
+	if err := u.auditService.LogUpdate(ctx, tx, &userID, entity.AuditActionSpecializationDefaultUpdate, "specialization_default", specialization, oldValue, newValue); err != nil {
+		u.log.Warnf("Failed to create audit log: %+v", err)
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		u.log.Warnf("Failed commit transaction: %+v", err)
+		return nil, err
+	}
+
+	return newValue, nil
+}
+
+func (u *specializationDefaultUsecase) DeleteDefault(ctx context.Context, specialization string) error {
+	tx := u.db.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	found, err := u.findSpecialization(tx, specialization)
+	if err != nil {
+		return err
+	}
+
+	existing, err := u.defaultsRepo.FindBySpecialization(tx, found.Name)
+	if err != nil {
+		u.log.Warnf("Failed to find specialization defaults: %+v", err)
+		return err
+	}
+	if existing == nil {
+		return ErrSpecializationDefaultNotFound
+	}
+
+	if _, err := u.defaultsRepo.Delete(tx, existing.Specialization); err != nil {
+		u.log.Warnf("Failed to delete specialization defaults: %+v", err)
+		return err
+	}
+
+	// Audit log - delete specialization defaults
+	userID, _ := middleware.GetUserIDFromContext(ctx)
+	if err := u.auditService.LogDelete(ctx, tx, &userID, entity.AuditActionSpecializationDefaultDelete, "specialization_default", existing.Specialization, converter.SpecializationDefaultToResponse(existing)); err != nil {
+		u.log.Warnf("Failed to create audit log: %+v", err)
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		u.log.Warnf("Failed commit transaction: %+v", err)
+		return err
+	}
+
+	return nil
+}
+
+// findSpecialization resolves the specialization of a path parameter by its slug
+func (u *specializationDefaultUsecase) findSpecialization(db *gorm.DB, specialization string) (*entity.Specialization, error) {
+	slug := entity.SpecializationSlug(specialization)
+	found, err := u.specializationRepo.FindBySlug(db, slug)
+	if err != nil {
+		u.log.Warnf("Failed to find specialization %s: %+v", slug, err)
+		return nil, err
+	}
+	if found == nil {
+		return nil, ErrSpecializationNotFound
+	}
+	return found, nil
+}
@@ -0,0 +1,231 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+
+	"go-template-clean-architecture/internal/converter"
+	"go-template-clean-architecture/internal/delivery/dto"
+	"go-template-clean-architecture/internal/delivery/http/middleware"
+	"go-template-clean-architecture/internal/domain/entity"
+	"go-template-clean-architecture/internal/domain/repository"
+	"go-template-clean-architecture/internal/service"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrSpecializationNotFound    = errors.New("specialization not found")
+	ErrSpecializationNameExists  = errors.New("a specialization with this name already exists")
+	ErrSpecializationSlugExists  = errors.New("a specialization with this slug already exists")
+	ErrInvalidSpecializationSlug = errors.New("slug must contain letters or digits")
+	ErrSpecializationInUse       = errors.New("specialization is assigned to doctors")
+)
+
+// SpecializationUsecase manages the specialization taxonomy doctors are assigned to
+type SpecializationUsecase interface {
+	CreateSpecialization(ctx context.Context, req *dto.CreateSpecializationRequest) (*dto.SpecializationResponse, error)
+	GetSpecialization(ctx context.Context, id int) (*dto.SpecializationResponse, error)
+	GetAllSpecializations(ctx context.Context) (*dto.SpecializationListResponse, error)
+	UpdateSpecialization(ctx context.Context, id int, req *dto.UpdateSpecializationRequest) (*dto.SpecializationResponse, error)
+	DeleteSpecialization(ctx context.Context, id int) error
+}
+
+type specializationUsecase struct {
+	db                 *gorm.DB
+	log                *logrus.Logger
+	specializationRepo repository.SpecializationRepository
+	auditService       service.AuditService
+	listingCache       service.ListingCacheService
+}
+
+func NewSpecializationUsecase(
+	db *gorm.DB,
+	log *logrus.Logger,
+	specializationRepo repository.SpecializationRepository,
+	auditService service.AuditService,
+	listingCache service.ListingCacheService,
+) SpecializationUsecase {
+	return &specializationUsecase{
+		db:                 db,
+		log:                log,
+		specializationRepo: specializationRepo,
+		auditService:       auditService,
+		listingCache:       listingCache,
+	}
+}
+
+// CreateSpecialization adds a specialization, its slug derived from the name unless given
+func (u *specializationUsecase) CreateSpecialization(ctx context.Context, req *dto.CreateSpecializationRequest) (*dto.SpecializationResponse, error) {
+	name := strings.TrimSpace(req.Name)
+	slug := req.Slug
+	if slug == "" {
+		slug = name
+	}
+	slug = entity.SpecializationSlug(slug)
+	if slug == "" {
+		return nil, ErrInvalidSpecializationSlug
+	}
+
+	tx := u.db.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	specialization := &entity.Specialization{
+		Name:        name,
+		Slug:        slug,
+		Description: req.Description,
+	}
+	if err := u.specializationRepo.Create(tx, specialization); err != nil {
+		if err := specializationDuplicateError(err); err != nil {
+			return nil, err
+		}
+		u.log.Warnf("Failed to create specialization: %+v", err)
+		return nil, err
+	}
+
+	// Audit log - create specialization
+	userID, _ := middleware.GetUserIDFromContext(ctx)
+	response := converter.SpecializationToResponse(specialization)
+	if err := u.auditService.LogCreate(ctx, tx, &userID, entity.AuditActionSpecializationCreate, "specialization", strconv.Itoa(specialization.ID), response); err != nil {
+		u.log.Warnf("Failed to create audit log: %+v", err)
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		u.log.Warnf("Failed commit transaction: %+v", err)
+		return nil, err
+	}
+
+	return response, nil
+}
+
+func (u *specializationUsecase) GetSpecialization(ctx context.Context, id int) (*dto.SpecializationResponse, error) {
+	specialization, err := u.specializationRepo.FindByID(u.db.WithContext(ctx), id)
+	if err != nil {
+		u.log.Warnf("Failed to find specialization %d: %+v", id, err)
+		return nil, err
+	}
+	if specialization == nil {
+		return nil, ErrSpecializationNotFound
+	}
+
+	return converter.SpecializationToResponse(specialization), nil
+}
+
+// GetAllSpecializations returns all specializations ordered by name
+func (u *specializationUsecase) GetAllSpecializations(ctx context.Context) (*dto.SpecializationListResponse, error) {
+	specializations, err := u.specializationRepo.FindAll(u.db.WithContext(ctx))
+	if err != nil {
+		u.log.Warnf("Failed to find specializations: %+v", err)
+		return nil, err
+	}
+
+	return &dto.SpecializationListResponse{
+		Specializations: converter.SpecializationsToResponses(specializations),
+		Total:           len(specializations),
+	}, nil
+}
+
+// UpdateSpecialization changes a specialization. Renaming keeps the slug (it is used in
+// links and filters) unless a new one is given; specialization defaults follow the name.
+func (u *specializationUsecase) UpdateSpecialization(ctx context.Context, id int, req *dto.UpdateSpecializationRequest) (*dto.SpecializationResponse, error) {
+	tx := u.db.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	specialization, err := u.specializationRepo.FindByID(tx, id)
+	if err != nil {
+		u.log.Warnf("Failed to find specialization %d: %+v", id, err)
+		return nil, err
+	}
+	if specialization == nil {
+		return nil, ErrSpecializationNotFound
+	}
+
+	oldValue := converter.SpecializationToResponse(specialization)
+
+	if req.Name != "" {
+		specialization.Name = strings.TrimSpace(req.Name)
+	}
+	if req.Slug != "" {
+		specialization.Slug = entity.SpecializationSlug(req.Slug)
+		if specialization.Slug == "" {
+			return nil, ErrInvalidSpecializationSlug
+		}
+	}
+	if req.Description != nil {
+		specialization.Description = *req.Description
+	}
+
+	if err := u.specializationRepo.Update(tx, specialization); err != nil {
+		if err := specializationDuplicateError(err); err != nil {
+			return nil, err
+		}
+		u.log.Warnf("Failed to update specialization: %+v", err)
+		return nil, err
+	}
+
+	// Audit log - update specialization
+	userID, _ := middleware.GetUserIDFromContext(ctx)
+	newValue := converter.SpecializationToResponse(specialization)
+	if err := u.auditService.LogUpdate(ctx, tx, &userID, entity.AuditActionSpecializationUpdate, "specialization", strconv.Itoa(id), oldValue, newValue); err != nil {
+		u.log.Warnf("Failed to create audit log: %+v", err)
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		u.log.Warnf("Failed commit transaction: %+v", err)
+		return nil, err
+	}
+	// Listed doctors and schedules carry the specialization name
+	u.listingCache.Invalidate(ctx, service.ListingDoctors, service.ListingSchedules)
+
+	return newValue, nil
+}
+
+// DeleteSpecialization removes a specialization no doctor is assigned to, with its defaults
+func (u *specializationUsecase) DeleteSpecialization(ctx context.Context, id int) error {
+	tx := u.db.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	specialization, err := u.specializationRepo.FindByID(tx, id)
+	if err != nil {
+		u.log.Warnf("Failed to find specialization %d: %+v", id, err)
+		return err
+	}
+	if specialization == nil {
+		return ErrSpecializationNotFound
+	}
+
+	if _, err := u.specializationRepo.Delete(tx, id); err != nil {
+		if isForeignKeyError(err, "doctor_profiles") {
+			return ErrSpecializationInUse
+		}
+		u.log.Warnf("Failed to delete specialization: %+v", err)
+		return err
+	}
+
+	// Audit log - delete specialization
+	userID, _ := middleware.GetUserIDFromContext(ctx)
+	if err := u.auditService.LogDelete(ctx, tx, &userID, entity.AuditActionSpecializationDelete, "specialization", strconv.Itoa(id), converter.SpecializationToResponse(specialization)); err != nil {
+		u.log.Warnf("Failed to create audit log: %+v", err)
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		u.log.Warnf("Failed commit transaction: %+v", err)
+		return err
+	}
+
+	return nil
+}
+
+// specializationDuplicateError maps unique violations of the name or slug, nil for other errors
+func specializationDuplicateError(err error) error {
+	switch {
+	case isDuplicateKeyError(err, "slug"):
+		return ErrSpecializationSlugExists
+	case isDuplicateKeyError(err, "name"):
+		return ErrSpecializationNameExists
+	}
+	return nil
+}
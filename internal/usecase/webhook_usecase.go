@@ -0,0 +1,361 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"go-template-clean-architecture/internal/converter"
+	"go-template-clean-architecture/internal/delivery/dto"
+	"go-template-clean-architecture/internal/delivery/http/middleware"
+	"go-template-clean-architecture/internal/domain/entity"
+	"go-template-clean-architecture/internal/domain/repository"
+	"go-template-clean-architecture/internal/service"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrWebhookNotFound              = errors.New("webhook not found")
+	ErrInvalidWebhookEventType      = errors.New("invalid webhook event type")
+	ErrWebhookAlreadyPaused         = errors.New("webhook is already paused")
+	ErrWebhookNotPaused             = errors.New("webhook is not paused")
+	ErrInvalidWebhookDeliveryStatus = errors.New("invalid webhook delivery status")
+)
+
+type WebhookUsecase interface {
+	CreateWebhook(ctx context.Context, req *dto.CreateWebhookRequest) (*dto.CreateWebhookResponse, error)
+	GetWebhook(ctx context.Context, id int) (*dto.WebhookResponse, error)
+	GetAllWebhooks(ctx context.Context) (*dto.WebhookListResponse, error)
+	UpdateWebhook(ctx context.Context, id int, req *dto.UpdateWebhookRequest) (*dto.WebhookResponse, error)
+	DeleteWebhook(ctx context.Context, id int) error
+	PauseWebhook(ctx context.Context, id int, req *dto.PauseWebhookRequest) (*dto.WebhookResponse, error)
+	ResumeWebhook(ctx context.Context, id int) (*dto.WebhookResponse, error)
+	TestWebhook(ctx context.Context, id int) (*dto.WebhookDeliveryResponse, error)
+	GetDeliveries(ctx context.Context, id int, status string, page, limit int) ([]dto.WebhookDeliveryResponse, int64, error)
+}
+
+type webhookUsecase struct {
+	db               *gorm.DB
+	log              *logrus.Logger
+	subscriptionRepo repository.WebhookSubscriptionRepository
+	deliveryRepo     repository.WebhookDeliveryRepository
+	webhookService   *service.WebhookService
+	auditService     service.AuditService
+}
+
+func NewWebhookUsecase(
+	db *gorm.DB,
+	log *logrus.Logger,
+	subscriptionRepo repository.WebhookSubscriptionRepository,
+	deliveryRepo repository.WebhookDeliveryRepository,
+	webhookService *service.WebhookService,
+	auditService service.AuditService,
+) WebhookUsecase {
+	return &webhookUsecase{
+		db:               db,
+		log:              log,
+		subscriptionRepo: subscriptionRepo,
+		deliveryRepo:     deliveryRepo,
+		webhookService:   webhookService,
+		auditService:     auditService,
+	}
+}
+
+// CreateWebhook registers an endpoint. The signing secret is returned only in this response.
+func (u *webhookUsecase) CreateWebhook(ctx context.Context, req *dto.CreateWebhookRequest) (*dto.CreateWebhookResponse, error) {
+	if err := validateWebhookEventTypes(req.EventTypes); err != nil {
+		return nil, err
+	}
+
+	secret := req.Secret
+	if secret == "" {
+		generated, err := service.GenerateWebhookSecret()
+		if err != nil {
+			u.log.Warnf("Failed to generate webhook secret: %+v", err)
+			return nil, err
+		}
+		secret = generated
+	}
+
+	tx := u.db.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	userID, _ := middleware.GetUserIDFromContext(ctx)
+	subscription := &entity.WebhookSubscription{
+		URL:         strings.TrimSpace(req.URL),
+		Secret:      secret,
+		EventTypes:  entity.WebhookEvents(req.EventTypes),
+		Description: strings.TrimSpace(req.Description),
+		Status:      entity.WebhookStatusActive,
+		CreatedBy:   &userID,
+	}
+	if err := u.subscriptionRepo.Create(tx, subscription); err != nil {
+		u.log.Warnf("Failed to create webhook: %+v", err)
+		return nil, err
+	}
+
+	// Audit log - create webhook (without its secret)
+	response := converter.WebhookToResponse(subscription)
+	if err := u.auditService.LogCreate(ctx, tx, &userID, entity.AuditActionWebhookCreate, "webhook", strconv.Itoa(subscription.ID), response); err != nil {
+		u.log.Warnf("Failed to create audit log: %+v", err)
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		u.log.Warnf("Failed commit transaction: %+v", err)
+		return nil, err
+	}
+
+	return &dto.CreateWebhookResponse{WebhookResponse: response, Secret: secret}, nil
+}
+
+func (u *webhookUsecase) GetWebhook(ctx context.Context, id int) (*dto.WebhookResponse, error) {
+	subscription, err := u.findWebhook(u.db.WithContext(ctx), id)
+	if err != nil {
+		return nil, err
+	}
+	return converter.WebhookToResponse(subscription), nil
+}
+
+// GetAllWebhooks returns every webhook and the event types that can be subscribed to
+func (u *webhookUsecase) GetAllWebhooks(ctx context.Context) (*dto.WebhookListResponse, error) {
+	subscriptions, err := u.subscriptionRepo.FindAll(u.db.WithContext(ctx))
+	if err != nil {
+		u.log.Warnf("Failed to find webhooks: %+v", err)
+		return nil, err
+	}
+
+	return &dto.WebhookListResponse{
+		Webhooks:   converter.WebhooksToResponses(subscriptions),
+		EventTypes: entity.WebhookEventTypes,
+		Total:      len(subscriptions),
+	}, nil
+}
+
+// UpdateWebhook changes the endpoint, secret, event types or description of a webhook.
+// Pending deliveries are sent to the new endpoint with the new secret.
+func (u *webhookUsecase) UpdateWebhook(ctx context.Context, id int, req *dto.UpdateWebhookRequest) (*dto.WebhookResponse, error) {
+	if req.EventTypes != nil {
+		if err := validateWebhookEventTypes(req.EventTypes); err != nil {
+			return nil, err
+		}
+	}
+
+	tx := u.db.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	subscription, err := u.findWebhook(tx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	oldValue := converter.WebhookToResponse(subscription)
+
+	if req.URL != nil {
+		subscription.URL = strings.TrimSpace(*req.URL)
+	}
+	if req.Secret != nil {
+		subscription.Secret = *req.Secret
+	}
+	if req.EventTypes != nil {
+		subscription.EventTypes = entity.WebhookEvents(req.EventTypes)
+	}
+	if req.Description != nil {
+		subscription.Description = strings.TrimSpace(*req.Description)
+	}
+
+	if err := u.subscriptionRepo.Update(tx, subscription); err != nil {
+		u.log.Warnf("Failed to update webhook: %+v", err)
+		return nil, err
+	}
+
+	// Audit log - update webhook (without its secret)
+	userID, _ := middleware.GetUserIDFromContext(ctx)
+	newValue := converter.WebhookToResponse(subscription)
+	if err := u.auditService.LogUpdate(ctx, tx, &userID, entity.AuditActionWebhookUpdate, "webhook", strconv.Itoa(id), oldValue, newValue); err != nil {
+		u.log.Warnf("Failed to create audit log: %+v", err)
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		u.log.Warnf("Failed commit transaction: %+v", err)
+		return nil, err
+	}
+
+	return newValue, nil
+}
+
+// DeleteWebhook removes a webhook with its delivery history
+func (u *webhookUsecase) DeleteWebhook(ctx context.Context, id int) error {
+	tx := u.db.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	subscription, err := u.findWebhook(tx, id)
+	if err != nil {
+		return err
+	}
+
+	if _, err := u.subscriptionRepo.Delete(tx, id); err != nil {
+		u.log.Warnf("Failed to delete webhook: %+v", err)
+		return err
+	}
+
+	// Audit log - delete webhook
+	userID, _ := middleware.GetUserIDFromContext(ctx)
+	if err := u.auditService.LogDelete(ctx, tx, &userID, entity.AuditActionWebhookDelete, "webhook", strconv.Itoa(id), converter.WebhookToResponse(subscription)); err != nil {
+		u.log.Warnf("Failed to create audit log: %+v", err)
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		u.log.Warnf("Failed commit transaction: %+v", err)
+		return err
+	}
+	return nil
+}
+
+// PauseWebhook stops the deliveries of a webhook, new events keep being recorded until it is resumed
+func (u *webhookUsecase) PauseWebhook(ctx context.Context, id int, req *dto.PauseWebhookRequest) (*dto.WebhookResponse, error) {
+	tx := u.db.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	subscription, err := u.findWebhook(tx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !subscription.IsActive() {
+		return nil, ErrWebhookAlreadyPaused
+	}
+
+	oldValue := converter.WebhookToResponse(subscription)
+
+	reason := strings.TrimSpace(req.Reason)
+	if reason == "" {
+		reason = "Paused by an admin"
+	}
+	now := time.Now()
+	affected, err := u.subscriptionRepo.Pause(tx, id, reason, now)
+	if err != nil {
+		u.log.Warnf("Failed to pause webhook: %+v", err)
+		return nil, err
+	}
+	// Paused by the delivery worker meanwhile
+	if affected == 0 {
+		return nil, ErrWebhookAlreadyPaused
+	}
+	subscription.Status = entity.WebhookStatusPaused
+	subscription.PausedAt = &now
+	subscription.PauseReason = reason
+
+	// Audit log - pause webhook
+	userID, _ := middleware.GetUserIDFromContext(ctx)
+	newValue := converter.WebhookToResponse(subscription)
+	if err := u.auditService.LogUpdate(ctx, tx, &userID, entity.AuditActionWebhookPause, "webhook", strconv.Itoa(id), oldValue, newValue); err != nil {
+		u.log.Warnf("Failed to create audit log: %+v", err)
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		u.log.Warnf("Failed commit transaction: %+v", err)
+		return nil, err
+	}
+
+	return newValue, nil
+}
+
+// ResumeWebhook reactivates a paused webhook and resets its failure count.
+// The deliveries recorded while it was paused are sent by the worker.
+func (u *webhookUsecase) ResumeWebhook(ctx context.Context, id int) (*dto.WebhookResponse, error) {
+	tx := u.db.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	subscription, err := u.findWebhook(tx, id)
+	if err != nil {
+		return nil, err
+	}
+	if subscription.IsActive() {
+		return nil, ErrWebhookNotPaused
+	}
+
+	oldValue := converter.WebhookToResponse(subscription)
+
+	subscription.Status = entity.WebhookStatusActive
+	subscription.ConsecutiveFailures = 0
+	subscription.PausedAt = nil
+	subscription.PauseReason = ""
+	if err := u.subscriptionRepo.Update(tx, subscription); err != nil {
+		u.log.Warnf("Failed to resume webhook: %+v", err)
+		return nil, err
+	}
+
+	// Audit log - resume webhook
+	userID, _ := middleware.GetUserIDFromContext(ctx)
+	newValue := converter.WebhookToResponse(subscription)
+	if err := u.auditService.LogUpdate(ctx, tx, &userID, entity.AuditActionWebhookResume, "webhook", strconv.Itoa(id), oldValue, newValue); err != nil {
+		u.log.Warnf("Failed to create audit log: %+v", err)
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		u.log.Warnf("Failed commit transaction: %+v", err)
+		return nil, err
+	}
+
+	return newValue, nil
+}
+
+// TestWebhook sends a webhook.test event to the endpoint and returns the recorded delivery.
+// A failing endpoint is not an error, the delivery shows the response or the error.
+func (u *webhookUsecase) TestWebhook(ctx context.Context, id int) (*dto.WebhookDeliveryResponse, error) {
+	delivery, err := u.webhookService.Test(ctx, id)
+	if err != nil {
+		if err == service.ErrWebhookSubscriptionNotFound {
+			return nil, ErrWebhookNotFound
+		}
+		u.log.Warnf("Failed to test webhook %d: %+v", id, err)
+		return nil, err
+	}
+	return converter.WebhookDeliveryToResponse(delivery), nil
+}
+
+// GetDeliveries returns one page of the webhook's delivery history, newest first, optionally of one status
+func (u *webhookUsecase) GetDeliveries(ctx context.Context, id int, status string, page, limit int) ([]dto.WebhookDeliveryResponse, int64, error) {
+	switch entity.WebhookDeliveryStatus(status) {
+	case "", entity.WebhookDeliveryPending, entity.WebhookDeliverySucceeded, entity.WebhookDeliveryFailed:
+	default:
+		return nil, 0, ErrInvalidWebhookDeliveryStatus
+	}
+
+	db := u.db.WithContext(ctx)
+	if _, err := u.findWebhook(db, id); err != nil {
+		return nil, 0, err
+	}
+
+	deliveries, total, err := u.deliveryRepo.FindBySubscriptionID(db, id, status, page, limit)
+	if err != nil {
+		u.log.Warnf("Failed to find deliveries of webhook %d: %+v", id, err)
+		return nil, 0, err
+	}
+	return converter.WebhookDeliveriesToResponses(deliveries), total, nil
+}
+
+func (u *webhookUsecase) findWebhook(db *gorm.DB, id int) (*entity.WebhookSubscription, error) {
+	subscription, err := u.subscriptionRepo.FindByID(db, id)
+	if err != nil {
+		u.log.Warnf("Failed to find webhook %d: %+v", id, err)
+		return nil, err
+	}
+	if subscription == nil {
+		return nil, ErrWebhookNotFound
+	}
+	return subscription, nil
+}
+
+// validateWebhookEventTypes checks that every event type can be subscribed to
+func validateWebhookEventTypes(eventTypes []string) error {
+	for _, eventType := range eventTypes {
+		if !entity.IsWebhookEventType(eventType) {
+			return ErrInvalidWebhookEventType
+		}
+	}
+	return nil
+}
@@ -0,0 +1,3 @@
+-- Rollback: Drop specialization_defaults table
+DROP INDEX IF EXISTS idx_specialization_defaults_lower;
+DROP TABLE IF EXISTS specialization_defaults;
@@ -0,0 +1,17 @@
+-- Migration: Create specialization_defaults table
+-- Description: Stores default quota, consultation duration and fee per doctor specialization
+
+CREATE TABLE IF NOT EXISTS specialization_defaults (
+    specialization VARCHAR(100) PRIMARY KEY,
+    default_quota INTEGER NOT NULL CHECK (default_quota > 0),
+    consultation_minutes INTEGER NOT NULL CHECK (consultation_minutes > 0),
+    fee BIGINT NOT NULL DEFAULT 0 CHECK (fee >= 0),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Case-insensitive lookup when matching doctor_profiles.specialization
+CREATE UNIQUE INDEX IF NOT EXISTS idx_specialization_defaults_lower ON specialization_defaults(LOWER(specialization));
+
+COMMENT ON TABLE specialization_defaults IS 'Default schedule settings used to prefill schedule creation';
+COMMENT ON COLUMN specialization_defaults.fee IS 'Default consultation fee in the smallest currency unit';
@@ -0,0 +1,4 @@
+-- Rollback: Remove doctor attendance tracking from doctor_schedules
+DROP INDEX IF EXISTS idx_doctor_schedules_pending_checkin;
+ALTER TABLE doctor_schedules DROP COLUMN IF EXISTS absence_flagged_at;
+ALTER TABLE doctor_schedules DROP COLUMN IF EXISTS doctor_checked_in_at;
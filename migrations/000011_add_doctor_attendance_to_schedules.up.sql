@@ -0,0 +1,13 @@
+-- Migration: Add doctor attendance tracking to doctor_schedules
+-- Description: Supports doctor check-in and "possibly absent" detection
+
+ALTER TABLE doctor_schedules ADD COLUMN IF NOT EXISTS doctor_checked_in_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE doctor_schedules ADD COLUMN IF NOT EXISTS absence_flagged_at TIMESTAMP WITH TIME ZONE;
+
+-- Partial index for the absence monitor scan (schedules without check-in)
+CREATE INDEX IF NOT EXISTS idx_doctor_schedules_pending_checkin
+    ON doctor_schedules(schedule_date, start_time)
+    WHERE doctor_checked_in_at IS NULL AND absence_flagged_at IS NULL;
+
+COMMENT ON COLUMN doctor_schedules.doctor_checked_in_at IS 'When the doctor checked in for this schedule';
+COMMENT ON COLUMN doctor_schedules.absence_flagged_at IS 'When the schedule was flagged as doctor possibly absent';
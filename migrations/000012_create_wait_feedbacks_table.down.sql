@@ -0,0 +1,3 @@
+-- Rollback: Drop wait_feedbacks table
+DROP INDEX IF EXISTS idx_wait_feedbacks_doctor_id;
+DROP TABLE IF EXISTS wait_feedbacks;
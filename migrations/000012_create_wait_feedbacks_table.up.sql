@@ -0,0 +1,17 @@
+-- Migration: Create wait_feedbacks table
+-- Description: Stores patient-reported waiting time per booking
+
+CREATE TABLE IF NOT EXISTS wait_feedbacks (
+    booking_id UUID PRIMARY KEY REFERENCES bookings(id) ON DELETE CASCADE,
+    patient_id UUID NOT NULL REFERENCES patient_profiles(user_id) ON DELETE CASCADE,
+    doctor_id UUID NOT NULL REFERENCES doctor_profiles(user_id) ON DELETE CASCADE,
+    schedule_id INTEGER NOT NULL REFERENCES doctor_schedules(id) ON DELETE CASCADE,
+    wait_minutes INTEGER NOT NULL CHECK (wait_minutes >= 0),
+    comment TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Index for per-doctor averages
+CREATE INDEX IF NOT EXISTS idx_wait_feedbacks_doctor_id ON wait_feedbacks(doctor_id);
+
+COMMENT ON TABLE wait_feedbacks IS 'Patient-reported waiting time, one entry per booking';
@@ -0,0 +1,4 @@
+-- Rollback: Remove queue calling
+DROP TABLE IF EXISTS doctor_queue_stats;
+DROP INDEX IF EXISTS idx_bookings_schedule_uncalled;
+ALTER TABLE bookings DROP COLUMN IF EXISTS called_at;
@@ -0,0 +1,20 @@
+-- Migration: Add queue calling
+-- Description: Tracks when a booking's queue number is called and the rolling
+--              average minutes per queue number of each doctor (estimated wait)
+
+ALTER TABLE bookings ADD COLUMN called_at TIMESTAMP WITH TIME ZONE;
+
+-- Index for finding the next uncalled booking of a schedule
+CREATE INDEX IF NOT EXISTS idx_bookings_schedule_uncalled
+    ON bookings(schedule_id, queue_number)
+    WHERE called_at IS NULL AND status != 'cancelled';
+
+CREATE TABLE IF NOT EXISTS doctor_queue_stats (
+    doctor_id UUID PRIMARY KEY REFERENCES doctor_profiles(user_id) ON DELETE CASCADE,
+    avg_minutes_per_queue DOUBLE PRECISION NOT NULL DEFAULT 0,
+    sample_count INTEGER NOT NULL DEFAULT 0,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+COMMENT ON COLUMN bookings.called_at IS 'When the doctor called this queue number';
+COMMENT ON TABLE doctor_queue_stats IS 'Rolling average minutes between queue calls per doctor, updated on each call';
@@ -0,0 +1,6 @@
+-- Rollback: Add soft delete for bookings
+-- Soft-deleted rows become visible again
+DROP INDEX IF EXISTS idx_doctor_schedules_deleted_at;
+DROP INDEX IF EXISTS idx_bookings_deleted_at;
+ALTER TABLE doctor_schedules DROP COLUMN IF EXISTS deleted_at;
+ALTER TABLE bookings DROP COLUMN IF EXISTS deleted_at;
@@ -0,0 +1,14 @@
+-- Migration: Add soft delete for bookings
+-- Description: Deleted bookings are kept with deleted_at set, so admins can list and restore
+--              them. Schedules are soft-deleted too, as their bookings keep referencing them
+--              (bookings.schedule_id is ON DELETE RESTRICT).
+
+ALTER TABLE bookings ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE doctor_schedules ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP WITH TIME ZONE;
+
+-- Admin list of deleted bookings, newest deletion first
+CREATE INDEX IF NOT EXISTS idx_bookings_deleted_at ON bookings(deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_doctor_schedules_deleted_at ON doctor_schedules(deleted_at) WHERE deleted_at IS NOT NULL;
+
+COMMENT ON COLUMN bookings.deleted_at IS 'Soft delete: set when the booking or its schedule was deleted, cleared on restore';
+COMMENT ON COLUMN doctor_schedules.deleted_at IS 'Soft delete: set when the schedule was deleted, its bookings are deleted with it';
@@ -0,0 +1,2 @@
+-- Rollback: Remove version from bookings table
+ALTER TABLE bookings DROP COLUMN IF EXISTS version;
@@ -0,0 +1,6 @@
+-- Migration: Add version to bookings table
+-- Description: Optimistic locking - every update checks and increments version
+
+ALTER TABLE bookings ADD COLUMN version INTEGER NOT NULL DEFAULT 1;
+
+COMMENT ON COLUMN bookings.version IS 'Optimistic lock version, updates use WHERE version = ? and increment it';
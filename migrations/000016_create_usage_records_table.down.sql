@@ -0,0 +1,2 @@
+-- Rollback: Drop usage_records table
+DROP TABLE IF EXISTS usage_records;
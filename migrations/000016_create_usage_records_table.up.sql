@@ -0,0 +1,14 @@
+-- Migration: Create usage_records table
+-- Description: Daily metered usage per tenant (API calls, bookings created) for billing.
+--              Counted in Redis during the day and flushed once the day is over.
+
+CREATE TABLE IF NOT EXISTS usage_records (
+    tenant_id VARCHAR(64) NOT NULL,
+    usage_date DATE NOT NULL,
+    api_calls BIGINT NOT NULL DEFAULT 0,
+    bookings_created BIGINT NOT NULL DEFAULT 0,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (tenant_id, usage_date)
+);
+
+COMMENT ON TABLE usage_records IS 'Daily usage per tenant, flushed from Redis counters usage:{tenant}:{date}';
@@ -0,0 +1,4 @@
+-- Rollback: Drop outbox_events table
+DROP INDEX IF EXISTS idx_outbox_events_aggregate;
+DROP INDEX IF EXISTS idx_outbox_events_pending;
+DROP TABLE IF EXISTS outbox_events;
@@ -0,0 +1,28 @@
+-- Migration: Create outbox_events table (Transactional Outbox)
+-- Description: Booking side effects written in the same transaction as the booking
+--              change and published by a background worker with retries
+
+CREATE TABLE IF NOT EXISTS outbox_events (
+    id BIGSERIAL PRIMARY KEY,
+    aggregate_type VARCHAR(50) NOT NULL,
+    aggregate_id VARCHAR(100) NOT NULL,
+    event_type VARCHAR(100) NOT NULL,
+    payload JSONB,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    attempts INTEGER NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    last_error TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    published_at TIMESTAMP WITH TIME ZONE
+);
+
+-- Index for the worker polling due events
+CREATE INDEX IF NOT EXISTS idx_outbox_events_pending
+    ON outbox_events(next_attempt_at, id)
+    WHERE status = 'pending';
+
+-- Index for event history per aggregate
+CREATE INDEX IF NOT EXISTS idx_outbox_events_aggregate ON outbox_events(aggregate_type, aggregate_id);
+
+COMMENT ON TABLE outbox_events IS 'Transactional outbox - events are published at least once by the outbox worker';
+COMMENT ON COLUMN outbox_events.status IS 'pending = waiting/retrying, published = handled, failed = gave up after max attempts';
@@ -0,0 +1,7 @@
+-- Rollback: Remove time-slot booking mode
+DROP INDEX IF EXISTS idx_bookings_slot_active;
+ALTER TABLE bookings DROP COLUMN IF EXISTS slot_id;
+DROP TABLE IF EXISTS schedule_slots;
+ALTER TABLE doctor_schedules
+    DROP COLUMN IF EXISTS slot_minutes,
+    DROP COLUMN IF EXISTS booking_mode;
@@ -0,0 +1,30 @@
+-- Migration: Add time-slot booking mode
+-- Description: Schedules can be split into fixed-length appointment slots where
+--              a patient books a specific time instead of a queue number
+
+ALTER TABLE doctor_schedules
+    ADD COLUMN booking_mode VARCHAR(10) NOT NULL DEFAULT 'queue'
+        CHECK (booking_mode IN ('queue', 'slot')),
+    ADD COLUMN slot_minutes INTEGER NOT NULL DEFAULT 0;
+
+CREATE TABLE IF NOT EXISTS schedule_slots (
+    id BIGSERIAL PRIMARY KEY,
+    schedule_id INTEGER NOT NULL REFERENCES doctor_schedules(id) ON DELETE CASCADE,
+    position INTEGER NOT NULL,
+    start_time TIME NOT NULL,
+    end_time TIME NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT uq_schedule_slots_position UNIQUE (schedule_id, position)
+);
+
+ALTER TABLE bookings ADD COLUMN slot_id BIGINT REFERENCES schedule_slots(id) ON DELETE RESTRICT;
+
+-- One active booking per slot (safety net behind the Redis reservation)
+CREATE UNIQUE INDEX IF NOT EXISTS idx_bookings_slot_active
+    ON bookings(slot_id)
+    WHERE slot_id IS NOT NULL AND status != 'cancelled';
+
+COMMENT ON COLUMN doctor_schedules.booking_mode IS 'queue = numbered queue, slot = fixed-length appointment times';
+COMMENT ON COLUMN doctor_schedules.slot_minutes IS 'Appointment length in minutes (slot mode only)';
+COMMENT ON TABLE schedule_slots IS 'Appointment times of slot-mode schedules, position is the booking queue number';
+COMMENT ON COLUMN bookings.slot_id IS 'Booked appointment slot (slot-mode schedules only)';
@@ -0,0 +1,4 @@
+-- Rollback: Drop broadcasts and broadcast_recipients tables
+DROP INDEX IF EXISTS idx_broadcast_recipients_status;
+DROP TABLE IF EXISTS broadcast_recipients;
+DROP TABLE IF EXISTS broadcasts;
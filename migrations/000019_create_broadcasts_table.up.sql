@@ -0,0 +1,35 @@
+-- Migration: Create broadcasts and broadcast_recipients tables
+-- Description: Admin announcements sent to a patient segment, delivered one
+--              recipient at a time by the outbox worker (throttled)
+
+CREATE TABLE IF NOT EXISTS broadcasts (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    title VARCHAR(150) NOT NULL,
+    message TEXT NOT NULL,
+    segment JSONB,
+    total_recipients INTEGER NOT NULL DEFAULT 0,
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS broadcast_recipients (
+    id BIGSERIAL PRIMARY KEY,
+    broadcast_id UUID NOT NULL REFERENCES broadcasts(id) ON DELETE CASCADE,
+    patient_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    attempts INTEGER NOT NULL DEFAULT 0,
+    last_error TEXT,
+    scheduled_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    sent_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT uq_broadcast_recipients_patient UNIQUE (broadcast_id, patient_id)
+);
+
+-- Index for the delivery report
+CREATE INDEX IF NOT EXISTS idx_broadcast_recipients_status ON broadcast_recipients(broadcast_id, status);
+
+COMMENT ON TABLE broadcasts IS 'Admin announcements to a patient segment';
+COMMENT ON COLUMN broadcasts.segment IS 'Filter the recipients were selected with (doctor, date range, upcoming bookings)';
+COMMENT ON TABLE broadcast_recipients IS 'Per-patient delivery state of a broadcast';
+COMMENT ON COLUMN broadcast_recipients.status IS 'pending = waiting/retrying, sent = delivered, failed = not deliverable';
+COMMENT ON COLUMN broadcast_recipients.scheduled_at IS 'Throttled delivery time assigned when the broadcast was created';
@@ -0,0 +1,4 @@
+-- Rollback: Drop holidays table
+DROP INDEX IF EXISTS idx_doctor_schedules_holiday_flagged;
+ALTER TABLE doctor_schedules DROP COLUMN IF EXISTS holiday_flagged_at;
+DROP TABLE IF EXISTS holidays;
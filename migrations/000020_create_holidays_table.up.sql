@@ -0,0 +1,23 @@
+-- Migration: Create holidays table
+-- Description: Clinic holidays / blackout dates. No schedules can be created on
+--              them; existing schedules on a new holiday are flagged for admin action
+
+CREATE TABLE IF NOT EXISTS holidays (
+    id SERIAL PRIMARY KEY,
+    holiday_date DATE NOT NULL,
+    name VARCHAR(150) NOT NULL,
+    description TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT uq_holidays_date UNIQUE (holiday_date)
+);
+
+ALTER TABLE doctor_schedules ADD COLUMN IF NOT EXISTS holiday_flagged_at TIMESTAMP WITH TIME ZONE;
+
+-- Partial index for the holiday conflict list
+CREATE INDEX IF NOT EXISTS idx_doctor_schedules_holiday_flagged
+    ON doctor_schedules(schedule_date)
+    WHERE holiday_flagged_at IS NOT NULL;
+
+COMMENT ON TABLE holidays IS 'Clinic holidays and blackout dates - schedules cannot be created on them';
+COMMENT ON COLUMN doctor_schedules.holiday_flagged_at IS 'When the schedule was found to fall on a newly added holiday';
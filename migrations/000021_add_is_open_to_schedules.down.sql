@@ -0,0 +1,2 @@
+-- Rollback: Remove booking open/closed toggle
+ALTER TABLE doctor_schedules DROP COLUMN IF EXISTS is_open;
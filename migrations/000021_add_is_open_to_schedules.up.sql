@@ -0,0 +1,6 @@
+-- Migration: Add booking open/closed toggle to doctor_schedules
+-- Description: Lets admins and doctors pause booking on a schedule without deleting it
+
+ALTER TABLE doctor_schedules ADD COLUMN IF NOT EXISTS is_open BOOLEAN NOT NULL DEFAULT true;
+
+COMMENT ON COLUMN doctor_schedules.is_open IS 'false = new bookings and waitlist joins are paused';
@@ -0,0 +1,2 @@
+-- Rollback: Remove unique queue number per schedule
+DROP INDEX IF EXISTS idx_bookings_schedule_queue_active;
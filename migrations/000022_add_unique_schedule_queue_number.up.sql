@@ -0,0 +1,9 @@
+-- Migration: Add unique queue number per schedule
+-- Description: Prevents two active bookings from sharing a queue number after a Redis resync.
+-- Cancelled bookings are excluded so time-slot schedules can rebook a freed slot position.
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_bookings_schedule_queue_active
+    ON bookings(schedule_id, queue_number)
+    WHERE status != 'cancelled';
+
+COMMENT ON INDEX idx_bookings_schedule_queue_active IS 'One active booking per queue number on a schedule';
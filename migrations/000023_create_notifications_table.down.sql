@@ -0,0 +1,6 @@
+-- Rollback: Drop notifications table
+DROP INDEX IF EXISTS idx_notifications_booking_id;
+DROP INDEX IF EXISTS idx_notifications_provider_message;
+DROP INDEX IF EXISTS idx_notifications_pending;
+DROP INDEX IF EXISTS idx_notifications_dedupe_key;
+DROP TABLE IF EXISTS notifications;
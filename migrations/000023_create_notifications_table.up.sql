@@ -0,0 +1,45 @@
+-- Migration: Create notifications table
+-- Description: Every outbound patient notification with its provider message ID and
+--              delivery status, retried with backoff on transient provider failures
+
+CREATE TABLE IF NOT EXISTS notifications (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    patient_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    booking_id UUID REFERENCES bookings(id) ON DELETE SET NULL,
+    dedupe_key VARCHAR(150) NOT NULL,
+    event_type VARCHAR(100) NOT NULL,
+    channel VARCHAR(20) NOT NULL,
+    recipient VARCHAR(255) NOT NULL,
+    message TEXT NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    provider VARCHAR(50),
+    provider_message_id VARCHAR(255),
+    attempts INTEGER NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    last_error TEXT,
+    sent_at TIMESTAMP WITH TIME ZONE,
+    delivered_at TIMESTAMP WITH TIME ZONE,
+    failed_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- One notification per source (outbox event, broadcast recipient), so redelivered events do not notify twice
+CREATE UNIQUE INDEX IF NOT EXISTS idx_notifications_dedupe_key ON notifications(dedupe_key);
+
+-- Index for the worker polling due notifications
+CREATE INDEX IF NOT EXISTS idx_notifications_pending
+    ON notifications(next_attempt_at, created_at)
+    WHERE status = 'pending';
+
+-- Index for provider delivery callbacks
+CREATE UNIQUE INDEX IF NOT EXISTS idx_notifications_provider_message
+    ON notifications(provider, provider_message_id)
+    WHERE provider_message_id IS NOT NULL;
+
+-- Index for notification history per booking
+CREATE INDEX IF NOT EXISTS idx_notifications_booking_id ON notifications(booking_id);
+
+COMMENT ON TABLE notifications IS 'Outbound patient notifications (SMS/email) and their delivery status';
+COMMENT ON COLUMN notifications.status IS 'pending = waiting/retrying, sent = accepted by provider, delivered = confirmed by provider callback, failed = rejected, undeliverable or gave up after max attempts';
+COMMENT ON COLUMN notifications.dedupe_key IS 'Source of the notification, e.g. outbox:<event id>';
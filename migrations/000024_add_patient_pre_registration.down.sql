@@ -0,0 +1,5 @@
+-- Rollback: Remove patient pre-registration
+DROP INDEX IF EXISTS idx_users_unclaimed;
+ALTER TABLE patient_profiles DROP COLUMN IF EXISTS partner;
+ALTER TABLE users DROP COLUMN IF EXISTS claimed_at;
+ALTER TABLE users DROP COLUMN IF EXISTS is_claimed;
@@ -0,0 +1,14 @@
+-- Migration: Add patient pre-registration
+-- Description: Patient accounts pre-created from a corporate/insurance partner roster,
+--              claimed later by the patient via an OTP sent to the registered phone
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS is_claimed BOOLEAN NOT NULL DEFAULT true;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS claimed_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE patient_profiles ADD COLUMN IF NOT EXISTS partner VARCHAR(100);
+
+-- Index for finding unclaimed accounts per partner
+CREATE INDEX IF NOT EXISTS idx_users_unclaimed ON users(id) WHERE is_claimed = false;
+
+COMMENT ON COLUMN users.is_claimed IS 'false = pre-registered from a partner roster, no usable email/password until claimed';
+COMMENT ON COLUMN users.claimed_at IS 'When a pre-registered patient claimed the account';
+COMMENT ON COLUMN patient_profiles.partner IS 'Corporate/insurance partner whose roster pre-registered the patient';
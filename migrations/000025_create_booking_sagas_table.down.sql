@@ -0,0 +1,4 @@
+-- Rollback: Drop booking_sagas table
+DROP INDEX IF EXISTS idx_booking_sagas_status;
+DROP INDEX IF EXISTS idx_booking_sagas_unfinished;
+DROP TABLE IF EXISTS booking_sagas;
@@ -0,0 +1,32 @@
+-- Migration: Create booking_sagas table
+-- Description: Persisted state of the CreateBooking saga (Redis slot reservation → booking insert),
+--              so a failure or crash between steps is always compensated and visible to admins
+
+CREATE TABLE IF NOT EXISTS booking_sagas (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    booking_id UUID NOT NULL,
+    booking_code VARCHAR(50) NOT NULL,
+    patient_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    schedule_id INTEGER NOT NULL,
+    slot_id BIGINT,
+    queue_number INTEGER NOT NULL DEFAULT 0,
+    status VARCHAR(20) NOT NULL DEFAULT 'running',
+    step VARCHAR(50) NOT NULL DEFAULT '',
+    last_error TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    finished_at TIMESTAMP WITH TIME ZONE
+);
+
+-- Index for the recovery worker polling unfinished sagas
+CREATE INDEX IF NOT EXISTS idx_booking_sagas_unfinished
+    ON booking_sagas(updated_at)
+    WHERE status IN ('running', 'compensating');
+
+-- Index for the admin saga list
+CREATE INDEX IF NOT EXISTS idx_booking_sagas_status ON booking_sagas(status, created_at DESC);
+
+COMMENT ON TABLE booking_sagas IS 'State of each CreateBooking saga, kept for recovery and troubleshooting';
+COMMENT ON COLUMN booking_sagas.booking_id IS 'ID assigned to the booking before it is inserted, so recovery can tell whether the insert committed';
+COMMENT ON COLUMN booking_sagas.step IS 'Last completed (and not yet compensated) step, empty before the first';
+COMMENT ON COLUMN booking_sagas.status IS 'running, completed, compensating, compensated, failed = compensation failed and needs manual correction';
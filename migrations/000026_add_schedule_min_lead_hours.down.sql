@@ -0,0 +1,3 @@
+-- Rollback: Remove schedule minimum booking lead time
+ALTER TABLE doctor_schedules DROP CONSTRAINT IF EXISTS chk_doctor_schedules_min_lead_hours;
+ALTER TABLE doctor_schedules DROP COLUMN IF EXISTS min_lead_hours;
@@ -0,0 +1,9 @@
+-- Migration: Add schedule minimum booking lead time
+-- Description: Optional per-schedule override of how many hours before the start
+--              bookings close (defaults to the global BOOKING_CUTOFF)
+
+ALTER TABLE doctor_schedules ADD COLUMN IF NOT EXISTS min_lead_hours INTEGER;
+
+ALTER TABLE doctor_schedules ADD CONSTRAINT chk_doctor_schedules_min_lead_hours CHECK (min_lead_hours IS NULL OR min_lead_hours >= 0);
+
+COMMENT ON COLUMN doctor_schedules.min_lead_hours IS 'Bookings must be made at least this many hours before the start; NULL = global BOOKING_CUTOFF';
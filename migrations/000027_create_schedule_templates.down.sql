@@ -0,0 +1,5 @@
+-- Rollback: Drop schedule_templates and schedule_generation_runs tables
+DROP TABLE IF EXISTS schedule_generation_runs;
+DROP INDEX IF EXISTS idx_doctor_schedules_template_date;
+ALTER TABLE doctor_schedules DROP COLUMN IF EXISTS template_id;
+DROP TABLE IF EXISTS schedule_templates;
@@ -0,0 +1,56 @@
+-- Migration: Create schedule_templates and schedule_generation_runs tables
+-- Description: Weekly recurring schedule templates per doctor, expanded nightly into
+--              doctor_schedules for the next N days, with a report of every generation run
+
+CREATE TABLE IF NOT EXISTS schedule_templates (
+    id SERIAL PRIMARY KEY,
+    doctor_id UUID NOT NULL REFERENCES doctor_profiles(user_id) ON DELETE CASCADE,
+    weekday SMALLINT NOT NULL CHECK (weekday BETWEEN 0 AND 6),
+    start_time TIME NOT NULL,
+    end_time TIME NOT NULL,
+    total_quota INTEGER NOT NULL DEFAULT 0,
+    booking_mode VARCHAR(10) NOT NULL DEFAULT 'queue',
+    slot_minutes INTEGER NOT NULL DEFAULT 0,
+    min_lead_hours INTEGER CHECK (min_lead_hours IS NULL OR min_lead_hours >= 0),
+    is_active BOOLEAN NOT NULL DEFAULT true,
+    generated_until DATE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT chk_schedule_templates_time CHECK (end_time > start_time)
+);
+
+CREATE INDEX IF NOT EXISTS idx_schedule_templates_doctor ON schedule_templates(doctor_id, weekday);
+
+-- Schedules generated from a template (kept when the template is deleted)
+ALTER TABLE doctor_schedules ADD COLUMN IF NOT EXISTS template_id INTEGER REFERENCES schedule_templates(id) ON DELETE SET NULL;
+
+-- One generated schedule per template and date - makes generation idempotent
+CREATE UNIQUE INDEX IF NOT EXISTS idx_doctor_schedules_template_date
+    ON doctor_schedules(template_id, schedule_date)
+    WHERE template_id IS NOT NULL;
+
+CREATE TABLE IF NOT EXISTS schedule_generation_runs (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    trigger VARCHAR(20) NOT NULL,
+    from_date DATE NOT NULL,
+    to_date DATE NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'running',
+    created_count INTEGER NOT NULL DEFAULT 0,
+    existing_count INTEGER NOT NULL DEFAULT 0,
+    skipped_count INTEGER NOT NULL DEFAULT 0,
+    failed_count INTEGER NOT NULL DEFAULT 0,
+    details JSONB,
+    error TEXT,
+    started_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    finished_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX IF NOT EXISTS idx_schedule_generation_runs_started ON schedule_generation_runs(started_at DESC);
+
+COMMENT ON TABLE schedule_templates IS 'Weekly recurring schedule of a doctor, expanded into doctor_schedules by the generation worker';
+COMMENT ON COLUMN schedule_templates.weekday IS '0 = Sunday ... 6 = Saturday';
+COMMENT ON COLUMN schedule_templates.generated_until IS 'Last date already expanded, later runs only generate after it (deleted schedules are not recreated)';
+COMMENT ON COLUMN doctor_schedules.template_id IS 'Template the schedule was generated from, NULL = created manually';
+COMMENT ON TABLE schedule_generation_runs IS 'Report of each schedule generation run (nightly or triggered by an admin)';
+COMMENT ON COLUMN schedule_generation_runs.existing_count IS 'Template dates already generated by an earlier run';
+COMMENT ON COLUMN schedule_generation_runs.details IS 'Created, skipped (holiday/conflict) and failed template dates';
@@ -0,0 +1,7 @@
+-- Rollback: Remove schedule approval workflow
+DROP INDEX IF EXISTS idx_doctor_schedules_pending_approval;
+ALTER TABLE doctor_schedules DROP CONSTRAINT IF EXISTS chk_doctor_schedules_approval_status;
+ALTER TABLE doctor_schedules DROP COLUMN IF EXISTS rejection_reason;
+ALTER TABLE doctor_schedules DROP COLUMN IF EXISTS reviewed_at;
+ALTER TABLE doctor_schedules DROP COLUMN IF EXISTS reviewed_by;
+ALTER TABLE doctor_schedules DROP COLUMN IF EXISTS approval_status;
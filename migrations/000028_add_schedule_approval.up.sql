@@ -0,0 +1,20 @@
+-- Migration: Add schedule approval workflow
+-- Description: Doctors can propose schedules that wait for admin approval.
+--              Existing and admin-created schedules are approved.
+
+ALTER TABLE doctor_schedules ADD COLUMN IF NOT EXISTS approval_status VARCHAR(20) NOT NULL DEFAULT 'approved';
+ALTER TABLE doctor_schedules ADD COLUMN IF NOT EXISTS reviewed_by UUID REFERENCES users(id) ON DELETE SET NULL;
+ALTER TABLE doctor_schedules ADD COLUMN IF NOT EXISTS reviewed_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE doctor_schedules ADD COLUMN IF NOT EXISTS rejection_reason TEXT;
+
+ALTER TABLE doctor_schedules ADD CONSTRAINT chk_doctor_schedules_approval_status
+    CHECK (approval_status IN ('approved', 'pending_approval', 'rejected'));
+
+-- Admin review queue
+CREATE INDEX IF NOT EXISTS idx_doctor_schedules_pending_approval ON doctor_schedules(schedule_date)
+    WHERE approval_status = 'pending_approval';
+
+COMMENT ON COLUMN doctor_schedules.approval_status IS 'approved = bookable; pending_approval = proposed by the doctor; rejected = declined by an admin';
+COMMENT ON COLUMN doctor_schedules.reviewed_by IS 'Admin who approved or rejected the proposal';
+COMMENT ON COLUMN doctor_schedules.reviewed_at IS 'When the proposal was approved or rejected';
+COMMENT ON COLUMN doctor_schedules.rejection_reason IS 'Reason given by the admin when rejecting the proposal';
@@ -0,0 +1,2 @@
+-- Rollback: Remove schedule break windows
+ALTER TABLE doctor_schedules DROP COLUMN IF EXISTS breaks;
@@ -0,0 +1,7 @@
+-- Migration: Add schedule break windows
+-- Description: Pauses inside a schedule (e.g. lunch 12:00-13:00). Time slots are not
+--              generated inside them and queue wait estimates skip them.
+
+ALTER TABLE doctor_schedules ADD COLUMN IF NOT EXISTS breaks JSONB NOT NULL DEFAULT '[]';
+
+COMMENT ON COLUMN doctor_schedules.breaks IS 'Breaks inside the schedule ordered by start time: [{"start_time":"HH:MM","end_time":"HH:MM"}]';
@@ -0,0 +1,2 @@
+-- Rollback: Drop schedule_versions table
+DROP TABLE IF EXISTS schedule_versions;
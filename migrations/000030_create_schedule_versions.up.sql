@@ -0,0 +1,25 @@
+-- Migration: Create schedule_versions table
+-- Description: History of every doctor schedule mutation (who, when, old/new values).
+--              Rows are kept after the schedule is deleted, so there is no foreign key on schedule_id.
+
+CREATE TABLE IF NOT EXISTS schedule_versions (
+    id BIGSERIAL PRIMARY KEY,
+    schedule_id INTEGER NOT NULL,
+    action VARCHAR(100) NOT NULL,
+    changed_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    old_value JSONB,
+    new_value JSONB,
+    changed_fields JSONB NOT NULL DEFAULT '[]',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_schedule_versions_schedule_id ON schedule_versions(schedule_id, id);
+CREATE INDEX IF NOT EXISTS idx_schedule_versions_changed_by ON schedule_versions(changed_by);
+CREATE INDEX IF NOT EXISTS idx_schedule_versions_changed_fields ON schedule_versions USING GIN (changed_fields);
+
+COMMENT ON TABLE schedule_versions IS 'One row per doctor schedule mutation';
+COMMENT ON COLUMN schedule_versions.action IS 'Audit action of the mutation, e.g. schedule.update';
+COMMENT ON COLUMN schedule_versions.changed_by IS 'User who made the change; NULL = system (absence monitor, nightly generation)';
+COMMENT ON COLUMN schedule_versions.old_value IS 'Schedule before the change; NULL on create';
+COMMENT ON COLUMN schedule_versions.new_value IS 'Schedule after the change; NULL on delete';
+COMMENT ON COLUMN schedule_versions.changed_fields IS 'Names of the fields that changed, e.g. ["start_time","total_quota"]';
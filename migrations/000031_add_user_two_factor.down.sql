@@ -0,0 +1,4 @@
+-- Rollback: Remove TOTP two-factor authentication
+ALTER TABLE users DROP COLUMN IF EXISTS two_factor_backup_codes;
+ALTER TABLE users DROP COLUMN IF EXISTS two_factor_enabled_at;
+ALTER TABLE users DROP COLUMN IF EXISTS two_factor_secret;
@@ -0,0 +1,11 @@
+-- Migration: Add TOTP two-factor authentication
+-- Description: Users can enroll an authenticator app. Logins of enrolled users only
+--              get their tokens after a valid TOTP or backup code.
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS two_factor_secret TEXT;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS two_factor_enabled_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS two_factor_backup_codes JSONB NOT NULL DEFAULT '[]';
+
+COMMENT ON COLUMN users.two_factor_secret IS 'Base32 TOTP secret, set on enrollment and cleared when 2FA is disabled or reset';
+COMMENT ON COLUMN users.two_factor_enabled_at IS 'When 2FA was activated; NULL = 2FA off (an enrollment may be pending)';
+COMMENT ON COLUMN users.two_factor_backup_codes IS 'SHA-256 hashes of the unused one-time backup codes';
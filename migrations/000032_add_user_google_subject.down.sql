@@ -0,0 +1,3 @@
+-- Rollback: Remove Google sign-in
+DROP INDEX IF EXISTS idx_users_google_subject;
+ALTER TABLE users DROP COLUMN IF EXISTS google_subject;
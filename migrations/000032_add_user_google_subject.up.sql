@@ -0,0 +1,9 @@
+-- Migration: Add Google sign-in
+-- Description: Patients can log in with their Google account. The Google subject
+--              (stable account ID) links the account to the user.
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS google_subject VARCHAR(255);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_google_subject ON users (google_subject) WHERE google_subject IS NOT NULL;
+
+COMMENT ON COLUMN users.google_subject IS 'Google account ID (OpenID sub) linked for SSO; NULL = not linked';
@@ -0,0 +1,3 @@
+-- Rollback: Drop permissions
+DROP TABLE IF EXISTS role_permissions;
+DROP TABLE IF EXISTS permissions;
@@ -0,0 +1,53 @@
+-- Migration: Create permissions
+-- Description: Routes require permissions granted to roles instead of hard-coded
+--              role IDs, so staff roles (nurse, receptionist) can be added as data.
+
+CREATE TABLE IF NOT EXISTS permissions (
+    id SERIAL PRIMARY KEY,
+    code VARCHAR(100) NOT NULL UNIQUE,
+    description TEXT
+);
+
+CREATE TABLE IF NOT EXISTS role_permissions (
+    role_id INT NOT NULL REFERENCES roles(id) ON DELETE CASCADE,
+    permission_id INT NOT NULL REFERENCES permissions(id) ON DELETE CASCADE,
+    PRIMARY KEY (role_id, permission_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_role_permissions_permission_id ON role_permissions(permission_id);
+
+INSERT INTO permissions (code, description) VALUES
+    ('user:manage', 'Create staff accounts and reset two-factor authentication'),
+    ('role:manage', 'Create roles and change their permissions'),
+    ('doctor:read', 'View doctors'),
+    ('doctor:write', 'Create, update and delete doctors'),
+    ('schedule:read', 'View schedules, their history and templates'),
+    ('schedule:write', 'Manage schedules, templates and schedule generation'),
+    ('schedule:approve', 'Approve or reject schedules proposed by doctors'),
+    ('booking:read', 'View bookings, their notifications and sagas'),
+    ('booking:write', 'Import and cancel bookings'),
+    ('patient:read', 'View patient timelines'),
+    ('patient:write', 'Import partner patient rosters'),
+    ('broadcast:read', 'View patient broadcasts'),
+    ('broadcast:send', 'Send patient broadcasts'),
+    ('settings:read', 'View holidays and specialization defaults'),
+    ('settings:write', 'Manage holidays and specialization defaults'),
+    ('system:manage', 'Change log levels and correct Redis booking counters'),
+    ('report:read', 'View reports'),
+    ('audit:read', 'View audit logs'),
+    ('portal:doctor', 'Doctor portal: own schedules, queue and profile'),
+    ('portal:patient', 'Patient portal: own bookings and profile')
+ON CONFLICT (code) DO NOTHING;
+
+-- Grants of the built-in roles (also in seeder.sql for databases seeded later)
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id
+FROM roles r
+JOIN permissions p ON
+    (r.role_name = 'admin' AND p.code NOT LIKE 'portal:%')
+    OR (r.role_name = 'doctor' AND p.code = 'portal:doctor')
+    OR (r.role_name = 'patient' AND p.code = 'portal:patient')
+ON CONFLICT DO NOTHING;
+
+COMMENT ON TABLE permissions IS 'Actions on resources (resource:action) routes require';
+COMMENT ON TABLE role_permissions IS 'Permissions granted to each role';
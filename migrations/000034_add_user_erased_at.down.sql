@@ -0,0 +1,2 @@
+-- Rollback: Remove patient account erasure
+ALTER TABLE users DROP COLUMN IF EXISTS erased_at;
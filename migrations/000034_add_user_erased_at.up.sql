@@ -0,0 +1,7 @@
+-- Migration: Add patient account erasure
+-- Description: Patients can delete their account (right to erasure). The account is
+--              deactivated and its PII anonymized; bookings are kept for statistics.
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS erased_at TIMESTAMP WITH TIME ZONE;
+
+COMMENT ON COLUMN users.erased_at IS 'When the account was erased on request of the patient; name, email, NIK and phone are anonymized';
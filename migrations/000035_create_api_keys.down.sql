@@ -0,0 +1,4 @@
+-- Rollback: Drop API keys
+DROP TABLE IF EXISTS api_key_permissions;
+DROP TABLE IF EXISTS api_keys;
+DELETE FROM permissions WHERE code = 'api_key:manage';
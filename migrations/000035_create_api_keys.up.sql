@@ -0,0 +1,39 @@
+-- Migration: Create API keys
+-- Description: Machine clients (kiosk, reporting service) call the admin API with an
+--              X-API-Key instead of a user JWT, limited to the permissions of the key.
+
+CREATE TABLE IF NOT EXISTS api_keys (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name VARCHAR(100) NOT NULL,
+    key_prefix VARCHAR(20) NOT NULL,
+    key_hash VARCHAR(64) NOT NULL UNIQUE,
+    expires_at TIMESTAMP WITH TIME ZONE,
+    last_used_at TIMESTAMP WITH TIME ZONE,
+    revoked_at TIMESTAMP WITH TIME ZONE,
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS api_key_permissions (
+    api_key_id UUID NOT NULL REFERENCES api_keys(id) ON DELETE CASCADE,
+    permission_id INT NOT NULL REFERENCES permissions(id) ON DELETE CASCADE,
+    PRIMARY KEY (api_key_id, permission_id)
+);
+
+INSERT INTO permissions (code, description) VALUES
+    ('api_key:manage', 'Create, change and revoke API keys')
+ON CONFLICT (code) DO NOTHING;
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id
+FROM roles r
+JOIN permissions p ON p.code = 'api_key:manage'
+WHERE r.role_name = 'admin'
+ON CONFLICT DO NOTHING;
+
+COMMENT ON TABLE api_keys IS 'Credentials of machine clients, sent as X-API-Key';
+COMMENT ON COLUMN api_keys.key_prefix IS 'Leading characters of the key, shown to tell keys apart';
+COMMENT ON COLUMN api_keys.key_hash IS 'SHA-256 (hex) of the key, the key itself is only shown on creation';
+COMMENT ON COLUMN api_keys.last_used_at IS 'Last authenticated request, updated at most once a minute';
+COMMENT ON TABLE api_key_permissions IS 'Permissions granted to each API key';
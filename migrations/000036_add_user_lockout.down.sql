@@ -0,0 +1,5 @@
+-- Rollback: Persist login lockouts
+DROP INDEX IF EXISTS idx_users_locked_until;
+ALTER TABLE users DROP COLUMN IF EXISTS last_locked_at;
+ALTER TABLE users DROP COLUMN IF EXISTS lockout_count;
+ALTER TABLE users DROP COLUMN IF EXISTS locked_until;
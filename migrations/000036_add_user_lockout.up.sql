@@ -0,0 +1,14 @@
+-- Migration: Persist login lockouts
+-- Description: Too many wrong passwords lock the account in the database instead of
+--              only in Redis. Each lockout lasts longer than the previous one until a
+--              successful login or an admin unlock resets the count.
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS locked_until TIMESTAMP WITH TIME ZONE;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS lockout_count INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS last_locked_at TIMESTAMP WITH TIME ZONE;
+
+CREATE INDEX IF NOT EXISTS idx_users_locked_until ON users (locked_until) WHERE locked_until IS NOT NULL;
+
+COMMENT ON COLUMN users.locked_until IS 'Logins are refused until this time; NULL or past = not locked';
+COMMENT ON COLUMN users.lockout_count IS 'Consecutive lockouts, sets the duration of the next one; reset on successful login or unlock';
+COMMENT ON COLUMN users.last_locked_at IS 'When the account was last locked';
@@ -0,0 +1,2 @@
+-- Rollback: Remove support impersonation permission
+DELETE FROM permissions WHERE code = 'user:impersonate';
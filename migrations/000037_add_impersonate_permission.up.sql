@@ -0,0 +1,14 @@
+-- Migration: Add support impersonation permission
+-- Description: Admins can act as a user for support with a short-lived token. Every
+--              request made with it is audited with both the user and the admin.
+
+INSERT INTO permissions (code, description) VALUES
+    ('user:impersonate', 'Act as a doctor, patient or staff user for support')
+ON CONFLICT (code) DO NOTHING;
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id
+FROM roles r
+JOIN permissions p ON p.code = 'user:impersonate'
+WHERE r.role_name = 'admin'
+ON CONFLICT DO NOTHING;
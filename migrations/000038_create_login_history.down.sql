@@ -0,0 +1,3 @@
+-- Rollback: Track logins
+DROP TABLE IF EXISTS login_history;
+ALTER TABLE users DROP COLUMN IF EXISTS last_login_at;
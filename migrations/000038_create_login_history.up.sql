@@ -0,0 +1,27 @@
+-- Migration: Track logins
+-- Description: Every login attempt (password, phone code, Google, 2FA step) is kept with
+--              the client it came from, for security reviews by users and admins.
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS last_login_at TIMESTAMP WITH TIME ZONE;
+
+CREATE TABLE IF NOT EXISTS login_history (
+    id BIGSERIAL PRIMARY KEY,
+    user_id UUID REFERENCES users(id) ON DELETE CASCADE,
+    email VARCHAR(255) NOT NULL DEFAULT '',
+    method VARCHAR(20) NOT NULL,
+    success BOOLEAN NOT NULL,
+    failure_reason VARCHAR(50) NOT NULL DEFAULT '',
+    ip_address VARCHAR(255) NOT NULL DEFAULT '',
+    user_agent VARCHAR(255) NOT NULL DEFAULT '',
+    device VARCHAR(255) NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_login_history_user_created ON login_history (user_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_login_history_created ON login_history (created_at);
+
+COMMENT ON COLUMN users.last_login_at IS 'Last successful login (tokens issued)';
+COMMENT ON TABLE login_history IS 'Login attempts with their client, for security reviews';
+COMMENT ON COLUMN login_history.user_id IS 'Account of the attempt; NULL when the email matched no account';
+COMMENT ON COLUMN login_history.method IS 'password, phone_otp, google or two_factor (second step)';
+COMMENT ON COLUMN login_history.failure_reason IS 'Why the attempt failed, empty on success';
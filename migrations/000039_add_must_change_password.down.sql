@@ -0,0 +1,3 @@
+-- Rollback: Force password change on next login
+ALTER TABLE users DROP COLUMN IF EXISTS password_changed_at;
+ALTER TABLE users DROP COLUMN IF EXISTS must_change_password;
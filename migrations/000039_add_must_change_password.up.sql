@@ -0,0 +1,10 @@
+-- Migration: Force password change on next login
+-- Description: Admins can require a user to choose a new password; accounts created by
+--              admins (staff, doctors) start with it set. Until the password is changed
+--              the API only allows changing it (and signing out).
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS must_change_password BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS password_changed_at TIMESTAMP WITH TIME ZONE;
+
+COMMENT ON COLUMN users.must_change_password IS 'The user must change the password before using the API; cleared by a password change';
+COMMENT ON COLUMN users.password_changed_at IS 'When the user last changed the password with the change password endpoint';
@@ -0,0 +1,3 @@
+-- Rollback: Index the patient search
+-- The pg_trgm extension is kept, other objects may depend on it
+DROP INDEX IF EXISTS idx_users_full_name_trgm;
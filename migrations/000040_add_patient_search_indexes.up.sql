@@ -0,0 +1,10 @@
+-- Migration: Index the patient search
+-- Description: Staff search patients by exact NIK, exact phone number or part of the
+--              name. NIK and phone number are indexed since 000004; partial name
+--              matches (ILIKE '%...%') need a trigram index.
+
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+CREATE INDEX IF NOT EXISTS idx_users_full_name_trgm ON users USING GIN (full_name gin_trgm_ops);
+
+COMMENT ON INDEX idx_users_full_name_trgm IS 'Partial name matches of the patient search';
@@ -0,0 +1,2 @@
+-- Rollback: Create medical_records table
+DROP TABLE IF EXISTS medical_records;
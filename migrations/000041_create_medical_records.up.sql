@@ -0,0 +1,33 @@
+-- Migration: Create medical_records table
+-- Description: Visit notes written by the doctor of a booking (diagnosis, clinical
+--              notes, vital signs), one record per booking. Readable by that doctor
+--              and the patient only.
+
+CREATE TABLE IF NOT EXISTS medical_records (
+    booking_id UUID PRIMARY KEY REFERENCES bookings(id) ON DELETE RESTRICT,
+    patient_id UUID NOT NULL REFERENCES patient_profiles(user_id) ON DELETE CASCADE,
+    doctor_id UUID NOT NULL REFERENCES doctor_profiles(user_id),
+    schedule_id INTEGER NOT NULL REFERENCES doctor_schedules(id),
+    diagnosis TEXT NOT NULL,
+    clinical_notes TEXT,
+    systolic_bp INTEGER CHECK (systolic_bp BETWEEN 30 AND 300),
+    diastolic_bp INTEGER CHECK (diastolic_bp BETWEEN 10 AND 200),
+    heart_rate INTEGER CHECK (heart_rate BETWEEN 20 AND 300),
+    respiratory_rate INTEGER CHECK (respiratory_rate BETWEEN 1 AND 100),
+    temperature NUMERIC(4,1) CHECK (temperature BETWEEN 25 AND 45),
+    oxygen_saturation INTEGER CHECK (oxygen_saturation BETWEEN 0 AND 100),
+    weight_kg NUMERIC(5,1) CHECK (weight_kg > 0),
+    height_cm NUMERIC(4,1) CHECK (height_cm > 0),
+    version INTEGER NOT NULL DEFAULT 1,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+-- Patient history, newest first
+CREATE INDEX IF NOT EXISTS idx_medical_records_patient_created ON medical_records (patient_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_medical_records_doctor_id ON medical_records (doctor_id);
+
+COMMENT ON TABLE medical_records IS 'Visit notes of a booking, written by its doctor after the patient was called';
+COMMENT ON COLUMN medical_records.doctor_id IS 'Author of the record, the only doctor allowed to update it';
+COMMENT ON COLUMN medical_records.temperature IS 'Body temperature in degrees Celsius';
+COMMENT ON COLUMN medical_records.oxygen_saturation IS 'SpO2 in percent';
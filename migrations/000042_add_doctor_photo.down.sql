@@ -0,0 +1,3 @@
+-- Rollback: Add doctor profile photos
+ALTER TABLE doctor_profiles DROP COLUMN IF EXISTS photo_url;
+ALTER TABLE doctor_profiles DROP COLUMN IF EXISTS photo_key;
@@ -0,0 +1,9 @@
+-- Migration: Add doctor profile photos
+-- Description: Photos are kept in the file storage (local disk or S3-compatible),
+--              the profile links them by URL and keeps the key to delete them.
+
+ALTER TABLE doctor_profiles ADD COLUMN IF NOT EXISTS photo_key VARCHAR(255) NOT NULL DEFAULT '';
+ALTER TABLE doctor_profiles ADD COLUMN IF NOT EXISTS photo_url VARCHAR(1024) NOT NULL DEFAULT '';
+
+COMMENT ON COLUMN doctor_profiles.photo_key IS 'Storage key of the profile photo, empty without photo';
+COMMENT ON COLUMN doctor_profiles.photo_url IS 'Public URL of the profile photo, empty without photo';
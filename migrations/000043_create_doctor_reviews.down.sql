@@ -0,0 +1,2 @@
+-- Rollback: Create doctor_reviews table
+DROP TABLE IF EXISTS doctor_reviews;
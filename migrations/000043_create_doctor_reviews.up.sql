@@ -0,0 +1,19 @@
+-- Migration: Create doctor_reviews table
+-- Description: Patients rate a visit (1-5 stars, optional comment) once their queue
+--              number was called; ratings are averaged per doctor.
+
+CREATE TABLE IF NOT EXISTS doctor_reviews (
+    booking_id UUID PRIMARY KEY REFERENCES bookings(id) ON DELETE CASCADE,
+    patient_id UUID NOT NULL REFERENCES patient_profiles(user_id) ON DELETE CASCADE,
+    doctor_id UUID NOT NULL REFERENCES doctor_profiles(user_id) ON DELETE CASCADE,
+    schedule_id INTEGER NOT NULL REFERENCES doctor_schedules(id) ON DELETE CASCADE,
+    rating SMALLINT NOT NULL CHECK (rating BETWEEN 1 AND 5),
+    comment TEXT,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+-- Review listing of a doctor, newest first (also serves the per-doctor average)
+CREATE INDEX IF NOT EXISTS idx_doctor_reviews_doctor_created ON doctor_reviews (doctor_id, created_at DESC);
+
+COMMENT ON TABLE doctor_reviews IS 'Patient ratings of visits, one per booking';
+COMMENT ON COLUMN doctor_reviews.rating IS 'Stars, 1 (poor) to 5 (excellent)';
@@ -0,0 +1,16 @@
+-- Rollback: Create specializations table
+ALTER TABLE specialization_defaults DROP CONSTRAINT IF EXISTS fk_specialization_defaults_specialization;
+
+ALTER TABLE doctor_profiles ADD COLUMN IF NOT EXISTS specialization VARCHAR(100);
+
+UPDATE doctor_profiles
+SET specialization = specializations.name
+FROM specializations
+WHERE specializations.id = doctor_profiles.specialization_id;
+
+ALTER TABLE doctor_profiles ALTER COLUMN specialization SET NOT NULL;
+ALTER TABLE doctor_profiles DROP COLUMN IF EXISTS specialization_id;
+
+CREATE INDEX IF NOT EXISTS idx_doctor_profiles_specialization ON doctor_profiles(specialization);
+
+DROP TABLE IF EXISTS specializations;
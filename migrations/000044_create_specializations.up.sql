@@ -0,0 +1,84 @@
+-- Migration: Create specializations table
+-- Description: Replaces the free-text doctor_profiles.specialization with a managed
+--              taxonomy. Existing values of doctors and specialization defaults are
+--              backfilled (case-insensitive), doctors without one get General.
+--              Doctors reference the specialization by id and the defaults by name
+--              (renames cascade).
+
+CREATE TABLE IF NOT EXISTS specializations (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(100) NOT NULL UNIQUE,
+    slug VARCHAR(100) NOT NULL UNIQUE,
+    description TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Names differing only in case are the same specialization
+CREATE UNIQUE INDEX IF NOT EXISTS idx_specializations_name_lower ON specializations (LOWER(name));
+
+-- Backfill: one specialization per distinct name, slugs as entity.SpecializationSlug
+-- derives them, numbered when two names give the same slug
+WITH names AS (
+    SELECT MIN(name) AS name
+    FROM (
+        SELECT TRIM(specialization) AS name FROM doctor_profiles
+        UNION
+        SELECT TRIM(specialization) FROM specialization_defaults
+    ) existing
+    WHERE name <> ''
+    GROUP BY LOWER(name)
+), slugs AS (
+    SELECT name, COALESCE(NULLIF(TRIM(BOTH '-' FROM regexp_replace(LOWER(name), '[^a-z0-9]+', '-', 'g')), ''), 'specialization') AS slug
+    FROM names
+), numbered AS (
+    SELECT name, slug, ROW_NUMBER() OVER (PARTITION BY slug ORDER BY name) AS n
+    FROM slugs
+)
+INSERT INTO specializations (name, slug)
+SELECT name, CASE WHEN n = 1 THEN slug ELSE slug || '-' || n END
+FROM numbered
+ON CONFLICT DO NOTHING;
+
+-- Doctors reference the specialization; one in use cannot be deleted
+ALTER TABLE doctor_profiles ADD COLUMN IF NOT EXISTS specialization_id INTEGER REFERENCES specializations(id) ON DELETE RESTRICT;
+
+UPDATE doctor_profiles
+SET specialization_id = specializations.id
+FROM specializations
+WHERE LOWER(specializations.name) = LOWER(TRIM(doctor_profiles.specialization));
+
+-- Doctors with a blank (or otherwise unmatched) specialization fall back to General,
+-- created only when needed
+INSERT INTO specializations (name, slug)
+SELECT 'General', 'general'
+WHERE EXISTS (SELECT 1 FROM doctor_profiles WHERE specialization_id IS NULL)
+ON CONFLICT DO NOTHING;
+
+UPDATE doctor_profiles
+SET specialization_id = (
+    SELECT id FROM specializations
+    WHERE LOWER(name) = 'general' OR slug = 'general'
+    ORDER BY (LOWER(name) = 'general') DESC, id
+    LIMIT 1
+)
+WHERE specialization_id IS NULL;
+
+ALTER TABLE doctor_profiles ALTER COLUMN specialization_id SET NOT NULL;
+ALTER TABLE doctor_profiles DROP COLUMN IF EXISTS specialization;
+
+CREATE INDEX IF NOT EXISTS idx_doctor_profiles_specialization_id ON doctor_profiles(specialization_id);
+
+-- Defaults keep their name key, pointing at the managed name
+UPDATE specialization_defaults
+SET specialization = specializations.name
+FROM specializations
+WHERE LOWER(specializations.name) = LOWER(TRIM(specialization_defaults.specialization));
+
+ALTER TABLE specialization_defaults
+    ADD CONSTRAINT fk_specialization_defaults_specialization
+    FOREIGN KEY (specialization) REFERENCES specializations(name) ON UPDATE CASCADE ON DELETE CASCADE;
+
+COMMENT ON TABLE specializations IS 'Managed medical specializations of doctors';
+COMMENT ON COLUMN specializations.slug IS 'Stable URL and filter key, kept when the specialization is renamed';
+COMMENT ON COLUMN doctor_profiles.specialization_id IS 'Specialization of the doctor';
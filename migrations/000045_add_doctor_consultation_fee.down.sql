@@ -0,0 +1,2 @@
+-- Rollback: Add consultation fee to doctor profiles
+ALTER TABLE doctor_profiles DROP COLUMN IF EXISTS consultation_fee;
@@ -0,0 +1,13 @@
+-- Migration: Add consultation fee to doctor profiles
+-- Description: Each doctor has their own consultation fee (charged by payments),
+--              starting at the default fee of their specialization.
+
+ALTER TABLE doctor_profiles ADD COLUMN IF NOT EXISTS consultation_fee BIGINT NOT NULL DEFAULT 0 CHECK (consultation_fee >= 0);
+
+UPDATE doctor_profiles
+SET consultation_fee = specialization_defaults.fee
+FROM specializations
+JOIN specialization_defaults ON specialization_defaults.specialization = specializations.name
+WHERE specializations.id = doctor_profiles.specialization_id;
+
+COMMENT ON COLUMN doctor_profiles.consultation_fee IS 'Consultation fee in the smallest currency unit';
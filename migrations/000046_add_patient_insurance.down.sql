@@ -0,0 +1,15 @@
+-- Rollback: Add patient insurance
+ALTER TABLE booking_sagas
+    DROP COLUMN IF EXISTS insurance_policy_number,
+    DROP COLUMN IF EXISTS insurance_provider;
+
+ALTER TABLE bookings
+    DROP COLUMN IF EXISTS insurance_policy_number,
+    DROP COLUMN IF EXISTS insurance_provider;
+
+ALTER TABLE patient_profiles
+    DROP CONSTRAINT IF EXISTS chk_patient_insurance_validity,
+    DROP COLUMN IF EXISTS insurance_valid_until,
+    DROP COLUMN IF EXISTS insurance_valid_from,
+    DROP COLUMN IF EXISTS insurance_policy_number,
+    DROP COLUMN IF EXISTS insurance_provider;
@@ -0,0 +1,21 @@
+-- Migration: Add patient insurance
+-- Description: Patients register a health insurance (provider, policy number, validity)
+--              and can attach it to a booking; the booking keeps a copy for claims.
+
+ALTER TABLE patient_profiles
+    ADD COLUMN IF NOT EXISTS insurance_provider VARCHAR(100) NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS insurance_policy_number VARCHAR(50) NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS insurance_valid_from DATE,
+    ADD COLUMN IF NOT EXISTS insurance_valid_until DATE,
+    ADD CONSTRAINT chk_patient_insurance_validity
+        CHECK (insurance_valid_from IS NULL OR insurance_valid_until IS NULL OR insurance_valid_from <= insurance_valid_until);
+
+ALTER TABLE bookings
+    ADD COLUMN IF NOT EXISTS insurance_provider VARCHAR(100) NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS insurance_policy_number VARCHAR(50) NOT NULL DEFAULT '';
+
+ALTER TABLE booking_sagas
+    ADD COLUMN IF NOT EXISTS insurance_provider VARCHAR(100) NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS insurance_policy_number VARCHAR(50) NOT NULL DEFAULT '';
+
+COMMENT ON COLUMN bookings.insurance_provider IS 'Insurance used for the visit, copied from the patient profile (empty = none)';
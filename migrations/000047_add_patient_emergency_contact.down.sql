@@ -0,0 +1,5 @@
+-- Rollback: Add patient emergency contact
+ALTER TABLE patient_profiles
+    DROP COLUMN IF EXISTS emergency_contact_phone,
+    DROP COLUMN IF EXISTS emergency_contact_relationship,
+    DROP COLUMN IF EXISTS emergency_contact_name;
@@ -0,0 +1,10 @@
+-- Migration: Add patient emergency contact
+-- Description: Patients register who to contact in an emergency; doctors and admins
+--              see it in booking details.
+
+ALTER TABLE patient_profiles
+    ADD COLUMN IF NOT EXISTS emergency_contact_name VARCHAR(100) NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS emergency_contact_relationship VARCHAR(50) NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS emergency_contact_phone VARCHAR(20) NOT NULL DEFAULT '';
+
+COMMENT ON COLUMN patient_profiles.emergency_contact_name IS 'Emergency contact of the patient (empty = none)';
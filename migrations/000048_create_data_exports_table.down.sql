@@ -0,0 +1,4 @@
+-- Rollback: Create data_exports table
+DROP INDEX IF EXISTS uq_data_exports_pending;
+DROP INDEX IF EXISTS idx_data_exports_user;
+DROP TABLE IF EXISTS data_exports;
@@ -0,0 +1,24 @@
+-- Migration: Create data_exports table
+-- Description: Personal data exports requested by patients (right of access),
+--              generated by the outbox worker and downloadable for a limited time
+
+CREATE TABLE IF NOT EXISTS data_exports (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    file_key VARCHAR(255),
+    file_size BIGINT NOT NULL DEFAULT 0,
+    last_error TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    completed_at TIMESTAMP WITH TIME ZONE,
+    expires_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX IF NOT EXISTS idx_data_exports_user ON data_exports(user_id, created_at DESC);
+
+-- One export in progress per user, concurrent requests share it
+CREATE UNIQUE INDEX IF NOT EXISTS uq_data_exports_pending ON data_exports(user_id) WHERE status = 'pending';
+
+COMMENT ON TABLE data_exports IS 'Personal data archives requested by users';
+COMMENT ON COLUMN data_exports.status IS 'pending = generating, ready = downloadable, failed = gave up, expired = archive deleted';
+COMMENT ON COLUMN data_exports.file_key IS 'Storage key of the ZIP archive (private prefix, never public)';
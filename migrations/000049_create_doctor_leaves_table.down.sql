@@ -0,0 +1,4 @@
+-- Rollback: Drop doctor leaves table
+DROP INDEX IF EXISTS idx_doctor_schedules_leave_flagged;
+ALTER TABLE doctor_schedules DROP COLUMN IF EXISTS leave_flagged_at;
+DROP TABLE IF EXISTS doctor_leaves;
@@ -0,0 +1,27 @@
+-- Migration: Create doctor leaves table
+-- Description: Leave and absence periods of doctors. Schedules are not generated on
+--              them; existing schedules in a new leave are flagged and closed for booking
+
+CREATE TABLE IF NOT EXISTS doctor_leaves (
+    id SERIAL PRIMARY KEY,
+    doctor_id UUID NOT NULL REFERENCES doctor_profiles(user_id) ON DELETE CASCADE,
+    start_date DATE NOT NULL,
+    end_date DATE NOT NULL,
+    reason TEXT,
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT chk_doctor_leaves_dates CHECK (end_date >= start_date)
+);
+
+CREATE INDEX IF NOT EXISTS idx_doctor_leaves_doctor_dates ON doctor_leaves(doctor_id, start_date, end_date);
+
+ALTER TABLE doctor_schedules ADD COLUMN IF NOT EXISTS leave_flagged_at TIMESTAMP WITH TIME ZONE;
+
+-- Partial index for the leave conflict list
+CREATE INDEX IF NOT EXISTS idx_doctor_schedules_leave_flagged
+    ON doctor_schedules(schedule_date)
+    WHERE leave_flagged_at IS NOT NULL;
+
+COMMENT ON TABLE doctor_leaves IS 'Doctor leave periods (inclusive dates) - schedules are not generated on them';
+COMMENT ON COLUMN doctor_schedules.leave_flagged_at IS 'When the schedule was closed for booking because its doctor took leave';
@@ -0,0 +1,2 @@
+-- Rollback: Add notification subject
+ALTER TABLE notifications DROP COLUMN IF EXISTS subject;
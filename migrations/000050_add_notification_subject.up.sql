@@ -0,0 +1,8 @@
+-- Migration: Add notification subject
+-- Description: Email notifications are sent through SMTP with a subject per event;
+--              other channels ignore it.
+
+ALTER TABLE notifications
+    ADD COLUMN IF NOT EXISTS subject VARCHAR(255) NOT NULL DEFAULT '';
+
+COMMENT ON COLUMN notifications.subject IS 'Email subject (empty = provider default)';
@@ -0,0 +1,4 @@
+-- Rollback: Create device tokens table
+DELETE FROM notifications WHERE channel = 'push';
+ALTER TABLE notifications ALTER COLUMN recipient TYPE VARCHAR(255);
+DROP TABLE IF EXISTS device_tokens;
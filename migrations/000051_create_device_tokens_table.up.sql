@@ -0,0 +1,21 @@
+-- Migration: Create device tokens table
+-- Description: FCM registration tokens of the mobile clients users are signed in on;
+--              booking, queue call and schedule notifications are pushed to them
+
+CREATE TABLE IF NOT EXISTS device_tokens (
+    id SERIAL PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    token VARCHAR(512) NOT NULL,
+    platform VARCHAR(20) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT uq_device_tokens_token UNIQUE (token),
+    CONSTRAINT chk_device_tokens_platform CHECK (platform IN ('android', 'ios', 'web'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_device_tokens_user_id ON device_tokens(user_id);
+
+-- Push notifications are addressed to a device token, longer than phone numbers and emails
+ALTER TABLE notifications ALTER COLUMN recipient TYPE VARCHAR(512);
+
+COMMENT ON TABLE device_tokens IS 'Push tokens of signed-in mobile clients - a token belongs to one user';
@@ -0,0 +1,5 @@
+-- Rollback: Add in-app notification center
+DROP INDEX IF EXISTS idx_notifications_in_app_unread;
+DROP INDEX IF EXISTS idx_notifications_in_app;
+DELETE FROM notifications WHERE channel = 'in_app';
+ALTER TABLE notifications DROP COLUMN IF EXISTS read_at;
@@ -0,0 +1,15 @@
+-- Migration: Add in-app notification center
+-- Description: Booking changes, schedule updates and announcements are also recorded as
+--              in_app notifications, which patients list, mark read and delete in the app
+
+ALTER TABLE notifications ADD COLUMN IF NOT EXISTS read_at TIMESTAMP WITH TIME ZONE;
+
+-- Partial indexes for the notification center and its unread count
+CREATE INDEX IF NOT EXISTS idx_notifications_in_app
+    ON notifications(patient_id, created_at DESC)
+    WHERE channel = 'in_app';
+CREATE INDEX IF NOT EXISTS idx_notifications_in_app_unread
+    ON notifications(patient_id)
+    WHERE channel = 'in_app' AND read_at IS NULL;
+
+COMMENT ON COLUMN notifications.read_at IS 'When the patient read the in-app notification (NULL = unread)';
@@ -0,0 +1,2 @@
+-- Rollback: Create notification_templates table
+DROP TABLE IF EXISTS notification_templates;
@@ -0,0 +1,19 @@
+-- Migration: Create notification_templates table
+-- Description: Admin-editable Go text/template content of notifications per event type,
+--              optionally per channel and locale, rendered in place of the default text
+
+CREATE TABLE IF NOT EXISTS notification_templates (
+    id SERIAL PRIMARY KEY,
+    event_type VARCHAR(100) NOT NULL,
+    channel VARCHAR(20) NOT NULL DEFAULT '',
+    locale VARCHAR(20) NOT NULL DEFAULT '',
+    subject VARCHAR(255) NOT NULL DEFAULT '',
+    body TEXT NOT NULL,
+    updated_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    CONSTRAINT uq_notification_templates_variant UNIQUE (event_type, channel, locale)
+);
+
+COMMENT ON TABLE notification_templates IS 'Notification content per event type, channel and locale (empty = any)';
+COMMENT ON COLUMN notification_templates.body IS 'Go text/template, e.g. Hello {{.PatientName}}, booking {{.BookingCode}}';
@@ -0,0 +1,2 @@
+-- Rollback: Create notification preferences table
+DROP TABLE IF EXISTS notification_preferences;
@@ -0,0 +1,13 @@
+-- Migration: Create notification preferences table
+-- Description: Preferred notification channel of users and their appointment reminder opt-out;
+--              users without a row get reminders on the channel of each notification
+
+CREATE TABLE IF NOT EXISTS notification_preferences (
+    user_id UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    channel VARCHAR(20) NOT NULL DEFAULT '',
+    reminders BOOLEAN NOT NULL DEFAULT TRUE,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT chk_notification_preferences_channel CHECK (channel IN ('', 'sms', 'whatsapp', 'email'))
+);
+
+COMMENT ON TABLE notification_preferences IS 'How users want to be notified - missing row = defaults';
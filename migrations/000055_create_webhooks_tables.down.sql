@@ -0,0 +1,3 @@
+-- Rollback: Create webhook subscriptions and deliveries tables
+DROP TABLE IF EXISTS webhook_deliveries;
+DROP TABLE IF EXISTS webhook_subscriptions;
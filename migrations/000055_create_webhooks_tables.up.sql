@@ -0,0 +1,53 @@
+-- Migration: Create webhook subscriptions and deliveries tables
+-- Description: External endpoints subscribed to booking and schedule events; every outbox
+--              event is fanned out as one signed delivery per active subscription
+
+CREATE TABLE IF NOT EXISTS webhook_subscriptions (
+    id SERIAL PRIMARY KEY,
+    url VARCHAR(500) NOT NULL,
+    secret VARCHAR(100) NOT NULL,
+    event_types JSONB NOT NULL DEFAULT '[]',
+    description VARCHAR(255),
+    status VARCHAR(20) NOT NULL DEFAULT 'active',
+    consecutive_failures INTEGER NOT NULL DEFAULT 0,
+    paused_at TIMESTAMP WITH TIME ZONE,
+    pause_reason VARCHAR(255),
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT chk_webhook_subscriptions_status CHECK (status IN ('active', 'paused'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_webhook_subscriptions_event_types
+    ON webhook_subscriptions USING GIN (event_types);
+
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+    id UUID PRIMARY KEY,
+    subscription_id INTEGER NOT NULL REFERENCES webhook_subscriptions(id) ON DELETE CASCADE,
+    event_id BIGINT,
+    event_type VARCHAR(100) NOT NULL,
+    payload JSONB NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    attempts INTEGER NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    response_status INTEGER,
+    response_body TEXT,
+    last_error TEXT,
+    delivered_at TIMESTAMP WITH TIME ZONE,
+    failed_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT uq_webhook_deliveries_event UNIQUE (subscription_id, event_id),
+    CONSTRAINT chk_webhook_deliveries_status CHECK (status IN ('pending', 'succeeded', 'failed'))
+);
+
+-- Delivery history per subscription and the worker's due scan
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_subscription
+    ON webhook_deliveries(subscription_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_due
+    ON webhook_deliveries(next_attempt_at)
+    WHERE status = 'pending';
+
+COMMENT ON TABLE webhook_subscriptions IS 'External endpoints receiving booking and schedule events as signed HTTP POSTs';
+COMMENT ON COLUMN webhook_subscriptions.secret IS 'HMAC-SHA256 key of the X-Webhook-Signature header';
+COMMENT ON TABLE webhook_deliveries IS 'One event per subscription, retried with exponential backoff';
@@ -0,0 +1,6 @@
+-- Rollback: Add notification attempt log and dead letters
+DROP INDEX IF EXISTS idx_notifications_dead_letter;
+UPDATE notifications SET status = 'failed' WHERE status = 'dead_letter';
+ALTER TABLE notifications DROP COLUMN IF EXISTS redriven_at;
+DROP TABLE IF EXISTS notification_attempts;
+COMMENT ON COLUMN notifications.status IS 'pending = waiting/retrying, sent = accepted by provider, delivered = confirmed by provider callback, failed = rejected, undeliverable or gave up after max attempts';
@@ -0,0 +1,37 @@
+-- Migration: Add notification attempt log and dead letters
+-- Description: Every send of a notification is recorded with its outcome. Notifications
+--              rejected by the provider or out of attempts become dead letters, which
+--              admins inspect and re-drive
+
+CREATE TABLE IF NOT EXISTS notification_attempts (
+    id BIGSERIAL PRIMARY KEY,
+    notification_id UUID NOT NULL REFERENCES notifications(id) ON DELETE CASCADE,
+    attempt INTEGER NOT NULL,
+    channel VARCHAR(20) NOT NULL,
+    recipient VARCHAR(512) NOT NULL,
+    provider VARCHAR(50) NOT NULL,
+    succeeded BOOLEAN NOT NULL,
+    provider_message_id VARCHAR(255),
+    error TEXT,
+    duration_ms BIGINT NOT NULL DEFAULT 0,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_notification_attempts_notification
+    ON notification_attempts(notification_id, created_at);
+
+ALTER TABLE notifications ADD COLUMN IF NOT EXISTS redriven_at TIMESTAMP WITH TIME ZONE;
+
+-- Sends the worker gave up on were failed until now; provider-reported failures were sent
+-- first and inactive accounts were never attempted
+UPDATE notifications SET status = 'dead_letter'
+WHERE status = 'failed' AND attempts > 0 AND sent_at IS NULL;
+
+-- Index for the dead letter list
+CREATE INDEX IF NOT EXISTS idx_notifications_dead_letter
+    ON notifications(failed_at DESC)
+    WHERE status = 'dead_letter';
+
+COMMENT ON TABLE notification_attempts IS 'Every send of a notification to its provider and the outcome';
+COMMENT ON COLUMN notifications.status IS 'pending = waiting/retrying, sent = accepted by provider, delivered = confirmed by provider callback, failed = reported undeliverable or inactive account, dead_letter = rejected or gave up after max attempts';
+COMMENT ON COLUMN notifications.redriven_at IS 'Last time an admin sent the dead letter again';
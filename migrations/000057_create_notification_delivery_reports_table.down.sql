@@ -0,0 +1,5 @@
+-- Rollback: Create notification delivery reports table
+DROP INDEX IF EXISTS idx_notifications_patient;
+DROP TABLE IF EXISTS notification_delivery_reports;
+UPDATE notifications SET status = 'failed' WHERE status = 'bounced';
+COMMENT ON COLUMN notifications.status IS 'pending = waiting/retrying, sent = accepted by provider, delivered = confirmed by provider callback, failed = reported undeliverable or inactive account, dead_letter = rejected or gave up after max attempts';
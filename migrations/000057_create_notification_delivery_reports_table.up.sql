@@ -0,0 +1,23 @@
+-- Migration: Create notification delivery reports table
+-- Description: Every provider callback is kept next to the send attempts, so support can
+--              tell whether a notification was queued, sent, delivered or bounced
+
+CREATE TABLE IF NOT EXISTS notification_delivery_reports (
+    id BIGSERIAL PRIMARY KEY,
+    notification_id UUID NOT NULL REFERENCES notifications(id) ON DELETE CASCADE,
+    provider VARCHAR(50) NOT NULL,
+    status VARCHAR(20) NOT NULL,
+    error TEXT,
+    applied BOOLEAN NOT NULL,
+    received_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_notification_delivery_reports_notification
+    ON notification_delivery_reports(notification_id, received_at);
+
+-- Index for the notification history of a patient (admin support)
+CREATE INDEX IF NOT EXISTS idx_notifications_patient
+    ON notifications(patient_id, created_at DESC);
+
+COMMENT ON TABLE notification_delivery_reports IS 'Provider delivery callbacks, applied = false when the notification already had a final status';
+COMMENT ON COLUMN notifications.status IS 'pending = queued/retrying, sent = accepted by provider, delivered = confirmed by provider callback, failed = reported undeliverable or inactive account, bounced = recipient address unknown or refused, dead_letter = rejected or gave up after max attempts';
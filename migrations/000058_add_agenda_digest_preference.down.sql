@@ -0,0 +1,2 @@
+-- Rollback: Add agenda digest preference
+ALTER TABLE notification_preferences DROP COLUMN IF EXISTS agenda_digest;
@@ -0,0 +1,6 @@
+-- Migration: Add agenda digest preference
+-- Description: Doctors get the agenda of their day by email every morning unless they opt out
+
+ALTER TABLE notification_preferences ADD COLUMN IF NOT EXISTS agenda_digest BOOLEAN NOT NULL DEFAULT TRUE;
+
+COMMENT ON COLUMN notification_preferences.agenda_digest IS 'Daily agenda email of doctors';
@@ -0,0 +1,8 @@
+-- Rollback: Create payments table
+-- Enum values cannot be dropped, unpaid bookings are cancelled instead
+UPDATE bookings SET status = 'cancelled' WHERE status = 'awaiting_payment';
+ALTER TABLE booking_sagas DROP COLUMN IF EXISTS awaiting_payment;
+DROP INDEX IF EXISTS idx_payments_patient;
+DROP INDEX IF EXISTS idx_payments_booking;
+DROP TABLE IF EXISTS payments;
+COMMENT ON COLUMN bookings.status IS 'pending = awaiting confirmation, confirmed = booking active, cancelled = booking cancelled';
@@ -0,0 +1,33 @@
+-- Migration: Create payments table
+-- Description: Consultation fees paid through the payment gateway. Bookings of doctors
+--              with a fee wait in awaiting_payment until the gateway reports the payment
+
+ALTER TYPE booking_status ADD VALUE IF NOT EXISTS 'awaiting_payment' BEFORE 'confirmed';
+
+CREATE TABLE IF NOT EXISTS payments (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    booking_id UUID NOT NULL REFERENCES bookings(id) ON DELETE RESTRICT,
+    patient_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    gateway VARCHAR(20) NOT NULL,
+    amount BIGINT NOT NULL,
+    currency VARCHAR(3) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    gateway_reference VARCHAR(255),
+    payment_url VARCHAR(1024),
+    failure_reason TEXT,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    paid_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_payments_booking ON payments(booking_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_payments_patient ON payments(patient_id);
+
+-- Sagas interrupted before the booking insert create it awaiting payment on recovery
+ALTER TABLE booking_sagas ADD COLUMN IF NOT EXISTS awaiting_payment BOOLEAN NOT NULL DEFAULT FALSE;
+
+COMMENT ON TABLE payments IS 'Consultation fees of bookings charged through the payment gateway';
+COMMENT ON COLUMN payments.status IS 'pending = waiting for the patient, paid = confirmed by the gateway, failed = declined or not opened, expired = not paid in time, cancelled = booking cancelled first';
+COMMENT ON COLUMN payments.amount IS 'Smallest currency unit';
+COMMENT ON COLUMN bookings.status IS 'pending = booked, awaiting_payment = fee not paid yet, confirmed = booking active, cancelled = booking cancelled';
@@ -0,0 +1,9 @@
+-- Rollback: Create invoices tables
+ALTER TABLE payments DROP COLUMN IF EXISTS admin_fee;
+DROP INDEX IF EXISTS idx_invoice_items_invoice;
+DROP TABLE IF EXISTS invoice_items;
+DROP INDEX IF EXISTS idx_invoices_doctor;
+DROP INDEX IF EXISTS idx_invoices_patient;
+DROP INDEX IF EXISTS idx_invoices_issued_at;
+DROP TABLE IF EXISTS invoices;
+DROP TABLE IF EXISTS invoice_sequences;
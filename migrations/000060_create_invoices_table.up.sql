@@ -0,0 +1,54 @@
+-- Migration: Create invoices tables
+-- Description: Invoices of paid and completed bookings, numbered per year without gaps,
+--              with their line items (consultation fee, admin fee)
+
+-- Last invoice number of each year, incremented in the transaction issuing the invoice
+CREATE TABLE IF NOT EXISTS invoice_sequences (
+    year INT PRIMARY KEY,
+    last_number BIGINT NOT NULL DEFAULT 0
+);
+
+-- Issued invoices are never deleted, deleting their booking or parties is refused so the
+-- numbering keeps no gaps
+CREATE TABLE IF NOT EXISTS invoices (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    number VARCHAR(50) NOT NULL,
+    booking_id UUID NOT NULL REFERENCES bookings(id) ON DELETE RESTRICT,
+    payment_id UUID REFERENCES payments(id) ON DELETE SET NULL,
+    patient_id UUID NOT NULL REFERENCES users(id) ON DELETE RESTRICT,
+    doctor_id UUID NOT NULL REFERENCES users(id) ON DELETE RESTRICT,
+    patient_name VARCHAR(255) NOT NULL,
+    doctor_name VARCHAR(255) NOT NULL,
+    booking_code VARCHAR(50) NOT NULL,
+    service_date DATE NOT NULL,
+    currency VARCHAR(3) NOT NULL,
+    total BIGINT NOT NULL,
+    status VARCHAR(20) NOT NULL,
+    issued_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT uq_invoices_number UNIQUE (number),
+    CONSTRAINT uq_invoices_booking UNIQUE (booking_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_invoices_issued_at ON invoices(issued_at DESC);
+CREATE INDEX IF NOT EXISTS idx_invoices_patient ON invoices(patient_id);
+CREATE INDEX IF NOT EXISTS idx_invoices_doctor ON invoices(doctor_id);
+
+CREATE TABLE IF NOT EXISTS invoice_items (
+    id BIGSERIAL PRIMARY KEY,
+    invoice_id UUID NOT NULL REFERENCES invoices(id) ON DELETE CASCADE,
+    type VARCHAR(30) NOT NULL,
+    description VARCHAR(255) NOT NULL,
+    amount BIGINT NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_invoice_items_invoice ON invoice_items(invoice_id);
+
+-- Admin fee charged with the consultation fee of gateway payments
+ALTER TABLE payments ADD COLUMN IF NOT EXISTS admin_fee BIGINT NOT NULL DEFAULT 0;
+
+COMMENT ON TABLE invoices IS 'Invoices of paid and completed bookings, names and amounts copied when issued';
+COMMENT ON COLUMN invoices.status IS 'paid = paid through the payment gateway, issued = settled at the clinic';
+COMMENT ON COLUMN invoices.total IS 'Sum of the items, smallest currency unit';
+COMMENT ON COLUMN invoice_items.type IS 'consultation_fee or admin_fee';
+COMMENT ON COLUMN payments.admin_fee IS 'Part of amount, the rest is the consultation fee';
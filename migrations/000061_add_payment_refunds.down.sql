@@ -0,0 +1,11 @@
+-- Rollback: Add payment refunds
+UPDATE invoices SET status = 'paid' WHERE status = 'refunded';
+COMMENT ON COLUMN invoices.status IS 'paid = paid through the payment gateway, issued = settled at the clinic';
+DROP INDEX IF EXISTS idx_payments_refund_pending;
+ALTER TABLE payments DROP COLUMN IF EXISTS refunded_at;
+ALTER TABLE payments DROP COLUMN IF EXISTS refund_requested_at;
+ALTER TABLE payments DROP COLUMN IF EXISTS refund_failure_reason;
+ALTER TABLE payments DROP COLUMN IF EXISTS refund_reference;
+ALTER TABLE payments DROP COLUMN IF EXISTS refund_reason;
+ALTER TABLE payments DROP COLUMN IF EXISTS refund_amount;
+ALTER TABLE payments DROP COLUMN IF EXISTS refund_status;
@@ -0,0 +1,17 @@
+-- Migration: Add payment refunds
+-- Description: Refund state of paid payments, refunded when the booking is cancelled
+--              within the cancellation policy or paid after it was closed
+
+ALTER TABLE payments ADD COLUMN IF NOT EXISTS refund_status VARCHAR(20) NOT NULL DEFAULT '';
+ALTER TABLE payments ADD COLUMN IF NOT EXISTS refund_amount BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE payments ADD COLUMN IF NOT EXISTS refund_reason VARCHAR(255);
+ALTER TABLE payments ADD COLUMN IF NOT EXISTS refund_reference VARCHAR(255);
+ALTER TABLE payments ADD COLUMN IF NOT EXISTS refund_failure_reason TEXT;
+ALTER TABLE payments ADD COLUMN IF NOT EXISTS refund_requested_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE payments ADD COLUMN IF NOT EXISTS refunded_at TIMESTAMP WITH TIME ZONE;
+
+-- Refunds still waiting for the gateway
+CREATE INDEX IF NOT EXISTS idx_payments_refund_pending ON payments(refund_requested_at) WHERE refund_status = 'pending';
+
+COMMENT ON COLUMN payments.refund_status IS 'empty = not refunded, pending = waiting for the gateway, succeeded = returned to the payer, failed = rejected by the gateway';
+COMMENT ON COLUMN invoices.status IS 'paid = paid through the payment gateway, issued = settled at the clinic, refunded = payment refunded';
@@ -0,0 +1,3 @@
+-- Rollback: Add audit log event id
+ALTER TABLE audit_logs DROP CONSTRAINT IF EXISTS uq_audit_logs_event;
+ALTER TABLE audit_logs DROP COLUMN IF EXISTS event_id;
@@ -0,0 +1,7 @@
+-- Migration: Add audit log event id
+-- Description: Domain event an audit log was recorded for, so a redelivered event is audited once
+
+ALTER TABLE audit_logs ADD COLUMN IF NOT EXISTS event_id VARCHAR(64);
+ALTER TABLE audit_logs ADD CONSTRAINT uq_audit_logs_event UNIQUE (event_id);
+
+COMMENT ON COLUMN audit_logs.event_id IS 'Domain event the entry was recorded for, NULL for actions logged directly';
@@ -8,6 +8,16 @@ INSERT INTO roles (role_name, description) VALUES
     ('patient', 'Patient who books appointments')
 ON CONFLICT (role_name) DO NOTHING;
 
+-- Grant permissions to the built-in roles (permissions are created by migration 000033)
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id
+FROM roles r
+JOIN permissions p ON
+    (r.role_name = 'admin' AND p.code NOT LIKE 'portal:%')
+    OR (r.role_name = 'doctor' AND p.code = 'portal:doctor')
+    OR (r.role_name = 'patient' AND p.code = 'portal:patient')
+ON CONFLICT DO NOTHING;
+
 -- Insert admin user
 -- Password: admin123 (bcrypt hashed with cost 10)
 -- Note: In production, change this password immediately
@@ -0,0 +1,125 @@
+// Package captcha verifies the response tokens of CAPTCHA widgets (reCAPTCHA, hCaptcha,
+// Cloudflare Turnstile) with the siteverify API of the provider.
+package captcha
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"go-template-clean-architecture/config"
+)
+
+// Supported providers, set with CAPTCHA_PROVIDER
+const (
+	ProviderRecaptcha = "recaptcha"
+	ProviderHCaptcha  = "hcaptcha"
+	ProviderTurnstile = "turnstile"
+)
+
+// The providers share the siteverify request and response format
+var verifyURLs = map[string]string{
+	ProviderRecaptcha: "https://www.google.com/recaptcha/api/siteverify",
+	ProviderHCaptcha:  "https://api.hcaptcha.com/siteverify",
+	ProviderTurnstile: "https://challenges.cloudflare.com/turnstile/v0/siteverify",
+}
+
+const requestTimeout = 5 * time.Second
+
+var (
+	// ErrInvalidToken means the token is missing, expired, reused or scored as a bot
+	ErrInvalidToken = errors.New("captcha verification failed")
+	// ErrUnavailable means the provider could not be asked
+	ErrUnavailable = errors.New("captcha provider unavailable")
+)
+
+// Verifier checks CAPTCHA tokens. It is disabled (accepts every request) when no provider is configured.
+type Verifier struct {
+	config     config.CaptchaConfig
+	verifyURL  string
+	httpClient *http.Client
+}
+
+// NewVerifier creates a Verifier, failing on an unknown provider or a missing secret key
+func NewVerifier(cfg config.CaptchaConfig) (*Verifier, error) {
+	v := &Verifier{
+		config:     cfg,
+		httpClient: &http.Client{Timeout: requestTimeout},
+	}
+	if cfg.Provider == "" {
+		return v, nil
+	}
+
+	verifyURL, ok := verifyURLs[cfg.Provider]
+	if !ok {
+		return nil, fmt.Errorf("unknown captcha provider %q, use recaptcha, hcaptcha or turnstile", cfg.Provider)
+	}
+	if cfg.SecretKey == "" {
+		return nil, fmt.Errorf("captcha provider %s needs CAPTCHA_SECRET_KEY", cfg.Provider)
+	}
+	v.verifyURL = verifyURL
+	return v, nil
+}
+
+// Enabled reports whether requests must pass a CAPTCHA
+func (v *Verifier) Enabled() bool {
+	return v.verifyURL != ""
+}
+
+// Verify checks the token of the widget. remoteIP is optional and lets the provider
+// compare the address that solved the challenge.
+func (v *Verifier) Verify(ctx context.Context, token, remoteIP string) error {
+	if !v.Enabled() {
+		return nil
+	}
+	token = strings.TrimSpace(token)
+	if token == "" {
+		return ErrInvalidToken
+	}
+
+	form := url.Values{}
+	form.Set("secret", v.config.SecretKey)
+	form.Set("response", token)
+	if remoteIP != "" {
+		form.Set("remoteip", remoteIP)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, v.verifyURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := v.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrUnavailable, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("%w: %s returned %d", ErrUnavailable, req.URL.Host, resp.StatusCode)
+	}
+
+	var result struct {
+		Success    bool     `json:"success"`
+		Score      *float64 `json:"score"` // reCAPTCHA v3 only
+		ErrorCodes []string `json:"error-codes"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return fmt.Errorf("%w: %v", ErrUnavailable, err)
+	}
+
+	if !result.Success {
+		return ErrInvalidToken
+	}
+	if result.Score != nil && *result.Score < v.config.MinScore {
+		return ErrInvalidToken
+	}
+	return nil
+}
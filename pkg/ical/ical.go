@@ -0,0 +1,97 @@
+// Package ical writes iCalendar (RFC 5545) documents for calendar subscriptions.
+//
+// Only what the schedule feeds need is supported: a VCALENDAR with VEVENTs whose
+// times are written in UTC, so no VTIMEZONE component is required.
+package ical
+
+import (
+	"strings"
+	"time"
+)
+
+const (
+	utcFormat = "20060102T150405Z"
+
+	// Lines longer than 75 octets must be folded (RFC 5545 section 3.1)
+	maxLineOctets = 75
+)
+
+// Calendar is a VCALENDAR document
+type Calendar struct {
+	ProdID string // Product identifier, e.g. "-//Clinic//Doctor Schedules//EN"
+	Name   string // Display name shown by calendar apps (X-WR-CALNAME)
+	Events []Event
+}
+
+// Event is a VEVENT
+type Event struct {
+	UID         string // Globally unique and stable across feed refreshes
+	Start       time.Time
+	End         time.Time
+	Stamp       time.Time // Last modification of the event
+	Summary     string
+	Description string
+	Location    string
+}
+
+// Marshal renders the calendar with CRLF line endings, escaped text and folded lines
+func (c *Calendar) Marshal() []byte {
+	var b strings.Builder
+
+	writeLine(&b, "BEGIN:VCALENDAR")
+	writeLine(&b, "VERSION:2.0")
+	writeLine(&b, "PRODID:"+escapeText(c.ProdID))
+	writeLine(&b, "CALSCALE:GREGORIAN")
+	writeLine(&b, "METHOD:PUBLISH")
+	if c.Name != "" {
+		writeLine(&b, "X-WR-CALNAME:"+escapeText(c.Name))
+	}
+
+	for _, event := range c.Events {
+		writeLine(&b, "BEGIN:VEVENT")
+		writeLine(&b, "UID:"+escapeText(event.UID))
+		writeLine(&b, "DTSTAMP:"+event.Stamp.UTC().Format(utcFormat))
+		writeLine(&b, "DTSTART:"+event.Start.UTC().Format(utcFormat))
+		writeLine(&b, "DTEND:"+event.End.UTC().Format(utcFormat))
+		writeLine(&b, "SUMMARY:"+escapeText(event.Summary))
+		if event.Description != "" {
+			writeLine(&b, "DESCRIPTION:"+escapeText(event.Description))
+		}
+		if event.Location != "" {
+			writeLine(&b, "LOCATION:"+escapeText(event.Location))
+		}
+		writeLine(&b, "END:VEVENT")
+	}
+
+	writeLine(&b, "END:VCALENDAR")
+	return []byte(b.String())
+}
+
+// escapeText escapes a TEXT value (RFC 5545 section 3.3.11)
+func escapeText(value string) string {
+	return strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\r\n", `\n`,
+		"\n", `\n`,
+	).Replace(value)
+}
+
+// writeLine writes a content line, folding it into 75-octet chunks without splitting UTF-8 characters
+func writeLine(b *strings.Builder, line string) {
+	limit := maxLineOctets
+	for len(line) > limit {
+		cut := limit
+		// Step back to the start of a UTF-8 character
+		for cut > 0 && line[cut]&0xC0 == 0x80 {
+			cut--
+		}
+		b.WriteString(line[:cut])
+		b.WriteString("\r\n ")
+		line = line[cut:]
+		limit = maxLineOctets - 1 // Continuation lines start with a space
+	}
+	b.WriteString(line)
+	b.WriteString("\r\n")
+}
@@ -0,0 +1,101 @@
+// Package imaging validates uploaded photos and scales them down with the standard
+// library codecs. Re-encoding also drops metadata such as EXIF location tags.
+package imaging
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/jpeg"
+	_ "image/png" // PNG decoder
+)
+
+// ContentType is the type of the images produced
+const ContentType = "image/jpeg"
+
+const (
+	// maxPixels guards against decompression bombs (small files of huge images)
+	maxPixels = 40_000_000
+
+	// jpegQuality balances size and quality of photos
+	jpegQuality = 85
+)
+
+var (
+	// ErrUnsupportedFormat means the data is not a JPEG or PNG image
+	ErrUnsupportedFormat = errors.New("image must be a JPEG or PNG")
+	// ErrTooLarge means the image has more than maxPixels pixels
+	ErrTooLarge = errors.New("image dimensions are too large")
+)
+
+// Square returns the image center-cropped to a square of at most size pixels, as a
+// JPEG. Smaller images are cropped but not scaled up; transparency becomes white.
+func Square(data []byte, size int) ([]byte, error) {
+	config, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil || (format != "jpeg" && format != "png") {
+		return nil, ErrUnsupportedFormat
+	}
+	if config.Width*config.Height > maxPixels {
+		return nil, ErrTooLarge
+	}
+
+	src, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrUnsupportedFormat
+	}
+
+	// Centered square crop, flattened on white
+	bounds := src.Bounds()
+	side := min(bounds.Dx(), bounds.Dy())
+	origin := image.Pt(bounds.Min.X+(bounds.Dx()-side)/2, bounds.Min.Y+(bounds.Dy()-side)/2)
+	cropped := image.NewRGBA(image.Rect(0, 0, side, side))
+	draw.Draw(cropped, cropped.Bounds(), image.NewUniform(color.White), image.Point{}, draw.Src)
+	draw.Draw(cropped, cropped.Bounds(), src, origin, draw.Over)
+
+	var out image.Image = cropped
+	if side > size {
+		out = scaleDown(cropped, size)
+	}
+
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, out, &jpeg.Options{Quality: jpegQuality}); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// scaleDown resizes a square image to size x size, averaging the source pixels
+// covered by each target pixel (box filter)
+func scaleDown(src *image.RGBA, size int) *image.RGBA {
+	side := src.Bounds().Dx()
+	dst := image.NewRGBA(image.Rect(0, 0, size, size))
+
+	for y := 0; y < size; y++ {
+		y0, y1 := y*side/size, (y+1)*side/size
+		for x := 0; x < size; x++ {
+			x0, x1 := x*side/size, (x+1)*side/size
+
+			var r, g, b, a, n uint32
+			for sy := y0; sy < y1; sy++ {
+				row := src.Pix[sy*src.Stride:]
+				for sx := x0; sx < x1; sx++ {
+					p := row[sx*4 : sx*4+4]
+					r += uint32(p[0])
+					g += uint32(p[1])
+					b += uint32(p[2])
+					a += uint32(p[3])
+					n++
+				}
+			}
+
+			i := dst.PixOffset(x, y)
+			dst.Pix[i] = uint8(r / n)
+			dst.Pix[i+1] = uint8(g / n)
+			dst.Pix[i+2] = uint8(b / n)
+			dst.Pix[i+3] = uint8(a / n)
+		}
+	}
+	return dst
+}
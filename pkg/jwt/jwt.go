@@ -1,7 +1,13 @@
 package jwt
 
 import (
+	"crypto/rsa"
+	"encoding/base64"
 	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"sort"
 	"time"
 
 	"go-template-clean-architecture/config"
@@ -17,31 +23,166 @@ const (
 	RefreshToken TokenType = "refresh"
 )
 
+// Signing algorithms (JWT_ALGORITHM)
+const (
+	AlgorithmHS256 = "HS256"
+	AlgorithmRS256 = "RS256"
+)
+
+// minRSAKeyBits is the smallest accepted signing key
+const minRSAKeyBits = 2048
+
 type Claims struct {
 	UserID    uuid.UUID `json:"user_id"`
 	Email     string    `json:"email"`
 	RoleID    int       `json:"role_id"`
 	TokenType TokenType `json:"token_type"`
 	TokenID   string    `json:"token_id"`
+	FamilyID  string    `json:"family_id,omitempty"` // Refresh tokens: shared by all rotations of one sign-in
+	// Access tokens: the role name and its permission codes when issued, so permission
+	// checks need no lookup. RoleName is empty in tokens without embedded permissions.
+	RoleName    string   `json:"role_name,omitempty"`
+	Permissions []string `json:"permissions,omitempty"`
+	// Refresh tokens: signed in with "remember me", kept on rotation
+	RememberMe bool `json:"remember_me,omitempty"`
+	// Impersonation tokens: the admin acting as UserID, nil for the user's own tokens
+	ImpersonatorID *uuid.UUID `json:"impersonator_id,omitempty"`
 	jwt.RegisteredClaims
 }
 
 type JWTService struct {
 	config config.JWTConfig
+	keys   map[string]*rsa.PrivateKey // RS256 keys by key ID, all verify
+}
+
+// JWK is the public part of an RSA signing key (RFC 7517)
+type JWK struct {
+	KeyType   string `json:"kty"`
+	Use       string `json:"use"`
+	Algorithm string `json:"alg"`
+	KeyID     string `json:"kid"`
+	Modulus   string `json:"n"`
+	Exponent  string `json:"e"`
+}
+
+// JWKS is the key set other services verify tokens with
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+// NewJWTService creates the token service. With RS256 the signing keys are loaded
+// from their PEM files; the active key must be one of them.
+func NewJWTService(cfg config.JWTConfig) (*JWTService, error) {
+	service := &JWTService{config: cfg, keys: map[string]*rsa.PrivateKey{}}
+
+	switch cfg.Algorithm {
+	case AlgorithmHS256:
+		if cfg.Secret == "" {
+			return nil, errors.New("JWT_SECRET is required for HS256")
+		}
+	case AlgorithmRS256:
+		for keyID, path := range cfg.SigningKeys {
+			key, err := loadRSAKey(path)
+			if err != nil {
+				return nil, fmt.Errorf("load signing key %q: %w", keyID, err)
+			}
+			service.keys[keyID] = key
+		}
+		if _, ok := service.keys[cfg.ActiveKeyID]; !ok {
+			return nil, fmt.Errorf("active signing key %q is not configured", cfg.ActiveKeyID)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported JWT algorithm %q", cfg.Algorithm)
+	}
+
+	return service, nil
+}
+
+func loadRSAKey(path string) (*rsa.PrivateKey, error) {
+	pemBytes, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	key, err := jwt.ParseRSAPrivateKeyFromPEM(pemBytes)
+	if err != nil {
+		return nil, err
+	}
+	if key.N.BitLen() < minRSAKeyBits {
+		return nil, fmt.Errorf("key has %d bits, at least %d required", key.N.BitLen(), minRSAKeyBits)
+	}
+	return key, nil
+}
+
+// sign signs the claims with the configured algorithm; RS256 tokens name their key (kid)
+func (s *JWTService) sign(claims Claims) (string, error) {
+	if s.config.Algorithm == AlgorithmRS256 {
+		token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+		token.Header["kid"] = s.config.ActiveKeyID
+		return token.SignedString(s.keys[s.config.ActiveKeyID])
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	return token.SignedString([]byte(s.config.Secret))
+}
+
+// verificationKey returns the key a token was signed with. RS256 tokens are verified
+// with the key of their kid, so tokens of a rotated-out key keep working while it is
+// configured. HS256 tokens are accepted while JWT_SECRET is set, to switch to RS256
+// without signing everyone out.
+func (s *JWTService) verificationKey(token *jwt.Token) (interface{}, error) {
+	switch token.Method.(type) {
+	case *jwt.SigningMethodRSA:
+		keyID, _ := token.Header["kid"].(string)
+		key, ok := s.keys[keyID]
+		if !ok {
+			return nil, errors.New("unknown signing key")
+		}
+		return &key.PublicKey, nil
+	case *jwt.SigningMethodHMAC:
+		if s.config.Secret == "" {
+			return nil, errors.New("invalid signing method")
+		}
+		return []byte(s.config.Secret), nil
+	default:
+		return nil, errors.New("invalid signing method")
+	}
 }
 
-func NewJWTService(cfg config.JWTConfig) *JWTService {
-	return &JWTService{config: cfg}
+// JWKS returns the public keys of the RS256 signing keys, ordered by key ID.
+// Empty with HS256: the shared secret is never published.
+func (s *JWTService) JWKS() JWKS {
+	keyIDs := make([]string, 0, len(s.keys))
+	for keyID := range s.keys {
+		keyIDs = append(keyIDs, keyID)
+	}
+	sort.Strings(keyIDs)
+
+	set := JWKS{Keys: make([]JWK, 0, len(keyIDs))}
+	for _, keyID := range keyIDs {
+		public := s.keys[keyID].PublicKey
+		set.Keys = append(set.Keys, JWK{
+			KeyType:   "RSA",
+			Use:       "sig",
+			Algorithm: AlgorithmRS256,
+			KeyID:     keyID,
+			Modulus:   base64.RawURLEncoding.EncodeToString(public.N.Bytes()),
+			Exponent:  base64.RawURLEncoding.EncodeToString(big.NewInt(int64(public.E)).Bytes()),
+		})
+	}
+	return set
 }
 
-func (s *JWTService) GenerateAccessToken(userID uuid.UUID, email string, roleID int) (string, string, error) {
+// GenerateAccessToken issues an access token carrying the role and its permissions
+func (s *JWTService) GenerateAccessToken(userID uuid.UUID, email string, roleID int, roleName string, permissions []string) (string, string, error) {
 	tokenID := uuid.New().String()
 	claims := Claims{
-		UserID:    userID,
-		Email:     email,
-		RoleID:    roleID,
-		TokenType: AccessToken,
-		TokenID:   tokenID,
+		UserID:      userID,
+		Email:       email,
+		RoleID:      roleID,
+		RoleName:    roleName,
+		Permissions: permissions,
+		TokenType:   AccessToken,
+		TokenID:     tokenID,
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(time.Now().Add(s.config.AccessExpiry)),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
@@ -49,8 +190,7 @@ func (s *JWTService) GenerateAccessToken(userID uuid.UUID, email string, roleID
 		},
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	signedToken, err := token.SignedString([]byte(s.config.Secret))
+	signedToken, err := s.sign(claims)
 	if err != nil {
 		return "", "", err
 	}
@@ -58,23 +198,54 @@ func (s *JWTService) GenerateAccessToken(userID uuid.UUID, email string, roleID
 	return signedToken, tokenID, nil
 }
 
-func (s *JWTService) GenerateRefreshToken(userID uuid.UUID, email string, roleID int) (string, string, error) {
+// GenerateRefreshToken issues a refresh token of the token family (one per sign-in, kept on rotation).
+// Remember-me tokens live for RememberMeExpiry instead of RefreshExpiry.
+func (s *JWTService) GenerateRefreshToken(userID uuid.UUID, email string, roleID int, familyID string, rememberMe bool) (string, string, error) {
 	tokenID := uuid.New().String()
 	claims := Claims{
-		UserID:    userID,
-		Email:     email,
-		RoleID:    roleID,
-		TokenType: RefreshToken,
-		TokenID:   tokenID,
+		UserID:     userID,
+		Email:      email,
+		RoleID:     roleID,
+		TokenType:  RefreshToken,
+		TokenID:    tokenID,
+		FamilyID:   familyID,
+		RememberMe: rememberMe,
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(s.config.RefreshExpiry)),
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(s.GetRefreshExpiryFor(rememberMe))),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
 			NotBefore: jwt.NewNumericDate(time.Now()),
 		},
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	signedToken, err := token.SignedString([]byte(s.config.Secret))
+	signedToken, err := s.sign(claims)
+	if err != nil {
+		return "", "", err
+	}
+
+	return signedToken, tokenID, nil
+}
+
+// GenerateImpersonationToken issues an access token acting as the user on behalf of an
+// admin. It carries the admin's ID and expires after expiry; there is no refresh token.
+func (s *JWTService) GenerateImpersonationToken(userID uuid.UUID, email string, roleID int, roleName string, permissions []string, impersonatorID uuid.UUID, expiry time.Duration) (string, string, error) {
+	tokenID := uuid.New().String()
+	claims := Claims{
+		UserID:         userID,
+		Email:          email,
+		RoleID:         roleID,
+		RoleName:       roleName,
+		Permissions:    permissions,
+		TokenType:      AccessToken,
+		TokenID:        tokenID,
+		ImpersonatorID: &impersonatorID,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(expiry)),
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			NotBefore: jwt.NewNumericDate(time.Now()),
+		},
+	}
+
+	signedToken, err := s.sign(claims)
 	if err != nil {
 		return "", "", err
 	}
@@ -83,12 +254,7 @@ func (s *JWTService) GenerateRefreshToken(userID uuid.UUID, email string, roleID
 }
 
 func (s *JWTService) ValidateToken(tokenString string) (*Claims, error) {
-	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, errors.New("invalid signing method")
-		}
-		return []byte(s.config.Secret), nil
-	})
+	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, s.verificationKey)
 
 	if err != nil {
 		return nil, err
@@ -109,3 +275,11 @@ func (s *JWTService) GetAccessExpiry() time.Duration {
 func (s *JWTService) GetRefreshExpiry() time.Duration {
 	return s.config.RefreshExpiry
 }
+
+// GetRefreshExpiryFor returns the refresh token lifetime of a regular or remember-me session
+func (s *JWTService) GetRefreshExpiryFor(rememberMe bool) time.Duration {
+	if rememberMe && s.config.RememberMeExpiry > 0 {
+		return s.config.RememberMeExpiry
+	}
+	return s.config.RefreshExpiry
+}
@@ -0,0 +1,205 @@
+// Package logger builds the application loggers from config.LogConfig.
+//
+// Every component package ("service", "usecase", ...) gets its own *logrus.Logger so
+// levels can be set per package. All loggers share one output and formatter, and the
+// levels can be changed at runtime (SIGHUP config reload or the admin endpoint).
+package logger
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+
+	"go-template-clean-architecture/config"
+
+	"github.com/sirupsen/logrus"
+)
+
+// DefaultPackage names the logrus standard logger (bootstrap, middleware, global logrus calls)
+const DefaultPackage = "default"
+
+// ErrUnknownPackage is returned when setting the level of a package without a logger
+var ErrUnknownPackage = errors.New("unknown log package")
+
+// Manager owns the per-package loggers and their levels
+type Manager struct {
+	mu        sync.RWMutex
+	level     logrus.Level            // Level of packages without an override
+	overrides map[string]logrus.Level // Per-package levels
+	loggers   map[string]*logrus.Logger
+	formatter *samplingFormatter
+}
+
+// New creates a Manager and configures the logrus standard logger as DefaultPackage
+func New(cfg config.LogConfig) (*Manager, error) {
+	m := &Manager{
+		overrides: make(map[string]logrus.Level),
+		loggers:   map[string]*logrus.Logger{DefaultPackage: logrus.StandardLogger()},
+		formatter: &samplingFormatter{},
+	}
+
+	std := logrus.StandardLogger()
+	std.SetOutput(os.Stdout)
+	std.SetFormatter(m.formatter)
+
+	if err := m.Apply(cfg); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// For returns the logger of a component package, creating it on first use
+func (m *Manager) For(pkg string) *logrus.Logger {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if log, ok := m.loggers[pkg]; ok {
+		return log
+	}
+
+	log := logrus.New()
+	log.SetOutput(os.Stdout)
+	log.SetFormatter(m.formatter)
+	log.SetLevel(m.levelOf(pkg))
+	m.loggers[pkg] = log
+	return log
+}
+
+// Apply (re)configures format, levels and sampling from config.
+// Runtime level changes are discarded in favour of the config values.
+func (m *Manager) Apply(cfg config.LogConfig) error {
+	level, err := logrus.ParseLevel(cfg.Level)
+	if err != nil {
+		return fmt.Errorf("invalid LOG_LEVEL %q: %w", cfg.Level, err)
+	}
+
+	overrides := make(map[string]logrus.Level, len(cfg.PackageLevels))
+	for pkg, raw := range cfg.PackageLevels {
+		pkgLevel, err := logrus.ParseLevel(raw)
+		if err != nil {
+			return fmt.Errorf("invalid level %q for package %s: %w", raw, pkg, err)
+		}
+		overrides[pkg] = pkgLevel
+	}
+
+	var next logrus.Formatter
+	switch cfg.Format {
+	case "json":
+		next = &logrus.JSONFormatter{}
+	case "text":
+		next = &logrus.TextFormatter{FullTimestamp: true}
+	default:
+		return fmt.Errorf("invalid LOG_FORMAT %q, use json or text", cfg.Format)
+	}
+	m.formatter.configure(next, cfg.SampleInitial, cfg.SampleThereafter)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.level = level
+	m.overrides = overrides
+	for pkg, log := range m.loggers {
+		log.SetLevel(m.levelOf(pkg))
+	}
+	return nil
+}
+
+// SetLevel changes the level of one package at runtime.
+// DefaultPackage changes the level of every package without an override.
+func (m *Manager) SetLevel(pkg string, level logrus.Level) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if pkg == DefaultPackage {
+		m.level = level
+		for name, log := range m.loggers {
+			log.SetLevel(m.levelOf(name))
+		}
+		return nil
+	}
+
+	log, ok := m.loggers[pkg]
+	if !ok {
+		return ErrUnknownPackage
+	}
+	m.overrides[pkg] = level
+	log.SetLevel(level)
+	return nil
+}
+
+// Levels returns the default level and the effective level of every package
+func (m *Manager) Levels() (logrus.Level, map[string]logrus.Level) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	levels := make(map[string]logrus.Level, len(m.loggers))
+	for pkg := range m.loggers {
+		levels[pkg] = m.levelOf(pkg)
+	}
+	return m.level, levels
+}
+
+// levelOf returns the effective level of a package (caller holds mu)
+func (m *Manager) levelOf(pkg string) logrus.Level {
+	if level, ok := m.overrides[pkg]; ok {
+		return level
+	}
+	return m.level
+}
+
+// samplingFormatter wraps the configured formatter and drops repeated info/debug entries.
+//
+// logrus has no sampling hook, so sampling happens here: a dropped entry formats to
+// nothing. Per message and second, the first `initial` entries are kept, then every
+// `thereafter`-th (none when thereafter is 0). Warnings and errors are never sampled.
+type samplingFormatter struct {
+	mu         sync.Mutex
+	next       logrus.Formatter
+	initial    int
+	thereafter int
+	second     int64
+	counts     map[string]int
+}
+
+func (f *samplingFormatter) configure(next logrus.Formatter, initial, thereafter int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.next = next
+	f.initial = initial
+	f.thereafter = thereafter
+	f.counts = make(map[string]int)
+}
+
+// Format implements logrus.Formatter
+func (f *samplingFormatter) Format(entry *logrus.Entry) ([]byte, error) {
+	f.mu.Lock()
+	next := f.next
+	keep := f.keep(entry)
+	f.mu.Unlock()
+
+	if !keep {
+		return nil, nil
+	}
+	return next.Format(entry)
+}
+
+// keep decides whether an entry is written (caller holds mu)
+func (f *samplingFormatter) keep(entry *logrus.Entry) bool {
+	if f.initial <= 0 || entry.Level <= logrus.WarnLevel {
+		return true
+	}
+
+	if second := entry.Time.Unix(); second != f.second {
+		f.second = second
+		f.counts = make(map[string]int)
+	}
+
+	f.counts[entry.Message]++
+	n := f.counts[entry.Message]
+	if n <= f.initial {
+		return true
+	}
+	return f.thereafter > 0 && (n-f.initial)%f.thereafter == 0
+}
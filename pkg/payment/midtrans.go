@@ -0,0 +1,204 @@
+package payment
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha512"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"go-template-clean-architecture/config"
+)
+
+const (
+	midtransSnapURL        = "https://app.midtrans.com/snap/v1/transactions"
+	midtransSandboxSnapURL = "https://app.sandbox.midtrans.com/snap/v1/transactions"
+	midtransAPIURL         = "https://api.midtrans.com/v2"
+	midtransSandboxAPIURL  = "https://api.sandbox.midtrans.com/v2"
+)
+
+// MidtransGateway charges through Midtrans Snap, the hosted payment page of Midtrans.
+// Requests authenticate with the server key, which also signs the payment notifications
+// (HTTP notification URL of the Midtrans dashboard).
+type MidtransGateway struct {
+	serverKey  string
+	snapURL    string
+	apiURL     string
+	finishURL  string
+	httpClient *http.Client
+}
+
+// NewMidtransGateway creates a MidtransGateway, on the sandbox when configured
+func NewMidtransGateway(cfg config.PaymentConfig) *MidtransGateway {
+	snapURL, apiURL := midtransSnapURL, midtransAPIURL
+	if cfg.Sandbox {
+		snapURL, apiURL = midtransSandboxSnapURL, midtransSandboxAPIURL
+	}
+	return &MidtransGateway{
+		serverKey:  cfg.SecretKey,
+		snapURL:    snapURL,
+		apiURL:     apiURL,
+		finishURL:  cfg.SuccessURL,
+		httpClient: &http.Client{Timeout: requestTimeout},
+	}
+}
+
+func (g *MidtransGateway) Name() string {
+	return GatewayMidtrans
+}
+
+// CreateCharge creates a Snap transaction. Its token is the reference, Midtrans reports
+// the transaction ID with the notifications.
+func (g *MidtransGateway) CreateCharge(ctx context.Context, charge *Charge) (*ChargeResult, error) {
+	payload := map[string]interface{}{
+		"transaction_details": map[string]interface{}{
+			"order_id":     charge.OrderID,
+			"gross_amount": charge.Amount,
+		},
+		"item_details": []map[string]interface{}{{
+			"id":       charge.OrderID,
+			"name":     charge.Description,
+			"price":    charge.Amount,
+			"quantity": 1,
+		}},
+		"customer_details": map[string]interface{}{
+			"first_name": charge.PayerName,
+			"email":      charge.PayerEmail,
+		},
+		"expiry": map[string]interface{}{
+			"unit":     "minute",
+			"duration": max(1, int(math.Ceil(time.Until(charge.ExpiresAt).Minutes()))),
+		},
+	}
+	if g.finishURL != "" {
+		payload["callbacks"] = map[string]string{"finish": g.finishURL}
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, g.snapURL, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.SetBasicAuth(g.serverKey, "")
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+
+	var result struct {
+		Token       string `json:"token"`
+		RedirectURL string `json:"redirect_url"`
+	}
+	if err := doJSON(g.httpClient, req, &result); err != nil {
+		return nil, err
+	}
+	if result.RedirectURL == "" {
+		return nil, fmt.Errorf("%w: midtrans returned no redirect_url", ErrUnavailable)
+	}
+
+	return &ChargeResult{Reference: result.Token, PaymentURL: result.RedirectURL}, nil
+}
+
+// Refund refunds the transaction of the order. Midtrans answers refunds right away; the
+// payment methods without refunds (e.g. bank transfers) are rejected.
+func (g *MidtransGateway) Refund(ctx context.Context, refund *Refund) (*RefundResult, error) {
+	body, err := json.Marshal(map[string]interface{}{
+		"refund_key": "refund-" + refund.Key,
+		"amount":     refund.Amount,
+		"reason":     refund.Reason,
+	})
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, g.apiURL+"/"+url.PathEscape(refund.OrderID)+"/refund", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.SetBasicAuth(g.serverKey, "")
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+
+	var result struct {
+		StatusCode         string      `json:"status_code"`
+		StatusMessage      string      `json:"status_message"`
+		RefundChargebackID json.Number `json:"refund_chargeback_id"`
+	}
+	if err := doJSON(g.httpClient, req, &result); err != nil {
+		return nil, err
+	}
+
+	// Errors come back with HTTP 200 and the status in the body
+	switch {
+	case result.StatusCode == "200":
+		return &RefundResult{Reference: result.RefundChargebackID.String(), Status: StatusRefunded}, nil
+	case strings.HasPrefix(result.StatusCode, "5"):
+		return nil, fmt.Errorf("%w: midtrans refund %s: %s", ErrUnavailable, result.StatusCode, result.StatusMessage)
+	default:
+		return nil, fmt.Errorf("%w: midtrans refund %s: %s", ErrRejected, result.StatusCode, result.StatusMessage)
+	}
+}
+
+// ParseCallback verifies the signature_key of a payment notification:
+// SHA-512 of order_id + status_code + gross_amount + server key
+func (g *MidtransGateway) ParseCallback(header http.Header, body []byte) (*Callback, error) {
+	var notification struct {
+		OrderID           string `json:"order_id"`
+		TransactionID     string `json:"transaction_id"`
+		TransactionStatus string `json:"transaction_status"`
+		FraudStatus       string `json:"fraud_status"`
+		StatusCode        string `json:"status_code"`
+		StatusMessage     string `json:"status_message"`
+		GrossAmount       string `json:"gross_amount"` // e.g. "150000.00"
+		Currency          string `json:"currency"`
+		SignatureKey      string `json:"signature_key"`
+	}
+	if err := json.Unmarshal(body, &notification); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidCallback, err)
+	}
+
+	sum := sha512.Sum512([]byte(notification.OrderID + notification.StatusCode + notification.GrossAmount + g.serverKey))
+	if subtle.ConstantTimeCompare([]byte(hex.EncodeToString(sum[:])), []byte(notification.SignatureKey)) != 1 {
+		return nil, ErrInvalidCallback
+	}
+
+	callback := &Callback{
+		OrderID:   notification.OrderID,
+		Reference: notification.TransactionID,
+		Status:    StatusPending,
+		Currency:  strings.ToUpper(notification.Currency),
+	}
+	if amount, err := strconv.ParseFloat(notification.GrossAmount, 64); err == nil {
+		callback.Amount = int64(math.Round(amount))
+	}
+
+	switch notification.TransactionStatus {
+	case "settlement":
+		callback.Status = StatusPaid
+	case "capture":
+		// Card payments held for review are decided by a later notification
+		switch notification.FraudStatus {
+		case "", "accept":
+			callback.Status = StatusPaid
+		case "deny":
+			callback.Status = StatusFailed
+			callback.Reason = "denied by fraud detection"
+		}
+	case "deny", "cancel", "failure":
+		callback.Status = StatusFailed
+		callback.Reason = fmt.Sprintf("%s: %s", notification.TransactionStatus, notification.StatusMessage)
+	case "expire":
+		callback.Status = StatusExpired
+	case "refund", "partial_refund":
+		callback.Status = StatusRefunded
+	}
+	return callback, nil
+}
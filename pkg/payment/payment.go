@@ -0,0 +1,168 @@
+// Package payment charges payments through a payment gateway (Midtrans, Xendit, Stripe):
+// it opens the hosted payment page of a charge, refunds paid charges and verifies the
+// callbacks the gateway posts when a payment or refund completes.
+package payment
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"go-template-clean-architecture/config"
+)
+
+// Supported gateways, set with PAYMENT_GATEWAY
+const (
+	GatewayMidtrans = "midtrans"
+	GatewayXendit   = "xendit"
+	GatewayStripe   = "stripe"
+)
+
+const (
+	requestTimeout = 15 * time.Second
+
+	// Gateway responses are small, larger ones are cut off
+	maxResponseSize = 1 << 20
+)
+
+var (
+	// ErrInvalidCallback means the callback is not signed by the gateway or cannot be read
+	ErrInvalidCallback = errors.New("invalid payment callback")
+	// ErrUnavailable means the gateway could not be asked, the request may be retried
+	ErrUnavailable = errors.New("payment gateway unavailable")
+	// ErrRejected means the gateway refused the request, retrying it gives the same answer
+	ErrRejected = errors.New("payment gateway rejected the request")
+)
+
+// Status is the outcome of a payment reported by a callback
+type Status string
+
+const (
+	StatusPending Status = "pending" // Not final yet, e.g. waiting for a bank transfer
+	StatusPaid    Status = "paid"
+	StatusFailed  Status = "failed" // Declined, denied or cancelled at the gateway
+	StatusExpired Status = "expired"
+
+	// Refund outcomes
+	StatusRefundPending Status = "refund_pending" // Accepted, the outcome follows with a callback
+	StatusRefunded      Status = "refunded"
+	StatusRefundFailed  Status = "refund_failed"
+)
+
+// Charge is a payment to collect
+type Charge struct {
+	OrderID     string // Our ID of the payment, sent back by the callbacks
+	Amount      int64  // Smallest currency unit
+	Currency    string // ISO 4217
+	Description string
+	PayerName   string
+	PayerEmail  string
+	ExpiresAt   time.Time
+}
+
+// ChargeResult is the charge opened at the gateway
+type ChargeResult struct {
+	Reference  string // ID of the charge at the gateway
+	PaymentURL string // Hosted page the payer completes the payment on
+}
+
+// Refund returns a paid charge to the payer
+type Refund struct {
+	OrderID   string // Our ID of the payment, sent back by the refund callbacks
+	Reference string // ID of the paid transaction at the gateway
+	Key       string // Unique per refund attempt of the payment, repeats of an attempt are not refunded twice
+	Amount    int64  // Smallest currency unit
+	Currency  string
+	Reason    string
+}
+
+// RefundResult is the refund accepted by the gateway
+type RefundResult struct {
+	Reference string // ID of the refund at the gateway
+	Status    Status // StatusRefunded, or StatusRefundPending until a callback reports it
+}
+
+// Callback is a verified payment or refund notification of the gateway
+type Callback struct {
+	OrderID   string
+	Reference string // ID of the transaction at the gateway, empty when not reported
+	Status    Status
+	Amount    int64  // 0 when not reported
+	Currency  string // ISO 4217 in upper case, empty when not reported
+	Reason    string
+}
+
+// Gateway charges payments at one payment provider
+type Gateway interface {
+	// Name is the gateway, as in the callback URL
+	Name() string
+	// CreateCharge opens the payment page of the charge
+	CreateCharge(ctx context.Context, charge *Charge) (*ChargeResult, error)
+	// Refund refunds a paid charge. Errors wrap ErrUnavailable when the refund may be retried.
+	Refund(ctx context.Context, refund *Refund) (*RefundResult, error)
+	// ParseCallback verifies a callback posted by the gateway and reads the payment or refund
+	// outcome. Notifications about something else come back with StatusPending.
+	ParseCallback(header http.Header, body []byte) (*Callback, error)
+}
+
+// New creates the gateway of the configuration, nil when payments are disabled
+func New(cfg config.PaymentConfig) (Gateway, error) {
+	if !cfg.Enabled() {
+		return nil, nil
+	}
+	if cfg.SecretKey == "" {
+		return nil, fmt.Errorf("payment gateway %s needs PAYMENT_SECRET_KEY", cfg.Gateway)
+	}
+
+	switch cfg.Gateway {
+	case GatewayMidtrans:
+		return NewMidtransGateway(cfg), nil
+	case GatewayXendit:
+		if cfg.CallbackToken == "" {
+			return nil, errors.New("payment gateway xendit needs PAYMENT_CALLBACK_TOKEN (callback verification token)")
+		}
+		return NewXenditGateway(cfg), nil
+	case GatewayStripe:
+		if cfg.CallbackToken == "" {
+			return nil, errors.New("payment gateway stripe needs PAYMENT_CALLBACK_TOKEN (webhook signing secret)")
+		}
+		return NewStripeGateway(cfg), nil
+	default:
+		return nil, fmt.Errorf("unknown payment gateway %q, use midtrans, xendit or stripe", cfg.Gateway)
+	}
+}
+
+// doJSON sends a request to the gateway and decodes the JSON response into v.
+// Client errors are ErrRejected, other non-2xx responses ErrUnavailable, with the body in
+// the message.
+func doJSON(client *http.Client, req *http.Request, v interface{}) error {
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrUnavailable, err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseSize))
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrUnavailable, err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		cause := ErrUnavailable
+		switch {
+		case resp.StatusCode == http.StatusRequestTimeout, resp.StatusCode == http.StatusConflict, resp.StatusCode == http.StatusTooManyRequests:
+			// Retried later
+		case resp.StatusCode >= 400 && resp.StatusCode < 500:
+			cause = ErrRejected
+		}
+		return fmt.Errorf("%w: %s returned %d: %s", cause, req.URL.Host, resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	if err := json.Unmarshal(body, v); err != nil {
+		return fmt.Errorf("%w: %v", ErrUnavailable, err)
+	}
+	return nil
+}
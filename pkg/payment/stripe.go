@@ -0,0 +1,251 @@
+package payment
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"go-template-clean-architecture/config"
+)
+
+const (
+	stripeCheckoutURL = "https://api.stripe.com/v1/checkout/sessions"
+	stripeRefundURL   = "https://api.stripe.com/v1/refunds"
+
+	// Checkout sessions expire between 30 minutes and 24 hours after creation
+	stripeMinExpiry = 31 * time.Minute
+
+	// Webhook signatures older than this are rejected (replays)
+	stripeSignatureTolerance = 5 * time.Minute
+)
+
+// StripeGateway charges through Stripe Checkout. Requests authenticate with the secret key;
+// webhook events are signed with the signing secret of the webhook endpoint, which must
+// send the checkout.session.* and refund.* events.
+type StripeGateway struct {
+	secretKey     string
+	signingSecret string
+	successURL    string
+	cancelURL     string
+	httpClient    *http.Client
+}
+
+// NewStripeGateway creates a StripeGateway. Test mode follows from the secret key.
+func NewStripeGateway(cfg config.PaymentConfig) *StripeGateway {
+	return &StripeGateway{
+		secretKey:     cfg.SecretKey,
+		signingSecret: cfg.CallbackToken,
+		successURL:    cfg.SuccessURL,
+		cancelURL:     cfg.CancelURL,
+		httpClient:    &http.Client{Timeout: requestTimeout},
+	}
+}
+
+func (g *StripeGateway) Name() string {
+	return GatewayStripe
+}
+
+// CreateCharge creates a Checkout Session with the payment ID as client_reference_id.
+// Sessions stay open for at least the minimum Stripe allows, even when the charge expires sooner.
+func (g *StripeGateway) CreateCharge(ctx context.Context, charge *Charge) (*ChargeResult, error) {
+	expiresAt := charge.ExpiresAt
+	if minimum := time.Now().Add(stripeMinExpiry); expiresAt.Before(minimum) {
+		expiresAt = minimum
+	}
+
+	form := url.Values{}
+	form.Set("mode", "payment")
+	form.Set("client_reference_id", charge.OrderID)
+	form.Set("metadata[order_id]", charge.OrderID)
+	form.Set("line_items[0][quantity]", "1")
+	form.Set("line_items[0][price_data][currency]", strings.ToLower(charge.Currency))
+	form.Set("line_items[0][price_data][unit_amount]", strconv.FormatInt(charge.Amount, 10))
+	form.Set("line_items[0][price_data][product_data][name]", charge.Description)
+	form.Set("expires_at", strconv.FormatInt(expiresAt.Unix(), 10))
+	if charge.PayerEmail != "" {
+		form.Set("customer_email", charge.PayerEmail)
+	}
+	if g.successURL != "" {
+		form.Set("success_url", g.successURL)
+	}
+	if g.cancelURL != "" {
+		form.Set("cancel_url", g.cancelURL)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, stripeCheckoutURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+g.secretKey)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	// A retried request must not open a second session for the same payment
+	req.Header.Set("Idempotency-Key", "checkout:"+charge.OrderID)
+
+	var result struct {
+		ID  string `json:"id"`
+		URL string `json:"url"`
+	}
+	if err := doJSON(g.httpClient, req, &result); err != nil {
+		return nil, err
+	}
+	if result.URL == "" {
+		return nil, fmt.Errorf("%w: stripe returned no checkout url", ErrUnavailable)
+	}
+
+	return &ChargeResult{Reference: result.ID, PaymentURL: result.URL}, nil
+}
+
+// Refund refunds the payment intent of the paid session, with the payment ID in the metadata
+// of the refund events
+func (g *StripeGateway) Refund(ctx context.Context, refund *Refund) (*RefundResult, error) {
+	form := url.Values{}
+	form.Set("payment_intent", refund.Reference)
+	form.Set("amount", strconv.FormatInt(refund.Amount, 10))
+	form.Set("reason", "requested_by_customer")
+	form.Set("metadata[order_id]", refund.OrderID)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, stripeRefundURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+g.secretKey)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	// A retried request must not refund twice
+	req.Header.Set("Idempotency-Key", "refund:"+refund.OrderID+":"+refund.Key)
+
+	var result struct {
+		ID            string `json:"id"`
+		Status        string `json:"status"`
+		FailureReason string `json:"failure_reason"`
+	}
+	if err := doJSON(g.httpClient, req, &result); err != nil {
+		return nil, err
+	}
+
+	switch result.Status {
+	case "succeeded":
+		return &RefundResult{Reference: result.ID, Status: StatusRefunded}, nil
+	case "failed", "canceled":
+		return nil, fmt.Errorf("%w: stripe refund %s: %s", ErrRejected, result.Status, result.FailureReason)
+	default:
+		return &RefundResult{Reference: result.ID, Status: StatusRefundPending}, nil
+	}
+}
+
+// ParseCallback verifies the Stripe-Signature header (HMAC-SHA256 of "timestamp.body"
+// with the signing secret) and reads the checkout session or refund of the event
+func (g *StripeGateway) ParseCallback(header http.Header, body []byte) (*Callback, error) {
+	if err := g.verifySignature(header.Get("Stripe-Signature"), body); err != nil {
+		return nil, err
+	}
+
+	var event struct {
+		Type string `json:"type"`
+		Data struct {
+			Object struct {
+				ID                string            `json:"id"`
+				ClientReferenceID string            `json:"client_reference_id"`
+				PaymentStatus     string            `json:"payment_status"`
+				PaymentIntent     string            `json:"payment_intent"`
+				AmountTotal       int64             `json:"amount_total"`
+				Currency          string            `json:"currency"`
+				Amount            int64             `json:"amount"`         // Refunds
+				Status            string            `json:"status"`         // Refunds
+				FailureReason     string            `json:"failure_reason"` // Refunds
+				Metadata          map[string]string `json:"metadata"`
+			} `json:"object"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(body, &event); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidCallback, err)
+	}
+
+	if strings.HasPrefix(event.Type, "refund.") {
+		refund := event.Data.Object
+		callback := &Callback{
+			OrderID:   refund.Metadata["order_id"],
+			Reference: refund.ID,
+			Status:    StatusPending,
+			Amount:    refund.Amount,
+			Currency:  strings.ToUpper(refund.Currency),
+		}
+		switch refund.Status {
+		case "succeeded":
+			callback.Status = StatusRefunded
+		case "failed", "canceled":
+			callback.Status = StatusRefundFailed
+			callback.Reason = refund.FailureReason
+		}
+		return callback, nil
+	}
+
+	session := event.Data.Object
+	callback := &Callback{
+		OrderID:   session.ClientReferenceID,
+		Reference: session.PaymentIntent,
+		Status:    StatusPending,
+		Amount:    session.AmountTotal,
+		Currency:  strings.ToUpper(session.Currency),
+	}
+	if callback.Reference == "" {
+		callback.Reference = session.ID
+	}
+
+	switch event.Type {
+	case "checkout.session.completed":
+		// Delayed methods (e.g. bank debits) complete unpaid and report the outcome later
+		if session.PaymentStatus == "paid" {
+			callback.Status = StatusPaid
+		}
+	case "checkout.session.async_payment_succeeded":
+		callback.Status = StatusPaid
+	case "checkout.session.async_payment_failed":
+		callback.Status = StatusFailed
+		callback.Reason = "asynchronous payment failed"
+	case "checkout.session.expired":
+		callback.Status = StatusExpired
+	}
+	return callback, nil
+}
+
+// verifySignature checks one of the v1 signatures of the header: t=<unix>,v1=<hex>[,v1=...]
+func (g *StripeGateway) verifySignature(header string, body []byte) error {
+	var timestamp string
+	var signatures []string
+	for _, part := range strings.Split(header, ",") {
+		key, value, _ := strings.Cut(strings.TrimSpace(part), "=")
+		switch key {
+		case "t":
+			timestamp = value
+		case "v1":
+			signatures = append(signatures, value)
+		}
+	}
+
+	unix, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil || len(signatures) == 0 {
+		return ErrInvalidCallback
+	}
+	if age := time.Since(time.Unix(unix, 0)); age > stripeSignatureTolerance || age < -stripeSignatureTolerance {
+		return ErrInvalidCallback
+	}
+
+	mac := hmac.New(sha256.New, []byte(g.signingSecret))
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(body)
+	expected := hex.EncodeToString(mac.Sum(nil))
+	for _, signature := range signatures {
+		if hmac.Equal([]byte(signature), []byte(expected)) {
+			return nil
+		}
+	}
+	return ErrInvalidCallback
+}
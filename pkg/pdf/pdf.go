@@ -0,0 +1,140 @@
+// Package pdf writes simple text documents (invoices) as PDF without external dependencies.
+//
+// Documents are A4 pages of text in the standard PDF fonts, which every reader ships, so
+// nothing is embedded. Text is encoded as WinAnsi: characters outside Latin-1 print as "?".
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// A4 page size in points (1/72 inch)
+const (
+	PageWidth  = 595.28
+	PageHeight = 841.89
+)
+
+// Font is one of the standard PDF fonts
+type Font int
+
+const (
+	Helvetica Font = iota
+	HelveticaBold
+	Courier // Monospaced, for right-aligned amounts
+)
+
+var fontNames = [...]string{"Helvetica", "Helvetica-Bold", "Courier"}
+
+// Document is a PDF under construction. Coordinates are points from the top-left corner
+// of the page.
+type Document struct {
+	pages []*bytes.Buffer
+}
+
+// New creates a document with one empty page
+func New() *Document {
+	d := &Document{}
+	d.AddPage()
+	return d
+}
+
+// AddPage starts a new page, later drawing goes to it
+func (d *Document) AddPage() {
+	d.pages = append(d.pages, &bytes.Buffer{})
+}
+
+func (d *Document) page() *bytes.Buffer {
+	return d.pages[len(d.pages)-1]
+}
+
+// Text draws text with its baseline at y
+func (d *Document) Text(x, y float64, font Font, size float64, text string) {
+	fmt.Fprintf(d.page(), "BT /F%d %.2f Tf %.2f %.2f Td (%s) Tj ET\n", font+1, size, x, PageHeight-y, escape(text))
+}
+
+// TextRight draws monospaced text ending at x, e.g. a column of amounts
+func (d *Document) TextRight(x, y float64, size float64, text string) {
+	// Every Courier glyph is 600/1000 em wide
+	width := float64(len([]rune(text))) * size * 0.6
+	d.Text(x-width, y, Courier, size, text)
+}
+
+// Line draws a thin horizontal or vertical rule
+func (d *Document) Line(x1, y1, x2, y2 float64) {
+	fmt.Fprintf(d.page(), "0.5 w %.2f %.2f m %.2f %.2f l S\n", x1, PageHeight-y1, x2, PageHeight-y2)
+}
+
+// Bytes renders the document
+func (d *Document) Bytes() []byte {
+	var out bytes.Buffer
+	var offsets []int
+
+	object := func(body string) {
+		offsets = append(offsets, out.Len())
+		fmt.Fprintf(&out, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+
+	out.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+
+	// 1: catalog, 2: page tree, 3..: fonts, then a page and its content stream per page
+	fontBase := 3
+	pageBase := fontBase + len(fontNames)
+	kids := make([]string, len(d.pages))
+	for i := range d.pages {
+		kids[i] = fmt.Sprintf("%d 0 R", pageBase+2*i)
+	}
+	fonts := make([]string, len(fontNames))
+	for i := range fontNames {
+		fonts[i] = fmt.Sprintf("/F%d %d 0 R", i+1, fontBase+i)
+	}
+
+	object("<< /Type /Catalog /Pages 2 0 R >>")
+	object(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(d.pages)))
+	for _, name := range fontNames {
+		object(fmt.Sprintf("<< /Type /Font /Subtype /Type1 /BaseFont /%s /Encoding /WinAnsiEncoding >>", name))
+	}
+	for i, content := range d.pages {
+		object(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.2f %.2f] /Resources << /Font << %s >> >> /Contents %d 0 R >>",
+			PageWidth, PageHeight, strings.Join(fonts, " "), pageBase+2*i+1))
+		object(fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", content.Len(), content.String()))
+	}
+
+	xref := out.Len()
+	fmt.Fprintf(&out, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&out, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&out, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+
+	return out.Bytes()
+}
+
+// escape encodes text as the bytes of a PDF literal string
+func escape(text string) string {
+	var b strings.Builder
+	for _, r := range text {
+		switch {
+		case r == '\\' || r == '(' || r == ')':
+			b.WriteByte('\\')
+			b.WriteByte(byte(r))
+		case r == '\n' || r == '\r' || r == '\t':
+			b.WriteByte(' ')
+		case r < 0x20:
+			// Control characters are dropped
+		case r < 0x80, r >= 0xA0 && r <= 0xFF:
+			// WinAnsi matches Latin-1 outside 0x80-0x9F
+			b.WriteByte(byte(r))
+		case r == '–':
+			b.WriteByte(0x96)
+		case r == '—':
+			b.WriteByte(0x97)
+		case r == '€':
+			b.WriteByte(0x80)
+		default:
+			b.WriteByte('?')
+		}
+	}
+	return b.String()
+}
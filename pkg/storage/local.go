@@ -0,0 +1,121 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// LocalURLPrefix is where the API serves the files of a LocalStorage without a public URL
+const LocalURLPrefix = "/uploads/"
+
+// LocalStorage keeps files in a directory, e.g. for development or a single instance
+// with a persistent volume
+type LocalStorage struct {
+	dir       string
+	publicURL string
+}
+
+// NewLocalStorage creates the directory if needed. Files are linked under publicURL,
+// by default the API itself (see Handler).
+func NewLocalStorage(dir, publicURL string) (*LocalStorage, error) {
+	if dir == "" {
+		dir = "uploads"
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("create storage directory: %w", err)
+	}
+	if publicURL == "" {
+		publicURL = strings.TrimSuffix(LocalURLPrefix, "/")
+	}
+	return &LocalStorage{
+		dir:       dir,
+		publicURL: strings.TrimSuffix(publicURL, "/"),
+	}, nil
+}
+
+// Put writes the file through a temporary file, readers never see a partial file
+func (s *LocalStorage) Put(ctx context.Context, key string, data []byte, contentType string) error {
+	target, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(target), ".upload-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), target)
+}
+
+func (s *LocalStorage) Get(ctx context.Context, key string) ([]byte, error) {
+	target, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(target)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, ErrNotFound
+	}
+	return data, err
+}
+
+func (s *LocalStorage) Delete(ctx context.Context, key string) error {
+	target, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(target); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+func (s *LocalStorage) URL(key string) string {
+	return s.publicURL + "/" + key
+}
+
+// Handler serves the stored files, to be mounted at LocalURLPrefix. Directories and
+// private files (PrivatePrefix) are not served.
+func (s *LocalStorage) Handler() http.Handler {
+	files := http.StripPrefix(LocalURLPrefix, http.FileServer(http.Dir(s.dir)))
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := strings.TrimPrefix(path.Clean(r.URL.Path), LocalURLPrefix)
+		if strings.HasSuffix(r.URL.Path, "/") || strings.HasPrefix(key, PrivatePrefix) {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("X-Content-Type-Options", "nosniff")
+		files.ServeHTTP(w, r)
+	})
+}
+
+// path maps a key into the storage directory, rejecting keys that leave it
+func (s *LocalStorage) path(key string) (string, error) {
+	clean := path.Clean("/" + key)
+	if key == "" || clean != "/"+key {
+		return "", fmt.Errorf("invalid storage key %q", key)
+	}
+	return filepath.Join(s.dir, filepath.FromSlash(clean)), nil
+}
@@ -0,0 +1,195 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"go-template-clean-architecture/config"
+)
+
+const s3RequestTimeout = 30 * time.Second
+
+// S3Storage keeps files in a bucket of an S3-compatible service (AWS S3, MinIO,
+// Cloudflare R2, ...). Requests are signed with AWS Signature Version 4.
+type S3Storage struct {
+	endpoint   *url.URL
+	region     string
+	bucket     string
+	accessKey  string
+	secretKey  string
+	pathStyle  bool
+	publicURL  string
+	httpClient *http.Client
+}
+
+// NewS3Storage checks the bucket settings. Without a public URL files are linked
+// at the bucket URL, which must then allow public reads.
+func NewS3Storage(cfg config.StorageConfig) (*S3Storage, error) {
+	if cfg.S3Endpoint == "" || cfg.S3Bucket == "" || cfg.S3AccessKey == "" || cfg.S3SecretKey == "" {
+		return nil, fmt.Errorf("s3 storage needs STORAGE_S3_ENDPOINT, STORAGE_S3_BUCKET, STORAGE_S3_ACCESS_KEY and STORAGE_S3_SECRET_KEY")
+	}
+	endpoint, err := url.Parse(strings.TrimSuffix(cfg.S3Endpoint, "/"))
+	if err != nil || endpoint.Host == "" {
+		return nil, fmt.Errorf("invalid STORAGE_S3_ENDPOINT %q", cfg.S3Endpoint)
+	}
+	region := cfg.S3Region
+	if region == "" {
+		region = "us-east-1"
+	}
+
+	s := &S3Storage{
+		endpoint:   endpoint,
+		region:     region,
+		bucket:     cfg.S3Bucket,
+		accessKey:  cfg.S3AccessKey,
+		secretKey:  cfg.S3SecretKey,
+		pathStyle:  cfg.S3PathStyle,
+		httpClient: &http.Client{Timeout: s3RequestTimeout},
+	}
+	s.publicURL = strings.TrimSuffix(cfg.PublicURL, "/")
+	if s.publicURL == "" {
+		s.publicURL = strings.TrimSuffix(s.objectURL(""), "/")
+	}
+	return s, nil
+}
+
+func (s *S3Storage) Put(ctx context.Context, key string, data []byte, contentType string) error {
+	_, err := s.do(ctx, http.MethodPut, key, data, contentType)
+	return err
+}
+
+func (s *S3Storage) Get(ctx context.Context, key string) ([]byte, error) {
+	return s.do(ctx, http.MethodGet, key, nil, "")
+}
+
+func (s *S3Storage) Delete(ctx context.Context, key string) error {
+	_, err := s.do(ctx, http.MethodDelete, key, nil, "")
+	return err
+}
+
+func (s *S3Storage) URL(key string) string {
+	return s.publicURL + "/" + key
+}
+
+// objectURL is the API URL of an object, the bucket in the host name or in the path
+func (s *S3Storage) objectURL(key string) string {
+	if s.pathStyle {
+		return fmt.Sprintf("%s://%s/%s/%s", s.endpoint.Scheme, s.endpoint.Host, s.bucket, key)
+	}
+	return fmt.Sprintf("%s://%s.%s/%s", s.endpoint.Scheme, s.bucket, s.endpoint.Host, key)
+}
+
+// do sends a signed object request and returns the response body. Deleting a missing
+// object succeeds, getting it returns ErrNotFound.
+func (s *S3Storage) do(ctx context.Context, method, key string, body []byte, contentType string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, method, s.objectURL(escapeKey(key)), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	s.sign(req, body, time.Now().UTC())
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("s3 %s %s: %w", method, key, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		data, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return nil, fmt.Errorf("s3 %s %s: %w", method, key, err)
+		}
+		return data, nil
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		switch method {
+		case http.MethodDelete:
+			return nil, nil
+		case http.MethodGet:
+			return nil, ErrNotFound
+		}
+	}
+	detail, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+	return nil, fmt.Errorf("s3 %s %s returned %d: %s", method, key, resp.StatusCode, strings.TrimSpace(string(detail)))
+}
+
+// sign adds the AWS Signature Version 4 headers of the request
+func (s *S3Storage) sign(req *http.Request, body []byte, now time.Time) {
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+	payloadHash := sha256Hex(body)
+
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	// Signed headers, sorted by name
+	headers := [][2]string{}
+	if contentType := req.Header.Get("Content-Type"); contentType != "" {
+		headers = append(headers, [2]string{"content-type", contentType})
+	}
+	headers = append(headers,
+		[2]string{"host", req.URL.Host},
+		[2]string{"x-amz-content-sha256", payloadHash},
+		[2]string{"x-amz-date", amzDate},
+	)
+	var canonicalHeaders strings.Builder
+	names := make([]string, len(headers))
+	for i, header := range headers {
+		canonicalHeaders.WriteString(header[0] + ":" + strings.TrimSpace(header[1]) + "\n")
+		names[i] = header[0]
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		req.URL.RawQuery,
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := date + "/" + s.region + "/s3/aws4_request"
+	stringToSign := strings.Join([]string{"AWS4-HMAC-SHA256", amzDate, scope, sha256Hex([]byte(canonicalRequest))}, "\n")
+
+	signingKey := hmacSHA256([]byte("AWS4"+s.secretKey), date)
+	signingKey = hmacSHA256(signingKey, s.region)
+	signingKey = hmacSHA256(signingKey, "s3")
+	signingKey = hmacSHA256(signingKey, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(signingKey, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s.accessKey, scope, signedHeaders, signature))
+}
+
+// escapeKey URI-encodes every segment of the key as SigV4 expects
+func escapeKey(key string) string {
+	segments := strings.Split(key, "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	return strings.Join(segments, "/")
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
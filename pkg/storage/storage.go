@@ -0,0 +1,50 @@
+// Package storage keeps uploaded files (e.g. doctor photos) on local disk or in an
+// S3-compatible object store, and tells the public URL they are served from.
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"go-template-clean-architecture/config"
+)
+
+// Supported drivers, set with STORAGE_DRIVER
+const (
+	DriverLocal = "local"
+	DriverS3    = "s3"
+)
+
+// PrivatePrefix starts the keys of files that must not be linked publicly, such as
+// personal data exports. LocalStorage does not serve them, S3 buckets must not allow
+// public reads of the prefix. The application reads them back with Get.
+const PrivatePrefix = "private/"
+
+// ErrNotFound is returned by Get for a missing file
+var ErrNotFound = errors.New("file not found")
+
+// Storage stores files by key, a slash separated relative path such as
+// "doctors/{id}/photo.jpg". Keys are chosen by the application, never by clients.
+type Storage interface {
+	// Put stores the file, replacing any file with the same key
+	Put(ctx context.Context, key string, data []byte, contentType string) error
+	// Get returns the content of the file, ErrNotFound when it is missing
+	Get(ctx context.Context, key string) ([]byte, error)
+	// Delete removes the file, deleting a missing file is not an error
+	Delete(ctx context.Context, key string) error
+	// URL returns the public URL of the file
+	URL(key string) string
+}
+
+// New creates the storage of the configured driver
+func New(cfg config.StorageConfig) (Storage, error) {
+	switch cfg.Driver {
+	case "", DriverLocal:
+		return NewLocalStorage(cfg.LocalDir, cfg.PublicURL)
+	case DriverS3:
+		return NewS3Storage(cfg)
+	default:
+		return nil, fmt.Errorf("unknown storage driver %q, use local or s3", cfg.Driver)
+	}
+}
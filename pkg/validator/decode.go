@@ -0,0 +1,100 @@
+package validator
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// DecodeError describes why a JSON request body could not be decoded
+type DecodeError struct {
+	Field   string `json:"field,omitempty"`  // JSON path of the offending field, e.g. "segment.doctor_id"
+	Message string `json:"message"`          // What is wrong
+	Offset  int64  `json:"offset,omitempty"` // Byte position in the body
+}
+
+func (e *DecodeError) Error() string {
+	if e.Field != "" {
+		return fmt.Sprintf("%s: %s", e.Field, e.Message)
+	}
+	return e.Message
+}
+
+// DecodeJSON decodes a JSON request body into dst.
+//
+// Errors are returned as *DecodeError with the field path, type mismatch and byte offset.
+// In strict mode unknown fields and trailing data after the JSON object are rejected.
+func (cv *CustomValidator) DecodeJSON(body io.Reader, dst interface{}) error {
+	decoder := json.NewDecoder(body)
+	if cv.strictJSON {
+		decoder.DisallowUnknownFields()
+	}
+
+	if err := decoder.Decode(dst); err != nil {
+		return toDecodeError(err)
+	}
+
+	if cv.strictJSON {
+		var extra json.RawMessage
+		if err := decoder.Decode(&extra); !errors.Is(err, io.EOF) {
+			return &DecodeError{Message: "request body must contain a single JSON object", Offset: decoder.InputOffset()}
+		}
+	}
+	return nil
+}
+
+// FormatDecodeError returns the error details for the response body
+func (cv *CustomValidator) FormatDecodeError(err error) *DecodeError {
+	var decodeErr *DecodeError
+	if errors.As(err, &decodeErr) {
+		return decodeErr
+	}
+	return &DecodeError{Message: err.Error()}
+}
+
+// toDecodeError translates encoding/json errors into a DecodeError
+func toDecodeError(err error) *DecodeError {
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+
+	switch {
+	case errors.Is(err, io.EOF):
+		return &DecodeError{Message: "request body is empty"}
+	case errors.Is(err, io.ErrUnexpectedEOF):
+		return &DecodeError{Message: "malformed JSON: unexpected end of body"}
+	case errors.As(err, &syntaxErr):
+		return &DecodeError{Message: "malformed JSON: " + syntaxErr.Error(), Offset: syntaxErr.Offset}
+	case errors.As(err, &typeErr):
+		return &DecodeError{
+			Field:   typeErr.Field,
+			Message: fmt.Sprintf("expected %s, got %s", jsonTypeName(typeErr.Type.String()), typeErr.Value),
+			Offset:  typeErr.Offset,
+		}
+	case strings.HasPrefix(err.Error(), "json: unknown field "):
+		// DisallowUnknownFields has no typed error
+		field := strings.Trim(strings.TrimPrefix(err.Error(), "json: unknown field "), `"`)
+		return &DecodeError{Field: field, Message: "unknown field"}
+	default:
+		return &DecodeError{Message: err.Error()}
+	}
+}
+
+// jsonTypeName describes a Go type the way API clients see it
+func jsonTypeName(goType string) string {
+	switch {
+	case goType == "string", goType == "uuid.UUID", goType == "time.Time":
+		return "string"
+	case goType == "bool":
+		return "boolean"
+	case strings.HasPrefix(goType, "int"), strings.HasPrefix(goType, "uint"), strings.HasPrefix(goType, "float"):
+		return "number"
+	case strings.HasPrefix(goType, "[]"):
+		return "array"
+	case strings.HasPrefix(goType, "map["), strings.HasPrefix(goType, "struct"):
+		return "object"
+	default:
+		return goType
+	}
+}
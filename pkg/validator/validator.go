@@ -5,12 +5,14 @@ import (
 )
 
 type CustomValidator struct {
-	validator *validator.Validate
+	validator  *validator.Validate
+	strictJSON bool // Reject unknown fields in request bodies
 }
 
-func NewValidator() *CustomValidator {
+func NewValidator(strictJSON bool) *CustomValidator {
 	return &CustomValidator{
-		validator: validator.New(),
+		validator:  validator.New(),
+		strictJSON: strictJSON,
 	}
 }
 